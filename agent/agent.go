@@ -12,8 +12,10 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -164,6 +166,10 @@ const (
 	MongoOplogSize         = "MONGO_OPLOG_SIZE"
 	NumaCtlPreference      = "NUMA_CTL_PREFERENCE"
 	AllowsSecureConnection = "SECURE_STATESERVER_CONNECTION"
+	LogRetention           = "LOG_RETENTION"
+	StatusHistoryRetention = "STATUS_HISTORY_RETENTION"
+	AuditSyslog            = "AUDIT_SYSLOG"
+	APIRateLimit           = "API_RATE_LIMIT"
 )
 
 // The Config interface is the sole way that the agent gets access to the
@@ -207,9 +213,15 @@ type Config interface {
 	// API server's certificate.
 	CACert() string
 
-	// APIAddresses returns the addresses needed to connect to the api server
+	// APIAddresses returns the addresses needed to connect to the api server.
+	// Addresses that have recently been connected to successfully are
+	// returned first, most recent first.
 	APIAddresses() ([]string, error)
 
+	// APIAddressesLastConnected returns the time of the last successful
+	// connection to each API address that has been connected to.
+	APIAddressesLastConnected() map[string]time.Time
+
 	// WriteCommands returns shell commands to write the agent configuration.
 	// It returns an error if the configuration does not have all the right
 	// elements.
@@ -278,6 +290,11 @@ type configSetterOnly interface {
 	// SetAPIHostPorts sets the API host/port addresses to connect to.
 	SetAPIHostPorts(servers [][]network.HostPort)
 
+	// SetAPIAddressConnected records the time of a successful
+	// connection to the given API address, so that it will be
+	// preferred when next connecting.
+	SetAPIAddressConnected(addr string, when time.Time)
+
 	// Migrate takes an existing agent config and applies the given
 	// parameters to change it.
 	//
@@ -361,6 +378,7 @@ type configInternal struct {
 	caCert            string
 	stateDetails      *connectionDetails
 	apiDetails        *connectionDetails
+	apiLastConnected  map[string]time.Time
 	oldPassword       string
 	servingInfo       *params.StateServingInfo
 	values            map[string]string
@@ -545,6 +563,10 @@ func (c0 *configInternal) Clone() Config {
 	// by ConfigSetter methods.
 	c1.stateDetails = c0.stateDetails.clone()
 	c1.apiDetails = c0.apiDetails.clone()
+	c1.apiLastConnected = make(map[string]time.Time, len(c0.apiLastConnected))
+	for addr, when := range c0.apiLastConnected {
+		c1.apiLastConnected[addr] = when
+	}
 	c1.jobs = append([]multiwatcher.MachineJob{}, c0.jobs...)
 	c1.values = make(map[string]string, len(c0.values))
 	for key, val := range c0.values {
@@ -592,6 +614,23 @@ func (c *configInternal) SetAPIHostPorts(servers [][]network.HostPort) {
 	}
 	c.apiDetails.addresses = addrs
 	logger.Infof("API server address details %q written to agent config as %q", servers, addrs)
+	// Forget about addresses that are no longer in use.
+	current := make(map[string]bool)
+	for _, addr := range addrs {
+		current[addr] = true
+	}
+	for addr := range c.apiLastConnected {
+		if !current[addr] {
+			delete(c.apiLastConnected, addr)
+		}
+	}
+}
+
+func (c *configInternal) SetAPIAddressConnected(addr string, when time.Time) {
+	if c.apiLastConnected == nil {
+		c.apiLastConnected = make(map[string]time.Time)
+	}
+	c.apiLastConnected[addr] = when
 }
 
 func (c *configInternal) SetValue(key, value string) {
@@ -691,7 +730,37 @@ func (c *configInternal) APIAddresses() ([]string, error) {
 	if c.apiDetails == nil {
 		return []string{}, errors.New("No apidetails in config")
 	}
-	return append([]string{}, c.apiDetails.addresses...), nil
+	return c.rankedAPIAddresses(), nil
+}
+
+func (c *configInternal) APIAddressesLastConnected() map[string]time.Time {
+	result := make(map[string]time.Time, len(c.apiLastConnected))
+	for addr, when := range c.apiLastConnected {
+		result[addr] = when
+	}
+	return result
+}
+
+// rankedAPIAddresses returns a copy of the API addresses, with those
+// most recently connected to first. Addresses that have never been
+// connected to retain their relative order, after all others.
+func (c *configInternal) rankedAPIAddresses() []string {
+	addrs := append([]string{}, c.apiDetails.addresses...)
+	sort.Stable(byLastConnected{addrs, c.apiLastConnected})
+	return addrs
+}
+
+// byLastConnected sorts addresses by the time they were
+// last connected to, most recent first.
+type byLastConnected struct {
+	addrs         []string
+	lastConnected map[string]time.Time
+}
+
+func (b byLastConnected) Len() int      { return len(b.addrs) }
+func (b byLastConnected) Swap(i, j int) { b.addrs[i], b.addrs[j] = b.addrs[j], b.addrs[i] }
+func (b byLastConnected) Less(i, j int) bool {
+	return b.lastConnected[b.addrs[i]].After(b.lastConnected[b.addrs[j]])
 }
 
 func (c *configInternal) OldPassword() string {
@@ -771,7 +840,7 @@ func (c *configInternal) APIInfo() (*api.Info, bool) {
 		return nil, false
 	}
 	servingInfo, isStateServer := c.StateServingInfo()
-	addrs := c.apiDetails.addresses
+	addrs := c.rankedAPIAddresses()
 	if isStateServer {
 		port := servingInfo.APIPort
 		localAPIAddr := net.JoinHostPort("localhost", strconv.Itoa(port))
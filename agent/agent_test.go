@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -584,6 +585,23 @@ func (*suite) TestWriteAndRead(c *gc.C) {
 	c.Assert(reread, jc.DeepEquals, conf)
 }
 
+func (*suite) TestWriteAndReadLastConnected(c *gc.C) {
+	testParams := attributeParams
+	testParams.Paths.DataDir = c.MkDir()
+	testParams.Paths.LogDir = c.MkDir()
+	conf, err := agent.NewAgentConfig(testParams)
+	c.Assert(err, jc.ErrorIsNil)
+	when := time.Date(2015, 10, 1, 12, 0, 0, 500, time.UTC)
+	conf.SetAPIAddressConnected("localhost:1235", when)
+
+	c.Assert(conf.Write(), gc.IsNil)
+	reread, err := agent.ReadConfig(agent.ConfigPath(conf.DataDir(), conf.Tag()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reread.APIAddressesLastConnected(), jc.DeepEquals, map[string]time.Time{
+		"localhost:1235": when,
+	})
+}
+
 func (*suite) TestAPIInfoMissingAddress(c *gc.C) {
 	conf := agent.EmptyConfig()
 	_, ok := conf.APIInfo()
@@ -777,3 +795,58 @@ func (*suite) TestSetAPIHostPorts(c *gc.C) {
 		"elsewhere.net:4444",
 	})
 }
+
+func (*suite) TestAPIAddressesRankedByLastConnected(c *gc.C) {
+	attrParams := attributeParams
+	attrParams.APIAddresses = []string{"0.1.2.3:1234", "0.1.2.4:1234", "0.1.2.5:1234"}
+	conf, err := agent.NewAgentConfig(attrParams)
+	c.Assert(err, jc.ErrorIsNil)
+
+	t0 := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	conf.SetAPIAddressConnected("0.1.2.4:1234", t0)
+	conf.SetAPIAddressConnected("0.1.2.5:1234", t0.Add(time.Minute))
+
+	addrs, err := conf.APIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []string{"0.1.2.5:1234", "0.1.2.4:1234", "0.1.2.3:1234"})
+
+	apiinfo, ok := conf.APIInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(apiinfo.Addrs, jc.DeepEquals, addrs)
+
+	c.Assert(conf.APIAddressesLastConnected(), jc.DeepEquals, map[string]time.Time{
+		"0.1.2.4:1234": t0,
+		"0.1.2.5:1234": t0.Add(time.Minute),
+	})
+}
+
+func (*suite) TestSetAPIHostPortsForgetsRemovedAddresses(c *gc.C) {
+	conf, err := agent.NewAgentConfig(attributeParams)
+	c.Assert(err, jc.ErrorIsNil)
+
+	t0 := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	conf.SetAPIAddressConnected("localhost:1235", t0)
+	conf.SetAPIAddressConnected("0.1.2.3:1234", t0)
+
+	server := network.NewAddresses("0.1.2.3")
+	server[0].Scope = network.ScopePublic
+	conf.SetAPIHostPorts([][]network.HostPort{
+		network.AddressesWithPort(server, 1234),
+	})
+	c.Assert(conf.APIAddressesLastConnected(), jc.DeepEquals, map[string]time.Time{
+		"0.1.2.3:1234": t0,
+	})
+}
+
+func (*suite) TestCloneCopiesLastConnected(c *gc.C) {
+	conf, err := agent.NewAgentConfig(attributeParams)
+	c.Assert(err, jc.ErrorIsNil)
+	t0 := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	conf.SetAPIAddressConnected("localhost:1235", t0)
+
+	clone := conf.Clone()
+	conf.SetAPIAddressConnected("localhost:1235", t0.Add(time.Hour))
+	c.Assert(clone.APIAddressesLastConnected(), jc.DeepEquals, map[string]time.Time{
+		"localhost:1235": t0,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -44,6 +45,10 @@ type format_1_18Serialization struct {
 	APIAddresses []string `yaml:",omitempty"`
 	APIPassword  string   `yaml:",omitempty"`
 
+	// APILastConnected holds the time, formatted as RFC3339,
+	// of the last successful connection to each API address.
+	APILastConnected map[string]string `yaml:",omitempty"`
+
 	OldPassword string
 	Values      map[string]string
 
@@ -118,6 +123,16 @@ func (formatter_1_18) unmarshal(data []byte) (*configInternal, error) {
 			format.APIPassword,
 		}
 	}
+	for addr, value := range format.APILastConnected {
+		when, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid last connection time for API address %q", addr)
+		}
+		if config.apiLastConnected == nil {
+			config.apiLastConnected = make(map[string]time.Time)
+		}
+		config.apiLastConnected[addr] = when
+	}
 	if len(format.StateServerKey) != 0 {
 		config.servingInfo = &params.StateServingInfo{
 			Cert:           format.StateServerCert,
@@ -187,5 +202,11 @@ func (formatter_1_18) marshal(config *configInternal) ([]byte, error) {
 		format.APIAddresses = config.apiDetails.addresses
 		format.APIPassword = config.apiDetails.password
 	}
+	if len(config.apiLastConnected) > 0 {
+		format.APILastConnected = make(map[string]string)
+		for addr, when := range config.apiLastConnected {
+			format.APILastConnected[addr] = when.UTC().Format(time.RFC3339Nano)
+		}
+	}
 	return goyaml.Marshal(format)
 }
@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+
+	coretools "github.com/juju/juju/tools"
+)
+
+const cachedToolsSuffix = ".tgz"
+
+// ToolsCacheDir returns the directory within dataDir that holds tools
+// tarballs named by their SHA256 checksum, so that tools already on
+// the machine need not be downloaded again.
+func ToolsCacheDir(dataDir string) string {
+	return path.Join(dataDir, "tools", "cache")
+}
+
+// CachedToolsPath returns the path of the cached tools tarball with the
+// given SHA256 checksum.
+func CachedToolsPath(dataDir, sha256 string) string {
+	return path.Join(ToolsCacheDir(dataDir), sha256+cachedToolsSuffix)
+}
+
+// CacheTools reads a gzipped tools tarball from r and stores it in the
+// tools cache within dataDir. The tarball is only stored if its
+// checksum matches tools.SHA256; tools without a checksum are refused.
+func CacheTools(dataDir string, tools *coretools.Tools, r io.Reader) (err error) {
+	if tools.SHA256 == "" {
+		return errors.Errorf("no checksum for tools %s", tools.Version)
+	}
+	cacheDir := ToolsCacheDir(dataDir)
+	if err := os.MkdirAll(cacheDir, dirPerm); err != nil {
+		return errors.Trace(err)
+	}
+	f, err := ioutil.TempFile(cacheDir, "downloading-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		f.Close()
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+	sha256hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, sha256hash), r); err != nil {
+		return errors.Annotate(err, "cannot read tools tarball")
+	}
+	gzipSHA256 := fmt.Sprintf("%x", sha256hash.Sum(nil))
+	if gzipSHA256 != tools.SHA256 {
+		return errors.Errorf("tarball sha256 mismatch, expected %s, got %s", tools.SHA256, gzipSHA256)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(f.Name(), CachedToolsPath(dataDir, tools.SHA256)))
+}
+
+// UnpackCachedTools unpacks the cached tarball with the tools' checksum
+// into the tools directory within dataDir, as UnpackTools does. It
+// returns an error satisfying errors.IsNotFound if there is no such
+// tarball. A cached tarball which cannot be unpacked is removed, so
+// that it will be downloaded again.
+func UnpackCachedTools(dataDir string, tools *coretools.Tools) error {
+	if tools.SHA256 == "" {
+		return errors.NotFoundf("cached tools %s without checksum", tools.Version)
+	}
+	cachedPath := CachedToolsPath(dataDir, tools.SHA256)
+	f, err := os.Open(cachedPath)
+	if os.IsNotExist(err) {
+		return errors.NotFoundf("cached tools %s", tools.Version)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	if err := UnpackTools(dataDir, tools, f); err != nil {
+		removeAll(cachedPath)
+		return errors.Annotatef(err, "cannot unpack cached tools %s", tools.Version)
+	}
+	return nil
+}
+
+// PruneToolsCache removes all cached tools tarballs except those with
+// the given checksums.
+func PruneToolsCache(dataDir string, keep ...string) error {
+	infos, err := ioutil.ReadDir(ToolsCacheDir(dataDir))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	keepNames := make(map[string]bool)
+	for _, sha256 := range keep {
+		keepNames[sha256+cachedToolsSuffix] = true
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if keepNames[name] || !strings.HasSuffix(name, cachedToolsSuffix) {
+			continue
+		}
+		if err := os.Remove(path.Join(ToolsCacheDir(dataDir), name)); err != nil && !os.IsNotExist(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
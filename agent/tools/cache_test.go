@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	agenttools "github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/testing"
+	coretest "github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+type CacheSuite struct {
+	testing.BaseSuite
+	dataDir string
+}
+
+var _ = gc.Suite(&CacheSuite{})
+
+func (s *CacheSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+}
+
+func (s *CacheSuite) makeTools(c *gc.C, vers string) ([]byte, []*testing.TarFile, *coretest.Tools) {
+	files := []*testing.TarFile{
+		testing.NewTarFile("jujud", agenttools.DirPerm, "jujud "+vers),
+	}
+	data, checksum := testing.TarGz(files...)
+	return data, files, &coretest.Tools{
+		URL:     "http://foo/bar",
+		Version: version.MustParseBinary(vers),
+		Size:    int64(len(data)),
+		SHA256:  checksum,
+	}
+}
+
+func (s *CacheSuite) TestCacheAndUnpack(c *gc.C) {
+	data, files, testTools := s.makeTools(c, "1.2.3-quantal-amd64")
+	err := agenttools.CacheTools(s.dataDir, testTools, bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	assertDirNames(c, agenttools.ToolsCacheDir(s.dataDir), []string{testTools.SHA256 + ".tgz"})
+	cached, err := ioutil.ReadFile(agenttools.CachedToolsPath(s.dataDir, testTools.SHA256))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cached, gc.DeepEquals, data)
+
+	err = agenttools.UnpackCachedTools(s.dataDir, testTools)
+	c.Assert(err, jc.ErrorIsNil)
+	assertDirNames(c, agenttools.SharedToolsDir(s.dataDir, testTools.Version), []string{"jujud", toolsFile})
+	assertFileContents(c, agenttools.SharedToolsDir(s.dataDir, testTools.Version), "jujud", files[0].Contents, 0400)
+}
+
+func (s *CacheSuite) TestCacheToolsBadChecksum(c *gc.C) {
+	data, _, testTools := s.makeTools(c, "1.2.3-quantal-amd64")
+	testTools.SHA256 = "1234"
+	err := agenttools.CacheTools(s.dataDir, testTools, bytes.NewReader(data))
+	c.Assert(err, gc.ErrorMatches, "tarball sha256 mismatch, expected 1234, got .*")
+	assertDirNames(c, agenttools.ToolsCacheDir(s.dataDir), []string{})
+}
+
+func (s *CacheSuite) TestCacheToolsNoChecksum(c *gc.C) {
+	data, _, testTools := s.makeTools(c, "1.2.3-quantal-amd64")
+	testTools.SHA256 = ""
+	err := agenttools.CacheTools(s.dataDir, testTools, bytes.NewReader(data))
+	c.Assert(err, gc.ErrorMatches, "no checksum for tools 1.2.3-quantal-amd64")
+	_, err = os.Stat(agenttools.ToolsCacheDir(s.dataDir))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *CacheSuite) TestUnpackCachedToolsNotFound(c *gc.C) {
+	_, _, testTools := s.makeTools(c, "1.2.3-quantal-amd64")
+	err := agenttools.UnpackCachedTools(s.dataDir, testTools)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	testTools.SHA256 = ""
+	err = agenttools.UnpackCachedTools(s.dataDir, testTools)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CacheSuite) TestUnpackCachedToolsCorrupt(c *gc.C) {
+	_, _, testTools := s.makeTools(c, "1.2.3-quantal-amd64")
+	err := os.MkdirAll(agenttools.ToolsCacheDir(s.dataDir), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	cachedPath := agenttools.CachedToolsPath(s.dataDir, testTools.SHA256)
+	err = ioutil.WriteFile(cachedPath, []byte("rubbish"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = agenttools.UnpackCachedTools(s.dataDir, testTools)
+	c.Assert(err, gc.ErrorMatches, "cannot unpack cached tools 1.2.3-quantal-amd64: .*")
+	_, err = os.Stat(cachedPath)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *CacheSuite) TestPruneToolsCache(c *gc.C) {
+	var checksums []string
+	for _, vers := range []string{"1.2.3-quantal-amd64", "1.2.4-quantal-amd64", "1.2.5-quantal-amd64"} {
+		data, _, testTools := s.makeTools(c, vers)
+		err := agenttools.CacheTools(s.dataDir, testTools, bytes.NewReader(data))
+		c.Assert(err, jc.ErrorIsNil)
+		checksums = append(checksums, testTools.SHA256)
+	}
+	err := agenttools.PruneToolsCache(s.dataDir, checksums[0], checksums[2])
+	c.Assert(err, jc.ErrorIsNil)
+	assertDirNames(c, agenttools.ToolsCacheDir(s.dataDir), []string{
+		checksums[0] + ".tgz", checksums[2] + ".tgz",
+	})
+}
+
+func (s *CacheSuite) TestPruneToolsCacheNoCache(c *gc.C) {
+	err := agenttools.PruneToolsCache(s.dataDir)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -20,6 +21,7 @@ import (
 	"github.com/juju/names"
 	"github.com/juju/utils"
 	"github.com/juju/utils/parallel"
+	"golang.org/x/net/context"
 	"golang.org/x/net/websocket"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
 
@@ -39,12 +41,24 @@ var PingPeriod = 1 * time.Minute
 
 // state is the internal implementation of the Connection interface.
 type state struct {
+	// mu guards client, conn, addr, serverRootAddress and the
+	// environTag, serverTag, serverVersion, hostPorts, facadeVersions
+	// and facadeDeprecations reported at login, all of which change
+	// when a lost connection is re-established.
+	mu sync.Mutex
+
 	client *rpc.Conn
 	conn   *websocket.Conn
 
 	// addr is the address used to connect to the API server.
 	addr string
 
+	// info, opts and loginFunc hold the parameters the connection
+	// was opened with, for use when re-establishing it.
+	info      *Info
+	opts      DialOpts
+	loginFunc func(st *state, tag names.Tag, pwd, nonce string) error
+
 	// cookieURL is the URL that HTTP cookies for the API
 	// will be associated with (specifically macaroon auth cookies).
 	cookieURL *url.URL
@@ -70,6 +84,15 @@ type state struct {
 	// Login
 	facadeVersions map[string][]int
 
+	// facadeDeprecations holds the notices for facade versions that
+	// Login reported as slated for removal, by facade and version.
+	facadeDeprecations map[string]map[int]string
+
+	// deprecationMu guards warnedDeprecations, which holds the
+	// deprecated facade versions that have already been warned about.
+	deprecationMu      sync.Mutex
+	warnedDeprecations map[string]bool
+
 	// authTag holds the authenticated entity's tag after login.
 	authTag names.Tag
 
@@ -147,16 +170,13 @@ func open(info *Info, opts DialOpts, loginFunc func(st *state, tag names.Tag, pw
 		bakeryClient.Client = &httpc
 	}
 	apiHost := conn.Config().Location.Host
-	bakeryClient.Client.Transport = &hostSwitchingTransport{
-		primaryHost: apiHost,
-		primary:     utils.NewHttpTLSTransport(tlsConfig),
-		fallback:    http.DefaultTransport,
-	}
-
 	st := &state{
-		client: client,
-		conn:   conn,
-		addr:   apiHost,
+		client:    client,
+		conn:      conn,
+		addr:      apiHost,
+		info:      info,
+		opts:      opts,
+		loginFunc: loginFunc,
 		cookieURL: &url.URL{
 			Scheme: "https",
 			Host:   conn.Config().Location.Host,
@@ -172,6 +192,11 @@ func open(info *Info, opts DialOpts, loginFunc func(st *state, tag names.Tag, pw
 		tlsConfig:    tlsConfig,
 		bakeryClient: bakeryClient,
 	}
+	bakeryClient.Client.Transport = &hostSwitchingTransport{
+		primaryHost: st.Addr,
+		primary:     utils.NewHttpTLSTransport(tlsConfig),
+		fallback:    http.DefaultTransport,
+	}
 	if info.Tag != nil || info.Password != "" || info.UseMacaroons {
 		if err := loginFunc(st, info.Tag, info.Password, info.Nonce); err != nil {
 			conn.Close()
@@ -191,14 +216,16 @@ func open(info *Info, opts DialOpts, loginFunc func(st *state, tag names.Tag, pw
 // This makes it possible to use a different set of root
 // CAs for the API and all other hosts.
 type hostSwitchingTransport struct {
-	primaryHost string
+	// primaryHost returns the address of the API server currently
+	// in use, which may change if the connection is re-established.
+	primaryHost func() string
 	primary     http.RoundTripper
 	fallback    http.RoundTripper
 }
 
 // RoundTrip implements http.RoundTripper.RoundTrip.
 func (t *hostSwitchingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	if req.URL.Host == t.primaryHost {
+	if req.URL.Host == t.primaryHost() {
 		return t.primary.RoundTrip(req)
 	}
 	return t.fallback.RoundTrip(req)
@@ -328,7 +355,7 @@ func (st *state) connectStream(path string, attrs url.Values) (base.Stream, erro
 	}
 	target := url.URL{
 		Scheme:   "wss",
-		Host:     st.addr,
+		Host:     st.Addr(),
 		Path:     path,
 		RawQuery: attrs.Encode(),
 	}
@@ -486,14 +513,39 @@ func createWebsocketDialer(cfg *websocket.Config, opts DialOpts) func(<-chan str
 	}
 }
 
+// heartbeatMonitor closes the broken channel when the connection is
+// lost, after trying to re-establish it if DialOpts.Reconnect is set.
 func (s *state) heartbeatMonitor() {
+	defer close(s.broken)
+	for {
+		s.waitLost()
+		if !s.opts.Reconnect || s.isClosed() {
+			return
+		}
+		logger.Warningf("connection to API server at %q lost, reconnecting", s.Addr())
+		s.notify(ConnectionLost)
+		if err := s.reconnect(); err != nil {
+			if !s.isClosed() {
+				logger.Errorf("%v", err)
+			}
+			s.notify(ConnectionBroken)
+			return
+		}
+		s.notify(ConnectionRestored)
+	}
+}
+
+// waitLost returns when the connection to the API server fails to
+// respond to a ping or its transport fails.
+func (s *state) waitLost() {
+	client := s.RPCClient()
 	for {
 		if err := s.Ping(); err != nil {
-			close(s.broken)
 			return
 		}
 		select {
 		case <-time.After(PingPeriod):
+		case <-client.Dead():
 		case <-s.closed:
 		}
 	}
@@ -509,7 +561,17 @@ func (s *state) Ping() error {
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
 func (s *state) APICall(facade string, version int, id, method string, args, response interface{}) error {
-	err := s.client.Call(rpc.Request{
+	return s.APICallContext(context.Background(), facade, version, id, method, args, response)
+}
+
+// APICallContext is like APICall, but returns the context's error
+// without waiting for the call to complete if the context is done
+// first.
+func (s *state) APICallContext(ctx context.Context, facade string, version int, id, method string, args, response interface{}) error {
+	if s.opts.CorrelationId != "" && rpc.CorrelationId(ctx) == "" {
+		ctx = rpc.WithCorrelationId(ctx, s.opts.CorrelationId)
+	}
+	err := s.RPCClient().CallContext(ctx, rpc.Request{
 		Type:    facade,
 		Version: version,
 		Id:      id,
@@ -519,12 +581,15 @@ func (s *state) APICall(facade string, version int, id, method string, args, res
 }
 
 func (s *state) Close() error {
-	err := s.client.Close()
+	s.mu.Lock()
 	select {
 	case <-s.closed:
 	default:
 		close(s.closed)
 	}
+	client := s.client
+	s.mu.Unlock()
+	err := client.Close()
 	<-s.broken
 	return err
 }
@@ -538,21 +603,29 @@ func (s *state) Broken() <-chan struct{} {
 // functions can tickle parts of the API that the conventional entry
 // points don't reach. This is exported for testing purposes only.
 func (s *state) RPCClient() *rpc.Conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.client
 }
 
 // Addr returns the address used to connect to the API server.
 func (s *state) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.addr
 }
 
 // EnvironTag returns the tag of the environment we are connected to.
 func (s *state) EnvironTag() (names.EnvironTag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return names.ParseEnvironTag(s.environTag)
 }
 
 // ServerTag returns the tag of the server we are connected to.
 func (s *state) ServerTag() (names.EnvironTag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return names.ParseEnvironTag(s.serverTag)
 }
 
@@ -567,6 +640,8 @@ func (s *state) ServerTag() (names.EnvironTag, error) {
 func (s *state) APIHostPorts() [][]network.HostPort {
 	// NOTE: We're making a copy of s.hostPorts before returning it,
 	// for safety.
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	hostPorts := make([][]network.HostPort, len(s.hostPorts))
 	for i, server := range s.hostPorts {
 		hostPorts[i] = append([]network.HostPort{}, server...)
@@ -576,6 +651,8 @@ func (s *state) APIHostPorts() [][]network.HostPort {
 
 // AllFacadeVersions returns what versions we know about for all facades
 func (s *state) AllFacadeVersions() map[string][]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	facades := make(map[string][]int, len(s.facadeVersions))
 	for name, versions := range s.facadeVersions {
 		facades[name] = append([]int{}, versions...)
@@ -590,11 +667,40 @@ func (s *state) AllFacadeVersions() map[string][]int {
 // Facade we will want to use. It needs to line up the versions that the server
 // reports to us, with the versions that our client knows how to use.
 func (s *state) BestFacadeVersion(facade string) int {
-	return bestVersion(facadeVersions[facade], s.facadeVersions[facade])
+	s.mu.Lock()
+	serverVersions := s.facadeVersions[facade]
+	s.mu.Unlock()
+	version := bestVersion(facadeVersions[facade], serverVersions)
+	s.warnIfDeprecated(facade, version)
+	return version
+}
+
+// warnIfDeprecated logs a warning, once per connection, if the server
+// has reported that the given facade version is slated for removal.
+func (s *state) warnIfDeprecated(facade string, version int) {
+	s.mu.Lock()
+	notice, ok := s.facadeDeprecations[facade][version]
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	key := fmt.Sprintf("%s(%d)", facade, version)
+	s.deprecationMu.Lock()
+	defer s.deprecationMu.Unlock()
+	if s.warnedDeprecations[key] {
+		return
+	}
+	if s.warnedDeprecations == nil {
+		s.warnedDeprecations = make(map[string]bool)
+	}
+	s.warnedDeprecations[key] = true
+	logger.Warningf("API server reports facade %s is deprecated: %s", key, notice)
 }
 
 // serverRoot returns the cached API server address and port used
 // to login, prefixed with "<URI scheme>://" (usually https).
 func (s *state) serverRoot() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.serverScheme + "://" + s.serverRootAddress
 }
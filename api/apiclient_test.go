@@ -4,8 +4,12 @@
 package api_test
 
 import (
+	"errors"
+	"io"
 	"net"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/juju/names"
 	"github.com/juju/testing"
@@ -17,6 +21,7 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 )
 
@@ -158,6 +163,137 @@ func (s *apiclientSuite) TestDialWebsocketStopped(c *gc.C) {
 	c.Assert(result, gc.IsNil)
 }
 
+func (s *apiclientSuite) TestBrokenWithoutReconnect(c *gc.C) {
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	api.BreakTransport(st)
+	select {
+	case <-st.Broken():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("connection not broken")
+	}
+}
+
+func (s *apiclientSuite) openReconnecting(c *gc.C, opts api.DialOpts) (api.Connection, <-chan api.ConnectionState) {
+	s.PatchValue(api.ReconnectInitialDelay, time.Millisecond)
+	states := make(chan api.ConnectionState, 10)
+	opts.Reconnect = true
+	opts.ConnectionStateChanged = func(cs api.ConnectionState) {
+		states <- cs
+	}
+	st, err := api.Open(s.APIInfo(c), opts)
+	c.Assert(err, jc.ErrorIsNil)
+	return st, states
+}
+
+func assertConnectionStates(c *gc.C, states <-chan api.ConnectionState, expected ...api.ConnectionState) {
+	for _, expect := range expected {
+		select {
+		case cs := <-states:
+			c.Assert(cs, gc.Equals, expect)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for connection %v", expect)
+		}
+	}
+}
+
+func (s *apiclientSuite) TestReconnect(c *gc.C) {
+	st, states := s.openReconnecting(c, api.DialOpts{})
+	defer st.Close()
+
+	api.BreakTransport(st)
+	assertConnectionStates(c, states, api.ConnectionLost, api.ConnectionRestored)
+
+	// The connection has been logged in again and is usable.
+	_, err := st.Client().AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-st.Broken():
+		c.Fatalf("connection broken")
+	default:
+	}
+}
+
+func (s *apiclientSuite) TestReconnectConcurrentCalls(c *gc.C) {
+	st, states := s.openReconnecting(c, api.DialOpts{})
+	defer st.Close()
+
+	// Make calls throughout the reconnect. They may fail while the
+	// connection is down, but none should reach a connection that
+	// has not yet logged in.
+	done := make(chan struct{})
+	errs := make(chan error, 3)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				st.BestFacadeVersion("Client")
+				st.APIHostPorts()
+				_, err := st.Client().AgentVersion()
+				if params.IsCodeUnauthorized(err) {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	api.BreakTransport(st)
+	assertConnectionStates(c, states, api.ConnectionLost, api.ConnectionRestored)
+	close(done)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		c.Errorf("call failed during reconnect: %v", err)
+	}
+
+	_, err := st.Client().AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *apiclientSuite) TestReconnectGivesUp(c *gc.C) {
+	st, states := s.openReconnecting(c, api.DialOpts{
+		ReconnectTimeout: 50 * time.Millisecond,
+	})
+	defer st.Close()
+
+	var dials int32
+	s.PatchValue(api.NewWebsocketDialerPtr, func(*websocket.Config, api.DialOpts) func(<-chan struct{}) (io.Closer, error) {
+		return func(<-chan struct{}) (io.Closer, error) {
+			atomic.AddInt32(&dials, 1)
+			return nil, errors.New("no route to host")
+		}
+	})
+	api.BreakTransport(st)
+	assertConnectionStates(c, states, api.ConnectionLost, api.ConnectionBroken)
+	select {
+	case <-st.Broken():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("connection not broken")
+	}
+	c.Assert(atomic.LoadInt32(&dials) > 1, jc.IsTrue)
+}
+
+func (s *apiclientSuite) TestCloseStopsReconnect(c *gc.C) {
+	st, states := s.openReconnecting(c, api.DialOpts{})
+	err := st.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case cs := <-states:
+		c.Fatalf("unexpected connection state %v", cs)
+	default:
+	}
+}
+
 func assertConnAddrForEnv(c *gc.C, conn *websocket.Conn, addr, envUUID, tail string) {
 	c.Assert(conn.RemoteAddr(), gc.Matches, "^wss://"+addr+"/environment/"+envUUID+tail+"$")
 }
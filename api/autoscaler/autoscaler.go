@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const autoscalerFacade = "Autoscaler"
+
+// ServiceInstanceGroup links a service to a cloud instance group.
+type ServiceInstanceGroup struct {
+	Service       names.ServiceTag
+	InstanceGroup string
+}
+
+// InstanceGroupSize holds the number of instances observed in the
+// cloud instance group linked to a service.
+type InstanceGroupSize struct {
+	ServiceInstanceGroup
+	Size int
+}
+
+// Facade provides access to the Autoscaler API facade.
+type Facade struct {
+	*common.EnvironWatcher
+	facade base.FacadeCaller
+}
+
+// NewFacade creates a new client-side Autoscaler facade.
+func NewFacade(caller base.APICaller) *Facade {
+	facadeCaller := base.NewFacadeCaller(caller, autoscalerFacade)
+	return &Facade{
+		EnvironWatcher: common.NewEnvironWatcher(facadeCaller),
+		facade:         facadeCaller,
+	}
+}
+
+// ServiceInstanceGroups returns the services that are
+// linked to cloud instance groups.
+func (f *Facade) ServiceInstanceGroups() ([]ServiceInstanceGroup, error) {
+	var result params.ServiceInstanceGroupsResult
+	if err := f.facade.FacadeCall("ServiceInstanceGroups", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	groups := make([]ServiceInstanceGroup, len(result.Results))
+	for i, r := range result.Results {
+		tag, err := names.ParseServiceTag(r.ServiceTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		groups[i] = ServiceInstanceGroup{
+			Service:       tag,
+			InstanceGroup: r.InstanceGroup,
+		}
+	}
+	return groups, nil
+}
+
+// SetInstanceGroupSizes reports the observed sizes of cloud instance
+// groups, so that the units of the linked services can be adjusted
+// to match.
+func (f *Facade) SetInstanceGroupSizes(sizes []InstanceGroupSize) error {
+	args := params.InstanceGroupSizes{
+		Sizes: make([]params.InstanceGroupSize, len(sizes)),
+	}
+	for i, size := range sizes {
+		args.Sizes[i] = params.InstanceGroupSize{
+			ServiceTag:    size.Service.String(),
+			InstanceGroup: size.InstanceGroup,
+			Size:          size.Size,
+		}
+	}
+	var results params.ErrorResults
+	if err := f.facade.FacadeCall("SetInstanceGroupSizes", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/autoscaler"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type autoscalerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&autoscalerSuite{})
+
+func (s *autoscalerSuite) TestServiceInstanceGroups(c *gc.C) {
+	var called bool
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "Autoscaler")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "ServiceInstanceGroups")
+		c.Check(arg, gc.IsNil)
+		c.Assert(result, gc.FitsTypeOf, &params.ServiceInstanceGroupsResult{})
+		*(result.(*params.ServiceInstanceGroupsResult)) = params.ServiceInstanceGroupsResult{
+			Results: []params.ServiceInstanceGroup{{
+				ServiceTag:    "service-wordpress",
+				InstanceGroup: "wordpress-group",
+			}},
+		}
+		return nil
+	})
+	groups, err := autoscaler.NewFacade(apiCaller).ServiceInstanceGroups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(groups, jc.DeepEquals, []autoscaler.ServiceInstanceGroup{{
+		Service:       names.NewServiceTag("wordpress"),
+		InstanceGroup: "wordpress-group",
+	}})
+}
+
+func (s *autoscalerSuite) TestSetInstanceGroupSizes(c *gc.C) {
+	var called bool
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "Autoscaler")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetInstanceGroupSizes")
+		c.Check(arg, jc.DeepEquals, params.InstanceGroupSizes{
+			Sizes: []params.InstanceGroupSize{{
+				ServiceTag:    "service-wordpress",
+				InstanceGroup: "wordpress-group",
+				Size:          3,
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		return nil
+	})
+	err := autoscaler.NewFacade(apiCaller).SetInstanceGroupSizes([]autoscaler.InstanceGroupSize{{
+		ServiceInstanceGroup: autoscaler.ServiceInstanceGroup{
+			Service:       names.NewServiceTag("wordpress"),
+			InstanceGroup: "wordpress-group",
+		},
+		Size: 3,
+	}})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
@@ -4,8 +4,12 @@
 package backups
 
 import (
+	"crypto/sha1"
+	"encoding/base64"
+	"hash"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/httprequest"
@@ -18,7 +22,9 @@ type downloadParams struct {
 	Body              params.BackupsDownloadArgs `httprequest:",body"`
 }
 
-// Download returns an io.ReadCloser for the given backup id.
+// Download returns an io.ReadCloser for the given backup id. If the
+// server reports the archive's checksum, it is verified as the archive
+// is read, and reading its end fails if the checksum does not match.
 func (c *Client) Download(id string) (io.ReadCloser, error) {
 	// Send the request.
 	var resp *http.Response
@@ -33,5 +39,52 @@ func (c *Client) Download(id string) (io.ReadCloser, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return resp.Body, nil
+	checksum, err := responseChecksum(resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, errors.Trace(err)
+	}
+	if checksum == "" {
+		return resp.Body, nil
+	}
+	return &checkedArchive{
+		ReadCloser: resp.Body,
+		hash:       sha1.New(),
+		checksum:   checksum,
+	}, nil
+}
+
+// responseChecksum returns the base64-encoded SHA-1 checksum held in
+// the Digest header of the given response, or "" if there is none.
+func responseChecksum(resp *http.Response) (string, error) {
+	header := resp.Header.Get("Digest")
+	if header == "" {
+		return "", nil
+	}
+	prefix := string(params.DigestSHA) + "="
+	if !strings.HasPrefix(header, prefix) {
+		return "", errors.Errorf("unsupported digest %q", header)
+	}
+	return strings.TrimPrefix(header, prefix), nil
+}
+
+// checkedArchive is a downloaded backup archive whose checksum is
+// verified when it has been read in full.
+type checkedArchive struct {
+	io.ReadCloser
+	hash     hash.Hash
+	checksum string
+}
+
+// Read implements io.Reader.
+func (a *checkedArchive) Read(p []byte) (int, error) {
+	n, err := a.ReadCloser.Read(p)
+	a.hash.Write(p[:n])
+	if err == io.EOF {
+		checksum := base64.StdEncoding.EncodeToString(a.hash.Sum(nil))
+		if checksum != a.checksum {
+			return n, errors.Errorf("archive checksum mismatch: expected %q, got %q", a.checksum, checksum)
+		}
+	}
+	return n, err
 }
@@ -4,6 +4,8 @@
 package backups_test
 
 import (
+	"crypto/sha1"
+	"encoding/base64"
 	"io/ioutil"
 	"strings"
 
@@ -41,6 +43,43 @@ func (s *downloadSuite) TestSuccessfulRequest(c *gc.C) {
 	c.Check(string(resultData), gc.Equals, "<compressed archive data>")
 }
 
+func (s *downloadSuite) addBackup(c *gc.C, data, checksum string) string {
+	store := backups.NewStorage(s.State)
+	defer store.Close()
+	backupsState := backups.NewBackups(store)
+
+	meta, err := backups.NewMetadataState(s.State, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = meta.MarkComplete(int64(len(data)), checksum)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := backupsState.Add(strings.NewReader(data), meta)
+	c.Assert(err, jc.ErrorIsNil)
+	return id
+}
+
+func (s *downloadSuite) TestChecksumVerified(c *gc.C) {
+	data := "<compressed archive data>"
+	sum := sha1.Sum([]byte(data))
+	id := s.addBackup(c, data, base64.StdEncoding.EncodeToString(sum[:]))
+
+	resultArchive, err := s.client.Download(id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resultArchive.Close()
+	resultData, err := ioutil.ReadAll(resultArchive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(resultData), gc.Equals, data)
+}
+
+func (s *downloadSuite) TestChecksumMismatch(c *gc.C) {
+	id := s.addBackup(c, "<compressed archive data>", "bogus")
+
+	resultArchive, err := s.client.Download(id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resultArchive.Close()
+	_, err = ioutil.ReadAll(resultArchive)
+	c.Assert(err, gc.ErrorMatches, `archive checksum mismatch: expected "bogus", got ".*"`)
+}
+
 func (s *downloadSuite) TestFailedRequest(c *gc.C) {
 	resultArchive, err := s.client.Download("unknown")
 	c.Assert(err, gc.ErrorMatches, `GET https://.*/environment/.*/backups: backup metadata "unknown" not found`)
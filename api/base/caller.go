@@ -7,8 +7,10 @@ import (
 	"io"
 	"net/url"
 
+	"github.com/juju/errors"
 	"github.com/juju/httprequest"
 	"github.com/juju/names"
+	"golang.org/x/net/context"
 )
 
 // APICaller is implemented by the client-facing State object.
@@ -41,6 +43,28 @@ type APICaller interface {
 	StreamConnector
 }
 
+// ContextAPICaller is implemented by APICallers whose calls can be
+// abandoned when a context is done.
+type ContextAPICaller interface {
+	// APICallContext is like APICaller.APICall, but returns the
+	// context's error without waiting for the call to complete if
+	// the context is done first.
+	APICallContext(ctx context.Context, objType string, version int, id, request string, params, response interface{}) error
+}
+
+// APICallContext makes the given call with caller, abandoning it when
+// ctx is done if caller implements ContextAPICaller. Otherwise the
+// context is only checked before the call is made.
+func APICallContext(ctx context.Context, caller APICaller, objType string, version int, id, request string, params, response interface{}) error {
+	if caller, ok := caller.(ContextAPICaller); ok {
+		return caller.APICallContext(ctx, objType, version, id, request, params, response)
+	}
+	if err := ctx.Err(); err != nil {
+		return errors.Trace(err)
+	}
+	return caller.APICall(objType, version, id, request, params, response)
+}
+
 // StreamConnector is implemented by the client-facing State object.
 type StreamConnector interface {
 	// ConnectStream connects to the given HTTP websocket
@@ -106,6 +130,14 @@ func (fc facadeCaller) FacadeCall(request string, params, response interface{})
 		request, params, response)
 }
 
+// FacadeCallContext is like FacadeCall, but the call is abandoned when
+// ctx is done.
+func (fc facadeCaller) FacadeCallContext(ctx context.Context, request string, params, response interface{}) error {
+	return APICallContext(ctx, fc.caller,
+		fc.facadeName, fc.bestVersion, "",
+		request, params, response)
+}
+
 // Name returns the facade name.
 func (fc facadeCaller) Name() string {
 	return fc.facadeName
@@ -141,3 +173,35 @@ func NewFacadeCallerForVersion(caller APICaller, facadeName string, version int)
 		caller:      caller,
 	}
 }
+
+// ContextFacadeCaller is implemented by FacadeCallers whose calls can
+// be abandoned when a context is done.
+type ContextFacadeCaller interface {
+	FacadeCallContext(ctx context.Context, request string, params, response interface{}) error
+}
+
+// NewContextFacadeCaller returns a FacadeCaller that makes all its
+// calls through caller with the given context, so that they are
+// abandoned when the context is done.
+func NewContextFacadeCaller(caller FacadeCaller, ctx context.Context) FacadeCaller {
+	return contextFacadeCaller{
+		FacadeCaller: caller,
+		ctx:          ctx,
+	}
+}
+
+type contextFacadeCaller struct {
+	FacadeCaller
+	ctx context.Context
+}
+
+// FacadeCall implements FacadeCaller.FacadeCall.
+func (fc contextFacadeCaller) FacadeCall(request string, params, response interface{}) error {
+	if caller, ok := fc.FacadeCaller.(ContextFacadeCaller); ok {
+		return caller.FacadeCallContext(fc.ctx, request, params, response)
+	}
+	if err := fc.ctx.Err(); err != nil {
+		return errors.Trace(err)
+	}
+	return fc.FacadeCaller.FacadeCall(request, params, response)
+}
@@ -4,6 +4,8 @@
 package testing
 
 import (
+	"golang.org/x/net/context"
+
 	"github.com/juju/juju/api/base"
 )
 
@@ -26,3 +28,12 @@ type facadeWrapper struct {
 func (f *facadeWrapper) FacadeCall(request string, params, response interface{}) error {
 	return f.facadeCall(request, params, response)
 }
+
+// FacadeCallContext diverts context-aware calls to the patched function
+// too, rather than letting them reach the wrapped FacadeCaller.
+func (f *facadeWrapper) FacadeCallContext(ctx context.Context, request string, params, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.facadeCall(request, params, response)
+}
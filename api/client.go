@@ -5,6 +5,8 @@ package api
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,9 +18,11 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/httprequest"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"golang.org/x/net/context"
 	"golang.org/x/net/websocket"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/macaroon.v1"
@@ -39,6 +43,16 @@ type Client struct {
 	st     *state
 }
 
+// WithContext returns a Client that makes the same calls as c, but
+// abandons them when ctx is done.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	return &Client{
+		ClientFacade: c.ClientFacade,
+		facade:       base.NewContextFacadeCaller(c.facade, ctx),
+		st:           c.st,
+	}
+}
+
 // Status returns the status of the juju environment.
 func (c *Client) Status(patterns []string) (*params.FullStatus, error) {
 	var result params.FullStatus
@@ -68,6 +82,70 @@ func (c *Client) UnitStatusHistory(kind params.HistoryKind, unitName string, siz
 	return &results, nil
 }
 
+// MachineStatusHistory retrieves the last <size> results of
+// <kind:combined|machine|instance> status for the <machineId> machine.
+// It requires version 1 of the Client facade.
+func (c *Client) MachineStatusHistory(kind params.HistoryKind, machineId string, size int) (*params.UnitStatusHistory, error) {
+	if c.facade.BestAPIVersion() < 1 {
+		return &params.UnitStatusHistory{}, errors.NotImplementedf("MachineStatusHistory")
+	}
+	var results params.UnitStatusHistory
+	args := params.StatusHistory{
+		Kind: kind,
+		Size: size,
+		Name: machineId,
+	}
+	err := c.facade.FacadeCall("MachineStatusHistory", args, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return &params.UnitStatusHistory{}, errors.NotImplementedf("MachineStatusHistory")
+		}
+		return &params.UnitStatusHistory{}, errors.Trace(err)
+	}
+	return &results, nil
+}
+
+// ServiceStatusHistory retrieves the last <size> results of
+// <kind:combined|service> status for the <serviceName> service.
+func (c *Client) ServiceStatusHistory(kind params.HistoryKind, serviceName string, size int) (*params.UnitStatusHistory, error) {
+	var results params.UnitStatusHistory
+	args := params.StatusHistory{
+		Kind: kind,
+		Size: size,
+		Name: serviceName,
+	}
+	err := c.facade.FacadeCall("ServiceStatusHistory", args, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return &params.UnitStatusHistory{}, errors.NotImplementedf("ServiceStatusHistory")
+		}
+		return &params.UnitStatusHistory{}, errors.Trace(err)
+	}
+	return &results, nil
+}
+
+// ListProcesses returns the workload processes tracked by the given
+// units, or by all units if none are given, optionally restricted to
+// processes of the given types and statuses. Up to historySize past
+// statuses are returned for each process.
+func (c *Client) ListProcesses(units, types, statuses []string, historySize int) ([]params.UnitProcessesResult, error) {
+	var results params.ListProcessesResults
+	args := params.ListProcessesArgs{
+		Units:       units,
+		Types:       types,
+		Statuses:    statuses,
+		HistorySize: historySize,
+	}
+	err := c.facade.FacadeCall("ListProcesses", args, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("ListProcesses")
+		}
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
 // LegacyStatus is a stub version of Status that 1.16 introduced. Should be
 // removed along with structs when api versioning makes it safe to do so.
 func (c *Client) LegacyStatus() (*params.LegacyStatus, error) {
@@ -107,6 +185,20 @@ func (c *Client) Resolved(unit string, retry bool) error {
 	return c.facade.FacadeCall("Resolved", p, nil)
 }
 
+// ResolveUnits clears errors on the given units, returning the result
+// for each unit.
+func (c *Client) ResolveUnits(units []params.Resolved) ([]params.ErrorResult, error) {
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("ResolveUnits", params.ResolveUnits{Units: units}, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("ResolveUnits")
+		}
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
 // RetryProvisioning updates the provisioning status of a machine allowing the
 // provisioner to retry.
 func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error) {
@@ -120,6 +212,26 @@ func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.Error
 	return results.Results, err
 }
 
+// ForceRetryProvisioning is like RetryProvisioning, but first stops and
+// forgets the failed instances of any of the machines that have them, so
+// that new instances are started. It requires version 2 of the Client
+// facade.
+func (c *Client) ForceRetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error) {
+	if c.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("ForceRetryProvisioning() (need V2+)")
+	}
+	p := params.RetryProvisioning{
+		Entities: make([]params.Entity, len(machines)),
+		Force:    true,
+	}
+	for i, machine := range machines {
+		p.Entities[i] = params.Entity{Tag: machine.String()}
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("RetryProvisioning", p, &results)
+	return results.Results, err
+}
+
 // PublicAddress returns the public address of the specified
 // machine or unit. For a machine, target is an id not a tag.
 func (c *Client) PublicAddress(target string) (string, error) {
@@ -129,6 +241,37 @@ func (c *Client) PublicAddress(target string) (string, error) {
 	return results.PublicAddress, err
 }
 
+// SSHHostKeys returns the public SSH host keys recorded for the
+// specified machine, or for the machine hosting the specified unit.
+func (c *Client) SSHHostKeys(target string) ([]string, error) {
+	var results params.SSHHostKeysResults
+	p := params.SSHHostKeysTarget{Target: target}
+	err := c.facade.FacadeCall("SSHHostKeys", p, &results)
+	return results.PublicKeys, err
+}
+
+// ProblemReports returns the problem reports uploaded by agents in
+// the environment, newest first.
+func (c *Client) ProblemReports() ([]params.ProblemReport, error) {
+	var results params.ProblemReports
+	err := c.facade.FacadeCall("ProblemReports", nil, &results)
+	return results.Reports, err
+}
+
+// AuditLog returns the entries in the environment's audit log that
+// match the given filter, newest first.
+func (c *Client) AuditLog(filter params.AuditLogFilter) ([]params.AuditLogEntry, error) {
+	var results params.AuditLogResults
+	err := c.facade.FacadeCall("AuditLog", filter, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("AuditLog")
+		}
+		return nil, errors.Trace(err)
+	}
+	return results.Entries, nil
+}
+
 // PrivateAddress returns the private address of the specified
 // machine or unit.
 func (c *Client) PrivateAddress(target string) (string, error) {
@@ -225,6 +368,21 @@ func (c *Client) ForceDestroyMachines(machines ...string) error {
 	return c.facade.FacadeCall("DestroyMachines", params, nil)
 }
 
+// DestroyMachinesBulk removes the given machines, along with all their
+// units if force is true, returning the result for each machine.
+func (c *Client) DestroyMachinesBulk(force bool, machines ...string) ([]params.ErrorResult, error) {
+	var results params.ErrorResults
+	args := params.DestroyMachines{Force: force, MachineNames: machines}
+	err := c.facade.FacadeCall("DestroyMachinesBulk", args, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("DestroyMachinesBulk")
+		}
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
 // ServiceExpose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) ServiceExpose(service string) error {
@@ -295,6 +453,30 @@ func (c *Client) ServiceSetCharm(serviceName string, charmUrl string, force bool
 	return c.facade.FacadeCall("ServiceSetCharm", args, nil)
 }
 
+// ServiceSetUpgradeStrategy sets the way in which charm upgrades are
+// rolled out to the units of the given service.
+func (c *Client) ServiceSetUpgradeStrategy(serviceName, strategy string, maxUnavailable int) error {
+	if c.facade.BestAPIVersion() < 4 {
+		return errors.NotImplementedf("ServiceSetUpgradeStrategy() (need V4+)")
+	}
+	args := params.ServiceSetUpgradeStrategy{
+		ServiceName:    serviceName,
+		Strategy:       strategy,
+		MaxUnavailable: maxUnavailable,
+	}
+	return c.facade.FacadeCall("ServiceSetUpgradeStrategy", args, nil)
+}
+
+// ServiceSetAddressPolicy sets the policy used to select the public and
+// private addresses of the units of the given service.
+func (c *Client) ServiceSetAddressPolicy(serviceName, policy string) error {
+	args := params.ServiceSetAddressPolicy{
+		ServiceName: serviceName,
+		Policy:      policy,
+	}
+	return c.facade.FacadeCall("ServiceSetAddressPolicy", args, nil)
+}
+
 // ServiceGetCharmURL returns the charm URL the given service is
 // running at present.
 func (c *Client) ServiceGetCharmURL(serviceName string) (*charm.URL, error) {
@@ -422,14 +604,22 @@ func (c *Client) EnvironmentUUID() string {
 	return tag.Id()
 }
 
-// ShareEnvironment allows the given users access to the environment.
+// ShareEnvironment allows the given users administrator access to the
+// environment.
 func (c *Client) ShareEnvironment(users ...names.UserTag) error {
+	return c.ShareEnvironmentWithAccess(params.EnvAdminAccess, users...)
+}
+
+// ShareEnvironmentWithAccess allows the given users access to the
+// environment, at the given access level.
+func (c *Client) ShareEnvironmentWithAccess(access string, users ...names.UserTag) error {
 	var args params.ModifyEnvironUsers
 	for _, user := range users {
 		if &user != nil {
 			args.Changes = append(args.Changes, params.ModifyEnvironUser{
 				UserTag: user.String(),
 				Action:  params.AddEnvUser,
+				Access:  access,
 			})
 		}
 	}
@@ -449,6 +639,26 @@ func (c *Client) ShareEnvironment(users ...names.UserTag) error {
 	return result.Combine()
 }
 
+// SetEnvironmentAccess changes the access the given users, who must
+// already have access to the environment, have to it.
+func (c *Client) SetEnvironmentAccess(access string, users ...names.UserTag) error {
+	var args params.ModifyEnvironUsers
+	for _, user := range users {
+		args.Changes = append(args.Changes, params.ModifyEnvironUser{
+			UserTag: user.String(),
+			Action:  params.SetEnvUserAccess,
+			Access:  access,
+		})
+	}
+
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ShareEnvironment", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.Combine()
+}
+
 // EnvironmentUserInfo returns information on all users in the environment.
 func (c *Client) EnvironmentUserInfo() ([]params.EnvUserInfo, error) {
 	var results params.EnvUserInfoResults
@@ -593,6 +803,9 @@ func (c *Client) RunOnAllMachines(commands string, timeout time.Duration) ([]par
 // Run the Commands specified on the machines identified through the ids
 // provided in the machines, services and units slices.
 func (c *Client) Run(run params.RunParams) ([]params.RunResult, error) {
+	if (run.RelationId != "" || run.RemoteUnit != "") && c.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("Run() in a relation context (need V3+)")
+	}
 	var results params.RunResults
 	err := c.facade.FacadeCall("Run", run, &results)
 	return results.Results, err
@@ -644,14 +857,17 @@ func (c *Client) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, err
 		return nil, errors.Errorf("unknown charm type %T", ch)
 	}
 
-	req, err := http.NewRequest("POST", "/charms?series="+curl.Series, nil)
+	hash := sha256.New()
+	size, err := io.Copy(hash, archive)
 	if err != nil {
-		return nil, errors.Annotate(err, "cannot create upload request")
+		return nil, errors.Annotate(err, "cannot read packaged charm")
 	}
-	req.Header.Set("Content-Type", "application/zip")
-
-	var resp params.CharmsResponse
-	if err := httpClient.Do(req, archive, &resp); err != nil {
+	digest := base64.StdEncoding.EncodeToString(hash.Sum(nil))
+	if _, err := archive.Seek(0, 0); err != nil {
+		return nil, errors.Annotate(err, "cannot rewind packaged charm")
+	}
+	resp, err := uploadCharm(httpClient, curl.Series, archive, digest, size)
+	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	curl, err = charm.ParseURL(resp.CharmURL)
@@ -661,6 +877,113 @@ func (c *Client) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, err
 	return curl, nil
 }
 
+// charmUploadChunkSize holds the size of the chunks in which large
+// charm archives are uploaded, so that an interrupted upload can be
+// resumed rather than started again.
+var charmUploadChunkSize int64 = 8 * 1024 * 1024
+
+// charmUploadRetries holds the number of times in a row that uploading
+// a chunk of a charm archive is retried before giving up.
+const charmUploadRetries = 3
+
+// uploadCharm uploads the given charm archive, which has the given
+// base64-encoded SHA-256 digest and size, to the charms endpoint. Archives larger than
+// a single chunk are uploaded in chunks if the server supports it.
+func uploadCharm(httpClient *httprequest.Client, series string, archive *os.File, digest string, size int64) (*params.CharmsResponse, error) {
+	if size > charmUploadChunkSize {
+		resp, err := charmUploadStatus(httpClient, series, digest, size)
+		if err == nil {
+			if resp.CharmURL != "" {
+				return resp, nil
+			}
+			return resumeCharmUpload(httpClient, series, archive, digest, size, resp.Received)
+		}
+		// Older servers know nothing of resumable uploads,
+		// and reject the status request.
+		logger.Debugf("cannot upload charm in chunks: %v", err)
+	}
+	req, err := newCharmUploadRequest(series, digest)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var resp params.CharmsResponse
+	if err := httpClient.Do(req, archive, &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &resp, nil
+}
+
+// resumeCharmUpload uploads the given charm archive in chunks, starting
+// at the given offset. When sending a chunk fails, the server is asked
+// how much of the archive it has received and the upload carries on
+// from there. Errors reported by the server are not retried.
+func resumeCharmUpload(httpClient *httprequest.Client, series string, archive io.ReaderAt, digest string, size, offset int64) (*params.CharmsResponse, error) {
+	failures := 0
+	for {
+		end := offset + charmUploadChunkSize
+		if end > size {
+			end = size
+		}
+		req, err := newCharmUploadRequest(series, digest)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, end-1, size))
+		var resp params.CharmsResponse
+		err = httpClient.Do(req, io.NewSectionReader(archive, offset, end-offset), &resp)
+		if err == nil {
+			if resp.CharmURL != "" {
+				return &resp, nil
+			}
+			offset, failures = resp.Received, 0
+			continue
+		}
+		if _, ok := errors.Cause(err).(*params.Error); ok {
+			return nil, errors.Trace(err)
+		}
+		if failures++; failures > charmUploadRetries {
+			return nil, errors.Annotate(err, "cannot upload charm")
+		}
+		logger.Debugf("resuming charm upload after error: %v", err)
+		status, err := charmUploadStatus(httpClient, series, digest, size)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot resume charm upload")
+		}
+		if status.CharmURL != "" {
+			return status, nil
+		}
+		offset = status.Received
+	}
+}
+
+// charmUploadStatus asks the server how much of the charm archive with
+// the given digest and size it has received. If the server already has
+// the whole archive, the charm is added and its URL returned.
+func charmUploadStatus(httpClient *httprequest.Client, series, digest string, size int64) (*params.CharmsResponse, error) {
+	req, err := newCharmUploadRequest(series, digest)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+	var resp params.CharmsResponse
+	if err := httpClient.Do(req, nil, &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &resp, nil
+}
+
+// newCharmUploadRequest returns a request to upload a charm archive
+// with the given base64-encoded SHA-256 digest for the given series.
+func newCharmUploadRequest(series, digest string) (*http.Request, error) {
+	req, err := http.NewRequest("POST", "/charms?series="+series, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create upload request")
+	}
+	req.Header.Set("Content-Type", "application/zip")
+	req.Header.Set("Digest", fmt.Sprintf("%s=%s", params.DigestSHA256, digest))
+	return req, nil
+}
+
 // AddCharm adds the given charm URL (which must include revision) to
 // the environment, if it does not exist yet. Local charms are not
 // supported, only charm store URLs. See also AddLocalCharm() in the
@@ -871,6 +1194,9 @@ type DebugLogParams struct {
 	Backlog uint
 	// Level specifies the minimum logging level to be sent back in the response.
 	Level loggo.Level
+	// MessageRegexp, if set, is a regular expression that the message of
+	// each line must match for it to be included in the response.
+	MessageRegexp string
 	// Replay tells the server to start at the start of the log file rather
 	// than the end. If replay is true, backlog is ignored.
 	Replay bool
@@ -911,6 +1237,9 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	if args.Level != loggo.UNSPECIFIED {
 		attrs.Set("level", fmt.Sprint(args.Level))
 	}
+	if args.MessageRegexp != "" {
+		attrs.Set("messageRegexp", args.MessageRegexp)
+	}
 
 	connection, err := c.st.ConnectStream("/log", attrs)
 	if err != nil {
@@ -12,7 +12,9 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/errors"
@@ -20,6 +22,8 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	"golang.org/x/net/context"
 	"golang.org/x/net/websocket"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -117,6 +121,43 @@ func (s *clientSuite) TestAddLocalCharm(c *gc.C) {
 	c.Assert(savedURL.String(), gc.Equals, curl.WithRevision(43).String())
 }
 
+func (s *clientSuite) TestAddLocalCharmInChunks(c *gc.C) {
+	s.PatchValue(api.CharmUploadChunkSize, int64(1024))
+	charmArchive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	curl := charm.MustParseURL("local:quantal/dummy-1")
+
+	savedURL, err := s.APIState.Client().AddLocalCharm(curl, charmArchive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(savedURL.String(), gc.Equals, curl.String())
+	_, err = s.State.Charm(curl)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestAddLocalCharmResumesUpload(c *gc.C) {
+	s.PatchValue(api.CharmUploadChunkSize, int64(1024))
+	charmArchive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	curl := charm.MustParseURL("local:quantal/dummy-1")
+
+	// Leave the start of the archive on the server, as if an
+	// earlier upload had been interrupted. The server refuses
+	// chunks that do not follow on from what it has, so the
+	// upload only succeeds if it carries on from there.
+	data, err := ioutil.ReadFile(charmArchive.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(data) > 1536, jc.IsTrue)
+	digest, _, err := utils.ReadSHA256(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	uploadDir := filepath.Join(s.DataDir(), "charm-uploads", s.State.EnvironUUID())
+	err = os.MkdirAll(uploadDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(uploadDir, digest+".zip"), data[:1536], 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	savedURL, err := s.APIState.Client().AddLocalCharm(curl, charmArchive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(savedURL.String(), gc.Equals, curl.String())
+}
+
 func (s *clientSuite) TestAddLocalCharmOtherEnvironment(c *gc.C) {
 	charmArchive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
 	curl := charm.MustParseURL(
@@ -314,6 +355,89 @@ func (s *clientSuite) TestShareEnvironmentThreeUsers(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `existing user`)
 }
 
+func (s *clientSuite) TestShareEnvironmentWithAccess(c *gc.C) {
+	client := s.APIState.Client()
+	user := names.NewUserTag("foo@bar")
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "ShareEnvironment")
+			c.Assert(paramsIn, jc.DeepEquals, params.ModifyEnvironUsers{
+				Changes: []params.ModifyEnvironUser{{
+					UserTag: user.String(),
+					Action:  params.AddEnvUser,
+					Access:  params.EnvReadAccess,
+				}},
+			})
+			*(response.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{}}}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := client.ShareEnvironmentWithAccess(params.EnvReadAccess, user)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestSetEnvironmentAccess(c *gc.C) {
+	client := s.APIState.Client()
+	user := names.NewUserTag("foo@bar")
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "ShareEnvironment")
+			c.Assert(paramsIn, jc.DeepEquals, params.ModifyEnvironUsers{
+				Changes: []params.ModifyEnvironUser{{
+					UserTag: user.String(),
+					Action:  params.SetEnvUserAccess,
+					Access:  params.EnvWriteAccess,
+				}},
+			})
+			err := &params.Error{Message: "not found", Code: params.CodeNotFound}
+			*(response.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{Error: err}}}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := client.SetEnvironmentAccess(params.EnvWriteAccess, user)
+	c.Assert(err, gc.ErrorMatches, "not found")
+}
+
+func (s *clientSuite) TestWithContext(c *gc.C) {
+	client := s.APIState.Client().WithContext(context.Background())
+	_, err := client.AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestWithContextCancelled(c *gc.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := s.APIState.Client().WithContext(ctx)
+	_, err := client.AgentVersion()
+	c.Assert(errors.Cause(err), gc.Equals, context.Canceled)
+}
+
+func (s *clientSuite) TestWithContextPatchedFacade(c *gc.C) {
+	client := s.APIState.Client()
+	var called int
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			called++
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := client.WithContext(context.Background()).ShareEnvironment(names.NewUserTag("foo@bar"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, gc.Equals, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.WithContext(ctx).ShareEnvironment(names.NewUserTag("foo@bar"))
+	c.Assert(errors.Cause(err), gc.Equals, context.Canceled)
+	c.Assert(called, gc.Equals, 1)
+}
+
 func (s *clientSuite) TestUnshareEnvironmentThreeUsers(c *gc.C) {
 	client := s.APIState.Client()
 	missingUser := s.Factory.MakeEnvUser(c, nil)
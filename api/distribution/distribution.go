@@ -0,0 +1,42 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package distribution
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const distributionFacade = "Distribution"
+
+// API provides access to the Distribution API facade.
+type API struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewAPI creates a new client-side Distribution facade.
+func NewAPI(caller base.APICallCloser) *API {
+	if caller == nil {
+		panic("caller is nil")
+	}
+	clientFacade, facadeCaller := base.NewClientFacade(caller, distributionFacade)
+	return &API{
+		ClientFacade: clientFacade,
+		facade:       facadeCaller,
+	}
+}
+
+// Report returns how the units of each service in the environment are
+// distributed across hosts and availability zones, along with the unit
+// moves that would balance each service across zones.
+func (api *API) Report() (params.DistributionReport, error) {
+	var result params.DistributionReport
+	if err := api.facade.FacadeCall("Report", nil, &result); err != nil {
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
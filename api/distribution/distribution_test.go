@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package distribution_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/distribution"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type distributionSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&distributionSuite{})
+
+func (s *distributionSuite) TestNewAPIWithNilCaller(c *gc.C) {
+	panicFunc := func() { distribution.NewAPI(nil) }
+	c.Assert(panicFunc, gc.PanicMatches, "caller is nil")
+}
+
+func (s *distributionSuite) TestReport(c *gc.C) {
+	expected := params.DistributionReport{
+		Services: []params.ServiceDistribution{{
+			ServiceTag: "service-wordpress",
+			Units:      2,
+			Zones:      map[string]int{"az1": 2, "az2": 0},
+			Hosts:      map[string]int{"0": 1, "1": 1},
+			Imbalanced: true,
+			SingleZone: true,
+			Moves: []params.UnitMove{{
+				UnitTag:  "unit-wordpress-1",
+				FromZone: "az1",
+				ToZone:   "az2",
+			}},
+		}},
+	}
+	var called int
+	apiCaller := apitesting.CheckingAPICaller(c, &apitesting.CheckArgs{
+		Facade:  "Distribution",
+		Method:  "Report",
+		Results: expected,
+	}, &called, nil)
+	result, err := distribution.NewAPI(apiCaller).Report()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, gc.Equals, 1)
+	c.Assert(result, jc.DeepEquals, expected)
+}
+
+func (s *distributionSuite) TestReportError(c *gc.C) {
+	apiCaller := apitesting.CheckingAPICaller(c, nil, nil, errors.New("boom"))
+	_, err := distribution.NewAPI(apiCaller).Report()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -17,8 +17,19 @@ var (
 	BestVersion           = bestVersion
 	FacadeVersions        = &facadeVersions
 	ConnectWebsocket      = connectWebsocket
+	ReconnectInitialDelay = &reconnectInitialDelay
+	CharmUploadChunkSize  = &charmUploadChunkSize
 )
 
+// BreakTransport closes the websocket underlying the given
+// connection, as if the network connection had been lost.
+func BreakTransport(st Connection) {
+	s := st.(*state)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn.Close()
+}
+
 // SetServerAddress allows changing the URL to the internal API server
 // that AddLocalCharm uses in order to test NotImplementedError.
 func SetServerAddress(c *Client, scheme, addr string) {
@@ -40,6 +51,8 @@ type TestingStateParams struct {
 	FacadeVersions map[string][]int
 	ServerScheme   string
 	ServerRoot     string
+
+	FacadeDeprecations map[string]map[int]string
 }
 
 // NewTestingState creates an api.State object that can be used for testing. It
@@ -47,12 +60,13 @@ type TestingStateParams struct {
 // called on it. But it can be used for testing general behavior.
 func NewTestingState(params TestingStateParams) Connection {
 	st := &state{
-		addr:              params.Address,
-		environTag:        params.EnvironTag,
-		hostPorts:         params.APIHostPorts,
-		facadeVersions:    params.FacadeVersions,
-		serverScheme:      params.ServerScheme,
-		serverRootAddress: params.ServerRoot,
+		addr:               params.Address,
+		environTag:         params.EnvironTag,
+		hostPorts:          params.APIHostPorts,
+		facadeVersions:     params.FacadeVersions,
+		facadeDeprecations: params.FacadeDeprecations,
+		serverScheme:       params.ServerScheme,
+		serverRootAddress:  params.ServerRoot,
 	}
 	return st
 }
@@ -17,27 +17,32 @@ var facadeVersions = map[string]int{
 	"AllWatcher":                   0,
 	"AllEnvWatcher":                1,
 	"Annotations":                  1,
+	"Autoscaler":                   1,
 	"Backups":                      0,
 	"Block":                        1,
 	"Charms":                       1,
 	"CharmRevisionUpdater":         0,
-	"Client":                       0,
+	"Client":                       4,
 	"Cleaner":                      1,
 	"Deployer":                     0,
 	"DiskManager":                  1,
+	"Distribution":                 1,
 	"EntityWatcher":                1,
 	"Environment":                  0,
 	"EnvironmentManager":           1,
 	"FilesystemAttachmentsWatcher": 1,
 	"Firewaller":                   1,
+	"FirewallRules":                1,
 	"HighAvailability":             1,
+	"HostKeyReporter":              1,
 	"ImageManager":                 1,
 	"ImageMetadata":                1,
-	"InstancePoller":               1,
+	"InstancePoller":               2,
 	"KeyManager":                   0,
 	"KeyUpdater":                   0,
 	"LeadershipService":            1,
 	"Logger":                       0,
+	"MachineFirewaller":            1,
 	"MachineManager":               1,
 	"Machiner":                     0,
 	"MetricsManager":               0,
@@ -46,6 +51,7 @@ var facadeVersions = map[string]int{
 	"Networker":                    0,
 	"NotifyWatcher":                0,
 	"Pinger":                       0,
+	"ProblemReporter":              1,
 	"Provisioner":                  1,
 	"Reboot":                       1,
 	"RelationUnitsWatcher":         0,
@@ -60,7 +66,7 @@ var facadeVersions = map[string]int{
 	"StringsWatcher":               0,
 	"SystemManager":                1,
 	"Upgrader":                     0,
-	"Uniter":                       2,
+	"Uniter":                       3,
 	"UserManager":                  0,
 	"VolumeAttachmentsWatcher":     1,
 }
@@ -6,6 +6,8 @@ package api_test
 import (
 	"strings"
 
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
 
@@ -115,3 +117,27 @@ func (s *facadeVersionSuite) TestBestFacadeVersionServerUnknown(c *gc.C) {
 		}})
 	c.Check(st.BestFacadeVersion("TestingAPI"), gc.Equals, 0)
 }
+
+func (s *facadeVersionSuite) TestBestFacadeVersionWarnsOfDeprecation(c *gc.C) {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("deprecation-test", &tw, loggo.WARNING), gc.IsNil)
+	defer loggo.RemoveWriter("deprecation-test")
+
+	s.PatchValue(api.FacadeVersions, map[string]int{"Client": 1, "Pinger": 0})
+	st := api.NewTestingState(api.TestingStateParams{
+		FacadeVersions: map[string][]int{
+			"Client": {0, 1, 2},
+			"Pinger": {0},
+		},
+		FacadeDeprecations: map[string]map[int]string{
+			"Client": {1: "use Client(2)"},
+		},
+	})
+	c.Check(st.BestFacadeVersion("Pinger"), gc.Equals, 0)
+	c.Check(st.BestFacadeVersion("Client"), gc.Equals, 1)
+	c.Check(st.BestFacadeVersion("Client"), gc.Equals, 1)
+	c.Check(tw.Log(), jc.LogMatches, []jc.SimpleMessage{{
+		loggo.WARNING, `API server reports facade Client\(1\) is deprecated: use Client\(2\)`,
+	}})
+	c.Check(tw.Log(), gc.HasLen, 1)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 )
 
 const firewallerFacade = "Firewaller"
@@ -114,3 +115,31 @@ func (st *State) WatchOpenedPorts() (watcher.StringsWatcher, error) {
 	w := watcher.NewStringsWatcher(st.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// WatchFirewallWhitelists returns a NotifyWatcher that notifies of
+// changes to the whitelists of well-known services.
+func (st *State) WatchFirewallWhitelists() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	err := st.facade.FacadeCall("WatchFirewallWhitelists", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Error; err != nil {
+		return nil, result.Error
+	}
+	return watcher.NewNotifyWatcher(st.facade.RawAPICaller(), result), nil
+}
+
+// FirewallWhitelists returns the whitelisted source address ranges
+// of each well-known service that has a whitelist.
+func (st *State) FirewallWhitelists() (map[network.WellKnownServiceType][]string, error) {
+	var result params.FirewallWhitelists
+	if err := st.facade.FacadeCall("FirewallWhitelists", nil, &result); err != nil {
+		return nil, err
+	}
+	whitelists := make(map[network.WellKnownServiceType][]string)
+	for _, whitelist := range result.Whitelists {
+		whitelists[network.WellKnownServiceType(whitelist.KnownService)] = whitelist.WhitelistCIDRs
+	}
+	return whitelists, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
@@ -106,3 +107,28 @@ func (s *stateSuite) TestWatchOpenedPorts(c *gc.C) {
 	statetesting.AssertStop(c, w)
 	wc.AssertClosed()
 }
+
+func (s *stateSuite) TestFirewallWhitelists(c *gc.C) {
+	w, err := s.firewaller.WatchFirewallWhitelists()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	wc.AssertOneChange()
+
+	whitelists, err := s.firewaller.FirewallWhitelists()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(whitelists, gc.HasLen, 0)
+
+	err = s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: network.JujuControllerRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	whitelists, err = s.firewaller.FirewallWhitelists()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(whitelists, jc.DeepEquals, map[network.WellKnownServiceType][]string{
+		network.JujuControllerRule: {"10.0.0.0/8"},
+	})
+}
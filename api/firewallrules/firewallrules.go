@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewallrules
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+const firewallRulesFacade = "FirewallRules"
+
+// Client provides access to the FirewallRules API facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client-side FirewallRules facade.
+func NewClient(caller base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(caller, firewallRulesFacade)
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ListFirewallRules returns the firewall rules Juju expects to be
+// applied in the environment, alongside those the provider actually
+// has applied.
+func (c *Client) ListFirewallRules() (params.FirewallRulesResults, error) {
+	var results params.FirewallRulesResults
+	err := c.facade.FacadeCall("ListFirewallRules", nil, &results)
+	return results, err
+}
+
+// SyncFirewallRules updates the provider's firewall rules to match
+// those Juju expects, and returns the rules as they are afterwards.
+func (c *Client) SyncFirewallRules() (params.FirewallRulesResults, error) {
+	var results params.FirewallRulesResults
+	err := c.facade.FacadeCall("SyncFirewallRules", nil, &results)
+	return results, err
+}
+
+// SetFirewallWhitelist restricts access to the given well-known
+// service to the given source address ranges. An empty whitelist
+// allows access from anywhere.
+func (c *Client) SetFirewallWhitelist(service network.WellKnownServiceType, cidrs []string) error {
+	args := params.FirewallWhitelists{
+		Whitelists: []params.FirewallWhitelist{{
+			KnownService:   string(service),
+			WhitelistCIDRs: cidrs,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetFirewallWhitelists", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListFirewallWhitelists returns the whitelists of the well-known
+// services that have one.
+func (c *Client) ListFirewallWhitelists() (params.FirewallWhitelists, error) {
+	var result params.FirewallWhitelists
+	err := c.facade.FacadeCall("ListFirewallWhitelists", nil, &result)
+	return result, err
+}
@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewallrules_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type firewallRulesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&firewallRulesSuite{})
+
+var sampleResults = params.FirewallRulesResults{
+	Mode: "instance",
+	Results: []params.FirewallRules{{
+		MachineId:  "0",
+		InstanceId: "inst-0",
+		Expected:   []params.PortRange{{80, 80, "tcp"}},
+		Actual:     []params.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}},
+	}},
+}
+
+func (s *firewallRulesSuite) testCall(c *gc.C, method string, call func(*firewallrules.Client) (params.FirewallRulesResults, error)) {
+	var called int
+	apiCaller := apitesting.CheckingAPICaller(c, &apitesting.CheckArgs{
+		Facade:  "FirewallRules",
+		Method:  method,
+		Results: sampleResults,
+	}, &called, nil)
+	results, err := call(firewallrules.NewClient(apiCaller))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, gc.Equals, 1)
+	c.Assert(results, jc.DeepEquals, sampleResults)
+}
+
+func (s *firewallRulesSuite) TestListFirewallRules(c *gc.C) {
+	s.testCall(c, "ListFirewallRules", (*firewallrules.Client).ListFirewallRules)
+}
+
+func (s *firewallRulesSuite) TestSyncFirewallRules(c *gc.C) {
+	s.testCall(c, "SyncFirewallRules", (*firewallrules.Client).SyncFirewallRules)
+}
+
+func (s *firewallRulesSuite) TestError(c *gc.C) {
+	var called int
+	apiCaller := apitesting.CheckingAPICaller(c, nil, &called, errors.New("boom"))
+	_, err := firewallrules.NewClient(apiCaller).ListFirewallRules()
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, gc.Equals, 1)
+}
+
+func (s *firewallRulesSuite) TestSetFirewallWhitelist(c *gc.C) {
+	var called int
+	apiCaller := apitesting.CheckingAPICaller(c, &apitesting.CheckArgs{
+		Facade: "FirewallRules",
+		Method: "SetFirewallWhitelists",
+		Args: params.FirewallWhitelists{
+			Whitelists: []params.FirewallWhitelist{{
+				KnownService:   "ssh",
+				WhitelistCIDRs: []string{"10.0.0.0/8"},
+			}},
+		},
+		Results: params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		},
+	}, &called, nil)
+	err := firewallrules.NewClient(apiCaller).SetFirewallWhitelist(network.SSHRule, []string{"10.0.0.0/8"})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, gc.Equals, 1)
+}
+
+func (s *firewallRulesSuite) TestListFirewallWhitelists(c *gc.C) {
+	expected := params.FirewallWhitelists{
+		Whitelists: []params.FirewallWhitelist{{
+			KnownService:   "juju-controller",
+			WhitelistCIDRs: []string{"10.0.0.0/8"},
+		}},
+	}
+	var called int
+	apiCaller := apitesting.CheckingAPICaller(c, &apitesting.CheckArgs{
+		Facade:  "FirewallRules",
+		Method:  "ListFirewallWhitelists",
+		Results: expected,
+	}, &called, nil)
+	result, err := firewallrules.NewClient(apiCaller).ListFirewallWhitelists()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, gc.Equals, 1)
+	c.Assert(result, jc.DeepEquals, expected)
+}
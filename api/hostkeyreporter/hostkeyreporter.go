@@ -0,0 +1,45 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const hostKeyReporterFacade = "HostKeyReporter"
+
+// State provides access to a hostkeyreporter worker's view of the
+// state.
+type State struct {
+	facade base.FacadeCaller
+	tag    names.MachineTag
+}
+
+// NewState creates a new client-side HostKeyReporter facade.
+func NewState(caller base.APICaller, authTag names.MachineTag) *State {
+	return &State{
+		base.NewFacadeCaller(caller, hostKeyReporterFacade),
+		authTag,
+	}
+}
+
+// ReportKeys records the SSH host keys of the machine identified by
+// the authenticated machine tag.
+func (st *State) ReportKeys(publicKeys []string) error {
+	args := params.SSHHostKeySet{
+		EntityKeys: []params.SSHHostKeys{{
+			Tag:        st.tag.String(),
+			PublicKeys: publicKeys,
+		}},
+	}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("ReportKeys", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter_test
+
+import (
+	"errors"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/hostkeyreporter"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&HostKeyReporterSuite{})
+
+type HostKeyReporterSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *HostKeyReporterSuite) TestReportKeys(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "HostKeyReporter")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "ReportKeys")
+		c.Check(arg, gc.DeepEquals, params.SSHHostKeySet{
+			EntityKeys: []params.SSHHostKeys{{
+				Tag:        "machine-123",
+				PublicKeys: []string{"rsa foo", "dsa bar"},
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: nil,
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := hostkeyreporter.NewState(apiCaller, names.NewMachineTag("123"))
+	err := st.ReportKeys([]string{"rsa foo", "dsa bar"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *HostKeyReporterSuite) TestReportKeysClientError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("blargh")
+	})
+	st := hostkeyreporter.NewState(apiCaller, names.NewMachineTag("123"))
+	err := st.ReportKeys(nil)
+	c.Check(err, gc.ErrorMatches, "blargh")
+}
+
+func (s *HostKeyReporterSuite) TestReportKeysServerError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "MSG", Code: "621"},
+			}},
+		}
+		return nil
+	})
+	st := hostkeyreporter.NewState(apiCaller, names.NewMachineTag("123"))
+	err := st.ReportKeys(nil)
+	c.Check(err, gc.ErrorMatches, "MSG")
+}
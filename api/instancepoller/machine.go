@@ -116,32 +116,58 @@ func (m *Machine) InstanceId() (instance.Id, error) {
 	return instance.Id(result.Result), nil
 }
 
-// InstanceStatus returns the machine's instance status.
-func (m *Machine) InstanceStatus() (string, error) {
+// InstanceStatus returns the machine's instance status. Servers
+// providing version 1 of the InstancePoller facade do not report a
+// message or the time of the last change.
+func (m *Machine) InstanceStatus() (params.StatusResult, error) {
+	if m.facade.BestAPIVersion() < 2 {
+		return m.instanceStatusV1()
+	}
+	var results params.StatusResults
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: m.tag.String()},
+	}}
+	err := m.facade.FacadeCall("InstanceStatus", args, &results)
+	if err != nil {
+		return params.StatusResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		err := errors.Errorf("expected 1 result, got %d", len(results.Results))
+		return params.StatusResult{}, err
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.StatusResult{}, result.Error
+	}
+	return result, nil
+}
+
+func (m *Machine) instanceStatusV1() (params.StatusResult, error) {
 	var results params.StringResults
 	args := params.Entities{Entities: []params.Entity{
 		{Tag: m.tag.String()},
 	}}
 	err := m.facade.FacadeCall("InstanceStatus", args, &results)
 	if err != nil {
-		return "", errors.Trace(err)
+		return params.StatusResult{}, errors.Trace(err)
 	}
 	if len(results.Results) != 1 {
 		err := errors.Errorf("expected 1 result, got %d", len(results.Results))
-		return "", err
+		return params.StatusResult{}, err
 	}
 	result := results.Results[0]
 	if result.Error != nil {
-		return "", result.Error
+		return params.StatusResult{}, result.Error
 	}
-	return result.Result, nil
+	return params.StatusResult{Status: params.Status(result.Result)}, nil
 }
 
-// SetInstanceStatus sets the instance status of the machine.
-func (m *Machine) SetInstanceStatus(status string) error {
+// SetInstanceStatus sets the instance status of the machine, along
+// with an optional message elaborating upon it.
+func (m *Machine) SetInstanceStatus(status, message string) error {
 	var result params.ErrorResults
 	args := params.SetInstancesStatus{Entities: []params.InstanceStatus{
-		{Tag: m.tag.String(), Status: status},
+		{Tag: m.tag.String(), Status: status, Info: message},
 	}}
 	err := m.facade.FacadeCall("SetInstanceStatus", args, &result)
 	if err != nil {
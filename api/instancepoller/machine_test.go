@@ -11,6 +11,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api/base"
 	apitesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/instancepoller"
 	"github.com/juju/juju/apiserver/params"
@@ -95,7 +96,7 @@ var machineErrorTests = []struct {
 }, {
 	method: "SetInstanceStatus",
 	wrapper: func(m *instancepoller.Machine) error {
-		return m.SetInstanceStatus("")
+		return m.SetInstanceStatus("", "")
 	},
 	resultsRef: params.ErrorResults{},
 }, {
@@ -203,16 +204,49 @@ func (s *MachineSuite) TestInstanceIdSuccess(c *gc.C) {
 	c.Check(called, gc.Equals, 1)
 }
 
+// versionedCaller is an APICaller that reports the given version
+// as the best version of every facade.
+type versionedCaller struct {
+	base.APICaller
+	version int
+}
+
+func (v versionedCaller) BestFacadeVersion(string) int {
+	return v.version
+}
+
 func (s *MachineSuite) TestInstanceStatusSuccess(c *gc.C) {
 	var called int
-	results := params.StringResults{
-		Results: []params.StringResult{{Result: "A-OK"}},
+	now := time.Now()
+	expectStatus := params.StatusResult{
+		Status: "A-OK",
+		Info:   "all systems go",
+		Since:  &now,
 	}
+	results := params.StatusResults{Results: []params.StatusResult{expectStatus}}
+	apiCaller := apitesting.CheckingAPICaller(c, &apitesting.CheckArgs{
+		Facade:    "InstancePoller",
+		Version:   2,
+		IdIsEmpty: true,
+		Method:    "InstanceStatus",
+		Args:      entitiesArgs,
+		Results:   results,
+	}, &called, nil)
+	machine := instancepoller.NewMachine(versionedCaller{apiCaller, 2}, s.tag, params.Alive)
+	status, err := machine.InstanceStatus()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(status, jc.DeepEquals, expectStatus)
+	c.Check(called, gc.Equals, 1)
+}
+
+func (s *MachineSuite) TestInstanceStatusV1Success(c *gc.C) {
+	var called int
+	results := params.StringResults{Results: []params.StringResult{{Result: "A-OK"}}}
 	apiCaller := successAPICaller(c, "InstanceStatus", entitiesArgs, results, &called)
 	machine := instancepoller.NewMachine(apiCaller, s.tag, params.Alive)
 	status, err := machine.InstanceStatus()
 	c.Check(err, jc.ErrorIsNil)
-	c.Check(status, gc.Equals, "A-OK")
+	c.Check(status, jc.DeepEquals, params.StatusResult{Status: "A-OK"})
 	c.Check(called, gc.Equals, 1)
 }
 
@@ -222,13 +256,14 @@ func (s *MachineSuite) TestSetInstanceStatusSuccess(c *gc.C) {
 		Entities: []params.InstanceStatus{{
 			Tag:    "machine-42",
 			Status: "RUNNING",
+			Info:   "up and running",
 		}}}
 	results := params.ErrorResults{
 		Results: []params.ErrorResult{{Error: nil}},
 	}
 	apiCaller := successAPICaller(c, "SetInstanceStatus", expectArgs, results, &called)
 	machine := instancepoller.NewMachine(apiCaller, s.tag, params.Alive)
-	err := machine.SetInstanceStatus("RUNNING")
+	err := machine.SetInstanceStatus("RUNNING", "up and running")
 	c.Check(err, jc.ErrorIsNil)
 	c.Check(called, gc.Equals, 1)
 }
@@ -18,12 +18,15 @@ import (
 	"github.com/juju/juju/api/diskmanager"
 	"github.com/juju/juju/api/environment"
 	"github.com/juju/juju/api/firewaller"
+	"github.com/juju/juju/api/hostkeyreporter"
 	"github.com/juju/juju/api/imagemetadata"
 	"github.com/juju/juju/api/instancepoller"
 	"github.com/juju/juju/api/keyupdater"
 	apilogger "github.com/juju/juju/api/logger"
+	"github.com/juju/juju/api/machinefirewaller"
 	"github.com/juju/juju/api/machiner"
 	"github.com/juju/juju/api/networker"
+	"github.com/juju/juju/api/problemreporter"
 	"github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/api/reboot"
 	"github.com/juju/juju/api/resumer"
@@ -95,6 +98,29 @@ type DialOpts struct {
 	// by Open, and any RoundTripper field
 	// the HTTP client is ignored.
 	BakeryClient *httpbakery.Client
+
+	// Reconnect, if true, causes a lost connection to be
+	// re-established automatically, trying all the known API server
+	// addresses in turn with increasing delays between attempts.
+	// Calls in progress when the connection is lost still fail, and
+	// watchers do not survive reconnection. The Broken channel is
+	// only closed once reconnection has been abandoned.
+	Reconnect bool
+
+	// ReconnectTimeout is the longest time to spend trying to
+	// re-establish a lost connection. If it is zero, a default of
+	// five minutes is used.
+	ReconnectTimeout time.Duration
+
+	// ConnectionStateChanged, if not nil, is called when a connection
+	// opened with Reconnect set is lost, restored or abandoned.
+	ConnectionStateChanged func(ConnectionState)
+
+	// CorrelationId, if not empty, is sent with every request made
+	// on the connection that does not already carry a correlation id
+	// in its context, so that all the requests made on behalf of a
+	// single operation can be found in the server logs.
+	CorrelationId string
 }
 
 // DefaultDialOpts returns a DialOpts representing the default
@@ -104,6 +130,7 @@ func DefaultDialOpts() DialOpts {
 		DialAddressInterval: 50 * time.Millisecond,
 		Timeout:             10 * time.Minute,
 		RetryDelay:          2 * time.Second,
+		Reconnect:           true,
 	}
 }
 
@@ -167,6 +194,9 @@ type Connection interface {
 	Provisioner() *provisioner.State
 	Uniter() (*uniter.State, error)
 	DiskManager() (*diskmanager.State, error)
+	HostKeyReporter() (*hostkeyreporter.State, error)
+	MachineFirewaller() (*machinefirewaller.State, error)
+	ProblemReporter() *problemreporter.State
 	StorageProvisioner(scope names.Tag) *storageprovisioner.State
 	Firewaller() *firewaller.State
 	Agent() *agent.State
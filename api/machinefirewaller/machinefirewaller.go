@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+const machineFirewallerFacade = "MachineFirewaller"
+
+// State provides access to a machine firewaller worker's view of the
+// state.
+type State struct {
+	facade base.FacadeCaller
+	tag    names.MachineTag
+}
+
+// NewState creates a new client-side MachineFirewaller facade.
+func NewState(caller base.APICaller, authTag names.MachineTag) *State {
+	return &State{
+		base.NewFacadeCaller(caller, machineFirewallerFacade),
+		authTag,
+	}
+}
+
+func (st *State) args() params.Entities {
+	return params.Entities{
+		Entities: []params.Entity{{Tag: st.tag.String()}},
+	}
+}
+
+// WatchOpenedPorts returns a NotifyWatcher that notifies of changes
+// to the port ranges opened on the machine identified by the
+// authenticated machine tag.
+func (st *State) WatchOpenedPorts() (watcher.NotifyWatcher, error) {
+	var results params.NotifyWatchResults
+	err := st.facade.FacadeCall("WatchOpenedPorts", st.args(), &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return watcher.NewNotifyWatcher(st.facade.RawAPICaller(), result), nil
+}
+
+// OpenedPorts returns the port ranges that should be allowed by the
+// host firewall of the machine identified by the authenticated
+// machine tag; that is, those opened by units of exposed services.
+func (st *State) OpenedPorts() ([]network.PortRange, error) {
+	var results params.MachinePortsResults
+	err := st.facade.FacadeCall("OpenedPorts", st.args(), &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	portRanges := make([]network.PortRange, len(result.Ports))
+	for i, port := range result.Ports {
+		portRanges[i] = port.PortRange.NetworkPortRange()
+	}
+	return portRanges, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/machinefirewaller"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&MachineFirewallerSuite{})
+
+type MachineFirewallerSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *MachineFirewallerSuite) TestOpenedPorts(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineFirewaller")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "OpenedPorts")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-123"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.MachinePortsResults{})
+		*(result.(*params.MachinePortsResults)) = params.MachinePortsResults{
+			Results: []params.MachinePortsResult{{
+				Ports: []params.MachinePortRange{{
+					UnitTag:   "unit-wordpress-0",
+					PortRange: params.PortRange{FromPort: 80, ToPort: 81, Protocol: "tcp"},
+				}},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := machinefirewaller.NewState(apiCaller, names.NewMachineTag("123"))
+	ports, err := st.OpenedPorts()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(ports, jc.DeepEquals, []network.PortRange{{FromPort: 80, ToPort: 81, Protocol: "tcp"}})
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachineFirewallerSuite) TestOpenedPortsError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.MachinePortsResults)) = params.MachinePortsResults{
+			Results: []params.MachinePortsResult{{
+				Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+			}},
+		}
+		return nil
+	})
+	st := machinefirewaller.NewState(apiCaller, names.NewMachineTag("123"))
+	_, err := st.OpenedPorts()
+	c.Check(err, gc.ErrorMatches, "permission denied")
+}
@@ -0,0 +1,43 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package problemreporter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const problemReporterFacade = "ProblemReporter"
+
+// State provides access to the ProblemReporter API facade, used by
+// agents to upload problem reports.
+type State struct {
+	facade base.FacadeCaller
+	tag    names.Tag
+}
+
+// NewState creates a new client-side ProblemReporter facade.
+func NewState(caller base.APICaller, authTag names.Tag) *State {
+	return &State{
+		base.NewFacadeCaller(caller, problemReporterFacade),
+		authTag,
+	}
+}
+
+// Report uploads a problem report on behalf of the agent identified
+// by the authenticated tag.
+func (st *State) Report(report params.ProblemReport) error {
+	report.Tag = st.tag.String()
+	args := params.ProblemReports{
+		Reports: []params.ProblemReport{report},
+	}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("Report", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
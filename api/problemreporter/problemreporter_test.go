@@ -0,0 +1,81 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package problemreporter_test
+
+import (
+	"errors"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/problemreporter"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&ProblemReporterSuite{})
+
+type ProblemReporterSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *ProblemReporterSuite) TestReport(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ProblemReporter")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "Report")
+		c.Check(arg, gc.DeepEquals, params.ProblemReports{
+			Reports: []params.ProblemReport{{
+				Tag:     "unit-mysql-0",
+				Kind:    "hook-failed",
+				Source:  "install",
+				Message: "boom",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: nil,
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := problemreporter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	err := st.Report(params.ProblemReport{
+		Kind:    "hook-failed",
+		Source:  "install",
+		Message: "boom",
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *ProblemReporterSuite) TestReportClientError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("blargh")
+	})
+	st := problemreporter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	err := st.Report(params.ProblemReport{})
+	c.Check(err, gc.ErrorMatches, "blargh")
+}
+
+func (s *ProblemReporterSuite) TestReportServerError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "MSG", Code: "621"},
+			}},
+		}
+		return nil
+	})
+	st := problemreporter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	err := st.Report(params.ProblemReport{})
+	c.Check(err, gc.ErrorMatches, "MSG")
+}
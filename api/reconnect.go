@@ -0,0 +1,232 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/jsoncodec"
+)
+
+// ConnectionState describes a change in the state of an API
+// connection opened with DialOpts.Reconnect set.
+type ConnectionState int
+
+const (
+	// ConnectionLost indicates that the connection to the API server
+	// has been lost and is being re-established.
+	ConnectionLost ConnectionState = iota
+
+	// ConnectionRestored indicates that the connection has been
+	// re-established and logged in again.
+	ConnectionRestored
+
+	// ConnectionBroken indicates that the connection could not be
+	// re-established in time; the Broken channel is closed.
+	ConnectionBroken
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionLost:
+		return "lost"
+	case ConnectionRestored:
+		return "restored"
+	case ConnectionBroken:
+		return "broken"
+	}
+	return fmt.Sprintf("ConnectionState(%d)", int(s))
+}
+
+// defaultReconnectTimeout is the time spent trying to re-establish a
+// lost connection when DialOpts.ReconnectTimeout is not set.
+const defaultReconnectTimeout = 5 * time.Minute
+
+// The delay between attempts to re-establish a lost connection starts
+// at reconnectInitialDelay and doubles after each failed attempt, up
+// to reconnectMaxDelay. They are variables so they can be changed in
+// tests.
+var (
+	reconnectInitialDelay = 1 * time.Second
+	reconnectMaxDelay     = 30 * time.Second
+)
+
+// jitter returns a random duration between d/2 and d, so that clients
+// that lost their connections at the same time do not all retry at
+// once.
+func jitter(d time.Duration) time.Duration {
+	half := int64(d / 2)
+	if half <= 0 {
+		return d
+	}
+	return time.Duration(half + rand.Int63n(half))
+}
+
+// notify reports a change in the connection state to the
+// ConnectionStateChanged callback, if there is one.
+func (s *state) notify(cs ConnectionState) {
+	if s.opts.ConnectionStateChanged != nil {
+		s.opts.ConnectionStateChanged(cs)
+	}
+}
+
+// isClosed reports whether Close has been called.
+func (s *state) isClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// reconnect tries to re-establish the connection to the API server
+// until it succeeds, the connection is closed or the reconnect timeout
+// expires.
+func (s *state) reconnect() error {
+	timeout := s.opts.ReconnectTimeout
+	if timeout <= 0 {
+		timeout = defaultReconnectTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	delay := reconnectInitialDelay
+	for attempt := 0; ; attempt++ {
+		err := s.reconnectOnce(attempt)
+		if err == nil {
+			return nil
+		}
+		if s.isClosed() {
+			return errors.New("connection closed")
+		}
+		logger.Debugf("cannot reconnect to API server: %v", err)
+		wait := jitter(delay)
+		if time.Now().Add(wait).After(deadline) {
+			return errors.Annotatef(err, "cannot reconnect to API server after %v", timeout)
+		}
+		select {
+		case <-time.After(wait):
+		case <-s.closed:
+			return errors.New("connection closed")
+		}
+		if delay *= 2; delay > reconnectMaxDelay {
+			delay = reconnectMaxDelay
+		}
+	}
+}
+
+// reconnectOnce makes a single attempt to connect and log in to one of
+// the known API server addresses. The addresses are rotated by attempt,
+// so that successive attempts favour different servers.
+func (s *state) reconnectOnce(attempt int) error {
+	info := *s.info
+	info.Addrs = rotateAddrs(s.reconnectAddrs(), attempt)
+	opts := s.opts
+	// Each address is dialled once per attempt; reconnect
+	// handles the retries.
+	opts.Timeout = 0
+	conn, _, err := connectWebsocket(&info, opts)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client := rpc.NewConn(jsoncodec.NewWebsocket(conn), nil)
+	client.Start()
+
+	// Log in on a separate state, so that calls made in the meantime
+	// never reach a client that is not yet logged in, and the login
+	// results are only published to s under s.mu.
+	host := conn.Config().Location.Host
+	broken := make(chan struct{})
+	close(broken)
+	newSt := &state{
+		client:            client,
+		conn:              conn,
+		addr:              host,
+		info:              s.info,
+		opts:              s.opts,
+		loginFunc:         s.loginFunc,
+		cookieURL:         s.cookieURL,
+		serverScheme:      s.serverScheme,
+		serverRootAddress: host,
+		tag:               s.tag,
+		password:          s.password,
+		nonce:             s.nonce,
+		tlsConfig:         s.tlsConfig,
+		certPool:          s.certPool,
+		bakeryClient:      s.bakeryClient,
+		broken:            broken,
+		closed:            make(chan struct{}),
+	}
+	if info.Tag != nil || info.Password != "" || info.UseMacaroons {
+		if err := s.loginFunc(newSt, info.Tag, info.Password, info.Nonce); err != nil {
+			client.Close()
+			return errors.Annotate(err, "cannot log in")
+		}
+	}
+
+	s.mu.Lock()
+	if s.isClosed() {
+		s.mu.Unlock()
+		client.Close()
+		return errors.New("connection closed")
+	}
+	old := s.client
+	s.client = client
+	s.conn = conn
+	s.addr = host
+	s.serverRootAddress = host
+	if newSt.loggedIn {
+		s.environTag = newSt.environTag
+		s.serverTag = newSt.serverTag
+		s.serverVersion = newSt.serverVersion
+		s.hostPorts = newSt.hostPorts
+		s.facadeVersions = newSt.facadeVersions
+		s.facadeDeprecations = newSt.facadeDeprecations
+	}
+	s.mu.Unlock()
+	old.Close()
+
+	logger.Infof("reconnected to API server at %q", host)
+	return nil
+}
+
+// reconnectAddrs returns the addresses to try when re-establishing the
+// connection: those of all the API servers reported at login, followed
+// by any others the connection was opened with.
+func (s *state) reconnectAddrs() []string {
+	s.mu.Lock()
+	hostPorts := s.hostPorts
+	s.mu.Unlock()
+	var addrs []string
+	seen := make(map[string]bool)
+	add := func(addr string) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	for _, server := range hostPorts {
+		for _, addr := range network.HostPortsToStrings(server) {
+			add(addr)
+		}
+	}
+	for _, addr := range s.info.Addrs {
+		add(addr)
+	}
+	return addrs
+}
+
+// rotateAddrs returns addrs rotated left by n places.
+func rotateAddrs(addrs []string, n int) []string {
+	if len(addrs) == 0 {
+		return addrs
+	}
+	n %= len(addrs)
+	return append(append([]string{}, addrs[n:]...), addrs[:n]...)
+}
@@ -63,7 +63,9 @@ func (c *Client) EnvironmentUUID() string {
 // requested networks that must be present on the machines where the
 // service is deployed. Another way to specify networks to include/exclude
 // is using constraints. Placement directives, if provided, specify the
-// machine on which the charm is deployed.
+// machine on which the charm is deployed. Endpoint bindings, if
+// provided, map charm endpoint names to the network spaces they are
+// bound to.
 func (c *Client) ServiceDeploy(
 	charmURL string,
 	serviceName string,
@@ -74,18 +76,20 @@ func (c *Client) ServiceDeploy(
 	placement []*instance.Placement,
 	networks []string,
 	storage map[string]storage.Constraints,
+	endpointBindings map[string]string,
 ) error {
 	args := params.ServicesDeploy{
 		Services: []params.ServiceDeploy{{
-			ServiceName:   serviceName,
-			CharmUrl:      charmURL,
-			NumUnits:      numUnits,
-			ConfigYAML:    configYAML,
-			Constraints:   cons,
-			ToMachineSpec: toMachineSpec,
-			Placement:     placement,
-			Networks:      networks,
-			Storage:       storage,
+			ServiceName:      serviceName,
+			CharmUrl:         charmURL,
+			NumUnits:         numUnits,
+			ConfigYAML:       configYAML,
+			Constraints:      cons,
+			ToMachineSpec:    toMachineSpec,
+			Placement:        placement,
+			Networks:         networks,
+			Storage:          storage,
+			EndpointBindings: endpointBindings,
 		}},
 	}
 	var results params.ErrorResults
@@ -106,3 +110,25 @@ func (c *Client) ServiceDeploy(
 	}
 	return results.OneError()
 }
+
+// ServiceDestroyImpact returns the entities that will be removed, or
+// otherwise affected, by destroying the given service.
+func (c *Client) ServiceDestroyImpact(service string) (params.RemovalImpact, error) {
+	var impact params.RemovalImpact
+	args := params.ServiceDestroy{ServiceName: service}
+	if err := c.facade.FacadeCall("ServiceDestroyImpact", args, &impact); err != nil {
+		return params.RemovalImpact{}, errors.Trace(err)
+	}
+	return impact, nil
+}
+
+// DestroyUnitsImpact returns the entities that will be removed, or
+// otherwise affected, by destroying the given principal units.
+func (c *Client) DestroyUnitsImpact(unitNames ...string) (params.RemovalImpact, error) {
+	var impact params.RemovalImpact
+	args := params.DestroyServiceUnits{UnitNames: unitNames}
+	if err := c.facade.FacadeCall("DestroyUnitsImpact", args, &impact); err != nil {
+		return params.RemovalImpact{}, errors.Trace(err)
+	}
+	return impact, nil
+}
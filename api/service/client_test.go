@@ -89,13 +89,53 @@ func (s *serviceSuite) TestSetServiceDeploy(c *gc.C) {
 		c.Assert(args.Services[0].ToMachineSpec, gc.Equals, "machineSpec")
 		c.Assert(args.Services[0].Networks, gc.DeepEquals, []string{"neta"})
 		c.Assert(args.Services[0].Storage, gc.DeepEquals, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}})
+		c.Assert(args.Services[0].EndpointBindings, gc.DeepEquals, map[string]string{"db": "internal"})
 
 		result := response.(*params.ErrorResults)
 		result.Results = make([]params.ErrorResult, 1)
 		return nil
 	})
 	err := s.client.ServiceDeploy("charmURL", "serviceA", 2, "configYAML", constraints.MustParse("mem=4G"),
-		"machineSpec", nil, []string{"neta"}, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}})
+		"machineSpec", nil, []string{"neta"}, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}},
+		map[string]string{"db": "internal"})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *serviceSuite) TestServiceDestroyImpact(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "ServiceDestroyImpact")
+		c.Assert(a, jc.DeepEquals, params.ServiceDestroy{ServiceName: "mysql"})
+		impact := response.(*params.RemovalImpact)
+		impact.Units = []string{"mysql/0"}
+		impact.Machines = []string{"1"}
+		return nil
+	})
+	impact, err := s.client.ServiceDestroyImpact("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(impact, jc.DeepEquals, params.RemovalImpact{
+		Units:    []string{"mysql/0"},
+		Machines: []string{"1"},
+	})
+}
+
+func (s *serviceSuite) TestDestroyUnitsImpact(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "DestroyUnitsImpact")
+		c.Assert(a, jc.DeepEquals, params.DestroyServiceUnits{UnitNames: []string{"mysql/0", "mysql/1"}})
+		impact := response.(*params.RemovalImpact)
+		impact.Units = []string{"mysql/0", "mysql/1"}
+		return nil
+	})
+	impact, err := s.client.DestroyUnitsImpact("mysql/0", "mysql/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(impact, jc.DeepEquals, params.RemovalImpact{
+		Units: []string{"mysql/0", "mysql/1"},
+	})
+}
@@ -20,12 +20,15 @@ import (
 	"github.com/juju/juju/api/diskmanager"
 	"github.com/juju/juju/api/environment"
 	"github.com/juju/juju/api/firewaller"
+	"github.com/juju/juju/api/hostkeyreporter"
 	"github.com/juju/juju/api/imagemetadata"
 	"github.com/juju/juju/api/instancepoller"
 	"github.com/juju/juju/api/keyupdater"
 	apilogger "github.com/juju/juju/api/logger"
+	"github.com/juju/juju/api/machinefirewaller"
 	"github.com/juju/juju/api/machiner"
 	"github.com/juju/juju/api/networker"
+	"github.com/juju/juju/api/problemreporter"
 	"github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/api/reboot"
 	"github.com/juju/juju/api/resumer"
@@ -118,10 +121,13 @@ func (st *state) loginV2(tag names.Tag, password, nonce string) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	st.serverVersion, err = version.Parse(result.ServerVersion)
+	serverVersion, err := version.Parse(result.ServerVersion)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	st.mu.Lock()
+	st.serverVersion = serverVersion
+	st.mu.Unlock()
 	return nil
 }
 
@@ -180,23 +186,34 @@ func (st *state) loginV1(tag names.Tag, password, nonce string) error {
 }
 
 func (st *state) setLoginResult(tag names.Tag, environTag, serverTag string, servers [][]network.HostPort, facades []params.FacadeVersions) error {
-	st.authTag = tag
-	st.environTag = environTag
-	st.serverTag = serverTag
-
-	hostPorts, err := addAddress(servers, st.addr)
+	hostPorts, err := addAddress(servers, st.Addr())
 	if err != nil {
 		if clerr := st.Close(); clerr != nil {
 			err = errors.Annotatef(err, "error closing state: %v", clerr)
 		}
 		return err
 	}
-	st.hostPorts = hostPorts
 
-	st.facadeVersions = make(map[string][]int, len(facades))
+	facadeVersions := make(map[string][]int, len(facades))
+	facadeDeprecations := make(map[string]map[int]string)
 	for _, facade := range facades {
-		st.facadeVersions[facade.Name] = facade.Versions
+		facadeVersions[facade.Name] = facade.Versions
+		for _, deprecation := range facade.Deprecations {
+			if facadeDeprecations[facade.Name] == nil {
+				facadeDeprecations[facade.Name] = make(map[int]string)
+			}
+			facadeDeprecations[facade.Name][deprecation.Version] = deprecation.Notice
+		}
 	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.authTag = tag
+	st.environTag = environTag
+	st.serverTag = serverTag
+	st.hostPorts = hostPorts
+	st.facadeVersions = facadeVersions
+	st.facadeDeprecations = facadeDeprecations
 	st.loggedIn = true
 	return nil
 }
@@ -312,6 +329,32 @@ func (st *state) DiskManager() (*diskmanager.State, error) {
 	return diskmanager.NewState(st, machineTag), nil
 }
 
+// HostKeyReporter returns a version of the state that provides
+// functionality required by the hostkeyreporter worker.
+func (st *state) HostKeyReporter() (*hostkeyreporter.State, error) {
+	machineTag, ok := st.authTag.(names.MachineTag)
+	if !ok {
+		return nil, errors.Errorf("expected MachineTag, got %#v", st.authTag)
+	}
+	return hostkeyreporter.NewState(st, machineTag), nil
+}
+
+// MachineFirewaller returns a version of the state that provides
+// functionality required by the machine firewaller worker.
+func (st *state) MachineFirewaller() (*machinefirewaller.State, error) {
+	machineTag, ok := st.authTag.(names.MachineTag)
+	if !ok {
+		return nil, errors.Errorf("expected MachineTag, got %#v", st.authTag)
+	}
+	return machinefirewaller.NewState(st, machineTag), nil
+}
+
+// ProblemReporter returns a version of the state that allows the
+// agent to upload problem reports.
+func (st *state) ProblemReporter() *problemreporter.State {
+	return problemreporter.NewState(st, st.authTag)
+}
+
 // StorageProvisioner returns a version of the state that provides
 // functionality required by the storageprovisioner worker.
 // The scope tag defines the type of storage that is provisioned, either
@@ -399,6 +442,8 @@ func (st *state) Rsyslog() *rsyslog.State {
 // during login. The second result argument indicates if the version number is
 // set.
 func (st *state) ServerVersion() (version.Number, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
 	return st.serverVersion, st.serverVersion != version.Zero
 }
 
@@ -114,3 +114,35 @@ func (c *Client) AddToUnit(storages []params.StorageAddParams) ([]params.ErrorRe
 	}
 	return out.Results, nil
 }
+
+// Detach detaches the specified storage instances from the units that own
+// them, releasing them to the units' services without destroying them.
+func (c *Client) Detach(tags []names.StorageTag) ([]params.ErrorResult, error) {
+	entities := make([]params.Entity, len(tags))
+	for i, tag := range tags {
+		entities[i] = params.Entity{Tag: tag.String()}
+	}
+	out := params.ErrorResults{}
+	err := c.facade.FacadeCall("Detach", params.Entities{Entities: entities}, &out)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// Attach attaches the specified detached storage instances to a unit.
+func (c *Client) Attach(unit names.UnitTag, tags []names.StorageTag) ([]params.ErrorResult, error) {
+	ids := make([]params.StorageAttachmentId, len(tags))
+	for i, tag := range tags {
+		ids[i] = params.StorageAttachmentId{
+			StorageTag: tag.String(),
+			UnitTag:    unit.String(),
+		}
+	}
+	out := params.ErrorResults{}
+	err := c.facade.FacadeCall("Attach", params.StorageAttachmentIds{Ids: ids}, &out)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
@@ -564,3 +564,88 @@ func (s *storageMockSuite) TestAddToUnitFacadeCallError(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 	c.Assert(found, gc.HasLen, 0)
 }
+
+func (s *storageMockSuite) TestDetach(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Detach")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{
+					{Tag: "storage-data-0"},
+					{Tag: "storage-data-1"},
+				},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{
+					{},
+					{Error: &params.Error{Message: "boom"}},
+				},
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	results, err := storageClient.Detach([]names.StorageTag{
+		names.NewStorageTag("data/0"),
+		names.NewStorageTag("data/1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "boom"}},
+	})
+}
+
+func (s *storageMockSuite) TestAttach(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Attach")
+			c.Check(a, jc.DeepEquals, params.StorageAttachmentIds{
+				Ids: []params.StorageAttachmentId{{
+					StorageTag: "storage-data-0",
+					UnitTag:    "unit-mysql-1",
+				}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	results, err := storageClient.Attach(
+		names.NewUnitTag("mysql/1"),
+		[]names.StorageTag{names.NewStorageTag("data/0")},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{}})
+}
+
+func (s *storageMockSuite) TestAttachFacadeCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("facade failure")
+		})
+	storageClient := storage.NewClient(apiCaller)
+	_, err := storageClient.Attach(
+		names.NewUnitTag("mysql/1"),
+		[]names.StorageTag{names.NewStorageTag("data/0")},
+	)
+	c.Assert(err, gc.ErrorMatches, "facade failure")
+}
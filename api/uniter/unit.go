@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/process"
 )
 
 // Unit represents a juju unit as seen by a uniter worker.
@@ -757,3 +758,67 @@ func (u *Unit) AddStorage(constraints map[string][]params.StorageConstraints) er
 
 	return results.Combine()
 }
+
+// SetProcesses records the workload processes tracked by the unit,
+// replacing any previously recorded.
+func (u *Unit) SetProcesses(infos []process.Info) error {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("SetProcesses() (need V3+)")
+	}
+	processes := make([]params.ProcessInfo, len(infos))
+	for i, info := range infos {
+		processes[i] = params.ProcessInfo{
+			Name: info.Name,
+			Type: info.Type,
+			Details: params.ProcessDetails{
+				ID:     info.Details.ID,
+				Status: info.Details.Status,
+				Extra:  info.Details.Extra,
+			},
+		}
+		if usage := info.Details.Usage; usage != nil {
+			processes[i].Details.Usage = &params.ProcessUsage{
+				CPU:      usage.CPU,
+				Memory:   usage.Memory,
+				Restarts: usage.Restarts,
+			}
+		}
+	}
+	args := params.UnitProcessesParams{
+		Units: []params.UnitProcesses{{
+			Tag:       u.tag.String(),
+			Processes: processes,
+		}},
+	}
+	var results params.ErrorResults
+	err := u.st.facade.FacadeCall("SetProcesses", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
+// WatchProcesses returns a watcher for observing changes to the
+// workload processes tracked by the unit.
+func (u *Unit) WatchProcesses() (watcher.NotifyWatcher, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("WatchProcesses() (need V3+)")
+	}
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WatchProcesses", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
+	return w, nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/process"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	jujufactory "github.com/juju/juju/testing/factory"
@@ -809,6 +810,38 @@ func (s *unitSuite) TestWatchMeterStatus(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *unitSuite) TestWatchProcesses(c *gc.C) {
+	w, err := s.apiUnit.WatchProcesses()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	info := process.Info{
+		Name: "web",
+		Type: "docker",
+		Details: process.Details{
+			ID:     "abc123",
+			Status: "running",
+			Extra:  map[string]interface{}{"image": "nginx"},
+		},
+	}
+	err = s.wordpressUnit.SetProcesses([]process.Info{info})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// A process failing is reported.
+	info.Details.Status = "exited"
+	err = s.wordpressUnit.SetProcesses([]process.Info{info})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *unitSuite) patchNewState(
 	c *gc.C,
 	patchFunc func(_ base.APICaller, _ names.UnitTag) *uniter.State,
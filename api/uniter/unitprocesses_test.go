@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/process"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type unitProcessesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&unitProcessesSuite{})
+
+func (s *unitProcessesSuite) createTestUnit(c *gc.C, apiCaller basetesting.APICallerFunc) *uniter.Unit {
+	tag := names.NewUnitTag("mysql/0")
+	st := uniter.NewState(apiCaller, tag)
+	return uniter.CreateUnit(st, tag)
+}
+
+func (s *unitProcessesSuite) TestSetProcesses(c *gc.C) {
+	expected := params.UnitProcessesParams{
+		Units: []params.UnitProcesses{{
+			Tag: "unit-mysql-0",
+			Processes: []params.ProcessInfo{{
+				Name: "web",
+				Type: "docker",
+				Details: params.ProcessDetails{
+					ID:     "abc123",
+					Status: "running",
+					Extra:  map[string]interface{}{"image": "nginx"},
+					Usage:  &params.ProcessUsage{CPU: 12.5, Memory: 1024, Restarts: 1},
+				},
+			}},
+		}},
+	}
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Assert(objType, gc.Equals, "Uniter")
+		c.Assert(version, gc.Equals, 3)
+		c.Assert(id, gc.Equals, "")
+		c.Assert(request, gc.Equals, "SetProcesses")
+		c.Assert(arg, gc.DeepEquals, expected)
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "yoink"},
+			}},
+		}
+		return nil
+	})
+	u := s.createTestUnit(c, apiCaller)
+	err := u.SetProcesses([]process.Info{{
+		Name: "web",
+		Type: "docker",
+		Details: process.Details{
+			ID:     "abc123",
+			Status: "running",
+			Extra:  map[string]interface{}{"image": "nginx"},
+			Usage:  &process.Usage{CPU: 12.5, Memory: 1024, Restarts: 1},
+		},
+	}})
+	c.Assert(err, gc.ErrorMatches, "yoink")
+}
+
+func (s *unitProcessesSuite) TestSetProcessesError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Assert(request, gc.Equals, "SetProcesses")
+		return errors.New("boom")
+	})
+	u := s.createTestUnit(c, apiCaller)
+	err := u.SetProcesses(nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *unitProcessesSuite) TestProcessesNeedV3(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+	tag := names.NewUnitTag("mysql/0")
+	u := uniter.CreateUnit(uniter.NewStateV2(apiCaller, tag), tag)
+	err := u.SetProcesses(nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = u.WatchProcesses()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
@@ -0,0 +1,166 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// accessRoot restricts the API calls an environment user may make to
+// those allowed by their access to the environment.
+type accessRoot struct {
+	rpc.MethodFinder
+	access func() (state.EnvUserAccess, error)
+}
+
+// newAccessRoot returns a new accessRoot for a user whose access to
+// the environment is returned by the given function. The access is
+// checked on every call, so that changes to it take effect without
+// the user logging in again.
+func newAccessRoot(finder rpc.MethodFinder, access func() (state.EnvUserAccess, error)) *accessRoot {
+	return &accessRoot{
+		MethodFinder: finder,
+		access:       access,
+	}
+}
+
+// envUserAccess returns a function that reads the given user's current
+// access to the environment.
+func envUserAccess(st *state.State, user names.UserTag) func() (state.EnvUserAccess, error) {
+	return func() (state.EnvUserAccess, error) {
+		envUser, err := st.EnvironmentUser(user)
+		if err != nil {
+			return "", err
+		}
+		return envUser.Access(), nil
+	}
+}
+
+// adminOnlyCalls holds calls, as "Facade.Method", that only
+// environment administrators may make.
+var adminOnlyCalls = set.NewStrings(
+	"Client.DestroyEnvironment",
+	"Client.ShareEnvironment",
+)
+
+// adminOnlyFacades holds the facades that only environment
+// administrators may call. The SystemManager facade manages the
+// whole system, so on the system environment only its
+// administrators, who are the system administrators, may use it.
+var adminOnlyFacades = set.NewStrings(
+	"SystemManager",
+)
+
+// readAccessCalls holds, for each facade, the methods that users with
+// read access to the environment may call. Any call not listed here
+// is denied to them, so new read-only methods must be added explicitly.
+// Calls that reveal secrets, such as Client.EnvironmentGet, must not
+// be listed. Calls listed here are not recorded in the audit log.
+var readAccessCalls = map[string]set.Strings{
+	"Action": set.NewStrings(
+		"Actions",
+		"FindActionTagsByPrefix",
+		"ListAll",
+		"ListCompleted",
+		"ListPending",
+		"ListRunning",
+		"ServicesCharmActions",
+	),
+	"AllWatcher":  set.NewStrings("Next", "Stop"),
+	"Annotations": set.NewStrings("Get"),
+	"Backups":     set.NewStrings("Info", "List"),
+	"Block":       set.NewStrings("List"),
+	"Charms":      set.NewStrings("CharmInfo", "IsMetered", "List"),
+	"Client": set.NewStrings(
+		"APIHostPorts",
+		"AgentVersion",
+		"AuditLog",
+		"CharmInfo",
+		"EnvUserInfo",
+		"EnvironmentInfo",
+		"FullStatus",
+		"GetAnnotations",
+		"GetBundleChanges",
+		"GetEnvironmentConstraints",
+		"GetServiceConstraints",
+		"ListProcesses",
+		"MachineStatusHistory",
+		"PrivateAddress",
+		"ProblemReports",
+		"PublicAddress",
+		"ServiceCharmRelations",
+		"ServiceGet",
+		"ServiceGetCharmURL",
+		"ServiceStatusHistory",
+		"Status",
+		"UnitStatusHistory",
+		"WatchAll",
+	),
+	"Distribution":  set.NewStrings("Report"),
+	"FirewallRules": set.NewStrings("ListFirewallRules", "ListFirewallWhitelists"),
+	"ImageManager":  set.NewStrings("ListImages"),
+	"ImageMetadata": set.NewStrings("List"),
+	"KeyManager":    set.NewStrings("ListKeys"),
+	"Pinger":        set.NewStrings("Ping", "Stop"),
+	"Service":       set.NewStrings("DestroyUnitsImpact", "ServiceDestroyImpact"),
+	"Spaces":        set.NewStrings("ListSpaces"),
+	"Storage": set.NewStrings(
+		"List",
+		"ListFilesystems",
+		"ListPools",
+		"ListVolumes",
+		"Show",
+	),
+	"Subnets":     set.NewStrings("AllSpaces", "AllZones", "ListSubnets"),
+	"UserManager": set.NewStrings("UserInfo"),
+}
+
+// anyAccessCalls holds calls, as "Facade.Method", that change state
+// but that users may make whatever their access to the environment.
+// The facades check that users only change what is their own.
+var anyAccessCalls = set.NewStrings(
+	"UserManager.SetPassword",
+)
+
+// FindMethod returns a permission denied error if the user's access to
+// the environment does not allow the call.
+func (r *accessRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	access, err := r.access()
+	if errors.IsNotFound(err) {
+		// The environment is no longer shared with the user.
+		return nil, common.ErrPerm
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !accessAllowsCall(access, rootName, methodName) {
+		return nil, common.ErrPerm
+	}
+	return caller, nil
+}
+
+// accessAllowsCall reports whether a user with the given access to the
+// environment may call the given facade method.
+func accessAllowsCall(access state.EnvUserAccess, facade, method string) bool {
+	call := facade + "." + method
+	switch access {
+	case state.EnvAdminAccess:
+		return true
+	case state.EnvWriteAccess:
+		return !adminOnlyCalls.Contains(call) && !adminOnlyFacades.Contains(facade)
+	case state.EnvReadAccess:
+		return anyAccessCalls.Contains(call) || readAccessCalls[facade].Contains(method)
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type accessRootIntSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&accessRootIntSuite{})
+
+func (s *accessRootIntSuite) TestAccessAllowsCall(c *gc.C) {
+	for i, test := range []struct {
+		access  state.EnvUserAccess
+		facade  string
+		method  string
+		allowed bool
+	}{
+		{state.EnvAdminAccess, "Client", "ServiceDeploy", true},
+		{state.EnvAdminAccess, "Client", "ShareEnvironment", true},
+		{state.EnvAdminAccess, "Client", "DestroyEnvironment", true},
+		{state.EnvWriteAccess, "Client", "ServiceDeploy", true},
+		{state.EnvWriteAccess, "Client", "DestroyMachines", true},
+		{state.EnvWriteAccess, "Client", "FullStatus", true},
+		{state.EnvWriteAccess, "Client", "ShareEnvironment", false},
+		{state.EnvWriteAccess, "Client", "DestroyEnvironment", false},
+		{state.EnvAdminAccess, "SystemManager", "DestroySystem", true},
+		{state.EnvWriteAccess, "SystemManager", "DestroySystem", false},
+		{state.EnvWriteAccess, "SystemManager", "AllEnvironments", false},
+		{state.EnvReadAccess, "SystemManager", "DestroySystem", false},
+		{state.EnvReadAccess, "SystemManager", "AllEnvironments", false},
+		{state.EnvReadAccess, "Client", "FullStatus", true},
+		{state.EnvReadAccess, "Client", "EnvUserInfo", true},
+		{state.EnvReadAccess, "Client", "WatchAll", true},
+		{state.EnvReadAccess, "AllWatcher", "Next", true},
+		{state.EnvReadAccess, "Pinger", "Ping", true},
+		{state.EnvReadAccess, "UserManager", "SetPassword", true},
+		{state.EnvReadAccess, "Client", "ServiceDeploy", false},
+		{state.EnvReadAccess, "Client", "DestroyServices", false},
+		{state.EnvReadAccess, "Client", "ShareEnvironment", false},
+		{state.EnvReadAccess, "Client", "EnvironmentGet", false},
+		{state.EnvReadAccess, "Client", "ProvisioningScript", false},
+		{state.EnvReadAccess, "Client", "GetNewThing", false},
+		{state.EnvReadAccess, "Service", "ServicesDeploy", false},
+		{state.EnvReadAccess, "Storage", "List", true},
+		{state.EnvReadAccess, "Unknown", "List", false},
+		{"", "Client", "FullStatus", false},
+	} {
+		c.Logf("test %d: %q %s.%s", i, test.access, test.facade, test.method)
+		c.Check(accessAllowsCall(test.access, test.facade, test.method), gc.Equals, test.allowed)
+	}
+}
+
+func (s *accessRootIntSuite) TestReadAccessCallsExist(c *gc.C) {
+	versions := make(map[string][]int)
+	for _, description := range common.Facades.List() {
+		versions[description.Name] = description.Versions
+	}
+	for facade, methods := range readAccessCalls {
+		c.Check(versions[facade], gc.Not(gc.HasLen), 0, gc.Commentf("facade %q", facade))
+		for _, method := range methods.SortedValues() {
+			found := false
+			for _, version := range versions[facade] {
+				facadeType, err := common.Facades.GetType(facade, version)
+				c.Assert(err, jc.ErrorIsNil)
+				if _, err := rpcreflect.ObjTypeOf(facadeType).Method(method); err == nil {
+					found = true
+				}
+			}
+			c.Check(found, jc.IsTrue, gc.Commentf("method %s.%s", facade, method))
+		}
+	}
+}
+
+func (s *accessRootIntSuite) TestFindMethod(c *gc.C) {
+	stub := &testing.Stub{}
+	access := state.EnvReadAccess
+	root := newAccessRoot(&fakeFinder{stub}, func() (state.EnvUserAccess, error) {
+		return access, nil
+	})
+	caller, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+	_, err = root.FindMethod("Client", 0, "ServiceDeploy")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	stub.CheckCallNames(c, "FindMethod", "FindMethod")
+
+	// A change of access applies to the next call.
+	access = state.EnvWriteAccess
+	_, err = root.FindMethod("Client", 0, "ServiceDeploy")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *accessRootIntSuite) TestFindMethodUserRemoved(c *gc.C) {
+	root := newAccessRoot(&fakeFinder{&testing.Stub{}}, func() (state.EnvUserAccess, error) {
+		return "", errors.NotFoundf("environment user")
+	})
+	_, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
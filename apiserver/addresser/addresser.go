@@ -59,7 +59,7 @@ func (api *AddresserAPI) getNetworkingEnviron() (environs.NetworkingEnviron, boo
 	if err != nil {
 		return nil, false, errors.Annotate(err, "validating environment config")
 	}
-	netEnv, ok := environs.SupportsNetworking(env)
+	netEnv, ok := environs.NetworkingCapable(config.Type(), env)
 	if !ok {
 		return nil, false, nil
 	}
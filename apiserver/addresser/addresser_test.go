@@ -39,7 +39,12 @@ var _ = gc.Suite(&AddresserSuite{})
 
 func (s *AddresserSuite) SetUpSuite(c *gc.C) {
 	s.BaseSuite.SetUpSuite(c)
-	environs.RegisterProvider("mock", mockEnvironProvider{})
+	environs.Register(environs.ProviderRegistration{
+		Type:         "mock",
+		Provider:     mockEnvironProvider{},
+		APIVersion:   environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{environs.CapabilityNetworking},
+	})
 }
 
 func (s *AddresserSuite) SetUpTest(c *gc.C) {
@@ -188,6 +188,17 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		loginResult.Facades = facades
 	}
 
+	// Users logged in to an environment may only make the calls
+	// their access to it allows.
+	if envUser, ok := entity.(*environmentUserEntity); ok {
+		authedApi = newAccessRoot(authedApi, envUserAccess(a.root.state, envUser.envUser.UserTag()))
+	}
+
+	// The API server's own agent is never rate limited.
+	if tag := entity.Tag(); tag != a.srv.tag {
+		authedApi = newRateLimitedRoot(authedApi, a.srv.rateLimiter.forConnection(tag.String()))
+	}
+
 	a.root.rpcConn.ServeFinder(authedApi, serverError)
 
 	return loginResult, nil
@@ -502,6 +502,40 @@ func (s *loginSuite) TestUsersAreNotRateLimited(c *gc.C) {
 	}
 }
 
+func (s *loginSuite) loginEnvUserWithAccess(c *gc.C, access state.EnvUserAccess) (api.Connection, func()) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "password", NoEnvUser: true})
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{User: user.Name(), Access: access})
+	st := s.openAPIWithoutLogin(c, info)
+	err := st.Login(user.UserTag(), "password", "")
+	c.Assert(err, jc.ErrorIsNil)
+	return st, func() {
+		st.Close()
+		cleanup()
+	}
+}
+
+func (s *loginSuite) TestReadAccessUser(c *gc.C) {
+	st, cleanup := s.loginEnvUserWithAccess(c, state.EnvReadAccess)
+	defer cleanup()
+
+	_, err := st.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.Client().EnvironmentSet(map[string]interface{}{"some-key": "value"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+}
+
+func (s *loginSuite) TestWriteAccessUser(c *gc.C) {
+	st, cleanup := s.loginEnvUserWithAccess(c, state.EnvWriteAccess)
+	defer cleanup()
+
+	err := st.Client().EnvironmentSet(map[string]interface{}{"some-key": "value"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.Client().ShareEnvironment(names.NewUserTag("bob@remote"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *loginSuite) TestNonEnvironUserLoginFails(c *gc.C) {
 	info, cleanup := s.setupServerWithValidator(c, nil)
 	defer cleanup()
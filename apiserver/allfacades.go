@@ -11,6 +11,7 @@ import (
 	_ "github.com/juju/juju/apiserver/addresser"
 	_ "github.com/juju/juju/apiserver/agent"
 	_ "github.com/juju/juju/apiserver/annotations"
+	_ "github.com/juju/juju/apiserver/autoscaler"
 	_ "github.com/juju/juju/apiserver/backups"
 	_ "github.com/juju/juju/apiserver/block"
 	_ "github.com/juju/juju/apiserver/charmrevisionupdater"
@@ -19,9 +20,12 @@ import (
 	_ "github.com/juju/juju/apiserver/client"
 	_ "github.com/juju/juju/apiserver/deployer"
 	_ "github.com/juju/juju/apiserver/diskmanager"
+	_ "github.com/juju/juju/apiserver/distribution"
 	_ "github.com/juju/juju/apiserver/environment"
 	_ "github.com/juju/juju/apiserver/environmentmanager"
 	_ "github.com/juju/juju/apiserver/firewaller"
+	_ "github.com/juju/juju/apiserver/firewallrules"
+	_ "github.com/juju/juju/apiserver/hostkeyreporter"
 	_ "github.com/juju/juju/apiserver/imagemanager"
 	_ "github.com/juju/juju/apiserver/imagemetadata"
 	_ "github.com/juju/juju/apiserver/instancepoller"
@@ -29,11 +33,13 @@ import (
 	_ "github.com/juju/juju/apiserver/keyupdater"
 	_ "github.com/juju/juju/apiserver/logger"
 	_ "github.com/juju/juju/apiserver/machine"
+	_ "github.com/juju/juju/apiserver/machinefirewaller"
 	_ "github.com/juju/juju/apiserver/machinemanager"
 	_ "github.com/juju/juju/apiserver/meterstatus"
 	_ "github.com/juju/juju/apiserver/metricsadder"
 	_ "github.com/juju/juju/apiserver/metricsmanager"
 	_ "github.com/juju/juju/apiserver/networker"
+	_ "github.com/juju/juju/apiserver/problemreporter"
 	_ "github.com/juju/juju/apiserver/provisioner"
 	_ "github.com/juju/juju/apiserver/reboot"
 	_ "github.com/juju/juju/apiserver/resumer"
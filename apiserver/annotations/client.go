@@ -52,16 +52,36 @@ func NewAPI(
 // Get returns annotations for given entities.
 // If annotations cannot be retrieved for a given entity, an error is returned.
 // Each entity is treated independently and, hence, will fail or succeed independently.
+// The annotations of all the entities found are read together.
 func (api *API) Get(args params.Entities) params.AnnotationsGetResults {
-	entityResults := []params.AnnotationsGetResult{}
-	for _, entity := range args.Entities {
-		anEntityResult := params.AnnotationsGetResult{EntityTag: entity.Tag}
-		if annts, err := api.getEntityAnnotations(entity.Tag); err != nil {
-			anEntityResult.Error = params.ErrorResult{annotateError(err, entity.Tag, "getting")}
-		} else {
-			anEntityResult.Annotations = annts
+	entityResults := make([]params.AnnotationsGetResult, len(args.Entities))
+	tags := make([]string, len(args.Entities))
+	var found []state.GlobalEntity
+	for i, entity := range args.Entities {
+		entityResults[i].EntityTag = entity.Tag
+		tag, err := names.ParseTag(entity.Tag)
+		if err != nil {
+			entityResults[i].Error = params.ErrorResult{annotateError(err, entity.Tag, "getting")}
+			continue
+		}
+		annotated, err := api.findEntity(tag)
+		if err != nil {
+			entityResults[i].Error = params.ErrorResult{annotateError(err, entity.Tag, "getting")}
+			continue
 		}
-		entityResults = append(entityResults, anEntityResult)
+		tags[i] = tag.String()
+		found = append(found, annotated)
+	}
+	annotations, err := api.access.AnnotationsForEntities(found)
+	for i, result := range entityResults {
+		if result.Error.Error != nil {
+			continue
+		}
+		if err != nil {
+			entityResults[i].Error = params.ErrorResult{annotateError(err, result.EntityTag, "getting")}
+			continue
+		}
+		entityResults[i].Annotations = annotations[tags[i]]
 	}
 	return params.AnnotationsGetResults{Results: entityResults}
 }
@@ -86,22 +106,6 @@ func annotateError(err error, tag, op string) *params.Error {
 				err, "while %v annotations to %q", op, tag)))
 }
 
-func (api *API) getEntityAnnotations(entityTag string) (map[string]string, error) {
-	tag, err := names.ParseTag(entityTag)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	entity, err := api.findEntity(tag)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	annotations, err := api.access.GetAnnotations(entity)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	return annotations, nil
-}
-
 func (api *API) findEntity(tag names.Tag) (state.GlobalEntity, error) {
 	entity0, err := api.access.FindEntity(tag)
 	if err != nil {
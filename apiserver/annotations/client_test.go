@@ -231,6 +231,42 @@ func (s *annotationSuite) TestMultipleEntitiesAnnotations(c *gc.C) {
 	c.Assert(rGet, jc.IsTrue)
 }
 
+func (s *annotationSuite) TestGetManyEntitiesAnnotations(c *gc.C) {
+	m0 := s.Factory.MakeMachine(c, nil)
+	m1 := s.Factory.MakeMachine(c, nil)
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	entities := []string{m0.Tag().String(), env.Tag().String()}
+	annotations := map[string]string{"mykey": "myvalue"}
+	setResult := s.annotationsApi.Set(
+		params.AnnotationsSet{Annotations: constructSetParameters(entities, annotations)})
+	c.Assert(setResult.Combine(), jc.ErrorIsNil)
+
+	got := s.annotationsApi.Get(params.Entities{[]params.Entity{
+		{m1.Tag().String()},
+		{"machine-42"},
+		{env.Tag().String()},
+		{"invalid"},
+		{m0.Tag().String()},
+	}})
+	c.Assert(got.Results, gc.HasLen, 5)
+
+	// The results are in the order requested.
+	c.Assert(got.Results[0].EntityTag, gc.Equals, m1.Tag().String())
+	c.Assert(got.Results[0].Error.Error, gc.IsNil)
+	c.Assert(got.Results[0].Annotations, gc.HasLen, 0)
+	c.Assert(got.Results[1].EntityTag, gc.Equals, "machine-42")
+	c.Assert(got.Results[1].Error.Error, gc.ErrorMatches, ".*permission denied.*")
+	c.Assert(got.Results[2].EntityTag, gc.Equals, env.Tag().String())
+	c.Assert(got.Results[2].Error.Error, gc.IsNil)
+	c.Assert(got.Results[2].Annotations, gc.DeepEquals, annotations)
+	c.Assert(got.Results[3].EntityTag, gc.Equals, "invalid")
+	c.Assert(got.Results[3].Error.Error, gc.ErrorMatches, `.*"invalid" is not a valid tag.*`)
+	c.Assert(got.Results[4].EntityTag, gc.Equals, m0.Tag().String())
+	c.Assert(got.Results[4].Error.Error, gc.IsNil)
+	c.Assert(got.Results[4].Annotations, gc.DeepEquals, annotations)
+}
+
 func (s *annotationSuite) testSetGetEntitiesAnnotations(c *gc.C, tag names.Tag) {
 	entity := tag.String()
 	entities := []string{entity}
@@ -11,7 +11,7 @@ import (
 
 type annotationAccess interface {
 	FindEntity(tag names.Tag) (state.Entity, error)
-	GetAnnotations(entity state.GlobalEntity) (map[string]string, error)
+	AnnotationsForEntities(entities []state.GlobalEntity) (map[string]map[string]string, error)
 	SetAnnotations(entity state.GlobalEntity, annotations map[string]string) error
 }
 
@@ -23,8 +23,8 @@ func (s stateShim) FindEntity(tag names.Tag) (state.Entity, error) {
 	return s.state.FindEntity(tag)
 }
 
-func (s stateShim) GetAnnotations(entity state.GlobalEntity) (map[string]string, error) {
-	return s.state.Annotations(entity)
+func (s stateShim) AnnotationsForEntities(entities []state.GlobalEntity) (map[string]map[string]string, error) {
+	return s.state.AnnotationsForEntities(entities)
 }
 
 func (s stateShim) SetAnnotations(entity state.GlobalEntity, annotations map[string]string) error {
@@ -51,6 +51,10 @@ type Server struct {
 	mongoUnavailable  uint32 // non zero if mongoUnavailable
 	environUUID       string
 	authCtxt          *authContext
+	auditor           *auditor
+	rateLimiter       *rateLimiter
+	metrics           *apiMetrics
+	charmUploads      *charmUploads
 }
 
 // LoginValidator functions are used to decide whether login requests
@@ -67,6 +71,15 @@ type ServerConfig struct {
 	LogDir      string
 	Validator   LoginValidator
 	CertChanged chan params.StateServingInfo
+
+	// AuditSyslog, if true, causes audit entries to be written to the
+	// local syslog daemon as well as to state.
+	AuditSyslog bool
+
+	// RateLimit holds the limits on the rate at which requests are
+	// served to authenticated connections. The zero value imposes no
+	// limits.
+	RateLimit RateLimitConfig
 }
 
 // changeCertListener wraps a TLS net.Listener.
@@ -193,8 +206,18 @@ func newServer(s *state.State, lis *net.TCPListener, cfg ServerConfig) (_ *Serve
 			1: newAdminApiV1,
 			2: newAdminApiV2,
 		},
+		charmUploads: newCharmUploads(),
 	}
 	srv.authCtxt = newAuthContext(srv)
+	var sink auditSink
+	if cfg.AuditSyslog {
+		if sink, err = newSyslogAuditSink(); err != nil {
+			return nil, errors.Annotate(err, "cannot write audit entries to syslog")
+		}
+	}
+	srv.auditor = newAuditor(sink)
+	srv.rateLimiter = newRateLimiter(cfg.RateLimit)
+	srv.metrics = newAPIMetrics()
 	tlsCert, err := tls.X509KeyPair(cfg.Cert, cfg.Key)
 	if err != nil {
 		return nil, err
@@ -232,20 +255,30 @@ func (srv *Server) Wait() error {
 }
 
 type requestNotifier struct {
-	id    int64
-	start time.Time
+	id      int64
+	start   time.Time
+	metrics *apiMetrics
 
 	mu   sync.Mutex
 	tag_ string
+
+	// auditor and st are set once the connection's environment is
+	// known, after which state-changing requests are recorded in its
+	// audit log. pending holds the argument summaries of the audited
+	// requests awaiting replies, keyed by request id.
+	auditor *auditor
+	st      *state.State
+	pending map[uint64]string
 }
 
 var globalCounter int64
 
-func newRequestNotifier() *requestNotifier {
+func newRequestNotifier(metrics *apiMetrics) *requestNotifier {
 	return &requestNotifier{
-		id:    atomic.AddInt64(&globalCounter, 1),
-		tag_:  "<unknown>",
-		start: time.Now(),
+		id:      atomic.AddInt64(&globalCounter, 1),
+		tag_:    "<unknown>",
+		start:   time.Now(),
+		metrics: metrics,
 	}
 }
 
@@ -262,30 +295,78 @@ func (n *requestNotifier) tag() (tag string) {
 	return
 }
 
+// startAudit causes state-changing requests to be recorded in the
+// audit log of the given environment.
+func (n *requestNotifier) startAudit(auditor *auditor, st *state.State) {
+	n.mu.Lock()
+	n.auditor = auditor
+	n.st = st
+	n.pending = make(map[uint64]string)
+	n.mu.Unlock()
+}
+
+func (n *requestNotifier) auditRequest(hdr *rpc.Header, body interface{}) {
+	if !isAuditedRequest(hdr.Request) {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.auditor != nil {
+		n.pending[hdr.RequestId] = summarizeArgs(body)
+	}
+}
+
+func (n *requestNotifier) auditReply(req rpc.Request, hdr *rpc.Header) {
+	n.mu.Lock()
+	args, ok := n.pending[hdr.RequestId]
+	delete(n.pending, hdr.RequestId)
+	tag := n.tag_
+	n.mu.Unlock()
+	if !ok {
+		return
+	}
+	n.auditor.record(n.st, state.AuditEntry{
+		Time:      time.Now(),
+		Entity:    tag,
+		Facade:    req.Type,
+		Version:   req.Version,
+		Method:    req.Action,
+		Args:      args,
+		Error:     hdr.Error,
+		ErrorCode: hdr.ErrorCode,
+
+		// The reply echoes the correlation id of its request.
+		CorrelationId: hdr.CorrelationId,
+	})
+}
+
 func (n *requestNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
 	if hdr.Request.Type == "Pinger" && hdr.Request.Action == "Ping" {
 		return
 	}
+	n.auditRequest(hdr, body)
 	// TODO(rog) 2013-10-11 remove secrets from some requests.
 	// Until secrets are removed, we only log the body of the requests at trace level
 	// which is below the default level of debug.
 	if logger.IsTraceEnabled() {
 		logger.Tracef("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
-	} else {
+	} else if logger.IsDebugEnabled() {
 		logger.Debugf("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, "'params redacted'"))
 	}
 }
 
 func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
+	n.metrics.requestServed(req.Type, timeSpent, hdr.Error != "")
 	if req.Type == "Pinger" && req.Action == "Ping" {
 		return
 	}
+	n.auditReply(req, hdr)
 	// TODO(rog) 2013-10-11 remove secrets from some responses.
 	// Until secrets are removed, we only log the body of the requests at trace level
 	// which is below the default level of debug.
 	if logger.IsTraceEnabled() {
 		logger.Tracef("-> [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
-	} else {
+	} else if logger.IsDebugEnabled() {
 		logger.Debugf("-> [%X] %s %s %s %s[%q].%s", n.id, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, "'body redacted'"), req.Type, req.Id, req.Action)
 	}
 }
@@ -333,6 +414,12 @@ func (srv *Server) run(lis net.Listener) {
 		srv.wg.Done()
 	}()
 
+	srv.wg.Add(1)
+	go func() {
+		srv.auditor.loop(srv.tomb.Dying())
+		srv.wg.Done()
+	}()
+
 	// for pat based handlers, they are matched in-order of being
 	// registered, first match wins. So more specific ones have to be
 	// registered first.
@@ -354,7 +441,9 @@ func (srv *Server) run(lis net.Listener) {
 	handleAll(mux, "/environment/:envuuid/charms",
 		&charmsHandler{
 			ctxt:    httpCtxt,
-			dataDir: srv.dataDir},
+			dataDir: srv.dataDir,
+			uploads: srv.charmUploads,
+		},
 	)
 	// TODO: We can switch from handleAll to mux.Post/Get/etc for entries
 	// where we only want to support specific request methods. However, our
@@ -399,6 +488,7 @@ func (srv *Server) run(lis net.Listener) {
 		&charmsHandler{
 			ctxt:    httpCtxt,
 			dataDir: srv.dataDir,
+			uploads: srv.charmUploads,
 		},
 	)
 	handleAll(mux, "/tools",
@@ -411,6 +501,16 @@ func (srv *Server) run(lis net.Listener) {
 			ctxt: httpCtxt,
 		},
 	)
+	// The metrics cover the whole API server, so they are only
+	// served to users of the state server environment.
+	metricsCtxt := httpCtxt
+	metricsCtxt.stateServerEnvOnly = true
+	handleAll(mux, "/introspection/metrics",
+		&metricsHandler{
+			ctxt:    metricsCtxt,
+			metrics: srv.metrics,
+		},
+	)
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
 
 	go func() {
@@ -423,7 +523,7 @@ func (srv *Server) run(lis net.Listener) {
 }
 
 func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
-	reqNotifier := newRequestNotifier()
+	reqNotifier := newRequestNotifier(srv.metrics)
 	reqNotifier.join(req)
 	defer reqNotifier.leave()
 	wsServer := websocket.Server{
@@ -437,6 +537,8 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 			if srv.tomb.Err() != tomb.ErrStillAlive {
 				return
 			}
+			srv.metrics.connectionOpened()
+			defer srv.metrics.connectionClosed()
 			envUUID := req.URL.Query().Get(":envuuid")
 			logger.Tracef("got a request for env %q", envUUID)
 			if err := srv.serveConn(conn, reqNotifier, envUUID); err != nil {
@@ -457,18 +559,15 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
-	var notifier rpc.RequestNotifier
-	if logger.EffectiveLogLevel() <= loggo.DEBUG {
-		// Incur request monitoring overhead only if we
-		// know we'll need it.
-		notifier = reqNotifier
-	}
-	conn := rpc.NewConn(codec, notifier)
+	// The request notifier is needed even when requests are not being
+	// logged, so that state-changing requests are audited.
+	conn := rpc.NewConn(codec, reqNotifier)
 
 	h, err := srv.newAPIHandler(conn, reqNotifier, envUUID)
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
 	} else {
+		reqNotifier.startAudit(srv.auditor, h.state)
 		adminApis := make(map[int]interface{})
 		for apiVersion, factory := range srv.adminApiFactories {
 			adminApis[apiVersion] = factory(srv, h, reqNotifier)
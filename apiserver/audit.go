@@ -0,0 +1,264 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+const (
+	// auditBacklog is the number of audit entries that may be waiting
+	// to be written before further entries are dropped.
+	auditBacklog = 1000
+
+	// maxAuditArgsLen bounds the length of the argument summaries
+	// recorded in the audit log.
+	maxAuditArgsLen = 512
+)
+
+// auditSink receives audit entries in addition to the audit log held
+// in state.
+type auditSink interface {
+	Write(envUUID string, entry state.AuditEntry) error
+}
+
+type auditRecord struct {
+	st    *state.State
+	entry state.AuditEntry
+}
+
+// auditor writes audit entries to the audit logs of the environments
+// they were made against, and to an optional sink. Entries are written
+// asynchronously so that API requests are not held up by the writes.
+type auditor struct {
+	records chan auditRecord
+	sink    auditSink
+}
+
+func newAuditor(sink auditSink) *auditor {
+	return &auditor{
+		records: make(chan auditRecord, auditBacklog),
+		sink:    sink,
+	}
+}
+
+// record queues the entry to be written to the audit log of st.
+func (a *auditor) record(st *state.State, entry state.AuditEntry) {
+	select {
+	case a.records <- auditRecord{st, entry}:
+	default:
+		logger.Warningf("audit backlog full, dropping entry for %s.%s by %s", entry.Facade, entry.Method, entry.Entity)
+	}
+}
+
+// loop writes queued audit entries until stop is closed.
+func (a *auditor) loop(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case r := <-a.records:
+			a.write(r)
+		}
+	}
+}
+
+func (a *auditor) write(r auditRecord) {
+	if err := r.st.AddAuditEntry(r.entry); err != nil {
+		logger.Errorf("cannot record %s.%s by %s: %v", r.entry.Facade, r.entry.Method, r.entry.Entity, err)
+	}
+	if a.sink == nil {
+		return
+	}
+	if err := a.sink.Write(r.st.EnvironUUID(), r.entry); err != nil {
+		logger.Errorf("cannot write audit entry: %v", err)
+	}
+}
+
+// formatAuditEntry returns a single-line description of the entry,
+// suitable for writing to a log.
+func formatAuditEntry(envUUID string, entry state.AuditEntry) string {
+	result := "ok"
+	if entry.Error != "" {
+		result = fmt.Sprintf("error %q", entry.Error)
+	}
+	return fmt.Sprintf("env=%s entity=%s call=%s(%d).%s args=%s result=%s",
+		envUUID, entry.Entity, entry.Facade, entry.Version, entry.Method, entry.Args, result,
+	)
+}
+
+// unauditedFacades holds the facades whose calls are never audited,
+// because they do not change state or because their arguments hold
+// credentials.
+var unauditedFacades = set.NewStrings("Admin", "Pinger")
+
+// watcherFacades holds the facades through which clients follow
+// watchers. None of their calls change state.
+var watcherFacades = set.NewStrings(
+	"AllEnvWatcher",
+	"AllWatcher",
+	"EntityWatcher",
+	"FilesystemAttachmentsWatcher",
+	"NotifyWatcher",
+	"RelationUnitsWatcher",
+	"StringsWatcher",
+	"VolumeAttachmentsWatcher",
+)
+
+// commonReadOnlyMethods holds the methods, provided to many facades by
+// the shared implementations in apiserver/common, that do not change
+// state.
+var commonReadOnlyMethods = set.NewStrings(
+	"APIAddresses",
+	"APIHostPorts",
+	"CACert",
+	"EnvironConfig",
+	"EnvironUUID",
+	"Life",
+	"StateAddresses",
+	"Watch",
+	"WatchAPIHostPorts",
+	"WatchForEnvironConfigChanges",
+)
+
+// agentReadOnlyCalls holds, for each facade used by agents, the methods
+// that do not change state. Calls made by users that do not change
+// state are listed in readAccessCalls.
+var agentReadOnlyCalls = map[string]set.Strings{
+	"Uniter": set.NewStrings(
+		"Actions",
+		"AllMachinePorts",
+		"AssignedMachine",
+		"AvailabilityZone",
+		"CharmArchiveSha256",
+		"CharmArchiveURLs",
+		"CharmURL",
+		"ConfigSettings",
+		"CurrentEnvironUUID",
+		"CurrentEnvironment",
+		"GetOwnerTag",
+		"GetPrincipal",
+		"HasSubordinates",
+		"JoinedRelations",
+		"PrivateAddress",
+		"ProviderType",
+		"PublicAddress",
+		"Read",
+		"ReadRemoteSettings",
+		"ReadSettings",
+		"Relation",
+		"RelationById",
+		"Resolved",
+		"ServiceOwner",
+		"StorageAttachmentLife",
+		"StorageAttachments",
+		"UnitStorageAttachments",
+		"WatchActionNotifications",
+		"WatchConfigSettings",
+		"WatchLeadershipSettings",
+		"WatchProcesses",
+		"WatchRelationUnits",
+		"WatchServiceRelations",
+		"WatchStorageAttachments",
+		"WatchUnitAddresses",
+		"WatchUnitStorageAttachments",
+	),
+}
+
+// isAuditedRequest reports whether the request may change state, and
+// so should be recorded in the audit log.
+func isAuditedRequest(req rpc.Request) bool {
+	if unauditedFacades.Contains(req.Type) {
+		return false
+	}
+	return !isReadOnlyCall(req.Type, req.Action)
+}
+
+// isReadOnlyCall reports whether a call to the given facade method is
+// known not to change state. Only calls that are explicitly listed are
+// considered read-only; any other call is assumed to change state, so
+// new read-only methods must be added to readAccessCalls (if users may
+// make them) or agentReadOnlyCalls to keep them out of the audit log.
+func isReadOnlyCall(facade, method string) bool {
+	if watcherFacades.Contains(facade) || commonReadOnlyMethods.Contains(method) {
+		return true
+	}
+	return readAccessCalls[facade].Contains(method) || agentReadOnlyCalls[facade].Contains(method)
+}
+
+// secretFields holds the substrings of field names whose values are
+// never recorded in the audit log.
+var secretFields = []string{"password", "secret", "privatekey", "credential", "token", "macaroon"}
+
+// summarizeArgs returns a JSON summary of the arguments to an API call,
+// with the values of any secret fields redacted and the result
+// truncated to maxAuditArgsLen bytes.
+func summarizeArgs(body interface{}) string {
+	if body == nil {
+		return ""
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ""
+	}
+	data, err = json.Marshal(redactSecrets(value))
+	if err != nil {
+		return ""
+	}
+	summary := string(data)
+	switch summary {
+	case "null", "{}":
+		return ""
+	}
+	if len(summary) > maxAuditArgsLen {
+		summary = summary[:maxAuditArgsLen]
+		for !utf8.ValidString(summary) {
+			summary = summary[:len(summary)-1]
+		}
+		summary += "..."
+	}
+	return summary
+}
+
+// redactSecrets returns the value with the values of any secret fields
+// replaced.
+func redactSecrets(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			if isSecretField(k) {
+				value[k] = "<redacted>"
+			} else {
+				value[k] = redactSecrets(v)
+			}
+		}
+	case []interface{}:
+		for i, v := range value {
+			value[i] = redactSecrets(v)
+		}
+	}
+	return value
+}
+
+func isSecretField(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretFields {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditIntSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&auditIntSuite{})
+
+func (s *auditIntSuite) TestIsAuditedRequest(c *gc.C) {
+	for i, test := range []struct {
+		facade  string
+		method  string
+		audited bool
+	}{
+		{"Client", "ServiceDeploy", true},
+		{"Client", "SetEnvironmentConstraints", true},
+		{"Uniter", "SetStatus", true},
+		{"Provisioner", "SetInstanceInfo", true},
+		{"Client", "Status", false},
+		{"Client", "FullStatus", false},
+		{"Client", "GetEnvironmentConstraints", false},
+		{"Client", "ServiceGet", false},
+		{"Client", "UnitStatusHistory", false},
+		{"Client", "AuditLog", false},
+		{"Uniter", "Life", false},
+		{"Uniter", "WatchProcesses", false},
+		{"Uniter", "Getaway", true},
+		{"Uniter", "GetawayList", true},
+		{"Uniter", "Is", true},
+		{"Machiner", "Life", false},
+		{"Machiner", "Watch", false},
+		{"Client", "ListKeys", true},
+		{"KeyManager", "ListKeys", false},
+		{"AllWatcher", "Next", false},
+		{"NotifyWatcher", "Stop", false},
+		{"Admin", "Login", false},
+		{"Pinger", "Ping", false},
+	} {
+		c.Logf("test %d: %s.%s", i, test.facade, test.method)
+		audited := isAuditedRequest(rpc.Request{Type: test.facade, Action: test.method})
+		c.Check(audited, gc.Equals, test.audited)
+	}
+}
+
+func (s *auditIntSuite) TestSummarizeArgs(c *gc.C) {
+	c.Check(summarizeArgs(nil), gc.Equals, "")
+	c.Check(summarizeArgs(params.Entities{}), gc.Equals, `{"Entities":null}`)
+	c.Check(summarizeArgs(struct{}{}), gc.Equals, "")
+	c.Check(summarizeArgs(params.ServiceExpose{ServiceName: "wordpress"}), gc.Equals, `{"ServiceName":"wordpress"}`)
+}
+
+func (s *auditIntSuite) TestSummarizeArgsRedactsSecrets(c *gc.C) {
+	args := params.EntityPasswords{
+		Changes: []params.EntityPassword{{Tag: "machine-0", Password: "sekrit"}},
+	}
+	c.Check(summarizeArgs(args), gc.Equals, `{"Changes":[{"Password":"<redacted>","Tag":"machine-0"}]}`)
+}
+
+func (s *auditIntSuite) TestSummarizeArgsTruncates(c *gc.C) {
+	args := params.ServiceExpose{ServiceName: strings.Repeat("x", 1000)}
+	summary := summarizeArgs(args)
+	c.Check(summary, gc.HasLen, maxAuditArgsLen+len("..."))
+	c.Check(strings.HasSuffix(summary, "..."), jc.IsTrue)
+}
+
+func (s *auditIntSuite) TestFormatAuditEntry(c *gc.C) {
+	entry := state.AuditEntry{
+		Time:   time.Now(),
+		Entity: "user-admin@local",
+		Facade: "Client",
+		Method: "ServiceExpose",
+		Args:   `{"ServiceName":"wordpress"}`,
+		Error:  `service "wordpress" not found`,
+	}
+	c.Check(formatAuditEntry("uuid", entry), gc.Equals,
+		`env=uuid entity=user-admin@local call=Client(0).ServiceExpose args={"ServiceName":"wordpress"} result=error "service \"wordpress\" not found"`,
+	)
+	entry.Error = ""
+	c.Check(formatAuditEntry("uuid", entry), gc.Equals,
+		`env=uuid entity=user-admin@local call=Client(0).ServiceExpose args={"ServiceName":"wordpress"} result=ok`,
+	)
+}
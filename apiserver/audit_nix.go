@@ -0,0 +1,32 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package apiserver
+
+import (
+	"log/syslog"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+)
+
+// syslogAuditSink writes audit entries to the local syslog daemon.
+type syslogAuditSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogAuditSink() (auditSink, error) {
+	w, err := syslog.New(syslog.LOG_NOTICE|syslog.LOG_DAEMON, "juju-audit")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &syslogAuditSink{w}, nil
+}
+
+// Write is part of the auditSink interface.
+func (s *syslogAuditSink) Write(envUUID string, entry state.AuditEntry) error {
+	return s.w.Notice(formatAuditEntry(envUUID, entry))
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+// waitAuditEntries waits for the given number of Client calls to be
+// recorded in the audit log, which is written asynchronously.
+func (s *auditSuite) waitAuditEntries(c *gc.C, count int) []state.AuditEntry {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		entries, err := s.State.AuditEntries(state.AuditFilter{Facade: "Client"})
+		c.Assert(err, jc.ErrorIsNil)
+		if len(entries) >= count || !a.HasNext() {
+			c.Assert(entries, gc.HasLen, count)
+			return entries
+		}
+	}
+	panic("unreachable")
+}
+
+func (s *auditSuite) TestStateChangingCallsAudited(c *gc.C) {
+	client := s.APIState.Client()
+	_, err := client.GetEnvironmentConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.SetEnvironmentConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.ServiceExpose("wordpress")
+	c.Assert(err, gc.ErrorMatches, `service "wordpress" not found`)
+
+	entries := s.waitAuditEntries(c, 2)
+	user := s.AdminUserTag(c).String()
+
+	expose := entries[0]
+	c.Check(expose.Entity, gc.Equals, user)
+	c.Check(expose.Facade, gc.Equals, "Client")
+	c.Check(expose.Method, gc.Equals, "ServiceExpose")
+	c.Check(expose.Args, gc.Equals, `{"ServiceName":"wordpress"}`)
+	c.Check(expose.Error, gc.Equals, `service "wordpress" not found`)
+	c.Check(expose.ErrorCode, gc.Equals, "not found")
+
+	setConstraints := entries[1]
+	c.Check(setConstraints.Entity, gc.Equals, user)
+	c.Check(setConstraints.Method, gc.Equals, "SetEnvironmentConstraints")
+	c.Check(setConstraints.Args, jc.Contains, `"mem":4096`)
+	c.Check(setConstraints.Error, gc.Equals, "")
+}
@@ -0,0 +1,12 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+)
+
+func newSyslogAuditSink() (auditSink, error) {
+	return nil, errors.NotSupportedf("syslog audit sink on windows")
+}
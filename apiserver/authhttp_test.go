@@ -170,6 +170,9 @@ type httpRequestParams struct {
 
 	// nonce holds the machine nonce to provide in the header.
 	nonce string
+
+	// header holds any other headers to send with the request.
+	header http.Header
 }
 
 func (s *authHttpSuite) sendRequest(c *gc.C, p httpRequestParams) *http.Response {
@@ -191,6 +194,9 @@ func (s *authHttpSuite) sendRequest(c *gc.C, p httpRequestParams) *http.Response
 	if p.nonce != "" {
 		hp.Header.Set(params.MachineNonceHeader, p.nonce)
 	}
+	for name, values := range p.header {
+		hp.Header[name] = values
+	}
 	if hp.Do == nil {
 		hp.Do = utils.GetNonValidatingHTTPClient().Do
 	}
@@ -241,6 +247,18 @@ func (s *authHttpSuite) setupOtherEnvironment(c *gc.C) *state.State {
 	return envState
 }
 
+// setupReadOnlyUser makes authRequest use a user with read access to
+// the environment.
+func (s *authHttpSuite) setupReadOnlyUser(c *gc.C) {
+	s.password = "password"
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: s.password, NoEnvUser: true})
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{
+		User:   user.UserTag().Id(),
+		Access: state.EnvReadAccess,
+	})
+	s.userTag = user.UserTag()
+}
+
 func (s *authHttpSuite) uploadRequest(c *gc.C, uri string, contentType, path string) *http.Response {
 	if path == "" {
 		return s.authRequest(c, httpRequestParams{
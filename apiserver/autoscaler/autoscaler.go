@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	jjj "github.com/juju/juju/juju"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Autoscaler", 1, NewAutoscalerAPI)
+}
+
+var logger = loggo.GetLogger("juju.apiserver.autoscaler")
+
+// AutoscalerAPI provides access to the Autoscaler API facade. It
+// allows the units of a service to be driven by the size of a cloud
+// instance group linked to the service.
+type AutoscalerAPI struct {
+	*common.EnvironWatcher
+
+	st    *state.State
+	check *common.BlockChecker
+}
+
+// NewAutoscalerAPI creates a new server-side Autoscaler API facade.
+func NewAutoscalerAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*AutoscalerAPI, error) {
+	if !authorizer.AuthEnvironManager() {
+		// Autoscaler must run as environment manager.
+		return nil, common.ErrPerm
+	}
+	return &AutoscalerAPI{
+		EnvironWatcher: common.NewEnvironWatcher(st, resources, authorizer),
+		st:             st,
+		check:          common.NewBlockChecker(st),
+	}, nil
+}
+
+// ServiceInstanceGroups returns the services that are linked to a
+// cloud instance group by way of the params.InstanceGroupAnnotation
+// service annotation.
+func (api *AutoscalerAPI) ServiceInstanceGroups() (params.ServiceInstanceGroupsResult, error) {
+	var result params.ServiceInstanceGroupsResult
+	services, err := api.st.AllServices()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, service := range services {
+		if service.Life() != state.Alive {
+			continue
+		}
+		group, err := api.st.Annotation(service, params.InstanceGroupAnnotation)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if group == "" {
+			continue
+		}
+		result.Results = append(result.Results, params.ServiceInstanceGroup{
+			ServiceTag:    service.Tag().String(),
+			InstanceGroup: group,
+		})
+	}
+	return result, nil
+}
+
+// SetInstanceGroupSizes records the observed sizes of the cloud
+// instance groups linked to services, adding or destroying units
+// so that each service has as many units as its group has instances.
+func (api *AutoscalerAPI) SetInstanceGroupSizes(args params.InstanceGroupSizes) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Sizes)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Sizes {
+		err := api.setInstanceGroupSize(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *AutoscalerAPI) setInstanceGroupSize(arg params.InstanceGroupSize) error {
+	tag, err := names.ParseServiceTag(arg.ServiceTag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	if arg.Size < 0 {
+		return errors.NotValidf("instance group size %d", arg.Size)
+	}
+	service, err := api.st.Service(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if service.Life() != state.Alive {
+		return errors.Errorf("service %q is not alive", service.Name())
+	}
+	group, err := api.st.Annotation(service, params.InstanceGroupAnnotation)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if group != arg.InstanceGroup {
+		return errors.Errorf(
+			"service %q is not linked to instance group %q",
+			service.Name(), arg.InstanceGroup,
+		)
+	}
+	units, err := service.AllUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var alive []*state.Unit
+	for _, unit := range units {
+		if unit.Life() == state.Alive {
+			alive = append(alive, unit)
+		}
+	}
+	switch {
+	case arg.Size > len(alive):
+		n := arg.Size - len(alive)
+		logger.Infof("adding %d unit(s) to service %q (instance group %q)", n, service.Name(), group)
+		if _, err := jjj.AddUnits(api.st, service, n, ""); err != nil {
+			return errors.Trace(err)
+		}
+	case arg.Size < len(alive):
+		if err := api.check.RemoveAllowed(); err != nil {
+			return errors.Trace(err)
+		}
+		return destroySurplusUnits(alive, arg.Size, group)
+	}
+	return nil
+}
+
+// destroySurplusUnits destroys all but the oldest size of the given
+// units. As with "juju remove-unit", the machine hosting a destroyed
+// unit is destroyed along with it if it is left with nothing to do.
+// A unit that cannot be destroyed does not stop the others from
+// being destroyed.
+func destroySurplusUnits(units []*state.Unit, size int, group string) error {
+	sort.Sort(byUnitNumber(units))
+	var failed []string
+	for _, unit := range units[size:] {
+		logger.Infof("destroying unit %q (instance group %q)", unit.Name(), group)
+		if err := unit.Destroy(); err != nil {
+			logger.Errorf("cannot destroy unit %q: %v", unit.Name(), err)
+			failed = append(failed, unit.Name())
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("cannot destroy units: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// byUnitNumber sorts units by their unit number.
+type byUnitNumber []*state.Unit
+
+func (u byUnitNumber) Len() int      { return len(u) }
+func (u byUnitNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u byUnitNumber) Less(i, j int) bool {
+	return unitNumber(u[i].Name()) < unitNumber(u[j].Name())
+}
+
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
@@ -0,0 +1,147 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/autoscaler"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type autoscalerSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *autoscaler.AutoscalerAPI
+	authorizer apiservertesting.FakeAuthorizer
+	service    *state.Service
+}
+
+var _ = gc.Suite(&autoscalerSuite{})
+
+func (s *autoscalerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		EnvironManager: true,
+	}
+	resources := common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { resources.StopAll() })
+	var err error
+	s.api, err = autoscaler.NewAutoscalerAPI(s.State, resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.service = s.Factory.MakeService(c, &factory.ServiceParams{Name: "wordpress"})
+	s.Factory.MakeService(c, &factory.ServiceParams{Name: "mysql"})
+	err = s.State.SetAnnotations(s.service, map[string]string{
+		params.InstanceGroupAnnotation: "wordpress-group",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *autoscalerSuite) TestNewAutoscalerAPIRequiresEnvironManager(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.EnvironManager = false
+	api, err := autoscaler.NewAutoscalerAPI(s.State, nil, anAuthorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *autoscalerSuite) TestServiceInstanceGroups(c *gc.C) {
+	result, err := s.api.ServiceInstanceGroups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ServiceInstanceGroupsResult{
+		Results: []params.ServiceInstanceGroup{{
+			ServiceTag:    "service-wordpress",
+			InstanceGroup: "wordpress-group",
+		}},
+	})
+}
+
+func (s *autoscalerSuite) setInstanceGroupSize(c *gc.C, size int) {
+	result, err := s.api.SetInstanceGroupSizes(params.InstanceGroupSizes{
+		Sizes: []params.InstanceGroupSize{{
+			ServiceTag:    "service-wordpress",
+			InstanceGroup: "wordpress-group",
+			Size:          size,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+}
+
+func (s *autoscalerSuite) aliveUnitNames(c *gc.C) []string {
+	units, err := s.service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, unit := range units {
+		if unit.Life() == state.Alive {
+			names = append(names, unit.Name())
+		}
+	}
+	return names
+}
+
+func (s *autoscalerSuite) TestSetInstanceGroupSizesAddsUnits(c *gc.C) {
+	s.setInstanceGroupSize(c, 3)
+	c.Assert(s.aliveUnitNames(c), jc.SameContents, []string{
+		"wordpress/0", "wordpress/1", "wordpress/2",
+	})
+}
+
+func (s *autoscalerSuite) TestSetInstanceGroupSizesDestroysNewestUnits(c *gc.C) {
+	s.setInstanceGroupSize(c, 3)
+	s.setInstanceGroupSize(c, 1)
+	c.Assert(s.aliveUnitNames(c), jc.DeepEquals, []string{"wordpress/0"})
+}
+
+func (s *autoscalerSuite) TestSetInstanceGroupSizesDestroysMachines(c *gc.C) {
+	s.setInstanceGroupSize(c, 3)
+	units, err := s.service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	machines := make(map[string]string)
+	for _, unit := range units {
+		id, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		machines[unit.Name()] = id
+	}
+
+	s.setInstanceGroupSize(c, 0)
+	c.Assert(s.aliveUnitNames(c), gc.HasLen, 0)
+	for name, id := range machines {
+		c.Logf("unit %s, machine %s", name, id)
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(m.Life(), gc.Equals, state.Dying)
+	}
+}
+
+func (s *autoscalerSuite) TestSetInstanceGroupSizesErrors(c *gc.C) {
+	result, err := s.api.SetInstanceGroupSizes(params.InstanceGroupSizes{
+		Sizes: []params.InstanceGroupSize{
+			{ServiceTag: "service-mysql", InstanceGroup: "wordpress-group", Size: 1},
+			{ServiceTag: "service-wordpress", InstanceGroup: "other-group", Size: 1},
+			{ServiceTag: "service-wordpress", InstanceGroup: "wordpress-group", Size: -1},
+			{ServiceTag: "service-missing", InstanceGroup: "wordpress-group", Size: 1},
+			{ServiceTag: "machine-0", InstanceGroup: "wordpress-group", Size: 1},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{&params.Error{Message: `service "mysql" is not linked to instance group "wordpress-group"`}},
+			{&params.Error{Message: `service "wordpress" is not linked to instance group "other-group"`}},
+			{&params.Error{Message: `instance group size -1 not valid`}},
+			{&params.Error{Message: `service "missing" not found`, Code: params.CodeNotFound}},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	c.Assert(s.aliveUnitNames(c), gc.HasLen, 0)
+}
@@ -33,11 +33,17 @@ type backupHandler struct {
 func (h *backupHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	// Validate before authenticate because the authentication is dependent
 	// on the state connection that is determined during the validation.
-	st, _, err := h.ctxt.stateForRequestAuthenticatedUser(req)
+	st, entity, err := h.ctxt.stateForRequestAuthenticatedUser(req)
 	if err != nil {
 		h.sendError(resp, err)
 		return
 	}
+	// Backup archives hold the environment's secrets, so they may not
+	// be downloaded by users with read access.
+	if err := checkWriteAccess(entity); err != nil {
+		h.sendError(resp, err)
+		return
+	}
 
 	backups, closer := newBackups(st)
 	defer closer.Close()
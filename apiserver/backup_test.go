@@ -76,6 +76,12 @@ func (s *backupsSuite) TestRequiresAuth(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "no credentials provided")
 }
 
+func (s *backupsSuite) TestRequiresWriteAccess(c *gc.C) {
+	s.setupReadOnlyUser(c)
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.backupURL(c)})
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "permission denied")
+}
+
 func (s *backupsSuite) checkInvalidMethod(c *gc.C, method, url string) {
 	resp := s.authRequest(c, httpRequestParams{method: method, url: url})
 	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "`+method+`"`)
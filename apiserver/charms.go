@@ -7,6 +7,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -19,8 +20,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
 	ziputil "github.com/juju/utils/zip"
 	"gopkg.in/juju/charm.v6-unstable"
 
@@ -35,6 +40,75 @@ import (
 type charmsHandler struct {
 	ctxt    httpContext
 	dataDir string
+	uploads *charmUploads
+}
+
+// charmUploadExpiry holds how long a partial charm upload is kept
+// after the last chunk of it was received.
+var charmUploadExpiry = 24 * time.Hour
+
+// charmUploads records the resumable charm uploads that are in
+// progress, so that chunks of the same upload are not written
+// concurrently, and partial uploads are not expired while they
+// are being written.
+type charmUploads struct {
+	mu     sync.Mutex
+	active set.Strings
+}
+
+func newCharmUploads() *charmUploads {
+	return &charmUploads{active: set.NewStrings()}
+}
+
+// start marks the upload of the archive at the given path as in
+// progress, or returns an error if it already is.
+func (u *charmUploads) start(path string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.active.Contains(path) {
+		return errors.Errorf("upload of charm archive %q already in progress", filepath.Base(path))
+	}
+	u.active.Add(path)
+	return nil
+}
+
+// finish marks the upload of the archive at the given path as no
+// longer in progress.
+func (u *charmUploads) finish(path string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.active.Remove(path)
+}
+
+// removeExpired removes the partial uploads, for all environments,
+// under the given directory that are not in progress and have not
+// been written to for longer than charmUploadExpiry.
+func (u *charmUploads) removeExpired(uploadDir string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	paths, err := filepath.Glob(filepath.Join(uploadDir, "*", "*.zip"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	expired := time.Now().Add(-charmUploadExpiry)
+	for _, path := range paths {
+		if u.active.Contains(path) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if info.ModTime().Before(expired) {
+			logger.Debugf("removing expired charm upload %q", path)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
 }
 
 // bundleContentSenderFunc functions are responsible for sending a
@@ -61,13 +135,18 @@ func (h *charmsHandler) servePost(w http.ResponseWriter, r *http.Request) error
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// Check if changes are allowed and the command may proceed; the
+	// error is returned as is, so the client sees that it was blocked.
+	if err := common.NewBlockChecker(st).ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
 	// Add a local charm to the store provider.
 	// Requires a "series" query specifying the series to use for the charm.
-	charmURL, err := h.processPost(r, st)
+	resp, err := h.processPost(r, st)
 	if err != nil {
 		return errors.NewBadRequest(err, "")
 	}
-	sendStatusAndJSON(w, http.StatusOK, &params.CharmsResponse{CharmURL: charmURL.String()})
+	sendStatusAndJSON(w, http.StatusOK, resp)
 	return nil
 }
 
@@ -80,7 +159,7 @@ func (h *charmsHandler) serveGet(w http.ResponseWriter, r *http.Request) error {
 	// Retrieve or list charm files.
 	// Requires "url" (charm URL) and an optional "file" (the path to the
 	// charm file) to be included in the query.
-	charmArchivePath, filePath, err := h.processGet(r, st)
+	charmArchivePath, digest, filePath, err := h.processGet(r, st)
 	if err != nil {
 		// An error occurred retrieving the charm bundle.
 		if errors.IsNotFound(err) {
@@ -95,7 +174,7 @@ func (h *charmsHandler) serveGet(w http.ResponseWriter, r *http.Request) error {
 		sender = h.manifestSender
 	case "*":
 		// The client requested the archive.
-		sender = h.archiveSender
+		sender = h.archiveSender(digest)
 	default:
 		// The client requested a specific file.
 		sender = h.archiveEntrySender(filePath)
@@ -190,21 +269,36 @@ func (h *charmsHandler) archiveEntrySender(filePath string) bundleContentSenderF
 	}
 }
 
-// archiveSender is a bundleContentSenderFunc which is responsible for sending
-// the contents of the given charm bundle.
-func (h *charmsHandler) archiveSender(w http.ResponseWriter, r *http.Request, bundle *charm.CharmArchive) error {
-	// Note that http.ServeFile's error responses are not our standard JSON
-	// responses (they are the usual textual error messages as produced
-	// by http.Error), but there's not a great deal we can do about that,
-	// except accept non-JSON error responses in the client, because
-	// http.ServeFile does not provide a way of customizing its
-	// error responses.
-	http.ServeFile(w, r, bundle.Path)
-	return nil
+// archiveSender returns a bundleContentSenderFunc which is responsible for
+// sending the contents of the given charm bundle, which has the given
+// hex-encoded SHA256 digest. The digest is sent in the Digest header, and
+// range requests are honoured so that interrupted downloads may be resumed.
+func (h *charmsHandler) archiveSender(digest string) bundleContentSenderFunc {
+	return func(w http.ResponseWriter, r *http.Request, bundle *charm.CharmArchive) error {
+		sum, err := hex.DecodeString(digest)
+		if err != nil {
+			return errors.Annotatef(err, "invalid SHA256 digest for archive in %q", bundle.Path)
+		}
+		w.Header().Set("Digest", fmt.Sprintf("%s=%s", params.DigestSHA256, base64.StdEncoding.EncodeToString(sum)))
+		// Note that http.ServeFile's error responses are not our standard JSON
+		// responses (they are the usual textual error messages as produced
+		// by http.Error), but there's not a great deal we can do about that,
+		// except accept non-JSON error responses in the client, because
+		// http.ServeFile does not provide a way of customizing its
+		// error responses.
+		http.ServeFile(w, r, bundle.Path)
+		return nil
+	}
 }
 
 // processPost handles a charm upload POST request after authentication.
-func (h *charmsHandler) processPost(r *http.Request, st *state.State) (*charm.URL, error) {
+//
+// If the request has a Digest header, the uploaded archive must match
+// it. If the request also has a Content-Range header, the body holds
+// one chunk of a resumable upload identified by that digest, and the
+// charm is only added once the whole archive has been received; until
+// then, the response reports how many bytes have been received.
+func (h *charmsHandler) processPost(r *http.Request, st *state.State) (*params.CharmsResponse, error) {
 	query := r.URL.Query()
 	series := query.Get("series")
 	if series == "" {
@@ -215,20 +309,62 @@ func (h *charmsHandler) processPost(r *http.Request, st *state.State) (*charm.UR
 	if contentType != "application/zip" {
 		return nil, fmt.Errorf("expected Content-Type: application/zip, got: %v", contentType)
 	}
-	tempFile, err := ioutil.TempFile("", "charm")
+	digest, err := requestDigest(r)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create temp file: %v", err)
+		return nil, err
 	}
-	defer tempFile.Close()
-	defer os.Remove(tempFile.Name())
-	if _, err := io.Copy(tempFile, r.Body); err != nil {
-		return nil, fmt.Errorf("error processing file upload: %v", err)
+	var archivePath string
+	if contentRange := r.Header.Get("Content-Range"); contentRange != "" {
+		if digest == "" {
+			return nil, fmt.Errorf("expected Digest header with Content-Range")
+		}
+		uploadDir := filepath.Join(h.dataDir, "charm-uploads")
+		if err := h.uploads.removeExpired(uploadDir); err != nil {
+			logger.Warningf("cannot remove expired charm uploads: %v", err)
+		}
+		// Partial uploads are kept apart for each environment, so
+		// that one environment cannot add to, or finish, an upload
+		// begun in another.
+		path := filepath.Join(uploadDir, st.EnvironUUID(), digest+".zip")
+		if err := h.uploads.start(path); err != nil {
+			return nil, err
+		}
+		defer h.uploads.finish(path)
+		received, complete, err := receiveChunk(r.Body, contentRange, path)
+		if err != nil {
+			return nil, err
+		}
+		if !complete {
+			return &params.CharmsResponse{Received: received}, nil
+		}
+		defer os.Remove(path)
+		archivePath = path
+	} else {
+		tempFile, err := ioutil.TempFile("", "charm")
+		if err != nil {
+			return nil, fmt.Errorf("cannot create temp file: %v", err)
+		}
+		defer tempFile.Close()
+		defer os.Remove(tempFile.Name())
+		if _, err := io.Copy(tempFile, r.Body); err != nil {
+			return nil, fmt.Errorf("error processing file upload: %v", err)
+		}
+		archivePath = tempFile.Name()
+	}
+	if digest != "" {
+		actual, _, err := utils.ReadFileSHA256(archivePath)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read uploaded archive")
+		}
+		if actual != digest {
+			return nil, fmt.Errorf("checksum mismatch: expected %s, got %s", digest, actual)
+		}
 	}
-	err = h.processUploadedArchive(tempFile.Name())
+	err = h.processUploadedArchive(archivePath)
 	if err != nil {
 		return nil, err
 	}
-	archive, err := charm.ReadCharmArchive(tempFile.Name())
+	archive, err := charm.ReadCharmArchive(archivePath)
 	if err != nil {
 		return nil, fmt.Errorf("invalid charm archive: %v", err)
 	}
@@ -250,7 +386,105 @@ func (h *charmsHandler) processPost(r *http.Request, st *state.State) (*charm.UR
 		return nil, err
 	}
 	// All done.
-	return preparedURL, nil
+	return &params.CharmsResponse{CharmURL: preparedURL.String()}, nil
+}
+
+// requestDigest returns the SHA-256 digest held in the Digest header
+// of the given request, hex-encoded, or "" if there is none. The header
+// holds the digest base64-encoded, as specified by RFC 5843.
+func requestDigest(r *http.Request) (string, error) {
+	header := r.Header.Get("Digest")
+	if header == "" {
+		return "", nil
+	}
+	prefix := string(params.DigestSHA256) + "="
+	if !strings.HasPrefix(header, prefix) {
+		return "", fmt.Errorf("unsupported digest %q", header)
+	}
+	digest := strings.TrimPrefix(header, prefix)
+	sum, err := base64.StdEncoding.DecodeString(digest)
+	if err != nil || len(sum) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 digest %q", digest)
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// receiveChunk appends the body of a resumable upload request, holding
+// the chunk described by contentRange, to the partial archive at the
+// given path. It returns the number of bytes received so far and
+// whether the whole archive has been received. A range of "*" sends
+// no data, and only reports on the upload.
+func receiveChunk(body io.Reader, contentRange, path string) (int64, bool, error) {
+	start, end, total, err := parseContentRange(contentRange)
+	if err != nil {
+		return 0, false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, false, errors.Annotate(err, "cannot create the charm uploads directory")
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, false, errors.Annotate(err, "cannot open partial upload")
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, false, errors.Annotate(err, "cannot open partial upload")
+	}
+	received := info.Size()
+	if start >= 0 {
+		if start != received {
+			return 0, false, fmt.Errorf("chunk starts at byte %d, but %d bytes have been received", start, received)
+		}
+		size := end - start + 1
+		n, err := io.Copy(f, io.LimitReader(body, size))
+		if err != nil {
+			return 0, false, fmt.Errorf("error processing file upload: %v", err)
+		}
+		if n != size {
+			return 0, false, fmt.Errorf("expected %d bytes in chunk, got %d", size, n)
+		}
+		received += n
+	}
+	if received > total {
+		os.Remove(path)
+		return 0, false, fmt.Errorf("received %d bytes of a %d byte archive", received, total)
+	}
+	return received, received == total, nil
+}
+
+// parseContentRange parses a Content-Range header value of the form
+// "bytes START-END/TOTAL", or "bytes */TOTAL", in which case start and
+// end are returned as -1.
+func parseContentRange(value string) (start, end, total int64, err error) {
+	invalid := fmt.Errorf("invalid Content-Range %q", value)
+	if !strings.HasPrefix(value, "bytes ") {
+		return 0, 0, 0, invalid
+	}
+	parts := strings.Split(strings.TrimPrefix(value, "bytes "), "/")
+	if len(parts) != 2 {
+		return 0, 0, 0, invalid
+	}
+	total, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || total <= 0 {
+		return 0, 0, 0, invalid
+	}
+	if parts[0] == "*" {
+		return -1, -1, total, nil
+	}
+	bounds := strings.Split(parts[0], "-")
+	if len(bounds) != 2 {
+		return 0, 0, 0, invalid
+	}
+	start, err = strconv.ParseInt(bounds[0], 10, 64)
+	if err != nil {
+		return 0, 0, 0, invalid
+	}
+	end, err = strconv.ParseInt(bounds[1], 10, 64)
+	if err != nil || start < 0 || end < start || end >= total {
+		return 0, 0, 0, invalid
+	}
+	return start, end, total, nil
 }
 
 // processUploadedArchive opens the given charm archive from path,
@@ -383,18 +617,19 @@ func (h *charmsHandler) repackageAndUploadCharm(st *state.State, archive *charm.
 }
 
 // processGet handles a charm file GET request after authentication.
-// It returns the bundle path, the requested file path (if any) and an error.
-func (h *charmsHandler) processGet(r *http.Request, st *state.State) (string, string, error) {
+// It returns the bundle path, the hex-encoded SHA256 digest of the bundle,
+// the requested file path (if any) and an error.
+func (h *charmsHandler) processGet(r *http.Request, st *state.State) (string, string, string, error) {
 	query := r.URL.Query()
 
 	// Retrieve and validate query parameters.
 	curlString := query.Get("url")
 	if curlString == "" {
-		return "", "", fmt.Errorf("expected url=CharmURL query argument")
+		return "", "", "", fmt.Errorf("expected url=CharmURL query argument")
 	}
 	curl, err := charm.ParseURL(curlString)
 	if err != nil {
-		return "", "", errors.Annotate(err, "cannot parse charm URL")
+		return "", "", "", errors.Annotate(err, "cannot parse charm URL")
 	}
 
 	var filePath string
@@ -405,6 +640,11 @@ func (h *charmsHandler) processGet(r *http.Request, st *state.State) (string, st
 		filePath = path.Clean(file)
 	}
 
+	ch, err := st.Charm(curl)
+	if err != nil {
+		return "", "", "", errors.Annotate(err, "cannot get charm from state")
+	}
+
 	// Prepare the bundle directories.
 	name := charm.Quote(curlString)
 	charmArchivePath := filepath.Join(h.dataDir, "charm-get-cache", name+".zip")
@@ -412,30 +652,26 @@ func (h *charmsHandler) processGet(r *http.Request, st *state.State) (string, st
 	// Check if the charm archive is already in the cache.
 	if _, err := os.Stat(charmArchivePath); os.IsNotExist(err) {
 		// Download the charm archive and save it to the cache.
-		if err = h.downloadCharm(st, curl, charmArchivePath); err != nil {
-			return "", "", errors.Annotate(err, "unable to retrieve and save the charm")
+		if err = h.downloadCharm(st, ch, charmArchivePath); err != nil {
+			return "", "", "", errors.Annotate(err, "unable to retrieve and save the charm")
 		}
 	} else if err != nil {
-		return "", "", errors.Annotate(err, "cannot access the charms cache")
+		return "", "", "", errors.Annotate(err, "cannot access the charms cache")
 	}
-	return charmArchivePath, filePath, nil
+	return charmArchivePath, ch.BundleSha256(), filePath, nil
 }
 
-// downloadCharm downloads the given charm name from the provider storage and
+// downloadCharm downloads the given charm from the environment storage and
 // saves the corresponding zip archive to the given charmArchivePath.
-func (h *charmsHandler) downloadCharm(st *state.State, curl *charm.URL, charmArchivePath string) error {
+func (h *charmsHandler) downloadCharm(st *state.State, ch *state.Charm, charmArchivePath string) error {
 	storage := storage.NewStorage(st.EnvironUUID(), st.MongoSession())
-	ch, err := st.Charm(curl)
-	if err != nil {
-		return errors.Annotate(err, "cannot get charm from state")
-	}
 
 	// In order to avoid races, the archive is saved in a temporary file which
 	// is then atomically renamed. The temporary file is created in the
 	// charm cache directory so that we can safely assume the rename source and
 	// target live in the same file system.
 	cacheDir := filepath.Dir(charmArchivePath)
-	if err = os.MkdirAll(cacheDir, 0755); err != nil {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return errors.Annotate(err, "cannot create the charms cache")
 	}
 	tempCharmArchive, err := ioutil.TempFile(cacheDir, "charm")
@@ -452,10 +688,15 @@ func (h *charmsHandler) downloadCharm(st *state.State, curl *charm.URL, charmArc
 	}
 	defer reader.Close()
 
-	if _, err = io.Copy(tempCharmArchive, reader); err != nil {
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tempCharmArchive, hash), reader); err != nil {
 		defer cleanupFile(tempCharmArchive)
 		return errors.Annotate(err, "error processing charm archive download")
 	}
+	if digest := hex.EncodeToString(hash.Sum(nil)); digest != ch.BundleSha256() {
+		defer cleanupFile(tempCharmArchive)
+		return errors.Errorf("charm archive checksum mismatch: expected %s, got %s", ch.BundleSha256(), digest)
+	}
 	tempCharmArchive.Close()
 	if err = os.Rename(tempCharmArchive.Name(), charmArchivePath); err != nil {
 		defer cleanupFile(tempCharmArchive)
@@ -5,6 +5,8 @@ package apiserver_test
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,6 +15,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -21,6 +25,7 @@ import (
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
 
+	commontesting "github.com/juju/juju/apiserver/common/testing"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/storage"
@@ -84,10 +89,17 @@ func (s *charmsCommonSuite) assertResponse(c *gc.C, resp *http.Response, expStat
 
 type charmsSuite struct {
 	charmsCommonSuite
+	commontesting.BlockHelper
 }
 
 var _ = gc.Suite(&charmsSuite{})
 
+func (s *charmsSuite) SetUpTest(c *gc.C) {
+	s.charmsCommonSuite.SetUpTest(c)
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+}
+
 func (s *charmsSuite) SetUpSuite(c *gc.C) {
 	// TODO(bogdanteleaga): Fix this on windows
 	if runtime.GOOS == "windows" {
@@ -116,6 +128,12 @@ func (s *charmsSuite) TestGETDoesNotRequireAuth(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected url=CharmURL query argument")
 }
 
+func (s *charmsSuite) TestPOSTRequiresWriteAccess(c *gc.C) {
+	s.setupReadOnlyUser(c)
+	resp := s.authRequest(c, httpRequestParams{method: "POST", url: s.charmsURI(c, "")})
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "permission denied")
+}
+
 func (s *charmsSuite) TestRequiresPOSTorGET(c *gc.C) {
 	resp := s.authRequest(c, httpRequestParams{method: "PUT", url: s.charmsURI(c, "")})
 	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "PUT"`)
@@ -190,6 +208,22 @@ func (s *charmsSuite) TestUploadBumpsRevision(c *gc.C) {
 	c.Assert(sch.BundleSha256(), gc.Not(gc.Equals), "")
 }
 
+func (s *charmsSuite) TestBlockUpload(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	s.BlockAllChanges(c, "TestBlockUpload")
+	resp := s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), "application/zip", ch.Path)
+	charmResponse := s.assertResponse(c, resp, http.StatusBadRequest)
+	s.AssertBlocked(c, &params.Error{
+		Message: charmResponse.Error,
+		Code:    charmResponse.ErrorCode,
+	}, "TestBlockUpload")
+
+	// Check the charm was not added.
+	curl := charm.MustParseURL(fmt.Sprintf("local:quantal/%s-%d", ch.Meta().Name, ch.Revision()))
+	_, err := s.State.Charm(curl)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *charmsSuite) TestUploadRespectsLocalRevision(c *gc.C) {
 	// Make a dummy charm dir with revision 123.
 	dir := testcharms.Repo.ClonedDir(c.MkDir(), "dummy")
@@ -325,6 +359,162 @@ func (s *charmsSuite) TestUploadRepackagesNestedArchives(c *gc.C) {
 	c.Assert(bundle.Config(), jc.DeepEquals, sch.Config())
 }
 
+func (s *charmsSuite) TestUploadChecksDigest(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	digest, _, err := utils.ReadFileSHA256(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.uploadChunk(c, digestHeader(c, strings.Repeat("0", 64)), "", data)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "checksum mismatch: expected 0+, got "+digest)
+	resp = s.uploadChunk(c, "MD5=1234", "", data)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, `unsupported digest "MD5=1234"`)
+	resp = s.uploadChunk(c, digestHeader(c, digest), "", data)
+	s.assertUploadResponse(c, resp, "local:quantal/dummy-1")
+}
+
+func (s *charmsSuite) TestUploadInChunks(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	digest, size, err := utils.ReadFileSHA256(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	half := size / 2
+
+	resp := s.uploadChunk(c, digestHeader(c, digest), fmt.Sprintf("bytes */%d", size), nil)
+	c.Assert(s.assertResponse(c, resp, http.StatusOK).Received, gc.Equals, int64(0))
+	resp = s.uploadChunk(c, digestHeader(c, digest), fmt.Sprintf("bytes 0-%d/%d", half-1, size), data[:half])
+	c.Assert(s.assertResponse(c, resp, http.StatusOK).Received, gc.Equals, half)
+
+	// Chunks must follow on from what has already been received.
+	resp = s.uploadChunk(c, digestHeader(c, digest), fmt.Sprintf("bytes 0-%d/%d", size-1, size), data)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest,
+		fmt.Sprintf("chunk starts at byte 0, but %d bytes have been received", half))
+	resp = s.uploadChunk(c, digestHeader(c, digest), fmt.Sprintf("bytes */%d", size), nil)
+	c.Assert(s.assertResponse(c, resp, http.StatusOK).Received, gc.Equals, half)
+
+	resp = s.uploadChunk(c, digestHeader(c, digest), fmt.Sprintf("bytes %d-%d/%d", half, size-1, size), data[half:])
+	s.assertUploadResponse(c, resp, "local:quantal/dummy-1")
+	_, err = s.State.Charm(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The partial upload is removed once the charm has been added.
+	_, err = os.Stat(filepath.Join(s.DataDir(), "charm-uploads", s.State.EnvironUUID(), digest+".zip"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *charmsSuite) TestUploadInChunksChecksDigest(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	data, err := ioutil.ReadFile(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	digest := strings.Repeat("0", 64)
+
+	contentRange := fmt.Sprintf("bytes 0-%d/%d", len(data)-1, len(data))
+	resp := s.uploadChunk(c, digestHeader(c, digest), contentRange, data)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "checksum mismatch: .*")
+	_, err = os.Stat(filepath.Join(s.DataDir(), "charm-uploads", s.State.EnvironUUID(), digest+".zip"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *charmsSuite) TestUploadInvalidDigest(c *gc.C) {
+	digest := strings.Repeat("0", 64)
+	resp := s.uploadChunk(c, "SHA="+digest, "", nil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, `unsupported digest "SHA=0+"`)
+	resp = s.uploadChunk(c, "SHA-256="+digest, "", nil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, `invalid SHA-256 digest "0+"`)
+}
+
+func (s *charmsSuite) TestUploadInChunksSeparatesEnvironments(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	digest, size, err := utils.ReadFileSHA256(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A partial upload of the same archive to another environment
+	// is not picked up.
+	otherDir := filepath.Join(s.DataDir(), "charm-uploads", "other-env-uuid")
+	err = os.MkdirAll(otherDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(otherDir, digest+".zip"), data[:size/2], 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.uploadChunk(c, digestHeader(c, digest), fmt.Sprintf("bytes */%d", size), nil)
+	c.Assert(s.assertResponse(c, resp, http.StatusOK).Received, gc.Equals, int64(0))
+}
+
+func (s *charmsSuite) TestUploadInChunksRemovesExpiredUploads(c *gc.C) {
+	uploadDir := filepath.Join(s.DataDir(), "charm-uploads", "other-env-uuid")
+	err := os.MkdirAll(uploadDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	expired := filepath.Join(uploadDir, strings.Repeat("1", 64)+".zip")
+	err = ioutil.WriteFile(expired, []byte("expired"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	old := time.Now().Add(-48 * time.Hour)
+	err = os.Chtimes(expired, old, old)
+	c.Assert(err, jc.ErrorIsNil)
+	recent := filepath.Join(uploadDir, strings.Repeat("2", 64)+".zip")
+	err = ioutil.WriteFile(recent, []byte("recent"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.uploadChunk(c, digestHeader(c, strings.Repeat("0", 64)), "bytes */10", nil)
+	s.assertResponse(c, resp, http.StatusOK)
+	_, err = os.Stat(expired)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	_, err = os.Stat(recent)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *charmsSuite) TestUploadInChunksRequiresDigest(c *gc.C) {
+	resp := s.uploadChunk(c, "", "bytes */10", nil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected Digest header with Content-Range")
+}
+
+func (s *charmsSuite) TestUploadInvalidContentRange(c *gc.C) {
+	digest := strings.Repeat("0", 64)
+	for _, contentRange := range []string{
+		"10-20/30",
+		"bytes 10-20",
+		"bytes 20-10/30",
+		"bytes 10-30/30",
+		"bytes */0",
+		"bytes a-b/c",
+	} {
+		c.Logf("Content-Range: %s", contentRange)
+		resp := s.uploadChunk(c, digestHeader(c, digest), contentRange, nil)
+		s.assertErrorResponse(c, resp, http.StatusBadRequest, `invalid Content-Range ".*"`)
+	}
+}
+
+// digestHeader returns the Digest header value for the given
+// hex-encoded SHA256 digest.
+func digestHeader(c *gc.C, digest string) string {
+	sum, err := hex.DecodeString(digest)
+	c.Assert(err, jc.ErrorIsNil)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum)
+}
+
+// uploadChunk uploads the given data as a charm archive for quantal,
+// sending the given Digest and Content-Range headers if they are not
+// empty.
+func (s *charmsSuite) uploadChunk(c *gc.C, digest, contentRange string, data []byte) *http.Response {
+	header := make(http.Header)
+	if digest != "" {
+		header.Set("Digest", digest)
+	}
+	if contentRange != "" {
+		header.Set("Content-Range", contentRange)
+	}
+	return s.authRequest(c, httpRequestParams{
+		method:      "POST",
+		url:         s.charmsURI(c, "?series=quantal"),
+		contentType: "application/zip",
+		body:        bytes.NewReader(data),
+		header:      header,
+	})
+}
+
 func (s *charmsSuite) TestGetRequiresCharmURL(c *gc.C) {
 	uri := s.charmsURI(c, "?file=hooks/install")
 	resp := s.authRequest(c, httpRequestParams{method: "GET", url: uri})
@@ -339,7 +529,7 @@ func (s *charmsSuite) TestGetFailsWithInvalidCharmURL(c *gc.C) {
 	resp := s.authRequest(c, httpRequestParams{method: "GET", url: uri})
 	s.assertErrorResponse(
 		c, resp, http.StatusNotFound,
-		`unable to retrieve and save the charm: cannot get charm from state: charm "local:precise/no-such" not found`,
+		`cannot get charm from state: charm "local:precise/no-such" not found`,
 	)
 }
 
@@ -412,6 +602,42 @@ func (s *charmsSuite) TestGetStarReturnsArchiveBytes(c *gc.C) {
 	uri := s.charmsURI(c, "?url=local:quantal/dummy-1&file=*")
 	resp := s.authRequest(c, httpRequestParams{method: "GET", url: uri})
 	s.assertGetFileResponse(c, resp, string(data), "application/zip")
+	digest, _, err := utils.ReadFileSHA256(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Header.Get("Digest"), gc.Equals, digestHeader(c, digest))
+}
+
+func (s *charmsSuite) TestGetStarReturnsArchiveRange(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), "application/zip", ch.Path)
+	data, err := ioutil.ReadFile(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	uri := s.charmsURI(c, "?url=local:quantal/dummy-1&file=*")
+	resp := s.authRequest(c, httpRequestParams{
+		method: "GET",
+		url:    uri,
+		header: http.Header{"Range": {"bytes=10-"}},
+	})
+	body := assertResponse(c, resp, http.StatusPartialContent, "application/zip")
+	c.Assert(string(body), gc.Equals, string(data[10:]))
+}
+
+func (s *charmsSuite) TestGetChecksDownloadedArchive(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	curl := charm.MustParseURL("local:quantal/dummy-1")
+	data, err := ioutil.ReadFile(ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	storage := storage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+	err = storage.Put("charm-path", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddCharm(ch, curl, "charm-path", "bad-sha256")
+	c.Assert(err, jc.ErrorIsNil)
+
+	uri := s.charmsURI(c, "?url=local:quantal/dummy-1&file=*")
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: uri})
+	s.assertErrorResponse(c, resp, http.StatusBadRequest,
+		"unable to retrieve and save the charm: charm archive checksum mismatch: expected bad-sha256, got .*")
 }
 
 func (s *charmsSuite) TestGetAllowsTopLevelPath(c *gc.C) {
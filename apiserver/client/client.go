@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/highavailability"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/service"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/instance"
@@ -29,6 +30,10 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 0, NewClient)
+	common.RegisterStandardFacade("Client", 1, NewClientV1)
+	common.RegisterStandardFacade("Client", 2, NewClientV2)
+	common.RegisterStandardFacade("Client", 3, NewClientV3)
+	common.RegisterStandardFacade("Client", 4, NewClientV4)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.client")
@@ -79,6 +84,67 @@ func NewClient(st *state.State, resources *common.Resources, authorizer common.A
 		check: common.NewBlockChecker(st)}, nil
 }
 
+// ClientV1 serves version 1 of the Client facade, which adds
+// MachineStatusHistory.
+type ClientV1 struct {
+	*Client
+}
+
+// NewClientV1 creates a new instance of version 1 of the Client facade.
+func NewClientV1(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ClientV1, error) {
+	client, err := NewClient(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientV1{client}, nil
+}
+
+// ClientV2 serves version 2 of the Client facade, whose
+// RetryProvisioning can clean up the failed instances of provisioned
+// machines.
+type ClientV2 struct {
+	*ClientV1
+}
+
+// NewClientV2 creates a new instance of version 2 of the Client facade.
+func NewClientV2(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ClientV2, error) {
+	client, err := NewClientV1(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientV2{client}, nil
+}
+
+// ClientV3 serves version 3 of the Client facade, whose Run can run
+// commands on units in the context of one of their relations.
+type ClientV3 struct {
+	*ClientV2
+}
+
+// NewClientV3 creates a new instance of version 3 of the Client facade.
+func NewClientV3(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ClientV3, error) {
+	client, err := NewClientV2(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientV3{client}, nil
+}
+
+// ClientV4 serves version 4 of the Client facade, which adds
+// ServiceSetUpgradeStrategy.
+type ClientV4 struct {
+	*ClientV3
+}
+
+// NewClientV4 creates a new instance of version 4 of the Client facade.
+func NewClientV4(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ClientV4, error) {
+	client, err := NewClientV3(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientV4{client}, nil
+}
+
 func (c *Client) WatchAll() (params.AllWatcherId, error) {
 	w := c.api.stateAccessor.Watch()
 	return params.AllWatcherId{
@@ -179,6 +245,25 @@ func (c *Client) Resolved(p params.Resolved) error {
 	return unit.Resolve(p.Retry)
 }
 
+// ResolveUnits marks the errors of each of the given units as resolved,
+// reporting the result for each unit separately.
+func (c *Client) ResolveUnits(args params.ResolveUnits) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Units)),
+	}
+	if err := c.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, p := range args.Units {
+		unit, err := c.api.stateAccessor.Unit(p.UnitName)
+		if err == nil {
+			err = unit.Resolve(p.Retry)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
 // PublicAddress implements the server side of Client.PublicAddress.
 func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAddressResults, err error) {
 	switch {
@@ -207,6 +292,98 @@ func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAdd
 	return results, errors.Errorf("unknown unit or machine %q", p.Target)
 }
 
+// SSHHostKeys implements the server side of Client.SSHHostKeys. The
+// target may be a machine or a unit; for a unit, the keys of its
+// assigned machine are returned.
+func (c *Client) SSHHostKeys(p params.SSHHostKeysTarget) (results params.SSHHostKeysResults, err error) {
+	var machineId string
+	switch {
+	case names.IsValidMachine(p.Target):
+		machineId = p.Target
+	case names.IsValidUnit(p.Target):
+		unit, err := c.api.stateAccessor.Unit(p.Target)
+		if err != nil {
+			return results, err
+		}
+		machineId, err = unit.AssignedMachineId()
+		if err != nil {
+			return results, errors.Annotatef(err, "error fetching machine for unit %q", p.Target)
+		}
+	default:
+		return results, errors.Errorf("unknown unit or machine %q", p.Target)
+	}
+	keys, err := c.api.stateAccessor.GetSSHHostKeys(names.NewMachineTag(machineId))
+	if err != nil {
+		return results, err
+	}
+	return params.SSHHostKeysResults{PublicKeys: keys}, nil
+}
+
+// ProblemReports returns the problem reports uploaded by agents in the
+// environment, newest first.
+func (c *Client) ProblemReports() (params.ProblemReports, error) {
+	reports, err := c.api.stateAccessor.ProblemReports()
+	if err != nil {
+		return params.ProblemReports{}, err
+	}
+	results := params.ProblemReports{
+		Reports: make([]params.ProblemReport, len(reports)),
+	}
+	for i, report := range reports {
+		results.Reports[i] = params.ProblemReport{
+			Id:      report.Id,
+			Tag:     report.Entity.String(),
+			Kind:    string(report.Kind),
+			Source:  report.Source,
+			Message: report.Message,
+			Stack:   report.Stack,
+			Logs:    report.Logs,
+			State:   report.State,
+			Time:    report.Time,
+		}
+	}
+	return results, nil
+}
+
+// AuditLog returns the entries in the environment's audit log that
+// match the given filter, newest first.
+func (c *Client) AuditLog(args params.AuditLogFilter) (params.AuditLogResults, error) {
+	if args.Limit < 0 {
+		return params.AuditLogResults{}, errors.Errorf("invalid limit: %d", args.Limit)
+	}
+	filter := state.AuditFilter{
+		Entity:        args.Entity,
+		Facade:        args.Facade,
+		CorrelationId: args.CorrelationId,
+		Limit:         args.Limit,
+	}
+	if args.Since != nil {
+		filter.Since = *args.Since
+	}
+	entries, err := c.api.stateAccessor.AuditEntries(filter)
+	if err != nil {
+		return params.AuditLogResults{}, errors.Trace(err)
+	}
+	results := params.AuditLogResults{
+		Entries: make([]params.AuditLogEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		results.Entries[i] = params.AuditLogEntry{
+			Time:      entry.Time,
+			Entity:    entry.Entity,
+			Facade:    entry.Facade,
+			Version:   entry.Version,
+			Method:    entry.Method,
+			Args:      entry.Args,
+			Error:     entry.Error,
+			ErrorCode: entry.ErrorCode,
+
+			CorrelationId: entry.CorrelationId,
+		}
+	}
+	return results, nil
+}
+
 // PrivateAddress implements the server side of Client.PrivateAddress.
 func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.PrivateAddressResults, err error) {
 	switch {
@@ -419,6 +596,35 @@ func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
 	return c.serviceSetCharm(service, args.CharmUrl, args.Force)
 }
 
+// ServiceSetUpgradeStrategy sets the way in which charm upgrades are
+// rolled out to the units of a service.
+func (c *ClientV4) ServiceSetUpgradeStrategy(args params.ServiceSetUpgradeStrategy) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.stateAccessor.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return service.SetUpgradeStrategy(state.UpgradeStrategy{
+		Mode:           state.UpgradeStrategyMode(args.Strategy),
+		MaxUnavailable: args.MaxUnavailable,
+	})
+}
+
+// ServiceSetAddressPolicy sets the policy used to select the public and
+// private addresses of the units of a service.
+func (c *Client) ServiceSetAddressPolicy(args params.ServiceSetAddressPolicy) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.stateAccessor.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return service.SetAddressPolicy(network.AddressPolicy(args.Policy))
+}
+
 // addServiceUnits adds a given number of units to a service.
 func addServiceUnits(st *state.State, args params.AddServiceUnits) ([]*state.Unit, error) {
 	service, err := st.Service(args.ServiceName)
@@ -746,6 +952,32 @@ func (c *Client) DestroyMachines(args params.DestroyMachines) error {
 	return destroyErr("machines", args.MachineNames, errs)
 }
 
+// DestroyMachinesBulk is like DestroyMachines, except that it reports
+// the result of destroying each machine separately.
+func (c *Client) DestroyMachinesBulk(args params.DestroyMachines) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.MachineNames)),
+	}
+	if !args.Force {
+		if err := c.check.RemoveAllowed(); err != nil {
+			return results, errors.Trace(err)
+		}
+	}
+	for i, id := range args.MachineNames {
+		machine, err := c.api.stateAccessor.Machine(id)
+		switch {
+		case err != nil:
+		case args.Force:
+			err = machine.ForceDestroy()
+		case machine.Life() != state.Alive:
+		default:
+			err = machine.Destroy()
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
 // CharmInfo returns information about the requested charm.
 func (c *Client) CharmInfo(args params.CharmInfo) (api.CharmInfo, error) {
 	curl, err := charm.ParseURL(args.CharmURL)
@@ -796,6 +1028,9 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	if createdBy, ok = c.api.auth.GetAuthTag().(names.UserTag); !ok {
 		return result, errors.Errorf("api connection is not through a user")
 	}
+	if err := c.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
 
 	result = params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Changes)),
@@ -813,11 +1048,21 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 		}
 		switch arg.Action {
 		case params.AddEnvUser:
-			_, err := c.api.stateAccessor.AddEnvironmentUser(user, createdBy, "")
+			access := state.EnvUserAccess(arg.Access)
+			if access == "" {
+				access = state.EnvAdminAccess
+			}
+			_, err := c.api.stateAccessor.AddEnvironmentUserWithAccess(user, createdBy, "", access)
 			if err != nil {
 				err = errors.Annotate(err, "could not share environment")
 				result.Results[i].Error = common.ServerError(err)
 			}
+		case params.SetEnvUserAccess:
+			err := c.setEnvUserAccess(user, state.EnvUserAccess(arg.Access))
+			if err != nil {
+				err = errors.Annotate(err, "could not set environment access")
+				result.Results[i].Error = common.ServerError(err)
+			}
 		case params.RemoveEnvUser:
 			err := c.api.stateAccessor.RemoveEnvironmentUser(user)
 			if err != nil {
@@ -831,6 +1076,14 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	return result, nil
 }
 
+func (c *Client) setEnvUserAccess(user names.UserTag, access state.EnvUserAccess) error {
+	envUser, err := c.api.stateAccessor.EnvironmentUser(user)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return envUser.SetAccess(access)
+}
+
 // EnvUserInfo returns information on all users in the environment.
 func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 	var results params.EnvUserInfoResults
@@ -860,6 +1113,7 @@ func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 				CreatedBy:      user.CreatedBy(),
 				DateCreated:    user.DateCreated(),
 				LastConnection: lastConn,
+				Access:         string(user.Access()),
 			},
 		})
 	}
@@ -1035,17 +1289,132 @@ func (c *Client) ResolveCharms(args params.ResolveCharms) (params.ResolveCharmRe
 }
 
 // RetryProvisioning marks a provisioning error as transient on the machines.
+// Machines that already have an instance are not retried by the
+// provisioner, so an error is returned for them instead.
 func (c *Client) RetryProvisioning(p params.Entities) (params.ErrorResults, error) {
+	return c.retryProvisioning(p.Entities, false)
+}
+
+// RetryProvisioning marks a provisioning error as transient on the
+// machines. Without Force, an error is returned for machines that
+// already have an instance, since the provisioner will not retry them;
+// with it, the instances of such machines are stopped and forgotten
+// first, provided their machine agents have not started.
+func (c *ClientV2) RetryProvisioning(args params.RetryProvisioning) (params.ErrorResults, error) {
+	return c.retryProvisioning(args.Entities, args.Force)
+}
+
+func (c *Client) retryProvisioning(entities []params.Entity, force bool) (params.ErrorResults, error) {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
-	entityStatus := make([]params.EntityStatusArgs, len(p.Entities))
-	for i, entity := range p.Entities {
-		entityStatus[i] = params.EntityStatusArgs{Tag: entity.Tag, Data: map[string]interface{}{"transient": true}}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(entities)),
+	}
+	var entityStatus []params.EntityStatusArgs
+	var indexes []int
+	for i, entity := range entities {
+		if force {
+			cleaned, err := c.cleanUpFailedInstance(entity.Tag)
+			if err != nil || cleaned {
+				results.Results[i].Error = common.ServerError(err)
+				continue
+			}
+		} else if err := c.checkNotProvisioned(entity.Tag); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		entityStatus = append(entityStatus, params.EntityStatusArgs{
+			Tag:  entity.Tag,
+			Data: map[string]interface{}{"transient": true},
+		})
+		indexes = append(indexes, i)
 	}
-	return c.api.statusSetter.UpdateStatus(params.SetStatus{
+	updated, err := c.api.statusSetter.UpdateStatus(params.SetStatus{
 		Entities: entityStatus,
 	})
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	for i, result := range updated.Results {
+		results.Results[indexes[i]] = result
+	}
+	return results, nil
+}
+
+// checkNotProvisioned returns an error if the entity with the given
+// tag is a machine that has been provisioned. Any other problem with
+// the entity is left for UpdateStatus to report.
+func (c *Client) checkNotProvisioned(tag string) error {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return nil
+	}
+	machine, err := c.api.stateAccessor.Machine(machineTag.Id())
+	if err != nil {
+		return nil
+	}
+	instId, err := machine.InstanceId()
+	if err == nil {
+		return errors.Errorf("machine %s is already provisioned as instance %q", machineTag.Id(), instId)
+	} else if !errors.IsNotProvisioned(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// cleanUpFailedInstance stops the instance of the machine with the
+// given tag, if it has one, and removes the record of it from state.
+// The machine is then put in a transient error state, so that the
+// provisioner starts a new instance for it, and true is returned.
+// Machines whose agents have started are left alone, since their
+// instances have not failed in a way that reprovisioning would fix.
+// As with checkNotProvisioned, other problems are left for
+// UpdateStatus to report.
+func (c *Client) cleanUpFailedInstance(tag string) (bool, error) {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return false, nil
+	}
+	machine, err := c.api.stateAccessor.Machine(machineTag.Id())
+	if err != nil {
+		return false, nil
+	}
+	instId, err := machine.InstanceId()
+	if errors.IsNotProvisioned(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	statusInfo, err := machine.Status()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	switch statusInfo.Status {
+	case state.StatusPending, state.StatusError:
+	default:
+		return false, errors.Errorf("machine %s has started on instance %q; remove it instead", machineTag.Id(), instId)
+	}
+	envConfig, err := c.api.stateAccessor.EnvironConfig()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	env, err := environs.New(envConfig)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if err := env.StopInstances(instId); err != nil {
+		return false, errors.Annotatef(err, "cannot stop instance %q", instId)
+	}
+	if err := machine.ClearProvisioned(instId); err != nil {
+		return false, errors.Trace(err)
+	}
+	message := fmt.Sprintf("instance %q stopped for reprovisioning", instId)
+	transient := map[string]interface{}{"transient": true}
+	if err := machine.SetStatus(state.StatusError, message, transient); err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
 }
 
 // APIHostPorts returns the API host/port addresses stored in state.
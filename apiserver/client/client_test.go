@@ -123,6 +123,16 @@ func (s *serverSuite) TestBlockEnsureAvailabilityDeprecated(c *gc.C) {
 	c.Assert(machines, gc.HasLen, 1)
 }
 
+func (s *serverSuite) TestRunInRelationContextNeedsV3(c *gc.C) {
+	_, err := s.client.Run(params.RunParams{
+		Commands:   "hostname",
+		Units:      []string{"magic/0"},
+		RelationId: "db:2",
+	})
+	c.Assert(err, gc.ErrorMatches, "running commands in a relation context in this version of the Client facade not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *serverSuite) TestEnvUsersInfo(c *gc.C) {
 	testAdmin := s.AdminUserTag(c)
 	owner, err := s.State.EnvironmentUser(testAdmin)
@@ -175,6 +185,7 @@ func (s *serverSuite) TestEnvUsersInfo(c *gc.C) {
 		r.info.CreatedBy = owner.UserName()
 		r.info.DateCreated = r.user.DateCreated()
 		r.info.LastConnection = lastConnPointer(c, r.user)
+		r.info.Access = params.EnvAdminAccess
 		expected.Results = append(expected.Results, params.EnvUserInfoResult{Result: r.info})
 	}
 
@@ -288,6 +299,22 @@ func (s *serverSuite) TestShareEnvironmentAddLocalUser(c *gc.C) {
 	c.Assert(lastConn, gc.Equals, time.Time{})
 }
 
+func (s *serverSuite) TestBlockChangesShareEnvironment(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+		}}}
+
+	s.BlockAllChanges(c, "TestBlockChangesShareEnvironment")
+	_, err := s.client.ShareEnvironment(args)
+	s.AssertBlocked(c, err, "TestBlockChangesShareEnvironment")
+
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *serverSuite) TestShareEnvironmentAddRemoteUser(c *gc.C) {
 	user := names.NewUserTag("foobar@ubuntuone")
 	args := params.ModifyEnvironUsers{
@@ -311,6 +338,67 @@ func (s *serverSuite) TestShareEnvironmentAddRemoteUser(c *gc.C) {
 	c.Assert(lastConn.IsZero(), jc.IsTrue)
 }
 
+func (s *serverSuite) TestShareEnvironmentAddUserWithAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  params.EnvReadAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvReadAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentAddUserInvalidAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  "superuser",
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `could not share environment: environment user access "superuser" not valid`)
+
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *serverSuite) TestShareEnvironmentSetAccess(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, nil)
+	missing := names.NewUserTag("missing@ubuntuone")
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: envUser.UserTag().String(),
+			Action:  params.SetEnvUserAccess,
+			Access:  params.EnvWriteAccess,
+		}, {
+			UserTag: missing.String(),
+			Action:  params.SetEnvUserAccess,
+			Access:  params.EnvWriteAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `could not set environment access: environment user "missing@ubuntuone" not found`)
+	c.Assert(result.Results[1].Error.Code, gc.Equals, params.CodeNotFound)
+
+	envUser, err = s.State.EnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvWriteAccess)
+}
+
 func (s *serverSuite) TestShareEnvironmentAddUserTwice(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
 	args := params.ModifyEnvironUsers{
@@ -1551,6 +1639,34 @@ func (s *clientSuite) TestClientUnitResolvedRetry(c *gc.C) {
 	s.testClientUnitResolved(c, true, state.ResolvedRetryHooks)
 }
 
+func (s *clientSuite) TestClientResolveUnits(c *gc.C) {
+	u := s.setupResolved(c)
+	results, err := s.APIState.Client().ResolveUnits([]params.Resolved{
+		{UnitName: "wordpress/0", Retry: true},
+		{UnitName: "wordpress/1"},
+		{UnitName: "wordpress/42"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{
+		{nil},
+		{&params.Error{Message: `unit "wordpress/1" is not in an error state`}},
+		{&params.Error{Message: `unit "wordpress/42" not found`, Code: params.CodeNotFound}},
+	})
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Resolved(), gc.Equals, state.ResolvedRetryHooks)
+}
+
+func (s *clientSuite) TestBlockChangeResolveUnits(c *gc.C) {
+	u := s.setupResolved(c)
+	s.BlockAllChanges(c, "TestBlockChangeResolveUnits")
+	_, err := s.APIState.Client().ResolveUnits([]params.Resolved{{UnitName: "wordpress/0"}})
+	s.AssertBlocked(c, err, "TestBlockChangeResolveUnits")
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Resolved(), gc.Equals, state.ResolvedNone)
+}
+
 func (s *clientSuite) setupResolved(c *gc.C) *state.Unit {
 	s.setUpScenario(c)
 	u, err := s.State.Unit("wordpress/0")
@@ -2115,6 +2231,41 @@ func (s *clientRepoSuite) TestClientServiceSetCharm(c *gc.C) {
 	c.Assert(force, jc.IsFalse)
 }
 
+func (s *clientRepoSuite) TestClientServiceSetUpgradeStrategy(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := s.APIState.Client().ServiceSetUpgradeStrategy("wordpress", "rolling", 2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.UpgradeStrategy(), jc.DeepEquals, state.UpgradeStrategy{
+		Mode:           state.UpgradeRolling,
+		MaxUnavailable: 2,
+	})
+}
+
+func (s *clientRepoSuite) TestClientServiceSetUpgradeStrategyInvalid(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := s.APIState.Client().ServiceSetUpgradeStrategy("wordpress", "rolling", 0)
+	c.Assert(err, gc.ErrorMatches, `cannot set upgrade strategy for service "wordpress": max-unavailable 0 not valid`)
+}
+
+func (s *clientRepoSuite) TestClientServiceSetAddressPolicy(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := s.APIState.Client().ServiceSetAddressPolicy("wordpress", "prefer-public")
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.AddressPolicy(), gc.Equals, network.PreferPublicAddressPolicy)
+}
+
+func (s *clientRepoSuite) TestClientServiceSetAddressPolicyInvalid(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := s.APIState.Client().ServiceSetAddressPolicy("wordpress", "prefer-ipv9")
+	c.Assert(err, gc.ErrorMatches, `cannot set address policy for service "wordpress": address policy "prefer-ipv9" not valid`)
+}
+
 func (s *clientRepoSuite) setupServiceSetCharm(c *gc.C) {
 	curl, _ := s.UploadCharm(c, "precise/dummy-0", "dummy")
 	err := service.AddCharmWithAuthorization(s.State, params.AddCharmWithAuthorization{URL: curl.String()})
@@ -2627,6 +2778,90 @@ func (s *clientSuite) TestClientPublicAddressUnit(c *gc.C) {
 	c.Assert(addr, gc.Equals, "public")
 }
 
+func (s *clientSuite) TestClientSSHHostKeys(c *gc.C) {
+	s.setUpScenario(c)
+
+	_, err := s.APIState.Client().SSHHostKeys("wordpress")
+	c.Assert(err, gc.ErrorMatches, `unknown unit or machine "wordpress"`)
+	_, err = s.APIState.Client().SSHHostKeys("1")
+	c.Assert(err, gc.ErrorMatches, `SSH host keys for machine-1 not found`)
+
+	err = s.State.SetSSHHostKeys(names.NewMachineTag("1"), state.SSHHostKeys{"rsa foo", "dsa bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	keys, err := s.APIState.Client().SSHHostKeys("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{"rsa foo", "dsa bar"})
+	keys, err = s.APIState.Client().SSHHostKeys("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{"rsa foo", "dsa bar"})
+}
+
+func (s *clientSuite) TestClientAuditLog(c *gc.C) {
+	when := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, method := range []string{"ServiceDeploy", "ServiceExpose"} {
+		err := s.State.AddAuditEntry(state.AuditEntry{
+			Time:   when.Add(time.Duration(i) * time.Minute),
+			Entity: "user-bob@local",
+			Facade: "Client",
+			Method: method,
+			Args:   `{"ServiceName":"wordpress"}`,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	entries, err := s.APIState.Client().AuditLog(params.AuditLogFilter{Entity: "user-bob@local"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 2)
+	c.Assert(entries[0].Method, gc.Equals, "ServiceExpose")
+	c.Assert(entries[0].Time.Equal(when.Add(time.Minute)), jc.IsTrue)
+	entries[0].Time = time.Time{}
+	c.Assert(entries[0], jc.DeepEquals, params.AuditLogEntry{
+		Entity: "user-bob@local",
+		Facade: "Client",
+		Method: "ServiceExpose",
+		Args:   `{"ServiceName":"wordpress"}`,
+	})
+
+	since := when.Add(30 * time.Second)
+	entries, err = s.APIState.Client().AuditLog(params.AuditLogFilter{Entity: "user-bob@local", Since: &since})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].Method, gc.Equals, "ServiceExpose")
+
+	_, err = s.APIState.Client().AuditLog(params.AuditLogFilter{Limit: -1})
+	c.Assert(err, gc.ErrorMatches, "invalid limit: -1")
+}
+
+func (s *clientSuite) TestClientProblemReports(c *gc.C) {
+	reports, err := s.APIState.Client().ProblemReports()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reports, gc.HasLen, 0)
+
+	when := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	_, err = s.State.AddProblemReport(state.ProblemReportInfo{
+		Entity:  names.NewUnitTag("wordpress/0"),
+		Kind:    state.HookFailure,
+		Source:  "install",
+		Message: `hook "install" failed`,
+		Logs:    []string{"installing"},
+		Time:    when,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	reports, err = s.APIState.Client().ProblemReports()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reports, gc.HasLen, 1)
+	c.Assert(reports[0].Time.Equal(when), jc.IsTrue)
+	reports[0].Time = time.Time{}
+	c.Assert(reports[0], jc.DeepEquals, params.ProblemReport{
+		Id:      "0",
+		Tag:     "unit-wordpress-0",
+		Kind:    "hook-failed",
+		Source:  "install",
+		Message: `hook "install" failed`,
+		Logs:    []string{"installing"},
+	})
+}
+
 func (s *clientSuite) TestClientPrivateAddressErrors(c *gc.C) {
 	s.setUpScenario(c)
 	_, err := s.APIState.Client().PrivateAddress("wordpress")
@@ -3344,6 +3579,79 @@ func (s *clientSuite) TestRetryProvisioning(c *gc.C) {
 	c.Assert(statusInfo.Data["transient"], jc.IsTrue)
 }
 
+func (s *clientSuite) TestRetryProvisioningProvisioned(c *gc.C) {
+	provisioned, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = provisioned.SetProvisioned("i-am", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = provisioned.SetStatus(state.StatusError, "error", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	failed := s.setupRetryProvisioning(c)
+
+	results, err := s.APIState.Client().RetryProvisioning(
+		provisioned.Tag().(names.MachineTag),
+		failed.Tag().(names.MachineTag),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.ErrorMatches, fmt.Sprintf(`machine %s is already provisioned as instance "i-am"`, provisioned.Id()))
+	c.Assert(results[1].Error, gc.IsNil)
+
+	statusInfo, err := provisioned.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Data["transient"], gc.IsNil)
+	statusInfo, err = failed.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Data["transient"], jc.IsTrue)
+}
+
+func (s *clientSuite) TestForceRetryProvisioning(c *gc.C) {
+	provisioned, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = provisioned.SetProvisioned("i-am", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	failed := s.setupRetryProvisioning(c)
+
+	results, err := s.APIState.Client().ForceRetryProvisioning(
+		provisioned.Tag().(names.MachineTag),
+		failed.Tag().(names.MachineTag),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.IsNil)
+
+	// The provisioned machine's instance has been forgotten, and the
+	// machine left for the provisioner to retry.
+	_, err = provisioned.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+	statusInfo, err := provisioned.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, state.StatusError)
+	c.Assert(statusInfo.Message, gc.Equals, `instance "i-am" stopped for reprovisioning`)
+	c.Assert(statusInfo.Data["transient"], jc.IsTrue)
+	statusInfo, err = failed.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Data["transient"], jc.IsTrue)
+}
+
+func (s *clientSuite) TestForceRetryProvisioningStarted(c *gc.C) {
+	started, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = started.SetProvisioned("i-am", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = started.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.APIState.Client().ForceRetryProvisioning(started.Tag().(names.MachineTag))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, fmt.Sprintf(`machine %s has started on instance "i-am"; remove it instead`, started.Id()))
+	instId, err := started.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("i-am"))
+}
+
 func (s *clientSuite) setupRetryProvisioning(c *gc.C) *state.Machine {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -3497,6 +3805,54 @@ func (s *clientSuite) TestAnyBlockForceDestroyMachines(c *gc.C) {
 	s.assertForceDestroyMachines(c)
 }
 
+func (s *clientSuite) TestDestroyMachinesBulk(c *gc.C) {
+	m0, m1, m2, u := s.setupDestroyMachinesTest(c)
+	results, err := s.APIState.Client().DestroyMachinesBulk(false, "0", "1", "2", "42")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{
+		{&params.Error{Message: "machine 0 is required by the environment"}},
+		{&params.Error{Message: `machine 1 has unit "wordpress/0" assigned`, Code: params.CodeHasAssignedUnits}},
+		{nil},
+		{&params.Error{Message: "machine 42 not found", Code: params.CodeNotFound}},
+	})
+	assertLife(c, m0, state.Alive)
+	assertLife(c, m1, state.Alive)
+	assertLife(c, m2, state.Dying)
+	assertLife(c, u, state.Alive)
+
+	// Destroying a dying machine is not an error.
+	results, err = s.APIState.Client().DestroyMachinesBulk(false, "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{nil}})
+}
+
+func (s *clientSuite) TestForceDestroyMachinesBulk(c *gc.C) {
+	m0, m1, m2, u := s.setupDestroyMachinesTest(c)
+	// Force bypasses all blocks.
+	s.BlockRemoveObject(c, "TestForceDestroyMachinesBulk")
+	results, err := s.APIState.Client().DestroyMachinesBulk(true, "0", "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{
+		{&params.Error{Message: "machine 0 is required by the environment"}},
+		{nil},
+		{nil},
+	})
+
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	assertLife(c, m0, state.Alive)
+	assertLife(c, m1, state.Dead)
+	assertLife(c, m2, state.Dead)
+	assertRemoved(c, u)
+}
+
+func (s *clientSuite) TestBlockRemoveDestroyMachinesBulk(c *gc.C) {
+	m0, m1, m2, u := s.setupDestroyMachinesTest(c)
+	s.BlockRemoveObject(c, "TestBlockRemoveDestroyMachinesBulk")
+	_, err := s.APIState.Client().DestroyMachinesBulk(false, "0", "1", "2")
+	s.assertBlockedErrorAndLiveliness(c, err, "TestBlockRemoveDestroyMachinesBulk", m0, m1, m2, u)
+}
+
 func (s *clientSuite) assertForceDestroyMachines(c *gc.C) {
 	m0, m1, m2, u := s.setupDestroyMachinesTest(c)
 
@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/process"
+)
+
+// ListProcesses returns the workload processes tracked by the
+// specified units, or by all units if none are specified. Units with
+// no matching processes are omitted unless they were specified.
+func (c *Client) ListProcesses(args params.ListProcessesArgs) (params.ListProcessesResults, error) {
+	if args.HistorySize < 0 {
+		return params.ListProcessesResults{}, errors.Errorf("invalid history size: %d", args.HistorySize)
+	}
+	var results params.ListProcessesResults
+	if len(args.Units) == 0 {
+		units, err := c.allUnits()
+		if err != nil {
+			return params.ListProcessesResults{}, errors.Trace(err)
+		}
+		for _, unit := range units {
+			result := unitProcesses(unit, args)
+			if len(result.Processes) > 0 || result.Error != nil {
+				results.Results = append(results.Results, result)
+			}
+		}
+		return results, nil
+	}
+	for _, name := range args.Units {
+		unit, err := c.api.stateAccessor.Unit(name)
+		if err != nil {
+			results.Results = append(results.Results, params.UnitProcessesResult{
+				Unit:  name,
+				Error: common.ServerError(err),
+			})
+			continue
+		}
+		results.Results = append(results.Results, unitProcesses(unit, args))
+	}
+	return results, nil
+}
+
+// allUnits returns all the units in the environment, ordered by name.
+func (c *Client) allUnits() ([]Unit, error) {
+	services, err := c.api.stateAccessor.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var units []Unit
+	for _, service := range services {
+		serviceUnits, err := service.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, unit := range serviceUnits {
+			units = append(units, unit)
+		}
+	}
+	sort.Sort(unitsByName(units))
+	return units, nil
+}
+
+type unitsByName []Unit
+
+func (u unitsByName) Len() int           { return len(u) }
+func (u unitsByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitsByName) Less(i, j int) bool { return u[i].Name() < u[j].Name() }
+
+// unitProcesses returns the processes of the unit that match args.
+func unitProcesses(unit Unit, args params.ListProcessesArgs) params.UnitProcessesResult {
+	result := params.UnitProcessesResult{Unit: unit.Name()}
+	infos, err := unit.Processes()
+	if err != nil {
+		result.Error = common.ServerError(err)
+		return result
+	}
+	for _, info := range infos {
+		if !matchesProcess(info, args) {
+			continue
+		}
+		status := params.ProcessStatus{Info: common.ProcessInfoToParams(info)}
+		if args.HistorySize > 0 {
+			history, err := unit.ProcessStatusHistory(info.Name, args.HistorySize)
+			if err != nil {
+				result.Error = common.ServerError(err)
+				return result
+			}
+			status.History = agentStatusFromStatusInfo(history, params.KindProcess)
+		}
+		result.Processes = append(result.Processes, status)
+	}
+	return result
+}
+
+// matchesProcess reports whether the process matches the type and
+// status filters in args.
+func matchesProcess(info process.Info, args params.ListProcessesArgs) bool {
+	return matchesAny(info.Type, args.Types) && matchesAny(info.Details.Status, args.Statuses)
+}
+
+// matchesAny reports whether value is one of values, or values is
+// empty.
+func matchesAny(value string, values []string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
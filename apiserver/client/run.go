@@ -84,9 +84,29 @@ func (c *Client) getDataDir() string {
 // Run the commands specified on the machines identified through the
 // list of machines, units and services.
 func (c *Client) Run(run params.RunParams) (results params.RunResults, err error) {
+	if run.RelationId != "" || run.RemoteUnit != "" {
+		return results, errors.NotSupportedf("running commands in a relation context in this version of the Client facade")
+	}
+	return c.run(run)
+}
+
+// Run the commands specified on the machines identified through the
+// list of machines, units and services, in the context of the relation
+// given by RelationId and RemoteUnit if they are set.
+func (c *ClientV3) Run(run params.RunParams) (results params.RunResults, err error) {
+	return c.run(run)
+}
+
+func (c *Client) run(run params.RunParams) (results params.RunResults, err error) {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.RunResults{}, errors.Trace(err)
 	}
+	if run.RelationId == "" && run.RemoteUnit != "" {
+		return results, errors.New("remote unit specified without a relation")
+	}
+	if run.RelationId != "" && len(run.Machines) > 0 {
+		return results, errors.New("cannot run commands on machines in a relation context")
+	}
 	units, err := getAllUnitNames(c.api.state(), run.Units, run.Services)
 	if err != nil {
 		return results, err
@@ -97,6 +117,7 @@ func (c *Client) Run(run params.RunParams) (results params.RunResults, err error
 	// the other outside the context just using bash.
 	var params []*RemoteExec
 	var quotedCommands = utils.ShQuote(run.Commands)
+	contextArgs := relationContextArgs(run)
 	for _, unit := range units {
 		// We know that the unit is both a principal unit, and that it has an
 		// assigned machine.
@@ -105,7 +126,7 @@ func (c *Client) Run(run params.RunParams) (results params.RunResults, err error
 		if err != nil {
 			return results, err
 		}
-		command := fmt.Sprintf("juju-run %s %s", unit.Name(), quotedCommands)
+		command := fmt.Sprintf("juju-run%s %s %s", contextArgs, unit.Name(), quotedCommands)
 		execParam := remoteParamsForMachine(machine, command, run.Timeout)
 		execParam.UnitId = unit.Name()
 		params = append(params, execParam)
@@ -122,6 +143,19 @@ func (c *Client) Run(run params.RunParams) (results params.RunResults, err error
 	return ParallelExecute(c.getDataDir(), params), nil
 }
 
+// relationContextArgs returns the juju-run flags needed to run the
+// commands in the relation context described by run, if any.
+func relationContextArgs(run params.RunParams) string {
+	var args string
+	if run.RelationId != "" {
+		args += " --relation " + utils.ShQuote(run.RelationId)
+	}
+	if run.RemoteUnit != "" {
+		args += " --remote-unit " + utils.ShQuote(run.RemoteUnit)
+	}
+	return args
+}
+
 // RunOnAllMachines attempts to run the specified command on all the machines.
 func (c *Client) RunOnAllMachines(run params.RunParams) (params.RunResults, error) {
 	if err := c.check.ChangeAllowed(); err != nil {
@@ -346,3 +346,50 @@ func (s *runSuite) TestBlockRunMachineAndService(c *gc.C) {
 		})
 	s.AssertBlocked(c, err, "TestBlockRunMachineAndService")
 }
+
+func (s *runSuite) TestRunUnitInRelationContext(c *gc.C) {
+	charm := s.AddTestingCharm(c, "dummy")
+	owner := s.Factory.MakeUser(c, nil).Tag()
+	magic, err := s.State.AddService("magic", owner.String(), charm, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addUnit(c, magic)
+
+	s.mockSSH(c, echoInput)
+
+	client := s.APIState.Client()
+	results, err := client.Run(
+		params.RunParams{
+			Commands:   "hostname",
+			Timeout:    testing.LongWait,
+			Units:      []string{"magic/0"},
+			RelationId: "db:2",
+			RemoteUnit: "mysql/0",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.RunResult{{
+		ExecResponse: exec.ExecResponse{Stdout: []byte(expectedCommand[3])},
+		MachineId:    "0",
+		UnitId:       "magic/0",
+	}})
+}
+
+func (s *runSuite) TestRunRelationContextErrors(c *gc.C) {
+	client := s.APIState.Client()
+	_, err := client.Run(
+		params.RunParams{
+			Commands:   "hostname",
+			Timeout:    testing.LongWait,
+			Units:      []string{"magic/0"},
+			RemoteUnit: "mysql/0",
+		})
+	c.Assert(err, gc.ErrorMatches, "remote unit specified without a relation")
+
+	_, err = client.Run(
+		params.RunParams{
+			Commands:   "hostname",
+			Timeout:    testing.LongWait,
+			Machines:   []string{"0"},
+			RelationId: "db:2",
+		})
+	c.Assert(err, gc.ErrorMatches, "cannot run commands on machines in a relation context")
+}
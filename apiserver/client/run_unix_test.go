@@ -9,6 +9,7 @@ var expectedCommand = []string{
 	"juju-run --no-context 'hostname'\n",
 	"juju-run magic/0 'hostname'\n",
 	"juju-run magic/1 'hostname'\n",
+	"juju-run --relation 'db:2' --remote-unit 'mysql/0' magic/0 'hostname'\n",
 }
 
 var echoInputShowArgs = `#!/bin/bash
@@ -9,6 +9,7 @@ var expectedCommand = []string{
 	"juju-run --no-context 'hostname'\r\n",
 	"juju-run magic/0 'hostname'\r\n",
 	"juju-run magic/1 'hostname'\r\n",
+	"juju-run --relation 'db:2' --remote-unit 'mysql/0' magic/0 'hostname'\r\n",
 }
 
 var echoInputShowArgs = `@echo off
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/process"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/version"
 )
@@ -23,8 +24,19 @@ type Unit interface {
 	IsPrincipal() bool
 	PublicAddress() (network.Address, error)
 	PrivateAddress() (network.Address, error)
+	AssignedMachineId() (string, error)
 	Resolve(retryHooks bool) error
 	AgentHistory() state.StatusHistoryGetter
+	Name() string
+	Processes() ([]process.Info, error)
+	ProcessStatusHistory(name string, size int) ([]state.StatusInfo, error)
+}
+
+// MachineHistory represents the status history of a state.Machine and
+// of its provider instance.
+type MachineHistory interface {
+	state.StatusHistoryGetter
+	InstanceStatusHistory(size int) ([]state.StatusInfo, error)
 }
 
 // stateInterface contains the state.State methods used in this package,
@@ -34,6 +46,8 @@ type stateInterface interface {
 	Unit(string) (Unit, error)
 	Service(string) (*state.Service, error)
 	Machine(string) (*state.Machine, error)
+	MachineHistory(string) (MachineHistory, error)
+	ServiceHistory(string) (state.StatusHistoryGetter, error)
 	AllMachines() ([]*state.Machine, error)
 	AllServices() ([]*state.Service, error)
 	AllRelations() ([]*state.Relation, error)
@@ -56,11 +70,17 @@ type stateInterface interface {
 	Charm(*charm.URL) (*state.Charm, error)
 	LatestPlaceholderCharm(*charm.URL) (*state.Charm, error)
 	AddRelation(...state.Endpoint) (*state.Relation, error)
-	AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*state.EnvironmentUser, error)
+	AddEnvironmentUserWithAccess(user, createdBy names.UserTag, displayName string, access state.EnvUserAccess) (*state.EnvironmentUser, error)
+	EnvironmentUser(names.UserTag) (*state.EnvironmentUser, error)
 	RemoveEnvironmentUser(names.UserTag) error
 	Watch() *state.Multiwatcher
 	AbortCurrentUpgrade() error
 	APIHostPorts() ([][]network.HostPort, error)
+	GetSSHHostKeys(names.MachineTag) (state.SSHHostKeys, error)
+	ProblemReports() ([]state.ProblemReport, error)
+	AuditEntries(state.AuditFilter) ([]state.AuditEntry, error)
+	ServiceLeaders() (map[string]string, error)
+	UnitProcesses([]string) (map[string][]process.Info, error)
 }
 
 type stateShim struct {
@@ -74,3 +94,19 @@ func (s *stateShim) Unit(name string) (Unit, error) {
 	}
 	return u, nil
 }
+
+func (s *stateShim) MachineHistory(id string) (MachineHistory, error) {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *stateShim) ServiceHistory(name string) (state.StatusHistoryGetter, error) {
+	svc, err := s.State.Service(name)
+	if err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
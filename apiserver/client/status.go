@@ -13,9 +13,11 @@ import (
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/process"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/worker/uniter/operation"
@@ -84,6 +86,61 @@ func (c *Client) UnitStatusHistory(args params.StatusHistory) (params.UnitStatus
 	return statuses, nil
 }
 
+// MachineStatusHistory returns a slice of past statuses for a given
+// machine and its provider instance.
+func (c *ClientV1) MachineStatusHistory(args params.StatusHistory) (params.UnitStatusHistory, error) {
+	if args.Size < 1 {
+		return params.UnitStatusHistory{}, errors.Errorf("invalid history size: %d", args.Size)
+	}
+	machine, err := c.api.stateAccessor.MachineHistory(args.Name)
+	if err != nil {
+		return params.UnitStatusHistory{}, errors.Trace(err)
+	}
+	statuses := params.UnitStatusHistory{}
+	if args.Kind == params.KindCombined || args.Kind == params.KindMachine {
+		machineStatuses, err := machine.StatusHistory(args.Size)
+		if err != nil {
+			return params.UnitStatusHistory{}, errors.Trace(err)
+		}
+		statuses.Statuses = append(statuses.Statuses, agentStatusFromStatusInfo(machineStatuses, params.KindMachine)...)
+	}
+	if args.Kind == params.KindCombined || args.Kind == params.KindMachineInstance {
+		instanceStatuses, err := machine.InstanceStatusHistory(args.Size)
+		if err != nil {
+			return params.UnitStatusHistory{}, errors.Trace(err)
+		}
+		statuses.Statuses = append(statuses.Statuses, agentStatusFromStatusInfo(instanceStatuses, params.KindMachineInstance)...)
+	}
+
+	sort.Sort(sortableStatuses(statuses.Statuses))
+	if args.Kind == params.KindCombined && len(statuses.Statuses) > args.Size {
+		statuses.Statuses = statuses.Statuses[len(statuses.Statuses)-args.Size:]
+	}
+	return statuses, nil
+}
+
+// ServiceStatusHistory returns a slice of past statuses for a given
+// service.
+func (c *Client) ServiceStatusHistory(args params.StatusHistory) (params.UnitStatusHistory, error) {
+	if args.Size < 1 {
+		return params.UnitStatusHistory{}, errors.Errorf("invalid history size: %d", args.Size)
+	}
+	service, err := c.api.stateAccessor.ServiceHistory(args.Name)
+	if err != nil {
+		return params.UnitStatusHistory{}, errors.Trace(err)
+	}
+	statuses := params.UnitStatusHistory{}
+	if args.Kind == params.KindCombined || args.Kind == params.KindService {
+		serviceStatuses, err := service.StatusHistory(args.Size)
+		if err != nil {
+			return params.UnitStatusHistory{}, errors.Trace(err)
+		}
+		statuses.Statuses = agentStatusFromStatusInfo(serviceStatuses, params.KindService)
+	}
+	sort.Sort(sortableStatuses(statuses.Statuses))
+	return statuses, nil
+}
+
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error) {
 	cfg, err := c.api.stateAccessor.EnvironConfig()
@@ -101,6 +158,8 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		return noStatus, errors.Annotate(err, "could not fetch relations")
 	} else if context.networks, err = fetchNetworks(c.api.stateAccessor); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch networks")
+	} else if context.leaders, err = c.api.stateAccessor.ServiceLeaders(); err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch service leaders")
 	}
 
 	logger.Debugf("Services: %v", context.services)
@@ -176,6 +235,10 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		}
 	}
 
+	if context.processes, err = fetchUnitProcesses(c.api.stateAccessor, context.units); err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch unit processes")
+	}
+
 	newToolsVersion, err := c.newToolsVersionAvailable()
 	if err != nil {
 		return noStatus, errors.Annotate(err, "cannot determine if there is a new tools version available")
@@ -242,6 +305,10 @@ type statusContext struct {
 	units        map[string]map[string]*state.Unit
 	networks     map[string]*state.Network
 	latestCharms map[charm.URL]string
+	// leaders: service name -> name of the unit holding leadership.
+	leaders map[string]string
+	// processes: unit name -> workload processes tracked by the unit.
+	processes map[string][]process.Info
 }
 
 // fetchMachines returns a map from top level machine id to machines, where machines[0] is the host
@@ -345,6 +412,18 @@ func fetchUnitMachineIds(units map[string]map[string]*state.Unit) (set.Strings,
 	return machineIds, nil
 }
 
+// fetchUnitProcesses returns the workload processes tracked by the
+// given units, keyed by unit name.
+func fetchUnitProcesses(st stateInterface, units map[string]map[string]*state.Unit) (map[string][]process.Info, error) {
+	var names []string
+	for _, unitMap := range units {
+		for name := range unitMap {
+			names = append(names, name)
+		}
+	}
+	return st.UnitProcesses(names)
+}
+
 // fetchRelations returns a map of all relations keyed by service name.
 //
 // This structure is useful for processServiceRelations() which needs
@@ -436,9 +515,12 @@ func makeMachineStatus(machine *state.Machine) (status params.MachineStatus) {
 	instid, err := machine.InstanceId()
 	if err == nil {
 		status.InstanceId = instid
-		status.InstanceState, err = machine.InstanceStatus()
+		instStatus, err := machine.InstanceStatus()
 		if err != nil {
 			status.InstanceState = "error"
+		} else {
+			status.InstanceState = string(instStatus.Status)
+			status.InstanceStateInfo = instStatus.Message
 		}
 		addr, err := machine.PublicAddress()
 		if err != nil {
@@ -617,6 +699,7 @@ func (context *statusContext) processService(service *state.Service) (status par
 		status.Status.Since = serviceStatus.Since
 
 		status.MeterStatuses = context.processUnitMeterStatuses(context.units[service.Name()])
+		status.Processes = context.processUnitProcesses(context.units[service.Name()])
 	}
 	return status
 }
@@ -642,6 +725,34 @@ func (context *statusContext) processUnitMeterStatuses(units map[string]*state.U
 	return nil
 }
 
+// processUnitProcesses summarises the workload processes tracked by
+// the given units. It returns nil if the units track no processes.
+func (context *statusContext) processUnitProcesses(units map[string]*state.Unit) *params.ProcessesStatus {
+	var summary params.ProcessesStatus
+	for _, unit := range units {
+		for _, info := range context.processes[unit.Name()] {
+			if summary.Statuses == nil {
+				summary.Statuses = make(map[string]int)
+			}
+			summary.Statuses[info.Details.Status]++
+			if !process.IsFailedStatus(info.Details.Status) {
+				continue
+			}
+			if summary.Failed == nil {
+				summary.Failed = make(map[string][]string)
+			}
+			summary.Failed[unit.Name()] = append(summary.Failed[unit.Name()], info.Name)
+		}
+	}
+	if summary.Statuses == nil {
+		return nil
+	}
+	for _, names := range summary.Failed {
+		sort.Strings(names)
+	}
+	return &summary
+}
+
 func (context *statusContext) processUnits(units map[string]*state.Unit, serviceCharm string) map[string]params.UnitStatus {
 	unitsMap := make(map[string]params.UnitStatus)
 	for _, unit := range units {
@@ -672,6 +783,11 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 		result.Charm = curl.String()
 	}
 	processUnitAndAgentStatus(unit, &result)
+	result.Leader = context.leaders[unit.ServiceName()] == unit.Name()
+
+	for _, info := range context.processes[unit.Name()] {
+		result.Processes = append(result.Processes, common.ProcessInfoToParams(info))
+	}
 
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
 		result.Subordinates = make(map[string]params.UnitStatus)
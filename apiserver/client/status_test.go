@@ -4,12 +4,15 @@
 package client_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/client"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/process"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
@@ -164,3 +167,117 @@ func (s *statusUnitTestSuite) TestMeterStatus(c *gc.C) {
 		}
 	}
 }
+
+func (s *statusUnitTestSuite) TestLeader(c *gc.C) {
+	service := s.MakeService(c, nil)
+	leader := s.MakeUnit(c, &factory.UnitParams{Service: service})
+	follower := s.MakeUnit(c, &factory.UnitParams{Service: service})
+	err := s.State.LeadershipClaimer().ClaimLeadership(service.Name(), leader.Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	status, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	serviceStatus, ok := status.Services[service.Name()]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(serviceStatus.Units[leader.Name()].Leader, jc.IsTrue)
+	c.Check(serviceStatus.Units[follower.Name()].Leader, jc.IsFalse)
+}
+
+func (s *statusUnitTestSuite) TestProcesses(c *gc.C) {
+	service := s.MakeService(c, nil)
+	processes := [][]process.Info{{{
+		Name:    "web",
+		Type:    "docker",
+		Details: process.Details{ID: "abc", Status: "running"},
+	}, {
+		Name:    "worker",
+		Type:    "systemd",
+		Details: process.Details{ID: "juju-worker.service", Status: "failed"},
+	}}, {{
+		Name:    "web",
+		Type:    "docker",
+		Details: process.Details{ID: "def", Status: "running"},
+	}}, nil}
+	var unitNames []string
+	for _, infos := range processes {
+		u, err := service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		unitNames = append(unitNames, u.Name())
+		err = u.SetProcesses(infos)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	idle := s.MakeService(c, &factory.ServiceParams{
+		Charm: s.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+
+	client := s.APIState.Client()
+	status, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Services[service.Name()].Processes, jc.DeepEquals, &params.ProcessesStatus{
+		Statuses: map[string]int{"running": 2, "failed": 1},
+		Failed:   map[string][]string{unitNames[0]: {"worker"}},
+	})
+	c.Assert(status.Services[idle.Name()].Processes, gc.IsNil)
+}
+
+func (s *statusUnitTestSuite) TestListProcesses(c *gc.C) {
+	service := s.MakeService(c, nil)
+	web := process.Info{
+		Name:    "web",
+		Type:    "docker",
+		Details: process.Details{ID: "abc", Status: "running"},
+	}
+	worker := process.Info{
+		Name:    "worker",
+		Type:    "systemd",
+		Details: process.Details{ID: "juju-worker.service", Status: "running"},
+	}
+	u0, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.SetProcesses([]process.Info{web, worker})
+	c.Assert(err, jc.ErrorIsNil)
+	worker.Details.Status = "failed"
+	err = u0.SetProcesses([]process.Info{web, worker})
+	c.Assert(err, jc.ErrorIsNil)
+	u1, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u1.SetProcesses([]process.Info{web})
+	c.Assert(err, jc.ErrorIsNil)
+	idle, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	results, err := client.ListProcesses(nil, nil, nil, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Unit, gc.Equals, u0.Name())
+	c.Assert(results[0].Processes, gc.HasLen, 2)
+	c.Assert(results[0].Processes[0].Info.Name, gc.Equals, "web")
+	c.Assert(results[0].Processes[1].Info.Details.Status, gc.Equals, "failed")
+	c.Assert(results[0].Processes[1].History, gc.HasLen, 0)
+	c.Assert(results[1].Unit, gc.Equals, u1.Name())
+
+	// Filter by type and status, with history.
+	results, err = client.ListProcesses(nil, []string{"systemd"}, []string{"failed"}, 5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Unit, gc.Equals, u0.Name())
+	c.Assert(results[0].Processes, gc.HasLen, 1)
+	c.Assert(results[0].Processes[0].Info.Name, gc.Equals, "worker")
+	history := results[0].Processes[0].History
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, params.Status("failed"))
+	c.Assert(history[0].Kind, gc.Equals, params.KindProcess)
+	c.Assert(history[1].Status, gc.Equals, params.Status("running"))
+
+	// Named units are included even without processes.
+	results, err = client.ListProcesses([]string{idle.Name(), "foo/0"}, nil, nil, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.DeepEquals, params.UnitProcessesResult{Unit: idle.Name()})
+	c.Assert(results[1].Error, gc.ErrorMatches, `unit "foo/0" not found`)
+
+	_, err = client.ListProcesses(nil, nil, nil, -1)
+	c.Assert(err, gc.ErrorMatches, "invalid history size: -1")
+}
@@ -12,6 +12,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/state"
@@ -23,7 +24,7 @@ var _ = gc.Suite(&statusHistoryTestSuite{})
 type statusHistoryTestSuite struct {
 	testing.BaseSuite
 	st  *mockState
-	api *client.Client
+	api *client.ClientV1
 }
 
 func (s *statusHistoryTestSuite) SetUpTest(c *gc.C) {
@@ -32,7 +33,7 @@ func (s *statusHistoryTestSuite) SetUpTest(c *gc.C) {
 	tag := names.NewUserTag("user")
 	authorizer := &apiservertesting.FakeAuthorizer{Tag: tag}
 	var err error
-	s.api, err = client.NewClient(nil, nil, authorizer)
+	s.api, err = client.NewClientV1(nil, nil, authorizer)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
@@ -170,10 +171,129 @@ func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 	checkStatusInfo(c, h.Statuses, expected)
 }
 
+func (s *statusHistoryTestSuite) TestMachineStatusHistoryNotInV0(c *gc.C) {
+	facadeType, err := common.Facades.GetType("Client", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := facadeType.MethodByName("MachineStatusHistory")
+	c.Assert(ok, jc.IsFalse)
+
+	facadeType, err = common.Facades.GetType("Client", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = facadeType.MethodByName("MachineStatusHistory")
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *statusHistoryTestSuite) TestMachineStatusHistorySizeRequired(c *gc.C) {
+	_, err := s.api.MachineStatusHistory(params.StatusHistory{
+		Name: "0",
+		Kind: params.KindCombined,
+		Size: 0,
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid history size: 0")
+}
+
+func (s *statusHistoryTestSuite) TestMachineStatusHistoryNotFound(c *gc.C) {
+	_, err := s.api.MachineStatusHistory(params.StatusHistory{
+		Name: "42",
+		Kind: params.KindCombined,
+		Size: 10,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *statusHistoryTestSuite) TestMachineStatusHistoryInstanceOnly(c *gc.C) {
+	s.st.machineHistory = statusInfoWithDates([]state.StatusInfo{
+		{
+			Status: state.StatusStarted,
+		},
+	})
+	s.st.instanceHistory = statusInfoWithDates([]state.StatusInfo{
+		{
+			Status: "deployed",
+		},
+		{
+			Status:  "deploying",
+			Message: "waiting for MAAS deployment",
+		},
+	})
+	h, err := s.api.MachineStatusHistory(params.StatusHistory{
+		Name: "0",
+		Kind: params.KindMachineInstance,
+		Size: 10,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	checkStatusInfo(c, h.Statuses, reverseStatusInfo(s.st.instanceHistory))
+}
+
+func (s *statusHistoryTestSuite) TestMachineStatusHistoryCombined(c *gc.C) {
+	statusAt := func(t int64, status state.Status, message string) state.StatusInfo {
+		since := time.Unix(t, 0)
+		return state.StatusInfo{Status: status, Message: message, Since: &since}
+	}
+	s.st.machineHistory = []state.StatusInfo{
+		statusAt(1003, state.StatusStarted, ""),
+		statusAt(1001, state.StatusPending, ""),
+	}
+	s.st.instanceHistory = []state.StatusInfo{
+		statusAt(1002, "deployed", ""),
+		statusAt(1000, "deploying", "waiting for MAAS deployment"),
+	}
+	h, err := s.api.MachineStatusHistory(params.StatusHistory{
+		Name: "0",
+		Kind: params.KindCombined,
+		Size: 3,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	expected := []state.StatusInfo{
+		s.st.machineHistory[1],
+		s.st.instanceHistory[0],
+		s.st.machineHistory[0],
+	}
+	checkStatusInfo(c, h.Statuses, expected)
+	c.Assert(h.Statuses[0].Kind, gc.Equals, params.KindMachine)
+	c.Assert(h.Statuses[1].Kind, gc.Equals, params.KindMachineInstance)
+}
+
+func (s *statusHistoryTestSuite) TestServiceStatusHistory(c *gc.C) {
+	s.st.serviceHistory = statusInfoWithDates([]state.StatusInfo{
+		{
+			Status:  state.StatusActive,
+			Message: "ready",
+		},
+		{
+			Status:  state.StatusMaintenance,
+			Message: "installing",
+		},
+		{
+			Status: state.StatusUnknown,
+		},
+	})
+	h, err := s.api.ServiceStatusHistory(params.StatusHistory{
+		Name: "wordpress",
+		Kind: params.KindCombined,
+		Size: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	checkStatusInfo(c, h.Statuses, reverseStatusInfo(s.st.serviceHistory[:2]))
+	c.Assert(h.Statuses[0].Kind, gc.Equals, params.KindService)
+}
+
+func (s *statusHistoryTestSuite) TestServiceStatusHistoryNotFound(c *gc.C) {
+	_, err := s.api.ServiceStatusHistory(params.StatusHistory{
+		Name: "mysql",
+		Kind: params.KindService,
+		Size: 10,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 type mockState struct {
 	client.StateInterface
-	unitHistory  []state.StatusInfo
-	agentHistory []state.StatusInfo
+	unitHistory     []state.StatusInfo
+	agentHistory    []state.StatusInfo
+	machineHistory  []state.StatusInfo
+	instanceHistory []state.StatusInfo
+	serviceHistory  []state.StatusInfo
 }
 
 func (m *mockState) EnvironUUID() string {
@@ -190,6 +310,32 @@ func (m *mockState) Unit(name string) (client.Unit, error) {
 	}, nil
 }
 
+func (m *mockState) MachineHistory(id string) (client.MachineHistory, error) {
+	if id != "0" {
+		return nil, errors.NotFoundf("machine %v", id)
+	}
+	return &mockMachine{
+		statuses: m.machineHistory,
+		instance: m.instanceHistory,
+	}, nil
+}
+
+func (m *mockState) ServiceHistory(name string) (state.StatusHistoryGetter, error) {
+	if name != "wordpress" {
+		return nil, errors.NotFoundf("service %q", name)
+	}
+	return statuses(m.serviceHistory), nil
+}
+
+type mockMachine struct {
+	statuses
+	instance statuses
+}
+
+func (m *mockMachine) InstanceStatusHistory(size int) ([]state.StatusInfo, error) {
+	return m.instance.StatusHistory(size)
+}
+
 type mockUnit struct {
 	status statuses
 	agent  *mockUnitAgent
@@ -19,6 +19,7 @@ type AddressAndCertGetter interface {
 	EnvironUUID() string
 	APIHostPorts() ([][]network.HostPort, error)
 	WatchAPIHostPorts() state.NotifyWatcher
+	AddressSelector() (network.AddressSelector, error)
 }
 
 // APIAddresser implements the APIAddresses method
@@ -36,12 +37,25 @@ func NewAPIAddresser(getter AddressAndCertGetter, resources *Resources) *APIAddr
 	}
 }
 
-// APIHostPorts returns the API server addresses.
+// APIHostPorts returns the API server addresses. When the environment
+// has an address selection policy, only the addresses of each server
+// that are selected by the policy are returned.
 func (api *APIAddresser) APIHostPorts() (params.APIHostPortsResult, error) {
 	servers, err := api.getter.APIHostPorts()
 	if err != nil {
 		return params.APIHostPortsResult{}, err
 	}
+	selector, err := api.getter.AddressSelector()
+	if err != nil {
+		return params.APIHostPortsResult{}, err
+	}
+	if selector.Policy != network.DefaultAddressPolicy {
+		for i, hostPorts := range servers {
+			if selected := selector.InternalHostPorts(hostPorts, false); len(selected) > 0 {
+				servers[i] = selected
+			}
+		}
+	}
 	return params.APIHostPortsResult{
 		Servers: params.FromNetworkHostsPorts(servers),
 	}, nil
@@ -64,11 +78,14 @@ func (api *APIAddresser) APIAddresses() (params.StringsResult, error) {
 	if err != nil {
 		return params.StringsResult{}, err
 	}
+	selector, err := api.getter.AddressSelector()
+	if err != nil {
+		return params.StringsResult{}, err
+	}
 	var addrs = make([]string, 0, len(apiHostPorts))
 	for _, hostPorts := range apiHostPorts {
-		addr := network.SelectInternalHostPort(hostPorts, false)
-		if addr != "" {
-			addrs = append(addrs, addr)
+		if selected := selector.InternalHostPorts(hostPorts, false); len(selected) > 0 {
+			addrs = append(addrs, selected[0].NetAddr())
 		}
 	}
 	return params.StringsResult{
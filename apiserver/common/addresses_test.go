@@ -8,6 +8,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 )
@@ -46,6 +47,27 @@ func (s *apiAddresserSuite) TestAPIAddresses(c *gc.C) {
 	c.Assert(result.Result, gc.DeepEquals, []string{"apiaddresses:1", "apiaddresses:2"})
 }
 
+func (s *apiAddresserSuite) TestAPIAddressesWithPolicy(c *gc.C) {
+	s.addresser = common.NewAPIAddresser(fakeAddresses{
+		policy: network.PreferPublicAddressPolicy,
+	}, common.NewResources())
+	result, err := s.addresser.APIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Result, gc.DeepEquals, []string{"8.8.8.8:1", "8.8.4.4:2"})
+}
+
+func (s *apiAddresserSuite) TestAPIHostPortsWithPolicy(c *gc.C) {
+	s.addresser = common.NewAPIAddresser(fakeAddresses{
+		policy: network.PreferPublicAddressPolicy,
+	}, common.NewResources())
+	result, err := s.addresser.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params.NetworkHostsPorts(result.Servers), jc.DeepEquals, [][]network.HostPort{
+		network.NewHostPorts(1, "8.8.8.8"),
+		network.NewHostPorts(2, "8.8.4.4"),
+	})
+}
+
 func (s *apiAddresserSuite) TestCACert(c *gc.C) {
 	result := s.addresser.CACert()
 	c.Assert(string(result.Result), gc.Equals, "a cert")
@@ -58,7 +80,9 @@ func (s *apiAddresserSuite) TestEnvironUUID(c *gc.C) {
 
 var _ common.AddressAndCertGetter = fakeAddresses{}
 
-type fakeAddresses struct{}
+type fakeAddresses struct {
+	policy network.AddressPolicy
+}
 
 func (fakeAddresses) Addresses() ([]string, error) {
 	return []string{"addresses:1", "addresses:2"}, nil
@@ -76,13 +100,23 @@ func (fakeAddresses) EnvironUUID() string {
 	return "the environ uuid"
 }
 
-func (fakeAddresses) APIHostPorts() ([][]network.HostPort, error) {
+func (f fakeAddresses) APIHostPorts() ([][]network.HostPort, error) {
+	if f.policy != network.DefaultAddressPolicy {
+		return [][]network.HostPort{
+			network.NewHostPorts(1, "10.0.0.1", "8.8.8.8"),
+			network.NewHostPorts(2, "10.0.0.2", "8.8.4.4"),
+		}, nil
+	}
 	return [][]network.HostPort{
 		network.NewHostPorts(1, "apiaddresses"),
 		network.NewHostPorts(2, "apiaddresses"),
 	}, nil
 }
 
+func (f fakeAddresses) AddressSelector() (network.AddressSelector, error) {
+	return network.AddressSelector{Policy: f.policy}, nil
+}
+
 func (fakeAddresses) WatchAPIHostPorts() state.NotifyWatcher {
 	panic("should never be called")
 }
@@ -96,6 +96,7 @@ var (
 	ErrBadRequest         = stderrors.New("invalid request")
 	ErrTryAgain           = stderrors.New("try again")
 	ErrActionNotAvailable = stderrors.New("action no longer available")
+	ErrRateLimitExceeded  = stderrors.New("request rate limit exceeded; try again later")
 )
 
 // OperationBlockedError returns an error which signifies that
@@ -125,6 +126,7 @@ var singletonErrorCodes = map[error]string{
 	ErrUnknownWatcher:            params.CodeNotFound,
 	ErrStoppedWatcher:            params.CodeStopped,
 	ErrTryAgain:                  params.CodeTryAgain,
+	ErrRateLimitExceeded:         params.CodeTryAgain,
 	ErrActionNotAvailable:        params.CodeActionNotAvailable,
 }
 
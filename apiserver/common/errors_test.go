@@ -127,6 +127,11 @@ var errorTransformTests = []struct {
 	code:       params.CodeTryAgain,
 	status:     http.StatusInternalServerError,
 	helperFunc: params.IsCodeTryAgain,
+}, {
+	err:        common.ErrRateLimitExceeded,
+	code:       params.CodeTryAgain,
+	status:     http.StatusInternalServerError,
+	helperFunc: params.IsCodeTryAgain,
 }, {
 	err:        state.UpgradeInProgressError,
 	code:       params.CodeUpgradeInProgress,
@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/process"
+)
+
+// ProcessInfoToParams converts a process.Info to a params.ProcessInfo.
+func ProcessInfoToParams(info process.Info) params.ProcessInfo {
+	result := params.ProcessInfo{
+		Name: info.Name,
+		Type: info.Type,
+		Details: params.ProcessDetails{
+			ID:     info.Details.ID,
+			Status: info.Details.Status,
+			Extra:  info.Details.Extra,
+		},
+	}
+	if usage := info.Details.Usage; usage != nil {
+		result.Details.Usage = &params.ProcessUsage{
+			CPU:      usage.CPU,
+			Memory:   usage.Memory,
+			Restarts: usage.Restarts,
+		}
+	}
+	return result
+}
+
+// ProcessInfoFromParams converts a params.ProcessInfo to a process.Info.
+func ProcessInfoFromParams(p params.ProcessInfo) process.Info {
+	info := process.Info{
+		Name: p.Name,
+		Type: p.Type,
+		Details: process.Details{
+			ID:     p.Details.ID,
+			Status: p.Details.Status,
+			Extra:  p.Details.Extra,
+		},
+	}
+	if usage := p.Details.Usage; usage != nil {
+		info.Details.Usage = &process.Usage{
+			CPU:      usage.CPU,
+			Memory:   usage.Memory,
+			Restarts: usage.Restarts,
+		}
+	}
+	return info
+}
@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/process"
+)
+
+type processesSuite struct{}
+
+var _ = gc.Suite(&processesSuite{})
+
+func (*processesSuite) TestProcessInfoConversion(c *gc.C) {
+	info := process.Info{
+		Name: "web",
+		Type: "docker",
+		Details: process.Details{
+			ID:     "abc123",
+			Status: "running",
+			Extra:  map[string]interface{}{"image": "nginx"},
+			Usage:  &process.Usage{CPU: 12.5, Memory: 1024, Restarts: 1},
+		},
+	}
+	p := common.ProcessInfoToParams(info)
+	c.Assert(p, jc.DeepEquals, params.ProcessInfo{
+		Name: "web",
+		Type: "docker",
+		Details: params.ProcessDetails{
+			ID:     "abc123",
+			Status: "running",
+			Extra:  map[string]interface{}{"image": "nginx"},
+			Usage:  &params.ProcessUsage{CPU: 12.5, Memory: 1024, Restarts: 1},
+		},
+	})
+	c.Assert(common.ProcessInfoFromParams(p), jc.DeepEquals, info)
+}
+
+func (*processesSuite) TestProcessInfoConversionNoUsage(c *gc.C) {
+	info := process.Info{
+		Name:    "web",
+		Type:    "docker",
+		Details: process.Details{ID: "abc123", Status: "running"},
+	}
+	p := common.ProcessInfoToParams(info)
+	c.Assert(p.Details.Usage, gc.IsNil)
+	c.Assert(common.ProcessInfoFromParams(p), jc.DeepEquals, info)
+}
@@ -29,6 +29,10 @@ type facadeRecord struct {
 	// If the feature is not the empty string, then this facade
 	// is only returned when that feature flag is set.
 	feature string
+	// If the deprecation is not the empty string, then this
+	// facade is slated for removal, and the deprecation explains
+	// what clients should use instead.
+	deprecation string
 }
 
 // RegisterFacade updates the global facade registry with a new version of a new type.
@@ -48,6 +52,18 @@ func RegisterFacadeForFeature(name string, version int, factory FacadeFactory, f
 	}
 }
 
+// DeprecateFacade marks a version of a facade in the global facade
+// registry as slated for removal. The notice is reported to clients that
+// log in, and logged when the facade is used, so it should say what to
+// use instead.
+func DeprecateFacade(name string, version int, notice string) {
+	if err := Facades.Deprecate(name, version, notice); err != nil {
+		// Like registration, this is meant to be called during
+		// init() so errors should be considered fatal.
+		panic(err)
+	}
+}
+
 // validateNewFacade ensures that the facade factory we have has the right
 // input and output parameters for being used as a NewFoo function.
 func validateNewFacade(funcValue reflect.Value) error {
@@ -199,6 +215,46 @@ func (f *FacadeRegistry) lookup(name string, version int) (facadeRecord, error)
 	return facadeRecord{}, errors.NotFoundf("%s(%d)", name, version)
 }
 
+// Deprecate marks the given version of a registered facade as slated
+// for removal, with a notice explaining what to use instead.
+func (f *FacadeRegistry) Deprecate(name string, version int, notice string) error {
+	if notice == "" {
+		return fmt.Errorf("cannot deprecate %s(%d) without a notice", name, version)
+	}
+	vers, ok := f.facades[name]
+	if !ok {
+		return errors.NotFoundf("%s(%d)", name, version)
+	}
+	record, ok := vers[version]
+	if !ok {
+		return errors.NotFoundf("%s(%d)", name, version)
+	}
+	record.deprecation = notice
+	vers[version] = record
+	return nil
+}
+
+// Deprecation returns the deprecation notice for the given facade
+// version, and whether it is deprecated at all.
+func (f *FacadeRegistry) Deprecation(name string, version int) (string, bool) {
+	record, err := f.lookup(name, version)
+	if err != nil || record.deprecation == "" {
+		return "", false
+	}
+	return record.deprecation, true
+}
+
+// Has reports whether any version of the named facade is registered
+// and available.
+func (f *FacadeRegistry) Has(name string) bool {
+	for _, record := range f.facades[name] {
+		if featureflag.Enabled(record.feature) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetFactory returns just the FacadeFactory for a given Facade name and version.
 // See also GetType for getting the type information instead of the creation factory.
 func (f *FacadeRegistry) GetFactory(name string, version int) (FacadeFactory, error) {
@@ -225,21 +281,33 @@ func (f *FacadeRegistry) GetType(name string, version int) (reflect.Type, error)
 type FacadeDescription struct {
 	Name     string
 	Versions []int
+	// Deprecations holds the deprecation notices of any of the
+	// versions that are slated for removal, by version.
+	Deprecations map[int]string
 }
 
 // descriptionFromVersions aggregates the information in a versions map into a
 // more friendly form for List().
 func descriptionFromVersions(name string, vers versions) FacadeDescription {
 	intVersions := make([]int, 0, len(vers))
+	var deprecations map[int]string
 	for version, record := range vers {
-		if featureflag.Enabled(record.feature) {
-			intVersions = append(intVersions, version)
+		if !featureflag.Enabled(record.feature) {
+			continue
+		}
+		intVersions = append(intVersions, version)
+		if record.deprecation != "" {
+			if deprecations == nil {
+				deprecations = make(map[int]string)
+			}
+			deprecations[version] = record.deprecation
 		}
 	}
 	sort.Ints(intVersions)
 	return FacadeDescription{
-		Name:     name,
-		Versions: intVersions,
+		Name:         name,
+		Versions:     intVersions,
+		Deprecations: deprecations,
 	}
 }
 
@@ -295,6 +295,50 @@ func (s *facadeRegistrySuite) TestRegisterAndListMultipleWithFeatures(c *gc.C) {
 	})
 }
 
+func (*facadeRegistrySuite) TestDeprecate(c *gc.C) {
+	r := &common.FacadeRegistry{}
+	c.Assert(r.Register("name", 0, validIdFactory, intPtrType, ""), gc.IsNil)
+	c.Assert(r.Register("name", 1, validIdFactory, intPtrType, ""), gc.IsNil)
+	_, ok := r.Deprecation("name", 0)
+	c.Check(ok, jc.IsFalse)
+
+	err := r.Deprecate("name", 0, "use name(1)")
+	c.Assert(err, jc.ErrorIsNil)
+	notice, ok := r.Deprecation("name", 0)
+	c.Check(ok, jc.IsTrue)
+	c.Check(notice, gc.Equals, "use name(1)")
+	_, ok = r.Deprecation("name", 1)
+	c.Check(ok, jc.IsFalse)
+	c.Check(r.List(), gc.DeepEquals, []common.FacadeDescription{{
+		Name:         "name",
+		Versions:     []int{0, 1},
+		Deprecations: map[int]string{0: "use name(1)"},
+	}})
+
+	// Deprecated facades are still available.
+	_, err = r.GetFactory("name", 0)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*facadeRegistrySuite) TestDeprecateErrors(c *gc.C) {
+	r := &common.FacadeRegistry{}
+	c.Assert(r.Register("name", 0, validIdFactory, intPtrType, ""), gc.IsNil)
+	err := r.Deprecate("name", 1, "gone soon")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `name\(1\) not found`)
+	err = r.Deprecate("other", 0, "gone soon")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	err = r.Deprecate("name", 0, "")
+	c.Check(err, gc.ErrorMatches, `cannot deprecate name\(0\) without a notice`)
+}
+
+func (s *facadeRegistrySuite) TestDeprecateFacadePanicsOnUnknownFacade(c *gc.C) {
+	common.SanitizeFacades(s)
+	c.Assert(func() {
+		common.DeprecateFacade("myfacade", 0, "gone soon")
+	}, gc.PanicMatches, `myfacade\(0\) not found`)
+}
+
 func (*facadeRegistrySuite) TestRegisterAlreadyPresent(c *gc.C) {
 	r := &common.FacadeRegistry{}
 	err := r.Register("name", 0, validIdFactory, intPtrType, "")
@@ -335,6 +379,18 @@ func (*facadeRegistrySuite) TestGetType(c *gc.C) {
 	c.Check(typ, gc.Equals, intPtrType)
 }
 
+func (s *facadeRegistrySuite) TestHas(c *gc.C) {
+	r := &common.FacadeRegistry{}
+	c.Assert(r.Register("name", 0, validIdFactory, intPtrType, ""), gc.IsNil)
+	c.Assert(r.Register("magic", 0, validIdFactory, intPtrType, "magic"), gc.IsNil)
+	c.Check(r.Has("name"), jc.IsTrue)
+	c.Check(r.Has("other"), jc.IsFalse)
+	c.Check(r.Has("magic"), jc.IsFalse)
+
+	s.SetFeatureFlags("magic")
+	c.Check(r.Has("magic"), jc.IsTrue)
+}
+
 func (*facadeRegistrySuite) TestDiscardHandlesNotPresent(c *gc.C) {
 	r := &common.FacadeRegistry{}
 	r.Discard("name", 1)
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"syscall"
 
@@ -67,6 +68,8 @@ func newDebugLogHandler(
 //      - go back this many lines from the end before starting to filter
 //      - has no meaning if 'replay' is true
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
+//   messageRegexp -> string - only include lines whose message matches
+//      this regular expression
 //   replay -> string - one of [true, false], if true, start the file from the start
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
@@ -150,6 +153,7 @@ type debugLogParams struct {
 	excludeEntity []string
 	includeModule []string
 	excludeModule []string
+	messageRegexp *regexp.Regexp
 }
 
 func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
@@ -194,5 +198,13 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 	params.includeModule = queryMap["includeModule"]
 	params.excludeModule = queryMap["excludeModule"]
 
+	if value := queryMap.Get("messageRegexp"); value != "" {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, errors.Errorf("messageRegexp value %q is not a valid regular expression", value)
+		}
+		params.messageRegexp = re
+	}
+
 	return params, nil
 }
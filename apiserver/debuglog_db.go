@@ -65,6 +65,9 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 		IncludeModule: reqParams.includeModule,
 		ExcludeModule: reqParams.excludeModule,
 	}
+	if reqParams.messageRegexp != nil {
+		params.MessageRegexp = reqParams.messageRegexp.String()
+	}
 	if reqParams.fromTheStart {
 		params.InitialLines = 0
 	}
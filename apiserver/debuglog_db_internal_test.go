@@ -5,6 +5,7 @@ package apiserver
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/juju/loggo"
@@ -36,6 +37,7 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 		includeModule: []string{"bar"},
 		excludeEntity: []string{"baz"},
 		excludeModule: []string{"qux"},
+		messageRegexp: regexp.MustCompile("fail(ed|ure)"),
 	}
 
 	called := false
@@ -52,6 +54,7 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 		c.Assert(params.IncludeModule, jc.DeepEquals, []string{"bar"})
 		c.Assert(params.ExcludeEntity, jc.DeepEquals, []string{"baz"})
 		c.Assert(params.ExcludeModule, jc.DeepEquals, []string{"qux"})
+		c.Assert(params.MessageRegexp, gc.Equals, "fail(ed|ure)")
 
 		return newFakeLogTailer()
 	})
@@ -71,6 +71,7 @@ type logFileLine struct {
 	agentName string
 	level     loggo.Level
 	module    string
+	message   string
 }
 
 func parseLogLine(line string) *logFileLine {
@@ -78,6 +79,7 @@ func parseLogLine(line string) *logFileLine {
 		agentTagIndex = 0
 		levelIndex    = 3
 		moduleIndex   = 4
+		messageIndex  = 6
 	)
 	fields := strings.Fields(line)
 	result := &logFileLine{
@@ -118,6 +120,9 @@ func parseLogLine(line string) *logFileLine {
 			result.module = fields[moduleIndex]
 		}
 	}
+	if parts := strings.SplitN(line, " ", messageIndex+1); len(parts) > messageIndex {
+		result.message = strings.TrimRight(parts[messageIndex], "\r\n")
+	}
 
 	return result
 }
@@ -167,7 +172,8 @@ func (stream *logFileStream) filterLine(line []byte) bool {
 	return stream.checkIncludeEntity(log) &&
 		stream.checkIncludeModule(log) &&
 		!stream.exclude(log) &&
-		stream.checkLevel(log)
+		stream.checkLevel(log) &&
+		stream.checkMessage(log)
 }
 
 // countedFilterLine checks the received line for one of the configured tags,
@@ -250,3 +256,7 @@ func (stream *logFileStream) exclude(line *logFileLine) bool {
 func (stream *logFileStream) checkLevel(line *logFileLine) bool {
 	return line.level >= stream.filterLevel
 }
+
+func (stream *logFileStream) checkMessage(line *logFileLine) bool {
+	return stream.messageRegexp == nil || stream.messageRegexp.MatchString(line.message)
+}
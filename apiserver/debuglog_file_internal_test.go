@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"time"
 
 	"github.com/juju/loggo"
@@ -32,6 +33,7 @@ func (s *debugLogFileIntSuite) TestParseLogLine(c *gc.C) {
 	c.Assert(logLine.agentTag, gc.Equals, "machine-0")
 	c.Assert(logLine.level, gc.Equals, loggo.INFO)
 	c.Assert(logLine.module, gc.Equals, "juju.cmd.jujud")
+	c.Assert(logLine.message, gc.Equals, "machine agent machine-0 start (1.17.7.1-trusty-amd64 [gc])")
 }
 
 func (s *debugLogFileIntSuite) TestParseLogLineMachineMultiline(c *gc.C) {
@@ -159,6 +161,31 @@ func (s *debugLogFileIntSuite) TestCheckExcludeModule(c *gc.C) {
 	c.Check(checkExcludeModule("unit.mysql/1", "juju", "unit"), jc.IsTrue)
 }
 
+func (s *debugLogFileIntSuite) TestCheckMessage(c *gc.C) {
+	stream := newLogFileStream(&debugLogParams{})
+	c.Check(stream.checkMessage(&logFileLine{message: "anything"}), jc.IsTrue)
+
+	stream = newLogFileStream(&debugLogParams{
+		messageRegexp: regexp.MustCompile("^hook .* failed"),
+	})
+	c.Check(stream.checkMessage(&logFileLine{message: `hook "install" failed`}), jc.IsTrue)
+	c.Check(stream.checkMessage(&logFileLine{message: `running hook "install"`}), jc.IsFalse)
+	c.Check(stream.checkMessage(&logFileLine{message: ""}), jc.IsFalse)
+}
+
+func (s *debugLogFileIntSuite) TestFilterLineMessage(c *gc.C) {
+	stream := newLogFileStream(&debugLogParams{
+		messageRegexp: regexp.MustCompile("refused$"),
+	})
+	c.Check(stream.filterLine([]byte(
+		"machine-0: date time WARNING juju foo.go:1 dial failed: connection refused\n")), jc.IsTrue)
+	c.Check(stream.filterLine([]byte(
+		"machine-0: date time WARNING juju foo.go:1 connected\n")), jc.IsFalse)
+	// The regexp is matched against the message only.
+	c.Check(stream.filterLine([]byte(
+		"machine-0: date time WARNING refused foo.go:1 connected\n")), jc.IsFalse)
+}
+
 func (s *debugLogFileIntSuite) TestFilterLine(c *gc.C) {
 	stream := newLogFileStream(&debugLogParams{
 		filterLevel:   loggo.INFO,
@@ -352,6 +379,9 @@ func (s *debugLogFileIntSuite) TestParamErrors(c *gc.C) {
 
 	_, err = readDebugLogParams(url.Values{"level": []string{"foo"}})
 	c.Assert(err, gc.ErrorMatches, `level value "foo" is not one of "TRACE", "DEBUG", "INFO", "WARNING", "ERROR"`)
+
+	_, err = readDebugLogParams(url.Values{"messageRegexp": []string{"foo("}})
+	c.Assert(err, gc.ErrorMatches, `messageRegexp value "foo\(" is not a valid regular expression`)
 }
 
 type agentMatchTest struct {
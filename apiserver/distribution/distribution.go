@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package distribution
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/distribution"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Distribution", 1, NewDistributionAPI)
+}
+
+// DistributionAPI provides access to the Distribution API facade. It
+// reports how the units of each service are distributed across hosts
+// and availability zones.
+type DistributionAPI struct {
+	st *state.State
+}
+
+// NewDistributionAPI creates a new server-side Distribution API facade.
+func NewDistributionAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*DistributionAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &DistributionAPI{st: st}, nil
+}
+
+// Report returns the distribution of the units of every service in
+// the environment, along with the unit moves that would balance each
+// service across availability zones.
+//
+// Availability zones are taken from the hardware characteristics of
+// provisioned machines, so only zones that host at least one machine
+// are considered.
+func (api *DistributionAPI) Report() (params.DistributionReport, error) {
+	var result params.DistributionReport
+	zones, machineZones, err := api.machineZones()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	services, err := api.st.AllServices()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, service := range services {
+		units, err := service.AllUnits()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		var placements []distribution.Placement
+		for _, unit := range units {
+			machineId, err := unit.AssignedMachineId()
+			if errors.IsNotAssigned(err) {
+				continue
+			} else if err != nil {
+				return result, errors.Trace(err)
+			}
+			host := state.TopParentId(machineId)
+			placements = append(placements, distribution.Placement{
+				Unit: unit.Name(),
+				Host: host,
+				Zone: machineZones[host],
+			})
+		}
+		distribution.SortPlacements(placements)
+		report := distribution.Analyse(placements, zones)
+		serviceResult := params.ServiceDistribution{
+			ServiceTag: service.Tag().String(),
+			Units:      report.Units,
+			Zones:      report.Zones,
+			Hosts:      report.Hosts,
+			Imbalanced: report.Imbalanced,
+			SingleHost: report.SingleHost,
+			SingleZone: report.SingleZone,
+		}
+		for _, move := range report.Moves {
+			serviceResult.Moves = append(serviceResult.Moves, params.UnitMove{
+				UnitTag:  names.NewUnitTag(move.Unit).String(),
+				FromZone: move.FromZone,
+				ToZone:   move.ToZone,
+			})
+		}
+		result.Services = append(result.Services, serviceResult)
+	}
+	return result, nil
+}
+
+// machineZones returns the sorted names of the availability zones of
+// all top-level machines, and a map of machine ids to their zones.
+func (api *DistributionAPI) machineZones() ([]string, map[string]string, error) {
+	machines, err := api.st.AllMachines()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	zones := set.NewStrings()
+	machineZones := make(map[string]string)
+	for _, machine := range machines {
+		if machine.IsContainer() {
+			continue
+		}
+		hc, err := machine.HardwareCharacteristics()
+		if errors.IsNotFound(err) {
+			// Not provisioned yet.
+			continue
+		} else if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if hc.AvailabilityZone == nil || *hc.AvailabilityZone == "" {
+			continue
+		}
+		zones.Add(*hc.AvailabilityZone)
+		machineZones[machine.Id()] = *hc.AvailabilityZone
+	}
+	sorted := zones.Values()
+	sort.Strings(sorted)
+	return sorted, machineZones, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package distribution_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/distribution"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type distributionSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *distribution.DistributionAPI
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&distributionSuite{})
+
+func (s *distributionSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = distribution.NewDistributionAPI(s.State, common.NewResources(), s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *distributionSuite) TestNewDistributionAPIRequiresClient(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = names.NewMachineTag("0")
+	api, err := distribution.NewDistributionAPI(s.State, nil, anAuthorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *distributionSuite) makeMachine(c *gc.C, zone string) *state.Machine {
+	return s.Factory.MakeMachine(c, &factory.MachineParams{
+		Characteristics: &instance.HardwareCharacteristics{
+			AvailabilityZone: &zone,
+		},
+	})
+}
+
+func (s *distributionSuite) TestReport(c *gc.C) {
+	m0 := s.makeMachine(c, "az1")
+	m1 := s.makeMachine(c, "az1")
+	s.makeMachine(c, "az2")
+	service := s.Factory.MakeService(c, &factory.ServiceParams{Name: "wordpress"})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: service, Machine: m0})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: service, Machine: m1})
+
+	result, err := s.api.Report()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.DistributionReport{
+		Services: []params.ServiceDistribution{{
+			ServiceTag: "service-wordpress",
+			Units:      2,
+			Zones:      map[string]int{"az1": 2, "az2": 0},
+			Hosts:      map[string]int{m0.Id(): 1, m1.Id(): 1},
+			Imbalanced: true,
+			SingleZone: true,
+			Moves: []params.UnitMove{{
+				UnitTag:  "unit-wordpress-1",
+				FromZone: "az1",
+				ToZone:   "az2",
+			}},
+		}},
+	})
+}
+
+func (s *distributionSuite) TestReportSingleHost(c *gc.C) {
+	m0 := s.makeMachine(c, "az1")
+	// Units in containers are considered to be on the
+	// container's host.
+	container0 := s.Factory.MakeMachineNested(c, m0.Id(), nil)
+	container1 := s.Factory.MakeMachineNested(c, m0.Id(), nil)
+	service := s.Factory.MakeService(c, &factory.ServiceParams{Name: "wordpress"})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: service, Machine: container0})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: service, Machine: container1})
+
+	result, err := s.api.Report()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Services, gc.HasLen, 1)
+	c.Assert(result.Services[0].Hosts, jc.DeepEquals, map[string]int{m0.Id(): 2})
+	c.Assert(result.Services[0].SingleHost, jc.IsTrue)
+	c.Assert(result.Services[0].Imbalanced, jc.IsFalse)
+}
@@ -292,6 +292,38 @@ func (f *FirewallerAPI) GetAssignedMachine(args params.Entities) (params.StringR
 	return result, nil
 }
 
+// WatchFirewallWhitelists returns a NotifyWatcher that notifies of
+// changes to the whitelists of well-known services.
+func (f *FirewallerAPI) WatchFirewallWhitelists() (params.NotifyWatchResult, error) {
+	watch := f.st.WatchFirewallRules()
+	// Consume the initial event.
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: f.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{}, watcher.EnsureErr(watch)
+}
+
+// FirewallWhitelists returns the whitelisted source address ranges of
+// each well-known service that has a whitelist.
+func (f *FirewallerAPI) FirewallWhitelists() (params.FirewallWhitelists, error) {
+	rules, err := f.st.AllFirewallRules()
+	if err != nil {
+		return params.FirewallWhitelists{}, errors.Trace(err)
+	}
+	result := params.FirewallWhitelists{
+		Whitelists: make([]params.FirewallWhitelist, len(rules)),
+	}
+	for i, rule := range rules {
+		result.Whitelists[i] = params.FirewallWhitelist{
+			KnownService:   string(rule.WellKnownService),
+			WhitelistCIDRs: rule.WhitelistCIDRs,
+		}
+	}
+	return result, nil
+}
+
 func (f *FirewallerAPI) getEntity(canAccess common.AuthFunc, tag names.Tag) (state.Entity, error) {
 	if !canAccess(tag) {
 		return nil, common.ErrPerm
@@ -213,3 +213,45 @@ func (s *firewallerSuite) TestGetMachineActiveNetworks(c *gc.C) {
 		},
 	})
 }
+
+func (s *firewallerSuite) TestWatchFirewallWhitelists(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	result, err := s.firewaller.WatchFirewallWhitelists()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: network.SSHRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *firewallerSuite) TestFirewallWhitelists(c *gc.C) {
+	result, err := s.firewaller.FirewallWhitelists()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Whitelists, gc.HasLen, 0)
+
+	err = s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: network.SSHRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.firewaller.FirewallWhitelists()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.FirewallWhitelists{
+		Whitelists: []params.FirewallWhitelist{{
+			KnownService:   "ssh",
+			WhitelistCIDRs: []string{"10.0.0.0/8"},
+		}},
+	})
+}
@@ -0,0 +1,324 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package firewallrules provides the API server facade used to inspect
+// the firewall rules Juju expects to be applied in an environment,
+// compare them with those actually applied by the provider, and
+// reconcile any drift between the two.
+package firewallrules
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.firewallrules")
+
+func init() {
+	common.RegisterStandardFacade("FirewallRules", 1, NewFirewallRulesAPI)
+}
+
+// FirewallRulesAPI implements the FirewallRules facade.
+type FirewallRulesAPI struct {
+	st    *state.State
+	check *common.BlockChecker
+}
+
+// NewFirewallRulesAPI creates a new server-side FirewallRules facade.
+func NewFirewallRulesAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*FirewallRulesAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &FirewallRulesAPI{
+		st:    st,
+		check: common.NewBlockChecker(st),
+	}, nil
+}
+
+// ListFirewallRules returns, for each provisioned machine, the port
+// ranges Juju expects to be open and those the provider actually has
+// open. When the environment's firewall mode is "global", a single
+// result describes the environment-wide rules.
+func (api *FirewallRulesAPI) ListFirewallRules() (params.FirewallRulesResults, error) {
+	return api.firewallRules(false)
+}
+
+// SyncFirewallRules opens and closes port ranges in the provider so
+// that its rules match those expected by Juju, and returns the rules
+// as they are afterwards.
+func (api *FirewallRulesAPI) SyncFirewallRules() (params.FirewallRulesResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.FirewallRulesResults{}, errors.Trace(err)
+	}
+	return api.firewallRules(true)
+}
+
+// SetFirewallWhitelists restricts access to each given well-known
+// service to its whitelisted source address ranges. The firewaller
+// applies the whitelists to the provider's firewall.
+func (api *FirewallRulesAPI) SetFirewallWhitelists(args params.FirewallWhitelists) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Whitelists)),
+	}
+	for i, arg := range args.Whitelists {
+		err := api.st.SaveFirewallRule(state.FirewallRule{
+			WellKnownService: network.WellKnownServiceType(arg.KnownService),
+			WhitelistCIDRs:   arg.WhitelistCIDRs,
+		})
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// ListFirewallWhitelists returns the whitelists of the well-known
+// services that have one.
+func (api *FirewallRulesAPI) ListFirewallWhitelists() (params.FirewallWhitelists, error) {
+	rules, err := api.st.AllFirewallRules()
+	if err != nil {
+		return params.FirewallWhitelists{}, errors.Trace(err)
+	}
+	result := params.FirewallWhitelists{
+		Whitelists: make([]params.FirewallWhitelist, len(rules)),
+	}
+	for i, rule := range rules {
+		result.Whitelists[i] = params.FirewallWhitelist{
+			KnownService:   string(rule.WellKnownService),
+			WhitelistCIDRs: rule.WhitelistCIDRs,
+		}
+	}
+	return result, nil
+}
+
+// firewallRules compares the expected and actual firewall rules,
+// reconciling them first if sync is true.
+func (api *FirewallRulesAPI) firewallRules(sync bool) (params.FirewallRulesResults, error) {
+	var results params.FirewallRulesResults
+	cfg, err := api.st.EnvironConfig()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	mode := cfg.FirewallMode()
+	if mode == config.FwNone || mode == config.FwMachine {
+		return results, errors.NotSupportedf("firewall-mode %q", mode)
+	}
+	env, err := environs.New(cfg)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	expected, err := api.expectedPorts()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+
+	results.Mode = mode
+	if mode == config.FwGlobal {
+		var all []network.PortRange
+		seen := make(map[network.PortRange]bool)
+		for _, machine := range expected {
+			for _, portRange := range machine.ports {
+				if !seen[portRange] {
+					seen[portRange] = true
+					all = append(all, portRange)
+				}
+			}
+		}
+		result := params.FirewallRules{Expected: fromPortRanges(all)}
+		actual, err := reconcile(globalPorter{env}, all, sync)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Actual = fromPortRanges(actual)
+		}
+		results.Results = []params.FirewallRules{result}
+		return results, nil
+	}
+
+	for _, machine := range expected {
+		result := params.FirewallRules{
+			MachineId:  machine.id,
+			InstanceId: string(machine.instanceId),
+			Expected:   fromPortRanges(machine.ports),
+		}
+		actual, err := api.reconcileInstance(env, machine, sync)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Actual = fromPortRanges(actual)
+		}
+		results.Results = append(results.Results, result)
+	}
+	return results, nil
+}
+
+// machinePorts holds the port ranges expected to be open on a machine.
+type machinePorts struct {
+	id         string
+	instanceId instance.Id
+	ports      []network.PortRange
+}
+
+// expectedPorts returns the port ranges expected to be open on each
+// provisioned machine. As in the firewaller, a port range is expected
+// to be open if it was opened by a unit of an exposed service.
+func (api *FirewallRulesAPI) expectedPorts() ([]machinePorts, error) {
+	machines, err := api.st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	exposed := make(map[string]bool)
+	var result []machinePorts
+	for _, machine := range machines {
+		instanceId, err := machine.InstanceId()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		allPorts, err := machine.AllPorts()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		mp := machinePorts{id: machine.Id(), instanceId: instanceId}
+		for _, ports := range allPorts {
+			for portRange, unitName := range ports.AllPortRanges() {
+				isExposed, err := api.serviceExposed(exposed, unitName)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				if isExposed {
+					mp.ports = append(mp.ports, portRange)
+				}
+			}
+		}
+		network.SortPortRanges(mp.ports)
+		result = append(result, mp)
+	}
+	return result, nil
+}
+
+// serviceExposed reports whether the service of the named unit is
+// exposed, caching the result in exposed.
+func (api *FirewallRulesAPI) serviceExposed(exposed map[string]bool, unitName string) (bool, error) {
+	unit, err := api.st.Unit(unitName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	serviceName := unit.ServiceName()
+	if isExposed, ok := exposed[serviceName]; ok {
+		return isExposed, nil
+	}
+	service, err := unit.Service()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	exposed[serviceName] = service.IsExposed()
+	return exposed[serviceName], nil
+}
+
+// reconcileInstance compares, and optionally reconciles, the port
+// ranges open on the machine's instance.
+func (api *FirewallRulesAPI) reconcileInstance(env environs.Environ, machine machinePorts, sync bool) ([]network.PortRange, error) {
+	instances, err := env.Instances([]instance.Id{machine.instanceId})
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get instance %q", machine.instanceId)
+	}
+	return reconcile(instancePorter{instances[0], machine.id}, machine.ports, sync)
+}
+
+// porter abstracts over environment-wide and per-instance firewall rules.
+type porter interface {
+	OpenPorts([]network.PortRange) error
+	ClosePorts([]network.PortRange) error
+	Ports() ([]network.PortRange, error)
+}
+
+type globalPorter struct {
+	env environs.Environ
+}
+
+func (p globalPorter) OpenPorts(ports []network.PortRange) error  { return p.env.OpenPorts(ports) }
+func (p globalPorter) ClosePorts(ports []network.PortRange) error { return p.env.ClosePorts(ports) }
+func (p globalPorter) Ports() ([]network.PortRange, error)        { return p.env.Ports() }
+
+type instancePorter struct {
+	inst      instance.Instance
+	machineId string
+}
+
+func (p instancePorter) OpenPorts(ports []network.PortRange) error {
+	return p.inst.OpenPorts(p.machineId, ports)
+}
+
+func (p instancePorter) ClosePorts(ports []network.PortRange) error {
+	return p.inst.ClosePorts(p.machineId, ports)
+}
+
+func (p instancePorter) Ports() ([]network.PortRange, error) {
+	return p.inst.Ports(p.machineId)
+}
+
+// reconcile returns the port ranges actually open. If sync is true,
+// any expected port ranges that are not open are opened, and any
+// open port ranges that are not expected are closed, first.
+func reconcile(p porter, expected []network.PortRange, sync bool) ([]network.PortRange, error) {
+	actual, err := p.Ports()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get open ports")
+	}
+	if sync {
+		toOpen := diffRanges(expected, actual)
+		toClose := diffRanges(actual, expected)
+		if len(toOpen) > 0 {
+			logger.Infof("opening port ranges %v", toOpen)
+			if err := p.OpenPorts(toOpen); err != nil {
+				return nil, errors.Annotate(err, "cannot open ports")
+			}
+		}
+		if len(toClose) > 0 {
+			logger.Infof("closing port ranges %v", toClose)
+			if err := p.ClosePorts(toClose); err != nil {
+				return nil, errors.Annotate(err, "cannot close ports")
+			}
+		}
+		if actual, err = p.Ports(); err != nil {
+			return nil, errors.Annotate(err, "cannot get open ports")
+		}
+	}
+	network.SortPortRanges(actual)
+	return actual, nil
+}
+
+// diffRanges returns the port ranges in A that are not in B.
+func diffRanges(A, B []network.PortRange) (missing []network.PortRange) {
+next:
+	for _, a := range A {
+		for _, b := range B {
+			if a == b {
+				continue next
+			}
+		}
+		missing = append(missing, a)
+	}
+	return
+}
+
+func fromPortRanges(ports []network.PortRange) []params.PortRange {
+	result := make([]params.PortRange, len(ports))
+	for i, portRange := range ports {
+		result[i] = params.FromNetworkPortRange(portRange)
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewallrules_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/firewallrules"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type firewallRulesSuite struct {
+	testing.JujuConnSuite
+	commontesting.BlockHelper
+
+	api   *firewallrules.FirewallRulesAPI
+	inst0 instance.Instance
+	inst1 instance.Instance
+}
+
+var _ = gc.Suite(&firewallRulesSuite{})
+
+func (s *firewallRulesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+
+	authorizer := apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)}
+	var err error
+	s.api, err = firewallrules.NewFirewallRulesAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Machine 0 hosts a unit of an exposed service, which has port
+	// 80 open; machine 1 hosts a unit of an unexposed service, which
+	// has port 3306 open.
+	s.inst0 = s.addUnitWithPort(c, "wordpress", true, 80)
+	s.inst1 = s.addUnitWithPort(c, "mysql", false, 3306)
+}
+
+func (s *firewallRulesSuite) addUnitWithPort(c *gc.C, serviceName string, exposed bool, port int) instance.Instance {
+	service := s.AddTestingService(c, serviceName, s.AddTestingCharm(c, serviceName))
+	if exposed {
+		c.Assert(service.SetExposed(), jc.ErrorIsNil)
+	}
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.AssignToMachine(m), jc.ErrorIsNil)
+	inst, md := testing.AssertStartInstance(c, s.Environ, m.Id())
+	c.Assert(m.SetProvisioned(inst.Id(), "fake_nonce", md), jc.ErrorIsNil)
+	c.Assert(unit.OpenPort("tcp", port), jc.ErrorIsNil)
+	return inst
+}
+
+func (s *firewallRulesSuite) TestNewAPIRequiresClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := firewallrules.NewFirewallRulesAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *firewallRulesSuite) TestListFirewallRules(c *gc.C) {
+	err := s.inst0.OpenPorts("0", []network.PortRange{network.MustParsePortRange("8080/tcp")})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.FirewallRulesResults{
+		Mode: "instance",
+		Results: []params.FirewallRules{{
+			MachineId:  "0",
+			InstanceId: string(s.inst0.Id()),
+			Expected:   []params.PortRange{{80, 80, "tcp"}},
+			Actual:     []params.PortRange{{8080, 8080, "tcp"}},
+		}, {
+			MachineId:  "1",
+			InstanceId: string(s.inst1.Id()),
+			Expected:   []params.PortRange{},
+			Actual:     []params.PortRange{},
+		}},
+	})
+}
+
+func (s *firewallRulesSuite) TestSyncFirewallRules(c *gc.C) {
+	err := s.inst0.OpenPorts("0", []network.PortRange{network.MustParsePortRange("8080/tcp")})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.SyncFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Actual, jc.DeepEquals, []params.PortRange{{80, 80, "tcp"}})
+
+	ports, err := s.inst0.Ports("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, jc.DeepEquals, []network.PortRange{network.MustParsePortRange("80/tcp")})
+}
+
+func (s *firewallRulesSuite) TestSyncFirewallRulesBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestSyncFirewallRulesBlocked")
+	_, err := s.api.SyncFirewallRules()
+	s.AssertBlocked(c, err, "TestSyncFirewallRulesBlocked")
+}
+
+func (s *firewallRulesSuite) TestSetFirewallWhitelists(c *gc.C) {
+	results, err := s.api.SetFirewallWhitelists(params.FirewallWhitelists{
+		Whitelists: []params.FirewallWhitelist{{
+			KnownService:   "ssh",
+			WhitelistCIDRs: []string{"10.0.0.0/8"},
+		}, {
+			KnownService:   "http",
+			WhitelistCIDRs: []string{"10.0.0.0/8"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot save firewall rule for "http": well known service type "http" not valid`)
+
+	rule, err := s.State.FirewallRule(network.SSHRule)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule.WhitelistCIDRs, jc.DeepEquals, []string{"10.0.0.0/8"})
+}
+
+func (s *firewallRulesSuite) TestSetFirewallWhitelistsBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestSetFirewallWhitelistsBlocked")
+	_, err := s.api.SetFirewallWhitelists(params.FirewallWhitelists{})
+	s.AssertBlocked(c, err, "TestSetFirewallWhitelistsBlocked")
+}
+
+func (s *firewallRulesSuite) TestListFirewallWhitelists(c *gc.C) {
+	err := s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: network.JujuControllerRule,
+		WhitelistCIDRs:   []string{"192.168.0.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.ListFirewallWhitelists()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.FirewallWhitelists{
+		Whitelists: []params.FirewallWhitelist{{
+			KnownService:   "juju-controller",
+			WhitelistCIDRs: []string{"192.168.0.0/16"},
+		}},
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The hostkeyreporter package implements the API interface used by
+// the hostkeyreporter worker to record a machine's SSH host keys.
+package hostkeyreporter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("HostKeyReporter", 1, NewHostKeyReporterAPI)
+}
+
+// HostKeyReporterAPI provides access to the HostKeyReporter API facade.
+type HostKeyReporterAPI struct {
+	st          stateInterface
+	getAuthFunc common.GetAuthFunc
+}
+
+var getState = func(st *state.State) stateInterface {
+	return st
+}
+
+// NewHostKeyReporterAPI creates a new server-side HostKeyReporter API
+// facade.
+func NewHostKeyReporterAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*HostKeyReporterAPI, error) {
+
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+
+	authEntityTag := authorizer.GetAuthTag()
+	getAuthFunc := func() (common.AuthFunc, error) {
+		return func(tag names.Tag) bool {
+			// A machine agent can only report its own keys.
+			return tag == authEntityTag
+		}, nil
+	}
+
+	return &HostKeyReporterAPI{
+		st:          getState(st),
+		getAuthFunc: getAuthFunc,
+	}, nil
+}
+
+// ReportKeys records the SSH host keys of the given machines.
+func (api *HostKeyReporterAPI) ReportKeys(args params.SSHHostKeySet) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.EntityKeys)),
+	}
+	canAccess, err := api.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.EntityKeys {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			err = common.ErrPerm
+		} else {
+			err = api.st.SetSSHHostKeys(tag, state.SSHHostKeys(arg.PublicKeys))
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
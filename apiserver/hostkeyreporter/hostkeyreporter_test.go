@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter_test
+
+import (
+	"errors"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/hostkeyreporter"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&HostKeyReporterSuite{})
+
+type HostKeyReporterSuite struct {
+	coretesting.BaseSuite
+	authorizer *apiservertesting.FakeAuthorizer
+	st         *mockState
+	api        *hostkeyreporter.HostKeyReporterAPI
+}
+
+func (s *HostKeyReporterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	s.st = &mockState{keys: make(map[string]state.SSHHostKeys)}
+	hostkeyreporter.PatchState(s, s.st)
+
+	var err error
+	s.api, err = hostkeyreporter.NewHostKeyReporterAPI(nil, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *HostKeyReporterSuite) TestNewHostKeyReporterAPINonMachine(c *gc.C) {
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewUnitTag("mysql/0")}
+	_, err := hostkeyreporter.NewHostKeyReporterAPI(nil, nil, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *HostKeyReporterSuite) TestReportKeys(c *gc.C) {
+	results, err := s.api.ReportKeys(params.SSHHostKeySet{
+		EntityKeys: []params.SSHHostKeys{{
+			Tag:        "machine-0",
+			PublicKeys: []string{"rsa foo", "dsa bar"},
+		}, {
+			Tag:        "machine-1",
+			PublicKeys: []string{"rsa baz"},
+		}, {
+			Tag:        "unit-mysql-0",
+			PublicKeys: []string{"rsa qux"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+	c.Assert(s.st.keys, jc.DeepEquals, map[string]state.SSHHostKeys{
+		"0": {"rsa foo", "dsa bar"},
+	})
+}
+
+func (s *HostKeyReporterSuite) TestReportKeysError(c *gc.C) {
+	s.st.err = errors.New("boom")
+	results, err := s.api.ReportKeys(params.SSHHostKeySet{
+		EntityKeys: []params.SSHHostKeys{{
+			Tag:        "machine-0",
+			PublicKeys: []string{"rsa foo"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: "boom"}},
+		},
+	})
+}
+
+type mockState struct {
+	keys map[string]state.SSHHostKeys
+	err  error
+}
+
+func (st *mockState) SetSSHHostKeys(tag names.MachineTag, keys state.SSHHostKeys) error {
+	if st.err != nil {
+		return st.err
+	}
+	st.keys[tag.Id()] = keys
+	return nil
+}
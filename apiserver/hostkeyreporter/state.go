@@ -0,0 +1,14 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+)
+
+type stateInterface interface {
+	SetSSHHostKeys(names.MachineTag, state.SSHHostKeys) error
+}
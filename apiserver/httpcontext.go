@@ -81,7 +81,9 @@ func (ctxt *httpContext) stateForRequestAuthenticated(r *http.Request) (*state.S
 }
 
 // stateForRequestAuthenticatedUser is like stateForRequestAuthenticated
-// except that it also verifies that the authenticated entity is a user.
+// except that it also verifies that the authenticated entity is a user,
+// and that users with read access to the environment only make GET
+// requests.
 func (ctxt *httpContext) stateForRequestAuthenticatedUser(r *http.Request) (*state.State, state.Entity, error) {
 	st, entity, err := ctxt.stateForRequestAuthenticated(r)
 	if err != nil {
@@ -89,12 +91,31 @@ func (ctxt *httpContext) stateForRequestAuthenticatedUser(r *http.Request) (*sta
 	}
 	switch entity.Tag().(type) {
 	case names.UserTag:
+		if r.Method != "GET" && r.Method != "HEAD" {
+			if err := checkWriteAccess(entity); err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+		}
 		return st, entity, nil
 	default:
 		return nil, nil, errors.Trace(common.ErrBadCreds)
 	}
 }
 
+// checkWriteAccess returns common.ErrPerm unless the given user entity
+// has write or admin access to the environment.
+func checkWriteAccess(entity state.Entity) error {
+	envUser, ok := entity.(*environmentUserEntity)
+	if !ok {
+		return common.ErrPerm
+	}
+	switch envUser.envUser.Access() {
+	case state.EnvAdminAccess, state.EnvWriteAccess:
+		return nil
+	}
+	return common.ErrPerm
+}
+
 // stateForRequestAuthenticatedUser is like stateForRequestAuthenticated
 // except that it also verifies that the authenticated entity is a user.
 func (ctxt *httpContext) stateForRequestAuthenticatedAgent(r *http.Request) (*state.State, state.Entity, error) {
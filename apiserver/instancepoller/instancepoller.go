@@ -15,12 +15,14 @@ import (
 )
 
 func init() {
-	common.RegisterStandardFacade("InstancePoller", 1, NewInstancePollerAPI)
+	common.RegisterStandardFacade("InstancePoller", 1, NewInstancePollerAPIV1)
+	common.RegisterStandardFacade("InstancePoller", 2, NewInstancePollerAPI)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.instancepoller")
 
-// InstancePollerAPI provides access to the InstancePoller API facade.
+// InstancePollerAPI provides access to version 2 of the InstancePoller
+// API facade.
 type InstancePollerAPI struct {
 	*common.LifeGetter
 	*common.EnvironWatcher
@@ -91,6 +93,44 @@ func NewInstancePollerAPI(
 	}, nil
 }
 
+// InstancePollerAPIV1 provides access to version 1 of the
+// InstancePoller API facade, whose InstanceStatus reports only the
+// status of each instance, without a message.
+type InstancePollerAPIV1 struct {
+	*InstancePollerAPI
+}
+
+// NewInstancePollerAPIV1 creates a new server-side InstancePoller API
+// facade, version 1.
+func NewInstancePollerAPIV1(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*InstancePollerAPIV1, error) {
+	api, err := NewInstancePollerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &InstancePollerAPIV1{api}, nil
+}
+
+// InstanceStatus returns the instance status for each given entity.
+// Only machine tags are accepted.
+func (a *InstancePollerAPIV1) InstanceStatus(args params.Entities) (params.StringResults, error) {
+	statuses, err := a.InstancePollerAPI.InstanceStatus(args)
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(statuses.Results)),
+	}
+	for i, status := range statuses.Results {
+		result.Results[i].Result = string(status.Status)
+		result.Results[i].Error = status.Error
+	}
+	return result, nil
+}
+
 func (a *InstancePollerAPI) getOneMachine(tag string, canAccess common.AuthFunc) (StateMachine, error) {
 	machineTag, err := names.ParseMachineTag(tag)
 	if err != nil {
@@ -156,9 +196,9 @@ func (a *InstancePollerAPI) SetProviderAddresses(args params.SetMachinesAddresse
 
 // InstanceStatus returns the instance status for each given entity.
 // Only machine tags are accepted.
-func (a *InstancePollerAPI) InstanceStatus(args params.Entities) (params.StringResults, error) {
-	result := params.StringResults{
-		Results: make([]params.StringResult, len(args.Entities)),
+func (a *InstancePollerAPI) InstanceStatus(args params.Entities) (params.StatusResults, error) {
+	result := params.StatusResults{
+		Results: make([]params.StatusResult, len(args.Entities)),
 	}
 	canAccess, err := a.accessMachine()
 	if err != nil {
@@ -167,7 +207,11 @@ func (a *InstancePollerAPI) InstanceStatus(args params.Entities) (params.StringR
 	for i, arg := range args.Entities {
 		machine, err := a.getOneMachine(arg.Tag, canAccess)
 		if err == nil {
-			result.Results[i].Result, err = machine.InstanceStatus()
+			var statusInfo state.StatusInfo
+			statusInfo, err = machine.InstanceStatus()
+			result.Results[i].Status = params.Status(statusInfo.Status)
+			result.Results[i].Info = statusInfo.Message
+			result.Results[i].Since = statusInfo.Since
 		}
 		result.Results[i].Error = common.ServerError(err)
 	}
@@ -187,7 +231,7 @@ func (a *InstancePollerAPI) SetInstanceStatus(args params.SetInstancesStatus) (p
 	for i, arg := range args.Entities {
 		machine, err := a.getOneMachine(arg.Tag, canAccess)
 		if err == nil {
-			err = machine.SetInstanceStatus(arg.Status)
+			err = machine.SetInstanceStatus(arg.Status, arg.Info)
 		}
 		result.Results[i].Error = common.ServerError(err)
 	}
@@ -320,8 +320,8 @@ func (s *InstancePollerSuite) TestInstanceIdFailure(c *gc.C) {
 
 	result, err := s.api.InstanceId(s.machineEntities)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(result, jc.DeepEquals, params.StringResults{
-		Results: []params.StringResult{
+	c.Assert(result, jc.DeepEquals, params.StatusResults{
+		Results: []params.StatusResult{
 			{Error: apiservertesting.ServerError("pow!")},
 			{Error: apiservertesting.ServerError("FAIL")},
 			{Error: apiservertesting.NotProvisionedError("42")},
@@ -528,15 +528,21 @@ func (s *InstancePollerSuite) TestSetProviderAddressesFailure(c *gc.C) {
 }
 
 func (s *InstancePollerSuite) TestInstanceStatusSuccess(c *gc.C) {
-	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: "foo"})
-	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: ""})
+	now := time.Now()
+	s1 := state.StatusInfo{
+		Status:  "foo",
+		Message: "bar",
+		Since:   &now,
+	}
+	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: s1})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: state.StatusInfo{}})
 
 	result, err := s.api.InstanceStatus(s.mixedEntities)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(result, jc.DeepEquals, params.StringResults{
-		Results: []params.StringResult{
-			{Result: "foo"},
-			{Result: ""},
+	c.Assert(result, jc.DeepEquals, params.StatusResults{
+		Results: []params.StatusResult{
+			{Status: "foo", Info: "bar", Since: s1.Since},
+			{Status: "", Info: "", Since: nil},
 			{Error: apiservertesting.NotFoundError("machine 42")},
 			{Error: apiservertesting.ServerError(`"service-unknown" is not a valid machine tag`)},
 			{Error: apiservertesting.ServerError(`"invalid-tag" is not a valid tag`)},
@@ -560,13 +566,13 @@ func (s *InstancePollerSuite) TestInstanceStatusFailure(c *gc.C) {
 		errors.New("FAIL"),                   // m2.InstanceStatus()
 		errors.NotProvisionedf("machine 42"), // FindEntity("3") (ensure wrapping is preserved)
 	)
-	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: "foo"})
-	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: ""})
+	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: state.StatusInfo{Status: "foo"}})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: state.StatusInfo{}})
 
 	result, err := s.api.InstanceStatus(s.machineEntities)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(result, jc.DeepEquals, params.StringResults{
-		Results: []params.StringResult{
+	c.Assert(result, jc.DeepEquals, params.StatusResults{
+		Results: []params.StatusResult{
 			{Error: apiservertesting.ServerError("pow!")},
 			{Error: apiservertesting.ServerError("FAIL")},
 			{Error: apiservertesting.NotProvisionedError("42")},
@@ -579,14 +585,36 @@ func (s *InstancePollerSuite) TestInstanceStatusFailure(c *gc.C) {
 	s.st.CheckFindEntityCall(c, 3, "3")
 }
 
+func (s *InstancePollerSuite) TestInstanceStatusV1(c *gc.C) {
+	now := time.Now()
+	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: state.StatusInfo{
+		Status:  "foo",
+		Message: "bar",
+		Since:   &now,
+	}})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: state.StatusInfo{}})
+
+	api, err := instancepoller.NewInstancePollerAPIV1(nil, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.InstanceStatus(s.machineEntities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: "foo"},
+			{Result: ""},
+			{Error: apiservertesting.NotFoundError("machine 3")},
+		}},
+	)
+}
+
 func (s *InstancePollerSuite) TestSetInstanceStatusSuccess(c *gc.C) {
-	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: "foo"})
-	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: ""})
+	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: state.StatusInfo{Status: "foo"}})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: state.StatusInfo{}})
 
 	result, err := s.api.SetInstanceStatus(params.SetInstancesStatus{
 		Entities: []params.InstanceStatus{
 			{Tag: "machine-1", Status: ""},
-			{Tag: "machine-2", Status: "new status"},
+			{Tag: "machine-2", Status: "new status", Info: "new message"},
 			{Tag: "machine-42"},
 			{Tag: "service-unknown"},
 			{Tag: "invalid-tag"},
@@ -599,9 +627,9 @@ func (s *InstancePollerSuite) TestSetInstanceStatusSuccess(c *gc.C) {
 	c.Assert(result, jc.DeepEquals, s.mixedErrorResults)
 
 	s.st.CheckFindEntityCall(c, 0, "1")
-	s.st.CheckCall(c, 1, "SetInstanceStatus", "")
+	s.st.CheckCall(c, 1, "SetInstanceStatus", "", "")
 	s.st.CheckFindEntityCall(c, 2, "2")
-	s.st.CheckCall(c, 3, "SetInstanceStatus", "new status")
+	s.st.CheckCall(c, 3, "SetInstanceStatus", "new status", "new message")
 	s.st.CheckFindEntityCall(c, 4, "42")
 
 	// Ensure machines were updated.
@@ -609,13 +637,16 @@ func (s *InstancePollerSuite) TestSetInstanceStatusSuccess(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	setStatus, err := machine.InstanceStatus()
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(setStatus, gc.Equals, "")
+	c.Assert(setStatus, jc.DeepEquals, state.StatusInfo{})
 
 	machine, err = s.st.Machine("2")
 	c.Assert(err, jc.ErrorIsNil)
 	setStatus, err = machine.InstanceStatus()
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(setStatus, gc.Equals, "new status")
+	c.Assert(setStatus, jc.DeepEquals, state.StatusInfo{
+		Status:  "new status",
+		Message: "new message",
+	})
 }
 
 func (s *InstancePollerSuite) TestSetInstanceStatusFailure(c *gc.C) {
@@ -625,8 +656,8 @@ func (s *InstancePollerSuite) TestSetInstanceStatusFailure(c *gc.C) {
 		errors.New("FAIL"),                   // m2.SetInstanceStatus()
 		errors.NotProvisionedf("machine 42"), // FindEntity("3") (ensure wrapping is preserved)
 	)
-	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: "foo"})
-	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: ""})
+	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: state.StatusInfo{Status: "foo"}})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: state.StatusInfo{}})
 
 	result, err := s.api.SetInstanceStatus(params.SetInstancesStatus{
 		Entities: []params.InstanceStatus{
@@ -640,7 +671,7 @@ func (s *InstancePollerSuite) TestSetInstanceStatusFailure(c *gc.C) {
 
 	s.st.CheckFindEntityCall(c, 0, "1")
 	s.st.CheckFindEntityCall(c, 1, "2")
-	s.st.CheckCall(c, 2, "SetInstanceStatus", "invalid")
+	s.st.CheckCall(c, 2, "SetInstanceStatus", "invalid", "")
 	s.st.CheckFindEntityCall(c, 3, "3")
 }
 
@@ -207,7 +207,7 @@ type machineInfo struct {
 	id                string
 	instanceId        instance.Id
 	status            state.StatusInfo
-	instanceStatus    string
+	instanceStatus    state.StatusInfo
 	providerAddresses []network.Address
 	life              state.Life
 	isManual          bool
@@ -264,27 +264,30 @@ func (m *mockMachine) SetProviderAddresses(addrs ...network.Address) error {
 }
 
 // InstanceStatus implements StateMachine.
-func (m *mockMachine) InstanceStatus() (string, error) {
+func (m *mockMachine) InstanceStatus() (state.StatusInfo, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	m.MethodCall(m, "InstanceStatus")
 	if err := m.NextErr(); err != nil {
-		return "", err
+		return state.StatusInfo{}, err
 	}
 	return m.instanceStatus, nil
 }
 
 // SetInstanceStatus implements StateMachine.
-func (m *mockMachine) SetInstanceStatus(status string) error {
+func (m *mockMachine) SetInstanceStatus(status, message string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.MethodCall(m, "SetInstanceStatus", status)
+	m.MethodCall(m, "SetInstanceStatus", status, message)
 	if err := m.NextErr(); err != nil {
 		return err
 	}
-	m.instanceStatus = status
+	m.instanceStatus = state.StatusInfo{
+		Status:  state.Status(status),
+		Message: message,
+	}
 	return nil
 }
 
@@ -0,0 +1,172 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machinefirewaller implements the API interface used by the
+// machine firewaller worker, which programs the host firewall of a
+// machine when the environment's firewall-mode is "machine".
+package machinefirewaller
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("MachineFirewaller", 1, NewMachineFirewallerAPI)
+}
+
+// MachineFirewallerAPI provides access to the MachineFirewaller API
+// facade.
+type MachineFirewallerAPI struct {
+	st          *state.State
+	resources   *common.Resources
+	getAuthFunc common.GetAuthFunc
+}
+
+// NewMachineFirewallerAPI creates a new server-side MachineFirewaller
+// API facade.
+func NewMachineFirewallerAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*MachineFirewallerAPI, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	getAuthFunc := func() (common.AuthFunc, error) {
+		// A machine agent can only access its own machine.
+		return authorizer.AuthOwner, nil
+	}
+	return &MachineFirewallerAPI{
+		st:          st,
+		resources:   resources,
+		getAuthFunc: getAuthFunc,
+	}, nil
+}
+
+// WatchOpenedPorts returns a NotifyWatcher for changes to the port
+// ranges opened on each given machine.
+func (api *MachineFirewallerAPI) WatchOpenedPorts(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := api.getAuthFunc()
+	if err != nil {
+		return params.NotifyWatchResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		machine, err := api.getMachine(canAccess, entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		watch := machine.WatchOpenedPorts(network.DefaultPublic)
+		// Consume the initial event.
+		if _, ok := <-watch.Changes(); ok {
+			result.Results[i].NotifyWatcherId = api.resources.Register(watch)
+		} else {
+			result.Results[i].Error = common.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return result, nil
+}
+
+// OpenedPorts returns, for each given machine, the port ranges opened
+// by units of exposed services deployed to it. These are the port
+// ranges the machine's host firewall should allow.
+func (api *MachineFirewallerAPI) OpenedPorts(args params.Entities) (params.MachinePortsResults, error) {
+	result := params.MachinePortsResults{
+		Results: make([]params.MachinePortsResult, len(args.Entities)),
+	}
+	canAccess, err := api.getAuthFunc()
+	if err != nil {
+		return params.MachinePortsResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		machine, err := api.getMachine(canAccess, entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		ports, err := api.exposedPorts(machine)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Ports = ports
+	}
+	return result, nil
+}
+
+// exposedPorts returns the port ranges opened on the machine by units
+// of exposed services, sorted by port range.
+func (api *MachineFirewallerAPI) exposedPorts(machine *state.Machine) ([]params.MachinePortRange, error) {
+	allPorts, err := machine.AllPorts()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	exposed := make(map[string]bool)
+	unitNames := make(map[network.PortRange]string)
+	var portRanges []network.PortRange
+	for _, ports := range allPorts {
+		for portRange, unitName := range ports.AllPortRanges() {
+			isExposed, err := api.serviceExposed(exposed, unitName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !isExposed {
+				continue
+			}
+			if _, ok := unitNames[portRange]; !ok {
+				portRanges = append(portRanges, portRange)
+			}
+			unitNames[portRange] = unitName
+		}
+	}
+	network.SortPortRanges(portRanges)
+	result := make([]params.MachinePortRange, len(portRanges))
+	for i, portRange := range portRanges {
+		result[i] = params.MachinePortRange{
+			UnitTag:   names.NewUnitTag(unitNames[portRange]).String(),
+			PortRange: params.FromNetworkPortRange(portRange),
+		}
+	}
+	return result, nil
+}
+
+// serviceExposed reports whether the service of the named unit is
+// exposed, caching the result in exposed.
+func (api *MachineFirewallerAPI) serviceExposed(exposed map[string]bool, unitName string) (bool, error) {
+	serviceName, err := names.UnitService(unitName)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if isExposed, ok := exposed[serviceName]; ok {
+		return isExposed, nil
+	}
+	service, err := api.st.Service(serviceName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	exposed[serviceName] = service.IsExposed()
+	return exposed[serviceName], nil
+}
+
+func (api *MachineFirewallerAPI) getMachine(canAccess common.AuthFunc, tagString string) (*state.Machine, error) {
+	tag, err := names.ParseMachineTag(tagString)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	if !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return api.st.Machine(tag.Id())
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/machinefirewaller"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type machineFirewallerSuite struct {
+	jujutesting.JujuConnSuite
+
+	machine    *state.Machine
+	unit       *state.Unit
+	service    *state.Service
+	resources  *common.Resources
+	authorizer apiservertesting.FakeAuthorizer
+	api        *machinefirewaller.MachineFirewallerAPI
+}
+
+var _ = gc.Suite(&machineFirewallerSuite{})
+
+func (s *machineFirewallerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	s.service = s.Factory.MakeService(c, &factory.ServiceParams{Name: "wordpress"})
+	s.machine = s.Factory.MakeMachine(c, nil)
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, Machine: s.machine})
+
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	s.authorizer = apiservertesting.FakeAuthorizer{Tag: s.machine.Tag()}
+
+	var err error
+	s.api, err = machinefirewaller.NewMachineFirewallerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *machineFirewallerSuite) TestNewMachineFirewallerAPIRequiresMachineAgent(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewUnitTag("wordpress/0")}
+	_, err := machinefirewaller.NewMachineFirewallerAPI(s.State, s.resources, authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *machineFirewallerSuite) args(tags ...names.Tag) params.Entities {
+	args := params.Entities{}
+	for _, tag := range tags {
+		args.Entities = append(args.Entities, params.Entity{Tag: tag.String()})
+	}
+	return args
+}
+
+func (s *machineFirewallerSuite) TestOpenedPortsOnlyIncludesExposedServices(c *gc.C) {
+	err := s.unit.OpenPorts("tcp", 80, 81)
+	c.Assert(err, jc.ErrorIsNil)
+	other := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "other",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	otherUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: other, Machine: s.machine})
+	err = otherUnit.OpenPort("udp", 53)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.OpenedPorts(s.args(s.machine.Tag()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Ports, gc.HasLen, 0)
+
+	err = s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = s.api.OpenedPorts(s.args(s.machine.Tag()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Ports, jc.DeepEquals, []params.MachinePortRange{{
+		UnitTag:   s.unit.Tag().String(),
+		PortRange: params.PortRange{FromPort: 80, ToPort: 81, Protocol: "tcp"},
+	}})
+}
+
+func (s *machineFirewallerSuite) TestOpenedPortsOtherMachine(c *gc.C) {
+	other := s.Factory.MakeMachine(c, nil)
+	results, err := s.api.OpenedPorts(s.args(other.Tag(), names.NewUnitTag("wordpress/0")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.MachinePortsResults{
+		Results: []params.MachinePortsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *machineFirewallerSuite) TestWatchOpenedPorts(c *gc.C) {
+	results, err := s.api.WatchOpenedPorts(s.args(s.machine.Tag()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+
+	w := s.resources.Get(results.Results[0].NotifyWatcherId).(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	err = s.unit.OpenPort("tcp", 8080)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *machineFirewallerSuite) TestWatchOpenedPortsOtherMachine(c *gc.C) {
+	other := s.Factory.MakeMachine(c, nil)
+	results, err := s.api.WatchOpenedPorts(s.args(other.Tag()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+}
@@ -0,0 +1,218 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+)
+
+// requestDurationBuckets holds the upper bounds, in seconds, of the
+// buckets into which request latencies are counted.
+var requestDurationBuckets = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// apiMetrics records the requests served by the API server, for
+// exposition to monitoring systems.
+type apiMetrics struct {
+	mu          sync.Mutex
+	connections int64
+	facades     map[string]*facadeMetrics
+}
+
+// facadeMetrics records the requests served by a single facade.
+type facadeMetrics struct {
+	requests uint64
+	errors   uint64
+	// durations holds the number of requests whose latency fell in
+	// each of requestDurationBuckets, with a final entry counting
+	// those that exceeded them all.
+	durations   []uint64
+	durationSum float64
+}
+
+func newAPIMetrics() *apiMetrics {
+	return &apiMetrics{
+		facades: make(map[string]*facadeMetrics),
+	}
+}
+
+// connectionOpened records the opening of an API connection.
+func (m *apiMetrics) connectionOpened() {
+	m.mu.Lock()
+	m.connections++
+	m.mu.Unlock()
+}
+
+// connectionClosed records the closing of an API connection.
+func (m *apiMetrics) connectionClosed() {
+	m.mu.Lock()
+	m.connections--
+	m.mu.Unlock()
+}
+
+// unknownFacade is the name under which requests to facades the API
+// server does not serve are recorded. The facade name is chosen by the
+// client, even before it logs in, so recording it verbatim would let
+// anyone add metrics without limit.
+const unknownFacade = "unknown"
+
+// requestServed records a request made to the given facade, which took
+// timeSpent to serve and failed if failed is true.
+func (m *apiMetrics) requestServed(facade string, timeSpent time.Duration, failed bool) {
+	if facade != "Admin" && !common.Facades.Has(facade) {
+		facade = unknownFacade
+	}
+	seconds := timeSpent.Seconds()
+	bucket := sort.SearchFloat64s(requestDurationBuckets, seconds)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.facades[facade]
+	if !ok {
+		f = &facadeMetrics{
+			durations: make([]uint64, len(requestDurationBuckets)+1),
+		}
+		m.facades[facade] = f
+	}
+	f.requests++
+	if failed {
+		f.errors++
+	}
+	f.durations[bucket]++
+	f.durationSum += seconds
+}
+
+// writeTo writes the metrics to w in the Prometheus text exposition
+// format.
+func (m *apiMetrics) writeTo(w io.Writer) error {
+	var buf bytes.Buffer
+	m.mu.Lock()
+	names := make([]string, 0, len(m.facades))
+	for name := range m.facades {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(&buf, "# HELP juju_apiserver_connections Number of open API connections.\n")
+	fmt.Fprintf(&buf, "# TYPE juju_apiserver_connections gauge\n")
+	fmt.Fprintf(&buf, "juju_apiserver_connections %d\n", m.connections)
+
+	fmt.Fprintf(&buf, "# HELP juju_apiserver_requests_total Number of API requests served.\n")
+	fmt.Fprintf(&buf, "# TYPE juju_apiserver_requests_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "juju_apiserver_requests_total{facade=%s} %d\n", labelValue(name), m.facades[name].requests)
+	}
+
+	fmt.Fprintf(&buf, "# HELP juju_apiserver_request_errors_total Number of API requests that failed.\n")
+	fmt.Fprintf(&buf, "# TYPE juju_apiserver_request_errors_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "juju_apiserver_request_errors_total{facade=%s} %d\n", labelValue(name), m.facades[name].errors)
+	}
+
+	fmt.Fprintf(&buf, "# HELP juju_apiserver_request_duration_seconds Time taken to serve API requests.\n")
+	fmt.Fprintf(&buf, "# TYPE juju_apiserver_request_duration_seconds histogram\n")
+	for _, name := range names {
+		f := m.facades[name]
+		label := labelValue(name)
+		var count uint64
+		for i, bound := range requestDurationBuckets {
+			count += f.durations[i]
+			fmt.Fprintf(&buf, "juju_apiserver_request_duration_seconds_bucket{facade=%s,le=%q} %d\n",
+				label, strconv.FormatFloat(bound, 'g', -1, 64), count)
+		}
+		fmt.Fprintf(&buf, "juju_apiserver_request_duration_seconds_bucket{facade=%s,le=\"+Inf\"} %d\n", label, f.requests)
+		fmt.Fprintf(&buf, "juju_apiserver_request_duration_seconds_sum{facade=%s} %s\n",
+			label, strconv.FormatFloat(f.durationSum, 'g', -1, 64))
+		fmt.Fprintf(&buf, "juju_apiserver_request_duration_seconds_count{facade=%s} %d\n", label, f.requests)
+	}
+	m.mu.Unlock()
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeTxnHealth writes the result of the last transaction queue check
+// to w in the Prometheus text exposition format.
+func writeTxnHealth(w io.Writer, health state.TxnHealth) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# HELP juju_state_txns Number of recorded transactions, at the last check.\n")
+	fmt.Fprintf(&buf, "# TYPE juju_state_txns gauge\n")
+	fmt.Fprintf(&buf, "juju_state_txns %d\n", health.Transactions)
+
+	fmt.Fprintf(&buf, "# HELP juju_state_oversized_txn_queues Number of documents with oversized transaction queues, at the last check.\n")
+	fmt.Fprintf(&buf, "# TYPE juju_state_oversized_txn_queues gauge\n")
+	fmt.Fprintf(&buf, "juju_state_oversized_txn_queues %d\n", health.OversizedQueues)
+
+	fmt.Fprintf(&buf, "# HELP juju_state_txn_queue_tokens_removed Number of stale transactions removed from queues by the last check.\n")
+	fmt.Fprintf(&buf, "# TYPE juju_state_txn_queue_tokens_removed gauge\n")
+	fmt.Fprintf(&buf, "juju_state_txn_queue_tokens_removed %d\n", health.RemovedTokens)
+
+	fmt.Fprintf(&buf, "# HELP juju_state_txn_check_timestamp_seconds When the transaction queues were last checked.\n")
+	fmt.Fprintf(&buf, "# TYPE juju_state_txn_check_timestamp_seconds gauge\n")
+	fmt.Fprintf(&buf, "juju_state_txn_check_timestamp_seconds %d\n", health.Time.Unix())
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelValue returns s quoted as a Prometheus label value.
+func labelValue(s string) string {
+	return `"` + labelValueReplacer.Replace(s) + `"`
+}
+
+// metricsContentType is the content type of the Prometheus text
+// exposition format.
+const metricsContentType = "text/plain; version=0.0.4"
+
+// metricsHandler serves the API server's metrics to users of the
+// state server environment.
+type metricsHandler struct {
+	ctxt    httpContext
+	metrics *apiMetrics
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	st, _, err := h.ctxt.stateForRequestAuthenticatedUser(req)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+	if req.Method != "GET" {
+		sendError(w, errors.MethodNotAllowedf("unsupported method: %q", req.Method))
+		return
+	}
+	w.Header().Set("Content-Type", metricsContentType)
+	if err := h.metrics.writeTo(w); err != nil {
+		logger.Debugf("cannot write API metrics: %v", err)
+		return
+	}
+	// The transaction queues are checked periodically by the state
+	// server, so there is nothing to report until the first check.
+	health, err := st.TxnHealth()
+	if errors.IsNotFound(err) {
+		return
+	} else if err != nil {
+		logger.Debugf("cannot read transaction queue health: %v", err)
+		return
+	}
+	if err := writeTxnHealth(w, health); err != nil {
+		logger.Debugf("cannot write transaction queue metrics: %v", err)
+	}
+}
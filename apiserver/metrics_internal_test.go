@@ -0,0 +1,105 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"bytes"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type metricsIntSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&metricsIntSuite{})
+
+func (s *metricsIntSuite) TestWriteTo(c *gc.C) {
+	s.PatchValue(&requestDurationBuckets, []float64{0.01, 1})
+	metrics := newAPIMetrics()
+	metrics.connectionOpened()
+	metrics.connectionOpened()
+	metrics.connectionClosed()
+	metrics.requestServed("Uniter", 5*time.Millisecond, false)
+	metrics.requestServed("Uniter", 10*time.Millisecond, true)
+	metrics.requestServed("Uniter", 2*time.Second, false)
+	metrics.requestServed("Client", 500*time.Millisecond, false)
+
+	var buf bytes.Buffer
+	err := metrics.writeTo(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `
+# HELP juju_apiserver_connections Number of open API connections.
+# TYPE juju_apiserver_connections gauge
+juju_apiserver_connections 1
+# HELP juju_apiserver_requests_total Number of API requests served.
+# TYPE juju_apiserver_requests_total counter
+juju_apiserver_requests_total{facade="Client"} 1
+juju_apiserver_requests_total{facade="Uniter"} 3
+# HELP juju_apiserver_request_errors_total Number of API requests that failed.
+# TYPE juju_apiserver_request_errors_total counter
+juju_apiserver_request_errors_total{facade="Client"} 0
+juju_apiserver_request_errors_total{facade="Uniter"} 1
+# HELP juju_apiserver_request_duration_seconds Time taken to serve API requests.
+# TYPE juju_apiserver_request_duration_seconds histogram
+juju_apiserver_request_duration_seconds_bucket{facade="Client",le="0.01"} 0
+juju_apiserver_request_duration_seconds_bucket{facade="Client",le="1"} 1
+juju_apiserver_request_duration_seconds_bucket{facade="Client",le="+Inf"} 1
+juju_apiserver_request_duration_seconds_sum{facade="Client"} 0.5
+juju_apiserver_request_duration_seconds_count{facade="Client"} 1
+juju_apiserver_request_duration_seconds_bucket{facade="Uniter",le="0.01"} 2
+juju_apiserver_request_duration_seconds_bucket{facade="Uniter",le="1"} 2
+juju_apiserver_request_duration_seconds_bucket{facade="Uniter",le="+Inf"} 3
+juju_apiserver_request_duration_seconds_sum{facade="Uniter"} 2.015
+juju_apiserver_request_duration_seconds_count{facade="Uniter"} 3
+`[1:])
+}
+
+func (s *metricsIntSuite) TestUnknownFacades(c *gc.C) {
+	metrics := newAPIMetrics()
+	metrics.requestServed("Admin", time.Millisecond, false)
+	metrics.requestServed("Client", time.Millisecond, false)
+	metrics.requestServed("NoSuchFacade", time.Millisecond, true)
+	metrics.requestServed("AnotherMadeUpFacade", time.Millisecond, true)
+
+	c.Assert(metrics.facades, gc.HasLen, 3)
+	c.Assert(metrics.facades["Admin"].requests, gc.Equals, uint64(1))
+	c.Assert(metrics.facades["Client"].requests, gc.Equals, uint64(1))
+	c.Assert(metrics.facades[unknownFacade].requests, gc.Equals, uint64(2))
+	c.Assert(metrics.facades[unknownFacade].errors, gc.Equals, uint64(2))
+}
+
+func (s *metricsIntSuite) TestWriteTxnHealth(c *gc.C) {
+	var buf bytes.Buffer
+	err := writeTxnHealth(&buf, state.TxnHealth{
+		Time:            time.Unix(1443700800, 0),
+		Transactions:    12345,
+		OversizedQueues: 2,
+		RemovedTokens:   1500,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `
+# HELP juju_state_txns Number of recorded transactions, at the last check.
+# TYPE juju_state_txns gauge
+juju_state_txns 12345
+# HELP juju_state_oversized_txn_queues Number of documents with oversized transaction queues, at the last check.
+# TYPE juju_state_oversized_txn_queues gauge
+juju_state_oversized_txn_queues 2
+# HELP juju_state_txn_queue_tokens_removed Number of stale transactions removed from queues by the last check.
+# TYPE juju_state_txn_queue_tokens_removed gauge
+juju_state_txn_queue_tokens_removed 1500
+# HELP juju_state_txn_check_timestamp_seconds When the transaction queues were last checked.
+# TYPE juju_state_txn_check_timestamp_seconds gauge
+juju_state_txn_check_timestamp_seconds 1443700800
+`[1:])
+}
+
+func (s *metricsIntSuite) TestLabelValue(c *gc.C) {
+	c.Assert(labelValue(`a"b\c`+"\n"), gc.Equals, `"a\"b\\c\n"`)
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing/factory"
+)
+
+type metricsSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+func (s *metricsSuite) metricsURL(c *gc.C) string {
+	uri := s.baseURL(c)
+	uri.Path = "/introspection/metrics"
+	return uri.String()
+}
+
+func (s *metricsSuite) assertError(c *gc.C, resp *http.Response, status int, msg string) {
+	body := assertResponse(c, resp, status, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, msg)
+}
+
+func (s *metricsSuite) TestRequiresAuth(c *gc.C) {
+	resp := s.sendRequest(c, httpRequestParams{method: "GET", url: s.metricsURL(c)})
+	s.assertError(c, resp, http.StatusUnauthorized, "no credentials provided")
+}
+
+func (s *metricsSuite) TestRequiresUser(c *gc.C) {
+	machine, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "fake_nonce",
+	})
+	resp := s.sendRequest(c, httpRequestParams{
+		tag:      machine.Tag().String(),
+		password: password,
+		method:   "GET",
+		url:      s.metricsURL(c),
+		nonce:    "fake_nonce",
+	})
+	s.assertError(c, resp, http.StatusUnauthorized, "invalid entity name or password")
+}
+
+func (s *metricsSuite) TestInvalidHTTPMethod(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "POST", url: s.metricsURL(c)})
+	s.assertError(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *metricsSuite) TestMetrics(c *gc.C) {
+	// Make a failing request, so that errors are recorded.
+	_, err := s.APIState.Client().ServiceGet("no-such-service")
+	c.Assert(err, gc.NotNil)
+
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.metricsURL(c)})
+	body := assertResponse(c, resp, http.StatusOK, "text/plain; version=0.0.4")
+	c.Check(string(body), gc.Matches, `(?s)`+
+		`.*\njuju_apiserver_connections [1-9][0-9]*\n`+
+		`.*\njuju_apiserver_requests_total\{facade="Admin"\} [1-9][0-9]*\n`+
+		`.*\njuju_apiserver_requests_total\{facade="Client"\} [1-9][0-9]*\n`+
+		`.*\njuju_apiserver_request_errors_total\{facade="Client"\} [1-9][0-9]*\n`+
+		`.*\njuju_apiserver_request_duration_seconds_count\{facade="Client"\} [1-9][0-9]*\n.*`,
+	)
+}
+
+func (s *metricsSuite) TestMetricsIncludeTxnHealth(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.metricsURL(c)})
+	body := assertResponse(c, resp, http.StatusOK, "text/plain; version=0.0.4")
+	c.Check(string(body), gc.Not(gc.Matches), `(?s).*juju_state_txns.*`)
+
+	_, err := s.State.CheckTxnQueues(1000)
+	c.Assert(err, jc.ErrorIsNil)
+	resp = s.authRequest(c, httpRequestParams{method: "GET", url: s.metricsURL(c)})
+	body = assertResponse(c, resp, http.StatusOK, "text/plain; version=0.0.4")
+	c.Check(string(body), gc.Matches, `(?s)`+
+		`.*\njuju_state_txns [1-9][0-9]*\n`+
+		`.*\njuju_state_oversized_txn_queues 0\n`+
+		`.*\njuju_state_txn_queue_tokens_removed 0\n`+
+		`.*\njuju_state_txn_check_timestamp_seconds [1-9][0-9]*\n`,
+	)
+}
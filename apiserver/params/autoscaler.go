@@ -0,0 +1,34 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// InstanceGroupAnnotation is the service annotation naming the cloud
+// instance group that drives the number of units of the service.
+const InstanceGroupAnnotation = "autoscaler-instance-group"
+
+// ServiceInstanceGroup links a service to a cloud instance group.
+type ServiceInstanceGroup struct {
+	ServiceTag    string
+	InstanceGroup string
+}
+
+// ServiceInstanceGroupsResult holds the services that are linked to
+// cloud instance groups.
+type ServiceInstanceGroupsResult struct {
+	Results []ServiceInstanceGroup
+}
+
+// InstanceGroupSize holds the number of instances observed in the
+// cloud instance group linked to a service.
+type InstanceGroupSize struct {
+	ServiceTag    string
+	InstanceGroup string
+	Size          int
+}
+
+// InstanceGroupSizes holds the arguments for reporting
+// changes in the sizes of cloud instance groups.
+type InstanceGroupSizes struct {
+	Sizes []InstanceGroupSize
+}
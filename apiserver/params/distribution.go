@@ -0,0 +1,31 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// DistributionReport holds the distribution of the units of
+// every service in an environment across hosts and zones.
+type DistributionReport struct {
+	Services []ServiceDistribution
+}
+
+// ServiceDistribution describes how the units of a service are
+// distributed across hosts and availability zones.
+type ServiceDistribution struct {
+	ServiceTag string
+	Units      int
+	Zones      map[string]int
+	Hosts      map[string]int
+	Imbalanced bool
+	SingleHost bool
+	SingleZone bool
+	Moves      []UnitMove
+}
+
+// UnitMove describes a suggested move of a unit from one
+// availability zone to another.
+type UnitMove struct {
+	UnitTag  string
+	FromZone string
+	ToZone   string
+}
@@ -37,14 +37,26 @@ type EnvironAction string
 
 // Actions that can be preformed on an environment.
 const (
-	AddEnvUser    EnvironAction = "add"
-	RemoveEnvUser EnvironAction = "remove"
+	AddEnvUser       EnvironAction = "add"
+	RemoveEnvUser    EnvironAction = "remove"
+	SetEnvUserAccess EnvironAction = "set-access"
+)
+
+// Access levels that users may have to an environment.
+const (
+	EnvAdminAccess = "admin"
+	EnvWriteAccess = "write"
+	EnvReadAccess  = "read"
 )
 
 // ModifyEnvironUser stores the parameters used for a Client.ShareEnvironment call.
 type ModifyEnvironUser struct {
 	UserTag string        `json:"user-tag"`
 	Action  EnvironAction `json:"action"`
+	// Access holds the access level the user is given by the
+	// add and set-access actions. When adding a user it defaults
+	// to EnvAdminAccess.
+	Access string `json:"access,omitempty"`
 }
 
 // SetEnvironAgentVersion contains the arguments for
@@ -60,6 +72,7 @@ type EnvUserInfo struct {
 	CreatedBy      string     `json:"createdby"`
 	DateCreated    time.Time  `json:"datecreated"`
 	LastConnection *time.Time `json:"lastconnection"`
+	Access         string     `json:"access"`
 }
 
 // EnvUserInfoResult holds the result of an EnvUserInfo call.
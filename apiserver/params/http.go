@@ -6,10 +6,10 @@ package params
 // DigestAlgorithm is one of the values in the IANA registry. See
 // RFC 3230 and 5843.
 //
-// Note that currently Juju does not conform to the standard.
-// It stores a hexadecimal SHA256 value in the Digest header,
+// Note that DigestSHA does not conform to the standard. It is
+// used with a hexadecimal SHA256 value in the Digest header,
 // but the above RFCs specify SHA-256 and a base64-encoded
-// value for this.
+// value for this, as used with DigestSHA256.
 // TODO fix that. https://bugs.launchpad.net/juju-core/+bug/1503992
 type DigestAlgorithm string
 
@@ -17,6 +17,10 @@ const (
 	// DigestSHA is the HTTP digest algorithm value used in juju's HTTP code.
 	DigestSHA DigestAlgorithm = "SHA"
 
+	// DigestSHA256 is the HTTP digest algorithm value for a
+	// base64-encoded SHA-256 digest, as specified by RFC 5843.
+	DigestSHA256 DigestAlgorithm = "SHA-256"
+
 	// The values used for content-type in juju's direct HTTP code:
 
 	// ContentTypeJSON is the HTTP content-type value used for JSON content.
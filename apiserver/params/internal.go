@@ -360,10 +360,12 @@ type SetStatus struct {
 	Entities []EntityStatusArgs
 }
 
-// InstanceStatus holds an entity tag and instance status.
+// InstanceStatus holds an entity tag, instance status and any
+// message elaborating upon the status.
 type InstanceStatus struct {
 	Tag    string
 	Status string
+	Info   string
 }
 
 // SetInstancesStatus holds parameters for making a
@@ -526,6 +528,10 @@ type CharmsResponse struct {
 
 	CharmURL string   `json:",omitempty"`
 	Files    []string `json:",omitempty"`
+
+	// Received holds the number of bytes the server has received
+	// so far for a resumable upload that is not yet complete.
+	Received int64 `json:",omitempty"`
 }
 
 // RunParams is used to provide the parameters to the Run method.
@@ -537,6 +543,15 @@ type RunParams struct {
 	Machines []string
 	Services []string
 	Units    []string
+
+	// RelationId, if set, names the relation (as an id such as "3",
+	// or as "endpoint:3") in whose context the commands are run on
+	// units. It may not be used when running on machines.
+	RelationId string `json:",omitempty"`
+
+	// RemoteUnit, if set, names the remote unit in whose context the
+	// commands are run within the relation given by RelationId.
+	RemoteUnit string `json:",omitempty"`
 }
 
 // RunResult contains the result from an individual run call on a machine.
@@ -631,3 +646,37 @@ type MeterStatusResult struct {
 type MeterStatusResults struct {
 	Results []MeterStatusResult
 }
+
+// ProcessDetails holds the details of a launched workload process.
+type ProcessDetails struct {
+	ID     string
+	Status string
+	Extra  map[string]interface{}
+	Usage  *ProcessUsage
+}
+
+// ProcessUsage holds the resource usage of a launched workload process.
+type ProcessUsage struct {
+	CPU      float64
+	Memory   uint64
+	Restarts int
+}
+
+// ProcessInfo describes a workload process tracked by a unit.
+type ProcessInfo struct {
+	Name    string
+	Type    string
+	Details ProcessDetails
+}
+
+// UnitProcesses holds the workload processes tracked by a unit.
+type UnitProcesses struct {
+	Tag       string
+	Processes []ProcessInfo
+}
+
+// UnitProcessesParams holds the workload processes tracked by
+// multiple units.
+type UnitProcessesParams struct {
+	Units []UnitProcesses
+}
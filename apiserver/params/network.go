@@ -217,6 +217,37 @@ func (pr PortRange) NetworkPortRange() network.PortRange {
 	}
 }
 
+// FirewallRules holds the port ranges Juju expects to be open on a
+// machine, or across the whole environment when MachineId is empty,
+// and those the provider actually has open.
+type FirewallRules struct {
+	MachineId  string      `json:"MachineId,omitempty"`
+	InstanceId string      `json:"InstanceId,omitempty"`
+	Expected   []PortRange `json:"Expected"`
+	Actual     []PortRange `json:"Actual"`
+	Error      *Error      `json:"Error,omitempty"`
+}
+
+// FirewallRulesResults holds the results of the ListFirewallRules
+// and SyncFirewallRules calls.
+type FirewallRulesResults struct {
+	Mode    string          `json:"Mode"`
+	Results []FirewallRules `json:"Results"`
+}
+
+// FirewallWhitelist holds the source address ranges allowed to reach
+// a well-known service, such as SSH or the API server. An empty
+// whitelist allows access from anywhere.
+type FirewallWhitelist struct {
+	KnownService   string   `json:"KnownService"`
+	WhitelistCIDRs []string `json:"WhitelistCIDRs"`
+}
+
+// FirewallWhitelists holds a number of firewall whitelists.
+type FirewallWhitelists struct {
+	Whitelists []FirewallWhitelist `json:"Whitelists"`
+}
+
 // EntityPort holds an entity's tag, a protocol and a port.
 type EntityPort struct {
 	Tag      string `json:"Tag"`
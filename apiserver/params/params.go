@@ -187,6 +187,14 @@ type DestroyMachines struct {
 	Force        bool
 }
 
+// RetryProvisioning holds parameters for the RetryProvisioning call.
+type RetryProvisioning struct {
+	Entities []Entity
+	// Force causes the failed instances of provisioned machines to
+	// be stopped and forgotten, so that new ones are started.
+	Force bool
+}
+
 // ServicesDeploy holds the parameters for deploying one or more services.
 type ServicesDeploy struct {
 	Services []ServiceDeploy
@@ -204,6 +212,10 @@ type ServiceDeploy struct {
 	Placement     []*instance.Placement
 	Networks      []string
 	Storage       map[string]storage.Constraints
+
+	// EndpointBindings maps charm endpoint names to the network
+	// spaces they are bound to.
+	EndpointBindings map[string]string
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -224,6 +236,21 @@ type ServiceSetCharm struct {
 	Force       bool
 }
 
+// ServiceSetUpgradeStrategy holds the parameters for making the
+// ServiceSetUpgradeStrategy call.
+type ServiceSetUpgradeStrategy struct {
+	ServiceName    string
+	Strategy       string
+	MaxUnavailable int
+}
+
+// ServiceSetAddressPolicy holds the parameters for making the
+// ServiceSetAddressPolicy call.
+type ServiceSetAddressPolicy struct {
+	ServiceName string
+	Policy      string
+}
+
 // ServiceExpose holds the parameters for making the ServiceExpose call.
 type ServiceExpose struct {
 	ServiceName string
@@ -312,12 +339,91 @@ type PrivateAddressResults struct {
 	PrivateAddress string
 }
 
+// SSHHostKeysTarget holds parameters for the SSHHostKeys call.
+type SSHHostKeysTarget struct {
+	Target string
+}
+
+// SSHHostKeysResults holds results of the SSHHostKeys call.
+type SSHHostKeysResults struct {
+	PublicKeys []string
+}
+
+// SSHHostKeys holds the public SSH host keys of an entity.
+type SSHHostKeys struct {
+	Tag        string
+	PublicKeys []string
+}
+
+// SSHHostKeySet holds the public SSH host keys of one or more
+// entities.
+type SSHHostKeySet struct {
+	EntityKeys []SSHHostKeys
+}
+
+// ProblemReport holds the details of a problem reported by an agent,
+// such as a crash-looping worker or a failed hook.
+type ProblemReport struct {
+	// Id identifies a recorded report. It is ignored when the
+	// report is uploaded.
+	Id      string
+	Tag     string
+	Kind    string
+	Source  string
+	Message string
+	Stack   string
+	Logs    []string
+	State   map[string]string
+	Time    time.Time
+}
+
+// ProblemReports holds one or more problem reports.
+type ProblemReports struct {
+	Reports []ProblemReport
+}
+
+// AuditLogFilter holds the parameters for the AuditLog call. Zero
+// valued fields do not restrict the entries returned.
+type AuditLogFilter struct {
+	Entity string
+	Facade string
+	Since  *time.Time
+	Limit  int
+
+	CorrelationId string `json:",omitempty"`
+}
+
+// AuditLogEntry holds a state-changing API request recorded in an
+// environment's audit log.
+type AuditLogEntry struct {
+	Time      time.Time
+	Entity    string
+	Facade    string
+	Version   int
+	Method    string
+	Args      string
+	Error     string
+	ErrorCode string
+
+	CorrelationId string `json:",omitempty"`
+}
+
+// AuditLogResults holds entries from an environment's audit log.
+type AuditLogResults struct {
+	Entries []AuditLogEntry
+}
+
 // Resolved holds parameters for the Resolved call.
 type Resolved struct {
 	UnitName string
 	Retry    bool
 }
 
+// ResolveUnits holds parameters for the ResolveUnits call.
+type ResolveUnits struct {
+	Units []Resolved
+}
+
 // ResolvedResults holds results of the Resolved call.
 type ResolvedResults struct {
 	Service  string
@@ -349,6 +455,18 @@ type ServiceDestroy struct {
 	ServiceName string
 }
 
+// RemovalImpact describes the entities that will be removed, or
+// otherwise affected, by destroying a service or some of its units.
+type RemovalImpact struct {
+	Units            []string
+	Relations        []string
+	Machines         []string
+	EmptyMachines    []string
+	DetachedStorage  []string
+	DestroyedStorage []string
+	UnitErrors       map[string]*Error `json:",omitempty"`
+}
+
 // Creds holds credentials for identifying an entity.
 type Creds struct {
 	AuthTag  string
@@ -586,6 +704,17 @@ type DistributionGroupResults struct {
 type FacadeVersions struct {
 	Name     string
 	Versions []int
+
+	// Deprecations lists the versions that are slated for removal.
+	// Servers that predate deprecations never set it.
+	Deprecations []FacadeDeprecation `json:",omitempty"`
+}
+
+// FacadeDeprecation describes a version of a facade that is slated for
+// removal, and what clients should use instead.
+type FacadeDeprecation struct {
+	Version int
+	Notice  string
 }
 
 // LoginResult holds the result of a Login call.
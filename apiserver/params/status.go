@@ -46,16 +46,17 @@ type MachineStatus struct {
 	Life           string
 	Err            error
 
-	DNSName       string
-	InstanceId    instance.Id
-	InstanceState string
-	Series        string
-	Id            string
-	Containers    map[string]MachineStatus
-	Hardware      string
-	Jobs          []multiwatcher.MachineJob
-	HasVote       bool
-	WantsVote     bool
+	DNSName           string
+	InstanceId        instance.Id
+	InstanceState     string
+	InstanceStateInfo string
+	Series            string
+	Id                string
+	Containers        map[string]MachineStatus
+	Hardware          string
+	Jobs              []multiwatcher.MachineJob
+	HasVote           bool
+	WantsVote         bool
 }
 
 // ServiceStatus holds status info about a service.
@@ -70,9 +71,21 @@ type ServiceStatus struct {
 	SubordinateTo []string
 	Units         map[string]UnitStatus
 	MeterStatuses map[string]MeterStatus
+	Processes     *ProcessesStatus
 	Status        AgentStatus
 }
 
+// ProcessesStatus summarises the workload processes tracked by the
+// units of a service.
+type ProcessesStatus struct {
+	// Statuses holds the number of processes with each status.
+	Statuses map[string]int
+
+	// Failed holds the names of the processes whose status indicates
+	// that they have failed, keyed on unit name.
+	Failed map[string][]string
+}
+
 // MeterStatus represents the meter status of a unit.
 type MeterStatus struct {
 	Color   string
@@ -100,6 +113,10 @@ type UnitStatus struct {
 	PublicAddress string
 	Charm         string
 	Subordinates  map[string]UnitStatus
+	Processes     []ProcessInfo
+
+	// Leader reports whether the unit holds leadership of its service.
+	Leader bool
 }
 
 // TODO(ericsnow) Rename to ServiceNetworksSepcification.
@@ -181,6 +198,47 @@ type UnitStatusHistory struct {
 	Statuses []AgentStatus
 }
 
+// ListProcessesArgs holds the parameters used to select the workload
+// processes returned by ListProcesses.
+type ListProcessesArgs struct {
+	// Units holds the names of the units whose processes are listed.
+	// If empty, the processes of all units are listed.
+	Units []string
+
+	// Types, if not empty, restricts the listing to processes of the
+	// given types.
+	Types []string
+
+	// Statuses, if not empty, restricts the listing to processes with
+	// the given statuses.
+	Statuses []string
+
+	// HistorySize is the number of past statuses to return for each
+	// process.
+	HistorySize int
+}
+
+// ListProcessesResults holds the workload processes of a number of
+// units.
+type ListProcessesResults struct {
+	Results []UnitProcessesResult
+}
+
+// UnitProcessesResult holds the workload processes of a unit, or an
+// error.
+type UnitProcessesResult struct {
+	Unit      string
+	Processes []ProcessStatus
+	Error     *Error
+}
+
+// ProcessStatus holds a workload process and, newest first, its past
+// statuses.
+type ProcessStatus struct {
+	Info    ProcessInfo
+	History []AgentStatus
+}
+
 const (
 	// DefaultMaxLogsPerEntity is the default value for logs for each entity
 	// that should be kept at any given time.
@@ -237,6 +295,15 @@ const (
 	KindAgent HistoryKind = "agent"
 	// KindWorkload represents a charm workload status history entry.
 	KindWorkload HistoryKind = "workload"
+	// KindMachine represents a machine agent status history entry.
+	KindMachine HistoryKind = "machine"
+	// KindMachineInstance represents a machine's provider instance
+	// status history entry.
+	KindMachineInstance HistoryKind = "instance"
+	// KindProcess represents a workload process status history entry.
+	KindProcess HistoryKind = "process"
+	// KindService represents a service status history entry.
+	KindService HistoryKind = "service"
 )
 
 // Life describes the lifecycle state of an entity ("alive", "dying" or "dead").
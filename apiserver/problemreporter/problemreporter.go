@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The problemreporter package implements the API interface used by
+// agents to upload problem reports, such as crash-looping workers and
+// failed hooks, to the controller.
+package problemreporter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("ProblemReporter", 1, NewProblemReporterAPI)
+}
+
+// ProblemReporterAPI provides access to the ProblemReporter API facade.
+type ProblemReporterAPI struct {
+	st          stateInterface
+	getAuthFunc common.GetAuthFunc
+}
+
+var getState = func(st *state.State) stateInterface {
+	return st
+}
+
+// NewProblemReporterAPI creates a new server-side ProblemReporter API
+// facade.
+func NewProblemReporterAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*ProblemReporterAPI, error) {
+
+	if !authorizer.AuthMachineAgent() && !authorizer.AuthUnitAgent() {
+		return nil, common.ErrPerm
+	}
+
+	authEntityTag := authorizer.GetAuthTag()
+	getAuthFunc := func() (common.AuthFunc, error) {
+		return func(tag names.Tag) bool {
+			// An agent can only report its own problems.
+			return tag == authEntityTag
+		}, nil
+	}
+
+	return &ProblemReporterAPI{
+		st:          getState(st),
+		getAuthFunc: getAuthFunc,
+	}, nil
+}
+
+// Report records the given problem reports.
+func (api *ProblemReporterAPI) Report(args params.ProblemReports) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Reports)),
+	}
+	canAccess, err := api.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Reports {
+		tag, err := names.ParseTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			err = common.ErrPerm
+		} else {
+			_, err = api.st.AddProblemReport(state.ProblemReportInfo{
+				Entity:  tag,
+				Kind:    state.ProblemReportKind(arg.Kind),
+				Source:  arg.Source,
+				Message: arg.Message,
+				Stack:   arg.Stack,
+				Logs:    arg.Logs,
+				State:   arg.State,
+				Time:    arg.Time,
+			})
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
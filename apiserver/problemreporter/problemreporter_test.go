@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package problemreporter_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/problemreporter"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&ProblemReporterSuite{})
+
+type ProblemReporterSuite struct {
+	coretesting.BaseSuite
+	authorizer *apiservertesting.FakeAuthorizer
+	st         *mockState
+	api        *problemreporter.ProblemReporterAPI
+}
+
+func (s *ProblemReporterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewUnitTag("mysql/0")}
+	s.st = &mockState{}
+	problemreporter.PatchState(s, s.st)
+
+	var err error
+	s.api, err = problemreporter.NewProblemReporterAPI(nil, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ProblemReporterSuite) TestNewProblemReporterAPIMachine(c *gc.C) {
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := problemreporter.NewProblemReporterAPI(nil, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ProblemReporterSuite) TestNewProblemReporterAPINonAgent(c *gc.C) {
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
+	_, err := problemreporter.NewProblemReporterAPI(nil, nil, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ProblemReporterSuite) TestReport(c *gc.C) {
+	when := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	results, err := s.api.Report(params.ProblemReports{
+		Reports: []params.ProblemReport{{
+			Tag:     "unit-mysql-0",
+			Kind:    "hook-failed",
+			Source:  "install",
+			Message: `hook "install" failed`,
+			Logs:    []string{"installing"},
+			Time:    when,
+		}, {
+			Tag:    "unit-mysql-1",
+			Kind:   "hook-failed",
+			Source: "install",
+		}, {
+			Tag:    "machine-0",
+			Kind:   "worker-crash-loop",
+			Source: "firewaller",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+	c.Assert(s.st.reports, jc.DeepEquals, []state.ProblemReportInfo{{
+		Entity:  names.NewUnitTag("mysql/0"),
+		Kind:    state.HookFailure,
+		Source:  "install",
+		Message: `hook "install" failed`,
+		Logs:    []string{"installing"},
+		Time:    when,
+	}})
+}
+
+func (s *ProblemReporterSuite) TestReportError(c *gc.C) {
+	s.st.err = errors.New("boom")
+	results, err := s.api.Report(params.ProblemReports{
+		Reports: []params.ProblemReport{{
+			Tag:    "unit-mysql-0",
+			Kind:   "hook-failed",
+			Source: "install",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: "boom"}},
+		},
+	})
+}
+
+type mockState struct {
+	reports []state.ProblemReportInfo
+	err     error
+}
+
+func (st *mockState) AddProblemReport(info state.ProblemReportInfo) (state.ProblemReport, error) {
+	if st.err != nil {
+		return state.ProblemReport{}, st.err
+	}
+	st.reports = append(st.reports, info)
+	return state.ProblemReport{ProblemReportInfo: info}, nil
+}
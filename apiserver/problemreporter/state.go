@@ -0,0 +1,12 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package problemreporter
+
+import (
+	"github.com/juju/juju/state"
+)
+
+type stateInterface interface {
+	AddProblemReport(state.ProblemReportInfo) (state.ProblemReport, error)
+}
@@ -267,7 +267,7 @@ func (p *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerConf
 	if err != nil {
 		return result, err
 	}
-	if netEnv, ok := environs.SupportsNetworking(env); ok {
+	if netEnv, ok := environs.NetworkingCapable(config.Type(), env); ok {
 		// Passing network.AnySubnet below should be interpreted by
 		// the provider as "does ANY subnet support this".
 		supported, err := netEnv.SupportsAddressAllocation(network.AnySubnet)
@@ -406,7 +406,7 @@ func (p *ProvisionerAPI) ProvisioningInfo(args params.Entities) (params.Provisio
 }
 
 func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.ProvisioningInfo, error) {
-	cons, err := m.Constraints()
+	cons, err := p.machineConstraints(m)
 	if err != nil {
 		return nil, err
 	}
@@ -428,7 +428,7 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.Provisio
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	subnetsToZones, err := p.machineSubnetsAndZones(m)
+	subnetsToZones, err := p.machineSubnetsAndZones(m, cons)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot match subnets to zones")
 	}
@@ -1069,7 +1069,7 @@ func (p *ProvisionerAPI) prepareContainerAccessEnvironment() (environs.Networkin
 	if err != nil {
 		return nil, nil, nil, errors.Annotate(err, "failed to construct an environment from config")
 	}
-	netEnviron, supported := environs.SupportsNetworking(environ)
+	netEnviron, supported := environs.NetworkingCapable(cfg.Type(), environ)
 	if !supported {
 		// " not supported" will be appended to the message below.
 		return nil, nil, nil, errors.NotSupportedf("environment %q networking", cfg.Name())
@@ -1354,29 +1354,95 @@ func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.Machi
 	return machineTags, nil
 }
 
-// machineSubnetsAndZones returns a map of subnet provider-specific id
-// to list of availability zone names for that subnet. The result can
-// be empty if there are no spaces constraints specified for the
-// machine, or there's an error fetching them.
-func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]string, error) {
-	mcons, err := m.Constraints()
+// machineConstraints returns the constraints of the given machine,
+// with the spaces that the endpoints of its units' services are bound
+// to added after any spaces the constraints already include.
+func (p *ProvisionerAPI) machineConstraints(m *state.Machine) (constraints.Value, error) {
+	cons, err := m.Constraints()
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	units, err := m.Units()
 	if err != nil {
-		return nil, errors.Annotate(err, "cannot get machine constraints")
+		return constraints.Value{}, errors.Trace(err)
+	}
+	included := set.NewStrings(cons.IncludeSpaces()...)
+	excluded := set.NewStrings(cons.ExcludeSpaces()...)
+	var bound []string
+	for _, unit := range units {
+		service, err := unit.Service()
+		if err != nil {
+			return constraints.Value{}, errors.Trace(err)
+		}
+		for _, space := range service.EndpointBindingSpaces() {
+			if excluded.Contains(space) {
+				return constraints.Value{}, errors.Errorf(
+					"service %q has endpoints bound to space %q, which is excluded by the constraints of machine %q",
+					service.Name(), space, m.Id(),
+				)
+			}
+			if !included.Contains(space) {
+				included.Add(space)
+				bound = append(bound, space)
+			}
+		}
+	}
+	if len(bound) == 0 {
+		return cons, nil
+	}
+	var spaces []string
+	if cons.Spaces != nil {
+		spaces = append(spaces, *cons.Spaces...)
 	}
+	spaces = append(spaces, bound...)
+	cons.Spaces = &spaces
+	return cons, nil
+}
+
+// machineSubnetsAndZones returns a map of subnet provider-specific id
+// to list of availability zone names for that subnet, for the subnets
+// of all the spaces included by the given machine constraints. Only the
+// subnets in availability zones that have subnets in every one of the
+// spaces are returned, so that the machine is started in a zone from
+// which it can use all of them. The result is empty if there are no
+// spaces constraints specified for the machine.
+func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine, mcons constraints.Value) (map[string][]string, error) {
 	includeSpaces := mcons.IncludeSpaces()
 	if len(includeSpaces) < 1 {
 		// Nothing to do.
 		return nil, nil
 	}
-	// TODO(dimitern): For the network model MVP we only use the first
-	// included space and ignore the rest.
-	spaceName := includeSpaces[0]
-	if len(includeSpaces) > 1 {
-		logger.Debugf(
-			"using space %q from constraints for machine %q (ignoring remaining: %v)",
-			spaceName, m.Id(), includeSpaces[1:],
+	subnetsToZones := make(map[string][]string)
+	var commonZones set.Strings
+	for i, spaceName := range includeSpaces {
+		spaceZones, err := p.spaceSubnetsAndZones(m, spaceName, subnetsToZones)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if i == 0 {
+			commonZones = spaceZones
+		} else {
+			commonZones = commonZones.Intersection(spaceZones)
+		}
+	}
+	for providerId, zones := range subnetsToZones {
+		if !commonZones.Contains(zones[0]) {
+			delete(subnetsToZones, providerId)
+		}
+	}
+	if len(includeSpaces) > 1 && len(subnetsToZones) == 0 {
+		return nil, errors.Errorf(
+			"no availability zone has subnets in all of spaces %s for machine %q",
+			strings.Join(includeSpaces, ", "), m.Id(),
 		)
 	}
+	return subnetsToZones, nil
+}
+
+// spaceSubnetsAndZones adds the usable subnets of the named space to
+// subnetsToZones, keyed by provider-specific id, and returns the names
+// of the availability zones those subnets are in.
+func (p *ProvisionerAPI) spaceSubnetsAndZones(m *state.Machine, spaceName string, subnetsToZones map[string][]string) (set.Strings, error) {
 	space, err := p.st.Space(spaceName)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1385,7 +1451,7 @@ func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	subnetsToZones := make(map[string][]string, len(subnets))
+	zones := set.NewStrings()
 	for _, subnet := range subnets {
 		warningPrefix := fmt.Sprintf(
 			"not using subnet %q in space %q for machine %q provisioning: ",
@@ -1406,6 +1472,7 @@ func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]
 			continue
 		}
 		subnetsToZones[providerId] = []string{zone}
+		zones.Add(zone)
 	}
-	return subnetsToZones, nil
+	return zones, nil
 }
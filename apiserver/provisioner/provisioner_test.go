@@ -859,6 +859,128 @@ func (s *withoutStateServerSuite) TestProvisioningInfo(c *gc.C) {
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *withoutStateServerSuite) TestProvisioningInfoWithEndpointBindings(c *gc.C) {
+	_, err := s.State.AddSpace("space0", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	testing.AddSubnetsWithTemplate(c, s.State, 1, state.SubnetInfo{
+		CIDR:             "10.10.{{.}}.0/24",
+		ProviderId:       "subnet-{{.}}",
+		AvailabilityZone: "zone{{.}}",
+		SpaceName:        "space0",
+	})
+	service := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err = service.SetEndpointBindings(map[string]string{"server": "space0"})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("mem=4G"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: machine.Tag().String()}}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	info := result.Results[0].Result
+	c.Assert(info.Constraints.String(), gc.Equals, "mem=4096M spaces=space0")
+	c.Assert(info.SubnetsToZones, jc.DeepEquals, map[string][]string{
+		"subnet-0": []string{"zone0"},
+	})
+}
+
+func (s *withoutStateServerSuite) addBoundMachine(c *gc.C, bindings map[string]string) *state.Machine {
+	service := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err := service.SetEndpointBindings(bindings)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	return machine
+}
+
+func (s *withoutStateServerSuite) TestProvisioningInfoWithEndpointBindingsInSeveralSpaces(c *gc.C) {
+	for _, name := range []string{"space0", "space1"} {
+		_, err := s.State.AddSpace(name, nil, false)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// space0 has subnets in zone0 and zone1, and space1 only in
+	// zone1, so only zone1 can host a machine using both.
+	testing.AddSubnetsWithTemplate(c, s.State, 3, state.SubnetInfo{
+		CIDR:             "10.10.{{.}}.0/24",
+		ProviderId:       "subnet-{{.}}",
+		AvailabilityZone: "zone{{if (eq . 0)}}0{{else}}1{{end}}",
+		SpaceName:        "{{if (eq . 2)}}space1{{else}}space0{{end}}",
+	})
+	machine := s.addBoundMachine(c, map[string]string{"server": "space0", "juju-info": "space1"})
+
+	args := params.Entities{Entities: []params.Entity{{Tag: machine.Tag().String()}}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	info := result.Results[0].Result
+	c.Assert(info.Constraints.String(), gc.Equals, "spaces=space0,space1")
+	c.Assert(info.SubnetsToZones, jc.DeepEquals, map[string][]string{
+		"subnet-1": []string{"zone1"},
+		"subnet-2": []string{"zone1"},
+	})
+}
+
+func (s *withoutStateServerSuite) TestProvisioningInfoWithEndpointBindingsNoCommonZone(c *gc.C) {
+	for _, name := range []string{"space0", "space1"} {
+		_, err := s.State.AddSpace(name, nil, false)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	testing.AddSubnetsWithTemplate(c, s.State, 2, state.SubnetInfo{
+		CIDR:             "10.10.{{.}}.0/24",
+		ProviderId:       "subnet-{{.}}",
+		AvailabilityZone: "zone{{.}}",
+		SpaceName:        "space{{.}}",
+	})
+	machine := s.addBoundMachine(c, map[string]string{"server": "space0", "juju-info": "space1"})
+
+	args := params.Entities{Entities: []params.Entity{{Tag: machine.Tag().String()}}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches,
+		`cannot match subnets to zones: no availability zone has subnets in all of spaces space0, space1 for machine "\d+"`)
+}
+
+func (s *withoutStateServerSuite) TestProvisioningInfoEndpointBindingsExcluded(c *gc.C) {
+	_, err := s.State.AddSpace("space0", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	service := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err = service.SetEndpointBindings(map[string]string{"server": "space0"})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("spaces=^space0"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: machine.Tag().String()}}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches,
+		`service "mysql" has endpoints bound to space "space0", which is excluded by the constraints of machine "\d+"`)
+}
+
 func (s *withoutStateServerSuite) TestStorageProviderFallbackToType(c *gc.C) {
 	registry.RegisterProvider("dynamic", &storagedummy.StorageProvider{IsDynamic: true})
 	defer registry.RegisterProvider("dynamic", nil)
@@ -0,0 +1,322 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// RateLimit describes a token bucket that fills at Rate requests per
+// second, up to a maximum of Burst requests. A zero Rate imposes no
+// limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int64
+}
+
+// RateLimitConfig holds the limits on the rate at which API requests
+// are served to authenticated connections.
+type RateLimitConfig struct {
+	// Connection limits the requests made over each connection.
+	Connection RateLimit
+
+	// Entity limits the requests made by each authenticated entity,
+	// across all of its connections.
+	Entity RateLimit
+
+	// Facades limits the requests made by each authenticated entity
+	// to the named facades, in addition to the Entity limit.
+	Facades map[string]RateLimit
+}
+
+// DefaultRateLimitConfig holds the connection and entity rate limits
+// used when rate limits are configured without specifying them.
+var DefaultRateLimitConfig = RateLimitConfig{
+	Connection: RateLimit{Rate: 50, Burst: 100},
+	Entity:     RateLimit{Rate: 100, Burst: 200},
+}
+
+// ParseRateLimitConfig parses a comma-separated list of rate limits of
+// the form "<name>=<rate>[/<burst>]", where name is "connection",
+// "entity" or the name of a facade, e.g.
+//
+//     connection=20/40,entity=50,Client=10/20
+//
+// The rate is in requests per second; if the burst is omitted, it is
+// the rate rounded up. An empty string imposes no limits. Otherwise,
+// limits that are not specified are taken from DefaultRateLimitConfig,
+// and a rate of zero removes a limit.
+func ParseRateLimitConfig(s string) (RateLimitConfig, error) {
+	if strings.TrimSpace(s) == "" {
+		return RateLimitConfig{}, nil
+	}
+	config := DefaultRateLimitConfig
+	config.Facades = make(map[string]RateLimit)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return RateLimitConfig{}, errors.NotValidf("rate limit %q", field)
+		}
+		limit, err := parseRateLimit(parts[1])
+		if err != nil {
+			return RateLimitConfig{}, errors.Annotatef(err, "rate limit %q", field)
+		}
+		switch parts[0] {
+		case "connection":
+			config.Connection = limit
+		case "entity":
+			config.Entity = limit
+		default:
+			config.Facades[parts[0]] = limit
+		}
+	}
+	return config, nil
+}
+
+func parseRateLimit(s string) (RateLimit, error) {
+	parts := strings.SplitN(s, "/", 2)
+	rate, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || rate < 0 {
+		return RateLimit{}, errors.NotValidf("rate %q", parts[0])
+	}
+	limit := RateLimit{Rate: rate}
+	if len(parts) == 2 {
+		burst, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || burst < 1 {
+			return RateLimit{}, errors.NotValidf("burst %q", parts[1])
+		}
+		limit.Burst = burst
+	}
+	return limit, nil
+}
+
+// newBucket returns a full token bucket implementing the limit, or nil
+// if the limit imposes no restriction.
+func (limit RateLimit) newBucket(now time.Time) *tokenBucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	capacity := float64(limit.Burst)
+	if capacity < 1 {
+		capacity = math.Ceil(limit.Rate)
+	}
+	return &tokenBucket{
+		rate:     limit.Rate,
+		capacity: capacity,
+		tokens:   capacity,
+		updated:  now,
+	}
+}
+
+// tokenBucket holds tokens that are added at rate per second, up to
+// capacity. The tokens may go negative, representing requests that
+// have been allowed but must wait for the bucket to refill.
+type tokenBucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	updated  time.Time
+}
+
+// refill adds the tokens accrued since the bucket was last updated.
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.updated) {
+		b.tokens += now.Sub(b.updated).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.updated = now
+	}
+}
+
+// delay returns how long a request made now must wait for a token.
+func (b *tokenBucket) delay(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// full reports whether the bucket has refilled completely, in which
+// case it is indistinguishable from a new one.
+func (b *tokenBucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= b.capacity
+}
+
+// rateLimitMaxWait is the longest a request will be delayed to keep
+// within the rate limits; requests that would need to wait longer are
+// rejected.
+var rateLimitMaxWait = 5 * time.Second
+
+// rateLimitPruneInterval is how often the rate limiter discards the
+// shared buckets that have refilled since they were last used.
+var rateLimitPruneInterval = time.Minute
+
+// rateLimiter holds the token buckets shared by all the connections
+// made by each authenticated entity.
+type rateLimiter struct {
+	config RateLimitConfig
+	now    func() time.Time
+
+	// mu guards the buckets, both the shared ones and those of
+	// the individual connections, so that a request takes tokens
+	// from all of them or from none.
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	pruned  time.Time
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// sharedBucket returns the bucket with the given key, creating it from
+// limit if necessary. It must be called with l.mu held.
+func (l *rateLimiter) sharedBucket(key string, limit RateLimit, now time.Time) *tokenBucket {
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = limit.newBucket(now)
+		if bucket != nil {
+			l.buckets[key] = bucket
+		}
+	}
+	return bucket
+}
+
+// pruneBuckets discards the shared buckets that have refilled
+// completely, so that the buckets of entities that have gone away do
+// not accumulate. It must be called with l.mu held.
+func (l *rateLimiter) pruneBuckets(now time.Time) {
+	if now.Sub(l.pruned) < rateLimitPruneInterval {
+		return
+	}
+	l.pruned = now
+	for key, bucket := range l.buckets {
+		if bucket.full(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// forConnection returns the limits to apply to a new connection
+// authenticated as the entity with the given tag.
+func (l *rateLimiter) forConnection(tag string) *connRateLimiter {
+	return &connRateLimiter{
+		limiter: l,
+		tag:     tag,
+		conn:    l.config.Connection.newBucket(l.now()),
+	}
+}
+
+// connRateLimiter applies the rate limits to the requests made over a
+// single connection.
+type connRateLimiter struct {
+	limiter *rateLimiter
+	tag     string
+	conn    *tokenBucket
+}
+
+// wait waits until a request to the given facade may be served within
+// the rate limits, returning ErrRateLimitExceeded if that would take
+// too long. A rejected request takes no tokens from any bucket.
+func (c *connRateLimiter) wait(facade string) error {
+	l := c.limiter
+	l.mu.Lock()
+	now := l.now()
+	l.pruneBuckets(now)
+	buckets := []*tokenBucket{
+		c.conn,
+		l.sharedBucket(c.tag, l.config.Entity, now),
+	}
+	if limit, ok := l.config.Facades[facade]; ok {
+		buckets = append(buckets, l.sharedBucket(c.tag+"#"+facade, limit, now))
+	}
+	var delay time.Duration
+	for _, bucket := range buckets {
+		if bucket == nil {
+			continue
+		}
+		if d := bucket.delay(now); d > delay {
+			delay = d
+		}
+	}
+	if delay > rateLimitMaxWait {
+		l.mu.Unlock()
+		logger.Debugf("rate limiting %s calls to %s", c.tag, facade)
+		return common.ErrRateLimitExceeded
+	}
+	for _, bucket := range buckets {
+		if bucket != nil {
+			bucket.tokens--
+		}
+	}
+	l.mu.Unlock()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	return nil
+}
+
+// rateLimitedRoot applies rate limits to the API calls made over a
+// connection.
+type rateLimitedRoot struct {
+	rpc.MethodFinder
+	limiter *connRateLimiter
+}
+
+// newRateLimitedRoot returns a new rateLimitedRoot.
+func newRateLimitedRoot(finder rpc.MethodFinder, limiter *connRateLimiter) *rateLimitedRoot {
+	return &rateLimitedRoot{finder, limiter}
+}
+
+// FindMethod returns a caller that waits for the rate limits to allow
+// the call before making it. The wait happens when the call is made,
+// rather than here, so that it does not hold up the reading of other
+// requests from the connection. Calls to watchers are not limited, as
+// they block until there are changes to report.
+func (r *rateLimitedRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(rootName, "Watcher") {
+		return caller, nil
+	}
+	return &rateLimitedCaller{caller, r.limiter, rootName}, nil
+}
+
+type rateLimitedCaller struct {
+	rpcreflect.MethodCaller
+	limiter *connRateLimiter
+	facade  string
+}
+
+// Call is part of the rpcreflect.MethodCaller interface.
+func (c *rateLimitedCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	if err := c.limiter.wait(c.facade); err != nil {
+		return reflect.Value{}, err
+	}
+	return c.MethodCaller.Call(objId, arg)
+}
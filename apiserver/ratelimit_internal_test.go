@@ -0,0 +1,187 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc/rpcreflect"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type rateLimitIntSuite struct {
+	coretesting.BaseSuite
+	stub testing.Stub
+}
+
+var _ = gc.Suite(&rateLimitIntSuite{})
+
+func (s *rateLimitIntSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	// Nothing waits for the buckets to refill, so each bucket
+	// allows exactly its burst of calls.
+	s.PatchValue(&rateLimitMaxWait, 0)
+}
+
+func (s *rateLimitIntSuite) newRoot(limiter *rateLimiter, tag string) *rateLimitedRoot {
+	return newRateLimitedRoot(&fakeFinder{&s.stub}, limiter.forConnection(tag))
+}
+
+func (s *rateLimitIntSuite) call(c *gc.C, root *rateLimitedRoot, facade string) error {
+	caller, err := root.FindMethod(facade, 1, "Method")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call("", reflect.Value{})
+	return err
+}
+
+func (s *rateLimitIntSuite) TestConnectionLimit(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{
+		Connection: RateLimit{Rate: 0.001, Burst: 2},
+	})
+	root := s.newRoot(limiter, "unit-mysql-0")
+	c.Check(s.call(c, root, "Uniter"), jc.ErrorIsNil)
+	c.Check(s.call(c, root, "Uniter"), jc.ErrorIsNil)
+	c.Check(s.call(c, root, "Uniter"), gc.Equals, common.ErrRateLimitExceeded)
+	s.stub.CheckCallNames(c, "Call", "Call")
+
+	// A new connection has its own allowance.
+	other := s.newRoot(limiter, "unit-mysql-0")
+	c.Check(s.call(c, other, "Uniter"), jc.ErrorIsNil)
+}
+
+func (s *rateLimitIntSuite) TestEntityLimit(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{
+		Entity: RateLimit{Rate: 0.001, Burst: 2},
+	})
+	first := s.newRoot(limiter, "unit-mysql-0")
+	second := s.newRoot(limiter, "unit-mysql-0")
+	c.Check(s.call(c, first, "Uniter"), jc.ErrorIsNil)
+	c.Check(s.call(c, second, "Uniter"), jc.ErrorIsNil)
+	c.Check(s.call(c, first, "Uniter"), gc.Equals, common.ErrRateLimitExceeded)
+	c.Check(s.call(c, second, "Uniter"), gc.Equals, common.ErrRateLimitExceeded)
+
+	// Other entities are unaffected.
+	other := s.newRoot(limiter, "unit-mysql-1")
+	c.Check(s.call(c, other, "Uniter"), jc.ErrorIsNil)
+}
+
+func (s *rateLimitIntSuite) TestFacadeLimit(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{
+		Facades: map[string]RateLimit{"Client": {Rate: 0.001, Burst: 1}},
+	})
+	root := s.newRoot(limiter, "user-admin@local")
+	c.Check(s.call(c, root, "Client"), jc.ErrorIsNil)
+	c.Check(s.call(c, root, "Client"), gc.Equals, common.ErrRateLimitExceeded)
+	c.Check(s.call(c, root, "KeyManager"), jc.ErrorIsNil)
+	c.Check(s.call(c, root, "KeyManager"), jc.ErrorIsNil)
+}
+
+func (s *rateLimitIntSuite) TestRejectedCallTakesNoTokens(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{
+		Entity:  RateLimit{Rate: 0.001, Burst: 2},
+		Facades: map[string]RateLimit{"Client": {Rate: 0.001, Burst: 1}},
+	})
+	root := s.newRoot(limiter, "user-admin@local")
+	c.Check(s.call(c, root, "Client"), jc.ErrorIsNil)
+	c.Check(s.call(c, root, "Client"), gc.Equals, common.ErrRateLimitExceeded)
+	c.Check(s.call(c, root, "Client"), gc.Equals, common.ErrRateLimitExceeded)
+
+	// The rejected calls left the entity's allowance untouched.
+	c.Check(s.call(c, root, "KeyManager"), jc.ErrorIsNil)
+	c.Check(s.call(c, root, "KeyManager"), gc.Equals, common.ErrRateLimitExceeded)
+}
+
+func (s *rateLimitIntSuite) TestWatchersNotLimited(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{
+		Connection: RateLimit{Rate: 0.001, Burst: 1},
+	})
+	root := s.newRoot(limiter, "unit-mysql-0")
+	for i := 0; i < 10; i++ {
+		c.Assert(s.call(c, root, "NotifyWatcher"), jc.ErrorIsNil)
+	}
+	c.Check(s.call(c, root, "Uniter"), jc.ErrorIsNil)
+	c.Check(s.call(c, root, "Uniter"), gc.Equals, common.ErrRateLimitExceeded)
+}
+
+func (s *rateLimitIntSuite) TestIdleBucketsExpire(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{
+		Entity: RateLimit{Rate: 0.01, Burst: 1},
+	})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	bucketKeys := func() []string {
+		var keys []string
+		for key := range limiter.buckets {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	c.Check(s.call(c, s.newRoot(limiter, "unit-mysql-0"), "Uniter"), jc.ErrorIsNil)
+	c.Check(bucketKeys(), jc.DeepEquals, []string{"unit-mysql-0"})
+
+	// By now unit-mysql-0's bucket has refilled, so it is discarded.
+	now = now.Add(2 * time.Minute)
+	c.Check(s.call(c, s.newRoot(limiter, "unit-mysql-1"), "Uniter"), jc.ErrorIsNil)
+	c.Check(bucketKeys(), jc.DeepEquals, []string{"unit-mysql-1"})
+
+	// unit-mysql-1's bucket has not refilled yet, so it is kept.
+	now = now.Add(time.Minute)
+	c.Check(s.call(c, s.newRoot(limiter, "unit-mysql-2"), "Uniter"), jc.ErrorIsNil)
+	c.Check(bucketKeys(), jc.DeepEquals, []string{"unit-mysql-1", "unit-mysql-2"})
+	c.Check(s.call(c, s.newRoot(limiter, "unit-mysql-1"), "Uniter"), gc.Equals, common.ErrRateLimitExceeded)
+}
+
+func (s *rateLimitIntSuite) TestNoLimits(c *gc.C) {
+	root := s.newRoot(newRateLimiter(RateLimitConfig{}), "machine-1")
+	for i := 0; i < 100; i++ {
+		c.Assert(s.call(c, root, "Provisioner"), jc.ErrorIsNil)
+	}
+}
+
+func (s *rateLimitIntSuite) TestFindMethodError(c *gc.C) {
+	s.stub.SetErrors(errors.New("no such method"))
+	root := s.newRoot(newRateLimiter(RateLimitConfig{}), "machine-1")
+	_, err := root.FindMethod("Provisioner", 1, "Method")
+	c.Assert(err, gc.ErrorMatches, "no such method")
+}
+
+type fakeFinder struct {
+	stub *testing.Stub
+}
+
+func (f *fakeFinder) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	f.stub.AddCall("FindMethod", rootName, version, methodName)
+	if err := f.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return &fakeCaller{f.stub}, nil
+}
+
+type fakeCaller struct {
+	stub *testing.Stub
+}
+
+func (f *fakeCaller) ParamsType() reflect.Type {
+	return nil
+}
+
+func (f *fakeCaller) ResultType() reflect.Type {
+	return nil
+}
+
+func (f *fakeCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	f.stub.AddCall("Call", objId)
+	return reflect.Value{}, f.stub.NextErr()
+}
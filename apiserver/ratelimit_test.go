@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type rateLimitConfigSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&rateLimitConfigSuite{})
+
+func (s *rateLimitConfigSuite) TestParseRateLimitConfig(c *gc.C) {
+	for i, test := range []struct {
+		value    string
+		expected apiserver.RateLimitConfig
+		err      string
+	}{{
+		value:    "",
+		expected: apiserver.RateLimitConfig{},
+	}, {
+		value: "Client=10/20",
+		expected: apiserver.RateLimitConfig{
+			Connection: apiserver.DefaultRateLimitConfig.Connection,
+			Entity:     apiserver.DefaultRateLimitConfig.Entity,
+			Facades: map[string]apiserver.RateLimit{
+				"Client": {Rate: 10, Burst: 20},
+			},
+		},
+	}, {
+		value: "connection=20/40, entity=0,Client=2.5",
+		expected: apiserver.RateLimitConfig{
+			Connection: apiserver.RateLimit{Rate: 20, Burst: 40},
+			Facades: map[string]apiserver.RateLimit{
+				"Client": {Rate: 2.5},
+			},
+		},
+	}, {
+		value: "connection",
+		err:   `rate limit "connection" not valid`,
+	}, {
+		value: "=5",
+		err:   `rate limit "=5" not valid`,
+	}, {
+		value: "entity=fast",
+		err:   `rate limit "entity=fast": rate "fast" not valid`,
+	}, {
+		value: "entity=-1",
+		err:   `rate limit "entity=-1": rate "-1" not valid`,
+	}, {
+		value: "entity=5/0",
+		err:   `rate limit "entity=5/0": burst "0" not valid`,
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		config, err := apiserver.ParseRateLimitConfig(test.value)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(config, jc.DeepEquals, test.expected)
+	}
+}
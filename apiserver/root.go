@@ -203,6 +203,7 @@ func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (r
 			objValue = asInterface
 		}
 		r.objectCache[objKey] = objValue
+		r.warnIfDeprecated(rootName, version)
 		return objValue, nil
 	}
 	return &srvCaller{
@@ -211,6 +212,21 @@ func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (r
 	}, nil
 }
 
+// warnIfDeprecated logs a warning if the given facade version is slated
+// for removal, so that operators can find the clients and agents that
+// need upgrading before it goes.
+func (r *apiRoot) warnIfDeprecated(rootName string, version int) {
+	notice, ok := common.Facades.Deprecation(rootName, version)
+	if !ok {
+		return
+	}
+	client := "client"
+	if r.authorizer != nil {
+		client = r.authorizer.GetAuthTag().String()
+	}
+	logger.Warningf("%s is using deprecated facade %s(%d): %s", client, rootName, version, notice)
+}
+
 func (r *apiRoot) lookupMethod(rootName string, version int, methodName string) (reflect.Type, rpcreflect.ObjMethod, error) {
 	noMethod := rpcreflect.ObjMethod{}
 	goType, err := common.Facades.GetType(rootName, version)
@@ -319,6 +335,14 @@ func DescribeFacades() []params.FacadeVersions {
 	for i, facade := range facades {
 		result[i].Name = facade.Name
 		result[i].Versions = facade.Versions
+		for _, version := range facade.Versions {
+			if notice, ok := facade.Deprecations[version]; ok {
+				result[i].Deprecations = append(result[i].Deprecations, params.FacadeDeprecation{
+					Version: version,
+					Notice:  notice,
+				})
+			}
+		}
 	}
 	return result
 }
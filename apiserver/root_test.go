@@ -9,12 +9,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/juju/loggo"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
@@ -385,6 +387,65 @@ func (r *rootSuite) TestDescribeFacades(c *gc.C) {
 	c.Check(clientVersions[0], gc.Equals, 0)
 }
 
+func (r *rootSuite) TestDescribeFacadesIncludesDeprecations(c *gc.C) {
+	defer common.Facades.Discard("my-counting-facade", 0)
+	defer common.Facades.Discard("my-counting-facade", 1)
+	newCounter := func(*state.State, *common.Resources, common.Authorizer) (*countingType, error) {
+		return &countingType{}, nil
+	}
+	common.RegisterStandardFacade("my-counting-facade", 0, newCounter)
+	common.RegisterStandardFacade("my-counting-facade", 1, newCounter)
+	common.DeprecateFacade("my-counting-facade", 0, "use my-counting-facade(1)")
+	var found bool
+	for _, facade := range apiserver.DescribeFacades() {
+		if facade.Name != "my-counting-facade" {
+			continue
+		}
+		found = true
+		c.Check(facade, jc.DeepEquals, params.FacadeVersions{
+			Name:     "my-counting-facade",
+			Versions: []int{0, 1},
+			Deprecations: []params.FacadeDeprecation{{
+				Version: 0,
+				Notice:  "use my-counting-facade(1)",
+			}},
+		})
+	}
+	c.Check(found, jc.IsTrue)
+}
+
+func (r *rootSuite) TestFindMethodWarnsOfDeprecatedFacade(c *gc.C) {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("deprecation-test", &tw, loggo.WARNING), gc.IsNil)
+	defer loggo.RemoveWriter("deprecation-test")
+
+	srvRoot := apiserver.TestingApiRoot(nil)
+	defer common.Facades.Discard("my-counting-facade", 0)
+	defer common.Facades.Discard("my-counting-facade", 1)
+	newCounter := func(*state.State, *common.Resources, common.Authorizer) (*countingType, error) {
+		return &countingType{count: 1}, nil
+	}
+	common.RegisterStandardFacade("my-counting-facade", 0, newCounter)
+	common.RegisterStandardFacade("my-counting-facade", 1, newCounter)
+	common.DeprecateFacade("my-counting-facade", 0, "use my-counting-facade(1)")
+
+	caller, err := srvRoot.FindMethod("my-counting-facade", 1, "Count")
+	c.Assert(err, jc.ErrorIsNil)
+	assertCallResult(c, caller, "", "1")
+	c.Check(tw.Log(), gc.HasLen, 0)
+
+	// The warning is only logged when the facade is first used.
+	for i := 0; i < 2; i++ {
+		caller, err = srvRoot.FindMethod("my-counting-facade", 0, "Count")
+		c.Assert(err, jc.ErrorIsNil)
+		assertCallResult(c, caller, "", "1")
+	}
+	c.Check(tw.Log(), jc.LogMatches, []jc.SimpleMessage{{
+		loggo.WARNING, `client is using deprecated facade my-counting-facade\(0\): use my-counting-facade\(1\)`,
+	}})
+	c.Check(tw.Log(), gc.HasLen, 1)
+}
+
 type stubStateEntity struct{ tag names.Tag }
 
 func (e *stubStateEntity) Tag() names.Tag { return e.tag }
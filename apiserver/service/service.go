@@ -103,6 +103,46 @@ func (api *API) ServicesDeployWithPlacement(args params.ServicesDeploy) (params.
 	return result, nil
 }
 
+// ServiceDestroyImpact returns the entities that will be removed, or
+// otherwise affected, by destroying the given service. Nothing is
+// changed.
+func (api *API) ServiceDestroyImpact(args params.ServiceDestroy) (params.RemovalImpact, error) {
+	impact, err := api.state.ServiceRemovalImpact(args.ServiceName)
+	if err != nil {
+		return params.RemovalImpact{}, errors.Trace(err)
+	}
+	return fromRemovalImpact(impact), nil
+}
+
+// DestroyUnitsImpact returns the entities that will be removed, or
+// otherwise affected, by destroying the given principal units.
+// Nothing is changed.
+func (api *API) DestroyUnitsImpact(args params.DestroyServiceUnits) (params.RemovalImpact, error) {
+	impact, err := api.state.UnitsRemovalImpact(args.UnitNames)
+	if err != nil {
+		return params.RemovalImpact{}, errors.Trace(err)
+	}
+	return fromRemovalImpact(impact), nil
+}
+
+func fromRemovalImpact(impact *state.RemovalImpact) params.RemovalImpact {
+	result := params.RemovalImpact{
+		Units:            impact.Units,
+		Relations:        impact.Relations,
+		Machines:         impact.Machines,
+		EmptyMachines:    impact.EmptyMachines,
+		DetachedStorage:  impact.DetachedStorage,
+		DestroyedStorage: impact.DestroyedStorage,
+	}
+	if len(impact.UnitErrors) > 0 {
+		result.UnitErrors = make(map[string]*params.Error)
+		for name, err := range impact.UnitErrors {
+			result.UnitErrors[name] = common.ServerError(err)
+		}
+	}
+	return result
+}
+
 // DeployService fetches the charm from the charm store and deploys it.
 // The logic has been factored out into a common function which is called by
 // both the legacy API on the client facade, as well as the new service facade.
@@ -160,15 +200,16 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 		jjj.DeployServiceParams{
 			ServiceName: args.ServiceName,
 			// TODO(dfc) ServiceOwner should be a tag
-			ServiceOwner:   owner,
-			Charm:          ch,
-			NumUnits:       args.NumUnits,
-			ConfigSettings: settings,
-			Constraints:    args.Constraints,
-			ToMachineSpec:  args.ToMachineSpec,
-			Placement:      args.Placement,
-			Networks:       requestedNetworks,
-			Storage:        args.Storage,
+			ServiceOwner:     owner,
+			Charm:            ch,
+			NumUnits:         args.NumUnits,
+			ConfigSettings:   settings,
+			Constraints:      args.Constraints,
+			ToMachineSpec:    args.ToMachineSpec,
+			Placement:        args.Placement,
+			Networks:         requestedNetworks,
+			Storage:          args.Storage,
+			EndpointBindings: args.EndpointBindings,
 		})
 	return err
 }
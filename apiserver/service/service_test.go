@@ -152,6 +152,70 @@ func (s *serviceSuite) TestSetMetricCredentials(c *gc.C) {
 	}
 }
 
+func (s *serviceSuite) TestServiceDestroyImpact(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service})
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	impact, err := s.serviceApi.ServiceDestroyImpact(params.ServiceDestroy{s.service.Name()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(impact, jc.DeepEquals, params.RemovalImpact{
+		Units:    []string{unit.Name()},
+		Machines: []string{machineId},
+	})
+
+	// Nothing is destroyed.
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.Life(), gc.Equals, state.Alive)
+}
+
+func (s *serviceSuite) TestServiceDestroyImpactNotFound(c *gc.C) {
+	_, err := s.serviceApi.ServiceDestroyImpact(params.ServiceDestroy{"not-a-service"})
+	c.Assert(err, gc.ErrorMatches, `service "not-a-service" not found`)
+}
+
+func (s *serviceSuite) TestDestroyUnitsImpact(c *gc.C) {
+	unit0 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service})
+	unit1 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service})
+	machineId, err := unit1.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	impact, err := s.serviceApi.DestroyUnitsImpact(params.DestroyServiceUnits{
+		UnitNames: []string{unit1.Name()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(impact, jc.DeepEquals, params.RemovalImpact{
+		Units:    []string{unit1.Name()},
+		Machines: []string{machineId},
+	})
+
+	// Nothing is destroyed.
+	for _, unit := range []*state.Unit{unit0, unit1} {
+		err = unit.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(unit.Life(), gc.Equals, state.Alive)
+	}
+}
+
+func (s *serviceSuite) TestDestroyUnitsImpactUnknownUnit(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service})
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	impact, err := s.serviceApi.DestroyUnitsImpact(params.DestroyServiceUnits{
+		UnitNames: []string{unit.Name(), "foo/9"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(impact, jc.DeepEquals, params.RemovalImpact{
+		Units:    []string{unit.Name()},
+		Machines: []string{machineId},
+		UnitErrors: map[string]*params.Error{
+			"foo/9": {Message: `unit "foo/9" not found`, Code: params.CodeNotFound},
+		},
+	})
+}
+
 func (s *serviceSuite) TestCompatibleSettingsParsing(c *gc.C) {
 	// Test the exported settings parsing in a compatible way.
 	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
@@ -180,7 +180,7 @@ func (api *spacesAPI) supportsSpaces() error {
 	if err != nil {
 		return errors.Annotate(err, "validating environment config")
 	}
-	netEnv, ok := environs.SupportsNetworking(env)
+	netEnv, ok := environs.NetworkingCapable(config.Type(), env)
 	if !ok {
 		return errors.NotSupportedf("networking")
 	}
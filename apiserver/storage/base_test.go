@@ -83,6 +83,8 @@ const (
 	allFilesystemsCall                      = "allFilesystems"
 	addStorageForUnitCall                   = "addStorageForUnit"
 	getBlockForTypeCall                     = "getBlockForType"
+	detachStorageCall                       = "detachStorage"
+	attachStorageCall                       = "attachStorage"
 	volumeAttachmentCall                    = "volumeAttachment"
 )
 
@@ -222,6 +224,14 @@ func (s *baseStorageSuite) constructState() *mockState {
 			s.calls = append(s.calls, addStorageForUnitCall)
 			return nil
 		},
+		detachStorage: func(storage names.StorageTag, unit names.UnitTag) error {
+			s.calls = append(s.calls, detachStorageCall)
+			return nil
+		},
+		attachStorage: func(storage names.StorageTag, unit names.UnitTag) error {
+			s.calls = append(s.calls, attachStorageCall)
+			return nil
+		},
 		getBlockForType: func(t state.BlockType) (state.Block, bool, error) {
 			s.calls = append(s.calls, getBlockForTypeCall)
 			val, found := s.blocks[t]
@@ -58,6 +58,8 @@ type mockState struct {
 	filesystemAttachments               func(filesystem names.FilesystemTag) ([]state.FilesystemAttachment, error)
 	allFilesystems                      func() ([]state.Filesystem, error)
 	addStorageForUnit                   func(u names.UnitTag, name string, cons state.StorageConstraints) error
+	detachStorage                       func(storage names.StorageTag, unit names.UnitTag) error
+	attachStorage                       func(storage names.StorageTag, unit names.UnitTag) error
 	getBlockForType                     func(t state.BlockType) (state.Block, bool, error)
 	blockDevices                        func(names.MachineTag) ([]state.BlockDeviceInfo, error)
 }
@@ -150,6 +152,14 @@ func (st *mockState) AddStorageForUnit(u names.UnitTag, name string, cons state.
 	return st.addStorageForUnit(u, name, cons)
 }
 
+func (st *mockState) DetachStorage(storage names.StorageTag, unit names.UnitTag) error {
+	return st.detachStorage(storage, unit)
+}
+
+func (st *mockState) AttachStorage(storage names.StorageTag, unit names.UnitTag) error {
+	return st.attachStorage(storage, unit)
+}
+
 func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
 	return st.getBlockForType(t)
 }
@@ -80,6 +80,12 @@ type storageAccess interface {
 	// AddStorageForUnit is required for storage add functionality.
 	AddStorageForUnit(tag names.UnitTag, name string, cons state.StorageConstraints) error
 
+	// DetachStorage is required for storage detach functionality.
+	DetachStorage(storage names.StorageTag, unit names.UnitTag) error
+
+	// AttachStorage is required for storage attach functionality.
+	AttachStorage(storage names.StorageTag, unit names.UnitTag) error
+
 	// GetBlockForType is required to block operations.
 	GetBlockForType(t state.BlockType) (state.Block, bool, error)
 }
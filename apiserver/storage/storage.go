@@ -694,3 +694,76 @@ func (a *API) AddToUnit(args params.StoragesAddParams) (params.ErrorResults, err
 	}
 	return params.ErrorResults{Results: result}, nil
 }
+
+// Detach detaches storage instances from the units that own them, without
+// destroying the storage. Detached storage is released to the owning unit's
+// service, and may later be attached to another unit of that service.
+// A "CHANGE" block can block this operation.
+func (a *API) Detach(args params.Entities) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	result := make([]params.ErrorResult, len(args.Entities))
+	for i, entity := range args.Entities {
+		err := a.detachStorage(entity.Tag)
+		if err != nil {
+			result[i].Error = common.ServerError(err)
+		}
+	}
+	return params.ErrorResults{Results: result}, nil
+}
+
+func (a *API) detachStorage(tag string) error {
+	storageTag, err := names.ParseStorageTag(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	si, err := a.storage.StorageInstance(storageTag)
+	if errors.IsNotFound(err) {
+		return common.ErrPerm
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	unitTag, ok := si.Owner().(names.UnitTag)
+	if !ok {
+		return errors.Errorf("storage %s is not attached to a unit", storageTag.Id())
+	}
+	return a.storage.DetachStorage(storageTag, unitTag)
+}
+
+// Attach attaches detached storage instances to units, which then become
+// the owners of the storage.
+// A "CHANGE" block can block this operation.
+func (a *API) Attach(args params.StorageAttachmentIds) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	result := make([]params.ErrorResult, len(args.Ids))
+	for i, id := range args.Ids {
+		err := a.attachStorage(id)
+		if err != nil {
+			result[i].Error = common.ServerError(err)
+		}
+	}
+	return params.ErrorResults{Results: result}, nil
+}
+
+func (a *API) attachStorage(id params.StorageAttachmentId) error {
+	storageTag, err := names.ParseStorageTag(id.StorageTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	unitTag, err := names.ParseUnitTag(id.UnitTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = a.storage.AttachStorage(storageTag, unitTag)
+	if errors.IsNotFound(err) {
+		return common.ErrPerm
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type storageAttachSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&storageAttachSuite{})
+
+func (s *storageAttachSuite) TestDetach(c *gc.C) {
+	var detached []names.Tag
+	s.state.detachStorage = func(storage names.StorageTag, unit names.UnitTag) error {
+		s.calls = append(s.calls, detachStorageCall)
+		detached = append(detached, storage, unit)
+		return nil
+	}
+	results, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{{Tag: s.storageTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(detached, jc.DeepEquals, []names.Tag{s.storageTag, s.unitTag})
+	s.assertCalls(c, []string{getBlockForTypeCall, storageInstanceCall, detachStorageCall})
+}
+
+func (s *storageAttachSuite) TestDetachErrors(c *gc.C) {
+	s.state.detachStorage = func(storage names.StorageTag, unit names.UnitTag) error {
+		s.calls = append(s.calls, detachStorageCall)
+		return errors.New("boom")
+	}
+	results, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "storage-foo-42"},
+			{Tag: s.storageTag.String()},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: `"machine-0" is not a valid storage tag`}},
+			{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+			{Error: &params.Error{Message: "boom"}},
+		},
+	})
+}
+
+func (s *storageAttachSuite) TestDetachNotOwnedByUnit(c *gc.C) {
+	s.storageInstance.owner = names.NewServiceTag("mysql")
+	results, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{{Tag: s.storageTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "storage data/0 is not attached to a unit")
+	s.assertCalls(c, []string{getBlockForTypeCall, storageInstanceCall})
+}
+
+func (s *storageAttachSuite) TestDetachBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestDetachBlocked")
+	_, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{{Tag: s.storageTag.String()}},
+	})
+	s.assertBlocked(c, err, "TestDetachBlocked")
+}
+
+func (s *storageAttachSuite) TestAttach(c *gc.C) {
+	unitTag := names.NewUnitTag("mysql/1")
+	var attached []names.Tag
+	s.state.attachStorage = func(storage names.StorageTag, unit names.UnitTag) error {
+		s.calls = append(s.calls, attachStorageCall)
+		attached = append(attached, storage, unit)
+		return nil
+	}
+	results, err := s.api.Attach(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: s.storageTag.String(),
+			UnitTag:    unitTag.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(attached, jc.DeepEquals, []names.Tag{s.storageTag, unitTag})
+	s.assertCalls(c, []string{getBlockForTypeCall, attachStorageCall})
+}
+
+func (s *storageAttachSuite) TestAttachErrors(c *gc.C) {
+	s.state.attachStorage = func(storage names.StorageTag, unit names.UnitTag) error {
+		s.calls = append(s.calls, attachStorageCall)
+		return errors.NotFoundf("unit %s", unit.Id())
+	}
+	results, err := s.api.Attach(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: s.storageTag.String(),
+			UnitTag:    "machine-0",
+		}, {
+			StorageTag: s.storageTag.String(),
+			UnitTag:    "unit-mysql-1",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: `"machine-0" is not a valid unit tag`}},
+			{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+		},
+	})
+}
+
+func (s *storageAttachSuite) TestAttachBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestAttachBlocked")
+	_, err := s.api.Attach(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: s.storageTag.String(),
+			UnitTag:    s.unitTag.String(),
+		}},
+	})
+	s.assertBlocked(c, err, "TestAttachBlocked")
+}
@@ -142,6 +142,13 @@ func (api *subnetsAPI) zonedEnviron() (providercommon.ZonedEnviron, error) {
 		return nil, errors.Annotate(err, "getting environment config")
 	}
 
+	capable, err := environs.HasCapability(envConfig.Type(), environs.CapabilityAvailabilityZones)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !capable {
+		return nil, errors.NotSupportedf("availability zones")
+	}
 	env, err := environs.New(envConfig)
 	if err != nil {
 		return nil, errors.Annotate(err, "opening environment")
@@ -166,7 +173,7 @@ func (api *subnetsAPI) networkingEnviron() (environs.NetworkingEnviron, error) {
 	if err != nil {
 		return nil, errors.Annotate(err, "opening environment")
 	}
-	if netEnv, ok := environs.SupportsNetworking(env); ok {
+	if netEnv, ok := environs.NetworkingCapable(envConfig.Type(), env); ok {
 		return netEnv, nil
 	}
 	return nil, errors.NotSupportedf("environment networking features") // " not supported"
@@ -103,7 +103,15 @@ func (s StubNetwork) SetUpSuite(c *gc.C) {
 		AvailabilityZones: []string{"zone3"},
 	}}
 
-	environs.RegisterProvider(StubProviderType, ProviderInstance)
+	environs.Register(environs.ProviderRegistration{
+		Type:       StubProviderType,
+		Provider:   ProviderInstance,
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityNetworking,
+			environs.CapabilityAvailabilityZones,
+		},
+	})
 }
 
 type errReturner func() error
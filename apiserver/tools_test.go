@@ -121,6 +121,12 @@ func (s *toolsSuite) TestRequiresPOST(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "PUT"`)
 }
 
+func (s *toolsSuite) TestPOSTRequiresWriteAccess(c *gc.C) {
+	s.setupReadOnlyUser(c)
+	resp := s.authRequest(c, httpRequestParams{method: "POST", url: s.toolsURI(c, "")})
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "permission denied")
+}
+
 func (s *toolsSuite) TestAuthRequiresUser(c *gc.C) {
 	// Add a machine and try to login.
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
@@ -156,6 +156,8 @@ func (u *uniterBaseAPI) PublicAddress(args params.Entities) (params.StringResult
 }
 
 // PrivateAddress returns the private address for each given unit, if set.
+// If a unit's service has endpoints bound to network spaces, an address
+// in one of those spaces is preferred.
 func (u *uniterBaseAPI) PrivateAddress(args params.Entities) (params.StringResults, error) {
 	result := params.StringResults{
 		Results: make([]params.StringResult, len(args.Entities)),
@@ -176,7 +178,7 @@ func (u *uniterBaseAPI) PrivateAddress(args params.Entities) (params.StringResul
 			unit, err = u.getUnit(tag)
 			if err == nil {
 				var address network.Address
-				address, err = unit.PrivateAddress()
+				address, err = unit.BoundPrivateAddress()
 				if err == nil {
 					result.Results[i].Result = address.Value
 				} else if network.IsNoAddress(err) {
@@ -433,10 +435,9 @@ func (u *uniterBaseAPI) CharmURL(args params.Entities) (params.StringBoolResults
 			var unitOrService state.Entity
 			unitOrService, err = u.st.FindEntity(tag)
 			if err == nil {
-				charmURLer := unitOrService.(interface {
-					CharmURL() (*charm.URL, bool)
-				})
-				curl, ok := charmURLer.CharmURL()
+				var curl *charm.URL
+				var ok bool
+				curl, ok, err = u.charmURL(unitOrService)
 				if curl != nil {
 					result.Results[i].Result = curl.String()
 					result.Results[i].Ok = ok
@@ -448,6 +449,26 @@ func (u *uniterBaseAPI) CharmURL(args params.Entities) (params.StringBoolResults
 	return result, nil
 }
 
+// charmURL returns the charm URL of the given unit or service. When
+// a unit asks for its service's charm URL, the URL it should upgrade
+// to is returned, taking the service's upgrade strategy into account.
+func (u *uniterBaseAPI) charmURL(unitOrService state.Entity) (*charm.URL, bool, error) {
+	if _, ok := unitOrService.(*state.Service); ok {
+		if unitTag, ok := u.auth.GetAuthTag().(names.UnitTag); ok {
+			unit, err := u.st.Unit(unitTag.Id())
+			if err != nil {
+				return nil, false, err
+			}
+			return unit.TargetCharmURL()
+		}
+	}
+	charmURLer := unitOrService.(interface {
+		CharmURL() (*charm.URL, bool)
+	})
+	curl, ok := charmURLer.CharmURL()
+	return curl, ok, nil
+}
+
 // SetCharmURL sets the charm URL for each given unit. An error will
 // be returned if a unit is dead, or the charm URL is not know.
 func (u *uniterBaseAPI) SetCharmURL(args params.EntitiesCharmURL) (params.ErrorResults, error) {
@@ -792,6 +792,43 @@ func (s *uniterBaseSuite) testCharmURL(
 	})
 }
 
+func (s *uniterBaseSuite) testCharmURLRollingUpgrade(
+	c *gc.C,
+	facade interface {
+		CharmURL(args params.Entities) (params.StringBoolResults, error)
+	},
+) {
+	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetUpgradeStrategy(state.UpgradeStrategy{
+		Mode:           state.UpgradeRolling,
+		MaxUnavailable: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	newCharm := s.Factory.MakeCharm(c, &jujuFactory.CharmParams{
+		Name: "wordpress",
+		URL:  "cs:quantal/wordpress-4",
+	})
+	err = s.wordpress.SetCharm(newCharm, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The unit keeps its current charm until the rollout reaches it.
+	args := params.Entities{Entities: []params.Entity{{Tag: "service-wordpress"}}}
+	result, err := facade.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{{Result: s.wpCharm.String()}},
+	})
+
+	err = s.State.AdvanceCharmRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = facade.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{{Result: newCharm.String()}},
+	})
+}
+
 func (s *uniterBaseSuite) testSetCharmURL(
 	c *gc.C,
 	facade interface {
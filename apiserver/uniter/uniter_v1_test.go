@@ -126,6 +126,10 @@ func (s *uniterV1Suite) TestCharmURL(c *gc.C) {
 	s.testCharmURL(c, s.uniter)
 }
 
+func (s *uniterV1Suite) TestCharmURLRollingUpgrade(c *gc.C) {
+	s.testCharmURLRollingUpgrade(c, s.uniter)
+}
+
 func (s *uniterV1Suite) TestSetCharmURL(c *gc.C) {
 	s.testSetCharmURL(c, s.uniter)
 }
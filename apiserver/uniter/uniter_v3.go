@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The uniter package implements the API interface used by the uniter
+// worker. This file contains the API facade version 3.
+
+package uniter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/process"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Uniter", 3, NewUniterAPIV3)
+}
+
+// UniterAPIV3 implements the API version 3, used by the uniter worker.
+// It adds the tracking of workload processes.
+type UniterAPIV3 struct {
+	UniterAPIV2
+}
+
+// SetProcesses records the workload processes tracked by each of the
+// specified units, replacing any previously recorded.
+func (u *UniterAPIV3) SetProcesses(args params.UnitProcessesParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Units)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Units {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		infos := make([]process.Info, len(arg.Processes))
+		for j, p := range arg.Processes {
+			infos[j] = common.ProcessInfoFromParams(p)
+		}
+		err = unit.SetProcesses(infos)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WatchProcesses returns a NotifyWatcher for observing changes to
+// the workload processes tracked by each of the specified units.
+func (u *UniterAPIV3) WatchProcesses(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		watcherId := ""
+		if canAccess(tag) {
+			watcherId, err = u.watchOneUnitProcesses(tag)
+		}
+		result.Results[i].NotifyWatcherId = watcherId
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV3) watchOneUnitProcesses(tag names.UnitTag) (string, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return "", err
+	}
+	watch := unit.WatchProcesses()
+	// Consume the initial event, which is implicitly delivered by
+	// the Watch call itself.
+	if _, ok := <-watch.Changes(); ok {
+		return u.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
+
+// NewUniterAPIV3 creates a new instance of the Uniter API, version 3.
+func NewUniterAPIV3(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV3, error) {
+	baseAPI, err := NewUniterAPIV2(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV3{
+		UniterAPIV2: *baseAPI,
+	}, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/process"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type uniterV3Suite struct {
+	uniterBaseSuite
+	uniter *uniter.UniterAPIV3
+}
+
+var _ = gc.Suite(&uniterV3Suite{})
+
+func (s *uniterV3Suite) SetUpTest(c *gc.C) {
+	s.uniterBaseSuite.setUpTest(c)
+
+	uniterAPIV3, err := uniter.NewUniterAPIV3(
+		s.State,
+		s.resources,
+		s.authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.uniter = uniterAPIV3
+}
+
+func (s *uniterV3Suite) TestSetProcesses(c *gc.C) {
+	args := params.UnitProcessesParams{
+		Units: []params.UnitProcesses{{
+			Tag: "unit-wordpress-0",
+			Processes: []params.ProcessInfo{{
+				Name: "web",
+				Type: "docker",
+				Details: params.ProcessDetails{
+					ID:     "abc123",
+					Status: "running",
+					Extra:  map[string]interface{}{"image": "nginx"},
+				},
+			}},
+		}, {
+			Tag: "unit-wordpress-0",
+			Processes: []params.ProcessInfo{{
+				Name:    "web",
+				Type:    "unknown",
+				Details: params.ProcessDetails{ID: "abc123", Status: "running"},
+			}},
+		}, {
+			Tag: "unit-mysql-0",
+		}, {
+			Tag: "machine-1",
+		}},
+	}
+	result, err := s.uniter.SetProcesses(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `cannot set processes for unit "wordpress/0": process plugin "unknown" not found`)
+	c.Assert(result.Results[2:], gc.DeepEquals, []params.ErrorResult{
+		{Error: apiservertesting.ErrUnauthorized},
+		{Error: apiservertesting.ErrUnauthorized},
+	})
+
+	infos, err := s.wordpressUnit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{{
+		Name: "web",
+		Type: "docker",
+		Details: process.Details{
+			ID:     "abc123",
+			Status: "running",
+			Extra:  map[string]interface{}{"image": "nginx"},
+		},
+	}})
+}
+
+func (s *uniterV3Suite) TestWatchProcesses(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WatchProcesses(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event, and reports
+	// subsequent changes to the unit's processes.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+	err = s.wordpressUnit.SetProcesses([]process.Info{{
+		Name: "web",
+		Type: "docker",
+		Details: process.Details{
+			ID:     "abc123",
+			Status: "exited",
+			Extra:  map[string]interface{}{"image": "nginx"},
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
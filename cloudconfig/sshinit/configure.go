@@ -24,6 +24,10 @@ type ConfigureParams struct {
 	// If Client is nil, ssh.DefaultClient will be used.
 	Client ssh.Client
 
+	// Options holds the options for the SSH connection.
+	// If Options is nil, the default options will be used.
+	Options *ssh.Options
+
 	// Config is the cloudinit config to carry out.
 	Config cloudinit.CloudConfig
 
@@ -54,7 +58,7 @@ func Configure(params ConfigureParams) error {
 // to have been returned by cloudinit ConfigureScript.
 func RunConfigureScript(script string, params ConfigureParams) error {
 	logger.Tracef("Running script on %s: %s", params.Host, script)
-	cmd := ssh.Command(params.Host, []string{"sudo", "/bin/bash"}, nil)
+	cmd := ssh.Command(params.Host, []string{"sudo", "/bin/bash"}, params.Options)
 	cmd.Stdin = strings.NewReader(script)
 	cmd.Stderr = params.ProgressWriter
 	return cmd.Run()
@@ -17,6 +17,7 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/juju"
 )
 
@@ -105,7 +106,13 @@ type baseCommandWrapper struct {
 // Run implements Command.Run.
 func (w *baseCommandWrapper) Run(ctx *cmd.Context) error {
 	defer w.closeContext()
-	return w.CommandBase.Run(ctx)
+	err := w.CommandBase.Run(ctx)
+	if _, ok := errors.Cause(err).(*params.Error); ok {
+		// The failure came from the API server; tell the user how
+		// to find the requests this command made in its logs.
+		logger.Errorf("API request failed (correlation id %s)", juju.CorrelationId())
+	}
+	return err
 }
 
 // SetFlags implements Command.SetFlags.
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -33,6 +34,7 @@ type doCommand struct {
 	actionName   string
 	paramsYAML   cmd.FileVar
 	parseStrings bool
+	wait         string
 	out          cmd.Output
 	args         [][]string
 }
@@ -53,6 +55,10 @@ is a boolean true in YAML.
 If --params is passed, along with key.key...=value explicit arguments, the
 explicit arguments will override the parameter file.
 
+To wait for the Action to complete or fail and display its results, use the
+--wait flag with a duration, as for "juju action fetch".  Use --wait 0 to
+wait indefinitely.
+
 Examples:
 
 $ juju action do mysql/3 backup 
@@ -103,6 +109,13 @@ $ juju action do sleeper/0 pause time=1000
 $ juju action do sleeper/0 pause --string-args time=1000
 ...
 The value for the "time" param will be the string literal "1000".
+
+$ juju action do mysql/3 backup --wait 1h
+id: <ID>
+results:
+...
+status: completed
+...
 `
 
 // ActionNameRule describes the format an action name must match to be valid.
@@ -113,6 +126,7 @@ func (c *doCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
 	f.BoolVar(&c.parseStrings, "string-args", false, "use raw string values of CLI args")
+	f.StringVar(&c.wait, "wait", "", "wait for results")
 }
 
 func (c *doCommand) Info() *cmd.Info {
@@ -168,6 +182,14 @@ func (c *doCommand) Init(args []string) error {
 }
 
 func (c *doCommand) Run(ctx *cmd.Context) error {
+	var waitDur time.Duration
+	if c.wait != "" {
+		var err error
+		if waitDur, err = parseWait(c.wait); err != nil {
+			return err
+		}
+	}
+
 	api, err := c.NewActionAPIClient()
 	if err != nil {
 		return err
@@ -258,6 +280,18 @@ func (c *doCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	output := map[string]string{"Action queued with id": tag.Id()}
+	if c.wait == "" {
+		output := map[string]string{"Action queued with id": tag.Id()}
+		return c.out.Write(ctx, output)
+	}
+
+	tick := time.NewTimer(2 * time.Second)
+	wait := newWaitTimer(waitDur)
+	actionResult, err := timerLoop(api, tag.Id(), wait, tick)
+	if err != nil {
+		return err
+	}
+	output := formatActionResult(actionResult)
+	output["id"] = tag.Id()
 	return c.out.Write(ctx, output)
 }
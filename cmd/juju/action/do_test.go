@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/names"
@@ -400,3 +401,52 @@ func (s *DoSuite) TestRun(c *gc.C) {
 		}()
 	}
 }
+
+func (s *DoSuite) TestRunWait(c *gc.C) {
+	fakeClient := makeFakeClient(
+		0*time.Second,
+		10*time.Second,
+		tagsForIdPrefix(validActionId, validActionTagString),
+		[]params.ActionResult{{
+			Action: &params.Action{Tag: validActionTagString},
+			Status: "completed",
+			Output: map[string]interface{}{
+				"foo": "bar",
+			},
+			Enqueued:  time.Date(2015, time.February, 14, 8, 13, 0, 0, time.UTC),
+			Started:   time.Date(2015, time.February, 14, 8, 15, 0, 0, time.UTC),
+			Completed: time.Date(2015, time.February, 14, 8, 15, 30, 0, time.UTC),
+		}},
+		"",
+	)
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewDoCommand()
+	ctx, err := testing.RunCommand(c, wrappedCommand,
+		"-e", "dummyenv", validUnitId, "some-action", "--wait", "10s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+id: `+validActionId+`
+results:
+  foo: bar
+status: completed
+timing:
+  completed: 2015-02-14 08:15:30 +0000 UTC
+  enqueued: 2015-02-14 08:13:00 +0000 UTC
+  started: 2015-02-14 08:15:00 +0000 UTC
+`[1:])
+	c.Check(fakeClient.EnqueuedActions().Actions, gc.HasLen, 1)
+}
+
+func (s *DoSuite) TestRunBadWait(c *gc.C) {
+	fakeClient := &fakeAPIClient{}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewDoCommand()
+	_, err := testing.RunCommand(c, wrappedCommand,
+		"-e", "dummyenv", validUnitId, "some-action", "--wait", "soon")
+	c.Assert(err, gc.ErrorMatches, "time: invalid duration.*")
+	c.Check(fakeClient.EnqueuedActions().Actions, gc.HasLen, 0)
+}
@@ -69,15 +69,7 @@ func (c *fetchCommand) Init(args []string) error {
 
 // Run issues the API call to get Actions by ID.
 func (c *fetchCommand) Run(ctx *cmd.Context) error {
-	// Check whether units were left off our time string.
-	r := regexp.MustCompile("[a-zA-Z]")
-	matches := r.FindStringSubmatch(c.wait[len(c.wait)-1:])
-	// If any match, we have units.  Otherwise, we don't; assume seconds.
-	if len(matches) == 0 {
-		c.wait = c.wait + "s"
-	}
-
-	waitDur, err := time.ParseDuration(c.wait)
+	waitDur, err := parseWait(c.wait)
 	if err != nil {
 		return err
 	}
@@ -90,6 +82,36 @@ func (c *fetchCommand) Run(ctx *cmd.Context) error {
 
 	// tick every two seconds, to delay the loop timer.
 	tick := time.NewTimer(2 * time.Second)
+	wait := newWaitTimer(waitDur)
+
+	result, err := timerLoop(api, c.requestedId, wait, tick)
+	if err != nil {
+		return err
+	}
+
+	return c.out.Write(ctx, formatActionResult(result))
+}
+
+// parseWait parses the value of a --wait flag as a duration. If units
+// were left off, seconds are assumed.
+func parseWait(wait string) (time.Duration, error) {
+	// Check whether units were left off our time string.
+	if wait == "" {
+		return 0, errors.New("no wait duration specified")
+	}
+	r := regexp.MustCompile("[a-zA-Z]")
+	matches := r.FindStringSubmatch(wait[len(wait)-1:])
+	// If any match, we have units.  Otherwise, we don't; assume seconds.
+	if len(matches) == 0 {
+		wait = wait + "s"
+	}
+	return time.ParseDuration(wait)
+}
+
+// newWaitTimer returns the timer to pass to timerLoop for the given
+// wait duration.  A negative duration means timerLoop returns
+// immediately, and zero means it waits indefinitely.
+func newWaitTimer(waitDur time.Duration) *time.Timer {
 	wait := time.NewTimer(0 * time.Second)
 
 	switch {
@@ -103,13 +125,7 @@ func (c *fetchCommand) Run(ctx *cmd.Context) error {
 		// Otherwise, start an ordinary timer.
 		wait = time.NewTimer(waitDur)
 	}
-
-	result, err := timerLoop(api, c.requestedId, wait, tick)
-	if err != nil {
-		return err
-	}
-
-	return c.out.Write(ctx, formatActionResult(result))
+	return wait
 }
 
 // timerLoop loops indefinitely to query the given API, until "wait" times
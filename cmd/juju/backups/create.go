@@ -29,16 +29,18 @@ backup's unique ID.  You may provide a note to associate with the backup.
 
 The backup archive and associated metadata are stored remotely by juju.
 
-The --download option may be used without the --filename option.  In
-that case, the backup archive will be stored in the current working
-directory with a name matching juju-backup-<date>-<time>.tar.gz.
+A local copy of the archive is also downloaded, unless --no-download is
+given.  Without the --filename option, the archive will be stored in the
+current working directory with a name matching
+juju-backup-<date>-<time>.tar.gz.  The archive's checksum is verified
+once it has been downloaded.
 
 WARNING: Remotely stored backups will be lost when the environment is
 destroyed.  Furthermore, the remotely backup is not guaranteed to be
 available.
 
-Therefore, you should use the --download or --filename options, or use
-"juju backups download", to get a local copy of the backup archive.
+Therefore, you should not use the --no-download option unless you use
+"juju backups download" to get a local copy of the backup archive.
 This local copy can then be used to restore an environment even if that
 environment was already destroyed or is otherwise unavailable.
 `
@@ -165,6 +167,10 @@ func (c *createCommand) download(ctx *cmd.Context, id string, filename string) e
 	}
 	defer outfile.Close()
 
-	_, err = io.Copy(outfile, archive)
-	return errors.Trace(err)
+	if _, err := io.Copy(outfile, archive); err != nil {
+		// Don't leave an incomplete or corrupt archive behind.
+		os.Remove(filename)
+		return errors.Trace(err)
+	}
+	return nil
 }
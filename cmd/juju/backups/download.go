@@ -20,7 +20,9 @@ const downloadDoc = `
 "download" retrieves a backup archive file.
 
 If --filename is not used, the archive is downloaded to a temporary
-location and the filename is printed to stdout.
+location and the filename is printed to stdout.  The archive's checksum
+is verified once it has been downloaded; if it does not match, the
+local file is removed.
 `
 
 func newDownloadCommand() cmd.Command {
@@ -90,6 +92,8 @@ func (c *downloadCommand) Run(ctx *cmd.Context) error {
 	// Write out the archive.
 	_, err = io.Copy(archive, resultArchive)
 	if err != nil {
+		// Don't leave an incomplete or corrupt archive behind.
+		os.Remove(filename)
 		return errors.Annotate(err, "while creating local archive file")
 	}
 
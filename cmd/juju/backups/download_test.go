@@ -4,6 +4,11 @@
 package backups_test
 
 import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -75,3 +80,26 @@ func (s *downloadSuite) TestError(c *gc.C) {
 	_, err := testing.RunCommand(c, s.wrappedCommand, s.metaresult.ID)
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+// failingReader returns its error on every read.
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func (s *downloadSuite) TestBadArchiveRemoved(c *gc.C) {
+	client := s.setSuccess()
+	client.archive = ioutil.NopCloser(io.MultiReader(
+		strings.NewReader(s.data),
+		failingReader{errors.New("archive checksum mismatch")},
+	))
+	_, err := testing.RunCommand(c, s.wrappedCommand, s.metaresult.ID, "--filename", "backup.tar.gz")
+	c.Check(err, gc.ErrorMatches, ".*archive checksum mismatch")
+
+	s.filename = "backup.tar.gz"
+	_, err = os.Stat(s.filename)
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
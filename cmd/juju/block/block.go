@@ -168,6 +168,8 @@ To by-pass the block, where available, run desired remove command with --force o
     deploy
     destroy-environment
     ensure-availability
+    environment share
+    environment unshare
     expose
     remove-machine
     remove-relation
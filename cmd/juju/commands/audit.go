@@ -0,0 +1,215 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/juju/osenv"
+)
+
+const auditDoc = `
+The API server records every request that may change the state of the
+environment in the environment's audit log, along with the entity that
+made the request, a summary of its arguments and its result. Read-only
+requests are not recorded, and secrets such as passwords are removed
+from the recorded arguments. Only the most recent requests are kept.
+
+This command shows the audit log, newest first. The entries may be
+restricted to those made by a particular machine, unit or user, to
+those made to a particular API facade, or to those made within a
+recent period. Every request carries a correlation id, shown in the
+yaml and json output, and --correlation-id shows only the requests
+made with that id. The columns shown in tabular output may be chosen
+with --columns.
+
+Examples:
+
+    juju audit
+    juju audit --entity admin --since 2h
+    juju audit --facade Client -n 10 --format yaml
+    juju audit --correlation-id 9f0c2d41e7a3b865
+    juju audit --columns time,call,result
+`
+
+func newAuditCommand() cmd.Command {
+	return envcmd.Wrap(&auditCommand{})
+}
+
+// auditCommand shows the audit log of state-changing API requests.
+type auditCommand struct {
+	envcmd.EnvCommandBase
+	out     cmd.Output
+	entity  string
+	facade  string
+	corrId  string
+	since   time.Duration
+	limit   int
+	isoTime bool
+	columns common.Columns
+}
+
+// auditEntry holds an audit log entry for output.
+type auditEntry struct {
+	Time   string `yaml:"time" json:"time"`
+	Entity string `yaml:"entity" json:"entity"`
+	Call   string `yaml:"call" json:"call"`
+	Args   string `yaml:"args,omitempty" json:"args,omitempty"`
+	Error  string `yaml:"error,omitempty" json:"error,omitempty"`
+
+	CorrelationId string `yaml:"correlation-id,omitempty" json:"correlation-id,omitempty"`
+}
+
+func (c *auditCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit",
+		Purpose: "show the audit log of state-changing API requests",
+		Doc:     auditDoc,
+	}
+}
+
+func (c *auditCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.entity, "entity", "", "only show requests made by this machine, unit or user")
+	f.StringVar(&c.facade, "facade", "", "only show requests made to this API facade")
+	f.StringVar(&c.corrId, "correlation-id", "", "only show requests made with this correlation id")
+	f.DurationVar(&c.since, "since", 0, "only show requests made within this period, e.g. 30m")
+	f.IntVar(&c.limit, "n", 50, "maximum number of entries to show")
+	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": c.formatTabular,
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+	})
+	c.columns.AddFlag(f)
+}
+
+func (c *auditCommand) Init(args []string) error {
+	if c.entity != "" {
+		tag, err := entityTag(c.entity)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.entity = tag.String()
+	}
+	if c.since < 0 {
+		return errors.Errorf("invalid period %v", c.since)
+	}
+	if c.limit < 1 {
+		return errors.Errorf("invalid number of entries %d", c.limit)
+	}
+	if err := c.columns.CheckFormat(c.out.Name()); err != nil {
+		return err
+	}
+	// If use of ISO time not specified on command line,
+	// check env var.
+	if !c.isoTime {
+		var err error
+		envVarValue := os.Getenv(osenv.JujuStatusIsoTimeEnvKey)
+		if envVarValue != "" {
+			if c.isoTime, err = strconv.ParseBool(envVarValue); err != nil {
+				return errors.Annotatef(err, "invalid %s env var, expected true|false", osenv.JujuStatusIsoTimeEnvKey)
+			}
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// entityTag returns the tag of the machine, unit or user identified by
+// the given name or tag.
+func entityTag(entity string) (names.Tag, error) {
+	if tag, err := names.ParseTag(entity); err == nil {
+		return tag, nil
+	}
+	switch {
+	case names.IsValidMachine(entity):
+		return names.NewMachineTag(entity), nil
+	case names.IsValidUnit(entity):
+		return names.NewUnitTag(entity), nil
+	case names.IsValidUser(entity):
+		if !strings.Contains(entity, "@") {
+			return names.NewLocalUserTag(entity), nil
+		}
+		return names.NewUserTag(entity), nil
+	}
+	return nil, errors.Errorf("invalid entity %q", entity)
+}
+
+// auditAPI defines the methods on the client API that the audit
+// command calls.
+type auditAPI interface {
+	Close() error
+	AuditLog(params.AuditLogFilter) ([]params.AuditLogEntry, error)
+}
+
+var getAuditAPI = func(c *auditCommand) (auditAPI, error) {
+	return c.NewAPIClient()
+}
+
+func (c *auditCommand) Run(ctx *cmd.Context) error {
+	client, err := getAuditAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	filter := params.AuditLogFilter{
+		Entity:        c.entity,
+		Facade:        c.facade,
+		CorrelationId: c.corrId,
+		Limit:         c.limit,
+	}
+	if c.since > 0 {
+		since := time.Now().Add(-c.since)
+		filter.Since = &since
+	}
+	results, err := client.AuditLog(filter)
+	if err != nil {
+		return err
+	}
+	entries := make([]auditEntry, len(results))
+	for i, result := range results {
+		entity := result.Entity
+		if tag, err := names.ParseTag(result.Entity); err == nil {
+			entity = tag.Id()
+		}
+		entries[i] = auditEntry{
+			Time:   common.FormatTime(&result.Time, c.isoTime),
+			Entity: entity,
+			Call:   fmt.Sprintf("%s(%d).%s", result.Facade, result.Version, result.Method),
+			Args:   result.Args,
+			Error:  result.Error,
+
+			CorrelationId: result.CorrelationId,
+		}
+	}
+	return c.out.Write(ctx, entries)
+}
+
+// formatTabular shows audit log entries as a table.
+func (c *auditCommand) formatTabular(value interface{}) ([]byte, error) {
+	entries, ok := value.([]auditEntry)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+	table := common.NewTable("TIME", "ENTITY", "CALL", "RESULT", "ARGS")
+	for _, entry := range entries {
+		result := "ok"
+		if entry.Error != "" {
+			result = "error: " + entry.Error
+		}
+		table.AddRow(entry.Time, entry.Entity, entry.Call, result, entry.Args)
+	}
+	return table.Format(c.columns)
+}
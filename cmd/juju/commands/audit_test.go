@@ -0,0 +1,166 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type AuditSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeAuditAPI
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+type fakeAuditAPI struct {
+	filter  params.AuditLogFilter
+	entries []params.AuditLogEntry
+}
+
+func (f *fakeAuditAPI) Close() error {
+	return nil
+}
+
+func (f *fakeAuditAPI) AuditLog(filter params.AuditLogFilter) ([]params.AuditLogEntry, error) {
+	f.filter = filter
+	return f.entries, nil
+}
+
+func (s *AuditSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeAuditAPI{
+		entries: []params.AuditLogEntry{{
+			Time:      time.Date(2015, 10, 1, 12, 30, 0, 0, time.UTC),
+			Entity:    "user-admin@local",
+			Facade:    "Client",
+			Method:    "ServiceExpose",
+			Args:      `{"ServiceName":"wordpress"}`,
+			Error:     `service "wordpress" not found`,
+			ErrorCode: "not found",
+
+			CorrelationId: "9f0c2d41e7a3b865",
+		}, {
+			Time:    time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC),
+			Entity:  "unit-mysql-0",
+			Facade:  "Uniter",
+			Version: 2,
+			Method:  "SetStatus",
+		}},
+	}
+	s.PatchValue(&getAuditAPI, func(*auditCommand) (auditAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *AuditSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		entity string
+		err    string
+	}{{}, {
+		args:   []string{"--entity", "admin"},
+		entity: "user-admin@local",
+	}, {
+		args:   []string{"--entity", "mysql/0"},
+		entity: "unit-mysql-0",
+	}, {
+		args:   []string{"--entity", "0"},
+		entity: "machine-0",
+	}, {
+		args:   []string{"--entity", "machine-0"},
+		entity: "machine-0",
+	}, {
+		args: []string{"--entity", "-"},
+		err:  `invalid entity "-"`,
+	}, {
+		args: []string{"-n", "0"},
+		err:  "invalid number of entries 0",
+	}, {
+		args: []string{"--columns", "time", "--format", "yaml"},
+		err:  "--columns cannot be used with the yaml format",
+	}, {
+		args: []string{"foo"},
+		err:  `unrecognized args: \["foo"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &auditCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.entity, gc.Equals, test.entity)
+	}
+}
+
+func (s *AuditSuite) TestFilter(c *gc.C) {
+	before := time.Now()
+	_, err := testing.RunCommand(c, newAuditCommand(), "--entity", "admin", "--facade", "Client", "--since", "1h", "-n", "10")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.filter.Since, gc.NotNil)
+	since := *s.api.filter.Since
+	c.Assert(since.Before(before.Add(-time.Hour)), jc.IsFalse)
+	c.Assert(since.After(time.Now().Add(-time.Hour)), jc.IsFalse)
+	s.api.filter.Since = nil
+	c.Assert(s.api.filter, jc.DeepEquals, params.AuditLogFilter{
+		Entity: "user-admin@local",
+		Facade: "Client",
+		Limit:  10,
+	})
+}
+
+func (s *AuditSuite) TestFilterByCorrelationId(c *gc.C) {
+	_, err := testing.RunCommand(c, newAuditCommand(), "--correlation-id", "9f0c2d41e7a3b865")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.filter, jc.DeepEquals, params.AuditLogFilter{
+		CorrelationId: "9f0c2d41e7a3b865",
+		Limit:         50,
+	})
+}
+
+func (s *AuditSuite) TestTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newAuditCommand(), "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.filter, jc.DeepEquals, params.AuditLogFilter{Limit: 50})
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                  ENTITY       CALL                     RESULT                                ARGS\n"+
+		"2015-10-01 12:30:00Z  admin@local  Client(0).ServiceExpose  error: service \"wordpress\" not found  {\"ServiceName\":\"wordpress\"}\n"+
+		"2015-10-01 12:00:00Z  mysql/0      Uniter(2).SetStatus      ok                                    \n",
+	)
+}
+
+func (s *AuditSuite) TestTabularColumns(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newAuditCommand(), "--utc", "--columns", "time,Call,result")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                  CALL                     RESULT\n"+
+		"2015-10-01 12:30:00Z  Client(0).ServiceExpose  error: service \"wordpress\" not found\n"+
+		"2015-10-01 12:00:00Z  Uniter(2).SetStatus      ok\n",
+	)
+}
+
+func (s *AuditSuite) TestYAML(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newAuditCommand(), "--utc", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- time: 2015-10-01 12:30:00Z
+  entity: admin@local
+  call: Client(0).ServiceExpose
+  args: '{"ServiceName":"wordpress"}'
+  error: service "wordpress" not found
+  correlation-id: 9f0c2d41e7a3b865
+- time: 2015-10-01 12:00:00Z
+  entity: mysql/0
+  call: Uniter(2).SetStatus
+`[1:])
+}
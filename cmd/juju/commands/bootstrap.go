@@ -24,6 +24,8 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
 	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/environs/offline"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
@@ -36,6 +38,7 @@ import (
 // provisionalProviders is the names of providers that are hidden behind
 // feature flags.
 var provisionalProviders = map[string]string{
+	"lxd":     feature.LXDProvider,
 	"vsphere": feature.VSphereProvider,
 }
 
@@ -55,6 +58,13 @@ algorithm by using the "--to" flag. The value associated with "--to" is a
 "placement directive", which tells Juju how to identify the first machine to use.
 For more information on placement directives, see "juju help placement".
 
+To use an existing machine as the state server rather than provisioning a new
+one, for example where the state server must run on a pre-approved hardened
+image, specify it with "--to ssh:[user@]host". The machine is set up over SSH,
+just as "juju add-machine ssh:[user@]host" does; if a user is given, it is used
+to create the "ubuntu" user on the machine. The machine is not managed by the
+cloud provider, so destroying the environment will not release it.
+
 Bootstrap initialises the cloud environment synchronously and displays information
 about the current installation steps.  The time for bootstrap to complete varies
 across cloud providers from a few seconds to several minutes.  Once bootstrap has
@@ -73,7 +83,14 @@ Private clouds may need to specify their own custom image metadata, and
 possibly upload Juju tools to cloud storage if no outgoing Internet access is
 available. In this case, use the --metadata-source parameter to point
 bootstrap to a local directory from which to upload tools and/or image
-metadata.
+metadata, or to an archive written by create-offline-bundle.
+
+With --interactive, bootstrap first adds a new environment to
+environments.yaml and makes it the default. Credentials are taken from the
+usual environment variables (such as AWS_ACCESS_KEY_ID or OS_USERNAME), from
+~/.novarc, from the active gcloud configuration, or from the profiles the MAAS
+CLI is logged in with; anything else needed is asked for. An environment named
+with -e is offered as the name of the new one.
 
 If agent-version is specifed, this is the default tools version to use when running the Juju agents.
 Only the numeric version is relevant. To enable ease of scripting, the full binary version
@@ -102,10 +119,12 @@ type bootstrapCommand struct {
 	seriesOld             []string
 	MetadataSource        string
 	Placement             string
+	BootstrapHost         string
 	KeepBrokenEnvironment bool
 	NoAutoUpgrade         bool
 	AgentVersionParam     string
 	AgentVersion          *version.Number
+	Interactive           bool
 }
 
 func (c *bootstrapCommand) Info() *cmd.Info {
@@ -126,6 +145,7 @@ func (c *bootstrapCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.KeepBrokenEnvironment, "keep-broken", false, "do not destroy the environment if bootstrap fails")
 	f.BoolVar(&c.NoAutoUpgrade, "no-auto-upgrade", false, "do not upgrade to newer tools on first bootstrap")
 	f.StringVar(&c.AgentVersionParam, "agent-version", "", "the version of tools to initially use for Juju agents")
+	f.BoolVar(&c.Interactive, "interactive", false, "add a new environment to environments.yaml, asking for any details that cannot be detected")
 }
 
 func (c *bootstrapCommand) Init(args []string) (err error) {
@@ -146,8 +166,15 @@ func (c *bootstrapCommand) Init(args []string) (err error) {
 	}
 
 	// Parse the placement directive. Bootstrap currently only
-	// supports provider-specific placement directives.
-	if c.Placement != "" {
+	// supports provider-specific placement directives, and
+	// existing hosts specified as ssh:[user@]host.
+	if strings.HasPrefix(c.Placement, sshHostPrefix) {
+		c.BootstrapHost = c.Placement[len(sshHostPrefix):]
+		c.Placement = ""
+		if c.BootstrapHost == "" {
+			return fmt.Errorf("no host specified in bootstrap placement directive %q", sshHostPrefix)
+		}
+	} else if c.Placement != "" {
 		_, err = instance.ParsePlacement(c.Placement)
 		if err != instance.ErrPlacementScopeMissing {
 			// We only support unscoped placement directives for bootstrap.
@@ -234,6 +261,14 @@ func (c *bootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 		fmt.Fprintln(ctx.Stderr, "Use of --upload-series is obsolete. --upload-tools now expands to all supported series of the same operating system.")
 	}
 
+	if c.Interactive {
+		envName, err := runBootstrapWizard(ctx, c.ConnectionName())
+		if err != nil {
+			return errors.Annotate(err, "cannot create environment")
+		}
+		c.SetEnvName(envName)
+	}
+
 	envName := getEnvName(c)
 	if envName == "" {
 		return errors.Errorf("the name of the environment must be specified")
@@ -298,6 +333,17 @@ func (c *bootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 	var metadataDir string
 	if c.MetadataSource != "" {
 		metadataDir = ctx.AbsPath(c.MetadataSource)
+		// An offline bundle is unpacked and used as the metadata directory.
+		isBundle, err := offline.IsBundleFile(metadataDir)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if isBundle {
+			if metadataDir, err = offline.UnpackFile(metadataDir); err != nil {
+				return errors.Trace(err)
+			}
+			defer os.RemoveAll(metadataDir)
+		}
 	}
 
 	// TODO (wallyworld): 2013-09-20 bug 1227931
@@ -308,11 +354,12 @@ func (c *bootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 	}
 
 	err = bootstrapFuncs.Bootstrap(envcmd.BootstrapContext(ctx), environ, bootstrap.BootstrapParams{
-		Constraints:  c.Constraints,
-		Placement:    c.Placement,
-		UploadTools:  c.UploadTools,
-		AgentVersion: c.AgentVersion,
-		MetadataDir:  metadataDir,
+		Constraints:   c.Constraints,
+		Placement:     c.Placement,
+		BootstrapHost: c.BootstrapHost,
+		UploadTools:   c.UploadTools,
+		AgentVersion:  c.AgentVersion,
+		MetadataDir:   metadataDir,
 	})
 	if err != nil {
 		return errors.Annotate(err, "failed to bootstrap environment")
@@ -429,6 +476,12 @@ var allInstances = func(environ environs.Environ) ([]instance.Instance, error) {
 	return environ.AllInstances()
 }
 
+var hostAddress = manual.HostAddress
+
+// sshHostPrefix is the prefix of a bootstrap placement directive
+// that specifies an existing host to use as the state server.
+const sshHostPrefix = "ssh:"
+
 var prepareEndpointsForCaching = juju.PrepareEndpointsForCaching
 
 // SetBootstrapEndpointAddress writes the API endpoint address of the
@@ -436,18 +489,10 @@ var prepareEndpointsForCaching = juju.PrepareEndpointsForCaching
 // once directly after Bootstrap. It assumes that there is just one instance
 // in the environment - the bootstrap instance.
 func (c *bootstrapCommand) SetBootstrapEndpointAddress(environ environs.Environ) error {
-	instances, err := allInstances(environ)
+	netAddrs, err := c.bootstrapAddresses(environ)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	length := len(instances)
-	if length == 0 {
-		return errors.Errorf("found no instances, expected at least one")
-	}
-	if length > 1 {
-		logger.Warningf("expected one instance, got %d", length)
-	}
-	bootstrapInstance := instances[0]
 	cfg := environ.Config()
 	info, err := envcmd.ConnectionInfoForName(c.ConnectionName())
 	if err != nil {
@@ -457,10 +502,6 @@ func (c *bootstrapCommand) SetBootstrapEndpointAddress(environ environs.Environ)
 	// Don't use c.ConnectionEndpoint as it attempts to contact the state
 	// server if no addresses are found in connection info.
 	endpoint := info.APIEndpoint()
-	netAddrs, err := bootstrapInstance.Addresses()
-	if err != nil {
-		return errors.Annotate(err, "failed to get bootstrap instance addresses")
-	}
 	apiPort := cfg.APIPort()
 	apiHostPorts := network.AddressesWithPort(netAddrs, apiPort)
 	addrs, hosts, addrsChanged := prepareEndpointsForCaching(
@@ -483,3 +524,33 @@ func (c *bootstrapCommand) SetBootstrapEndpointAddress(environ environs.Environ)
 	}
 	return nil
 }
+
+// bootstrapAddresses returns the addresses of the bootstrap server:
+// either the existing host it was bootstrapped onto, or the single
+// instance in the environment.
+func (c *bootstrapCommand) bootstrapAddresses(environ environs.Environ) ([]network.Address, error) {
+	if c.BootstrapHost != "" {
+		_, host := bootstrap.SplitUserHost(c.BootstrapHost)
+		addr, err := hostAddress(host)
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to get bootstrap host address")
+		}
+		return []network.Address{addr}, nil
+	}
+	instances, err := allInstances(environ)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	length := len(instances)
+	if length == 0 {
+		return nil, errors.Errorf("found no instances, expected at least one")
+	}
+	if length > 1 {
+		logger.Warningf("expected one instance, got %d", length)
+	}
+	netAddrs, err := instances[0].Addresses()
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to get bootstrap instance addresses")
+	}
+	return netAddrs, nil
+}
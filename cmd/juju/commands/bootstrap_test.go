@@ -390,6 +390,8 @@ environments:
         type: dummy
     cloudsigma:
         type: cloudsigma
+    lxd:
+        type: lxd
     vsphere:
         type: vsphere
 `
@@ -652,6 +654,36 @@ func (s *BootstrapSuite) TestBootstrapCalledWithMetadataDir(c *gc.C) {
 	c.Assert(bootstrap.args.MetadataDir, gc.Equals, sourceDir)
 }
 
+func (s *BootstrapSuite) TestBootstrapToExistingHost(c *gc.C) {
+	resetJujuHome(c, "devenv")
+
+	var bootstrap fakeBootstrapFuncs
+	s.PatchValue(&getBootstrapFuncs, func() BootstrapInterface {
+		return &bootstrap
+	})
+	s.PatchValue(&allInstances, func(environ environs.Environ) ([]instance.Instance, error) {
+		c.Fatalf("unexpected call to allInstances")
+		return nil, nil
+	})
+	var addressHost string
+	s.PatchValue(&hostAddress, func(host string) (network.Address, error) {
+		addressHost = host
+		return network.NewAddress("10.0.0.1"), nil
+	})
+
+	_, err := coretesting.RunCommand(c, newBootstrapCommand(), "--to", "ssh:admin@10.0.0.1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bootstrap.args.BootstrapHost, gc.Equals, "admin@10.0.0.1")
+	c.Assert(bootstrap.args.Placement, gc.Equals, "")
+	c.Assert(addressHost, gc.Equals, "10.0.0.1")
+}
+
+func (s *BootstrapSuite) TestBootstrapToExistingHostMissingHost(c *gc.C) {
+	resetJujuHome(c, "devenv")
+	_, err := coretesting.RunCommand(c, newBootstrapCommand(), "--to", "ssh:")
+	c.Assert(err, gc.ErrorMatches, `no host specified in bootstrap placement directive "ssh:"`)
+}
+
 func (s *BootstrapSuite) checkBootstrapWithVersion(c *gc.C, vers, expect string) {
 	resetJujuHome(c, "devenv")
 
@@ -0,0 +1,559 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/readpass"
+	"gopkg.in/goose.v1/identity"
+	goyaml "gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/juju/osenv"
+)
+
+// wizardField describes an environment attribute that the bootstrap
+// wizard fills in, either from detected credentials or by asking
+// the user.
+type wizardField struct {
+	// name is the name of the attribute.
+	name string
+
+	// prompt is shown when asking the user for the value.
+	prompt string
+
+	// envVars holds the environment variables from which the value
+	// may be taken, in order of preference.
+	envVars []string
+
+	// defaultValue is used if the user enters nothing. If it is
+	// empty, a value must be given.
+	defaultValue string
+
+	// secret is true if the value should not be echoed as it is
+	// typed.
+	secret bool
+}
+
+// wizardCloud describes the credentials needed to bootstrap an
+// environment of one provider type.
+type wizardCloud struct {
+	providerType string
+	fields       []wizardField
+
+	// detect, if set, returns attribute values found in the
+	// cloud's own configuration files or tools.
+	detect func() (map[string]string, error)
+}
+
+// wizardClouds holds the clouds supported by the bootstrap wizard, in
+// the order in which they are offered.
+var wizardClouds = []wizardCloud{{
+	providerType: "ec2",
+	fields: []wizardField{{
+		name:    "access-key",
+		prompt:  "AWS access key",
+		envVars: []string{"AWS_ACCESS_KEY_ID", "EC2_ACCESS_KEY"},
+	}, {
+		name:    "secret-key",
+		prompt:  "AWS secret key",
+		envVars: []string{"AWS_SECRET_ACCESS_KEY", "EC2_SECRET_KEY"},
+		secret:  true,
+	}, {
+		name:         "region",
+		prompt:       "AWS region",
+		envVars:      []string{"AWS_DEFAULT_REGION"},
+		defaultValue: "us-east-1",
+	}},
+}, {
+	providerType: "gce",
+	fields: []wizardField{{
+		name:    "project-id",
+		prompt:  "GCE project ID",
+		envVars: []string{"CLOUDSDK_CORE_PROJECT"},
+	}, {
+		name:    "auth-file",
+		prompt:  "Path to the service account's JSON key file",
+		envVars: []string{"GOOGLE_APPLICATION_CREDENTIALS"},
+	}, {
+		name:         "region",
+		prompt:       "GCE region",
+		envVars:      []string{"CLOUDSDK_COMPUTE_REGION"},
+		defaultValue: "us-central1",
+	}},
+	detect: detectGCloudConfig,
+}, {
+	providerType: "maas",
+	fields: []wizardField{{
+		name:   "maas-server",
+		prompt: "MAAS server URL",
+	}, {
+		name:   "maas-oauth",
+		prompt: "MAAS API key",
+		secret: true,
+	}},
+	detect: detectMAASProfile,
+}, {
+	providerType: "openstack",
+	fields:       openstackWizardFields,
+	detect:       detectNovarc,
+}}
+
+// openstackWizardFields holds the fields filled in by the bootstrap
+// wizard for OpenStack environments.
+var openstackWizardFields = []wizardField{{
+	name:    "auth-url",
+	prompt:  "Keystone URL",
+	envVars: identity.CredEnvAuthURL,
+}, {
+	name:    "username",
+	prompt:  "OpenStack user name",
+	envVars: identity.CredEnvUser,
+}, {
+	name:    "password",
+	prompt:  "OpenStack password",
+	envVars: identity.CredEnvSecrets,
+	secret:  true,
+}, {
+	name:    "tenant-name",
+	prompt:  "OpenStack tenant name",
+	envVars: identity.CredEnvTenantName,
+}, {
+	name:    "region",
+	prompt:  "OpenStack region",
+	envVars: identity.CredEnvRegion,
+}}
+
+// detectCredentials returns the attribute values for the cloud that
+// can be found without asking the user. Values from environment
+// variables take precedence over those found by the cloud's detect
+// function.
+func (cloud wizardCloud) detectCredentials() (map[string]string, error) {
+	values := make(map[string]string)
+	if cloud.detect != nil {
+		detected, err := cloud.detect()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot detect %s credentials", cloud.providerType)
+		}
+		for name, value := range detected {
+			values[name] = value
+		}
+	}
+	for _, field := range cloud.fields {
+		for _, envVar := range field.envVars {
+			if value := os.Getenv(envVar); value != "" {
+				values[field.name] = value
+				break
+			}
+		}
+	}
+	return values, nil
+}
+
+// hasCredentials reports whether values holds every attribute of the
+// cloud that has no default.
+func (cloud wizardCloud) hasCredentials(values map[string]string) bool {
+	for _, field := range cloud.fields {
+		if field.defaultValue == "" && values[field.name] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// novarcPath holds the path of the OpenStack credentials file read by
+// the bootstrap wizard, relative to the user's home directory.
+var novarcPath = ".novarc"
+
+// detectNovarc returns the OpenStack credentials exported by the
+// user's novarc file, if there is one.
+func detectNovarc() (map[string]string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(utils.Home(), novarcPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	vars := parseShellExports(string(data))
+	values := make(map[string]string)
+	for _, field := range openstackWizardFields {
+		for _, envVar := range field.envVars {
+			if value := vars[envVar]; value != "" {
+				values[field.name] = value
+				break
+			}
+		}
+	}
+	return values, nil
+}
+
+// parseShellExports returns the variables assigned by lines of the
+// form "export NAME=value" or "NAME=value" in the given shell script.
+// Quotes around the values are removed; anything more elaborate is
+// ignored.
+func parseShellExports(script string) map[string]string {
+	vars := make(map[string]string)
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "export ")
+		i := strings.Index(line, "=")
+		if i <= 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[name] = value
+	}
+	return vars
+}
+
+// gcloudConfigDir holds the path of the gcloud configuration
+// directory, relative to the user's home directory.
+var gcloudConfigDir = filepath.Join(".config", "gcloud")
+
+// detectGCloudConfig returns the project and region of the active gcloud
+// configuration, if there is one.
+func detectGCloudConfig() (map[string]string, error) {
+	dir := filepath.Join(utils.Home(), gcloudConfigDir)
+	active := "default"
+	data, err := ioutil.ReadFile(filepath.Join(dir, "active_config"))
+	if err == nil {
+		active = strings.TrimSpace(string(data))
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Trace(err)
+	}
+	data, err = ioutil.ReadFile(filepath.Join(dir, "configurations", "config_"+active))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	values := make(map[string]string)
+	section := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			continue
+		}
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch {
+		case section == "core" && key == "project":
+			values["project-id"] = value
+		case section == "compute" && key == "region":
+			values["region"] = value
+		}
+	}
+	return values, nil
+}
+
+// maasList runs "maas list", which prints the name, URL and API key
+// of each profile the MAAS CLI is logged in with.
+var maasList = func() ([]byte, error) {
+	if _, err := exec.LookPath("maas"); err != nil {
+		return nil, nil
+	}
+	return exec.Command("maas", "list").Output()
+}
+
+// detectMAASProfile returns the server and API key of the first
+// profile the MAAS CLI is logged in with, if any.
+func detectMAASProfile() (map[string]string, error) {
+	out, err := maasList()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list MAAS CLI profiles")
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		// MAAS API URLs end in /api/<version>/, but juju wants
+		// the base URL of the server.
+		server := fields[1]
+		if i := strings.Index(server, "/api/"); i >= 0 {
+			server = server[:i+1]
+		}
+		return map[string]string{
+			"maas-server": server,
+			"maas-oauth":  fields[2],
+		}, nil
+	}
+	return nil, nil
+}
+
+var readWizardSecret = readpass.ReadPassword
+
+// bootstrapWizard asks the user for the details of a new environment,
+// filling in as much as it can from credentials found on the local
+// machine, and adds the environment to environments.yaml.
+type bootstrapWizard struct {
+	ctx     *cmd.Context
+	scanner *bufio.Scanner
+}
+
+// runBootstrapWizard runs the bootstrap wizard and returns the name of
+// the environment it creates. If suggestedName is not empty, it is
+// offered as the name of the new environment.
+func runBootstrapWizard(ctx *cmd.Context, suggestedName string) (string, error) {
+	w := &bootstrapWizard{
+		ctx:     ctx,
+		scanner: bufio.NewScanner(ctx.Stdin),
+	}
+	return w.run(suggestedName)
+}
+
+func (w *bootstrapWizard) run(suggestedName string) (string, error) {
+	environsFile := osenv.JujuHomePath("environments.yaml")
+	existing, err := ioutil.ReadFile(environsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return "", errors.Trace(err)
+	}
+
+	detected := make([]map[string]string, len(wizardClouds))
+	var names, found []string
+	for i, cloud := range wizardClouds {
+		values, err := cloud.detectCredentials()
+		if err != nil {
+			logger.Warningf("%v", err)
+		}
+		detected[i] = values
+		names = append(names, cloud.providerType)
+		if cloud.hasCredentials(values) {
+			found = append(found, cloud.providerType)
+		}
+	}
+	defaultType := names[0]
+	if len(found) > 0 {
+		fmt.Fprintf(w.ctx.Stdout, "Found credentials for: %s\n", strings.Join(found, ", "))
+		defaultType = found[0]
+	}
+	providerType, err := w.ask(fmt.Sprintf("Cloud type (%s)", strings.Join(names, ", ")), defaultType, false)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	index := -1
+	for i, cloud := range wizardClouds {
+		if cloud.providerType == providerType {
+			index = i
+		}
+	}
+	if index < 0 {
+		return "", errors.Errorf("cloud type %q not supported by the bootstrap wizard", providerType)
+	}
+	cloud, values := wizardClouds[index], detected[index]
+
+	if suggestedName == "" {
+		suggestedName = providerType
+	}
+	envName, err := w.ask("Environment name", suggestedName, false)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	attrs := map[string]interface{}{
+		"type": providerType,
+	}
+	for _, field := range cloud.fields {
+		value := values[field.name]
+		if value == "" {
+			if value, err = w.ask(field.prompt, field.defaultValue, field.secret); err != nil {
+				return "", errors.Trace(err)
+			}
+		}
+		if value != "" {
+			attrs[field.name] = value
+		}
+	}
+
+	data, defaultName, err := addEnvironment(existing, envName, attrs)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	cfg, err := validateEnvironment(data, envName)
+	if err != nil {
+		return "", errors.Annotatef(err, "invalid %s environment", providerType)
+	}
+	fmt.Fprintf(w.ctx.Stdout, "Checking the %s credentials...\n", providerType)
+	if err := verifyWizardEnvironment(w.ctx, cfg); err != nil {
+		return "", errors.Annotatef(err, "cannot use %s environment", providerType)
+	}
+	if len(existing) > 0 {
+		backup, err := backupEnvironments(environsFile, existing)
+		if err != nil {
+			return "", errors.Annotate(err, "cannot back up environments.yaml")
+		}
+		fmt.Fprintf(w.ctx.Stdout, "The previous environments.yaml has been saved as %s.\n", backup)
+	}
+	if _, err := environs.WriteEnvirons("", string(data)); err != nil {
+		return "", errors.Annotate(err, "cannot write environments.yaml")
+	}
+	fmt.Fprintf(w.ctx.Stdout, "Environment %q has been added to %s.\n", envName, environsFile)
+	if defaultName != envName {
+		fmt.Fprintf(w.ctx.Stdout, "The default environment is still %q; run \"juju switch %s\" to change it.\n", defaultName, envName)
+	}
+	return envName, nil
+}
+
+// wizardNow returns the current time, used to name backups of
+// environments.yaml.
+var wizardNow = time.Now
+
+// backupEnvironments saves the given contents of environments.yaml in a
+// new file next to it, named for the current time, and returns the
+// file's path. An existing file is never overwritten.
+func backupEnvironments(environsFile string, data []byte) (string, error) {
+	base := fmt.Sprintf("%s.%s", environsFile, wizardNow().UTC().Format("20060102-150405"))
+	for i := 0; ; i++ {
+		path := base + ".orig"
+		if i > 0 {
+			path = fmt.Sprintf("%s-%d.orig", base, i)
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		} else if err != nil {
+			return "", errors.Trace(err)
+		}
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return "", errors.Trace(err)
+		}
+		return path, nil
+	}
+}
+
+// verifyWizardEnvironment checks that the environment with the given
+// configuration can be used, by preparing it as bootstrap would and
+// making a request to the cloud with the credentials given.
+var verifyWizardEnvironment = func(ctx *cmd.Context, cfg *config.Config) error {
+	p, err := environs.Provider(cfg.Type())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := p.PrepareForBootstrap(envcmd.BootstrapContext(ctx), cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := env.AllInstances(); err != nil && err != environs.ErrNoInstances {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// ask prompts the user for a value, returning defaultValue if nothing
+// is entered. It is an error to enter nothing if there is no default.
+func (w *bootstrapWizard) ask(prompt, defaultValue string, secret bool) (string, error) {
+	if defaultValue != "" {
+		prompt = fmt.Sprintf("%s [%s]", prompt, defaultValue)
+	}
+	fmt.Fprintf(w.ctx.Stdout, "%s: ", prompt)
+	var value string
+	if secret {
+		var err error
+		value, err = readWizardSecret()
+		fmt.Fprint(w.ctx.Stdout, "\n")
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+	} else {
+		w.scanner.Scan()
+		if err := w.scanner.Err(); err != nil && err != io.EOF {
+			return "", errors.Trace(err)
+		}
+		value = w.scanner.Text()
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		value = defaultValue
+	}
+	if value == "" {
+		return "", errors.Errorf("no value given for %s", prompt)
+	}
+	return value, nil
+}
+
+// addEnvironment returns the contents of an environments.yaml file
+// that holds the environments in existing and a new environment with
+// the given name and attributes, along with the name of the default
+// environment. The new environment only becomes the default if there
+// are no others; the existing default is otherwise left alone.
+// Comments in existing are not preserved.
+func addEnvironment(existing []byte, envName string, attrs map[string]interface{}) ([]byte, string, error) {
+	var raw struct {
+		Default      string                            `yaml:"default"`
+		Environments map[string]map[string]interface{} `yaml:"environments"`
+	}
+	if err := goyaml.Unmarshal(existing, &raw); err != nil {
+		return nil, "", errors.Annotate(err, "cannot parse environments.yaml")
+	}
+	if _, ok := raw.Environments[envName]; ok {
+		return nil, "", errors.Errorf("environment %q already exists in environments.yaml", envName)
+	}
+	switch len(raw.Environments) {
+	case 0:
+		raw.Default = envName
+	case 1:
+		// A sole environment is the default even if not named as
+		// such; adding another must not change that.
+		if raw.Default == "" {
+			for name := range raw.Environments {
+				raw.Default = name
+			}
+		}
+	}
+	if raw.Environments == nil {
+		raw.Environments = make(map[string]map[string]interface{})
+	}
+	raw.Environments[envName] = attrs
+	data, err := goyaml.Marshal(&raw)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return data, raw.Default, nil
+}
+
+// validateEnvironment checks the configuration of the named
+// environment in the given environments.yaml contents, as the
+// environment's provider would before bootstrapping it, and returns
+// the validated configuration.
+func validateEnvironment(data []byte, envName string) (*config.Config, error) {
+	envs, err := environs.ReadEnvironsBytes(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err := envs.Config(envName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	p, err := environs.Provider(cfg.Type())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err = p.Validate(cfg, nil)
+	return cfg, errors.Trace(err)
+}
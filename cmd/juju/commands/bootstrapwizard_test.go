@@ -0,0 +1,253 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/juju/osenv"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type BootstrapWizardSuite struct {
+	coretesting.FakeJujuHomeSuite
+	verified []string
+}
+
+var _ = gc.Suite(&BootstrapWizardSuite{})
+
+func (s *BootstrapWizardSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.PatchValue(&wizardClouds, []wizardCloud{{
+		providerType: "dummy",
+		fields: []wizardField{{
+			name:    "authorized-keys",
+			prompt:  "Authorized keys",
+			envVars: []string{"WIZARD_TEST_KEYS"},
+		}, {
+			name:         "state-server",
+			prompt:       "State server",
+			defaultValue: "true",
+		}, {
+			name:         "admin-secret",
+			prompt:       "Admin secret",
+			defaultValue: coretesting.DefaultMongoPassword,
+		}, {
+			name:   "secret",
+			prompt: "Secret",
+			secret: true,
+		}},
+	}})
+	s.PatchEnvironment("WIZARD_TEST_KEYS", coretesting.FakeAuthKeys)
+	s.PatchValue(&readWizardSecret, func() (string, error) {
+		return "bacon", nil
+	})
+	s.verified = nil
+	s.PatchValue(&verifyWizardEnvironment, func(_ *cmd.Context, cfg *config.Config) error {
+		s.verified = append(s.verified, cfg.Name())
+		return nil
+	})
+	s.PatchValue(&wizardNow, func() time.Time {
+		return time.Date(2015, 10, 21, 16, 29, 0, 0, time.UTC)
+	})
+}
+
+func (s *BootstrapWizardSuite) runWizard(c *gc.C, input string) (string, string, error) {
+	ctx := coretesting.Context(c)
+	ctx.Stdin = strings.NewReader(input)
+	envName, err := runBootstrapWizard(ctx, "")
+	return envName, ctx.Stdout.(*bytes.Buffer).String(), err
+}
+
+func (s *BootstrapWizardSuite) TestAddsEnvironment(c *gc.C) {
+	envName, out, err := s.runWizard(c, "\nnewenv\n\n\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envName, gc.Equals, "newenv")
+	c.Assert(out, jc.Contains, "Cloud type (dummy) [dummy]: Environment name [dummy]: ")
+	c.Assert(out, jc.Contains, "Secret: \n")
+	c.Assert(out, jc.Contains, `Environment "newenv" has been added to `)
+	c.Assert(out, gc.Not(jc.Contains), "The default environment is still")
+	c.Assert(s.verified, jc.DeepEquals, []string{"newenv"})
+
+	envs, err := environs.ReadEnvirons("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envs.Default, gc.Equals, "newenv")
+	cfg, err := envs.Config("newenv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AuthorizedKeys(), gc.Equals, coretesting.FakeAuthKeys)
+	c.Assert(cfg.UnknownAttrs()["secret"], gc.Equals, "bacon")
+}
+
+func (s *BootstrapWizardSuite) TestKeepsExistingEnvironments(c *gc.C) {
+	coretesting.MakeSampleJujuHome(c)
+	original, err := ioutil.ReadFile(osenv.JujuHomePath("environments.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, out, err := s.runWizard(c, "\nnewenv\n\n\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.Contains, `The default environment is still "erewhemos"; run "juju switch newenv" to change it.`)
+	envs, err := environs.ReadEnvirons("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envs.Names(), jc.SameContents, []string{"erewhemos", "newenv"})
+	c.Assert(envs.Default, gc.Equals, "erewhemos")
+
+	backup, err := ioutil.ReadFile(osenv.JujuHomePath("environments.yaml.20151021-162900.orig"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(backup), gc.Equals, string(original))
+}
+
+func (s *BootstrapWizardSuite) TestBackupNeverOverwritten(c *gc.C) {
+	coretesting.MakeSampleJujuHome(c)
+	original, err := ioutil.ReadFile(osenv.JujuHomePath("environments.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	existing := osenv.JujuHomePath("environments.yaml.20151021-162900.orig")
+	err = ioutil.WriteFile(existing, []byte("older backup"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, out, err := s.runWizard(c, "\nnewenv\n\n\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.Contains, "environments.yaml.20151021-162900-1.orig")
+
+	older, err := ioutil.ReadFile(existing)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(older), gc.Equals, "older backup")
+	backup, err := ioutil.ReadFile(osenv.JujuHomePath("environments.yaml.20151021-162900-1.orig"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(backup), gc.Equals, string(original))
+}
+
+func (s *BootstrapWizardSuite) TestExistingEnvironmentName(c *gc.C) {
+	coretesting.MakeSampleJujuHome(c)
+	_, _, err := s.runWizard(c, "\nerewhemos\n\n\n")
+	c.Assert(err, gc.ErrorMatches, `environment "erewhemos" already exists in environments.yaml`)
+	backups, err := filepath.Glob(osenv.JujuHomePath("environments.yaml.*.orig"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(backups, gc.HasLen, 0)
+}
+
+func (s *BootstrapWizardSuite) TestVerificationFails(c *gc.C) {
+	coretesting.MakeSampleJujuHome(c)
+	original, err := ioutil.ReadFile(osenv.JujuHomePath("environments.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(&verifyWizardEnvironment, func(*cmd.Context, *config.Config) error {
+		return errors.New("authentication failed")
+	})
+
+	_, _, err = s.runWizard(c, "\nnewenv\n\n\n")
+	c.Assert(err, gc.ErrorMatches, "cannot use dummy environment: authentication failed")
+	current, err := ioutil.ReadFile(osenv.JujuHomePath("environments.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(current), gc.Equals, string(original))
+	backups, err := filepath.Glob(osenv.JujuHomePath("environments.yaml.*.orig"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(backups, gc.HasLen, 0)
+}
+
+func (s *BootstrapWizardSuite) TestUnknownCloudType(c *gc.C) {
+	_, _, err := s.runWizard(c, "azure\n")
+	c.Assert(err, gc.ErrorMatches, `cloud type "azure" not supported by the bootstrap wizard`)
+}
+
+func (s *BootstrapWizardSuite) TestMissingValue(c *gc.C) {
+	s.PatchValue(&readWizardSecret, func() (string, error) {
+		return "", nil
+	})
+	_, _, err := s.runWizard(c, "\nnewenv\n\n\n")
+	c.Assert(err, gc.ErrorMatches, "no value given for Secret")
+}
+
+func (s *BootstrapWizardSuite) TestInvalidEnvironment(c *gc.C) {
+	_, _, err := s.runWizard(c, "\nnewenv\nnot-a-bool\n\n")
+	c.Assert(err, gc.ErrorMatches, `invalid dummy environment: .*state-server.*`)
+	_, err = environs.ReadEnvirons("")
+	c.Assert(err, jc.Satisfies, environs.IsNoEnv)
+}
+
+func (s *BootstrapWizardSuite) TestParseShellExports(c *gc.C) {
+	vars := parseShellExports(`
+# OpenStack credentials
+export OS_USERNAME=bob
+export OS_PASSWORD="s3cret"
+OS_TENANT_NAME='tenant'
+if [ -z "$OS_REGION_NAME" ]; then unset OS_REGION_NAME; fi
+`)
+	c.Assert(vars["OS_USERNAME"], gc.Equals, "bob")
+	c.Assert(vars["OS_PASSWORD"], gc.Equals, "s3cret")
+	c.Assert(vars["OS_TENANT_NAME"], gc.Equals, "tenant")
+	c.Assert(vars["OS_REGION_NAME"], gc.Equals, "")
+}
+
+func (s *BootstrapWizardSuite) TestDetectNovarc(c *gc.C) {
+	err := ioutil.WriteFile(filepath.Join(utils.Home(), ".novarc"), []byte(`
+export OS_AUTH_URL=https://keystone.example.com:5000/v2.0/
+export OS_USERNAME=bob
+export OS_PASSWORD=s3cret
+export OS_TENANT_NAME=tenant
+export OS_REGION_NAME=region-a
+`), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	values, err := detectNovarc()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{
+		"auth-url":    "https://keystone.example.com:5000/v2.0/",
+		"username":    "bob",
+		"password":    "s3cret",
+		"tenant-name": "tenant",
+		"region":      "region-a",
+	})
+}
+
+func (s *BootstrapWizardSuite) TestDetectNovarcMissing(c *gc.C) {
+	values, err := detectNovarc()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, gc.HasLen, 0)
+}
+
+func (s *BootstrapWizardSuite) TestDetectGCloudConfig(c *gc.C) {
+	dir := filepath.Join(utils.Home(), ".config", "gcloud")
+	err := os.MkdirAll(filepath.Join(dir, "configurations"), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(dir, "active_config"), []byte("work\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(dir, "configurations", "config_work"), []byte(`
+[core]
+account = bob@example.com
+project = my-project
+
+[compute]
+region = europe-west1
+`), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	values, err := detectGCloudConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{
+		"project-id": "my-project",
+		"region":     "europe-west1",
+	})
+}
+
+func (s *BootstrapWizardSuite) TestDetectMAASProfile(c *gc.C) {
+	s.PatchValue(&maasList, func() ([]byte, error) {
+		return []byte("admin http://10.0.0.1/MAAS/api/1.0/ a:b:c\n"), nil
+	})
+	values, err := detectMAASProfile()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{
+		"maas-server": "http://10.0.0.1/MAAS/",
+		"maas-oauth":  "a:b:c",
+	})
+}
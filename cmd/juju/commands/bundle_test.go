@@ -312,8 +312,43 @@ func (s *deployRepoCharmStoreSuite) TestDeployBundleWatcherTimeout(c *gc.C) {
 }
 
 func (s *deployRepoCharmStoreSuite) TestDeployBundleDirectoryError(c *gc.C) {
-	_, err := runDeployCommand(c, c.MkDir())
-	c.Assert(err, gc.ErrorMatches, "deployment of bundle directories not yet supported")
+	dir := c.MkDir()
+	_, err := runDeployCommand(c, dir)
+	c.Assert(err, gc.ErrorMatches, `cannot read bundle directory ".*": .*bundle.yaml.*`)
+}
+
+func (s *deployRepoCharmStoreSuite) TestDeployBundleDirectory(c *gc.C) {
+	testcharms.Repo.ClonedDirPath(s.SeriesPath, "mysql")
+	testcharms.Repo.ClonedDirPath(s.SeriesPath, "wordpress")
+	bundlePath := filepath.Join(c.MkDir(), "example")
+	c.Assert(os.Mkdir(bundlePath, 0777), jc.ErrorIsNil)
+	err := ioutil.WriteFile(filepath.Join(bundlePath, "bundle.yaml"), []byte(`
+        services:
+            wordpress:
+                charm: local:wordpress
+                num_units: 1
+            mysql:
+                charm: local:mysql
+                num_units: 1
+        relations:
+            - ["wordpress:db", "mysql:server"]
+    `), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(bundlePath, "README.md"), []byte("README"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	output, err := runDeployCommand(c, bundlePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, jc.HasSuffix, fmt.Sprintf("deployment of bundle %q completed", bundlePath))
+	s.assertServicesDeployed(c, map[string]serviceInfo{
+		"mysql":     {charm: "local:trusty/mysql-1"},
+		"wordpress": {charm: "local:trusty/wordpress-3"},
+	})
+	s.assertRelationsEstablished(c, "wordpress:db mysql:server")
+	s.assertUnitsCreated(c, map[string]string{
+		"mysql/0":     "0",
+		"wordpress/0": "1",
+	})
 }
 
 func (s *deployRepoCharmStoreSuite) TestDeployBundleLocalDeployment(c *gc.C) {
@@ -0,0 +1,224 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/simplestreams"
+	envtools "github.com/juju/juju/environs/tools"
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+const checkEnvironmentDoc = `
+Run a series of diagnostic checks against the environment's provider,
+and report which of them pass and which fail. This is useful before
+bootstrapping an environment or deploying many services, to detect
+problems such as invalid credentials, an unreachable endpoint, missing
+tools or image metadata, or an exhausted quota.
+
+The checks run depend on the provider; all providers check that the
+environment configuration is valid and that tools can be found.
+
+The command exits with a non-zero status if any check fails.
+
+Examples:
+
+    juju check-environment
+    juju check-environment -e my-ec2-env --format yaml
+`
+
+func newCheckEnvironmentCommand() cmd.Command {
+	return envcmd.Wrap(&checkEnvironmentCommand{})
+}
+
+// checkEnvironmentCommand runs health checks against an environment's
+// provider without requiring the environment to be bootstrapped.
+type checkEnvironmentCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+// checkResult holds the outcome of a single health check.
+type checkResult struct {
+	Check  string `yaml:"check" json:"check"`
+	Status string `yaml:"status" json:"status"`
+	Detail string `yaml:"detail,omitempty" json:"detail,omitempty"`
+}
+
+const (
+	checkPassed = "pass"
+	checkFailed = "fail"
+)
+
+func (c *checkEnvironmentCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "check-environment",
+		Purpose: "run diagnostic checks against an environment's provider",
+		Doc:     checkEnvironmentDoc,
+	}
+}
+
+func (c *checkEnvironmentCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCheckResultsTabular,
+	})
+}
+
+func (c *checkEnvironmentCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// openEnvironForChecks returns the named environment without modifying
+// the config store. An environment that has not yet been prepared is
+// prepared in memory only.
+var openEnvironForChecks = func(ctx *cmd.Context, envName string) (environs.Environ, error) {
+	store, err := configstore.Default()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, source, err := environs.ConfigForName(envName, store)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if source == environs.ConfigFromEnvirons {
+		return environs.Prepare(cfg, envcmd.BootstrapContextNoVerify(ctx), configstore.NewMem())
+	}
+	return environs.New(cfg)
+}
+
+func (c *checkEnvironmentCommand) Run(ctx *cmd.Context) error {
+	var results []checkResult
+	report := func(name string, err error) {
+		result := checkResult{Check: name, Status: checkPassed}
+		if err != nil {
+			result.Status = checkFailed
+			result.Detail = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	env, err := openEnvironForChecks(ctx, c.ConnectionName())
+	report("configuration", err)
+	if err == nil {
+		for _, check := range environHealthChecks(env) {
+			report(check.Name, check.Check())
+		}
+	}
+
+	if err := c.out.Write(ctx, results); err != nil {
+		return err
+	}
+	var failed int
+	for _, result := range results {
+		if result.Status == checkFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		ctx.Infof("%d of %d checks failed", failed, len(results))
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+// environHealthChecks returns the checks common to all environments,
+// followed by any provider-specific checks.
+func environHealthChecks(env environs.Environ) []environs.HealthCheck {
+	checks := []environs.HealthCheck{{
+		Name:  "tools-metadata",
+		Check: func() error { return checkToolsMetadata(env) },
+	}}
+	if _, ok := env.(simplestreams.HasRegion); ok {
+		checks = append(checks, environs.HealthCheck{
+			Name:  "image-metadata",
+			Check: func() error { return checkImageMetadata(env) },
+		})
+	}
+	if hce, ok := environs.SupportsHealthChecks(env); ok {
+		checks = append(checks, hce.HealthChecks()...)
+	}
+	return checks
+}
+
+var findTools = envtools.FindTools
+
+// checkToolsMetadata checks that tools matching the client's version
+// can be found for the environment.
+func checkToolsMetadata(env environs.Environ) error {
+	cfg := env.Config()
+	_, err := findTools(
+		env, version.Current.Major, version.Current.Minor,
+		cfg.AgentStream(), coretools.Filter{},
+	)
+	if errors.IsNotFound(err) {
+		return errors.Errorf("no %d.%d tools found in stream %q", version.Current.Major, version.Current.Minor, cfg.AgentStream())
+	}
+	return errors.Trace(err)
+}
+
+// checkImageMetadata checks that images for the environment's default
+// series can be found in the environment's region.
+func checkImageMetadata(env environs.Environ) error {
+	cloudSpec, err := env.(simplestreams.HasRegion).Region()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sources, err := environs.ImageMetadataSources(env)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg := env.Config()
+	series := config.PreferredSeries(cfg)
+	imageConstraint := imagemetadata.NewImageConstraint(simplestreams.LookupParams{
+		CloudSpec: cloudSpec,
+		Series:    []string{series},
+		Stream:    cfg.ImageStream(),
+	})
+	images, _, err := imagemetadata.Fetch(sources, imageConstraint, false)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if len(images) == 0 {
+		return errors.Errorf("no %s images found in region %q", series, cloudSpec.Region)
+	}
+	return nil
+}
+
+func formatCheckResultsTabular(value interface{}) ([]byte, error) {
+	results, ok := value.([]checkResult)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", results, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "CHECK\tSTATUS\tDETAIL\n")
+	for _, result := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Check, result.Status, result.Detail)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
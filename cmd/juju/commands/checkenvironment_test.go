@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+)
+
+type CheckEnvironmentSuite struct {
+	testing.FakeJujuHomeSuite
+	env     *healthCheckEnviron
+	openErr error
+}
+
+var _ = gc.Suite(&CheckEnvironmentSuite{})
+
+type healthCheckEnviron struct {
+	environs.Environ
+	cfg    *config.Config
+	checks []environs.HealthCheck
+}
+
+func (e *healthCheckEnviron) Config() *config.Config {
+	return e.cfg
+}
+
+func (e *healthCheckEnviron) HealthChecks() []environs.HealthCheck {
+	return e.checks
+}
+
+func (s *CheckEnvironmentSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.openErr = nil
+	s.env = &healthCheckEnviron{
+		cfg: testing.EnvironConfig(c),
+		checks: []environs.HealthCheck{{
+			Name:  "credentials",
+			Check: func() error { return nil },
+		}, {
+			Name:  "quota",
+			Check: func() error { return nil },
+		}},
+	}
+	s.PatchValue(&openEnvironForChecks, func(*cmd.Context, string) (environs.Environ, error) {
+		if s.openErr != nil {
+			return nil, s.openErr
+		}
+		return s.env, nil
+	})
+	s.PatchValue(&findTools, func(environs.Environ, int, int, string, coretools.Filter) (coretools.List, error) {
+		return coretools.List{&coretools.Tools{}}, nil
+	})
+}
+
+func (s *CheckEnvironmentSuite) TestInit(c *gc.C) {
+	_, err := testing.RunCommand(c, newCheckEnvironmentCommand(), "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func (s *CheckEnvironmentSuite) TestAllPass(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newCheckEnvironmentCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"CHECK           STATUS  DETAIL\n"+
+		"configuration   pass    \n"+
+		"tools-metadata  pass    \n"+
+		"credentials     pass    \n"+
+		"quota           pass    \n",
+	)
+}
+
+func (s *CheckEnvironmentSuite) TestCheckFails(c *gc.C) {
+	s.env.checks[1].Check = func() error {
+		return errors.New("quota exhausted: INSTANCES (24 of 24)")
+	}
+	ctx, err := testing.RunCommand(c, newCheckEnvironmentCommand(), "--format", "yaml")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- check: configuration
+  status: pass
+- check: tools-metadata
+  status: pass
+- check: credentials
+  status: pass
+- check: quota
+  status: fail
+  detail: 'quota exhausted: INSTANCES (24 of 24)'
+`[1:])
+	c.Assert(testing.Stderr(ctx), gc.Equals, "1 of 4 checks failed\n")
+}
+
+func (s *CheckEnvironmentSuite) TestNoToolsFound(c *gc.C) {
+	s.PatchValue(&findTools, func(environs.Environ, int, int, string, coretools.Filter) (coretools.List, error) {
+		return nil, errors.NotFoundf("tools")
+	})
+	ctx, err := testing.RunCommand(c, newCheckEnvironmentCommand(), "--format", "json")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stdout(ctx), gc.Matches, `(?s).*{"check":"tools-metadata","status":"fail","detail":"no .*`)
+}
+
+func (s *CheckEnvironmentSuite) TestConfigurationFails(c *gc.C) {
+	s.openErr = errors.New(`environment "erewhemos" not found`)
+	ctx, err := testing.RunCommand(c, newCheckEnvironmentCommand())
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"CHECK          STATUS  DETAIL\n"+
+		"configuration  fail    environment \"erewhemos\" not found\n",
+	)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "1 of 1 checks failed\n")
+}
@@ -6,6 +6,7 @@ package commands
 import (
 	"fmt"
 	"io"
+	"regexp"
 
 	"github.com/juju/cmd"
 	"github.com/juju/loggo"
@@ -56,6 +57,8 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.level, "l", "", "log level to show, one of [TRACE, DEBUG, INFO, WARNING, ERROR]")
 	f.StringVar(&c.level, "level", "", "")
 
+	f.StringVar(&c.params.MessageRegexp, "grep", "", "only show log messages matching this regular expression")
+
 	f.UintVar(&c.params.Backlog, "n", defaultLineCount, "go back this many lines from the end before starting to filter")
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "show at most this many lines")
@@ -71,6 +74,11 @@ func (c *debugLogCommand) Init(args []string) error {
 		}
 		c.params.Level = level
 	}
+	if c.params.MessageRegexp != "" {
+		if _, err := regexp.Compile(c.params.MessageRegexp); err != nil {
+			return fmt.Errorf("grep value %q is not a valid regular expression", c.params.MessageRegexp)
+		}
+	}
 	return cmd.CheckEmpty(args)
 }
 
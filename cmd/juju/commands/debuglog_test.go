@@ -73,6 +73,15 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				ExcludeModule: []string{"juju.foo", "unit"},
 				Backlog:       10,
 			},
+		}, {
+			args: []string{"--grep", "hook .* failed"},
+			expected: api.DebugLogParams{
+				Backlog:       10,
+				MessageRegexp: "hook .* failed",
+			},
+		}, {
+			args:     []string{"--grep", "foo("},
+			errMatch: `grep value "foo\(" is not a valid regular expression`,
 		}, {
 			args: []string{"--replay"},
 			expected: api.DebugLogParams{
@@ -46,6 +46,10 @@ type deployCommand struct {
 	// Storage is a map of storage constraints, keyed on the storage name
 	// defined in charm storage metadata.
 	Storage map[string]storage.Constraints
+
+	// Bindings maps charm endpoint names to the network spaces they
+	// are bound to.
+	Bindings map[string]string
 }
 
 const deployDoc = `
@@ -76,7 +80,8 @@ environment, one must specify the series. For example:
 
 Local bundles can be specified either with a local:bundle/<name> URL, which is
 interpreted relative to $JUJU_REPOSITORY, or with a direct path to a
-bundle.yaml file. For example, to deploy the bundle in
+bundle.yaml file or the directory holding it. For example, to deploy the
+bundle in
 $JUJU_REPOSITORY/bundle/openstack:
 
   juju deploy local:bundle/openstack
@@ -84,6 +89,7 @@ $JUJU_REPOSITORY/bundle/openstack:
 To deploy this using a direct path:
 
   juju deploy $JUJU_REPOSITORY/bundle/openstack/bundle.yaml
+  juju deploy $JUJU_REPOSITORY/bundle/openstack
 
 <service name>, if omitted, will be derived from <charm name>.
 
@@ -117,6 +123,12 @@ When deploying a service or adding machines, the "spaces" constraint can be
 used to define a comma-delimited list of required and forbidden spaces
 (the latter prefixed with "^", similar to the "tags" constraint).
 
+Individual charm endpoints can be bound to spaces with the --bind flag, which
+takes space-separated <endpoint>=<space> pairs. Machines for the service's
+units are provisioned in the bound spaces as well as those in the "spaces"
+constraint, and the address a unit publishes in the settings of a relation
+is chosen from the space its endpoint is bound to.
+
 If you have the main container directory mounted on a btrfs partition,
 then the clone will be using btrfs snapshots to create the containers.
 This means that clones use up much less disk space.  If you do not have btrfs,
@@ -137,6 +149,10 @@ Examples:
    (deploy 2 instances of haproxy on cloud instances being part of the dmz
     space but not of the cmd and the database space)
 
+   juju deploy mysql --bind "db=internal monitors=dmz"
+   (deploy mysql with its db endpoint in the internal space and its
+    monitors endpoint in the dmz space)
+
 See Also:
    juju help spaces
    juju help constraints
@@ -163,6 +179,7 @@ func (c *deployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Networks, "networks", "", "deprecated and ignored: use space constraints instead.")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	f.Var(storageFlag{&c.Storage}, "storage", "charm storage constraints")
+	f.Var(bindingsFlag{&c.Bindings}, "bind", "bind charm endpoints to network spaces")
 }
 
 func (c *deployCommand) Init(args []string) error {
@@ -222,11 +239,14 @@ func (c *deployCommand) Run(ctx *cmd.Context) error {
 		if err != nil {
 			return block.ProcessBlockedError(err, block.BlockChange)
 		}
+		var bundleData *charm.BundleData
 		if info.IsDir() {
-			return errors.New("deployment of bundle directories not yet supported")
-		}
-		bundleData, err := charm.ReadBundleData(f)
-		if err != nil {
+			bundleDir, err := charm.ReadBundleDir(f.Name())
+			if err != nil {
+				return errors.Annotatef(err, "cannot read bundle directory %q", f.Name())
+			}
+			bundleData = bundleDir.Data()
+		} else if bundleData, err = charm.ReadBundleData(f); err != nil {
 			return block.ProcessBlockedError(err, block.BlockChange)
 		}
 		if err := deployBundle(bundleData, client, csClient, repoPath, conf, ctx); err != nil {
@@ -296,9 +316,10 @@ func (c *deployCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	// If storage or placement is specified, we attempt to use a new API on the service facade.
-	if len(c.Storage) > 0 || len(c.Placement) > 0 {
-		notSupported := errors.New("cannot deploy charms with storage or placement: not supported by the API server")
+	// If storage, placement or endpoint bindings are specified, we
+	// attempt to use a new API on the service facade.
+	if len(c.Storage) > 0 || len(c.Placement) > 0 || len(c.Bindings) > 0 {
+		notSupported := errors.New("cannot deploy charms with storage, placement or endpoint bindings: not supported by the API server")
 		serviceClient, err := c.newServiceAPIClient()
 		if err != nil {
 			return notSupported
@@ -320,6 +341,7 @@ func (c *deployCommand) Run(ctx *cmd.Context) error {
 			c.Placement,
 			[]string{},
 			c.Storage,
+			c.Bindings,
 		)
 		if params.IsCodeNotImplemented(err) {
 			return notSupported
@@ -80,6 +80,15 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db"},
+		err:  `invalid value "db" for flag --bind: expected <endpoint>=<space>, got "db"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=Bad_Space"},
+		err:  `invalid value "db=Bad_Space" for flag --bind: invalid space name "Bad_Space"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=a db=b"},
+		err:  `invalid value "db=a db=b" for flag --bind: endpoint "db" bound more than once`,
 	},
 }
 
@@ -205,6 +214,29 @@ func (s *DeploySuite) TestNetworksIsDeprecated(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "use of --networks is deprecated. Please use spaces")
 }
 
+func (s *DeploySuite) TestEndpointBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	err = runDeploy(c, "local:mysql", "--bind", "server=internal juju-info=dmz")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/mysql-1")
+	service, _ := s.AssertService(c, "mysql", curl, 1, 0)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{
+		"server":    "internal",
+		"juju-info": "dmz",
+	})
+}
+
+func (s *DeploySuite) TestEndpointBindingsUnknownSpace(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	err := runDeploy(c, "local:mysql", "--bind", "server=internal")
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "mysql": space "internal" not found`)
+}
+
 // TODO(wallyworld) - add another test that deploy with storage fails for older environments
 // (need deploy client to be refactored to use API stub)
 func (s *DeploySuite) TestStorage(c *gc.C) {
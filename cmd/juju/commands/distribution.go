@@ -0,0 +1,221 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/distribution"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const distributionDoc = `
+Reports how the units of each service are distributed across machines
+and availability zones, and warns about services whose units are not
+spread evenly across zones, or whose units all share a single machine
+or zone.
+
+Units in containers are counted against the machine hosting the
+container. Availability zones are only known for provisioned machines.
+
+With --rebalance, the report also suggests which units to move, and to
+which zones, to balance each service across zones. No units are moved.
+
+Examples:
+
+    juju distribution
+    juju distribution --rebalance --format yaml
+`
+
+func newDistributionCommand() cmd.Command {
+	return envcmd.Wrap(&distributionCommand{})
+}
+
+// distributionCommand reports the distribution of units across
+// machines and availability zones.
+type distributionCommand struct {
+	envcmd.EnvCommandBase
+	out       cmd.Output
+	rebalance bool
+	api       distributionAPI
+}
+
+// distributionAPI defines the methods on the Distribution facade
+// that the distribution command calls.
+type distributionAPI interface {
+	Close() error
+	Report() (params.DistributionReport, error)
+}
+
+func (c *distributionCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "distribution",
+		Purpose: "report the distribution of units across machines and zones",
+		Doc:     distributionDoc,
+	}
+}
+
+func (c *distributionCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.rebalance, "rebalance", false, "suggest unit moves that would balance services across zones")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatDistributionTabular,
+	})
+}
+
+func (c *distributionCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *distributionCommand) getAPI() (distributionAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return distribution.NewAPI(root), nil
+}
+
+func (c *distributionCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	report, err := api.Report()
+	if err != nil {
+		return err
+	}
+	services := make(map[string]serviceDistribution)
+	for _, service := range report.Services {
+		tag, err := names.ParseServiceTag(service.ServiceTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		services[tag.Id()] = c.formatService(service)
+	}
+	return c.out.Write(ctx, services)
+}
+
+// serviceDistribution is the formatted distribution of a service.
+type serviceDistribution struct {
+	Units    int            `yaml:"units" json:"units"`
+	Machines map[string]int `yaml:"machines,omitempty" json:"machines,omitempty"`
+	Zones    map[string]int `yaml:"zones,omitempty" json:"zones,omitempty"`
+	Warnings []string       `yaml:"warnings,omitempty" json:"warnings,omitempty"`
+	Moves    []unitMove     `yaml:"rebalance,omitempty" json:"rebalance,omitempty"`
+}
+
+// unitMove is a formatted suggestion to move a unit to another zone.
+type unitMove struct {
+	Unit string `yaml:"unit" json:"unit"`
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+}
+
+func (c *distributionCommand) formatService(service params.ServiceDistribution) serviceDistribution {
+	result := serviceDistribution{
+		Units:    service.Units,
+		Machines: service.Hosts,
+		Zones:    service.Zones,
+	}
+	if service.SingleHost {
+		result.Warnings = append(result.Warnings, "all units are on a single machine")
+	}
+	if service.SingleZone {
+		result.Warnings = append(result.Warnings, "all units are in a single zone")
+	}
+	if service.Imbalanced {
+		result.Warnings = append(result.Warnings, "units are not balanced across zones")
+	}
+	if !c.rebalance {
+		return result
+	}
+	for _, move := range service.Moves {
+		unit := move.UnitTag
+		if tag, err := names.ParseUnitTag(move.UnitTag); err == nil {
+			unit = tag.Id()
+		}
+		result.Moves = append(result.Moves, unitMove{
+			Unit: unit,
+			From: move.FromZone,
+			To:   move.ToZone,
+		})
+	}
+	return result
+}
+
+func formatDistributionTabular(value interface{}) ([]byte, error) {
+	services, ok := value.(map[string]serviceDistribution)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", services, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "SERVICE\tUNITS\tMACHINES\tZONES\tWARNINGS\n")
+	var moves []string
+	for _, name := range sortedServiceNames(services) {
+		service := services[name]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n",
+			name, service.Units, len(service.Machines),
+			formatCounts(service.Zones), strings.Join(service.Warnings, "; "),
+		)
+		for _, move := range service.Moves {
+			moves = append(moves, fmt.Sprintf("%s\t%s\t%s\n", move.Unit, move.From, move.To))
+		}
+	}
+	if len(moves) > 0 {
+		fmt.Fprintf(tw, "\nUNIT\tFROM ZONE\tTO ZONE\n")
+		for _, move := range moves {
+			fmt.Fprint(tw, move)
+		}
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+func sortedServiceNames(services map[string]serviceDistribution) []string {
+	result := make([]string, 0, len(services))
+	for name := range services {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// formatCounts formats a map of names to counts as
+// space-separated name:count pairs, sorted by name.
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s:%d", key, counts[key])
+	}
+	return strings.Join(parts, " ")
+}
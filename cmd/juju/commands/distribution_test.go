@@ -0,0 +1,126 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type DistributionSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeDistributionAPI
+}
+
+var _ = gc.Suite(&DistributionSuite{})
+
+type fakeDistributionAPI struct {
+	report params.DistributionReport
+	err    error
+}
+
+func (f *fakeDistributionAPI) Close() error {
+	return nil
+}
+
+func (f *fakeDistributionAPI) Report() (params.DistributionReport, error) {
+	return f.report, f.err
+}
+
+func (s *DistributionSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeDistributionAPI{
+		report: params.DistributionReport{
+			Services: []params.ServiceDistribution{{
+				ServiceTag: "service-wordpress",
+				Units:      2,
+				Zones:      map[string]int{"az1": 2, "az2": 0},
+				Hosts:      map[string]int{"0": 1, "1": 1},
+				Imbalanced: true,
+				SingleZone: true,
+				Moves: []params.UnitMove{{
+					UnitTag:  "unit-wordpress-1",
+					FromZone: "az1",
+					ToZone:   "az2",
+				}},
+			}, {
+				ServiceTag: "service-mysql",
+				Units:      1,
+				Zones:      map[string]int{"az1": 1, "az2": 0},
+				Hosts:      map[string]int{"2": 1},
+			}},
+		},
+	}
+}
+
+func (s *DistributionSuite) newCommand() cmd.Command {
+	return envcmd.Wrap(&distributionCommand{api: s.api})
+}
+
+func (s *DistributionSuite) TestInit(c *gc.C) {
+	_, err := testing.RunCommand(c, s.newCommand(), "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func (s *DistributionSuite) TestTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.newCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"SERVICE    UNITS  MACHINES  ZONES        WARNINGS\n"+
+		"mysql      1      1         az1:1 az2:0  \n"+
+		"wordpress  2      2         az1:2 az2:0  all units are in a single zone; units are not balanced across zones\n",
+	)
+}
+
+func (s *DistributionSuite) TestTabularRebalance(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.newCommand(), "--rebalance")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), jc.HasSuffix, ""+
+		"\n"+
+		"UNIT         FROM ZONE  TO ZONE\n"+
+		"wordpress/1  az1        az2\n",
+	)
+}
+
+func (s *DistributionSuite) TestYAMLRebalance(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.newCommand(), "--rebalance", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+mysql:
+  units: 1
+  machines:
+    "2": 1
+  zones:
+    az1: 1
+    az2: 0
+wordpress:
+  units: 2
+  machines:
+    "0": 1
+    "1": 1
+  zones:
+    az1: 2
+    az2: 0
+  warnings:
+  - all units are in a single zone
+  - units are not balanced across zones
+  rebalance:
+  - unit: wordpress/1
+    from: az1
+    to: az2
+`[1:])
+}
+
+func (s *DistributionSuite) TestError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := testing.RunCommand(c, s.newCommand())
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
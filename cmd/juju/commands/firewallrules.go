@@ -0,0 +1,231 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
+)
+
+func newFirewallRulesCommand() cmd.Command {
+	return envcmd.Wrap(&firewallRulesCommand{})
+}
+
+// firewallRulesCommand displays, and optionally reconciles, the
+// firewall rules applied in an environment.
+type firewallRulesCommand struct {
+	envcmd.EnvCommandBase
+	out     cmd.Output
+	columns common.Columns
+	client  FirewallRulesClient
+
+	// Sync, if true, causes the provider's firewall rules to be
+	// updated to match those expected by Juju.
+	Sync bool
+}
+
+const firewallRulesDoc = `
+Shows the port ranges Juju expects to be open on each machine, alongside
+those the cloud provider actually has open, and any differences between
+the two. Juju expects a port range to be open if a unit of an exposed
+service has opened it. When the environment's firewall-mode is "global",
+a single set of rules for the whole environment is shown.
+
+Differences may arise when firewall rules are changed outside of Juju.
+With --sync, port ranges are opened and closed in the provider so that
+its rules match those expected by Juju.
+
+The columns shown in tabular output may be chosen with --columns.
+
+Examples:
+    juju firewall-rules
+    juju firewall-rules --format yaml
+    juju firewall-rules --sync
+    juju firewall-rules --columns machine,status
+`
+
+func (c *firewallRulesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "firewall-rules",
+		Args:    "[--sync]",
+		Purpose: "show, and optionally reconcile, the environment's firewall rules",
+		Doc:     firewallRulesDoc,
+	}
+}
+
+func (c *firewallRulesCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Sync, "sync", false, "update the provider's firewall rules to match those expected by Juju")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+	})
+	c.columns.AddFlag(f)
+}
+
+func (c *firewallRulesCommand) Init(args []string) error {
+	if err := c.columns.CheckFormat(c.out.Name()); err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// FirewallRulesClient defines the methods on the FirewallRules API
+// facade that the firewall-rules command calls.
+type FirewallRulesClient interface {
+	Close() error
+	ListFirewallRules() (params.FirewallRulesResults, error)
+	SyncFirewallRules() (params.FirewallRulesResults, error)
+}
+
+func (c *firewallRulesCommand) getClient() (FirewallRulesClient, error) {
+	if c.client != nil {
+		return c.client, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return firewallrules.NewClient(root), nil
+}
+
+func (c *firewallRulesCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var results params.FirewallRulesResults
+	if c.Sync {
+		results, err = client.SyncFirewallRules()
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	} else {
+		results, err = client.ListFirewallRules()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return c.out.Write(ctx, newFirewallRulesInfo(results))
+}
+
+type firewallRulesInfo struct {
+	Mode  string             `json:"mode" yaml:"mode"`
+	Rules []firewallRuleInfo `json:"rules" yaml:"rules"`
+}
+
+type firewallRuleInfo struct {
+	Machine    string   `json:"machine,omitempty" yaml:"machine,omitempty"`
+	Instance   string   `json:"instance,omitempty" yaml:"instance,omitempty"`
+	Expected   []string `json:"expected" yaml:"expected,flow"`
+	Actual     []string `json:"actual" yaml:"actual,flow"`
+	Missing    []string `json:"missing,omitempty" yaml:"missing,flow,omitempty"`
+	Unexpected []string `json:"unexpected,omitempty" yaml:"unexpected,flow,omitempty"`
+	Error      string   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func newFirewallRulesInfo(results params.FirewallRulesResults) firewallRulesInfo {
+	info := firewallRulesInfo{
+		Mode:  results.Mode,
+		Rules: make([]firewallRuleInfo, len(results.Results)),
+	}
+	for i, result := range results.Results {
+		rule := firewallRuleInfo{
+			Machine:  result.MachineId,
+			Instance: result.InstanceId,
+			Expected: portRangeStrings(result.Expected),
+			Actual:   portRangeStrings(result.Actual),
+		}
+		if result.Error != nil {
+			rule.Error = result.Error.Error()
+		} else {
+			rule.Missing = diffStrings(rule.Expected, rule.Actual)
+			rule.Unexpected = diffStrings(rule.Actual, rule.Expected)
+		}
+		info.Rules[i] = rule
+	}
+	return info
+}
+
+func portRangeStrings(ports []params.PortRange) []string {
+	result := make([]string, len(ports))
+	for i, portRange := range ports {
+		result[i] = portRange.NetworkPortRange().String()
+	}
+	return result
+}
+
+// diffStrings returns the strings in a that are not in b.
+func diffStrings(a, b []string) []string {
+	var result []string
+next:
+	for _, s := range a {
+		for _, t := range b {
+			if s == t {
+				continue next
+			}
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// formatTabular returns a tabular summary of the firewall rules.
+func (c *firewallRulesCommand) formatTabular(value interface{}) ([]byte, error) {
+	info, ok := value.(firewallRulesInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", info, value)
+	}
+	table := common.NewTable("MACHINE", "INSTANCE", "EXPECTED", "ACTUAL", "STATUS")
+	table.Padding = 1
+	for _, rule := range info.Rules {
+		machine := rule.Machine
+		if machine == "" {
+			machine = "(environment)"
+		}
+		table.AddRow(
+			machine,
+			rule.Instance,
+			joinOrNone(rule.Expected),
+			joinOrNone(rule.Actual),
+			ruleStatus(rule),
+		)
+	}
+	return table.Format(c.columns)
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ",")
+}
+
+func ruleStatus(rule firewallRuleInfo) string {
+	if rule.Error != "" {
+		return "error: " + rule.Error
+	}
+	var drift []string
+	if len(rule.Missing) > 0 {
+		drift = append(drift, "missing "+strings.Join(rule.Missing, ","))
+	}
+	if len(rule.Unexpected) > 0 {
+		drift = append(drift, "unexpected "+strings.Join(rule.Unexpected, ","))
+	}
+	if len(drift) == 0 {
+		return "ok"
+	}
+	return strings.Join(drift, "; ")
+}
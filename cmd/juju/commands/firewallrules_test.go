@@ -0,0 +1,159 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type FirewallRulesSuite struct {
+	coretesting.FakeJujuHomeSuite
+	fake *fakeFirewallRulesClient
+}
+
+var _ = gc.Suite(&FirewallRulesSuite{})
+
+func (s *FirewallRulesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeFirewallRulesClient{
+		results: params.FirewallRulesResults{
+			Mode: "instance",
+			Results: []params.FirewallRules{{
+				MachineId:  "0",
+				InstanceId: "inst-0",
+				Expected:   []params.PortRange{{80, 80, "tcp"}, {443, 443, "tcp"}},
+				Actual:     []params.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}},
+			}, {
+				MachineId:  "1",
+				InstanceId: "inst-1",
+				Expected:   []params.PortRange{},
+				Actual:     []params.PortRange{},
+			}, {
+				MachineId:  "2",
+				InstanceId: "inst-2",
+				Expected:   []params.PortRange{{22, 22, "tcp"}},
+				Error:      &params.Error{Message: "instance not found"},
+			}},
+		},
+	}
+}
+
+type fakeFirewallRulesClient struct {
+	calls   []string
+	results params.FirewallRulesResults
+	err     error
+}
+
+func (f *fakeFirewallRulesClient) Close() error {
+	return nil
+}
+
+func (f *fakeFirewallRulesClient) ListFirewallRules() (params.FirewallRulesResults, error) {
+	f.calls = append(f.calls, "ListFirewallRules")
+	return f.results, f.err
+}
+
+func (f *fakeFirewallRulesClient) SyncFirewallRules() (params.FirewallRulesResults, error) {
+	f.calls = append(f.calls, "SyncFirewallRules")
+	return f.results, f.err
+}
+
+func (s *FirewallRulesSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &firewallRulesCommand{client: s.fake}
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *FirewallRulesSuite) TestTabular(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"ListFirewallRules"})
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"MACHINE INSTANCE EXPECTED       ACTUAL          STATUS\n"+
+		"0       inst-0   80/tcp,443/tcp 80/tcp,8080/tcp missing 443/tcp; unexpected 8080/tcp\n"+
+		"1       inst-1   -              -               ok\n"+
+		"2       inst-2   22/tcp         -               error: instance not found\n",
+	)
+}
+
+func (s *FirewallRulesSuite) TestTabularGlobal(c *gc.C) {
+	s.fake.results = params.FirewallRulesResults{
+		Mode: "global",
+		Results: []params.FirewallRules{{
+			Expected: []params.PortRange{{80, 80, "tcp"}},
+			Actual:   []params.PortRange{{80, 80, "tcp"}},
+		}},
+	}
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"MACHINE       INSTANCE EXPECTED ACTUAL STATUS\n"+
+		"(environment)          80/tcp   80/tcp ok\n",
+	)
+}
+
+func (s *FirewallRulesSuite) TestTabularColumns(c *gc.C) {
+	ctx, err := s.run(c, "--columns", "machine,status")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"MACHINE STATUS\n"+
+		"0       missing 443/tcp; unexpected 8080/tcp\n"+
+		"1       ok\n"+
+		"2       error: instance not found\n",
+	)
+}
+
+func (s *FirewallRulesSuite) TestYAML(c *gc.C) {
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+mode: instance
+rules:
+- machine: "0"
+  instance: inst-0
+  expected: [80/tcp, 443/tcp]
+  actual: [80/tcp, 8080/tcp]
+  missing: [443/tcp]
+  unexpected: [8080/tcp]
+- machine: "1"
+  instance: inst-1
+  expected: []
+  actual: []
+- machine: "2"
+  instance: inst-2
+  expected: [22/tcp]
+  actual: []
+  error: instance not found
+`[1:])
+}
+
+func (s *FirewallRulesSuite) TestSync(c *gc.C) {
+	_, err := s.run(c, "--sync")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"SyncFirewallRules"})
+}
+
+func (s *FirewallRulesSuite) TestSyncBlocked(c *gc.C) {
+	s.fake.err = &params.Error{Code: params.CodeOperationBlocked, Message: "TestSyncBlocked"}
+	_, err := s.run(c, "--sync")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Check(c.GetTestLog(), jc.Contains, "TestSyncBlocked")
+}
+
+func (s *FirewallRulesSuite) TestError(c *gc.C) {
+	s.fake.err = errors.NotSupportedf(`firewall-mode "none"`)
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, `firewall-mode "none" not supported`)
+}
+
+func (s *FirewallRulesSuite) TestInitRejectsArgs(c *gc.C) {
+	_, err := s.run(c, "0")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["0"\]`)
+}
@@ -5,9 +5,11 @@ package commands
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/storage"
 )
@@ -41,3 +43,42 @@ func (f storageFlag) String() string {
 	}
 	return strings.Join(strs, " ")
 }
+
+// bindingsFlag is a gnuflag.Value for endpoint bindings, written as
+// space-separated <endpoint>=<space> pairs. It may be given more than
+// once.
+type bindingsFlag struct {
+	bindings *map[string]string
+}
+
+// Set implements gnuflag.Value.Set.
+func (f bindingsFlag) Set(s string) error {
+	for _, field := range strings.Fields(s) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) < 2 || parts[0] == "" {
+			return errors.Errorf("expected <endpoint>=<space>, got %q", field)
+		}
+		endpoint, space := parts[0], parts[1]
+		if !names.IsValidSpace(space) {
+			return errors.Errorf("invalid space name %q", space)
+		}
+		if *f.bindings == nil {
+			*f.bindings = make(map[string]string)
+		}
+		if _, ok := (*f.bindings)[endpoint]; ok {
+			return errors.Errorf("endpoint %q bound more than once", endpoint)
+		}
+		(*f.bindings)[endpoint] = space
+	}
+	return nil
+}
+
+// String implements gnuflag.Value.String.
+func (f bindingsFlag) String() string {
+	strs := make([]string, 0, len(*f.bindings))
+	for endpoint, space := range *f.bindings {
+		strs = append(strs, endpoint+"="+space)
+	}
+	sort.Strings(strs)
+	return strings.Join(strs, " ")
+}
@@ -0,0 +1,210 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+const listInstanceTypesDoc = `
+List the instance types available in the environment's region, with
+their cpu cores, memory, root disk and cost, in order of increasing cost.
+
+Costs are those Juju uses to choose between instance types, and are only
+known for some providers; on EC2 they are in thousandths of a US dollar
+per hour.
+
+If --constraints is specified, only the instance types satisfying the
+constraints are listed, and the type Juju would select for a new machine
+with those constraints is marked with "*". The selection also depends on
+the images available, so a more expensive type may be chosen if there are
+no images suitable for the cheapest.
+
+The environment does not need to be bootstrapped. Not all providers can
+list their instance types.
+
+Examples:
+
+    juju list-instance-types
+    juju list-instance-types --constraints "mem=4G cpu-cores=2"
+    juju list-instance-types -e my-ec2-env --format yaml
+
+See Also:
+   juju help constraints
+`
+
+func newListInstanceTypesCommand() cmd.Command {
+	return envcmd.Wrap(&listInstanceTypesCommand{})
+}
+
+// listInstanceTypesCommand lists the instance types available to an
+// environment, optionally filtered by constraints.
+type listInstanceTypesCommand struct {
+	envcmd.EnvCommandBase
+	out         cmd.Output
+	constraints constraints.Value
+}
+
+// instanceTypesInfo holds the instance types reported by
+// list-instance-types.
+type instanceTypesInfo struct {
+	Region        string             `yaml:"region,omitempty" json:"region,omitempty"`
+	InstanceTypes []instanceTypeInfo `yaml:"instance-types" json:"instance-types"`
+}
+
+// instanceTypeInfo describes a single instance type.
+type instanceTypeInfo struct {
+	Name     string   `yaml:"name" json:"name"`
+	Arches   []string `yaml:"arches" json:"arches"`
+	CpuCores uint64   `yaml:"cpu-cores" json:"cpu-cores"`
+	CpuPower *uint64  `yaml:"cpu-power,omitempty" json:"cpu-power,omitempty"`
+	Mem      uint64   `yaml:"mem" json:"mem"`
+	RootDisk uint64   `yaml:"root-disk,omitempty" json:"root-disk,omitempty"`
+	VirtType string   `yaml:"virt-type,omitempty" json:"virt-type,omitempty"`
+	Cost     uint64   `yaml:"cost,omitempty" json:"cost,omitempty"`
+	Selected bool     `yaml:"selected,omitempty" json:"selected,omitempty"`
+}
+
+func (c *listInstanceTypesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-instance-types",
+		Purpose: "list the instance types available to an environment",
+		Doc:     listInstanceTypesDoc,
+	}
+}
+
+func (c *listInstanceTypesCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(constraints.ConstraintsValue{Target: &c.constraints}, "constraints", "only list instance types satisfying these constraints")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatInstanceTypesTabular,
+	})
+}
+
+func (c *listInstanceTypesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// openEnvironForInstanceTypes returns the named environment, which
+// need not be bootstrapped. It is a variable so it can be changed for
+// testing.
+var openEnvironForInstanceTypes = func(ctx *cmd.Context, envName string) (environs.Environ, error) {
+	return openEnvironForChecks(ctx, envName)
+}
+
+func (c *listInstanceTypesCommand) Run(ctx *cmd.Context) error {
+	env, err := openEnvironForInstanceTypes(ctx, c.ConnectionName())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fetcher, ok := environs.SupportsInstanceTypes(env)
+	if !ok {
+		return errors.NotSupportedf("listing instance types in %q environments", env.Config().Type())
+	}
+	itypes, err := fetcher.InstanceTypes()
+	if err != nil {
+		return errors.Annotate(err, "cannot list instance types")
+	}
+
+	var info instanceTypesInfo
+	if hasRegion, ok := env.(simplestreams.HasRegion); ok {
+		cloudSpec, err := hasRegion.Region()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		info.Region = cloudSpec.Region
+	}
+	selecting := !constraints.IsEmpty(&c.constraints)
+	if selecting {
+		itypes, err = instances.MatchingInstanceTypes(itypes, info.Region, c.constraints)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	info.InstanceTypes = make([]instanceTypeInfo, len(itypes))
+	for i, itype := range itypes {
+		info.InstanceTypes[i] = instanceTypeInfo{
+			Name:     itype.Name,
+			Arches:   itype.Arches,
+			CpuCores: itype.CpuCores,
+			CpuPower: itype.CpuPower,
+			Mem:      itype.Mem,
+			RootDisk: itype.RootDisk,
+			Cost:     itype.Cost,
+			Selected: selecting && i == 0,
+		}
+		if itype.VirtType != nil {
+			info.InstanceTypes[i].VirtType = *itype.VirtType
+		}
+	}
+	return c.out.Write(ctx, info)
+}
+
+func formatInstanceTypesTabular(value interface{}) ([]byte, error) {
+	info, ok := value.(instanceTypesInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", info, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "NAME\tARCHES\tCPU-CORES\tMEM\tROOT-DISK\tCOST\n")
+	for _, itype := range info.InstanceTypes {
+		name := itype.Name
+		if itype.Selected {
+			name += "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\n",
+			name,
+			strings.Join(itype.Arches, ","),
+			itype.CpuCores,
+			formatMegabytes(itype.Mem),
+			formatMegabytes(itype.RootDisk),
+			formatCost(itype.Cost),
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+// formatMegabytes formats a size in megabytes the way it is written in
+// constraints, or as "-" if it is unknown.
+func formatMegabytes(mb uint64) string {
+	if mb == 0 {
+		return "-"
+	}
+	if mb%1024 == 0 {
+		return fmt.Sprintf("%dG", mb/1024)
+	}
+	return fmt.Sprintf("%dM", mb)
+}
+
+// formatCost formats an instance type's cost, or "-" if it is unknown.
+func formatCost(cost uint64) string {
+	if cost == 0 {
+		return "-"
+	}
+	return fmt.Sprint(cost)
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/testing"
+)
+
+type ListInstanceTypesSuite struct {
+	testing.FakeJujuHomeSuite
+	env *instanceTypesEnviron
+}
+
+var _ = gc.Suite(&ListInstanceTypesSuite{})
+
+type instanceTypesEnviron struct {
+	environs.Environ
+	cfg    *config.Config
+	itypes []instances.InstanceType
+	err    error
+}
+
+func (e *instanceTypesEnviron) Config() *config.Config {
+	return e.cfg
+}
+
+func (e *instanceTypesEnviron) InstanceTypes() ([]instances.InstanceType, error) {
+	return e.itypes, e.err
+}
+
+func (e *instanceTypesEnviron) Region() (simplestreams.CloudSpec, error) {
+	return simplestreams.CloudSpec{Region: "test-region"}, nil
+}
+
+func (s *ListInstanceTypesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.env = &instanceTypesEnviron{
+		cfg: testing.EnvironConfig(c),
+		itypes: []instances.InstanceType{{
+			Name:     "small",
+			Arches:   []string{"amd64"},
+			CpuCores: 1,
+			Mem:      512,
+			Cost:     10,
+		}, {
+			Name:     "medium",
+			Arches:   []string{"amd64", "i386"},
+			CpuCores: 2,
+			Mem:      2048,
+			RootDisk: 8192,
+			Cost:     20,
+		}, {
+			Name:     "large",
+			Arches:   []string{"amd64"},
+			CpuCores: 4,
+			Mem:      8192,
+			Cost:     40,
+		}},
+	}
+	s.PatchValue(&openEnvironForInstanceTypes, func(*cmd.Context, string) (environs.Environ, error) {
+		return s.env, nil
+	})
+}
+
+func (s *ListInstanceTypesSuite) TestListTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newListInstanceTypesCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"NAME    ARCHES      CPU-CORES  MEM   ROOT-DISK  COST\n"+
+		"small   amd64       1          512M  -          10\n"+
+		"medium  amd64,i386  2          2G    8G         20\n"+
+		"large   amd64       4          8G    -          40\n",
+	)
+}
+
+func (s *ListInstanceTypesSuite) TestListConstraints(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newListInstanceTypesCommand(), "--constraints", "cpu-cores=2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"NAME     ARCHES      CPU-CORES  MEM  ROOT-DISK  COST\n"+
+		"medium*  amd64,i386  2          2G   8G         20\n"+
+		"large    amd64       4          8G   -          40\n",
+	)
+}
+
+func (s *ListInstanceTypesSuite) TestListYAML(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newListInstanceTypesCommand(), "--constraints", "mem=4G", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"region: test-region\n"+
+		"instance-types:\n"+
+		"- name: large\n"+
+		"  arches:\n"+
+		"  - amd64\n"+
+		"  cpu-cores: 4\n"+
+		"  mem: 8192\n"+
+		"  cost: 40\n"+
+		"  selected: true\n",
+	)
+}
+
+func (s *ListInstanceTypesSuite) TestListNoMatches(c *gc.C) {
+	_, err := testing.RunCommand(c, newListInstanceTypesCommand(), "--constraints", "cpu-cores=8")
+	c.Assert(err, gc.ErrorMatches, `no instance types in test-region matching constraints "cpu-cores=8"`)
+}
+
+func (s *ListInstanceTypesSuite) TestListError(c *gc.C) {
+	s.env.err = errors.New("boom")
+	_, err := testing.RunCommand(c, newListInstanceTypesCommand())
+	c.Assert(err, gc.ErrorMatches, "cannot list instance types: boom")
+}
+
+func (s *ListInstanceTypesSuite) TestNotSupported(c *gc.C) {
+	s.PatchValue(&openEnvironForInstanceTypes, func(*cmd.Context, string) (environs.Environ, error) {
+		return &healthCheckEnviron{cfg: testing.EnvironConfig(c)}, nil
+	})
+	_, err := testing.RunCommand(c, newListInstanceTypesCommand())
+	c.Assert(err, gc.ErrorMatches, `listing instance types in "dummy" environments not supported`)
+}
@@ -6,8 +6,10 @@ package commands
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/utils/featureflag"
 
 	jujucmd "github.com/juju/juju/cmd"
@@ -26,6 +28,7 @@ import (
 	"github.com/juju/juju/cmd/juju/subnet"
 	"github.com/juju/juju/cmd/juju/system"
 	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/juju/osenv"
@@ -61,6 +64,10 @@ func Main(args []string) {
 		fmt.Fprintf(os.Stderr, "error: %s\n", err)
 		os.Exit(2)
 	}
+	if err = initMetadataCache(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(2)
+	}
 	for i := range x {
 		x[i] ^= 255
 	}
@@ -72,6 +79,24 @@ func Main(args []string) {
 	os.Exit(cmd.Main(jcmd, ctx, args[1:]))
 }
 
+// initMetadataCache enables caching of the simplestreams metadata
+// fetched by the client, so that it is not downloaded again by every
+// command that needs it.
+func initMetadataCache() error {
+	ttl := simplestreams.DefaultMetadataCacheTTL
+	if value := os.Getenv(osenv.JujuMetadataCacheTTLEnvKey); value != "" {
+		var err error
+		if ttl, err = time.ParseDuration(value); err != nil {
+			return errors.Annotatef(err, "invalid %s", osenv.JujuMetadataCacheTTLEnvKey)
+		}
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	simplestreams.SetMetadataCache(simplestreams.NewMetadataCache(osenv.JujuHomePath("metadata-cache"), ttl))
+	return nil
+}
+
 func NewJujuCommand(ctx *cmd.Context) cmd.Command {
 	jcmd := jujucmd.NewSuperCommand(cmd.SuperCommandParams{
 		Name:                "juju",
@@ -131,6 +156,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(newEndpointCommand())
 	r.Register(newAPIInfoCommand())
 	r.Register(status.NewStatusHistoryCommand())
+	r.Register(status.NewListProcessesCommand())
+	r.Register(newDistributionCommand())
+	r.Register(newCheckEnvironmentCommand())
+	r.Register(newListInstanceTypesCommand())
 
 	// Error resolution and debugging commands.
 	r.Register(newRunCommand())
@@ -139,6 +168,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(newResolvedCommand())
 	r.Register(newDebugLogCommand())
 	r.Register(newDebugHooksCommand())
+	r.Register(newProblemReportsCommand())
+	r.Register(newAuditCommand())
 
 	// Configuration commands.
 	r.Register(newInitCommand())
@@ -147,7 +178,11 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterDeprecated(common.NewSetConstraintsCommand(),
 		twoDotOhDeprecation("environment set-constraints or service set-constraints"))
 	r.Register(newExposeCommand())
+	r.Register(newFirewallRulesCommand())
+	r.Register(newSetFirewallRuleCommand())
 	r.Register(newSyncToolsCommand())
+	r.Register(newCreateOfflineBundleCommand())
+	r.Register(newImportOfflineBundleCommand())
 	r.Register(newUnexposeCommand())
 	r.Register(newUpgradeJujuCommand())
 	r.Register(newUpgradeCharmCommand())
@@ -158,6 +193,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Charm tool commands.
 	r.Register(newHelpToolCommand())
 
+	// Shell completion commands.
+	r.Register(common.NewCompletionCommand())
+	r.RegisterDeprecated(common.NewCompleteEntitiesCommand(), hiddenCommand{})
+
 	// Manage backups.
 	r.Register(backups.NewSuperCommand())
 
@@ -220,6 +259,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 		// Add top level aliases of the same name as the subcommands.
 		r.RegisterSuperAlias("environments", "system", "environments", nil)
+		r.RegisterSuperAlias("list-environments", "system", "environments", nil)
 		r.RegisterSuperAlias("login", "system", "login", nil)
 		r.RegisterSuperAlias("create-environment", "system", "create-environment", nil)
 		r.RegisterSuperAlias("create-env", "system", "create-env", nil)
@@ -253,6 +293,22 @@ func (v *versionDeprecation) Obsolete() bool {
 	return version.Current.Compare(v.obsolete) > 0
 }
 
+// hiddenCommand is a cmd.DeprecationCheck for commands that are run by
+// other tools rather than by users. Such commands are dispatched as
+// usual but, being reported as deprecated, are left out of
+// "juju help commands".
+type hiddenCommand struct{}
+
+// Deprecated implements cmd.DeprecationCheck.
+func (hiddenCommand) Deprecated() (bool, string) {
+	return true, ""
+}
+
+// Obsolete implements cmd.DeprecationCheck.
+func (hiddenCommand) Obsolete() bool {
+	return false
+}
+
 func twoDotOhDeprecation(replacement string) cmd.DeprecationCheck {
 	return &versionDeprecation{
 		replacement: replacement,
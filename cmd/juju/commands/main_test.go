@@ -194,6 +194,14 @@ func (s *MainSuite) TestActualRunJujuArgOrder(c *gc.C) {
 	}
 }
 
+func (s *MainSuite) TestHiddenCommand(c *gc.C) {
+	// complete-entities is not listed by "juju help commands", but
+	// is still run. Being registered as deprecated, it may warn
+	// about it first.
+	out := badrun(c, 1, "complete-entities", "discombobulators")
+	c.Assert(out, gc.Matches, `(?s).*ERROR unknown entity kind "discombobulators"\n`)
+}
+
 var commandNames = []string{
 	"action",
 	"add-machine",
@@ -201,12 +209,16 @@ var commandNames = []string{
 	"add-unit",
 	"api-endpoints",
 	"api-info",
+	"audit",
 	"authorised-keys", // alias for authorized-keys
 	"authorized-keys",
 	"backups",
 	"block",
 	"bootstrap",
 	"cached-images",
+	"check-environment",
+	"completion",
+	"create-offline-bundle",
 	"debug-hooks",
 	"debug-log",
 	"deploy",
@@ -215,10 +227,12 @@ var commandNames = []string{
 	"destroy-relation",
 	"destroy-service",
 	"destroy-unit",
+	"distribution",
 	"ensure-availability",
 	"env", // alias for switch
 	"environment",
 	"expose",
+	"firewall-rules",
 	"generate-config", // alias for init
 	"get",
 	"get-constraints",
@@ -226,8 +240,12 @@ var commandNames = []string{
 	"get-environment",
 	"help",
 	"help-tool",
+	"import-offline-bundle",
 	"init",
+	"list-instance-types",
+	"list-processes",
 	"machine",
+	"problem-reports",
 	"publish",
 	"remove-machine",  // alias for destroy-machine
 	"remove-relation", // alias for destroy-relation
@@ -242,6 +260,7 @@ var commandNames = []string{
 	"set-constraints",
 	"set-env", // alias for set-environment
 	"set-environment",
+	"set-firewall-rule",
 	"space",
 	"ssh",
 	"stat", // alias for status
@@ -508,3 +527,9 @@ func (s *MainSuite) TestObsoleteRegistration(c *gc.C) {
 	c.Logf("Registered obsolete commands: %s", intersection.Values())
 	c.Assert(intersection.IsEmpty(), gc.Equals, true)
 }
+
+func (s *MainSuite) TestInitMetadataCacheInvalidTTL(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuMetadataCacheTTLEnvKey, "soon")
+	err := initMetadataCache()
+	c.Assert(err, gc.ErrorMatches, `invalid JUJU_METADATA_CACHE_TTL: time: invalid duration .*soon.*`)
+}
@@ -0,0 +1,493 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"github.com/juju/utils/fs"
+	"github.com/juju/utils/series"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/charmrepo.v1"
+	"gopkg.in/macaroon-bakery.v1/httpbakery"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/imagemetadata"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/offline"
+	"github.com/juju/juju/environs/sync"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/version"
+)
+
+func newCreateOfflineBundleCommand() cmd.Command {
+	return &createOfflineBundleCommand{}
+}
+
+// createOfflineBundleCommand writes an archive holding the tools,
+// image metadata and charms needed to use Juju without Internet access.
+type createOfflineBundleCommand struct {
+	cmd.CommandBase
+	filename     string
+	series       []string
+	arches       []string
+	versionStr   string
+	majorVersion int
+	minorVersion int
+	stream       string
+	source       string
+	imagesDir    string
+	charms       []*charm.Reference
+	keyFile      string
+	passphrase   string
+}
+
+const createOfflineBundleDoc = `
+create-offline-bundle writes a single archive holding everything needed to
+bootstrap and operate an environment with no access to the Internet:
+
+  - agent tools for the selected series and architectures, with their
+    simplestreams metadata, from the official tools store or --source;
+  - image metadata, as generated by "juju metadata generate-image -d <dir>",
+    from the directory given with --image-metadata;
+  - the charms given with --charms, downloaded from the charm store.
+
+The tools and image metadata may be signed with the armored private key in
+the file given with --signing-key.
+
+Copy the archive to a machine with access to the cloud, and bootstrap with:
+
+    juju bootstrap --metadata-source bundle.tar.gz
+
+For an environment that is already running, use import-offline-bundle.
+
+Examples:
+
+    juju create-offline-bundle --series trusty --arch amd64 \
+        --charms cs:trusty/mysql,cs:trusty/wordpress bundle.tar.gz
+
+See Also:
+   juju help import-offline-bundle
+   juju help bootstrap
+   juju help sync-tools
+`
+
+func (c *createOfflineBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create-offline-bundle",
+		Args:    "<filename>",
+		Purpose: "create an archive of tools, image metadata and charms for offline use",
+		Doc:     createOfflineBundleDoc,
+	}
+}
+
+func (c *createOfflineBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(newSeriesValue(nil, &c.series), "series", "comma-separated series of the tools to include (default all)")
+	f.Var((*cmd.StringsValue)(&c.arches), "arch", "comma-separated architectures of the tools to include (default all)")
+	f.StringVar(&c.versionStr, "version", "", "include a specific major[.minor] version of the tools")
+	f.StringVar(&c.stream, "stream", "", "simplestreams stream from which to take the tools")
+	f.StringVar(&c.source, "source", "", "local source directory for the tools")
+	f.StringVar(&c.imagesDir, "image-metadata", "", "local directory holding image metadata to include")
+	f.Var(charmsValue{&c.charms}, "charms", "comma-separated charm store URLs of the charms to include")
+	f.StringVar(&c.keyFile, "signing-key", "", "file containing the armored private key used to sign metadata")
+	f.StringVar(&c.passphrase, "passphrase", "", "passphrase used to decrypt the private signing key")
+}
+
+func (c *createOfflineBundleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no bundle filename specified")
+	}
+	c.filename, args = args[0], args[1:]
+	if c.versionStr != "" {
+		var err error
+		if c.majorVersion, c.minorVersion, err = version.ParseMajorMinor(c.versionStr); err != nil {
+			return err
+		}
+	}
+	for _, ref := range c.charms {
+		if ref.Schema != "cs" {
+			return errors.Errorf("charm %q: only charm store charms may be included", ref)
+		}
+		if ref.Series == "" {
+			return errors.Errorf("charm %q: series must be specified", ref)
+		}
+	}
+	if c.passphrase != "" && c.keyFile == "" {
+		return errors.New("--passphrase requires --signing-key")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *createOfflineBundleCommand) Run(ctx *cmd.Context) error {
+	loggo.RegisterWriter("offlinebundle", cmd.NewCommandLogWriter("juju.environs.sync", ctx.Stdout, ctx.Stderr), loggo.INFO)
+	defer loggo.RemoveWriter("offlinebundle")
+
+	dir, err := ioutil.TempDir("", "juju-offline-bundle")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := c.addTools(dir); err != nil {
+		return errors.Annotate(err, "cannot add tools to offline bundle")
+	}
+	if c.imagesDir != "" {
+		src := filepath.Join(ctx.AbsPath(c.imagesDir), offline.ImagesDir)
+		if err := fs.Copy(src, filepath.Join(dir, offline.ImagesDir)); err != nil {
+			return errors.Annotate(err, "cannot add image metadata to offline bundle")
+		}
+	}
+	if err := c.addCharms(ctx, dir); err != nil {
+		return errors.Trace(err)
+	}
+	if c.keyFile != "" {
+		key, err := ioutil.ReadFile(ctx.AbsPath(c.keyFile))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := offline.SignMetadata(dir, string(key), c.passphrase); err != nil {
+			return errors.Annotate(err, "cannot sign offline bundle metadata")
+		}
+	}
+
+	if err := writeBundle(ctx.AbsPath(c.filename), dir); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("offline bundle written to %s", c.filename)
+	return nil
+}
+
+// writeBundle packs the bundle in dir into the archive at path. The
+// archive is written to a temporary file alongside path and then
+// renamed, so that an interrupted export never leaves a truncated
+// bundle behind.
+func writeBundle(path, dir string) (err error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".juju-offline-bundle-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+	err = offline.Pack(f, dir)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(utils.ReplaceFile(f.Name(), path))
+}
+
+// addTools copies the selected tools, and their metadata, into the
+// bundle in dir.
+func (c *createOfflineBundleCommand) addTools(dir string) error {
+	stor, err := filestorage.NewFileStorageWriter(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return syncTools(&sync.SyncContext{
+		MajorVersion:        c.majorVersion,
+		MinorVersion:        c.minorVersion,
+		Stream:              c.stream,
+		Source:              c.source,
+		Series:              c.series,
+		Arches:              c.arches,
+		TargetToolsFinder:   sync.StorageToolsFinder{Storage: stor},
+		TargetToolsUploader: sync.StorageToolsUploader{Storage: stor, WriteMetadata: true},
+	})
+}
+
+// addCharms downloads the selected charms into the bundle in dir.
+func (c *createOfflineBundleCommand) addCharms(ctx *cmd.Context, dir string) error {
+	if len(c.charms) == 0 {
+		return nil
+	}
+	csParams := newCharmStoreClient(httpbakery.NewHTTPClient()).params
+	for _, ref := range c.charms {
+		ch, err := getCharmArchive(ref, csParams)
+		if err != nil {
+			return errors.Annotatef(err, "cannot get charm %q", ref)
+		}
+		if err := offline.AddCharm(dir, ref.Series, ch); err != nil {
+			return errors.Trace(err)
+		}
+		ctx.Infof("added charm %s", ref)
+	}
+	return nil
+}
+
+// getCharmArchive downloads the charm with the given reference from the
+// charm store. It is a variable so it can be changed for testing.
+var getCharmArchive = func(ref *charm.Reference, csParams charmrepo.NewCharmStoreParams) (*charm.CharmArchive, error) {
+	repo, err := charmrepo.InferRepository(ref, csParams, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	curl, err := repo.Resolve(ref)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ch, err := repo.Get(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	archive, ok := ch.(*charm.CharmArchive)
+	if !ok {
+		return nil, errors.Errorf("unexpected charm type %T", ch)
+	}
+	return archive, nil
+}
+
+// charmsValue implements gnuflag.Value for a comma-separated list of
+// charm references.
+type charmsValue struct {
+	refs *[]*charm.Reference
+}
+
+func (v charmsValue) Set(s string) error {
+	var refs []*charm.Reference
+	for _, str := range strings.Split(s, ",") {
+		str = strings.TrimSpace(str)
+		if str == "" {
+			continue
+		}
+		ref, err := charm.ParseReference(str)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		refs = append(refs, ref)
+	}
+	*v.refs = refs
+	return nil
+}
+
+func (v charmsValue) String() string {
+	strs := make([]string, len(*v.refs))
+	for i, ref := range *v.refs {
+		strs[i] = ref.String()
+	}
+	return strings.Join(strs, ",")
+}
+
+func newImportOfflineBundleCommand() cmd.Command {
+	return envcmd.Wrap(&importOfflineBundleCommand{})
+}
+
+// importOfflineBundleCommand imports the contents of an offline bundle
+// into a running environment.
+type importOfflineBundleCommand struct {
+	envcmd.EnvCommandBase
+	path     string
+	stream   string
+	repoPath string
+}
+
+const importOfflineBundleDoc = `
+import-offline-bundle imports the contents of an archive written by
+create-offline-bundle into a running environment. The bundle's agent
+tools, image metadata and charms are all uploaded to the environment's
+state server, so that machines can be provisioned and the charms'
+units run without access to the Internet.
+
+The charms are also copied into the local charm repository given with
+--repository (by default $JUJU_REPOSITORY), if any, from which they may be
+deployed with
+
+    juju deploy --repository <repository> local:<series>/<charm>
+
+See Also:
+   juju help create-offline-bundle
+   juju help deploy
+`
+
+func (c *importOfflineBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "import-offline-bundle",
+		Args:    "<filename>",
+		Purpose: "import tools, image metadata and charms from an offline bundle",
+		Doc:     importOfflineBundleDoc,
+	}
+}
+
+func (c *importOfflineBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.stream, "stream", "", "simplestreams stream of the bundle's tools")
+	f.StringVar(&c.repoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository into which to also copy charms")
+}
+
+func (c *importOfflineBundleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no bundle filename specified")
+	}
+	c.path, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+// importOfflineBundleAPI defines the API methods used by the
+// import-offline-bundle command.
+type importOfflineBundleAPI interface {
+	syncToolsAPI
+	AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, error)
+	SaveImageMetadata(metadata []params.CloudImageMetadata) ([]params.ErrorResult, error)
+}
+
+// importOfflineBundleClient implements importOfflineBundleAPI.
+type importOfflineBundleClient struct {
+	*api.Client
+	images *imagemetadata.Client
+}
+
+// SaveImageMetadata is part of the importOfflineBundleAPI interface.
+func (c importOfflineBundleClient) SaveImageMetadata(metadata []params.CloudImageMetadata) ([]params.ErrorResult, error) {
+	return c.images.Save(metadata)
+}
+
+// getImportOfflineBundleAPI returns the API used to import the
+// bundle. It is a variable so it can be changed for testing.
+var getImportOfflineBundleAPI = func(c *importOfflineBundleCommand) (importOfflineBundleAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return importOfflineBundleClient{root.Client(), imagemetadata.NewClient(root)}, nil
+}
+
+func (c *importOfflineBundleCommand) Run(ctx *cmd.Context) error {
+	loggo.RegisterWriter("offlinebundle", cmd.NewCommandLogWriter("juju.environs.sync", ctx.Stdout, ctx.Stderr), loggo.INFO)
+	defer loggo.RemoveWriter("offlinebundle")
+
+	dir, err := offline.UnpackFile(ctx.AbsPath(c.path))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := getImportOfflineBundleAPI(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, offline.ToolsDir)); err == nil {
+		adapter := syncToolsAPIAdapter{client}
+		err = syncTools(&sync.SyncContext{
+			AllVersions:         true,
+			Stream:              c.stream,
+			Source:              dir,
+			TargetToolsFinder:   adapter,
+			TargetToolsUploader: adapter,
+		})
+		if err != nil {
+			return block.ProcessBlockedError(errors.Annotate(err, "cannot upload tools"), block.BlockChange)
+		}
+	}
+	if err := importBundleImageMetadata(ctx, client, dir); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if err := c.importBundleCharms(ctx, client, dir); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
+
+// importBundleImageMetadata saves the image metadata held in the
+// bundle in dir as custom image metadata in the environment.
+func importBundleImageMetadata(ctx *cmd.Context, client importOfflineBundleAPI, dir string) error {
+	published, err := offline.ImageMetadata(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(published) == 0 {
+		return nil
+	}
+	metadata := make([]params.CloudImageMetadata, len(published))
+	for i, p := range published {
+		s, err := series.VersionSeries(p.Version)
+		if err != nil {
+			return errors.Annotatef(err, "cannot determine series for image %q", p.Id)
+		}
+		metadata[i] = params.CloudImageMetadata{
+			Source:          "custom",
+			ImageId:         p.Id,
+			Stream:          p.Stream,
+			Region:          p.RegionName,
+			Series:          s,
+			Arch:            p.Arch,
+			VirtType:        p.VirtType,
+			RootStorageType: p.Storage,
+		}
+	}
+	results, err := client.SaveImageMetadata(metadata)
+	if err != nil {
+		return errors.Annotate(err, "cannot save image metadata")
+	}
+	for _, result := range results {
+		if result.Error != nil {
+			return errors.Annotate(result.Error, "cannot save image metadata")
+		}
+	}
+	ctx.Infof("added %d image metadata entries", len(metadata))
+	return nil
+}
+
+// importBundleCharms uploads the charms held in the bundle in dir to
+// the environment, and copies them into the local charm repository if
+// one was specified.
+func (c *importOfflineBundleCommand) importBundleCharms(ctx *cmd.Context, client importOfflineBundleAPI, dir string) error {
+	charms, err := offline.Charms(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, curl := range charms {
+		path := filepath.Join(dir, offline.CharmsDir, curl.Series, curl.Name+".charm")
+		ch, err := charm.ReadCharmArchive(path)
+		if err != nil {
+			return errors.Annotatef(err, "cannot read charm %s", curl)
+		}
+		stateCurl, err := client.AddLocalCharm(curl, ch)
+		if err != nil {
+			return errors.Annotatef(err, "cannot add charm %s", curl)
+		}
+		ctx.Infof("added charm %s", stateCurl)
+		if c.repoPath == "" {
+			continue
+		}
+		if err := copyBundleCharm(dir, ctx.AbsPath(c.repoPath), curl); err != nil {
+			return errors.Trace(err)
+		}
+		ctx.Infof("copied charm %s to %s", curl, c.repoPath)
+	}
+	return nil
+}
+
+// copyBundleCharm copies the charm with the given URL from the bundle
+// in dir into the local charm repository at repoPath.
+func copyBundleCharm(dir, repoPath string, curl *charm.URL) error {
+	name := curl.Name + ".charm"
+	seriesDir := filepath.Join(repoPath, curl.Series)
+	if err := os.MkdirAll(seriesDir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	src := filepath.Join(dir, offline.CharmsDir, curl.Series, name)
+	if err := utils.CopyFile(filepath.Join(seriesDir, name), src); err != nil {
+		return errors.Annotatef(err, "cannot copy charm %s", curl)
+	}
+	return nil
+}
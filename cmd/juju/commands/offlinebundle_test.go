@@ -0,0 +1,253 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/charmrepo.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/offline"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/sync"
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type offlineBundleSuite struct {
+	coretesting.BaseSuite
+	fakeAPI *fakeImportOfflineBundleAPI
+}
+
+var _ = gc.Suite(&offlineBundleSuite{})
+
+func (s *offlineBundleSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.fakeAPI = &fakeImportOfflineBundleAPI{}
+	s.PatchValue(&getImportOfflineBundleAPI, func(*importOfflineBundleCommand) (importOfflineBundleAPI, error) {
+		return s.fakeAPI, nil
+	})
+}
+
+func (s *offlineBundleSuite) TestCreateInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  "no bundle filename specified",
+	}, {
+		args: []string{"bundle.tar.gz", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"--version", "foo", "bundle.tar.gz"},
+		err:  `invalid major version number foo: .*`,
+	}, {
+		args: []string{"--charms", "local:trusty/dummy", "bundle.tar.gz"},
+		err:  `charm "local:trusty/dummy": only charm store charms may be included`,
+	}, {
+		args: []string{"--charms", "cs:dummy", "bundle.tar.gz"},
+		err:  `charm "cs:dummy": series must be specified`,
+	}, {
+		args: []string{"--passphrase", "secret", "bundle.tar.gz"},
+		err:  "--passphrase requires --signing-key",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := coretesting.RunCommand(c, newCreateOfflineBundleCommand(), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *offlineBundleSuite) TestCreate(c *gc.C) {
+	s.PatchValue(&syncTools, func(sctx *sync.SyncContext) error {
+		c.Check(sctx.Series, jc.DeepEquals, []string{"trusty"})
+		c.Check(sctx.Arches, jc.DeepEquals, []string{"amd64", "i386"})
+		c.Check(sctx.MajorVersion, gc.Equals, 1)
+		c.Check(sctx.MinorVersion, gc.Equals, 25)
+		c.Assert(sctx.TargetToolsUploader, gc.FitsTypeOf, sync.StorageToolsUploader{})
+		uploader := sctx.TargetToolsUploader.(sync.StorageToolsUploader)
+		c.Check(uploader.WriteMetadata, jc.IsTrue)
+		return uploader.Storage.Put("tools/streams/v1/index.json", strings.NewReader("{}"), 2)
+	})
+	s.PatchValue(&getCharmArchive, func(ref *charm.Reference, _ charmrepo.NewCharmStoreParams) (*charm.CharmArchive, error) {
+		c.Check(ref.String(), gc.Equals, "cs:trusty/dummy")
+		return testcharms.Repo.CharmArchive(c.MkDir(), "dummy"), nil
+	})
+	imagesDir := c.MkDir()
+	err := os.MkdirAll(filepath.Join(imagesDir, "images", "streams", "v1"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(imagesDir, "images", "streams", "v1", "index.json"), []byte("{}"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	path := filepath.Join(c.MkDir(), "bundle.tar.gz")
+	_, err = coretesting.RunCommand(c, newCreateOfflineBundleCommand(),
+		"--series", "trusty", "--arch", "amd64,i386", "--version", "1.25",
+		"--image-metadata", imagesDir, "--charms", "cs:trusty/dummy", path,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	dir, err := offline.UnpackFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{
+		"tools/streams/v1/index.json",
+		"images/streams/v1/index.json",
+		"charms/trusty/dummy.charm",
+	} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *offlineBundleSuite) TestCreateReplacesExisting(c *gc.C) {
+	s.PatchValue(&syncTools, func(sctx *sync.SyncContext) error {
+		uploader := sctx.TargetToolsUploader.(sync.StorageToolsUploader)
+		return uploader.Storage.Put("tools/streams/v1/index.json", strings.NewReader("{}"), 2)
+	})
+	dir := c.MkDir()
+	path := filepath.Join(dir, "bundle.tar.gz")
+	err := ioutil.WriteFile(path, []byte("old bundle"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = coretesting.RunCommand(c, newCreateOfflineBundleCommand(), path)
+	c.Assert(err, jc.ErrorIsNil)
+	unpacked, err := offline.UnpackFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer os.RemoveAll(unpacked)
+
+	// Only the bundle remains; the temporary file was renamed.
+	entries, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].Name(), gc.Equals, "bundle.tar.gz")
+}
+
+func (s *offlineBundleSuite) TestCreateFailureKeepsExisting(c *gc.C) {
+	s.PatchValue(&syncTools, func(sctx *sync.SyncContext) error {
+		return nil
+	})
+	dir := c.MkDir()
+	path := filepath.Join(dir, "bundle.tar.gz")
+	err := ioutil.WriteFile(path, []byte("old bundle"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Nothing was added, so packing the bundle fails.
+	_, err = coretesting.RunCommand(c, newCreateOfflineBundleCommand(), path)
+	c.Assert(err, gc.ErrorMatches, `offline bundle directory ".*" is empty`)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "old bundle")
+	entries, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+}
+
+func (s *offlineBundleSuite) makeBundle(c *gc.C) string {
+	dir := c.MkDir()
+	err := os.MkdirAll(filepath.Join(dir, offline.ToolsDir), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = offline.AddCharm(dir, "trusty", testcharms.Repo.CharmArchive(c.MkDir(), "dummy"))
+	c.Assert(err, jc.ErrorIsNil)
+	stor, err := filestorage.NewFileStorageWriter(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	err = imagemetadata.MergeAndWriteMetadata("trusty", []*imagemetadata.ImageMetadata{{
+		Id:   "1234",
+		Arch: "amd64",
+	}}, &simplestreams.CloudSpec{Region: "region", Endpoint: "endpoint"}, stor)
+	c.Assert(err, jc.ErrorIsNil)
+	path := filepath.Join(c.MkDir(), "bundle.tar.gz")
+	f, err := os.Create(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	err = offline.Pack(f, dir)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *offlineBundleSuite) TestImport(c *gc.C) {
+	path := s.makeBundle(c)
+	called := false
+	s.PatchValue(&syncTools, func(sctx *sync.SyncContext) error {
+		c.Check(sctx.AllVersions, jc.IsTrue)
+		c.Check(sctx.Stream, gc.Equals, "proposed")
+		_, err := os.Stat(filepath.Join(sctx.Source, offline.ToolsDir))
+		c.Check(err, jc.ErrorIsNil)
+		c.Assert(sctx.TargetToolsUploader, gc.FitsTypeOf, syncToolsAPIAdapter{})
+		uploader := sctx.TargetToolsUploader.(syncToolsAPIAdapter)
+		c.Check(uploader.syncToolsAPI, gc.Equals, s.fakeAPI)
+		called = true
+		return nil
+	})
+	repoPath := c.MkDir()
+	ctx, err := coretesting.RunCommand(c, newImportOfflineBundleCommand(),
+		"-e", "test-target", "--stream", "proposed", "--repository", repoPath, path,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(called, jc.IsTrue)
+
+	c.Assert(s.fakeAPI.charms, gc.HasLen, 1)
+	c.Check(s.fakeAPI.charms[0].String(), gc.Matches, "local:trusty/dummy-[0-9]+")
+	c.Assert(s.fakeAPI.metadata, gc.HasLen, 1)
+	metadata := s.fakeAPI.metadata[0]
+	c.Check(metadata.Source, gc.Equals, "custom")
+	c.Check(metadata.ImageId, gc.Equals, "1234")
+	c.Check(metadata.Region, gc.Equals, "region")
+	c.Check(metadata.Series, gc.Equals, "trusty")
+	c.Check(metadata.Arch, gc.Equals, "amd64")
+	c.Check(coretesting.Stderr(ctx), jc.Contains, "added charm local:trusty/dummy")
+	_, err = os.Stat(filepath.Join(repoPath, "trusty", "dummy.charm"))
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *offlineBundleSuite) TestImportNoRepository(c *gc.C) {
+	path := s.makeBundle(c)
+	s.PatchValue(&syncTools, func(*sync.SyncContext) error {
+		return nil
+	})
+	s.PatchEnvironment("JUJU_REPOSITORY", "")
+	_, err := coretesting.RunCommand(c, newImportOfflineBundleCommand(), "-e", "test-target", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeAPI.charms, gc.HasLen, 1)
+}
+
+func (s *offlineBundleSuite) TestImportImageMetadataError(c *gc.C) {
+	path := s.makeBundle(c)
+	s.PatchValue(&syncTools, func(*sync.SyncContext) error {
+		return nil
+	})
+	s.fakeAPI.saveErr = &params.Error{Message: "boom"}
+	_, err := coretesting.RunCommand(c, newImportOfflineBundleCommand(), "-e", "test-target", path)
+	c.Assert(err, gc.ErrorMatches, "cannot save image metadata: boom")
+	c.Assert(s.fakeAPI.charms, gc.HasLen, 0)
+}
+
+type fakeImportOfflineBundleAPI struct {
+	fakeSyncToolsAPI
+	charms   []*charm.URL
+	metadata []params.CloudImageMetadata
+	saveErr  *params.Error
+}
+
+func (f *fakeImportOfflineBundleAPI) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, error) {
+	f.charms = append(f.charms, curl)
+	return curl, nil
+}
+
+func (f *fakeImportOfflineBundleAPI) SaveImageMetadata(metadata []params.CloudImageMetadata) ([]params.ErrorResult, error) {
+	f.metadata = append(f.metadata, metadata...)
+	results := make([]params.ErrorResult, len(metadata))
+	for i := range results {
+		results[i].Error = f.saveErr
+	}
+	return results, nil
+}
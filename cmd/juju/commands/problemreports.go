@@ -0,0 +1,174 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
+)
+
+const problemReportsDoc = `
+Agents upload a problem report when one of their workers keeps failing
+shortly after being restarted, or when a charm hook fails. Each report
+records the failure, its error stack if known, the agent's most recent
+log lines and details of the failed worker's state.
+
+Without arguments, a summary of the environment's problem reports is
+shown, newest first. With the id of a report, the full report is shown.
+Only the newest 100 reports in the environment are kept.
+
+Examples:
+
+    juju problem-reports
+    juju problem-reports 42
+`
+
+func newProblemReportsCommand() cmd.Command {
+	return envcmd.Wrap(&problemReportsCommand{})
+}
+
+// problemReportsCommand lists the problem reports uploaded by agents.
+type problemReportsCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+	id  string
+}
+
+// problemReport holds a problem report for output.
+type problemReport struct {
+	Id      string            `yaml:"id" json:"id"`
+	Time    string            `yaml:"time" json:"time"`
+	Entity  string            `yaml:"entity" json:"entity"`
+	Kind    string            `yaml:"kind" json:"kind"`
+	Source  string            `yaml:"source" json:"source"`
+	Message string            `yaml:"message" json:"message"`
+	Stack   string            `yaml:"stack,omitempty" json:"stack,omitempty"`
+	State   map[string]string `yaml:"state,omitempty" json:"state,omitempty"`
+	Logs    []string          `yaml:"logs,omitempty" json:"logs,omitempty"`
+}
+
+func (c *problemReportsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "problem-reports",
+		Args:    "[<id>]",
+		Purpose: "show problem reports uploaded by agents",
+		Doc:     problemReportsDoc,
+	}
+}
+
+func (c *problemReportsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "default", map[string]cmd.Formatter{
+		"default": c.formatDefault,
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+	})
+}
+
+func (c *problemReportsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.id, args = args[0], args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// problemReportsAPI defines the methods on the client API that the
+// problem-reports command calls.
+type problemReportsAPI interface {
+	Close() error
+	ProblemReports() ([]params.ProblemReport, error)
+}
+
+var getProblemReportsAPI = func(c *problemReportsCommand) (problemReportsAPI, error) {
+	return c.NewAPIClient()
+}
+
+func (c *problemReportsCommand) Run(ctx *cmd.Context) error {
+	client, err := getProblemReportsAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	results, err := client.ProblemReports()
+	if err != nil {
+		return err
+	}
+	reports := make([]problemReport, 0, len(results))
+	for _, result := range results {
+		if c.id != "" && result.Id != c.id {
+			continue
+		}
+		entity := result.Tag
+		if tag, err := names.ParseTag(result.Tag); err == nil {
+			entity = tag.Id()
+		}
+		reports = append(reports, problemReport{
+			Id:      result.Id,
+			Time:    common.FormatTime(&result.Time, true),
+			Entity:  entity,
+			Kind:    result.Kind,
+			Source:  result.Source,
+			Message: result.Message,
+			Stack:   result.Stack,
+			State:   result.State,
+			Logs:    result.Logs,
+		})
+	}
+	if c.id == "" {
+		return c.out.Write(ctx, reports)
+	}
+	if len(reports) == 0 {
+		return errors.NotFoundf("problem report %q", c.id)
+	}
+	return c.out.Write(ctx, reports[0])
+}
+
+// formatDefault shows a summary table of problem reports, or a single
+// problem report in full as YAML.
+func (c *problemReportsCommand) formatDefault(value interface{}) ([]byte, error) {
+	if c.id != "" {
+		return cmd.FormatYaml(value)
+	}
+	reports, ok := value.([]problemReport)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", reports, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "ID\tTIME\tENTITY\tKIND\tSOURCE\tMESSAGE\n")
+	for _, report := range reports {
+		message := report.Message
+		if i := strings.Index(message, "\n"); i >= 0 {
+			message = message[:i]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			report.Id,
+			report.Time,
+			report.Entity,
+			report.Kind,
+			report.Source,
+			message,
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type ProblemReportsSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeProblemReportsAPI
+}
+
+var _ = gc.Suite(&ProblemReportsSuite{})
+
+type fakeProblemReportsAPI struct {
+	reports []params.ProblemReport
+}
+
+func (f *fakeProblemReportsAPI) Close() error {
+	return nil
+}
+
+func (f *fakeProblemReportsAPI) ProblemReports() ([]params.ProblemReport, error) {
+	return f.reports, nil
+}
+
+func (s *ProblemReportsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeProblemReportsAPI{
+		reports: []params.ProblemReport{{
+			Id:      "1",
+			Tag:     "unit-mysql-0",
+			Kind:    "hook-failed",
+			Source:  "install",
+			Message: `hook "install" failed`,
+			Logs:    []string{"installing"},
+			Time:    time.Date(2015, 10, 1, 12, 30, 0, 0, time.UTC),
+		}, {
+			Id:      "0",
+			Tag:     "machine-0",
+			Kind:    "worker-crash-loop",
+			Source:  "firewaller",
+			Message: "worker \"firewaller\" failed 3 times: boom\nmore",
+			State:   map[string]string{"failures": "3"},
+			Time:    time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC),
+		}},
+	}
+	s.PatchValue(&getProblemReportsAPI, func(*problemReportsCommand) (problemReportsAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *ProblemReportsSuite) TestInit(c *gc.C) {
+	_, err := testing.RunCommand(c, newProblemReportsCommand(), "1", "2")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["2"\]`)
+}
+
+func (s *ProblemReportsSuite) TestList(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newProblemReportsCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"ID  TIME                  ENTITY   KIND               SOURCE      MESSAGE\n"+
+		"1   2015-10-01 12:30:00Z  mysql/0  hook-failed        install     hook \"install\" failed\n"+
+		"0   2015-10-01 12:00:00Z  0        worker-crash-loop  firewaller  worker \"firewaller\" failed 3 times: boom\n",
+	)
+}
+
+func (s *ProblemReportsSuite) TestShow(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newProblemReportsCommand(), "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+id: "1"
+time: 2015-10-01 12:30:00Z
+entity: mysql/0
+kind: hook-failed
+source: install
+message: hook "install" failed
+logs:
+- installing
+`[1:])
+}
+
+func (s *ProblemReportsSuite) TestShowNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, newProblemReportsCommand(), "42")
+	c.Assert(err, gc.ErrorMatches, `problem report "42" not found`)
+}
+
+func (s *ProblemReportsSuite) TestListJSON(c *gc.C) {
+	s.api.reports = s.api.reports[:1]
+	ctx, err := testing.RunCommand(c, newProblemReportsCommand(), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `[{"id":"1","time":"2015-10-01 12:30:00Z","entity":"mysql/0","kind":"hook-failed","source":"install","message":"hook \"install\" failed","logs":["installing"]}]`+"\n")
+}
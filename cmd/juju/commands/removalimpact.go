@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// isTerminal reports whether r is an interactive terminal. Removal
+// is only confirmed when standard input is a terminal, so that
+// scripts are not stopped by the prompt. It is a variable so that
+// it can be patched in tests.
+var isTerminal = func(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && terminal.IsTerminal(int(f.Fd()))
+}
+
+// confirmRemoval describes the impact of a removal, as returned by
+// getImpact, and asks the user to confirm it. An error is returned
+// if the removal should not go ahead.
+func confirmRemoval(ctx *cmd.Context, what string, getImpact func() (params.RemovalImpact, error)) error {
+	impact, err := getImpact()
+	switch {
+	case params.IsCodeNotImplemented(err):
+		fmt.Fprintf(ctx.Stdout, "Cannot determine the impact of removing %s: not supported by the API server.\n", what)
+	case err != nil:
+		return errors.Trace(err)
+	default:
+		fmt.Fprintf(ctx.Stdout, "Removing %s will:\n", what)
+		writeRemovalImpact(ctx.Stdout, impact)
+	}
+	fmt.Fprint(ctx.Stdout, "Continue [y/N]? ")
+
+	scanner := bufio.NewScanner(ctx.Stdin)
+	scanner.Scan()
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return errors.Annotatef(err, "removal of %s aborted", what)
+	}
+	answer := strings.ToLower(scanner.Text())
+	if answer != "y" && answer != "yes" {
+		return errors.Errorf("removal of %s aborted", what)
+	}
+	return nil
+}
+
+// writeRemovalImpact writes a line to w for each kind of entity
+// affected by a removal.
+func writeRemovalImpact(w io.Writer, impact params.RemovalImpact) {
+	for _, item := range []struct {
+		action string
+		ids    []string
+	}{
+		{"remove units", impact.Units},
+		{"remove relations", impact.Relations},
+		{"destroy machines", impact.Machines},
+		{"leave machines without units", impact.EmptyMachines},
+		{"detach storage", impact.DetachedStorage},
+		{"destroy storage", impact.DestroyedStorage},
+	} {
+		if len(item.ids) > 0 {
+			fmt.Fprintf(w, "  - %s: %s\n", item.action, strings.Join(item.ids, ", "))
+		}
+	}
+	var names []string
+	for name := range impact.UnitErrors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  - fail: %v\n", impact.UnitErrors[name])
+	}
+}
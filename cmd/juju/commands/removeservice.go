@@ -8,7 +8,10 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -21,6 +24,7 @@ func newRemoveServiceCommand() cmd.Command {
 type removeServiceCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	assumeYes   bool
 }
 
 const removeServiceDoc = `
@@ -31,6 +35,11 @@ the service is hosted will also be destroyed, if possible.
 The machine will be destroyed if:
 - it is not a state server
 - it is not hosting any Juju managed containers
+
+When run from a terminal, the units, relations, machines and storage
+that will be affected are listed before anything is removed, and
+confirmation is requested. Use --yes to skip the confirmation. No
+confirmation is requested when standard input is not a terminal.
 `
 
 func (c *removeServiceCommand) Info() *cmd.Info {
@@ -43,6 +52,11 @@ func (c *removeServiceCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *removeServiceCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.assumeYes, "y", false, "Do not ask for confirmation")
+	f.BoolVar(&c.assumeYes, "yes", false, "")
+}
+
 func (c *removeServiceCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("no service specified")
@@ -54,11 +68,21 @@ func (c *removeServiceCommand) Init(args []string) error {
 	return cmd.CheckEmpty(args)
 }
 
-func (c *removeServiceCommand) Run(_ *cmd.Context) error {
-	client, err := c.NewAPIClient()
+func (c *removeServiceCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
 	if err != nil {
 		return err
 	}
-	defer client.Close()
+	defer root.Close()
+	if !c.assumeYes && isTerminal(ctx.Stdin) {
+		what := fmt.Sprintf("service %q", c.ServiceName)
+		err := confirmRemoval(ctx, what, func() (params.RemovalImpact, error) {
+			return apiservice.NewClient(root).ServiceDestroyImpact(c.ServiceName)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	client := root.Client()
 	return block.ProcessBlockedError(client.ServiceDestroy(c.ServiceName), block.BlockRemove)
 }
@@ -4,6 +4,10 @@
 package commands
 
 import (
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -32,6 +36,16 @@ func runRemoveService(c *gc.C, args ...string) error {
 	return err
 }
 
+func runRemoveServiceWithInput(c *gc.C, input string, args ...string) (*cmd.Context, error) {
+	com := newRemoveServiceCommand()
+	if err := testing.InitCommand(com, args); err != nil {
+		return nil, err
+	}
+	ctx := testing.Context(c)
+	ctx.Stdin = strings.NewReader(input)
+	return ctx, com.Run(ctx)
+}
+
 func (s *RemoveServiceSuite) setupTestService(c *gc.C) {
 	// Destroy a service that exists.
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "riak")
@@ -41,7 +55,40 @@ func (s *RemoveServiceSuite) setupTestService(c *gc.C) {
 
 func (s *RemoveServiceSuite) TestSuccess(c *gc.C) {
 	s.setupTestService(c)
-	err := runRemoveService(c, "riak")
+	err := runRemoveService(c, "riak", "--yes")
+	c.Assert(err, jc.ErrorIsNil)
+	riak, err := s.State.Service("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(riak.Life(), gc.Equals, state.Dying)
+}
+
+func (s *RemoveServiceSuite) TestNoConfirmationWithoutTerminal(c *gc.C) {
+	s.setupTestService(c)
+	ctx, err := runRemoveServiceWithInput(c, "", "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	riak, err := s.State.Service("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(riak.Life(), gc.Equals, state.Dying)
+}
+
+func (s *RemoveServiceSuite) TestConfirmation(c *gc.C) {
+	s.PatchValue(&isTerminal, func(io.Reader) bool { return true })
+	s.setupTestService(c)
+	for _, answer := range []string{"", "n", "no"} {
+		ctx, err := runRemoveServiceWithInput(c, answer, "riak")
+		c.Assert(err, gc.ErrorMatches, `removal of service "riak" aborted`)
+		stdout := testing.Stdout(ctx)
+		c.Assert(stdout, jc.HasPrefix, `Removing service "riak" will:`+"\n")
+		c.Assert(stdout, jc.Contains, "  - remove units: riak/0\n")
+		c.Assert(stdout, jc.Contains, "  - remove relations: riak:ring\n")
+		c.Assert(stdout, jc.HasSuffix, "Continue [y/N]? ")
+		riak, err := s.State.Service("riak")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(riak.Life(), gc.Equals, state.Alive)
+	}
+
+	_, err := runRemoveServiceWithInput(c, "y", "riak")
 	c.Assert(err, jc.ErrorIsNil)
 	riak, err := s.State.Service("riak")
 	c.Assert(err, jc.ErrorIsNil)
@@ -53,7 +100,7 @@ func (s *RemoveServiceSuite) TestBlockRemoveService(c *gc.C) {
 
 	// block operation
 	s.BlockRemoveObject(c, "TestBlockRemoveService")
-	err := runRemoveService(c, "riak")
+	err := runRemoveService(c, "riak", "--yes")
 	s.AssertBlocked(c, err, ".*TestBlockRemoveService.*")
 	riak, err := s.State.Service("riak")
 	c.Assert(err, jc.ErrorIsNil)
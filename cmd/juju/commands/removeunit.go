@@ -5,10 +5,14 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -21,6 +25,7 @@ func newRemoveUnitCommand() cmd.Command {
 type removeUnitCommand struct {
 	envcmd.EnvCommandBase
 	UnitNames []string
+	assumeYes bool
 }
 
 const removeUnitDoc = `
@@ -31,6 +36,11 @@ the unit is hosted will also be destroyed, if possible.
 The machine will be destroyed if:
 - it is not a state server
 - it is not hosting any Juju managed containers
+
+When run from a terminal, the units, machines and storage that will be
+affected are listed before anything is removed, and confirmation is
+requested. Use --yes to skip the confirmation. No confirmation is
+requested when standard input is not a terminal.
 `
 
 func (c *removeUnitCommand) Info() *cmd.Info {
@@ -43,6 +53,11 @@ func (c *removeUnitCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *removeUnitCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.assumeYes, "y", false, "Do not ask for confirmation")
+	f.BoolVar(&c.assumeYes, "yes", false, "")
+}
+
 func (c *removeUnitCommand) Init(args []string) error {
 	c.UnitNames = args
 	if len(c.UnitNames) == 0 {
@@ -58,11 +73,21 @@ func (c *removeUnitCommand) Init(args []string) error {
 
 // Run connects to the environment specified on the command line and destroys
 // units therein.
-func (c *removeUnitCommand) Run(_ *cmd.Context) error {
-	client, err := c.NewAPIClient()
+func (c *removeUnitCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
 	if err != nil {
 		return err
 	}
-	defer client.Close()
+	defer root.Close()
+	if !c.assumeYes && isTerminal(ctx.Stdin) {
+		what := "units " + strings.Join(c.UnitNames, ", ")
+		err := confirmRemoval(ctx, what, func() (params.RemovalImpact, error) {
+			return apiservice.NewClient(root).DestroyUnitsImpact(c.UnitNames...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	client := root.Client()
 	return block.ProcessBlockedError(client.DestroyServiceUnits(c.UnitNames...), block.BlockRemove)
 }
@@ -5,7 +5,10 @@ package commands
 
 import (
 	"fmt"
+	"io"
+	"strings"
 
+	"github.com/juju/cmd"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -35,6 +38,16 @@ func runRemoveUnit(c *gc.C, args ...string) error {
 	return err
 }
 
+func runRemoveUnitWithInput(c *gc.C, input string, args ...string) (*cmd.Context, error) {
+	com := newRemoveUnitCommand()
+	if err := testing.InitCommand(com, args); err != nil {
+		return nil, err
+	}
+	ctx := testing.Context(c)
+	ctx.Stdin = strings.NewReader(input)
+	return ctx, com.Run(ctx)
+}
+
 func (s *RemoveUnitSuite) setupUnitForRemove(c *gc.C) *state.Service {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
 	err := runDeploy(c, "-n", "2", "local:dummy", "dummy")
@@ -47,7 +60,7 @@ func (s *RemoveUnitSuite) setupUnitForRemove(c *gc.C) *state.Service {
 func (s *RemoveUnitSuite) TestRemoveUnit(c *gc.C) {
 	svc := s.setupUnitForRemove(c)
 
-	err := runRemoveUnit(c, "dummy/0", "dummy/1", "dummy/2", "sillybilly/17")
+	err := runRemoveUnit(c, "--yes", "dummy/0", "dummy/1", "dummy/2", "sillybilly/17")
 	c.Assert(err, gc.ErrorMatches, `some units were not destroyed: unit "dummy/2" does not exist; unit "sillybilly/17" does not exist`)
 	units, err := svc.AllUnits()
 	c.Assert(err, jc.ErrorIsNil)
@@ -55,12 +68,65 @@ func (s *RemoveUnitSuite) TestRemoveUnit(c *gc.C) {
 		c.Assert(u.Life(), gc.Equals, state.Dying)
 	}
 }
+
+func (s *RemoveUnitSuite) TestRemoveUnitNoConfirmationWithoutTerminal(c *gc.C) {
+	svc := s.setupUnitForRemove(c)
+
+	ctx, err := runRemoveUnitWithInput(c, "", "dummy/0", "dummy/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	units, err := svc.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, u := range units {
+		c.Assert(u.Life(), gc.Equals, state.Dying)
+	}
+}
+
+func (s *RemoveUnitSuite) TestRemoveUnitConfirmation(c *gc.C) {
+	s.PatchValue(&isTerminal, func(io.Reader) bool { return true })
+	svc := s.setupUnitForRemove(c)
+
+	ctx, err := runRemoveUnitWithInput(c, "n", "dummy/0", "dummy/1")
+	c.Assert(err, gc.ErrorMatches, "removal of units dummy/0, dummy/1 aborted")
+	stdout := testing.Stdout(ctx)
+	c.Assert(stdout, jc.HasPrefix, "Removing units dummy/0, dummy/1 will:\n  - remove units: dummy/0, dummy/1\n")
+	c.Assert(stdout, jc.HasSuffix, "Continue [y/N]? ")
+	units, err := svc.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, u := range units {
+		c.Assert(u.Life(), gc.Equals, state.Alive)
+	}
+
+	_, err = runRemoveUnitWithInput(c, "yes", "dummy/0", "dummy/1")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = svc.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, u := range units {
+		c.Assert(u.Life(), gc.Equals, state.Dying)
+	}
+}
+
+func (s *RemoveUnitSuite) TestRemoveUnitConfirmationUnknownUnit(c *gc.C) {
+	s.PatchValue(&isTerminal, func(io.Reader) bool { return true })
+	svc := s.setupUnitForRemove(c)
+
+	ctx, err := runRemoveUnitWithInput(c, "y", "dummy/0", "sillybilly/17")
+	c.Assert(err, gc.ErrorMatches, `some units were not destroyed: unit "sillybilly/17" does not exist`)
+	stdout := testing.Stdout(ctx)
+	c.Assert(stdout, jc.Contains, "  - remove units: dummy/0\n")
+	c.Assert(stdout, jc.Contains, `  - fail: unit "sillybilly/17" not found`+"\n")
+	unit, err := s.State.Unit("dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Life(), gc.Equals, state.Dying)
+	c.Assert(svc.Life(), gc.Equals, state.Alive)
+}
+
 func (s *RemoveUnitSuite) TestBlockRemoveUnit(c *gc.C) {
 	svc := s.setupUnitForRemove(c)
 
 	// block operation
 	s.BlockRemoveObject(c, "TestBlockRemoveUnit")
-	err := runRemoveUnit(c, "dummy/0", "dummy/1")
+	err := runRemoveUnit(c, "--yes", "dummy/0", "dummy/1")
 	s.AssertBlocked(c, err, ".*TestBlockRemoveUnit.*")
 	c.Assert(svc.Life(), gc.Equals, state.Alive)
 }
@@ -11,12 +11,15 @@ import (
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
 )
 
 func newRunCommand() cmd.Command {
@@ -28,11 +31,15 @@ type runCommand struct {
 	envcmd.EnvCommandBase
 	out      cmd.Output
 	all      bool
+	stream   bool
 	timeout  time.Duration
 	machines []string
 	services []string
 	units    []string
 	commands string
+
+	relationId string
+	remoteUnit string
 }
 
 const runDoc = `
@@ -61,6 +68,24 @@ the unit.
 in the environment.  If you specify --all you cannot provide additional
 targets.
 
+The timeout applies to each target separately, and is enforced by the
+API server: a target whose command has not completed in time is reported
+as having timed out, without affecting the results of other targets.
+
+Normally the results are shown once the command has completed on every
+target. With --stream, each target's result is shown as soon as it
+completes instead. Services and --all are expanded to the units and
+machines they have when the command starts. In yaml and smart formats
+the results together form a single list; in json format each result is
+written as an object on its own line.
+
+Commands run on units may be run in the context of one of the unit's
+relations by specifying --relation, as either the relation id or in the
+form <endpoint>:<id>, so that relation-get and relation-set operate on
+that relation just as they would in one of its relation hooks. Use
+--remote-unit to choose the remote unit that relation-get reads from
+by default. Neither option can be used with --all or --machine.
+
 `
 
 func (c *runCommand) Info() *cmd.Info {
@@ -75,10 +100,13 @@ func (c *runCommand) Info() *cmd.Info {
 func (c *runCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.BoolVar(&c.all, "all", false, "run the commands on all the machines")
+	f.BoolVar(&c.stream, "stream", false, "show each target's result as soon as it completes")
 	f.DurationVar(&c.timeout, "timeout", 5*time.Minute, "how long to wait before the remote command is considered to have failed")
 	f.Var(cmd.NewStringsValue(nil, &c.machines), "machine", "one or more machine ids")
 	f.Var(cmd.NewStringsValue(nil, &c.services), "service", "one or more service names")
 	f.Var(cmd.NewStringsValue(nil, &c.units), "unit", "one or more unit ids")
+	f.StringVar(&c.relationId, "relation", "", "run the commands on units in the context of the given relation")
+	f.StringVar(&c.remoteUnit, "remote-unit", "", "run the commands for the given remote unit in the relation context")
 }
 
 func (c *runCommand) Init(args []string) error {
@@ -103,6 +131,18 @@ func (c *runCommand) Init(args []string) error {
 		}
 	}
 
+	if c.relationId != "" && (c.all || len(c.machines) != 0) {
+		return fmt.Errorf("You cannot specify --relation when running on machines")
+	}
+	if c.remoteUnit != "" {
+		if c.relationId == "" {
+			return fmt.Errorf("You cannot specify --remote-unit without --relation")
+		}
+		if !names.IsValidUnit(c.remoteUnit) {
+			return fmt.Errorf("%q is not a valid remote unit name", c.remoteUnit)
+		}
+	}
+
 	var nameErrors []string
 	for _, machineId := range c.machines {
 		if !names.IsValidMachine(machineId) {
@@ -176,6 +216,10 @@ func ConvertRunResults(runResults []params.RunResult) interface{} {
 	return results
 }
 
+// errRelationNotSupported is returned when --relation is used against
+// an API server that cannot run commands in a relation context.
+var errRelationNotSupported = errors.New("--relation is not supported by this environment")
+
 func (c *runCommand) Run(ctx *cmd.Context) error {
 	client, err := getRunAPIClient(c)
 	if err != nil {
@@ -183,44 +227,192 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if c.stream {
+		return c.runStreaming(ctx, client)
+	}
+
 	var runResults []params.RunResult
 	if c.all {
 		runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
 	} else {
 		params := params.RunParams{
-			Commands: c.commands,
-			Timeout:  c.timeout,
-			Machines: c.machines,
-			Services: c.services,
-			Units:    c.units,
+			Commands:   c.commands,
+			Timeout:    c.timeout,
+			Machines:   c.machines,
+			Services:   c.services,
+			Units:      c.units,
+			RelationId: c.relationId,
+			RemoteUnit: c.remoteUnit,
 		}
 		runResults, err = client.Run(params)
 	}
 
-	if err != nil {
+	if errors.IsNotImplemented(err) {
+		return errRelationNotSupported
+	} else if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 
 	// If we are just dealing with one result, AND we are using the smart
 	// format, then pretend we were running it locally.
 	if len(runResults) == 1 && c.out.Name() == "smart" {
-		result := runResults[0]
-		ctx.Stdout.Write(result.Stdout)
-		ctx.Stderr.Write(result.Stderr)
-		if result.Error != "" {
-			// Convert the error string back into an error object.
-			return fmt.Errorf("%s", result.Error)
-		}
-		if result.Code != 0 {
-			return cmd.NewRcPassthroughError(result.Code)
-		}
-		return nil
+		return writeLocalResult(ctx, runResults[0])
 	}
 
 	c.out.Write(ctx, ConvertRunResults(runResults))
 	return nil
 }
 
+// writeLocalResult writes the output of a single result as if the
+// commands had been run locally.
+func writeLocalResult(ctx *cmd.Context, result params.RunResult) error {
+	ctx.Stdout.Write(result.Stdout)
+	ctx.Stderr.Write(result.Stderr)
+	if result.Error != "" {
+		// Convert the error string back into an error object.
+		return fmt.Errorf("%s", result.Error)
+	}
+	if result.Code != 0 {
+		return cmd.NewRcPassthroughError(result.Code)
+	}
+	return nil
+}
+
+// targetResult holds the outcome of running the commands on a single
+// target.
+type targetResult struct {
+	target  params.RunParams
+	results []params.RunResult
+	err     error
+}
+
+// runStreaming runs the commands on each target concurrently, writing
+// each target's results as soon as they are available.
+func (c *runCommand) runStreaming(ctx *cmd.Context, client RunClient) error {
+	targets, err := c.expandTargets(client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	done := make(chan targetResult, len(targets))
+	for _, target := range targets {
+		go func(target params.RunParams) {
+			results, err := client.Run(target)
+			done <- targetResult{target, results, err}
+		}(target)
+	}
+
+	var blockedErr error
+	for range targets {
+		r := <-done
+		if r.err != nil {
+			if errors.IsNotImplemented(r.err) {
+				return errRelationNotSupported
+			}
+			if params.IsCodeOperationBlocked(r.err) {
+				blockedErr = r.err
+				continue
+			}
+			r.results = []params.RunResult{targetError(r.target, r.err)}
+		}
+		if len(targets) == 1 && len(r.results) == 1 && c.out.Name() == "smart" {
+			return writeLocalResult(ctx, r.results[0])
+		}
+		for _, result := range r.results {
+			var value interface{} = ConvertRunResults([]params.RunResult{result})
+			if c.out.Name() == "json" {
+				value = value.([]interface{})[0]
+			}
+			if err := c.out.Write(ctx, value); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	if blockedErr != nil {
+		return block.ProcessBlockedError(blockedErr, block.BlockChange)
+	}
+	return nil
+}
+
+// targetError returns a result recording that the commands could not
+// be run on the given target.
+func targetError(target params.RunParams, err error) params.RunResult {
+	var result params.RunResult
+	if len(target.Machines) > 0 {
+		result.MachineId = target.Machines[0]
+	}
+	if len(target.Units) > 0 {
+		result.UnitId = target.Units[0]
+	}
+	result.Error = err.Error()
+	return result
+}
+
+// expandTargets returns parameters for running the commands on each
+// machine and unit targeted by the command separately. Services and
+// --all are expanded to the units and machines currently in the
+// environment.
+func (c *runCommand) expandTargets(client RunClient) ([]params.RunParams, error) {
+	machines := set.NewStrings(c.machines...)
+	units := set.NewStrings(c.units...)
+	if c.all || len(c.services) > 0 {
+		status, err := client.Status(nil)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot get environment status")
+		}
+		if c.all {
+			addMachineIds(machines, status.Machines)
+		}
+		services := set.NewStrings(c.services...)
+		for _, service := range c.services {
+			if _, ok := status.Services[service]; !ok {
+				return nil, errors.NotFoundf("service %q", service)
+			}
+		}
+		for _, service := range status.Services {
+			addServiceUnits(units, services, service.Units)
+		}
+	}
+
+	var targets []params.RunParams
+	for _, id := range common.SortStringsNaturally(machines.Values()) {
+		targets = append(targets, params.RunParams{
+			Commands: c.commands,
+			Timeout:  c.timeout,
+			Machines: []string{id},
+		})
+	}
+	for _, name := range common.SortStringsNaturally(units.Values()) {
+		targets = append(targets, params.RunParams{
+			Commands:   c.commands,
+			Timeout:    c.timeout,
+			Units:      []string{name},
+			RelationId: c.relationId,
+			RemoteUnit: c.remoteUnit,
+		})
+	}
+	return targets, nil
+}
+
+// addMachineIds adds the ids of the given machines and their
+// containers to ids.
+func addMachineIds(ids set.Strings, machines map[string]params.MachineStatus) {
+	for id, machine := range machines {
+		ids.Add(id)
+		addMachineIds(ids, machine.Containers)
+	}
+}
+
+// addServiceUnits adds the names of those of the given units and their
+// subordinates that belong to the given services to unitNames.
+func addServiceUnits(unitNames, services set.Strings, units map[string]params.UnitStatus) {
+	for name, unit := range units {
+		if service, err := names.UnitService(name); err == nil && services.Contains(service) {
+			unitNames.Add(name)
+		}
+		addServiceUnits(unitNames, services, unit.Subordinates)
+	}
+}
+
 // In order to be able to easily mock out the API side for testing,
 // the API client is got using a function.
 
@@ -228,6 +420,7 @@ type RunClient interface {
 	Close() error
 	RunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error)
 	Run(run params.RunParams) ([]params.RunResult, error)
+	Status(patterns []string) (*params.FullStatus, error)
 }
 
 // Here we need the signature to be correct for the interface.
@@ -7,12 +7,15 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/exec"
 	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -122,6 +125,50 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 	}
 }
 
+func (*RunSuite) TestRelationArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		message    string
+		args       []string
+		relationId string
+		remoteUnit string
+		errMatch   string
+	}{{
+		message:    "relation for units",
+		args:       []string{"--unit=wordpress/0", "--relation=db:2", "relation-get"},
+		relationId: "db:2",
+	}, {
+		message:    "relation and remote unit for a service",
+		args:       []string{"--service=wordpress", "--relation=2", "--remote-unit=mysql/0", "relation-get"},
+		relationId: "2",
+		remoteUnit: "mysql/0",
+	}, {
+		message:  "relation for all machines",
+		args:     []string{"--all", "--relation=db:2", "relation-get"},
+		errMatch: "You cannot specify --relation when running on machines",
+	}, {
+		message:  "relation for a machine",
+		args:     []string{"--machine=0", "--unit=wordpress/0", "--relation=db:2", "relation-get"},
+		errMatch: "You cannot specify --relation when running on machines",
+	}, {
+		message:  "remote unit without relation",
+		args:     []string{"--unit=wordpress/0", "--remote-unit=mysql/0", "relation-get"},
+		errMatch: "You cannot specify --remote-unit without --relation",
+	}, {
+		message:  "bad remote unit",
+		args:     []string{"--unit=wordpress/0", "--relation=db:2", "--remote-unit=mysql", "relation-get"},
+		errMatch: `"mysql" is not a valid remote unit name`,
+	}} {
+		c.Log(fmt.Sprintf("%v: %s", i, test.message))
+		cmd := &runCommand{}
+		runCmd := envcmd.Wrap(cmd)
+		testing.TestInit(c, runCmd, test.args, test.errMatch)
+		if test.errMatch == "" {
+			c.Check(cmd.relationId, gc.Equals, test.relationId)
+			c.Check(cmd.remoteUnit, gc.Equals, test.remoteUnit)
+		}
+	}
+}
+
 func (*RunSuite) TestTimeoutArgParsing(c *gc.C) {
 	for i, test := range []struct {
 		message  string
@@ -271,6 +318,39 @@ func (s *RunSuite) TestRunForMachineAndUnit(c *gc.C) {
 	c.Check(testing.Stdout(context), gc.Equals, string(jsonFormatted)+"\n")
 }
 
+func (s *RunSuite) TestRunInRelationContext(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setResponse("unit/0", mockResponse{
+		stdout:    "10.0.0.1",
+		machineId: "1",
+		unitId:    "unit/0",
+	})
+
+	context, err := testing.RunCommand(c, newRunCommand(),
+		"--unit=unit/0", "--relation=db:2", "--remote-unit=mysql/0", "relation-get private-address",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(context), gc.Equals, "10.0.0.1")
+	c.Assert(mock.runParams, gc.HasLen, 1)
+	c.Check(mock.runParams[0].RelationId, gc.Equals, "db:2")
+	c.Check(mock.runParams[0].RemoteUnit, gc.Equals, "mysql/0")
+}
+
+func (s *RunSuite) TestRunInRelationContextNotSupported(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.noRelations = true
+
+	_, err := testing.RunCommand(c, newRunCommand(),
+		"--unit=unit/0", "--relation=db:2", "relation-get private-address",
+	)
+	c.Assert(err, gc.ErrorMatches, "--relation is not supported by this environment")
+
+	_, err = testing.RunCommand(c, newRunCommand(),
+		"--unit=unit/0", "--relation=db:2", "--stream", "relation-get private-address",
+	)
+	c.Assert(err, gc.ErrorMatches, "--relation is not supported by this environment")
+}
+
 func (s *RunSuite) TestBlockRunForMachineAndUnit(c *gc.C) {
 	mock := s.setupMockAPI()
 	// Block operation
@@ -377,6 +457,88 @@ func (s *RunSuite) TestSingleResponse(c *gc.C) {
 	}
 }
 
+func (s *RunSuite) TestStream(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.services = map[string][]string{
+		"wordpress": {"wordpress/0", "wordpress/1"},
+		"mysql":     {"mysql/0"},
+	}
+	responses := []mockResponse{{
+		stdout:    "megatron\n",
+		machineId: "0",
+	}, {
+		stdout:    "bumblebee",
+		machineId: "1",
+		unitId:    "wordpress/0",
+	}, {
+		stderr:    "optimus",
+		code:      1,
+		machineId: "2",
+		unitId:    "wordpress/1",
+	}}
+	var expected []string
+	for _, response := range responses {
+		id := response.unitId
+		if id == "" {
+			id = response.machineId
+		}
+		mock.setResponse(id, response)
+		converted := ConvertRunResults([]params.RunResult{makeRunResult(response)})
+		formatted, err := cmd.FormatJson(converted.([]interface{})[0])
+		c.Assert(err, jc.ErrorIsNil)
+		expected = append(expected, string(formatted))
+	}
+
+	context, err := testing.RunCommand(c, newRunCommand(),
+		"--stream", "--format=json", "--machine=0", "--service=wordpress", "hostname",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	lines := strings.Split(strings.TrimSuffix(testing.Stdout(context), "\n"), "\n")
+	c.Check(lines, jc.SameContents, expected)
+}
+
+func (s *RunSuite) TestStreamAllMachines(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setMachinesAlive("0", "1")
+	mock.setResponse("0", mockResponse{stdout: "megatron\n", machineId: "0"})
+	mock.setResponse("1", mockResponse{stdout: "bumblebee\n", machineId: "1"})
+
+	context, err := testing.RunCommand(c, newRunCommand(), "--stream", "--format=yaml", "--all", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+	var results []map[string]string
+	err = goyaml.Unmarshal([]byte(testing.Stdout(context)), &results)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results, jc.SameContents, []map[string]string{
+		{"MachineId": "0", "Stdout": "megatron\n"},
+		{"MachineId": "1", "Stdout": "bumblebee\n"},
+	})
+}
+
+func (s *RunSuite) TestStreamSingleResponse(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setResponse("0", mockResponse{stdout: "stdout\n", stderr: "stderr\n", code: 42, machineId: "0"})
+
+	context, err := testing.RunCommand(c, newRunCommand(), "--stream", "--machine=0", "hostname")
+	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 42")
+	c.Check(testing.Stdout(context), gc.Equals, "stdout\n")
+	c.Check(testing.Stderr(context), gc.Equals, "stderr\n")
+}
+
+func (s *RunSuite) TestStreamUnknownService(c *gc.C) {
+	s.setupMockAPI()
+	_, err := testing.RunCommand(c, newRunCommand(), "--stream", "--service=mysql", "hostname")
+	c.Assert(err, gc.ErrorMatches, `service "mysql" not found`)
+}
+
+func (s *RunSuite) TestStreamBlocked(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.block = true
+	_, err := testing.RunCommand(c, newRunCommand(), "--stream", "--machine=0", "--unit=unit/0", "hostname")
+	c.Assert(err, gc.ErrorMatches, cmd.ErrSilent.Error())
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*To unblock changes.*")
+}
+
 func (s *RunSuite) setupMockAPI() *mockRunAPI {
 	mock := &mockRunAPI{}
 	s.PatchValue(&getRunAPIClient, func(_ *runCommand) (RunClient, error) {
@@ -391,8 +553,18 @@ type mockRunAPI struct {
 	code   int
 	// machines, services, units
 	machines  map[string]bool
+	services  map[string][]string
 	responses map[string]params.RunResult
 	block     bool
+
+	// noRelations causes Run to fail as an API server that cannot
+	// run commands in a relation context does.
+	noRelations bool
+
+	// runParams records the parameters of each call to Run, which
+	// may be made concurrently when streaming.
+	mu        sync.Mutex
+	runParams []params.RunParams
 }
 
 type mockResponse struct {
@@ -465,10 +637,16 @@ func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) ([
 
 func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.RunResult, error) {
 	var result []params.RunResult
+	m.mu.Lock()
+	m.runParams = append(m.runParams, runParams)
+	m.mu.Unlock()
 
 	if m.block {
 		return result, common.OperationBlockedError("the operation has been blocked")
 	}
+	if m.noRelations && runParams.RelationId != "" {
+		return result, errors.NotImplementedf("Run() in a relation context (need V3+)")
+	}
 	// Just add in ids that match in order.
 	for _, id := range runParams.Machines {
 		response, found := m.responses[id]
@@ -486,3 +664,21 @@ func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.RunResult, error)
 
 	return result, nil
 }
+
+func (m *mockRunAPI) Status(patterns []string) (*params.FullStatus, error) {
+	status := &params.FullStatus{
+		Machines: make(map[string]params.MachineStatus),
+		Services: make(map[string]params.ServiceStatus),
+	}
+	for machineId := range m.machines {
+		status.Machines[machineId] = params.MachineStatus{Id: machineId}
+	}
+	for service, units := range m.services {
+		serviceStatus := params.ServiceStatus{Units: make(map[string]params.UnitStatus)}
+		for _, unit := range units {
+			serviceStatus.Units[unit] = params.UnitStatus{}
+		}
+		status.Services[service] = serviceStatus
+	}
+	return status, nil
+}
@@ -113,5 +113,10 @@ func (c *scpCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	cleanup, err := c.setHostKeyChecks(options)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	return ssh.Copy(args, options)
 }
@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/network"
+)
+
+func newSetFirewallRuleCommand() cmd.Command {
+	return envcmd.Wrap(&setFirewallRuleCommand{})
+}
+
+// setFirewallRuleCommand restricts access to a well-known service to
+// a whitelist of source address ranges.
+type setFirewallRuleCommand struct {
+	envcmd.EnvCommandBase
+	client SetFirewallRuleClient
+
+	service   network.WellKnownServiceType
+	whitelist string
+}
+
+const setFirewallRuleDoc = `
+Restricts access to a service that Juju makes available on machines
+to the given comma-separated source address ranges (CIDRs). The known
+services are:
+
+    ssh              SSH on every machine
+    juju-controller  the API server on state servers
+
+By default both are reachable from anywhere. Whitelisting 0.0.0.0/0
+restores that default. The whitelist is applied by the firewaller to
+the security groups of the environment's machines; clouds without
+support for it are left unchanged.
+
+Examples:
+    juju set-firewall-rule ssh --whitelist 10.0.0.0/8
+    juju set-firewall-rule juju-controller --whitelist 10.0.0.0/8,192.168.1.0/24
+`
+
+func (c *setFirewallRuleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-firewall-rule",
+		Args:    "<service-name> --whitelist <cidr>[,<cidr>...]",
+		Purpose: "restrict access to a well-known service to the given source addresses",
+		Doc:     setFirewallRuleDoc,
+	}
+}
+
+func (c *setFirewallRuleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.whitelist, "whitelist", "", "comma-separated source address ranges allowed to access the service")
+}
+
+func (c *setFirewallRuleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no well known service specified")
+	}
+	c.service = network.WellKnownServiceType(args[0])
+	if err := c.service.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if c.whitelist == "" {
+		return errors.New("no whitelist specified")
+	}
+	if err := network.ValidateWhitelistCIDRs(c.cidrs()); err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *setFirewallRuleCommand) cidrs() []string {
+	return strings.Split(c.whitelist, ",")
+}
+
+// SetFirewallRuleClient defines the methods on the FirewallRules API
+// facade that the set-firewall-rule command calls.
+type SetFirewallRuleClient interface {
+	Close() error
+	SetFirewallWhitelist(service network.WellKnownServiceType, cidrs []string) error
+}
+
+func (c *setFirewallRuleCommand) getClient() (SetFirewallRuleClient, error) {
+	if c.client != nil {
+		return c.client, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return firewallrules.NewClient(root), nil
+}
+
+func (c *setFirewallRuleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.SetFirewallWhitelist(c.service, c.cidrs())
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SetFirewallRuleSuite struct {
+	coretesting.FakeJujuHomeSuite
+	fake *fakeSetFirewallRuleClient
+}
+
+var _ = gc.Suite(&SetFirewallRuleSuite{})
+
+func (s *SetFirewallRuleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeSetFirewallRuleClient{}
+}
+
+type fakeSetFirewallRuleClient struct {
+	service network.WellKnownServiceType
+	cidrs   []string
+	err     error
+}
+
+func (f *fakeSetFirewallRuleClient) Close() error {
+	return nil
+}
+
+func (f *fakeSetFirewallRuleClient) SetFirewallWhitelist(service network.WellKnownServiceType, cidrs []string) error {
+	f.service = service
+	f.cidrs = cidrs
+	return f.err
+}
+
+func (s *SetFirewallRuleSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &setFirewallRuleCommand{client: s.fake}
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *SetFirewallRuleSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no well known service specified",
+	}, {
+		args: []string{"http", "--whitelist", "10.0.0.0/8"},
+		err:  `well known service type "http" not valid`,
+	}, {
+		args: []string{"ssh"},
+		err:  "no whitelist specified",
+	}, {
+		args: []string{"ssh", "--whitelist", "10.0.0.0/8,10.0.0.1"},
+		err:  `CIDR "10.0.0.1" not valid`,
+	}, {
+		args: []string{"ssh", "--whitelist", "10.0.0.0/8", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *SetFirewallRuleSuite) TestSetFirewallRule(c *gc.C) {
+	_, err := s.run(c, "juju-controller", "--whitelist", "10.0.0.0/8,192.168.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.service, gc.Equals, network.JujuControllerRule)
+	c.Assert(s.fake.cidrs, jc.DeepEquals, []string{"10.0.0.0/8", "192.168.1.0/24"})
+}
+
+func (s *SetFirewallRuleSuite) TestSetFirewallRuleError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.run(c, "ssh", "--whitelist", "10.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -5,6 +5,7 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"os/exec"
@@ -16,6 +17,7 @@ import (
 	"github.com/juju/utils"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/utils/ssh"
@@ -33,17 +35,28 @@ type sshCommand struct {
 // SSHCommon provides common methods for sshCommand, SCPCommand and DebugHooksCommand.
 type SSHCommon struct {
 	envcmd.EnvCommandBase
-	proxy     bool
-	pty       bool
-	Target    string
-	Args      []string
-	apiClient sshAPIClient
-	apiAddr   string
+	proxy           bool
+	pty             bool
+	noHostKeyChecks bool
+	Target          string
+	Args            []string
+	apiClient       sshAPIClient
+	apiAddr         string
+
+	// knownHosts holds the known_hosts lines for the machines
+	// resolved by userHostFromTarget.
+	knownHosts []string
+
+	// flagSet holds the command's flags, so that proxySSH can
+	// tell whether --proxy was given explicitly.
+	flagSet *gnuflag.FlagSet
 }
 
 func (c *SSHCommon) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.proxy, "proxy", true, "proxy through the API server")
 	f.BoolVar(&c.pty, "pty", true, "enable pseudo-tty allocation")
+	f.BoolVar(&c.noHostKeyChecks, "no-host-key-checks", false, "skip verification of the remote host's SSH key")
+	c.flagSet = f
 }
 
 // setProxyCommand sets the proxy command option.
@@ -87,6 +100,16 @@ Connect to the first mysql unit and run 'ls -la /var/log/juju':
 Connect to the first jenkins unit as the user jenkins:
 
     juju ssh jenkins@jenkins/0
+
+Connections are proxied through the API server when the environment's
+proxy-ssh setting is true, which is the default, so that machines in
+private networks can be reached. Use --proxy to proxy the connection
+regardless of that setting, or --proxy=false to connect directly.
+
+When connecting to a machine or unit, the SSH host keys reported by the
+machine's agent are verified, and the connection is refused if they do
+not match. Use --no-host-key-checks to connect without verification, for
+example to a machine whose agent has not yet reported its keys.
 `
 
 func (c *sshCommand) Info() *cmd.Info {
@@ -133,6 +156,39 @@ func (c *SSHCommon) getSSHOptions(enablePty bool) (*ssh.Options, error) {
 	return &options, nil
 }
 
+// newKnownHostsFile returns a new file to hold the known_hosts
+// lines collected while resolving targets.
+var newKnownHostsFile = func() (*os.File, error) {
+	return ioutil.TempFile("", "juju-known-hosts")
+}
+
+// setHostKeyChecks configures options to verify the host keys collected
+// by userHostFromTarget. If no keys were collected, because the targets
+// were hostnames or checks were disabled, host keys are not verified.
+// The returned function removes the known hosts file, and must be called
+// once ssh has exited.
+func (c *SSHCommon) setHostKeyChecks(options *ssh.Options) (cleanup func(), err error) {
+	cleanup = func() {}
+	if len(c.knownHosts) == 0 {
+		return cleanup, nil
+	}
+	f, err := newKnownHostsFile()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create known hosts file: %v", err)
+	}
+	defer f.Close()
+	cleanup = func() {
+		os.Remove(f.Name())
+	}
+	if _, err := f.WriteString(strings.Join(c.knownHosts, "\n") + "\n"); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("cannot write known hosts file: %v", err)
+	}
+	options.SetKnownHostsFile(f.Name())
+	options.EnableStrictHostKeyChecking()
+	return cleanup, nil
+}
+
 // Run resolves c.Target to a machine, to the address of a i
 // machine or unit forks ssh passing any arguments provided.
 func (c *sshCommand) Run(ctx *cmd.Context) error {
@@ -155,6 +211,11 @@ func (c *sshCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	cleanup, err := c.setHostKeyChecks(options)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	cmd := ssh.Command(user+"@"+host, c.Args, options)
 	cmd.Stdin = ctx.Stdin
 	cmd.Stdout = ctx.Stdout
@@ -162,16 +223,22 @@ func (c *sshCommand) Run(ctx *cmd.Context) error {
 	return cmd.Run()
 }
 
-// proxySSH returns true iff both c.proxy and
-// the proxy-ssh environment configuration
-// are true.
+// proxySSH returns true iff c.proxy is true and either
+// --proxy was given explicitly or the proxy-ssh environment
+// configuration is true.
 func (c *SSHCommon) proxySSH() (bool, error) {
 	if !c.proxy {
 		return false, nil
 	}
+	// The API client is needed either way, as the proxy
+	// command connects through the API server's address.
 	if _, err := c.ensureAPIClient(); err != nil {
 		return false, err
 	}
+	if c.proxyRequested() {
+		logger.Debugf("proxy requested explicitly")
+		return true, nil
+	}
 	var cfg *config.Config
 	attrs, err := c.apiClient.EnvironmentGet()
 	if err == nil {
@@ -184,6 +251,21 @@ func (c *SSHCommon) proxySSH() (bool, error) {
 	return cfg.ProxySSH(), nil
 }
 
+// proxyRequested reports whether --proxy was given on the
+// command line.
+func (c *SSHCommon) proxyRequested() bool {
+	if c.flagSet == nil {
+		return false
+	}
+	requested := false
+	c.flagSet.Visit(func(flag *gnuflag.Flag) {
+		if flag.Name == "proxy" {
+			requested = true
+		}
+	})
+	return requested
+}
+
 func (c *SSHCommon) ensureAPIClient() (sshAPIClient, error) {
 	if c.apiClient != nil {
 		return c.apiClient, nil
@@ -208,6 +290,7 @@ type sshAPIClient interface {
 	PublicAddress(target string) (string, error)
 	PrivateAddress(target string) (string, error)
 	ServiceCharmRelations(service string) ([]string, error)
+	SSHHostKeys(target string) ([]string, error)
 	Close() error
 }
 
@@ -259,12 +342,36 @@ func (c *SSHCommon) userHostFromTarget(target string) (user, host string, err er
 			addr, err = c.apiClient.PublicAddress(target)
 		}
 		if err == nil {
+			if err := c.addKnownHosts(target, addr); err != nil {
+				return "", "", err
+			}
 			return user, addr, nil
 		}
 	}
 	return "", "", err
 }
 
+// addKnownHosts records known_hosts lines associating the SSH host
+// keys of the given machine or unit with its address.
+func (c *SSHCommon) addKnownHosts(target, addr string) error {
+	if c.noHostKeyChecks {
+		return nil
+	}
+	keys, err := c.apiClient.SSHHostKeys(target)
+	if params.IsCodeNotImplemented(err) {
+		logger.Warningf("API server does not record SSH host keys; not verifying host key for %s", target)
+		return nil
+	} else if params.IsCodeNotFound(err) {
+		return fmt.Errorf("no SSH host keys recorded for %s (use --no-host-key-checks to connect anyway)", target)
+	} else if err != nil {
+		return fmt.Errorf("cannot get SSH host keys for %s: %v", target, err)
+	}
+	for _, key := range keys {
+		c.knownHosts = append(c.knownHosts, addr+" "+key)
+	}
+	return nil
+}
+
 // AllowInterspersedFlags for ssh/scp is set to false so that
 // flags after the unit name are passed through to ssh, for eg.
 // `juju ssh -v service-name/0 uname -a`.
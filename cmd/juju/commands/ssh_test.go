@@ -6,6 +6,7 @@ package commands
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -52,15 +53,22 @@ func (s *SSHCommonSuite) SetUpTest(c *gc.C) {
 	}
 	client, _ := ssh.NewOpenSSHClient()
 	s.PatchValue(&ssh.DefaultClient, client)
+	s.PatchValue(&newKnownHostsFile, func() (*os.File, error) {
+		return os.Create(knownHostsFile)
+	})
 }
 
 const (
-	noProxy           = `-o StrictHostKeyChecking no -o PasswordAuthentication no -o ServerAliveInterval 30 `
-	args              = `-o StrictHostKeyChecking no -o ProxyCommand juju ssh --proxy=false --pty=false localhost nc %h %p -o PasswordAuthentication no -o ServerAliveInterval 30 `
-	commonArgsNoProxy = noProxy + `-o UserKnownHostsFile /dev/null `
-	commonArgs        = args + `-o UserKnownHostsFile /dev/null `
-	sshArgs           = args + `-t -t -o UserKnownHostsFile /dev/null `
-	sshArgsNoProxy    = noProxy + `-t -t -o UserKnownHostsFile /dev/null `
+	knownHostsFile = "/tmp/juju-known-hosts-test"
+
+	noProxy           = `-o StrictHostKeyChecking yes -o PasswordAuthentication no -o ServerAliveInterval 30 `
+	args              = `-o StrictHostKeyChecking yes -o ProxyCommand juju ssh --proxy=false --pty=false localhost nc %h %p -o PasswordAuthentication no -o ServerAliveInterval 30 `
+	commonArgsNoProxy = noProxy + `-o UserKnownHostsFile ` + knownHostsFile + ` `
+	commonArgs        = args + `-o UserKnownHostsFile ` + knownHostsFile + ` `
+	sshArgs           = args + `-t -t -o UserKnownHostsFile ` + knownHostsFile + ` `
+	sshArgsNoProxy    = noProxy + `-t -t -o UserKnownHostsFile ` + knownHostsFile + ` `
+
+	sshArgsNoChecks = `-o StrictHostKeyChecking no -o ProxyCommand juju ssh --proxy=false --pty=false localhost nc %h %p -o PasswordAuthentication no -o ServerAliveInterval 30 -t -t -o UserKnownHostsFile /dev/null `
 )
 
 var sshTests = []struct {
@@ -98,6 +106,16 @@ var sshTests = []struct {
 		[]string{"ssh", "--proxy=false", "mysql/0"},
 		sshArgsNoProxy + "ubuntu@dummyenv-0.dns",
 	},
+	{
+		"connect to machine 0 without host key checks",
+		[]string{"ssh", "--no-host-key-checks", "0"},
+		sshArgsNoChecks + "ubuntu@dummyenv-0.internal",
+	},
+	{
+		"connect to a hostname",
+		[]string{"ssh", "some.host"},
+		sshArgsNoChecks + "ubuntu@some.host",
+	},
 }
 
 func (s *SSHSuite) TestSSHCommand(c *gc.C) {
@@ -130,7 +148,8 @@ func (s *SSHSuite) TestSSHCommand(c *gc.C) {
 
 func (s *SSHSuite) TestSSHCommandEnvironProxySSH(c *gc.C) {
 	s.makeMachines(1, c, true)
-	// Setting proxy-ssh=false in the environment overrides --proxy.
+	// Setting proxy-ssh=false in the environment overrides the
+	// default for --proxy.
 	err := s.State.UpdateEnvironConfig(map[string]interface{}{"proxy-ssh": false}, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	ctx := coretesting.Context(c)
@@ -142,6 +161,48 @@ func (s *SSHSuite) TestSSHCommandEnvironProxySSH(c *gc.C) {
 	c.Check(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n"), gc.Equals, sshArgsNoProxy+"ubuntu@dummyenv-0.dns")
 }
 
+func (s *SSHSuite) TestSSHCommandExplicitProxy(c *gc.C) {
+	s.makeMachines(1, c, true)
+	// An explicit --proxy overrides proxy-ssh=false in the environment.
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"proxy-ssh": false}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := coretesting.Context(c)
+	jujucmd := cmd.NewSuperCommand(cmd.SuperCommandParams{})
+	jujucmd.Register(newSSHCommand())
+	code := cmd.Main(jujucmd, ctx, []string{"ssh", "--proxy", "0"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
+	c.Check(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n"), gc.Equals, sshArgs+"ubuntu@dummyenv-0.internal")
+}
+
+func (s *SSHSuite) TestSSHCommandNoHostKeys(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setAddresses(m, c)
+	ctx := coretesting.Context(c)
+	code := cmd.Main(newSSHCommand(), ctx, []string{"0"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals,
+		"error: no SSH host keys recorded for 0 (use --no-host-key-checks to connect anyway)\n")
+	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, "")
+}
+
+func (s *SSHSuite) TestSetHostKeyChecks(c *gc.C) {
+	sshCommon := &SSHCommon{
+		knownHosts: []string{"10.0.0.1 ssh-rsa foo", "10.0.0.2 ssh-dss bar"},
+	}
+	var options ssh.Options
+	cleanup, err := sshCommon.setHostKeyChecks(&options)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(knownHostsFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "10.0.0.1 ssh-rsa foo\n10.0.0.2 ssh-dss bar\n")
+
+	cleanup()
+	_, err = os.Stat(knownHostsFile)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
 func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
 	// Check the API client interface used by "juju ssh" against what
 	// the API server will allow during upgrades. Ensure that the API
@@ -243,6 +304,8 @@ func (s *SSHCommonSuite) makeMachines(n int, c *gc.C, setAddresses bool) []*stat
 		// machine has been provisioned
 		inst, md := testing.AssertStartInstance(c, s.Environ, m.Id())
 		c.Assert(m.SetProvisioned(inst.Id(), "fake_nonce", md), gc.IsNil)
+		keys := state.SSHHostKeys{fmt.Sprintf("ssh-rsa dummy-key-%s", m.Id())}
+		c.Assert(s.State.SetSSHHostKeys(m.MachineTag(), keys), jc.ErrorIsNil)
 		machines[i] = m
 	}
 	return machines
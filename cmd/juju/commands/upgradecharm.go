@@ -6,6 +6,7 @@ package commands
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -30,6 +31,9 @@ type upgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+
+	Strategy       string
+	MaxUnavailable int
 }
 
 const upgradeCharmDoc = `
@@ -52,14 +56,15 @@ a deploy command.
 
 Please note that --switch is dangerous, because juju only has limited
 information with which to determine compatibility; the operation will succeed,
-regardless of potential havoc, so long as the following conditions hold:
+regardless of potential havoc, so long as the following condition holds:
 
 - The new charm must declare all relations that the service is currently
 participating in.
-- All config settings shared by the old and new charms must
-have the same types.
 
-The new charm may add new relations and configuration settings.
+The new charm may add new relations and configuration settings. Settings of
+the service that the new charm does not define, or whose values are not valid
+for the new charm, are dropped; the settings affected are reported before the
+switch is made.
 
 --switch and --revision are mutually exclusive. To specify a given revision
 number with --switch, give it in the charm URL, for instance "cs:wordpress-5"
@@ -68,6 +73,18 @@ would specify revision number 5 of the wordpress charm.
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
+
+By default all units of the service are upgraded at once. The --strategy
+flag changes how this and later upgrades of the service are rolled out:
+with "rolling", at most --max-unavailable units are upgraded at a time, and
+further units are only upgraded once the upgraded units are idle and their
+workload status is active. The strategy is remembered for the service; use
+--strategy=all-at-once to return to the default. --force upgrades all units
+immediately regardless of the strategy.
+
+Examples:
+
+    juju upgrade-charm --strategy=rolling --max-unavailable=5 wordpress
 `
 
 func (c *upgradeCharmCommand) Info() *cmd.Info {
@@ -84,6 +101,8 @@ func (c *upgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.StringVar(&c.Strategy, "strategy", "", "how to roll out the upgrade to units: all-at-once or rolling")
+	f.IntVar(&c.MaxUnavailable, "max-unavailable", 0, "maximum number of units upgrading at once with a rolling strategy")
 }
 
 func (c *upgradeCharmCommand) Init(args []string) error {
@@ -101,6 +120,18 @@ func (c *upgradeCharmCommand) Init(args []string) error {
 	if c.SwitchURL != "" && c.Revision != -1 {
 		return fmt.Errorf("--switch and --revision are mutually exclusive")
 	}
+	switch c.Strategy {
+	case "rolling":
+		if c.MaxUnavailable < 1 {
+			c.MaxUnavailable = 1
+		}
+	case "", "all-at-once":
+		if c.MaxUnavailable != 0 {
+			return fmt.Errorf("--max-unavailable requires --strategy=rolling")
+		}
+	default:
+		return fmt.Errorf("invalid upgrade strategy %q", c.Strategy)
+	}
 	return nil
 }
 
@@ -157,11 +188,70 @@ func (c *upgradeCharmCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
+	// Settings are carried over to a new charm only where it
+	// accepts them, so read them before switching to report any
+	// that will be lost.
+	var oldSettings map[string]interface{}
+	if c.SwitchURL != "" {
+		results, err := client.ServiceGet(c.ServiceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		oldSettings = results.Config
+	}
+
 	addedURL, err := addCharmViaAPI(client, newURL, repo, csClient)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 	ctx.Infof("Added charm %q to the environment.", addedURL)
 
+	if c.SwitchURL != "" {
+		info, err := client.CharmInfo(addedURL.String())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		reportSwitchedSettings(ctx, oldSettings, info.Config)
+	}
+
+	if c.Strategy != "" {
+		err := client.ServiceSetUpgradeStrategy(c.ServiceName, c.Strategy, c.MaxUnavailable)
+		if errors.IsNotImplemented(err) {
+			return errors.New("--strategy is not supported by this environment")
+		} else if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
+
 	return block.ProcessBlockedError(client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force), block.BlockChange)
 }
+
+// reportSwitchedSettings tells the user which of the service's
+// settings, as described by ServiceGet, are not defined by the new
+// charm's config or have changed type. Only settings that have been
+// set explicitly are reported.
+func reportSwitchedSettings(ctx *cmd.Context, oldSettings map[string]interface{}, newConfig *charm.Config) {
+	var names []string
+	for name := range oldSettings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		info, _ := oldSettings[name].(map[string]interface{})
+		if isDefault, _ := info["default"].(bool); isDefault || info["value"] == nil {
+			continue
+		}
+		var option charm.Option
+		ok := false
+		if newConfig != nil {
+			option, ok = newConfig.Options[name]
+		}
+		oldType, _ := info["type"].(string)
+		switch {
+		case !ok:
+			ctx.Infof("Setting %q is not defined by the new charm and will be dropped.", name)
+		case oldType != option.Type:
+			ctx.Infof("Setting %q changes type from %s to %s; its value is kept only if valid for the new type.", name, oldType, option.Type)
+		}
+	}
+}
@@ -111,6 +111,13 @@ func (s *UpgradeCharmErrorsSuite) TestSwitchAndRevisionFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "--switch and --revision are mutually exclusive")
 }
 
+func (s *UpgradeCharmErrorsSuite) TestInvalidStrategy(c *gc.C) {
+	err := runUpgradeCharm(c, "riak", "--strategy=sometimes")
+	c.Assert(err, gc.ErrorMatches, `invalid upgrade strategy "sometimes"`)
+	err = runUpgradeCharm(c, "riak", "--max-unavailable=2")
+	c.Assert(err, gc.ErrorMatches, "--max-unavailable requires --strategy=rolling")
+}
+
 func (s *UpgradeCharmErrorsSuite) TestInvalidRevision(c *gc.C) {
 	s.deployService(c)
 	err := runUpgradeCharm(c, "riak", "--revision=blah")
@@ -230,6 +237,18 @@ func (s *UpgradeCharmSuccessSuite) TestForcedUpgrade(c *gc.C) {
 	s.assertLocalRevision(c, 7, s.path)
 }
 
+func (s *UpgradeCharmSuccessSuite) TestRollingUpgrade(c *gc.C) {
+	err := runUpgradeCharm(c, "riak", "--strategy=rolling", "--max-unavailable=2")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+	service, err := s.State.Service("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.UpgradeStrategy(), jc.DeepEquals, state.UpgradeStrategy{
+		Mode:           state.UpgradeRolling,
+		MaxUnavailable: 2,
+	})
+}
+
 func (s *UpgradeCharmSuccessSuite) TestBlockForcedUpgrade(c *gc.C) {
 	// Block operation
 	s.BlockAllChanges(c, "TestBlockForcedUpgrade")
@@ -280,6 +299,49 @@ func (s *UpgradeCharmSuccessSuite) TestSwitch(c *gc.C) {
 	s.assertLocalRevision(c, 42, myriakPath)
 }
 
+func (s *UpgradeCharmSuccessSuite) TestSwitchReportsSettings(c *gc.C) {
+	testcharms.Repo.ClonedDirPath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	dummy, err := s.State.Service("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	err = dummy.UpdateConfigSettings(charm.Settings{
+		"title":       "Nearly There",
+		"outlook":     "sunny",
+		"skill-level": int64(9),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	mydummyPath := testcharms.Repo.RenamedClonedDirPath(s.SeriesPath, "dummy", "mydummy")
+	err = ioutil.WriteFile(path.Join(mydummyPath, "metadata.yaml"), []byte(`
+name: mydummy
+summary: "That's another dummy charm."
+description: "A dummy charm with different settings."
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path.Join(mydummyPath, "config.yaml"), []byte(`
+options:
+  title: {default: My Title, description: A title., type: string}
+  username: {default: admin001, description: A user name., type: string}
+  skill-level: {description: A skill., type: string}
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := testing.RunCommand(c, newUpgradeCharmCommand(), "dummy", "--switch=local:mydummy")
+	c.Assert(err, jc.ErrorIsNil)
+	stderr := testing.Stderr(ctx)
+	c.Check(stderr, jc.Contains, `Setting "outlook" is not defined by the new charm and will be dropped.`)
+	c.Check(stderr, jc.Contains, `Setting "skill-level" changes type from int to string; its value is kept only if valid for the new type.`)
+	c.Check(stderr, gc.Not(jc.Contains), `"title"`)
+	c.Check(stderr, gc.Not(jc.Contains), `"username"`)
+
+	err = dummy.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := dummy.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"title": "Nearly There"})
+}
+
 type UpgradeCharmCharmStoreSuite struct {
 	charmStoreSuite
 }
@@ -0,0 +1,386 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/osenv"
+)
+
+var logger = loggo.GetLogger("juju.cmd.juju.common")
+
+// Entity kinds that may be completed dynamically.
+const (
+	completeServices = "services"
+	completeUnits    = "units"
+	completeMachines = "machines"
+	completeTargets  = "targets" // units and machines
+)
+
+var completionKinds = []string{
+	completeServices,
+	completeUnits,
+	completeMachines,
+	completeTargets,
+}
+
+// completionCacheTTL is how long entity names fetched from the API
+// are used to complete commands before they are fetched again.
+const completionCacheTTL = 30 * time.Second
+
+// superCommands holds the names of the commands whose first argument
+// is a subcommand name.
+var superCommands = []string{
+	"action",
+	"backups",
+	"block",
+	"cached-images",
+	"environment",
+	"machine",
+	"service",
+	"space",
+	"storage",
+	"subnet",
+	"system",
+	"user",
+}
+
+// commandEntities maps commands (and supercommand subcommands) to the
+// kind of entity names that their arguments are completed with.
+var commandEntities = map[string]string{
+	"add-relation":      completeServices,
+	"add-unit":          completeServices,
+	"debug-hooks":       completeUnits,
+	"debug-log":         completeTargets,
+	"destroy-machine":   completeMachines,
+	"destroy-relation":  completeServices,
+	"destroy-service":   completeServices,
+	"destroy-unit":      completeUnits,
+	"expose":            completeServices,
+	"get":               completeServices,
+	"get-constraints":   completeServices,
+	"machine remove":    completeMachines,
+	"remove-machine":    completeMachines,
+	"remove-relation":   completeServices,
+	"remove-service":    completeServices,
+	"remove-unit":       completeUnits,
+	"resolved":          completeUnits,
+	"scp":               completeTargets,
+	"service add-unit":  completeServices,
+	"service get":       completeServices,
+	"service set":       completeServices,
+	"service unset":     completeServices,
+	"set":               completeServices,
+	"ssh":               completeTargets,
+	"status":            completeServices,
+	"terminate-machine": completeMachines,
+	"unexpose":          completeServices,
+	"unset":             completeServices,
+	"upgrade-charm":     completeServices,
+}
+
+const bashCompletionScript = `# bash completion for juju; generated by "juju completion bash".
+
+_juju_complete()
+{
+    local cur key words
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    if [ $COMP_CWORD -eq 1 ]; then
+        words=$(juju help commands 2>/dev/null | awk '{print $1}')
+    else
+        key="${COMP_WORDS[1]}"
+        case "$key" in
+        %s)
+            if [ $COMP_CWORD -eq 2 ]; then
+                words=$(juju $key help commands 2>/dev/null | awk '{print $1}')
+                COMPREPLY=($(compgen -W "$words" -- "$cur"))
+                return 0
+            fi
+            key="$key ${COMP_WORDS[2]}"
+            ;;
+        esac
+        case "$key" in
+%s        esac
+    fi
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+    return 0
+}
+
+complete -F _juju_complete juju
+`
+
+const zshCompletionScript = `# zsh completion for juju; generated by "juju completion zsh".
+
+autoload -U +X bashcompinit && bashcompinit
+`
+
+// CompletionScript returns a script that, when sourced by the named
+// shell, completes juju command names and the names of the services,
+// units and machines in the current environment.
+func CompletionScript(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return bashCompletion(), nil
+	case "zsh":
+		return zshCompletionScript + bashCompletion(), nil
+	}
+	return "", errors.NotSupportedf("shell %q", shell)
+}
+
+func bashCompletion() string {
+	// Group commands by the kind of entity they complete,
+	// so each kind gets a single case arm.
+	byKind := make(map[string][]string)
+	for command, kind := range commandEntities {
+		byKind[kind] = append(byKind[kind], fmt.Sprintf("%q", command))
+	}
+	var cases bytes.Buffer
+	for _, kind := range completionKinds {
+		commands := byKind[kind]
+		if len(commands) == 0 {
+			continue
+		}
+		sort.Strings(commands)
+		fmt.Fprintf(&cases, "        %s)\n", strings.Join(commands, "|"))
+		fmt.Fprintf(&cases, "            words=$(juju complete-entities %s 2>/dev/null)\n", kind)
+		fmt.Fprintf(&cases, "            ;;\n")
+	}
+	return fmt.Sprintf(bashCompletionScript, strings.Join(superCommands, "|"), cases.String())
+}
+
+const completionDoc = `
+Prints a script that enables completion of juju command names, and of
+the names of services, units and machines in the current environment,
+for the specified shell (bash or zsh).
+
+To enable completion in the current shell:
+
+    source <(juju completion bash)
+
+Entity names are fetched from the API server and cached for a short
+time in $JUJU_HOME, so that completion does not connect to the API
+server on every keypress.
+`
+
+// NewCompletionCommand returns a command that prints a shell
+// completion script.
+func NewCompletionCommand() cmd.Command {
+	return &CompletionCommand{}
+}
+
+// CompletionCommand prints a shell completion script.
+type CompletionCommand struct {
+	cmd.CommandBase
+	Shell string
+}
+
+func (c *CompletionCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "completion",
+		Args:    "bash|zsh",
+		Purpose: "print a shell completion script",
+		Doc:     completionDoc,
+	}
+}
+
+func (c *CompletionCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no shell specified")
+	}
+	c.Shell, args = args[0], args[1:]
+	if _, err := CompletionScript(c.Shell); err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *CompletionCommand) Run(ctx *cmd.Context) error {
+	script, err := CompletionScript(c.Shell)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(ctx.Stdout, script)
+	return err
+}
+
+const completeEntitiesDoc = `
+Prints the names of entities of the specified kind (services, units,
+machines or targets, which are units and machines) in the environment,
+one per line. It is called by the scripts printed by "juju completion",
+and is not intended to be run directly.
+`
+
+// NewCompleteEntitiesCommand returns a command that prints the names
+// of entities in the environment, for use by completion scripts.
+func NewCompleteEntitiesCommand() cmd.Command {
+	return envcmd.Wrap(&CompleteEntitiesCommand{})
+}
+
+// CompleteEntitiesCommand prints the names of entities in the
+// environment, for use by completion scripts.
+type CompleteEntitiesCommand struct {
+	envcmd.EnvCommandBase
+	Kind string
+	api  CompletionAPI
+}
+
+// CompletionAPI defines the methods on the client API that the
+// complete-entities command calls.
+type CompletionAPI interface {
+	Close() error
+	Status(patterns []string) (*params.FullStatus, error)
+}
+
+func (c *CompleteEntitiesCommand) getAPI() (CompletionAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+func (c *CompleteEntitiesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "complete-entities",
+		Args:    strings.Join(completionKinds, "|"),
+		Purpose: "print entity names for shell completion (internal use only)",
+		Doc:     completeEntitiesDoc,
+	}
+}
+
+func (c *CompleteEntitiesCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no entity kind specified")
+	}
+	c.Kind, args = args[0], args[1:]
+	if !set.NewStrings(completionKinds...).Contains(c.Kind) {
+		return errors.Errorf("unknown entity kind %q", c.Kind)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *CompleteEntitiesCommand) Run(ctx *cmd.Context) error {
+	path := completionCachePath(c.ConnectionName())
+	entities, err := readCompletionCache(path)
+	if err != nil {
+		logger.Debugf("cannot read completion cache: %v", err)
+	}
+	if entities == nil || time.Since(entities.Updated) > completionCacheTTL {
+		apiclient, err := c.getAPI()
+		if err != nil {
+			return err
+		}
+		defer apiclient.Close()
+		status, err := apiclient.Status(nil)
+		if err != nil {
+			return err
+		}
+		entities = entitiesFromStatus(status)
+		entities.Updated = time.Now()
+		if err := writeCompletionCache(path, entities); err != nil {
+			logger.Debugf("cannot write completion cache: %v", err)
+		}
+	}
+	var names []string
+	switch c.Kind {
+	case completeServices:
+		names = entities.Services
+	case completeUnits:
+		names = entities.Units
+	case completeMachines:
+		names = entities.Machines
+	case completeTargets:
+		names = append(append(names, entities.Units...), entities.Machines...)
+	}
+	for _, name := range names {
+		fmt.Fprintln(ctx.Stdout, name)
+	}
+	return nil
+}
+
+// completionEntities holds the entity names cached for completion.
+type completionEntities struct {
+	Services []string  `json:"services"`
+	Units    []string  `json:"units"`
+	Machines []string  `json:"machines"`
+	Updated  time.Time `json:"updated"`
+}
+
+// entitiesFromStatus returns the names of the services, units and
+// machines (including subordinate units and containers) in status.
+func entitiesFromStatus(status *params.FullStatus) *completionEntities {
+	var entities completionEntities
+	for name, service := range status.Services {
+		entities.Services = append(entities.Services, name)
+		entities.Units = appendUnitNames(entities.Units, service.Units)
+	}
+	entities.Machines = appendMachineIds(entities.Machines, status.Machines)
+	SortStringsNaturally(entities.Services)
+	SortStringsNaturally(entities.Units)
+	SortStringsNaturally(entities.Machines)
+	return &entities
+}
+
+func appendUnitNames(names []string, units map[string]params.UnitStatus) []string {
+	for name, unit := range units {
+		names = append(names, name)
+		names = appendUnitNames(names, unit.Subordinates)
+	}
+	return names
+}
+
+func appendMachineIds(ids []string, machines map[string]params.MachineStatus) []string {
+	for id, machine := range machines {
+		ids = append(ids, id)
+		ids = appendMachineIds(ids, machine.Containers)
+	}
+	return ids
+}
+
+// completionCachePath returns the path of the file in which entity
+// names are cached for the named environment.
+func completionCachePath(envName string) string {
+	return osenv.JujuHomePath("completion", envName+".json")
+}
+
+func readCompletionCache(path string) (*completionEntities, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var entities completionEntities
+	if err := json.Unmarshal(data, &entities); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse %q", path)
+	}
+	return &entities, nil
+}
+
+func writeCompletionCache(path string, entities *completionEntities) error {
+	data, err := json.Marshal(entities)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	return utils.AtomicWriteFile(path, data, 0600)
+}
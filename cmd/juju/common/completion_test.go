@@ -0,0 +1,146 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	cmdcommon "github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/testing"
+)
+
+type CompletionSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeCompletionClient
+}
+
+var _ = gc.Suite(&CompletionSuite{})
+
+type fakeCompletionClient struct {
+	calls  int
+	status *params.FullStatus
+	err    error
+}
+
+func (f *fakeCompletionClient) Close() error {
+	return nil
+}
+
+func (f *fakeCompletionClient) Status(patterns []string) (*params.FullStatus, error) {
+	f.calls++
+	return f.status, f.err
+}
+
+func (s *CompletionSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeCompletionClient{
+		status: &params.FullStatus{
+			Machines: map[string]params.MachineStatus{
+				"0":  {},
+				"10": {},
+				"2": {
+					Containers: map[string]params.MachineStatus{
+						"2/lxc/0": {},
+					},
+				},
+			},
+			Services: map[string]params.ServiceStatus{
+				"wordpress": {
+					Units: map[string]params.UnitStatus{
+						"wordpress/0": {
+							Subordinates: map[string]params.UnitStatus{
+								"logging/0": {},
+							},
+						},
+					},
+				},
+				"mysql": {
+					Units: map[string]params.UnitStatus{
+						"mysql/1": {},
+					},
+				},
+				"logging": {},
+			},
+		},
+	}
+}
+
+func (s *CompletionSuite) runComplete(c *gc.C, args ...string) string {
+	ctx, err := testing.RunCommand(c, cmdcommon.NewCompleteEntitiesCommandWithAPI(s.fake), args...)
+	c.Assert(err, jc.ErrorIsNil)
+	return testing.Stdout(ctx)
+}
+
+func (s *CompletionSuite) TestCompleteEntities(c *gc.C) {
+	c.Check(s.runComplete(c, "services"), gc.Equals, "logging\nmysql\nwordpress\n")
+	c.Check(s.runComplete(c, "units"), gc.Equals, "logging/0\nmysql/1\nwordpress/0\n")
+	c.Check(s.runComplete(c, "machines"), gc.Equals, "0\n2\n10\n2/lxc/0\n")
+	c.Check(s.runComplete(c, "targets"), gc.Equals, "logging/0\nmysql/1\nwordpress/0\n0\n2\n10\n2/lxc/0\n")
+	// Entity names are cached between calls.
+	c.Check(s.fake.calls, gc.Equals, 1)
+}
+
+func (s *CompletionSuite) TestCompleteEntitiesWritesCache(c *gc.C) {
+	s.runComplete(c, "services")
+	path := cmdcommon.CompletionCachePath("erewhemos")
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, `"services":["logging","mysql","wordpress"]`)
+}
+
+func (s *CompletionSuite) TestCompleteEntitiesIgnoresCorruptCache(c *gc.C) {
+	path := cmdcommon.CompletionCachePath("erewhemos")
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path, []byte("not json"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.runComplete(c, "services"), gc.Equals, "logging\nmysql\nwordpress\n")
+	c.Check(s.fake.calls, gc.Equals, 1)
+}
+
+func (s *CompletionSuite) TestCompleteEntitiesAPIError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := testing.RunCommand(c, cmdcommon.NewCompleteEntitiesCommandWithAPI(s.fake), "units")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *CompletionSuite) TestCompleteEntitiesInit(c *gc.C) {
+	_, err := testing.RunCommand(c, cmdcommon.NewCompleteEntitiesCommandWithAPI(s.fake))
+	c.Assert(err, gc.ErrorMatches, "no entity kind specified")
+	_, err = testing.RunCommand(c, cmdcommon.NewCompleteEntitiesCommandWithAPI(s.fake), "relations")
+	c.Assert(err, gc.ErrorMatches, `unknown entity kind "relations"`)
+}
+
+func (s *CompletionSuite) TestCompletionScript(c *gc.C) {
+	script, err := cmdcommon.CompletionScript("bash")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(script, jc.Contains, "complete -F _juju_complete juju")
+	c.Check(script, jc.Contains, `"expose"|`)
+	c.Check(script, jc.Contains, "juju complete-entities services")
+	c.Check(script, jc.Contains, "juju complete-entities targets")
+
+	script, err = cmdcommon.CompletionScript("zsh")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(script, jc.Contains, "bashcompinit")
+	c.Check(script, jc.Contains, "complete -F _juju_complete juju")
+
+	_, err = cmdcommon.CompletionScript("fish")
+	c.Assert(err, gc.ErrorMatches, `shell "fish" not supported`)
+}
+
+func (s *CompletionSuite) TestCompletionCommand(c *gc.C) {
+	ctx, err := testing.RunCommand(c, cmdcommon.NewCompletionCommand(), "bash")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), jc.Contains, "_juju_complete()")
+
+	_, err = testing.RunCommand(c, cmdcommon.NewCompletionCommand())
+	c.Assert(err, gc.ErrorMatches, "no shell specified")
+}
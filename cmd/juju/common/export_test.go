@@ -22,3 +22,13 @@ func NewSetConstraintsCommandWithAPI(api ConstraintsAPI) cmd.Command {
 		api: api,
 	})
 }
+
+// NewCompleteEntitiesCommandWithAPI returns a CompleteEntitiesCommand
+// with the api provided as specified.
+func NewCompleteEntitiesCommandWithAPI(api CompletionAPI) cmd.Command {
+	return envcmd.Wrap(&CompleteEntitiesCommand{
+		api: api,
+	})
+}
+
+var CompletionCachePath = completionCachePath
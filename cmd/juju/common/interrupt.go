@@ -0,0 +1,34 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"os"
+
+	"github.com/juju/cmd"
+	"golang.org/x/net/context"
+)
+
+// InterruptContext returns a context that is cancelled when the
+// command is interrupted, for example by the user pressing Ctrl-C.
+// The returned function stops watching for interrupts and cancels the
+// context; it must be called once the context is no longer needed.
+func InterruptContext(ctx *cmd.Context) (context.Context, func()) {
+	stdctx, cancel := context.WithCancel(context.Background())
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupted:
+			cancel()
+		case <-stop:
+		}
+	}()
+	return stdctx, func() {
+		ctx.StopInterruptNotify(interrupted)
+		close(stop)
+		cancel()
+	}
+}
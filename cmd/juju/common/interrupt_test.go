@@ -0,0 +1,23 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"golang.org/x/net/context"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/testing"
+)
+
+type InterruptSuite struct{}
+
+var _ = gc.Suite(&InterruptSuite{})
+
+func (s *InterruptSuite) TestStopCancelsContext(c *gc.C) {
+	ctx, stop := common.InterruptContext(testing.Context(c))
+	c.Assert(ctx.Err(), gc.IsNil)
+	stop()
+	c.Assert(ctx.Err(), gc.Equals, context.Canceled)
+}
@@ -0,0 +1,147 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"bytes"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// Table holds values to be shown in aligned columns under a row of
+// headings, as commands do for their tabular output format.
+type Table struct {
+	// Headings holds the heading of each column.
+	Headings []string
+
+	// Rows holds the values in each row, one for each heading.
+	Rows [][]string
+
+	// Padding holds the number of spaces between columns. If it
+	// is zero, two spaces are used.
+	Padding int
+}
+
+// NewTable returns a table with the given column headings.
+func NewTable(headings ...string) *Table {
+	return &Table{Headings: headings}
+}
+
+// AddRow adds a row holding the given values to the table.
+func (t *Table) AddRow(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// Format returns the table aligned in columns. If columns is not empty,
+// only the columns it names are included, in the order given.
+func (t *Table) Format(columns Columns) ([]byte, error) {
+	indexes := make([]int, len(t.Headings))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	if len(columns) > 0 {
+		indexes = indexes[:0]
+		for _, column := range columns {
+			i := t.columnIndex(column)
+			if i < 0 {
+				return nil, errors.Errorf("unknown column %q (expected one of %s)", column, t.columnNames())
+			}
+			indexes = append(indexes, i)
+		}
+	}
+	padding := t.Padding
+	if padding == 0 {
+		padding = 2
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, padding, ' ', 0)
+	writeRow := func(values []string) {
+		cells := make([]string, len(indexes))
+		for i, index := range indexes {
+			if index < len(values) {
+				cells[i] = values[index]
+			}
+		}
+		tw.Write([]byte(strings.Join(cells, "\t") + "\n"))
+	}
+	writeRow(t.Headings)
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	if err := tw.Flush(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Bytes(), nil
+}
+
+// columnIndex returns the index of the column with the given name, or
+// -1 if there is none.
+func (t *Table) columnIndex(name string) int {
+	name = normaliseColumn(name)
+	for i, heading := range t.Headings {
+		if normaliseColumn(heading) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// columnNames returns the names by which the table's columns may be
+// selected, as a comma-separated list.
+func (t *Table) columnNames() string {
+	names := make([]string, len(t.Headings))
+	for i, heading := range t.Headings {
+		names[i] = strings.Replace(strings.ToLower(heading), " ", "-", -1)
+	}
+	return strings.Join(names, ", ")
+}
+
+// normaliseColumn returns the given column name or heading in a form
+// in which case and word separators do not matter.
+func normaliseColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("-", " ", "_", " ").Replace(name)
+}
+
+// Columns holds the names of the columns selected for tabular output.
+// It implements gnuflag.Value, so that it can be used for a --columns
+// flag.
+type Columns []string
+
+// AddFlag adds a --columns flag, setting c, to the given flag set.
+func (c *Columns) AddFlag(f *gnuflag.FlagSet) {
+	f.Var(c, "columns", "comma-separated list of columns to show in tabular output")
+}
+
+// Set implements gnuflag.Value.
+func (c *Columns) Set(value string) error {
+	var columns Columns
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			columns = append(columns, name)
+		}
+	}
+	if len(columns) == 0 {
+		return errors.New("no columns specified")
+	}
+	*c = columns
+	return nil
+}
+
+// String implements gnuflag.Value.
+func (c *Columns) String() string {
+	return strings.Join(*c, ",")
+}
+
+// CheckFormat returns an error if columns have been selected but the
+// given output format is not tabular.
+func (c Columns) CheckFormat(format string) error {
+	if len(c) > 0 && format != "tabular" {
+		return errors.Errorf("--columns cannot be used with the %s format", format)
+	}
+	return nil
+}
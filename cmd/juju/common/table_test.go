@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/juju/common"
+)
+
+type TableSuite struct{}
+
+var _ = gc.Suite(&TableSuite{})
+
+func (s *TableSuite) newTable() *common.Table {
+	table := common.NewTable("NAME", "DISPLAY NAME", "STATUS")
+	table.AddRow("adam", "Adam Zulu", "active")
+	table.AddRow("barbara", "Barbara Yellow", "disabled")
+	return table
+}
+
+func (s *TableSuite) TestFormat(c *gc.C) {
+	out, err := s.newTable().Format(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(out), gc.Equals, ""+
+		"NAME     DISPLAY NAME    STATUS\n"+
+		"adam     Adam Zulu       active\n"+
+		"barbara  Barbara Yellow  disabled\n",
+	)
+}
+
+func (s *TableSuite) TestFormatPadding(c *gc.C) {
+	table := s.newTable()
+	table.Padding = 1
+	out, err := table.Format(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(out), gc.Equals, ""+
+		"NAME    DISPLAY NAME   STATUS\n"+
+		"adam    Adam Zulu      active\n"+
+		"barbara Barbara Yellow disabled\n",
+	)
+}
+
+func (s *TableSuite) TestFormatColumns(c *gc.C) {
+	for i, columns := range []common.Columns{
+		{"status", "display-name"},
+		{"STATUS", "display_name"},
+		{"Status", "Display Name"},
+	} {
+		c.Logf("test %d: %v", i, columns)
+		out, err := s.newTable().Format(columns)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(string(out), gc.Equals, ""+
+			"STATUS    DISPLAY NAME\n"+
+			"active    Adam Zulu\n"+
+			"disabled  Barbara Yellow\n",
+		)
+	}
+}
+
+func (s *TableSuite) TestFormatUnknownColumn(c *gc.C) {
+	_, err := s.newTable().Format(common.Columns{"name", "email"})
+	c.Assert(err, gc.ErrorMatches, `unknown column "email" \(expected one of name, display-name, status\)`)
+}
+
+func (s *TableSuite) TestColumnsFlag(c *gc.C) {
+	var columns common.Columns
+	f := gnuflag.NewFlagSet("test", gnuflag.ContinueOnError)
+	columns.AddFlag(f)
+	err := f.Parse(true, []string{"--columns", "name, status,"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(columns, jc.DeepEquals, common.Columns{"name", "status"})
+	c.Assert(columns.String(), gc.Equals, "name,status")
+
+	err = columns.Set(" , ")
+	c.Assert(err, gc.ErrorMatches, "no columns specified")
+}
+
+func (s *TableSuite) TestColumnsCheckFormat(c *gc.C) {
+	var columns common.Columns
+	c.Assert(columns.CheckFormat("json"), jc.ErrorIsNil)
+	columns = common.Columns{"name"}
+	c.Assert(columns.CheckFormat("tabular"), jc.ErrorIsNil)
+	c.Assert(columns.CheckFormat("json"), gc.ErrorMatches, "--columns cannot be used with the json format")
+}
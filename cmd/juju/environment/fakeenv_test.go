@@ -31,6 +31,7 @@ type fakeEnvAPI struct {
 	err         error
 	keys        []string
 	addUsers    []names.UserTag
+	addAccess   string
 	removeUsers []names.UserTag
 }
 
@@ -52,7 +53,8 @@ func (f *fakeEnvAPI) EnvironmentUnset(keys ...string) error {
 	return f.err
 }
 
-func (f *fakeEnvAPI) ShareEnvironment(users ...names.UserTag) error {
+func (f *fakeEnvAPI) ShareEnvironmentWithAccess(access string, users ...names.UserTag) error {
+	f.addAccess = access
 	f.addUsers = users
 	return f.err
 }
@@ -11,6 +11,7 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs"
 )
 
 func newGetCommand() cmd.Command {
@@ -33,6 +34,8 @@ for the environment are output using the selected formatter.
 A single environment value can be output by adding the environment key name to
 the end of the command line.
 
+The values of secret keys, such as credentials, are not shown.
+
 Example:
   
   juju environment get default-series  (returns the default series for the environment)
@@ -79,6 +82,7 @@ func (c *getCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	attrs = environs.RedactSecrets(attrs)
 
 	if c.key != "" {
 		if value, found := attrs[c.key]; found {
@@ -72,3 +72,19 @@ func (s *GetSuite) TestAllValuesJSON(c *gc.C) {
 	expected := `{"name":"test-env","running":true,"special":"special value"}`
 	c.Assert(output, gc.Equals, expected)
 }
+
+func (s *GetSuite) TestSecretsRedacted(c *gc.C) {
+	s.fake.values["type"] = "dummy"
+	s.fake.values["secret"] = "pork"
+	s.fake.values["admin-secret"] = "fish"
+
+	context, err := s.run(c, "--format=json", "secret")
+	c.Assert(err, jc.ErrorIsNil)
+	output := strings.TrimSpace(testing.Stdout(context))
+	c.Assert(output, gc.Equals, `"<redacted>"`)
+
+	context, err = s.run(c, "--format=json", "admin-secret")
+	c.Assert(err, jc.ErrorIsNil)
+	output = strings.TrimSpace(testing.Stdout(context))
+	c.Assert(output, gc.Equals, `"<redacted>"`)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
@@ -24,6 +25,7 @@ func newRetryProvisioningCommand() cmd.Command {
 type retryProvisioningCommand struct {
 	envcmd.EnvCommandBase
 	Machines []names.MachineTag
+	Force    bool
 	api      RetryProvisioningAPI
 }
 
@@ -32,16 +34,35 @@ type retryProvisioningCommand struct {
 type RetryProvisioningAPI interface {
 	Close() error
 	RetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error)
+	ForceRetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error)
 }
 
+const retryProvisioningDoc = `
+Tell the provisioner to try again to start an instance for each of the
+given machines, after a provisioning error caused by a transient cloud
+failure. Only machines in an error state that have no instance can be
+retried.
+
+With --force, a machine whose instance was started but whose agent never
+came up can also be retried: its instance is stopped and forgotten, and a
+new one started in its place. Machines whose agents have started, or that
+have storage attached, must be removed with remove-machine and replaced
+with add-machine instead.
+`
+
 func (c *retryProvisioningCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "retry-provisioning",
 		Args:    "<machine> [...]",
 		Purpose: "retries provisioning for failed machines",
+		Doc:     retryProvisioningDoc,
 	}
 }
 
+func (c *retryProvisioningCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Force, "force", false, "stop and replace the failed instances of provisioned machines")
+}
+
 func (c *retryProvisioningCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.Errorf("no machine specified")
@@ -73,8 +94,14 @@ func (c *retryProvisioningCommand) Run(context *cmd.Context) error {
 	}
 	defer client.Close()
 
-	results, err := client.RetryProvisioning(c.Machines...)
-	if err != nil {
+	retry := client.RetryProvisioning
+	if c.Force {
+		retry = client.ForceRetryProvisioning
+	}
+	results, err := retry(c.Machines...)
+	if errors.IsNotImplemented(err) {
+		return errors.New("--force is not supported by this environment")
+	} else if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 	for _, result := range results {
@@ -30,8 +30,10 @@ var _ = gc.Suite(&retryProvisioningSuite{})
 // about machines in the environment to mock out the behavior
 // of the real RetryProvisioning command.
 type fakeRetryProvisioningClient struct {
-	m   map[string]fakeMachine
-	err error
+	m        map[string]fakeMachine
+	err      error
+	forceErr error
+	forced   []names.MachineTag
 }
 
 type fakeMachine struct {
@@ -75,6 +77,16 @@ func (f *fakeRetryProvisioningClient) RetryProvisioning(machines ...names.Machin
 	return results, nil
 }
 
+func (f *fakeRetryProvisioningClient) ForceRetryProvisioning(machines ...names.MachineTag) (
+	[]params.ErrorResult, error) {
+
+	if f.forceErr != nil {
+		return nil, f.forceErr
+	}
+	f.forced = append(f.forced, machines...)
+	return make([]params.ErrorResult, len(machines)), nil
+}
+
 func (s *retryProvisioningSuite) SetUpTest(c *gc.C) {
 	s.FakeJujuHomeSuite.SetUpTest(c)
 
@@ -159,3 +171,21 @@ func (s *retryProvisioningSuite) TestBlockRetryProvisioning(c *gc.C) {
 		c.Check(stripped, gc.Matches, ".*TestBlockRetryProvisioning.*")
 	}
 }
+
+func (s *retryProvisioningSuite) TestRetryProvisioningForce(c *gc.C) {
+	command := environment.NewRetryProvisioningCommand(s.fake)
+	_, err := testing.RunCommand(c, command, "--force", "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.forced, jc.DeepEquals, []names.MachineTag{
+		names.NewMachineTag("1"),
+		names.NewMachineTag("2"),
+	})
+	c.Assert(s.fake.m["1"].data["transient"], gc.IsNil)
+}
+
+func (s *retryProvisioningSuite) TestRetryProvisioningForceNotSupported(c *gc.C) {
+	s.fake.forceErr = errors.NotImplementedf("ForceRetryProvisioning() (need V2+)")
+	command := environment.NewRetryProvisioningCommand(s.fake)
+	_, err := testing.RunCommand(c, command, "--force", "1")
+	c.Assert(err, gc.ErrorMatches, "--force is not supported by this environment")
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -17,15 +19,24 @@ import (
 const shareEnvHelpDoc = `
 Share the current environment with another user.
 
+Users are given one of the following levels of access, read by default:
+ admin   may do anything, including sharing and destroying the environment
+ write   may change the environment, e.g. deploying and destroying services
+ read    may look at the environment, e.g. with "juju status", but not change it
+
 Examples:
  juju environment share joe
-     Give local user "joe" access to the current environment
+     Give local user "joe" read-only access to the current environment
 
  juju environment share user1 user2 user3@ubuntuone
-     Give two local users and one remote user access to the current environment
+     Give two local users and one remote user read-only access to the
+     current environment
 
  juju environment share sam --environment myenv
-     Give local user "sam" access to the environment named "myenv"
+     Give local user "sam" read-only access to the environment named "myenv"
+
+ juju environment share --access admin ops
+     Give local user "ops" administrator access to the current environment
  `
 
 func newShareCommand() cmd.Command {
@@ -40,6 +51,9 @@ type shareCommand struct {
 
 	// Users to share the environment with.
 	Users []names.UserTag
+
+	// Access is the level of access the users are given.
+	Access string
 }
 
 // Info implements Command.Info.
@@ -52,10 +66,20 @@ func (c *shareCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *shareCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Access, "access", params.EnvReadAccess, "access level: admin, write or read")
+}
+
 func (c *shareCommand) Init(args []string) (err error) {
 	if len(args) == 0 {
 		return errors.New("no users specified")
 	}
+	switch c.Access {
+	case params.EnvAdminAccess, params.EnvWriteAccess, params.EnvReadAccess:
+	default:
+		return errors.Errorf("invalid access level: %q", c.Access)
+	}
 
 	for _, arg := range args {
 		if !names.IsValidUser(arg) {
@@ -77,7 +101,7 @@ func (c *shareCommand) getAPI() (ShareEnvironmentAPI, error) {
 // ShareEnvironmentAPI defines the API functions used by the environment share command.
 type ShareEnvironmentAPI interface {
 	Close() error
-	ShareEnvironment(...names.UserTag) error
+	ShareEnvironmentWithAccess(string, ...names.UserTag) error
 }
 
 func (c *shareCommand) Run(ctx *cmd.Context) error {
@@ -87,5 +111,5 @@ func (c *shareCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	return block.ProcessBlockedError(client.ShareEnvironment(c.Users...), block.BlockChange)
+	return block.ProcessBlockedError(client.ShareEnvironmentWithAccess(c.Access, c.Users...), block.BlockChange)
 }
@@ -38,6 +38,9 @@ func (s *shareSuite) TestInit(c *gc.C) {
 
 	err = testing.InitCommand(wrappedCmd, []string{"not valid/0"})
 	c.Assert(err, gc.ErrorMatches, `invalid username: "not valid/0"`)
+
+	err = testing.InitCommand(wrappedCmd, []string{"--access", "superuser", "bob"})
+	c.Assert(err, gc.ErrorMatches, `invalid access level: "superuser"`)
 }
 
 func (s *shareSuite) TestPassesValues(c *gc.C) {
@@ -47,6 +50,14 @@ func (s *shareSuite) TestPassesValues(c *gc.C) {
 	_, err := s.run(c, "sam", "ralph")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(s.fake.addUsers, jc.DeepEquals, []names.UserTag{sam, ralph})
+	c.Assert(s.fake.addAccess, gc.Equals, "read")
+}
+
+func (s *shareSuite) TestPassesAccess(c *gc.C) {
+	_, err := s.run(c, "--access", "write", "sam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.addUsers, jc.DeepEquals, []names.UserTag{names.NewUserTag("sam")})
+	c.Assert(s.fake.addAccess, gc.Equals, "write")
 }
 
 func (s *shareSuite) TestBlockShare(c *gc.C) {
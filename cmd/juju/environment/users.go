@@ -34,6 +34,7 @@ type usersCommand struct {
 // UserInfo defines the serialization behaviour of the user information.
 type UserInfo struct {
 	Username       string `yaml:"user-name" json:"user-name"`
+	Access         string `yaml:"access" json:"access"`
 	DateCreated    string `yaml:"date-created" json:"date-created"`
 	LastConnection string `yaml:"last-connection" json:"last-connection"`
 }
@@ -102,9 +103,9 @@ func (c *usersCommand) formatTabular(value interface{}) ([]byte, error) {
 		flags    = 0
 	)
 	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
-	fmt.Fprintf(tw, "NAME\tDATE CREATED\tLAST CONNECTION\n")
+	fmt.Fprintf(tw, "NAME\tACCESS\tDATE CREATED\tLAST CONNECTION\n")
 	for _, user := range users {
-		fmt.Fprintf(tw, "%s\t%s\t%s\n", user.Username, user.DateCreated, user.LastConnection)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", user.Username, user.Access, user.DateCreated, user.LastConnection)
 	}
 	tw.Flush()
 	return out.Bytes(), nil
@@ -113,7 +114,7 @@ func (c *usersCommand) formatTabular(value interface{}) ([]byte, error) {
 func (c *usersCommand) apiUsersToUserInfoSlice(users []params.EnvUserInfo) []UserInfo {
 	var output []UserInfo
 	for _, info := range users {
-		outInfo := UserInfo{Username: info.UserName}
+		outInfo := UserInfo{Username: info.UserName, Access: info.Access}
 		outInfo.DateCreated = user.UserFriendlyDuration(info.DateCreated, time.Now())
 		if info.LastConnection != nil {
 			outInfo.LastConnection = user.UserFriendlyDuration(*info.LastConnection, time.Now())
@@ -99,7 +99,52 @@ instance-type
    Instance-type is the provider-specific name of a type of machine to deploy,
    for example m1.small on EC2 or A4 on Azure.  Specifying this constraint may
    conflict with other constraints depending on the provider (since the instance
-   type my determine things like memory size etc.)
+   type my determine things like memory size etc.)  Use list-instance-types to
+   see the instance types available, and which one given constraints select.
+
+zones
+   Zones defines the list of availability zones a machine may be started in,
+   delimited by commas. Machines are spread across the listed zones as they
+   would be across all zones otherwise. Zones are currently supported by the
+   EC2, OpenStack, GCE and MaaS environments.
+
+   Example: zones=us-east-1a,us-east-1c
+
+virt-type
+   Virt-type defines the kind of virtualisation the machine must use or
+   support. On EC2 it selects between "hvm" and "pv" instance types and
+   images. On MaaS, "kvm" selects nodes tagged "kvm", which must be able to
+   host KVM guests. The local provider accepts "lxc" and "kvm" to choose
+   the kind of container to start.
+
+profile
+   Profile names a set of constraints defined in the "constraint-profiles"
+   environment setting. The profile's constraints apply to any attributes
+   not given alongside it, so profile=db mem=32G uses the "db" profile but
+   with 32G of memory.
+
+   Example: profile=db
+
+Ranges:
+
+   The cpu-cores, cpu-power, mem and root-disk constraints may also be given
+   as a range of the form min..max, such as mem=4G..16G or cpu-cores=2..8.
+   The machine must then fall within both bounds, and the least expensive
+   matching machine is chosen. This avoids being given a much larger
+   machine than needed on clouds with few instance sizes.
+
+Profiles:
+
+   Constraints used by several services may be defined once, by name, in
+   the environment's "constraint-profiles" setting, for example:
+
+      constraint-profiles:
+         db: mem=16G cpu-cores=8
+         web: instance-type=m3.medium
+
+   and referred to with the profile constraint:
+
+      juju deploy mysql --constraints profile=db
 
 Example:
 
@@ -112,4 +157,5 @@ See Also:
    juju help add-unit
    juju help add-machine
    juju help bootstrap
+   juju help list-instance-types
 `
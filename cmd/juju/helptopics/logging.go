@@ -21,6 +21,13 @@ back to the bootstrap node, and they are accumulated into the all-machines.log
 file.  Each line is prefixed with the source agent tag (also the same as
 the filename without the extension).
 
+When the "db-log" feature flag is enabled, the agents instead send their
+logs to the state server, which stores them in the database in place of
+all-machines.log. Old logs are pruned periodically; the retention of logs
+of each level may be limited with the LOG_RETENTION agent configuration
+value, for example "DEBUG=24h:512,TRACE=1h" keeps DEBUG logs for at most
+a day and 512MB, and TRACE logs for an hour.
+
 Juju has a hierarchical logging system internally, and as a user you can
 control how much information is logged out.
 
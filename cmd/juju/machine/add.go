@@ -10,12 +10,15 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"golang.org/x/net/context"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
@@ -54,7 +57,14 @@ container types are: $CONTAINER_TYPES$.
 Manual provisioning is the process of installing Juju on an existing machine
 and bringing it under Juju's management; currently this requires that the
 machine be running Ubuntu, that it be accessible via SSH, and be running on
-the same network as the API server.
+the same network as the API server. Several machines may be provisioned at
+once by specifying more than one ssh: target; they are provisioned in
+parallel. Each machine is checked for available disk space and connectivity
+to the API server before it is added to the environment.
+
+Machines that are not directly reachable may be provisioned through an SSH
+bastion (jump) host by specifying it with --via, in the format [user@]host.
+The bastion must be able to reach the machines on their SSH port.
 
 It is possible to override or augment constraints by passing provider-specific
 "placement directives" as an argument; these give the provider additional
@@ -70,6 +80,10 @@ Examples:
    juju machine add lxc:4                (starts a new lxc container on machine 4)
    juju machine add --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju machine add ssh:user@10.10.0.3   (manually provisions a machine with ssh)
+   juju machine add ssh:10.10.0.3 ssh:10.10.0.4
+                                         (manually provisions two machines in parallel)
+   juju machine add ssh:10.10.0.3 --via ubuntu@bastion.example.com
+                                         (manually provisions a machine through a bastion)
    juju machine add zone=us-east-1a      (start a machine in zone us-east-1a on AWS)
    juju machine add maas2.name           (acquire machine maas2.name on MAAS)
 
@@ -110,12 +124,18 @@ type addCommand struct {
 	NumMachines int
 	// Disks describes disks that are to be attached to the machine.
 	Disks []storage.Constraints
+	// SSHHosts holds the hosts to provision manually, when more than
+	// one ssh: target is specified.
+	SSHHosts []string
+	// Via, if specified, is an SSH bastion host through which the
+	// ssh: targets are provisioned.
+	Via string
 }
 
 func (c *addCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "add",
-		Args:    "[<container>:machine | <container> | ssh:[user@]host | placement]",
+		Args:    "[<container>:machine | <container> | ssh:[user@]host... | placement]",
 		Purpose: "start a new, empty machine and optionally a container, or add a container to a machine",
 		Doc:     addMachineDoc,
 	}
@@ -126,12 +146,22 @@ func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumMachines, "n", 1, "The number of machines to add")
 	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "additional machine constraints")
 	f.Var(disksFlag{&c.Disks}, "disks", "constraints for disks to attach to the machine")
+	f.StringVar(&c.Via, "via", "", "provision ssh: targets through this SSH bastion host, as [user@]host")
 }
 
 func (c *addCommand) Init(args []string) error {
 	if c.Constraints.Container != nil {
 		return fmt.Errorf("container constraint %q not allowed when adding a machine", *c.Constraints.Container)
 	}
+	if len(args) > 1 && allSSHHosts(args) {
+		if c.NumMachines > 1 {
+			return fmt.Errorf("cannot use -n when specifying ssh: targets")
+		}
+		for _, arg := range args {
+			c.SSHHosts = append(c.SSHHosts, strings.TrimPrefix(arg, sshHostPrefix))
+		}
+		return c.validateVia()
+	}
 	placement, err := cmd.ZeroOrOneArgs(args)
 	if err != nil {
 		return err
@@ -147,9 +177,83 @@ func (c *addCommand) Init(args []string) error {
 	if c.NumMachines > 1 && c.Placement != nil && c.Placement.Directive != "" {
 		return fmt.Errorf("cannot use -n when specifying a placement directive")
 	}
+	return c.validateVia()
+}
+
+// validateVia checks that --via is only specified along with ssh:
+// targets, and that it names a host.
+func (c *addCommand) validateVia() error {
+	if c.Via == "" {
+		return nil
+	}
+	if len(c.SSHHosts) == 0 && (c.Placement == nil || c.Placement.Scope != "ssh") {
+		return fmt.Errorf("--via can only be used when specifying ssh: targets")
+	}
+	if host := c.Via[strings.Index(c.Via, "@")+1:]; host == "" {
+		return fmt.Errorf("invalid --via %q: missing host", c.Via)
+	}
 	return nil
 }
 
+// manualProvisionArgs returns the arguments for manually provisioning
+// the given host.
+func (c *addCommand) manualProvisionArgs(
+	ctx *cmd.Context, client AddMachineAPI, config *config.Config, host string,
+) manual.ProvisionMachineArgs {
+	var apiAddrs []string
+	if endpoint, err := c.ConnectionEndpoint(false); err != nil {
+		logger.Warningf("cannot get API addresses, not checking connectivity: %v", err)
+	} else {
+		apiAddrs = endpoint.Addresses
+	}
+	return manual.ProvisionMachineArgs{
+		Host:         host,
+		Via:          c.Via,
+		Client:       client,
+		Stdin:        ctx.Stdin,
+		Stdout:       ctx.Stdout,
+		Stderr:       ctx.Stderr,
+		APIAddresses: apiAddrs,
+		UpdateBehavior: &params.UpdateBehavior{
+			config.EnableOSRefreshUpdate(),
+			config.EnableOSUpgrade(),
+		},
+	}
+}
+
+// reportProvisionResults reports the outcome of manually provisioning
+// several hosts, returning an error if any of them failed.
+func reportProvisionResults(ctx *cmd.Context, results []manual.ProvisionResult) error {
+	var failed int
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+			continue
+		}
+		ctx.Infof("created machine %v (%s)", result.MachineId, result.Host)
+	}
+	if failed == 0 {
+		return nil
+	}
+	fmt.Fprintf(ctx.Stderr, "failed to provision %d of %d hosts:\n", failed, len(results))
+	for _, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "  %s (%s): %v\n", result.Host, result.Error.Phase, result.Error.Err)
+		}
+	}
+	return cmd.ErrSilent
+}
+
+// allSSHHosts reports whether every argument is an ssh: target.
+func allSSHHosts(args []string) bool {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, sshHostPrefix) {
+			return false
+		}
+	}
+	return true
+}
+
 type AddMachineAPI interface {
 	AddMachines([]params.AddMachineParams) ([]params.AddMachinesResult, error)
 	AddMachines1dot18([]params.AddMachineParams) ([]params.AddMachinesResult, error)
@@ -160,13 +264,38 @@ type AddMachineAPI interface {
 	ProvisioningScript(params.ProvisioningScriptParams) (script string, err error)
 }
 
+// interruptibleClient returns a client that abandons its calls to
+// fetch provisioning scripts, which may take some time, when ctx is
+// done. Its other calls are unaffected, so that machines can still be
+// cleaned up after an interrupted provisioning.
+func interruptibleClient(client AddMachineAPI, ctx context.Context) AddMachineAPI {
+	apiClient, ok := client.(*api.Client)
+	if !ok {
+		return client
+	}
+	return contextProvisioningClient{client, apiClient.WithContext(ctx)}
+}
+
+type contextProvisioningClient struct {
+	AddMachineAPI
+	ctxClient *api.Client
+}
+
+// ProvisioningScript is part of the AddMachineAPI interface.
+func (c contextProvisioningClient) ProvisioningScript(args params.ProvisioningScriptParams) (string, error) {
+	return c.ctxClient.ProvisioningScript(args)
+}
+
 type MachineManagerAPI interface {
 	AddMachines([]params.AddMachineParams) ([]params.AddMachinesResult, error)
 	BestAPIVersion() int
 	Close() error
 }
 
-var manualProvisioner = manual.ProvisionMachine
+var (
+	manualProvisioner         = manual.ProvisionMachine
+	manualProvisionerParallel = manual.ProvisionMachines
+)
 
 func (c *addCommand) getClientAPI() (AddMachineAPI, error) {
 	if c.api != nil {
@@ -217,25 +346,28 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	if (c.Placement != nil && c.Placement.Scope == "ssh") || len(c.SSHHosts) > 0 {
+		stdctx, cancel := common.InterruptContext(ctx)
+		defer cancel()
+		client = interruptibleClient(client, stdctx)
+	}
 	if c.Placement != nil && c.Placement.Scope == "ssh" {
 		logger.Infof("manual provisioning")
-		args := manual.ProvisionMachineArgs{
-			Host:   c.Placement.Directive,
-			Client: client,
-			Stdin:  ctx.Stdin,
-			Stdout: ctx.Stdout,
-			Stderr: ctx.Stderr,
-			UpdateBehavior: &params.UpdateBehavior{
-				config.EnableOSRefreshUpdate(),
-				config.EnableOSUpgrade(),
-			},
-		}
+		args := c.manualProvisionArgs(ctx, client, config, c.Placement.Directive)
 		machineId, err := manualProvisioner(args)
 		if err == nil {
 			ctx.Infof("created machine %v", machineId)
 		}
 		return err
 	}
+	if len(c.SSHHosts) > 0 {
+		logger.Infof("manual provisioning of %d hosts", len(c.SSHHosts))
+		args := make([]manual.ProvisionMachineArgs, len(c.SSHHosts))
+		for i, host := range c.SSHHosts {
+			args[i] = c.manualProvisionArgs(ctx, client, config, host)
+		}
+		return reportProvisionResults(ctx, manualProvisionerParallel(args))
+	}
 
 	logger.Infof("environment provisioning")
 	if c.Placement != nil && c.Placement.Scope == "env-uuid" {
@@ -149,6 +149,93 @@ func (s *AddMachineSuite) TestSSHPlacementError(c *gc.C) {
 	c.Assert(testing.Stderr(context), gc.Equals, "")
 }
 
+func (s *AddMachineSuite) TestInitMultipleSSHHosts(c *gc.C) {
+	wrappedCommand, addCmd := machine.NewAddCommand(s.fakeAddMachine, s.fakeMachineManager)
+	err := testing.InitCommand(wrappedCommand, []string{"ssh:10.1.2.3", "ssh:user@10.1.2.4"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addCmd.SSHHosts, jc.DeepEquals, []string{"10.1.2.3", "user@10.1.2.4"})
+	c.Assert(addCmd.Placement, gc.IsNil)
+
+	wrappedCommand, _ = machine.NewAddCommand(s.fakeAddMachine, s.fakeMachineManager)
+	err = testing.InitCommand(wrappedCommand, []string{"-n", "2", "ssh:10.1.2.3", "ssh:10.1.2.4"})
+	c.Assert(err, gc.ErrorMatches, "cannot use -n when specifying ssh: targets")
+
+	wrappedCommand, _ = machine.NewAddCommand(s.fakeAddMachine, s.fakeMachineManager)
+	err = testing.InitCommand(wrappedCommand, []string{"ssh:10.1.2.3", "lxc"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["lxc"\]`)
+}
+
+func (s *AddMachineSuite) TestInitVia(c *gc.C) {
+	wrappedCommand, addCmd := machine.NewAddCommand(s.fakeAddMachine, s.fakeMachineManager)
+	err := testing.InitCommand(wrappedCommand, []string{"--via", "ubuntu@bastion", "ssh:10.1.2.3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addCmd.Via, gc.Equals, "ubuntu@bastion")
+
+	wrappedCommand, _ = machine.NewAddCommand(s.fakeAddMachine, s.fakeMachineManager)
+	err = testing.InitCommand(wrappedCommand, []string{"--via", "bastion", "ssh:10.1.2.3", "ssh:10.1.2.4"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	wrappedCommand, _ = machine.NewAddCommand(s.fakeAddMachine, s.fakeMachineManager)
+	err = testing.InitCommand(wrappedCommand, []string{"--via", "bastion", "lxc"})
+	c.Assert(err, gc.ErrorMatches, "--via can only be used when specifying ssh: targets")
+
+	wrappedCommand, _ = machine.NewAddCommand(s.fakeAddMachine, s.fakeMachineManager)
+	err = testing.InitCommand(wrappedCommand, []string{"--via", "ubuntu@", "ssh:10.1.2.3"})
+	c.Assert(err, gc.ErrorMatches, `invalid --via "ubuntu@": missing host`)
+}
+
+func (s *AddMachineSuite) TestSSHPlacementVia(c *gc.C) {
+	s.PatchValue(machine.ManualProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		c.Check(args.Host, gc.Equals, "10.1.2.3")
+		c.Check(args.Via, gc.Equals, "ubuntu@bastion")
+		return "42", nil
+	})
+	_, err := s.run(c, "--via", "ubuntu@bastion", "ssh:10.1.2.3")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AddMachineSuite) TestMultipleSSHPlacement(c *gc.C) {
+	s.PatchValue(machine.ManualProvisionerParallel, func(args []manual.ProvisionMachineArgs) []manual.ProvisionResult {
+		c.Assert(args, gc.HasLen, 2)
+		return []manual.ProvisionResult{{
+			Host:      args[0].Host,
+			MachineId: "42",
+		}, {
+			Host:      args[1].Host,
+			MachineId: "43",
+		}}
+	})
+	context, err := s.run(c, "ssh:10.1.2.3", "ssh:10.1.2.4")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(context), gc.Equals, ""+
+		"created machine 42 (10.1.2.3)\n"+
+		"created machine 43 (10.1.2.4)\n",
+	)
+}
+
+func (s *AddMachineSuite) TestMultipleSSHPlacementError(c *gc.C) {
+	s.PatchValue(machine.ManualProvisionerParallel, func(args []manual.ProvisionMachineArgs) []manual.ProvisionResult {
+		return []manual.ProvisionResult{{
+			Host:      "10.1.2.3",
+			MachineId: "42",
+		}, {
+			Host: "10.1.2.4",
+			Error: &manual.HostError{
+				Host:  "10.1.2.4",
+				Phase: manual.PhasePreflight,
+				Err:   errors.New("insufficient disk space: 100MB available, 1024MB required"),
+			},
+		}}
+	})
+	context, err := s.run(c, "ssh:10.1.2.3", "ssh:10.1.2.4")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(context), gc.Equals, ""+
+		"created machine 42 (10.1.2.3)\n"+
+		"failed to provision 1 of 2 hosts:\n"+
+		"  10.1.2.4 (preflight checks): insufficient disk space: 100MB available, 1024MB required\n",
+	)
+}
+
 func (s *AddMachineSuite) TestParamsPassedOn(c *gc.C) {
 	_, err := s.run(c, "--constraints", "mem=8G", "--series=special", "zone=nz")
 	c.Assert(err, jc.ErrorIsNil)
@@ -11,7 +11,8 @@ import (
 )
 
 var (
-	ManualProvisioner = &manualProvisioner
+	ManualProvisioner         = &manualProvisioner
+	ManualProvisionerParallel = &manualProvisionerParallel
 )
 
 type AddCommand struct {
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/network"
+)
+
+const setAddressPolicyDoc = `
+Sets the policy used to select the public-address and private-address of
+the units of a service, overriding the environment's
+address-selection-policy setting. The policy is one of:
+
+    prefer-internal    use cloud-local addresses for both
+    prefer-public      use public addresses for both
+    space:<name>       use addresses in the named network space
+    default            use the environment's address-selection-policy
+
+Example:
+
+    set-address-policy mysql space:db    (mysql units use their addresses in the db space)
+`
+
+func newSetAddressPolicyCommand() cmd.Command {
+	return envcmd.Wrap(&setAddressPolicyCommand{})
+}
+
+// setAddressPolicyCommand sets the address selection policy of a service.
+type setAddressPolicyCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Policy      network.AddressPolicy
+	api         SetAddressPolicyAPI
+}
+
+func (c *setAddressPolicyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-address-policy",
+		Args:    "<service> <policy>",
+		Purpose: "set the address selection policy of a service",
+		Doc:     setAddressPolicyDoc,
+	}
+}
+
+func (c *setAddressPolicyCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service name specified")
+	case 1:
+		return errors.New("no address policy specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return fmt.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	if args[1] != "default" {
+		c.Policy = network.AddressPolicy(args[1])
+	}
+	if err := c.Policy.Validate(); err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+// SetAddressPolicyAPI defines the methods on the client API
+// that the service set-address-policy command calls.
+type SetAddressPolicyAPI interface {
+	Close() error
+	ServiceSetAddressPolicy(service, policy string) error
+}
+
+func (c *setAddressPolicyCommand) getAPI() (SetAddressPolicyAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run sets the address selection policy of a service.
+func (c *setAddressPolicyCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer apiclient.Close()
+	err = apiclient.ServiceSetAddressPolicy(c.ServiceName, string(c.Policy))
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,78 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/service"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type fakeAddressPolicyAPI struct {
+	service string
+	policy  string
+	err     error
+}
+
+func (f *fakeAddressPolicyAPI) Close() error {
+	return nil
+}
+
+func (f *fakeAddressPolicyAPI) ServiceSetAddressPolicy(service, policy string) error {
+	f.service = service
+	f.policy = policy
+	return f.err
+}
+
+type SetAddressPolicySuite struct {
+	coretesting.FakeJujuHomeSuite
+	fake *fakeAddressPolicyAPI
+}
+
+var _ = gc.Suite(&SetAddressPolicySuite{})
+
+func (s *SetAddressPolicySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeAddressPolicyAPI{}
+}
+
+func (s *SetAddressPolicySuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service name specified",
+	}, {
+		args: []string{"mysql"},
+		err:  "no address policy specified",
+	}, {
+		args: []string{"mysql!", "prefer-public"},
+		err:  `invalid service name "mysql!"`,
+	}, {
+		args: []string{"mysql", "prefer-ipv9"},
+		err:  `address policy "prefer-ipv9" not valid`,
+	}, {
+		args: []string{"mysql", "prefer-public", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := coretesting.InitCommand(service.NewSetAddressPolicyCommand(s.fake), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SetAddressPolicySuite) TestRun(c *gc.C) {
+	_, err := coretesting.RunCommand(c, service.NewSetAddressPolicyCommand(s.fake), "mysql", "space:db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.service, gc.Equals, "mysql")
+	c.Assert(s.fake.policy, gc.Equals, "space:db")
+}
+
+func (s *SetAddressPolicySuite) TestRunDefault(c *gc.C) {
+	_, err := coretesting.RunCommand(c, service.NewSetAddressPolicyCommand(s.fake), "mysql", "default")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.policy, gc.Equals, "")
+}
@@ -37,6 +37,14 @@ func NewAddUnitCommand(api ServiceAddUnitAPI) cmd.Command {
 	})
 }
 
+// NewSetAddressPolicyCommand returns a SetAddressPolicyCommand with the api
+// provided as specified.
+func NewSetAddressPolicyCommand(api SetAddressPolicyAPI) cmd.Command {
+	return envcmd.Wrap(&setAddressPolicyCommand{
+		api: api,
+	})
+}
+
 var (
 	NewServiceSetConstraintsCommand = newServiceSetConstraintsCommand
 	NewServiceGetConstraintsCommand = newServiceGetConstraintsCommand
@@ -29,6 +29,7 @@ func NewSuperCommand() cmd.Command {
 	environmentCmd.Register(newServiceSetConstraintsCommand())
 	environmentCmd.Register(newGetCommand())
 	environmentCmd.Register(NewSetCommand())
+	environmentCmd.Register(newSetAddressPolicyCommand())
 	environmentCmd.Register(newUnsetCommand())
 
 	return environmentCmd
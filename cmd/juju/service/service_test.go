@@ -25,6 +25,7 @@ var expectedCommmandNames = []string{
 	"get-constraints",
 	"help",
 	"set",
+	"set-address-policy",
 	"set-constraints",
 	"unset",
 }
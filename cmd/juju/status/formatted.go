@@ -28,19 +28,20 @@ type environmentStatus struct {
 }
 
 type machineStatus struct {
-	Err            error                    `json:"-" yaml:",omitempty"`
-	AgentState     params.Status            `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
-	AgentStateInfo string                   `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
-	AgentVersion   string                   `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
-	DNSName        string                   `json:"dns-name,omitempty" yaml:"dns-name,omitempty"`
-	InstanceId     instance.Id              `json:"instance-id,omitempty" yaml:"instance-id,omitempty"`
-	InstanceState  string                   `json:"instance-state,omitempty" yaml:"instance-state,omitempty"`
-	Life           string                   `json:"life,omitempty" yaml:"life,omitempty"`
-	Series         string                   `json:"series,omitempty" yaml:"series,omitempty"`
-	Id             string                   `json:"-" yaml:"-"`
-	Containers     map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
-	Hardware       string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
-	HAStatus       string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
+	Err               error                    `json:"-" yaml:",omitempty"`
+	AgentState        params.Status            `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
+	AgentStateInfo    string                   `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
+	AgentVersion      string                   `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+	DNSName           string                   `json:"dns-name,omitempty" yaml:"dns-name,omitempty"`
+	InstanceId        instance.Id              `json:"instance-id,omitempty" yaml:"instance-id,omitempty"`
+	InstanceState     string                   `json:"instance-state,omitempty" yaml:"instance-state,omitempty"`
+	InstanceStateInfo string                   `json:"instance-state-info,omitempty" yaml:"instance-state-info,omitempty"`
+	Life              string                   `json:"life,omitempty" yaml:"life,omitempty"`
+	Series            string                   `json:"series,omitempty" yaml:"series,omitempty"`
+	Id                string                   `json:"-" yaml:"-"`
+	Containers        map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Hardware          string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
+	HAStatus          string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -72,6 +73,7 @@ type serviceStatus struct {
 	Relations     map[string][]string   `json:"relations,omitempty" yaml:"relations,omitempty"`
 	Networks      map[string][]string   `json:"networks,omitempty" yaml:"networks,omitempty"`
 	SubordinateTo []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
+	Processes     *processesStatus      `json:"processes,omitempty" yaml:"processes,omitempty"`
 	Units         map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
 }
 
@@ -93,6 +95,14 @@ func (s serviceStatus) MarshalYAML() (interface{}, error) {
 	return noMethods(s), nil
 }
 
+// processesStatus summarises the workload processes of a service's
+// units, so that failed processes can be spotted without inspecting
+// each unit.
+type processesStatus struct {
+	Statuses map[string]int      `json:"statuses" yaml:"statuses"`
+	Failed   map[string][]string `json:"failed,omitempty" yaml:"failed,omitempty"`
+}
+
 type meterStatus struct {
 	Color   string `json:"color,omitempty" yaml:"color,omitempty"`
 	Message string `json:"message,omitempty" yaml:"message,omitempty"`
@@ -116,6 +126,19 @@ type unitStatus struct {
 	OpenedPorts   []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
 	PublicAddress string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
 	Subordinates  map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+	Leader        bool                  `json:"leader,omitempty" yaml:"leader,omitempty"`
+
+	Processes map[string]processStatus `json:"processes,omitempty" yaml:"processes,omitempty"`
+}
+
+// processStatus describes a workload process tracked by a unit. The
+// usage fields are only set when reported by the process's plugin.
+type processStatus struct {
+	Type     string `json:"type" yaml:"type"`
+	Status   string `json:"status" yaml:"status"`
+	CPU      string `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory   string `json:"memory,omitempty" yaml:"memory,omitempty"`
+	Restarts int    `json:"restarts,omitempty" yaml:"restarts,omitempty"`
 }
 
 type statusInfoContents struct {
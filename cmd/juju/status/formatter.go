@@ -6,6 +6,8 @@ package status
 import (
 	"fmt"
 
+	"github.com/dustin/go-humanize"
+
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/state/multiwatcher"
@@ -68,35 +70,37 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 		// Older server
 		// TODO: this will go away at some point (v1.21?).
 		out = machineStatus{
-			AgentState:     machine.AgentState,
-			AgentStateInfo: machine.AgentStateInfo,
-			AgentVersion:   machine.AgentVersion,
-			Life:           machine.Life,
-			Err:            machine.Err,
-			DNSName:        machine.DNSName,
-			InstanceId:     machine.InstanceId,
-			InstanceState:  machine.InstanceState,
-			Series:         machine.Series,
-			Id:             machine.Id,
-			Containers:     make(map[string]machineStatus),
-			Hardware:       machine.Hardware,
+			AgentState:        machine.AgentState,
+			AgentStateInfo:    machine.AgentStateInfo,
+			AgentVersion:      machine.AgentVersion,
+			Life:              machine.Life,
+			Err:               machine.Err,
+			DNSName:           machine.DNSName,
+			InstanceId:        machine.InstanceId,
+			InstanceState:     machine.InstanceState,
+			InstanceStateInfo: machine.InstanceStateInfo,
+			Series:            machine.Series,
+			Id:                machine.Id,
+			Containers:        make(map[string]machineStatus),
+			Hardware:          machine.Hardware,
 		}
 	} else {
 		// New server
 		agent := machine.Agent
 		out = machineStatus{
-			AgentState:     agent.Status,
-			AgentStateInfo: adjustInfoIfMachineAgentDown(machine.AgentState, agent.Status, agent.Info),
-			AgentVersion:   agent.Version,
-			Life:           agent.Life,
-			Err:            agent.Err,
-			DNSName:        machine.DNSName,
-			InstanceId:     machine.InstanceId,
-			InstanceState:  machine.InstanceState,
-			Series:         machine.Series,
-			Id:             machine.Id,
-			Containers:     make(map[string]machineStatus),
-			Hardware:       machine.Hardware,
+			AgentState:        agent.Status,
+			AgentStateInfo:    adjustInfoIfMachineAgentDown(machine.AgentState, agent.Status, agent.Info),
+			AgentVersion:      agent.Version,
+			Life:              agent.Life,
+			Err:               agent.Err,
+			DNSName:           machine.DNSName,
+			InstanceId:        machine.InstanceId,
+			InstanceState:     machine.InstanceState,
+			InstanceStateInfo: machine.InstanceStateInfo,
+			Series:            machine.Series,
+			Id:                machine.Id,
+			Containers:        make(map[string]machineStatus),
+			Hardware:          machine.Hardware,
 		}
 	}
 
@@ -126,6 +130,12 @@ func (sf *statusFormatter) formatService(name string, service params.ServiceStat
 		Units:         make(map[string]unitStatus),
 		StatusInfo:    sf.getServiceStatusInfo(service),
 	}
+	if service.Processes != nil {
+		out.Processes = &processesStatus{
+			Statuses: service.Processes.Statuses,
+			Failed:   service.Processes.Failed,
+		}
+	}
 	if len(service.Networks.Enabled) > 0 {
 		out.Networks["enabled"] = service.Networks.Enabled
 	}
@@ -175,6 +185,7 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 		PublicAddress:      info.unit.PublicAddress,
 		Charm:              info.unit.Charm,
 		Subordinates:       make(map[string]unitStatus),
+		Leader:             info.unit.Leader,
 	}
 
 	if ms, ok := info.meterStatuses[info.unitName]; ok {
@@ -193,6 +204,13 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 		out.AgentVersion = info.unit.AgentVersion
 	}
 
+	for _, p := range info.unit.Processes {
+		if out.Processes == nil {
+			out.Processes = make(map[string]processStatus)
+		}
+		out.Processes[p.Name] = formatProcess(p)
+	}
+
 	for k, m := range info.unit.Subordinates {
 		out.Subordinates[k] = sf.formatUnit(unitFormatInfo{
 			unit:          m,
@@ -204,6 +222,23 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 	return out
 }
 
+func formatProcess(p params.ProcessInfo) processStatus {
+	out := processStatus{
+		Type:   p.Type,
+		Status: p.Details.Status,
+	}
+	if usage := p.Details.Usage; usage != nil {
+		if usage.CPU > 0 {
+			out.CPU = fmt.Sprintf("%.1f%%", usage.CPU)
+		}
+		if usage.Memory > 0 {
+			out.Memory = humanize.IBytes(usage.Memory)
+		}
+		out.Restarts = usage.Restarts
+	}
+	return out
+}
+
 func (sf *statusFormatter) getWorkloadStatusInfo(unit params.UnitStatus) statusInfoContents {
 	info := statusInfoContents{
 		Err:     unit.Workload.Err,
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -19,7 +20,7 @@ import (
 )
 
 // NewStatusHistoryCommand returns a command that reports the history
-// of status changes for the specified unit.
+// of status changes for the specified unit, machine or service.
 func NewStatusHistoryCommand() cmd.Command {
 	return envcmd.Wrap(&statusHistoryCommand{})
 }
@@ -30,31 +31,44 @@ type statusHistoryCommand struct {
 	outputContent string
 	backlogSize   int
 	isoTime       bool
-	unitName      string
+	entityName    string
 }
 
 var statusHistoryDoc = `
 This command will report the history of status changes for
-a given unit.
+a given unit, machine or service.
 The statuses for the unit workload and/or agent are available.
 -type supports:
     agent: will show statuses for the unit's agent
     workload: will show statuses for the unit's workload
     combined: will show agent and workload statuses combined
  and sorted by time of occurrence.
+For a machine, the statuses for the machine agent and/or the
+provider instance are available.
+-type supports:
+    machine: will show statuses for the machine's agent
+    instance: will show statuses for the machine's instance,
+ as reported by the provider
+    combined: will show machine and instance statuses combined
+ and sorted by time of occurrence.
+For a service, the statuses set for the service as a whole are
+available.
+-type supports:
+    service: will show statuses set for the service
+    combined: the same as service
 `
 
 func (c *statusHistoryCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "status-history",
-		Args:    "[-n N] <unit>",
-		Purpose: "output past statuses for a unit",
+		Args:    "[-n N] <unit>|<machine>|<service>",
+		Purpose: "output past statuses for a unit, machine or service",
 		Doc:     statusHistoryDoc,
 	}
 }
 
 func (c *statusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
-	f.StringVar(&c.outputContent, "type", "combined", "type of statuses to be displayed [agent|workload|combined].")
+	f.StringVar(&c.outputContent, "type", "combined", "type of statuses to be displayed [agent|workload|machine|instance|service|combined].")
 	f.IntVar(&c.backlogSize, "n", 20, "size of logs backlog.")
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
 }
@@ -62,11 +76,11 @@ func (c *statusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
 func (c *statusHistoryCommand) Init(args []string) error {
 	switch {
 	case len(args) > 1:
-		return errors.Errorf("unexpected arguments after unit name.")
+		return errors.Errorf("unexpected arguments after unit, machine or service name.")
 	case len(args) == 0:
-		return errors.Errorf("unit name is missing.")
+		return errors.Errorf("unit, machine or service name is missing.")
 	default:
-		c.unitName = args[0]
+		c.entityName = args[0]
 	}
 	// If use of ISO time not specified on command line,
 	// check env var.
@@ -80,6 +94,20 @@ func (c *statusHistoryCommand) Init(args []string) error {
 		}
 	}
 	kind := params.HistoryKind(c.outputContent)
+	if names.IsValidMachine(c.entityName) {
+		switch kind {
+		case params.KindCombined, params.KindMachine, params.KindMachineInstance:
+			return nil
+		}
+		return errors.Errorf("unexpected status type %q for a machine", c.outputContent)
+	}
+	if names.IsValidService(c.entityName) {
+		switch kind {
+		case params.KindCombined, params.KindService:
+			return nil
+		}
+		return errors.Errorf("unexpected status type %q for a service", c.outputContent)
+	}
 	switch kind {
 	case params.KindCombined, params.KindAgent, params.KindWorkload:
 		return nil
@@ -96,7 +124,13 @@ func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
 	defer apiclient.Close()
 	var statuses *params.UnitStatusHistory
 	kind := params.HistoryKind(c.outputContent)
-	statuses, err = apiclient.UnitStatusHistory(kind, c.unitName, c.backlogSize)
+	if names.IsValidMachine(c.entityName) {
+		statuses, err = apiclient.MachineStatusHistory(kind, c.entityName, c.backlogSize)
+	} else if names.IsValidService(c.entityName) {
+		statuses, err = apiclient.ServiceStatusHistory(kind, c.entityName, c.backlogSize)
+	} else {
+		statuses, err = apiclient.UnitStatusHistory(kind, c.entityName, c.backlogSize)
+	}
 	if err != nil {
 		if len(statuses.Statuses) == 0 {
 			return errors.Trace(err)
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type StatusHistorySuite struct {
+	coretesting.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+func (s *StatusHistorySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args       []string
+		entityName string
+		err        string
+	}{{
+		err: "unit, machine or service name is missing.",
+	}, {
+		args: []string{"mysql/0", "wordpress/0"},
+		err:  "unexpected arguments after unit, machine or service name.",
+	}, {
+		args:       []string{"mysql/0"},
+		entityName: "mysql/0",
+	}, {
+		args:       []string{"--type", "workload", "mysql/0"},
+		entityName: "mysql/0",
+	}, {
+		args: []string{"--type", "instance", "mysql/0"},
+		err:  `unexpected status type "instance"`,
+	}, {
+		args:       []string{"0"},
+		entityName: "0",
+	}, {
+		args:       []string{"--type", "instance", "0/lxc/1"},
+		entityName: "0/lxc/1",
+	}, {
+		args:       []string{"--type", "machine", "0"},
+		entityName: "0",
+	}, {
+		args: []string{"--type", "workload", "0"},
+		err:  `unexpected status type "workload" for a machine`,
+	}, {
+		args:       []string{"mysql"},
+		entityName: "mysql",
+	}, {
+		args:       []string{"--type", "service", "mysql"},
+		entityName: "mysql",
+	}, {
+		args: []string{"--type", "agent", "mysql"},
+		err:  `unexpected status type "agent" for a service`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &statusHistoryCommand{}
+		err := coretesting.InitCommand(envcmd.Wrap(command), test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.entityName, gc.Equals, test.entityName)
+	}
+}
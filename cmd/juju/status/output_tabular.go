@@ -58,6 +58,10 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	tw.Flush()
 
 	pUnit := func(name string, u unitStatus, level int) {
+		// The service leader is marked with an asterisk.
+		if u.Leader {
+			name += "*"
+		}
 		message := u.WorkloadStatusInfo.Message
 		agentDoing := agentDoing(u.AgentStatusInfo)
 		if agentDoing != "" {
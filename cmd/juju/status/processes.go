@@ -0,0 +1,246 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/juju/osenv"
+)
+
+// NewListProcessesCommand returns a command that lists the workload
+// processes tracked by units.
+func NewListProcessesCommand() cmd.Command {
+	return envcmd.Wrap(&listProcessesCommand{})
+}
+
+var listProcessesDoc = `
+This command lists the workload processes tracked by the given units,
+or by all units in the environment if none are given. Units track
+processes from their hooks, with process-launch and process-track.
+
+The listing may be restricted to processes of particular types, or
+with particular statuses; both --type and --status may be repeated,
+or given a comma-separated list of values.
+
+With -n, up to N past statuses of each process are also shown, newest
+first.
+
+Examples:
+    juju list-processes
+    juju list-processes redis/0 --type docker
+    juju list-processes --status exited,unhealthy -n 5
+`
+
+// ListProcessesAPI is the API used by the list-processes command.
+type ListProcessesAPI interface {
+	Close() error
+	ListProcesses(units, types, statuses []string, historySize int) ([]params.UnitProcessesResult, error)
+}
+
+type listProcessesCommand struct {
+	envcmd.EnvCommandBase
+	out         cmd.Output
+	api         ListProcessesAPI
+	units       []string
+	types       []string
+	statuses    []string
+	historySize int
+	isoTime     bool
+}
+
+func (c *listProcessesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-processes",
+		Args:    "[<unit> ...]",
+		Purpose: "list the workload processes tracked by units",
+		Doc:     listProcessesDoc,
+	}
+}
+
+func (c *listProcessesCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewAppendStringsValue(&c.types), "type", "only show processes of these types")
+	f.Var(cmd.NewAppendStringsValue(&c.statuses), "status", "only show processes with these statuses")
+	f.IntVar(&c.historySize, "n", 0, "number of past statuses to show for each process")
+	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatProcessesTabular,
+	})
+}
+
+func (c *listProcessesCommand) Init(args []string) error {
+	for _, arg := range args {
+		if !names.IsValidUnit(arg) {
+			return errors.Errorf("invalid unit name %q", arg)
+		}
+	}
+	c.units = args
+	c.types = splitValues(c.types)
+	c.statuses = splitValues(c.statuses)
+	if c.historySize < 0 {
+		return errors.Errorf("invalid history size %d", c.historySize)
+	}
+	// If use of ISO time not specified on command line,
+	// check env var.
+	if !c.isoTime {
+		var err error
+		envVarValue := os.Getenv(osenv.JujuStatusIsoTimeEnvKey)
+		if envVarValue != "" {
+			if c.isoTime, err = strconv.ParseBool(envVarValue); err != nil {
+				return errors.Annotatef(err, "invalid %s env var, expected true|false", osenv.JujuStatusIsoTimeEnvKey)
+			}
+		}
+	}
+	return nil
+}
+
+// splitValues splits any comma-separated values, dropping empty ones.
+func splitValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+var getListProcessesAPI = func(c *listProcessesCommand) (ListProcessesAPI, error) {
+	return c.NewAPIClient()
+}
+
+func (c *listProcessesCommand) Run(ctx *cmd.Context) error {
+	if c.api == nil {
+		api, err := getListProcessesAPI(c)
+		if err != nil {
+			return errors.Errorf(connectionError, c.ConnectionName(), err)
+		}
+		defer api.Close()
+		c.api = api
+	}
+	results, err := c.api.ListProcesses(c.units, c.types, c.statuses, c.historySize)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	failed := false
+	output := make(map[string]map[string]listedProcess)
+	for _, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "unit %q: %v\n", result.Unit, result.Error)
+			failed = true
+			continue
+		}
+		processes := make(map[string]listedProcess)
+		for _, p := range result.Processes {
+			processes[p.Info.Name] = c.formatListedProcess(p)
+		}
+		output[result.Unit] = processes
+	}
+	if len(output) > 0 {
+		if err := c.out.Write(ctx, output); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+// listedProcess describes a workload process in the output of the
+// list-processes command.
+type listedProcess struct {
+	processStatus `yaml:",inline"`
+	ID            string               `json:"id" yaml:"id"`
+	History       []processStatusEntry `json:"history,omitempty" yaml:"history,omitempty"`
+}
+
+// processStatusEntry describes a past status of a workload process.
+type processStatusEntry struct {
+	Status string `json:"status" yaml:"status"`
+	Since  string `json:"since" yaml:"since"`
+}
+
+func (c *listProcessesCommand) formatListedProcess(p params.ProcessStatus) listedProcess {
+	out := listedProcess{
+		processStatus: formatProcess(p.Info),
+		ID:            p.Info.Details.ID,
+	}
+	for _, h := range p.History {
+		out.History = append(out.History, processStatusEntry{
+			Status: string(h.Status),
+			Since:  common.FormatTime(h.Since, c.isoTime),
+		})
+	}
+	return out
+}
+
+// formatProcessesTabular returns a tabular summary of the processes of
+// each unit, followed by their past statuses if any were requested.
+func formatProcessesTabular(value interface{}) ([]byte, error) {
+	units, ok := value.(map[string]map[string]listedProcess)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", units, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	p := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+
+	unitNames := common.SortStringsNaturally(stringKeysFromMap(units))
+	hasHistory := false
+	p("UNIT", "PROCESS", "TYPE", "ID", "STATUS")
+	for _, unitName := range unitNames {
+		processes := units[unitName]
+		for _, name := range sortedProcessNames(processes) {
+			process := processes[name]
+			p(unitName, name, process.Type, process.ID, process.Status)
+			hasHistory = hasHistory || len(process.History) > 0
+		}
+	}
+	tw.Flush()
+
+	if hasHistory {
+		p()
+		p("UNIT", "PROCESS", "TIME", "STATUS")
+		for _, unitName := range unitNames {
+			processes := units[unitName]
+			for _, name := range sortedProcessNames(processes) {
+				for _, h := range processes[name].History {
+					p(unitName, name, h.Since, h.Status)
+				}
+			}
+		}
+		tw.Flush()
+	}
+	return out.Bytes(), nil
+}
+
+func sortedProcessNames(processes map[string]listedProcess) []string {
+	result := make([]string, 0, len(processes))
+	for name := range processes {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
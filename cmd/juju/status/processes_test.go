@@ -0,0 +1,169 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ListProcessesSuite struct {
+	coretesting.FakeJujuHomeSuite
+	stub    testing.Stub
+	results []params.UnitProcessesResult
+}
+
+var _ = gc.Suite(&ListProcessesSuite{})
+
+func (s *ListProcessesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	s.results = nil
+}
+
+func (s *ListProcessesSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &listProcessesCommand{api: &fakeListProcessesAPI{s}}
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *ListProcessesSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		units    []string
+		types    []string
+		statuses []string
+		err      string
+	}{{}, {
+		args:  []string{"redis/0", "redis/1"},
+		units: []string{"redis/0", "redis/1"},
+	}, {
+		args: []string{"redis"},
+		err:  `invalid unit name "redis"`,
+	}, {
+		args:     []string{"--type", "docker,systemd", "--status", "exited", "--status", "unhealthy"},
+		types:    []string{"docker", "systemd"},
+		statuses: []string{"exited", "unhealthy"},
+	}, {
+		args: []string{"-n", "-1"},
+		err:  "invalid history size -1",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &listProcessesCommand{}
+		err := coretesting.InitCommand(envcmd.Wrap(command), test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.units, jc.DeepEquals, test.units)
+		c.Check(command.types, jc.DeepEquals, test.types)
+		c.Check(command.statuses, jc.DeepEquals, test.statuses)
+	}
+}
+
+func processResult(unit string, history ...params.AgentStatus) params.UnitProcessesResult {
+	return params.UnitProcessesResult{
+		Unit: unit,
+		Processes: []params.ProcessStatus{{
+			Info: params.ProcessInfo{
+				Name:    "cache",
+				Type:    "docker",
+				Details: params.ProcessDetails{ID: "abc", Status: "running"},
+			},
+			History: history,
+		}},
+	}
+}
+
+func (s *ListProcessesSuite) TestTabular(c *gc.C) {
+	s.results = []params.UnitProcessesResult{processResult("redis/1"), processResult("redis/0")}
+
+	ctx, err := s.run(c, "--type", "docker", "redis/0", "redis/1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		"ListProcesses", []interface{}{[]string{"redis/0", "redis/1"}, []string{"docker"}, []string(nil), 0},
+	}})
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"UNIT    PROCESS TYPE   ID  STATUS\n"+
+		"redis/0 cache   docker abc running\n"+
+		"redis/1 cache   docker abc running\n")
+}
+
+func (s *ListProcessesSuite) TestTabularHistory(c *gc.C) {
+	since := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	earlier := since.Add(-time.Hour)
+	s.results = []params.UnitProcessesResult{processResult("redis/0",
+		params.AgentStatus{Status: "running", Since: &since, Kind: params.KindProcess},
+		params.AgentStatus{Status: "exited", Since: &earlier, Kind: params.KindProcess},
+	)}
+
+	ctx, err := s.run(c, "-n", "2", "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"UNIT    PROCESS TYPE   ID  STATUS\n"+
+		"redis/0 cache   docker abc running\n"+
+		"\n"+
+		"UNIT    PROCESS TIME                 STATUS\n"+
+		"redis/0 cache   2015-10-01 12:00:00Z running\n"+
+		"redis/0 cache   2015-10-01 11:00:00Z exited\n")
+}
+
+func (s *ListProcessesSuite) TestYAML(c *gc.C) {
+	s.results = []params.UnitProcessesResult{processResult("redis/0")}
+
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"redis/0:\n"+
+		"  cache:\n"+
+		"    type: docker\n"+
+		"    status: running\n"+
+		"    id: abc\n")
+}
+
+func (s *ListProcessesSuite) TestUnitError(c *gc.C) {
+	s.results = []params.UnitProcessesResult{
+		processResult("redis/0"),
+		{Unit: "redis/9", Error: &params.Error{Message: `unit "redis/9" not found`, Code: params.CodeNotFound}},
+	}
+
+	ctx, err := s.run(c, "redis/0", "redis/9")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"UNIT    PROCESS TYPE   ID  STATUS\n"+
+		"redis/0 cache   docker abc running\n")
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, `unit "redis/9": unit "redis/9" not found`+"\n")
+}
+
+func (s *ListProcessesSuite) TestAPIError(c *gc.C) {
+	s.stub.SetErrors(errors.New("boom"))
+
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeListProcessesAPI struct {
+	s *ListProcessesSuite
+}
+
+func (api *fakeListProcessesAPI) Close() error {
+	return nil
+}
+
+func (api *fakeListProcessesAPI) ListProcesses(units, types, statuses []string, historySize int) ([]params.UnitProcessesResult, error) {
+	api.s.stub.AddCall("ListProcesses", units, types, statuses, historySize)
+	if err := api.s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return api.s.results, nil
+}
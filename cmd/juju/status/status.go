@@ -36,7 +36,9 @@ type statusCommand struct {
 	out      cmd.Output
 	patterns []string
 	isoTime  bool
+	watch    bool
 	api      statusAPI
+	flagSet  *gnuflag.FlagSet
 }
 
 var statusDoc = `
@@ -56,6 +58,7 @@ There are a number of ways to format the status output:
            - Services: NAME, EXPOSED, CHARM
            - Units: ID, STATE, VERSION, MACHINE, PORTS, PUBLIC-ADDRESS
              - Also displays subordinate units.
+             - The leader of each service is marked with "*".
 - yaml (DEFAULT): Displays information on machines, services, and units
                   in the yaml format.
 
@@ -68,6 +71,12 @@ will be displayed.
 Wildcards ('*') may be specified in service/unit names to match any sequence
 of characters. For example, 'nova-*' will match any service whose name begins
 with 'nova-': 'nova-compute', 'nova-volume', etc.
+
+With --watch, status reports changes to the environment as they happen
+instead of printing a snapshot. Each change is written as a JSON array of
+the kind of entity, "change" or "remove", and the entity's details, one
+change per line; the first changes describe the whole environment. Only the
+json format is supported, and patterns cannot be given.
 `
 
 func (c *statusCommand) Info() *cmd.Info {
@@ -82,6 +91,7 @@ func (c *statusCommand) Info() *cmd.Info {
 
 func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	f.BoolVar(&c.watch, "watch", false, "report changes as they happen, as a stream of JSON deltas")
 
 	oneLineFormatter := FormatOneline
 	defaultFormat := "yaml"
@@ -99,10 +109,25 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 		"tabular": FormatTabular,
 		"summary": FormatSummary,
 	})
+	c.flagSet = f
 }
 
 func (c *statusCommand) Init(args []string) error {
 	c.patterns = args
+	if c.watch {
+		if len(args) > 0 {
+			return errors.New("patterns cannot be used with --watch")
+		}
+		var format string
+		c.flagSet.Visit(func(flag *gnuflag.Flag) {
+			if flag.Name == "format" {
+				format = flag.Value.String()
+			}
+		})
+		if format != "" && format != "json" {
+			return errors.Errorf("--watch does not support the %s format", format)
+		}
+	}
 	// If use of ISO time not specified on command line,
 	// check env var.
 	if !c.isoTime {
@@ -129,6 +154,9 @@ var newApiClientForStatus = func(c *statusCommand) (statusAPI, error) {
 }
 
 func (c *statusCommand) Run(ctx *cmd.Context) error {
+	if c.watch {
+		return c.runWatch(ctx)
+	}
 	apiclient, err := newApiClientForStatus(c)
 	if err != nil {
 		return errors.Errorf(connectionError, c.ConnectionName(), err)
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/process"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/presence"
@@ -2312,6 +2313,129 @@ var statusTests = []testCase{
 			},
 		},
 	),
+	test( // 19
+		"deploy a service; track workload processes on its units",
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		setAddresses{"0", network.NewAddresses("dummyenv-0.dns")},
+		startAliveMachine{"0"},
+		setMachineStatus{"0", state.StatusStarted, ""},
+
+		addMachine{machineId: "1", job: state.JobHostUnits},
+		setAddresses{"1", network.NewAddresses("dummyenv-1.dns")},
+		startAliveMachine{"1"},
+		setMachineStatus{"1", state.StatusStarted, ""},
+
+		addMachine{machineId: "2", job: state.JobHostUnits},
+		setAddresses{"2", network.NewAddresses("dummyenv-2.dns")},
+		startAliveMachine{"2"},
+		setMachineStatus{"2", state.StatusStarted, ""},
+
+		addCharm{"mysql"},
+		addService{name: "mysql", charm: "mysql"},
+		addAliveUnit{"mysql", "1"},
+		addAliveUnit{"mysql", "2"},
+		setAgentStatus{"mysql/0", state.StatusIdle, "", nil},
+		setUnitStatus{"mysql/0", state.StatusActive, "", nil},
+		setAgentStatus{"mysql/1", state.StatusIdle, "", nil},
+		setUnitStatus{"mysql/1", state.StatusActive, "", nil},
+
+		setUnitProcesses{"mysql/0", []process.Info{{
+			Name:    "backup",
+			Type:    "systemd",
+			Details: process.Details{ID: "juju-backup.service", Status: "failed"},
+		}, {
+			Name:    "db",
+			Type:    "docker",
+			Details: process.Details{ID: "abc", Status: "running"},
+		}}},
+		setUnitProcesses{"mysql/1", []process.Info{{
+			Name: "db",
+			Type: "docker",
+			Details: process.Details{
+				ID:     "def",
+				Status: "running",
+				Usage:  &process.Usage{CPU: 12.5, Memory: 1572864, Restarts: 3},
+			},
+		}}},
+
+		expect{
+			"process statuses are summarised for the service",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": machine0,
+					"1": machine1,
+					"2": machine2,
+				},
+				"services": M{
+					"mysql": M{
+						"charm":   "cs:quantal/mysql-1",
+						"exposed": false,
+						"service-status": M{
+							"current": "active",
+							"since":   "01 Apr 15 01:23+10:00",
+						},
+						"processes": M{
+							"statuses": M{
+								"failed":  1,
+								"running": 2,
+							},
+							"failed": M{
+								"mysql/0": L{"backup"},
+							},
+						},
+						"units": M{
+							"mysql/0": M{
+								"machine":     "1",
+								"agent-state": "started",
+								"workload-status": M{
+									"current": "active",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"agent-status": M{
+									"current": "idle",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"public-address": "dummyenv-1.dns",
+								"processes": M{
+									"backup": M{
+										"type":   "systemd",
+										"status": "failed",
+									},
+									"db": M{
+										"type":   "docker",
+										"status": "running",
+									},
+								},
+							},
+							"mysql/1": M{
+								"machine":     "2",
+								"agent-state": "started",
+								"workload-status": M{
+									"current": "active",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"agent-status": M{
+									"current": "idle",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"public-address": "dummyenv-2.dns",
+								"processes": M{
+									"db": M{
+										"type":     "docker",
+										"status":   "running",
+										"cpu":      "12.5%",
+										"memory":   "1.5MiB",
+										"restarts": 3,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	),
 }
 
 // TODO(dfc) test failing components by destructively mutating the state under the hood
@@ -2392,7 +2516,7 @@ func (sm startMissingMachine) step(c *gc.C, ctx *context) {
 	_, hc := testing.AssertStartInstanceWithConstraints(c, ctx.env, m.Id(), cons)
 	err = m.SetProvisioned("i-missing", "fake_nonce", hc)
 	c.Assert(err, jc.ErrorIsNil)
-	err = m.SetInstanceStatus("missing")
+	err = m.SetInstanceStatus("missing", "")
 	c.Assert(err, jc.ErrorIsNil)
 }
 
@@ -2598,6 +2722,18 @@ func (s setUnitMeterStatus) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setUnitProcesses struct {
+	unitName  string
+	processes []process.Info
+}
+
+func (s setUnitProcesses) step(c *gc.C, ctx *context) {
+	u, err := ctx.st.Unit(s.unitName)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.SetProcesses(s.processes)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type setUnitStatus struct {
 	unitName   string
 	status     state.Status
@@ -3285,6 +3421,49 @@ ID         STATE VERSION DNS INS-ID SERIES HARDWARE
 `[1:])
 }
 
+func (s *StatusSuite) TestFormatTabularLeader(c *gc.C) {
+	status := formattedStatus{
+		Services: map[string]serviceStatus{
+			"foo": serviceStatus{
+				Units: map[string]unitStatus{
+					"foo/0": unitStatus{
+						AgentStatusInfo: statusInfoContents{
+							Current: params.StatusIdle,
+						},
+						WorkloadStatusInfo: statusInfoContents{
+							Current: params.StatusActive,
+						},
+						Leader: true,
+					},
+					"foo/1": unitStatus{
+						AgentStatusInfo: statusInfoContents{
+							Current: params.StatusIdle,
+						},
+						WorkloadStatusInfo: statusInfoContents{
+							Current: params.StatusActive,
+						},
+					},
+				},
+			},
+		},
+	}
+	out, err := FormatTabular(status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(out), gc.Equals, `
+[Services] 
+NAME       STATUS EXPOSED CHARM 
+foo               false         
+
+[Units] 
+ID      WORKLOAD-STATE AGENT-STATE VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE 
+foo/0*  active         idle                                                     
+foo/1   active         idle                                                     
+
+[Machines] 
+ID         STATE VERSION DNS INS-ID SERIES HARDWARE 
+`[1:])
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusApi(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
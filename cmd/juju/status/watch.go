@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// allWatcher is the part of api.AllWatcher used by status --watch.
+type allWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
+// clientAllWatcher closes the API client used by an AllWatcher when
+// the watcher is stopped.
+type clientAllWatcher struct {
+	*api.AllWatcher
+	client *api.Client
+}
+
+// Stop implements allWatcher.
+func (w clientAllWatcher) Stop() error {
+	err := w.AllWatcher.Stop()
+	if closeErr := w.client.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+var newAllWatcherForStatus = func(c *statusCommand) (allWatcher, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	w, err := client.WatchAll()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return clientAllWatcher{w, client}, nil
+}
+
+// runWatch writes the changes to the environment to ctx.Stdout as
+// they happen, one JSON-encoded delta per line, until the command is
+// interrupted. The first deltas describe the whole environment.
+func (c *statusCommand) runWatch(ctx *cmd.Context) error {
+	w, err := newAllWatcherForStatus(c)
+	if err != nil {
+		return errors.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	var stopOnce sync.Once
+	stopping := make(chan struct{})
+	stop := func() {
+		stopOnce.Do(func() {
+			close(stopping)
+			if err := w.Stop(); err != nil {
+				logger.Debugf("cannot stop watcher: %v", err)
+			}
+		})
+	}
+	defer stop()
+
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+	go func() {
+		select {
+		case <-interrupted:
+			stop()
+		case <-stopping:
+		}
+	}()
+
+	enc := json.NewEncoder(ctx.Stdout)
+	for {
+		deltas, err := w.Next()
+		if err != nil {
+			select {
+			case <-stopping:
+				// The watcher failed because it was stopped.
+				return nil
+			default:
+			}
+			return errors.Annotate(err, "cannot watch environment")
+		}
+		for i := range deltas {
+			if err := enc.Encode(&deltas[i]); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"errors"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+type fakeAllWatcher struct {
+	deltas  [][]multiwatcher.Delta
+	stopped bool
+}
+
+func (w *fakeAllWatcher) Next() ([]multiwatcher.Delta, error) {
+	if len(w.deltas) == 0 {
+		return nil, errors.New("no more deltas")
+	}
+	deltas := w.deltas[0]
+	w.deltas = w.deltas[1:]
+	return deltas, nil
+}
+
+func (w *fakeAllWatcher) Stop() error {
+	w.stopped = true
+	return nil
+}
+
+func (s *StatusSuite) TestWatch(c *gc.C) {
+	w := &fakeAllWatcher{
+		deltas: [][]multiwatcher.Delta{{{
+			Entity: &multiwatcher.MachineInfo{Id: "0"},
+		}, {
+			Entity: &multiwatcher.ServiceInfo{Name: "wordpress"},
+		}}, {{
+			Removed: true,
+			Entity:  &multiwatcher.ServiceInfo{Name: "wordpress"},
+		}}},
+	}
+	s.PatchValue(&newAllWatcherForStatus, func(*statusCommand) (allWatcher, error) {
+		return w, nil
+	})
+	code, stdout, stderr := runStatus(c, "--watch")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(string(stderr), gc.Equals, "error: cannot watch environment: no more deltas\n")
+	c.Assert(w.stopped, jc.IsTrue)
+
+	lines := strings.Split(strings.TrimRight(string(stdout), "\n"), "\n")
+	c.Assert(lines, gc.HasLen, 3)
+	c.Assert(lines[0], gc.Matches, `\["machine","change",\{.*"Id":"0".*\}\]`)
+	c.Assert(lines[1], gc.Matches, `\["service","change",\{.*"Name":"wordpress".*\}\]`)
+	c.Assert(lines[2], gc.Matches, `\["service","remove",\{.*"Name":"wordpress".*\}\]`)
+}
+
+func (s *StatusSuite) TestWatchBadArgs(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--watch", "wordpress"},
+		err:  "patterns cannot be used with --watch",
+	}, {
+		args: []string{"--watch", "--format", "yaml"},
+		err:  "--watch does not support the yaml format",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		code, _, stderr := runStatus(c, test.args...)
+		c.Check(code, gc.Equals, 2)
+		c.Check(string(stderr), gc.Equals, "error: "+test.err+"\n")
+	}
+}
+
+func (s *StatusSuite) TestWatchJSONFormat(c *gc.C) {
+	w := &fakeAllWatcher{}
+	s.PatchValue(&newAllWatcherForStatus, func(*statusCommand) (allWatcher, error) {
+		return w, nil
+	})
+	code, _, _ := runStatus(c, "--watch", "--format", "json")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(w.stopped, jc.IsTrue)
+}
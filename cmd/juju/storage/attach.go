@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newAttachCommand() cmd.Command {
+	return envcmd.Wrap(&attachCommand{})
+}
+
+const attachCommandDoc = `
+Attach storage instances, previously detached with "juju storage detach",
+to a unit of the same service. The unit becomes the owner of the storage,
+and the storage's existing volume or filesystem, along with its data, is
+attached to the unit's machine.
+
+Example:
+    Attach storage instance "data/0" to unit mysql/1:
+
+      juju storage attach mysql/1 data/0
+`
+
+// attachCommand attaches detached storage instances to a unit.
+type attachCommand struct {
+	StorageCommandBase
+	unitTag     names.UnitTag
+	storageTags []names.StorageTag
+	api         StorageAttachAPI
+}
+
+// Init implements Command.Init.
+func (c *attachCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("storage attach requires a unit and at least one storage ID")
+	}
+	if !names.IsValidUnit(args[0]) {
+		return errors.NotValidf("unit name %q", args[0])
+	}
+	c.unitTag = names.NewUnitTag(args[0])
+	for _, id := range args[1:] {
+		if !names.IsValidStorage(id) {
+			return errors.NotValidf("storage ID %q", id)
+		}
+		c.storageTags = append(c.storageTags, names.NewStorageTag(id))
+	}
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *attachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "attach",
+		Purpose: "attaches detached storage to a unit",
+		Doc:     attachCommandDoc,
+		Args:    "<unit name> <storage ID> ...",
+	}
+}
+
+// Run implements Command.Run.
+func (c *attachCommand) Run(ctx *cmd.Context) (err error) {
+	api := c.api
+	if api == nil {
+		api, err = c.NewStorageAPI()
+		if err != nil {
+			return err
+		}
+		defer api.Close()
+	}
+
+	results, err := api.Attach(c.unitTag, c.storageTags)
+	if err != nil {
+		return err
+	}
+	return reportStorageResults(ctx, c.storageTags, results)
+}
+
+// StorageAttachAPI defines the API methods that the storage attach
+// command uses.
+type StorageAttachAPI interface {
+	Close() error
+	Attach(unit names.UnitTag, tags []names.StorageTag) ([]params.ErrorResult, error)
+}
@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type attachSuite struct {
+	SubStorageSuite
+	mockAPI *mockAttachAPI
+}
+
+var _ = gc.Suite(&attachSuite{})
+
+func (s *attachSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.mockAPI = &mockAttachAPI{}
+}
+
+func (s *attachSuite) runAttach(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, storage.NewAttachCommand(s.mockAPI), args...)
+}
+
+func (s *attachSuite) TestAttachArgs(c *gc.C) {
+	for i, t := range []tstData{
+		{nil, "storage attach requires a unit and at least one storage ID"},
+		{[]string{"mysql/1"}, "storage attach requires a unit and at least one storage ID"},
+		{[]string{"mysql-1", "data/0"}, `unit name "mysql-1" not valid`},
+		{[]string{"mysql/1", "data-0"}, `storage ID "data-0" not valid`},
+	} {
+		c.Logf("test %d for %q", i, t.args)
+		_, err := s.runAttach(c, t.args...)
+		c.Check(err, gc.ErrorMatches, t.expectedErr)
+	}
+}
+
+func (s *attachSuite) TestAttach(c *gc.C) {
+	context, err := s.runAttach(c, "mysql/1", "data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+	c.Assert(testing.Stderr(context), gc.Equals, "")
+	c.Assert(s.mockAPI.unit, gc.Equals, names.NewUnitTag("mysql/1"))
+	c.Assert(s.mockAPI.tags, jc.DeepEquals, []names.StorageTag{
+		names.NewStorageTag("data/0"),
+	})
+}
+
+func (s *attachSuite) TestAttachFailure(c *gc.C) {
+	context, err := s.runAttach(c, "mysql/1", "err/0", "data/1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(context), gc.Equals, "fail: storage \"err/0\": test failure\n")
+}
+
+func (s *attachSuite) TestAttachAPIError(c *gc.C) {
+	s.mockAPI.abort = true
+	_, err := s.runAttach(c, "mysql/1", "data/0")
+	c.Assert(err, gc.ErrorMatches, "aborted")
+}
+
+type mockAttachAPI struct {
+	abort bool
+	unit  names.UnitTag
+	tags  []names.StorageTag
+}
+
+func (s *mockAttachAPI) Close() error {
+	return nil
+}
+
+func (s *mockAttachAPI) Attach(unit names.UnitTag, tags []names.StorageTag) ([]params.ErrorResult, error) {
+	if s.abort {
+		return nil, errors.New("aborted")
+	}
+	s.unit = unit
+	s.tags = tags
+	return storageErrorResults(tags), nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newDetachCommand() cmd.Command {
+	return envcmd.Wrap(&detachCommand{})
+}
+
+const detachCommandDoc = `
+Detach storage instances from the units that own them, without destroying
+the storage. The storage's volume or filesystem is detached from the unit's
+machine once the unit has run its storage-detaching hook.
+
+Detached storage is retained by the unit's service when the unit is removed,
+and may be attached to another unit of the same service with
+"juju storage attach". Only storage provisioned by dynamic, environment-scoped
+storage providers (e.g. EBS volumes) may be detached.
+
+Example:
+    Detach storage instance "data/0" from its unit:
+
+      juju storage detach data/0
+`
+
+// detachCommand detaches storage instances from their units.
+type detachCommand struct {
+	StorageCommandBase
+	storageTags []names.StorageTag
+	api         StorageDetachAPI
+}
+
+// Init implements Command.Init.
+func (c *detachCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("storage detach requires at least one storage ID")
+	}
+	for _, id := range args {
+		if !names.IsValidStorage(id) {
+			return errors.NotValidf("storage ID %q", id)
+		}
+		c.storageTags = append(c.storageTags, names.NewStorageTag(id))
+	}
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *detachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "detach",
+		Purpose: "detaches storage from units without destroying it",
+		Doc:     detachCommandDoc,
+		Args:    "<storage ID> ...",
+	}
+}
+
+// Run implements Command.Run.
+func (c *detachCommand) Run(ctx *cmd.Context) (err error) {
+	api := c.api
+	if api == nil {
+		api, err = c.NewStorageAPI()
+		if err != nil {
+			return err
+		}
+		defer api.Close()
+	}
+
+	results, err := api.Detach(c.storageTags)
+	if err != nil {
+		return err
+	}
+	return reportStorageResults(ctx, c.storageTags, results)
+}
+
+// StorageDetachAPI defines the API methods that the storage detach
+// command uses.
+type StorageDetachAPI interface {
+	Close() error
+	Detach(tags []names.StorageTag) ([]params.ErrorResult, error)
+}
+
+// reportStorageResults writes any failures for the specified storage
+// instances to stderr, and returns cmd.ErrSilent if there were any.
+func reportStorageResults(ctx *cmd.Context, tags []names.StorageTag, results []params.ErrorResult) error {
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, fail+": %v\n", tags[i].Id(), result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type detachSuite struct {
+	SubStorageSuite
+	mockAPI *mockDetachAPI
+}
+
+var _ = gc.Suite(&detachSuite{})
+
+func (s *detachSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.mockAPI = &mockDetachAPI{}
+}
+
+func (s *detachSuite) runDetach(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, storage.NewDetachCommand(s.mockAPI), args...)
+}
+
+func (s *detachSuite) TestDetachNoArgs(c *gc.C) {
+	_, err := s.runDetach(c)
+	c.Assert(err, gc.ErrorMatches, "storage detach requires at least one storage ID")
+}
+
+func (s *detachSuite) TestDetachInvalidStorage(c *gc.C) {
+	_, err := s.runDetach(c, "data-0")
+	c.Assert(err, gc.ErrorMatches, `storage ID "data-0" not valid`)
+}
+
+func (s *detachSuite) TestDetach(c *gc.C) {
+	context, err := s.runDetach(c, "data/0", "data/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+	c.Assert(testing.Stderr(context), gc.Equals, "")
+	c.Assert(s.mockAPI.tags, jc.DeepEquals, []names.StorageTag{
+		names.NewStorageTag("data/0"),
+		names.NewStorageTag("data/1"),
+	})
+}
+
+func (s *detachSuite) TestDetachFailure(c *gc.C) {
+	context, err := s.runDetach(c, "data/0", "err/1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(context), gc.Equals, "fail: storage \"err/1\": test failure\n")
+}
+
+func (s *detachSuite) TestDetachAPIError(c *gc.C) {
+	s.mockAPI.abort = true
+	_, err := s.runDetach(c, "data/0")
+	c.Assert(err, gc.ErrorMatches, "aborted")
+}
+
+type mockDetachAPI struct {
+	abort bool
+	tags  []names.StorageTag
+}
+
+func (s *mockDetachAPI) Close() error {
+	return nil
+}
+
+func (s *mockDetachAPI) Detach(tags []names.StorageTag) ([]params.ErrorResult, error) {
+	if s.abort {
+		return nil, errors.New("aborted")
+	}
+	s.tags = tags
+	return storageErrorResults(tags), nil
+}
+
+// storageErrorResults returns a result for each storage tag, failing
+// those whose storage name begins with "err".
+func storageErrorResults(tags []names.StorageTag) []params.ErrorResult {
+	results := make([]params.ErrorResult, len(tags))
+	for i, tag := range tags {
+		if strings.HasPrefix(tag.Id(), "err") {
+			results[i].Error = common.ServerError(errors.New("test failure"))
+		}
+	}
+	return results
+}
@@ -11,6 +11,8 @@ import (
 
 var expectedSubCommmandNames = []string{
 	"add",
+	"attach",
+	"detach",
 	"filesystem",
 	"help",
 	"list",
@@ -71,6 +71,7 @@ func (c *environmentsCommand) Info() *cmd.Info {
 		Name:    "environments",
 		Purpose: "list all environments the user can access on the current system",
 		Doc:     envsDoc,
+		Aliases: []string{"list-environments"},
 	}
 }
 
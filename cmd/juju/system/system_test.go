@@ -28,6 +28,7 @@ var expectedCommmandNames = []string{
 	"kill",
 	"list",
 	"list-blocks",
+	"list-environments", // alias for environments
 	"login",
 	"remove-blocks",
 	"use-env", // alias for use-environment
@@ -4,21 +4,23 @@
 package user
 
 import (
-	"bytes"
-	"fmt"
-	"text/tabwriter"
-
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
 )
 
 const listCommandDoc = `
 List all the current users in the Juju server.
 
+The columns shown in tabular output may be chosen with --columns, which
+takes a comma-separated list of column headings, e.g.
+
+   juju user list --columns name,last-connection
+
 See Also:
    juju help user info
 `
@@ -30,7 +32,8 @@ func newListCommand() cmd.Command {
 // listCommand shows all the users in the Juju server.
 type listCommand struct {
 	infoCommandBase
-	All bool
+	All     bool
+	columns common.Columns
 }
 
 // Info implements Command.Info.
@@ -51,6 +54,15 @@ func (c *listCommand) SetFlags(f *gnuflag.FlagSet) {
 		"json":    cmd.FormatJson,
 		"tabular": c.formatTabular,
 	})
+	c.columns.AddFlag(f)
+}
+
+// Init implements Command.Init.
+func (c *listCommand) Init(args []string) error {
+	if err := c.columns.CheckFormat(c.out.Name()); err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args)
 }
 
 // Run implements Command.Run.
@@ -76,24 +88,13 @@ func (c *listCommand) formatTabular(value interface{}) ([]byte, error) {
 	if !valueConverted {
 		return nil, errors.Errorf("expected value of type %T, got %T", users, value)
 	}
-	var out bytes.Buffer
-	const (
-		// To format things into columns.
-		minwidth = 0
-		tabwidth = 1
-		padding  = 2
-		padchar  = ' '
-		flags    = 0
-	)
-	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
-	fmt.Fprintf(tw, "NAME\tDISPLAY NAME\tDATE CREATED\tLAST CONNECTION\n")
+	table := common.NewTable("NAME", "DISPLAY NAME", "DATE CREATED", "LAST CONNECTION")
 	for _, user := range users {
 		conn := user.LastConnection
 		if user.Disabled {
 			conn += " (disabled)"
 		}
-		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", user.Username, user.DisplayName, user.DateCreated, conn)
+		table.AddRow(user.Username, user.DisplayName, user.DateCreated, conn)
 	}
-	tw.Flush()
-	return out.Bytes(), nil
+	return table.Format(c.columns)
 }
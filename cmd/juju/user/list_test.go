@@ -110,6 +110,27 @@ func (s *UserListCommandSuite) TestUserInfoExactTime(c *gc.C) {
 		"\n")
 }
 
+func (s *UserListCommandSuite) TestUserInfoColumns(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserListCommand(), "--columns", "last-connection,name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"LAST CONNECTION  NAME\n"+
+		"2014-01-01       adam\n"+
+		"just now         barbara\n"+
+		"never connected  charlie\n"+
+		"\n")
+}
+
+func (s *UserListCommandSuite) TestUserInfoUnknownColumn(c *gc.C) {
+	_, err := testing.RunCommand(c, newUserListCommand(), "--columns", "name,email")
+	c.Assert(err, gc.ErrorMatches, `unknown column "email" \(expected one of name, display-name, date-created, last-connection\)`)
+}
+
+func (s *UserListCommandSuite) TestUserInfoColumnsNotTabular(c *gc.C) {
+	_, err := testing.RunCommand(c, newUserListCommand(), "--columns", "name", "--format", "json")
+	c.Assert(err, gc.ErrorMatches, "--columns cannot be used with the json format")
+}
+
 func (*UserListCommandSuite) TestUserInfoFormatJson(c *gc.C) {
 	context, err := testing.RunCommand(c, newUserListCommand(), "--format", "json")
 	c.Assert(err, jc.ErrorIsNil)
@@ -30,6 +30,7 @@ import (
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/introspection"
 	"github.com/juju/juju/worker/proxyupdater"
 )
 
@@ -100,6 +101,11 @@ func (s *AgentSuite) SetUpTest(c *gc.C) {
 	s.PatchValue(&proxyupdater.New, func(*apienvironment.Facade, bool) worker.Worker {
 		return newDummyWorker()
 	})
+	// Agents run concurrently by other test packages may use the
+	// same introspection socket names.
+	s.PatchValue(&newIntrospectionWorker, func(introspection.Config) (worker.Worker, error) {
+		return newDummyWorker(), nil
+	})
 
 	// Tests should not try to use internet. Ensure base url is empty.
 	imagemetadata.DefaultBaseURL = ""
@@ -0,0 +1,39 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"runtime"
+
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/introspection"
+)
+
+// introspectionWorkerStarter returns a function that starts a worker
+// serving the introspection socket for the given agent, describing its
+// API addresses and the workers run by the given runner. The socket is named
+// "jujud-<tag>", so a stuck machine agent can be examined with, for
+// example:
+//
+//	socat - ABSTRACT-CONNECT:jujud-machine-0 <<< $'GET /goroutines HTTP/1.0\n'
+//
+// Abstract unix sockets exist only on Linux; elsewhere the worker does
+// nothing.
+func introspectionWorkerStarter(agent introspection.AgentConfigGetter, runner worker.Runner) func() (worker.Worker, error) {
+	return func() (worker.Worker, error) {
+		if runtime.GOOS != "linux" {
+			return worker.NewNoOpWorker(), nil
+		}
+		// Runners created by worker.NewRunner can describe their
+		// workers; others, such as those used in tests, may not.
+		reporter, _ := runner.(introspection.Reporter)
+		return newIntrospectionWorker(introspection.Config{
+			SocketName: "jujud-" + agent.CurrentConfig().Tag().String(),
+			Reporter:   reporter,
+			Agent:      agent,
+		})
+	}
+}
+
+var newIntrospectionWorker = introspection.NewWorker
@@ -0,0 +1,44 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"runtime"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/introspection"
+)
+
+type IntrospectionSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&IntrospectionSuite{})
+
+func (s *IntrospectionSuite) TestIntrospectionWorkerStarter(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("introspection is only supported on linux")
+	}
+	var config introspection.Config
+	s.PatchValue(&newIntrospectionWorker, func(cfg introspection.Config) (worker.Worker, error) {
+		config = cfg
+		return newDummyWorker(), nil
+	})
+	runner := worker.NewRunner(cmdutil.IsFatal, cmdutil.MoreImportant)
+	defer worker.Stop(runner)
+
+	agent := FakeAgentConfig{}
+	start := introspectionWorkerStarter(agent, runner)
+	w, err := start()
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+	c.Assert(config.SocketName, gc.Equals, "jujud-machine-42")
+	c.Assert(config.Reporter, gc.Equals, runner)
+	c.Assert(config.Agent, gc.Equals, agent)
+}
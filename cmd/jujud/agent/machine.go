@@ -35,6 +35,7 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	apiagent "github.com/juju/juju/api/agent"
+	apiautoscaler "github.com/juju/juju/api/autoscaler"
 	apideployer "github.com/juju/juju/api/deployer"
 	apilogsender "github.com/juju/juju/api/logsender"
 	"github.com/juju/juju/api/metricsmanager"
@@ -43,6 +44,7 @@ import (
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
+	"github.com/juju/juju/cmd/jujud/agent/machine"
 	"github.com/juju/juju/cmd/jujud/reboot"
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/container"
@@ -67,30 +69,33 @@ import (
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/addresser"
-	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/autoscaler"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
+	"github.com/juju/juju/worker/charmrollout"
 	"github.com/juju/juju/worker/cleaner"
 	"github.com/juju/juju/worker/conv2state"
 	"github.com/juju/juju/worker/dblogpruner"
+	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
 	"github.com/juju/juju/worker/envworkermanager"
 	"github.com/juju/juju/worker/firewaller"
+	"github.com/juju/juju/worker/hostkeyreporter"
 	"github.com/juju/juju/worker/imagemetadataworker"
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
-	workerlogger "github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
+	"github.com/juju/juju/worker/machinefirewaller"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/metricworker"
 	"github.com/juju/juju/worker/minunitsworker"
 	"github.com/juju/juju/worker/networker"
 	"github.com/juju/juju/worker/peergrouper"
+	"github.com/juju/juju/worker/problemreporter"
 	"github.com/juju/juju/worker/provisioner"
-	"github.com/juju/juju/worker/proxyupdater"
 	rebootworker "github.com/juju/juju/worker/reboot"
 	"github.com/juju/juju/worker/resumer"
 	"github.com/juju/juju/worker/rsyslog"
@@ -105,6 +110,10 @@ import (
 
 const bootstrapMachineId = "0"
 
+// autoscalerPollInterval is how often the autoscaler worker checks
+// the sizes of the cloud instance groups linked to services.
+const autoscalerPollInterval = time.Minute
+
 var (
 	logger     = loggo.GetLogger("juju.cmd.jujud")
 	retryDelay = 3 * time.Second
@@ -447,6 +456,7 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 	if err := a.createJujuRun(agentConfig.DataDir()); err != nil {
 		return fmt.Errorf("cannot create juju run symlink: %v", err)
 	}
+	a.runner.StartWorker("introspection", introspectionWorkerStarter(a, a.runner))
 	a.runner.StartWorker("api", a.APIWorker)
 	a.runner.StartWorker("statestarter", a.newStateStarterWorker)
 	a.runner.StartWorker("termination", func() (worker.Worker, error) {
@@ -715,6 +725,16 @@ func (a *MachineAgent) APIWorker() (_ worker.Worker, err error) {
 	runner.StartWorker("upgrader", a.agentUpgraderWorkerStarter(st.Upgrader(), agentConfig))
 	runner.StartWorker("upgrade-steps", a.upgradeStepsWorkerStarter(st, entity.Jobs()))
 
+	// The engine makes its own API connection, so start it only now that
+	// ours is open and any password change has been made. Its workers
+	// wait for the upgrade steps themselves.
+	// TODO(fwereade): this is *still* a hideous layering violation, but at least
+	// it's confined to jujud rather than extending into the worker itself.
+	writeSystemFiles := shouldWriteProxyFiles(agentConfig)
+	runner.StartWorker("engine", func() (worker.Worker, error) {
+		return a.newEngine(writeSystemFiles)
+	})
+
 	// All other workers must wait for the upgrade steps to complete before starting.
 	a.startWorkerAfterUpgrade(runner, "api-post-upgrade", func() (worker.Worker, error) {
 		return a.postUpgradeAPIWorker(st, agentConfig, entity)
@@ -723,6 +743,34 @@ func (a *MachineAgent) APIWorker() (_ worker.Worker, err error) {
 	return cmdutil.NewCloseWorker(logger, runner, st), nil // Note: a worker.Runner is itself a worker.Worker.
 }
 
+// newEngine returns a dependency.Engine running those of the machine
+// agent's workers that have been converted to manifolds.
+func (a *MachineAgent) newEngine(writeProxyFiles bool) (worker.Worker, error) {
+	manifolds := machine.Manifolds(machine.ManifoldsConfig{
+		Agent:                agent.APIHostPortsSetter{a},
+		UpgradeStepsComplete: a.upgradeWorkerContext.UpgradeComplete,
+		UpgradeCheckComplete: a.initialAgentUpgradeCheckComplete,
+		WriteProxyFiles:      writeProxyFiles,
+	})
+	config := dependency.EngineConfig{
+		IsFatal:     cmdutil.IsFatal,
+		WorstError:  cmdutil.MoreImportantError,
+		ErrorDelay:  3 * time.Second,
+		BounceDelay: 10 * time.Millisecond,
+	}
+	engine, err := dependency.NewEngine(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := dependency.Install(engine, manifolds); err != nil {
+		if err := worker.Stop(engine); err != nil {
+			logger.Errorf("while stopping engine with bad manifolds: %v", err)
+		}
+		return nil, err
+	}
+	return engine, nil
+}
+
 func (a *MachineAgent) postUpgradeAPIWorker(
 	st api.Connection,
 	agentConfig agent.Config,
@@ -737,16 +785,8 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 		}
 	}
 
-	runner := newConnRunner(st)
-
-	// TODO(fwereade): this is *still* a hideous layering violation, but at least
-	// it's confined to jujud rather than extending into the worker itself.
-	// Start this worker first to try and get proxy settings in place
-	// before we do anything else.
-	writeSystemFiles := shouldWriteProxyFiles(agentConfig)
-	runner.StartWorker("proxyupdater", func() (worker.Worker, error) {
-		return proxyupdater.New(st.Environment(), writeSystemFiles), nil
-	})
+	// Report workers that keep failing to the controller.
+	runner := problemreporter.NewRunner(newConnRunner(st), st.ProblemReporter())
 
 	if isEnvironManager {
 		runner.StartWorker("resumer", func() (worker.Worker, error) {
@@ -786,15 +826,10 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 		}
 		return rebootworker.NewReboot(reboot, agentConfig, lock)
 	})
-	runner.StartWorker("apiaddressupdater", func() (worker.Worker, error) {
-		addressUpdater := agent.APIHostPortsSetter{a}
-		return apiaddressupdater.NewAPIAddressUpdater(st.Machiner(), addressUpdater), nil
-	})
-	runner.StartWorker("logger", func() (worker.Worker, error) {
-		return workerlogger.NewLogger(st.Logger(), agentConfig), nil
-	})
 
-	if !featureflag.Enabled(feature.DisableRsyslog) {
+	// When logs are stored in the database there is no need to
+	// accumulate them in all-machines.log as well.
+	if !featureflag.Enabled(feature.DisableRsyslog) && !feature.IsDbLogEnabled() {
 		rsyslogMode := rsyslog.RsyslogModeForwarding
 		if isEnvironManager {
 			rsyslogMode = rsyslog.RsyslogModeAccumulate
@@ -818,6 +853,22 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 		}
 		return newDiskManager(diskmanager.DefaultListBlockDevices, api), nil
 	})
+	runner.StartWorker("hostkeyreporter", func() (worker.Worker, error) {
+		api, err := st.HostKeyReporter()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return hostkeyreporter.NewWorker(api, hostkeyreporter.DefaultSSHDir), nil
+	})
+	if envConfig.FirewallMode() == config.FwMachine {
+		runner.StartWorker("machinefirewaller", func() (worker.Worker, error) {
+			api, err := st.MachineFirewaller()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return machinefirewaller.NewWorker(api, newMachineFirewall(agentConfig, envConfig)), nil
+		})
+	}
 	runner.StartWorker("storageprovisioner-machine", func() (worker.Worker, error) {
 		scope := agentConfig.Tag()
 		api := st.StorageProvisioner(scope)
@@ -1062,6 +1113,7 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 
 	stor := statestorage.NewStorage(st.EnvironUUID(), st.MongoSession())
 	registerSimplestreamsDataSource(stor)
+	registerCustomImageDataSource(st.CloudImageMetadataStorage)
 
 	runner := newConnRunner(st)
 	singularRunner, err := newSingularStateRunner(runner, st, m)
@@ -1121,7 +1173,13 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 
 			if feature.IsDbLogEnabled() {
 				a.startWorkerAfterUpgrade(singularRunner, "dblogpruner", func() (worker.Worker, error) {
-					return dblogpruner.New(st, dblogpruner.NewLogPruneParams()), nil
+					params := dblogpruner.NewLogPruneParams()
+					retention, err := dblogpruner.ParseLevelRetention(agentConfig.Value(agent.LogRetention))
+					if err != nil {
+						return nil, errors.Annotate(err, "cannot read log retention")
+					}
+					params.LevelRetention = retention
+					return dblogpruner.New(st, params), nil
 				})
 			}
 
@@ -1146,6 +1204,7 @@ func (s stateWorkerCloser) Close() error {
 	// This state-dependent data source will be useless once state is closed -
 	// un-register it before closing state.
 	unregisterSimplestreamsDataSource()
+	unregisterCustomImageDataSource()
 	return s.stateCloser.Close()
 }
 
@@ -1212,6 +1271,9 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
+	singularRunner.StartWorker("charmrollout", func() (worker.Worker, error) {
+		return charmrollout.New(st), nil
+	})
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
@@ -1258,10 +1320,14 @@ func (a *MachineAgent) startEnvWorkers(
 	}
 
 	singularRunner.StartWorker("statushistorypruner", func() (worker.Worker, error) {
+		maxLogs, err := statushistorypruner.ParseMaxLogsPerEntity(agentConfig.Value(agent.StatusHistoryRetention))
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read status history retention")
+		}
 		f := statushistory.NewFacade(apiSt)
 		conf := statushistorypruner.Config{
 			Facade:           f,
-			MaxLogsPerEntity: params.DefaultMaxLogsPerEntity,
+			MaxLogsPerEntity: maxLogs,
 			PruneInterval:    params.DefaultPruneInterval,
 			NewTimer:         worker.NewTimer,
 		}
@@ -1272,9 +1338,54 @@ func (a *MachineAgent) startEnvWorkers(
 		return w, nil
 	})
 
+	// The autoscaler is only useful where the provider can report
+	// the sizes of cloud instance groups. Failing to find out is no
+	// reason to stop the other environment workers.
+	instanceGroups, err := supportsInstanceGroups(apiSt)
+	if err != nil {
+		logger.Warningf("cannot check for instance group support: %v", err)
+	}
+	if err == nil && instanceGroups {
+		singularRunner.StartWorker("autoscaler", func() (worker.Worker, error) {
+			conf := autoscaler.Config{
+				Facade:       apiautoscaler.NewFacade(apiSt),
+				NewEnviron:   environs.New,
+				PollInterval: autoscalerPollInterval,
+				NewTimer:     worker.NewTimer,
+			}
+			w, err := autoscaler.New(conf)
+			if err != nil {
+				return nil, errors.Annotate(err, "cannot start \"autoscaler\"")
+			}
+			return w, nil
+		})
+	} else {
+		logger.Debugf("not starting autoscaler worker - environment does not support instance groups")
+	}
+
 	return runner, nil
 }
 
+// newMachineFirewall returns the host firewall programmed by the
+// machinefirewaller worker. Container bridges are trusted, and state
+// servers always accept connections from agents to the API server,
+// mongo and rsyslog.
+func newMachineFirewall(agentConfig agent.Config, envConfig *config.Config) *machinefirewaller.IPTables {
+	fw := &machinefirewaller.IPTables{
+		TrustedInterfaces: []string{lxc.DefaultLxcBridge, kvm.DefaultKvmBridge},
+	}
+	if info, ok := agentConfig.StateServingInfo(); ok {
+		for _, port := range []int{info.APIPort, info.StatePort, envConfig.SyslogPort()} {
+			fw.AlwaysOpen = append(fw.AlwaysOpen, network.PortRange{
+				FromPort: port,
+				ToPort:   port,
+				Protocol: "tcp",
+			})
+		}
+	}
+	return fw
+}
+
 var getFirewallMode = _getFirewallMode
 
 func _getFirewallMode(apiSt api.Connection) (string, error) {
@@ -1285,6 +1396,21 @@ func _getFirewallMode(apiSt api.Connection) (string, error) {
 	return envConfig.FirewallMode(), nil
 }
 
+var supportsInstanceGroups = _supportsInstanceGroups
+
+func _supportsInstanceGroups(apiSt api.Connection) (bool, error) {
+	envConfig, err := apiSt.Environment().EnvironConfig()
+	if err != nil {
+		return false, errors.Annotate(err, "cannot read environment config")
+	}
+	env, err := environs.New(envConfig)
+	if err != nil {
+		return false, errors.Annotate(err, "cannot open environment")
+	}
+	_, ok := environs.SupportsInstanceGroups(env)
+	return ok, nil
+}
+
 // stateWorkerDialOpts is a mongo.DialOpts suitable
 // for use by StateWorker to dial mongo.
 //
@@ -1316,6 +1442,11 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 	dataDir := agentConfig.DataDir()
 	logDir := agentConfig.LogDir()
 
+	rateLimit, err := apiserver.ParseRateLimitConfig(agentConfig.Value(agent.APIRateLimit))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read API rate limits")
+	}
+
 	endpoint := net.JoinHostPort("", strconv.Itoa(info.APIPort))
 	listener, err := net.Listen("tcp", endpoint)
 	if err != nil {
@@ -1329,6 +1460,8 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 		LogDir:      logDir,
 		Validator:   a.limitLogins,
 		CertChanged: certChanged,
+		AuditSyslog: agentConfig.Value(agent.AuditSyslog) == "true",
+		RateLimit:   rateLimit,
 	})
 }
 
@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	coreagent "github.com/juju/juju/agent"
+	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/apiaddressupdater"
+	"github.com/juju/juju/worker/apicaller"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/gate"
+	"github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/proxyupdater"
+)
+
+// ManifoldsConfig allows specialisation of the result of Manifolds.
+type ManifoldsConfig struct {
+
+	// Agent contains the agent that will be wrapped and made available to
+	// its dependencies via a dependency.Engine.
+	Agent coreagent.Agent
+
+	// UpgradeStepsComplete is closed by the machine agent when its
+	// upgrade steps have run.
+	UpgradeStepsComplete <-chan struct{}
+
+	// UpgradeCheckComplete is closed by the machine agent when it has
+	// checked that it is running the tools it should be.
+	UpgradeCheckComplete <-chan struct{}
+
+	// WriteProxyFiles controls whether the proxy config updater writes
+	// proxy settings to the machine's system files.
+	WriteProxyFiles bool
+}
+
+// Manifolds returns a set of co-configured manifolds covering those of a
+// machine agent's responsibilities that have so far been moved out of its
+// worker runners. Those that must wait for upgrades to complete do so via
+// the upgrade waiter, and each is restarted whenever the resources it
+// depends on change.
+//
+// Thou Shalt Not Use String Literals In This Function. Or Else.
+func Manifolds(config ManifoldsConfig) dependency.Manifolds {
+	return dependency.Manifolds{
+
+		// The agent manifold references the enclosing agent, and is the
+		// foundation stone on which most other manifolds ultimately depend.
+		AgentName: agent.Manifold(config.Agent),
+
+		// The api caller is a thin concurrent wrapper around a connection
+		// to some API server, made and maintained on behalf of the agent.
+		// The machine agent still opens its own connection for the workers
+		// in its runners, and starts this engine only once that has
+		// succeeded, so any password change has been made before the api
+		// caller connects.
+		APICallerName: apicaller.Manifold(apicaller.ManifoldConfig{
+			AgentName:       AgentName,
+			APIInfoGateName: APIInfoGateName,
+		}),
+
+		// This manifold is unlocked by the api caller once it has fixed up
+		// the agent's config, for the benefit of any other manifolds that
+		// connect to the API with the same credentials.
+		APIInfoGateName: gate.Manifold(),
+
+		// The upgrade waiter reports whether the agent's upgrade steps
+		// have run and its tools have been checked. Workers wrapped with
+		// ifUpgraded do not start until it does.
+		UpgradeWaiterName: upgradeWaiterManifold(
+			config.UpgradeStepsComplete,
+			config.UpgradeCheckComplete,
+		),
+
+		// The logging config updater is a leaf worker that indirectly
+		// controls the messages sent via the log sender or rsyslog,
+		// according to changes in environment config.
+		LoggingConfigUpdaterName: ifUpgraded(UpgradeWaiterName, logger.Manifold(logger.ManifoldConfig{
+			AgentName:     AgentName,
+			APICallerName: APICallerName,
+		})),
+
+		// The api address updater is a leaf worker that rewrites agent config
+		// as the state server addresses change.
+		APIAddressUpdaterName: ifUpgraded(UpgradeWaiterName, apiaddressupdater.Manifold(apiaddressupdater.ManifoldConfig{
+			AgentName:     AgentName,
+			APICallerName: APICallerName,
+		})),
+
+		// The proxy config updater is a leaf worker that sets http/https/apt/etc
+		// proxy settings, writing them to system files where the machine's
+		// own configuration is not managed by some other means.
+		ProxyConfigUpdaterName: ifUpgraded(UpgradeWaiterName, proxyupdater.Manifold(proxyupdater.ManifoldConfig{
+			APICallerName:    APICallerName,
+			WriteSystemFiles: config.WriteProxyFiles,
+		})),
+	}
+}
+
+const (
+	AgentName                = "agent"
+	APIAddressUpdaterName    = "api-address-updater"
+	APICallerName            = "api-caller"
+	APIInfoGateName          = "api-info-gate"
+	LoggingConfigUpdaterName = "logging-config-updater"
+	ProxyConfigUpdaterName   = "proxy-config-updater"
+	UpgradeWaiterName        = "upgrade-waiter"
+)
@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/jujud/agent/machine"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	dependencytesting "github.com/juju/juju/worker/dependency/testing"
+)
+
+type ManifoldsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ManifoldsSuite{})
+
+func (s *ManifoldsSuite) TestStartFuncs(c *gc.C) {
+	manifolds := machine.Manifolds(machine.ManifoldsConfig{
+		Agent: fakeAgent{},
+	})
+
+	for name, manifold := range manifolds {
+		c.Logf("checking %q manifold", name)
+		c.Check(manifold.Start, gc.NotNil)
+	}
+}
+
+func (s *ManifoldsSuite) TestAcyclic(c *gc.C) {
+	manifolds := machine.Manifolds(machine.ManifoldsConfig{
+		Agent: fakeAgent{},
+	})
+	err := dependency.Validate(manifolds)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
+	manifolds := machine.Manifolds(machine.ManifoldsConfig{})
+	expectedKeys := []string{
+		machine.AgentName,
+		machine.APIAddressUpdaterName,
+		machine.APICallerName,
+		machine.APIInfoGateName,
+		machine.LoggingConfigUpdaterName,
+		machine.ProxyConfigUpdaterName,
+		machine.UpgradeWaiterName,
+	}
+	keys := make([]string, 0, len(manifolds))
+	for k := range manifolds {
+		keys = append(keys, k)
+	}
+	c.Assert(expectedKeys, jc.SameContents, keys)
+}
+
+func (s *ManifoldsSuite) TestUpgradeWaiterAlreadyUpgraded(c *gc.C) {
+	done := make(chan struct{})
+	close(done)
+	manifolds := machine.Manifolds(machine.ManifoldsConfig{
+		UpgradeStepsComplete: done,
+		UpgradeCheckComplete: done,
+	})
+	manifold := manifolds[machine.UpgradeWaiterName]
+	c.Assert(manifold.Inputs, gc.HasLen, 0)
+
+	w, err := manifold.Start(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	var upgraded bool
+	err = manifold.Output(w, &upgraded)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upgraded, jc.IsTrue)
+
+	var wrong base.APICaller
+	err = manifold.Output(w, &wrong)
+	c.Assert(err, gc.ErrorMatches, `expected \*machine.upgradeWaiter->\*bool; got .*`)
+}
+
+func (s *ManifoldsSuite) TestUpgradeWaiterBouncesWhenUpgraded(c *gc.C) {
+	stepsDone := make(chan struct{})
+	checkDone := make(chan struct{})
+	manifolds := machine.Manifolds(machine.ManifoldsConfig{
+		UpgradeStepsComplete: stepsDone,
+		UpgradeCheckComplete: checkDone,
+	})
+	manifold := manifolds[machine.UpgradeWaiterName]
+
+	w, err := manifold.Start(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	var upgraded bool
+	err = manifold.Output(w, &upgraded)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upgraded, jc.IsFalse)
+
+	close(stepsDone)
+	close(checkDone)
+	errc := make(chan error)
+	go func() {
+		errc <- w.Wait()
+	}()
+	select {
+	case err := <-errc:
+		c.Assert(err, gc.Equals, dependency.ErrBounce)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("upgrade waiter did not bounce")
+	}
+}
+
+func (s *ManifoldsSuite) TestUpgradeGatedManifolds(c *gc.C) {
+	manifolds := machine.Manifolds(machine.ManifoldsConfig{})
+	getResource := dependencytesting.StubGetResource(dependencytesting.StubResources{
+		machine.UpgradeWaiterName: dependencytesting.StubResource{Output: false},
+	})
+	for _, name := range []string{
+		machine.APIAddressUpdaterName,
+		machine.LoggingConfigUpdaterName,
+		machine.ProxyConfigUpdaterName,
+	} {
+		c.Logf("checking %q manifold", name)
+		manifold := manifolds[name]
+		c.Check(manifold.Inputs, jc.Contains, machine.UpgradeWaiterName)
+		w, err := manifold.Start(getResource)
+		c.Check(w, gc.IsNil)
+		c.Check(err, gc.Equals, dependency.ErrMissing)
+	}
+}
+
+type fakeAgent struct {
+	agent.Agent
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// upgradeWaiterManifold returns a manifold whose worker reports, via a
+// *bool output, whether all the supplied channels have been closed. The
+// machine agent closes them when its upgrade steps have run and it has
+// checked it is running the right tools; until then, the worker waits,
+// and when they are all closed it bounces so that its dependents see
+// the change.
+func upgradeWaiterManifold(complete ...<-chan struct{}) dependency.Manifold {
+	return dependency.Manifold{
+		Start: func(_ dependency.GetResourceFunc) (worker.Worker, error) {
+			w := &upgradeWaiter{upgraded: allClosed(complete)}
+			go func() {
+				defer w.tomb.Done()
+				w.tomb.Kill(w.loop(complete))
+			}()
+			return w, nil
+		},
+		Output: upgradeWaiterOutput,
+	}
+}
+
+// upgradeWaiterOutput extracts a bool from its *upgradeWaiter.
+func upgradeWaiterOutput(in worker.Worker, out interface{}) error {
+	inWorker, _ := in.(*upgradeWaiter)
+	outPointer, _ := out.(*bool)
+	if inWorker == nil || outPointer == nil {
+		return errors.Errorf("expected %T->%T; got %T->%T", inWorker, outPointer, in, out)
+	}
+	*outPointer = inWorker.upgraded
+	return nil
+}
+
+// allClosed returns whether all the supplied channels are closed.
+func allClosed(chs []<-chan struct{}) bool {
+	for _, ch := range chs {
+		select {
+		case <-ch:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// upgradeWaiter is a worker.Worker that waits for upgrades to complete.
+type upgradeWaiter struct {
+	tomb     tomb.Tomb
+	upgraded bool
+}
+
+func (w *upgradeWaiter) loop(complete []<-chan struct{}) error {
+	if w.upgraded {
+		<-w.tomb.Dying()
+		return tomb.ErrDying
+	}
+	for _, ch := range complete {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-ch:
+		}
+	}
+	return dependency.ErrBounce
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *upgradeWaiter) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *upgradeWaiter) Wait() error {
+	return w.tomb.Wait()
+}
+
+// ifUpgraded returns a copy of the supplied manifold that also depends
+// on the named upgrade waiter, and only starts its worker once upgrades
+// are complete.
+func ifUpgraded(upgradeWaiterName string, manifold dependency.Manifold) dependency.Manifold {
+	start := manifold.Start
+	manifold.Inputs = append([]string{upgradeWaiterName}, manifold.Inputs...)
+	manifold.Start = func(getResource dependency.GetResourceFunc) (worker.Worker, error) {
+		var upgraded bool
+		if err := getResource(upgradeWaiterName, &upgraded); err != nil {
+			return nil, err
+		}
+		if !upgraded {
+			return nil, dependency.ErrMissing
+		}
+		return start(getResource)
+	}
+	return manifold
+}
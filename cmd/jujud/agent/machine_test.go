@@ -716,7 +716,7 @@ func (s *MachineSuite) TestManageEnvironRunsInstancePoller(c *gc.C) {
 		c.Assert(err, jc.ErrorIsNil)
 		instStatus, err := m.InstanceStatus()
 		c.Assert(err, jc.ErrorIsNil)
-		if reflect.DeepEqual(m.Addresses(), addrs) && instStatus == "running" {
+		if reflect.DeepEqual(m.Addresses(), addrs) && instStatus.Status == "running" {
 			break
 		}
 	}
@@ -829,6 +829,54 @@ func (s *MachineSuite) TestManageEnvironRunsStatusHistoryPruner(c *gc.C) {
 	runner.waitForWorker(c, "statushistorypruner")
 }
 
+func (s *MachineSuite) TestManageEnvironRunsAutoscaler(c *gc.C) {
+	s.AgentSuite.PatchValue(&supportsInstanceGroups, func(api.Connection) (bool, error) {
+		return true, nil
+	})
+	m, _, _ := s.primeAgent(c, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	_ = s.singularRecord.nextRunner(c)
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "autoscaler")
+}
+
+func (s *MachineSuite) TestManageEnvironDoesNotRunAutoscalerWithoutInstanceGroups(c *gc.C) {
+	// The dummy provider does not support instance groups.
+	s.assertNoAutoscaler(c)
+}
+
+func (s *MachineSuite) TestManageEnvironDoesNotRunAutoscalerIfInstanceGroupsUnknown(c *gc.C) {
+	s.AgentSuite.PatchValue(&supportsInstanceGroups, func(api.Connection) (bool, error) {
+		return false, errors.New("cannot open environment")
+	})
+	s.assertNoAutoscaler(c)
+}
+
+// assertNoAutoscaler checks that the environment workers are started,
+// but the autoscaler is not.
+func (s *MachineSuite) assertNoAutoscaler(c *gc.C) {
+	m, _, _ := s.primeAgent(c, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	_ = s.singularRecord.nextRunner(c)
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "statushistorypruner")
+	timeout := time.After(startWorkerWait)
+	for {
+		select {
+		case name := <-runner.startC:
+			c.Assert(name, gc.Not(gc.Equals), "autoscaler")
+		case <-timeout:
+			return
+		}
+	}
+}
+
 func (s *MachineSuite) TestManageEnvironCallsUseMultipleCPUs(c *gc.C) {
 	// If it has been enabled, the JobManageEnviron agent should call utils.UseMultipleCPUs
 	usefulVersion := version.Binary{
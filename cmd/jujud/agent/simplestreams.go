@@ -9,16 +9,25 @@ import (
 	"io"
 	"io/ioutil"
 	"path"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/series"
 
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
 	envstorage "github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/state/cloudimagemetadata"
 	"github.com/juju/juju/state/storage"
 )
 
 const (
 	storageDataSourceId          = "environment storage"
 	storageDataSourceDescription = storageDataSourceId
+
+	customImageDataSourceId          = "custom image metadata"
+	customImageDataSourceDescription = customImageDataSourceId
 )
 
 // environmentStorageDataSource is a simplestreams.DataSource that
@@ -77,3 +86,99 @@ func registerSimplestreamsDataSource(stor storage.Storage) {
 func unregisterSimplestreamsDataSource() {
 	environs.UnregisterImageDataSourceFunc(storageDataSourceId)
 }
+
+// customImageDataSource is a simplestreams.DataSource that serves the
+// custom image metadata recorded in state, such as that added with
+// "juju metadata add-image", as unsigned simplestreams image metadata.
+type customImageDataSource struct {
+	stor cloudimagemetadata.Storage
+}
+
+// NewCustomImageDataSource returns a new datasource that serves the
+// custom image metadata held in the given storage.
+func NewCustomImageDataSource(stor cloudimagemetadata.Storage) simplestreams.DataSource {
+	return customImageDataSource{stor}
+}
+
+// Description is defined in simplestreams.DataSource.
+func (d customImageDataSource) Description() string {
+	return customImageDataSourceDescription
+}
+
+// Fetch is defined in simplestreams.DataSource.
+func (d customImageDataSource) Fetch(file string) (io.ReadCloser, string, error) {
+	url, _ := d.URL(file)
+	isIndex := file == simplestreams.UnsignedIndex("v1", 1) || file == simplestreams.UnsignedIndex("v1", 2)
+	if !isIndex && file != imagemetadata.ProductMetadataPath {
+		return nil, url, errors.NotFoundf("%q", url)
+	}
+	metadata, err := d.customImageMetadata()
+	if err != nil {
+		return nil, url, err
+	}
+	if len(metadata) == 0 {
+		return nil, url, errors.NotFoundf("%q", url)
+	}
+	var data []byte
+	if isIndex {
+		data, err = imagemetadata.MarshalImageMetadataIndexJSON(metadata, nil, time.Now())
+	} else {
+		data, err = imagemetadata.MarshalImageMetadataProductsJSON(metadata, time.Now())
+	}
+	if err != nil {
+		return nil, url, errors.Trace(err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), url, nil
+}
+
+// customImageMetadata returns the custom image metadata held in state.
+func (d customImageDataSource) customImageMetadata() ([]*imagemetadata.ImageMetadata, error) {
+	found, err := d.stor.FindMetadata(cloudimagemetadata.MetadataFilter{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read custom image metadata")
+	}
+	var metadata []*imagemetadata.ImageMetadata
+	for _, m := range found[cloudimagemetadata.Custom] {
+		version, err := series.SeriesVersion(m.Series)
+		if err != nil {
+			logger.Warningf("ignoring custom image %q: %v", m.ImageId, err)
+			continue
+		}
+		metadata = append(metadata, &imagemetadata.ImageMetadata{
+			Id:         m.ImageId,
+			Storage:    m.RootStorageType,
+			VirtType:   m.VirtType,
+			Arch:       m.Arch,
+			Version:    version,
+			RegionName: m.Region,
+			Stream:     m.Stream,
+		})
+	}
+	return metadata, nil
+}
+
+// URL is defined in simplestreams.DataSource.
+func (d customImageDataSource) URL(file string) (string, error) {
+	return fmt.Sprintf("custom-image-metadata://%s", file), nil
+}
+
+// Defined in simplestreams.DataSource.
+func (d customImageDataSource) SetAllowRetry(allow bool) {
+}
+
+// registerCustomImageDataSource registers a customImageDataSource. It
+// is registered as a user datasource, so custom images are preferred
+// to those in any other image metadata.
+func registerCustomImageDataSource(stor cloudimagemetadata.Storage) {
+	ds := NewCustomImageDataSource(stor)
+	environs.RegisterUserImageDataSourceFunc(customImageDataSourceId, func(environs.Environ) (simplestreams.DataSource, error) {
+		return ds, nil
+	})
+}
+
+// unregisterCustomImageDataSource de-registers a customImageDataSource.
+func unregisterCustomImageDataSource() {
+	environs.UnregisterImageDataSourceFunc(customImageDataSourceId)
+}
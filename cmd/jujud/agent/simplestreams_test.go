@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/state/cloudimagemetadata"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type customImageDataSourceSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&customImageDataSourceSuite{})
+
+type fakeImageMetadataStorage struct {
+	cloudimagemetadata.Storage
+	metadata map[cloudimagemetadata.SourceType][]cloudimagemetadata.Metadata
+}
+
+func (s *fakeImageMetadataStorage) FindMetadata(cloudimagemetadata.MetadataFilter) (map[cloudimagemetadata.SourceType][]cloudimagemetadata.Metadata, error) {
+	if len(s.metadata) == 0 {
+		return nil, errors.NotFoundf("matching cloud image metadata")
+	}
+	return s.metadata, nil
+}
+
+func customImage(imageId, region, series string, source cloudimagemetadata.SourceType) cloudimagemetadata.Metadata {
+	return cloudimagemetadata.Metadata{
+		MetadataAttributes: cloudimagemetadata.MetadataAttributes{
+			Stream: "released",
+			Region: region,
+			Series: series,
+			Arch:   "amd64",
+			Source: source,
+		},
+		ImageId: imageId,
+	}
+}
+
+func (s *customImageDataSourceSuite) fetch(c *gc.C, stor cloudimagemetadata.Storage, region string) ([]*imagemetadata.ImageMetadata, error) {
+	ds := NewCustomImageDataSource(stor)
+	cons := imagemetadata.NewImageConstraint(simplestreams.LookupParams{
+		CloudSpec: simplestreams.CloudSpec{Region: region},
+		Series:    []string{"trusty"},
+		Arches:    []string{"amd64"},
+		Stream:    "released",
+	})
+	images, _, err := imagemetadata.Fetch([]simplestreams.DataSource{ds}, cons, false)
+	return images, err
+}
+
+func (s *customImageDataSourceSuite) TestFetchCustomImages(c *gc.C) {
+	stor := &fakeImageMetadataStorage{
+		metadata: map[cloudimagemetadata.SourceType][]cloudimagemetadata.Metadata{
+			cloudimagemetadata.Custom: {
+				customImage("custom-1", "region-1", "trusty", cloudimagemetadata.Custom),
+				customImage("custom-2", "region-2", "trusty", cloudimagemetadata.Custom),
+				customImage("custom-3", "region-1", "precise", cloudimagemetadata.Custom),
+			},
+			cloudimagemetadata.Public: {
+				customImage("public-1", "region-1", "trusty", cloudimagemetadata.Public),
+			},
+		},
+	}
+	images, err := s.fetch(c, stor, "region-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(images, gc.HasLen, 1)
+	c.Check(images[0].Id, gc.Equals, "custom-1")
+	c.Check(images[0].RegionName, gc.Equals, "region-1")
+	c.Check(images[0].Version, gc.Equals, "14.04")
+}
+
+func (s *customImageDataSourceSuite) TestFetchNoCustomImages(c *gc.C) {
+	_, err := s.fetch(c, &fakeImageMetadataStorage{}, "region-1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *customImageDataSourceSuite) TestFetchUnknownFile(c *gc.C) {
+	ds := NewCustomImageDataSource(&fakeImageMetadataStorage{})
+	_, url, err := ds.Fetch("streams/v1/mirrors.json")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(url, gc.Equals, "custom-image-metadata://streams/v1/mirrors.json")
+}
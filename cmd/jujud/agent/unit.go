@@ -136,6 +136,7 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 	// should move back to the upgrade package when we do unify the agents.
 	runUpgrades(agentConfig.Tag(), agentConfig.DataDir())
 
+	a.runner.StartWorker("introspection", introspectionWorkerStarter(a, a.runner))
 	a.runner.StartWorker("api", a.APIWorkers)
 	err := cmdutil.AgentDone(logger, a.runner.Wait())
 	a.tomb.Kill(err)
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/worker/metrics/collect"
 	"github.com/juju/juju/worker/metrics/sender"
 	"github.com/juju/juju/worker/metrics/spool"
+	"github.com/juju/juju/worker/processhealth"
 	"github.com/juju/juju/worker/proxyupdater"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/uniter"
@@ -183,6 +184,15 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			MachineLockName: MachineLockName,
 		}),
 
+		// The process health worker periodically checks the workload
+		// processes launched by the unit, and relaunches those that have
+		// failed if their restart policies say so.
+		ProcessHealthName: processhealth.Manifold(processhealth.ManifoldConfig{
+			AgentName:       AgentName,
+			APICallerName:   APICallerName,
+			MachineLockName: MachineLockName,
+		}),
+
 		// The metric sender worker periodically sends accumulated metrics to the state server.
 		MetricSenderName: sender.Manifold(sender.ManifoldConfig{
 			APICallerName:   APICallerName,
@@ -209,4 +219,5 @@ const (
 	MeterStatusName          = "meter-status"
 	MetricCollectName        = "metric-collect"
 	MetricSenderName         = "metric-sender"
+	ProcessHealthName        = "process-health"
 )
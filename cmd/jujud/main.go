@@ -22,10 +22,13 @@ import (
 	agentcmd "github.com/juju/juju/cmd/jujud/agent"
 	"github.com/juju/juju/juju/names"
 	"github.com/juju/juju/juju/sockets"
+	// Import the process launch plugins.
+	_ "github.com/juju/juju/process/plugin/all"
 	// Import the providers.
 	_ "github.com/juju/juju/provider/all"
 	"github.com/juju/juju/storage/looputil"
 	"github.com/juju/juju/worker/logsender"
+	"github.com/juju/juju/worker/problemreporter"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
@@ -124,6 +127,11 @@ func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
 	if err != nil {
 		return 1, errors.Trace(err)
 	}
+	// Remember the most recent log lines for inclusion in problem
+	// reports.
+	if err := problemreporter.InstallLogTail(500); err != nil {
+		return 1, errors.Trace(err)
+	}
 
 	jujud := jujucmd.NewSuperCommand(cmd.SuperCommandParams{
 		Name: "jujud",
@@ -24,6 +24,10 @@ Add image metadata to Juju environment.
 Image metadata properties vary between providers. Consequently, some properties
 are optional for this command but they may still be needed by your provider.
 
+Images added with this command are preferred to those described by published
+simplestreams metadata when a new machine is provisioned, so private clouds
+without published image metadata can deploy their own images.
+
 options:
 -e, --environment (= "")
    juju environment to operate in
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -20,18 +21,40 @@ import (
 // The following constants list the supported constraint attribute names, as defined
 // by the fields in the Value struct.
 const (
-	Arch         = "arch"
-	Container    = "container"
-	CpuCores     = "cpu-cores"
-	CpuPower     = "cpu-power"
-	Mem          = "mem"
-	RootDisk     = "root-disk"
-	Tags         = "tags"
-	InstanceType = "instance-type"
-	Networks     = "networks"
-	Spaces       = "spaces"
+	Arch           = "arch"
+	Container      = "container"
+	CpuCores       = "cpu-cores"
+	CpuPower       = "cpu-power"
+	Mem            = "mem"
+	RootDisk       = "root-disk"
+	RootDiskSource = "root-disk-source"
+	Tags           = "tags"
+	InstanceType   = "instance-type"
+	Networks       = "networks"
+	Profile        = "profile"
+	Spaces         = "spaces"
+	VirtType       = "virt-type"
+	Zones          = "zones"
+
+	// The following attributes hold the upper bounds of numeric
+	// constraints given as ranges, such as "mem=4G..16G". They cannot
+	// be specified directly.
+	MaxCpuCores = "max-cpu-cores"
+	MaxCpuPower = "max-cpu-power"
+	MaxMem      = "max-mem"
+	MaxRootDisk = "max-root-disk"
 )
 
+// rangeAttributes maps the numeric constraint attributes that may be
+// specified as a min..max range to the attributes holding their upper
+// bounds.
+var rangeAttributes = map[string]string{
+	CpuCores: MaxCpuCores,
+	CpuPower: MaxCpuPower,
+	Mem:      MaxMem,
+	RootDisk: MaxRootDisk,
+}
+
 // Value describes a user's requirements of the hardware on which units
 // of a service will run. Constraints are used to choose an existing machine
 // onto which a unit will be deployed, or to provision a new machine if no
@@ -49,15 +72,29 @@ type Value struct {
 	// number of effective cores available.
 	CpuCores *uint64 `json:"cpu-cores,omitempty" yaml:"cpu-cores,omitempty"`
 
+	// MaxCpuCores, if not nil, indicates that a machine must have at most
+	// that number of effective cores available. It is only set along with
+	// CpuCores.
+	MaxCpuCores *uint64 `json:"max-cpu-cores,omitempty" yaml:"max-cpu-cores,omitempty"`
+
 	// CpuPower, if not nil, indicates that a machine must have at least that
 	// amount of CPU power available, where 100 CpuPower is considered to be
 	// equivalent to 1 Amazon ECU (or, roughly, a single 2007-era Xeon).
 	CpuPower *uint64 `json:"cpu-power,omitempty" yaml:"cpu-power,omitempty"`
 
+	// MaxCpuPower, if not nil, indicates that a machine must have at most
+	// that amount of CPU power available. It is only set along with
+	// CpuPower.
+	MaxCpuPower *uint64 `json:"max-cpu-power,omitempty" yaml:"max-cpu-power,omitempty"`
+
 	// Mem, if not nil, indicates that a machine must have at least that many
 	// megabytes of RAM.
 	Mem *uint64 `json:"mem,omitempty" yaml:"mem,omitempty"`
 
+	// MaxMem, if not nil, indicates that a machine must have at most that
+	// many megabytes of RAM. It is only set along with Mem.
+	MaxMem *uint64 `json:"max-mem,omitempty" yaml:"max-mem,omitempty"`
+
 	// RootDisk, if not nil, indicates that a machine must have at least
 	// that many megabytes of disk space available in the root disk. In
 	// providers where the root disk is configurable at instance startup
@@ -65,6 +102,16 @@ type Value struct {
 	// disk might be requested.
 	RootDisk *uint64 `json:"root-disk,omitempty" yaml:"root-disk,omitempty"`
 
+	// MaxRootDisk, if not nil, indicates that a machine must have at most
+	// that many megabytes of disk space available in the root disk. It is
+	// only set along with RootDisk.
+	MaxRootDisk *uint64 `json:"max-root-disk,omitempty" yaml:"max-root-disk,omitempty"`
+
+	// RootDiskSource, if not nil or empty, indicates the kind of storage
+	// that a machine's root disk must be provided by. Its values are
+	// provider-specific; for example, MAAS accepts "ssd" and "hdd".
+	RootDiskSource *string `json:"root-disk-source,omitempty" yaml:"root-disk-source,omitempty"`
+
 	// Tags, if not nil, indicates tags that the machine must have applied to it.
 	// An empty list is treated the same as a nil (unspecified) list, except an
 	// empty list will override any default tags, where a nil list will not.
@@ -88,6 +135,23 @@ type Value struct {
 	// TODO(dimitern): Drop this as soon as spaces can be used for
 	// deployments instead.
 	Networks *[]string `json:"networks,omitempty" yaml:"networks,omitempty"`
+
+	// VirtType, if not nil or empty, indicates that a machine must run
+	// the named virtualisation type. Only valid for providers that can
+	// start more than one kind of machine, such as the local provider's
+	// "lxc" and "kvm".
+	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
+
+	// Zones, if not nil, holds a list of availability zones limiting
+	// where the machine can be located. An empty list is treated the
+	// same as a nil (unspecified) list, except an empty list will
+	// override any default zones, where a nil list will not.
+	Zones *[]string `json:"zones,omitempty" yaml:"zones,omitempty"`
+
+	// Profile, if not nil or empty, names a constraint profile defined
+	// in the environment's configuration. The profile's constraints
+	// apply to any attributes not specified alongside it.
+	Profile *string `json:"profile,omitempty" yaml:"profile,omitempty"`
 }
 
 // fieldNames records a mapping from the constraint tag to struct field name.
@@ -125,6 +189,30 @@ func (v *Value) HasInstanceType() bool {
 	return v.InstanceType != nil && *v.InstanceType != ""
 }
 
+// HasRootDiskSource returns true if the constraints.Value specifies
+// the kind of storage providing the root disk.
+func (v *Value) HasRootDiskSource() bool {
+	return v.RootDiskSource != nil && *v.RootDiskSource != ""
+}
+
+// HaveZones returns whether any availability zone constraints were
+// specified.
+func (v *Value) HaveZones() bool {
+	return v.Zones != nil && len(*v.Zones) > 0
+}
+
+// HasVirtType returns true if the constraints.Value specifies a
+// virtualisation type.
+func (v *Value) HasVirtType() bool {
+	return v.VirtType != nil && *v.VirtType != ""
+}
+
+// HasProfile returns true if the constraints.Value names a
+// constraint profile.
+func (v *Value) HasProfile() bool {
+	return v.Profile != nil && *v.Profile != ""
+}
+
 // extractItems returns the list of entries in the given field which
 // are either positive (included) or negative (!included; with prefix
 // "^").
@@ -204,27 +292,22 @@ func (v Value) String() string {
 		strs = append(strs, "container="+string(*v.Container))
 	}
 	if v.CpuCores != nil {
-		strs = append(strs, "cpu-cores="+uintStr(*v.CpuCores))
+		strs = append(strs, "cpu-cores="+rangeStr(*v.CpuCores, v.MaxCpuCores, ""))
 	}
 	if v.CpuPower != nil {
-		strs = append(strs, "cpu-power="+uintStr(*v.CpuPower))
+		strs = append(strs, "cpu-power="+rangeStr(*v.CpuPower, v.MaxCpuPower, ""))
 	}
 	if v.InstanceType != nil {
 		strs = append(strs, "instance-type="+string(*v.InstanceType))
 	}
 	if v.Mem != nil {
-		s := uintStr(*v.Mem)
-		if s != "" {
-			s += "M"
-		}
-		strs = append(strs, "mem="+s)
+		strs = append(strs, "mem="+rangeStr(*v.Mem, v.MaxMem, "M"))
 	}
 	if v.RootDisk != nil {
-		s := uintStr(*v.RootDisk)
-		if s != "" {
-			s += "M"
-		}
-		strs = append(strs, "root-disk="+s)
+		strs = append(strs, "root-disk="+rangeStr(*v.RootDisk, v.MaxRootDisk, "M"))
+	}
+	if v.RootDiskSource != nil {
+		strs = append(strs, "root-disk-source="+*v.RootDiskSource)
 	}
 	if v.Tags != nil {
 		s := strings.Join(*v.Tags, ",")
@@ -238,6 +321,16 @@ func (v Value) String() string {
 		s := strings.Join(*v.Networks, ",")
 		strs = append(strs, "networks="+s)
 	}
+	if v.VirtType != nil {
+		strs = append(strs, "virt-type="+*v.VirtType)
+	}
+	if v.Zones != nil {
+		s := strings.Join(*v.Zones, ",")
+		strs = append(strs, "zones="+s)
+	}
+	if v.Profile != nil {
+		strs = append(strs, "profile="+*v.Profile)
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -251,15 +344,30 @@ func (v Value) GoString() string {
 	if v.CpuCores != nil {
 		values = append(values, fmt.Sprintf("CpuCores: %v", *v.CpuCores))
 	}
+	if v.MaxCpuCores != nil {
+		values = append(values, fmt.Sprintf("MaxCpuCores: %v", *v.MaxCpuCores))
+	}
 	if v.CpuPower != nil {
 		values = append(values, fmt.Sprintf("CpuPower: %v", *v.CpuPower))
 	}
+	if v.MaxCpuPower != nil {
+		values = append(values, fmt.Sprintf("MaxCpuPower: %v", *v.MaxCpuPower))
+	}
 	if v.Mem != nil {
 		values = append(values, fmt.Sprintf("Mem: %v", *v.Mem))
 	}
+	if v.MaxMem != nil {
+		values = append(values, fmt.Sprintf("MaxMem: %v", *v.MaxMem))
+	}
 	if v.RootDisk != nil {
 		values = append(values, fmt.Sprintf("RootDisk: %v", *v.RootDisk))
 	}
+	if v.MaxRootDisk != nil {
+		values = append(values, fmt.Sprintf("MaxRootDisk: %v", *v.MaxRootDisk))
+	}
+	if v.RootDiskSource != nil {
+		values = append(values, fmt.Sprintf("RootDiskSource: %q", *v.RootDiskSource))
+	}
 	if v.InstanceType != nil {
 		values = append(values, fmt.Sprintf("InstanceType: %q", *v.InstanceType))
 	}
@@ -281,6 +389,17 @@ func (v Value) GoString() string {
 	} else if v.Networks != nil {
 		values = append(values, "Networks: (*[]string)(nil)")
 	}
+	if v.VirtType != nil {
+		values = append(values, fmt.Sprintf("VirtType: %q", *v.VirtType))
+	}
+	if v.Zones != nil && *v.Zones != nil {
+		values = append(values, fmt.Sprintf("Zones: %q", *v.Zones))
+	} else if v.Zones != nil {
+		values = append(values, "Zones: (*[]string)(nil)")
+	}
+	if v.Profile != nil {
+		values = append(values, fmt.Sprintf("Profile: %q", *v.Profile))
+	}
 	return fmt.Sprintf("{%s}", strings.Join(values, ", "))
 }
 
@@ -291,6 +410,19 @@ func uintStr(i uint64) string {
 	return fmt.Sprintf("%d", i)
 }
 
+// rangeStr returns the string form of a numeric constraint with the
+// given lower bound and, if not nil, upper bound.
+func rangeStr(min uint64, max *uint64, suffix string) string {
+	if max == nil {
+		s := uintStr(min)
+		if s != "" {
+			s += suffix
+		}
+		return s
+	}
+	return fmt.Sprintf("%d%s..%d%s", min, suffix, *max, suffix)
+}
+
 // Parse constructs a constraints.Value from the supplied arguments,
 // each of which must contain only spaces and name=value pairs. If any
 // name is specified more than once, an error is returned.
@@ -389,6 +521,11 @@ func (v *Value) without(attrTags ...string) (Value, error) {
 			return Value{}, errors.Errorf("unknown constraint %q", tag)
 		}
 		val.Set(reflect.Zero(val.Type()))
+		// The upper bound of a range goes with its lower bound.
+		if maxTag, ok := rangeAttributes[tag]; ok {
+			maxVal, _ := result.fieldFromTag(maxTag)
+			maxVal.Set(reflect.Zero(maxVal.Type()))
+		}
 	}
 	return result, nil
 }
@@ -414,6 +551,8 @@ func (v *Value) setRaw(raw string) error {
 		err = v.setMem(str)
 	case RootDisk:
 		err = v.setRootDisk(str)
+	case RootDiskSource:
+		err = v.setRootDiskSource(str)
 	case Tags:
 		err = v.setTags(str)
 	case InstanceType:
@@ -422,6 +561,12 @@ func (v *Value) setRaw(raw string) error {
 		err = v.setSpaces(str)
 	case Networks:
 		err = v.setNetworks(str)
+	case VirtType:
+		err = v.setVirtType(str)
+	case Zones:
+		err = v.setZones(str)
+	case Profile:
+		err = v.setProfile(str)
 	default:
 		return errors.Errorf("unknown constraint %q", name)
 	}
@@ -452,6 +597,8 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			v.Container = &ctype
 		case InstanceType:
 			v.InstanceType = &vstr
+		case VirtType:
+			v.VirtType = &vstr
 		case CpuCores:
 			v.CpuCores, err = parseUint64(vstr)
 		case CpuPower:
@@ -460,6 +607,16 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			v.Mem, err = parseUint64(vstr)
 		case RootDisk:
 			v.RootDisk, err = parseUint64(vstr)
+		case MaxCpuCores:
+			v.MaxCpuCores, err = parseUint64(vstr)
+		case MaxCpuPower:
+			v.MaxCpuPower, err = parseUint64(vstr)
+		case MaxMem:
+			v.MaxMem, err = parseUint64(vstr)
+		case MaxRootDisk:
+			v.MaxRootDisk, err = parseUint64(vstr)
+		case RootDiskSource:
+			v.RootDiskSource = &vstr
 		case Tags:
 			v.Tags, err = parseYamlStrings("tags", val)
 		case Spaces:
@@ -482,6 +639,20 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			if err == nil {
 				v.Networks = networks
 			}
+		case Zones:
+			var zones *[]string
+			zones, err = parseYamlStrings("zones", val)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = v.validateZones(zones)
+			if err == nil {
+				v.Zones = zones
+			}
+		case Profile:
+			if err = validateProfile(vstr); err == nil {
+				v.Profile = &vstr
+			}
 		default:
 			return errors.Errorf("unknown constraint value: %v", k)
 		}
@@ -529,7 +700,7 @@ func (v *Value) setCpuCores(str string) (err error) {
 	if v.CpuCores != nil {
 		return errors.Errorf("already set")
 	}
-	v.CpuCores, err = parseUint64(str)
+	v.CpuCores, v.MaxCpuCores, err = parseRange(str, parseUint64)
 	return
 }
 
@@ -537,7 +708,7 @@ func (v *Value) setCpuPower(str string) (err error) {
 	if v.CpuPower != nil {
 		return errors.Errorf("already set")
 	}
-	v.CpuPower, err = parseUint64(str)
+	v.CpuPower, v.MaxCpuPower, err = parseRange(str, parseUint64)
 	return
 }
 
@@ -549,11 +720,19 @@ func (v *Value) setInstanceType(str string) error {
 	return nil
 }
 
+func (v *Value) setVirtType(str string) error {
+	if v.VirtType != nil {
+		return errors.Errorf("already set")
+	}
+	v.VirtType = &str
+	return nil
+}
+
 func (v *Value) setMem(str string) (err error) {
 	if v.Mem != nil {
 		return errors.Errorf("already set")
 	}
-	v.Mem, err = parseSize(str)
+	v.Mem, v.MaxMem, err = parseRange(str, parseSize)
 	return
 }
 
@@ -561,10 +740,18 @@ func (v *Value) setRootDisk(str string) (err error) {
 	if v.RootDisk != nil {
 		return errors.Errorf("already set")
 	}
-	v.RootDisk, err = parseSize(str)
+	v.RootDisk, v.MaxRootDisk, err = parseRange(str, parseSize)
 	return
 }
 
+func (v *Value) setRootDiskSource(str string) error {
+	if v.RootDiskSource != nil {
+		return errors.Errorf("already set")
+	}
+	v.RootDiskSource = &str
+	return nil
+}
+
 func (v *Value) setTags(str string) error {
 	if v.Tags != nil {
 		return errors.Errorf("already set")
@@ -623,6 +810,56 @@ func (v *Value) validateNetworks(networks *[]string) error {
 	return nil
 }
 
+func (v *Value) setZones(str string) error {
+	if v.Zones != nil {
+		return errors.Errorf("already set")
+	}
+	zones := parseCommaDelimited(str)
+	if err := v.validateZones(zones); err != nil {
+		return err
+	}
+	v.Zones = zones
+	return nil
+}
+
+func (v *Value) validateZones(zones *[]string) error {
+	if zones == nil {
+		return nil
+	}
+	for _, zone := range *zones {
+		if zone == "" {
+			return errors.Errorf("empty zone name")
+		}
+	}
+	return nil
+}
+
+func (v *Value) setProfile(str string) error {
+	if v.Profile != nil {
+		return errors.Errorf("already set")
+	}
+	if err := validateProfile(str); err != nil {
+		return err
+	}
+	v.Profile = &str
+	return nil
+}
+
+func validateProfile(name string) error {
+	if name != "" && !IsValidProfileName(name) {
+		return errors.Errorf("%q is not a valid profile name", name)
+	}
+	return nil
+}
+
+var validProfileName = regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$")
+
+// IsValidProfileName returns whether name is a valid constraint
+// profile name.
+func IsValidProfileName(name string) bool {
+	return validProfileName.MatchString(name)
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -653,6 +890,31 @@ func parseSize(str string) (*uint64, error) {
 	return &value, nil
 }
 
+// parseRange parses either a single lower bound, or a range of the
+// form "min..max", using parse to parse each bound. The upper bound
+// returned is nil if no range was given.
+func parseRange(str string, parse func(string) (*uint64, error)) (min, max *uint64, err error) {
+	i := strings.Index(str, "..")
+	if i < 0 {
+		min, err = parse(str)
+		return min, nil, err
+	}
+	minStr, maxStr := str[:i], str[i+len(".."):]
+	if minStr == "" || maxStr == "" {
+		return nil, nil, errors.Errorf("range %q must have a lower and an upper bound", str)
+	}
+	if min, err = parse(minStr); err != nil {
+		return nil, nil, err
+	}
+	if max, err = parse(maxStr); err != nil {
+		return nil, nil, err
+	}
+	if *max < *min {
+		return nil, nil, errors.Errorf("range %q upper bound is less than its lower bound", str)
+	}
+	return min, max, nil
+}
+
 // parseCommaDelimited returns the items in the value s. We expect the
 // items to be comma delimited strings.
 func parseCommaDelimited(s string) *[]string {
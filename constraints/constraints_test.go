@@ -135,6 +135,28 @@ var parseConstraintsTests = []struct {
 		summary: "double set cpu-cores separately",
 		args:    []string{"cpu-cores=128", "cpu-cores=1"},
 		err:     `bad "cpu-cores" constraint: already set`,
+	}, {
+		summary: "set cpu-cores range",
+		args:    []string{"cpu-cores=2..8"},
+	}, {
+		summary: "set cpu-cores range from zero",
+		args:    []string{"cpu-cores=0..8"},
+	}, {
+		summary: "set cpu-cores range without lower bound",
+		args:    []string{"cpu-cores=..8"},
+		err:     `bad "cpu-cores" constraint: range "..8" must have a lower and an upper bound`,
+	}, {
+		summary: "set cpu-cores range without upper bound",
+		args:    []string{"cpu-cores=2.."},
+		err:     `bad "cpu-cores" constraint: range "2.." must have a lower and an upper bound`,
+	}, {
+		summary: "set cpu-cores inverted range",
+		args:    []string{"cpu-cores=8..2"},
+		err:     `bad "cpu-cores" constraint: range "8..2" upper bound is less than its lower bound`,
+	}, {
+		summary: "set nonsense cpu-cores range",
+		args:    []string{"cpu-cores=2..cheese"},
+		err:     `bad "cpu-cores" constraint: must be a non-negative integer`,
 	},
 
 	// "cpu-power" in detail.
@@ -207,6 +229,20 @@ var parseConstraintsTests = []struct {
 		summary: "double set mem separately",
 		args:    []string{"mem=1G", "mem=2G"},
 		err:     `bad "mem" constraint: already set`,
+	}, {
+		summary: "set mem range",
+		args:    []string{"mem=4G..16G"},
+	}, {
+		summary: "set mem range with mixed suffixes",
+		args:    []string{"mem=512M..2G"},
+	}, {
+		summary: "set mem inverted range",
+		args:    []string{"mem=2G..512M"},
+		err:     `bad "mem" constraint: range "2G..512M" upper bound is less than its lower bound`,
+	}, {
+		summary: "set nonsense mem range",
+		args:    []string{"mem=4G..lots"},
+		err:     `bad "mem" constraint: must be a non-negative float with optional M/G/T/P suffix`,
 	},
 
 	// "root-disk" in detail.
@@ -310,6 +346,75 @@ var parseConstraintsTests = []struct {
 		args:    []string{"instance-type="},
 	},
 
+	// virt type
+	{
+		summary: "set virt type",
+		args:    []string{"virt-type=kvm"},
+	}, {
+		summary: "virt type empty",
+		args:    []string{"virt-type="},
+	}, {
+		summary: "double set virt type together",
+		args:    []string{"virt-type=kvm virt-type=lxc"},
+		err:     `bad "virt-type" constraint: already set`,
+	},
+
+	// root disk source
+	{
+		summary: "set root disk source",
+		args:    []string{"root-disk-source=ssd"},
+	}, {
+		summary: "root disk source empty",
+		args:    []string{"root-disk-source="},
+	}, {
+		summary: "double set root disk source together",
+		args:    []string{"root-disk-source=ssd root-disk-source=hdd"},
+		err:     `bad "root-disk-source" constraint: already set`,
+	},
+
+	// zones
+	{
+		summary: "single zone",
+		args:    []string{"zones=az1"},
+	}, {
+		summary: "multiple zones",
+		args:    []string{"zones=az1,az2"},
+	}, {
+		summary: "no zones",
+		args:    []string{"zones="},
+	}, {
+		summary: "empty zone name",
+		args:    []string{"zones=az1,,az2"},
+		err:     `bad "zones" constraint: empty zone name`,
+	}, {
+		summary: "double set zones together",
+		args:    []string{"zones=az1 zones=az2"},
+		err:     `bad "zones" constraint: already set`,
+	},
+
+	// profile
+	{
+		summary: "set profile",
+		args:    []string{"profile=db"},
+	}, {
+		summary: "set hyphenated profile",
+		args:    []string{"profile=big-db"},
+	}, {
+		summary: "clear profile",
+		args:    []string{"profile="},
+	}, {
+		summary: "invalid profile name",
+		args:    []string{"profile=Big_DB"},
+		err:     `bad "profile" constraint: "Big_DB" is not a valid profile name`,
+	}, {
+		summary: "double set profile together",
+		args:    []string{"profile=db profile=web"},
+		err:     `bad "profile" constraint: already set`,
+	}, {
+		summary: "profile with other constraints",
+		args:    []string{"profile=db mem=32G"},
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -344,6 +449,17 @@ func (s *ConstraintsSuite) TestParseConstraints(c *gc.C) {
 	}
 }
 
+func (s *ConstraintsSuite) TestParseRange(c *gc.C) {
+	cons := constraints.MustParse("mem=4G..16G cpu-cores=2..8")
+	c.Assert(cons, jc.DeepEquals, constraints.Value{
+		Mem:         uint64p(4096),
+		MaxMem:      uint64p(16384),
+		CpuCores:    uint64p(2),
+		MaxCpuCores: uint64p(8),
+	})
+	c.Assert(cons.String(), gc.Equals, "cpu-cores=2..8 mem=4096M..16384M")
+}
+
 func (s *ConstraintsSuite) TestMerge(c *gc.C) {
 	con1 := constraints.MustParse("arch=amd64 mem=4G")
 	con2 := constraints.MustParse("cpu-cores=42")
@@ -525,6 +641,10 @@ var constraintsRoundtripTests = []roundTrip{
 	{"RootDisk1", constraints.Value{RootDisk: nil}},
 	{"RootDisk2", constraints.Value{RootDisk: uint64p(0)}},
 	{"RootDisk2", constraints.Value{RootDisk: uint64p(109876)}},
+	{"CpuCoresRange", constraints.Value{CpuCores: uint64p(2), MaxCpuCores: uint64p(8)}},
+	{"CpuPowerRange", constraints.Value{CpuPower: uint64p(0), MaxCpuPower: uint64p(500)}},
+	{"MemRange", constraints.Value{Mem: uint64p(4096), MaxMem: uint64p(16384)}},
+	{"RootDiskRange", constraints.Value{RootDisk: uint64p(8192), MaxRootDisk: uint64p(8192)}},
 	{"Tags1", constraints.Value{Tags: nil}},
 	{"Tags2", constraints.Value{Tags: &[]string{}}},
 	{"Tags3", constraints.Value{Tags: &[]string{"foo", "bar"}}},
@@ -536,6 +656,15 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Networks3", constraints.Value{Networks: &[]string{"net1", "^net2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
+	{"VirtType1", constraints.Value{VirtType: strp("")}},
+	{"VirtType2", constraints.Value{VirtType: strp("kvm")}},
+	{"RootDiskSource1", constraints.Value{RootDiskSource: strp("")}},
+	{"RootDiskSource2", constraints.Value{RootDiskSource: strp("ssd")}},
+	{"Zones1", constraints.Value{Zones: nil}},
+	{"Zones2", constraints.Value{Zones: &[]string{}}},
+	{"Zones3", constraints.Value{Zones: &[]string{"az1", "az2"}}},
+	{"Profile1", constraints.Value{Profile: strp("")}},
+	{"Profile2", constraints.Value{Profile: strp("db")}},
 	{"All", constraints.Value{
 		Arch:         strp("i386"),
 		Container:    ctypep("lxc"),
@@ -547,6 +676,7 @@ var constraintsRoundtripTests = []roundTrip{
 		Spaces:       &[]string{"space1", "^space2"},
 		Networks:     &[]string{"net1", "^net2"},
 		InstanceType: strp("foo"),
+		VirtType:     strp("kvm"),
 	}},
 }
 
@@ -617,6 +747,43 @@ func (s *ConstraintsSuite) TestHasContainer(c *gc.C) {
 	}
 }
 
+func (s *ConstraintsSuite) TestHasVirtType(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasVirtType(), jc.IsFalse)
+	cons = constraints.MustParse("virt-type=")
+	c.Check(cons.HasVirtType(), jc.IsFalse)
+	cons = constraints.MustParse("arch=amd64 virt-type=kvm")
+	c.Check(cons.HasVirtType(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHasRootDiskSource(c *gc.C) {
+	cons := constraints.MustParse("root-disk=8G")
+	c.Check(cons.HasRootDiskSource(), jc.IsFalse)
+	cons = constraints.MustParse("root-disk-source=")
+	c.Check(cons.HasRootDiskSource(), jc.IsFalse)
+	cons = constraints.MustParse("root-disk=8G root-disk-source=ssd")
+	c.Check(cons.HasRootDiskSource(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHaveZones(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HaveZones(), jc.IsFalse)
+	cons = constraints.MustParse("zones=")
+	c.Check(cons.HaveZones(), jc.IsFalse)
+	cons = constraints.MustParse("zones=az1,az2")
+	c.Check(cons.HaveZones(), jc.IsTrue)
+	c.Check(*cons.Zones, gc.DeepEquals, []string{"az1", "az2"})
+}
+
+func (s *ConstraintsSuite) TestHasProfile(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasProfile(), jc.IsFalse)
+	cons = constraints.MustParse("profile=")
+	c.Check(cons.HasProfile(), jc.IsFalse)
+	cons = constraints.MustParse("profile=db")
+	c.Check(cons.HasProfile(), jc.IsTrue)
+}
+
 func (s *ConstraintsSuite) TestHasInstanceType(c *gc.C) {
 	cons := constraints.MustParse("arch=amd64")
 	c.Check(cons.HasInstanceType(), jc.IsFalse)
@@ -695,7 +862,7 @@ func (s *ConstraintsSuite) TestWithoutError(c *gc.C) {
 func (s *ConstraintsSuite) TestAttributesWithValues(c *gc.C) {
 	for i, consStr := range []string{
 		"",
-		"root-disk=8G mem=4G arch=amd64 cpu-power=1000 cpu-cores=4 instance-type=foo tags=foo,bar spaces=space1,^space2",
+		"root-disk=8G mem=4G arch=amd64 cpu-power=1000 cpu-cores=4 instance-type=foo tags=foo,bar spaces=space1,^space2 virt-type=kvm",
 	} {
 		c.Logf("test %d", i)
 		cons := constraints.MustParse(consStr)
@@ -749,6 +916,11 @@ func (s *ConstraintsSuite) TestAttributesWithValues(c *gc.C) {
 		} else {
 			assertMissing("instance-type")
 		}
+		if cons.VirtType != nil {
+			c.Check(obtained["virt-type"], gc.Equals, *cons.VirtType)
+		} else {
+			assertMissing("virt-type")
+		}
 	}
 }
 
@@ -184,6 +184,16 @@ func withFallbacks(v Value, vFallback Value) Value {
 			resultVal.Set(val)
 		}
 	}
+	// A range is taken as a whole, so a lower bound without an upper
+	// bound replaces any fallback range.
+	for minTag, maxTag := range rangeAttributes {
+		if !reflect.ValueOf(&v).Elem().FieldByName(fieldNames[minTag]).IsNil() {
+			maxField := fieldNames[maxTag]
+			reflect.ValueOf(&result).Elem().FieldByName(maxField).Set(
+				reflect.ValueOf(&v).Elem().FieldByName(maxField),
+			)
+		}
+	}
 	return result
 }
 
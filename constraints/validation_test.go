@@ -313,6 +313,22 @@ var mergeTests = []struct {
 		reds:         []string{"mem", "arch"},
 		blues:        []string{"instance-type"},
 		expected:     "root-disk=8G cpu-cores=4 arch=amd64 mem=4G",
+	}, {
+		desc:         "mem range from fallback",
+		consFallback: "mem=4G..16G",
+		expected:     "mem=4G..16G",
+	}, {
+		desc:         "mem range replaced by lower bound",
+		consFallback: "mem=4G..16G",
+		cons:         "mem=8G",
+		expected:     "mem=8G",
+	}, {
+		desc:         "mem range masked from fallback by conflict",
+		consFallback: "mem=4G..16G",
+		cons:         "instance-type=bar",
+		reds:         []string{"mem"},
+		blues:        []string{"instance-type"},
+		expected:     "instance-type=bar",
 	},
 }
 
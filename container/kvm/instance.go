@@ -23,11 +23,11 @@ func (kvm *kvmInstance) Id() instance.Id {
 }
 
 // Status implements instance.Instance.Status.
-func (kvm *kvmInstance) Status() string {
+func (kvm *kvmInstance) Status() instance.InstanceStatus {
 	if kvm.container.IsRunning() {
-		return "running"
+		return instance.InstanceStatus{Status: "running"}
 	}
-	return "stopped"
+	return instance.InstanceStatus{Status: "stopped"}
 }
 
 func (*kvmInstance) Refresh() error {
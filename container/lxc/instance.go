@@ -26,10 +26,10 @@ func (lxc *lxcInstance) Id() instance.Id {
 }
 
 // Status implements instance.Instance.Status.
-func (lxc *lxcInstance) Status() string {
+func (lxc *lxcInstance) Status() instance.InstanceStatus {
 	// On error, the state will be "unknown".
 	state, _, _ := lxc.Info()
-	return string(state)
+	return instance.InstanceStatus{Status: string(state)}
 }
 
 func (*lxcInstance) Refresh() error {
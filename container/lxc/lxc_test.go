@@ -846,13 +846,13 @@ func (s *LxcSuite) TestContainerState(c *gc.C) {
 	instance := containertesting.CreateContainer(c, manager, "1/lxc/0")
 
 	// The mock container will be immediately "running".
-	c.Assert(instance.Status(), gc.Equals, string(golxc.StateRunning))
+	c.Assert(instance.Status().Status, gc.Equals, string(golxc.StateRunning))
 
 	// DestroyContainer stops and then destroys the container, putting it
 	// into "unknown" state.
 	err := manager.DestroyContainer(instance.Id())
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(instance.Status(), gc.Equals, string(golxc.StateUnknown))
+	c.Assert(instance.Status().Status, gc.Equals, string(golxc.StateUnknown))
 }
 
 func (s *LxcSuite) TestDestroyContainer(c *gc.C) {
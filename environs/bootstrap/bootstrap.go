@@ -44,6 +44,12 @@ type BootstrapParams struct {
 	// directive used to choose the initial instance.
 	Placement string
 
+	// BootstrapHost, if non-empty, holds the address of an existing
+	// host, as [user@]host, to use as the initial state server in
+	// place of a new instance. The host is set up over SSH, as with
+	// the manual provider.
+	BootstrapHost string
+
 	// UploadTools reports whether we should upload the local tools and
 	// override the environment's specified agent-version.
 	UploadTools bool
@@ -96,7 +102,10 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 		return err
 	}
 
-	_, supportsNetworking := environs.SupportsNetworking(environ)
+	supportsNetworking, err := environs.HasCapability(cfg.Type(), environs.CapabilityNetworking)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	ctx.Infof("Bootstrapping environment %q", cfg.Name())
 	logger.Debugf("environment %q supports service/machine networks: %v", cfg.Name(), supportsNetworking)
@@ -130,12 +139,22 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 		return err
 	}
 
-	ctx.Infof("Starting new instance for initial state server")
-	arch, series, finalizer, err := environ.Bootstrap(ctx, environs.BootstrapParams{
-		Constraints:    args.Constraints,
-		Placement:      args.Placement,
-		AvailableTools: availableTools,
-	})
+	var arch, series string
+	var finalizer environs.BootstrapFinalizer
+	if args.BootstrapHost != "" {
+		if args.Placement != "" {
+			return errors.New("cannot specify both a placement directive and a bootstrap host")
+		}
+		ctx.Infof("Using existing host %s for initial state server", args.BootstrapHost)
+		arch, series, finalizer, err = bootstrapExistingHost(ctx, environ, args.BootstrapHost, args.Constraints)
+	} else {
+		ctx.Infof("Starting new instance for initial state server")
+		arch, series, finalizer, err = environ.Bootstrap(ctx, environs.BootstrapParams{
+			Constraints:    args.Constraints,
+			Placement:      args.Placement,
+			AvailableTools: availableTools,
+		})
+	}
 	if err != nil {
 		return err
 	}
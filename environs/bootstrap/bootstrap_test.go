@@ -5,6 +5,7 @@ package bootstrap_test
 
 import (
 	"fmt"
+	"io"
 	"runtime"
 	"strings"
 	stdtesting "testing"
@@ -22,13 +23,17 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/storage"
 	envtesting "github.com/juju/juju/environs/testing"
 	envtools "github.com/juju/juju/environs/tools"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/dummy"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/tools"
+	"github.com/juju/juju/utils/ssh"
 	"github.com/juju/juju/version"
 )
 
@@ -122,6 +127,141 @@ func (s *bootstrapSuite) TestBootstrapSpecifiedPlacement(c *gc.C) {
 	c.Assert(env.args.Placement, gc.DeepEquals, placement)
 }
 
+func (s *bootstrapSuite) patchExistingHost(c *gc.C, env *bootstrapEnviron, provisioned bool) (configured *string, icfg **instancecfg.InstanceConfig, saved *[]instance.Id) {
+	configured = new(string)
+	icfg = new(*instancecfg.InstanceConfig)
+	saved = new([]instance.Id)
+	s.PatchValue(&manual.CheckProvisioned, func(host string) (bool, error) {
+		c.Check(host, gc.Equals, "10.0.0.1")
+		return provisioned, nil
+	})
+	s.PatchValue(&manual.DetectSeriesAndHardwareCharacteristics, func(host string) (instance.HardwareCharacteristics, string, error) {
+		hostArch := arch.HostArch()
+		mem := uint64(4096)
+		cores := uint64(2)
+		return instance.HardwareCharacteristics{Arch: &hostArch, Mem: &mem, CpuCores: &cores}, series.HostSeries(), nil
+	})
+	s.PatchValue(bootstrap.ConfigureMachine, func(_ environs.BootstrapContext, _ ssh.Client, host string, instanceConfig *instancecfg.InstanceConfig) error {
+		*configured = host
+		*icfg = instanceConfig
+		// The host is recorded before it is configured.
+		state, err := common.LoadState(env.storage)
+		c.Check(err, jc.ErrorIsNil)
+		if err == nil {
+			*saved = state.StateInstances
+		}
+		return nil
+	})
+	return configured, icfg, saved
+}
+
+func (s *bootstrapSuite) TestBootstrapExistingHost(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	configured, icfg, saved := s.patchExistingHost(c, env, false)
+	s.PatchValue(bootstrap.InitUbuntuUser, func(host, login, authorizedKeys, via string, stdin io.Reader, stdout io.Writer) error {
+		c.Fatalf("unexpected call to InitUbuntuUser")
+		return nil
+	})
+
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		BootstrapHost: "10.0.0.1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.bootstrapCount, gc.Equals, 0)
+	c.Assert(*configured, gc.Equals, "10.0.0.1")
+	c.Assert((*icfg).InstanceId, gc.Equals, instance.Id("manual:10.0.0.1"))
+	c.Assert(*saved, jc.DeepEquals, []instance.Id{"manual:10.0.0.1"})
+}
+
+func (s *bootstrapSuite) TestBootstrapExistingHostInitialisesUser(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	configured, _, _ := s.patchExistingHost(c, env, false)
+	var login string
+	s.PatchValue(bootstrap.InitUbuntuUser, func(host, user, authorizedKeys, via string, stdin io.Reader, stdout io.Writer) error {
+		c.Check(host, gc.Equals, "10.0.0.1")
+		c.Check(authorizedKeys, gc.Equals, env.Config().AuthorizedKeys())
+		login = user
+		return nil
+	})
+
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		BootstrapHost: "admin@10.0.0.1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(login, gc.Equals, "admin")
+	c.Assert(*configured, gc.Equals, "10.0.0.1")
+}
+
+func (s *bootstrapSuite) TestBootstrapExistingHostProvisioned(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	configured, _, _ := s.patchExistingHost(c, env, true)
+
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		BootstrapHost: "10.0.0.1",
+	})
+	c.Assert(err, gc.Equals, manual.ErrProvisioned)
+	c.Assert(*configured, gc.Equals, "")
+}
+
+func (s *bootstrapSuite) TestBootstrapExistingHostWithPlacement(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		BootstrapHost: "10.0.0.1",
+		Placement:     "directive",
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot specify both a placement directive and a bootstrap host")
+	c.Assert(env.bootstrapCount, gc.Equals, 0)
+}
+
+func (s *bootstrapSuite) TestBootstrapExistingHostConstraints(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	configured, _, _ := s.patchExistingHost(c, env, false)
+
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		BootstrapHost: "10.0.0.1",
+		Constraints:   constraints.MustParse("mem=2G cpu-cores=2"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*configured, gc.Equals, "10.0.0.1")
+}
+
+func (s *bootstrapSuite) TestBootstrapExistingHostUnsatisfiedConstraints(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	configured, _, _ := s.patchExistingHost(c, env, false)
+
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		BootstrapHost: "10.0.0.1",
+		Constraints:   constraints.MustParse("mem=8G max-cpu-cores=1 instance-type=m1.large"),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot bootstrap to "10.0.0.1": existing host does not satisfy constraints: max-cpu-cores=1 mem=8192 instance-type=m1.large`)
+	c.Assert(*configured, gc.Equals, "")
+}
+
+func (s *bootstrapSuite) TestBootstrapExistingHostStateServerNotReported(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	env.tagsStateServers = true
+	s.setDummyStorage(c, env)
+	configured, _, _ := s.patchExistingHost(c, env, false)
+
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		BootstrapHost: "10.0.0.1",
+	})
+	c.Assert(err, gc.ErrorMatches, `bootstrapping to an existing host with the "dummy" provider not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(*configured, gc.Equals, "")
+	_, err = common.LoadState(env.storage)
+	c.Assert(err, jc.ErrorIsNil)
+	ids, err := common.ProviderStateInstances(env, env.storage)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, gc.HasLen, 0)
+}
+
 func (s *bootstrapSuite) TestBootstrapNoToolsNonReleaseStream(c *gc.C) {
 	if runtime.GOOS == "windows" {
 		c.Skip("issue 1403084: Currently does not work because of jujud problems")
@@ -385,6 +525,10 @@ type bootstrapEnviron struct {
 	args                        environs.BootstrapParams
 	instanceConfig              *instancecfg.InstanceConfig
 	storage                     storage.Storage
+
+	// tagsStateServers is set to make the environment find its
+	// state servers without using the provider state.
+	tagsStateServers bool
 }
 
 func newEnviron(name string, defaultKeys bool, extraAttrs map[string]interface{}) *bootstrapEnviron {
@@ -439,6 +583,13 @@ func (e *bootstrapEnviron) Storage() storage.Storage {
 	return e.storage
 }
 
+func (e *bootstrapEnviron) StateServerInstances() ([]instance.Id, error) {
+	if e.tagsStateServers {
+		return nil, environs.ErrNotBootstrapped
+	}
+	return common.ProviderStateInstances(e, e.storage)
+}
+
 func (e *bootstrapEnviron) SupportedArchitectures() ([]string, error) {
 	e.supportedArchitecturesCount++
 	return []string{arch.AMD64, arch.ARM64}, nil
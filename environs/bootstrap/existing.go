@@ -0,0 +1,191 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bootstrap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/utils/ssh"
+)
+
+var (
+	initUbuntuUser   = manual.InitUbuntuUser
+	configureMachine = common.ConfigureMachine
+	addStateInstance = common.AddStateInstance
+)
+
+// ExistingInstanceId returns the instance ID recorded for a state
+// server bootstrapped onto an existing host. It uses the same form
+// as machines added with "juju add-machine ssh:host", so that the
+// provisioner and instance poller leave it alone.
+func ExistingInstanceId(host string) instance.Id {
+	return instance.Id("manual:" + host)
+}
+
+// SplitUserHost splits a "[user@]host" bootstrap host into its user
+// and host parts.
+func SplitUserHost(userHost string) (user, host string) {
+	if at := strings.Index(userHost, "@"); at != -1 {
+		return userHost[:at], userHost[at+1:]
+	}
+	return "", userHost
+}
+
+// bootstrapExistingHost prepares an already-provisioned host, given
+// as "[user@]host", to become the initial state server of the
+// environment in place of a new instance started by the provider.
+// It returns the host's architecture and series, and a finalizer
+// that installs the state server agent on the host over SSH.
+//
+// The host is recorded as a state server in the provider state, as
+// the providers that keep one do for the state server instances they
+// start. Providers without provider storage are not supported.
+func bootstrapExistingHost(
+	ctx environs.BootstrapContext, environ environs.Environ, userHost string, cons constraints.Value,
+) (arch, series string, _ environs.BootstrapFinalizer, _ error) {
+	cfg := environ.Config()
+	if provider.IsManual(cfg.Type()) {
+		return "", "", nil, errors.New(`bootstrapping to an existing host is not supported by the manual provider; use "bootstrap-host" instead`)
+	}
+	envStorage, ok := environ.(environs.EnvironStorage)
+	if !ok {
+		return "", "", nil, errors.NotSupportedf("bootstrapping to an existing host with the %q provider", cfg.Type())
+	}
+	user, host := SplitUserHost(userHost)
+	if host == "" {
+		return "", "", nil, errors.NotValidf("bootstrap host %q", userHost)
+	}
+
+	// Create the "ubuntu" user if necessary, so we can log in with
+	// the environment's authorized keys from now on.
+	if user != "" {
+		err := initUbuntuUser(host, user, cfg.AuthorizedKeys(), "", ctx.GetStdin(), ctx.GetStdout())
+		if err != nil {
+			return "", "", nil, errors.Annotatef(err, "cannot initialise %q", host)
+		}
+	}
+	provisioned, err := manual.CheckProvisioned(host)
+	if err != nil {
+		return "", "", nil, errors.Annotate(err, "failed to check provisioned status")
+	}
+	if provisioned {
+		return "", "", nil, manual.ErrProvisioned
+	}
+	hc, series, err := manual.DetectSeriesAndHardwareCharacteristics(host)
+	if err != nil {
+		return "", "", nil, errors.Annotatef(err, "cannot detect hardware characteristics of %q", host)
+	}
+	if err := checkExistingHostConstraints(cons, hc); err != nil {
+		return "", "", nil, errors.Annotatef(err, "cannot bootstrap to %q", host)
+	}
+
+	instanceId := ExistingInstanceId(host)
+	finalize := func(ctx environs.BootstrapContext, icfg *instancecfg.InstanceConfig) error {
+		icfg.InstanceId = instanceId
+		icfg.HardwareCharacteristics = &hc
+		if err := instancecfg.FinishInstanceConfig(icfg, environ.Config()); err != nil {
+			return errors.Trace(err)
+		}
+		// Record the host as the state server, so that the
+		// environment is known to be bootstrapped. Providers that
+		// find their state servers some other way, such as by
+		// instance tags, will not report it.
+		stor := envStorage.Storage()
+		if err := addStateInstance(stor, instanceId); err != nil {
+			return errors.Annotate(err, "cannot save state")
+		}
+		if err := checkStateServerInstance(environ, instanceId); err != nil {
+			if err := common.RemoveStateInstances(stor, instanceId); err != nil {
+				logger.Errorf("cannot remove state server %q from provider state: %v", instanceId, err)
+			}
+			return errors.Trace(err)
+		}
+		return configureMachine(ctx, ssh.DefaultClient, host, icfg)
+	}
+	return *hc.Arch, series, finalize, nil
+}
+
+// checkStateServerInstance returns an error if the environment does
+// not report the given instance as a state server.
+func checkStateServerInstance(environ environs.Environ, instanceId instance.Id) error {
+	ids, err := environ.StateServerInstances()
+	if err != nil && errors.Cause(err) != environs.ErrNotBootstrapped {
+		return errors.Trace(err)
+	}
+	for _, id := range ids {
+		if id == instanceId {
+			return nil
+		}
+	}
+	return errors.NotSupportedf("bootstrapping to an existing host with the %q provider", environ.Config().Type())
+}
+
+// checkExistingHostConstraints returns an error if the constraints
+// are not satisfied by an existing host with the given hardware.
+// Constraints that only affect the choice or creation of a new
+// instance cannot be applied to an existing host, and are rejected.
+func checkExistingHostConstraints(cons constraints.Value, hc instance.HardwareCharacteristics) error {
+	var failed []string
+	fail := func(format string, args ...interface{}) {
+		failed = append(failed, fmt.Sprintf(format, args...))
+	}
+	if cons.Arch != nil && *cons.Arch != "" && (hc.Arch == nil || *hc.Arch != *cons.Arch) {
+		fail("arch=%s", *cons.Arch)
+	}
+	for _, c := range []struct {
+		name     string
+		min, max *uint64
+		have     *uint64
+	}{
+		{constraints.CpuCores, cons.CpuCores, cons.MaxCpuCores, hc.CpuCores},
+		{constraints.CpuPower, cons.CpuPower, cons.MaxCpuPower, hc.CpuPower},
+		{constraints.Mem, cons.Mem, cons.MaxMem, hc.Mem},
+		{constraints.RootDisk, cons.RootDisk, cons.MaxRootDisk, hc.RootDisk},
+	} {
+		if c.min != nil && *c.min > 0 && (c.have == nil || *c.have < *c.min) {
+			fail("%s=%d", c.name, *c.min)
+		}
+		if c.max != nil && *c.max > 0 && (c.have == nil || *c.have > *c.max) {
+			fail("max-%s=%d", c.name, *c.max)
+		}
+	}
+	if cons.Container != nil && *cons.Container != "" {
+		fail("%s=%s", constraints.Container, *cons.Container)
+	}
+	if cons.VirtType != nil && *cons.VirtType != "" {
+		fail("%s=%s", constraints.VirtType, *cons.VirtType)
+	}
+	if cons.RootDiskSource != nil && *cons.RootDiskSource != "" {
+		fail("%s=%s", constraints.RootDiskSource, *cons.RootDiskSource)
+	}
+	if cons.HasInstanceType() {
+		fail("%s=%s", constraints.InstanceType, *cons.InstanceType)
+	}
+	for _, c := range []struct {
+		name   string
+		values *[]string
+	}{
+		{constraints.Tags, cons.Tags},
+		{constraints.Spaces, cons.Spaces},
+		{constraints.Networks, cons.Networks},
+	} {
+		if c.values != nil && len(*c.values) > 0 {
+			fail("%s=%s", c.name, strings.Join(*c.values, ","))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("existing host does not satisfy constraints: %s", strings.Join(failed, " "))
+	}
+	return nil
+}
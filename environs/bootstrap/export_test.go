@@ -9,4 +9,7 @@ var (
 	FindTools             = &findTools
 	FindBootstrapTools    = findBootstrapTools
 	FindAvailableTools    = findAvailableTools
+	InitUbuntuUser        = &initUbuntuUser
+	ConfigureMachine      = &configureMachine
+	AddStateInstance      = &addStateInstance
 )
@@ -175,8 +175,20 @@ type ProviderRegistry interface {
 	// RegisterProvider registers a new environment provider with the given
 	// name, and zero or more aliases. If a provider already exists with the
 	// given name or alias, an error will be returned.
+	//
+	// Providers registered this way declare no capabilities.
 	RegisterProvider(p EnvironProvider, providerType string, providerTypeAliases ...string) error
 
+	// Register registers a new environment provider as described by
+	// the given registration. An error is returned if the registration
+	// is invalid, or if a provider already exists with the given type
+	// or alias.
+	Register(r ProviderRegistration) error
+
+	// Capabilities returns the capabilities declared by the
+	// environment provider with the specified name.
+	Capabilities(providerType string) ([]ProviderCapability, error)
+
 	// RegisteredProviders returns the names of the registered environment
 	// providers.
 	RegisteredProviders() []string
@@ -196,27 +208,54 @@ type globalProviderRegistry struct {
 	providers map[string]EnvironProvider
 	// providerAliases is a map of provider type aliases.
 	aliases map[string]string
+	// capabilities maps from provider type to the capabilities
+	// declared by each registered provider type.
+	capabilities map[string][]ProviderCapability
 }
 
 var globalProviders = &globalProviderRegistry{
-	providers: map[string]EnvironProvider{},
-	aliases:   map[string]string{},
+	providers:    map[string]EnvironProvider{},
+	aliases:      map[string]string{},
+	capabilities: map[string][]ProviderCapability{},
 }
 
 func (r *globalProviderRegistry) RegisterProvider(p EnvironProvider, providerType string, providerTypeAliases ...string) error {
-	if r.providers[providerType] != nil || r.aliases[providerType] != "" {
-		return errors.Errorf("duplicate provider name %q", providerType)
+	return r.Register(ProviderRegistration{
+		Type:       providerType,
+		Aliases:    providerTypeAliases,
+		Provider:   p,
+		APIVersion: ProviderAPIVersion,
+	})
+}
+
+func (r *globalProviderRegistry) Register(reg ProviderRegistration) error {
+	if err := reg.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if r.providers[reg.Type] != nil || r.aliases[reg.Type] != "" {
+		return errors.Errorf("duplicate provider name %q", reg.Type)
 	}
-	r.providers[providerType] = p
-	for _, alias := range providerTypeAliases {
+	r.providers[reg.Type] = reg.Provider
+	r.capabilities[reg.Type] = reg.Capabilities
+	for _, alias := range reg.Aliases {
 		if r.providers[alias] != nil || r.aliases[alias] != "" {
 			return errors.Errorf("duplicate provider alias %q", alias)
 		}
-		r.aliases[alias] = providerType
+		r.aliases[alias] = reg.Type
 	}
 	return nil
 }
 
+func (r *globalProviderRegistry) Capabilities(providerType string) ([]ProviderCapability, error) {
+	if alias, ok := r.aliases[providerType]; ok {
+		providerType = alias
+	}
+	if _, ok := r.providers[providerType]; !ok {
+		return nil, errors.Errorf("no registered provider for %q", providerType)
+	}
+	return r.capabilities[providerType], nil
+}
+
 func (r *globalProviderRegistry) RegisteredProviders() []string {
 	var p []string
 	for k := range r.providers {
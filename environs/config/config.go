@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -24,8 +25,10 @@ import (
 	"gopkg.in/macaroon-bakery.v1/bakery"
 
 	"github.com/juju/juju/cert"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/version"
 )
 
@@ -46,6 +49,12 @@ const (
 	// instance security groups.
 	FwNone = "none"
 
+	// FwMachine requests that each machine agent programs the host
+	// firewall of its own machine (using iptables) to allow only the
+	// ports opened by exposed services on that machine. It's useful
+	// for clouds without support for security groups.
+	FwMachine = "machine"
+
 	// DefaultStatePort is the default port the state server is listening on.
 	DefaultStatePort int = 37017
 
@@ -208,6 +217,18 @@ const (
 	// machine worker not to discover any machine addresses
 	// on start up.
 	IgnoreMachineAddresses = "ignore-machine-addresses"
+
+	// AddressSelectionPolicyKey stores the policy used to select
+	// machine addresses for units and agent API connections.
+	AddressSelectionPolicyKey = "address-selection-policy"
+
+	// ConstraintProfilesKey stores named sets of constraints that
+	// may be referred to with the "profile" constraint.
+	ConstraintProfilesKey = "constraint-profiles"
+
+	// AutomaticallyRetryHooks stores whether units retry failed
+	// hooks by themselves, rather than waiting for "juju resolved".
+	AutomaticallyRetryHooks = "automatically-retry-hooks"
 )
 
 // ParseHarvestMode parses description of harvesting method and
@@ -665,6 +686,16 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[AddressSelectionPolicyKey].(string); ok {
+		if err := network.AddressPolicy(v).Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if err := validateConstraintProfiles(cfg.ConstraintProfiles()); err != nil {
+		return errors.Trace(err)
+	}
+
 	// Check LXCDefaultMTU is a positive integer, when set.
 	if lxcDefaultMTU, ok := cfg.LXCDefaultMTU(); ok && lxcDefaultMTU < 0 {
 		return errors.Errorf("%s: expected positive integer, got %v", LXCDefaultMTU, lxcDefaultMTU)
@@ -1016,8 +1047,8 @@ func (c *Config) AdminSecret() string {
 }
 
 // FirewallMode returns whether the firewall should
-// manage ports per machine, globally, or not at all.
-// (FwInstance, FwGlobal, or FwNone).
+// manage ports per instance, globally, on each machine's host
+// firewall, or not at all (FwInstance, FwGlobal, FwMachine, or FwNone).
 func (c *Config) FirewallMode() string {
 	return c.mustString("firewall-mode")
 }
@@ -1180,6 +1211,56 @@ func (c *Config) IgnoreMachineAddresses() (bool, bool) {
 	return v, ok
 }
 
+// AutomaticallyRetryHooks reports whether units should retry failed
+// hooks, with increasing delays, without waiting to be resolved.
+// It defaults to true.
+func (c *Config) AutomaticallyRetryHooks() bool {
+	if val, ok := c.defined[AutomaticallyRetryHooks].(bool); ok {
+		return val
+	}
+	return true
+}
+
+// AddressSelectionPolicy returns the policy used to select machine
+// addresses for units and agent API connections.
+func (c *Config) AddressSelectionPolicy() network.AddressPolicy {
+	return network.AddressPolicy(c.asString(AddressSelectionPolicyKey))
+}
+
+// ConstraintProfiles returns the constraint profiles defined for the
+// environment, keyed by profile name.
+func (c *Config) ConstraintProfiles() map[string]string {
+	v, _ := c.defined[ConstraintProfilesKey].(map[string]string)
+	return v
+}
+
+// ConstraintProfile returns the constraints of the named profile.
+// It returns an error satisfying errors.IsNotFound if the profile
+// is not defined.
+func (c *Config) ConstraintProfile(name string) (constraints.Value, error) {
+	str, ok := c.ConstraintProfiles()[name]
+	if !ok {
+		return constraints.Value{}, errors.NotFoundf("constraint profile %q", name)
+	}
+	return constraints.Parse(str)
+}
+
+func validateConstraintProfiles(profiles map[string]string) error {
+	for name, str := range profiles {
+		if !constraints.IsValidProfileName(name) {
+			return errors.NotValidf("constraint profile name %q", name)
+		}
+		cons, err := constraints.Parse(str)
+		if err != nil {
+			return errors.Annotatef(err, "invalid constraint profile %q", name)
+		}
+		if cons.Profile != nil {
+			return errors.Errorf("constraint profile %q cannot refer to another profile", name)
+		}
+	}
+	return nil
+}
+
 // StorageDefaultBlockSource returns the default block storage
 // source for the environment.
 func (c *Config) StorageDefaultBlockSource() (string, bool) {
@@ -1329,6 +1410,9 @@ var alwaysOptional = schema.Defaults{
 	LXCDefaultMTU:                schema.Omit,
 	"disable-network-management": schema.Omit,
 	IgnoreMachineAddresses:       schema.Omit,
+	AddressSelectionPolicyKey:    schema.Omit,
+	ConstraintProfilesKey:        schema.Omit,
+	AutomaticallyRetryHooks:      schema.Omit,
 	AgentStreamKey:               schema.Omit,
 	IdentityURL:                  schema.Omit,
 	IdentityPublicKey:            schema.Omit,
@@ -1470,7 +1554,13 @@ func (cfg *Config) ValidateUnknownAttrs(fields schema.Fields, defaults schema.De
 	coerced, err := checker.Coerce(attrs, nil)
 	if err != nil {
 		// TODO(ericsnow) Drop this?
-		logger.Debugf("coercion failed attributes: %#v, checker: %#v, %v", attrs, checker, err)
+		// The attributes may hold secrets, so only their names are logged.
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		logger.Debugf("coercion failed attributes: %v, checker: %#v, %v", names, checker, err)
 		return nil, err
 	}
 	result := coerced.(map[string]interface{})
@@ -1577,6 +1667,26 @@ func Schema(extra environschema.Fields) (environschema.Fields, error) {
 	return fields, nil
 }
 
+// RedactedValue is shown in place of the values of secret
+// configuration attributes.
+const RedactedValue = "<redacted>"
+
+// RedactSecrets returns a copy of attrs in which the values of any
+// attributes marked as secret, either in the given fields or in the
+// fields defined by this package, are replaced by RedactedValue.
+// Attributes with empty values are left as they are.
+func RedactSecrets(attrs map[string]interface{}, fields environschema.Fields) map[string]interface{} {
+	result := make(map[string]interface{}, len(attrs))
+	for name, value := range attrs {
+		if isEmpty(value) || !(fields[name].Secret || configSchema[name].Secret) {
+			result[name] = value
+			continue
+		}
+		result[name] = RedactedValue
+	}
+	return result
+}
+
 // configSchema holds information on all the fields defined by
 // the config package.
 // TODO(rog) make this available to external packages.
@@ -1725,6 +1835,21 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tbool,
 		Group:       environschema.EnvironGroup,
 	},
+	AutomaticallyRetryHooks: {
+		Description: "Whether units should retry failed hooks automatically, waiting longer between each attempt, instead of waiting for \"juju resolved\"",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	AddressSelectionPolicyKey: {
+		Description: `How to select machine addresses for units and agent API connections: "prefer-internal", "prefer-public" or "space:<name>" (default selects public and cloud-local addresses as appropriate)`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ConstraintProfilesKey: {
+		Description: `Named sets of constraints, such as "db: mem=16G cpu-cores=8", which may be used with the "profile" constraint`,
+		Type:        environschema.Tattrs,
+		Group:       environschema.EnvironGroup,
+	},
 	"enable-os-refresh-update": {
 		Description: `Whether newly provisioned instances should run their respective OS's update capability.`,
 		Type:        environschema.Tbool,
@@ -1744,13 +1869,16 @@ var configSchema = environschema.Fields{
 for a network port is enabled to one instance if any instance requires
 that port).
 
+'machine' programs the host firewall (iptables) of each machine to
+allow only the ports opened by the exposed services deployed to it.
+
 'none' requests that no firewalling should be performed
 inside the environment. It's useful for clouds without support for either
 global or per instance security groups.`,
 		Type: environschema.Tstring,
 		// Note that we need the empty value because it can
 		// be found in legacy environments.
-		Values:    []interface{}{FwInstance, FwGlobal, FwNone, ""},
+		Values:    []interface{}{FwInstance, FwGlobal, FwMachine, FwNone, ""},
 		Immutable: true,
 		Group:     environschema.EnvironGroup,
 	},
@@ -1844,7 +1972,7 @@ global or per instance security groups.`,
 		Group:       environschema.EnvironGroup,
 	},
 	ResourceTagsKey: {
-		Description: "resource tags",
+		Description: `Space-separated key=value pairs to set as tags on the cloud resources Juju creates, where the provider supports them`,
 		Type:        environschema.Tattrs,
 		Group:       environschema.EnvironGroup,
 	},
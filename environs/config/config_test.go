@@ -11,6 +11,7 @@ import (
 	stdtesting "testing"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/schema"
 	gitjujutesting "github.com/juju/testing"
@@ -22,8 +23,10 @@ import (
 	"gopkg.in/macaroon-bakery.v1/bakery"
 
 	"github.com/juju/juju/cert"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 )
@@ -258,6 +261,67 @@ var configTests = []configTest{
 			"lxc-default-mtu": -42,
 		},
 		err: `lxc-default-mtu: expected positive integer, got -42`,
+	}, {
+		about:       "Address selection policy set explicitly",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                     "my-type",
+			"name":                     "my-name",
+			"address-selection-policy": "space:internal",
+		},
+	}, {
+		about:       "Address selection policy invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                     "my-type",
+			"name":                     "my-name",
+			"address-selection-policy": "prefer-ipv9",
+		},
+		err: `address policy "prefer-ipv9" not valid`,
+	}, {
+		about:       "Constraint profiles set explicitly",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"constraint-profiles": map[string]interface{}{
+				"db":  "mem=16G cpu-cores=8",
+				"web": "instance-type=m3.medium",
+			},
+		},
+	}, {
+		about:       "Constraint profile name invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"constraint-profiles": map[string]interface{}{
+				"Big_DB": "mem=16G",
+			},
+		},
+		err: `constraint profile name "Big_DB" not valid`,
+	}, {
+		about:       "Constraint profile constraints invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"constraint-profiles": map[string]interface{}{
+				"db": "mem=lots",
+			},
+		},
+		err: `invalid constraint profile "db": bad "mem" constraint: .*`,
+	}, {
+		about:       "Constraint profile refers to another profile",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"constraint-profiles": map[string]interface{}{
+				"db": "profile=big mem=16G",
+			},
+		},
+		err: `constraint profile "db" cannot refer to another profile`,
 	}, {
 		about:       "CA cert & key from path",
 		useDefaults: config.UseDefaults,
@@ -431,6 +495,23 @@ var configTests = []configTest{
 			"name": "my-name",
 			"ignore-machine-addresses": true,
 		},
+	}, {
+		about:       "automatically-retry-hooks off",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"automatically-retry-hooks": false,
+		},
+	}, {
+		about:       "Invalid automatically-retry-hooks flag",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"automatically-retry-hooks": "sometimes",
+		},
+		err: `automatically-retry-hooks: expected bool, got string\("sometimes"\)`,
 	}, {
 		about:       "set-numa-control-policy on",
 		useDefaults: config.UseDefaults,
@@ -616,6 +697,14 @@ var configTests = []configTest{
 			"name":          "my-name",
 			"firewall-mode": config.FwNone,
 		},
+	}, {
+		about:       "Machine firewall mode",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"firewall-mode": config.FwMachine,
+		},
 	}, {
 		about:       "Illegal firewall mode",
 		useDefaults: config.UseDefaults,
@@ -624,7 +713,7 @@ var configTests = []configTest{
 			"name":          "my-name",
 			"firewall-mode": "illegal",
 		},
-		err: `firewall-mode: expected one of \[instance global none ], got "illegal"`,
+		err: `firewall-mode: expected one of \[instance global machine none ], got "illegal"`,
 	}, {
 		about:       "ssl-hostname-verification off",
 		useDefaults: config.UseDefaults,
@@ -1233,6 +1322,11 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	if statePort, ok := test.attrs["state-port"]; ok {
 		c.Assert(cfg.StatePort(), gc.Equals, statePort)
 	}
+	if policy, ok := test.attrs["address-selection-policy"]; ok {
+		c.Assert(string(cfg.AddressSelectionPolicy()), gc.Equals, policy)
+	} else {
+		c.Assert(cfg.AddressSelectionPolicy(), gc.Equals, network.DefaultAddressPolicy)
+	}
 	if apiPort, ok := test.attrs["api-port"]; ok {
 		c.Assert(cfg.APIPort(), gc.Equals, apiPort)
 	}
@@ -1332,6 +1426,12 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	} else {
 		c.Assert(cfg.ProvisionerHarvestMode(), gc.Equals, config.HarvestDestroyed)
 	}
+
+	if v, ok := test.attrs["automatically-retry-hooks"]; ok {
+		c.Assert(cfg.AutomaticallyRetryHooks(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.AutomaticallyRetryHooks(), jc.IsTrue)
+	}
 	sshOpts := cfg.BootstrapSSHOpts()
 	test.assertDuration(
 		c,
@@ -1428,6 +1528,24 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	}
 }
 
+func (s *ConfigSuite) TestConstraintProfile(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"constraint-profiles": map[string]interface{}{
+			"db": "mem=16G cpu-cores=8",
+		},
+	})
+	c.Assert(cfg.ConstraintProfiles(), jc.DeepEquals, map[string]string{
+		"db": "mem=16G cpu-cores=8",
+	})
+	cons, err := cfg.ConstraintProfile("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=16G cpu-cores=8"))
+
+	_, err = cfg.ConstraintProfile("web")
+	c.Assert(err, gc.ErrorMatches, `constraint profile "web" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (test configTest) assertDuration(c *gc.C, name string, actual time.Duration, defaultInSeconds int) {
 	value, ok := test.attrs[name].(int)
 	if !ok || value == 0 {
@@ -1903,6 +2021,26 @@ func (s *ConfigSuite) TestSchemaWithExtraOverlap(c *gc.C) {
 	c.Assert(schema, gc.IsNil)
 }
 
+func (s *ConfigSuite) TestRedactSecrets(c *gc.C) {
+	attrs := map[string]interface{}{
+		"name":         "my-name",
+		"admin-secret": "fish",
+		"password":     "hunter2",
+		"token":        "",
+	}
+	redacted := config.RedactSecrets(attrs, environschema.Fields{
+		"password": {Type: environschema.Tstring, Secret: true},
+		"token":    {Type: environschema.Tstring, Secret: true},
+	})
+	c.Assert(redacted, jc.DeepEquals, map[string]interface{}{
+		"name":         "my-name",
+		"admin-secret": config.RedactedValue,
+		"password":     config.RedactedValue,
+		"token":        "",
+	})
+	c.Assert(attrs["password"], gc.Equals, "hunter2")
+}
+
 func (s *ConfigSuite) TestGenerateStateServerCertAndKey(c *gc.C) {
 	// Add a cert.
 	s.FakeHomeSuite.Home.AddFiles(c, gitjujutesting.TestFile{".ssh/id_rsa.pub", "rsa\n"})
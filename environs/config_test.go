@@ -342,6 +342,7 @@ type dummyProvider struct {
 func (s *suite) TestRegisterProvider(c *gc.C) {
 	s.PatchValue(environs.Providers, make(map[string]environs.EnvironProvider))
 	s.PatchValue(environs.ProviderAliases, make(map[string]string))
+	s.PatchValue(environs.ProviderCapabilityMap, make(map[string][]environs.ProviderCapability))
 	type step struct {
 		name    string
 		aliases []string
@@ -422,6 +423,121 @@ func (s *suite) TestRegisterProvider(c *gc.C) {
 	}
 }
 
+func (s *suite) patchProviderRegistry() {
+	s.PatchValue(environs.Providers, make(map[string]environs.EnvironProvider))
+	s.PatchValue(environs.ProviderAliases, make(map[string]string))
+	s.PatchValue(environs.ProviderCapabilityMap, make(map[string][]environs.ProviderCapability))
+}
+
+func (s *suite) TestRegisterCapabilities(c *gc.C) {
+	s.patchProviderRegistry()
+	registered := &dummyProvider{}
+	environs.Register(environs.ProviderRegistration{
+		Type:       "providerName",
+		Aliases:    []string{"providerAlias"},
+		Provider:   registered,
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityNetworking,
+			environs.CapabilityAvailabilityZones,
+		},
+	})
+	p, err := environs.Provider("providerAlias")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p, gc.Equals, registered)
+
+	capabilities, err := environs.ProviderCapabilities("providerAlias")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(capabilities, jc.DeepEquals, []environs.ProviderCapability{
+		environs.CapabilityNetworking,
+		environs.CapabilityAvailabilityZones,
+	})
+	for capability, expect := range map[environs.ProviderCapability]bool{
+		environs.CapabilityNetworking:        true,
+		environs.CapabilityStorage:           false,
+		environs.CapabilityAvailabilityZones: true,
+	} {
+		has, err := environs.HasCapability("providerName", capability)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(has, gc.Equals, expect, gc.Commentf("capability %q", capability))
+	}
+}
+
+func (s *suite) TestRegisterProviderDeclaresNoCapabilities(c *gc.C) {
+	s.patchProviderRegistry()
+	environs.RegisterProvider("providerName", &dummyProvider{})
+	capabilities, err := environs.ProviderCapabilities("providerName")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(capabilities, gc.HasLen, 0)
+}
+
+func (s *suite) TestProviderCapabilitiesUnknownProvider(c *gc.C) {
+	s.patchProviderRegistry()
+	_, err := environs.ProviderCapabilities("providerName")
+	c.Assert(err, gc.ErrorMatches, `no registered provider for "providerName"`)
+	_, err = environs.HasCapability("providerName", environs.CapabilityNetworking)
+	c.Assert(err, gc.ErrorMatches, `no registered provider for "providerName"`)
+}
+
+// networkingEnviron implements environs.NetworkingEnviron, panicking
+// if any of its methods are called.
+type networkingEnviron struct {
+	environs.NetworkingEnviron
+}
+
+func (s *suite) TestNetworkingCapable(c *gc.C) {
+	s.patchProviderRegistry()
+	environs.Register(environs.ProviderRegistration{
+		Type:         "capable",
+		Provider:     &dummyProvider{},
+		APIVersion:   environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{environs.CapabilityNetworking},
+	})
+	environs.RegisterProvider("incapable", &dummyProvider{})
+
+	env := networkingEnviron{}
+	netEnv, ok := environs.NetworkingCapable("capable", env)
+	c.Check(ok, jc.IsTrue)
+	c.Check(netEnv, gc.Equals, environs.NetworkingEnviron(env))
+
+	_, ok = environs.NetworkingCapable("incapable", env)
+	c.Check(ok, jc.IsFalse)
+	_, ok = environs.NetworkingCapable("unknown", env)
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *suite) TestRegisterInvalid(c *gc.C) {
+	s.patchProviderRegistry()
+	for i, test := range []struct {
+		registration environs.ProviderRegistration
+		err          string
+	}{{
+		registration: environs.ProviderRegistration{
+			Provider:   &dummyProvider{},
+			APIVersion: environs.ProviderAPIVersion,
+		},
+		err: "empty provider type not valid",
+	}, {
+		registration: environs.ProviderRegistration{
+			Type:       "providerName",
+			APIVersion: environs.ProviderAPIVersion,
+		},
+		err: `nil provider for "providerName" not valid`,
+	}, {
+		registration: environs.ProviderRegistration{
+			Type:     "providerName",
+			Provider: &dummyProvider{},
+		},
+		err: `provider "providerName" built for provider API version 0, expected 1`,
+	}} {
+		c.Logf("test %d", i)
+		err := environs.GlobalProviderRegistry().Register(test.registration)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(func() { environs.Register(test.registration) }, gc.PanicMatches, "juju: "+test.err)
+	}
+	c.Assert(environs.RegisteredProviders(), gc.HasLen, 0)
+}
+
 type ConfigDeprecationSuite struct {
 	suite
 	writer *loggo.TestWriter
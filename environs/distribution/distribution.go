@@ -0,0 +1,164 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package distribution analyses how the units of a service are spread
+// across hosts and availability zones, and suggests how they might be
+// moved to spread them more evenly.
+package distribution
+
+import (
+	"sort"
+
+	"github.com/juju/utils/set"
+)
+
+// Placement describes where a unit is placed.
+type Placement struct {
+	// Unit is the name of the unit.
+	Unit string
+
+	// Host is the id of the top-level machine hosting the unit;
+	// for a unit in a container, this is the container's host.
+	Host string
+
+	// Zone is the availability zone of Host, or empty if unknown.
+	Zone string
+}
+
+// Move describes the move of a unit from one zone to another.
+type Move struct {
+	Unit     string
+	FromZone string
+	ToZone   string
+}
+
+// Report describes the distribution of a service's units.
+type Report struct {
+	// Units is the number of units of the service.
+	Units int
+
+	// Zones maps availability zones to the number of units in them.
+	// Every known zone is present, even those with no units.
+	Zones map[string]int
+
+	// Hosts maps top-level machine ids to the number of units they host.
+	Hosts map[string]int
+
+	// Imbalanced reports whether the unit counts of any two zones
+	// differ by more than one.
+	Imbalanced bool
+
+	// SingleHost reports whether all of a service's units, of which
+	// there are more than one, share a single host.
+	SingleHost bool
+
+	// SingleZone reports whether all of a service's units, of which
+	// there are more than one, share a single availability zone, when
+	// other zones are available.
+	SingleZone bool
+
+	// Moves holds the unit moves that would balance the service's
+	// units across zones.
+	Moves []Move
+}
+
+// Analyse reports on the distribution of a service's units, given
+// their placements and the names of all of the known availability
+// zones. Units in an unknown zone are not considered when computing
+// zone balance.
+func Analyse(placements []Placement, zones []string) Report {
+	report := Report{
+		Units: len(placements),
+		Zones: make(map[string]int),
+		Hosts: make(map[string]int),
+	}
+	for _, zone := range zones {
+		report.Zones[zone] = 0
+	}
+	zoneUnits := make(map[string][]string)
+	for _, p := range placements {
+		report.Hosts[p.Host]++
+		if p.Zone == "" {
+			continue
+		}
+		report.Zones[p.Zone]++
+		zoneUnits[p.Zone] = append(zoneUnits[p.Zone], p.Unit)
+	}
+	if report.Units > 1 {
+		report.SingleHost = len(report.Hosts) == 1
+		report.SingleZone = len(zoneUnits) == 1 && len(report.Zones) > 1
+	}
+	report.Moves = rebalance(report.Zones, zoneUnits)
+	report.Imbalanced = len(report.Moves) > 0
+	return report
+}
+
+// rebalance returns the moves required to bring the unit counts of all
+// zones within one of each other. Units are moved from the most to the
+// least populated zones, last listed units first.
+func rebalance(counts map[string]int, zoneUnits map[string][]string) []Move {
+	counts = copyCounts(counts)
+	remaining := make(map[string][]string)
+	for zone, units := range zoneUnits {
+		remaining[zone] = append([]string(nil), units...)
+	}
+	var moves []Move
+	for {
+		from, to := extremes(counts)
+		if counts[from]-counts[to] <= 1 {
+			return moves
+		}
+		units := remaining[from]
+		unit := units[len(units)-1]
+		remaining[from] = units[:len(units)-1]
+		counts[from]--
+		counts[to]++
+		moves = append(moves, Move{Unit: unit, FromZone: from, ToZone: to})
+	}
+}
+
+// extremes returns the most and least populated zones, breaking ties
+// by zone name so the result is deterministic.
+func extremes(counts map[string]int) (most, least string) {
+	zones := set.NewStrings()
+	for zone := range counts {
+		zones.Add(zone)
+	}
+	sorted := zones.SortedValues()
+	for i, zone := range sorted {
+		if i == 0 || counts[zone] > counts[most] {
+			most = zone
+		}
+		if i == 0 || counts[zone] < counts[least] {
+			least = zone
+		}
+	}
+	return most, least
+}
+
+func copyCounts(counts map[string]int) map[string]int {
+	result := make(map[string]int, len(counts))
+	for k, v := range counts {
+		result[k] = v
+	}
+	return result
+}
+
+// SortPlacements sorts the placements of a service's units by unit
+// number, so that the most recently added units are moved first.
+func SortPlacements(placements []Placement) {
+	sort.Sort(byUnit(placements))
+}
+
+type byUnit []Placement
+
+func (p byUnit) Len() int      { return len(p) }
+func (p byUnit) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byUnit) Less(i, j int) bool {
+	// Units of the same service differ only in their unit
+	// numbers, so shorter names have lower numbers.
+	if len(p[i].Unit) != len(p[j].Unit) {
+		return len(p[i].Unit) < len(p[j].Unit)
+	}
+	return p[i].Unit < p[j].Unit
+}
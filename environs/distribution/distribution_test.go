@@ -0,0 +1,105 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package distribution_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/distribution"
+	"github.com/juju/juju/testing"
+)
+
+type distributionSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&distributionSuite{})
+
+var zones = []string{"az1", "az2", "az3"}
+
+func (*distributionSuite) TestAnalyseBalanced(c *gc.C) {
+	report := distribution.Analyse([]distribution.Placement{
+		{Unit: "wordpress/0", Host: "0", Zone: "az1"},
+		{Unit: "wordpress/1", Host: "1", Zone: "az2"},
+		{Unit: "wordpress/2", Host: "2", Zone: "az3"},
+		{Unit: "wordpress/3", Host: "3", Zone: "az1"},
+	}, zones)
+	c.Assert(report, jc.DeepEquals, distribution.Report{
+		Units: 4,
+		Zones: map[string]int{"az1": 2, "az2": 1, "az3": 1},
+		Hosts: map[string]int{"0": 1, "1": 1, "2": 1, "3": 1},
+	})
+}
+
+func (*distributionSuite) TestAnalyseImbalanced(c *gc.C) {
+	report := distribution.Analyse([]distribution.Placement{
+		{Unit: "wordpress/0", Host: "0", Zone: "az1"},
+		{Unit: "wordpress/1", Host: "1", Zone: "az1"},
+		{Unit: "wordpress/2", Host: "2", Zone: "az1"},
+		{Unit: "wordpress/3", Host: "3", Zone: "az2"},
+	}, zones)
+	c.Assert(report.Imbalanced, jc.IsTrue)
+	c.Assert(report.SingleZone, jc.IsFalse)
+	c.Assert(report.Moves, jc.DeepEquals, []distribution.Move{
+		{Unit: "wordpress/2", FromZone: "az1", ToZone: "az3"},
+	})
+}
+
+func (*distributionSuite) TestAnalyseSingleZone(c *gc.C) {
+	report := distribution.Analyse([]distribution.Placement{
+		{Unit: "mysql/0", Host: "0", Zone: "az1"},
+		{Unit: "mysql/1", Host: "1", Zone: "az1"},
+		{Unit: "mysql/2", Host: "2", Zone: "az1"},
+	}, zones)
+	c.Assert(report.SingleZone, jc.IsTrue)
+	c.Assert(report.SingleHost, jc.IsFalse)
+	c.Assert(report.Moves, jc.DeepEquals, []distribution.Move{
+		{Unit: "mysql/2", FromZone: "az1", ToZone: "az2"},
+		{Unit: "mysql/1", FromZone: "az1", ToZone: "az3"},
+	})
+}
+
+func (*distributionSuite) TestAnalyseSingleHost(c *gc.C) {
+	report := distribution.Analyse([]distribution.Placement{
+		{Unit: "mysql/0", Host: "0", Zone: "az1"},
+		{Unit: "mysql/1", Host: "0", Zone: "az1"},
+	}, []string{"az1"})
+	c.Assert(report.SingleHost, jc.IsTrue)
+	// There are no other zones, so a single zone is not
+	// reported as a single point of failure.
+	c.Assert(report.SingleZone, jc.IsFalse)
+	c.Assert(report.Imbalanced, jc.IsFalse)
+}
+
+func (*distributionSuite) TestAnalyseSingleUnit(c *gc.C) {
+	report := distribution.Analyse([]distribution.Placement{
+		{Unit: "mysql/0", Host: "0", Zone: "az1"},
+	}, zones)
+	c.Assert(report.SingleHost, jc.IsFalse)
+	c.Assert(report.SingleZone, jc.IsFalse)
+	c.Assert(report.Imbalanced, jc.IsFalse)
+}
+
+func (*distributionSuite) TestAnalyseUnknownZones(c *gc.C) {
+	report := distribution.Analyse([]distribution.Placement{
+		{Unit: "mysql/0", Host: "0"},
+		{Unit: "mysql/1", Host: "1"},
+	}, nil)
+	c.Assert(report, jc.DeepEquals, distribution.Report{
+		Units: 2,
+		Zones: map[string]int{},
+		Hosts: map[string]int{"0": 1, "1": 1},
+	})
+}
+
+func (*distributionSuite) TestSortPlacements(c *gc.C) {
+	placements := []distribution.Placement{
+		{Unit: "mysql/10"}, {Unit: "mysql/2"}, {Unit: "mysql/1"},
+	}
+	distribution.SortPlacements(placements)
+	c.Assert(placements, jc.DeepEquals, []distribution.Placement{
+		{Unit: "mysql/1"}, {Unit: "mysql/2"}, {Unit: "mysql/10"},
+	})
+}
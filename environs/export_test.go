@@ -4,8 +4,9 @@
 package environs
 
 var (
-	Providers       = &globalProviders.providers
-	ProviderAliases = &globalProviders.aliases
+	Providers             = &globalProviders.providers
+	ProviderAliases       = &globalProviders.aliases
+	ProviderCapabilityMap = &globalProviders.capabilities
 )
 
 func UpdateEnvironAttrs(envs *Environs, name string, newAttrs map[string]interface{}) {
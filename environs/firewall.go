@@ -0,0 +1,24 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import "github.com/juju/juju/network"
+
+// FirewallWhitelister defines methods that environments able to
+// restrict access to well-known services, such as SSH and the API
+// server, to a whitelist of source addresses may implement.
+type FirewallWhitelister interface {
+	// SetFirewallWhitelist restricts access to the given well-known
+	// service on all machines in the environment to the given source
+	// address ranges, replacing any existing restriction. An empty
+	// whitelist allows access from anywhere.
+	SetFirewallWhitelist(service network.WellKnownServiceType, cidrs []string) error
+}
+
+// SupportsFirewallWhitelists is a convenience helper to check if an
+// environment supports firewall whitelists.
+func SupportsFirewallWhitelists(environ Environ) (FirewallWhitelister, bool) {
+	fw, ok := environ.(FirewallWhitelister)
+	return fw, ok
+}
@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+// HealthCheck is a single named diagnostic check of an environment's
+// readiness to be bootstrapped or to host new machines. Check returns
+// nil if the check passes, or an error describing the problem.
+type HealthCheck struct {
+	Name  string
+	Check func() error
+}
+
+// HealthChecks defines methods that environments able to diagnose
+// provider-specific problems, such as invalid credentials or
+// exhausted quotas, may implement.
+type HealthChecks interface {
+	// HealthChecks returns the provider-specific checks to run
+	// against the environment, in the order they should be run.
+	HealthChecks() []HealthCheck
+}
+
+// HealthChecksEnviron combines the standard Environ interface with
+// the functionality for running provider-specific health checks.
+type HealthChecksEnviron interface {
+	// Environ represents a juju environment.
+	Environ
+
+	// HealthChecks defines the methods of environments
+	// supporting health checks.
+	HealthChecks
+}
+
+// SupportsHealthChecks is a convenience helper to check if an
+// environment supports health checks. It returns an interface
+// containing Environ and HealthChecks in this case.
+func SupportsHealthChecks(environ Environ) (HealthChecksEnviron, bool) {
+	hce, ok := environ.(HealthChecksEnviron)
+	return hce, ok
+}
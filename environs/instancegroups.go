@@ -0,0 +1,33 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+// InstanceGroups defines methods that environments supporting
+// cloud-managed groups of instances, such as those maintained by a
+// cloud autoscaler, may implement.
+type InstanceGroups interface {
+	// InstanceGroupSize returns the number of instances currently
+	// in the named instance group. If the group has no instances,
+	// 0 is returned with no error.
+	InstanceGroupSize(name string) (int, error)
+}
+
+// InstanceGroupsEnviron combines the standard Environ interface with
+// the functionality for querying instance groups.
+type InstanceGroupsEnviron interface {
+	// Environ represents a juju environment.
+	Environ
+
+	// InstanceGroups defines the methods of environments
+	// supporting instance groups.
+	InstanceGroups
+}
+
+// SupportsInstanceGroups is a convenience helper to check if an
+// environment supports instance groups. It returns an interface
+// containing Environ and InstanceGroups in this case.
+func SupportsInstanceGroups(environ Environ) (InstanceGroupsEnviron, bool) {
+	ige, ok := environ.(InstanceGroupsEnviron)
+	return ige, ok
+}
@@ -50,18 +50,33 @@ func (itype InstanceType) match(cons constraints.Value) (InstanceType, bool) {
 	if cons.CpuCores != nil && itype.CpuCores < *cons.CpuCores {
 		return nothing, false
 	}
+	if cons.MaxCpuCores != nil && itype.CpuCores > *cons.MaxCpuCores {
+		return nothing, false
+	}
 	if cons.CpuPower != nil && itype.CpuPower != nil && *itype.CpuPower < *cons.CpuPower {
 		return nothing, false
 	}
+	if cons.MaxCpuPower != nil && itype.CpuPower != nil && *itype.CpuPower > *cons.MaxCpuPower {
+		return nothing, false
+	}
 	if cons.Mem != nil && itype.Mem < *cons.Mem {
 		return nothing, false
 	}
+	if cons.MaxMem != nil && itype.Mem > *cons.MaxMem {
+		return nothing, false
+	}
 	if cons.RootDisk != nil && itype.RootDisk > 0 && itype.RootDisk < *cons.RootDisk {
 		return nothing, false
 	}
+	if cons.MaxRootDisk != nil && itype.RootDisk > *cons.MaxRootDisk {
+		return nothing, false
+	}
 	if cons.Tags != nil && len(*cons.Tags) > 0 && !tagsMatch(*cons.Tags, itype.Tags) {
 		return nothing, false
 	}
+	if cons.HasVirtType() && itype.VirtType != nil && *itype.VirtType != *cons.VirtType {
+		return nothing, false
+	}
 	return itype, true
 }
 
@@ -131,6 +146,12 @@ func MatchingInstanceTypes(allInstanceTypes []InstanceType, region string, cons
 	return nil, fmt.Errorf("no instance types in %s matching constraints %q", region, origCons)
 }
 
+// SortByCost sorts itypes by increasing cost. Instance types of equal
+// cost are ordered by memory, cpu power, cpu cores and root disk.
+func SortByCost(itypes []InstanceType) {
+	sort.Sort(byCost(itypes))
+}
+
 // tagsMatch returns if the tags in wanted all exist in have.
 // Note that duplicates of tags are disregarded in both lists
 func tagsMatch(wanted, have []string) bool {
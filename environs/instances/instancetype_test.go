@@ -4,8 +4,6 @@
 package instances
 
 import (
-	"sort"
-
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -146,6 +144,14 @@ var getInstanceTypesTest = []struct {
 		expectedItypes: []string{
 			"m1.medium", "m1.large", "m1.xlarge", "c1.xlarge", "cc1.4xlarge", "cc2.8xlarge",
 		},
+	}, {
+		about:          "mem range",
+		cons:           "mem=4G..16G",
+		expectedItypes: []string{"m1.large", "m1.xlarge", "c1.xlarge"},
+	}, {
+		about:          "cpu-cores range",
+		cons:           "cpu-cores=2..4",
+		expectedItypes: []string{"c1.medium", "m1.large", "m1.xlarge"},
 	}, {
 		about:          "arches filtered by constraint",
 		cons:           "cpu-power=100 arch=armhf",
@@ -281,12 +287,21 @@ var instanceTypeMatchTests = []struct {
 	{"cpu-power=2000", "c1.xlarge", []string{"amd64"}},
 	{"cpu-power=2001", "cc1.4xlarge", []string{"amd64"}},
 	{"mem=2G", "m1.medium", []string{"amd64", "armhf"}},
+	{"virt-type=hvm", "cc1.4xlarge", []string{"amd64"}},
+	{"virt-type=pv", "m1.small", []string{"amd64", "armhf"}},
+	{"mem=1G..2G", "m1.small", []string{"amd64", "armhf"}},
+	{"cpu-cores=1..2", "m1.large", []string{"amd64"}},
 
 	{"arch=i386", "m1.small", nil},
 	{"cpu-power=100", "t1.micro", nil},
 	{"cpu-power=9001", "cc2.8xlarge", nil},
 	{"mem=1G", "t1.micro", nil},
 	{"arch=armhf", "c1.xlarge", nil},
+	{"virt-type=pv", "cc1.4xlarge", nil},
+	{"mem=1G..2G", "m1.medium", nil},
+	{"cpu-cores=1..1", "m1.large", nil},
+	{"cpu-power=100..300", "c1.medium", nil},
+	{"root-disk=4G..8G", "m1.medium", nil},
 }
 
 func (s *instanceTypeSuite) TestMatch(c *gc.C) {
@@ -396,7 +411,7 @@ var byCostTests = []struct {
 func (s *instanceTypeSuite) TestSortByCost(c *gc.C) {
 	for i, t := range byCostTests {
 		c.Logf("test %d: %s", i, t.about)
-		sort.Sort(byCost(t.itypesToUse))
+		SortByCost(t.itypesToUse)
 		names := make([]string, len(t.itypesToUse))
 		for i, itype := range t.itypesToUse {
 			names[i] = itype.Name
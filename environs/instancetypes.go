@@ -0,0 +1,25 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/environs/instances"
+)
+
+// InstanceTypesFetcher defines methods that environments able to list
+// the instance types available in their region may implement.
+type InstanceTypesFetcher interface {
+	// InstanceTypes returns the instance types available in the
+	// environment's region, with their region-specific cost where
+	// known, sorted by increasing cost.
+	InstanceTypes() ([]instances.InstanceType, error)
+}
+
+// SupportsInstanceTypes is a convenience helper to check if an
+// environment can list its available instance types. It returns
+// an InstanceTypesFetcher in this case.
+func SupportsInstanceTypes(environ Environ) (InstanceTypesFetcher, bool) {
+	fetcher, ok := environ.(InstanceTypesFetcher)
+	return fetcher, ok
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"os"
 
+	"gopkg.in/juju/environschema.v1"
+
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
@@ -66,6 +68,15 @@ type EnvironProvider interface {
 	SecretAttrs(cfg *config.Config) (map[string]string, error)
 }
 
+// ProviderSchema can be implemented by a provider to declare the
+// types, descriptions and secrecy of its configuration attributes.
+type ProviderSchema interface {
+	// Schema returns the configuration schema for the provider. It
+	// should include all fields defined in environs/config,
+	// conventionally by calling config.Schema.
+	Schema() environschema.Fields
+}
+
 // EnvironConfigUpgrader is an interface that an EnvironProvider may
 // implement in order to modify environment configuration on agent upgrade.
 type EnvironConfigUpgrader interface {
@@ -220,6 +231,17 @@ type InstanceTagger interface {
 	TagInstance(id instance.Id, tags map[string]string) error
 }
 
+// InstanceChangeNotifier is an interface that can be implemented by
+// environs whose clouds report changes to instances as they happen
+// (for example MAAS events), so the instance poller need not wait for
+// its next poll to notice them.
+type InstanceChangeNotifier interface {
+	// WatchInstanceChanges returns a channel on which the ids of
+	// instances whose addresses or status may have changed are
+	// sent, until the given stop channel is closed.
+	WatchInstanceChanges(stop <-chan struct{}) (<-chan []instance.Id, error)
+}
+
 // BootstrapContext is an interface that is passed to
 // Environ.Bootstrap, providing a means of obtaining
 // information about and manipulating the context in which
@@ -6,6 +6,7 @@ package manual
 var (
 	NetLookupHost         = &netLookupHost
 	ProvisionMachineAgent = &provisionMachineAgent
+	CheckPreflightOutput  = checkPreflightOutput
 )
 
 const (
@@ -117,6 +117,11 @@ type fakeSSH struct {
 	// exit code for the machine agent provisioning script.
 	ProvisionAgentExitCode int
 
+	// DiskAvailableKB is the available disk space reported in
+	// response to the preflight checks. If zero, ample space
+	// is reported.
+	DiskAvailableKB int
+
 	// InitUbuntuUser should be set to true if the fakeSSH script
 	// should respond to an attempt to initialise the ubuntu user.
 	InitUbuntuUser bool
@@ -143,6 +148,11 @@ func (r fakeSSH) install(c *gc.C) testing.Restorer {
 	if !r.SkipProvisionAgent {
 		add(nil, nil, r.ProvisionAgentExitCode)
 	}
+	diskAvailableKB := r.DiskAvailableKB
+	if diskAvailableKB == 0 {
+		diskAvailableKB = 10 * 1024 * 1024
+	}
+	add(nil, fmt.Sprintf("disk-available-kb %d", diskAvailableKB), 0)
 	if !r.SkipDetection {
 		restore.Add(installDetectionFakeSSH(c, r.Series, r.Arch))
 	}
@@ -29,14 +29,16 @@ cat /proc/cpuinfo`
 
 // CheckProvisioned checks if any juju init service already
 // exist on the host machine.
-var CheckProvisioned = checkProvisioned
+var CheckProvisioned = func(host string) (bool, error) {
+	return checkProvisioned(host, "")
+}
 
-func checkProvisioned(host string) (bool, error) {
+func checkProvisioned(host, via string) (bool, error) {
 	logger.Infof("Checking if %s is already provisioned", host)
 
 	script := service.ListServicesScript()
 
-	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, sshOptions(via))
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -61,11 +63,13 @@ func checkProvisioned(host string) (bool, error) {
 // DetectSeriesAndHardwareCharacteristics detects the OS
 // series and hardware characteristics of the remote machine
 // by connecting to the machine and executing a bash script.
-var DetectSeriesAndHardwareCharacteristics = detectSeriesAndHardwareCharacteristics
+var DetectSeriesAndHardwareCharacteristics = func(host string) (instance.HardwareCharacteristics, string, error) {
+	return detectSeriesAndHardwareCharacteristics(host, "")
+}
 
-func detectSeriesAndHardwareCharacteristics(host string) (hc instance.HardwareCharacteristics, series string, err error) {
+func detectSeriesAndHardwareCharacteristics(host, via string) (hc instance.HardwareCharacteristics, series string, err error) {
 	logger.Infof("Detecting series and characteristics on %s", host)
-	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, sshOptions(via))
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -133,9 +137,12 @@ func detectSeriesAndHardwareCharacteristics(host string) (hc instance.HardwareCh
 // authorizedKeys may be empty, in which case the file
 // will be created and left empty.
 //
+// If via is non-empty, the host is connected to through
+// the SSH bastion host it specifies, as [user@]host.
+//
 // stdin and stdout will be used for remote sudo prompts,
 // if the ubuntu user must be created/updated.
-func InitUbuntuUser(host, login, authorizedKeys string, stdin io.Reader, stdout io.Writer) error {
+func InitUbuntuUser(host, login, authorizedKeys, via string, stdin io.Reader, stdout io.Writer) error {
 	logger.Infof("initialising %q, user %q", host, login)
 
 	// To avoid unnecessary prompting for the specified login,
@@ -145,7 +152,7 @@ func InitUbuntuUser(host, login, authorizedKeys string, stdin io.Reader, stdout
 	//
 	// Note that we explicitly do not allocate a PTY, so we
 	// get a failure if sudo prompts.
-	cmd := ssh.Command("ubuntu@"+host, []string{"sudo", "-n", "true"}, nil)
+	cmd := ssh.Command("ubuntu@"+host, []string{"sudo", "-n", "true"}, sshOptions(via))
 	if cmd.Run() == nil {
 		logger.Infof("ubuntu user is already initialised")
 		return nil
@@ -157,10 +164,10 @@ func InitUbuntuUser(host, login, authorizedKeys string, stdin io.Reader, stdout
 		host = login + "@" + host
 	}
 	script := fmt.Sprintf(initUbuntuScript, utils.ShQuote(authorizedKeys))
-	var options ssh.Options
+	options := sshOptions(via)
 	options.AllowPasswordAuthentication()
 	options.EnablePTY()
-	cmd = ssh.Command(host, []string{"sudo", "/bin/bash -c " + utils.ShQuote(script)}, &options)
+	cmd = ssh.Command(host, []string{"sudo", "/bin/bash -c " + utils.ShQuote(script)}, options)
 	var stderr bytes.Buffer
 	cmd.Stdin = stdin
 	cmd.Stdout = stdout // for sudo prompt
@@ -174,6 +181,17 @@ func InitUbuntuUser(host, login, authorizedKeys string, stdin io.Reader, stdout
 	return nil
 }
 
+// sshOptions returns the options for SSH connections to a host being
+// provisioned. If via is non-empty, connections are proxied through the
+// SSH bastion host it specifies, as [user@]host.
+func sshOptions(via string) *ssh.Options {
+	var options ssh.Options
+	if via != "" {
+		options.SetProxyCommand("ssh", "-W", "%h:%p", via)
+	}
+	return &options
+}
+
 const initUbuntuScript = `
 set -e
 (id ubuntu &> /dev/null) || useradd -m ubuntu -s /bin/bash
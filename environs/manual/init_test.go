@@ -4,6 +4,8 @@
 package manual_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	jc "github.com/juju/testing/checkers"
@@ -12,6 +14,7 @@ import (
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/ssh"
 )
 
 type initialisationSuite struct {
@@ -142,18 +145,38 @@ func (s *initialisationSuite) TestCheckProvisioned(c *gc.C) {
 func (s *initialisationSuite) TestInitUbuntuUserNonExisting(c *gc.C) {
 	defer installFakeSSH(c, "", "", 0)() // successful creation of ubuntu user
 	defer installFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
-	err := manual.InitUbuntuUser("testhost", "testuser", "", nil, nil)
+	err := manual.InitUbuntuUser("testhost", "testuser", "", "", nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (s *initialisationSuite) TestInitUbuntuUserExisting(c *gc.C) {
 	defer installFakeSSH(c, "", nil, 0)()
-	manual.InitUbuntuUser("testhost", "testuser", "", nil, nil)
+	manual.InitUbuntuUser("testhost", "testuser", "", "", nil, nil)
 }
 
 func (s *initialisationSuite) TestInitUbuntuUserError(c *gc.C) {
 	defer installFakeSSH(c, "", []string{"", "failed to create ubuntu user"}, 123)()
 	defer installFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
-	err := manual.InitUbuntuUser("testhost", "testuser", "", nil, nil)
+	err := manual.InitUbuntuUser("testhost", "testuser", "", "", nil, nil)
 	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 123 \\(failed to create ubuntu user\\)")
 }
+
+func (s *initialisationSuite) TestInitUbuntuUserVia(c *gc.C) {
+	// Record the arguments passed to ssh, to check that
+	// the connection is proxied through the bastion.
+	fakebin := c.MkDir()
+	argsFile := filepath.Join(fakebin, "ssh.args")
+	script := "#!/bin/bash --norc\necho \"$@\" >> " + argsFile + "\n"
+	err := ioutil.WriteFile(filepath.Join(fakebin, "ssh"), []byte(script), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchEnvPathPrepend(fakebin)
+	client, err := ssh.NewOpenSSHClient()
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(&ssh.DefaultClient, client)
+
+	err = manual.InitUbuntuUser("testhost", "testuser", "", "ubuntu@bastion", nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	args, err := ioutil.ReadFile(argsFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(args), jc.Contains, "ProxyCommand ssh -W %h:%p ubuntu@bastion")
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/utils/ssh"
+)
+
+// minDiskAvailableKB is the minimum amount of disk space, in kilobytes,
+// that must be available under /var/lib for a host to be provisioned.
+const minDiskAvailableKB = 1024 * 1024
+
+// preflightScript is the script run on the remote machine to check
+// that it can be provisioned. It is formatted with the list of API
+// server addresses to check connectivity to.
+const preflightScript = `#!/bin/bash
+set -e
+echo "disk-available-kb $(df -Pk /var/lib | awk 'NR==2 {print $4}')"
+for addr in %s; do
+    host=${addr%%:*}
+    port=${addr##*:}
+    host=${host#[}
+    host=${host%%]}
+    if timeout 10 bash -c "exec 3<>/dev/tcp/$host/$port" 2>/dev/null; then
+        echo "api-reachable $addr"
+    else
+        echo "api-unreachable $addr"
+    fi
+done`
+
+// RunPreflightChecks checks that the host has enough disk space for
+// the agent, and that it can connect to at least one of the given API
+// server addresses. If via is non-empty, the host is connected to
+// through the SSH bastion host it specifies.
+var RunPreflightChecks = runPreflightChecks
+
+func runPreflightChecks(host, via string, apiAddrs []string) error {
+	logger.Infof("Running preflight checks on %s", host)
+	quoted := make([]string, 0, len(apiAddrs))
+	for _, addr := range apiAddrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return errors.Annotatef(err, "invalid API address %q", addr)
+		}
+		quoted = append(quoted, utils.ShQuote(addr))
+	}
+
+	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, sshOptions(via))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Stdin = strings.NewReader(fmt.Sprintf(preflightScript, strings.Join(quoted, " ")))
+	if err := cmd.Run(); err != nil {
+		if stderr.Len() != 0 {
+			err = fmt.Errorf("%v (%v)", err, strings.TrimSpace(stderr.String()))
+		}
+		return err
+	}
+	return checkPreflightOutput(stdout.String(), apiAddrs)
+}
+
+// checkPreflightOutput interprets the output of preflightScript,
+// returning an error describing every check that failed.
+func checkPreflightOutput(output string, apiAddrs []string) error {
+	var failures []string
+	var diskChecked bool
+	var unreachable []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "disk-available-kb":
+			diskChecked = true
+			available, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("cannot parse available disk space %q", fields[1]))
+			} else if available < minDiskAvailableKB {
+				failures = append(failures, fmt.Sprintf(
+					"insufficient disk space: %dMB available, %dMB required",
+					available/1024, minDiskAvailableKB/1024,
+				))
+			}
+		case "api-unreachable":
+			unreachable = append(unreachable, fields[1])
+		}
+	}
+	if !diskChecked {
+		failures = append(failures, "cannot determine available disk space")
+	}
+	if len(apiAddrs) > 0 && len(unreachable) == len(apiAddrs) {
+		failures = append(failures, fmt.Sprintf(
+			"cannot connect to API server at %s", strings.Join(unreachable, ", "),
+		))
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
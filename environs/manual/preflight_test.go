@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/manual"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type preflightSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&preflightSuite{})
+
+var apiAddrs = []string{"10.0.0.1:17070", "[2001:db8::1]:17070"}
+
+func (s *preflightSuite) TestCheckPreflightOutput(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		output string
+		err    string
+	}{{
+		about:  "all checks pass",
+		output: "disk-available-kb 2097152\napi-reachable 10.0.0.1:17070\napi-reachable [2001:db8::1]:17070\n",
+	}, {
+		about:  "one API server reachable",
+		output: "disk-available-kb 2097152\napi-unreachable 10.0.0.1:17070\napi-reachable [2001:db8::1]:17070\n",
+	}, {
+		about:  "insufficient disk space",
+		output: "disk-available-kb 102400\napi-reachable 10.0.0.1:17070\n",
+		err:    "insufficient disk space: 100MB available, 1024MB required",
+	}, {
+		about:  "no API server reachable",
+		output: "disk-available-kb 2097152\napi-unreachable 10.0.0.1:17070\napi-unreachable [2001:db8::1]:17070\n",
+		err:    `cannot connect to API server at 10.0.0.1:17070, \[2001:db8::1\]:17070`,
+	}, {
+		about:  "all checks fail",
+		output: "api-unreachable 10.0.0.1:17070\napi-unreachable [2001:db8::1]:17070\n",
+		err:    "cannot determine available disk space; cannot connect to API server at .*",
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		err := manual.CheckPreflightOutput(test.output, apiAddrs)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *preflightSuite) TestCheckPreflightOutputNoAPIAddresses(c *gc.C) {
+	err := manual.CheckPreflightOutput("disk-available-kb 2097152\n", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -59,6 +60,15 @@ type ProvisionMachineArgs struct {
 	// Stderr is required to present machine provisioning progress to the user.
 	Stderr io.Writer
 
+	// Via, if non-empty, is an SSH bastion host, in the format
+	// [user@]host, through which Host is connected to.
+	Via string
+
+	// APIAddresses holds the host:port addresses of the API servers.
+	// If specified, the host must be able to connect to at least one
+	// of them before it is provisioned.
+	APIAddresses []string
+
 	*params.UpdateBehavior
 }
 
@@ -73,6 +83,91 @@ var ErrProvisioned = errors.New("machine is already provisioned")
 // On successful completion, this function will return the id of the state.Machine
 // that was entered into state.
 func ProvisionMachine(args ProvisionMachineArgs) (machineId string, err error) {
+	machineId, _, err = provisionMachine(args, nil)
+	return machineId, err
+}
+
+// Provisioning phases, as reported by HostError.
+const (
+	PhaseConnect   = "connecting"
+	PhasePreflight = "preflight checks"
+	PhaseRegister  = "registering machine"
+	PhaseInstall   = "installing agent"
+)
+
+// HostError describes a failure to provision a host.
+type HostError struct {
+	// Host is the host that failed to be provisioned.
+	Host string
+
+	// Phase is the provisioning phase in which the failure occurred.
+	Phase string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error is part of the error interface.
+func (e *HostError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Host, e.Phase, e.Err)
+}
+
+// ProvisionResult holds the result of provisioning one host with
+// ProvisionMachines.
+type ProvisionResult struct {
+	// Host is the host that was provisioned.
+	Host string
+
+	// MachineId is the id of the machine entered into state,
+	// if provisioning succeeded.
+	MachineId string
+
+	// Error is non-nil if provisioning failed.
+	Error *HostError
+}
+
+// ProvisionMachines provisions machine agents to several existing hosts
+// concurrently, as ProvisionMachine does for a single host. Each host is
+// checked before it is entered into state, and the failure of one host
+// does not affect the others. The results are returned in the same order
+// as the arguments.
+//
+// The initial SSH connections, which may prompt for a sudo password, are
+// made one host at a time; output written to Stderr is prefixed with the
+// host it relates to.
+func ProvisionMachines(args []ProvisionMachineArgs) []ProvisionResult {
+	results := make([]ProvisionResult, len(args))
+	var promptMu sync.Mutex
+	var outputMu sync.Mutex
+	var wg sync.WaitGroup
+	for i, arg := range args {
+		results[i].Host = arg.Host
+		if arg.Stderr != nil {
+			arg.Stderr = &prefixWriter{
+				w:      arg.Stderr,
+				mu:     &outputMu,
+				prefix: arg.Host + ": ",
+			}
+		}
+		wg.Add(1)
+		go func(i int, arg ProvisionMachineArgs) {
+			defer wg.Done()
+			machineId, phase, err := provisionMachine(arg, &promptMu)
+			if err != nil {
+				results[i].Error = &HostError{Host: arg.Host, Phase: phase, Err: err}
+				return
+			}
+			results[i].MachineId = machineId
+		}(i, arg)
+	}
+	wg.Wait()
+	return results
+}
+
+// provisionMachine implements ProvisionMachine, additionally returning
+// the phase in which any error occurred. If promptMu is non-nil, it is
+// held while initialising the ubuntu user, which may prompt the user.
+func provisionMachine(args ProvisionMachineArgs, promptMu *sync.Mutex) (machineId, phase string, err error) {
 	defer func() {
 		if machineId != "" && err != nil {
 			logger.Errorf("provisioning failed, removing machine %v: %v", machineId, err)
@@ -89,19 +184,29 @@ func ProvisionMachine(args ProvisionMachineArgs) (machineId string, err error) {
 	// ubuntu user's authorized_keys.
 	user, hostname := splitUserHost(args.Host)
 	authorizedKeys, err := config.ReadAuthorizedKeys("")
-	if err := InitUbuntuUser(hostname, user, authorizedKeys, args.Stdin, args.Stdout); err != nil {
-		return "", err
+	if promptMu != nil {
+		promptMu.Lock()
+	}
+	err = InitUbuntuUser(hostname, user, authorizedKeys, args.Via, args.Stdin, args.Stdout)
+	if promptMu != nil {
+		promptMu.Unlock()
+	}
+	if err != nil {
+		return "", PhaseConnect, err
 	}
 
-	machineParams, err := gatherMachineParams(hostname)
+	machineParams, err := gatherMachineParams(hostname, args.Via)
 	if err != nil {
-		return "", err
+		return "", PhasePreflight, err
+	}
+	if err := RunPreflightChecks(hostname, args.Via, args.APIAddresses); err != nil {
+		return "", PhasePreflight, err
 	}
 
 	// Inform Juju that the machine exists.
 	machineId, err = recordMachineInState(args.Client, *machineParams)
 	if err != nil {
-		return "", err
+		return "", PhaseRegister, err
 	}
 
 	provisioningScript, err := args.Client.ProvisioningScript(params.ProvisioningScriptParams{
@@ -112,17 +217,47 @@ func ProvisionMachine(args ProvisionMachineArgs) (machineId string, err error) {
 
 	if err != nil {
 		logger.Errorf("cannot obtain provisioning script")
-		return "", err
+		return machineId, PhaseRegister, err
 	}
 
 	// Finally, provision the machine agent.
-	err = runProvisionScript(provisioningScript, hostname, args.Stderr)
+	err = runProvisionScript(provisioningScript, hostname, args.Via, args.Stderr)
 	if err != nil {
-		return machineId, err
+		return machineId, PhaseInstall, err
 	}
 
 	logger.Infof("Provisioned machine %v", machineId)
-	return machineId, nil
+	return machineId, "", nil
+}
+
+// prefixWriter is an io.Writer that prefixes each line written to the
+// underlying writer, serialising writes with other prefixWriters that
+// share its mutex. Partial lines are buffered until they are complete.
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+// Write is part of the io.Writer interface.
+func (w *prefixWriter) Write(data []byte) (int, error) {
+	w.buf = append(w.buf, data...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			break
+		}
+		line := w.buf[:i+1]
+		w.mu.Lock()
+		_, err := fmt.Fprintf(w.w, "%s%s", w.prefix, line)
+		w.mu.Unlock()
+		w.buf = w.buf[i+1:]
+		if err != nil {
+			return len(data), err
+		}
+	}
+	return len(data), nil
 }
 
 func splitUserHost(host string) (string, string) {
@@ -150,7 +285,7 @@ func recordMachineInState(client ProvisioningClientAPI, machineParams params.Add
 // The hostname supplied should not include a username.
 // If we can, we will reverse lookup the hostname by its IP address, and use
 // the DNS resolved name, rather than the name that was supplied
-func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
+func gatherMachineParams(hostname, via string) (*params.AddMachineParams, error) {
 
 	// Generate a unique nonce for the machine.
 	uuid, err := utils.NewUUID()
@@ -165,7 +300,7 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 		addrs = append(addrs, addr)
 	}
 
-	provisioned, err := checkProvisioned(hostname)
+	provisioned, err := checkProvisioned(hostname, via)
 	if err != nil {
 		err = fmt.Errorf("error checking if provisioned: %v", err)
 		return nil, err
@@ -174,7 +309,7 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 		return nil, ErrProvisioned
 	}
 
-	hc, series, err := DetectSeriesAndHardwareCharacteristics(hostname)
+	hc, series, err := detectSeriesAndHardwareCharacteristics(hostname, via)
 	if err != nil {
 		err = fmt.Errorf("error detecting hardware characteristics: %v", err)
 		return nil, err
@@ -208,7 +343,7 @@ var provisionMachineAgent = func(host string, icfg *instancecfg.InstanceConfig,
 	if err != nil {
 		return err
 	}
-	return runProvisionScript(script, host, progressWriter)
+	return runProvisionScript(script, host, "", progressWriter)
 }
 
 // ProvisioningScript generates a bash script that can be
@@ -244,9 +379,10 @@ func ProvisioningScript(icfg *instancecfg.InstanceConfig) (string, error) {
 	return buf.String(), nil
 }
 
-func runProvisionScript(script, host string, progressWriter io.Writer) error {
+func runProvisionScript(script, host, via string, progressWriter io.Writer) error {
 	params := sshinit.ConfigureParams{
 		Host:           "ubuntu@" + host,
+		Options:        sshOptions(via),
 		ProgressWriter: progressWriter,
 	}
 	return sshinit.RunConfigureScript(script, params)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/shell"
 	gc "gopkg.in/check.v1"
@@ -123,6 +124,47 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "error checking if provisioned: subprocess encountered error code 255")
 }
 
+func (s *provisionerSuite) TestProvisionMachinePreflightFailure(c *gc.C) {
+	defer fakeSSH{
+		Series:             coretesting.FakeDefaultSeries,
+		Arch:               "amd64",
+		InitUbuntuUser:     true,
+		SkipProvisionAgent: true,
+		DiskAvailableKB:    1024,
+	}.install(c).Restore()
+	machineId, err := manual.ProvisionMachine(s.getArgs(c))
+	c.Assert(err, gc.ErrorMatches, "insufficient disk space: 1MB available, 1024MB required")
+	c.Assert(machineId, gc.Equals, "")
+
+	// The machine was never entered into state.
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *provisionerSuite) TestProvisionMachines(c *gc.C) {
+	s.PatchValue(&manual.RunPreflightChecks, func(host, via string, apiAddrs []string) error {
+		c.Check(apiAddrs, jc.DeepEquals, []string{"10.0.0.1:17070"})
+		return errors.New("cannot connect to API server at 10.0.0.1:17070")
+	})
+	defer fakeSSH{
+		Series:             coretesting.FakeDefaultSeries,
+		Arch:               "amd64",
+		InitUbuntuUser:     true,
+		SkipProvisionAgent: true,
+	}.install(c).Restore()
+	args := s.getArgs(c)
+	args.APIAddresses = []string{"10.0.0.1:17070"}
+	results := manual.ProvisionMachines([]manual.ProvisionMachineArgs{args})
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Host, gc.Equals, args.Host)
+	c.Assert(results[0].MachineId, gc.Equals, "")
+	c.Assert(results[0].Error, gc.NotNil)
+	c.Assert(results[0].Error.Phase, gc.Equals, manual.PhasePreflight)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		args.Host+": preflight checks: cannot connect to API server at 10.0.0.1:17070")
+}
+
 func (s *provisionerSuite) TestFinishInstancConfig(c *gc.C) {
 	const series = coretesting.FakeDefaultSeries
 	const arch = "amd64"
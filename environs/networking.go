@@ -60,6 +60,17 @@ func SupportsNetworking(environ Environ) (NetworkingEnviron, bool) {
 	return ne, ok
 }
 
+// NetworkingCapable is like SupportsNetworking, but only reports that
+// the environment supports networking if its provider, of the given
+// type, declared CapabilityNetworking when it was registered.
+func NetworkingCapable(providerType string, environ Environ) (NetworkingEnviron, bool) {
+	capable, err := HasCapability(providerType, CapabilityNetworking)
+	if err != nil || !capable {
+		return nil, false
+	}
+	return SupportsNetworking(environ)
+}
+
 // AddressAllocationEnabled is a shortcut for checking if the
 // AddressAllocation feature flag is enabled.
 func AddressAllocationEnabled() bool {
@@ -0,0 +1,211 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package offline creates and unpacks offline bundles: single archives
+// holding the agent tools, image metadata and charms needed to bootstrap
+// and operate an environment without access to the Internet.
+//
+// An unpacked bundle is laid out so that its root directory may be used
+// as the metadata source when bootstrapping, and its charms directory
+// as a local charm repository:
+//
+//	tools/    agent tools and their simplestreams metadata
+//	images/   image simplestreams metadata
+//	charms/   charm archives, as <series>/<name>.charm
+package offline
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"github.com/juju/utils/tar"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+var logger = loggo.GetLogger("juju.environs.offline")
+
+const (
+	// ToolsDir is the bundle directory holding agent tools.
+	ToolsDir = "tools"
+
+	// ImagesDir is the bundle directory holding image metadata.
+	ImagesDir = "images"
+
+	// CharmsDir is the bundle directory holding charms.
+	CharmsDir = "charms"
+)
+
+// Pack writes the bundle held in dir to w, as a gzipped tar archive.
+func Pack(w io.Writer, dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(entries) == 0 {
+		return errors.Errorf("offline bundle directory %q is empty", dir)
+	}
+	files := make([]string, len(entries))
+	for i, entry := range entries {
+		files[i] = filepath.Join(dir, entry.Name())
+	}
+	gzw := gzip.NewWriter(w)
+	stripPrefix := dir + string(os.PathSeparator)
+	if _, err := tar.TarFiles(files, gzw, stripPrefix); err != nil {
+		return errors.Annotate(err, "cannot archive offline bundle")
+	}
+	return errors.Trace(gzw.Close())
+}
+
+// Unpack extracts the bundle archive read from r into dir.
+func Unpack(r io.Reader, dir string) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Annotate(err, "cannot uncompress offline bundle")
+	}
+	defer gzr.Close()
+	if err := tar.UntarFiles(gzr, dir); err != nil {
+		return errors.Annotate(err, "cannot extract offline bundle")
+	}
+	return nil
+}
+
+// UnpackFile extracts the bundle archive at path into a new temporary
+// directory, and returns the directory. It is the caller's
+// responsibility to remove the directory when done.
+func UnpackFile(path string) (_ string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer f.Close()
+	dir, err := ioutil.TempDir("", "juju-offline-bundle")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+	if err := Unpack(f, dir); err != nil {
+		return "", errors.Trace(err)
+	}
+	return dir, nil
+}
+
+// IsBundleFile reports whether path names an offline bundle archive
+// rather than an unpacked bundle directory.
+func IsBundleFile(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return info.Mode().IsRegular(), nil
+}
+
+// AddCharm copies the given charm archive into the bundle in dir,
+// for deployment on machines running the given series.
+func AddCharm(dir, series string, ch *charm.CharmArchive) error {
+	seriesDir := filepath.Join(dir, CharmsDir, series)
+	if err := os.MkdirAll(seriesDir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	target := filepath.Join(seriesDir, ch.Meta().Name+".charm")
+	if err := utils.CopyFile(target, ch.Path); err != nil {
+		return errors.Annotatef(err, "cannot add charm %q to offline bundle", ch.Meta().Name)
+	}
+	return nil
+}
+
+// Charms returns the local URLs of the charms held in the bundle in
+// dir, as they would be deployed from its charm repository.
+func Charms(dir string) ([]*charm.URL, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, CharmsDir, "*", "*.charm"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var urls []*charm.URL
+	for _, path := range paths {
+		ch, err := charm.ReadCharmArchive(path)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read charm %q", path)
+		}
+		urls = append(urls, &charm.URL{
+			Schema:   "local",
+			Name:     ch.Meta().Name,
+			Series:   filepath.Base(filepath.Dir(path)),
+			Revision: ch.Revision(),
+		})
+	}
+	return urls, nil
+}
+
+// ImageMetadata returns the image metadata held in the bundle in dir.
+// If the bundle holds no image metadata, nil is returned.
+func ImageMetadata(dir string) ([]*imagemetadata.ImageMetadata, error) {
+	imagesDir := filepath.Join(dir, ImagesDir)
+	if _, err := os.Stat(imagesDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	baseURL := "file://" + filepath.ToSlash(imagesDir)
+	source := simplestreams.NewURLDataSource("offline bundle", baseURL, utils.NoVerifySSLHostnames)
+	cons := imagemetadata.NewImageConstraint(simplestreams.LookupParams{})
+	metadata, _, err := imagemetadata.Fetch([]simplestreams.DataSource{source}, cons, false)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read image metadata")
+	}
+	return metadata, nil
+}
+
+// SignMetadata inline signs the simplestreams metadata for tools and
+// images held in the bundle in dir, writing a signed copy of each
+// metadata file alongside it. The private key must be armored; if it
+// is encrypted, the passphrase is used to decrypt it.
+func SignMetadata(dir, armoredPrivateKey, passphrase string) error {
+	for _, subdir := range []string{ToolsDir, ImagesDir} {
+		root := filepath.Join(dir, subdir)
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !strings.HasSuffix(path, simplestreams.UnsignedSuffix) {
+				return nil
+			}
+			return signFile(path, armoredPrivateKey, passphrase)
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func signFile(path, armoredPrivateKey, passphrase string) error {
+	logger.Debugf("signing %q", path)
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	encoded, err := simplestreams.Encode(f, armoredPrivateKey, passphrase)
+	if err != nil {
+		return errors.Annotatef(err, "cannot sign %q", path)
+	}
+	signedPath := strings.TrimSuffix(path, simplestreams.UnsignedSuffix) + simplestreams.SignedSuffix
+	return errors.Trace(ioutil.WriteFile(signedPath, encoded, 0644))
+}
@@ -0,0 +1,167 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/offline"
+	"github.com/juju/juju/environs/simplestreams"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	"github.com/juju/juju/testcharms"
+	"github.com/juju/juju/testing"
+)
+
+type bundleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&bundleSuite{})
+
+func writeFile(c *gc.C, dir, name, content string) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bundleSuite) TestPackUnpack(c *gc.C) {
+	dir := c.MkDir()
+	writeFile(c, dir, "tools/streams/v1/index.json", "tools index")
+	writeFile(c, dir, "images/streams/v1/index.json", "images index")
+
+	var buf bytes.Buffer
+	err := offline.Pack(&buf, dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	target := c.MkDir()
+	err = offline.Unpack(&buf, target)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(filepath.Join(target, "tools", "streams", "v1", "index.json"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "tools index")
+	data, err = ioutil.ReadFile(filepath.Join(target, "images", "streams", "v1", "index.json"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "images index")
+}
+
+func (s *bundleSuite) TestPackEmpty(c *gc.C) {
+	dir := c.MkDir()
+	var buf bytes.Buffer
+	err := offline.Pack(&buf, dir)
+	c.Assert(err, gc.ErrorMatches, `offline bundle directory ".*" is empty`)
+}
+
+func (s *bundleSuite) TestUnpackFile(c *gc.C) {
+	dir := c.MkDir()
+	writeFile(c, dir, "tools/streams/v1/index.json", "tools index")
+	path := filepath.Join(c.MkDir(), "bundle.tar.gz")
+	f, err := os.Create(path)
+	c.Assert(err, jc.ErrorIsNil)
+	err = offline.Pack(f, dir)
+	f.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	isFile, err := offline.IsBundleFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isFile, jc.IsTrue)
+	isFile, err = offline.IsBundleFile(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isFile, jc.IsFalse)
+
+	unpacked, err := offline.UnpackFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer os.RemoveAll(unpacked)
+	_, err = os.Stat(filepath.Join(unpacked, "tools", "streams", "v1", "index.json"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bundleSuite) TestUnpackNotGzipped(c *gc.C) {
+	err := offline.Unpack(bytes.NewBufferString("not a bundle"), c.MkDir())
+	c.Assert(err, gc.ErrorMatches, "cannot uncompress offline bundle: .*")
+}
+
+func (s *bundleSuite) TestAddCharm(c *gc.C) {
+	dir := c.MkDir()
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	err := offline.AddCharm(dir, "trusty", ch)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = os.Stat(filepath.Join(dir, "charms", "trusty", "dummy.charm"))
+	c.Assert(err, jc.ErrorIsNil)
+	urls, err := offline.Charms(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(urls, jc.DeepEquals, []*charm.URL{
+		charm.MustParseURL(fmt.Sprintf("local:trusty/dummy-%d", ch.Revision())),
+	})
+}
+
+// writeImageMetadata writes simplestreams image metadata for a single
+// image into the bundle in dir.
+func writeImageMetadata(c *gc.C, dir string) {
+	stor, err := filestorage.NewFileStorageWriter(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	metadata := []*imagemetadata.ImageMetadata{{
+		Id:       "1234",
+		Arch:     "amd64",
+		VirtType: "hvm",
+	}}
+	cloudSpec := &simplestreams.CloudSpec{
+		Region:   "region",
+		Endpoint: "endpoint",
+	}
+	err = imagemetadata.MergeAndWriteMetadata("trusty", metadata, cloudSpec, stor)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bundleSuite) TestImageMetadata(c *gc.C) {
+	dir := c.MkDir()
+	writeImageMetadata(c, dir)
+
+	metadata, err := offline.ImageMetadata(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 1)
+	c.Check(metadata[0].Id, gc.Equals, "1234")
+	c.Check(metadata[0].Arch, gc.Equals, "amd64")
+	c.Check(metadata[0].Version, gc.Equals, "14.04")
+	c.Check(metadata[0].RegionName, gc.Equals, "region")
+}
+
+func (s *bundleSuite) TestImageMetadataNone(c *gc.C) {
+	metadata, err := offline.ImageMetadata(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 0)
+}
+
+func (s *bundleSuite) TestSignMetadata(c *gc.C) {
+	dir := c.MkDir()
+	writeFile(c, dir, "tools/streams/v1/index.json", `{"format": "index:1.0"}`)
+	writeFile(c, dir, "charms/trusty/metadata.json", `{}`)
+
+	err := offline.SignMetadata(dir, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+
+	f, err := os.Open(filepath.Join(dir, "tools", "streams", "v1", "index.sjson"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	data, err := simplestreams.DecodeCheckSignature(f, sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.TrimSpace(string(data)), gc.Equals, `{"format": "index:1.0"}`)
+
+	// Only tools and images metadata are signed.
+	_, err = os.Stat(filepath.Join(dir, "charms", "trusty", "metadata.sjson"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
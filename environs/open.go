@@ -67,7 +67,7 @@ func ConfigForName(name string, store configstore.Storage) (*config.Config, Conf
 		if len(info.BootstrapConfig()) == 0 {
 			return nil, ConfigFromNowhere, EmptyConfig{fmt.Errorf("environment has no bootstrap configuration data")}
 		}
-		logger.Debugf("ConfigForName found bootstrap config %#v", info.BootstrapConfig())
+		logger.Debugf("ConfigForName found bootstrap config %#v", RedactSecrets(info.BootstrapConfig()))
 		cfg, err := config.New(config.NoDefaults, info.BootstrapConfig())
 		return cfg, ConfigFromInfo, err
 	} else if !errors.IsNotFound(err) {
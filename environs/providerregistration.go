@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+)
+
+// ProviderAPIVersion is the version of the provider registration
+// interface implemented by this package. A provider must be built
+// against the same version in order to be registered.
+const ProviderAPIVersion = 1
+
+// ProviderCapability names an optional feature that a provider's
+// environments implement.
+type ProviderCapability string
+
+const (
+	// CapabilityNetworking indicates that the provider's environments
+	// implement NetworkingEnviron.
+	CapabilityNetworking ProviderCapability = "networking"
+
+	// CapabilityStorage indicates that the provider supplies its own
+	// storage providers, in addition to the common ones.
+	CapabilityStorage ProviderCapability = "storage"
+
+	// CapabilityAvailabilityZones indicates that the provider's
+	// environments expose availability zones.
+	CapabilityAvailabilityZones ProviderCapability = "availability-zones"
+)
+
+// ProviderRegistration holds everything needed to register an
+// environment provider.
+//
+// Providers, including those maintained outside this repository, are
+// registered by calling Register from an init function in their
+// package; a provider is compiled into a binary by importing its
+// package from the binary's main package (or from provider/all for
+// in-tree providers).
+type ProviderRegistration struct {
+	// Type is the provider type, as used in environment
+	// configuration.
+	Type string
+
+	// Aliases holds alternative names for the provider type.
+	Aliases []string
+
+	// Provider is the provider itself.
+	Provider EnvironProvider
+
+	// APIVersion is the provider API version that the provider was
+	// built against; it should be set to ProviderAPIVersion.
+	APIVersion int
+
+	// Capabilities holds the optional features implemented by the
+	// provider's environments.
+	Capabilities []ProviderCapability
+}
+
+// Validate returns an error if the registration is not valid.
+func (r ProviderRegistration) Validate() error {
+	if r.Type == "" {
+		return errors.NotValidf("empty provider type")
+	}
+	if r.Provider == nil {
+		return errors.NotValidf("nil provider for %q", r.Type)
+	}
+	if r.APIVersion != ProviderAPIVersion {
+		return errors.Errorf(
+			"provider %q built for provider API version %d, expected %d",
+			r.Type, r.APIVersion, ProviderAPIVersion,
+		)
+	}
+	return nil
+}
+
+// Register registers a new environment provider.
+//
+// Register will panic if the registration is invalid, or if the
+// provider type or any of the aliases are registered more than once.
+func Register(r ProviderRegistration) {
+	if err := GlobalProviderRegistry().Register(r); err != nil {
+		panic(fmt.Errorf("juju: %v", err))
+	}
+}
+
+// ProviderCapabilities returns the capabilities declared by the
+// provider with the given type.
+func ProviderCapabilities(providerType string) ([]ProviderCapability, error) {
+	return GlobalProviderRegistry().Capabilities(providerType)
+}
+
+// HasCapability reports whether the provider with the given type
+// declared the given capability when it was registered.
+func HasCapability(providerType string, capability ProviderCapability) (bool, error) {
+	capabilities, err := ProviderCapabilities(providerType)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, c := range capabilities {
+		if c == capability {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/environs/config"
+)
+
+// RedactSecrets returns a copy of the given environment configuration
+// attributes in which the values of secret attributes are replaced by
+// config.RedactedValue, so that the result may be shown or logged.
+//
+// Secret attributes are those marked as secret in the schema of the
+// environment's provider or of the config package. For providers that
+// do not declare a schema, the attributes returned by the provider's
+// SecretAttrs method are treated as secret.
+func RedactSecrets(attrs map[string]interface{}) map[string]interface{} {
+	var fields environschema.Fields
+	providerType, _ := attrs["type"].(string)
+	if p, err := Provider(providerType); err == nil {
+		fields = providerSecretFields(p, attrs)
+	}
+	return config.RedactSecrets(attrs, fields)
+}
+
+// providerSecretFields returns schema fields describing the secret
+// attributes of the given provider's configuration.
+func providerSecretFields(p EnvironProvider, attrs map[string]interface{}) environschema.Fields {
+	if ps, ok := p.(ProviderSchema); ok {
+		return ps.Schema()
+	}
+	cfg, err := config.New(config.NoDefaults, attrs)
+	if err != nil {
+		return nil
+	}
+	secrets, err := p.SecretAttrs(cfg)
+	if err != nil {
+		logger.Debugf("cannot determine secret attributes for %q provider: %v", cfg.Type(), err)
+		return nil
+	}
+	fields := make(environschema.Fields)
+	for name := range secrets {
+		fields[name] = environschema.Attr{
+			Type:   environschema.Tstring,
+			Secret: true,
+		}
+	}
+	return fields
+}
@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type secretsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func (s *secretsSuite) TestRedactSecretsProviderSchema(c *gc.C) {
+	attrs := dummy.SampleConfig()
+	redacted := environs.RedactSecrets(attrs)
+	c.Check(redacted["secret"], gc.Equals, config.RedactedValue)
+	c.Check(redacted["admin-secret"], gc.Equals, config.RedactedValue)
+	c.Check(redacted["name"], gc.Equals, attrs["name"])
+	// The original attributes are left alone.
+	c.Check(attrs["secret"], gc.Equals, "pork")
+}
+
+func (s *secretsSuite) TestRedactSecretsProviderSecretAttrs(c *gc.C) {
+	attrs := testing.FakeConfig().Merge(testing.Attrs{
+		"type":             "manual",
+		"bootstrap-host":   "hostname",
+		"storage-auth-key": "whatever",
+		"use-sshstorage":   false,
+	})
+	redacted := environs.RedactSecrets(attrs)
+	c.Check(redacted["storage-auth-key"], gc.Equals, config.RedactedValue)
+	c.Check(redacted["admin-secret"], gc.Equals, config.RedactedValue)
+	c.Check(redacted["bootstrap-host"], gc.Equals, "hostname")
+}
+
+func (s *secretsSuite) TestRedactSecretsUnknownProvider(c *gc.C) {
+	attrs := testing.FakeConfig().Merge(testing.Attrs{
+		"secret": "pork",
+	})
+	redacted := environs.RedactSecrets(attrs)
+	c.Check(redacted["admin-secret"], gc.Equals, config.RedactedValue)
+	c.Check(redacted["secret"], gc.Equals, "pork")
+	c.Check(redacted, gc.HasLen, len(attrs))
+}
@@ -0,0 +1,190 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package simplestreams
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// DefaultMetadataCacheTTL is how long cached metadata is used before
+// checking whether it has changed.
+const DefaultMetadataCacheTTL = time.Hour
+
+// MetadataCache is an on-disk cache of simplestreams metadata fetched
+// over HTTP. Cached metadata is used without contacting the server
+// until it is older than the cache's TTL; after that it is revalidated
+// with a conditional GET, so that metadata which has not changed is not
+// downloaded again.
+type MetadataCache struct {
+	dir string
+	ttl time.Duration
+
+	// now returns the current time. It is a field so that it can be
+	// changed for testing.
+	now func() time.Time
+}
+
+// NewMetadataCache returns a cache which stores metadata in dir and
+// revalidates it once it is older than ttl.
+func NewMetadataCache(dir string, ttl time.Duration) *MetadataCache {
+	return &MetadataCache{
+		dir: dir,
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// cacheEntry records where and when a cached file was fetched, and the
+// validators the server returned for it.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last-modified,omitempty"`
+	Fetched      time.Time `json:"fetched"`
+}
+
+var (
+	metadataCacheMu sync.Mutex
+	metadataCache   *MetadataCache
+)
+
+// SetMetadataCache sets the cache used by URL datasources when fetching
+// metadata over HTTP. Caching is disabled if cache is nil.
+func SetMetadataCache(cache *MetadataCache) {
+	metadataCacheMu.Lock()
+	defer metadataCacheMu.Unlock()
+	metadataCache = cache
+}
+
+func currentMetadataCache() *MetadataCache {
+	metadataCacheMu.Lock()
+	defer metadataCacheMu.Unlock()
+	return metadataCache
+}
+
+// isCacheableURL reports whether data at the given URL may be cached.
+// Local files are never cached.
+func isCacheableURL(dataURL string) bool {
+	return strings.HasPrefix(dataURL, "http://") || strings.HasPrefix(dataURL, "https://")
+}
+
+// paths returns the paths of the files holding the entry and data
+// cached for the given URL.
+func (c *MetadataCache) paths(dataURL string) (entryPath, dataPath string) {
+	name := fmt.Sprintf("%x", sha256.Sum256([]byte(dataURL)))
+	return filepath.Join(c.dir, name+".json"), filepath.Join(c.dir, name+".data")
+}
+
+// readEntry returns the cache entry for the given URL, or nil if there
+// is none.
+func (c *MetadataCache) readEntry(entryPath, dataURL string) *cacheEntry {
+	data, err := ioutil.ReadFile(entryPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Debugf("cannot read metadata cache entry for %q: %v", dataURL, err)
+		}
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != dataURL {
+		logger.Debugf("ignoring invalid metadata cache entry for %q", dataURL)
+		return nil
+	}
+	return &entry
+}
+
+// writeEntry records entry, and data if it is not nil, in the cache.
+func (c *MetadataCache) writeEntry(entryPath, dataPath string, entry *cacheEntry, data []byte) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	if data != nil {
+		if err := utils.AtomicWriteFile(dataPath, data, 0644); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	entryData, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(utils.AtomicWriteFile(entryPath, entryData, 0644))
+}
+
+// fetch returns the data at dataURL, from the cache if it is fresh or
+// the server reports it unchanged, and from the server otherwise.
+func (c *MetadataCache) fetch(client *http.Client, dataURL string) (io.ReadCloser, error) {
+	entryPath, dataPath := c.paths(dataURL)
+	entry := c.readEntry(entryPath, dataURL)
+	if entry != nil && c.now().Sub(entry.Fetched) < c.ttl {
+		if f, err := os.Open(dataPath); err == nil {
+			logger.Tracef("using cached metadata for %q", dataURL)
+			return f, nil
+		}
+		entry = nil
+	}
+
+	req, err := http.NewRequest("GET", dataURL, nil)
+	if err != nil {
+		return nil, errors.NotFoundf("invalid URL %q", dataURL)
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		logger.Tracef("Got error requesting %q: %v", dataURL, err)
+		return nil, errors.NotFoundf("invalid URL %q", dataURL)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		f, err := os.Open(dataPath)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		logger.Tracef("cached metadata for %q is unchanged", dataURL)
+		entry.Fetched = c.now()
+		if err := c.writeEntry(entryPath, dataPath, entry, nil); err != nil {
+			logger.Warningf("cannot update metadata cache for %q: %v", dataURL, err)
+		}
+		return f, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, httpFetchError(dataURL, resp)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read URL %q", dataURL)
+	}
+	entry = &cacheEntry{
+		URL:          dataURL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Fetched:      c.now(),
+	}
+	if err := c.writeEntry(entryPath, dataPath, entry, data); err != nil {
+		logger.Warningf("cannot cache metadata for %q: %v", dataURL, err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
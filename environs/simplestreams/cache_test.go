@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package simplestreams_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+var _ = gc.Suite(&cacheSuite{})
+
+type cacheSuite struct {
+	server      *httptest.Server
+	cacheDir    string
+	cache       *simplestreams.MetadataCache
+	now         time.Time
+	etag        string
+	content     string
+	requests    int
+	notModified int
+}
+
+func (s *cacheSuite) SetUpTest(c *gc.C) {
+	s.etag = `"v1"`
+	s.content = "version one"
+	s.requests = 0
+	s.notModified = 0
+	s.server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		s.requests++
+		if req.URL.Path != "/streams/v1/index.json" {
+			http.NotFound(resp, req)
+			return
+		}
+		if req.Header.Get("If-None-Match") == s.etag {
+			s.notModified++
+			resp.WriteHeader(http.StatusNotModified)
+			return
+		}
+		resp.Header().Set("ETag", s.etag)
+		resp.Write([]byte(s.content))
+	}))
+	s.now = time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.cacheDir = c.MkDir()
+	s.cache = simplestreams.NewMetadataCache(s.cacheDir, time.Hour)
+	simplestreams.SetMetadataCacheClock(s.cache, func() time.Time { return s.now })
+	simplestreams.SetMetadataCache(s.cache)
+}
+
+func (s *cacheSuite) TearDownTest(c *gc.C) {
+	simplestreams.SetMetadataCache(nil)
+	s.server.Close()
+}
+
+func (s *cacheSuite) fetch(c *gc.C) string {
+	ds := simplestreams.NewURLDataSource("test", s.server.URL, utils.VerifySSLHostnames)
+	rc, url, err := ds.Fetch("streams/v1/index.json")
+	c.Assert(err, jc.ErrorIsNil)
+	defer rc.Close()
+	c.Assert(url, gc.Equals, s.server.URL+"/streams/v1/index.json")
+	data, err := ioutil.ReadAll(rc)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *cacheSuite) TestFetchWithinTTLUsesCache(c *gc.C) {
+	c.Assert(s.fetch(c), gc.Equals, "version one")
+	s.now = s.now.Add(30 * time.Minute)
+	c.Assert(s.fetch(c), gc.Equals, "version one")
+	c.Assert(s.requests, gc.Equals, 1)
+}
+
+func (s *cacheSuite) TestFetchAfterTTLRevalidates(c *gc.C) {
+	c.Assert(s.fetch(c), gc.Equals, "version one")
+	s.now = s.now.Add(2 * time.Hour)
+	c.Assert(s.fetch(c), gc.Equals, "version one")
+	c.Assert(s.requests, gc.Equals, 2)
+	c.Assert(s.notModified, gc.Equals, 1)
+
+	// The revalidated entry is fresh again.
+	s.now = s.now.Add(30 * time.Minute)
+	c.Assert(s.fetch(c), gc.Equals, "version one")
+	c.Assert(s.requests, gc.Equals, 2)
+}
+
+func (s *cacheSuite) TestFetchAfterTTLGetsChangedData(c *gc.C) {
+	c.Assert(s.fetch(c), gc.Equals, "version one")
+	s.etag = `"v2"`
+	s.content = "version two"
+	s.now = s.now.Add(2 * time.Hour)
+	c.Assert(s.fetch(c), gc.Equals, "version two")
+	c.Assert(s.notModified, gc.Equals, 0)
+}
+
+func (s *cacheSuite) TestFetchNotFound(c *gc.C) {
+	ds := simplestreams.NewURLDataSource("test", s.server.URL, utils.VerifySSLHostnames)
+	_, _, err := ds.Fetch("streams/v1/missing.json")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	files, err := ioutil.ReadDir(s.cacheDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
+
+func (s *cacheSuite) TestFileURLNotCached(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "index.json"), []byte("local"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ds := simplestreams.NewURLDataSource("test", "file://"+dir, utils.VerifySSLHostnames)
+	rc, _, err := ds.Fetch("index.json")
+	c.Assert(err, jc.ErrorIsNil)
+	rc.Close()
+	files, err := ioutil.ReadDir(s.cacheDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
+
+func (s *cacheSuite) TestCacheDisabled(c *gc.C) {
+	simplestreams.SetMetadataCache(nil)
+	c.Assert(s.fetch(c), gc.Equals, "version one")
+	c.Assert(s.fetch(c), gc.Equals, "version one")
+	c.Assert(s.requests, gc.Equals, 2)
+}
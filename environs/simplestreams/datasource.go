@@ -73,6 +73,10 @@ func (h *urlDataSource) Fetch(path string) (io.ReadCloser, string, error) {
 	// dataURL can be http:// or file://
 	// MakeFileURL will only modify the URL if it's a file URL
 	dataURL = utils.MakeFileURL(dataURL)
+	if cache := currentMetadataCache(); cache != nil && isCacheableURL(dataURL) {
+		rc, err := cache.fetch(client, dataURL)
+		return rc, dataURL, err
+	}
 	resp, err := client.Get(dataURL)
 	if err != nil {
 		logger.Tracef("Got error requesting %q: %v", dataURL, err)
@@ -80,17 +84,23 @@ func (h *urlDataSource) Fetch(path string) (io.ReadCloser, string, error) {
 	}
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return nil, dataURL, errors.NotFoundf("cannot find URL %q", dataURL)
-		case http.StatusUnauthorized:
-			return nil, dataURL, errors.Unauthorizedf("unauthorised access to URL %q", dataURL)
-		}
-		return nil, dataURL, fmt.Errorf("cannot access URL %q, %q", dataURL, resp.Status)
+		return nil, dataURL, httpFetchError(dataURL, resp)
 	}
 	return resp.Body, dataURL, nil
 }
 
+// httpFetchError returns the error to report when fetching dataURL
+// results in a response other than 200 OK.
+func httpFetchError(dataURL string, resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return errors.NotFoundf("cannot find URL %q", dataURL)
+	case http.StatusUnauthorized:
+		return errors.Unauthorizedf("unauthorised access to URL %q", dataURL)
+	}
+	return fmt.Errorf("cannot access URL %q, %q", dataURL, resp.Status)
+}
+
 // URL is defined in simplestreams.DataSource.
 func (h *urlDataSource) URL(path string) (string, error) {
 	return utils.MakeFileURL(urlJoin(h.baseURL, path)), nil
@@ -3,6 +3,8 @@
 
 package simplestreams
 
+import "time"
+
 func ExtractCatalogsForProducts(metadata CloudMetadata, productIds []string) []MetadataCatalog {
 	return metadata.extractCatalogsForProducts(productIds)
 }
@@ -22,3 +24,7 @@ func HasProduct(metadata IndexMetadata, prodIds []string) bool {
 func Filter(entries IndexMetadataSlice, match func(*IndexMetadata) bool) IndexMetadataSlice {
 	return entries.filter(match)
 }
+
+func SetMetadataCacheClock(cache *MetadataCache, now func() time.Time) {
+	cache.now = now
+}
@@ -67,3 +67,12 @@ type Storage interface {
 	StorageReader
 	StorageWriter
 }
+
+// A MultipartStorageWriter is implemented by storage that can write a
+// file in several parts, so that a failure while writing a large file
+// only requires the failed part to be written again.
+type MultipartStorageWriter interface {
+	// PutMultipart writes length bytes read from r to the given
+	// storage file, in parts of at most partSize bytes.
+	PutMultipart(name string, r io.ReaderAt, length, partSize int64) error
+}
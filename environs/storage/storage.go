@@ -65,6 +65,20 @@ func ListWithRetry(stor StorageReader, prefix string, attempt utils.AttemptStrat
 	return list, err
 }
 
+// DefaultPartSize is the size of the parts in which PutMultipart
+// writes large files.
+const DefaultPartSize = 64 * 1024 * 1024
+
+// PutMultipart writes length bytes read from r to the named file in
+// stor. Files larger than DefaultPartSize are written in parts if stor
+// is a MultipartStorageWriter; otherwise the file is written with Put.
+func PutMultipart(stor StorageWriter, name string, r io.ReaderAt, length int64) error {
+	if writer, ok := stor.(MultipartStorageWriter); ok && length > DefaultPartSize {
+		return writer.PutMultipart(name, r, length, DefaultPartSize)
+	}
+	return stor.Put(name, io.NewSectionReader(r, 0, length), length)
+}
+
 // BaseToolsPath is the container where tools tarballs and metadata are found.
 var BaseToolsPath = "tools"
 
@@ -185,3 +185,54 @@ func (s *storageSuite) TestListNoRetryAllowed(c *gc.C) {
 	c.Assert(stor.listPrefix, gc.Equals, "foo")
 	c.Assert(stor.invokeCount, gc.Equals, 1)
 }
+
+var _ = gc.Suite(&multipartSuite{})
+
+type multipartSuite struct {
+	stor storage.Storage
+}
+
+func (s *multipartSuite) SetUpTest(c *gc.C) {
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	s.stor = stor
+}
+
+// multipartStorage records the use of the optional multipart writing
+// capability of the storage it wraps.
+type multipartStorage struct {
+	storage.Storage
+	multipart string
+	partSize  int64
+}
+
+func (s *multipartStorage) PutMultipart(name string, r io.ReaderAt, length, partSize int64) error {
+	s.multipart = name
+	s.partSize = partSize
+	return nil
+}
+
+func (s *multipartSuite) assertContents(c *gc.C, name, contents string) {
+	r, err := s.stor.Get(name)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, contents)
+}
+
+func (s *multipartSuite) TestPutMultipartSmallFile(c *gc.C) {
+	stor := &multipartStorage{Storage: s.stor}
+	err := storage.PutMultipart(stor, "foo", bytes.NewReader([]byte("hello world")), 5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stor.multipart, gc.Equals, "")
+	s.assertContents(c, "foo", "hello")
+}
+
+func (s *multipartSuite) TestPutMultipartLargeFile(c *gc.C) {
+	stor := &multipartStorage{Storage: s.stor}
+	err := storage.PutMultipart(stor, "foo", bytes.NewReader(nil), storage.DefaultPartSize+1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stor.multipart, gc.Equals, "foo")
+	c.Assert(stor.partSize, gc.Equals, int64(storage.DefaultPartSize))
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
 	jujuseries "github.com/juju/utils/series"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/simplestreams"
@@ -55,6 +56,14 @@ type SyncContext struct {
 	// Source, if non-empty, specifies a directory in the local file system
 	// to use as a source.
 	Source string
+
+	// Series, if non-empty, limits the tools copied to those for
+	// the listed series.
+	Series []string
+
+	// Arches, if non-empty, limits the tools copied to those for
+	// the listed architectures.
+	Arches []string
 }
 
 // ToolsFinder provides an interface for finding tools of a specified version.
@@ -111,6 +120,13 @@ func SyncTools(syncContext *SyncContext) error {
 	}
 
 	logger.Infof("found %d tools", len(sourceTools))
+	if len(syncContext.Series) > 0 || len(syncContext.Arches) > 0 {
+		sourceTools = filterTools(sourceTools, syncContext.Series, syncContext.Arches)
+		if len(sourceTools) == 0 {
+			return coretools.ErrNoMatches
+		}
+		logger.Infof("found %d tools for series %v and arches %v", len(sourceTools), syncContext.Series, syncContext.Arches)
+	}
 	if !syncContext.AllVersions {
 		var latest version.Number
 		latest, sourceTools = sourceTools.Newest()
@@ -148,6 +164,23 @@ func SyncTools(syncContext *SyncContext) error {
 	return nil
 }
 
+// filterTools returns the tools in the list whose series and arch are
+// among those given. An empty list of series or arches matches any.
+func filterTools(list coretools.List, series, arches []string) coretools.List {
+	seriesSet, archSet := set.NewStrings(series...), set.NewStrings(arches...)
+	var result coretools.List
+	for _, tools := range list {
+		if !seriesSet.IsEmpty() && !seriesSet.Contains(tools.Version.Series) {
+			continue
+		}
+		if !archSet.IsEmpty() && !archSet.Contains(tools.Version.Arch) {
+			continue
+		}
+		result = append(result, tools)
+	}
+	return result
+}
+
 // selectSourceDatasource returns a storage reader based on the source setting.
 func selectSourceDatasource(syncContext *SyncContext) (simplestreams.DataSource, error) {
 	source := syncContext.Source
@@ -400,7 +433,7 @@ type StorageToolsUploader struct {
 
 func (u StorageToolsUploader) UploadTools(toolsDir, stream string, tools *coretools.Tools, data []byte) error {
 	toolsName := envtools.StorageName(tools.Version, toolsDir)
-	if err := u.Storage.Put(toolsName, bytes.NewReader(data), int64(len(data))); err != nil {
+	if err := storage.PutMultipart(u.Storage, toolsName, bytes.NewReader(data), int64(len(data))); err != nil {
 		return err
 	}
 	if !u.WriteMetadata {
@@ -137,6 +137,29 @@ var tests = []struct {
 		},
 		tools: v1all,
 	},
+	{
+		description: "copy newest for the given series",
+		ctx: &sync.SyncContext{
+			Series: []string{"quantal"},
+		},
+		tools: []version.Binary{v180q64},
+	},
+	{
+		description: "copy newest for the given arches",
+		ctx: &sync.SyncContext{
+			Arches: []string{"i386"},
+		},
+		tools: []version.Binary{v180p32},
+	},
+	{
+		description: "copy all for the given series and arches",
+		ctx: &sync.SyncContext{
+			AllVersions: true,
+			Series:      []string{"precise"},
+			Arches:      []string{"amd64"},
+		},
+		tools: []version.Binary{v100p64},
+	},
 }
 
 func (s *syncSuite) TestSyncing(c *gc.C) {
@@ -62,3 +62,6 @@ const DisableRsyslog = "disable-rsyslog"
 
 // VSphereProvider enables the generic vmware provider.
 const VSphereProvider = "vsphere-provider"
+
+// LXDProvider enables the LXD provider.
+const LXDProvider = "lxd-provider"
@@ -49,6 +49,7 @@ func (s *cmdEnvironmentSuite) TestEnvironmentShareCmdStack(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(envUser.UserName(), gc.Equals, user.Canonical())
 	c.Assert(envUser.CreatedBy(), gc.Equals, s.AdminUserTag(c).Canonical())
+	c.Assert(envUser.Access(), gc.Equals, state.EnvReadAccess)
 	lastConn, err := envUser.LastConnection()
 	c.Assert(err, jc.Satisfies, state.IsNeverConnectedError)
 	c.Assert(lastConn.IsZero(), jc.IsTrue)
@@ -86,9 +87,9 @@ func (s *cmdEnvironmentSuite) TestEnvironmentUsersCmd(c *gc.C) {
 	context = s.run(c, "users")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(testing.Stdout(context), gc.Equals, ""+
-		"NAME               DATE CREATED  LAST CONNECTION\n"+
-		"dummy-admin@local  just now      just now\n"+
-		"bar@ubuntuone      just now      never connected\n"+
+		"NAME               ACCESS  DATE CREATED  LAST CONNECTION\n"+
+		"dummy-admin@local  admin   just now      just now\n"+
+		"bar@ubuntuone      read    just now      never connected\n"+
 		"\n")
 
 }
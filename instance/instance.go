@@ -26,7 +26,7 @@ type Instance interface {
 	Id() Id
 
 	// Status returns the provider-specific status for the instance.
-	Status() string
+	Status() InstanceStatus
 
 	// Addresses returns a list of hostnames or ip addresses
 	// associated with the instance.
@@ -46,6 +46,17 @@ type Instance interface {
 	Ports(machineId string) ([]network.PortRange, error)
 }
 
+// InstanceStatus holds the provider-specific status of an instance.
+type InstanceStatus struct {
+	// Status is the state of the instance as reported by the
+	// provider, e.g. "running".
+	Status string
+
+	// Message optionally elaborates upon Status, e.g. to explain
+	// what the provider is waiting for.
+	Message string
+}
+
 // HardwareCharacteristics represents the characteristics of the instance (if known).
 // Attributes that are nil are unknown or not supported.
 type HardwareCharacteristics struct {
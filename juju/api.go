@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/rpc"
 )
 
 var logger = loggo.GetLogger("juju.api")
@@ -38,6 +39,17 @@ type apiStateCachedInfo struct {
 
 var errAborted = fmt.Errorf("aborted")
 
+// correlationId is sent with every API request made on connections
+// opened for a named environment, so that all the requests made by one
+// client process, such as a single juju command, can be correlated.
+var correlationId = rpc.NewCorrelationId()
+
+// CorrelationId returns the correlation id sent with the API requests
+// made by this process.
+func CorrelationId() string {
+	return correlationId
+}
+
 // NewAPIState creates an api.State object from an Environ
 // This is almost certainly the wrong thing to do as it assumes
 // the old admin password (stored as admin-secret in the config).
@@ -283,6 +295,7 @@ func apiInfoConnect(info configstore.EnvironInfo, apiOpen api.OpenFunc, stop <-c
 
 	dialOpts := api.DefaultDialOpts()
 	dialOpts.BakeryClient = bClient
+	dialOpts.CorrelationId = correlationId
 
 	st, err := apiOpen(apiInfo, dialOpts)
 	if err != nil {
@@ -311,7 +324,9 @@ func apiConfigConnect(cfg *config.Config, apiOpen api.OpenFunc, stop <-chan stru
 		return nil, err
 	}
 
-	st, err := apiOpen(apiInfo, api.DefaultDialOpts())
+	dialOpts := api.DefaultDialOpts()
+	dialOpts.CorrelationId = correlationId
+	st, err := apiOpen(apiInfo, dialOpts)
 	// TODO(rog): handle errUnauthorized when the API handles passwords.
 	if err != nil {
 		return nil, err
@@ -279,7 +279,7 @@ func (s *NewAPIClientSuite) TestWithConfigAndNoInfo(c *gc.C) {
 		c.Check(apiInfo.Password, gc.Equals, "adminpass")
 		// EnvironTag wasn't in regular Config
 		c.Check(apiInfo.EnvironTag.Id(), gc.Equals, "")
-		c.Check(opts, gc.DeepEquals, api.DefaultDialOpts())
+		c.Check(opts, gc.DeepEquals, expectedDialOpts())
 		called++
 		return expectState, nil
 	}
@@ -371,11 +371,17 @@ func mockedAPIState(flags mockedStateFlags) *mockAPIState {
 	}
 }
 
+func expectedDialOpts() api.DialOpts {
+	opts := api.DefaultDialOpts()
+	opts.CorrelationId = juju.CorrelationId()
+	return opts
+}
+
 func checkCommonAPIInfoAttrs(c *gc.C, apiInfo *api.Info, opts api.DialOpts) {
 	c.Check(apiInfo.Tag, gc.Equals, names.NewUserTag("foo"))
 	c.Check(string(apiInfo.CACert), gc.Equals, "certificated")
 	c.Check(apiInfo.Password, gc.Equals, "foopass")
-	c.Check(opts, gc.DeepEquals, api.DefaultDialOpts())
+	c.Check(opts, gc.DeepEquals, expectedDialOpts())
 }
 
 func (s *NewAPIClientSuite) TestWithInfoNoEnvironTag(c *gc.C) {
@@ -73,6 +73,34 @@ func (s *DeployLocalSuite) TestDeployMinimal(c *gc.C) {
 	c.Assert(service.GetOwnerTag(), gc.Equals, s.AdminUserTag(c).String())
 }
 
+func (s *DeployLocalSuite) TestDeployEndpointBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	bindings := map[string]string{"juju-info": "internal"}
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			EndpointBindings: bindings,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, bindings)
+}
+
+func (s *DeployLocalSuite) TestDeployEndpointBindingsError(c *gc.C) {
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			EndpointBindings: map[string]string{"juju-info": "internal"},
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "bob": space "internal" not found`)
+
+	// The service is not left behind without its bindings.
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *DeployLocalSuite) TestDeployOwnerTag(c *gc.C) {
 	s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
 	service, err := juju.DeployService(s.State,
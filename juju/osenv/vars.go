@@ -38,6 +38,12 @@ const (
 	// timestamps to be written in RFC3339 format.
 	JujuStatusIsoTimeEnvKey = "JUJU_STATUS_ISO_TIME"
 
+	// JujuMetadataCacheTTLEnvKey is the env var which, if set, holds
+	// how long the client uses cached simplestreams metadata before
+	// checking whether it has changed, as a duration such as "30m".
+	// A duration of 0 disables the cache.
+	JujuMetadataCacheTTLEnvKey = "JUJU_METADATA_CACHE_TTL"
+
 	// JujuCLIVersion is a numeric value (1, 2, 3 etc) representing
 	// the oldest CLI version which should be adhered to.
 	// This includes args and output.
@@ -0,0 +1,185 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+)
+
+// AddressPolicy determines which of a machine's addresses are selected
+// when Juju needs a single public or internal address for it, such as
+// a unit's public-address and private-address, or the addresses agents
+// use to connect to the API server.
+type AddressPolicy string
+
+const (
+	// DefaultAddressPolicy selects public addresses as public addresses
+	// and cloud-local addresses as internal addresses, falling back to
+	// the other kind when none are available.
+	DefaultAddressPolicy AddressPolicy = ""
+
+	// PreferInternalAddressPolicy selects cloud-local addresses for both
+	// public and internal addresses. This is useful when a machine's
+	// public addresses are not reachable by the clients of its units.
+	PreferInternalAddressPolicy AddressPolicy = "prefer-internal"
+
+	// PreferPublicAddressPolicy selects public addresses for both public
+	// and internal addresses. This is useful when a machine's cloud-local
+	// addresses are on networks its peers cannot reach.
+	PreferPublicAddressPolicy AddressPolicy = "prefer-public"
+)
+
+// spaceAddressPolicyPrefix prefixes the space name in the string form
+// of a space-scoped address policy.
+const spaceAddressPolicyPrefix = "space:"
+
+// SpaceAddressPolicy returns an address policy that selects addresses
+// in the named network space, when a machine has any.
+func SpaceAddressPolicy(space string) AddressPolicy {
+	return AddressPolicy(spaceAddressPolicyPrefix + space)
+}
+
+// Space returns the name of the network space whose addresses are
+// selected by the policy, and whether the policy is space-scoped.
+func (p AddressPolicy) Space() (string, bool) {
+	if !strings.HasPrefix(string(p), spaceAddressPolicyPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(p), spaceAddressPolicyPrefix), true
+}
+
+// Validate returns an error if the policy is not valid.
+func (p AddressPolicy) Validate() error {
+	switch p {
+	case DefaultAddressPolicy, PreferInternalAddressPolicy, PreferPublicAddressPolicy:
+		return nil
+	}
+	if space, ok := p.Space(); ok {
+		if !names.IsValidSpace(space) {
+			return errors.NotValidf("space name %q in address policy", space)
+		}
+		return nil
+	}
+	return errors.NotValidf("address policy %q", string(p))
+}
+
+// AddressSelector selects addresses according to an address policy.
+type AddressSelector struct {
+	// Policy is the address policy to apply.
+	Policy AddressPolicy
+
+	// SpaceCIDRs holds the CIDRs of the subnets in the policy's space,
+	// when the policy is space-scoped. Spaces are not known to this
+	// package, so these must be supplied by the caller.
+	SpaceCIDRs []string
+}
+
+// SelectPublicAddress picks one address from a slice that would be
+// appropriate to display as a publicly accessible endpoint, according
+// to the selector's policy. If there are no suitable addresses, ok is
+// false.
+func (s AddressSelector) SelectPublicAddress(addresses []Address) (Address, bool) {
+	indexes := s.bestAddressIndexes(len(addresses), func(i int) Address {
+		return addresses[i]
+	}, true, false)
+	if len(indexes) == 0 {
+		return Address{}, false
+	}
+	return addresses[indexes[0]], true
+}
+
+// SelectInternalAddress picks one address from a slice that can be
+// used as an endpoint for juju internal communication, according to
+// the selector's policy. If there are no suitable addresses, ok is
+// false.
+func (s AddressSelector) SelectInternalAddress(addresses []Address, machineLocal bool) (Address, bool) {
+	indexes := s.bestAddressIndexes(len(addresses), func(i int) Address {
+		return addresses[i]
+	}, false, machineLocal)
+	if len(indexes) == 0 {
+		return Address{}, false
+	}
+	return addresses[indexes[0]], true
+}
+
+// InternalHostPorts returns the best matching HostPorts from a slice
+// for juju internal communication, according to the selector's policy.
+// If there are no suitable HostPorts, an empty slice is returned.
+func (s AddressSelector) InternalHostPorts(hps []HostPort, machineLocal bool) []HostPort {
+	indexes := s.bestAddressIndexes(len(hps), func(i int) Address {
+		return hps[i].Address
+	}, false, machineLocal)
+	out := make([]HostPort, 0, len(indexes))
+	for _, index := range indexes {
+		out = append(out, hps[index])
+	}
+	return out
+}
+
+// bestAddressIndexes returns the indexes of the best addresses for a
+// public or internal endpoint. When the policy is space-scoped, only
+// addresses in the space are considered, unless there are none.
+func (s AddressSelector) bestAddressIndexes(numAddr int, getAddr func(i int) Address, public, machineLocal bool) []int {
+	candidates := s.spaceAddressIndexes(numAddr, getAddr)
+	match := s.matcher(public, machineLocal)
+	indexes := bestAddressIndexes(len(candidates), globalPreferIPv6, func(i int) Address {
+		return getAddr(candidates[i])
+	}, match)
+	for i, index := range indexes {
+		indexes[i] = candidates[index]
+	}
+	return indexes
+}
+
+// spaceAddressIndexes returns the indexes of the addresses in the
+// selector's space, or of all addresses if there are none.
+func (s AddressSelector) spaceAddressIndexes(numAddr int, getAddr func(i int) Address) []int {
+	var nets []*net.IPNet
+	if _, ok := s.Policy.Space(); ok {
+		for _, cidr := range s.SpaceCIDRs {
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+				nets = append(nets, ipNet)
+			}
+		}
+	}
+	var all, inSpace []int
+	for i := 0; i < numAddr; i++ {
+		all = append(all, i)
+		ip := net.ParseIP(getAddr(i).Value)
+		if ip == nil {
+			continue
+		}
+		for _, ipNet := range nets {
+			if ipNet.Contains(ip) {
+				inSpace = append(inSpace, i)
+				break
+			}
+		}
+	}
+	if len(inSpace) > 0 {
+		return inSpace
+	}
+	return all
+}
+
+// matcher returns the scope match function to use for a public or
+// internal endpoint.
+func (s AddressSelector) matcher(public, machineLocal bool) func(Address, bool) scopeMatch {
+	switch s.Policy {
+	case PreferInternalAddressPolicy:
+		public = false
+	case PreferPublicAddressPolicy:
+		// Machine-local addresses are still preferred when asked
+		// for, since they are only used on the machine itself.
+		public = !machineLocal
+	}
+	if public {
+		return publicMatch
+	}
+	return internalAddressMatcher(machineLocal)
+}
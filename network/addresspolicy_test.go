@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type AddressPolicySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&AddressPolicySuite{})
+
+func (s *AddressPolicySuite) TestValidate(c *gc.C) {
+	for _, policy := range []network.AddressPolicy{
+		network.DefaultAddressPolicy,
+		network.PreferInternalAddressPolicy,
+		network.PreferPublicAddressPolicy,
+		network.SpaceAddressPolicy("db"),
+	} {
+		c.Check(policy.Validate(), jc.ErrorIsNil)
+	}
+	err := network.AddressPolicy("prefer-ipv9").Validate()
+	c.Check(err, gc.ErrorMatches, `address policy "prefer-ipv9" not valid`)
+	err = network.SpaceAddressPolicy("$db").Validate()
+	c.Check(err, gc.ErrorMatches, `space name "\$db" in address policy not valid`)
+}
+
+func (s *AddressPolicySuite) TestSpace(c *gc.C) {
+	space, ok := network.SpaceAddressPolicy("db").Space()
+	c.Check(ok, jc.IsTrue)
+	c.Check(space, gc.Equals, "db")
+	_, ok = network.PreferPublicAddressPolicy.Space()
+	c.Check(ok, jc.IsFalse)
+}
+
+var policyAddresses = []network.Address{
+	network.NewScopedAddress("127.0.0.1", network.ScopeMachineLocal),
+	network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+	network.NewScopedAddress("192.168.1.1", network.ScopeCloudLocal),
+	network.NewScopedAddress("8.8.8.8", network.ScopePublic),
+}
+
+var addressSelectorTests = []struct {
+	about    string
+	selector network.AddressSelector
+	public   string
+	internal string
+}{{
+	about:    "default policy",
+	public:   "8.8.8.8",
+	internal: "10.0.0.1",
+}, {
+	about:    "prefer internal",
+	selector: network.AddressSelector{Policy: network.PreferInternalAddressPolicy},
+	public:   "10.0.0.1",
+	internal: "10.0.0.1",
+}, {
+	about:    "prefer public",
+	selector: network.AddressSelector{Policy: network.PreferPublicAddressPolicy},
+	public:   "8.8.8.8",
+	internal: "8.8.8.8",
+}, {
+	about: "space",
+	selector: network.AddressSelector{
+		Policy:     network.SpaceAddressPolicy("db"),
+		SpaceCIDRs: []string{"192.168.1.0/24"},
+	},
+	public:   "192.168.1.1",
+	internal: "192.168.1.1",
+}, {
+	about: "space with no addresses falls back to default",
+	selector: network.AddressSelector{
+		Policy:     network.SpaceAddressPolicy("db"),
+		SpaceCIDRs: []string{"172.16.0.0/16"},
+	},
+	public:   "8.8.8.8",
+	internal: "10.0.0.1",
+}}
+
+func (s *AddressPolicySuite) TestSelectAddresses(c *gc.C) {
+	for i, t := range addressSelectorTests {
+		c.Logf("test %d: %s", i, t.about)
+		addr, ok := t.selector.SelectPublicAddress(policyAddresses)
+		c.Check(ok, jc.IsTrue)
+		c.Check(addr.Value, gc.Equals, t.public)
+		addr, ok = t.selector.SelectInternalAddress(policyAddresses, false)
+		c.Check(ok, jc.IsTrue)
+		c.Check(addr.Value, gc.Equals, t.internal)
+	}
+}
+
+func (s *AddressPolicySuite) TestSelectInternalAddressMachineLocal(c *gc.C) {
+	selector := network.AddressSelector{Policy: network.PreferPublicAddressPolicy}
+	addr, ok := selector.SelectInternalAddress(policyAddresses, true)
+	c.Check(ok, jc.IsTrue)
+	c.Check(addr.Value, gc.Equals, "127.0.0.1")
+}
+
+func (s *AddressPolicySuite) TestSelectNoAddresses(c *gc.C) {
+	_, ok := network.AddressSelector{}.SelectPublicAddress(nil)
+	c.Check(ok, jc.IsFalse)
+	_, ok = network.AddressSelector{}.SelectInternalAddress(nil, false)
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *AddressPolicySuite) TestInternalHostPorts(c *gc.C) {
+	hps := network.AddressesWithPort(policyAddresses, 17070)
+	selector := network.AddressSelector{Policy: network.PreferPublicAddressPolicy}
+	c.Check(selector.InternalHostPorts(hps, false), jc.DeepEquals, []network.HostPort{hps[3]})
+	c.Check(network.AddressSelector{}.InternalHostPorts(hps, false), jc.DeepEquals, hps[1:3])
+}
@@ -0,0 +1,50 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+)
+
+// WellKnownServiceType identifies a service that Juju itself makes
+// available on machines, access to which can be restricted to a
+// whitelist of source addresses.
+type WellKnownServiceType string
+
+const (
+	// SSHRule identifies the SSH service on every machine.
+	SSHRule WellKnownServiceType = "ssh"
+
+	// JujuControllerRule identifies the API server on state servers.
+	JujuControllerRule WellKnownServiceType = "juju-controller"
+)
+
+// WellKnownServices holds all the well-known service types.
+var WellKnownServices = []WellKnownServiceType{
+	SSHRule,
+	JujuControllerRule,
+}
+
+// Validate returns an error if the service type is not well known.
+func (s WellKnownServiceType) Validate() error {
+	for _, known := range WellKnownServices {
+		if s == known {
+			return nil
+		}
+	}
+	return errors.NotValidf("well known service type %q", string(s))
+}
+
+// ValidateWhitelistCIDRs returns an error if any of the given source
+// address ranges is not a valid CIDR.
+func ValidateWhitelistCIDRs(cidrs []string) error {
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("CIDR %q", cidr)
+		}
+	}
+	return nil
+}
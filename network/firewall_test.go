@@ -0,0 +1,36 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type FirewallSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&FirewallSuite{})
+
+func (*FirewallSuite) TestWellKnownServiceTypeValidate(c *gc.C) {
+	for _, service := range network.WellKnownServices {
+		c.Check(service.Validate(), jc.ErrorIsNil)
+	}
+	err := network.WellKnownServiceType("http").Validate()
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `well known service type "http" not valid`)
+}
+
+func (*FirewallSuite) TestValidateWhitelistCIDRs(c *gc.C) {
+	err := network.ValidateWhitelistCIDRs([]string{"10.0.0.0/8", "2001:db8::/32"})
+	c.Check(err, jc.ErrorIsNil)
+	err = network.ValidateWhitelistCIDRs([]string{"10.0.0.0/8", "10.0.0.1"})
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `CIDR "10.0.0.1" not valid`)
+}
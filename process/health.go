@@ -0,0 +1,135 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package process
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// The types of health check that may be defined for a process.
+const (
+	HealthCheckHTTP = "http"
+	HealthCheckTCP  = "tcp"
+	HealthCheckExec = "exec"
+)
+
+// UnhealthyStatus is the status recorded for a process whose health
+// check fails while its plugin still reports it as running.
+const UnhealthyStatus = "unhealthy"
+
+// healthCheckTimeout bounds the time taken by a single health check.
+const healthCheckTimeout = 10 * time.Second
+
+// HealthCheck describes how to determine whether a running process
+// is healthy.
+type HealthCheck struct {
+	// Type is the type of the check: "http", "tcp" or "exec".
+	Type string `json:"type" yaml:"type"`
+
+	// Target is what is checked. For http checks it is a URL that
+	// must respond with a 2xx or 3xx status; for tcp checks it is a
+	// host:port that must accept connections; for exec checks it is
+	// a command that must exit with status zero.
+	Target string `json:"target" yaml:"target"`
+}
+
+// ParseHealthCheck parses a health check of the form "<type>:<target>",
+// e.g. "tcp:localhost:6379".
+func ParseHealthCheck(s string) (HealthCheck, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return HealthCheck{}, errors.NotValidf("health check %q", s)
+	}
+	check := HealthCheck{Type: parts[0], Target: parts[1]}
+	if err := check.Validate(); err != nil {
+		return HealthCheck{}, errors.Trace(err)
+	}
+	return check, nil
+}
+
+// Validate returns an error if the health check is not valid.
+func (check HealthCheck) Validate() error {
+	if check.Target == "" {
+		return errors.NotValidf("%s health check with empty target", check.Type)
+	}
+	switch check.Type {
+	case HealthCheckHTTP:
+		u, err := url.Parse(check.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.NotValidf("http health check URL %q", check.Target)
+		}
+	case HealthCheckTCP:
+		if _, _, err := net.SplitHostPort(check.Target); err != nil {
+			return errors.NotValidf("tcp health check address %q", check.Target)
+		}
+	case HealthCheckExec:
+	default:
+		return errors.NotValidf("health check type %q", check.Type)
+	}
+	return nil
+}
+
+var runCommand = utils.RunCommand
+
+// Run runs the health check, returning an error if it fails.
+func (check HealthCheck) Run() error {
+	switch check.Type {
+	case HealthCheckHTTP:
+		client := &http.Client{Timeout: healthCheckTimeout}
+		resp, err := client.Get(check.Target)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return errors.Errorf("%s returned %q", check.Target, resp.Status)
+		}
+	case HealthCheckTCP:
+		conn, err := net.DialTimeout("tcp", check.Target, healthCheckTimeout)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		conn.Close()
+	case HealthCheckExec:
+		out, err := runCommand("/bin/sh", "-c", check.Target)
+		if err != nil {
+			if out = strings.TrimSpace(out); out != "" {
+				return errors.Errorf("%v: %s", err, out)
+			}
+			return errors.Trace(err)
+		}
+	default:
+		return errors.NotValidf("health check type %q", check.Type)
+	}
+	return nil
+}
+
+// RestartPolicy determines what is done when a process fails.
+type RestartPolicy string
+
+const (
+	// RestartNever leaves failed processes for the charm to deal with.
+	RestartNever RestartPolicy = "never"
+
+	// RestartOnFailure relaunches processes that have failed, or
+	// whose health check fails.
+	RestartOnFailure RestartPolicy = "on-failure"
+)
+
+// Validate returns an error if the restart policy is not valid. An
+// empty policy is treated as RestartNever.
+func (policy RestartPolicy) Validate() error {
+	switch policy {
+	case "", RestartNever, RestartOnFailure:
+		return nil
+	}
+	return errors.NotValidf("restart policy %q", policy)
+}
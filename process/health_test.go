@@ -0,0 +1,112 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package process_test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+)
+
+type healthSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&healthSuite{})
+
+func (s *healthSuite) TestParseHealthCheck(c *gc.C) {
+	check, err := process.ParseHealthCheck("tcp:localhost:6379")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(check, jc.DeepEquals, process.HealthCheck{Type: "tcp", Target: "localhost:6379"})
+
+	check, err = process.ParseHealthCheck("http:http://localhost:8080/health")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(check, jc.DeepEquals, process.HealthCheck{Type: "http", Target: "http://localhost:8080/health"})
+
+	check, err = process.ParseHealthCheck("exec:pgrep -f redis")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(check, jc.DeepEquals, process.HealthCheck{Type: "exec", Target: "pgrep -f redis"})
+}
+
+func (s *healthSuite) TestParseHealthCheckErrors(c *gc.C) {
+	for i, test := range []struct {
+		check string
+		err   string
+	}{{
+		check: "tcp",
+		err:   `health check "tcp" not valid`,
+	}, {
+		check: "exec:",
+		err:   `exec health check with empty target not valid`,
+	}, {
+		check: "udp:localhost:53",
+		err:   `health check type "udp" not valid`,
+	}, {
+		check: "tcp:localhost",
+		err:   `tcp health check address "localhost" not valid`,
+	}, {
+		check: "http:localhost:8080",
+		err:   `http health check URL "localhost:8080" not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.check)
+		_, err := process.ParseHealthCheck(test.check)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *healthSuite) TestRunHTTP(c *gc.C) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	check := process.HealthCheck{Type: "http", Target: server.URL}
+	c.Assert(check.Run(), jc.ErrorIsNil)
+
+	healthy = false
+	c.Assert(check.Run(), gc.ErrorMatches, `.* returned "503 Service Unavailable"`)
+}
+
+func (s *healthSuite) TestRunTCP(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	addr := listener.Addr().String()
+
+	check := process.HealthCheck{Type: "tcp", Target: addr}
+	c.Assert(check.Run(), jc.ErrorIsNil)
+
+	listener.Close()
+	c.Assert(check.Run(), gc.NotNil)
+}
+
+func (s *healthSuite) TestRunExec(c *gc.C) {
+	var stub testing.Stub
+	s.PatchValue(process.RunCommand, func(command string, args ...string) (string, error) {
+		stub.AddCall(command, args)
+		return "redis not running\n", stub.NextErr()
+	})
+	check := process.HealthCheck{Type: "exec", Target: "pgrep -f redis"}
+	c.Assert(check.Run(), jc.ErrorIsNil)
+	stub.CheckCall(c, 0, "/bin/sh", []string{"-c", "pgrep -f redis"})
+
+	stub.SetErrors(errors.New("exit status 1"))
+	c.Assert(check.Run(), gc.ErrorMatches, "exit status 1: redis not running")
+}
+
+func (s *healthSuite) TestRestartPolicyValidate(c *gc.C) {
+	for _, policy := range []process.RestartPolicy{"", "never", "on-failure"} {
+		c.Check(policy.Validate(), jc.ErrorIsNil)
+	}
+	c.Check(process.RestartPolicy("always").Validate(), gc.ErrorMatches, `restart policy "always" not valid`)
+}
@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package process
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// Definition describes a workload process to be launched by a plugin.
+type Definition struct {
+	// Name is the name of the process, unique within the unit.
+	Name string `json:"name" yaml:"name"`
+
+	// Type is the type of the process, and identifies the plugin
+	// that launches it.
+	Type string `json:"type" yaml:"type"`
+
+	// Image is the image from which the process is launched, for
+	// plugins that launch processes in containers.
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+
+	// Command is the command run to start the process. For container
+	// plugins it overrides the default command of the image.
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+
+	// HealthCheck, if set, is run periodically by the unit agent to
+	// check that the process is healthy.
+	HealthCheck *HealthCheck `json:"health-check,omitempty" yaml:"health-check,omitempty"`
+
+	// Restart determines whether the unit agent relaunches the
+	// process if it fails.
+	Restart RestartPolicy `json:"restart,omitempty" yaml:"restart,omitempty"`
+}
+
+// Validate returns an error if the process definition is not valid.
+// The plugin-specific fields are validated when the process is
+// launched.
+func (def Definition) Validate() error {
+	if !validName.MatchString(def.Name) {
+		return errors.NotValidf("process name %q", def.Name)
+	}
+	if def.Type == "" {
+		return errors.NotValidf("process %q with empty type", def.Name)
+	}
+	if def.HealthCheck != nil {
+		if err := def.HealthCheck.Validate(); err != nil {
+			return errors.Annotatef(err, "process %q", def.Name)
+		}
+	}
+	if err := def.Restart.Validate(); err != nil {
+		return errors.Annotatef(err, "process %q", def.Name)
+	}
+	return nil
+}
+
+// Plugin launches, tracks and destroys workload processes of a
+// particular type on behalf of the unit agent.
+type Plugin interface {
+	// Launch starts the defined process and returns its details. The
+	// details must conform to the schema registered for the type.
+	Launch(def Definition) (Details, error)
+
+	// Status returns the plugin-specific status of the process with
+	// the given ID.
+	Status(id string) (string, error)
+
+	// Destroy stops the process with the given ID and releases any
+	// resources held by it.
+	Destroy(id string) error
+}
+
+// UsagePlugin is implemented by plugins that can report the resource
+// usage of the processes they launch.
+type UsagePlugin interface {
+	Plugin
+
+	// Usage returns the current resource usage of the process with
+	// the given ID.
+	Usage(id string) (Usage, error)
+}
+
+var (
+	launchPluginsMu sync.Mutex
+	launchPlugins   = make(map[string]Plugin)
+)
+
+// RegisterLaunchPlugin registers the plugin used to launch processes
+// of the given type. A details schema must already be registered for
+// the type. It will panic if a plugin is already registered for the
+// type.
+func RegisterLaunchPlugin(processType string, plugin Plugin) {
+	if _, err := PluginSchema(processType); err != nil {
+		panic(err)
+	}
+	launchPluginsMu.Lock()
+	defer launchPluginsMu.Unlock()
+	if _, ok := launchPlugins[processType]; ok {
+		panic(fmt.Errorf("process launch plugin %q already registered", processType))
+	}
+	launchPlugins[processType] = plugin
+}
+
+// LaunchPlugin returns the plugin used to launch processes of the
+// given type.
+func LaunchPlugin(processType string) (Plugin, error) {
+	launchPluginsMu.Lock()
+	defer launchPluginsMu.Unlock()
+	plugin, ok := launchPlugins[processType]
+	if !ok {
+		return nil, errors.NotFoundf("process launch plugin %q", processType)
+	}
+	return plugin, nil
+}
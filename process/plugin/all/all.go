@@ -0,0 +1,11 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package all
+
+// Register all the available process launch plugins.
+import (
+	_ "github.com/juju/juju/process/plugin/docker"
+	_ "github.com/juju/juju/process/plugin/rkt"
+	_ "github.com/juju/juju/process/plugin/systemd"
+)
@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package docker implements the process plugin that launches workload
+// processes as docker containers.
+package docker
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/process"
+)
+
+// Type is the type of the processes launched by the plugin.
+const Type = "docker"
+
+var runCommand = utils.RunCommand
+
+func init() {
+	process.RegisterLaunchPlugin(Type, Plugin{})
+}
+
+// Plugin launches workload processes as detached docker containers.
+// Containers are identified by the ID assigned to them by docker.
+type Plugin struct{}
+
+// Launch implements process.Plugin.
+func (Plugin) Launch(def process.Definition) (process.Details, error) {
+	if def.Image == "" {
+		return process.Details{}, errors.NotValidf("docker process %q without image", def.Name)
+	}
+	args := []string{"run", "--detach", def.Image}
+	args = append(args, strings.Fields(def.Command)...)
+	id, err := docker(args...)
+	if err != nil {
+		return process.Details{}, errors.Annotatef(err, "cannot launch process %q", def.Name)
+	}
+	status, err := Plugin{}.Status(id)
+	if err != nil {
+		// The container is running, but cannot be tracked without
+		// its status; remove it rather than leak it.
+		if destroyErr := (Plugin{}).Destroy(id); destroyErr != nil {
+			return process.Details{}, errors.Errorf("cannot launch process %q: %v (%v)", def.Name, err, destroyErr)
+		}
+		return process.Details{}, errors.Annotatef(err, "cannot launch process %q", def.Name)
+	}
+	extra := map[string]interface{}{"image": def.Image}
+	if def.Command != "" {
+		extra["command"] = def.Command
+	}
+	return process.Details{ID: id, Status: status, Extra: extra}, nil
+}
+
+// Status implements process.Plugin.
+func (Plugin) Status(id string) (string, error) {
+	status, err := docker("inspect", "--format", "{{.State.Status}}", id)
+	return status, errors.Annotatef(err, "cannot get status of container %q", id)
+}
+
+// Destroy implements process.Plugin.
+func (Plugin) Destroy(id string) error {
+	_, err := docker("rm", "--force", id)
+	return errors.Annotatef(err, "cannot remove container %q", id)
+}
+
+// Usage implements process.UsagePlugin.
+func (Plugin) Usage(id string) (process.Usage, error) {
+	out, err := docker("inspect", "--format", "{{.RestartCount}}", id)
+	if err != nil {
+		return process.Usage{}, errors.Annotatef(err, "cannot get restart count of container %q", id)
+	}
+	restarts, err := strconv.Atoi(out)
+	if err != nil {
+		return process.Usage{}, errors.Annotatef(err, "cannot parse restart count of container %q", id)
+	}
+	out, err = docker("stats", "--no-stream", "--format", "{{.CPUPerc}} {{.MemUsage}}", id)
+	if err != nil {
+		return process.Usage{}, errors.Annotatef(err, "cannot get resource usage of container %q", id)
+	}
+	// The output looks like "0.07% 1.52MiB / 1.952GiB"; the memory
+	// limit is ignored.
+	fields := strings.Fields(out)
+	if len(fields) < 2 {
+		return process.Usage{}, errors.Errorf("unexpected resource usage %q for container %q", out, id)
+	}
+	cpu, err := strconv.ParseFloat(strings.TrimSuffix(fields[0], "%"), 64)
+	if err != nil {
+		return process.Usage{}, errors.Annotatef(err, "cannot parse cpu usage of container %q", id)
+	}
+	memory, err := humanize.ParseBytes(fields[1])
+	if err != nil {
+		return process.Usage{}, errors.Annotatef(err, "cannot parse memory usage of container %q", id)
+	}
+	return process.Usage{CPU: cpu, Memory: memory, Restarts: restarts}, nil
+}
+
+// docker runs the docker command with the given arguments, returning
+// its trimmed output.
+func docker(args ...string) (string, error) {
+	out, err := runCommand("docker", args...)
+	out = strings.TrimSpace(out)
+	if err != nil {
+		if out != "" {
+			return "", errors.Errorf("%v: %s", err, out)
+		}
+		return "", errors.Trace(err)
+	}
+	return out, nil
+}
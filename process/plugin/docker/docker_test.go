@@ -0,0 +1,157 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docker_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+	"github.com/juju/juju/process/plugin/docker"
+)
+
+type dockerSuite struct {
+	testing.IsolationSuite
+	stub    testing.Stub
+	outputs []string
+}
+
+var _ = gc.Suite(&dockerSuite{})
+
+func (s *dockerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	s.outputs = nil
+	s.PatchValue(docker.RunCommand, func(command string, args ...string) (string, error) {
+		s.stub.AddCall(command, args)
+		var out string
+		if len(s.outputs) > 0 {
+			out, s.outputs = s.outputs[0], s.outputs[1:]
+		}
+		return out, s.stub.NextErr()
+	})
+}
+
+func (s *dockerSuite) TestRegistered(c *gc.C) {
+	plugin, err := process.LaunchPlugin("docker")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plugin, gc.Equals, docker.Plugin{})
+	c.Assert(plugin, gc.Implements, new(process.UsagePlugin))
+}
+
+func (s *dockerSuite) TestLaunch(c *gc.C) {
+	s.outputs = []string{"abc123\n", "running\n"}
+	details, err := docker.Plugin{}.Launch(process.Definition{
+		Name:    "web",
+		Type:    "docker",
+		Image:   "nginx",
+		Command: "nginx -g daemon",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details, jc.DeepEquals, process.Details{
+		ID:     "abc123",
+		Status: "running",
+		Extra: map[string]interface{}{
+			"image":   "nginx",
+			"command": "nginx -g daemon",
+		},
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		"docker", []interface{}{[]string{"run", "--detach", "nginx", "nginx", "-g", "daemon"}},
+	}, {
+		"docker", []interface{}{[]string{"inspect", "--format", "{{.State.Status}}", "abc123"}},
+	}})
+
+	err = process.Info{Name: "web", Type: "docker", Details: details}.Validate()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *dockerSuite) TestLaunchNoImage(c *gc.C) {
+	_, err := docker.Plugin{}.Launch(process.Definition{Name: "web", Type: "docker"})
+	c.Assert(err, gc.ErrorMatches, `docker process "web" without image not valid`)
+	s.stub.CheckCalls(c, nil)
+}
+
+func (s *dockerSuite) TestLaunchError(c *gc.C) {
+	s.outputs = []string{"Unable to find image 'nginx:latest' locally\n"}
+	s.stub.SetErrors(errors.New("exit status 1"))
+	_, err := docker.Plugin{}.Launch(process.Definition{Name: "web", Type: "docker", Image: "nginx"})
+	c.Assert(err, gc.ErrorMatches, `cannot launch process "web": exit status 1: Unable to find image 'nginx:latest' locally`)
+}
+
+func (s *dockerSuite) TestLaunchStatusErrorRemovesContainer(c *gc.C) {
+	s.outputs = []string{"abc123\n"}
+	s.stub.SetErrors(nil, errors.New("exit status 1"))
+	_, err := docker.Plugin{}.Launch(process.Definition{Name: "web", Type: "docker", Image: "nginx"})
+	c.Assert(err, gc.ErrorMatches, `cannot launch process "web": cannot get status of container "abc123": exit status 1`)
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		"docker", []interface{}{[]string{"run", "--detach", "nginx"}},
+	}, {
+		"docker", []interface{}{[]string{"inspect", "--format", "{{.State.Status}}", "abc123"}},
+	}, {
+		"docker", []interface{}{[]string{"rm", "--force", "abc123"}},
+	}})
+}
+
+func (s *dockerSuite) TestStatus(c *gc.C) {
+	s.outputs = []string{"exited\n"}
+	status, err := docker.Plugin{}.Status("abc123")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, "exited")
+}
+
+func (s *dockerSuite) TestUsage(c *gc.C) {
+	s.outputs = []string{"3\n", "12.50% 1.5MiB / 1.952GiB\n"}
+	usage, err := docker.Plugin{}.Usage("abc123")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, process.Usage{
+		CPU:      12.5,
+		Memory:   1572864,
+		Restarts: 3,
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		"docker", []interface{}{[]string{"inspect", "--format", "{{.RestartCount}}", "abc123"}},
+	}, {
+		"docker", []interface{}{[]string{"stats", "--no-stream", "--format", "{{.CPUPerc}} {{.MemUsage}}", "abc123"}},
+	}})
+}
+
+func (s *dockerSuite) TestUsageErrors(c *gc.C) {
+	for i, test := range []struct {
+		outputs []string
+		err     string
+	}{{
+		outputs: []string{"many"},
+		err:     `cannot parse restart count of container "abc123": .*`,
+	}, {
+		outputs: []string{"0", "--"},
+		err:     `unexpected resource usage "--" for container "abc123"`,
+	}, {
+		outputs: []string{"0", "lots 1.5MiB / 2GiB"},
+		err:     `cannot parse cpu usage of container "abc123": .*`,
+	}, {
+		outputs: []string{"0", "1.00% heaps / 2GiB"},
+		err:     `cannot parse memory usage of container "abc123": .*`,
+	}} {
+		c.Logf("test %d: %v", i, test.outputs)
+		s.outputs = test.outputs
+		_, err := docker.Plugin{}.Usage("abc123")
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *dockerSuite) TestDestroy(c *gc.C) {
+	err := docker.Plugin{}.Destroy("abc123")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCall(c, 0, "docker", []string{"rm", "--force", "abc123"})
+}
+
+func (s *dockerSuite) TestDestroyError(c *gc.C) {
+	s.stub.SetErrors(errors.New("exit status 1"))
+	err := docker.Plugin{}.Destroy("abc123")
+	c.Assert(err, gc.ErrorMatches, `cannot remove container "abc123": exit status 1`)
+}
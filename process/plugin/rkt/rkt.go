@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package rkt implements the process plugin that launches workload
+// processes as rkt pods.
+package rkt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/process"
+)
+
+// Type is the type of the processes launched by the plugin.
+const Type = "rkt"
+
+var runCommand = utils.RunCommand
+
+func init() {
+	process.RegisterLaunchPlugin(Type, Plugin{})
+}
+
+// Plugin launches workload processes as rkt pods. rkt has no daemon of
+// its own, so each pod is run under a transient systemd service, and
+// identified by the name of the service unit.
+type Plugin struct{}
+
+// Launch implements process.Plugin.
+func (Plugin) Launch(def process.Definition) (process.Details, error) {
+	if def.Image == "" {
+		return process.Details{}, errors.NotValidf("rkt process %q without image", def.Name)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return process.Details{}, errors.Trace(err)
+	}
+	// Several units on a machine may launch processes with the same
+	// name, so the service name is made unique.
+	unit := fmt.Sprintf("juju-%s-%s.service", def.Name, uuid.String()[:8])
+	args := []string{"--unit", unit, "rkt", "run", def.Image}
+	if fields := strings.Fields(def.Command); len(fields) > 0 {
+		args = append(args, "--exec", fields[0])
+		if len(fields) > 1 {
+			args = append(args, "--")
+			args = append(args, fields[1:]...)
+		}
+	}
+	if _, err := run("systemd-run", args...); err != nil {
+		return process.Details{}, errors.Annotatef(err, "cannot launch process %q", def.Name)
+	}
+	status, err := Plugin{}.Status(unit)
+	if err != nil {
+		// The pod is running, but cannot be tracked without its
+		// status; stop it rather than leak it.
+		if destroyErr := (Plugin{}).Destroy(unit); destroyErr != nil {
+			return process.Details{}, errors.Errorf("cannot launch process %q: %v (%v)", def.Name, err, destroyErr)
+		}
+		return process.Details{}, errors.Annotatef(err, "cannot launch process %q", def.Name)
+	}
+	extra := map[string]interface{}{
+		"unit":  unit,
+		"image": def.Image,
+	}
+	if def.Command != "" {
+		extra["command"] = def.Command
+	}
+	return process.Details{ID: unit, Status: status, Extra: extra}, nil
+}
+
+// Status implements process.Plugin. It reports the state of the
+// service running the pod.
+func (Plugin) Status(id string) (string, error) {
+	// is-active exits non-zero for services that are not active, but
+	// still reports their state.
+	out, err := runCommand("systemctl", "is-active", id)
+	if status := strings.TrimSpace(out); status != "" {
+		return status, nil
+	}
+	return "", errors.Annotatef(err, "cannot get status of pod service %q", id)
+}
+
+// Destroy implements process.Plugin. Stopping the service stops the
+// pod; the exited pod is left for rkt's garbage collection.
+func (Plugin) Destroy(id string) error {
+	_, err := run("systemctl", "stop", id)
+	return errors.Annotatef(err, "cannot stop pod service %q", id)
+}
+
+// run runs the given command, returning its trimmed output.
+func run(command string, args ...string) (string, error) {
+	out, err := runCommand(command, args...)
+	out = strings.TrimSpace(out)
+	if err != nil {
+		if out != "" {
+			return "", errors.Errorf("%v: %s", err, out)
+		}
+		return "", errors.Trace(err)
+	}
+	return out, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rkt_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+	"github.com/juju/juju/process/plugin/rkt"
+)
+
+type rktSuite struct {
+	testing.IsolationSuite
+	stub    testing.Stub
+	outputs []string
+}
+
+var _ = gc.Suite(&rktSuite{})
+
+func (s *rktSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	s.outputs = nil
+	s.PatchValue(rkt.RunCommand, func(command string, args ...string) (string, error) {
+		s.stub.AddCall(command, args)
+		var out string
+		if len(s.outputs) > 0 {
+			out, s.outputs = s.outputs[0], s.outputs[1:]
+		}
+		return out, s.stub.NextErr()
+	})
+}
+
+func (s *rktSuite) TestRegistered(c *gc.C) {
+	plugin, err := process.LaunchPlugin("rkt")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plugin, gc.Equals, rkt.Plugin{})
+}
+
+func (s *rktSuite) TestLaunch(c *gc.C) {
+	s.outputs = []string{"Running as unit juju-web.service.\n", "active\n"}
+	details, err := rkt.Plugin{}.Launch(process.Definition{
+		Name:    "web",
+		Type:    "rkt",
+		Image:   "coreos.com/etcd:v2.0.0",
+		Command: "/etcd --debug",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details.ID, gc.Matches, `juju-web-[0-9a-f]{8}\.service`)
+	c.Assert(details, jc.DeepEquals, process.Details{
+		ID:     details.ID,
+		Status: "active",
+		Extra: map[string]interface{}{
+			"unit":    details.ID,
+			"image":   "coreos.com/etcd:v2.0.0",
+			"command": "/etcd --debug",
+		},
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		"systemd-run", []interface{}{[]string{
+			"--unit", details.ID, "rkt", "run", "coreos.com/etcd:v2.0.0", "--exec", "/etcd", "--", "--debug",
+		}},
+	}, {
+		"systemctl", []interface{}{[]string{"is-active", details.ID}},
+	}})
+
+	err = process.Info{Name: "web", Type: "rkt", Details: details}.Validate()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *rktSuite) TestLaunchDefaultCommand(c *gc.C) {
+	s.outputs = []string{"", "active\n"}
+	details, err := rkt.Plugin{}.Launch(process.Definition{
+		Name:  "web",
+		Type:  "rkt",
+		Image: "coreos.com/etcd:v2.0.0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCall(c, 0, "systemd-run", []string{
+		"--unit", details.ID, "rkt", "run", "coreos.com/etcd:v2.0.0",
+	})
+}
+
+func (s *rktSuite) TestLaunchNoImage(c *gc.C) {
+	_, err := rkt.Plugin{}.Launch(process.Definition{Name: "web", Type: "rkt"})
+	c.Assert(err, gc.ErrorMatches, `rkt process "web" without image not valid`)
+	s.stub.CheckCalls(c, nil)
+}
+
+func (s *rktSuite) TestLaunchStatusErrorStopsPod(c *gc.C) {
+	s.stub.SetErrors(nil, errors.New("exit status 1"))
+	_, err := rkt.Plugin{}.Launch(process.Definition{Name: "web", Type: "rkt", Image: "coreos.com/etcd:v2.0.0"})
+	c.Assert(err, gc.ErrorMatches, `cannot launch process "web": cannot get status of pod service "juju-web-[0-9a-f]{8}\.service": exit status 1`)
+	s.stub.CheckCallNames(c, "systemd-run", "systemctl", "systemctl")
+	unit := s.stub.Calls()[0].Args[0].([]string)[1]
+	s.stub.CheckCall(c, 2, "systemctl", []string{"stop", unit})
+}
+
+func (s *rktSuite) TestStatusInactive(c *gc.C) {
+	s.outputs = []string{"failed\n"}
+	s.stub.SetErrors(errors.New("exit status 3"))
+	status, err := rkt.Plugin{}.Status("juju-web.service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, "failed")
+}
+
+func (s *rktSuite) TestDestroy(c *gc.C) {
+	err := rkt.Plugin{}.Destroy("juju-web.service")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCall(c, 0, "systemctl", []string{"stop", "juju-web.service"})
+}
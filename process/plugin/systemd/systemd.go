@@ -0,0 +1,126 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package systemd implements the process plugin that launches workload
+// processes as transient systemd services.
+package systemd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/process"
+)
+
+// Type is the type of the processes launched by the plugin.
+const Type = "systemd"
+
+var runCommand = utils.RunCommand
+
+func init() {
+	process.RegisterLaunchPlugin(Type, Plugin{})
+}
+
+// Plugin launches workload processes as transient systemd services,
+// identified by the name of the service unit.
+type Plugin struct{}
+
+// Launch implements process.Plugin.
+func (Plugin) Launch(def process.Definition) (process.Details, error) {
+	if def.Command == "" {
+		return process.Details{}, errors.NotValidf("systemd process %q without command", def.Name)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return process.Details{}, errors.Trace(err)
+	}
+	// Several units on a machine may launch processes with the same
+	// name, so the service name is made unique.
+	unit := fmt.Sprintf("juju-%s-%s.service", def.Name, uuid.String()[:8])
+	if _, err := systemctl("systemd-run", "--unit", unit, "/bin/sh", "-c", def.Command); err != nil {
+		return process.Details{}, errors.Annotatef(err, "cannot launch process %q", def.Name)
+	}
+	status, err := Plugin{}.Status(unit)
+	if err != nil {
+		// The service is running, but cannot be tracked without its
+		// status; stop it rather than leak it.
+		if destroyErr := (Plugin{}).Destroy(unit); destroyErr != nil {
+			return process.Details{}, errors.Errorf("cannot launch process %q: %v (%v)", def.Name, err, destroyErr)
+		}
+		return process.Details{}, errors.Annotatef(err, "cannot launch process %q", def.Name)
+	}
+	return process.Details{
+		ID:     unit,
+		Status: status,
+		Extra: map[string]interface{}{
+			"unit":    unit,
+			"command": def.Command,
+		},
+	}, nil
+}
+
+// Status implements process.Plugin.
+func (Plugin) Status(id string) (string, error) {
+	// is-active exits non-zero for services that are not active, but
+	// still reports their state.
+	out, err := runCommand("systemctl", "is-active", id)
+	if status := strings.TrimSpace(out); status != "" {
+		return status, nil
+	}
+	return "", errors.Annotatef(err, "cannot get status of service %q", id)
+}
+
+// Destroy implements process.Plugin.
+func (Plugin) Destroy(id string) error {
+	_, err := systemctl("systemctl", "stop", id)
+	return errors.Annotatef(err, "cannot stop service %q", id)
+}
+
+// Usage implements process.UsagePlugin. systemd only reports the
+// cumulative CPU time of a service, so CPU usage is not reported.
+func (Plugin) Usage(id string) (process.Usage, error) {
+	out, err := systemctl("systemctl", "show", "--property=MemoryCurrent", "--property=NRestarts", id)
+	if err != nil {
+		return process.Usage{}, errors.Annotatef(err, "cannot get resource usage of service %q", id)
+	}
+	var usage process.Usage
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		// Properties that are unset, or not supported by the running
+		// version of systemd, are reported empty or as the maximum
+		// integer, and are ignored.
+		switch parts[0] {
+		case "MemoryCurrent":
+			if memory, err := strconv.ParseUint(parts[1], 10, 64); err == nil && memory != math.MaxUint64 {
+				usage.Memory = memory
+			}
+		case "NRestarts":
+			if restarts, err := strconv.Atoi(parts[1]); err == nil {
+				usage.Restarts = restarts
+			}
+		}
+	}
+	return usage, nil
+}
+
+// systemctl runs the given systemd command, returning its trimmed
+// output.
+func systemctl(command string, args ...string) (string, error) {
+	out, err := runCommand(command, args...)
+	out = strings.TrimSpace(out)
+	if err != nil {
+		if out != "" {
+			return "", errors.Errorf("%v: %s", err, out)
+		}
+		return "", errors.Trace(err)
+	}
+	return out, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemd_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+	"github.com/juju/juju/process/plugin/systemd"
+)
+
+type systemdSuite struct {
+	testing.IsolationSuite
+	stub    testing.Stub
+	outputs []string
+}
+
+var _ = gc.Suite(&systemdSuite{})
+
+func (s *systemdSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	s.outputs = nil
+	s.PatchValue(systemd.RunCommand, func(command string, args ...string) (string, error) {
+		s.stub.AddCall(command, args)
+		var out string
+		if len(s.outputs) > 0 {
+			out, s.outputs = s.outputs[0], s.outputs[1:]
+		}
+		return out, s.stub.NextErr()
+	})
+}
+
+func (s *systemdSuite) TestRegistered(c *gc.C) {
+	plugin, err := process.LaunchPlugin("systemd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plugin, gc.Equals, systemd.Plugin{})
+	c.Assert(plugin, gc.Implements, new(process.UsagePlugin))
+}
+
+func (s *systemdSuite) TestLaunch(c *gc.C) {
+	s.outputs = []string{"Running as unit juju-web.service.\n", "active\n"}
+	details, err := systemd.Plugin{}.Launch(process.Definition{
+		Name:    "web",
+		Type:    "systemd",
+		Command: "/usr/bin/python -m SimpleHTTPServer",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details.ID, gc.Matches, `juju-web-[0-9a-f]{8}\.service`)
+	c.Assert(details, jc.DeepEquals, process.Details{
+		ID:     details.ID,
+		Status: "active",
+		Extra: map[string]interface{}{
+			"unit":    details.ID,
+			"command": "/usr/bin/python -m SimpleHTTPServer",
+		},
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		"systemd-run", []interface{}{[]string{
+			"--unit", details.ID, "/bin/sh", "-c", "/usr/bin/python -m SimpleHTTPServer",
+		}},
+	}, {
+		"systemctl", []interface{}{[]string{"is-active", details.ID}},
+	}})
+
+	err = process.Info{Name: "web", Type: "systemd", Details: details}.Validate()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *systemdSuite) TestLaunchNoCommand(c *gc.C) {
+	_, err := systemd.Plugin{}.Launch(process.Definition{Name: "web", Type: "systemd"})
+	c.Assert(err, gc.ErrorMatches, `systemd process "web" without command not valid`)
+	s.stub.CheckCalls(c, nil)
+}
+
+func (s *systemdSuite) TestLaunchStatusErrorStopsService(c *gc.C) {
+	s.stub.SetErrors(nil, errors.New("exit status 1"))
+	_, err := systemd.Plugin{}.Launch(process.Definition{Name: "web", Type: "systemd", Command: "sleep 1000"})
+	c.Assert(err, gc.ErrorMatches, `cannot launch process "web": cannot get status of service "juju-web-[0-9a-f]{8}\.service": exit status 1`)
+	s.stub.CheckCallNames(c, "systemd-run", "systemctl", "systemctl")
+	unit := s.stub.Calls()[0].Args[0].([]string)[1]
+	s.stub.CheckCall(c, 2, "systemctl", []string{"stop", unit})
+}
+
+func (s *systemdSuite) TestStatusInactive(c *gc.C) {
+	s.outputs = []string{"failed\n"}
+	s.stub.SetErrors(errors.New("exit status 3"))
+	status, err := systemd.Plugin{}.Status("juju-web.service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, "failed")
+}
+
+func (s *systemdSuite) TestStatusError(c *gc.C) {
+	s.stub.SetErrors(errors.New("exec: \"systemctl\": executable file not found in $PATH"))
+	_, err := systemd.Plugin{}.Status("juju-web.service")
+	c.Assert(err, gc.ErrorMatches, `cannot get status of service "juju-web.service": exec: .*`)
+}
+
+func (s *systemdSuite) TestUsage(c *gc.C) {
+	s.outputs = []string{"MemoryCurrent=1048576\nNRestarts=2\n"}
+	usage, err := systemd.Plugin{}.Usage("juju-web.service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, process.Usage{Memory: 1048576, Restarts: 2})
+	s.stub.CheckCall(c, 0, "systemctl", []string{
+		"show", "--property=MemoryCurrent", "--property=NRestarts", "juju-web.service",
+	})
+}
+
+func (s *systemdSuite) TestUsageNotReported(c *gc.C) {
+	s.outputs = []string{"MemoryCurrent=18446744073709551615\nNRestarts=\n"}
+	usage, err := systemd.Plugin{}.Usage("juju-web.service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, process.Usage{})
+}
+
+func (s *systemdSuite) TestDestroy(c *gc.C) {
+	err := systemd.Plugin{}.Destroy("juju-web.service")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCall(c, 0, "systemctl", []string{"stop", "juju-web.service"})
+}
@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package process_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+)
+
+type pluginSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&pluginSuite{})
+
+type fakePlugin struct {
+	process.Plugin
+}
+
+func (s *pluginSuite) TestDefinitionValidate(c *gc.C) {
+	def := process.Definition{Name: "web", Type: "docker", Image: "nginx"}
+	c.Assert(def.Validate(), jc.ErrorIsNil)
+
+	def.Name = "Web"
+	c.Check(def.Validate(), gc.ErrorMatches, `process name "Web" not valid`)
+
+	def.Name, def.Type = "web", ""
+	c.Check(def.Validate(), gc.ErrorMatches, `process "web" with empty type not valid`)
+
+	def.Type = "docker"
+	def.HealthCheck = &process.HealthCheck{Type: "udp", Target: "localhost:53"}
+	c.Check(def.Validate(), gc.ErrorMatches, `process "web": health check type "udp" not valid`)
+
+	def.HealthCheck = &process.HealthCheck{Type: "tcp", Target: "localhost:80"}
+	def.Restart = "sometimes"
+	c.Check(def.Validate(), gc.ErrorMatches, `process "web": restart policy "sometimes" not valid`)
+
+	def.Restart = process.RestartOnFailure
+	c.Check(def.Validate(), jc.ErrorIsNil)
+}
+
+func (s *pluginSuite) TestRegisterLaunchPlugin(c *gc.C) {
+	s.PatchValue(process.LaunchPlugins, map[string]process.Plugin{})
+	_, err := process.LaunchPlugin("exec")
+	c.Assert(err, gc.ErrorMatches, `process launch plugin "exec" not found`)
+
+	plugin := &fakePlugin{}
+	process.RegisterLaunchPlugin("exec", plugin)
+	registered, err := process.LaunchPlugin("exec")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(registered, gc.Equals, plugin)
+
+	c.Assert(func() { process.RegisterLaunchPlugin("exec", plugin) }, gc.PanicMatches, `process launch plugin "exec" already registered`)
+}
+
+func (s *pluginSuite) TestRegisterLaunchPluginNoSchema(c *gc.C) {
+	s.PatchValue(process.LaunchPlugins, map[string]process.Plugin{})
+	c.Assert(func() { process.RegisterLaunchPlugin("unknown", &fakePlugin{}) }, gc.PanicMatches, `process plugin "unknown" not found`)
+}
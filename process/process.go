@@ -0,0 +1,143 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package process defines structures and functions relating to the
+// workload processes that charms launch outside of juju's control.
+package process
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/juju/errors"
+)
+
+var validName = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// Info describes a workload process tracked by a unit.
+type Info struct {
+	// Name is the name of the process, unique within the unit.
+	Name string `json:"name" yaml:"name"`
+
+	// Type is the type of the process, and identifies the plugin
+	// whose schema the process details are validated against.
+	Type string `json:"type" yaml:"type"`
+
+	// Details holds the details of the launched process.
+	Details Details `json:"details" yaml:"details"`
+
+	// Definition holds the definition the process was launched from,
+	// if it was launched by juju rather than tracked by the charm. It
+	// is used to check the health of the process and relaunch it.
+	Definition *Definition `json:"definition,omitempty" yaml:"definition,omitempty"`
+}
+
+// Details holds the details of a launched process, as supplied by
+// the charm that launched it.
+type Details struct {
+	// ID uniquely identifies the process to its plugin.
+	ID string `json:"id" yaml:"id"`
+
+	// Status is the plugin-specific status of the process.
+	Status string `json:"status" yaml:"status"`
+
+	// Extra holds any other plugin-specific details.
+	Extra map[string]interface{} `json:"extra,omitempty" yaml:"extra,omitempty"`
+
+	// Usage holds the resource usage of the process, if its plugin
+	// reports it.
+	Usage *Usage `json:"usage,omitempty" yaml:"usage,omitempty"`
+}
+
+// Usage holds the resource usage of a launched process. Zero values
+// indicate that the plugin does not report that resource.
+type Usage struct {
+	// CPU is the percentage of a single CPU used by the process.
+	CPU float64 `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+
+	// Memory is the number of bytes of memory used by the process.
+	Memory uint64 `json:"memory,omitempty" yaml:"memory,omitempty"`
+
+	// Restarts is the number of times the process has been restarted.
+	Restarts int `json:"restarts,omitempty" yaml:"restarts,omitempty"`
+}
+
+// failedStatuses holds the plugin statuses that indicate that a
+// process has failed, or has stopped without being destroyed.
+var failedStatuses = map[string]bool{
+	"dead":   true,
+	"error":  true,
+	"exited": true,
+	"failed": true,
+
+	UnhealthyStatus: true,
+}
+
+// IsFailedStatus reports whether the plugin-specific status of a
+// process indicates that it has failed.
+func IsFailedStatus(status string) bool {
+	return failedStatuses[status]
+}
+
+// Validate returns an error if the process info is not valid.
+func (info Info) Validate() error {
+	if !validName.MatchString(info.Name) {
+		return errors.NotValidf("process name %q", info.Name)
+	}
+	schema, err := PluginSchema(info.Type)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if info.Details.ID == "" {
+		return errors.NotValidf("process %q with empty id", info.Name)
+	}
+	if info.Details.Status == "" {
+		return errors.NotValidf("process %q with empty status", info.Name)
+	}
+	if err := schema.Validate(info.Details.Extra); err != nil {
+		return errors.Annotatef(err, "invalid %s details for process %q", info.Type, info.Name)
+	}
+	return nil
+}
+
+// ParseDetails parses the JSON-encoded details of a launched process
+// of the given type. The details must be a JSON object holding the
+// "id" and "status" of the process; any other fields are validated
+// against the schema of the type's plugin.
+func ParseDetails(processType string, data []byte) (Details, error) {
+	schema, err := PluginSchema(processType)
+	if err != nil {
+		return Details{}, errors.Trace(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Details{}, errors.Annotate(err, "cannot parse details")
+	}
+	id, err := takeString(fields, "id")
+	if err != nil {
+		return Details{}, errors.Trace(err)
+	}
+	status, err := takeString(fields, "status")
+	if err != nil {
+		return Details{}, errors.Trace(err)
+	}
+	details := Details{ID: id, Status: status}
+	if err := schema.Validate(fields); err != nil {
+		return Details{}, errors.Annotatef(err, "invalid %s details", processType)
+	}
+	if len(fields) > 0 {
+		details.Extra = fields
+	}
+	return details, nil
+}
+
+// takeString removes the named field from fields, returning its value
+// if it is a non-empty string.
+func takeString(fields map[string]interface{}, name string) (string, error) {
+	value, ok := fields[name].(string)
+	if !ok || value == "" {
+		return "", errors.Errorf("details must include a non-empty %q string", name)
+	}
+	delete(fields, name)
+	return value, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package process_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+)
+
+type processSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&processSuite{})
+
+func (s *processSuite) TestParseDetails(c *gc.C) {
+	details, err := process.ParseDetails("docker", []byte(`{"id": "abc", "status": "running", "image": "nginx"}`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details, jc.DeepEquals, process.Details{
+		ID:     "abc",
+		Status: "running",
+		Extra:  map[string]interface{}{"image": "nginx"},
+	})
+}
+
+func (s *processSuite) TestParseDetailsNoExtra(c *gc.C) {
+	details, err := process.ParseDetails("docker", []byte(`{"id": "abc", "status": "running"}`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details, jc.DeepEquals, process.Details{ID: "abc", Status: "running"})
+}
+
+func (s *processSuite) TestParseDetailsErrors(c *gc.C) {
+	for i, test := range []struct {
+		processType string
+		data        string
+		err         string
+	}{{
+		processType: "unknown",
+		data:        `{"id": "abc", "status": "running"}`,
+		err:         `process plugin "unknown" not found`,
+	}, {
+		processType: "docker",
+		data:        `["abc"]`,
+		err:         "cannot parse details: .*",
+	}, {
+		processType: "docker",
+		data:        `{"id": 1, "status": "running"}`,
+		err:         `details must include a non-empty "id" string`,
+	}, {
+		processType: "docker",
+		data:        `{"id": "abc"}`,
+		err:         `details must include a non-empty "status" string`,
+	}, {
+		processType: "docker",
+		data:        `{"id": "abc", "status": "running", "image": true}`,
+		err:         `invalid docker details: field "image": expected string, got boolean`,
+	}} {
+		c.Logf("test %d: %s", i, test.data)
+		_, err := process.ParseDetails(test.processType, []byte(test.data))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *processSuite) TestValidate(c *gc.C) {
+	info := process.Info{
+		Name:    "web-1",
+		Type:    "exec",
+		Details: process.Details{ID: "42", Status: "running"},
+	}
+	c.Assert(info.Validate(), jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		mutate func(*process.Info)
+		err    string
+	}{{
+		mutate: func(info *process.Info) { info.Name = "" },
+		err:    `process name "" not valid`,
+	}, {
+		mutate: func(info *process.Info) { info.Name = "web_1" },
+		err:    `process name "web_1" not valid`,
+	}, {
+		mutate: func(info *process.Info) { info.Type = "unknown" },
+		err:    `process plugin "unknown" not found`,
+	}, {
+		mutate: func(info *process.Info) { info.Details.ID = "" },
+		err:    `process "web-1" with empty id not valid`,
+	}, {
+		mutate: func(info *process.Info) { info.Details.Status = "" },
+		err:    `process "web-1" with empty status not valid`,
+	}, {
+		mutate: func(info *process.Info) { info.Details.Extra = map[string]interface{}{"image": "nginx"} },
+		err:    `invalid exec details for process "web-1": unknown field "image"`,
+	}} {
+		c.Logf("test %d", i)
+		invalid := info
+		test.mutate(&invalid)
+		c.Check(invalid.Validate(), gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *processSuite) TestIsFailedStatus(c *gc.C) {
+	for _, status := range []string{"failed", "exited", "dead", "error", "unhealthy"} {
+		c.Check(process.IsFailedStatus(status), jc.IsTrue, gc.Commentf("%s", status))
+	}
+	for _, status := range []string{"running", "active", "starting", ""} {
+		c.Check(process.IsFailedStatus(status), jc.IsFalse, gc.Commentf("%s", status))
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package process
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// FieldType is the type of a field in a plugin's details schema.
+type FieldType string
+
+const (
+	FieldString FieldType = "string"
+	FieldNumber FieldType = "number"
+	FieldBool   FieldType = "boolean"
+)
+
+// Field describes a field in a plugin's details schema.
+type Field struct {
+	// Type is the type that the field's value must have.
+	Type FieldType
+
+	// Required indicates that the field must be present.
+	Required bool
+}
+
+// Schema describes the plugin-specific details of a process, keyed
+// on field name. Fields not in the schema are not allowed.
+type Schema map[string]Field
+
+// Validate returns an error if the given details, as decoded from
+// JSON, do not conform to the schema.
+func (s Schema) Validate(details map[string]interface{}) error {
+	var names []string
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field := s[name]
+		value, ok := details[name]
+		if !ok {
+			if field.Required {
+				return errors.Errorf("missing required field %q", name)
+			}
+			continue
+		}
+		if !field.Type.matches(value) {
+			return errors.Errorf("field %q: expected %s, got %s", name, field.Type, describeValue(value))
+		}
+	}
+	var unknown []string
+	for name := range details {
+		if _, ok := s[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return errors.Errorf("unknown field %q", unknown[0])
+	}
+	return nil
+}
+
+// matches reports whether the value, as decoded from JSON, has the
+// field type.
+func (t FieldType) matches(value interface{}) bool {
+	switch value.(type) {
+	case string:
+		return t == FieldString
+	case float64:
+		return t == FieldNumber
+	case bool:
+		return t == FieldBool
+	}
+	return false
+}
+
+// describeValue returns the JSON type of the value.
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return string(FieldString)
+	case float64:
+		return string(FieldNumber)
+	case bool:
+		return string(FieldBool)
+	case nil:
+		return "null"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+var (
+	pluginsMu sync.Mutex
+	plugins   = map[string]Schema{
+		// docker processes are containers started by the charm.
+		"docker": {
+			"image":   {Type: FieldString},
+			"command": {Type: FieldString},
+		},
+		// exec processes are plain processes started by the charm.
+		"exec": {
+			"pid":     {Type: FieldNumber},
+			"command": {Type: FieldString},
+		},
+		// rkt processes are pods run under transient systemd units.
+		"rkt": {
+			"unit":    {Type: FieldString},
+			"image":   {Type: FieldString},
+			"command": {Type: FieldString},
+		},
+		// systemd processes are transient units started by the charm.
+		"systemd": {
+			"unit":    {Type: FieldString},
+			"command": {Type: FieldString},
+		},
+	}
+)
+
+// RegisterPlugin registers the details schema for processes of the
+// given type. It will panic if the type is already registered.
+func RegisterPlugin(processType string, schema Schema) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	if _, ok := plugins[processType]; ok {
+		panic(fmt.Errorf("process plugin %q already registered", processType))
+	}
+	plugins[processType] = schema
+}
+
+// PluginSchema returns the details schema for processes of the
+// given type.
+func PluginSchema(processType string) (Schema, error) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	schema, ok := plugins[processType]
+	if !ok {
+		return nil, errors.NotFoundf("process plugin %q", processType)
+	}
+	return schema, nil
+}
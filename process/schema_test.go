@@ -0,0 +1,81 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package process_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+)
+
+type schemaSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&schemaSuite{})
+
+var testSchema = process.Schema{
+	"name":    {Type: process.FieldString, Required: true},
+	"port":    {Type: process.FieldNumber},
+	"enabled": {Type: process.FieldBool},
+}
+
+func (s *schemaSuite) TestValidate(c *gc.C) {
+	err := testSchema.Validate(map[string]interface{}{
+		"name":    "foo",
+		"port":    8080.0,
+		"enabled": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = testSchema.Validate(map[string]interface{}{"name": "foo"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *schemaSuite) TestValidateErrors(c *gc.C) {
+	for i, test := range []struct {
+		details map[string]interface{}
+		err     string
+	}{{
+		details: nil,
+		err:     `missing required field "name"`,
+	}, {
+		details: map[string]interface{}{"name": "foo", "port": "8080"},
+		err:     `field "port": expected number, got string`,
+	}, {
+		details: map[string]interface{}{"name": "foo", "enabled": nil},
+		err:     `field "enabled": expected boolean, got null`,
+	}, {
+		details: map[string]interface{}{"name": []interface{}{"foo"}},
+		err:     `field "name": expected string, got array`,
+	}, {
+		details: map[string]interface{}{"name": "foo", "zone": "a", "colour": "red"},
+		err:     `unknown field "colour"`,
+	}} {
+		c.Logf("test %d", i)
+		c.Check(testSchema.Validate(test.details), gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *schemaSuite) TestRegisterPlugin(c *gc.C) {
+	s.PatchValue(process.Plugins, map[string]process.Schema{})
+	_, err := process.PluginSchema("test")
+	c.Assert(err, gc.ErrorMatches, `process plugin "test" not found`)
+
+	process.RegisterPlugin("test", testSchema)
+	schema, err := process.PluginSchema("test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schema, jc.DeepEquals, testSchema)
+
+	c.Assert(func() { process.RegisterPlugin("test", nil) }, gc.PanicMatches, `process plugin "test" already registered`)
+}
+
+func (s *schemaSuite) TestBuiltinPlugins(c *gc.C) {
+	for _, processType := range []string{"docker", "exec", "rkt", "systemd"} {
+		_, err := process.PluginSchema(processType)
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
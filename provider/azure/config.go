@@ -11,19 +11,59 @@ import (
 	"os"
 
 	"github.com/juju/schema"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 )
 
-var configFields = schema.Fields{
-	"location":                    schema.String(),
-	"management-subscription-id":  schema.String(),
-	"management-certificate-path": schema.String(),
-	"management-certificate":      schema.String(),
-	"storage-account-name":        schema.String(),
-	"force-image-name":            schema.String(),
-	"availability-sets-enabled":   schema.Bool(),
+var configSchema = environschema.Fields{
+	"location": {
+		Description: "The Azure region in which the environment's resources are created.",
+		Type:        environschema.Tstring,
+	},
+	"management-subscription-id": {
+		Description: "The ID of the Azure subscription to use.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	"management-certificate-path": {
+		Description: "The path to a PEM file holding the management certificate and private key.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	"management-certificate": {
+		Description: "The PEM-encoded management certificate and private key.",
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.AccountGroup,
+	},
+	"storage-account-name": {
+		Description: "The name of the storage account used for the environment's storage.",
+		Type:        environschema.Tstring,
+	},
+	"force-image-name": {
+		Description: "The name of an image to use instead of one found via simplestreams.",
+		Type:        environschema.Tstring,
+	},
+	"availability-sets-enabled": {
+		Description: "Whether services' machines are placed in availability sets.",
+		Type:        environschema.Tbool,
+	},
+	"storage-account-type": {
+		Description: "The type of storage account to create.",
+		Type:        environschema.Tstring,
+	},
 }
+
+var configFields = func() schema.Fields {
+	fs, _, err := configSchema.ValidationSchema()
+	if err != nil {
+		panic(err)
+	}
+	return fs
+}()
+
 var configDefaults = schema.Defaults{
 	"location":                    "",
 	"management-certificate":      "",
@@ -32,6 +72,24 @@ var configDefaults = schema.Defaults{
 	// availability-sets-enabled is set to Omit (equivalent
 	// to false) for backwards compatibility.
 	"availability-sets-enabled": schema.Omit,
+	"storage-account-type":      storageAccountTypeStandardLRS,
+}
+
+const (
+	storageAccountTypeStandardLRS   = "Standard_LRS"
+	storageAccountTypeStandardZRS   = "Standard_ZRS"
+	storageAccountTypeStandardGRS   = "Standard_GRS"
+	storageAccountTypeStandardRAGRS = "Standard_RAGRS"
+	storageAccountTypePremiumLRS    = "Premium_LRS"
+)
+
+// storageAccountTypes holds the valid values for storage-account-type.
+var storageAccountTypes = []string{
+	storageAccountTypeStandardLRS,
+	storageAccountTypeStandardZRS,
+	storageAccountTypeStandardGRS,
+	storageAccountTypeStandardRAGRS,
+	storageAccountTypePremiumLRS,
 }
 
 type azureEnvironConfig struct {
@@ -64,6 +122,17 @@ func (cfg *azureEnvironConfig) availabilitySetsEnabled() bool {
 	return enabled
 }
 
+func (cfg *azureEnvironConfig) storageAccountType() string {
+	return cfg.attrs["storage-account-type"].(string)
+}
+
+// premiumStorage reports whether the environment's storage account is
+// backed by Premium (SSD) storage. Only some role sizes may use disks
+// in a Premium storage account.
+func (cfg *azureEnvironConfig) premiumStorage() bool {
+	return cfg.storageAccountType() == storageAccountTypePremiumLRS
+}
+
 func (prov azureEnvironProvider) newConfig(cfg *config.Config) (*azureEnvironConfig, error) {
 	validCfg, err := prov.Validate(cfg, nil)
 	if err != nil {
@@ -123,6 +192,21 @@ func (prov azureEnvironProvider) Validate(cfg, oldCfg *config.Config) (*config.C
 	if envCfg.location() == "" {
 		return nil, fmt.Errorf("environment has no location; you need to set one.  E.g. 'West US'")
 	}
+
+	accountType := envCfg.storageAccountType()
+	if !set.NewStrings(storageAccountTypes...).Contains(accountType) {
+		return nil, fmt.Errorf(
+			"invalid storage-account-type %q: expected one of %q",
+			accountType, storageAccountTypes,
+		)
+	}
+	// The storage account type is a property of the storage account,
+	// which cannot be changed after the environment is prepared.
+	if oldCfg != nil {
+		if oldType, ok := oldCfg.UnknownAttrs()["storage-account-type"]; ok && oldType != accountType {
+			return nil, fmt.Errorf("cannot change storage-account-type from %q to %q", oldType, accountType)
+		}
+	}
 	return cfg.Apply(envCfg.attrs)
 }
 
@@ -167,6 +251,13 @@ azure:
     #
     storage-account-name: abcdefghijkl
 
+    # storage-account-type describes the replication and performance
+    # type of the storage account named above. Set it to Premium_LRS
+    # if the account uses Premium (SSD) storage; instances will then
+    # be restricted to role sizes that support Premium storage.
+    #
+    # storage-account-type: Standard_LRS
+
     # force-image-name overrides the OS image selection to use a fixed
     # image for all deployments. Most useful for developers.
     #
@@ -204,6 +295,15 @@ func (prov azureEnvironProvider) BoilerplateConfig() string {
 	return boilerplateYAML
 }
 
+// Schema implements environs.ProviderSchema.
+func (prov azureEnvironProvider) Schema() environschema.Fields {
+	fields, err := config.Schema(configSchema)
+	if err != nil {
+		panic(err)
+	}
+	return fields
+}
+
 // SecretAttrs is specified in the EnvironProvider interface.
 func (prov azureEnvironProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	secretAttrs := make(map[string]string)
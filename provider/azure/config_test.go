@@ -12,6 +12,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 	envtesting "github.com/juju/juju/environs/testing"
@@ -217,6 +218,13 @@ func (*configSuite) TestSecretAttrsReturnsSensitiveAttributes(c *gc.C) {
 	c.Check(secretAttrs, gc.DeepEquals, expectedAttrs)
 }
 
+func (*configSuite) TestSchema(c *gc.C) {
+	fields := azureEnvironProvider{}.Schema()
+	c.Check(fields["management-certificate"].Secret, jc.IsTrue)
+	c.Check(fields["management-certificate-path"].Secret, jc.IsFalse)
+	c.Check(fields["name"].Type, gc.Equals, environschema.Tstring)
+}
+
 func (*configSuite) TestEmptyImageStream1dot16Compat(c *gc.C) {
 	attrs := makeAzureConfigMap(c)
 	attrs["image-stream"] = ""
@@ -264,3 +272,42 @@ func (s *configSuite) TestAvailabilitySetsEnabledImmutable(c *gc.C) {
 	err = env.SetConfig(cfg)
 	c.Assert(err, gc.ErrorMatches, "cannot change availability-sets-enabled")
 }
+
+func (*configSuite) TestStorageAccountTypeDefault(c *gc.C) {
+	cfg, err := config.New(config.UseDefaults, makeAzureConfigMap(c))
+	c.Assert(err, jc.ErrorIsNil)
+	ecfg, err := azureEnvironProvider{}.newConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ecfg.storageAccountType(), gc.Equals, "Standard_LRS")
+	c.Assert(ecfg.premiumStorage(), jc.IsFalse)
+}
+
+func (*configSuite) TestStorageAccountTypePremium(c *gc.C) {
+	attrs := makeAzureConfigMap(c)
+	attrs["storage-account-type"] = "Premium_LRS"
+	cfg, err := config.New(config.UseDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	ecfg, err := azureEnvironProvider{}.newConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ecfg.premiumStorage(), jc.IsTrue)
+}
+
+func (*configSuite) TestStorageAccountTypeInvalid(c *gc.C) {
+	attrs := makeAzureConfigMap(c)
+	attrs["storage-account-type"] = "Fancy_LRS"
+	cfg, err := config.New(config.UseDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = azureEnvironProvider{}.Validate(cfg, nil)
+	c.Assert(err, gc.ErrorMatches, `invalid storage-account-type "Fancy_LRS": expected one of .*`)
+}
+
+func (*configSuite) TestStorageAccountTypeImmutable(c *gc.C) {
+	cfg, err := config.New(config.UseDefaults, makeAzureConfigMap(c))
+	c.Assert(err, jc.ErrorIsNil)
+	oldCfg, err := azureEnvironProvider{}.Validate(cfg, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	newCfg, err := oldCfg.Apply(map[string]interface{}{"storage-account-type": "Premium_LRS"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = azureEnvironProvider{}.Validate(newCfg, oldCfg)
+	c.Assert(err, gc.ErrorMatches, `cannot change storage-account-type from "Standard_LRS" to "Premium_LRS"`)
+}
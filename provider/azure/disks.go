@@ -28,6 +28,14 @@ const (
 	volumeSizeMaxGiB = 1023
 )
 
+// premiumDiskSizesGiB holds the sizes (in gibibytes) of the disk types
+// offered by Azure Premium storage, in ascending order. Premium disks
+// are billed, and perform, according to the smallest type that can
+// hold them, so we always create disks of exactly those sizes.
+//
+// See: https://azure.microsoft.com/en-gb/documentation/articles/storage-premium-storage-preview-portal/
+var premiumDiskSizesGiB = []uint64{128, 512, volumeSizeMaxGiB}
+
 // azureStorageProvider is a storage provider for Azure disks.
 type azureStorageProvider struct{}
 
@@ -158,6 +166,9 @@ func (v *azureVolumeSource) createVolume(p storage.VolumeParams) (*storage.Volum
 
 	// Create and attach a disk to the instance.
 	sizeInGib := mibToGib(p.Size)
+	if v.env.ecfg.premiumStorage() {
+		sizeInGib = premiumDiskSizeGiB(sizeInGib)
+	}
 	if err := v.env.api.AddDataDisk(&gwacl.AddDataDiskRequest{
 		ServiceName:    cloudServiceName,
 		DeploymentName: deploymentName,
@@ -201,6 +212,17 @@ func (v *azureVolumeSource) createVolume(p storage.VolumeParams) (*storage.Volum
 	return &volume, &volumeAttachment, nil
 }
 
+// premiumDiskSizeGiB returns the size of the smallest Premium storage
+// disk type that can hold a disk of the given size.
+func premiumDiskSizeGiB(sizeInGib uint64) uint64 {
+	for _, size := range premiumDiskSizesGiB {
+		if sizeInGib <= size {
+			return size
+		}
+	}
+	return sizeInGib
+}
+
 // vhdMediaLinkPrefix returns the media link prefix for disks
 // associated with the environment. gwacl's helper returns
 // http scheme URLs; we use https to simplify matching what
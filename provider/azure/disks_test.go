@@ -249,6 +249,19 @@ func (s *azureVolumeSuite) TestCreateVolumesLegacyInstance(c *gc.C) {
 	c.Assert(results[0].Error, gc.ErrorMatches, "attaching disks to legacy instances not supported")
 }
 
+func (s *azureVolumeSuite) TestPremiumDiskSizeGiB(c *gc.C) {
+	for _, t := range []struct{ in, out uint64 }{
+		{1, 128},
+		{128, 128},
+		{129, 512},
+		{512, 512},
+		{513, 1023},
+		{1023, 1023},
+	} {
+		c.Check(premiumDiskSizeGiB(t.in), gc.Equals, t.out)
+	}
+}
+
 func (s *azureVolumeSuite) TestDestroyVolumes(c *gc.C) {
 	vs := s.volumeSource(c, nil)
 	results, err := vs.DestroyVolumes([]string{"volume-0.vhd", "volume-1.vhd"})
@@ -9,12 +9,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils"
 	jujuos "github.com/juju/utils/os"
 	"github.com/juju/utils/series"
@@ -30,6 +32,7 @@ import (
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
@@ -57,11 +60,16 @@ const (
 	// stateServerLabel is the label applied to the cloud service created
 	// for state servers.
 	stateServerLabel = "juju-state-server"
+
+	// defaultAvailabilitySetName is the name of the availability set
+	// used for instances that are not grouped by Juju service.
+	defaultAvailabilitySetName = "juju"
 )
 
 // vars for testing purposes.
 var (
 	createInstance = (*azureEnviron).createInstance
+	getOSDiskSize  = (*azureEnviron).getOSDiskSize
 )
 
 type azureEnviron struct {
@@ -101,6 +109,7 @@ type azureEnviron struct {
 var _ environs.Environ = (*azureEnviron)(nil)
 var _ simplestreams.HasRegion = (*azureEnviron)(nil)
 var _ state.Prechecker = (*azureEnviron)(nil)
+var _ environs.InstanceTypesFetcher = (*azureEnviron)(nil)
 
 // NewEnviron creates a new azureEnviron.
 func NewEnviron(cfg *config.Config) (*azureEnviron, error) {
@@ -572,6 +581,9 @@ func (env *azureEnviron) selectInstanceTypeAndImage(constraint *instances.Instan
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Zones,
+	constraints.VirtType,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -774,13 +786,24 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if snapshot.ecfg.availabilitySetsEnabled() {
+		role.AvailabilitySetName = availabilitySetName(args.InstanceConfig)
+	}
 	inst, err := createInstance(env, snapshot.api, role, cloudServiceName, stateServer)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// The role size only tells us the maximum size of the OS disk;
+	// report the actual size of the disk created from the image.
+	rootDisk := instanceType.RootDisk
+	if size, err := getOSDiskSize(env, vhd.MediaLink); err != nil {
+		logger.Warningf("cannot get OS disk size, reporting maximum for %q: %v", instanceType.Name, err)
+	} else {
+		rootDisk = size
+	}
 	hc := &instance.HardwareCharacteristics{
 		Mem:      &instanceType.Mem,
-		RootDisk: &instanceType.RootDisk,
+		RootDisk: &rootDisk,
 		CpuCores: &instanceType.CpuCores,
 	}
 	if len(instanceType.Arches) == 1 {
@@ -867,6 +890,25 @@ func (env *azureEnviron) newOSDisk(sourceImageName string, ser string) (*gwacl.O
 	return gwacl.NewOSVirtualHardDisk("", "", "", mediaLink, sourceImageName, OSType), nil
 }
 
+// getOSDiskSize returns the size, in MiB, of the OS disk
+// with the given media link.
+func (env *azureEnviron) getOSDiskSize(mediaLink string) (uint64, error) {
+	disks, err := env.getSnapshot().api.ListDisks()
+	if err != nil {
+		return 0, errors.Annotate(err, "listing disks")
+	}
+	// Azure always reports https media links, regardless of
+	// the scheme specified when the disk was created, so we
+	// match on the (randomly generated) VHD name only.
+	_, vhdName := path.Split(mediaLink)
+	for _, disk := range disks {
+		if _, name := path.Split(disk.MediaLink); name == vhdName {
+			return gibToMib(uint64(disk.LogicalSizeInGB)), nil
+		}
+	}
+	return 0, errors.NotFoundf("OS disk %q", vhdName)
+}
+
 // getInitialEndpoints returns a slice of the endpoints every instance should have open
 // (ssh port, etc).
 func (env *azureEnviron) getInitialEndpoints(stateServer bool) []gwacl.InputEndpoint {
@@ -924,10 +966,32 @@ func (env *azureEnviron) newRole(roleSize string, vhd *gwacl.OSVirtualHardDisk,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	role.AvailabilitySetName = "juju"
+	role.AvailabilitySetName = defaultAvailabilitySetName
 	return role, nil
 }
 
+// availabilitySetName returns the name of the availability set that
+// the instance described by icfg should be placed in. Instances hosting
+// units of the same service are grouped into an availability set named
+// after that service, so that Azure's rolling maintenance will not take
+// down all of the service's units at once. State servers, and instances
+// not yet hosting any units, are placed in the default availability set.
+func availabilitySetName(icfg *instancecfg.InstanceConfig) string {
+	if multiwatcher.AnyJobNeedsState(icfg.Jobs...) {
+		return defaultAvailabilitySetName
+	}
+	unitNames := strings.Fields(icfg.Tags[tags.JujuUnitsDeployed])
+	if len(unitNames) == 0 {
+		return defaultAvailabilitySetName
+	}
+	serviceName, err := names.UnitService(unitNames[0])
+	if err != nil {
+		logger.Warningf("cannot determine service for unit %q: %v", unitNames[0], err)
+		return defaultAvailabilitySetName
+	}
+	return defaultAvailabilitySetName + "-" + serviceName
+}
+
 // makeLinuxRole will create a gwacl.Role for a Linux VM.
 // The VM will have:
 // - an 'ubuntu' user defined with an unguessable (randomly generated) password
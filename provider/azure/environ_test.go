@@ -15,6 +15,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -32,6 +33,7 @@ import (
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/tags"
 	envtesting "github.com/juju/juju/environs/testing"
 	"github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/instance"
@@ -1307,6 +1309,32 @@ func (s *environSuite) TestNewWindowsRole(c *gc.C) {
 	c.Assert(publicParam.Type, gc.Equals, gwacl.ResourceExtensionParameterTypePublic)
 }
 
+func (*environSuite) TestGetOSDiskSize(c *gc.C) {
+	env := makeEnviron(c)
+	type disks struct {
+		Disks []gwacl.Disk `xml:"Disk"`
+	}
+	listDisksResponse, err := xml.Marshal(&disks{Disks: []gwacl.Disk{{
+		MediaLink:       "https://account.blob.core.windows.net/vhds/other.vhd",
+		LogicalSizeInGB: 10,
+	}, {
+		MediaLink:       "https://account.blob.core.windows.net/vhds/os.vhd",
+		LogicalSizeInGB: 30,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	gwacl.PatchManagementAPIResponses([]gwacl.DispatcherResponse{
+		gwacl.NewDispatcherResponse(listDisksResponse, http.StatusOK, nil),
+		gwacl.NewDispatcherResponse(listDisksResponse, http.StatusOK, nil),
+	})
+
+	size, err := env.getOSDiskSize("http://account.blob.core.windows.net/vhds/os.vhd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(30*1024))
+
+	_, err = env.getOSDiskSize("http://account.blob.core.windows.net/vhds/missing.vhd")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (*environSuite) TestProviderReturnsAzureEnvironProvider(c *gc.C) {
 	prov := makeEnviron(c).Provider()
 	c.Assert(prov, gc.NotNil)
@@ -1573,6 +1601,9 @@ func (s *startInstanceSuite) SetUpTest(c *gc.C) {
 		),
 		InstanceConfig: icfg,
 	}
+	s.PatchValue(&getOSDiskSize, func(*azureEnviron, string) (uint64, error) {
+		return 30 * 1024, nil
+	})
 }
 
 func (s *startInstanceSuite) startInstance(c *gc.C) (serviceName string, stateServer bool) {
@@ -1597,15 +1628,30 @@ func (s *startInstanceSuite) startInstance(c *gc.C) (serviceName string, stateSe
 	c.Assert(result, gc.NotNil)
 	c.Assert(result.Hardware, gc.NotNil)
 	arch := "amd64"
+	rootDisk := uint64(30 * 1024)
 	c.Assert(result.Hardware, gc.DeepEquals, &instance.HardwareCharacteristics{
 		Arch:     &arch,
 		Mem:      &roleSize.Mem,
-		RootDisk: &roleSize.OSDiskSpace,
+		RootDisk: &rootDisk,
 		CpuCores: &roleSize.CpuCores,
 	})
 	return serviceName, stateServer
 }
 
+func (s *startInstanceSuite) TestStartInstanceOSDiskSizeError(c *gc.C) {
+	s.PatchValue(&getOSDiskSize, func(*azureEnviron, string) (uint64, error) {
+		return 0, fmt.Errorf("no disks for you")
+	})
+	s.PatchValue(&createInstance, func(*azureEnviron, *gwacl.ManagementAPI, *gwacl.Role, string, bool) (instance.Instance, error) {
+		return nil, nil
+	})
+	result, err := s.env.StartInstance(s.params)
+	c.Assert(err, jc.ErrorIsNil)
+	// The role size's maximum OS disk size is reported instead.
+	c.Assert(result.Hardware.RootDisk, gc.NotNil)
+	c.Assert(*result.Hardware.RootDisk, gc.Not(gc.Equals), uint64(30*1024))
+}
+
 func (s *startInstanceSuite) TestStartInstanceDistributionGroupError(c *gc.C) {
 	s.params.DistributionGroup = func() ([]instance.Id, error) {
 		return nil, fmt.Errorf("DistributionGroupError")
@@ -1644,6 +1690,47 @@ func (s *startInstanceSuite) TestStartInstanceDistributionGroup(c *gc.C) {
 	c.Assert(serviceName, gc.Equals, "juju-testenv-whatever")
 }
 
+func (s *startInstanceSuite) startInstanceAvailabilitySet(c *gc.C) string {
+	var availabilitySetName string
+	restore := testing.PatchValue(&createInstance, func(env *azureEnviron, azure *gwacl.ManagementAPI, role *gwacl.Role, serviceName string, stateServer bool) (instance.Instance, error) {
+		availabilitySetName = role.AvailabilitySetName
+		return nil, nil
+	})
+	defer restore()
+	_, err := s.env.StartInstance(s.params)
+	c.Assert(err, jc.ErrorIsNil)
+	return availabilitySetName
+}
+
+func (s *startInstanceSuite) TestStartInstanceAvailabilitySetPerService(c *gc.C) {
+	s.params.InstanceConfig.Tags = map[string]string{
+		tags.JujuUnitsDeployed: "wordpress/0 mysql/1",
+	}
+	// The service name will only be used if
+	// availability-sets-enabled=true.
+	s.env.ecfg.attrs["availability-sets-enabled"] = false
+	c.Assert(s.startInstanceAvailabilitySet(c), gc.Equals, "juju")
+	s.env.ecfg.attrs["availability-sets-enabled"] = true
+	c.Assert(s.startInstanceAvailabilitySet(c), gc.Equals, "juju-wordpress")
+}
+
+func (s *startInstanceSuite) TestStartInstanceAvailabilitySetNoUnits(c *gc.C) {
+	s.env.ecfg.attrs["availability-sets-enabled"] = true
+	c.Assert(s.startInstanceAvailabilitySet(c), gc.Equals, "juju")
+}
+
+func (s *startInstanceSuite) TestStartInstanceAvailabilitySetStateServer(c *gc.C) {
+	s.env.ecfg.attrs["availability-sets-enabled"] = true
+	s.params.InstanceConfig.Jobs = []multiwatcher.MachineJob{
+		multiwatcher.JobHostUnits,
+		multiwatcher.JobManageEnviron,
+	}
+	s.params.InstanceConfig.Tags = map[string]string{
+		tags.JujuUnitsDeployed: "wordpress/0",
+	}
+	c.Assert(s.startInstanceAvailabilitySet(c), gc.Equals, "juju")
+}
+
 func (s *startInstanceSuite) TestStartInstanceStateServerJobs(c *gc.C) {
 	// If the machine has the JobManagesEnviron job,
 	// we should see stateServer==true.
@@ -1666,10 +1753,10 @@ func (s *environSuite) TestConstraintsValidator(c *gc.C) {
 	env := s.setupEnvWithDummyMetadata(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10")
+	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10 root-disk-source=ssd")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "tags"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "root-disk-source", "tags"})
 }
 
 func (s *environSuite) TestConstraintsValidatorVocab(c *gc.C) {
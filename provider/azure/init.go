@@ -13,7 +13,14 @@ const (
 )
 
 func init() {
-	environs.RegisterProvider(providerType, azureEnvironProvider{})
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Provider:   azureEnvironProvider{},
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityStorage,
+		},
+	})
 
 	// Register the Azure storage provider.
 	registry.RegisterProvider(storageProviderType, &azureStorageProvider{})
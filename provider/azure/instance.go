@@ -42,11 +42,11 @@ func (azInstance *azureInstance) supportsLoadBalancing() bool {
 }
 
 // Status is specified in the Instance interface.
-func (azInstance *azureInstance) Status() string {
+func (azInstance *azureInstance) Status() instance.InstanceStatus {
 	if azInstance.roleInstance == nil {
-		return ""
+		return instance.InstanceStatus{}
 	}
-	return azInstance.roleInstance.InstanceStatus
+	return instance.InstanceStatus{Status: azInstance.roleInstance.InstanceStatus}
 }
 
 func (azInstance *azureInstance) serviceName() string {
@@ -102,6 +102,11 @@ func (azInstance *azureInstance) apiCall(lock bool, f func(*gwacl.ManagementAPI)
 }
 
 // openEndpoints opens the endpoints in the Azure deployment.
+//
+// TODO Endpoints are always public. Scoping them to the virtual
+// network requires an internal load balancer (ILB) in the deployment,
+// and InputEndpoint.LoadBalancerName to reference it; gwacl supports
+// neither, so "juju expose" cannot yet create intranet-only endpoints.
 func (azInstance *azureInstance) openEndpoints(api *gwacl.ManagementAPI, portRanges []network.PortRange) error {
 	request := &gwacl.AddRoleEndpointsRequest{
 		ServiceName:    azInstance.serviceName(),
@@ -65,9 +65,9 @@ func (*instanceSuite) TestId(c *gc.C) {
 
 func (*instanceSuite) TestStatus(c *gc.C) {
 	var inst azureInstance
-	c.Check(inst.Status(), gc.Equals, "")
+	c.Check(inst.Status().Status, gc.Equals, "")
 	inst.roleInstance = &gwacl.RoleInstance{InstanceStatus: "anyoldthing"}
-	c.Check(inst.Status(), gc.Equals, "anyoldthing")
+	c.Check(inst.Status().Status, gc.Equals, "anyoldthing")
 }
 
 func makeInputEndpoint(port int, protocol string) gwacl.InputEndpoint {
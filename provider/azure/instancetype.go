@@ -136,7 +136,9 @@ func listInstanceTypes(env *azureEnviron) ([]instances.InstanceType, error) {
 	}
 	limitedTypes := make(set.Strings)
 
-	region := env.getSnapshot().ecfg.location()
+	ecfg := env.getSnapshot().ecfg
+	region := ecfg.location()
+	premiumStorage := ecfg.premiumStorage()
 	arches, err := env.SupportedArchitectures()
 	if err != nil {
 		return nil, err
@@ -154,6 +156,10 @@ func listInstanceTypes(env *azureEnviron) ([]instances.InstanceType, error) {
 			logger.Debugf("role size %q is unsupported", roleSize.Name)
 			continue
 		}
+		if premiumStorage && !supportsPremiumStorage(roleSize.Name) {
+			logger.Debugf("role size %q does not support Premium storage", roleSize.Name)
+			continue
+		}
 		if vnet != nil && vnet.AffinityGroup != "" && isLimitedRoleSize(roleSize.Name) {
 			limitedTypes.Add(roleSize.Name)
 			continue
@@ -176,6 +182,16 @@ func listInstanceTypes(env *azureEnviron) ([]instances.InstanceType, error) {
 	return types, nil
 }
 
+// InstanceTypes is specified in the environs.InstanceTypesFetcher interface.
+func (env *azureEnviron) InstanceTypes() ([]instances.InstanceType, error) {
+	types, err := listInstanceTypes(env)
+	if err != nil {
+		return nil, err
+	}
+	instances.SortByCost(types)
+	return types, nil
+}
+
 // isLimitedRoleSize reports whether the named role size is limited to some
 // physical hosts only.
 func isLimitedRoleSize(name string) bool {
@@ -193,6 +209,13 @@ func isLimitedRoleSize(name string) bool {
 	return true
 }
 
+// supportsPremiumStorage reports whether the named role size may use
+// disks in a Premium storage account. At the time of writing, only
+// the DS-series and GS-series role sizes support Premium storage.
+func supportsPremiumStorage(name string) bool {
+	return strings.HasPrefix(name, "Standard_DS") || strings.HasPrefix(name, "Standard_GS")
+}
+
 // findInstanceSpec returns the InstanceSpec that best satisfies the supplied
 // InstanceConstraint.
 func findInstanceSpec(env *azureEnviron, constraint *instances.InstanceConstraint) (*instances.InstanceSpec, error) {
@@ -290,6 +290,36 @@ func (s *instanceTypeSuite) TestListInstanceTypesMaintainsOrder(c *gc.C) {
 	c.Assert(types, gc.DeepEquals, expectation)
 }
 
+func (s *instanceTypeSuite) TestListInstanceTypesPremiumStorageFiltering(c *gc.C) {
+	// Only DS- and GS-series role sizes may use Premium storage.
+	expectation := make([]instances.InstanceType, 0, len(gwacl.RoleSizes))
+	for _, roleSize := range gwacl.RoleSizes {
+		if !strings.HasPrefix(roleSize.Name, "Standard_DS") && !strings.HasPrefix(roleSize.Name, "Standard_GS") {
+			continue
+		}
+		instanceType, err := newInstanceType(roleSize, "West US")
+		c.Assert(err, jc.ErrorIsNil)
+		instanceType.Arches = []string{"amd64"}
+		expectation = append(expectation, instanceType)
+	}
+
+	env := s.setupEnvWithDummyMetadata(c)
+	env.ecfg.attrs["storage-account-type"] = "Premium_LRS"
+	types, err := listInstanceTypes(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(types, gc.DeepEquals, expectation)
+}
+
+func (s *instanceTypeSuite) TestInstanceTypesSortsByCost(c *gc.C) {
+	env := s.setupEnvWithDummyMetadata(c)
+	types, err := env.InstanceTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(types, gc.Not(gc.HasLen), 0)
+	for i := 1; i < len(types); i++ {
+		c.Check(types[i].Cost >= types[i-1].Cost, jc.IsTrue)
+	}
+}
+
 func (s *instanceTypeSuite) TestFindInstanceSpecFailsImpossibleRequest(c *gc.C) {
 	impossibleConstraint := &instances.InstanceConstraint{
 		Series: "precise",
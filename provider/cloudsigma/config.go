@@ -8,6 +8,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 )
@@ -32,21 +33,49 @@ const (
 	defaultStoragePort = 8040
 )
 
-var configFields = schema.Fields{
-	"username": schema.String(),
-	"password": schema.String(),
-	"region":   schema.String(),
+var configSchema = environschema.Fields{
+	"username": {
+		Description: "The CloudSigma account user name.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	"password": {
+		Description: "The CloudSigma account password.",
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.AccountGroup,
+	},
+	"region": {
+		Description: "The CloudSigma region to use.",
+		Type:        environschema.Tstring,
+	},
 }
 
+var configFields = func() schema.Fields {
+	fs, _, err := configSchema.ValidationSchema()
+	if err != nil {
+		panic(err)
+	}
+	return fs
+}()
+
 var configDefaultFields = schema.Defaults{
 	"username": "",
 	"password": "",
 	"region":   gosigma.DefaultRegion,
 }
 
-var configSecretFields = []string{
-	"password",
-}
+// configSecretFields lists the config values marked as secret in
+// configSchema.
+var configSecretFields = func() []string {
+	var fields []string
+	for name, attr := range configSchema {
+		if attr.Secret {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}()
 
 var configImmutableFields = []string{
 	"region",
@@ -5,7 +5,9 @@ package cloudsigma
 
 import (
 	"github.com/juju/schema"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -283,6 +285,13 @@ func (s *configSuite) TestSecretAttrsAreStrings(c *gc.C) {
 	}
 }
 
+func (s *configSuite) TestSchema(c *gc.C) {
+	fields := providerInstance.Schema()
+	c.Check(fields["password"].Secret, jc.IsTrue)
+	c.Check(fields["username"].Secret, jc.IsFalse)
+	c.Check(fields["name"].Type, gc.Equals, environschema.Tstring)
+}
+
 func (s *configSuite) TestClientConfigChanged(c *gc.C) {
 	ecfg := &environConfig{
 		Config: newConfig(c, testing.Attrs{"name": "client-test"}),
@@ -36,6 +36,9 @@ var unsupportedConstraints = []string{
 	constraints.Container,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Zones,
+	constraints.VirtType,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator returns a Validator instance which
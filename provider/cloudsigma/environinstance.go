@@ -134,7 +134,7 @@ func (env *environ) AllInstances() ([]instance.Instance, error) {
 	if logger.LogLevel() <= loggo.TRACE {
 		logger.Tracef("All instances, len = %d:", len(instances))
 		for _, instance := range instances {
-			logger.Tracef("... id: %q, status: %q", instance.Id(), instance.Status())
+			logger.Tracef("... id: %q, status: %q", instance.Id(), instance.Status().Status)
 		}
 	}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -44,7 +45,11 @@ func init() {
 	// somewhere. To enable a provider, import it in the "providers/all"
 	// package; please do *not* import individual providers anywhere else,
 	// except in direct tests for that provider.
-	environs.RegisterProvider("cloudsigma", providerInstance)
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Provider:   providerInstance,
+		APIVersion: environs.ProviderAPIVersion,
+	})
 	environs.RegisterImageDataSourceFunc("cloud sigma image source", getImageSource)
 	registry.RegisterEnvironStorageProviders(providerType)
 }
@@ -133,6 +138,15 @@ func (environProvider) Validate(cfg, old *config.Config) (*config.Config, error)
 	return newEcfg.Config, nil
 }
 
+// Schema implements environs.ProviderSchema.
+func (environProvider) Schema() environschema.Fields {
+	fields, err := config.Schema(configSchema)
+	if err != nil {
+		panic(err)
+	}
+	return fields
+}
+
 // SecretAttrs filters the supplied configuration returning only values
 // which are considered sensitive. All of the values of these secret
 // attributes need to be strings.
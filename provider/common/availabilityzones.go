@@ -5,7 +5,12 @@ package common
 
 import (
 	"sort"
+	"strings"
 
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 )
@@ -131,6 +136,30 @@ func AvailabilityZoneAllocations(env ZonedEnviron, group []instance.Id) ([]Avail
 	return zoneInstances, nil
 }
 
+// FilterZonesByConstraints returns the availability zones in zones
+// that are allowed by the "zones" constraint in cons, in their original
+// order. If the constraint is not set, zones is returned unchanged. An
+// error is returned if none of the zones are allowed.
+func FilterZonesByConstraints(zones []string, cons constraints.Value) ([]string, error) {
+	if !cons.HaveZones() {
+		return zones, nil
+	}
+	allowed := set.NewStrings(*cons.Zones...)
+	var result []string
+	for _, zone := range zones {
+		if allowed.Contains(zone) {
+			result = append(result, zone)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.Errorf(
+			"no availability zones match constraint %q",
+			"zones="+strings.Join(*cons.Zones, ","),
+		)
+	}
+	return result, nil
+}
+
 var internalAvailabilityZoneAllocations = AvailabilityZoneAllocations
 
 // DistributeInstances is a common function for implement the
@@ -9,6 +9,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/common"
@@ -254,3 +255,22 @@ func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
 		c.Assert(eligible, jc.SameContents, test.eligible)
 	}
 }
+
+func (s *AvailabilityZoneSuite) TestFilterZonesByConstraintsUnset(c *gc.C) {
+	zones, err := common.FilterZonesByConstraints([]string{"az1", "az2"}, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.DeepEquals, []string{"az1", "az2"})
+}
+
+func (s *AvailabilityZoneSuite) TestFilterZonesByConstraints(c *gc.C) {
+	cons := constraints.MustParse("zones=az3,az1")
+	zones, err := common.FilterZonesByConstraints([]string{"az1", "az2", "az3"}, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.DeepEquals, []string{"az1", "az3"})
+}
+
+func (s *AvailabilityZoneSuite) TestFilterZonesByConstraintsNoMatch(c *gc.C) {
+	cons := constraints.MustParse("zones=az3,az4")
+	_, err := common.FilterZonesByConstraints([]string{"az1", "az2"}, cons)
+	c.Assert(err, gc.ErrorMatches, `no availability zones match constraint "zones=az3,az4"`)
+}
@@ -191,21 +191,22 @@ type OpSubnets struct {
 }
 
 type OpStartInstance struct {
-	Env              string
-	MachineId        string
-	MachineNonce     string
-	PossibleTools    coretools.List
-	Instance         instance.Instance
-	Constraints      constraints.Value
-	SubnetsToZones   map[network.Id][]string
-	Networks         []string
-	NetworkInfo      []network.InterfaceInfo
-	Volumes          []storage.Volume
-	Info             *mongo.MongoInfo
-	Jobs             []multiwatcher.MachineJob
-	APIInfo          *api.Info
-	Secret           string
-	AgentEnvironment map[string]string
+	Env               string
+	MachineId         string
+	MachineNonce      string
+	PossibleTools     coretools.List
+	Instance          instance.Instance
+	Constraints       constraints.Value
+	SubnetsToZones    map[network.Id][]string
+	DistributionGroup []instance.Id
+	Networks          []string
+	NetworkInfo       []network.InterfaceInfo
+	Volumes           []storage.Volume
+	Info              *mongo.MongoInfo
+	Jobs              []multiwatcher.MachineJob
+	APIInfo           *api.Info
+	Secret            string
+	AgentEnvironment  map[string]string
 }
 
 type OpStopInstances struct {
@@ -213,6 +214,8 @@ type OpStopInstances struct {
 	Ids []instance.Id
 }
 
+// OpOpenPorts records ports being opened on an instance or, when
+// MachineId and InstanceId are empty, on the whole environment.
 type OpOpenPorts struct {
 	Env        string
 	MachineId  string
@@ -220,6 +223,8 @@ type OpOpenPorts struct {
 	Ports      []network.PortRange
 }
 
+// OpClosePorts records ports being closed on an instance or, when
+// MachineId and InstanceId are empty, on the whole environment.
 type OpClosePorts struct {
 	Env        string
 	MachineId  string
@@ -261,6 +266,7 @@ type environState struct {
 	maxAddr      int // maximum allocated address last byte
 	insts        map[instance.Id]*dummyInstance
 	globalPorts  map[network.PortRange]bool
+	whitelists   map[network.WellKnownServiceType][]string
 	bootstrapped bool
 	storageDelay time.Duration
 	storage      *storageServer
@@ -283,12 +289,22 @@ type environ struct {
 }
 
 var _ environs.Environ = (*environ)(nil)
+var _ environs.FirewallWhitelister = (*environ)(nil)
 
 // discardOperations discards all Operations written to it.
 var discardOperations chan<- Operation
 
 func init() {
-	environs.RegisterProvider("dummy", &providerInstance)
+	environs.Register(environs.ProviderRegistration{
+		Type:       "dummy",
+		Provider:   &providerInstance,
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityNetworking,
+			environs.CapabilityStorage,
+			environs.CapabilityAvailabilityZones,
+		},
+	})
 
 	// Prime the first ops channel, so that naive clients can use
 	// the testing environment by simply importing it.
@@ -378,6 +394,7 @@ func newState(name string, ops chan<- Operation, policy state.Policy) *environSt
 		statePolicy: policy,
 		insts:       make(map[instance.Id]*dummyInstance),
 		globalPorts: make(map[network.PortRange]bool),
+		whitelists:  make(map[network.WellKnownServiceType][]string),
 	}
 	s.storage = newStorageServer(s, "/"+name+"/private")
 	s.listenStorage()
@@ -473,6 +490,7 @@ var configSchema = environschema.Fields{
 	"secret": {
 		Description: "A secret",
 		Type:        environschema.Tstring,
+		Secret:      true,
 	},
 	"state-id": {
 		Description: "Id of state server",
@@ -893,6 +911,17 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	if err := e.checkBroken("StartInstance"); err != nil {
 		return nil, err
 	}
+	// Record the distribution group, as a provider supporting
+	// availability zones would use it to spread instances. It is
+	// evaluated before taking the lock because it may call the API.
+	var group []instance.Id
+	if args.DistributionGroup != nil {
+		var err error
+		group, err = args.DistributionGroup()
+		if err != nil {
+			return nil, err
+		}
+	}
 	estate, err := e.state()
 	if err != nil {
 		return nil, err
@@ -999,19 +1028,20 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	estate.insts[i.id] = i
 	estate.maxId++
 	estate.ops <- OpStartInstance{
-		Env:              e.name,
-		MachineId:        machineId,
-		MachineNonce:     args.InstanceConfig.MachineNonce,
-		PossibleTools:    args.Tools,
-		Constraints:      args.Constraints,
-		SubnetsToZones:   subnetsToZones,
-		Volumes:          volumes,
-		Instance:         i,
-		Jobs:             args.InstanceConfig.Jobs,
-		Info:             args.InstanceConfig.MongoInfo,
-		APIInfo:          args.InstanceConfig.APIInfo,
-		AgentEnvironment: args.InstanceConfig.AgentEnvironment,
-		Secret:           e.ecfg().secret(),
+		Env:               e.name,
+		MachineId:         machineId,
+		MachineNonce:      args.InstanceConfig.MachineNonce,
+		PossibleTools:     args.Tools,
+		Constraints:       args.Constraints,
+		SubnetsToZones:    subnetsToZones,
+		DistributionGroup: group,
+		Volumes:           volumes,
+		Instance:          i,
+		Jobs:              args.InstanceConfig.Jobs,
+		Info:              args.InstanceConfig.MongoInfo,
+		APIInfo:           args.InstanceConfig.APIInfo,
+		AgentEnvironment:  args.InstanceConfig.AgentEnvironment,
+		Secret:            e.ecfg().secret(),
 	}
 	return &environs.StartInstanceResult{
 		Instance: i,
@@ -1381,6 +1411,10 @@ func (e *environ) OpenPorts(ports []network.PortRange) error {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
+	estate.ops <- OpOpenPorts{
+		Env:   e.name,
+		Ports: ports,
+	}
 	for _, p := range ports {
 		estate.globalPorts[p] = true
 	}
@@ -1397,12 +1431,41 @@ func (e *environ) ClosePorts(ports []network.PortRange) error {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
+	estate.ops <- OpClosePorts{
+		Env:   e.name,
+		Ports: ports,
+	}
 	for _, p := range ports {
 		delete(estate.globalPorts, p)
 	}
 	return nil
 }
 
+// SetFirewallWhitelist is specified in the environs.FirewallWhitelister
+// interface.
+func (e *environ) SetFirewallWhitelist(service network.WellKnownServiceType, cidrs []string) error {
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	estate.whitelists[service] = cidrs
+	return nil
+}
+
+// FirewallWhitelist returns the source address ranges last whitelisted
+// for the given service, for tests to inspect.
+func (e *environ) FirewallWhitelist(service network.WellKnownServiceType) ([]string, error) {
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	return estate.whitelists[service], nil
+}
+
 func (e *environ) Ports() (ports []network.PortRange, err error) {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment", mode)
@@ -1428,7 +1491,7 @@ type dummyInstance struct {
 	state        *environState
 	ports        map[network.PortRange]bool
 	id           instance.Id
-	status       string
+	status       instance.InstanceStatus
 	machineId    string
 	series       string
 	firewallMode string
@@ -1442,7 +1505,9 @@ func (inst *dummyInstance) Id() instance.Id {
 	return inst.id
 }
 
-func (inst *dummyInstance) Status() string {
+func (inst *dummyInstance) Status() instance.InstanceStatus {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
 	return inst.status
 }
 
@@ -1459,9 +1524,15 @@ func SetInstanceAddresses(inst instance.Instance, addrs []network.Address) {
 // SetInstanceStatus sets the status associated with the given
 // dummy instance.
 func SetInstanceStatus(inst instance.Instance, status string) {
+	SetInstanceStatusMessage(inst, status, "")
+}
+
+// SetInstanceStatusMessage sets the status and the message
+// elaborating upon it associated with the given dummy instance.
+func SetInstanceStatusMessage(inst instance.Instance, status, message string) {
 	inst0 := inst.(*dummyInstance)
 	inst0.mu.Lock()
-	inst0.status = status
+	inst0.status = instance.InstanceStatus{Status: status, Message: message}
 	inst0.mu.Unlock()
 }
 
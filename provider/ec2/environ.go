@@ -83,6 +83,7 @@ var _ environs.NetworkingEnviron = (*environ)(nil)
 var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
+var _ environs.InstanceTypesFetcher = (*environ)(nil)
 
 type defaultVpc struct {
 	hasDefaultVpc bool
@@ -248,6 +249,7 @@ func (e *environ) SupportsAddressAllocation(_ network.Id) (bool, error) {
 
 var unsupportedConstraints = []string{
 	constraints.Tags,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -267,6 +269,7 @@ func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 		instTypeNames[i] = itype.Name
 	}
 	validator.RegisterVocabulary(constraints.InstanceType, instTypeNames)
+	validator.RegisterVocabulary(constraints.VirtType, []string{hvm, paravirtual})
 	return validator, nil
 }
 
@@ -409,6 +412,16 @@ func (e *environ) Region() (simplestreams.CloudSpec, error) {
 	return e.cloudSpec(e.ecfg().region())
 }
 
+// InstanceTypes is specified in the environs.InstanceTypesFetcher interface.
+func (e *environ) InstanceTypes() ([]instances.InstanceType, error) {
+	itypes, err := regionInstanceTypes(e.ecfg().region())
+	if err != nil {
+		return nil, err
+	}
+	instances.SortByCost(itypes)
+	return itypes, nil
+}
+
 func (e *environ) cloudSpec(region string) (simplestreams.CloudSpec, error) {
 	ec2Region, ok := allRegions[region]
 	if !ok {
@@ -491,6 +504,12 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 		}
 	}
 
+	// If zones= constraints is set, then only use the zones it lists.
+	availabilityZones, err := common.FilterZonesByConstraints(availabilityZones, args.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// If spaces= constraints is also set, then filter availabilityZones to only
 	// contain zones matching the space's subnets' zones
 	if len(args.SubnetsToZones) > 0 {
@@ -1318,19 +1337,25 @@ func (e *environ) jujuGroupName() string {
 // addition, a specific machine security group is created for each
 // machine, so that its firewall rules can be configured per machine.
 func (e *environ) setUpGroups(machineId string, apiPort int) ([]ec2.SecurityGroup, error) {
+	// Keep any whitelists applied by the firewaller to SSH and
+	// the API server.
+	sources, err := e.whitelistedSources(22, apiPort)
+	if err != nil {
+		return nil, err
+	}
 	jujuGroup, err := e.ensureGroup(e.jujuGroupName(),
 		[]ec2.IPPerm{
 			{
 				Protocol:  "tcp",
 				FromPort:  22,
 				ToPort:    22,
-				SourceIPs: []string{"0.0.0.0/0"},
+				SourceIPs: sources[22],
 			},
 			{
 				Protocol:  "tcp",
 				FromPort:  apiPort,
 				ToPort:    apiPort,
-				SourceIPs: []string{"0.0.0.0/0"},
+				SourceIPs: sources[apiPort],
 			},
 			{
 				Protocol: "tcp",
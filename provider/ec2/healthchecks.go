@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"strconv"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+)
+
+var _ environs.HealthChecks = (*environ)(nil)
+
+// HealthChecks is specified in the environs.HealthChecks interface.
+func (e *environ) HealthChecks() []environs.HealthCheck {
+	return []environs.HealthCheck{{
+		Name:  "credentials",
+		Check: func() error { return verifyCredentials(e) },
+	}, {
+		Name:  "endpoint",
+		Check: e.checkEndpoint,
+	}, {
+		Name:  "instance-quota",
+		Check: e.checkInstanceQuota,
+	}}
+}
+
+// checkEndpoint verifies that the EC2 endpoint for the
+// environment's region is reachable and lists availability zones.
+func (e *environ) checkEndpoint() error {
+	zones, err := e.AvailabilityZones()
+	if err != nil {
+		return errors.Annotatef(err, "cannot reach region %q", e.ecfg().region())
+	}
+	if len(zones) == 0 {
+		return errors.Errorf("no availability zones in region %q", e.ecfg().region())
+	}
+	return nil
+}
+
+// checkInstanceQuota verifies that the account may start at least
+// one more instance in the environment's region.
+func (e *environ) checkInstanceQuota() error {
+	resp, err := e.ec2().AccountAttributes("max-instances")
+	if err != nil {
+		return errors.Annotate(err, "cannot get instance limit")
+	}
+	if len(resp.Attributes) == 0 || len(resp.Attributes[0].Values) == 0 {
+		// No limit reported, so nothing to check.
+		return nil
+	}
+	maxInstances, err := strconv.Atoi(resp.Attributes[0].Values[0])
+	if err != nil {
+		return errors.Annotate(err, "invalid instance limit")
+	}
+
+	filter := ec2.NewFilter()
+	filter.Add("instance-state-name", "pending", "running")
+	instResp, err := e.ec2().Instances(nil, filter)
+	if err != nil {
+		return errors.Annotate(err, "cannot count instances")
+	}
+	running := 0
+	for _, r := range instResp.Reservations {
+		running += len(r.Instances)
+	}
+	if running >= maxInstances {
+		return errors.Errorf("instance limit reached: %d of %d instances in use", running, maxInstances)
+	}
+	return nil
+}
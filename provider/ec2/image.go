@@ -70,10 +70,20 @@ func findInstanceSpec(
 	suitableImages := filterImages(matchingImages, ic)
 	images := instances.ImageMetadataToImages(suitableImages)
 
-	// Make a copy of the known EC2 instance types, filling in the cost for the specified region.
-	regionCosts := allRegionCosts[ic.Region]
+	itypesWithCosts, err := regionInstanceTypes(ic.Region)
+	if err != nil {
+		return nil, err
+	}
+	return instances.FindInstanceSpec(images, ic, itypesWithCosts)
+}
+
+// regionInstanceTypes returns a copy of the known EC2 instance types
+// available in the specified region, with the cost for that region
+// filled in.
+func regionInstanceTypes(region string) ([]instances.InstanceType, error) {
+	regionCosts := allRegionCosts[region]
 	if len(regionCosts) == 0 && len(allRegionCosts) > 0 {
-		return nil, fmt.Errorf("no instance types found in %s", ic.Region)
+		return nil, fmt.Errorf("no instance types found in %s", region)
 	}
 
 	var itypesWithCosts []instances.InstanceType
@@ -86,5 +96,5 @@ func findInstanceSpec(
 		itWithCost.Cost = cost
 		itypesWithCosts = append(itypesWithCosts, itWithCost)
 	}
-	return instances.FindInstanceSpec(images, ic, itypesWithCosts)
+	return itypesWithCosts, nil
 }
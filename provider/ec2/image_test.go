@@ -113,6 +113,12 @@ var findInstanceSpecTests = []struct {
 		cons:   "instance-type=cc2.8xlarge",
 		itype:  "cc2.8xlarge",
 		image:  "ami-01000035",
+	}, {
+		series: testing.FakeDefaultSeries,
+		arches: both,
+		cons:   "virt-type=hvm",
+		itype:  "cc2.8xlarge",
+		image:  "ami-00000035",
 	}, {
 		series: testing.FakeDefaultSeries,
 		arches: []string{"i386"},
@@ -220,6 +226,11 @@ var findInstanceSpecErrorTests = []struct {
 		arches: both,
 		cons:   "instance-type=m1.small mem=4G",
 		err:    `no instance types in test matching constraints "instance-type=m1.small mem=4096M"`,
+	}, {
+		series: testing.FakeDefaultSeries,
+		arches: both,
+		cons:   "instance-type=cc2.8xlarge virt-type=pv",
+		err:    `no instance types in test matching constraints "instance-type=cc2.8xlarge virt-type=pv"`,
 	},
 }
 
@@ -13,7 +13,16 @@ const (
 )
 
 func init() {
-	environs.RegisterProvider(providerType, environProvider{})
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Provider:   environProvider{},
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityNetworking,
+			environs.CapabilityStorage,
+			environs.CapabilityAvailabilityZones,
+		},
+	})
 
 	//Register the AWS specific providers.
 	registry.RegisterProvider(EBS_ProviderType, &ebsProvider{})
@@ -309,7 +309,7 @@ func (t *localServerSuite) TestInstanceStatus(c *gc.C) {
 	t.srv.ec2srv.SetInitialInstanceState(ec2test.Terminated)
 	inst, _ := testing.AssertStartInstance(c, env, "1")
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(inst.Status(), gc.Equals, "terminated")
+	c.Assert(inst.Status().Status, gc.Equals, "terminated")
 }
 
 func (t *localServerSuite) TestStartInstanceHardwareCharacteristics(c *gc.C) {
@@ -483,6 +483,35 @@ func (t *localServerSuite) TestStartInstanceDistribution(c *gc.C) {
 	c.Assert(ec2.InstanceEC2(inst).AvailZone, gc.Equals, "test-available")
 }
 
+func (t *localServerSuite) TestStartInstanceZonesConstraint(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := environs.StartInstanceParams{
+		Constraints: constraints.MustParse("zones=test-available,test-unknown"),
+	}
+	result, err := testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ec2.InstanceEC2(result.Instance).AvailZone, gc.Equals, "test-available")
+}
+
+func (t *localServerSuite) TestStartInstanceZonesConstraintNoMatch(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := environs.StartInstanceParams{
+		Constraints: constraints.MustParse("zones=test-impaired"),
+	}
+	_, err = testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, `no availability zones match constraint "zones=test-impaired"`)
+
+	params.Placement = "zone=test-available"
+	_, err = testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, `no availability zones match constraint "zones=test-impaired"`)
+}
+
 var azConstrainedErr = &amzec2.Error{
 	Code:    "Unsupported",
 	Message: "The requested Availability Zone is currently constrained etc.",
@@ -683,10 +712,10 @@ func (t *localServerSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	env := t.Prepare(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 tags=foo")
+	cons := constraints.MustParse("arch=amd64 tags=foo root-disk-source=ssd")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, gc.DeepEquals, []string{"tags"})
+	c.Assert(unsupported, jc.SameContents, []string{"root-disk-source", "tags"})
 }
 
 func (t *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -699,6 +728,9 @@ func (t *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	cons = constraints.MustParse("instance-type=foo")
 	_, err = validator.Validate(cons)
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: instance-type=foo\nvalid values are:.*")
+	cons = constraints.MustParse("virt-type=kvm")
+	_, err = validator.Validate(cons)
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: virt-type=kvm\nvalid values are:.*")
 }
 
 func (t *localServerSuite) TestConstraintsMerge(c *gc.C) {
@@ -787,6 +819,23 @@ func (t *localServerSuite) TestSupportedArchitectures(c *gc.C) {
 	c.Assert(a, jc.SameContents, []string{"amd64", "i386"})
 }
 
+func (t *localServerSuite) TestInstanceTypes(c *gc.C) {
+	env := t.Prepare(c)
+	fetcher, ok := environs.SupportsInstanceTypes(env)
+	c.Assert(ok, jc.IsTrue)
+	itypes, err := fetcher.InstanceTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(itypes, gc.Not(gc.HasLen), 0)
+	for i, itype := range itypes {
+		cost, ok := ec2.TestInstanceTypeCosts[itype.Name]
+		c.Check(ok, jc.IsTrue)
+		c.Check(itype.Cost, gc.Equals, cost)
+		if i > 0 {
+			c.Check(itype.Cost >= itypes[i-1].Cost, jc.IsTrue)
+		}
+	}
+}
+
 func (t *localServerSuite) TestSupportsNetworking(c *gc.C) {
 	env := t.Prepare(c)
 	_, supported := environs.SupportsNetworking(env)
@@ -1096,6 +1145,37 @@ func (t *localServerSuite) TestSupportsAddressAllocationFalse(c *gc.C) {
 	c.Assert(result, jc.IsFalse)
 }
 
+func (t *localServerSuite) runHealthChecks(c *gc.C, env environs.Environ) map[string]error {
+	checker, ok := environs.SupportsHealthChecks(env)
+	c.Assert(ok, jc.IsTrue)
+	results := make(map[string]error)
+	for _, check := range checker.HealthChecks() {
+		results[check.Name] = check.Check()
+	}
+	return results
+}
+
+func (t *localServerSuite) TestHealthChecks(c *gc.C) {
+	t.srv.ec2srv.SetAccountAttributes(map[string][]string{
+		"max-instances": {"20"},
+	})
+	env := t.Prepare(c)
+	results := t.runHealthChecks(c, env)
+	c.Assert(results, gc.HasLen, 3)
+	for name, err := range results {
+		c.Check(err, jc.ErrorIsNil, gc.Commentf("check %q", name))
+	}
+}
+
+func (t *localServerSuite) TestHealthChecksInstanceQuotaReached(c *gc.C) {
+	t.srv.ec2srv.SetAccountAttributes(map[string][]string{
+		"max-instances": {"0"},
+	})
+	env := t.Prepare(c)
+	results := t.runHealthChecks(c, env)
+	c.Assert(results["instance-quota"], gc.ErrorMatches, "instance limit reached: 0 of 0 instances in use")
+}
+
 func (t *localServerSuite) TestInstanceTags(c *gc.C) {
 	env := t.Prepare(c)
 	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
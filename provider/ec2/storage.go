@@ -68,6 +68,44 @@ func (s *ec2storage) Put(file string, r io.Reader, length int64) error {
 	return nil
 }
 
+var _ storage.MultipartStorageWriter = (*ec2storage)(nil)
+
+// PutMultipart is specified in the MultipartStorageWriter interface.
+// The file is written with an S3 multipart upload, which is aborted
+// if any part cannot be written.
+func (s *ec2storage) PutMultipart(file string, r io.ReaderAt, length, partSize int64) (err error) {
+	if err := s.makeBucket(); err != nil {
+		return fmt.Errorf("cannot make S3 control bucket: %v", err)
+	}
+	multi, err := s.bucket.InitMulti(file, "binary/octet-stream", s3.Private)
+	if err != nil {
+		return fmt.Errorf("cannot start upload of file %q to control bucket: %v", file, err)
+	}
+	defer func() {
+		if err != nil {
+			if abortErr := multi.Abort(); abortErr != nil {
+				logger.Warningf("cannot abort upload of file %q: %v", file, abortErr)
+			}
+		}
+	}()
+	var parts []s3.Part
+	for offset := int64(0); offset < length; offset += partSize {
+		size := partSize
+		if length-offset < size {
+			size = length - offset
+		}
+		part, err := multi.PutPart(len(parts)+1, io.NewSectionReader(r, offset, size))
+		if err != nil {
+			return fmt.Errorf("cannot write part %d of file %q to control bucket: %v", len(parts)+1, file, err)
+		}
+		parts = append(parts, part)
+	}
+	if err := multi.Complete(parts); err != nil {
+		return fmt.Errorf("cannot complete upload of file %q to control bucket: %v", file, err)
+	}
+	return nil
+}
+
 func (s *ec2storage) Get(file string) (r io.ReadCloser, err error) {
 	r, err = s.bucket.GetReader(file)
 	return r, maybeNotFound(err)
@@ -0,0 +1,105 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/network"
+)
+
+var _ environs.FirewallWhitelister = (*environ)(nil)
+
+// anywhere is the source address range allowed to reach well-known
+// services when they are not restricted to a whitelist.
+var anywhere = []string{"0.0.0.0/0"}
+
+// wellKnownServicePort returns the TCP port of the given well-known
+// service.
+func (e *environ) wellKnownServicePort(service network.WellKnownServiceType) (int, error) {
+	switch service {
+	case network.SSHRule:
+		return 22, nil
+	case network.JujuControllerRule:
+		return e.Config().APIPort(), nil
+	}
+	return 0, errors.NotValidf("well known service type %q", string(service))
+}
+
+// SetFirewallWhitelist is specified in the environs.FirewallWhitelister
+// interface. The whitelist is applied to the environment-wide juju
+// security group, which all machines belong to.
+func (e *environ) SetFirewallWhitelist(service network.WellKnownServiceType, cidrs []string) error {
+	port, err := e.wellKnownServicePort(service)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(cidrs) == 0 {
+		cidrs = anywhere
+	}
+	group, err := e.groupInfoByName(e.jujuGroupName())
+	if err != nil {
+		return errors.Annotate(err, "cannot get juju security group")
+	}
+	have := set.NewStrings(sourcesForPort(group.IPPerms, port)...)
+	want := set.NewStrings(cidrs...)
+	perm := func(sources set.Strings) []ec2.IPPerm {
+		return []ec2.IPPerm{{
+			Protocol:  "tcp",
+			FromPort:  port,
+			ToPort:    port,
+			SourceIPs: sources.SortedValues(),
+		}}
+	}
+	// Authorize the new sources before revoking the old ones, so
+	// that access is never lost altogether.
+	if add := want.Difference(have); !add.IsEmpty() {
+		if _, err := e.ec2().AuthorizeSecurityGroup(group.SecurityGroup, perm(add)); err != nil {
+			return errors.Annotatef(err, "cannot authorize %v to access port %d", add.SortedValues(), port)
+		}
+	}
+	if revoke := have.Difference(want); !revoke.IsEmpty() {
+		if _, err := e.ec2().RevokeSecurityGroup(group.SecurityGroup, perm(revoke)); err != nil {
+			return errors.Annotatef(err, "cannot revoke access to port %d from %v", port, revoke.SortedValues())
+		}
+	}
+	return nil
+}
+
+// whitelistedSources returns the source address ranges the juju
+// security group allows to reach each of the given TCP ports. Ports
+// are reachable from anywhere if the group does not exist yet or
+// allows no address range to reach them.
+func (e *environ) whitelistedSources(ports ...int) (map[int][]string, error) {
+	var perms []ec2.IPPerm
+	group, err := e.groupInfoByName(e.jujuGroupName())
+	if err == nil {
+		perms = group.IPPerms
+	} else if ec2ErrCode(err) != "InvalidGroup.NotFound" {
+		return nil, errors.Annotate(err, "cannot get juju security group")
+	}
+	sources := make(map[int][]string)
+	for _, port := range ports {
+		sources[port] = sourcesForPort(perms, port)
+		if len(sources[port]) == 0 {
+			sources[port] = anywhere
+		}
+	}
+	return sources, nil
+}
+
+// sourcesForPort returns the source address ranges the given
+// permissions allow to reach the given TCP port.
+func sourcesForPort(perms []ec2.IPPerm, port int) []string {
+	var sources []string
+	for _, p := range perms {
+		if p.Protocol == "tcp" && p.FromPort == port && p.ToPort == port {
+			sources = append(sources, p.SourceIPs...)
+		}
+	}
+	return sources
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2_test
+
+import (
+	"sort"
+
+	jc "github.com/juju/testing/checkers"
+	amzec2 "gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/bootstrap"
+	envtesting "github.com/juju/juju/environs/testing"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/ec2"
+)
+
+// bootstrapWhitelister bootstraps a new environment and returns it
+// along with its firewall whitelister.
+func (t *localServerSuite) bootstrapWhitelister(c *gc.C) (environs.Environ, environs.FirewallWhitelister) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	whitelister, ok := environs.SupportsFirewallWhitelists(env)
+	c.Assert(ok, jc.IsTrue)
+	return env, whitelister
+}
+
+// jujuGroupSources returns the source address ranges the environment's
+// juju security group allows to reach the given TCP port.
+func jujuGroupSources(c *gc.C, env environs.Environ, port int) []string {
+	resp, err := ec2.EnvironEC2(env).SecurityGroups(amzec2.SecurityGroupNames(ec2.JujuGroupName(env)), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Groups, gc.HasLen, 1)
+	var sources []string
+	for _, perm := range resp.Groups[0].IPPerms {
+		if perm.Protocol == "tcp" && perm.FromPort == port && perm.ToPort == port {
+			sources = append(sources, perm.SourceIPs...)
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+func (t *localServerSuite) TestSetFirewallWhitelistSSH(c *gc.C) {
+	env, whitelister := t.bootstrapWhitelister(c)
+	c.Assert(jujuGroupSources(c, env, 22), jc.DeepEquals, []string{"0.0.0.0/0"})
+
+	err := whitelister.SetFirewallWhitelist(network.SSHRule, []string{"10.0.0.0/8", "192.168.0.0/16"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(jujuGroupSources(c, env, 22), jc.DeepEquals, []string{"10.0.0.0/8", "192.168.0.0/16"})
+
+	err = whitelister.SetFirewallWhitelist(network.SSHRule, []string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(jujuGroupSources(c, env, 22), jc.DeepEquals, []string{"10.0.0.0/8"})
+}
+
+func (t *localServerSuite) TestSetFirewallWhitelistEmptyAllowsAnywhere(c *gc.C) {
+	env, whitelister := t.bootstrapWhitelister(c)
+	err := whitelister.SetFirewallWhitelist(network.SSHRule, []string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = whitelister.SetFirewallWhitelist(network.SSHRule, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(jujuGroupSources(c, env, 22), jc.DeepEquals, []string{"0.0.0.0/0"})
+}
+
+func (t *localServerSuite) TestSetFirewallWhitelistAPIServer(c *gc.C) {
+	env, whitelister := t.bootstrapWhitelister(c)
+	apiPort := env.Config().APIPort()
+
+	err := whitelister.SetFirewallWhitelist(network.JujuControllerRule, []string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(jujuGroupSources(c, env, apiPort), jc.DeepEquals, []string{"10.0.0.0/8"})
+	// SSH is left alone.
+	c.Assert(jujuGroupSources(c, env, 22), jc.DeepEquals, []string{"0.0.0.0/0"})
+}
+
+func (t *localServerSuite) TestSetFirewallWhitelistInvalidService(c *gc.C) {
+	_, whitelister := t.bootstrapWhitelister(c)
+	err := whitelister.SetFirewallWhitelist("foo", []string{"10.0.0.0/8"})
+	c.Assert(err, gc.ErrorMatches, `well known service type "foo" not valid`)
+}
+
+func (t *localServerSuite) TestFirewallWhitelistKeptForNewMachines(c *gc.C) {
+	env, whitelister := t.bootstrapWhitelister(c)
+	err := whitelister.SetFirewallWhitelist(network.SSHRule, []string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Starting a machine sets up the juju group again, but must
+	// not undo the whitelist.
+	testing.AssertStartInstance(c, env, "1")
+	c.Assert(jujuGroupSources(c, env, 22), jc.DeepEquals, []string{"10.0.0.0/8"})
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/provider/gce/google"
@@ -74,17 +75,50 @@ gce:
   # image-endpoint: https://www.googleapis.com
 `[1:]
 
-// configFields is the spec for each GCE config value's type.
-var configFields = schema.Fields{
-	cfgAuthFile:      schema.String(),
-	cfgPrivateKey:    schema.String(),
-	cfgClientID:      schema.String(),
-	cfgClientEmail:   schema.String(),
-	cfgRegion:        schema.String(),
-	cfgProjectID:     schema.String(),
-	cfgImageEndpoint: schema.String(),
+// configSchema describes each GCE config value.
+var configSchema = environschema.Fields{
+	cfgAuthFile: {
+		Description: "The path to a JSON file holding the GCE credentials, as downloaded from the developer console.",
+		Type:        environschema.Tstring,
+	},
+	cfgPrivateKey: {
+		Description: "The private key of the GCE service account.",
+		Type:        environschema.Tstring,
+		Secret:      true,
+	},
+	cfgClientID: {
+		Description: "The client ID of the GCE service account.",
+		Type:        environschema.Tstring,
+	},
+	cfgClientEmail: {
+		Description: "The client email address of the GCE service account.",
+		Type:        environschema.Tstring,
+	},
+	cfgRegion: {
+		Description: "The GCE region in which to provision instances.",
+		Type:        environschema.Tstring,
+		Example:     "us-central1",
+	},
+	cfgProjectID: {
+		Description: "The ID of the GCE project to use.",
+		Type:        environschema.Tstring,
+	},
+	cfgImageEndpoint: {
+		Description: "The endpoint from which to fetch the images used to provision instances.",
+		Type:        environschema.Tstring,
+		Example:     "https://www.googleapis.com",
+	},
 }
 
+// configFields is the spec for each GCE config value's type.
+var configFields = func() schema.Fields {
+	fs, _, err := configSchema.ValidationSchema()
+	if err != nil {
+		panic(err)
+	}
+	return fs
+}()
+
 // TODO(ericsnow) Do we need custom defaults for "image-metadata-url" or
 // "agent-metadata-url"? The defaults are the official ones (e.g.
 // cloud-images).
@@ -96,9 +130,17 @@ var configDefaults = schema.Defaults{
 	cfgRegion:        "us-central1",
 }
 
-var configSecretFields = []string{
-	cfgPrivateKey,
-}
+// configSecretFields lists the config values marked as secret in
+// configSchema.
+var configSecretFields = func() []string {
+	var fields []string
+	for name, attr := range configSchema {
+		if attr.Secret {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}()
 
 var configImmutableFields = []string{
 	cfgAuthFile,
@@ -276,6 +318,15 @@ func (c environConfig) validate() error {
 		return errors.Trace(handleInvalidField(err))
 	}
 
+	// Resource tags are recorded as instance metadata, so they must
+	// not override the metadata GCE itself acts on.
+	resourceTags, _ := c.ResourceTags()
+	for key := range resourceTags {
+		if reservedMetadataKeys.Contains(key) {
+			return errors.Errorf("%s: %q is a reserved GCE metadata key", config.ResourceTagsKey, key)
+		}
+	}
+
 	return nil
 }
 
@@ -241,6 +241,10 @@ var newConfigTests = []configTestSpec{{
 	info:   "image-endpoint cannot be empty",
 	insert: testing.Attrs{"image-endpoint": ""},
 	err:    "image-endpoint: must not be empty",
+}, {
+	info:   "resource-tags cannot use reserved metadata keys",
+	insert: testing.Attrs{"resource-tags": "owner=me startup-script=evil"},
+	err:    `resource-tags: "startup-script" is a reserved GCE metadata key`,
 }, {
 	info:   "unknown field is not touched",
 	insert: testing.Attrs{"unknown-field": 12345},
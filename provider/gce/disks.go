@@ -5,6 +5,7 @@ package gce
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -199,6 +200,7 @@ func (v *volumeSource) createOneVolume(p storage.VolumeParams, instances instanc
 		SizeHintGB:         mibToGib(p.Size),
 		Name:               volumeName,
 		PersistentDiskType: persistentType,
+		Description:        tagsDescription(p.ResourceTags),
 	}
 
 	gceDisks, err := v.gce.CreateDisks(zone, []google.DiskSpec{disk})
@@ -209,7 +211,6 @@ func (v *volumeSource) createOneVolume(p storage.VolumeParams, instances instanc
 		return nil, nil, errors.New(fmt.Sprintf("unexpected number of disks created: %d", len(gceDisks)))
 	}
 	gceDisk := gceDisks[0]
-	// TODO(perrito666) Tag, there are no tags in gce, how do we fix it?
 
 	attachedDisk, err := v.attachOneVolume(gceDisk.Name, google.ModeRW, inst.ID)
 	if err != nil {
@@ -396,3 +397,21 @@ func (v *volumeSource) detachOneVolume(attachParam storage.VolumeAttachmentParam
 	}
 	return v.gce.DetachDisk(zone, string(instId), volumeName)
 }
+
+// tagsDescription returns a disk description recording the given
+// resource tags, as GCE disks have no key/value tags.
+func tagsDescription(resourceTags map[string]string) string {
+	if len(resourceTags) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(resourceTags))
+	for key := range resourceTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + resourceTags[key]
+	}
+	return "tags: " + strings.Join(pairs, " ")
+}
@@ -96,6 +96,10 @@ func (s *volumeSourceSuite) SetUpTest(c *gc.C) {
 		Size:       1024,
 		Provider:   "gce",
 		Attachment: s.attachmentParams,
+		ResourceTags: map[string]string{
+			"juju-env-uuid": "some-uuid",
+			"cost-centre":   "accounting",
+		},
 	}}
 
 }
@@ -149,6 +153,7 @@ func (s *volumeSourceSuite) TestCreateVolumes(c *gc.C) {
 	c.Assert(createCalled, jc.IsTrue)
 	c.Assert(call[0].ZoneName, gc.Equals, "home-zone")
 	c.Assert(call[0].Disks[0].Name, jc.HasPrefix, "home-zone--")
+	c.Assert(call[0].Disks[0].Description, gc.Equals, "tags: cost-centre=accounting juju-env-uuid=some-uuid")
 
 	// Instance existence Checking
 	instanceDisksCalled, call := s.FakeConn.WasCalled("InstanceDisks")
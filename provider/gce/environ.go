@@ -18,6 +18,7 @@ import (
 
 type gceConnection interface {
 	VerifyCredentials() error
+	ProjectQuotas() ([]google.Quota, error)
 
 	// Instance gets the up-to-date info about the given instance
 	// and returns it.
@@ -93,7 +93,8 @@ func (env *environ) parseAvailabilityZones(args environs.StartInstanceParams) ([
 			return nil, errors.Trace(err)
 		}
 		// TODO(ericsnow) Fail if placement.Zone is not in the env's configured region?
-		return []string{placement.Zone.Name()}, nil
+		zoneNames, err := common.FilterZonesByConstraints([]string{placement.Zone.Name()}, args.Constraints)
+		return zoneNames, errors.Trace(err)
 	}
 
 	// If no availability zone is specified, then automatically spread across
@@ -122,5 +123,10 @@ func (env *environ) parseAvailabilityZones(args environs.StartInstanceParams) ([
 		return nil, errors.NotFoundf("failed to determine availability zones")
 	}
 
+	// If zones= constraints is set, then only use the zones it lists.
+	zoneNames, err = common.FilterZonesByConstraints(zoneNames, args.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return zoneNames, nil
 }
@@ -5,6 +5,7 @@ package gce
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
@@ -18,6 +19,7 @@ import (
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
@@ -217,6 +219,14 @@ func getMetadata(args environs.StartInstanceParams, os jujuos.OSType) (map[strin
 	logger.Debugf("GCE user data; %d bytes", len(userData))
 
 	metadata := make(map[string]string)
+	// GCE instances have no key/value tags, so the environment's
+	// resource tags are recorded as metadata instead. Juju's own tags
+	// are left out, as they are already recorded below.
+	for k, v := range args.InstanceConfig.Tags {
+		if !strings.HasPrefix(k, tags.JujuTagPrefix) {
+			metadata[metadataKeyResourceTagPrefix+k] = v
+		}
+	}
 	if isStateServer(args.InstanceConfig) {
 		metadata[metadataKeyIsState] = metadataValueTrue
 	} else {
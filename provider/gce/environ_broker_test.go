@@ -178,6 +178,22 @@ func (s *environBrokerSuite) TestGetMetadataUbuntu(c *gc.C) {
 
 }
 
+func (s *environBrokerSuite) TestGetMetadataResourceTags(c *gc.C) {
+	s.StartInstArgs.InstanceConfig.Tags = map[string]string{
+		"juju-env-uuid": "some-uuid",
+		"cost-centre":   "accounting",
+		"juju-is-state": "false",
+	}
+	metadata, err := gce.GetMetadata(s.StartInstArgs, jujuos.Ubuntu)
+
+	c.Assert(err, jc.ErrorIsNil)
+	expected := map[string]string{"resource-tag-cost-centre": "accounting"}
+	for k, v := range s.UbuntuMetadata {
+		expected[k] = v
+	}
+	c.Check(metadata, gc.DeepEquals, expected)
+}
+
 func (s *environBrokerSuite) TestGetMetadataWindows(c *gc.C) {
 	metadata, err := gce.GetMetadata(s.StartInstArgs, jujuos.Windows)
 
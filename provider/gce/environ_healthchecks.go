@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+)
+
+var _ environs.HealthChecks = (*environ)(nil)
+
+// HealthChecks is specified in the environs.HealthChecks interface.
+func (env *environ) HealthChecks() []environs.HealthCheck {
+	return []environs.HealthCheck{{
+		Name:  "credentials",
+		Check: env.gce.VerifyCredentials,
+	}, {
+		Name:  "endpoint",
+		Check: env.checkEndpoint,
+	}, {
+		Name:  "quota",
+		Check: env.checkQuotas,
+	}}
+}
+
+// checkEndpoint verifies that the GCE API lists availability zones
+// for the environment's region.
+func (env *environ) checkEndpoint() error {
+	zones, err := env.AvailabilityZones()
+	if err != nil {
+		return errors.Annotatef(err, "cannot reach region %q", env.ecfg.region())
+	}
+	if len(zones) == 0 {
+		return errors.Errorf("no availability zones in region %q", env.ecfg.region())
+	}
+	return nil
+}
+
+// checkQuotas verifies that none of the project's resource quotas
+// are exhausted.
+func (env *environ) checkQuotas() error {
+	quotas, err := env.gce.ProjectQuotas()
+	if err != nil {
+		return errors.Annotate(err, "cannot get project quotas")
+	}
+	var exhausted []string
+	for _, q := range quotas {
+		if q.Limit > 0 && q.Usage >= q.Limit {
+			exhausted = append(exhausted, fmt.Sprintf("%s (%g of %g)", q.Metric, q.Usage, q.Limit))
+		}
+	}
+	if len(exhausted) > 0 {
+		return errors.Errorf("quota exhausted: %s", strings.Join(exhausted, ", "))
+	}
+	return nil
+}
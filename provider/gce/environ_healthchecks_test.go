@@ -0,0 +1,78 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
+)
+
+type environHealthChecksSuite struct {
+	gce.BaseSuite
+}
+
+var _ = gc.Suite(&environHealthChecksSuite{})
+
+func (s *environHealthChecksSuite) check(c *gc.C, name string) error {
+	for _, check := range s.Env.HealthChecks() {
+		if check.Name == name {
+			return check.Check()
+		}
+	}
+	c.Fatalf("no %q check", name)
+	return nil
+}
+
+func (s *environHealthChecksSuite) TestHealthChecks(c *gc.C) {
+	var names []string
+	for _, check := range s.Env.HealthChecks() {
+		names = append(names, check.Name)
+	}
+	c.Check(names, jc.DeepEquals, []string{"credentials", "endpoint", "quota"})
+
+	_, ok := environs.SupportsHealthChecks(s.Env)
+	c.Check(ok, jc.IsTrue)
+}
+
+func (s *environHealthChecksSuite) TestCredentialsFailed(c *gc.C) {
+	failure := errors.New("<unknown>")
+	s.FakeConn.Err = failure
+
+	err := s.check(c, "credentials")
+	c.Check(errors.Cause(err), gc.Equals, failure)
+}
+
+func (s *environHealthChecksSuite) TestEndpointNoZones(c *gc.C) {
+	err := s.check(c, "endpoint")
+	c.Check(err, gc.ErrorMatches, `no availability zones in region ".*"`)
+}
+
+func (s *environHealthChecksSuite) TestQuotaHeadroom(c *gc.C) {
+	s.FakeConn.Quotas = []google.Quota{{
+		Metric: "FIREWALLS",
+		Limit:  100,
+		Usage:  3,
+	}}
+	err := s.check(c, "quota")
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *environHealthChecksSuite) TestQuotaExhausted(c *gc.C) {
+	s.FakeConn.Quotas = []google.Quota{{
+		Metric: "FIREWALLS",
+		Limit:  100,
+		Usage:  3,
+	}, {
+		Metric: "STATIC_ADDRESSES",
+		Limit:  8,
+		Usage:  8,
+	}}
+	err := s.check(c, "quota")
+	c.Check(err, gc.ErrorMatches, `quota exhausted: STATIC_ADDRESSES \(8 of 8\)`)
+}
@@ -186,3 +186,14 @@ func (s *environInstSuite) TestCheckInstanceTypeUnknown(c *gc.C) {
 
 	c.Check(matched, jc.IsFalse)
 }
+
+func (s *environInstSuite) TestInstanceTypes(c *gc.C) {
+	itypes, err := s.Env.InstanceTypes()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(itypes, gc.Not(gc.HasLen), 0)
+	c.Check(itypes[0].Name, gc.Equals, "f1-micro")
+	for i := 1; i < len(itypes); i++ {
+		c.Check(itypes[i].Mem >= itypes[i-1].Mem, jc.IsTrue)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/instances"
+)
+
+var _ environs.InstanceTypesFetcher = (*environ)(nil)
+
+// InstanceTypes is specified in the environs.InstanceTypesFetcher
+// interface. GCE machine types are the same in every zone, and their
+// costs are not known, so they are ordered by size.
+func (env *environ) InstanceTypes() ([]instances.InstanceType, error) {
+	itypes := make([]instances.InstanceType, len(allInstanceTypes))
+	copy(itypes, allInstanceTypes)
+	instances.SortByCost(itypes)
+	return itypes, nil
+}
@@ -68,6 +68,7 @@ var unsupportedConstraints = []string{
 	constraints.Tags,
 	// TODO(dimitern: Replace Networks with Spaces in a follow-up.
 	constraints.Networks,
+	constraints.RootDiskSource,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -112,6 +113,7 @@ func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 	validator.RegisterVocabulary(constraints.InstanceType, instTypeNames)
 
 	validator.RegisterVocabulary(constraints.Container, []string{vtype})
+	validator.RegisterVocabulary(constraints.VirtType, []string{vtype})
 
 	return validator, nil
 }
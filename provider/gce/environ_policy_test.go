@@ -163,11 +163,11 @@ func (s *environPolSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	validator, err := s.Env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
 
-	cons := constraints.MustParse("arch=amd64 tags=foo")
+	cons := constraints.MustParse("arch=amd64 tags=foo root-disk-source=ssd")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(unsupported, jc.DeepEquals, []string{"tags"})
+	c.Check(unsupported, jc.SameContents, []string{"root-disk-source", "tags"})
 }
 
 func (s *environPolSuite) TestConstraintsValidatorVocabArch(c *gc.C) {
@@ -6,6 +6,7 @@ package gce
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/environs/tags"
 )
@@ -24,6 +25,27 @@ const (
 	// GCE uses this specific key for authentication (*handwaving*)
 	// https://cloud.google.com/compute/docs/instances#sshkeys
 	metadataKeySSHKeys = "sshKeys"
+	// The environment's resource tags are recorded in the instance
+	// metadata under keys with this prefix, as GCE instances have no
+	// key/value tags. The prefix keeps them apart from the keys above.
+	metadataKeyResourceTagPrefix = "resource-tag-"
+)
+
+// reservedMetadataKeys holds the instance metadata keys that GCE, its
+// guest environment or cloud-init give meaning to. They may not be
+// used as resource tags.
+var reservedMetadataKeys = set.NewStrings(
+	"startup-script",
+	"startup-script-url",
+	"shutdown-script",
+	"shutdown-script-url",
+	"ssh-keys",
+	"block-project-ssh-keys",
+	metadataKeySSHKeys,
+	metadataKeyCloudInit,
+	metadataKeyEncoding,
+	metadataKeyWindowsUserdata,
+	metadataKeyWindowsSysprep,
 )
 
 // Common metadata values used when creating new instances.
@@ -124,6 +124,32 @@ func (gc Connection) VerifyCredentials() error {
 	return nil
 }
 
+// Quota describes the limit on, and current usage of, one kind of
+// resource in a GCE project.
+type Quota struct {
+	Metric string
+	Limit  float64
+	Usage  float64
+}
+
+// ProjectQuotas returns the resource quotas of the project defined
+// for the Connection.
+func (gc Connection) ProjectQuotas() ([]Quota, error) {
+	project, err := gc.raw.GetProject(gc.projectID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var quotas []Quota
+	for _, q := range project.Quotas {
+		quotas = append(quotas, Quota{
+			Metric: q.Metric,
+			Limit:  q.Limit,
+			Usage:  q.Usage,
+		})
+	}
+	return quotas, nil
+}
+
 // AvailabilityZones returns the list of availability zones for a given
 // GCE region. If none are found the the list is empty. Any failure in
 // the low-level request is returned as an error.
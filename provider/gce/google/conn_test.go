@@ -57,6 +57,26 @@ func (s *connSuite) TestConnectionVerifyCredentialsInvalid(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `retrieving auth token for user@mail.com: Invalid Key`)
 }
 
+func (s *connSuite) TestConnectionProjectQuotas(c *gc.C) {
+	s.FakeConn.Project = &compute.Project{
+		Quotas: []*compute.Quota{{
+			Metric: "FIREWALLS",
+			Limit:  100,
+			Usage:  3,
+		}},
+	}
+	quotas, err := s.Conn.ProjectQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(quotas, jc.DeepEquals, []google.Quota{{
+		Metric: "FIREWALLS",
+		Limit:  100,
+		Usage:  3,
+	}})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetProject")
+}
+
 func (s *connSuite) TestConnectionAvailabilityZones(c *gc.C) {
 	s.FakeConn.Zones = []*compute.Zone{{
 		Name:   "a-zone",
@@ -106,6 +106,10 @@ type DiskSpec struct {
 	// characters must be a dash, lowercase letter, or digit, except the
 	// last character, which cannot be a dash.
 	Name string
+	// Description is the optional description of the disk. As GCE
+	// disks have no key/value tags, it is used to record the tags of
+	// detached disks. (detached only)
+	Description string
 }
 
 // TooSmall checks the spec's size hint and indicates whether or not
@@ -169,6 +173,7 @@ func (ds *DiskSpec) newDetached() (*compute.Disk, error) {
 	}
 	return &compute.Disk{
 		Name:        ds.Name,
+		Description: ds.Description,
 		SizeGb:      int64(ds.SizeGB()),
 		SourceImage: ds.ImageURL,
 		Type:        string(ds.PersistentDiskType),
@@ -13,7 +13,15 @@ const (
 )
 
 func init() {
-	environs.RegisterProvider(providerType, providerInstance)
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Provider:   providerInstance,
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityStorage,
+			environs.CapabilityAvailabilityZones,
+		},
+	})
 
 	// Register the GCE specific providers.
 	registry.RegisterProvider(storageProviderType, &storageProvider{})
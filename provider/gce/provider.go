@@ -5,6 +5,7 @@ package gce
 
 import (
 	"github.com/juju/errors"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -106,6 +107,15 @@ func (environProvider) SecretAttrs(cfg *config.Config) (map[string]string, error
 	return ecfg.secret(), nil
 }
 
+// Schema implements environs.ProviderSchema.
+func (environProvider) Schema() environschema.Fields {
+	fields, err := config.Schema(configSchema)
+	if err != nil {
+		panic(err)
+	}
+	return fields
+}
+
 // BoilerplateConfig implements environs.EnvironProvider.
 func (environProvider) BoilerplateConfig() string {
 	// boilerplateConfig is kept in config.go, in the hope that people editing
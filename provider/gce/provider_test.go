@@ -8,6 +8,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	envtesting "github.com/juju/juju/environs/testing"
 	"github.com/juju/juju/provider/gce"
 )
@@ -63,6 +64,17 @@ func (s *providerSuite) TestSecretAttrs(c *gc.C) {
 
 }
 
+func (s *providerSuite) TestSchema(c *gc.C) {
+	fields := s.provider.(environs.ProviderSchema).Schema()
+	globalFields, err := config.Schema(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	for name, field := range globalFields {
+		c.Check(fields[name], jc.DeepEquals, field)
+	}
+	c.Check(fields["private-key"].Secret, jc.IsTrue)
+	c.Check(fields["client-email"].Secret, jc.IsFalse)
+}
+
 func (s *providerSuite) TestBoilerplateConfig(c *gc.C) {
 	// (wwitzel3) purposefully duplicate here so that this test will
 	// fail if someone updates gce/config.go without updating this test.
@@ -448,6 +448,7 @@ type fakeConn struct {
 	Insts      []google.Instance
 	PortRanges []network.PortRange
 	Zones      []google.AvailabilityZone
+	Quotas     []google.Quota
 
 	GoogleDisks   []*google.Disk
 	GoogleDisk    *google.Disk
@@ -472,6 +473,13 @@ func (fc *fakeConn) VerifyCredentials() error {
 	return fc.err()
 }
 
+func (fc *fakeConn) ProjectQuotas() ([]google.Quota, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "ProjectQuotas",
+	})
+	return fc.Quotas, fc.err()
+}
+
 func (fc *fakeConn) Instance(id, zone string) (google.Instance, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName: "Instance",
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 )
@@ -93,19 +94,75 @@ var environmentVariables = map[string]string{
 	privateKeyPath: MantaPrivateKeyFile,
 }
 
-var configFields = schema.Fields{
-	sdcUser:        schema.String(),
-	sdcKeyId:       schema.String(),
-	sdcUrl:         schema.String(),
-	mantaUser:      schema.String(),
-	mantaKeyId:     schema.String(),
-	mantaUrl:       schema.String(),
-	privateKeyPath: schema.String(),
-	algorithm:      schema.String(),
-	controlDir:     schema.String(),
-	privateKey:     schema.String(),
+var configSchema = environschema.Fields{
+	sdcUser: {
+		Description: "The SmartDataCenter account name.",
+		EnvVar:      SdcAccount,
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.AccountGroup,
+	},
+	sdcKeyId: {
+		Description: "The fingerprint of the SSH key used to sign SmartDataCenter requests.",
+		EnvVar:      SdcKeyId,
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.AccountGroup,
+	},
+	sdcUrl: {
+		Description: "The URL of the SmartDataCenter API endpoint.",
+		EnvVar:      SdcUrl,
+		Type:        environschema.Tstring,
+	},
+	mantaUser: {
+		Description: "The Manta account name.",
+		EnvVar:      MantaUser,
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.AccountGroup,
+	},
+	mantaKeyId: {
+		Description: "The fingerprint of the SSH key used to sign Manta requests.",
+		EnvVar:      MantaKeyId,
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.AccountGroup,
+	},
+	mantaUrl: {
+		Description: "The URL of the Manta API endpoint.",
+		EnvVar:      MantaUrl,
+		Type:        environschema.Tstring,
+	},
+	privateKeyPath: {
+		Description: "The path to the private key used to sign requests.",
+		EnvVar:      MantaPrivateKeyFile,
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	algorithm: {
+		Description: "The algorithm used to sign requests.",
+		Type:        environschema.Tstring,
+	},
+	controlDir: {
+		Description: "The Manta directory used to store environment metadata.",
+		Type:        environschema.Tstring,
+	},
+	privateKey: {
+		Description: "The private key used to sign requests.",
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.AccountGroup,
+	},
 }
 
+var configFields = func() schema.Fields {
+	fs, _, err := configSchema.ValidationSchema()
+	if err != nil {
+		panic(err)
+	}
+	return fs
+}()
+
 var configDefaults = schema.Defaults{
 	sdcUrl:         "https://us-west-1.api.joyentcloud.com",
 	mantaUrl:       "https://us-east.manta.joyent.com",
@@ -129,13 +186,17 @@ var requiredFields = []string{
 	// privatekey and privatekeypath are handled separately
 }
 
-var configSecretFields = []string{
-	sdcUser,
-	sdcKeyId,
-	mantaUser,
-	mantaKeyId,
-	privateKey,
-}
+// configSecretFields lists the config values marked as secret in
+// configSchema.
+var configSecretFields = func() []string {
+	var fields []string
+	for name, attr := range configSchema {
+		if attr.Secret {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}()
 
 var configImmutableFields = []string{
 	sdcUrl,
@@ -32,6 +32,10 @@ type joyentEnviron struct {
 	archLock               sync.Mutex
 	supportedArchitectures []string
 
+	// images caches the results of image metadata lookups. It is
+	// shared by snapshots of the environ.
+	images *imageCache
+
 	// All mutating operations should lock the mutex. Non-mutating operations
 	// should read all fields (other than name, which is immutable) from a
 	// shallow copy taken with getSnapshot().
@@ -45,10 +49,11 @@ type joyentEnviron struct {
 
 var _ environs.Environ = (*joyentEnviron)(nil)
 var _ state.Prechecker = (*joyentEnviron)(nil)
+var _ environs.InstanceTypesFetcher = (*joyentEnviron)(nil)
 
 // newEnviron create a new Joyent environ instance from config.
 func newEnviron(cfg *config.Config) (*joyentEnviron, error) {
-	env := new(joyentEnviron)
+	env := &joyentEnviron{images: newImageCache()}
 	if err := env.SetConfig(cfg); err != nil {
 		return nil, err
 	}
@@ -75,8 +80,8 @@ func (*joyentEnviron) Provider() environs.EnvironProvider {
 
 // PrecheckInstance is defined on the state.Prechecker interface.
 func (env *joyentEnviron) PrecheckInstance(series string, cons constraints.Value, placement string) error {
-	if placement != "" {
-		return fmt.Errorf("unknown placement directive: %s", placement)
+	if _, err := env.parsePlacement(placement); err != nil {
+		return err
 	}
 	if !cons.HasInstanceType() {
 		return nil
@@ -124,6 +129,10 @@ func (env *joyentEnviron) SetConfig(cfg *config.Config) error {
 		return err
 	}
 	env.ecfg = ecfg
+	if env.images != nil {
+		// Lookups depend on the configured image sources.
+		env.images.flush()
+	}
 	return nil
 }
 
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package joyent
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+)
+
+var _ common.ZonedEnviron = (*joyentEnviron)(nil)
+
+// joyentAvailZone is an availability zone in a Joyent environment.
+// Each Joyent datacenter is reached through its own CloudAPI endpoint,
+// so the datacenter named by the environment's region is its only
+// availability zone.
+type joyentAvailZone struct {
+	name string
+}
+
+// Name implements common.AvailabilityZone.
+func (z *joyentAvailZone) Name() string {
+	return z.name
+}
+
+// Available implements common.AvailabilityZone.
+func (z *joyentAvailZone) Available() bool {
+	return true
+}
+
+// AvailabilityZones returns all availability zones in the environment.
+func (env *joyentEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
+	return []common.AvailabilityZone{
+		&joyentAvailZone{name: env.Ecfg().Region()},
+	}, nil
+}
+
+// InstanceAvailabilityZoneNames returns the names of the availability
+// zones for the specified instances. The error returned follows the same
+// rules as Environ.Instances.
+func (env *joyentEnviron) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	instances, err := env.Instances(ids)
+	if err != nil && err != environs.ErrPartialInstances {
+		return nil, err
+	}
+	zone := env.Ecfg().Region()
+	zones := make([]string, len(instances))
+	for i, inst := range instances {
+		if inst != nil {
+			zones[i] = zone
+		}
+	}
+	return zones, err
+}
+
+// parsePlacement returns the availability zone named by the placement
+// directive, which must be the environment's only zone. If placement is
+// empty, that zone is returned.
+func (env *joyentEnviron) parsePlacement(placement string) (string, error) {
+	zone := env.Ecfg().Region()
+	if placement == "" {
+		return zone, nil
+	}
+	pos := strings.IndexRune(placement, '=')
+	if pos == -1 || placement[:pos] != "zone" {
+		return "", errors.Errorf("unknown placement directive: %s", placement)
+	}
+	if value := placement[pos+1:]; value != zone {
+		return "", errors.Errorf("invalid availability zone %q", value)
+	}
+	return zone, nil
+}
@@ -61,6 +61,8 @@ func (env *joyentEnviron) machineFullName(machineId string) string {
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Zones,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -81,6 +83,7 @@ func (env *joyentEnviron) ConstraintsValidator() (constraints.Validator, error)
 		instTypeNames[i] = pkg.Name
 	}
 	validator.RegisterVocabulary(constraints.InstanceType, instTypeNames)
+	validator.RegisterVocabulary(constraints.VirtType, []string{vTypeVirtualmachine})
 	return validator, nil
 }
 
@@ -95,6 +98,11 @@ func (env *joyentEnviron) StartInstance(args environs.StartInstanceParams) (*env
 		return nil, errors.New("starting instances with networks is not supported yet")
 	}
 
+	zone, err := env.parsePlacement(args.Placement)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	series := args.Tools.OneSeries()
 	arches := args.Tools.Arches()
 	spec, err := env.FindInstanceSpec(&instances.InstanceConstraint{
@@ -189,11 +197,12 @@ func (env *joyentEnviron) StartInstance(args environs.StartInstanceParams) (*env
 
 	disk64 := uint64(machine.Disk)
 	hc := instance.HardwareCharacteristics{
-		Arch:     &spec.Image.Arch,
-		Mem:      &spec.InstanceType.Mem,
-		CpuCores: &spec.InstanceType.CpuCores,
-		CpuPower: spec.InstanceType.CpuPower,
-		RootDisk: &disk64,
+		Arch:             &spec.Image.Arch,
+		Mem:              &spec.InstanceType.Mem,
+		CpuCores:         &spec.InstanceType.CpuCores,
+		CpuPower:         spec.InstanceType.CpuPower,
+		RootDisk:         &disk64,
+		AvailabilityZone: &zone,
 	}
 
 	return &environs.StartInstanceResult{
@@ -345,6 +354,18 @@ func (env *joyentEnviron) listInstanceTypes() ([]instances.InstanceType, error)
 	return allInstanceTypes, nil
 }
 
+// InstanceTypes is specified in the environs.InstanceTypesFetcher
+// interface. Joyent packages are ordered by size, as their costs are
+// not published through the API.
+func (env *joyentEnviron) InstanceTypes() ([]instances.InstanceType, error) {
+	itypes, err := env.listInstanceTypes()
+	if err != nil {
+		return nil, err
+	}
+	instances.SortByCost(itypes)
+	return itypes, nil
+}
+
 // FindInstanceSpec returns an InstanceSpec satisfying the supplied instanceConstraint.
 func (env *joyentEnviron) FindInstanceSpec(ic *instances.InstanceConstraint) (*instances.InstanceSpec, error) {
 	// Require at least one VCPU so we get KVM rather than smart package.
@@ -360,12 +381,14 @@ func (env *joyentEnviron) FindInstanceSpec(ic *instances.InstanceConstraint) (*i
 		Series:    []string{ic.Series},
 		Arches:    ic.Arches,
 	})
-	sources, err := environs.ImageMetadataSources(env)
-	if err != nil {
-		return nil, err
-	}
-
-	matchingImages, _, err := imagemetadata.Fetch(sources, imageConstraint, signedImageDataOnly)
+	matchingImages, err := env.images.fetch(imageConstraint, func() ([]*imagemetadata.ImageMetadata, error) {
+		sources, err := environs.ImageMetadataSources(env)
+		if err != nil {
+			return nil, err
+		}
+		matchingImages, _, err := imagemetadata.Fetch(sources, imageConstraint, signedImageDataOnly)
+		return matchingImages, err
+	})
 	if err != nil {
 		return nil, err
 	}
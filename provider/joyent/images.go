@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package joyent
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juju/juju/environs/imagemetadata"
+)
+
+// imageCacheTTL is how long the results of an image metadata lookup
+// are reused for, so that new images are eventually picked up by a
+// long-lived environ.
+var imageCacheTTL = time.Hour
+
+// imageCache caches the results of simplestreams image metadata
+// lookups, so that starting several instances does not repeat the
+// full lookup for each one.
+type imageCache struct {
+	mu      sync.Mutex
+	entries map[string]imageCacheEntry
+}
+
+type imageCacheEntry struct {
+	images  []*imagemetadata.ImageMetadata
+	expires time.Time
+}
+
+func newImageCache() *imageCache {
+	return &imageCache{entries: make(map[string]imageCacheEntry)}
+}
+
+// fetch returns the images matching the constraint, calling lookup
+// to find them if they are not cached. Failed lookups are not cached.
+func (c *imageCache) fetch(
+	constraint *imagemetadata.ImageConstraint,
+	lookup func() ([]*imagemetadata.ImageMetadata, error),
+) ([]*imagemetadata.ImageMetadata, error) {
+	key := fmt.Sprintf("%+v", constraint.LookupParams)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok && time.Now().Before(entry.expires) {
+		logger.Debugf("using cached image metadata for %s", key)
+		return entry.images, nil
+	}
+	images, err := lookup()
+	if err != nil {
+		return nil, err
+	}
+	c.entries[key] = imageCacheEntry{
+		images:  images,
+		expires: time.Now().Add(imageCacheTTL),
+	}
+	return images, nil
+}
+
+// flush discards all cached lookups.
+func (c *imageCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]imageCacheEntry)
+}
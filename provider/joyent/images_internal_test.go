@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package joyent
+
+import (
+	"errors"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/simplestreams"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type imageCacheSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&imageCacheSuite{})
+
+func imageConstraint(series string) *imagemetadata.ImageConstraint {
+	return imagemetadata.NewImageConstraint(simplestreams.LookupParams{
+		CloudSpec: simplestreams.CloudSpec{"some-region", "some-endpoint"},
+		Series:    []string{series},
+		Arches:    []string{"amd64"},
+	})
+}
+
+// countingLookup returns a lookup function that returns the given
+// images, and records the number of times it was called.
+func countingLookup(calls *int, images []*imagemetadata.ImageMetadata) func() ([]*imagemetadata.ImageMetadata, error) {
+	return func() ([]*imagemetadata.ImageMetadata, error) {
+		*calls++
+		return images, nil
+	}
+}
+
+func (s *imageCacheSuite) TestFetchCaches(c *gc.C) {
+	cache := newImageCache()
+	images := []*imagemetadata.ImageMetadata{{Id: "trusty-image"}}
+	var calls int
+	for i := 0; i < 3; i++ {
+		result, err := cache.fetch(imageConstraint("trusty"), countingLookup(&calls, images))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(result, jc.DeepEquals, images)
+	}
+	c.Assert(calls, gc.Equals, 1)
+
+	// A different constraint is looked up separately.
+	_, err := cache.fetch(imageConstraint("precise"), countingLookup(&calls, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 2)
+}
+
+func (s *imageCacheSuite) TestFetchErrorNotCached(c *gc.C) {
+	cache := newImageCache()
+	_, err := cache.fetch(imageConstraint("trusty"), func() ([]*imagemetadata.ImageMetadata, error) {
+		return nil, errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	var calls int
+	_, err = cache.fetch(imageConstraint("trusty"), countingLookup(&calls, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 1)
+}
+
+func (s *imageCacheSuite) TestFetchExpired(c *gc.C) {
+	s.PatchValue(&imageCacheTTL, time.Duration(0))
+	cache := newImageCache()
+	var calls int
+	for i := 0; i < 2; i++ {
+		_, err := cache.fetch(imageConstraint("trusty"), countingLookup(&calls, nil))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(calls, gc.Equals, 2)
+}
+
+func (s *imageCacheSuite) TestFlush(c *gc.C) {
+	cache := newImageCache()
+	var calls int
+	_, err := cache.fetch(imageConstraint("trusty"), countingLookup(&calls, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	cache.flush()
+	_, err = cache.fetch(imageConstraint("trusty"), countingLookup(&calls, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 2)
+}
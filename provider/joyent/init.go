@@ -13,7 +13,14 @@ const (
 )
 
 func init() {
-	environs.RegisterProvider(providerType, providerInstance)
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Provider:   providerInstance,
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityAvailabilityZones,
+		},
+	})
 
 	registry.RegisterEnvironStorageProviders(providerType)
 }
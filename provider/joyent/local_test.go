@@ -26,7 +26,9 @@ import (
 	"github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/joyent"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 )
@@ -200,7 +202,51 @@ func (s *localServerSuite) TestStartInstanceAvailabilityZone(c *gc.C) {
 	err = env.StopInstances(inst.Id())
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(hwc.AvailabilityZone, gc.IsNil)
+	c.Assert(hwc.AvailabilityZone, gc.NotNil)
+	c.Check(*hwc.AvailabilityZone, gc.Equals, "some-region")
+}
+
+func (s *localServerSuite) TestStartInstanceInvalidPlacement(c *gc.C) {
+	env := s.Prepare(c)
+	err := bootstrap.Bootstrap(bootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	params := environs.StartInstanceParams{Placement: "zone=other-region"}
+	_, err = testing.StartInstanceWithParams(env, "100", params, nil)
+	c.Assert(err, gc.ErrorMatches, `invalid availability zone "other-region"`)
+}
+
+func (s *localServerSuite) TestPrecheckInstancePlacement(c *gc.C) {
+	env := s.Prepare(c)
+	prechecker := env.(state.Prechecker)
+	err := prechecker.PrecheckInstance("trusty", constraints.Value{}, "zone=some-region")
+	c.Assert(err, jc.ErrorIsNil)
+	err = prechecker.PrecheckInstance("trusty", constraints.Value{}, "zone=other-region")
+	c.Assert(err, gc.ErrorMatches, `invalid availability zone "other-region"`)
+	err = prechecker.PrecheckInstance("trusty", constraints.Value{}, "host=foo")
+	c.Assert(err, gc.ErrorMatches, "unknown placement directive: host=foo")
+}
+
+func (s *localServerSuite) TestAvailabilityZones(c *gc.C) {
+	env := s.Prepare(c)
+	zones, err := env.(common.ZonedEnviron).AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 1)
+	c.Check(zones[0].Name(), gc.Equals, "some-region")
+	c.Check(zones[0].Available(), jc.IsTrue)
+}
+
+func (s *localServerSuite) TestInstanceAvailabilityZoneNames(c *gc.C) {
+	env := s.Prepare(c)
+	err := bootstrap.Bootstrap(bootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	inst, _ := testing.AssertStartInstance(c, env, "100")
+	defer env.StopInstances(inst.Id())
+
+	zones, err := env.(common.ZonedEnviron).InstanceAvailabilityZoneNames(
+		[]instance.Id{inst.Id(), "not-an-instance"},
+	)
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(zones, jc.DeepEquals, []string{"some-region", ""})
 }
 
 func (s *localServerSuite) TestStartInstanceHardwareCharacteristics(c *gc.C) {
@@ -264,7 +310,7 @@ var instanceGathering = []struct {
 func (s *localServerSuite) TestInstanceStatus(c *gc.C) {
 	env := s.Prepare(c)
 	inst, _ := testing.AssertStartInstance(c, env, "100")
-	c.Assert(inst.Status(), gc.Equals, "running")
+	c.Assert(inst.Status().Status, gc.Equals, "running")
 	err := env.StopInstances(inst.Id())
 	c.Assert(err, jc.ErrorIsNil)
 }
@@ -357,6 +403,18 @@ func (s *localServerSuite) TestFindImageBadDefaultImage(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `no "saucy" images in some-region with arches \[amd64\]`)
 }
 
+func (s *localServerSuite) TestInstanceTypes(c *gc.C) {
+	env := s.Prepare(c)
+	fetcher, ok := environs.SupportsInstanceTypes(env)
+	c.Assert(ok, jc.IsTrue)
+	itypes, err := fetcher.InstanceTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(itypes, gc.Not(gc.HasLen), 0)
+	for i := 1; i < len(itypes); i++ {
+		c.Check(itypes[i].Mem >= itypes[i-1].Mem, jc.IsTrue)
+	}
+}
+
 func (s *localServerSuite) TestValidateImageMetadata(c *gc.C) {
 	env := s.Prepare(c)
 	params, err := env.(simplestreams.MetadataValidator).MetadataLookupParams("some-region")
@@ -418,10 +476,10 @@ func (s *localServerSuite) TestConstraintsValidator(c *gc.C) {
 	env := s.Prepare(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10")
+	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10 root-disk-source=ssd")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "tags"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "root-disk-source", "tags"})
 }
 
 func (s *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
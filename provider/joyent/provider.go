@@ -13,6 +13,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -124,6 +125,15 @@ func (joyentProvider) Validate(cfg, old *config.Config) (valid *config.Config, e
 	return cfg.Apply(newEcfg.attrs)
 }
 
+// Schema implements environs.ProviderSchema.
+func (joyentProvider) Schema() environschema.Fields {
+	fields, err := config.Schema(configSchema)
+	if err != nil {
+		panic(err)
+	}
+	return fields
+}
+
 func (joyentProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	// If you keep configSecretFields up to date, this method should Just Work.
 	ecfg, err := validateConfig(cfg, nil)
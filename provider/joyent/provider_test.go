@@ -6,7 +6,9 @@ package joyent_test
 import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/provider/joyent"
 	"github.com/juju/juju/testing"
@@ -34,3 +36,11 @@ func (*providerSimpleSuite) TestPrepareSetsControlDir(c *gc.C) {
 	c.Assert(ok, jc.IsTrue)
 	c.Assert(value, gc.Matches, "[a-f0-9]{32}")
 }
+
+func (*providerSimpleSuite) TestSchema(c *gc.C) {
+	fields := joyent.Provider.(environs.ProviderSchema).Schema()
+	c.Check(fields["private-key"].Secret, jc.IsTrue)
+	c.Check(fields["sdc-key-id"].Secret, jc.IsTrue)
+	c.Check(fields["sdc-url"].Secret, jc.IsFalse)
+	c.Check(fields["name"].Type, gc.Equals, environschema.Tstring)
+}
@@ -50,7 +50,7 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tstring,
 	},
 	ContainerKey: {
-		Description: `The kind of container to use for machines`,
+		Description: `The kind of container to use for machines, unless a machine is given a virt-type constraint or container= placement directive`,
 		Type:        environschema.Tstring,
 		Values: []interface{}{
 			string(instance.LXC),
@@ -133,6 +133,20 @@ func (c *environConfig) setDefaultNetworkBridge() {
 	c.attrs[NetworkBridgeKey] = name
 }
 
+// networkBridgeFor returns the network bridge that containers of the
+// given type are connected to. Containers of the configured type use
+// the configured bridge; other containers use their type's default.
+func (c *environConfig) networkBridgeFor(containerType instance.ContainerType) string {
+	if containerType == c.container() {
+		return c.networkBridge()
+	}
+	switch containerType {
+	case instance.KVM:
+		return kvm.DefaultKvmBridge
+	}
+	return lxc.DefaultLxcBridge
+}
+
 func (c *environConfig) networkBridge() string {
 	// We don't care if it's not a string, because Validate takes care
 	// of that.
@@ -56,26 +56,74 @@ var _ environs.Environ = (*localEnviron)(nil)
 type localEnviron struct {
 	common.SupportsUnitPlacementPolicy
 
-	localMutex       sync.Mutex
-	config           *environConfig
-	name             string
-	bridgeAddress    string
-	localStorage     storage.Storage
-	storageListener  net.Listener
-	containerManager container.Manager
+	localMutex      sync.Mutex
+	config          *environConfig
+	name            string
+	bridgeAddress   string
+	localStorage    storage.Storage
+	storageListener net.Listener
+	// containerManagers holds a container manager for each of the
+	// supported container types, so that LXC and KVM machines can be
+	// started in the same environment.
+	containerManagers map[instance.ContainerType]container.Manager
 }
 
+// containerTypes lists the container types that the local provider
+// can start machines as.
+var containerTypes = []instance.ContainerType{instance.LXC, instance.KVM}
+
 // SupportedArchitectures is specified on the EnvironCapability interface.
 func (*localEnviron) SupportedArchitectures() ([]string, error) {
 	localArch := arch.HostArch()
 	return []string{localArch}, nil
 }
 
-func (*localEnviron) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+// PrecheckInstance is specified in the state.Prechecker interface.
+func (env *localEnviron) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	_, err := instanceContainerType(env.config.container(), cons, placement)
+	return err
+}
+
+// instanceContainerType returns the container type that a new machine
+// should be started as. A "container=<type>" placement directive takes
+// precedence, followed by the virt-type constraint; otherwise the
+// environment's configured container type is used.
+func instanceContainerType(defaultType instance.ContainerType, cons constraints.Value, placement string) (instance.ContainerType, error) {
+	var placed, wanted instance.ContainerType
 	if placement != "" {
-		return fmt.Errorf("unknown placement directive: %s", placement)
+		parts := strings.SplitN(placement, "=", 2)
+		if len(parts) != 2 || parts[0] != "container" {
+			return "", fmt.Errorf("unknown placement directive: %s", placement)
+		}
+		placed = instance.ContainerType(parts[1])
+		if !isSupportedContainerType(placed) {
+			return "", fmt.Errorf("invalid container type in placement directive: %s", placement)
+		}
 	}
-	return nil
+	if cons.HasVirtType() {
+		wanted = instance.ContainerType(*cons.VirtType)
+		if !isSupportedContainerType(wanted) {
+			return "", fmt.Errorf("invalid virt-type constraint %q", *cons.VirtType)
+		}
+	}
+	switch {
+	case placed != "" && wanted != "" && placed != wanted:
+		return "", fmt.Errorf("placement directive %q conflicts with virt-type constraint %q", placement, wanted)
+	case placed != "":
+		return placed, nil
+	case wanted != "":
+		return wanted, nil
+	}
+	return defaultType, nil
+}
+
+func isSupportedContainerType(containerType instance.ContainerType) bool {
+	for _, t := range containerTypes {
+		if t == containerType {
+			return true
+		}
+	}
+	return false
 }
 
 func (env *localEnviron) machineAgentServiceName() string {
@@ -249,10 +297,49 @@ func (env *localEnviron) SetConfig(cfg *config.Config) error {
 	defer env.localMutex.Unlock()
 	env.config = ecfg
 	env.name = ecfg.Name()
-	containerType := ecfg.container()
+	env.containerManagers = make(map[instance.ContainerType]container.Manager)
+	for _, containerType := range containerTypes {
+		manager, err := newContainerManager(ecfg, containerType)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		env.containerManagers[containerType] = manager
+	}
+
+	// When the localEnviron value is created on the client
+	// side, the bootstrap-ip attribute will not exist,
+	// because it is only set *within* the running
+	// environment, not in the configuration created by
+	// Prepare.
+	//
+	// When bootstrapIPAddress returns a non-empty string,
+	// we know we are running server-side and thus must use
+	// httpstorage.
+	if addr := ecfg.bootstrapIPAddress(); addr != "" {
+		env.bridgeAddress = addr
+		return nil
+	}
+	// If we get to here, it is because we haven't yet bootstrapped an
+	// environment, and saved the config in it, or we are running a command
+	// from the command line, so it is ok to work on the assumption that we
+	// have direct access to the directories.
+	if err := env.config.createDirs(); err != nil {
+		return errors.Trace(err)
+	}
+	// Record the network bridge address and create a filestorage.
+	if err := env.resolveBridgeAddress(cfg); err != nil {
+		return errors.Trace(err)
+	}
+	return env.setLocalStorage()
+}
+
+// newContainerManager returns a container manager for the given
+// container type, configured for the environment.
+func newContainerManager(ecfg *environConfig, containerType instance.ContainerType) (container.Manager, error) {
+	cfg := ecfg.Config
 	managerConfig := container.ManagerConfig{
-		container.ConfigName:   env.config.namespace(),
-		container.ConfigLogDir: env.config.logDir(),
+		container.ConfigName:   ecfg.namespace(),
+		container.ConfigLogDir: ecfg.logDir(),
 	}
 	var imageURLGetter container.ImageURLGetter
 	if containerType == instance.LXC {
@@ -281,37 +368,7 @@ func (env *localEnviron) SetConfig(cfg *config.Config) error {
 
 		}
 	}
-	env.containerManager, err = factory.NewContainerManager(
-		containerType, managerConfig, imageURLGetter)
-	if err != nil {
-		return errors.Trace(err)
-	}
-
-	// When the localEnviron value is created on the client
-	// side, the bootstrap-ip attribute will not exist,
-	// because it is only set *within* the running
-	// environment, not in the configuration created by
-	// Prepare.
-	//
-	// When bootstrapIPAddress returns a non-empty string,
-	// we know we are running server-side and thus must use
-	// httpstorage.
-	if addr := ecfg.bootstrapIPAddress(); addr != "" {
-		env.bridgeAddress = addr
-		return nil
-	}
-	// If we get to here, it is because we haven't yet bootstrapped an
-	// environment, and saved the config in it, or we are running a command
-	// from the command line, so it is ok to work on the assumption that we
-	// have direct access to the directories.
-	if err := env.config.createDirs(); err != nil {
-		return errors.Trace(err)
-	}
-	// Record the network bridge address and create a filestorage.
-	if err := env.resolveBridgeAddress(cfg); err != nil {
-		return errors.Trace(err)
-	}
-	return env.setLocalStorage()
+	return factory.NewContainerManager(containerType, managerConfig, imageURLGetter)
 }
 
 // resolveBridgeAddress finishes up the setup of the environment in
@@ -351,6 +408,8 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Zones,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -362,6 +421,7 @@ func (env *localEnviron) ConstraintsValidator() (constraints.Validator, error) {
 		return nil, err
 	}
 	validator.RegisterVocabulary(constraints.Arch, supportedArches)
+	validator.RegisterVocabulary(constraints.VirtType, []string{string(instance.LXC), string(instance.KVM)})
 	return validator, nil
 }
 
@@ -379,7 +439,18 @@ func (env *localEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 	logger.Debugf("StartInstance: %q, %s", args.InstanceConfig.MachineId, series)
 	args.InstanceConfig.Tools = args.Tools[0]
 
-	args.InstanceConfig.MachineContainerType = env.config.container()
+	containerType, err := instanceContainerType(env.config.container(), args.Constraints, args.Placement)
+	if err != nil {
+		return nil, err
+	}
+	if containerType != env.config.container() {
+		// Bootstrap only verified the prerequisites of the configured
+		// container type.
+		if err := VerifyPrerequisites(containerType); err != nil {
+			return nil, errors.Annotatef(err, "cannot start %s machine", containerType)
+		}
+	}
+	args.InstanceConfig.MachineContainerType = containerType
 	logger.Debugf("tools: %#v", args.InstanceConfig.Tools)
 	if err := instancecfg.FinishInstanceConfig(args.InstanceConfig, env.config.Config); err != nil {
 		return nil, err
@@ -389,7 +460,7 @@ func (env *localEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 	// This limitation is why the constraints are assigned directly here.
 	args.InstanceConfig.Constraints = args.Constraints
 	args.InstanceConfig.AgentEnvironment[agent.Namespace] = env.config.namespace()
-	inst, hardware, err := createContainer(env, args)
+	inst, hardware, err := createContainer(env, containerType, args)
 	if err != nil {
 		return nil, err
 	}
@@ -400,15 +471,15 @@ func (env *localEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 }
 
 // Override for testing.
-var createContainer = func(env *localEnviron, args environs.StartInstanceParams) (instance.Instance, *instance.HardwareCharacteristics, error) {
+var createContainer = func(env *localEnviron, containerType instance.ContainerType, args environs.StartInstanceParams) (instance.Instance, *instance.HardwareCharacteristics, error) {
 	series := args.Tools.OneSeries()
-	network := container.BridgeNetworkConfig(env.config.networkBridge(), 0, args.NetworkInfo)
+	network := container.BridgeNetworkConfig(env.config.networkBridgeFor(containerType), 0, args.NetworkInfo)
 	allowLoopMounts, _ := env.config.AllowLXCLoopMounts()
-	isLXC := env.config.container() == instance.LXC
+	isLXC := containerType == instance.LXC
 	storage := &container.StorageConfig{
 		AllowMount: !isLXC || allowLoopMounts,
 	}
-	inst, hardware, err := env.containerManager.CreateContainer(args.InstanceConfig, series, network, storage)
+	inst, hardware, err := env.containerManagers[containerType].CreateContainer(args.InstanceConfig, series, network, storage)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -417,17 +488,75 @@ var createContainer = func(env *localEnviron, args environs.StartInstanceParams)
 
 // StopInstances is specified in the InstanceBroker interface.
 func (env *localEnviron) StopInstances(ids ...instance.Id) error {
+	managers, err := env.instanceManagers()
+	if err != nil {
+		return err
+	}
 	for _, id := range ids {
 		if id == bootstrapInstanceId {
 			return fmt.Errorf("cannot stop the bootstrap instance")
 		}
-		if err := env.containerManager.DestroyContainer(id); err != nil {
+		manager, ok := managers[id]
+		if !ok {
+			// Let the default manager report the missing container.
+			manager = env.containerManagers[env.config.container()]
+		}
+		if err := manager.DestroyContainer(id); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// instanceManagers returns the container manager responsible for each
+// of the environment's containers.
+func (env *localEnviron) instanceManagers() (map[instance.Id]container.Manager, error) {
+	managers := make(map[instance.Id]container.Manager)
+	for _, containerType := range env.listedContainerTypes() {
+		manager := env.containerManagers[containerType]
+		containers, err := manager.ListContainers()
+		if err != nil {
+			return nil, err
+		}
+		for _, inst := range containers {
+			managers[inst.Id()] = manager
+		}
+	}
+	return managers, nil
+}
+
+// listedContainerTypes returns the container types whose containers
+// are listed: the configured container type, and any other type whose
+// userspace tools are installed. Listing KVM machines needs virsh, which
+// is absent on hosts that only run LXC.
+func (env *localEnviron) listedContainerTypes() []instance.ContainerType {
+	configured := env.config.container()
+	listed := []instance.ContainerType{configured}
+	for _, containerType := range containerTypes {
+		if containerType != configured && containerToolsInstalled(containerType) {
+			listed = append(listed, containerType)
+		}
+	}
+	return listed
+}
+
+// containerToolsInstalled reports whether the userspace tools used to
+// list containers of the given type are installed. It is a variable
+// only to support unit testing.
+var containerToolsInstalled = func(containerType instance.ContainerType) bool {
+	var tool string
+	switch containerType {
+	case instance.LXC:
+		tool = lxclsPath
+	case instance.KVM:
+		tool = virshPath
+	default:
+		return false
+	}
+	_, err := exec.LookPath(tool)
+	return err == nil
+}
+
 // Instances is specified in the Environ interface.
 func (env *localEnviron) Instances(ids []instance.Id) ([]instance.Instance, error) {
 	if len(ids) == 0 {
@@ -463,12 +592,14 @@ func (env *localEnviron) Instances(ids []instance.Id) ([]instance.Instance, erro
 func (env *localEnviron) AllInstances() (instances []instance.Instance, err error) {
 	instances = append(instances, &localInstance{bootstrapInstanceId, env})
 	// Add in all the containers as well.
-	lxcInstances, err := env.containerManager.ListContainers()
-	if err != nil {
-		return nil, err
-	}
-	for _, inst := range lxcInstances {
-		instances = append(instances, &localInstance{inst.Id(), env})
+	for _, containerType := range env.listedContainerTypes() {
+		containers, err := env.containerManagers[containerType].ListContainers()
+		if err != nil {
+			return nil, err
+		}
+		for _, inst := range containers {
+			instances = append(instances, &localInstance{inst.Id(), env})
+		}
 	}
 	return instances, nil
 }
@@ -512,12 +643,12 @@ func (env *localEnviron) Destroy() error {
 	}
 	// Kill all running instances. This must be done as
 	// root, or listing/stopping containers will fail.
-	containers, err := env.containerManager.ListContainers()
+	managers, err := env.instanceManagers()
 	if err != nil {
 		return err
 	}
-	for _, inst := range containers {
-		if err := env.containerManager.DestroyContainer(inst.Id()); err != nil {
+	for id, manager := range managers {
+		if err := manager.DestroyContainer(id); err != nil {
 			return err
 		}
 	}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
 	"github.com/juju/utils/series"
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
 	containertesting "github.com/juju/juju/container/testing"
 	"github.com/juju/juju/environs"
@@ -350,6 +352,44 @@ func (s *localJujuTestSuite) TestDestroyRemovesContainers(c *gc.C) {
 	c.Assert(container.IsConstructed(), jc.IsFalse)
 }
 
+// noVirshFactory is a KVM container factory that fails to list
+// containers as it would on a host without libvirt.
+type noVirshFactory struct {
+	kvm.ContainerFactory
+}
+
+func (noVirshFactory) List() ([]kvm.Container, error) {
+	return nil, errors.New(`exec: "virsh": executable file not found in $PATH`)
+}
+
+func (s *localJujuTestSuite) TestAllInstancesWithoutKVM(c *gc.C) {
+	env := s.testBootstrap(c, minimalConfig(c))
+	s.PatchValue(local.VirshPath, filepath.Join(c.MkDir(), "virsh"))
+	s.PatchValue(&kvm.KvmObjectFactory, kvm.ContainerFactory(noVirshFactory{}))
+
+	namespace := env.Config().AllAttrs()["namespace"].(string)
+	manager, err := lxc.NewContainerManager(container.ManagerConfig{
+		container.ConfigName:   namespace,
+		container.ConfigLogDir: "logdir",
+		"use-clone":            "false",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	machine1 := containertesting.CreateContainer(c, manager, "1")
+
+	instances, err := env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	var ids []instance.Id
+	for _, inst := range instances {
+		ids = append(ids, inst.Id())
+	}
+	c.Assert(ids, jc.SameContents, []instance.Id{"localhost", machine1.Id()})
+
+	instances, err = env.Instances([]instance.Id{machine1.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+	c.Assert(instances[0].Id(), gc.Equals, machine1.Id())
+}
+
 func (s *localJujuTestSuite) TestBootstrapRemoveLeftovers(c *gc.C) {
 	cfg := minimalConfig(c)
 	rootDir := cfg.AllAttrs()["root-dir"].(string)
@@ -378,10 +418,10 @@ func (s *localJujuTestSuite) TestConstraintsValidator(c *gc.C) {
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
 	hostArch := arch.HostArch()
-	cons := constraints.MustParse(fmt.Sprintf("arch=%s instance-type=foo tags=bar cpu-power=10 cpu-cores=2", hostArch))
+	cons := constraints.MustParse(fmt.Sprintf("arch=%s instance-type=foo tags=bar cpu-power=10 cpu-cores=2 root-disk-source=ssd", hostArch))
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-cores", "cpu-power", "instance-type", "tags"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-cores", "cpu-power", "instance-type", "root-disk-source", "tags"})
 }
 
 func (s *localJujuTestSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -402,6 +442,57 @@ func (s *localJujuTestSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: arch="+invalidArch+"\nvalid values are:.*")
 }
 
+func (s *localJujuTestSuite) TestConstraintsValidatorVirtType(c *gc.C) {
+	env := s.Prepare(c)
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, virtType := range []string{"lxc", "kvm"} {
+		unsupported, err := validator.Validate(constraints.MustParse("virt-type=" + virtType))
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(unsupported, gc.HasLen, 0)
+	}
+	_, err = validator.Validate(constraints.MustParse("virt-type=xen"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: virt-type=xen\nvalid values are:.*")
+}
+
+func (s *localJujuTestSuite) TestPrecheckInstance(c *gc.C) {
+	env := s.Prepare(c)
+	for i, test := range []struct {
+		cons      string
+		placement string
+		err       string
+	}{{
+		placement: "container=kvm",
+	}, {
+		cons: "virt-type=kvm",
+	}, {
+		cons:      "virt-type=lxc",
+		placement: "container=lxc",
+	}, {
+		placement: "zone=a",
+		err:       "unknown placement directive: zone=a",
+	}, {
+		placement: "container=xen",
+		err:       "invalid container type in placement directive: container=xen",
+	}, {
+		cons: "virt-type=xen",
+		err:  `invalid virt-type constraint "xen"`,
+	}, {
+		cons:      "virt-type=lxc",
+		placement: "container=kvm",
+		err:       `placement directive "container=kvm" conflicts with virt-type constraint "lxc"`,
+	}} {
+		c.Logf("test %d: %q %q", i, test.cons, test.placement)
+		err := env.PrecheckInstance("trusty", constraints.MustParse(test.cons), test.placement)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
 func (s *localJujuTestSuite) TestStateServerInstances(c *gc.C) {
 	env := s.testBootstrap(c, minimalConfig(c))
 
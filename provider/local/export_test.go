@@ -21,6 +21,7 @@ var (
 	ExecuteCloudConfig   = &executeCloudConfig
 	Provider             = providerInstance
 	UserCurrent          = &userCurrent
+	VirshPath            = &virshPath
 )
 
 // CheckConfigNamespace checks the result of the namespace call on the
@@ -14,7 +14,14 @@ const (
 )
 
 func init() {
-	environs.RegisterProvider(providerType, providerInstance)
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Provider:   providerInstance,
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityStorage,
+		},
+	})
 
 	// TODO(wallyworld) - sort out policy for allowing loop provider
 	registry.RegisterEnvironStorageProviders(
@@ -51,6 +51,11 @@ The local provider is currently only available for Linux`
 // unit testing.
 var lxclsPath = "lxc-ls"
 
+// virshPath is the path to "virsh", the libvirt tool used
+// to list KVM containers. This is a variable only to support
+// unit testing.
+var virshPath = "virsh"
+
 // The operating system the process is running in.
 // This is a variable only to support unit testing.
 var goos = runtime.GOOS
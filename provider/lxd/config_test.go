@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/provider/lxd/lxdclient"
+	"github.com/juju/juju/testing"
+)
+
+type configSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&configSuite{})
+
+var remoteAttrs = testing.Attrs{
+	"remote-url":  "https://10.0.0.1:8443",
+	"client-cert": "client cert",
+	"client-key":  "client key",
+	"server-cert": "server cert",
+}
+
+func (s *configSuite) TestDefaults(c *gc.C) {
+	cfg, err := providerInstance.Validate(s.Config, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ecfg := newConfig(cfg)
+	c.Check(ecfg.remoteURL(), gc.Equals, "")
+	c.Check(ecfg.clientConfig().IsLocal(), jc.IsTrue)
+	c.Check(ecfg.imageServer(), gc.Equals, "https://cloud-images.ubuntu.com/releases")
+}
+
+func (s *configSuite) TestRemote(c *gc.C) {
+	cfg, err := providerInstance.Validate(s.NewConfig(c, remoteAttrs), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ecfg := newConfig(cfg)
+	c.Check(ecfg.clientConfig(), jc.DeepEquals, lxdclient.Config{
+		Remote:     "https://10.0.0.1:8443",
+		ClientCert: "client cert",
+		ClientKey:  "client key",
+		ServerCert: "server cert",
+	})
+}
+
+func (s *configSuite) TestDailyImages(c *gc.C) {
+	cfg, err := providerInstance.Validate(s.NewConfig(c, testing.Attrs{"image-stream": "daily"}), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(newConfig(cfg).imageServer(), gc.Equals, "https://cloud-images.ubuntu.com/daily")
+}
+
+var invalidConfigTests = []struct {
+	about string
+	attrs testing.Attrs
+	err   string
+}{{
+	about: "remote without credentials",
+	attrs: testing.Attrs{"remote-url": "https://10.0.0.1:8443"},
+	err:   `.*remote "https://10.0.0.1:8443" without client certificate and key not valid`,
+}, {
+	about: "remote without https",
+	attrs: remoteAttrs.Merge(testing.Attrs{"remote-url": "10.0.0.1:8443"}),
+	err:   `.*remote URL "10.0.0.1:8443" \(expected https://host\[:port\]\) not valid`,
+}, {
+	about: "unknown image stream",
+	attrs: testing.Attrs{"image-stream": "proposed"},
+	err:   `.*image-stream "proposed" not valid`,
+}, {
+	about: "global firewall",
+	attrs: testing.Attrs{"firewall-mode": "global"},
+	err:   `.*firewall-mode "global" not supported`,
+}}
+
+func (s *configSuite) TestValidateInvalid(c *gc.C) {
+	for i, test := range invalidConfigTests {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := providerInstance.Validate(s.NewConfig(c, test.attrs), nil)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *configSuite) TestValidateImmutable(c *gc.C) {
+	old, err := providerInstance.Validate(s.Config, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = providerInstance.Validate(s.NewConfig(c, remoteAttrs), old)
+	c.Assert(err, gc.ErrorMatches, "invalid config change: remote-url: cannot change from  to https://10.0.0.1:8443")
+}
+
+func (s *configSuite) TestSecretAttrs(c *gc.C) {
+	secrets, err := providerInstance.SecretAttrs(s.NewConfig(c, remoteAttrs))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, jc.DeepEquals, map[string]string{
+		"client-key": "client key",
+	})
+}
+
+func (s *configSuite) TestSchema(c *gc.C) {
+	fields := providerInstance.Schema()
+	c.Check(fields["client-key"].Secret, jc.IsTrue)
+	c.Check(fields["client-cert"].Secret, jc.IsFalse)
+	c.Check(fields["name"].Type, gc.Equals, environschema.Tstring)
+}
+
+func (s *configSuite) TestSetConfig(c *gc.C) {
+	err := s.Env.SetConfig(s.NewConfig(c, testing.Attrs{"image-stream": "daily"}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.Env.ecfg.imageServer(), gc.Equals, "https://cloud-images.ubuntu.com/daily")
+
+	err = s.Env.SetConfig(s.NewConfig(c, remoteAttrs))
+	c.Assert(err, gc.ErrorMatches, "invalid config change: remote-url: .*")
+}
@@ -0,0 +1,208 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+	jujuos "github.com/juju/utils/os"
+	"github.com/juju/utils/series"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/cloudconfig/providerinit"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/tools"
+)
+
+func isStateServer(icfg *instancecfg.InstanceConfig) bool {
+	return multiwatcher.AnyJobNeedsState(icfg.Jobs...)
+}
+
+// MaintainInstance is specified in the InstanceBroker interface.
+func (*environ) MaintainInstance(args environs.StartInstanceParams) error {
+	return nil
+}
+
+// StartInstance implements environs.InstanceBroker.
+func (env *environ) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	env = env.getSnapshot()
+
+	if args.InstanceConfig.HasNetworks() {
+		return nil, errors.New("starting instances with networks is not supported yet")
+	}
+
+	archName, err := env.instanceArch(args.Tools)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := env.finishInstanceConfig(args, archName); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	raw, err := newRawInstance(env, args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("started instance %q", raw.Name)
+	inst := newInstance(raw, env)
+
+	// Build the result.
+	hwc := env.getHardwareCharacteristics(args, archName)
+	result := environs.StartInstanceResult{
+		Instance: inst,
+		Hardware: hwc,
+	}
+	return &result, nil
+}
+
+var newRawInstance = func(env *environ, args environs.StartInstanceParams) (*lxdclient.Instance, error) {
+	return env.newRawInstance(args)
+}
+
+// instanceArch returns the first architecture of the given tools that
+// the LXD server can run containers for.
+func (env *environ) instanceArch(possibleTools tools.List) (string, error) {
+	supported, err := env.SupportedArchitectures()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, toolsArch := range possibleTools.Arches() {
+		for _, serverArch := range supported {
+			if toolsArch == serverArch {
+				return toolsArch, nil
+			}
+		}
+	}
+	return "", errors.Errorf("no tools for the LXD server's architectures %v", supported)
+}
+
+// finishInstanceConfig updates args.InstanceConfig in place. Setting up
+// the API, StateServing, and SSHkeys information.
+func (env *environ) finishInstanceConfig(args environs.StartInstanceParams, archName string) error {
+	envTools, err := args.Tools.Match(tools.Filter{Arch: archName})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	args.InstanceConfig.Tools = envTools[0]
+	return instancecfg.FinishInstanceConfig(args.InstanceConfig, env.Config())
+}
+
+// newRawInstance is where the new container is actually created,
+// relative to the provided args. Info for that low-level container is
+// returned.
+func (env *environ) newRawInstance(args environs.StartInstanceParams) (*lxdclient.Instance, error) {
+	machineID := common.MachineFullName(env, args.InstanceConfig.MachineId)
+
+	os, err := series.GetOSFromSeries(args.InstanceConfig.Series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if os != jujuos.Ubuntu {
+		return nil, errors.Errorf("os %s is not supported on the lxd provider", os.String())
+	}
+
+	metadata, err := getMetadata(args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	spec := lxdclient.InstanceSpec{
+		Name:        machineID,
+		ImageServer: env.ecfg.imageServer(),
+		ImageAlias:  args.InstanceConfig.Series,
+		Metadata:    metadata,
+		Config:      getLimits(args),
+	}
+	inst, err := env.raw.AddInstance(spec)
+	return inst, errors.Trace(err)
+}
+
+// getMetadata builds the raw "user-defined" metadata for the new
+// container (relative to the provided args) and returns it.
+func getMetadata(args environs.StartInstanceParams) (map[string]string, error) {
+	userData, err := providerinit.ComposeUserData(args.InstanceConfig, nil, LXDRenderer{})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot make user data")
+	}
+	logger.Debugf("LXD user data; %d bytes", len(userData))
+
+	metadata := map[string]string{
+		metadataKeyCloudInit: string(userData),
+	}
+	if isStateServer(args.InstanceConfig) {
+		metadata[metadataKeyIsState] = metadataValueTrue
+	} else {
+		metadata[metadataKeyIsState] = metadataValueFalse
+	}
+	return metadata, nil
+}
+
+// getLimits returns the LXD resource limits for a new container,
+// derived from the provided constraints.
+func getLimits(args environs.StartInstanceParams) map[string]string {
+	limits := make(map[string]string)
+	if args.Constraints.CpuCores != nil {
+		limits["limits.cpu"] = fmt.Sprint(*args.Constraints.CpuCores)
+	}
+	if args.Constraints.Mem != nil {
+		limits["limits.memory"] = fmt.Sprintf("%dMB", *args.Constraints.Mem)
+	}
+	return limits
+}
+
+// getHardwareCharacteristics compiles hardware-related details about
+// a new container relative to the provided args and returns it.
+func (env *environ) getHardwareCharacteristics(args environs.StartInstanceParams, archName string) *instance.HardwareCharacteristics {
+	hwc := instance.HardwareCharacteristics{
+		Arch: &archName,
+	}
+	if args.Constraints.CpuCores != nil {
+		hwc.CpuCores = args.Constraints.CpuCores
+	}
+	if args.Constraints.Mem != nil {
+		hwc.Mem = args.Constraints.Mem
+	}
+	return &hwc
+}
+
+// AllInstances implements environs.InstanceBroker.
+func (env *environ) AllInstances() ([]instance.Instance, error) {
+	instances, err := getInstances(env)
+	return instances, errors.Trace(err)
+}
+
+// StopInstances implements environs.InstanceBroker.
+func (env *environ) StopInstances(instances ...instance.Id) error {
+	env = env.getSnapshot()
+
+	var ids []string
+	for _, id := range instances {
+		ids = append(ids, string(id))
+	}
+
+	prefix := common.MachineFullName(env, "")
+	err := env.raw.RemoveInstances(prefix, ids...)
+	return errors.Trace(err)
+}
+
+// normaliseArches returns the juju names of the given LXD
+// architecture names, omitting any that juju does not support.
+func normaliseArches(lxdArches []string) []string {
+	var arches []string
+	for _, lxdArch := range lxdArches {
+		name := arch.NormaliseArch(lxdArch)
+		if !arch.IsSupportedArch(name) {
+			continue
+		}
+		arches = append(arches, name)
+	}
+	return arches
+}
@@ -0,0 +1,81 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"errors"
+
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+type environBrokerSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&environBrokerSuite{})
+
+func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
+	raw := s.NewRawInstance(s.Prefix+"0", true)
+	s.Raw.Inst = &raw
+	s.StartInstArgs.Constraints = constraints.MustParse("cpu-cores=2 mem=2G")
+
+	result, err := s.Env.StartInstance(s.StartInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Instance.Id(), gc.Equals, instance.Id(s.Prefix+"0"))
+	c.Check(result.Hardware.String(), gc.Equals, "arch=amd64 cpu-cores=2 mem=2048M")
+
+	s.Raw.CheckCallNames(c, "ServerArchitectures", "AddInstance")
+	spec := s.Raw.Calls()[1].Args[0].(lxdclient.InstanceSpec)
+	c.Check(spec.Name, gc.Equals, s.Prefix+"0")
+	c.Check(spec.ImageServer, gc.Equals, "https://cloud-images.ubuntu.com/releases")
+	c.Check(spec.ImageAlias, gc.Equals, "trusty")
+	c.Check(spec.Config, jc.DeepEquals, map[string]string{
+		"limits.cpu":    "2",
+		"limits.memory": "2048MB",
+	})
+	c.Check(spec.Metadata[metadataKeyIsState], gc.Equals, metadataValueTrue)
+	c.Check(spec.Metadata[metadataKeyCloudInit], gc.Matches, "(?s)#cloud-config\n.*")
+}
+
+func (s *environBrokerSuite) TestStartInstanceUnsupportedArch(c *gc.C) {
+	s.Raw.Arches = []string{"ppc64le"}
+
+	_, err := s.Env.StartInstance(s.StartInstArgs)
+	c.Assert(err, gc.ErrorMatches, `no tools for the LXD server's architectures \[ppc64el\]`)
+	s.Raw.CheckCallNames(c, "ServerArchitectures")
+}
+
+func (s *environBrokerSuite) TestStartInstanceError(c *gc.C) {
+	s.Raw.SetErrors(nil, errors.New("boom"))
+
+	_, err := s.Env.StartInstance(s.StartInstArgs)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
+	err := s.Env.StopInstances(instance.Id(s.Prefix+"0"), instance.Id(s.Prefix+"1"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.Raw.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "RemoveInstances",
+		Args:     []interface{}{s.Prefix, []string{s.Prefix + "0", s.Prefix + "1"}},
+	}})
+}
+
+func (s *environBrokerSuite) TestSupportedArchitectures(c *gc.C) {
+	arches, err := s.Env.SupportedArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(arches, jc.DeepEquals, []string{arch.AMD64, arch.I386})
+
+	// The result is cached.
+	_, err = s.Env.SupportedArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	s.Raw.CheckCallNames(c, "ServerArchitectures")
+}
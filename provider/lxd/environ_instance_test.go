@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+type environInstanceSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&environInstanceSuite{})
+
+func (s *environInstanceSuite) TestInstances(c *gc.C) {
+	s.Raw.Insts = []lxdclient.Instance{
+		s.NewRawInstance(s.Prefix+"0", true),
+		s.NewRawInstance(s.Prefix+"1", false),
+	}
+
+	insts, err := s.Env.Instances([]instance.Id{
+		instance.Id(s.Prefix + "1"),
+		instance.Id(s.Prefix + "2"),
+	})
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(insts, gc.HasLen, 2)
+	c.Check(insts[0].Id(), gc.Equals, instance.Id(s.Prefix+"1"))
+	c.Check(insts[1], gc.IsNil)
+	addrs, err := insts[0].Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addrs, jc.DeepEquals, network.NewAddresses("10.0.3.5"))
+
+	s.Raw.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "Instances",
+		Args:     []interface{}{s.Prefix, instStatuses},
+	}})
+}
+
+func (s *environInstanceSuite) TestInstancesNone(c *gc.C) {
+	insts, err := s.Env.Instances([]instance.Id{instance.Id(s.Prefix + "0")})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+	c.Assert(insts, gc.HasLen, 1)
+}
+
+func (s *environInstanceSuite) TestStateServerInstances(c *gc.C) {
+	s.Raw.Insts = []lxdclient.Instance{
+		s.NewRawInstance(s.Prefix+"0", true),
+		s.NewRawInstance(s.Prefix+"1", false),
+	}
+
+	ids, err := s.Env.StateServerInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []instance.Id{instance.Id(s.Prefix + "0")})
+}
+
+func (s *environInstanceSuite) TestStateServerInstancesNotBootstrapped(c *gc.C) {
+	_, err := s.Env.StateServerInstances()
+	c.Assert(err, gc.Equals, environs.ErrNotBootstrapped)
+}
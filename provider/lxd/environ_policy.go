@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/network"
+)
+
+// PrecheckInstance verifies that the provided series and constraints
+// are valid for use in creating an instance in this environment.
+func (env *environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if placement != "" {
+		return errors.Errorf("unknown placement directive: %s", placement)
+	}
+	return nil
+}
+
+// SupportedArchitectures returns the image architectures which can
+// be hosted by this environment.
+func (env *environ) SupportedArchitectures() ([]string, error) {
+	env.archLock.Lock()
+	defer env.archLock.Unlock()
+
+	if env.supportedArchitectures != nil {
+		return env.supportedArchitectures, nil
+	}
+
+	lxdArches, err := env.raw.ServerArchitectures()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	archList := normaliseArches(lxdArches)
+	if len(archList) == 0 {
+		return nil, errors.Errorf("LXD server architectures %v not supported", lxdArches)
+	}
+	env.supportedArchitectures = archList
+	return archList, nil
+}
+
+var unsupportedConstraints = []string{
+	constraints.CpuPower,
+	constraints.RootDisk,
+	constraints.InstanceType,
+	constraints.Tags,
+	constraints.Container,
+	constraints.Networks,
+	constraints.Zones,
+	constraints.VirtType,
+	constraints.RootDiskSource,
+}
+
+// ConstraintsValidator returns a Validator value which is used to
+// validate and merge constraints.
+func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
+	validator := constraints.NewValidator()
+
+	// unsupported
+
+	validator.RegisterUnsupported(unsupportedConstraints)
+
+	// vocab
+
+	supportedArches, err := env.SupportedArchitectures()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	validator.RegisterVocabulary(constraints.Arch, supportedArches)
+
+	return validator, nil
+}
+
+// environ provides SupportsUnitPlacement (a method of the
+// state.EnvironCapatability interface) by embedding
+// common.SupportsUnitPlacementPolicy.
+
+// SupportNetworks returns whether the environment has support to
+// specify networks for services and machines.
+func (env *environ) SupportNetworks() bool {
+	return false
+}
+
+// SupportAddressAllocation takes a network.Id and returns a bool
+// and an error. The bool indicates whether that network supports
+// static ip address allocation.
+func (env *environ) SupportAddressAllocation(netID network.Id) (bool, error) {
+	return false, nil
+}
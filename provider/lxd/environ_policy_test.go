@@ -0,0 +1,38 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+)
+
+type environPolicySuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&environPolicySuite{})
+
+func (s *environPolicySuite) TestPrecheckInstance(c *gc.C) {
+	err := s.Env.PrecheckInstance("trusty", constraints.Value{}, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.Env.PrecheckInstance("trusty", constraints.Value{}, "zone=a")
+	c.Assert(err, gc.ErrorMatches, "unknown placement directive: zone=a")
+}
+
+func (s *environPolicySuite) TestConstraintsValidator(c *gc.C) {
+	validator, err := s.Env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("arch=amd64 cpu-cores=2 mem=1G root-disk=10G tags=foo root-disk-source=ssd")
+	unsupported, err := validator.Validate(cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(unsupported, jc.SameContents, []string{"root-disk", "root-disk-source", "tags"})
+
+	_, err = validator.Validate(constraints.MustParse("arch=ppc64el"))
+	c.Check(err, gc.ErrorMatches, "invalid constraint value: arch=ppc64el\nvalid values are:.*")
+}
@@ -0,0 +1,22 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/storage/provider/registry"
+)
+
+const (
+	providerType = "lxd"
+)
+
+func init() {
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Provider:   providerInstance,
+		APIVersion: environs.ProviderAPIVersion,
+	})
+	registry.RegisterEnvironStorageProviders(providerType)
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+type environInstance struct {
+	base *lxdclient.Instance
+	env  *environ
+}
+
+var _ instance.Instance = (*environInstance)(nil)
+
+func newInstance(base *lxdclient.Instance, env *environ) *environInstance {
+	return &environInstance{
+		base: base,
+		env:  env,
+	}
+}
+
+// Id implements instance.Instance.
+func (inst *environInstance) Id() instance.Id {
+	return instance.Id(inst.base.Name)
+}
+
+// Status implements instance.Instance.
+func (inst *environInstance) Status() instance.InstanceStatus {
+	return instance.InstanceStatus{Status: inst.base.Status}
+}
+
+// Addresses implements instance.Instance.
+func (inst *environInstance) Addresses() ([]network.Address, error) {
+	return inst.base.Addresses(), nil
+}
+
+func findInst(id instance.Id, instances []instance.Instance) instance.Instance {
+	for _, inst := range instances {
+		if id == inst.Id() {
+			return inst
+		}
+	}
+	return nil
+}
+
+// firewall stuff
+
+// Containers are attached to the LXD host's bridge, from which all of
+// their ports are reachable, so there is no firewall to manage.
+
+// OpenPorts opens the given ports on the instance, which
+// should have been started with the given machine id.
+func (inst *environInstance) OpenPorts(machineID string, ports []network.PortRange) error {
+	return nil
+}
+
+// ClosePorts closes the given ports on the instance, which
+// should have been started with the given machine id.
+func (inst *environInstance) ClosePorts(machineID string, ports []network.PortRange) error {
+	return nil
+}
+
+// Ports returns the set of ports open on the instance, which
+// should have been started with the given machine id.
+func (inst *environInstance) Ports(machineID string) ([]network.PortRange, error) {
+	return nil, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs/tags"
+)
+
+// The metadata keys used when creating new instances.
+const (
+	metadataKeyIsState = tags.JujuStateServer
+	// This is read by cloud-init's LXD support, from the container's
+	// "user.user-data" config key.
+	metadataKeyCloudInit = "user-data"
+)
+
+// Common metadata values used when creating new instances.
+const (
+	metadataValueTrue  = "true"
+	metadataValueFalse = "false"
+)
+
+// The image servers from which container images are pulled, keyed
+// by image stream.
+var imageServers = map[string]string{
+	"released": "https://cloud-images.ubuntu.com/releases",
+	"daily":    "https://cloud-images.ubuntu.com/daily",
+}
+
+var logger = loggo.GetLogger("juju.provider.lxd")
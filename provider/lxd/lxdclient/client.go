@@ -0,0 +1,247 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+var logger = loggo.GetLogger("juju.provider.lxd.lxdclient")
+
+// DefaultSocketPath is the path of the unix socket on which the local
+// LXD daemon serves its REST API.
+const DefaultSocketPath = "/var/lib/lxd/unix.socket"
+
+// apiVersion is the version of the LXD REST API used by the client.
+const apiVersion = "1.0"
+
+// Config holds the information needed to connect to an LXD server.
+type Config struct {
+	// Remote is the URL of the LXD server's REST API, for example
+	// "https://10.0.0.1:8443". If it is empty then the local LXD
+	// daemon is used, via its unix socket.
+	Remote string
+	// ClientCert is the PEM-encoded certificate with which the client
+	// authenticates to a remote LXD server.
+	ClientCert string
+	// ClientKey is the PEM-encoded private key for ClientCert.
+	ClientKey string
+	// ServerCert is the PEM-encoded certificate that a remote LXD
+	// server is expected to present.
+	ServerCert string
+}
+
+// IsLocal reports whether the config refers to the local LXD daemon.
+func (cfg Config) IsLocal() bool {
+	return cfg.Remote == ""
+}
+
+// Validate checks that the config is complete and consistent.
+func (cfg Config) Validate() error {
+	if cfg.IsLocal() {
+		return nil
+	}
+	u, err := url.Parse(cfg.Remote)
+	if err != nil {
+		return errors.Annotatef(err, "invalid remote URL %q", cfg.Remote)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return errors.NotValidf("remote URL %q (expected https://host[:port])", cfg.Remote)
+	}
+	if cfg.ClientCert == "" || cfg.ClientKey == "" {
+		return errors.NotValidf("remote %q without client certificate and key", cfg.Remote)
+	}
+	if cfg.ServerCert == "" {
+		return errors.NotValidf("remote %q without server certificate", cfg.Remote)
+	}
+	return nil
+}
+
+// Client is a client for the LXD REST API. Its methods are limited
+// to those needed by the juju LXD provider.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// Connect returns a new Client for the LXD server identified by the
+// given config.
+func Connect(cfg Config) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if cfg.IsLocal() {
+		transport := &http.Transport{
+			Dial: func(_, _ string) (net.Conn, error) {
+				return net.Dial("unix", DefaultSocketPath)
+			},
+		}
+		return newClient("http://unix.socket", &http.Client{Transport: transport}), nil
+	}
+
+	cert, err := tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey))
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid client certificate")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(cfg.ServerCert)) {
+		return nil, errors.New("invalid server certificate")
+	}
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			RootCAs:      pool,
+		},
+	}
+	return newClient(strings.TrimSuffix(cfg.Remote, "/"), &http.Client{Transport: transport}), nil
+}
+
+func newClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL: baseURL,
+		http:    httpClient,
+	}
+}
+
+// response is the envelope in which the LXD REST API returns the
+// result of every request.
+type response struct {
+	Type       string          `json:"type"`
+	Status     string          `json:"status"`
+	StatusCode int             `json:"status_code"`
+	Operation  string          `json:"operation"`
+	ErrorCode  int             `json:"error_code"`
+	Error      string          `json:"error"`
+	Metadata   json.RawMessage `json:"metadata"`
+}
+
+// operation holds the parts of an LXD background operation that the
+// client cares about.
+type operation struct {
+	ID         string `json:"id"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+	Err        string `json:"err"`
+}
+
+const (
+	responseSync  = "sync"
+	responseAsync = "async"
+	responseError = "error"
+
+	// operationSuccess is the status code of an operation that
+	// completed successfully.
+	operationSuccess = 200
+)
+
+// call sends a request with the given method and body to the API
+// path, waiting for any resulting background operation to complete.
+// The metadata of a synchronous response is unmarshalled into result,
+// if it is not nil.
+func (c *Client) call(method, path string, body, result interface{}) error {
+	resp, err := c.send(method, path, body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch resp.Type {
+	case responseSync:
+		if result == nil || len(resp.Metadata) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(resp.Metadata, result); err != nil {
+			return errors.Annotatef(err, "cannot parse response to %s %s", method, path)
+		}
+		return nil
+	case responseAsync:
+		return errors.Trace(c.wait(resp.Operation))
+	}
+	return errors.Errorf("unexpected %q response to %s %s", resp.Type, method, path)
+}
+
+// wait blocks until the operation at the given path completes.
+func (c *Client) wait(opPath string) error {
+	if opPath == "" {
+		return errors.New("no operation to wait for")
+	}
+	var op operation
+	if err := c.call("GET", strings.TrimPrefix(opPath, "/"+apiVersion)+"/wait", nil, &op); err != nil {
+		return errors.Trace(err)
+	}
+	if op.StatusCode != operationSuccess {
+		if op.Err != "" {
+			return errors.New(op.Err)
+		}
+		return errors.Errorf("operation %s %s", op.ID, strings.ToLower(op.Status))
+	}
+	return nil
+}
+
+// send sends a request to the API and returns the decoded response.
+// Error responses are converted into errors.
+func (c *Client) send(method, path string, body interface{}) (*response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	reqURL := fmt.Sprintf("%s/%s%s", c.baseURL, apiVersion, path)
+	logger.Tracef("%s %s", method, reqURL)
+	req, err := http.NewRequest(method, reqURL, reqBody)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	httpResp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to LXD")
+	}
+	defer httpResp.Body.Close()
+
+	var resp response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse response to %s %s", method, path)
+	}
+	if resp.Type == responseError {
+		if resp.ErrorCode == http.StatusNotFound {
+			return nil, errors.NewNotFound(nil, resp.Error)
+		}
+		return nil, errors.Errorf("%s %s: %s", method, path, resp.Error)
+	}
+	return &resp, nil
+}
+
+// serverInfo holds the parts of the LXD server's description that the
+// client cares about.
+type serverInfo struct {
+	Environment struct {
+		Architectures []string `json:"architectures"`
+	} `json:"environment"`
+}
+
+// ServerArchitectures returns the names, as reported by the server,
+// of the architectures that the LXD server can run containers for.
+func (c *Client) ServerArchitectures() ([]string, error) {
+	var info serverInfo
+	if err := c.call("GET", "", nil, &info); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return info.Environment.Architectures, nil
+}
@@ -0,0 +1,286 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdclient_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+	"github.com/juju/juju/testing"
+)
+
+// fakeLXD is a minimal LXD REST API server.
+type fakeLXD struct {
+	server *httptest.Server
+	// responses maps "METHOD /path" to the response envelope
+	// returned for that request.
+	responses map[string]interface{}
+	// requests records the requests made, as "METHOD /path".
+	requests []string
+	// bodies records the decoded body of each request.
+	bodies []map[string]interface{}
+}
+
+func newFakeLXD() *fakeLXD {
+	f := &fakeLXD{responses: make(map[string]interface{})}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return f
+}
+
+func (f *fakeLXD) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	key := req.Method + " " + req.URL.Path
+	f.requests = append(f.requests, key)
+	var body map[string]interface{}
+	json.NewDecoder(req.Body).Decode(&body)
+	f.bodies = append(f.bodies, body)
+	resp, ok := f.responses[key]
+	if !ok {
+		resp = errorResponse(http.StatusNotFound, "not found")
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeLXD) sync(key string, metadata interface{}) {
+	f.responses[key] = map[string]interface{}{
+		"type":        "sync",
+		"status":      "Success",
+		"status_code": 200,
+		"metadata":    metadata,
+	}
+}
+
+func (f *fakeLXD) async(key, opID string) {
+	f.responses[key] = map[string]interface{}{
+		"type":        "async",
+		"status":      "Operation created",
+		"status_code": 100,
+		"operation":   "/1.0/operations/" + opID,
+	}
+	f.sync("GET /1.0/operations/"+opID+"/wait", map[string]interface{}{
+		"id":          opID,
+		"status":      "Success",
+		"status_code": 200,
+	})
+}
+
+func errorResponse(code int, msg string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "error",
+		"error_code": code,
+		"error":      msg,
+	}
+}
+
+type clientSuite struct {
+	testing.BaseSuite
+
+	lxd    *fakeLXD
+	client *lxdclient.Client
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.lxd = newFakeLXD()
+	s.AddCleanup(func(*gc.C) { s.lxd.server.Close() })
+	s.client = lxdclient.NewClientForTest(s.lxd.server.URL)
+}
+
+func (s *clientSuite) addContainer(name, status string, config map[string]string) {
+	s.lxd.sync("GET /1.0/containers/"+name, map[string]interface{}{
+		"name":         name,
+		"status":       status,
+		"architecture": "x86_64",
+		"config":       config,
+	})
+	s.lxd.sync("GET /1.0/containers/"+name+"/state", map[string]interface{}{
+		"status": status,
+		"network": map[string]interface{}{
+			"lo": map[string]interface{}{
+				"addresses": []map[string]string{
+					{"family": "inet", "address": "127.0.0.1", "scope": "local"},
+				},
+			},
+			"eth0": map[string]interface{}{
+				"addresses": []map[string]string{
+					{"family": "inet", "address": "10.0.3.5", "scope": "global"},
+					{"family": "inet6", "address": "fe80::1", "scope": "link"},
+				},
+			},
+		},
+	})
+}
+
+func (s *clientSuite) TestConfigValidate(c *gc.C) {
+	valid := lxdclient.Config{
+		Remote:     "https://10.0.0.1:8443",
+		ClientCert: "cert",
+		ClientKey:  "key",
+		ServerCert: "server cert",
+	}
+	c.Check(lxdclient.Config{}.Validate(), jc.ErrorIsNil)
+	c.Check(valid.Validate(), jc.ErrorIsNil)
+
+	cfg := valid
+	cfg.Remote = "http://10.0.0.1:8443"
+	c.Check(cfg.Validate(), gc.ErrorMatches, `remote URL "http://10.0.0.1:8443" \(expected https://host\[:port\]\) not valid`)
+
+	cfg = valid
+	cfg.ClientKey = ""
+	c.Check(cfg.Validate(), gc.ErrorMatches, `remote "https://10.0.0.1:8443" without client certificate and key not valid`)
+
+	cfg = valid
+	cfg.ServerCert = ""
+	c.Check(cfg.Validate(), gc.ErrorMatches, `remote "https://10.0.0.1:8443" without server certificate not valid`)
+}
+
+func (s *clientSuite) TestServerArchitectures(c *gc.C) {
+	s.lxd.sync("GET /1.0", map[string]interface{}{
+		"environment": map[string]interface{}{
+			"architectures": []string{"x86_64", "i686"},
+		},
+	})
+	arches, err := s.client.ServerArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(arches, jc.DeepEquals, []string{"x86_64", "i686"})
+}
+
+func (s *clientSuite) TestInstances(c *gc.C) {
+	s.lxd.sync("GET /1.0/containers", []string{
+		"/1.0/containers/juju-x-machine-0",
+		"/1.0/containers/juju-x-machine-1",
+		"/1.0/containers/other",
+	})
+	s.addContainer("juju-x-machine-0", lxdclient.StatusRunning, map[string]string{
+		"user.juju-is-state": "true",
+		"limits.cpu":         "2",
+	})
+	s.addContainer("juju-x-machine-1", lxdclient.StatusStopped, nil)
+	s.addContainer("other", lxdclient.StatusRunning, nil)
+
+	insts, err := s.client.Instances("juju-x-", lxdclient.StatusRunning)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts, gc.HasLen, 1)
+	c.Check(insts[0].Name, gc.Equals, "juju-x-machine-0")
+	c.Check(insts[0].Status, gc.Equals, lxdclient.StatusRunning)
+	c.Check(insts[0].Architecture, gc.Equals, "x86_64")
+	c.Check(insts[0].Metadata(), jc.DeepEquals, map[string]string{
+		"juju-is-state": "true",
+	})
+	c.Check(insts[0].Addresses(), jc.DeepEquals, network.NewAddresses("10.0.3.5"))
+
+	insts, err = s.client.Instances("juju-x-")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts, gc.HasLen, 2)
+	c.Check(insts[1].Name, gc.Equals, "juju-x-machine-1")
+	c.Check(insts[1].Addresses(), gc.HasLen, 0)
+}
+
+func (s *clientSuite) TestAddInstance(c *gc.C) {
+	s.lxd.async("POST /1.0/containers", "create")
+	s.lxd.async("PUT /1.0/containers/juju-x-machine-0/state", "start")
+	s.addContainer("juju-x-machine-0", lxdclient.StatusRunning, nil)
+
+	inst, err := s.client.AddInstance(lxdclient.InstanceSpec{
+		Name:        "juju-x-machine-0",
+		ImageServer: "https://cloud-images.ubuntu.com/releases",
+		ImageAlias:  "trusty",
+		Metadata:    map[string]string{"user-data": "#cloud-config"},
+		Config:      map[string]string{"limits.cpu": "2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(inst.Name, gc.Equals, "juju-x-machine-0")
+	c.Check(s.lxd.requests, jc.DeepEquals, []string{
+		"POST /1.0/containers",
+		"GET /1.0/operations/create/wait",
+		"PUT /1.0/containers/juju-x-machine-0/state",
+		"GET /1.0/operations/start/wait",
+		"GET /1.0/containers/juju-x-machine-0",
+		"GET /1.0/containers/juju-x-machine-0/state",
+	})
+	c.Check(s.lxd.bodies[0], jc.DeepEquals, map[string]interface{}{
+		"name": "juju-x-machine-0",
+		"config": map[string]interface{}{
+			"user.user-data": "#cloud-config",
+			"limits.cpu":     "2",
+		},
+		"source": map[string]interface{}{
+			"type":     "image",
+			"mode":     "pull",
+			"server":   "https://cloud-images.ubuntu.com/releases",
+			"protocol": "simplestreams",
+			"alias":    "trusty",
+		},
+	})
+	c.Check(s.lxd.bodies[2]["action"], gc.Equals, "start")
+}
+
+func (s *clientSuite) TestAddInstanceOperationFails(c *gc.C) {
+	s.lxd.async("POST /1.0/containers", "create")
+	s.lxd.sync("GET /1.0/operations/create/wait", map[string]interface{}{
+		"id":          "create",
+		"status":      "Failure",
+		"status_code": 400,
+		"err":         "image not found",
+	})
+	_, err := s.client.AddInstance(lxdclient.InstanceSpec{Name: "juju-x-machine-0"})
+	c.Assert(err, gc.ErrorMatches, `cannot create container "juju-x-machine-0": image not found`)
+}
+
+func (s *clientSuite) TestRemoveInstances(c *gc.C) {
+	s.addContainer("juju-x-machine-0", lxdclient.StatusRunning, nil)
+	s.addContainer("juju-x-machine-1", lxdclient.StatusStopped, nil)
+	s.lxd.async("PUT /1.0/containers/juju-x-machine-0/state", "stop")
+	s.lxd.async("DELETE /1.0/containers/juju-x-machine-0", "delete0")
+	s.lxd.async("DELETE /1.0/containers/juju-x-machine-1", "delete1")
+
+	err := s.client.RemoveInstances("juju-x-", "juju-x-machine-0", "juju-x-machine-1", "juju-x-machine-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.lxd.requests, jc.DeepEquals, []string{
+		"GET /1.0/containers/juju-x-machine-0",
+		"GET /1.0/containers/juju-x-machine-0/state",
+		"PUT /1.0/containers/juju-x-machine-0/state",
+		"GET /1.0/operations/stop/wait",
+		"DELETE /1.0/containers/juju-x-machine-0",
+		"GET /1.0/operations/delete0/wait",
+		"GET /1.0/containers/juju-x-machine-1",
+		"DELETE /1.0/containers/juju-x-machine-1",
+		"GET /1.0/operations/delete1/wait",
+		"GET /1.0/containers/juju-x-machine-2",
+	})
+	c.Check(s.lxd.bodies[2], jc.DeepEquals, map[string]interface{}{
+		"action":  "stop",
+		"timeout": float64(-1),
+		"force":   true,
+	})
+}
+
+func (s *clientSuite) TestRemoveInstancesChecksPrefix(c *gc.C) {
+	err := s.client.RemoveInstances("juju-x-", "other")
+	c.Assert(err, gc.ErrorMatches, `container "other" does not have prefix "juju-x-"`)
+	c.Assert(s.lxd.requests, gc.HasLen, 0)
+}
+
+func (s *clientSuite) TestErrorResponse(c *gc.C) {
+	s.lxd.responses["GET /1.0/containers"] = errorResponse(http.StatusForbidden, "not authorized")
+	_, err := s.client.Instances("juju-x-")
+	c.Assert(err, gc.ErrorMatches, "GET /containers: not authorized")
+}
+
+func (s *clientSuite) TestNotFoundResponse(c *gc.C) {
+	s.lxd.sync("GET /1.0/containers", []string{"/1.0/containers/juju-x-machine-0"})
+	_, err := s.client.Instances("juju-x-")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.client.ServerArchitectures()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,14 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdclient
+
+import (
+	"net/http"
+)
+
+// NewClientForTest returns a Client that sends requests to the
+// API at the given base URL using plain HTTP.
+func NewClientForTest(baseURL string) *Client {
+	return newClient(baseURL, http.DefaultClient)
+}
@@ -0,0 +1,285 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdclient
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+)
+
+// The various status values used for LXD containers.
+const (
+	StatusRunning  = "Running"
+	StatusStarting = "Starting"
+	StatusStopped  = "Stopped"
+	StatusStopping = "Stopping"
+	StatusFrozen   = "Frozen"
+	StatusError    = "Error"
+)
+
+// metadataPrefix is the prefix of the container config keys in which
+// user-specified metadata is stored.
+const metadataPrefix = "user."
+
+// InstanceSpec holds all the information needed to create a new LXD
+// container.
+type InstanceSpec struct {
+	// Name is the name of the container.
+	Name string
+	// ImageServer is the URL of the simplestreams server from which
+	// the container's image is pulled.
+	ImageServer string
+	// ImageAlias identifies the image on the image server.
+	ImageAlias string
+	// Profiles is the names of the LXD profiles applied to the
+	// container. If it is empty then the server's default profile is
+	// used.
+	Profiles []string
+	// Metadata is the user-specified metadata that will be
+	// initialized on the new container. Cloud-init reads the
+	// "user-data" key.
+	Metadata map[string]string
+	// Config holds raw LXD config for the container, for example
+	// resource limits.
+	Config map[string]string
+}
+
+func (spec InstanceSpec) config() map[string]string {
+	config := make(map[string]string)
+	for key, value := range spec.Config {
+		config[key] = value
+	}
+	for key, value := range spec.Metadata {
+		config[metadataPrefix+key] = value
+	}
+	return config
+}
+
+// Instance holds the information about an LXD container that juju
+// cares about.
+type Instance struct {
+	// Name is the name of the container.
+	Name string
+	// Status is the LXD status of the container.
+	Status string
+	// Architecture is the LXD name of the container's architecture.
+	Architecture string
+
+	config    map[string]string
+	addresses []network.Address
+}
+
+// NewInstance returns a new Instance with the given details. The
+// metadata is stored as it would be by AddInstance.
+func NewInstance(name, status string, metadata map[string]string, addrs []network.Address) *Instance {
+	spec := InstanceSpec{Metadata: metadata}
+	return &Instance{
+		Name:      name,
+		Status:    status,
+		config:    spec.config(),
+		addresses: addrs,
+	}
+}
+
+// Metadata returns the user-specified metadata of the container.
+func (inst Instance) Metadata() map[string]string {
+	metadata := make(map[string]string)
+	for key, value := range inst.config {
+		if strings.HasPrefix(key, metadataPrefix) {
+			metadata[strings.TrimPrefix(key, metadataPrefix)] = value
+		}
+	}
+	return metadata
+}
+
+// Addresses returns the network addresses of the container.
+func (inst Instance) Addresses() []network.Address {
+	return append([]network.Address(nil), inst.addresses...)
+}
+
+// container is the LXD API representation of a container.
+type container struct {
+	Name         string            `json:"name"`
+	Status       string            `json:"status"`
+	Architecture string            `json:"architecture"`
+	Config       map[string]string `json:"config"`
+}
+
+// containerState is the LXD API representation of the runtime state
+// of a container.
+type containerState struct {
+	Status  string `json:"status"`
+	Network map[string]struct {
+		Addresses []struct {
+			Family  string `json:"family"`
+			Address string `json:"address"`
+			Scope   string `json:"scope"`
+		} `json:"addresses"`
+	} `json:"network"`
+}
+
+// addresses returns the addresses in the container state that may be
+// used to reach the container from outside it.
+func (state containerState) addresses() []network.Address {
+	var addrs []network.Address
+	for ifname, iface := range state.Network {
+		if ifname == "lo" {
+			continue
+		}
+		for _, addr := range iface.Addresses {
+			if addr.Scope != "global" {
+				continue
+			}
+			addrs = append(addrs, network.NewAddress(addr.Address))
+		}
+	}
+	network.SortAddresses(addrs, false)
+	return addrs
+}
+
+// Instances returns the containers whose names start with the given
+// prefix and whose status is one of those given. If no statuses are
+// given then all matching containers are returned.
+func (c *Client) Instances(prefix string, statuses ...string) ([]Instance, error) {
+	var paths []string
+	if err := c.call("GET", "/containers", nil, &paths); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results []Instance
+	for _, p := range paths {
+		name := p[strings.LastIndex(p, "/")+1:]
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		inst, err := c.instance(name)
+		if errors.IsNotFound(err) {
+			// The container was removed while we were looking.
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(statuses) > 0 && !hasStatus(inst.Status, statuses) {
+			continue
+		}
+		results = append(results, *inst)
+	}
+	return results, nil
+}
+
+func hasStatus(status string, statuses []string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// instance returns the named container, including its addresses if
+// it is running.
+func (c *Client) instance(name string) (*Instance, error) {
+	var raw container
+	if err := c.call("GET", "/containers/"+name, nil, &raw); err != nil {
+		return nil, errors.Trace(err)
+	}
+	inst := &Instance{
+		Name:         raw.Name,
+		Status:       raw.Status,
+		Architecture: raw.Architecture,
+		config:       raw.Config,
+	}
+	if inst.Status == StatusRunning {
+		var state containerState
+		if err := c.call("GET", "/containers/"+name+"/state", nil, &state); err != nil {
+			return nil, errors.Trace(err)
+		}
+		inst.addresses = state.addresses()
+	}
+	return inst, nil
+}
+
+// AddInstance creates and starts a new container as described by the
+// spec, and returns it. The call blocks until the container has been
+// started or the request fails.
+func (c *Client) AddInstance(spec InstanceSpec) (*Instance, error) {
+	req := map[string]interface{}{
+		"name":   spec.Name,
+		"config": spec.config(),
+		"source": map[string]string{
+			"type":     "image",
+			"mode":     "pull",
+			"server":   spec.ImageServer,
+			"protocol": "simplestreams",
+			"alias":    spec.ImageAlias,
+		},
+	}
+	if len(spec.Profiles) > 0 {
+		req["profiles"] = spec.Profiles
+	}
+	if err := c.call("POST", "/containers", req, nil); err != nil {
+		return nil, errors.Annotatef(err, "cannot create container %q", spec.Name)
+	}
+	if err := c.changeState(spec.Name, "start"); err != nil {
+		if err := c.removeInstance(spec.Name); err != nil {
+			logger.Errorf("cannot remove container %q: %v", spec.Name, err)
+		}
+		return nil, errors.Annotatef(err, "cannot start container %q", spec.Name)
+	}
+	inst, err := c.instance(spec.Name)
+	return inst, errors.Trace(err)
+}
+
+// RemoveInstances stops and removes the named containers. Names that
+// do not start with the given prefix are rejected, so that containers
+// not created by the caller are left alone. Containers that do not
+// exist are ignored.
+func (c *Client) RemoveInstances(prefix string, names ...string) error {
+	var failed []string
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			return errors.Errorf("container %q does not have prefix %q", name, prefix)
+		}
+		if err := c.removeInstance(name); err != nil {
+			logger.Errorf("cannot remove container %q: %v", name, err)
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("some containers were not removed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (c *Client) removeInstance(name string) error {
+	inst, err := c.instance(name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if inst.Status != StatusStopped {
+		if err := c.changeState(name, "stop"); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	err = c.call("DELETE", "/containers/"+name, nil, nil)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// changeState requests the given state change of the named container,
+// and waits for it to complete.
+func (c *Client) changeState(name, action string) error {
+	req := map[string]interface{}{
+		"action":  action,
+		"timeout": -1,
+		"force":   action == "stop",
+	}
+	return errors.Trace(c.call("PUT", "/containers/"+name+"/state", req, nil))
+}
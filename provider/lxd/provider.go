@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
+
+type environProvider struct{}
+
+var providerInstance environProvider
+
+// Open implements environs.EnvironProvider.
+func (environProvider) Open(cfg *config.Config) (environs.Environ, error) {
+	env, err := newEnviron(cfg)
+	return env, errors.Trace(err)
+}
+
+// PrepareForBootstrap implements environs.EnvironProvider.
+func (p environProvider) PrepareForBootstrap(ctx environs.BootstrapContext, cfg *config.Config) (environs.Environ, error) {
+	cfg, err := p.PrepareForCreateEnvironment(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	env, err := newEnviron(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if ctx.ShouldVerifyCredentials() {
+		// Any request will fail if the server cannot be reached
+		// or does not trust our certificate.
+		if _, err := env.raw.ServerArchitectures(); err != nil {
+			return nil, errors.Annotate(err, "cannot connect to LXD")
+		}
+	}
+	return env, nil
+}
+
+// PrepareForCreateEnvironment is specified in the EnvironProvider interface.
+func (environProvider) PrepareForCreateEnvironment(cfg *config.Config) (*config.Config, error) {
+	return cfg, nil
+}
+
+// RestrictedConfigAttributes is specified in the EnvironProvider interface.
+func (environProvider) RestrictedConfigAttributes() []string {
+	return []string{
+		cfgRemoteURL,
+		cfgClientCert,
+		cfgClientKey,
+		cfgServerCert,
+	}
+}
+
+// Validate implements environs.EnvironProvider.
+func (environProvider) Validate(cfg, old *config.Config) (valid *config.Config, err error) {
+	if old == nil {
+		ecfg, err := newValidConfig(cfg, configDefaults)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid config")
+		}
+		return ecfg.Config, nil
+	}
+
+	// The defaults should be set already, so we pass nil.
+	ecfg, err := newValidConfig(old, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid base config")
+	}
+
+	if err := ecfg.update(cfg); err != nil {
+		return nil, errors.Annotate(err, "invalid config change")
+	}
+
+	return ecfg.Config, nil
+}
+
+// Schema implements environs.ProviderSchema.
+func (environProvider) Schema() environschema.Fields {
+	fields, err := config.Schema(configSchema)
+	if err != nil {
+		panic(err)
+	}
+	return fields
+}
+
+// SecretAttrs implements environs.EnvironProvider.
+func (environProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
+	// The defaults should be set already, so we pass nil.
+	ecfg, err := newValidConfig(cfg, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ecfg.secret(), nil
+}
+
+// BoilerplateConfig implements environs.EnvironProvider.
+func (environProvider) BoilerplateConfig() string {
+	// boilerplateConfig is kept in config.go, in the hope that people editing
+	// config will keep it up to date.
+	return boilerplateConfig
+}
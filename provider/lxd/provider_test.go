@@ -0,0 +1,43 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	envtesting "github.com/juju/juju/environs/testing"
+)
+
+type providerSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&providerSuite{})
+
+func (s *providerSuite) TestRegistered(c *gc.C) {
+	provider, err := environs.Provider("lxd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(provider, gc.Equals, providerInstance)
+}
+
+func (s *providerSuite) TestPrepareForBootstrap(c *gc.C) {
+	env, err := providerInstance.PrepareForBootstrap(envtesting.BootstrapContext(c), s.Config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env, gc.NotNil)
+	s.Raw.CheckCallNames(c, "ServerArchitectures")
+}
+
+func (s *providerSuite) TestPrepareForBootstrapCannotConnect(c *gc.C) {
+	s.Raw.SetErrors(errors.New("connection refused"))
+	_, err := providerInstance.PrepareForBootstrap(envtesting.BootstrapContext(c), s.Config)
+	c.Assert(err, gc.ErrorMatches, "cannot connect to LXD: connection refused")
+}
+
+func (s *providerSuite) TestBoilerplateConfig(c *gc.C) {
+	c.Assert(providerInstance.BoilerplateConfig(), gc.Matches, "(?s)lxd:\n  type: lxd\n.*")
+}
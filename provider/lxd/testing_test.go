@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+// ConfigAttrs are fake config values for use in tests.
+var ConfigAttrs = testing.FakeConfig().Merge(testing.Attrs{
+	"type":        "lxd",
+	"remote-url":  "",
+	"client-cert": "",
+	"client-key":  "",
+	"server-cert": "",
+	"uuid":        "2d02eeac-9dbb-11e4-89d3-123b93f75cba",
+})
+
+var _ environs.Environ = (*environ)(nil)
+var _ instance.Instance = (*environInstance)(nil)
+
+type BaseSuite struct {
+	testing.BaseSuite
+
+	Config *config.Config
+	Env    *environ
+	Prefix string
+	Raw    *fakeRaw
+
+	StartInstArgs environs.StartInstanceParams
+}
+
+func (s *BaseSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.Raw = &fakeRaw{
+		Stub:   &gitjujutesting.Stub{},
+		Arches: []string{"x86_64", "i686"},
+	}
+	s.PatchValue(&newRawProvider, func(*environConfig) (rawProvider, error) {
+		return s.Raw, nil
+	})
+
+	s.Config = s.NewConfig(c, nil)
+	env, err := newEnviron(s.Config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Env = env
+	s.Prefix = "juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba-machine-"
+
+	envTools := []*tools.Tools{{
+		Version: version.Binary{Arch: arch.AMD64, Series: "trusty"},
+		URL:     "https://example.org",
+	}}
+	instanceConfig, err := instancecfg.NewBootstrapInstanceConfig(constraints.Value{}, "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Tools = envTools[0]
+	instanceConfig.AuthorizedKeys = s.Config.AuthorizedKeys()
+	s.StartInstArgs = environs.StartInstanceParams{
+		InstanceConfig: instanceConfig,
+		Tools:          envTools,
+	}
+}
+
+func (s *BaseSuite) NewConfig(c *gc.C, updates testing.Attrs) *config.Config {
+	cfg, err := config.New(config.NoDefaults, ConfigAttrs.Merge(updates))
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func (s *BaseSuite) NewRawInstance(name string, isState bool) lxdclient.Instance {
+	metadata := map[string]string{metadataKeyIsState: metadataValueFalse}
+	if isState {
+		metadata[metadataKeyIsState] = metadataValueTrue
+	}
+	addrs := network.NewAddresses("10.0.3.5")
+	return *lxdclient.NewInstance(name, lxdclient.StatusRunning, metadata, addrs)
+}
+
+// fakeRaw is a fake rawProvider that records the calls made to it.
+type fakeRaw struct {
+	*gitjujutesting.Stub
+
+	Arches []string
+	Insts  []lxdclient.Instance
+	Inst   *lxdclient.Instance
+}
+
+func (raw *fakeRaw) ServerArchitectures() ([]string, error) {
+	raw.AddCall("ServerArchitectures")
+	return raw.Arches, raw.NextErr()
+}
+
+func (raw *fakeRaw) Instances(prefix string, statuses ...string) ([]lxdclient.Instance, error) {
+	raw.AddCall("Instances", prefix, statuses)
+	return raw.Insts, raw.NextErr()
+}
+
+func (raw *fakeRaw) AddInstance(spec lxdclient.InstanceSpec) (*lxdclient.Instance, error) {
+	raw.AddCall("AddInstance", spec)
+	return raw.Inst, raw.NextErr()
+}
+
+func (raw *fakeRaw) RemoveInstances(prefix string, names ...string) error {
+	raw.AddCall("RemoveInstances", prefix, names)
+	return raw.NextErr()
+}
@@ -0,0 +1,22 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+	jujuos "github.com/juju/utils/os"
+)
+
+// LXDRenderer renders user data for LXD containers. Cloud-init reads
+// it, unencoded, from the container's config.
+type LXDRenderer struct{}
+
+func (LXDRenderer) EncodeUserdata(udata []byte, os jujuos.OSType) ([]byte, error) {
+	switch os {
+	case jujuos.Ubuntu:
+		return udata, nil
+	default:
+		return nil, errors.Errorf("cannot encode userdata for OS %q", os.String())
+	}
+}
@@ -24,6 +24,7 @@ var configSchema = environschema.Fields{
 	"maas-oauth": {
 		Description: "maas-oauth holds the OAuth credentials from MAAS.",
 		Type:        environschema.Tstring,
+		Secret:      true,
 	},
 	"maas-agent-name": {
 		Description: "maas-agent-name is an optional UUID to group the instances acquired from MAAS, to support multiple environments per MAAS user.",
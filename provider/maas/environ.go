@@ -617,8 +617,18 @@ func convertConstraints(cons constraints.Value) url.Values {
 	if cons.Mem != nil {
 		params.Add("mem", fmt.Sprintf("%d", *cons.Mem))
 	}
-	if cons.Tags != nil && len(*cons.Tags) > 0 {
-		tags, notTags := parseTags(*cons.Tags)
+	var rawTags []string
+	if cons.Tags != nil {
+		rawTags = append(rawTags, *cons.Tags...)
+	}
+	if cons.HasVirtType() {
+		// MAAS has no notion of virtualisation support, so nodes
+		// able to host the requested virt-type are identified by a
+		// tag of the same name, e.g. "kvm".
+		rawTags = append(rawTags, *cons.VirtType)
+	}
+	if len(rawTags) > 0 {
+		tags, notTags := parseTags(rawTags)
 		if len(tags) > 0 {
 			params.Add("tags", strings.Join(tags, ","))
 		}
@@ -629,6 +639,15 @@ func convertConstraints(cons constraints.Value) url.Values {
 	if cons.CpuPower != nil {
 		logger.Warningf("ignoring unsupported constraint 'cpu-power'")
 	}
+	// MAAS can only be asked for nodes with at least the given
+	// number of cores and memory; the upper bounds of ranges are
+	// ignored.
+	if cons.MaxCpuCores != nil {
+		logger.Warningf("ignoring unsupported constraint 'max-cpu-cores'")
+	}
+	if cons.MaxMem != nil {
+		logger.Warningf("ignoring unsupported constraint 'max-mem'")
+	}
 	return params
 }
 
@@ -768,6 +787,8 @@ func (environ *maasEnviron) startNode(node gomaasapi.MAASObject, series string,
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
+	constraints.MaxCpuCores,
+	constraints.MaxMem,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -779,6 +800,8 @@ func (environ *maasEnviron) ConstraintsValidator() (constraints.Validator, error
 		return nil, err
 	}
 	validator.RegisterVocabulary(constraints.Arch, supportedArches)
+	validator.RegisterVocabulary(constraints.RootDiskSource, []string{"ssd", "hdd"})
+	validator.RegisterVocabulary(constraints.VirtType, []string{"kvm"})
 	return validator, nil
 }
 
@@ -899,6 +922,16 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 			}
 		}
 	}
+
+	// If zones= constraints is set, then only use the zones it lists,
+	// unless a specific node has been requested.
+	if nodeName == "" {
+		var err error
+		availabilityZones, err = common.FilterZonesByConstraints(availabilityZones, args.Constraints)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	if len(availabilityZones) == 0 {
 		availabilityZones = []string{""}
 	}
@@ -940,6 +973,14 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 		}
 	}()
 
+	if args.Constraints.HasRootDiskSource() {
+		// MAAS matches storage tags on a best-effort basis, so make
+		// sure the root disk we were given is of the requested kind.
+		if err = inst.checkRootDiskSource(*args.Constraints.RootDiskSource); err != nil {
+			return nil, errors.Annotate(err, "cannot run instances")
+		}
+	}
+
 	hc, err := inst.hardwareCharacteristics()
 	if err != nil {
 		return nil, err
@@ -497,10 +497,17 @@ var testValues = []struct {
 
 	// CpuPower is ignored.
 	{constraints.Value{CpuPower: uint64p(1024)}, url.Values{}},
+	// The upper bounds of ranges are ignored.
+	{constraints.Value{CpuCores: uint64p(4), MaxCpuCores: uint64p(8)}, url.Values{"cpu_count": {"4"}}},
+	{constraints.Value{Mem: uint64p(1024), MaxMem: uint64p(4096)}, url.Values{"mem": {"1024"}}},
 
 	// RootDisk is ignored.
 	{constraints.Value{RootDisk: uint64p(8192)}, url.Values{}},
 	{constraints.Value{Tags: &[]string{"foo", "bar"}}, url.Values{"tags": {"foo,bar"}}},
+
+	// VirtType is requested as a node tag.
+	{constraints.Value{VirtType: stringp("kvm")}, url.Values{"tags": {"kvm"}}},
+	{constraints.Value{VirtType: stringp("kvm"), Tags: &[]string{"foo", "^bar"}}, url.Values{"tags": {"foo,kvm"}, "not_tags": {"bar"}}},
 	{constraints.Value{Arch: stringp("arm"), CpuCores: uint64p(4), Mem: uint64p(1024), CpuPower: uint64p(1024), RootDisk: uint64p(8192), Tags: &[]string{"foo", "bar"}}, url.Values{"arch": {"arm"}, "cpu_count": {"4"}, "mem": {"1024"}, "tags": {"foo,bar"}}},
 }
 
@@ -804,10 +811,10 @@ func (suite *environSuite) TestConstraintsValidator(c *gc.C) {
 	env := suite.makeEnviron()
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 cpu-power=10 instance-type=foo")
+	cons := constraints.MustParse("arch=amd64 cpu-power=10 instance-type=foo cpu-cores=2..4 mem=1G..4G")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "instance-type"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "instance-type", "max-cpu-cores", "max-mem"})
 }
 
 func (suite *environSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -821,6 +828,41 @@ func (suite *environSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: arch=ppc64el\nvalid values are: \\[amd64 armhf\\]")
 }
 
+func (suite *environSuite) TestConstraintsValidatorRootDiskSource(c *gc.C) {
+	suite.testMAASObject.TestServer.AddBootImage("uuid-0", `{"architecture": "amd64", "release": "trusty"}`)
+	env := suite.makeEnviron()
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("root-disk-source=ssd"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("root-disk-source=tape"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: root-disk-source=tape\nvalid values are: \\[ssd hdd\\]")
+}
+
+func (suite *environSuite) TestConstraintsValidatorVirtType(c *gc.C) {
+	suite.testMAASObject.TestServer.AddBootImage("uuid-0", `{"architecture": "amd64", "release": "trusty"}`)
+	env := suite.makeEnviron()
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("virt-type=kvm"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("virt-type=lxc"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: virt-type=lxc\nvalid values are: \\[kvm\\]")
+}
+
+func (suite *environSuite) TestHealthChecks(c *gc.C) {
+	env := suite.makeEnviron()
+	checks := env.HealthChecks()
+	c.Assert(checks, gc.HasLen, 2)
+	c.Assert(checks[0].Name, gc.Equals, "credentials")
+	c.Assert(checks[0].Check(), jc.ErrorIsNil)
+	c.Assert(checks[1].Name, gc.Equals, "available-nodes")
+	c.Assert(checks[1].Check(), gc.ErrorMatches, "no nodes are ready to be acquired")
+
+	suite.testMAASObject.TestServer.NewNode(`{"system_id": "node0", "status": 4}`)
+	c.Assert(checks[1].Check(), jc.ErrorIsNil)
+}
+
 func (suite *environSuite) TestGetNetworkMACs(c *gc.C) {
 	env := suite.makeEnviron()
 
@@ -1628,6 +1670,23 @@ func (s *environSuite) TestStartInstanceStorage(c *gc.C) {
 	})
 }
 
+func (s *environSuite) TestStartInstanceRootDiskSourceMismatch(c *gc.C) {
+	env := s.bootstrap(c)
+	s.newNode(c, "thenode1", "host1", map[string]interface{}{
+		"memory":                  8192,
+		"physicalblockdevice_set": nodeStorageAttrs,
+		"constraint_map":          storageConstraintAttrs,
+	})
+	params := environs.StartInstanceParams{
+		Constraints: constraints.MustParse("root-disk-source=ssd"),
+	}
+	_, err := testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, "cannot run instances: root disk is not ssd: no tags")
+	operations := s.testMAASObject.TestServer.NodesOperations()
+	c.Check(operations, gc.DeepEquals, []string{"acquire", "acquire", "release"})
+	c.Assert(s.testMAASObject.TestServer.OwnedNodes()["thenode1"], jc.IsFalse)
+}
+
 func (s *environSuite) TestStartInstanceUnsupportedStorage(c *gc.C) {
 	env := s.bootstrap(c)
 	s.newNode(c, "thenode1", "host1", map[string]interface{}{
@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maas
+
+import (
+	"net/url"
+
+	"github.com/juju/errors"
+	"launchpad.net/gomaasapi"
+
+	"github.com/juju/juju/environs"
+)
+
+var _ environs.HealthChecks = (*maasEnviron)(nil)
+
+// HealthChecks is specified in the environs.HealthChecks interface.
+func (environ *maasEnviron) HealthChecks() []environs.HealthCheck {
+	return []environs.HealthCheck{{
+		Name:  "credentials",
+		Check: environ.checkCredentials,
+	}, {
+		Name:  "available-nodes",
+		Check: environ.checkAvailableNodes,
+	}}
+}
+
+// checkCredentials verifies that the MAAS server is reachable and
+// accepts the environment's OAuth credentials.
+func (environ *maasEnviron) checkCredentials() error {
+	_, err := environ.AllInstances()
+	if err, ok := err.(gomaasapi.ServerError); ok && err.StatusCode == 401 {
+		return errors.New("authentication failed: check the maas-oauth setting")
+	}
+	return errors.Annotate(err, "cannot list allocated nodes")
+}
+
+// checkAvailableNodes verifies that there is at least one node ready
+// to be acquired by the environment.
+func (environ *maasEnviron) checkAvailableNodes() error {
+	filter := make(url.Values)
+	filter.Add("status", gomaasapi.NodeStatusReady)
+	nodes, err := environ.instances(filter)
+	if err != nil {
+		return errors.Annotate(err, "cannot list nodes")
+	}
+	if len(nodes) == 0 {
+		return errors.New("no nodes are ready to be acquired")
+	}
+	return nil
+}
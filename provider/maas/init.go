@@ -13,7 +13,16 @@ const (
 )
 
 func init() {
-	environs.RegisterProvider(providerType, maasEnvironProvider{})
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Provider:   maasEnvironProvider{},
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityNetworking,
+			environs.CapabilityStorage,
+			environs.CapabilityAvailabilityZones,
+		},
+	})
 
 	//Register the MAAS specific storage providers.
 	registry.RegisterProvider(maasStorageProviderType, &maasStorageProvider{})
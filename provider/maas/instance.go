@@ -38,13 +38,17 @@ func maasObjectId(maasObject *gomaasapi.MAASObject) instance.Id {
 	return instance.Id(maasObject.URI().String())
 }
 
-func (mi *maasInstance) Status() string {
-	// MAAS does not track node status once they're allocated.
-	// Since any instance that juju knows about will be an
-	// allocated one, it doesn't make sense to report any
-	// state unless we obtain it through some means other than
-	// through the MAAS API.
-	return ""
+// Status returns the node's substatus, e.g. "deploying", along with
+// the latest message MAAS has recorded for it.
+func (mi *maasInstance) Status() instance.InstanceStatus {
+	// MAAS versions before 1.9 do not track node status once
+	// they're allocated, in which case there's nothing to report.
+	name, _ := mi.maasObject.GetField("substatus_name")
+	message, _ := mi.maasObject.GetField("substatus_message")
+	return instance.InstanceStatus{
+		Status:  strings.ToLower(name),
+		Message: message,
+	}
 }
 
 func (mi *maasInstance) Addresses() ([]network.Address, error) {
@@ -9,6 +9,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 )
 
@@ -48,6 +49,31 @@ func (s *instanceTest) TestStringWithoutHostname(c *gc.C) {
 	c.Assert(fmt.Sprint(instance), gc.Equals, expected)
 }
 
+func (s *instanceTest) TestStatus(c *gc.C) {
+	jsonValue := `{
+			"system_id": "system_id",
+			"substatus_name": "Deploying",
+			"substatus_message": "waiting for MAAS deployment"
+		}`
+	obj := s.testMAASObject.TestServer.NewNode(jsonValue)
+	inst := maasInstance{&obj}
+
+	c.Check(inst.Status(), jc.DeepEquals, instance.InstanceStatus{
+		Status:  "deploying",
+		Message: "waiting for MAAS deployment",
+	})
+}
+
+func (s *instanceTest) TestStatusMissing(c *gc.C) {
+	// Older MAAS versions do not report the status of allocated
+	// nodes.
+	jsonValue := `{"system_id": "system_id"}`
+	obj := s.testMAASObject.TestServer.NewNode(jsonValue)
+	inst := maasInstance{&obj}
+
+	c.Check(inst.Status(), jc.DeepEquals, instance.InstanceStatus{})
+}
+
 func (s *instanceTest) TestAddresses(c *gc.C) {
 	jsonValue := `{
 			"hostname": "testing.invalid",
@@ -134,7 +134,7 @@ func mibToGb(m uint64) uint64 {
 // buildMAASVolumeParameters creates the MAAS volume information to include
 // in a request to acquire a MAAS node, based on the supplied storage parameters.
 func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Value) ([]volumeInfo, error) {
-	if len(args) == 0 && cons.RootDisk == nil {
+	if len(args) == 0 && cons.RootDisk == nil && !cons.HasRootDiskSource() {
 		return nil, nil
 	}
 	volumes := make([]volumeInfo, len(args)+1)
@@ -142,6 +142,13 @@ func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Val
 	if cons.RootDisk != nil {
 		rootVolume.sizeInGB = mibToGb(*cons.RootDisk)
 	}
+	if cons.HasRootDiskSource() {
+		tag, err := rootDiskSourceTag(*cons.RootDiskSource)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rootVolume.tags = []string{tag}
+	}
 	volumes[0] = rootVolume
 	for i, v := range args {
 		cfg, err := newStorageConfig(v.Attributes)
@@ -158,6 +165,85 @@ func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Val
 	return volumes, nil
 }
 
+// rootDiskSourceTags maps the values of the root-disk-source
+// constraint to the MAAS storage tags identifying those disks.
+var rootDiskSourceTags = map[string]string{
+	"ssd": "ssd",
+	"hdd": "rotary",
+}
+
+// rootDiskSourceTag returns the MAAS storage tag
+// corresponding to the given root-disk-source.
+func rootDiskSourceTag(source string) (string, error) {
+	tag, ok := rootDiskSourceTags[source]
+	if !ok {
+		return "", errors.NotValidf("root-disk-source %q", source)
+	}
+	return tag, nil
+}
+
+// checkRootDiskSource verifies that the disk MAAS allocated as the
+// root disk of the node carries the tag required by the given
+// root-disk-source.
+func (mi *maasInstance) checkRootDiskSource(source string) error {
+	tag, err := rootDiskSourceTag(source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	deviceInfo, ok := mi.maasObject.GetMap()["physicalblockdevice_set"]
+	if !ok || deviceInfo.IsNil() {
+		return errors.NotSupportedf("root-disk-source on MAAS without storage support")
+	}
+	labelsMap, ok := mi.maasObject.GetMap()["constraint_map"]
+	if !ok || labelsMap.IsNil() {
+		return errors.NotFoundf("constraint map field")
+	}
+	devices, err := deviceInfo.GetArray()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	deviceLabels, err := labelsMap.GetMap()
+	if err != nil {
+		return errors.Annotate(err, "invalid constraint map value")
+	}
+	for _, d := range devices {
+		deviceAttrs, err := d.GetMap()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		id, err := deviceAttrs["id"].GetFloat64()
+		if err != nil {
+			return errors.Annotate(err, "invalid device id")
+		}
+		deviceLabelValue, ok := deviceLabels[strconv.Itoa(int(id))]
+		if !ok {
+			continue
+		}
+		deviceLabel, err := deviceLabelValue.GetString()
+		if err != nil {
+			return errors.Annotate(err, "invalid device label")
+		}
+		if deviceLabel != rootDiskLabel {
+			continue
+		}
+		tagsValue, ok := deviceAttrs["tags"]
+		if !ok || tagsValue.IsNil() {
+			return errors.Errorf("root disk is not %s: no tags", source)
+		}
+		tags, err := tagsValue.GetArray()
+		if err != nil {
+			return errors.Annotate(err, "invalid device tags")
+		}
+		for _, t := range tags {
+			if s, err := t.GetString(); err == nil && s == tag {
+				return nil
+			}
+		}
+		return errors.Errorf("root disk is not %s: missing tag %q", source, tag)
+	}
+	return errors.NotFoundf("root disk")
+}
+
 // volumes creates the storage volumes and attachments
 // corresponding to the volume info associated with a MAAS node.
 func (mi *maasInstance) volumes(
@@ -4,6 +4,7 @@
 package maas
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -72,6 +73,36 @@ func (s *volumeSuite) TestBuildMAASVolumeParametersWithTags(c *gc.C) {
 	})
 }
 
+func (s *volumeSuite) TestBuildMAASVolumeParametersWithRootDiskSource(c *gc.C) {
+	cons := constraints.MustParse("root-disk=20000M root-disk-source=hdd")
+	vInfo, err := buildMAASVolumeParameters(nil, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 20, []string{"rotary"}},
+	})
+}
+
+func (s *volumeSuite) TestBuildMAASVolumeParametersInvalidRootDiskSource(c *gc.C) {
+	cons := constraints.MustParse("root-disk-source=floppy")
+	_, err := buildMAASVolumeParameters(nil, cons)
+	c.Assert(err, gc.ErrorMatches, `root-disk-source "floppy" not valid`)
+}
+
+func (s *volumeSuite) TestInstanceCheckRootDiskSource(c *gc.C) {
+	obj := s.testMAASObject.TestServer.NewNode(validVolumeJson)
+	instance := maasInstance{&obj}
+	c.Assert(instance.checkRootDiskSource("ssd"), jc.ErrorIsNil)
+	err := instance.checkRootDiskSource("hdd")
+	c.Assert(err, gc.ErrorMatches, `root disk is not hdd: missing tag "rotary"`)
+}
+
+func (s *volumeSuite) TestInstanceCheckRootDiskSourceOldMaas(c *gc.C) {
+	obj := s.testMAASObject.TestServer.NewNode(`{"system_id": "node0"}`)
+	instance := maasInstance{&obj}
+	err := instance.checkRootDiskSource("ssd")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *volumeSuite) TestInstanceVolumes(c *gc.C) {
 	obj := s.testMAASObject.TestServer.NewNode(validVolumeJson)
 	instance := maasInstance{&obj}
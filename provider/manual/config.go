@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 )
@@ -14,14 +15,40 @@ import (
 const defaultStoragePort = 8040
 
 var (
-	configFields = schema.Fields{
-		"bootstrap-host":    schema.String(),
-		"bootstrap-user":    schema.String(),
-		"storage-listen-ip": schema.String(),
-		"storage-port":      schema.ForceInt(),
-		"storage-auth-key":  schema.String(),
-		"use-sshstorage":    schema.Bool(),
+	configSchema = environschema.Fields{
+		"bootstrap-host": {
+			Description: "The host name or IP address of the machine to bootstrap.",
+			Type:        environschema.Tstring,
+		},
+		"bootstrap-user": {
+			Description: "The user to log in as when bootstrapping. Defaults to the current user.",
+			Type:        environschema.Tstring,
+		},
+		"storage-listen-ip": {
+			Description: "The IP address the bootstrap machine's storage listens on.",
+			Type:        environschema.Tstring,
+		},
+		"storage-port": {
+			Description: "The port the bootstrap machine's storage listens on.",
+			Type:        environschema.Tint,
+		},
+		"storage-auth-key": {
+			Description: "The key used to authenticate requests to the bootstrap machine's storage.",
+			Type:        environschema.Tstring,
+			Secret:      true,
+		},
+		"use-sshstorage": {
+			Description: "Whether storage is accessed over SSH rather than HTTP.",
+			Type:        environschema.Tbool,
+		},
 	}
+	configFields = func() schema.Fields {
+		fs, _, err := configSchema.ValidationSchema()
+		if err != nil {
+			panic(err)
+		}
+		return fs
+	}()
 	configDefaults = schema.Defaults{
 		"bootstrap-user":    "",
 		"storage-listen-ip": "",
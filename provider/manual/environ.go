@@ -316,6 +316,9 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Zones,
+	constraints.VirtType,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -14,7 +14,12 @@ const (
 
 func init() {
 	p := manualProvider{}
-	environs.RegisterProvider(providerType, p, "null")
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Aliases:    []string{"null"},
+		Provider:   p,
+		APIVersion: environs.ProviderAPIVersion,
+	})
 
 	registry.RegisterEnvironStorageProviders(providerType)
 }
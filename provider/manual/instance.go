@@ -17,8 +17,8 @@ func (manualBootstrapInstance) Id() instance.Id {
 	return BootstrapInstanceId
 }
 
-func (manualBootstrapInstance) Status() string {
-	return ""
+func (manualBootstrapInstance) Status() instance.InstanceStatus {
+	return instance.InstanceStatus{}
 }
 
 func (manualBootstrapInstance) Refresh() error {
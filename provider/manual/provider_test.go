@@ -10,6 +10,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -27,7 +28,7 @@ var _ = gc.Suite(&providerSuite{})
 
 func (s *providerSuite) SetUpTest(c *gc.C) {
 	s.FakeJujuHomeSuite.SetUpTest(c)
-	s.PatchValue(manual.InitUbuntuUser, func(host, user, keys string, stdin io.Reader, stdout io.Writer) error {
+	s.PatchValue(manual.InitUbuntuUser, func(host, user, keys, via string, stdin io.Reader, stdout io.Writer) error {
 		return nil
 	})
 }
@@ -100,6 +101,13 @@ func (s *providerSuite) TestNullAlias(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *providerSuite) TestSchema(c *gc.C) {
+	fields := manual.ProviderInstance.Schema()
+	c.Check(fields["storage-auth-key"].Secret, jc.IsTrue)
+	c.Check(fields["bootstrap-host"].Secret, jc.IsFalse)
+	c.Check(fields["storage-port"].Type, gc.Equals, environschema.Tint)
+}
+
 func (s *providerSuite) TestDisablesUpdatesByDefault(c *gc.C) {
 	p, err := environs.Provider("manual")
 	c.Assert(err, jc.ErrorIsNil)
@@ -14,24 +14,10 @@ import (
 // The instance type comes from querying the flavors supported by the deployment.
 func findInstanceSpec(e *environ, ic *instances.InstanceConstraint) (*instances.InstanceSpec, error) {
 	// first construct all available instance types from the supported flavors.
-	nova := e.nova()
-	flavors, err := nova.ListFlavorsDetail()
+	allInstanceTypes, err := flavorInstanceTypes(e, ic.Arches)
 	if err != nil {
 		return nil, err
 	}
-	allInstanceTypes := []instances.InstanceType{}
-	for _, flavor := range flavors {
-		instanceType := instances.InstanceType{
-			Id:       flavor.Id,
-			Name:     flavor.Name,
-			Arches:   ic.Arches,
-			Mem:      uint64(flavor.RAM),
-			CpuCores: uint64(flavor.VCPUs),
-			RootDisk: uint64(flavor.Disk * 1024),
-			// tags not currently supported on openstack
-		}
-		allInstanceTypes = append(allInstanceTypes, instanceType)
-	}
 
 	imageConstraint := imagemetadata.NewImageConstraint(simplestreams.LookupParams{
 		CloudSpec: simplestreams.CloudSpec{ic.Region, e.ecfg().authURL()},
@@ -55,3 +41,27 @@ func findInstanceSpec(e *environ, ic *instances.InstanceConstraint) (*instances.
 	}
 	return spec, nil
 }
+
+// flavorInstanceTypes returns an instance type, running on the given
+// architectures, for each of the flavors supported by the deployment.
+func flavorInstanceTypes(e *environ, arches []string) ([]instances.InstanceType, error) {
+	nova := e.nova()
+	flavors, err := nova.ListFlavorsDetail()
+	if err != nil {
+		return nil, err
+	}
+	allInstanceTypes := []instances.InstanceType{}
+	for _, flavor := range flavors {
+		instanceType := instances.InstanceType{
+			Id:       flavor.Id,
+			Name:     flavor.Name,
+			Arches:   arches,
+			Mem:      uint64(flavor.RAM),
+			CpuCores: uint64(flavor.VCPUs),
+			RootDisk: uint64(flavor.Disk * 1024),
+			// tags not currently supported on openstack
+		}
+		allInstanceTypes = append(allInstanceTypes, instanceType)
+	}
+	return allInstanceTypes, nil
+}
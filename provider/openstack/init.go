@@ -14,7 +14,15 @@ const (
 )
 
 func init() {
-	environs.RegisterProvider(providerType, environProvider{})
+	environs.Register(environs.ProviderRegistration{
+		Type:       providerType,
+		Provider:   environProvider{},
+		APIVersion: environs.ProviderAPIVersion,
+		Capabilities: []environs.ProviderCapability{
+			environs.CapabilityStorage,
+			environs.CapabilityAvailabilityZones,
+		},
+	})
 
 	logger.Infof("openstack init")
 	environs.RegisterImageDataSourceFunc("keystone catalog", getKeystoneImageSource)
@@ -604,7 +604,7 @@ func (s *localServerSuite) TestInstanceStatus(c *gc.C) {
 	env := s.Prepare(c)
 	// goose's test service always returns ACTIVE state.
 	inst, _ := testing.AssertStartInstance(c, env, "100")
-	c.Assert(inst.Status(), gc.Equals, nova.StatusActive)
+	c.Assert(inst.Status().Status, gc.Equals, nova.StatusActive)
 	err := env.StopInstances(inst.Id())
 	c.Assert(err, jc.ErrorIsNil)
 }
@@ -715,7 +715,7 @@ func (s *localServerSuite) TestInstancesBuildSpawning(c *gc.C) {
 
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(instances, gc.HasLen, 1)
-	c.Assert(instances[0].Status(), gc.Equals, nova.StatusBuildSpawning)
+	c.Assert(instances[0].Status().Status, gc.Equals, nova.StatusBuildSpawning)
 }
 
 func (s *localServerSuite) TestInstancesShutoffSuspended(c *gc.C) {
@@ -749,8 +749,8 @@ func (s *localServerSuite) TestInstancesShutoffSuspended(c *gc.C) {
 
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(instances, gc.HasLen, 2)
-	c.Assert(instances[0].Status(), gc.Equals, nova.StatusShutoff)
-	c.Assert(instances[1].Status(), gc.Equals, nova.StatusSuspended)
+	c.Assert(instances[0].Status().Status, gc.Equals, nova.StatusShutoff)
+	c.Assert(instances[1].Status().Status, gc.Equals, nova.StatusSuspended)
 }
 
 func (s *localServerSuite) TestInstancesErrorResponse(c *gc.C) {
@@ -894,6 +894,20 @@ func (s *localServerSuite) TestSupportedArchitectures(c *gc.C) {
 	c.Assert(a, jc.SameContents, []string{"amd64", "i386", "ppc64el"})
 }
 
+func (s *localServerSuite) TestInstanceTypes(c *gc.C) {
+	env := s.Open(c)
+	fetcher, ok := environs.SupportsInstanceTypes(env)
+	c.Assert(ok, jc.IsTrue)
+	itypes, err := fetcher.InstanceTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(itypes, gc.Not(gc.HasLen), 0)
+	c.Check(itypes[0].Name, gc.Equals, "m1.tiny")
+	c.Check(itypes[0].Arches, jc.SameContents, []string{"amd64", "i386", "ppc64el"})
+	for i := 1; i < len(itypes); i++ {
+		c.Check(itypes[i].Mem >= itypes[i-1].Mem, jc.IsTrue)
+	}
+}
+
 func (s *localServerSuite) TestSupportsNetworking(c *gc.C) {
 	env := s.Open(c)
 	_, ok := environs.SupportsNetworking(env)
@@ -915,10 +929,10 @@ func (s *localServerSuite) TestConstraintsValidator(c *gc.C) {
 	env := s.Open(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 cpu-power=10")
+	cons := constraints.MustParse("arch=amd64 cpu-power=10 root-disk-source=ssd")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-power"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "root-disk-source"})
 }
 
 func (s *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -367,6 +367,7 @@ var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
 var _ environs.InstanceTagger = (*environ)(nil)
+var _ environs.InstanceTypesFetcher = (*environ)(nil)
 
 type openstackInstance struct {
 	e        *environ
@@ -406,8 +407,8 @@ func (inst *openstackInstance) Id() instance.Id {
 	return instance.Id(inst.getServerDetail().Id)
 }
 
-func (inst *openstackInstance) Status() string {
-	return inst.getServerDetail().Status
+func (inst *openstackInstance) Status() instance.InstanceStatus {
+	return instance.InstanceStatus{Status: inst.getServerDetail().Status}
 }
 
 func (inst *openstackInstance) hardwareCharacteristics() *instance.HardwareCharacteristics {
@@ -576,6 +577,8 @@ func (e *environ) SupportedArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.VirtType,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -996,10 +999,16 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 				availabilityZones = append(availabilityZones, zone.ZoneName)
 			}
 		}
-		if len(availabilityZones) == 0 {
-			// No explicitly selectable zones available, so use an unspecified zone.
-			availabilityZones = []string{""}
-		}
+	}
+
+	// If zones= constraints is set, then only use the zones it lists.
+	availabilityZones, err := common.FilterZonesByConstraints(availabilityZones, args.Constraints)
+	if err != nil {
+		return nil, err
+	}
+	if len(availabilityZones) == 0 {
+		// No explicitly selectable zones available, so use an unspecified zone.
+		availabilityZones = []string{""}
 	}
 
 	if args.InstanceConfig.HasNetworks() {
@@ -1689,6 +1698,22 @@ func (e *environ) Region() (simplestreams.CloudSpec, error) {
 	return e.cloudSpec(e.ecfg().region())
 }
 
+// InstanceTypes is specified in the environs.InstanceTypesFetcher
+// interface. Nova does not report what flavors cost, so they are
+// ordered by memory and then cpu cores.
+func (e *environ) InstanceTypes() ([]instances.InstanceType, error) {
+	arches, err := e.SupportedArchitectures()
+	if err != nil {
+		return nil, err
+	}
+	itypes, err := flavorInstanceTypes(e, arches)
+	if err != nil {
+		return nil, err
+	}
+	instances.SortByCost(itypes)
+	return itypes, nil
+}
+
 func (e *environ) cloudSpec(region string) (simplestreams.CloudSpec, error) {
 	return simplestreams.CloudSpec{
 		Region:   region,
@@ -200,6 +200,51 @@ func (c *client) getVm(name string) (*mo.VirtualMachine, error) {
 	return &vm, nil
 }
 
+// datastore returns the datastore, available to the given compute
+// resource, with the given name. If name is empty, the compute
+// resource's first datastore is returned.
+func (c *client) datastore(zone *mo.ComputeResource, name string) (*types.ManagedObjectReference, error) {
+	if len(zone.Datastore) == 0 {
+		return nil, errors.Errorf("no datastores available in zone %q", zone.Name)
+	}
+	if name == "" {
+		return &zone.Datastore[0], nil
+	}
+	for i, ref := range zone.Datastore {
+		var ds mo.Datastore
+		if err := c.connection.RetrieveOne(context.TODO(), ref, []string{"name"}, &ds); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ds.Name == name {
+			return &zone.Datastore[i], nil
+		}
+	}
+	return nil, errors.NotFoundf("datastore %q in zone %q", name, zone.Name)
+}
+
+// resourcePool returns the resource pool, directly under the root
+// resource pool of the given compute resource, with the given name.
+// If name is empty, the root resource pool is returned.
+func (c *client) resourcePool(zone *mo.ComputeResource, name string) (*types.ManagedObjectReference, error) {
+	if name == "" {
+		return zone.ResourcePool, nil
+	}
+	var root mo.ResourcePool
+	if err := c.connection.RetrieveOne(context.TODO(), *zone.ResourcePool, []string{"resourcePool"}, &root); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for i, ref := range root.ResourcePool {
+		var pool mo.ResourcePool
+		if err := c.connection.RetrieveOne(context.TODO(), ref, []string{"name"}, &pool); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if pool.Name == name {
+			return &root.ResourcePool[i], nil
+		}
+	}
+	return nil, errors.NotFoundf("resource pool %q in zone %q", name, zone.Name)
+}
+
 //AvailabilityZones retuns list of all root compute resources in the system
 func (c *client) AvailabilityZones() ([]*mo.ComputeResource, error) {
 	folders, err := c.datacenter.Folders(context.TODO())
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 )
@@ -22,6 +23,8 @@ const (
 	cfgUser            = "user"
 	cfgPassword        = "password"
 	cfgExternalNetwork = "external-network"
+	cfgDatastore       = "datastore"
+	cfgResourcePool    = "resource-pool"
 )
 
 // boilerplateConfig will be shown in help output, so please keep it up to
@@ -44,17 +47,63 @@ vmware:
   # This network should have ip pool configured or DHCP server connected to it.
   # This parameter is optional. 
   extenal-network:
+
+  # Name of the datastore that created vms will be stored in. It must be
+  # available to every compute resource (availability zone) in the datacenter.
+  # If not specified, the first datastore of each compute resource is used.
+  # datastore:
+
+  # Name of the resource pool that created vms will be placed in. It must
+  # exist directly under the root resource pool of every compute resource
+  # (availability zone) in the datacenter. If not specified, vms are placed
+  # in the root resource pool.
+  # resource-pool:
 `[1:]
 
-// configFields is the spec for each vmware config value's type.
-var configFields = schema.Fields{
-	cfgHost:            schema.String(),
-	cfgUser:            schema.String(),
-	cfgPassword:        schema.String(),
-	cfgDatacenter:      schema.String(),
-	cfgExternalNetwork: schema.String(),
+// configSchema describes each vmware config value.
+var configSchema = environschema.Fields{
+	cfgHost: {
+		Description: "The IP address or DNS name of the vsphere API host.",
+		Type:        environschema.Tstring,
+	},
+	cfgUser: {
+		Description: "The vsphere API user name.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	cfgPassword: {
+		Description: "The vsphere API password.",
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.AccountGroup,
+	},
+	cfgDatacenter: {
+		Description: "The name of the vsphere datacenter.",
+		Type:        environschema.Tstring,
+	},
+	cfgExternalNetwork: {
+		Description: "The name of the network that vms use to obtain a public address.",
+		Type:        environschema.Tstring,
+	},
+	cfgDatastore: {
+		Description: "The name of the datastore in which vms are stored.",
+		Type:        environschema.Tstring,
+	},
+	cfgResourcePool: {
+		Description: "The name of the resource pool in which vms are placed.",
+		Type:        environschema.Tstring,
+	},
 }
 
+// configFields is the spec for each vmware config value's type.
+var configFields = func() schema.Fields {
+	fs, _, err := configSchema.ValidationSchema()
+	if err != nil {
+		panic(err)
+	}
+	return fs
+}()
+
 var requiredFields = []string{
 	cfgHost,
 	cfgUser,
@@ -64,15 +113,28 @@ var requiredFields = []string{
 
 var configDefaults = schema.Defaults{
 	cfgExternalNetwork: "",
+	cfgDatastore:       "",
+	cfgResourcePool:    "",
 }
 
-var configSecretFields = []string{
-	cfgPassword,
-}
+// configSecretFields lists the config values marked as secret in
+// configSchema.
+var configSecretFields = func() []string {
+	var fields []string
+	for name, attr := range configSchema {
+		if attr.Secret {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}()
 
 var configImmutableFields = []string{
 	cfgHost,
 	cfgDatacenter,
+	// Instances are mapped to availability zones by their
+	// resource pool, so it must not change.
+	cfgResourcePool,
 }
 
 type environConfig struct {
@@ -138,6 +200,14 @@ func (c *environConfig) externalNetwork() string {
 	return c.attrs[cfgExternalNetwork].(string)
 }
 
+func (c *environConfig) datastore() string {
+	return c.attrs[cfgDatastore].(string)
+}
+
+func (c *environConfig) resourcePool() string {
+	return c.attrs[cfgResourcePool].(string)
+}
+
 func (c *environConfig) url() (*url.URL, error) {
 	return url.Parse(fmt.Sprintf("https://%s:%s@%s/sdk", c.user(), c.password(), c.host()))
 }
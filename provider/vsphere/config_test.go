@@ -206,6 +206,14 @@ var changeConfigTests = []configTestSpec{{
 	info:   "cannot change password",
 	insert: testing.Attrs{"password": "password2"},
 	expect: testing.Attrs{"password": "password2"},
+}, {
+	info:   "can change datastore",
+	insert: testing.Attrs{"datastore": "datastore2"},
+	expect: testing.Attrs{"datastore": "datastore2"},
+}, {
+	info:   "cannot change resource-pool",
+	insert: testing.Attrs{"resource-pool": "pool2"},
+	err:    "resource-pool: cannot change from  to pool2",
 }, {
 	info:   "can insert unknown field",
 	insert: testing.Attrs{"unknown": "ignoti"},
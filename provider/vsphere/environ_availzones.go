@@ -8,6 +8,7 @@ package vsphere
 import (
 	"github.com/juju/errors"
 	"github.com/juju/govmomi/vim25/mo"
+	"github.com/juju/govmomi/vim25/types"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
@@ -55,10 +56,20 @@ func (env *environ) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// Instances are placed in the configured resource pool of
+	// their zone's compute resource, so map pools back to zones.
+	pools := make([]*types.ManagedObjectReference, len(zones))
+	for i, zone := range zones {
+		pool, err := env.client.resourcePool(zone, env.ecfg.resourcePool())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		pools[i] = pool
+	}
 	results := make([]string, 0, len(ids))
 	for _, inst := range instances {
-		for _, zone := range zones {
-			if eInst := inst.(*environInstance); eInst != nil && zone.ResourcePool.Value == eInst.base.ResourcePool.Value {
+		for i, zone := range zones {
+			if eInst := inst.(*environInstance); eInst != nil && pools[i].Value == eInst.base.ResourcePool.Value {
 				results = append(results, zone.Name)
 				break
 			}
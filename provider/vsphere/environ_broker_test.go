@@ -78,6 +78,39 @@ func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *environBrokerSuite) prepareStartInstanceFakesWithDatastore(c *gc.C, datastoreName string) {
+	s.BaseSuite.PatchValue(&imagemetadata.DefaultBaseURL, "")
+
+	client := vsphere.ExposeEnvFakeClient(s.Env)
+	client.SetPropertyProxyHandler("FakeDatacenter", vsphere.RetrieveDatacenterProperties)
+	s.FakeInstances(client)
+	s.FakeAvailabilityZones(client, "z1")
+	s.FakeAvailabilityZones(client, "z1")
+	s.FakeAvailabilityZones(client, "z1")
+	client.SetPropertyProxyHandler("FakeDatastore", func(reqBody, resBody *methods.RetrievePropertiesBody) {
+		vsphere.CommonRetrieveProperties(resBody, "Datastore", "FakeDatastore", "name", datastoreName)
+	})
+	s.FakeCreateInstance(client, s.ServerUrl, c)
+}
+
+func (s *environBrokerSuite) TestStartInstanceWithDatastore(c *gc.C) {
+	s.UpdateConfig(c, map[string]interface{}{"datastore": "ds1"})
+	s.prepareStartInstanceFakesWithDatastore(c, "ds1")
+	startInstArgs := s.CreateStartInstanceArgs(c)
+	_, err := s.Env.StartInstance(startInstArgs)
+
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environBrokerSuite) TestStartInstanceWithDatastoreNotFound(c *gc.C) {
+	s.UpdateConfig(c, map[string]interface{}{"datastore": "ds2"})
+	s.prepareStartInstanceFakesWithDatastore(c, "ds1")
+	startInstArgs := s.CreateStartInstanceArgs(c)
+	_, err := s.Env.StartInstance(startInstArgs)
+
+	c.Assert(err, gc.ErrorMatches, `.*datastore "ds2" in zone "z1" not found`)
+}
+
 func (s *environBrokerSuite) TestStartInstanceWithNetworks(c *gc.C) {
 	s.PrepareStartInstanceFakes(c)
 	startInstArgs := s.CreateStartInstanceArgs(c)
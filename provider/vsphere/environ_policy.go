@@ -71,6 +71,9 @@ func (env *environ) lookupArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.Networks,
+	constraints.VirtType,
+	constraints.RootDiskSource,
+	constraints.Zones,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
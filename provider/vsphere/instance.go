@@ -33,9 +33,9 @@ func (inst *environInstance) Id() instance.Id {
 }
 
 // Status implements instance.Instance.
-func (inst *environInstance) Status() string {
+func (inst *environInstance) Status() instance.InstanceStatus {
 	//return inst.base.Status()
-	return ""
+	return instance.InstanceStatus{}
 }
 
 // Addresses implements instance.Instance.
@@ -52,6 +52,14 @@ type ovaImportManager struct {
 }
 
 func (m *ovaImportManager) importOva(ecfg *environConfig, instSpec *instanceSpec) (*object.VirtualMachine, error) {
+	poolRef, err := m.client.resourcePool(&instSpec.zone.r, ecfg.resourcePool())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	datastoreRef, err := m.client.datastore(&instSpec.zone.r, ecfg.datastore())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	folders, err := m.client.datacenter.Folders(context.TODO())
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -80,8 +88,8 @@ func (m *ovaImportManager) importOva(ecfg *environConfig, instSpec *instanceSpec
 	}
 
 	ovfManager := object.NewOvfManager(m.client.connection.Client)
-	resourcePool := object.NewReference(m.client.connection.Client, *instSpec.zone.r.ResourcePool)
-	datastore := object.NewReference(m.client.connection.Client, instSpec.zone.r.Datastore[0])
+	resourcePool := object.NewReference(m.client.connection.Client, *poolRef)
+	datastore := object.NewReference(m.client.connection.Client, *datastoreRef)
 	spec, err := ovfManager.CreateImportSpec(context.TODO(), string(ovf), resourcePool, datastore, cisp)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -132,7 +140,7 @@ func (m *ovaImportManager) importOva(ecfg *environConfig, instSpec *instanceSpec
 			},
 		})
 	}
-	rp := object.NewResourcePool(m.client.connection.Client, *instSpec.zone.r.ResourcePool)
+	rp := object.NewResourcePool(m.client.connection.Client, *poolRef)
 	lease, err := rp.ImportVApp(context.TODO(), spec.ImportSpec, folders.VmFolder, nil)
 	if err != nil {
 		return nil, errors.Annotatef(err, "failed to import vapp")
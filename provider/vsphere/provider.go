@@ -8,6 +8,7 @@ package vsphere
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -78,6 +79,15 @@ func (environProvider) Validate(cfg, old *config.Config) (valid *config.Config,
 	return ecfg.Config, nil
 }
 
+// Schema implements environs.ProviderSchema.
+func (environProvider) Schema() environschema.Fields {
+	fields, err := config.Schema(configSchema)
+	if err != nil {
+		panic(err)
+	}
+	return fields
+}
+
 // SecretAttrs implements environs.EnvironProvider.
 func (environProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	// The defaults should be set already, so we pass nil.
@@ -8,6 +8,7 @@ package vsphere_test
 import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs"
 	envtesting "github.com/juju/juju/environs/testing"
@@ -64,3 +65,10 @@ func (s *providerSuite) TestSecretAttrs(c *gc.C) {
 	c.Assert(obtainedAttrs, gc.DeepEquals, expectedAttrs)
 
 }
+
+func (s *providerSuite) TestSchema(c *gc.C) {
+	fields := s.provider.(environs.ProviderSchema).Schema()
+	c.Check(fields["password"].Secret, jc.IsTrue)
+	c.Check(fields["user"].Secret, jc.IsFalse)
+	c.Check(fields["name"].Type, gc.Equals, environschema.Tstring)
+}
@@ -6,6 +6,8 @@ package rpc
 import (
 	"errors"
 	"strings"
+
+	"golang.org/x/net/context"
 )
 
 var ErrShutdown = errors.New("connection is shut down")
@@ -17,12 +19,25 @@ type Call struct {
 	Response interface{}
 	Error    error
 	Done     chan *Call
+
+	// CorrelationId holds the correlation id the request is sent
+	// with. If it is empty when the call is made, a new one is
+	// generated.
+	CorrelationId string
+
+	// reqId holds the id the request was sent with, so that the
+	// call can be abandoned.
+	reqId uint64
 }
 
 // RequestError represents an error returned from an RPC request.
 type RequestError struct {
 	Message string
 	Code    string
+
+	// CorrelationId holds the correlation id of the failed request,
+	// so that it can be quoted when reporting the failure.
+	CorrelationId string
 }
 
 func (e *RequestError) Error() string {
@@ -30,6 +45,9 @@ func (e *RequestError) Error() string {
 	if e.Code != "" {
 		m += " (" + e.Code + ")"
 	}
+	if e.CorrelationId != "" {
+		m += " (correlation id " + e.CorrelationId + ")"
+	}
 	return m
 }
 
@@ -54,13 +72,18 @@ func (conn *Conn) send(call *Call) {
 	}
 	conn.reqId++
 	reqId := conn.reqId
+	call.reqId = reqId
+	if call.CorrelationId == "" {
+		call.CorrelationId = NewCorrelationId()
+	}
 	conn.clientPending[reqId] = call
 	conn.mutex.Unlock()
 
 	// Encode and send the request.
 	hdr := &Header{
-		RequestId: reqId,
-		Request:   call.Request,
+		RequestId:     reqId,
+		Request:       call.Request,
+		CorrelationId: call.CorrelationId,
 	}
 	params := call.Params
 	if params == nil {
@@ -109,9 +132,12 @@ func (conn *Conn) handleResponse(hdr *Header) error {
 		// any subsequent requests will get the ReadResponseBody
 		// error if there is one.
 		call.Error = &RequestError{
-			Message: hdr.Error,
-			Code:    hdr.ErrorCode,
+			Message:       hdr.Error,
+			Code:          hdr.ErrorCode,
+			CorrelationId: call.CorrelationId,
 		}
+		logger.Debugf("%s.%s failed (correlation id %s): %s",
+			call.Request.Type, call.Request.Action, call.CorrelationId, hdr.Error)
 		err = conn.readBody(nil, false)
 		if conn.notifier != nil {
 			conn.notifier.ClientReply(call.Request, hdr, nil)
@@ -145,8 +171,37 @@ func (call *Call) done() {
 // no parameters are provided; the response value may be nil to indicate
 // that any result should be discarded.
 func (conn *Conn) Call(req Request, params, response interface{}) error {
-	call := <-conn.Go(req, params, response, make(chan *Call, 1)).Done
-	return call.Error
+	return conn.CallContext(context.Background(), req, params, response)
+}
+
+// CallContext is like Call, but abandons the call, returning the
+// context's error, if the context is done before the call completes.
+// The server is not told that the call has been abandoned; any reply
+// it sends is discarded. If the context holds a correlation id (see
+// WithCorrelationId), the request is sent with it.
+func (conn *Conn) CallContext(ctx context.Context, req Request, params, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	call := &Call{
+		Request:       req,
+		Params:        params,
+		Response:      response,
+		Done:          make(chan *Call, 1),
+		CorrelationId: CorrelationId(ctx),
+	}
+	conn.send(call)
+	select {
+	case call = <-call.Done:
+		return call.Error
+	case <-ctx.Done():
+	}
+	conn.mutex.Lock()
+	if conn.clientPending[call.reqId] == call {
+		delete(conn.clientPending, call.reqId)
+	}
+	conn.mutex.Unlock()
+	return ctx.Err()
 }
 
 // Go invokes the request asynchronously.  It returns the Call structure representing
@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rpc
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/net/context"
+)
+
+// correlationIdKey is the context key under which a correlation id is
+// stored by WithCorrelationId.
+type correlationIdKey struct{}
+
+// NewCorrelationId returns a new random correlation id.
+func NewCorrelationId() string {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		panic(fmt.Errorf("cannot generate correlation id: %v", err))
+	}
+	return hex.EncodeToString(buf[:])
+}
+
+// WithCorrelationId returns a context holding the given correlation
+// id. Calls made with Conn.CallContext using the returned context are
+// sent with that id, so that several calls made on behalf of a single
+// operation can be traced together.
+func WithCorrelationId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIdKey{}, id)
+}
+
+// CorrelationId returns the correlation id held in the given context,
+// or "" if there is none.
+func CorrelationId(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIdKey{}).(string)
+	return id
+}
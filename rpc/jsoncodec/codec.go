@@ -71,6 +71,8 @@ type inMsg struct {
 	Error     string
 	ErrorCode string
 	Response  json.RawMessage
+
+	CorrelationId string
 }
 
 // outMsg holds an outgoing message.
@@ -84,6 +86,8 @@ type outMsg struct {
 	Error     string      `json:",omitempty"`
 	ErrorCode string      `json:",omitempty"`
 	Response  interface{} `json:",omitempty"`
+
+	CorrelationId string `json:",omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -131,6 +135,7 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	}
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
+	hdr.CorrelationId = c.msg.CorrelationId
 	return nil
 }
 
@@ -191,6 +196,7 @@ func (m *outMsg) init(hdr *rpc.Header, body interface{}) {
 	m.Request = hdr.Request.Action
 	m.Error = hdr.Error
 	m.ErrorCode = hdr.ErrorCode
+	m.CorrelationId = hdr.CorrelationId
 	if hdr.IsRequest() {
 		m.Params = body
 	} else {
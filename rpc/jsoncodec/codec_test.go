@@ -72,6 +72,14 @@ var readTests = []struct {
 		},
 	},
 	expectBody: &value{X: "param"},
+}, {
+	msg: `{"RequestId": 5, "Error": "an error", "CorrelationId": "abc123"}`,
+	expectHdr: rpc.Header{
+		RequestId:     5,
+		Error:         "an error",
+		CorrelationId: "abc123",
+	},
+	expectBody: new(map[string]interface{}),
 }}
 
 func (*suite) TestRead(c *gc.C) {
@@ -255,6 +263,17 @@ var writeTests = []struct {
 	},
 	body:   &value{X: "param"},
 	expect: `{"RequestId": 4, "Type": "foo", "Version": 2, "Request": "frob", "Params": {"X": "param"}}`,
+}, {
+	hdr: &rpc.Header{
+		RequestId: 5,
+		Request: rpc.Request{
+			Type:   "foo",
+			Action: "frob",
+		},
+		CorrelationId: "abc123",
+	},
+	body:   &value{X: "param"},
+	expect: `{"RequestId": 5, "Type": "foo", "Request": "frob", "Params": {"X": "param"}, "CorrelationId": "abc123"}`,
 }}
 
 func (*suite) TestWrite(c *gc.C) {
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/rpc"
@@ -470,7 +471,7 @@ func (root *Root) testCall(c *gc.C, p testCallParams) {
 	err := p.client.Call(p.request(), stringVal{"arg"}, &r)
 	switch {
 	case p.retErr && p.testErr:
-		c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+		assertRequestError(c, err, &rpc.RequestError{
 			Message: p.errorMessage(),
 		})
 		c.Assert(r, gc.Equals, stringVal{})
@@ -488,9 +489,9 @@ func (root *Root) testCall(c *gc.C, p testCallParams) {
 
 	root.assertCallMade(c, p)
 
-	requestId := root.assertClientNotified(c, p, &r)
+	requestId, correlationId := root.assertClientNotified(c, p, &r)
 
-	root.assertServerNotified(c, p, requestId)
+	root.assertServerNotified(c, p, requestId, correlationId)
 }
 
 func (root *Root) assertCallMade(c *gc.C, p testCallParams) {
@@ -508,8 +509,8 @@ func (root *Root) assertCallMade(c *gc.C, p testCallParams) {
 // assertClientNotified asserts that the right client notifications
 // were made for the given test call parameters. The value of r
 // holds the result parameter passed to the call.
-// It returns the request id.
-func (root *Root) assertClientNotified(c *gc.C, p testCallParams, r interface{}) uint64 {
+// It returns the request id and the correlation id.
+func (root *Root) assertClientNotified(c *gc.C, p testCallParams, r interface{}) (uint64, string) {
 	c.Assert(p.clientNotifier.serverRequests, gc.HasLen, 0)
 	c.Assert(p.clientNotifier.serverReplies, gc.HasLen, 0)
 
@@ -518,6 +519,9 @@ func (root *Root) assertClientNotified(c *gc.C, p testCallParams, r interface{})
 	clientReq := p.clientNotifier.clientRequests[0]
 	requestId := clientReq.hdr.RequestId
 	clientReq.hdr.RequestId = 0 // Ignore the exact value of the request id to start with.
+	correlationId := clientReq.hdr.CorrelationId
+	c.Assert(correlationId, gc.Not(gc.Equals), "")
+	clientReq.hdr.CorrelationId = "" // Nor that of the generated correlation id.
 	c.Assert(clientReq.hdr, gc.DeepEquals, rpc.Header{
 		Request: p.request(),
 	})
@@ -534,21 +538,23 @@ func (root *Root) assertClientNotified(c *gc.C, p testCallParams, r interface{})
 	}
 	if p.retErr && p.testErr {
 		c.Assert(clientReply.hdr, gc.DeepEquals, rpc.Header{
-			RequestId: requestId,
-			Error:     p.errorMessage(),
+			RequestId:     requestId,
+			Error:         p.errorMessage(),
+			CorrelationId: correlationId,
 		})
 	} else {
 		c.Assert(clientReply.hdr, gc.DeepEquals, rpc.Header{
-			RequestId: requestId,
+			RequestId:     requestId,
+			CorrelationId: correlationId,
 		})
 	}
-	return requestId
+	return requestId, correlationId
 }
 
 // assertServerNotified asserts that the right server notifications
 // were made for the given test call parameters. The id of the request
-// is held in requestId.
-func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint64) {
+// is held in requestId, and its correlation id in correlationId.
+func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint64, correlationId string) {
 	// Check that the right server notifications were made.
 	c.Assert(p.serverNotifier.clientRequests, gc.HasLen, 0)
 	c.Assert(p.serverNotifier.clientReplies, gc.HasLen, 0)
@@ -557,8 +563,9 @@ func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint
 	c.Assert(p.serverNotifier.serverRequests, gc.HasLen, 1)
 	serverReq := p.serverNotifier.serverRequests[0]
 	c.Assert(serverReq.hdr, gc.DeepEquals, rpc.Header{
-		RequestId: requestId,
-		Request:   p.request(),
+		RequestId:     requestId,
+		Request:       p.request(),
+		CorrelationId: correlationId,
 	})
 	if p.narg > 0 {
 		c.Assert(serverReq.body, gc.Equals, stringVal{"arg"})
@@ -577,12 +584,14 @@ func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint
 	}
 	if p.retErr && p.testErr {
 		c.Assert(serverReply.hdr, gc.Equals, rpc.Header{
-			RequestId: requestId,
-			Error:     p.errorMessage(),
+			RequestId:     requestId,
+			Error:         p.errorMessage(),
+			CorrelationId: correlationId,
 		})
 	} else {
 		c.Assert(serverReply.hdr, gc.Equals, rpc.Header{
-			RequestId: requestId,
+			RequestId:     requestId,
+			CorrelationId: correlationId,
 		})
 	}
 }
@@ -610,7 +619,7 @@ func (*rpcSuite) TestInterfaceMethods(c *gc.C) {
 	// CodeNotImplemented.
 	var r stringVal
 	err := client.Call(rpc.Request{"InterfaceMethods", 0, "a99", "Call0r0"}, stringVal{"arg"}, &r)
-	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+	assertRequestError(c, err, &rpc.RequestError{
 		Message: "no such request - method InterfaceMethods.Call0r0 is not implemented",
 		Code:    rpc.CodeNotImplemented,
 	})
@@ -637,7 +646,7 @@ func (*rpcSuite) TestCustomMethodFinderV0(c *gc.C) {
 	// Call1r1 is exposed in version 1, but not in version 0.
 	var r stringVal
 	err := client.Call(rpc.Request{"MultiVersion", 0, "a99", "Call1r1"}, stringVal{"arg"}, &r)
-	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+	assertRequestError(c, err, &rpc.RequestError{
 		Message: "no such request - method MultiVersion.Call1r1 is not implemented",
 		Code:    rpc.CodeNotImplemented,
 	})
@@ -664,7 +673,7 @@ func (*rpcSuite) TestCustomMethodFinderV1(c *gc.C) {
 	// Call0r1 is exposed in version 0, but not in version 1.
 	var r stringVal
 	err := client.Call(rpc.Request{"MultiVersion", 1, "a99", "Call0r1"}, nil, &r)
-	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+	assertRequestError(c, err, &rpc.RequestError{
 		Message: "no such request - method MultiVersion(1).Call0r1 is not implemented",
 		Code:    rpc.CodeNotImplemented,
 	})
@@ -692,7 +701,7 @@ func (*rpcSuite) TestCustomMethodFinderV2(c *gc.C) {
 	// in InterfaceMethods.
 	var r stringVal
 	err := client.Call(rpc.Request{"MultiVersion", 2, "a99", "Call0r1e"}, nil, &r)
-	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+	assertRequestError(c, err, &rpc.RequestError{
 		Message: `no such request - method MultiVersion(2).Call0r1e is not implemented`,
 		Code:    rpc.CodeNotImplemented,
 	})
@@ -705,7 +714,7 @@ func (*rpcSuite) TestCustomMethodFinderUnknownVersion(c *gc.C) {
 	var r stringVal
 	// Unknown version 5
 	err := client.Call(rpc.Request{"MultiVersion", 5, "a99", "Call0r1"}, nil, &r)
-	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+	assertRequestError(c, err, &rpc.RequestError{
 		Message: `unknown version (5) of interface "MultiVersion"`,
 		Code:    rpc.CodeNotImplemented,
 	})
@@ -749,6 +758,104 @@ func (*rpcSuite) TestConcurrentCalls(c *gc.C) {
 	chanRead(c, done2, "method 2 done")
 }
 
+func (*rpcSuite) TestCallContextCancelled(c *gc.C) {
+	start1 := make(chan string)
+	start2 := make(chan string, 1)
+	ready1 := make(chan struct{})
+	ready2 := make(chan struct{}, 1)
+	root := &Root{
+		delayed: map[string]*DelayedMethods{
+			"1": {ready: ready1, done: start1},
+			"2": {ready: ready2, done: start2},
+		},
+	}
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		var r stringVal
+		result <- client.CallContext(ctx, rpc.Request{"DelayedMethods", 0, "1", "Delay"}, nil, &r)
+	}()
+	chanRead(c, ready1, "method 1 ready")
+	cancel()
+	select {
+	case err := <-result:
+		c.Assert(err, gc.Equals, context.Canceled)
+	case <-time.After(3 * time.Second):
+		c.Fatalf("cancelled call did not return")
+	}
+
+	// The abandoned call's reply is discarded, and the connection
+	// can still be used.
+	start1 <- "return 1"
+	start2 <- "return 2"
+	var r stringVal
+	err := client.Call(rpc.Request{"DelayedMethods", 0, "2", "Delay"}, nil, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Val, gc.Equals, "return 2")
+}
+
+func (*rpcSuite) TestCallContextAlreadyDone(c *gc.C) {
+	client, srvDone, _, _ := newRPCClientServer(c, &Root{}, nil, false)
+	defer closeClient(c, client, srvDone)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := client.CallContext(ctx, rpc.Request{"Foo", 0, "", "Bar"}, nil, nil)
+	c.Assert(err, gc.Equals, context.Canceled)
+}
+
+func (*rpcSuite) TestCallContextDeadline(c *gc.C) {
+	ready := make(chan struct{}, 1)
+	start := make(chan string, 1)
+	root := &Root{
+		delayed: map[string]*DelayedMethods{
+			"1": {ready: ready, done: start},
+		},
+	}
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	defer func() { start <- "late" }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := client.CallContext(ctx, rpc.Request{"DelayedMethods", 0, "1", "Delay"}, nil, nil)
+	c.Assert(err, gc.Equals, context.DeadlineExceeded)
+}
+
+func (*rpcSuite) TestCallContextCorrelationId(c *gc.C) {
+	root := SimpleRoot()
+	client, srvDone, clientNotifier, serverNotifier := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	ctx := rpc.WithCorrelationId(context.Background(), "deploy-1234")
+	c.Assert(rpc.CorrelationId(ctx), gc.Equals, "deploy-1234")
+	err := client.CallContext(ctx, rpc.Request{"SimpleMethods", 0, "a99", "Call0r0"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	root.returnErr = true
+	err = client.CallContext(ctx, rpc.Request{"SimpleMethods", 0, "a99", "Call0r0e"}, nil, nil)
+	c.Assert(err, gc.ErrorMatches, `request error: error calling Call0r0e \(correlation id deploy-1234\)`)
+	c.Assert(err.(*rpc.RequestError).CorrelationId, gc.Equals, "deploy-1234")
+
+	c.Assert(clientNotifier.clientRequests, gc.HasLen, 2)
+	c.Assert(serverNotifier.serverRequests, gc.HasLen, 2)
+	c.Assert(serverNotifier.serverReplies, gc.HasLen, 2)
+	c.Assert(clientNotifier.clientReplies, gc.HasLen, 2)
+	for i := 0; i < 2; i++ {
+		c.Check(clientNotifier.clientRequests[i].hdr.CorrelationId, gc.Equals, "deploy-1234")
+		c.Check(serverNotifier.serverRequests[i].hdr.CorrelationId, gc.Equals, "deploy-1234")
+		c.Check(serverNotifier.serverReplies[i].hdr.CorrelationId, gc.Equals, "deploy-1234")
+		c.Check(clientNotifier.clientReplies[i].hdr.CorrelationId, gc.Equals, "deploy-1234")
+	}
+}
+
+func (*rpcSuite) TestNewCorrelationId(c *gc.C) {
+	id0, id1 := rpc.NewCorrelationId(), rpc.NewCorrelationId()
+	c.Assert(id0, gc.Matches, "[0-9a-f]{16}")
+	c.Assert(id1, gc.Not(gc.Equals), id0)
+}
+
 type codedError struct {
 	m    string
 	code string
@@ -769,7 +876,7 @@ func (*rpcSuite) TestErrorCode(c *gc.C) {
 	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
 	defer closeClient(c, client, srvDone)
 	err := client.Call(rpc.Request{"ErrorMethods", 0, "", "Call"}, nil, nil)
-	c.Assert(err, gc.ErrorMatches, `request error: message \(code\)`)
+	c.Assert(err, gc.ErrorMatches, `request error: message \(code\)`+correlationIdPattern)
 	c.Assert(err.(rpc.ErrorCoder).ErrorCode(), gc.Equals, "code")
 }
 
@@ -791,13 +898,13 @@ func (*rpcSuite) TestTransformErrors(c *gc.C) {
 	defer closeClient(c, client, srvDone)
 	// First, we don't transform methods we can't find.
 	err := client.Call(rpc.Request{"foo", 0, "", "bar"}, nil, nil)
-	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+	assertRequestError(c, err, &rpc.RequestError{
 		Message: `unknown object type "foo"`,
 		Code:    rpc.CodeNotImplemented,
 	})
 
 	err = client.Call(rpc.Request{"ErrorMethods", 0, "", "NoMethod"}, nil, nil)
-	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+	assertRequestError(c, err, &rpc.RequestError{
 		Message: "no such request - method ErrorMethods.NoMethod is not implemented",
 		Code:    rpc.CodeNotImplemented,
 	})
@@ -805,7 +912,7 @@ func (*rpcSuite) TestTransformErrors(c *gc.C) {
 	// We do transform any errors that happen from calling the RootMethod
 	// and beyond.
 	err = client.Call(rpc.Request{"ErrorMethods", 0, "", "Call"}, nil, nil)
-	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+	assertRequestError(c, err, &rpc.RequestError{
 		Message: "transformed: message",
 		Code:    "transformed: code",
 	})
@@ -816,7 +923,7 @@ func (*rpcSuite) TestTransformErrors(c *gc.C) {
 
 	root.errorInst = nil
 	err = client.Call(rpc.Request{"ErrorMethods", 0, "", "Call"}, nil, nil)
-	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+	assertRequestError(c, err, &rpc.RequestError{
 		Message: "transformed: no error methods",
 	})
 
@@ -853,6 +960,21 @@ func (*rpcSuite) TestServerWaitsForOutstandingCalls(c *gc.C) {
 	start <- "xxx"
 }
 
+// correlationIdPattern matches the correlation id suffix of a
+// RequestError message.
+const correlationIdPattern = ` \(correlation id [0-9a-f]+\)`
+
+// assertRequestError checks that err is the expected RequestError,
+// apart from its correlation id, which must be set.
+func assertRequestError(c *gc.C, err error, expected *rpc.RequestError) {
+	rerr, ok := err.(*rpc.RequestError)
+	c.Assert(ok, jc.IsTrue, gc.Commentf("unexpected error %#v", err))
+	c.Assert(rerr.CorrelationId, gc.Not(gc.Equals), "")
+	actual := *rerr
+	actual.CorrelationId = ""
+	c.Assert(&actual, gc.DeepEquals, expected)
+}
+
 func chanRead(c *gc.C, ch <-chan struct{}, what string) {
 	select {
 	case <-ch:
@@ -951,16 +1073,19 @@ func testBadCall(
 	if expectedErrCode != "" {
 		msg += " (" + expectedErrCode + ")"
 	}
-	c.Assert(err, gc.ErrorMatches, regexp.QuoteMeta("request error: "+msg))
+	c.Assert(err, gc.ErrorMatches, regexp.QuoteMeta("request error: "+msg)+correlationIdPattern)
 
 	// Test that there was a notification for the client request.
 	c.Assert(clientNotifier.clientRequests, gc.HasLen, 1)
 	clientReq := clientNotifier.clientRequests[0]
 	requestId := clientReq.hdr.RequestId
+	correlationId := clientReq.hdr.CorrelationId
+	c.Assert(correlationId, gc.Not(gc.Equals), "")
 	c.Assert(clientReq, gc.DeepEquals, requestEvent{
 		hdr: rpc.Header{
-			RequestId: requestId,
-			Request:   req,
+			RequestId:     requestId,
+			Request:       req,
+			CorrelationId: correlationId,
 		},
 		body: struct{}{},
 	})
@@ -970,9 +1095,10 @@ func testBadCall(
 	c.Assert(clientReply, gc.DeepEquals, replyEvent{
 		req: req,
 		hdr: rpc.Header{
-			RequestId: requestId,
-			Error:     expectedErr,
-			ErrorCode: expectedErrCode,
+			RequestId:     requestId,
+			Error:         expectedErr,
+			ErrorCode:     expectedErrCode,
+			CorrelationId: correlationId,
 		},
 	})
 
@@ -989,8 +1115,9 @@ func testBadCall(
 	}
 	c.Assert(serverReq, gc.DeepEquals, requestEvent{
 		hdr: rpc.Header{
-			RequestId: requestId,
-			Request:   req,
+			RequestId:     requestId,
+			Request:       req,
+			CorrelationId: correlationId,
 		},
 		body: expectBody,
 	})
@@ -1000,9 +1127,10 @@ func testBadCall(
 	serverReply := serverNotifier.serverReplies[0]
 	c.Assert(serverReply, gc.DeepEquals, replyEvent{
 		hdr: rpc.Header{
-			RequestId: requestId,
-			Error:     expectedErr,
-			ErrorCode: expectedErrCode,
+			RequestId:     requestId,
+			Error:         expectedErr,
+			ErrorCode:     expectedErrCode,
+			CorrelationId: correlationId,
 		},
 		req:  req,
 		body: struct{}{},
@@ -1025,10 +1153,10 @@ func (*rpcSuite) TestContinueAfterReadBodyError(c *gc.C) {
 		X: map[string]int{"hello": 65},
 	}
 	err := client.Call(rpc.Request{"SimpleMethods", 0, "a0", "SliceArg"}, arg0, &ret)
-	c.Assert(err, gc.ErrorMatches, `request error: json: cannot unmarshal object into Go value of type \[\]string`)
+	c.Assert(err, gc.ErrorMatches, `request error: json: cannot unmarshal object into Go value of type \[\]string`+correlationIdPattern)
 
 	err = client.Call(rpc.Request{"SimpleMethods", 0, "a0", "SliceArg"}, arg0, &ret)
-	c.Assert(err, gc.ErrorMatches, `request error: json: cannot unmarshal object into Go value of type \[\]string`)
+	c.Assert(err, gc.ErrorMatches, `request error: json: cannot unmarshal object into Go value of type \[\]string`+correlationIdPattern)
 
 	arg1 := struct {
 		X []string
@@ -1102,7 +1230,7 @@ func (*rpcSuite) TestServerRequestWhenNotServing(c *gc.C) {
 	defer closeClient(c, client, srvDone)
 	var r int64val
 	err := client.Call(rpc.Request{"CallbackMethods", 0, "", "Factorial"}, int64val{12}, &r)
-	c.Assert(err, gc.ErrorMatches, "request error: request error: no service")
+	c.Assert(err, gc.ErrorMatches, "request error: request error: no service"+correlationIdPattern+correlationIdPattern)
 }
 
 func (*rpcSuite) TestChangeAPI(c *gc.C) {
@@ -1111,11 +1239,11 @@ func (*rpcSuite) TestChangeAPI(c *gc.C) {
 	defer closeClient(c, client, srvDone)
 	var s stringVal
 	err := client.Call(rpc.Request{"NewlyAvailable", 0, "", "NewMethod"}, nil, &s)
-	c.Assert(err, gc.ErrorMatches, `request error: unknown object type "NewlyAvailable" \(not implemented\)`)
+	c.Assert(err, gc.ErrorMatches, `request error: unknown object type "NewlyAvailable" \(not implemented\)`+correlationIdPattern)
 	err = client.Call(rpc.Request{"ChangeAPIMethods", 0, "", "ChangeAPI"}, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	err = client.Call(rpc.Request{"ChangeAPIMethods", 0, "", "ChangeAPI"}, nil, nil)
-	c.Assert(err, gc.ErrorMatches, `request error: unknown object type "ChangeAPIMethods" \(not implemented\)`)
+	c.Assert(err, gc.ErrorMatches, `request error: unknown object type "ChangeAPIMethods" \(not implemented\)`+correlationIdPattern)
 	err = client.Call(rpc.Request{"NewlyAvailable", 0, "", "NewMethod"}, nil, &s)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(s, gc.Equals, stringVal{"new method result"})
@@ -1130,7 +1258,7 @@ func (*rpcSuite) TestChangeAPIToNil(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	err = client.Call(rpc.Request{"ChangeAPIMethods", 0, "", "RemoveAPI"}, nil, nil)
-	c.Assert(err, gc.ErrorMatches, "request error: no service")
+	c.Assert(err, gc.ErrorMatches, "request error: no service"+correlationIdPattern)
 }
 
 func (*rpcSuite) TestChangeAPIWhileServingRequest(c *gc.C) {
@@ -1161,7 +1289,7 @@ func (*rpcSuite) TestChangeAPIWhileServingRequest(c *gc.C) {
 	done <- fmt.Errorf("an error")
 	select {
 	case r := <-result:
-		c.Assert(r, gc.ErrorMatches, "request error: transformed: an error")
+		c.Assert(r, gc.ErrorMatches, "request error: transformed: an error"+correlationIdPattern)
 	case <-time.After(3 * time.Second):
 		c.Fatalf("timeout on channel read")
 	}
@@ -62,6 +62,11 @@ type Header struct {
 
 	// ErrorCode holds the code of the error, if any.
 	ErrorCode string
+
+	// CorrelationId identifies the operation a request is part of,
+	// so that everything done on its behalf can be traced. Replies
+	// echo the correlation id of the request.
+	CorrelationId string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -432,6 +437,11 @@ func (conn *Conn) readBody(resp interface{}, isRequest bool) error {
 
 func (conn *Conn) handleRequest(hdr *Header) error {
 	startTime := time.Now()
+	if hdr.CorrelationId == "" {
+		// Clients that predate correlation ids do not send them,
+		// but their requests can still be traced on the server.
+		hdr.CorrelationId = NewCorrelationId()
+	}
 	req, err := conn.bindRequest(hdr)
 	if err != nil {
 		if conn.notifier != nil {
@@ -495,7 +505,8 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, startTime time.T
 	conn.sending.Lock()
 	defer conn.sending.Unlock()
 	hdr := &Header{
-		RequestId: reqHdr.RequestId,
+		RequestId:     reqHdr.RequestId,
+		CorrelationId: reqHdr.CorrelationId,
 	}
 	if err, ok := err.(ErrorCoder); ok {
 		hdr.ErrorCode = err.ErrorCode()
@@ -557,7 +568,8 @@ func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, startTime time
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), startTime)
 	} else {
 		hdr := &Header{
-			RequestId: req.hdr.RequestId,
+			RequestId:     req.hdr.RequestId,
+			CorrelationId: req.hdr.CorrelationId,
 		}
 		var rvi interface{}
 		if rv.IsValid() {
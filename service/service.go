@@ -10,7 +10,6 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
-	"github.com/juju/utils/series"
 
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/service/common"
@@ -136,9 +135,10 @@ func newService(name string, conf common.Conf, initSystem, series string) (Servi
 	}
 }
 
-// ListServices lists all installed services on the running system
+// ListServices lists all installed services on the running system,
+// using the same init system as DiscoverService.
 func ListServices() ([]string, error) {
-	initName, err := VersionInitSystem(series.HostSeries())
+	initName, err := discoverInitSystem()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -4,6 +4,8 @@
 package service_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/errors"
@@ -75,6 +77,21 @@ func (s *serviceSuite) TestListServices(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 }
 
+func (s *serviceSuite) TestListServicesLocalInitSystem(c *gc.C) {
+	// A vivid host still running upstart must have its services
+	// listed from upstart, as DiscoverService would install them.
+	s.PatchSeries("vivid")
+	s.PatchLocalDiscoveryNoMatch(service.InitSystemUpstart)
+	initDir := c.MkDir()
+	s.PatchValue(&upstart.InitDir, initDir)
+	err := ioutil.WriteFile(filepath.Join(initDir, "jujud-unit-wordpress-0.conf"), nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	services, err := service.ListServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(services, jc.DeepEquals, []string{"jujud-unit-wordpress-0"})
+}
+
 func (*serviceSuite) TestListServicesScript(c *gc.C) {
 	script := service.ListServicesScript()
 
@@ -576,8 +576,40 @@ func (st *State) machineStorageOps(
 		})
 	}
 
-	// TODO(axw) handle args.filesystemAttachments, args.volumeAttachments
-	// when we handle attaching to existing (e.g. shared) storage.
+	// Create attachments to existing filesystems and volumes.
+	for tag, params := range args.filesystemAttachments {
+		f, err := st.filesystemByTag(tag)
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		filesystemOps = append(filesystemOps, attachExistingStorageOp(
+			filesystemsC, f.doc.FilesystemId,
+		))
+		var storageTag names.StorageTag
+		if f.doc.StorageId != "" {
+			storageTag = names.NewStorageTag(f.doc.StorageId)
+		}
+		fsAttachments = append(fsAttachments, filesystemAttachmentTemplate{
+			tag, storageTag, params,
+		})
+		if f.doc.VolumeId != "" {
+			// The filesystem is volume-backed, so attach the volume too.
+			volumeOps = append(volumeOps, attachExistingStorageOp(
+				volumesC, f.doc.VolumeId,
+			))
+			volumeAttachments = append(volumeAttachments, volumeAttachmentTemplate{
+				names.NewVolumeTag(f.doc.VolumeId), VolumeAttachmentParams{},
+			})
+		}
+	}
+	for tag, params := range args.volumeAttachments {
+		volumeOps = append(volumeOps, attachExistingStorageOp(
+			volumesC, tag.Id(),
+		))
+		volumeAttachments = append(volumeAttachments, volumeAttachmentTemplate{
+			tag, params,
+		})
+	}
 
 	ops := make([]txn.Op, 0, len(filesystemOps)+len(volumeOps)+len(fsAttachments)+len(volumeAttachments))
 	if len(fsAttachments) > 0 {
@@ -593,6 +625,17 @@ func (st *State) machineStorageOps(
 	return ops, volumeAttachments, fsAttachments, nil
 }
 
+// attachExistingStorageOp returns a txn.Op that increments the attachment
+// count of an existing volume or filesystem, asserting that it is Alive.
+func attachExistingStorageOp(collection, id string) txn.Op {
+	return txn.Op{
+		C:      collection,
+		Id:     id,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$inc", bson.D{{"attachmentcount", 1}}}},
+	}
+}
+
 // addMachineStorageAttachmentsOps returns txn.Ops for adding the IDs of
 // attached volumes and filesystems to an existing machine. Filesystem
 // mount points are checked against existing filesystem attachments for
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// AddressSelector returns a selector that chooses machine addresses
+// according to the environment's address selection policy.
+func (st *State) AddressSelector() (network.AddressSelector, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return network.AddressSelector{}, errors.Trace(err)
+	}
+	return st.addressSelector(cfg.AddressSelectionPolicy())
+}
+
+// addressSelector returns a selector for the given policy. The CIDRs
+// of the subnets in the space of a space-scoped policy are looked up;
+// if the space does not exist the selector falls back to the default
+// selection.
+func (st *State) addressSelector(policy network.AddressPolicy) (network.AddressSelector, error) {
+	selector := network.AddressSelector{Policy: policy}
+	spaceName, ok := policy.Space()
+	if !ok {
+		return selector, nil
+	}
+	space, err := st.Space(spaceName)
+	if errors.IsNotFound(err) {
+		logger.Warningf("address selection policy refers to unknown space %q", spaceName)
+		return selector, nil
+	} else if err != nil {
+		return network.AddressSelector{}, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return network.AddressSelector{}, errors.Trace(err)
+	}
+	for _, subnet := range subnets {
+		selector.SpaceCIDRs = append(selector.SpaceCIDRs, subnet.CIDR())
+	}
+	return selector, nil
+}
+
+// AddressPolicy returns the policy used to select the public and
+// private addresses of the service's units. If it is the default
+// policy, the environment's address selection policy applies.
+func (s *Service) AddressPolicy() network.AddressPolicy {
+	return s.doc.AddressPolicy
+}
+
+// SetAddressPolicy changes the policy used to select the public and
+// private addresses of the service's units.
+func (s *Service) SetAddressPolicy(policy network.AddressPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set address policy for service %q", s)
+	if err := policy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"addresspolicy", policy}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errNotAlive
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.AddressPolicy = policy
+	return nil
+}
+
+// unitAddressSelector returns the selector for the addresses of the
+// unit with the given service, and whether the service overrides the
+// environment's address selection policy.
+func (st *State) unitAddressSelector(serviceName string) (network.AddressSelector, bool, error) {
+	service, err := st.Service(serviceName)
+	if err != nil {
+		return network.AddressSelector{}, false, errors.Trace(err)
+	}
+	policy := service.AddressPolicy()
+	if policy == network.DefaultAddressPolicy {
+		return network.AddressSelector{}, false, nil
+	}
+	selector, err := st.addressSelector(policy)
+	if err != nil {
+		return network.AddressSelector{}, false, errors.Trace(err)
+	}
+	return selector, true, nil
+}
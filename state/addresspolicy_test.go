@@ -0,0 +1,146 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type AddressPolicySuite struct {
+	ConnSuite
+	service *state.Service
+	unit    *state.Unit
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&AddressPolicySuite{})
+
+var (
+	policyPublic  = network.NewScopedAddress("8.8.8.8", network.ScopePublic)
+	policyPrivate = network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal)
+	policySpace   = network.NewScopedAddress("192.168.1.1", network.ScopeCloudLocal)
+)
+
+func (s *AddressPolicySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	var err error
+	s.unit, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AddressPolicySuite) addSpace(c *gc.C, name, cidr string) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{
+		ProviderId: "subnet-" + name,
+		CIDR:       cidr,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace(name, []string{cidr}, false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AddressPolicySuite) assertUnitAddresses(c *gc.C, public, private string) {
+	addr, err := s.unit.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addr.Value, gc.Equals, public)
+	addr, err = s.unit.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addr.Value, gc.Equals, private)
+}
+
+func (s *AddressPolicySuite) TestSetAddressPolicy(c *gc.C) {
+	c.Assert(s.service.AddressPolicy(), gc.Equals, network.DefaultAddressPolicy)
+	err := s.service.SetAddressPolicy(network.PreferPublicAddressPolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.AddressPolicy(), gc.Equals, network.PreferPublicAddressPolicy)
+
+	service, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.AddressPolicy(), gc.Equals, network.PreferPublicAddressPolicy)
+}
+
+func (s *AddressPolicySuite) TestSetAddressPolicyInvalid(c *gc.C) {
+	err := s.service.SetAddressPolicy("prefer-ipv9")
+	c.Assert(err, gc.ErrorMatches, `cannot set address policy for service "mysql": address policy "prefer-ipv9" not valid`)
+}
+
+func (s *AddressPolicySuite) TestSetAddressPolicyServiceNotAlive(c *gc.C) {
+	err := s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetAddressPolicy(network.PreferPublicAddressPolicy)
+	c.Assert(err, gc.ErrorMatches, `cannot set address policy for service "mysql": not found or not alive`)
+}
+
+func (s *AddressPolicySuite) TestServiceAddressPolicy(c *gc.C) {
+	err := s.machine.SetProviderAddresses(policyPublic, policyPrivate, policySpace)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitAddresses(c, "8.8.8.8", "10.0.0.1")
+
+	err = s.service.SetAddressPolicy(network.PreferPublicAddressPolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitAddresses(c, "8.8.8.8", "8.8.8.8")
+
+	err = s.service.SetAddressPolicy(network.PreferInternalAddressPolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitAddresses(c, "10.0.0.1", "10.0.0.1")
+}
+
+func (s *AddressPolicySuite) TestServiceSpaceAddressPolicy(c *gc.C) {
+	s.addSpace(c, "db", "192.168.1.0/24")
+	err := s.machine.SetProviderAddresses(policyPublic, policyPrivate, policySpace)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.SetAddressPolicy(network.SpaceAddressPolicy("db"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitAddresses(c, "192.168.1.1", "192.168.1.1")
+}
+
+func (s *AddressPolicySuite) TestServiceSpaceAddressPolicyUnknownSpace(c *gc.C) {
+	err := s.machine.SetProviderAddresses(policyPublic, policyPrivate, policySpace)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.SetAddressPolicy(network.SpaceAddressPolicy("db"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitAddresses(c, "8.8.8.8", "10.0.0.1")
+}
+
+func (s *AddressPolicySuite) TestEnvironAddressPolicy(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"address-selection-policy": "prefer-internal",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetProviderAddresses(policyPublic, policyPrivate)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitAddresses(c, "10.0.0.1", "10.0.0.1")
+
+	selector, err := s.State.AddressSelector()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(selector, jc.DeepEquals, network.AddressSelector{
+		Policy: network.PreferInternalAddressPolicy,
+	})
+}
+
+func (s *AddressPolicySuite) TestEnvironSpaceAddressSelector(c *gc.C) {
+	s.addSpace(c, "db", "192.168.1.0/24")
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"address-selection-policy": "space:db",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	selector, err := s.State.AddressSelector()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(selector, jc.DeepEquals, network.AddressSelector{
+		Policy:     network.SpaceAddressPolicy("db"),
+		SpaceCIDRs: []string{"192.168.1.0/24"},
+	})
+}
@@ -15,6 +15,9 @@ var (
 	txnLogSizeTests = 1000000
 )
 
+// The capped collection used for the API audit log defaults to 10MB.
+var auditLogSize = 10000000
+
 // allCollections should be the single source of truth for information about
 // any collection we use. It's broken up into 4 main sections:
 //
@@ -107,6 +110,13 @@ func allCollections() collectionSchema {
 			rawAccess: true,
 		},
 
+		// This collection holds the result of the last check of the
+		// transaction queues, made on behalf of all environments.
+		txnHealthC: {
+			global:    true,
+			rawAccess: true,
+		},
+
 		// This collection is used as a unique key restraint. The _id field is
 		// a concatenation of multiple fields that form a compound index,
 		// allowing us to ensure users cannot have the same name for two
@@ -189,6 +199,9 @@ func allCollections() collectionSchema {
 		// meterStatusC is the collection used to store meter status information.
 		meterStatusC:  {},
 		settingsrefsC: {},
+
+		// unitProcessesC holds the workload processes tracked by units.
+		unitProcessesC: {},
 		relationsC: {
 			indexes: []mgo.Index{{
 				Key: []string{"env-uuid", "endpoints.relationname"},
@@ -205,6 +218,7 @@ func allCollections() collectionSchema {
 		instanceDataC:  {},
 		machinesC:      {},
 		rebootC:        {},
+		sshHostKeysC:   {},
 
 		// -----
 
@@ -270,6 +284,7 @@ func allCollections() collectionSchema {
 				Unique: true,
 			}},
 		},
+		firewallRulesC:     {},
 		openedPortsC:       {},
 		requestedNetworksC: {},
 		subnetsC: {
@@ -314,6 +329,13 @@ func allCollections() collectionSchema {
 				Key: []string{"env-uuid", "globalkey"},
 			}},
 		},
+
+		// This collection holds problem reports uploaded by agents.
+		problemReportsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "seq"},
+			}},
+		},
 		spacesC: {},
 
 		// This collection holds information about cloud image metadata.
@@ -325,6 +347,22 @@ func allCollections() collectionSchema {
 		// ======================
 
 		// metrics; status-history; logs; ..?
+
+		// This collection holds the audit log of state-changing API
+		// requests for all environments. It is capped, so the oldest
+		// entries are discarded once it is full.
+		auditLogC: {
+			rawAccess: true,
+			explicitCreate: &mgo.CollectionInfo{
+				Capped:   true,
+				MaxBytes: auditLogSize,
+			},
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "time"},
+			}, {
+				Key: []string{"env-uuid", "correlation-id"},
+			}},
+		},
 	}
 }
 
@@ -337,6 +375,7 @@ const (
 	actionresultsC         = "actionresults"
 	actionsC               = "actions"
 	annotationsC           = "annotations"
+	auditLogC              = "auditlog"
 	blockDevicesC          = "blockdevices"
 	blocksC                = "blocks"
 	charmsC                = "charms"
@@ -348,6 +387,7 @@ const (
 	environmentsC          = "environments"
 	filesystemAttachmentsC = "filesystemAttachments"
 	filesystemsC           = "filesystems"
+	firewallRulesC         = "firewallRules"
 	instanceDataC          = "instanceData"
 	ipaddressesC           = "ipaddresses"
 	leaseC                 = "lease"
@@ -368,6 +408,7 @@ const (
 	sequenceC              = "sequence"
 	servicesC              = "services"
 	settingsC              = "settings"
+	problemReportsC        = "problemreports"
 	settingsrefsC          = "settingsrefs"
 	stateServersC          = "stateServers"
 	statusesC              = "statuses"
@@ -377,9 +418,12 @@ const (
 	storageInstancesC      = "storageinstances"
 	subnetsC               = "subnets"
 	spacesC                = "spaces"
+	sshHostKeysC           = "sshhostkeys"
 	toolsmetadataC         = "toolsmetadata"
+	txnHealthC             = "txnhealth"
 	txnLogC                = "txns.log"
 	txnsC                  = "txns"
+	unitProcessesC         = "unitprocesses"
 	unitsC                 = "units"
 	upgradeInfoC           = "upgradeInfo"
 	userenvnameC           = "userenvname"
@@ -86,6 +86,39 @@ func (st *State) Annotations(entity GlobalEntity) (map[string]string, error) {
 	return doc.Annotations, nil
 }
 
+// AnnotationsForEntities returns the annotations of each of the given
+// entities, keyed by the string form of the entity's tag. The
+// annotations are read in a single query; entities without annotations
+// have empty maps.
+func (st *State) AnnotationsForEntities(entities []GlobalEntity) (map[string]map[string]string, error) {
+	result := make(map[string]map[string]string)
+	tags := make(map[string]string)
+	docIds := make([]string, len(entities))
+	for i, entity := range entities {
+		tag := entity.Tag().String()
+		result[tag] = make(map[string]string)
+		tags[entity.globalKey()] = tag
+		docIds[i] = st.docID(entity.globalKey())
+	}
+	if len(entities) == 0 {
+		return result, nil
+	}
+
+	annotations, closer := st.getCollection(annotationsC)
+	defer closer()
+	var docs []annotatorDoc
+	err := annotations.Find(bson.D{{"_id", bson.D{{"$in", docIds}}}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, doc := range docs {
+		if tag, ok := tags[doc.GlobalKey]; ok && doc.Annotations != nil {
+			result[tag] = doc.Annotations
+		}
+	}
+	return result, nil
+}
+
 // Annotation returns the annotation value corresponding to the given key.
 // If the requested annotation is not found, an empty string is returned.
 func (st *State) Annotation(entity GlobalEntity, key string) (string, error) {
@@ -66,6 +66,25 @@ func assertAnnotation(c *gc.C, st *state.State, entity state.GlobalEntity, key,
 	c.Assert(value, gc.DeepEquals, expected)
 }
 
+func (s *AnnotationsSuite) TestAnnotationsForEntities(c *gc.C) {
+	s.assertSetAnnotation(c, "testkey", "typo")
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.State.AnnotationsForEntities([]state.GlobalEntity{s.testEntity, other})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, map[string]map[string]string{
+		s.testEntity.Tag().String(): {"testkey": "typo"},
+		other.Tag().String():        {},
+	})
+}
+
+func (s *AnnotationsSuite) TestAnnotationsForNoEntities(c *gc.C) {
+	result, err := s.State.AnnotationsForEntities(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 0)
+}
+
 func (s *AnnotationsSuite) TestSetAnnotationsUpdate(c *gc.C) {
 	key := s.createTestAnnotation(c)
 	updated := "fixed"
@@ -0,0 +1,143 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// AuditEntry records a state-changing API request made against an
+// environment.
+type AuditEntry struct {
+	// Time is when the request completed.
+	Time time.Time
+
+	// Entity is the tag of the entity that made the request.
+	Entity string
+
+	// Facade, Version and Method identify the API call made.
+	Facade  string
+	Version int
+	Method  string
+
+	// Args holds a summary of the arguments to the call, with any
+	// secrets removed.
+	Args string
+
+	// Error and ErrorCode hold the error returned by the call, if
+	// any.
+	Error     string
+	ErrorCode string
+
+	// CorrelationId identifies the operation the call was made as
+	// part of, as given by the client.
+	CorrelationId string
+}
+
+// AuditFilter restricts the audit entries returned by AuditEntries.
+// Zero-valued fields do not restrict the results.
+type AuditFilter struct {
+	// Entity restricts the entries to those made by the entity with
+	// the given tag.
+	Entity string
+
+	// Facade restricts the entries to calls made to the named facade.
+	Facade string
+
+	// CorrelationId restricts the entries to calls made with the
+	// given correlation id.
+	CorrelationId string
+
+	// Since restricts the entries to those made after the given time.
+	Since time.Time
+
+	// Limit is the maximum number of entries returned.
+	Limit int
+}
+
+type auditEntryDoc struct {
+	EnvUUID   string    `bson:"env-uuid"`
+	Time      time.Time `bson:"time"`
+	Entity    string    `bson:"entity"`
+	Facade    string    `bson:"facade"`
+	Version   int       `bson:"version"`
+	Method    string    `bson:"method"`
+	Args      string    `bson:"args,omitempty"`
+	Error     string    `bson:"error,omitempty"`
+	ErrorCode string    `bson:"errorcode,omitempty"`
+
+	CorrelationId string `bson:"correlation-id,omitempty"`
+}
+
+// AddAuditEntry records the given entry in the environment's audit log.
+// The audit log is capped, so the oldest entries are discarded as new
+// ones are added.
+func (st *State) AddAuditEntry(entry AuditEntry) error {
+	auditLog, closer := st.getCollection(auditLogC)
+	defer closer()
+	doc := &auditEntryDoc{
+		Time:      entry.Time.UTC(),
+		Entity:    entry.Entity,
+		Facade:    entry.Facade,
+		Version:   entry.Version,
+		Method:    entry.Method,
+		Args:      entry.Args,
+		Error:     entry.Error,
+		ErrorCode: entry.ErrorCode,
+
+		CorrelationId: entry.CorrelationId,
+	}
+	if err := auditLog.Writeable().Insert(doc); err != nil {
+		return errors.Annotate(err, "cannot add audit entry")
+	}
+	return nil
+}
+
+// AuditEntries returns the entries in the environment's audit log that
+// match the given filter, newest first.
+func (st *State) AuditEntries(filter AuditFilter) ([]AuditEntry, error) {
+	auditLog, closer := st.getCollection(auditLogC)
+	defer closer()
+
+	sel := bson.D{}
+	if filter.Entity != "" {
+		sel = append(sel, bson.DocElem{"entity", filter.Entity})
+	}
+	if filter.Facade != "" {
+		sel = append(sel, bson.DocElem{"facade", filter.Facade})
+	}
+	if filter.CorrelationId != "" {
+		sel = append(sel, bson.DocElem{"correlation-id", filter.CorrelationId})
+	}
+	if !filter.Since.IsZero() {
+		sel = append(sel, bson.DocElem{"time", bson.D{{"$gt", filter.Since.UTC()}}})
+	}
+	query := auditLog.Find(sel).Sort("-time")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var docs []auditEntryDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get audit entries")
+	}
+	entries := make([]AuditEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = AuditEntry{
+			Time:      doc.Time.UTC(),
+			Entity:    doc.Entity,
+			Facade:    doc.Facade,
+			Version:   doc.Version,
+			Method:    doc.Method,
+			Args:      doc.Args,
+			Error:     doc.Error,
+			ErrorCode: doc.ErrorCode,
+
+			CorrelationId: doc.CorrelationId,
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type auditSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+func (s *auditSuite) addEntries(c *gc.C, st *state.State, entries ...state.AuditEntry) {
+	for _, entry := range entries {
+		err := st.AddAuditEntry(entry)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *auditSuite) TestIndexesCreated(c *gc.C) {
+	auditLog := s.State.MongoSession().DB("juju").C("auditlog")
+	indexes, err := auditLog.Indexes()
+	c.Assert(err, jc.ErrorIsNil)
+	keys := set.NewStrings()
+	for _, index := range indexes {
+		keys.Add(strings.Join(index.Key, ","))
+	}
+	c.Check(keys.Contains("env-uuid,time"), jc.IsTrue)
+	c.Check(keys.Contains("env-uuid,correlation-id"), jc.IsTrue)
+}
+
+func (s *auditSuite) TestAuditEntries(c *gc.C) {
+	now := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	deploy := state.AuditEntry{
+		Time:   now.Add(-2 * time.Hour),
+		Entity: "user-admin@local",
+		Facade: "Client",
+		Method: "ServiceDeploy",
+		Args:   `{"ServiceName":"mysql"}`,
+	}
+	expose := state.AuditEntry{
+		Time:      now.Add(-time.Hour),
+		Entity:    "user-bob@local",
+		Facade:    "Client",
+		Method:    "ServiceExpose",
+		Args:      `{"ServiceName":"wordpress"}`,
+		Error:     `service "wordpress" not found`,
+		ErrorCode: "not found",
+
+		CorrelationId: "0123456789abcdef",
+	}
+	setStatus := state.AuditEntry{
+		Time:    now,
+		Entity:  "unit-mysql-0",
+		Facade:  "Uniter",
+		Version: 2,
+		Method:  "SetStatus",
+	}
+	s.addEntries(c, s.State, deploy, expose, setStatus)
+
+	for i, test := range []struct {
+		filter   state.AuditFilter
+		expected []state.AuditEntry
+	}{{
+		expected: []state.AuditEntry{setStatus, expose, deploy},
+	}, {
+		filter:   state.AuditFilter{Entity: "user-admin@local"},
+		expected: []state.AuditEntry{deploy},
+	}, {
+		filter:   state.AuditFilter{Facade: "Client"},
+		expected: []state.AuditEntry{expose, deploy},
+	}, {
+		filter:   state.AuditFilter{CorrelationId: "0123456789abcdef"},
+		expected: []state.AuditEntry{expose},
+	}, {
+		filter:   state.AuditFilter{Since: now.Add(-90 * time.Minute)},
+		expected: []state.AuditEntry{setStatus, expose},
+	}, {
+		filter:   state.AuditFilter{Limit: 1},
+		expected: []state.AuditEntry{setStatus},
+	}} {
+		c.Logf("test %d: %+v", i, test.filter)
+		entries, err := s.State.AuditEntries(test.filter)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(entries, jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *auditSuite) TestAuditEntriesPerEnvironment(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	s.addEntries(c, st, state.AuditEntry{
+		Time:   time.Now(),
+		Entity: "user-admin@local",
+		Facade: "Client",
+		Method: "ServiceDeploy",
+	})
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+	entries, err = st.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+}
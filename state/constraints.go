@@ -29,37 +29,61 @@ type constraintsDoc struct {
 	Spaces       *[]string
 	// TODO(dimitern): Drop this once it's not possible to specify
 	// networks= in constraints.
-	Networks *[]string
+	Networks       *[]string
+	VirtType       *string
+	RootDiskSource *string
+	Zones          *[]string
+	Profile        *string
+	MaxCpuCores    *uint64
+	MaxCpuPower    *uint64
+	MaxMem         *uint64
+	MaxRootDisk    *uint64
 }
 
 func (doc constraintsDoc) value() constraints.Value {
 	return constraints.Value{
-		Arch:         doc.Arch,
-		CpuCores:     doc.CpuCores,
-		CpuPower:     doc.CpuPower,
-		Mem:          doc.Mem,
-		RootDisk:     doc.RootDisk,
-		InstanceType: doc.InstanceType,
-		Container:    doc.Container,
-		Tags:         doc.Tags,
-		Spaces:       doc.Spaces,
-		Networks:     doc.Networks,
+		Arch:           doc.Arch,
+		CpuCores:       doc.CpuCores,
+		CpuPower:       doc.CpuPower,
+		Mem:            doc.Mem,
+		RootDisk:       doc.RootDisk,
+		InstanceType:   doc.InstanceType,
+		Container:      doc.Container,
+		Tags:           doc.Tags,
+		Spaces:         doc.Spaces,
+		Networks:       doc.Networks,
+		VirtType:       doc.VirtType,
+		RootDiskSource: doc.RootDiskSource,
+		Zones:          doc.Zones,
+		Profile:        doc.Profile,
+		MaxCpuCores:    doc.MaxCpuCores,
+		MaxCpuPower:    doc.MaxCpuPower,
+		MaxMem:         doc.MaxMem,
+		MaxRootDisk:    doc.MaxRootDisk,
 	}
 }
 
 func newConstraintsDoc(st *State, cons constraints.Value) constraintsDoc {
 	return constraintsDoc{
-		EnvUUID:      st.EnvironUUID(),
-		Arch:         cons.Arch,
-		CpuCores:     cons.CpuCores,
-		CpuPower:     cons.CpuPower,
-		Mem:          cons.Mem,
-		RootDisk:     cons.RootDisk,
-		InstanceType: cons.InstanceType,
-		Container:    cons.Container,
-		Tags:         cons.Tags,
-		Spaces:       cons.Spaces,
-		Networks:     cons.Networks,
+		EnvUUID:        st.EnvironUUID(),
+		Arch:           cons.Arch,
+		CpuCores:       cons.CpuCores,
+		CpuPower:       cons.CpuPower,
+		Mem:            cons.Mem,
+		RootDisk:       cons.RootDisk,
+		InstanceType:   cons.InstanceType,
+		Container:      cons.Container,
+		Tags:           cons.Tags,
+		Spaces:         cons.Spaces,
+		Networks:       cons.Networks,
+		VirtType:       cons.VirtType,
+		RootDiskSource: cons.RootDiskSource,
+		Zones:          cons.Zones,
+		Profile:        cons.Profile,
+		MaxCpuCores:    cons.MaxCpuCores,
+		MaxCpuPower:    cons.MaxCpuPower,
+		MaxMem:         cons.MaxMem,
+		MaxRootDisk:    cons.MaxRootDisk,
 	}
 }
 
@@ -231,3 +231,47 @@ func (s *constraintsValidationSuite) TestServiceConstraints(c *gc.C) {
 		c.Check(scons, jc.DeepEquals, constraints.MustParse(t.effectiveServiceCons))
 	}
 }
+
+func (s *constraintsValidationSuite) TestConstraintProfiles(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"constraint-profiles": map[string]interface{}{
+			"db": "mem=16G cpu-cores=8",
+		},
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironConstraints(constraints.MustParse("arch=amd64 cpu-cores=2"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Profiles are expanded when machine constraints are resolved,
+	// with explicit constraints taking precedence.
+	m, err := s.addOneMachine(c, constraints.MustParse("profile=db mem=32G"))
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err := m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cons, jc.DeepEquals, constraints.MustParse("arch=amd64 cpu-cores=8 mem=32G"))
+
+	// Service constraints keep the profile, and units get the
+	// expanded constraints.
+	charm := s.AddTestingCharm(c, "wordpress")
+	service := s.AddTestingService(c, "wordpress", charm)
+	err = service.SetConstraints(constraints.MustParse("profile=db"))
+	c.Assert(err, jc.ErrorIsNil)
+	scons, err := service.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(scons, jc.DeepEquals, constraints.MustParse("profile=db"))
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ucons, err := u.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*ucons, jc.DeepEquals, constraints.MustParse("arch=amd64 cpu-cores=8 mem=16G"))
+}
+
+func (s *constraintsValidationSuite) TestUnknownConstraintProfile(c *gc.C) {
+	charm := s.AddTestingCharm(c, "wordpress")
+	service := s.AddTestingService(c, "wordpress", charm)
+	err := service.SetConstraints(constraints.MustParse("profile=db"))
+	c.Assert(err, gc.ErrorMatches, `constraint profile "db" not found`)
+
+	_, err = s.addOneMachine(c, constraints.MustParse("profile=db"))
+	c.Assert(err, gc.ErrorMatches, `.*constraint profile "db" not found`)
+}
@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// EndpointBindings returns the network spaces the service's charm
+// endpoints are bound to, keyed by endpoint name. Endpoints which are
+// not bound are not included.
+func (s *Service) EndpointBindings() map[string]string {
+	bindings := make(map[string]string, len(s.doc.EndpointBindings))
+	for endpoint, space := range s.doc.EndpointBindings {
+		bindings[endpoint] = space
+	}
+	return bindings
+}
+
+// EndpointBindingSpaces returns the names of the spaces the service's
+// endpoints are bound to, in alphabetical order.
+func (s *Service) EndpointBindingSpaces() []string {
+	spaces := set.NewStrings()
+	for _, space := range s.doc.EndpointBindings {
+		spaces.Add(space)
+	}
+	return spaces.SortedValues()
+}
+
+// SetEndpointBindings binds the service's charm endpoints to network
+// spaces, replacing any existing bindings. Each endpoint must be
+// defined by the service's charm and each space must exist.
+func (s *Service) SetEndpointBindings(bindings map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set endpoint bindings for service %q", s)
+	ch, _, err := s.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	bindingsOps, err := endpointBindingsOps(s.st, ch.Meta(), bindings)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: bson.D{{"life", Alive}, {"charmurl", s.doc.CharmURL}},
+		Update: bson.D{{"$set", bson.D{{"endpointbindings", bindings}}}},
+	}}
+	ops = append(ops, bindingsOps...)
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("service, its charm or a space changed or was removed")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.EndpointBindings = make(map[string]string, len(bindings))
+	for endpoint, space := range bindings {
+		s.doc.EndpointBindings[endpoint] = space
+	}
+	return nil
+}
+
+// endpointBindingsOps checks that each endpoint in bindings is defined
+// by the charm with the given metadata, and that each space exists. It
+// returns txn.Ops asserting that the spaces still exist.
+func endpointBindingsOps(st *State, meta *charm.Meta, bindings map[string]string) ([]txn.Op, error) {
+	known := set.NewStrings("juju-info")
+	for _, rels := range []map[string]charm.Relation{meta.Peers, meta.Provides, meta.Requires} {
+		for name := range rels {
+			known.Add(name)
+		}
+	}
+	var ops []txn.Op
+	for _, endpoint := range sortedKeys(bindings) {
+		space := bindings[endpoint]
+		if !known.Contains(endpoint) {
+			return nil, errors.NotFoundf("endpoint %q", endpoint)
+		}
+		if _, err := st.Space(space); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     st.docID(space),
+			Assert: txn.DocExists,
+		})
+	}
+	return ops, nil
+}
+
+// sortedKeys returns the keys of m in alphabetical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// endpointAddressSelector returns the selector for the addresses used
+// by the given endpoint of the named service, and whether the endpoint
+// is bound to a space.
+func (st *State) endpointAddressSelector(serviceName, endpoint string) (network.AddressSelector, bool, error) {
+	service, err := st.Service(serviceName)
+	if err != nil {
+		return network.AddressSelector{}, false, errors.Trace(err)
+	}
+	space, ok := service.doc.EndpointBindings[endpoint]
+	if !ok {
+		return network.AddressSelector{}, false, nil
+	}
+	selector, err := st.addressSelector(network.SpaceAddressPolicy(space))
+	if err != nil {
+		return network.AddressSelector{}, false, errors.Trace(err)
+	}
+	return selector, true, nil
+}
+
+// BoundPrivateAddress returns the private address of the unit. If the
+// unit's service has endpoints bound to network spaces, an address in
+// one of those spaces is preferred, trying the spaces in alphabetical
+// order.
+func (u *Unit) BoundPrivateAddress() (network.Address, error) {
+	service, err := u.Service()
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	spaces := service.EndpointBindingSpaces()
+	if len(spaces) == 0 {
+		return u.PrivateAddress()
+	}
+	m, err := u.machine()
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	for _, space := range spaces {
+		selector, err := u.st.addressSelector(network.SpaceAddressPolicy(space))
+		if err != nil {
+			return network.Address{}, errors.Trace(err)
+		}
+		if addr, ok := selector.SelectInternalAddress(m.Addresses(), false); ok {
+			return addr, nil
+		}
+	}
+	return u.PrivateAddress()
+}
@@ -0,0 +1,154 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type EndpointBindingsSuite struct {
+	ConnSuite
+	service *state.Service
+	unit    *state.Unit
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&EndpointBindingsSuite{})
+
+func (s *EndpointBindingsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	var err error
+	s.unit, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, space := range []struct{ name, cidr string }{
+		{"internal", "192.168.1.0/24"},
+		{"dmz", "172.16.0.0/16"},
+	} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{
+			ProviderId: "subnet-" + space.name,
+			CIDR:       space.cidr,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = s.State.AddSpace(space.name, []string{space.cidr}, false)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindings(c *gc.C) {
+	c.Assert(s.service.EndpointBindings(), gc.HasLen, 0)
+	bindings := map[string]string{"server": "internal", "juju-info": "dmz"}
+	err := s.service.SetEndpointBindings(bindings)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.EndpointBindings(), jc.DeepEquals, bindings)
+	c.Assert(s.service.EndpointBindingSpaces(), jc.DeepEquals, []string{"dmz", "internal"})
+
+	service, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, bindings)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindingsUnknownEndpoint(c *gc.C) {
+	err := s.service.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "mysql": endpoint "db" not found`)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindingsUnknownSpace(c *gc.C) {
+	err := s.service.SetEndpointBindings(map[string]string{"server": "public"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "mysql": space "public" not found`)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindingsServiceNotAlive(c *gc.C) {
+	err := s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetEndpointBindings(map[string]string{"server": "internal"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "mysql": service, its charm or a space changed or was removed`)
+}
+
+func (s *EndpointBindingsSuite) TestAddServiceWithEndpointBindings(c *gc.C) {
+	bindings := map[string]string{"server": "internal"}
+	service, err := s.State.AddServiceWithEndpointBindings(
+		"mysql2", s.Owner.String(), s.AddTestingCharm(c, "mysql"), nil, nil, bindings,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, bindings)
+
+	service, err = s.State.Service("mysql2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, bindings)
+}
+
+func (s *EndpointBindingsSuite) TestAddServiceWithEndpointBindingsInvalid(c *gc.C) {
+	ch := s.AddTestingCharm(c, "mysql")
+	_, err := s.State.AddServiceWithEndpointBindings(
+		"mysql2", s.Owner.String(), ch, nil, nil, map[string]string{"db": "internal"},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "mysql2": endpoint "db" not found`)
+	_, err = s.State.AddServiceWithEndpointBindings(
+		"mysql2", s.Owner.String(), ch, nil, nil, map[string]string{"server": "public"},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "mysql2": space "public" not found`)
+
+	// No service is added without its bindings.
+	_, err = s.State.Service("mysql2")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EndpointBindingsSuite) TestUnitBoundPrivateAddress(c *gc.C) {
+	err := s.machine.SetProviderAddresses(
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+		network.NewScopedAddress("172.16.0.1", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err := s.unit.BoundPrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "10.0.0.1")
+
+	// The machine has no address in the internal space, so the
+	// address in the dmz space is used.
+	err = s.service.SetEndpointBindings(map[string]string{"server": "internal", "juju-info": "dmz"})
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err = s.unit.BoundPrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "172.16.0.1")
+}
+
+func (s *EndpointBindingsSuite) TestRelationUnitPrivateAddress(c *gc.C) {
+	err := s.machine.SetProviderAddresses(
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+		network.NewScopedAddress("192.168.1.1", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(s.unit)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addr, err := ru.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "10.0.0.1")
+
+	err = s.service.SetEndpointBindings(map[string]string{"server": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err = ru.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "192.168.1.1")
+
+	// The unit's own private address is unaffected.
+	addr, err = s.unit.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "10.0.0.1")
+}
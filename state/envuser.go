@@ -31,6 +31,37 @@ type envUserDoc struct {
 	DisplayName string    `bson:"displayname"`
 	CreatedBy   string    `bson:"createdby"`
 	DateCreated time.Time `bson:"datecreated"`
+	// Access is empty for users added before access levels were
+	// introduced; they are environment administrators.
+	Access EnvUserAccess `bson:"access,omitempty"`
+}
+
+// EnvUserAccess describes what an environment user is allowed to do
+// in the environment.
+type EnvUserAccess string
+
+const (
+	// EnvAdminAccess allows a user to do anything in the environment,
+	// including sharing it with other users and destroying it.
+	EnvAdminAccess EnvUserAccess = "admin"
+
+	// EnvWriteAccess allows a user to change the environment, for
+	// example by deploying and destroying services and machines.
+	EnvWriteAccess EnvUserAccess = "write"
+
+	// EnvReadAccess allows a user to look at the environment, but not
+	// change it.
+	EnvReadAccess EnvUserAccess = "read"
+)
+
+// Validate returns an error if the access level is not one of those
+// defined above.
+func (a EnvUserAccess) Validate() error {
+	switch a {
+	case EnvAdminAccess, EnvWriteAccess, EnvReadAccess:
+		return nil
+	}
+	return errors.NotValidf("environment user access %q", string(a))
 }
 
 // envUserLastConnectionDoc is updated by the apiserver whenever the user
@@ -80,6 +111,67 @@ func (e *EnvironmentUser) DateCreated() time.Time {
 	return e.doc.DateCreated.UTC()
 }
 
+// Access returns the access level the user has to the environment.
+func (e *EnvironmentUser) Access() EnvUserAccess {
+	if e.doc.Access == "" {
+		return EnvAdminAccess
+	}
+	return e.doc.Access
+}
+
+// adminAccessDoc matches the environment users with administrator
+// access, including those added before access levels were introduced.
+var adminAccessDoc = bson.D{{"access", bson.D{{"$in", []interface{}{EnvAdminAccess, nil}}}}}
+
+// SetAccess changes the access level the user has to the environment.
+// The last administrator of an environment cannot be given less access.
+func (e *EnvironmentUser) SetAccess(access EnvUserAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		envUsers, closer := e.st.getCollection(envUsersC)
+		defer closer()
+
+		var doc envUserDoc
+		err := envUsers.FindId(e.doc.ID).One(&doc)
+		if err == mgo.ErrNotFound {
+			return nil, errors.NotFoundf("environment user %q", e.doc.UserName)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      envUsersC,
+			Id:     e.doc.ID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"access", access}}}},
+		}}
+		if access == EnvAdminAccess || (doc.Access != "" && doc.Access != EnvAdminAccess) {
+			return ops, nil
+		}
+		// The user is an administrator losing that access, so
+		// ensure that another administrator remains.
+		var other envUserDoc
+		err = envUsers.Find(append(bson.D{{"_id", bson.D{{"$ne", doc.ID}}}}, adminAccessDoc...)).One(&other)
+		if err == mgo.ErrNotFound {
+			return nil, errors.New("cannot remove administrator access from the last environment administrator")
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      envUsersC,
+			Id:     other.ID,
+			Assert: adminAccessDoc,
+		}), nil
+	}
+	err := e.st.run(buildTxn)
+	if err != nil {
+		return errors.Annotatef(err, "cannot set access for environment user %q", e.doc.UserName)
+	}
+	e.doc.Access = access
+	return nil
+}
+
 // LastConnection returns when this EnvironmentUser last connected through the API
 // in UTC. The resulting time will be nil if the user has never logged in.
 func (e *EnvironmentUser) LastConnection() (time.Time, error) {
@@ -154,8 +246,18 @@ func (st *State) EnvironmentUser(user names.UserTag) (*EnvironmentUser, error) {
 	return envUser, nil
 }
 
-// AddEnvironmentUser adds a new user to the database.
+// AddEnvironmentUser adds a new user to the database, with
+// administrator access to the environment.
 func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*EnvironmentUser, error) {
+	return st.AddEnvironmentUserWithAccess(user, createdBy, displayName, EnvAdminAccess)
+}
+
+// AddEnvironmentUserWithAccess adds a new user to the database, with
+// the given access to the environment.
+func (st *State) AddEnvironmentUserWithAccess(user, createdBy names.UserTag, displayName string, access EnvUserAccess) (*EnvironmentUser, error) {
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	// Ensure local user exists in state before adding them as an environment user.
 	if user.IsLocal() {
 		localUser, err := st.User(user)
@@ -175,7 +277,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName s
 	}
 
 	envuuid := st.EnvironUUID()
-	op := createEnvUserOp(envuuid, user, createdBy, displayName)
+	op := createEnvUserOp(envuuid, user, createdBy, displayName, access)
 	err := st.runTransaction([]txn.Op{op})
 	if err == txn.ErrAborted {
 		err = errors.AlreadyExistsf("environment user %q", user.Canonical())
@@ -193,7 +295,7 @@ func envUserID(user names.UserTag) string {
 	return strings.ToLower(username)
 }
 
-func createEnvUserOp(envuuid string, user, createdBy names.UserTag, displayName string) txn.Op {
+func createEnvUserOp(envuuid string, user, createdBy names.UserTag, displayName string, access EnvUserAccess) txn.Op {
 	creatorname := createdBy.Canonical()
 	doc := &envUserDoc{
 		ID:          envUserID(user),
@@ -202,6 +304,7 @@ func createEnvUserOp(envuuid string, user, createdBy names.UserTag, displayName
 		DisplayName: displayName,
 		CreatedBy:   creatorname,
 		DateCreated: nowToTheSecond(),
+		Access:      access,
 	}
 	return txn.Op{
 		C:      envUsersC,
@@ -283,8 +386,8 @@ func (st *State) EnvironmentsForUser(user names.UserTag) ([]*UserEnvironment, er
 	return result, nil
 }
 
-// IsSystemAdministrator returns true if the user specified has access to the
-// state server environment (the system environment).
+// IsSystemAdministrator returns true if the user specified has administrator
+// access to the state server environment (the system environment).
 func (st *State) IsSystemAdministrator(user names.UserTag) (bool, error) {
 	ssinfo, err := st.StateServerInfo()
 	if err != nil {
@@ -296,10 +399,10 @@ func (st *State) IsSystemAdministrator(user names.UserTag) (bool, error) {
 	envUsers, userCloser := st.getRawCollection(envUsersC)
 	defer userCloser()
 
-	count, err := envUsers.Find(bson.D{
+	count, err := envUsers.Find(append(bson.D{
 		{"env-uuid", serverUUID},
 		{"user", user.Canonical()},
-	}).Count()
+	}, adminAccessDoc...)).Count()
 	if err != nil {
 		return false, errors.Trace(err)
 	}
@@ -113,6 +113,69 @@ func (s *EnvUserSuite) TestAddEnvironmentDisplayName(c *gc.C) {
 	c.Assert(envUser.DisplayName(), gc.Equals, "Override user display name")
 }
 
+func (s *EnvUserSuite) TestAddEnvironmentUserAccess(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, nil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvAdminAccess)
+
+	envUser = s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvReadAccess})
+	c.Assert(envUser.Access(), gc.Equals, state.EnvReadAccess)
+	envUser, err := s.State.EnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvReadAccess)
+}
+
+func (s *EnvUserSuite) TestAddEnvironmentUserInvalidAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddEnvironmentUserWithAccess(user.UserTag(), env.Owner(), "", "superuser")
+	c.Assert(err, gc.ErrorMatches, `environment user access "superuser" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *EnvUserSuite) TestSetAccess(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, nil)
+	err := envUser.SetAccess(state.EnvWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvWriteAccess)
+
+	envUser, err = s.State.EnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvWriteAccess)
+
+	err = envUser.SetAccess("superuser")
+	c.Assert(err, gc.ErrorMatches, `environment user access "superuser" not valid`)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvWriteAccess)
+}
+
+func (s *EnvUserSuite) TestSetAccessRemovedUser(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, nil)
+	err := s.State.RemoveEnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = envUser.SetAccess(state.EnvReadAccess)
+	c.Assert(err, gc.ErrorMatches, `cannot set access for environment user ".*": environment user ".*" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvUserSuite) TestSetAccessLastAdmin(c *gc.C) {
+	owner, err := s.State.EnvironmentUser(s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvWriteAccess})
+
+	err = owner.SetAccess(state.EnvReadAccess)
+	c.Assert(err, gc.ErrorMatches, `cannot set access for environment user ".*": cannot remove administrator access from the last environment administrator`)
+	c.Assert(owner.Access(), gc.Equals, state.EnvAdminAccess)
+
+	// Once there is another administrator, the owner may be demoted.
+	other := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvAdminAccess})
+	err = owner.SetAccess(state.EnvReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(owner.Access(), gc.Equals, state.EnvReadAccess)
+
+	err = other.SetAccess(state.EnvWriteAccess)
+	c.Assert(err, gc.ErrorMatches, `.*cannot remove administrator access from the last environment administrator`)
+}
+
 func (s *EnvUserSuite) TestAddEnvironmentNoUserFails(c *gc.C) {
 	createdBy := s.Factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
 	_, err := s.State.AddEnvironmentUser(names.NewLocalUserTag("validusername"), createdBy.UserTag(), "")
@@ -310,6 +373,19 @@ func (s *EnvUserSuite) TestIsSystemAdministrator(c *gc.C) {
 	c.Assert(isAdmin, jc.IsTrue)
 }
 
+func (s *EnvUserSuite) TestIsSystemAdministratorNeedsAdminAccess(c *gc.C) {
+	for _, access := range []state.EnvUserAccess{state.EnvReadAccess, state.EnvWriteAccess} {
+		user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+		s.Factory.MakeEnvUser(c, &factory.EnvUserParams{
+			User:   user.UserTag().Canonical(),
+			Access: access,
+		})
+		isAdmin, err := s.State.IsSystemAdministrator(user.UserTag())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(isAdmin, jc.IsFalse)
+	}
+}
+
 func (s *EnvUserSuite) TestIsSystemAdministratorFromOtherState(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
 
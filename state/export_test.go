@@ -52,6 +52,9 @@ var (
 	PickAddress            = &pickAddress
 	AddVolumeOps           = (*State).addVolumeOps
 	CombineMeterStatus     = combineMeterStatus
+
+	MaxProblemReports        = &maxProblemReports
+	MaxProblemReportLogLines = &maxProblemReportLogLines
 )
 
 type (
@@ -201,6 +204,7 @@ func GetPasswordHash(e Authenticator) string {
 
 func init() {
 	txnLogSize = txnLogSizeTests
+	auditLogSize = txnLogSizeTests
 }
 
 // TxnRevno returns the txn-revno field of the document
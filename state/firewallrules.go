@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// FirewallRule restricts access to a well-known service, such as SSH
+// or the API server, to the given source address ranges. An empty
+// whitelist allows access from anywhere.
+type FirewallRule struct {
+	WellKnownService network.WellKnownServiceType
+	WhitelistCIDRs   []string
+}
+
+// firewallRulesDoc records the whitelist for a well-known service.
+type firewallRulesDoc struct {
+	DocID            string   `bson:"_id"`
+	EnvUUID          string   `bson:"env-uuid"`
+	WellKnownService string   `bson:"known-service"`
+	WhitelistCIDRs   []string `bson:"whitelist-source-cidrs"`
+}
+
+func (doc firewallRulesDoc) toRule() *FirewallRule {
+	return &FirewallRule{
+		WellKnownService: network.WellKnownServiceType(doc.WellKnownService),
+		WhitelistCIDRs:   doc.WhitelistCIDRs,
+	}
+}
+
+// SaveFirewallRule records the given firewall rule, replacing any
+// existing rule for the same well-known service.
+func (st *State) SaveFirewallRule(rule FirewallRule) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot save firewall rule for %q", rule.WellKnownService)
+	if err := rule.WellKnownService.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := network.ValidateWhitelistCIDRs(rule.WhitelistCIDRs); err != nil {
+		return errors.Trace(err)
+	}
+	id := string(rule.WellKnownService)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		existing, err := st.FirewallRule(rule.WellKnownService)
+		switch {
+		case errors.IsNotFound(err):
+			return []txn.Op{{
+				C:      firewallRulesC,
+				Id:     st.docID(id),
+				Assert: txn.DocMissing,
+				Insert: &firewallRulesDoc{
+					WellKnownService: id,
+					WhitelistCIDRs:   rule.WhitelistCIDRs,
+				},
+			}}, nil
+		case err != nil:
+			return nil, errors.Trace(err)
+		case stringSlicesEqual(existing.WhitelistCIDRs, rule.WhitelistCIDRs):
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      firewallRulesC,
+			Id:     st.docID(id),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"whitelist-source-cidrs", rule.WhitelistCIDRs}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// FirewallRule returns the firewall rule for the given well-known
+// service. A NotFound error is returned if no rule has been saved.
+func (st *State) FirewallRule(service network.WellKnownServiceType) (*FirewallRule, error) {
+	coll, closer := st.getCollection(firewallRulesC)
+	defer closer()
+
+	var doc firewallRulesDoc
+	err := coll.FindId(string(service)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("firewall rule for %q", service)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get firewall rule for %q", service)
+	}
+	return doc.toRule(), nil
+}
+
+// AllFirewallRules returns all the saved firewall rules.
+func (st *State) AllFirewallRules() ([]*FirewallRule, error) {
+	coll, closer := st.getCollection(firewallRulesC)
+	defer closer()
+
+	var docs []firewallRulesDoc
+	if err := coll.Find(nil).Sort("known-service").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get firewall rules")
+	}
+	rules := make([]*FirewallRule, len(docs))
+	for i, doc := range docs {
+		rules[i] = doc.toRule()
+	}
+	return rules, nil
+}
+
+// WatchFirewallRules returns a NotifyWatcher that notifies of changes
+// to the firewall rules of any well-known service.
+func (st *State) WatchFirewallRules() NotifyWatcher {
+	docKeys := make([]docKey, len(network.WellKnownServices))
+	for i, service := range network.WellKnownServices {
+		docKeys[i] = docKey{firewallRulesC, st.docID(string(service))}
+	}
+	return newDocWatcher(st, docKeys)
+}
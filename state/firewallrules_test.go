@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type FirewallRulesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&FirewallRulesSuite{})
+
+func (s *FirewallRulesSuite) TestFirewallRuleNotFound(c *gc.C) {
+	_, err := s.State.FirewallRule(network.SSHRule)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `firewall rule for "ssh" not found`)
+}
+
+func (s *FirewallRulesSuite) TestSaveFirewallRule(c *gc.C) {
+	for _, cidrs := range [][]string{{"10.0.0.0/8"}, {"10.0.0.0/8", "192.168.0.0/16"}, nil} {
+		err := s.State.SaveFirewallRule(state.FirewallRule{
+			WellKnownService: network.SSHRule,
+			WhitelistCIDRs:   cidrs,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		rule, err := s.State.FirewallRule(network.SSHRule)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(rule.WellKnownService, gc.Equals, network.SSHRule)
+		c.Assert(rule.WhitelistCIDRs, jc.DeepEquals, cidrs)
+	}
+}
+
+func (s *FirewallRulesSuite) TestSaveFirewallRuleInvalid(c *gc.C) {
+	err := s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: "http",
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot save firewall rule for "http": well known service type "http" not valid`)
+
+	err = s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: network.JujuControllerRule,
+		WhitelistCIDRs:   []string{"10.0.0.1"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot save firewall rule for "juju-controller": CIDR "10.0.0.1" not valid`)
+}
+
+func (s *FirewallRulesSuite) TestAllFirewallRules(c *gc.C) {
+	rules, err := s.State.AllFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	for _, service := range []network.WellKnownServiceType{network.SSHRule, network.JujuControllerRule} {
+		err := s.State.SaveFirewallRule(state.FirewallRule{
+			WellKnownService: service,
+			WhitelistCIDRs:   []string{"10.0.0.0/8"},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	rules, err = s.State.AllFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []*state.FirewallRule{{
+		WellKnownService: network.JujuControllerRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	}, {
+		WellKnownService: network.SSHRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	}})
+}
+
+func (s *FirewallRulesSuite) TestWatchFirewallRules(c *gc.C) {
+	w := s.State.WatchFirewallRules()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	rule := state.FirewallRule{
+		WellKnownService: network.JujuControllerRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	}
+	err := s.State.SaveFirewallRule(rule)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Saving the same rule again is not a change.
+	err = s.State.SaveFirewallRule(rule)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	rule.WellKnownService = network.SSHRule
+	err = s.State.SaveFirewallRule(rule)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -34,6 +34,17 @@ func (st *State) LeadershipChecker() leadership.Checker {
 	return st.leadershipManager
 }
 
+// ServiceLeaders returns a map of service name to the name of the unit
+// holding leadership of that service. Services without a leader are
+// omitted, as are services whose leadership lease has expired.
+func (st *State) ServiceLeaders() (map[string]string, error) {
+	leaders, err := st.leadershipManager.Leaders()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return leaders, nil
+}
+
 // HackLeadership stops the state's internal leadership manager to prevent it
 // from interfering with apiserver shutdown.
 func (st *State) HackLeadership() {
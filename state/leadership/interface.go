@@ -16,6 +16,12 @@ import (
 type ManagerWorker interface {
 	leadership.Checker
 	leadership.Claimer
+
+	// Leaders returns a map of service name to the name of the unit
+	// currently holding leadership of that service. Services whose
+	// leases have expired are omitted.
+	Leaders() (map[string]string, error)
+
 	Kill()
 	Wait() error
 }
@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership
+
+import (
+	"github.com/juju/errors"
+)
+
+// leaders is used to deliver leader-listing requests to a manager's loop
+// goroutine on behalf of Leaders.
+type leaders struct {
+	response chan map[string]string
+	abort    <-chan struct{}
+}
+
+// validate returns an error if any fields are invalid or missing.
+func (l leaders) validate() error {
+	if l.response == nil {
+		return errors.New("missing response channel")
+	}
+	if l.abort == nil {
+		return errors.New("missing abort channel")
+	}
+	return nil
+}
+
+// invoke sends the request on the supplied channel, and waits for the map
+// of service names to leader unit names to be sent back.
+func (l leaders) invoke(ch chan<- leaders) (map[string]string, error) {
+	if err := l.validate(); err != nil {
+		return nil, errors.Annotatef(err, "cannot list leaders")
+	}
+	for {
+		select {
+		case <-l.abort:
+			return nil, errStopped
+		case ch <- l:
+			ch = nil
+		case result := <-l.response:
+			return result, nil
+		}
+	}
+}
+
+// respond sends the supplied leaders back to the originating invoke.
+func (l leaders) respond(result map[string]string) {
+	select {
+	case <-l.abort:
+	case l.response <- result:
+	}
+}
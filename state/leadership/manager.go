@@ -31,6 +31,7 @@ func NewManager(config ManagerConfig) (ManagerWorker, error) {
 		claims: make(chan claim),
 		checks: make(chan check),
 		blocks: make(chan block),
+		reads:  make(chan leaders),
 	}
 	go func() {
 		defer manager.tomb.Done()
@@ -56,6 +57,9 @@ type manager struct {
 
 	// blocks is used to deliver leaderlessness block requests to the loop.
 	blocks chan block
+
+	// reads is used to deliver leader-listing requests to the loop.
+	reads chan leaders
 }
 
 // Kill is part of the worker.Worker interface.
@@ -110,6 +114,9 @@ func (manager *manager) choose(blocks blocks) error {
 	case block := <-manager.blocks:
 		blocks.add(block)
 		return nil
+	case read := <-manager.reads:
+		manager.handleLeaders(read)
+		return nil
 	}
 }
 
@@ -198,6 +205,29 @@ func (manager *manager) BlockUntilLeadershipReleased(serviceName string) error {
 	}.invoke(manager.blocks)
 }
 
+// Leaders is part of the ManagerWorker interface.
+func (manager *manager) Leaders() (map[string]string, error) {
+	return leaders{
+		response: make(chan map[string]string),
+		abort:    manager.tomb.Dying(),
+	}.invoke(manager.reads)
+}
+
+// handleLeaders responds to the supplied request with the holders of all
+// leases known to the client that have not yet expired; expired leases
+// that have not yet been cleaned up by expire are not reported.
+func (manager *manager) handleLeaders(read leaders) {
+	now := manager.config.Clock.Now()
+	result := make(map[string]string)
+	for serviceName, info := range manager.config.Client.Leases() {
+		if info.Expiry.Before(now) {
+			continue
+		}
+		result[serviceName] = info.Holder
+	}
+	read.respond(result)
+}
+
 // nextExpiry returns a channel that will send a value at some point when we
 // expect at least one lease to be ready to expire. If no leases are known,
 // it will return nil.
@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/leadership"
+	"github.com/juju/juju/state/lease"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type LeadersSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&LeadersSuite{})
+
+func (s *LeadersSuite) TestLeaders(c *gc.C) {
+	fix := &Fixture{
+		leases: map[string]lease.Info{
+			"redis": lease.Info{
+				Holder: "redis/0",
+				Expiry: offset(time.Second),
+			},
+			"store": lease.Info{
+				Holder: "store/3",
+				Expiry: offset(time.Minute),
+			},
+		},
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		leaders, err := manager.Leaders()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(leaders, jc.DeepEquals, map[string]string{
+			"redis": "redis/0",
+			"store": "store/3",
+		})
+	})
+}
+
+func (s *LeadersSuite) TestLeadersOmitsExpired(c *gc.C) {
+	fix := &Fixture{
+		leases: map[string]lease.Info{
+			"redis": lease.Info{
+				Holder: "redis/0",
+				Expiry: offset(-time.Second),
+			},
+			"store": lease.Info{
+				Holder: "store/3",
+				Expiry: offset(time.Minute),
+			},
+		},
+		expectCalls: []call{{
+			method: "ExpireLease",
+			args:   []interface{}{"redis"},
+			callback: func(leases map[string]lease.Info) {
+				delete(leases, "redis")
+			},
+		}},
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		// Whether or not the manager has got round to expiring the
+		// lease yet, it must not be reported.
+		leaders, err := manager.Leaders()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(leaders, jc.DeepEquals, map[string]string{
+			"store": "store/3",
+		})
+	})
+}
+
+func (s *LeadersSuite) TestLeadersStopped(c *gc.C) {
+	fix := &Fixture{}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		manager.Kill()
+		c.Check(manager.Wait(), jc.ErrorIsNil)
+
+		leaders, err := manager.Leaders()
+		c.Check(err, gc.ErrorMatches, "leadership manager stopped")
+		c.Check(leaders, gc.IsNil)
+	})
+}
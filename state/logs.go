@@ -38,7 +38,7 @@ type LoggingState interface {
 // be called as state is opened. It is idempotent.
 func InitDbLogs(session *mgo.Session) error {
 	logsColl := session.DB(logsDB).C(logsC)
-	for _, key := range [][]string{{"e", "t"}, {"e", "n"}} {
+	for _, key := range [][]string{{"e", "t"}, {"e", "n"}, {"e", "v"}, {"v", "t"}} {
 		err := logsColl.EnsureIndex(mgo.Index{Key: key})
 		if err != nil {
 			return errors.Annotate(err, "cannot create index for logs collection")
@@ -144,6 +144,7 @@ type LogTailerParams struct {
 	ExcludeEntity []string
 	IncludeModule []string
 	ExcludeModule []string
+	MessageRegexp string
 	Oplog         *mgo.Collection // For testing only
 }
 
@@ -337,6 +338,9 @@ func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bso
 		sel = append(sel,
 			bson.DocElem{"m", bson.M{"$not": bson.RegEx{Pattern: makeModulePattern(params.ExcludeModule)}}})
 	}
+	if params.MessageRegexp != "" {
+		sel = append(sel, bson.DocElem{"x", bson.RegEx{Pattern: params.MessageRegexp}})
+	}
 
 	if prefix != "" {
 		for i, elem := range sel {
@@ -505,6 +509,85 @@ func PruneLogs(st LoggingState, minLogTime time.Time, maxLogsMB int) error {
 	return nil
 }
 
+// LogRetention describes how long log records of a particular level
+// are kept for. A zero MaxAge or MaxLogsMB means that records of the
+// level are not limited in that way.
+type LogRetention struct {
+	MaxAge    time.Duration
+	MaxLogsMB int
+}
+
+// PruneLogsByLevel removes log records according to the retention
+// policy for their level, across all environments. Records with a
+// level that has no policy are left alone; they are still subject to
+// PruneLogs.
+func PruneLogsByLevel(st LoggingState, now time.Time, retention map[loggo.Level]LogRetention) error {
+	session, logsColl := initLogsSession(st)
+	defer session.Close()
+
+	for level, policy := range retention {
+		removed := 0
+		if policy.MaxAge > 0 {
+			removeInfo, err := logsColl.RemoveAll(bson.M{
+				"v": level,
+				"t": bson.M{"$lt": now.Add(-policy.MaxAge)},
+			})
+			if err != nil {
+				return errors.Annotatef(err, "failed to prune %s logs by time", level)
+			}
+			removed += removeInfo.Removed
+		}
+		if policy.MaxLogsMB > 0 {
+			count, err := pruneLevelBySize(logsColl, level, policy.MaxLogsMB)
+			if err != nil {
+				return errors.Annotatef(err, "failed to prune %s logs by size", level)
+			}
+			removed += count
+		}
+		if removed > 0 {
+			logger.Debugf("pruned %d %s logs", removed, level)
+		}
+	}
+	return nil
+}
+
+// pruneLevelBySize removes the oldest log records of the given level
+// until the space they use is estimated to be no more than maxLogsMB.
+// It returns the number of records removed.
+func pruneLevelBySize(logsColl *mgo.Collection, level loggo.Level, maxLogsMB int) (int, error) {
+	avgSize, err := getAverageDocSize(logsColl)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if avgSize <= 0 {
+		return 0, nil
+	}
+	count, err := logsColl.Find(bson.M{"v": level}).Count()
+	if err != nil {
+		return 0, errors.Annotate(err, "failed to get log count")
+	}
+	maxCount := int(float64(maxLogsMB) * humanize.MiByte / avgSize)
+	toRemove := count - maxCount
+	if toRemove <= 0 {
+		return 0, nil
+	}
+
+	// Find the timestamp of the oldest record to keep.
+	var doc bson.M
+	err = logsColl.Find(bson.M{"v": level}).Sort("t").Skip(toRemove).Select(bson.M{"t": 1}).One(&doc)
+	sel := bson.M{"v": level}
+	if err == nil {
+		sel["t"] = bson.M{"$lt": doc["t"].(time.Time)}
+	} else if err != mgo.ErrNotFound {
+		return 0, errors.Annotate(err, "log pruning timestamp query failed")
+	}
+	removeInfo, err := logsColl.RemoveAll(sel)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return removeInfo.Removed, nil
+}
+
 // initLogsSession creates a new session suitable for logging updates,
 // returning the session and a logs mgo.Collection connected to that
 // session.
@@ -534,6 +617,26 @@ func getCollectionMB(coll *mgo.Collection) (int, error) {
 	return result["size"].(int), nil
 }
 
+// getAverageDocSize returns the average size (in bytes) of the
+// documents in a MongoDB collection.
+func getAverageDocSize(coll *mgo.Collection) (float64, error) {
+	var result bson.M
+	err := coll.Database.Run(bson.D{{"collStats", coll.Name}}, &result)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	switch size := result["avgObjSize"].(type) {
+	case int:
+		return float64(size), nil
+	case int64:
+		return float64(size), nil
+	case float64:
+		return size, nil
+	}
+	// An empty collection has no average size.
+	return 0, nil
+}
+
 // getEnvsInLogs returns the unique environment UUIDs that exist in
 // the logs collection. This uses the one of the indexes on the
 // collection and should be fast.
@@ -45,6 +45,8 @@ func (s *LogsSuite) TestIndexesCreated(c *gc.C) {
 		"_id", // default index
 		"e-t", // env-uuid and timestamp
 		"e-n", // env-uuid and entity
+		"e-v", // env-uuid and level
+		"v-t", // level and timestamp
 	})
 }
 
@@ -154,6 +156,71 @@ func (s *LogsSuite) TestPruneLogsBySize(c *gc.C) {
 	assertLatestTs(s2)
 }
 
+func (s *LogsSuite) TestPruneLogsByLevelTime(c *gc.C) {
+	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("22"))
+	defer dbLogger.Close()
+	log := func(t time.Time, level loggo.Level, msg string) {
+		err := dbLogger.Log(t, "module", "loc", level, msg)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	now := time.Now()
+	log(now, loggo.DEBUG, "keep")
+	log(now.Add(-30*time.Minute), loggo.DEBUG, "keep")
+	log(now.Add(-2*time.Hour), loggo.DEBUG, "prune")
+	log(now.Add(-2*time.Hour), loggo.INFO, "keep")
+	log(now.Add(-48*time.Hour), loggo.ERROR, "keep")
+
+	err := state.PruneLogsByLevel(s.State, now, map[loggo.Level]state.LogRetention{
+		loggo.DEBUG: {MaxAge: time.Hour},
+		loggo.ERROR: {MaxAge: 72 * time.Hour},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var docs []bson.M
+	err = s.logsColl.Find(nil).All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 4)
+	for _, doc := range docs {
+		c.Assert(doc["x"], gc.Equals, "keep")
+	}
+}
+
+func (s *LogsSuite) TestPruneLogsByLevelSize(c *gc.C) {
+	now := time.Now().Truncate(time.Millisecond)
+	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("0"))
+	defer dbLogger.Close()
+	for i := 0; i < 10000; i++ {
+		ts := now.Add(-time.Duration(i) * time.Second)
+		err := dbLogger.Log(ts, "module", "loc", loggo.DEBUG, "message")
+		c.Assert(err, jc.ErrorIsNil)
+		if i < 100 {
+			err := dbLogger.Log(ts, "module", "loc", loggo.WARNING, "message")
+			c.Assert(err, jc.ErrorIsNil)
+		}
+	}
+
+	err := state.PruneLogsByLevel(s.State, now, map[loggo.Level]state.LogRetention{
+		loggo.DEBUG:   {MaxLogsMB: 1},
+		loggo.WARNING: {MaxLogsMB: 1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	countLevel := func(level loggo.Level) int {
+		count, err := s.logsColl.Find(bson.M{"v": level}).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		return count
+	}
+	c.Assert(countLevel(loggo.DEBUG), jc.LessThan, 10000)
+	c.Assert(countLevel(loggo.WARNING), gc.Equals, 100)
+
+	// The latest records are kept.
+	var doc bson.M
+	err = s.logsColl.Find(bson.M{"v": loggo.DEBUG}).Sort("-t").One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc["t"].(time.Time), gc.Equals, now)
+}
+
 func (s *LogsSuite) generateLogs(c *gc.C, st *state.State, endTime time.Time, count int) {
 	dbLogger := state.NewDbLogger(st, names.NewMachineTag("0"))
 	defer dbLogger.Close()
@@ -473,6 +540,25 @@ func (s *LogTailerSuite) TestIncludeExcludeModule(c *gc.C) {
 	s.checkLogTailerFiltering(params, writeLogs, assert)
 }
 
+func (s *LogTailerSuite) TestMessageRegexp(c *gc.C) {
+	started := logTemplate{Message: "starting worker: uniter"}
+	failed := logTemplate{Message: "worker uniter failed: connection refused"}
+	other := logTemplate{Message: "hook completed"}
+	writeLogs := func() {
+		s.writeLogs(c, 1, started)
+		s.writeLogs(c, 1, other)
+		s.writeLogs(c, 1, failed)
+	}
+	params := &state.LogTailerParams{
+		MessageRegexp: "uniter( failed)?:",
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 1, started)
+		s.assertTailer(c, tailer, 1, failed)
+	}
+	s.checkLogTailerFiltering(params, writeLogs, assert)
+}
+
 func (s *LogTailerSuite) checkLogTailerFiltering(
 	params *state.LogTailerParams,
 	writeLogs func(),
@@ -181,6 +181,18 @@ func (m *Machine) globalKey() string {
 	return machineGlobalKey(m.doc.Id)
 }
 
+// machineGlobalInstanceKey returns the global database key for the
+// provider instance of the identified machine.
+func machineGlobalInstanceKey(id string) string {
+	return machineGlobalKey(id) + "#instance"
+}
+
+// globalInstanceKey returns the global database key for the
+// machine's provider instance.
+func (m *Machine) globalInstanceKey() string {
+	return machineGlobalInstanceKey(m.doc.Id)
+}
+
 // instanceData holds attributes relevant to a provisioned machine.
 type instanceData struct {
 	DocID      string      `bson:"_id"`
@@ -834,10 +846,12 @@ func (m *Machine) Remove() (err error) {
 			Remove: true,
 		},
 		removeStatusOp(m.st, m.globalKey()),
+		removeStatusOp(m.st, m.globalInstanceKey()),
 		removeConstraintsOp(m.st, m.globalKey()),
 		removeRequestedNetworksOp(m.st, m.globalKey()),
 		annotationRemoveOp(m.st, m.globalKey()),
 		removeRebootDocOp(m.st, m.globalKey()),
+		removeSSHHostKeyOp(m.st, m.globalKey()),
 		removeMachineBlockDevicesOp(m.Id()),
 	}
 	ifacesOps, err := m.removeNetworkInterfacesOps()
@@ -954,36 +968,73 @@ func (m *Machine) InstanceId() (instance.Id, error) {
 
 // InstanceStatus returns the provider specific instance status for this machine,
 // or a NotProvisionedError if instance is not yet provisioned.
-func (m *Machine) InstanceStatus() (string, error) {
+func (m *Machine) InstanceStatus() (StatusInfo, error) {
 	instData, err := getInstanceData(m.st, m.Id())
 	if errors.IsNotFound(err) {
 		err = errors.NotProvisionedf("machine %v", m.Id())
 	}
 	if err != nil {
-		return "", err
+		return StatusInfo{}, err
 	}
-	return instData.Status, err
+	statusInfo, err := getStatus(m.st, m.globalInstanceKey(), "instance")
+	if errors.IsNotFound(err) {
+		// Older versions of juju recorded just the status string
+		// alongside the instance data.
+		return StatusInfo{Status: Status(instData.Status)}, nil
+	}
+	return statusInfo, err
 }
 
-// SetInstanceStatus sets the provider specific instance status for a machine.
-func (m *Machine) SetInstanceStatus(status string) (err error) {
+// SetInstanceStatus sets the provider specific instance status for a
+// machine, along with an optional message elaborating upon it.
+func (m *Machine) SetInstanceStatus(status, message string) (err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot set instance status for machine %q", m)
 
-	ops := []txn.Op{
-		{
+	globalKey := m.globalInstanceKey()
+	doc := statusDoc{
+		Status:     Status(status),
+		StatusInfo: message,
+		Updated:    time.Now().UnixNano(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		ops := []txn.Op{{
 			C:      instanceDataC,
 			Id:     m.doc.DocID,
 			Assert: txn.DocExists,
-			Update: bson.D{{"$set", bson.D{{"status", status}}}},
-		},
+		}}
+		if attempt > 0 {
+			if _, err := getInstanceData(m.st, m.Id()); errors.IsNotFound(err) {
+				return nil, errors.NotProvisionedf("machine %v", m.Id())
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		txnRevno, err := m.st.readTxnRevno(statusesC, globalKey)
+		if errors.Cause(err) == mgo.ErrNotFound {
+			// The instance status is recorded when it is first
+			// reported by the provider.
+			return append(ops, createStatusOp(m.st, globalKey, doc)), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      statusesC,
+			Id:     globalKey,
+			Assert: bson.D{{"txn-revno", txnRevno}},
+			Update: bson.D{{"$set", &doc}},
+		}), nil
+	}
+	if err := m.st.run(buildTxn); err != nil {
+		return errors.Trace(err)
 	}
+	probablyUpdateStatusHistory(m.st, globalKey, doc)
+	return nil
+}
 
-	if err = m.st.runTransaction(ops); err == nil {
-		return nil
-	} else if err != txn.ErrAborted {
-		return err
-	}
-	return errors.NotProvisionedf("machine %v", m.Id())
+// InstanceStatusHistory returns a slice of at most size StatusInfo
+// items representing past instance statuses for this machine.
+func (m *Machine) InstanceStatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(m.st, m.globalInstanceKey(), size)
 }
 
 // AvailabilityZone returns the provier-specific instance availability
@@ -1087,6 +1138,77 @@ func (m *Machine) SetProvisioned(id instance.Id, nonce string, characteristics *
 	return fmt.Errorf("already set")
 }
 
+// ClearProvisioned removes the record of the machine's instance, which
+// must have the given id, along with the nonce and addresses that came
+// with it, so that the provisioner will start a new instance for the
+// machine. It is intended for recovering machines whose instance failed;
+// the caller is responsible for stopping the old instance. A machine with
+// storage attached to its instance cannot be cleared, since the
+// attachments would refer to the old instance.
+func (m *Machine) ClearProvisioned(id instance.Id) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot clear instance data for machine %q", m)
+
+	volumeAttachments, err := m.st.MachineVolumeAttachments(m.MachineTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, a := range volumeAttachments {
+		if _, err := a.Info(); err == nil {
+			return errors.Errorf("volume %s is attached", a.Volume().Id())
+		} else if !errors.IsNotProvisioned(err) {
+			return errors.Trace(err)
+		}
+	}
+	filesystemAttachments, err := m.st.MachineFilesystemAttachments(m.MachineTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, a := range filesystemAttachments {
+		if _, err := a.Info(); err == nil {
+			return errors.Errorf("filesystem %s is attached", a.Filesystem().Id())
+		} else if !errors.IsNotProvisioned(err) {
+			return errors.Trace(err)
+		}
+	}
+
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{
+			{"$set", bson.D{
+				{"nonce", ""},
+				{"addresses", []address{}},
+				{"machineaddresses", []address{}},
+			}},
+			{"$unset", bson.D{
+				{"preferredpublicaddress", nil},
+				{"preferredprivateaddress", nil},
+			}},
+		},
+	}, {
+		C:      instanceDataC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"instanceid", id}},
+		Remove: true,
+	}}
+	if err = m.st.runTransaction(ops); err == nil {
+		m.doc.Nonce = ""
+		m.doc.Addresses = nil
+		m.doc.MachineAddresses = nil
+		m.doc.PreferredPublicAddress = address{}
+		m.doc.PreferredPrivateAddress = address{}
+		return nil
+	} else if err != txn.ErrAborted {
+		return err
+	} else if alive, err := isAlive(m.st, machinesC, m.doc.DocID); err != nil {
+		return err
+	} else if !alive {
+		return errNotAlive
+	}
+	return errors.Errorf("machine is not provisioned as instance %q", id)
+}
+
 // SetInstanceInfo is used to provision a machine and in one steps set
 // it's instance id, nonce, hardware characteristics, add networks and
 // network interfaces as needed.
@@ -1236,7 +1358,7 @@ func (m *Machine) setPreferredAddressOps(netAddr network.Address, isPublic bool)
 	return ops
 }
 
-func (m *Machine) setPublicAddressOps(addresses []network.Address) ([]txn.Op, network.Address, bool) {
+func (m *Machine) setPublicAddressOps(addresses []network.Address, selector network.AddressSelector) ([]txn.Op, network.Address, bool) {
 	publicAddress := m.doc.PreferredPublicAddress.networkAddress()
 	// Always prefer an exact match if available.
 	checkScope := func(addr network.Address) bool {
@@ -1247,6 +1369,17 @@ func (m *Machine) setPublicAddressOps(addresses []network.Address) ([]txn.Op, ne
 		addr, _ := network.SelectPublicAddress(addresses)
 		return addr
 	}
+	if selector.Policy != network.DefaultAddressPolicy {
+		// The policy decides which address is best, so
+		// only the address it selects is good enough.
+		selected, _ := selector.SelectPublicAddress(addresses)
+		checkScope = func(addr network.Address) bool {
+			return addr.Value == selected.Value
+		}
+		getAddr = func() network.Address {
+			return selected
+		}
+	}
 
 	newAddr, changed := maybeGetNewAddress(publicAddress, addresses, getAddr, checkScope)
 	if !changed {
@@ -1258,7 +1391,7 @@ func (m *Machine) setPublicAddressOps(addresses []network.Address) ([]txn.Op, ne
 	return ops, newAddr, true
 }
 
-func (m *Machine) setPrivateAddressOps(addresses []network.Address) ([]txn.Op, network.Address, bool) {
+func (m *Machine) setPrivateAddressOps(addresses []network.Address, selector network.AddressSelector) ([]txn.Op, network.Address, bool) {
 	privateAddress := m.doc.PreferredPrivateAddress.networkAddress()
 	// Always prefer an exact match if available.
 	checkScope := func(addr network.Address) bool {
@@ -1269,6 +1402,15 @@ func (m *Machine) setPrivateAddressOps(addresses []network.Address) ([]txn.Op, n
 		addr, _ := network.SelectInternalAddress(addresses, false)
 		return addr
 	}
+	if selector.Policy != network.DefaultAddressPolicy {
+		selected, _ := selector.SelectInternalAddress(addresses, false)
+		checkScope = func(addr network.Address) bool {
+			return addr.Value == selected.Value
+		}
+		getAddr = func() network.Address {
+			return selected
+		}
+	}
 
 	newAddr, changed := maybeGetNewAddress(privateAddress, addresses, getAddr, checkScope)
 	if !changed {
@@ -1369,6 +1511,10 @@ func (m *Machine) setAddresses(addresses []network.Address, field *[]address, fi
 	}
 	network.SortAddresses(addressesToSet, envConfig.PreferIPv6())
 	stateAddresses := fromNetworkAddresses(addressesToSet)
+	selector, err := m.st.addressSelector(envConfig.AddressSelectionPolicy())
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	var newPrivate, newPublic network.Address
 	var changedPrivate, changedPublic bool
@@ -1402,8 +1548,8 @@ func (m *Machine) setAddresses(addresses []network.Address, field *[]address, fi
 		network.SortAddresses(allAddresses, envConfig.PreferIPv6())
 
 		var setPrivateAddressOps, setPublicAddressOps []txn.Op
-		setPrivateAddressOps, newPrivate, changedPrivate = machine.setPrivateAddressOps(allAddresses)
-		setPublicAddressOps, newPublic, changedPublic = machine.setPublicAddressOps(allAddresses)
+		setPrivateAddressOps, newPrivate, changedPrivate = machine.setPrivateAddressOps(allAddresses, selector)
+		setPublicAddressOps, newPublic, changedPublic = machine.setPublicAddressOps(allAddresses, selector)
 		ops = append(ops, setPrivateAddressOps...)
 		ops = append(ops, setPublicAddressOps...)
 		return ops, nil
@@ -1632,6 +1778,12 @@ func (m *Machine) Status() (StatusInfo, error) {
 	return getStatus(m.st, m.globalKey(), "machine")
 }
 
+// StatusHistory returns a slice of at most size StatusInfo items
+// representing past statuses for this machine.
+func (m *Machine) StatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(m.st, m.globalKey(), size)
+}
+
 // SetStatus sets the status of the machine.
 func (m *Machine) SetStatus(status Status, info string, data map[string]interface{}) error {
 	switch status {
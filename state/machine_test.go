@@ -1148,12 +1148,53 @@ func (s *MachineSuite) TestMachineSetProvisionedWhenNotAlive(c *gc.C) {
 	})
 }
 
+func (s *MachineSuite) TestMachineClearProvisioned(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetProviderAddresses(network.NewAddress("10.0.0.1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.ClearProvisioned("umbrella/0")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.ProviderAddresses(), gc.HasLen, 0)
+	c.Assert(s.machine.CheckProvisioned("fake_nonce"), jc.IsFalse)
+
+	// The machine can be provisioned again.
+	err = s.machine.SetProvisioned("umbrella/1", "another_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.CheckProvisioned("another_nonce"), jc.IsTrue)
+}
+
+func (s *MachineSuite) TestMachineClearProvisionedWrongInstance(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.ClearProvisioned("umbrella/1")
+	c.Assert(err, gc.ErrorMatches, `cannot clear instance data for machine "1": machine is not provisioned as instance "umbrella/1"`)
+	instId, err := s.machine.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("umbrella/0"))
+}
+
+func (s *MachineSuite) TestMachineClearProvisionedWhenNotAlive(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	testWhenDying(c, s.machine, notAliveErr, notAliveErr, func() error {
+		return s.machine.ClearProvisioned("umbrella/0")
+	})
+}
+
 func (s *MachineSuite) TestMachineSetInstanceStatus(c *gc.C) {
 	// Machine needs to be provisioned first.
 	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
 	c.Assert(err, jc.ErrorIsNil)
 
-	err = s.machine.SetInstanceStatus("ALIVE")
+	err = s.machine.SetInstanceStatus("ALIVE", "all is well")
 	c.Assert(err, jc.ErrorIsNil)
 
 	// Reload machine and check result.
@@ -1161,12 +1202,57 @@ func (s *MachineSuite) TestMachineSetInstanceStatus(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	status, err := s.machine.InstanceStatus()
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(status, gc.DeepEquals, "ALIVE")
+	c.Assert(status.Status, gc.Equals, state.Status("ALIVE"))
+	c.Assert(status.Message, gc.Equals, "all is well")
+	c.Assert(status.Since, gc.NotNil)
+}
+
+func (s *MachineSuite) TestMachineInstanceStatusUnset(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.machine.InstanceStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, state.StatusInfo{})
+}
+
+func (s *MachineSuite) TestMachineInstanceStatusHistory(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetInstanceStatus("allocating", "waiting for deployment")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetInstanceStatus("running", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.machine.InstanceStatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Check(history[0].Status, gc.Equals, state.Status("running"))
+	c.Check(history[0].Message, gc.Equals, "")
+	c.Check(history[1].Status, gc.Equals, state.Status("allocating"))
+	c.Check(history[1].Message, gc.Equals, "waiting for deployment")
+
+	history, err = s.machine.InstanceStatusHistory(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Status, gc.Equals, state.Status("running"))
+}
+
+func (s *MachineSuite) TestMachineStatusHistory(c *gc.C) {
+	err := s.machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.machine.StatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Check(history[0].Status, gc.Equals, state.StatusStarted)
+	c.Check(history[1].Status, gc.Equals, state.StatusPending)
 }
 
 func (s *MachineSuite) TestNotProvisionedMachineSetInstanceStatus(c *gc.C) {
-	err := s.machine.SetInstanceStatus("ALIVE")
+	err := s.machine.SetInstanceStatus("ALIVE", "")
 	c.Assert(err, gc.ErrorMatches, ".* not provisioned")
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
 }
 
 func (s *MachineSuite) TestNotProvisionedMachineInstanceStatus(c *gc.C) {
@@ -28,7 +28,7 @@ import (
 //
 // Open returns unauthorizedError if access is unauthorized.
 func Open(tag names.EnvironTag, info *mongo.MongoInfo, opts mongo.DialOpts, policy Policy) (*State, error) {
-	st, err := open(tag, info, opts, policy)
+	st, err := open(tag, info, opts, policy, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -47,7 +47,10 @@ func Open(tag names.EnvironTag, info *mongo.MongoInfo, opts mongo.DialOpts, poli
 	return st, nil
 }
 
-func open(tag names.EnvironTag, info *mongo.MongoInfo, opts mongo.DialOpts, policy Policy) (*State, error) {
+// open connects to mongo and returns an unstarted *State for the given
+// environment. If txnWatcher is not nil, the State shares it rather than
+// starting its own transaction log watcher.
+func open(tag names.EnvironTag, info *mongo.MongoInfo, opts mongo.DialOpts, policy Policy, txnWatcher *watcher.Shared) (*State, error) {
 	logger.Infof("opening state, mongo addresses: %q; entity %v", info.Addrs, info.Tag)
 	logger.Debugf("dialing mongo")
 	session, err := mongo.DialWithInfo(info.Info, opts)
@@ -77,7 +80,7 @@ func open(tag names.EnvironTag, info *mongo.MongoInfo, opts mongo.DialOpts, poli
 		tag = ssInfo.EnvironmentTag
 	}
 
-	st, err := newState(tag, session, info, policy)
+	st, err := newState(tag, session, info, policy, txnWatcher)
 	if err != nil {
 		session.Close()
 		return nil, errors.Trace(err)
@@ -109,7 +112,7 @@ func Initialize(owner names.UserTag, info *mongo.MongoInfo, cfg *config.Config,
 		return nil, errors.Errorf("environment uuid was not supplied")
 	}
 	envTag := names.NewEnvironTag(uuid)
-	st, err := open(envTag, info, opts, policy)
+	st, err := open(envTag, info, opts, policy, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -186,7 +189,7 @@ func (st *State) envSetupOps(cfg *config.Config, envUUID, serverUUID string, own
 	if serverUUID == "" {
 		serverUUID = envUUID
 	}
-	envUserOp := createEnvUserOp(envUUID, owner, owner, owner.Name())
+	envUserOp := createEnvUserOp(envUUID, owner, owner, owner.Name(), EnvAdminAccess)
 	ops := []txn.Op{
 		createConstraintsOp(st, environGlobalKey, constraints.Value{}),
 		createSettingsOp(environGlobalKey, cfg.AllAttrs()),
@@ -230,8 +233,9 @@ func isUnauthorized(err error) bool {
 
 // newState creates an incomplete *State, with a configured watcher but no
 // pwatcher, leadershipManager, or serverTag. You must start() the returned
-// *State before it will function correctly.
-func newState(environTag names.EnvironTag, session *mgo.Session, mongoInfo *mongo.MongoInfo, policy Policy) (_ *State, resultErr error) {
+// *State before it will function correctly. The watcher is a new view of
+// txnWatcher if that is not nil.
+func newState(environTag names.EnvironTag, session *mgo.Session, mongoInfo *mongo.MongoInfo, policy Policy, txnWatcher *watcher.Shared) (_ *State, resultErr error) {
 	// Set up database.
 	rawDB := session.DB(jujuDB)
 	database, err := allCollections().Load(rawDB, environTag.Id())
@@ -242,6 +246,12 @@ func newState(environTag names.EnvironTag, session *mgo.Session, mongoInfo *mong
 		return nil, errors.Trace(err)
 	}
 
+	if txnWatcher != nil {
+		txnWatcher = txnWatcher.Share(rawDB.C(txnLogC))
+	} else {
+		txnWatcher = watcher.NewShared(rawDB.C(txnLogC))
+	}
+
 	// Create State.
 	return &State{
 		environTag: environTag,
@@ -249,7 +259,7 @@ func newState(environTag names.EnvironTag, session *mgo.Session, mongoInfo *mong
 		session:    session,
 		database:   database,
 		policy:     policy,
-		watcher:    watcher.New(rawDB.C(txnLogC)),
+		watcher:    txnWatcher,
 	}, nil
 }
 
@@ -133,6 +133,12 @@ func (st *State) resolveConstraints(cons constraints.Value) (constraints.Value,
 	if err != nil {
 		return constraints.Value{}, err
 	}
+	if envCons, err = st.expandConstraintProfile(validator, envCons); err != nil {
+		return constraints.Value{}, err
+	}
+	if cons, err = st.expandConstraintProfile(validator, cons); err != nil {
+		return constraints.Value{}, err
+	}
 	return validator.Merge(envCons, cons)
 }
 
@@ -143,9 +149,36 @@ func (st *State) validateConstraints(cons constraints.Value) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cons, err = st.expandConstraintProfile(validator, cons); err != nil {
+		return nil, err
+	}
 	return validator.Validate(cons)
 }
 
+// expandConstraintProfile returns the given constraints with any
+// constraint profile they name replaced by the profile's constraints,
+// as defined in the environment config. Constraints set explicitly
+// take precedence over those of the profile.
+func (st *State) expandConstraintProfile(validator constraints.Validator, cons constraints.Value) (constraints.Value, error) {
+	if cons.Profile == nil {
+		return cons, nil
+	}
+	name := *cons.Profile
+	cons.Profile = nil
+	if name == "" {
+		return cons, nil
+	}
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return constraints.Value{}, errors.Trace(err)
+	}
+	profileCons, err := cfg.ConstraintProfile(name)
+	if err != nil {
+		return constraints.Value{}, errors.Trace(err)
+	}
+	return validator.Merge(profileCons, cons)
+}
+
 // validate calls the state's assigned policy, if non-nil, to obtain
 // a ConfigValidator, and calls Validate if a non-nil ConfigValidator is
 // returned.
@@ -391,6 +391,12 @@ func (m *Machine) AllPorts() ([]*Ports, error) {
 	return results, nil
 }
 
+// WatchOpenedPorts returns a watcher that notifies of changes to the
+// port ranges opened on this machine on the given network.
+func (m *Machine) WatchOpenedPorts(networkName string) NotifyWatcher {
+	return newEntityWatcher(m.st, openedPortsC, m.st.docID(portsGlobalKey(m.Id(), networkName)))
+}
+
 // addPortsDocOps returns the ops for adding a number of port ranges
 // to a new ports document. portsAssert allows specifying an assert
 // statement for on the openedPorts collection op.
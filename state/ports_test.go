@@ -342,6 +342,42 @@ func (s *PortsDocSuite) TestRemovePortsDoc(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `ports for machine "0", network "juju-public" not found`)
 }
 
+func (s *PortsDocSuite) TestWatchMachineOpenedPorts(c *gc.C) {
+	w := s.machine.WatchOpenedPorts(network.DefaultPublic)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	portRange := state.PortRange{
+		FromPort: 100,
+		ToPort:   200,
+		UnitName: s.unit1.Name(),
+		Protocol: "TCP",
+	}
+	err := s.ports.OpenPorts(portRange)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Ports opened on another machine are not reported.
+	f := factory.NewFactory(s.State)
+	machine := f.MakeMachine(c, &factory.MachineParams{Series: "quantal"})
+	unit := f.MakeUnit(c, &factory.UnitParams{Service: s.service, Machine: machine})
+	ports, err := state.GetOrCreatePorts(s.State, machine.Id(), network.DefaultPublic)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ports.OpenPorts(state.PortRange{
+		FromPort: 100,
+		ToPort:   200,
+		UnitName: unit.Name(),
+		Protocol: "TCP",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = s.ports.ClosePorts(portRange)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func (s *PortsDocSuite) TestWatchPorts(c *gc.C) {
 	// No port ranges open initially, no changes.
 	w := s.State.WatchOpenedPorts()
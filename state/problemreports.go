@@ -0,0 +1,220 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ProblemReportKind identifies the kind of failure described by a
+// problem report.
+type ProblemReportKind string
+
+const (
+	// WorkerCrashLoop reports a worker that keeps failing shortly
+	// after it is restarted.
+	WorkerCrashLoop ProblemReportKind = "worker-crash-loop"
+
+	// HookFailure reports a charm hook that failed.
+	HookFailure ProblemReportKind = "hook-failed"
+)
+
+// Validate returns an error if the kind is not known.
+func (k ProblemReportKind) Validate() error {
+	switch k {
+	case WorkerCrashLoop, HookFailure:
+		return nil
+	}
+	return errors.NotValidf("problem report kind %q", string(k))
+}
+
+var (
+	// maxProblemReports is the number of problem reports kept for
+	// each environment; older reports are removed as new ones arrive.
+	maxProblemReports = 100
+
+	// maxProblemReportLogLines is the number of log lines kept with
+	// each problem report.
+	maxProblemReportLogLines = 200
+)
+
+// ProblemReportInfo holds the details of a problem reported by an
+// agent.
+type ProblemReportInfo struct {
+	// Entity identifies the agent that reported the problem.
+	Entity names.Tag
+
+	// Kind is the kind of failure.
+	Kind ProblemReportKind
+
+	// Source names the worker or hook that failed.
+	Source string
+
+	// Message describes the failure.
+	Message string
+
+	// Stack holds the error stack of the failure, if known.
+	Stack string
+
+	// Logs holds the agent's log lines leading up to the failure.
+	Logs []string
+
+	// State holds additional details of the failed worker's state.
+	State map[string]string
+
+	// Time is when the problem occurred.
+	Time time.Time
+}
+
+// ProblemReport is a problem report recorded in state.
+type ProblemReport struct {
+	// Id uniquely identifies the report within the environment.
+	Id string
+
+	ProblemReportInfo
+}
+
+// problemReportDoc records a problem reported by an agent.
+type problemReportDoc struct {
+	DocID   string            `bson:"_id"`
+	EnvUUID string            `bson:"env-uuid"`
+	Seq     int               `bson:"seq"`
+	Entity  string            `bson:"entity"`
+	Kind    ProblemReportKind `bson:"kind"`
+	Source  string            `bson:"source"`
+	Message string            `bson:"message"`
+	Stack   string            `bson:"stack,omitempty"`
+	Logs    []string          `bson:"logs,omitempty"`
+	State   map[string]string `bson:"state,omitempty"`
+	Time    time.Time         `bson:"time"`
+}
+
+func (doc *problemReportDoc) report() (ProblemReport, error) {
+	entity, err := names.ParseTag(doc.Entity)
+	if err != nil {
+		return ProblemReport{}, errors.Trace(err)
+	}
+	return ProblemReport{
+		Id: strconv.Itoa(doc.Seq),
+		ProblemReportInfo: ProblemReportInfo{
+			Entity:  entity,
+			Kind:    doc.Kind,
+			Source:  doc.Source,
+			Message: doc.Message,
+			Stack:   doc.Stack,
+			Logs:    doc.Logs,
+			State:   doc.State,
+			Time:    doc.Time.UTC(),
+		},
+	}, nil
+}
+
+func validateProblemReport(info ProblemReportInfo) error {
+	if info.Entity == nil {
+		return errors.NotValidf("missing entity")
+	}
+	if err := info.Kind.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if info.Source == "" {
+		return errors.NotValidf("missing source")
+	}
+	for key := range info.State {
+		if strings.HasPrefix(key, "$") || strings.Contains(key, ".") {
+			return errors.NotValidf("state key %q", key)
+		}
+	}
+	return nil
+}
+
+// AddProblemReport records a problem reported by an agent. Only the
+// newest reports in the environment are kept.
+func (st *State) AddProblemReport(info ProblemReportInfo) (_ ProblemReport, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add problem report")
+	if err := validateProblemReport(info); err != nil {
+		return ProblemReport{}, errors.Trace(err)
+	}
+	if info.Time.IsZero() {
+		info.Time = time.Now()
+	}
+	logs := info.Logs
+	if len(logs) > maxProblemReportLogLines {
+		logs = logs[len(logs)-maxProblemReportLogLines:]
+	}
+	seq, err := st.sequence("problemreport")
+	if err != nil {
+		return ProblemReport{}, errors.Trace(err)
+	}
+	doc := problemReportDoc{
+		DocID:   st.docID(strconv.Itoa(seq)),
+		EnvUUID: st.EnvironUUID(),
+		Seq:     seq,
+		Entity:  info.Entity.String(),
+		Kind:    info.Kind,
+		Source:  info.Source,
+		Message: info.Message,
+		Stack:   info.Stack,
+		Logs:    logs,
+		State:   info.State,
+		Time:    info.Time.UTC(),
+	}
+	reports, closer := st.getCollection(problemReportsC)
+	defer closer()
+	reportsW := reports.Writeable()
+	if err := reportsW.Insert(&doc); err != nil {
+		return ProblemReport{}, errors.Trace(err)
+	}
+	if oldest := seq - maxProblemReports; oldest >= 0 {
+		// Failing to prune is not fatal; the next report will
+		// try again.
+		_, err := reportsW.RemoveAll(bson.D{{"seq", bson.D{{"$lte", oldest}}}})
+		if err != nil {
+			logger.Errorf("cannot prune problem reports: %v", err)
+		}
+	}
+	return doc.report()
+}
+
+// ProblemReports returns the problem reports recorded for the
+// environment, newest first.
+func (st *State) ProblemReports() ([]ProblemReport, error) {
+	reports, closer := st.getCollection(problemReportsC)
+	defer closer()
+
+	var docs []problemReportDoc
+	if err := reports.Find(nil).Sort("-seq").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get problem reports")
+	}
+	results := make([]ProblemReport, len(docs))
+	for i, doc := range docs {
+		report, err := doc.report()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get problem report %d", doc.Seq)
+		}
+		results[i] = report
+	}
+	return results, nil
+}
+
+// ProblemReport returns the problem report with the given id.
+func (st *State) ProblemReport(id string) (ProblemReport, error) {
+	reports, closer := st.getCollection(problemReportsC)
+	defer closer()
+
+	var doc problemReportDoc
+	err := reports.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return ProblemReport{}, errors.NotFoundf("problem report %q", id)
+	} else if err != nil {
+		return ProblemReport{}, errors.Annotatef(err, "cannot get problem report %q", id)
+	}
+	return doc.report()
+}
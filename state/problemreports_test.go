@@ -0,0 +1,123 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ProblemReportsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ProblemReportsSuite{})
+
+func (s *ProblemReportsSuite) TestAddProblemReport(c *gc.C) {
+	when := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	info := state.ProblemReportInfo{
+		Entity:  names.NewMachineTag("0"),
+		Kind:    state.WorkerCrashLoop,
+		Source:  "firewaller",
+		Message: "worker failed 3 times",
+		Stack:   "firewaller.go:42: boom",
+		Logs:    []string{"line 1", "line 2"},
+		State:   map[string]string{"failures": "3"},
+		Time:    when,
+	}
+	report, err := s.State.AddProblemReport(info)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report, jc.DeepEquals, state.ProblemReport{Id: "0", ProblemReportInfo: info})
+
+	report, err = s.State.ProblemReport("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report, jc.DeepEquals, state.ProblemReport{Id: "0", ProblemReportInfo: info})
+}
+
+func (s *ProblemReportsSuite) TestAddProblemReportInvalid(c *gc.C) {
+	for i, test := range []struct {
+		info state.ProblemReportInfo
+		err  string
+	}{{
+		info: state.ProblemReportInfo{Kind: state.HookFailure, Source: "install"},
+		err:  "missing entity not valid",
+	}, {
+		info: state.ProblemReportInfo{Entity: names.NewUnitTag("mysql/0"), Kind: "sad", Source: "install"},
+		err:  `problem report kind "sad" not valid`,
+	}, {
+		info: state.ProblemReportInfo{Entity: names.NewUnitTag("mysql/0"), Kind: state.HookFailure},
+		err:  "missing source not valid",
+	}, {
+		info: state.ProblemReportInfo{
+			Entity: names.NewUnitTag("mysql/0"),
+			Kind:   state.HookFailure,
+			Source: "install",
+			State:  map[string]string{"a.b": "c"},
+		},
+		err: `state key "a.b" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.AddProblemReport(test.info)
+		c.Check(err, gc.ErrorMatches, "cannot add problem report: "+test.err)
+	}
+}
+
+func (s *ProblemReportsSuite) TestProblemReportNotFound(c *gc.C) {
+	_, err := s.State.ProblemReport("42")
+	c.Assert(err, gc.ErrorMatches, `problem report "42" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ProblemReportsSuite) TestProblemReportsNewestFirst(c *gc.C) {
+	for _, hook := range []string{"install", "start"} {
+		_, err := s.State.AddProblemReport(state.ProblemReportInfo{
+			Entity: names.NewUnitTag("mysql/0"),
+			Kind:   state.HookFailure,
+			Source: hook,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	reports, err := s.State.ProblemReports()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reports, gc.HasLen, 2)
+	c.Check(reports[0].Id, gc.Equals, "1")
+	c.Check(reports[0].Source, gc.Equals, "start")
+	c.Check(reports[1].Id, gc.Equals, "0")
+	c.Check(reports[1].Source, gc.Equals, "install")
+}
+
+func (s *ProblemReportsSuite) TestProblemReportsPruned(c *gc.C) {
+	s.PatchValue(state.MaxProblemReports, 3)
+	for i := 0; i < 5; i++ {
+		_, err := s.State.AddProblemReport(state.ProblemReportInfo{
+			Entity: names.NewUnitTag("mysql/0"),
+			Kind:   state.HookFailure,
+			Source: fmt.Sprintf("hook-%d", i),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	reports, err := s.State.ProblemReports()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reports, gc.HasLen, 3)
+	c.Check(reports[2].Source, gc.Equals, "hook-2")
+}
+
+func (s *ProblemReportsSuite) TestProblemReportLogsTruncated(c *gc.C) {
+	s.PatchValue(state.MaxProblemReportLogLines, 2)
+	report, err := s.State.AddProblemReport(state.ProblemReportInfo{
+		Entity: names.NewUnitTag("mysql/0"),
+		Kind:   state.HookFailure,
+		Source: "install",
+		Logs:   []string{"one", "two", "three"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Logs, jc.DeepEquals, []string{"two", "three"})
+}
@@ -0,0 +1,235 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/process"
+)
+
+// unitProcessesDoc records the workload processes tracked by a unit.
+type unitProcessesDoc struct {
+	DocID     string       `bson:"_id"`
+	EnvUUID   string       `bson:"env-uuid"`
+	Processes []processDoc `bson:"processes"`
+}
+
+// processDoc is the persistent representation of a process.Info.
+type processDoc struct {
+	Name   string                 `bson:"name"`
+	Type   string                 `bson:"type"`
+	ID     string                 `bson:"id"`
+	Status string                 `bson:"status"`
+	Extra  map[string]interface{} `bson:"extra,omitempty"`
+	Usage  *processUsageDoc       `bson:"usage,omitempty"`
+}
+
+// processUsageDoc is the persistent representation of a process.Usage.
+type processUsageDoc struct {
+	CPU      float64 `bson:"cpu"`
+	Memory   uint64  `bson:"memory"`
+	Restarts int     `bson:"restarts"`
+}
+
+func newProcessDoc(info process.Info) processDoc {
+	doc := processDoc{
+		Name:   info.Name,
+		Type:   info.Type,
+		ID:     info.Details.ID,
+		Status: info.Details.Status,
+		Extra:  info.Details.Extra,
+	}
+	if usage := info.Details.Usage; usage != nil {
+		doc.Usage = &processUsageDoc{
+			CPU:      usage.CPU,
+			Memory:   usage.Memory,
+			Restarts: usage.Restarts,
+		}
+	}
+	return doc
+}
+
+func (doc processDoc) info() process.Info {
+	info := process.Info{
+		Name: doc.Name,
+		Type: doc.Type,
+		Details: process.Details{
+			ID:     doc.ID,
+			Status: doc.Status,
+			Extra:  doc.Extra,
+		},
+	}
+	if doc.Usage != nil {
+		info.Details.Usage = &process.Usage{
+			CPU:      doc.Usage.CPU,
+			Memory:   doc.Usage.Memory,
+			Restarts: doc.Usage.Restarts,
+		}
+	}
+	return info
+}
+
+// infos returns the processes recorded in the document, in the order
+// they were set.
+func (doc *unitProcessesDoc) infos() []process.Info {
+	infos := make([]process.Info, len(doc.Processes))
+	for i, p := range doc.Processes {
+		infos[i] = p.info()
+	}
+	return infos
+}
+
+// Processes returns the workload processes tracked by the unit, in
+// the order they were last set.
+func (u *Unit) Processes() ([]process.Info, error) {
+	doc, err := u.processesDoc()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get processes for unit %q", u.Name())
+	}
+	return doc.infos(), nil
+}
+
+// UnitProcesses returns the workload processes tracked by each of the
+// named units, keyed by unit name. The processes of all the units are
+// fetched with a single query. Units that track no processes are
+// omitted from the result.
+func (st *State) UnitProcesses(unitNames []string) (map[string][]process.Info, error) {
+	coll, closer := st.getCollection(unitProcessesC)
+	defer closer()
+	ids := make([]string, len(unitNames))
+	namesByID := make(map[string]string, len(unitNames))
+	for i, name := range unitNames {
+		ids[i] = st.docID(unitGlobalKey(name))
+		namesByID[ids[i]] = name
+	}
+	var docs []unitProcessesDoc
+	if err := coll.Find(bson.D{{"_id", bson.D{{"$in", ids}}}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get unit processes")
+	}
+	result := make(map[string][]process.Info, len(docs))
+	for _, doc := range docs {
+		result[namesByID[doc.DocID]] = doc.infos()
+	}
+	return result, nil
+}
+
+// SetProcesses records the workload processes tracked by the unit,
+// replacing any previously recorded.
+func (u *Unit) SetProcesses(infos []process.Info) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set processes for unit %q", u.Name())
+	docs := make([]processDoc, len(infos))
+	seen := make(map[string]bool)
+	for i, info := range infos {
+		if err := info.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		if seen[info.Name] {
+			return errors.NotValidf("duplicate process %q", info.Name)
+		}
+		seen[info.Name] = true
+		docs[i] = newProcessDoc(info)
+	}
+	var previous map[string]string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		previous = make(map[string]string)
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.Life == Dead {
+			return nil, errors.Errorf("unit is dead")
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+		existing, err := u.processesDoc()
+		switch {
+		case errors.IsNotFound(err):
+			ops = append(ops, txn.Op{
+				C:      unitProcessesC,
+				Id:     u.st.docID(u.globalKey()),
+				Assert: txn.DocMissing,
+				Insert: &unitProcessesDoc{
+					EnvUUID:   u.st.EnvironUUID(),
+					Processes: docs,
+				},
+			})
+		case err != nil:
+			return nil, errors.Trace(err)
+		default:
+			for _, p := range existing.Processes {
+				previous[p.Name] = p.Status
+			}
+			ops = append(ops, txn.Op{
+				C:      unitProcessesC,
+				Id:     u.st.docID(u.globalKey()),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"processes", docs}}}},
+			})
+		}
+		return ops, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return err
+	}
+	now := time.Now().UnixNano()
+	for _, doc := range docs {
+		if previous[doc.Name] == doc.Status {
+			continue
+		}
+		probablyUpdateStatusHistory(u.st, processGlobalKey(u.globalKey(), doc.Name), statusDoc{
+			Status:  Status(doc.Status),
+			Updated: now,
+		})
+	}
+	return nil
+}
+
+// ProcessStatusHistory returns up to size of the most recent statuses
+// of the named workload process tracked by the unit, newest first.
+// A status is recorded each time the process's status changes.
+func (u *Unit) ProcessStatusHistory(name string, size int) ([]StatusInfo, error) {
+	return statusHistory(u.st, processGlobalKey(u.globalKey(), name), size)
+}
+
+// processGlobalKey returns the global database key for the named
+// process of the unit with the given global key.
+func processGlobalKey(unitKey, name string) string {
+	return unitKey + "#process#" + name
+}
+
+func (u *Unit) processesDoc() (*unitProcessesDoc, error) {
+	coll, closer := u.st.getCollection(unitProcessesC)
+	defer closer()
+	var doc unitProcessesDoc
+	err := coll.FindId(u.globalKey()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("processes for unit %q", u.Name())
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+// removeUnitProcessesOp returns the operation needed to remove the
+// record of the workload processes tracked by the unit with the given
+// global key, if there is one.
+func removeUnitProcessesOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      unitProcessesC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ProcessesSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&ProcessesSuite{})
+
+var (
+	webProcess = process.Info{
+		Name: "web",
+		Type: "docker",
+		Details: process.Details{
+			ID:     "abc123",
+			Status: "running",
+			Extra:  map[string]interface{}{"image": "nginx"},
+			Usage:  &process.Usage{CPU: 12.5, Memory: 1 << 20, Restarts: 2},
+		},
+	}
+	workerProcess = process.Info{
+		Name: "worker",
+		Type: "exec",
+		Details: process.Details{
+			ID:     "42",
+			Status: "running",
+			Extra:  map[string]interface{}{"pid": 42.0},
+		},
+	}
+)
+
+func (s *ProcessesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, nil)
+}
+
+func (s *ProcessesSuite) TestProcessesNoneSet(c *gc.C) {
+	infos, err := s.unit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 0)
+}
+
+func (s *ProcessesSuite) TestSetProcesses(c *gc.C) {
+	err := s.unit.SetProcesses([]process.Info{webProcess, workerProcess})
+	c.Assert(err, jc.ErrorIsNil)
+	infos, err := s.unit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{webProcess, workerProcess})
+
+	err = s.unit.SetProcesses([]process.Info{workerProcess})
+	c.Assert(err, jc.ErrorIsNil)
+	infos, err = s.unit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{workerProcess})
+
+	err = s.unit.SetProcesses(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	infos, err = s.unit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 0)
+}
+
+func (s *ProcessesSuite) TestSetProcessesInvalid(c *gc.C) {
+	invalid := webProcess
+	invalid.Details.Status = ""
+	err := s.unit.SetProcesses([]process.Info{invalid})
+	c.Assert(err, gc.ErrorMatches, `cannot set processes for unit "mysql/0": process "web" with empty status not valid`)
+
+	err = s.unit.SetProcesses([]process.Info{webProcess, webProcess})
+	c.Assert(err, gc.ErrorMatches, `cannot set processes for unit "mysql/0": duplicate process "web" not valid`)
+}
+
+func (s *ProcessesSuite) TestSetProcessesDeadUnit(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetProcesses([]process.Info{webProcess})
+	c.Assert(err, gc.ErrorMatches, `cannot set processes for unit "mysql/0": unit is dead`)
+}
+
+func (s *ProcessesSuite) TestProcessesRemovedWithUnit(c *gc.C) {
+	err := s.unit.SetProcesses([]process.Info{webProcess})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	infos, err := s.unit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 0)
+}
+
+func (s *ProcessesSuite) TestUnitProcesses(c *gc.C) {
+	err := s.unit.SetProcesses([]process.Info{webProcess, workerProcess})
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.unit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	other := s.Factory.MakeUnit(c, &factory.UnitParams{Service: service})
+	err = other.SetProcesses([]process.Info{workerProcess})
+	c.Assert(err, jc.ErrorIsNil)
+	idle := s.Factory.MakeUnit(c, &factory.UnitParams{Service: service})
+
+	processes, err := s.State.UnitProcesses([]string{s.unit.Name(), other.Name(), idle.Name()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(processes, jc.DeepEquals, map[string][]process.Info{
+		s.unit.Name(): {webProcess, workerProcess},
+		other.Name():  {workerProcess},
+	})
+
+	// Only the named units are returned.
+	processes, err = s.State.UnitProcesses([]string{other.Name()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(processes, jc.DeepEquals, map[string][]process.Info{
+		other.Name(): {workerProcess},
+	})
+}
+
+func (s *ProcessesSuite) TestProcessStatusHistory(c *gc.C) {
+	err := s.unit.SetProcesses([]process.Info{webProcess, workerProcess})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Changes that leave the status alone are not recorded.
+	web := webProcess
+	web.Details.Usage = &process.Usage{CPU: 50, Memory: 1 << 20, Restarts: 2}
+	err = s.unit.SetProcesses([]process.Info{web, workerProcess})
+	c.Assert(err, jc.ErrorIsNil)
+
+	web.Details.Status = "exited"
+	err = s.unit.SetProcesses([]process.Info{web, workerProcess})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.unit.ProcessStatusHistory("web", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, state.Status("exited"))
+	c.Assert(history[1].Status, gc.Equals, state.Status("running"))
+	c.Assert(history[0].Since.After(*history[1].Since), jc.IsTrue)
+
+	history, err = s.unit.ProcessStatusHistory("web", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, state.Status("exited"))
+
+	history, err = s.unit.ProcessStatusHistory("worker", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, state.Status("running"))
+
+	history, err = s.unit.ProcessStatusHistory("unknown", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+}
+
+func (s *ProcessesSuite) TestWatchProcesses(c *gc.C) {
+	w := s.unit.WatchProcesses()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Record processes for the first time, check one event.
+	err := s.unit.SetProcesses([]process.Info{webProcess})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Change the status of a process, check one event.
+	failed := webProcess
+	failed.Details.Status = "exited"
+	err = s.unit.SetProcesses([]process.Info{failed, workerProcess})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Processes of other units are not reported.
+	service, err := s.unit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	other := s.Factory.MakeUnit(c, &factory.UnitParams{Service: service})
+	err = other.SetProcesses([]process.Info{webProcess})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Stop, check closed.
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
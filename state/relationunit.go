@@ -40,9 +40,26 @@ func (ru *RelationUnit) Endpoint() Endpoint {
 	return ru.endpoint
 }
 
-// PrivateAddress returns the private address of the unit.
+// PrivateAddress returns the private address of the unit. If the
+// relation's endpoint is bound to a network space, an address in that
+// space is preferred.
 func (ru *RelationUnit) PrivateAddress() (network.Address, error) {
-	return ru.unit.PrivateAddress()
+	selector, ok, err := ru.st.endpointAddressSelector(ru.unit.ServiceName(), ru.endpoint.Name)
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	if !ok {
+		return ru.unit.PrivateAddress()
+	}
+	m, err := ru.unit.machine()
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	addr, ok := selector.SelectInternalAddress(m.Addresses(), false)
+	if !ok {
+		return network.Address{}, network.NoAddressf("private")
+	}
+	return addr, nil
 }
 
 // ErrCannotEnterScope indicates that a relation unit failed to enter its scope
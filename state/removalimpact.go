@@ -0,0 +1,225 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+)
+
+// RemovalImpact describes the entities that will be removed, or
+// otherwise affected, as a consequence of destroying a service or
+// some of its units. It is computed without changing anything, and
+// may be out of date as soon as it is returned.
+type RemovalImpact struct {
+	// Units holds the names of the units that will be removed,
+	// including the subordinates of any removed principal units.
+	Units []string
+
+	// Relations holds the keys of the relations that will be removed.
+	Relations []string
+
+	// Machines holds the ids of the machines that will be destroyed,
+	// because they will no longer host any units.
+	Machines []string
+
+	// EmptyMachines holds the ids of the machines that will no longer
+	// host any units, but that will not be destroyed, because they
+	// host containers or manage the environment.
+	EmptyMachines []string
+
+	// DetachedStorage holds the ids of the storage instances that will
+	// be detached from removed units, but not destroyed.
+	DetachedStorage []string
+
+	// DestroyedStorage holds the ids of the storage instances that
+	// will be destroyed along with the units or service owning them.
+	DestroyedStorage []string
+
+	// UnitErrors holds, for each requested unit that cannot be
+	// removed, the reason why. Those units do not contribute to
+	// the rest of the impact.
+	UnitErrors map[string]error
+}
+
+// ServiceRemovalImpact returns the impact of destroying the named
+// service, along with all of its units and relations.
+func (st *State) ServiceRemovalImpact(name string) (*RemovalImpact, error) {
+	svc, err := st.Service(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := svc.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relations, err := svc.Relations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	b := newRemovalImpactBuilder(st)
+	b.owners.Add(svc.Tag().String())
+	for _, unit := range units {
+		if err := b.addUnit(unit); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	for _, relation := range relations {
+		b.relations.Add(relation.String())
+	}
+	return b.impact()
+}
+
+// UnitsRemovalImpact returns the impact of destroying the named
+// principal units. Units that do not exist, or are subordinates,
+// are reported in the UnitErrors field of the result.
+func (st *State) UnitsRemovalImpact(names []string) (*RemovalImpact, error) {
+	b := newRemovalImpactBuilder(st)
+	unitErrors := make(map[string]error)
+	for _, name := range names {
+		unit, err := st.Unit(name)
+		if errors.IsNotFound(err) {
+			unitErrors[name] = err
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !unit.IsPrincipal() {
+			unitErrors[name] = errors.Errorf("unit %q is a subordinate", name)
+			continue
+		}
+		if err := b.addUnit(unit); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	impact, err := b.impact()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(unitErrors) > 0 {
+		impact.UnitErrors = unitErrors
+	}
+	return impact, nil
+}
+
+// removalImpactBuilder accumulates the entities affected by the
+// removal of a set of units.
+type removalImpactBuilder struct {
+	st        *State
+	units     set.Strings
+	relations set.Strings
+	// owners holds the tags of the removed entities that
+	// may own storage instances.
+	owners set.Strings
+	// machines holds the ids of the machines hosting
+	// removed principal units.
+	machines set.Strings
+	// storage holds the storage instances attached
+	// to removed units.
+	storage []StorageInstance
+}
+
+func newRemovalImpactBuilder(st *State) *removalImpactBuilder {
+	return &removalImpactBuilder{
+		st:        st,
+		units:     make(set.Strings),
+		relations: make(set.Strings),
+		owners:    make(set.Strings),
+		machines:  make(set.Strings),
+	}
+}
+
+// addUnit records the removal of the unit, its subordinates, and
+// its storage attachments.
+func (b *removalImpactBuilder) addUnit(unit *Unit) error {
+	if b.units.Contains(unit.Name()) {
+		return nil
+	}
+	b.units.Add(unit.Name())
+	b.owners.Add(unit.Tag().String())
+	if unit.doc.MachineId != "" {
+		b.machines.Add(unit.doc.MachineId)
+	}
+	attachments, err := b.st.UnitStorageAttachments(unit.UnitTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, attachment := range attachments {
+		si, err := b.st.StorageInstance(attachment.StorageInstance())
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		b.storage = append(b.storage, si)
+	}
+	for _, name := range unit.SubordinateNames() {
+		subordinate, err := b.st.Unit(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if err := b.addUnit(subordinate); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// impact returns the RemovalImpact of the units added so far.
+func (b *removalImpactBuilder) impact() (*RemovalImpact, error) {
+	machines := make(set.Strings)
+	emptyMachines := make(set.Strings)
+	for _, id := range b.machines.Values() {
+		m, err := b.st.Machine(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		empty := true
+		for _, principal := range m.doc.Principals {
+			if !b.units.Contains(principal) {
+				empty = false
+				break
+			}
+		}
+		if !empty {
+			continue
+		}
+		// As in destroyHostOps, a machine left empty is only
+		// destroyed if it hosts no containers and has no
+		// responsibilities that prevent a lifecycle change.
+		containers, err := m.Containers()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(containers) > 0 || hasJob(m.doc.Jobs, JobManageEnviron) || m.doc.HasVote {
+			emptyMachines.Add(id)
+		} else {
+			machines.Add(id)
+		}
+	}
+
+	detached := make(set.Strings)
+	destroyed := make(set.Strings)
+	for _, si := range b.storage {
+		id := si.StorageTag().Id()
+		if b.owners.Contains(si.Owner().String()) {
+			destroyed.Add(id)
+		} else {
+			detached.Add(id)
+		}
+	}
+
+	return &RemovalImpact{
+		Units:            b.units.SortedValues(),
+		Relations:        b.relations.SortedValues(),
+		Machines:         machines.SortedValues(),
+		EmptyMachines:    emptyMachines.SortedValues(),
+		DetachedStorage:  detached.SortedValues(),
+		DestroyedStorage: destroyed.SortedValues(),
+	}, nil
+}
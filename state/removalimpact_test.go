@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type RemovalImpactSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&RemovalImpactSuite{})
+
+// addRelatedServices adds a wordpress service with two units, each on
+// its own machine, related to a mysql service and to a logging
+// subordinate.
+func (s *RemovalImpactSuite) addRelatedServices(c *gc.C) (wordpress *state.Service, units []*state.Unit) {
+	wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	eps, err = s.State.InferEndpoints("wordpress", "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	loggingRel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i := 0; i < 2; i++ {
+		unit, err := wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+		ru, err := loggingRel.Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ru.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+		units = append(units, unit)
+	}
+	return wordpress, units
+}
+
+func (s *RemovalImpactSuite) TestServiceRemovalImpact(c *gc.C) {
+	s.addRelatedServices(c)
+	impact, err := s.State.ServiceRemovalImpact("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(impact, jc.DeepEquals, &state.RemovalImpact{
+		Units:     []string{"logging/0", "logging/1", "wordpress/0", "wordpress/1"},
+		Relations: []string{"logging:info wordpress:juju-info", "wordpress:db mysql:server"},
+		Machines:  []string{"0", "1"},
+	})
+}
+
+func (s *RemovalImpactSuite) TestServiceRemovalImpactNotFound(c *gc.C) {
+	_, err := s.State.ServiceRemovalImpact("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemovalImpactSuite) TestUnitsRemovalImpact(c *gc.C) {
+	s.addRelatedServices(c)
+	impact, err := s.State.UnitsRemovalImpact([]string{"wordpress/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(impact, jc.DeepEquals, &state.RemovalImpact{
+		Units:    []string{"logging/1", "wordpress/1"},
+		Machines: []string{"1"},
+	})
+}
+
+func (s *RemovalImpactSuite) TestUnitsRemovalImpactEmptyMachines(c *gc.C) {
+	_, units := s.addRelatedServices(c)
+
+	// A machine hosting another principal is not left empty.
+	mysql, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	mysqlUnit, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m0, err := units[0].AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysqlUnit.AssignToMachine(s.machine(c, m0))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A machine hosting a container is left empty, but not destroyed.
+	m1, err := units[1].AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, m1, "lxc")
+	c.Assert(err, jc.ErrorIsNil)
+
+	impact, err := s.State.UnitsRemovalImpact([]string{"wordpress/0", "wordpress/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(impact, jc.DeepEquals, &state.RemovalImpact{
+		Units:         []string{"logging/0", "logging/1", "wordpress/0", "wordpress/1"},
+		EmptyMachines: []string{m1},
+	})
+}
+
+func (s *RemovalImpactSuite) TestUnitsRemovalImpactSubordinate(c *gc.C) {
+	s.addRelatedServices(c)
+	impact, err := s.State.UnitsRemovalImpact([]string{"logging/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(impact.Units, gc.HasLen, 0)
+	c.Assert(impact.UnitErrors, gc.HasLen, 1)
+	c.Assert(impact.UnitErrors["logging/0"], gc.ErrorMatches, `unit "logging/0" is a subordinate`)
+}
+
+func (s *RemovalImpactSuite) TestUnitsRemovalImpactUnknownUnit(c *gc.C) {
+	s.addRelatedServices(c)
+	impact, err := s.State.UnitsRemovalImpact([]string{"wordpress/1", "wordpress/9"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(impact.Units, jc.DeepEquals, []string{"logging/1", "wordpress/1"})
+	c.Assert(impact.Machines, jc.DeepEquals, []string{"1"})
+	c.Assert(impact.UnitErrors, gc.HasLen, 1)
+	c.Assert(impact.UnitErrors["wordpress/9"], jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemovalImpactSuite) TestUnitsRemovalImpactStorage(c *gc.C) {
+	_, unit, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	impact, err := s.State.UnitsRemovalImpact([]string{unit.Name()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(impact, jc.DeepEquals, &state.RemovalImpact{
+		Units:            []string{unit.Name()},
+		DestroyedStorage: []string{storageTag.Id()},
+	})
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/network"
 )
 
 // Service represents the state of a service.
@@ -47,6 +48,13 @@ type serviceDoc struct {
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
 	MetricCredentials []byte     `bson:"metric-credentials"`
+
+	UpgradeStrategy *UpgradeStrategy      `bson:"upgradestrategy,omitempty"`
+	AddressPolicy   network.AddressPolicy `bson:"addresspolicy,omitempty"`
+
+	// EndpointBindings maps charm endpoint names to the network
+	// spaces they are bound to.
+	EndpointBindings map[string]string `bson:"endpointbindings,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -170,7 +178,11 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 	// removed, the service can also be removed.
 	if s.doc.UnitCount == 0 && s.doc.RelationCount == removeCount {
 		hasLastRefs := bson.D{{"life", Alive}, {"unitcount", 0}, {"relationcount", removeCount}}
-		return append(ops, s.removeOps(hasLastRefs)...), nil
+		removeOps, err := s.removeOps(hasLastRefs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, removeOps...), nil
 	}
 	// In all other cases, service removal will be handled as a consequence
 	// of the removal of the last unit or relation referencing it. If any
@@ -208,7 +220,7 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 
 // removeOps returns the operations required to remove the service. Supplied
 // asserts will be included in the operation on the service document.
-func (s *Service) removeOps(asserts bson.D) []txn.Op {
+func (s *Service) removeOps(asserts bson.D) ([]txn.Op, error) {
 	settingsDocID := s.st.docID(s.settingsKey())
 	ops := []txn.Op{
 		{
@@ -232,7 +244,11 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		removeLeadershipSettingsOp(s.Tag().Id()),
 		removeStatusOp(s.st, s.globalKey()),
 	}
-	return ops
+	storageOps, err := removeServiceStorageInstancesOps(s.st, names.NewServiceTag(s.doc.Name))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(ops, storageOps...), nil
 }
 
 // IsExposed returns whether this service is exposed. The explicitly open
@@ -848,6 +864,7 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 			Remove: true,
 		},
 		removeMeterStatusOp(s.st, u.globalMeterStatusKey()),
+		removeUnitProcessesOp(s.st, u.globalKey()),
 		removeStatusOp(s.st, u.globalAgentKey()),
 		removeStatusOp(s.st, u.globalKey()),
 		removeConstraintsOp(s.st, u.globalAgentKey()),
@@ -867,7 +884,11 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	}
 	if s.doc.Life == Dying && s.doc.RelationCount == 0 && s.doc.UnitCount == 1 {
 		hasLastRef := bson.D{{"life", Dying}, {"relationcount", 0}, {"unitcount", 1}}
-		return append(ops, s.removeOps(hasLastRef)...), nil
+		removeOps, err := s.removeOps(hasLastRef)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, removeOps...), nil
 	}
 	svcOp := txn.Op{
 		C:      servicesC,
@@ -1279,6 +1300,12 @@ func (s *Service) SetStatus(status Status, info string, data map[string]interfac
 	})
 }
 
+// StatusHistory returns a slice of at most <size> StatusInfo items
+// representing past statuses set for the service, newest first.
+func (s *Service) StatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(s.st, s.globalKey(), size)
+}
+
 // ServiceAndUnitsStatus returns the status for this service and all its units.
 func (s *Service) ServiceAndUnitsStatus() (StatusInfo, map[string]StatusInfo, error) {
 	serviceStatus, err := s.Status()
@@ -1821,6 +1821,25 @@ func (s *ServiceSuite) TestStatus(c *gc.C) {
 	}
 }
 
+func (s *ServiceSuite) TestStatusHistory(c *gc.C) {
+	err := s.mysql.SetStatus(state.StatusMaintenance, "installing", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetStatus(state.StatusActive, "ready", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.mysql.StatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Check(history[0].Status, gc.Equals, state.StatusActive)
+	c.Check(history[0].Message, gc.Equals, "ready")
+	c.Check(history[1].Status, gc.Equals, state.StatusMaintenance)
+
+	history, err = s.mysql.StatusHistory(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Status, gc.Equals, state.StatusActive)
+}
+
 const oneRequiredStorageMeta = `
 storage:
   data0:
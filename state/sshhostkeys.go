@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// SSHHostKeys holds the public SSH host keys of a machine, each in
+// the format of an authorized_keys line ("<type> <key> [<comment>]").
+type SSHHostKeys []string
+
+// sshHostKeysDoc records the public SSH host keys of a machine.
+type sshHostKeysDoc struct {
+	DocID   string   `bson:"_id"`
+	EnvUUID string   `bson:"env-uuid"`
+	Keys    []string `bson:"keys"`
+}
+
+// GetSSHHostKeys returns the public SSH host keys recorded for the
+// given machine. A NotFound error is returned if no keys have been
+// recorded.
+func (st *State) GetSSHHostKeys(tag names.MachineTag) (SSHHostKeys, error) {
+	coll, closer := st.getCollection(sshHostKeysC)
+	defer closer()
+
+	var doc sshHostKeysDoc
+	err := coll.FindId(machineGlobalKey(tag.Id())).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("SSH host keys for %s", tag)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get SSH host keys for %s", tag)
+	}
+	return SSHHostKeys(doc.Keys), nil
+}
+
+// SetSSHHostKeys records the public SSH host keys of the given
+// machine, replacing any previously recorded keys.
+func (st *State) SetSSHHostKeys(tag names.MachineTag, keys SSHHostKeys) error {
+	id := machineGlobalKey(tag.Id())
+	doc := sshHostKeysDoc{
+		Keys: keys,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		machine, err := st.Machine(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if machine.Life() == Dead {
+			return nil, errors.Errorf("machine %s is dead", tag.Id())
+		}
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     machine.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+
+		coll, closer := st.getCollection(sshHostKeysC)
+		defer closer()
+		var existing sshHostKeysDoc
+		err = coll.FindId(id).One(&existing)
+		switch {
+		case err == mgo.ErrNotFound:
+			return append(ops, txn.Op{
+				C:      sshHostKeysC,
+				Id:     st.docID(id),
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		case err != nil:
+			return nil, errors.Trace(err)
+		case stringSlicesEqual(existing.Keys, keys):
+			return nil, jujutxn.ErrNoOperations
+		}
+		return append(ops, txn.Op{
+			C:      sshHostKeysC,
+			Id:     st.docID(id),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"keys", keys}}}},
+		}), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set SSH host keys for %s", tag)
+	}
+	return nil
+}
+
+// removeSSHHostKeyOp returns the operation needed to remove the SSH
+// host key document associated with the given globalKey.
+func removeSSHHostKeyOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      sshHostKeysC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type SSHHostKeysSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&SSHHostKeysSuite{})
+
+func (s *SSHHostKeysSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SSHHostKeysSuite) TestGetWithNoKeys(c *gc.C) {
+	_, err := s.State.GetSSHHostKeys(s.machine.MachineTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SSHHostKeysSuite) TestSetGet(c *gc.C) {
+	for i := 0; i < 3; i++ {
+		keys := state.SSHHostKeys{"rsa foo", "dsa bar"}
+		err := s.State.SetSSHHostKeys(s.machine.MachineTag(), keys)
+		c.Assert(err, jc.ErrorIsNil)
+		s.checkGet(c, keys)
+	}
+}
+
+func (s *SSHHostKeysSuite) TestSetReplaces(c *gc.C) {
+	err := s.State.SetSSHHostKeys(s.machine.MachineTag(), state.SSHHostKeys{"rsa foo", "dsa bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetSSHHostKeys(s.machine.MachineTag(), state.SSHHostKeys{"ed25519 baz"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkGet(c, state.SSHHostKeys{"ed25519 baz"})
+}
+
+func (s *SSHHostKeysSuite) TestSetMissingMachine(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.EnsureDead(), jc.ErrorIsNil)
+	err = s.State.SetSSHHostKeys(m.MachineTag(), state.SSHHostKeys{"rsa foo"})
+	c.Assert(err, gc.ErrorMatches, "cannot set SSH host keys for machine-1: machine 1 is dead")
+}
+
+func (s *SSHHostKeysSuite) TestRemovedWithMachine(c *gc.C) {
+	err := s.State.SetSSHHostKeys(s.machine.MachineTag(), state.SSHHostKeys{"rsa foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.EnsureDead(), jc.ErrorIsNil)
+	c.Assert(s.machine.Remove(), jc.ErrorIsNil)
+
+	_, err = s.State.GetSSHHostKeys(s.machine.MachineTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SSHHostKeysSuite) checkGet(c *gc.C, expected state.SSHHostKeys) {
+	actual, err := s.State.GetSSHHostKeys(s.machine.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actual, gc.DeepEquals, expected)
+}
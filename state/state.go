@@ -68,7 +68,7 @@ type State struct {
 
 	// TODO(fwereade): move these out of state and make them independent
 	// workers on which state depends.
-	watcher           *watcher.Watcher
+	watcher           *watcher.Shared
 	pwatcher          *presence.Watcher
 	leadershipManager leadership.ManagerWorker
 
@@ -156,9 +156,12 @@ func (st *State) RemoveAllEnvironDocs() error {
 }
 
 // ForEnviron returns a connection to mongo for the specified environment. The
-// connection uses the same credentials and policy as the existing connection.
+// connection uses the same credentials and policy as the existing connection,
+// and shares its transaction log watcher, so that the log is polled once
+// however many environments are being watched. If that watcher has stopped,
+// a new one is started, and shared by later calls to ForEnviron instead.
 func (st *State) ForEnviron(env names.EnvironTag) (*State, error) {
-	newState, err := open(env, st.mongoInfo, mongo.DefaultDialOpts(), st.policy)
+	newState, err := open(env, st.mongoInfo, mongo.DefaultDialOpts(), st.policy, st.watcher)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -173,6 +176,33 @@ func (st *State) ForEnviron(env names.EnvironTag) (*State, error) {
 func (st *State) start(serverTag names.EnvironTag) error {
 	st.serverTag = serverTag
 
+	leaseClient, err := st.newLeaseClient(serviceLeadershipNamespace)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	clock := GetClock()
+	datastore := &environMongo{st}
+	logger.Infof("starting leadership manager")
+	leadershipManager, err := leadership.NewManager(leadership.ManagerConfig{
+		Client: leaseClient,
+		Clock:  clock,
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot create leadership manager")
+	}
+	st.leadershipManager = leadershipManager
+
+	logger.Infof("creating cloud image metadata storage")
+	st.CloudImageMetadataStorage = cloudimagemetadata.NewStorage(st.EnvironUUID(), cloudimagemetadataC, datastore)
+
+	logger.Infof("starting presence watcher")
+	st.pwatcher = presence.NewWatcher(st.getPresence(), st.environTag)
+	return nil
+}
+
+// newLeaseClient returns a lease.Client for the supplied namespace in the
+// state's environment.
+func (st *State) newLeaseClient(namespace string) (lease.Client, error) {
 	var clientId string
 	if identity := st.mongoInfo.Tag; identity != nil {
 		// TODO(fwereade): it feels a bit wrong to take this from MongoInfo -- I
@@ -187,40 +217,23 @@ func (st *State) start(serverTag names.EnvironTag) error {
 		logger.Infof("running state anonymously; using unique client id")
 		uuid, err := utils.NewUUID()
 		if err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		clientId = fmt.Sprintf("anon-%s", uuid.String())
 	}
 
-	logger.Infof("creating lease client as %s", clientId)
-	clock := GetClock()
-	datastore := &environMongo{st}
+	logger.Debugf("creating lease client as %s", clientId)
 	leaseClient, err := lease.NewClient(lease.ClientConfig{
 		Id:         clientId,
-		Namespace:  serviceLeadershipNamespace,
+		Namespace:  namespace,
 		Collection: leasesC,
-		Mongo:      datastore,
-		Clock:      clock,
+		Mongo:      &environMongo{st},
+		Clock:      GetClock(),
 	})
 	if err != nil {
-		return errors.Annotatef(err, "cannot create lease client")
+		return nil, errors.Annotatef(err, "cannot create lease client")
 	}
-	logger.Infof("starting leadership manager")
-	leadershipManager, err := leadership.NewManager(leadership.ManagerConfig{
-		Client: leaseClient,
-		Clock:  clock,
-	})
-	if err != nil {
-		return errors.Annotatef(err, "cannot create leadership manager")
-	}
-	st.leadershipManager = leadershipManager
-
-	logger.Infof("creating cloud image metadata storage")
-	st.CloudImageMetadataStorage = cloudimagemetadata.NewStorage(st.EnvironUUID(), cloudimagemetadataC, datastore)
-
-	logger.Infof("starting presence watcher")
-	st.pwatcher = presence.NewWatcher(st.getPresence(), st.environTag)
-	return nil
+	return leaseClient, nil
 }
 
 // EnvironTag() returns the environment tag for the environment controlled by
@@ -1096,6 +1109,15 @@ func (st *State) addPeerRelationsOps(serviceName string, peers map[string]charm.
 // they will be created automatically.
 func (st *State) AddService(
 	name, owner string, ch *Charm, networks []string, storage map[string]StorageConstraints,
+) (service *Service, err error) {
+	return st.AddServiceWithEndpointBindings(name, owner, ch, networks, storage, nil)
+}
+
+// AddServiceWithEndpointBindings is like AddService, but also binds the
+// charm's endpoints to network spaces as SetEndpointBindings does, in
+// the same transaction that adds the service.
+func (st *State) AddServiceWithEndpointBindings(
+	name, owner string, ch *Charm, networks []string, storage map[string]StorageConstraints, bindings map[string]string,
 ) (service *Service, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add service %q", name)
 	ownerTag, err := names.ParseUserTag(owner)
@@ -1132,6 +1154,10 @@ func (st *State) AddService(
 	if err := validateStorageConstraints(st, storage, ch.Meta()); err != nil {
 		return nil, errors.Trace(err)
 	}
+	bindingsOps, err := endpointBindingsOps(st, ch.Meta(), bindings)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	serviceID := st.docID(name)
 	// Create the service addition operations.
 	peers := ch.Meta().Peers
@@ -1146,6 +1172,9 @@ func (st *State) AddService(
 		Life:          Alive,
 		OwnerTag:      owner,
 	}
+	if len(bindings) > 0 {
+		svcDoc.EndpointBindings = bindings
+	}
 	svc := newService(st, svcDoc)
 
 	statusDoc := statusDoc{
@@ -1194,6 +1223,7 @@ func (st *State) AddService(
 		return nil, errors.Trace(err)
 	}
 	ops = append(ops, peerOps...)
+	ops = append(ops, bindingsOps...)
 
 	// At the last moment before inserting the service, prime status history.
 	probablyUpdateStatusHistory(st, svc.globalKey(), statusDoc)
@@ -1202,6 +1232,11 @@ func (st *State) AddService(
 		if err := checkEnvLife(st); err != nil {
 			return nil, errors.Trace(err)
 		}
+		for _, space := range bindings {
+			if _, err := st.Space(space); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 		return nil, errors.Errorf("service already exists")
 	} else if err != nil {
 		return nil, errors.Trace(err)
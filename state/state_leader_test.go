@@ -37,3 +37,28 @@ func (s *StateLeadershipSuite) TestHackLeadershipUnblocksClaimer(c *gc.C) {
 	case <-unblocked:
 	}
 }
+
+func (s *StateLeadershipSuite) TestServiceLeaders(c *gc.C) {
+	leaders, err := s.State.ServiceLeaders()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(leaders, gc.HasLen, 0)
+
+	claimer := s.State.LeadershipClaimer()
+	err = claimer.ClaimLeadership("blah", "blah/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = claimer.ClaimLeadership("other", "other/3", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	leaders, err = s.State.ServiceLeaders()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(leaders, jc.DeepEquals, map[string]string{
+		"blah":  "blah/0",
+		"other": "other/3",
+	})
+}
+
+func (s *StateLeadershipSuite) TestServiceLeadersManagerStopped(c *gc.C) {
+	s.State.HackLeadership()
+	_, err := s.State.ServiceLeaders()
+	c.Assert(err, gc.ErrorMatches, "leadership manager stopped")
+}
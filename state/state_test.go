@@ -164,6 +164,54 @@ func (s *StateSuite) TestOpenSetsEnvironmentTag(c *gc.C) {
 	c.Assert(st.EnvironTag(), gc.Equals, s.envTag)
 }
 
+func (s *StateSuite) TestForEnvironWatchesAfterParentClosed(c *gc.C) {
+	parent, err := state.Open(s.envTag, statetesting.NewMongoInfo(), statetesting.NewDialOpts(), state.Policy(nil))
+	c.Assert(err, jc.ErrorIsNil)
+	st, err := parent.ForEnviron(s.envTag)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// The transaction log watcher is shared with the parent, but
+	// keeps running for st after the parent is closed.
+	err = parent.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := st.WatchForEnvironConfigChanges()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, st, w)
+	wc.AssertOneChange()
+
+	newVersion := version.Current
+	newVersion.Minor++
+	err = statetesting.SetAgentVersion(st, newVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *StateSuite) TestForEnvironAfterWatcherStopped(c *gc.C) {
+	parent, err := state.Open(s.envTag, statetesting.NewMongoInfo(), statetesting.NewDialOpts(), state.Policy(nil))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Closing the parent stops the transaction log watcher, as no
+	// other State shares it.
+	err = parent.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	st, err := parent.ForEnviron(s.envTag)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	w := st.WatchForEnvironConfigChanges()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, st, w)
+	wc.AssertOneChange()
+
+	newVersion := version.Current
+	newVersion.Minor++
+	err = statetesting.SetAgentVersion(st, newVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func (s *StateSuite) TestEnvironUUID(c *gc.C) {
 	c.Assert(s.State.EnvironUUID(), gc.Equals, s.envTag.Id())
 }
@@ -701,6 +701,16 @@ func removeStorageAttachmentOps(
 			{"life", Alive},
 			{"attachmentcount", bson.D{{"$gt", 0}}},
 		}
+		// The storage instance outlives the attachment, so its
+		// volume or filesystem must be detached from the unit's
+		// machine to free it up for another unit.
+		detachOps, err := detachUnitMachineStorageOps(
+			st, si.StorageTag(), names.NewUnitTag(s.doc.Unit),
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, detachOps...)
 	} else {
 		// If it's not the last reference when we checked, we want to
 		// allow for concurrent attachment removals but want to ensure
@@ -737,6 +747,348 @@ func removeStorageInstancesOps(st *State, owner names.Tag) ([]txn.Op, error) {
 	return ops, nil
 }
 
+// removeServiceStorageInstancesOps returns the transaction operations to
+// remove all storage instances owned by the specified service, i.e. those
+// detached from its units with DetachStorage. Any volumes and filesystems
+// bound to the storage instances are destroyed along with them.
+func removeServiceStorageInstancesOps(st *State, service names.ServiceTag) ([]txn.Op, error) {
+	coll, closer := st.getCollection(storageInstancesC)
+	defer closer()
+
+	var docs []storageInstanceDoc
+	err := coll.Find(bson.D{{"owner", service.String()}}).Select(bson.D{{"id", true}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get storage instances for %s", service)
+	}
+	var ops []txn.Op
+	for _, doc := range docs {
+		// The service's units, and so their attachments, have all
+		// been removed by the time the service is.
+		assert := bson.D{
+			{"owner", service.String()},
+			{"attachmentcount", 0},
+		}
+		removeOps, err := removeStorageInstanceOps(st, names.NewStorageTag(doc.Id), assert)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, removeOps...)
+	}
+	return ops, nil
+}
+
+// detachUnitMachineStorageOps returns txn.Ops to detach the filesystem or
+// volume assigned to the specified storage instance from the machine that
+// the unit is assigned to. If the unit is not assigned to a machine, or
+// the storage is not attached to it, no operations are returned.
+func detachUnitMachineStorageOps(st *State, storage names.StorageTag, unit names.UnitTag) ([]txn.Op, error) {
+	u, err := st.Unit(unit.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineId, err := u.AssignedMachineId()
+	if errors.IsNotAssigned(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine := names.NewMachineTag(machineId)
+
+	// Filesystems are detached in preference to volumes; a volume
+	// backing a filesystem is detached when the filesystem attachment
+	// is removed.
+	filesystem, err := st.storageInstanceFilesystem(storage)
+	if err == nil {
+		attachment, err := st.FilesystemAttachment(machine, filesystem.FilesystemTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if attachment.Life() != Alive {
+			return nil, nil
+		}
+		return detachFilesystemOps(machine, filesystem.FilesystemTag()), nil
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	volume, err := st.storageInstanceVolume(storage)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	attachment, err := st.VolumeAttachment(machine, volume.VolumeTag())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if attachment.Life() != Alive {
+		return nil, nil
+	}
+	return detachVolumeOps(machine, volume.VolumeTag()), nil
+}
+
+// DetachStorage detaches the storage instance from the unit that owns it,
+// without destroying the storage. Ownership of the storage instance passes
+// to the unit's service, so that the storage instance survives the removal
+// of the unit and may later be attached to another unit of the service with
+// AttachStorage. The storage attachment is marked Dying, and the storage's
+// volume or filesystem will be detached from the unit's machine once the
+// storage attachment has been removed.
+//
+// Only storage provisioned by dynamic, environment-scoped providers may be
+// detached, since other storage cannot be moved between machines.
+func (st *State) DetachStorage(storage names.StorageTag, unit names.UnitTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot detach storage %s from unit %s", storage.Id(), unit.Id())
+	u, err := st.Unit(unit.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	service := u.ServiceName()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		si, err := st.storageInstance(storage)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if si.doc.Life != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		if si.doc.Owner != unit.String() {
+			return nil, errors.Errorf("storage is not owned by unit %s", unit.Id())
+		}
+		s, err := st.storageAttachment(storage, unit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Life == Dead {
+			return nil, errors.New("storage attachment is dead")
+		}
+		if err := validateStorageDetachable(st, si); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:  storageInstancesC,
+			Id: si.doc.Id,
+			Assert: bson.D{
+				{"life", Alive},
+				{"owner", unit.String()},
+			},
+			Update: bson.D{{"$set", bson.D{
+				{"owner", names.NewServiceTag(service).String()},
+			}}},
+		}}
+		if s.doc.Life == Alive {
+			ops = append(ops, destroyStorageAttachmentOps(storage, unit)...)
+		} else {
+			// The attachment of a Dying unit has already been
+			// destroyed by the unit's cleanup; the storage can
+			// still be handed over to the service before the
+			// unit is removed along with the storage it owns.
+			ops = append(ops, txn.Op{
+				C:      storageAttachmentsC,
+				Id:     storageAttachmentId(unit.Id(), storage.Id()),
+				Assert: bson.D{{"life", Dying}},
+			})
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+// validateStorageDetachable returns an error if the volume or filesystem
+// assigned to the storage instance cannot be moved to another machine.
+// Storage that has not yet been assigned a volume or filesystem holds no
+// data, and may always be detached.
+func validateStorageDetachable(st *State, si *storageInstance) error {
+	var pool string
+	filesystem, err := st.storageInstanceFilesystem(si.StorageTag())
+	if err == nil {
+		if pool, err = filesystemPool(filesystem); err != nil {
+			return errors.Trace(err)
+		}
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	} else {
+		volume, err := st.storageInstanceVolume(si.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if pool, err = volumePool(volume); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	_, provider, err := poolStorageProvider(st, pool)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if provider.Scope() != storage.ScopeEnviron || !provider.Dynamic() {
+		return errors.NotSupportedf("detaching storage from pool %q", pool)
+	}
+	return nil
+}
+
+// volumePool returns the name of the pool that the volume will be, or was,
+// provisioned from.
+func volumePool(v *volume) (string, error) {
+	if params, ok := v.Params(); ok {
+		return params.Pool, nil
+	}
+	info, err := v.Info()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return info.Pool, nil
+}
+
+// filesystemPool returns the name of the pool that the filesystem will be,
+// or was, provisioned from.
+func filesystemPool(f *filesystem) (string, error) {
+	if params, ok := f.Params(); ok {
+		return params.Pool, nil
+	}
+	info, err := f.Info()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return info.Pool, nil
+}
+
+// AttachStorage attaches a detached storage instance to the specified unit,
+// which then becomes the owner of the storage instance. The storage must
+// have been released to the unit's service with DetachStorage, and its
+// volume or filesystem must no longer be attached to any machine. If the
+// unit is assigned to a machine, the storage's volume or filesystem will
+// be attached to that machine; otherwise it will be attached when the unit
+// is assigned.
+func (st *State) AttachStorage(storage names.StorageTag, unit names.UnitTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot attach storage %s to unit %s", storage.Id(), unit.Id())
+	u, err := st.Unit(unit.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.Life() != Alive {
+			return nil, unitNotAliveErr
+		}
+		si, err := st.storageInstance(storage)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if si.doc.Life != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		serviceTag := names.NewServiceTag(u.ServiceName()).String()
+		if si.doc.Owner != serviceTag || si.doc.AttachmentCount != 0 {
+			return nil, errors.Errorf(
+				"storage is not detached from service %s", u.ServiceName(),
+			)
+		}
+		if err := validateStorageMachineAttachmentsRemoved(st, si); err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		svc, err := u.Service()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ch, _, err := svc.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		charmStorage, ok := ch.Meta().Storage[si.doc.StorageName]
+		if !ok {
+			return nil, errors.NotFoundf("charm storage %q", si.doc.StorageName)
+		}
+		if charmStorage.Shared {
+			return nil, errors.NotSupportedf("attaching shared storage")
+		}
+		count, err := st.countEntityStorageInstancesForName(unit, si.doc.StorageName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if charmStorage.CountMax >= 0 && count >= uint64(charmStorage.CountMax) {
+			return nil, errors.Errorf(
+				"unit already has %d %q storage instance(s), the maximum for the charm",
+				count, si.doc.StorageName,
+			)
+		}
+
+		priorCount := u.doc.StorageAttachmentCount
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: append(bson.D{{"storageattachmentcount", priorCount}}, isAliveDoc...),
+			Update: bson.D{{"$inc", bson.D{{"storageattachmentcount", 1}}}},
+		}, {
+			C:  storageInstancesC,
+			Id: si.doc.Id,
+			Assert: bson.D{
+				{"life", Alive},
+				{"owner", serviceTag},
+				{"attachmentcount", 0},
+			},
+			Update: bson.D{
+				{"$set", bson.D{{"owner", unit.String()}}},
+				{"$inc", bson.D{{"attachmentcount", 1}}},
+			},
+		},
+			createStorageAttachmentOp(storage, unit),
+		}
+
+		// If the unit is already assigned to a machine, attach the
+		// storage's volume or filesystem to that machine now.
+		if _, err := u.AssignedMachineId(); err == nil {
+			cons, err := u.StorageConstraints()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			machineOps, err := unitAssignedMachineStorageOps(
+				st, unit, ch.Meta(), cons, u.Series(), si,
+			)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, machineOps...)
+		} else if !errors.IsNotAssigned(err) {
+			return nil, errors.Trace(err)
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+// validateStorageMachineAttachmentsRemoved returns an error if the volume
+// or filesystem assigned to the storage instance is still attached to any
+// machine.
+func validateStorageMachineAttachmentsRemoved(st *State, si *storageInstance) error {
+	filesystem, err := st.storageInstanceFilesystem(si.StorageTag())
+	if err == nil {
+		if filesystem.doc.AttachmentCount > 0 {
+			return errors.Errorf("filesystem %s is still attached", filesystem.doc.FilesystemId)
+		}
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	volume, err := st.storageInstanceVolume(si.StorageTag())
+	if err == nil {
+		if volume.doc.AttachmentCount > 0 {
+			return errors.Errorf("volume %s is still attached", volume.doc.Name)
+		}
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 // storageConstraintsDoc contains storage constraints for an entity.
 type storageConstraintsDoc struct {
 	DocID       string                        `bson:"_id"`
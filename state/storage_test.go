@@ -828,6 +828,226 @@ func (s *StorageStateSuite) TestDestroyUnitStorageAttachments(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+// setupDetachableStorage adds a unit, assigned to a new machine, with a
+// volume provisioned by a dynamic, environment-scoped provider. The tag
+// of the corresponding storage instance is returned.
+func (s *StorageStateSuite) setupDetachableStorage(c *gc.C) (*state.Service, *state.Unit, names.StorageTag) {
+	ch := s.AddTestingCharm(c, "storage-block")
+	storage := map[string]state.StorageConstraints{
+		"data":    makeStorageCons("loop-pool", 1024, 1),
+		"allecto": makeStorageCons("environscoped-block", 1024, 1),
+	}
+	service := s.AddTestingServiceWithStorage(c, "storage-block", ch, storage)
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	return service, u, s.unitStorageTag(c, u, "allecto")
+}
+
+func (s *StorageStateSuite) unitStorageTag(c *gc.C, u *state.Unit, name string) names.StorageTag {
+	attachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	for _, a := range attachments {
+		si, err := s.State.StorageInstance(a.StorageInstance())
+		c.Assert(err, jc.ErrorIsNil)
+		if si.StorageName() == name {
+			return si.StorageTag()
+		}
+	}
+	c.Fatalf("unit %s has no %q storage", u.Name(), name)
+	panic("unreachable")
+}
+
+func (s *StorageStateSuite) unitMachineTag(c *gc.C, u *state.Unit) names.MachineTag {
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	return names.NewMachineTag(machineId)
+}
+
+func (s *StorageStateSuite) TestDetachStorage(c *gc.C) {
+	service, u, storageTag := s.setupDetachableStorage(c)
+	machineTag := s.unitMachineTag(c, u)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The storage instance is released to the service.
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Owner(), gc.Equals, service.Tag())
+	c.Assert(si.Life(), gc.Equals, state.Alive)
+	att, err := s.State.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Dying)
+
+	// The volume remains attached to the machine until the
+	// storage attachment is removed.
+	va := s.volumeAttachment(c, machineTag, volume.VolumeTag())
+	c.Assert(va.Life(), gc.Equals, state.Alive)
+
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	exists := s.storageInstanceExists(c, storageTag)
+	c.Assert(exists, jc.IsTrue)
+	va = s.volumeAttachment(c, machineTag, volume.VolumeTag())
+	c.Assert(va.Life(), gc.Equals, state.Dying)
+	volume = s.volume(c, volume.VolumeTag())
+	c.Assert(volume.Life(), gc.Equals, state.Alive)
+}
+
+func (s *StorageStateSuite) TestDetachStorageMachineScoped(c *gc.C) {
+	_, u, _ := s.setupDetachableStorage(c)
+	storageTag := s.unitStorageTag(c, u, "data")
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot detach storage data/\d+ from unit storage-block/0: detaching storage from pool "loop-pool" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *StorageStateSuite) TestDetachStorageNotOwned(c *gc.C) {
+	service, u, storageTag := s.setupDetachableStorage(c)
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.DetachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot detach storage allecto/\d+ from unit storage-block/1: storage is not owned by unit storage-block/1`)
+
+	err = s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot detach storage allecto/\d+ from unit storage-block/0: storage is not owned by unit storage-block/0`)
+}
+
+func (s *StorageStateSuite) TestDetachStorageDyingUnit(c *gc.C) {
+	service, u, storageTag := s.setupDetachableStorage(c)
+	err := u.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	att, err := s.State.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Dying)
+
+	err = s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Owner(), gc.Equals, service.Tag())
+
+	// The storage outlives the unit it was detached from.
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	exists := s.storageInstanceExists(c, storageTag)
+	c.Assert(exists, jc.IsTrue)
+}
+
+func (s *StorageStateSuite) TestRemoveServiceRemovesDetachedStorage(c *gc.C) {
+	service, u, storageTag := s.setupDetachableStorage(c)
+	volume := s.storageInstanceVolume(c, storageTag)
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	exists := s.storageInstanceExists(c, storageTag)
+	c.Assert(exists, jc.IsTrue)
+
+	err = service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	exists = s.storageInstanceExists(c, storageTag)
+	c.Assert(exists, jc.IsFalse)
+	// The volume bound to the storage is destroyed with it.
+	volume = s.volume(c, volume.VolumeTag())
+	c.Assert(volume.Life(), gc.Equals, state.Dying)
+}
+
+func (s *StorageStateSuite) TestAttachStorage(c *gc.C) {
+	service, u, storageTag := s.setupDetachableStorage(c)
+	machineTag := s.unitMachineTag(c, u)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveVolumeAttachment(machineTag, volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u2, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineTag2 := s.unitMachineTag(c, u2)
+	c.Assert(machineTag2, gc.Not(gc.Equals), machineTag)
+
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Owner(), gc.Equals, u2.Tag())
+	att, err := s.State.StorageAttachment(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Alive)
+
+	// The existing volume is attached to the new unit's machine,
+	// rather than a new volume being created.
+	c.Assert(s.storageInstanceVolume(c, storageTag).VolumeTag(), gc.Equals, volume.VolumeTag())
+	va := s.volumeAttachment(c, machineTag2, volume.VolumeTag())
+	c.Assert(va.Life(), gc.Equals, state.Alive)
+	assertMachineStorageRefs(c, s.State, machineTag2)
+}
+
+func (s *StorageStateSuite) TestAttachStorageUnassignedUnit(c *gc.C) {
+	service, u, storageTag := s.setupDetachableStorage(c)
+	machineTag := s.unitMachineTag(c, u)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveVolumeAttachment(machineTag, volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volume is attached when the unit is assigned to a machine.
+	err = s.State.AssignUnit(u2, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	va := s.volumeAttachment(c, s.unitMachineTag(c, u2), volume.VolumeTag())
+	c.Assert(va.Life(), gc.Equals, state.Alive)
+}
+
+func (s *StorageStateSuite) TestAttachStorageNotDetached(c *gc.C) {
+	service, _, storageTag := s.setupDetachableStorage(c)
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot attach storage allecto/\d+ to unit storage-block/1: storage is not detached from service storage-block`)
+}
+
+func (s *StorageStateSuite) TestAttachStorageStillAttachedToMachine(c *gc.C) {
+	service, u, storageTag := s.setupDetachableStorage(c)
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot attach storage allecto/\d+ to unit storage-block/1: volume \d+ is still attached`)
+}
+
 func (s *StorageStateSuite) TestStorageLocationConflictIdentical(c *gc.C) {
 	s.testStorageLocationConflict(
 		c, "/srv", "/srv",
@@ -0,0 +1,215 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TxnHealth summarises the health of the transaction queues, as found
+// by a call to CheckTxnQueues.
+type TxnHealth struct {
+	// Time is when the check was made.
+	Time time.Time
+
+	// Transactions is the number of transactions recorded, including
+	// completed ones that have yet to be pruned.
+	Transactions int
+
+	// OversizedQueues is the number of documents whose txn-queue was
+	// found to be longer than the maximum length checked for.
+	OversizedQueues int
+
+	// RemovedTokens is the number of tokens removed from those queues
+	// because their transactions had completed or been pruned.
+	RemovedTokens int
+}
+
+// txnHealthDoc records the result of the last transaction queue
+// check. There is a single such document for all environments.
+type txnHealthDoc struct {
+	DocID           string    `bson:"_id"`
+	Time            time.Time `bson:"time"`
+	Transactions    int       `bson:"transactions"`
+	OversizedQueues int       `bson:"oversized-queues"`
+	RemovedTokens   int       `bson:"removed-tokens"`
+}
+
+const txnHealthKey = "txnqueues"
+
+// mgo/txn transaction states, as recorded in the "s" field of the
+// transaction documents.
+const (
+	txnAborted = 5
+	txnApplied = 6
+)
+
+// txnStashC is the collection in which mgo/txn holds documents that
+// are being inserted or removed by a transaction.
+const txnStashC = "txns.stash"
+
+// CheckTxnQueues finds the documents, in every collection written by
+// transactions, whose txn-queue holds more than maxLength tokens. The
+// tokens of transactions that have completed or been pruned are removed
+// from those queues; long queues of such tokens slow every transaction
+// that touches the document. The result is recorded for TxnHealth to
+// return.
+func (st *State) CheckTxnQueues(maxLength int) (TxnHealth, error) {
+	txns, closer := st.getRawCollection(txnsC)
+	defer closer()
+
+	count, err := txns.Count()
+	if err != nil {
+		return TxnHealth{}, errors.Annotate(err, "cannot count transactions")
+	}
+	health := TxnHealth{
+		Time:         time.Now(),
+		Transactions: count,
+	}
+	for _, name := range txnQueueCollections() {
+		oversized, removed, err := repairTxnQueues(txns.Database.C(name), txns, maxLength)
+		if err != nil {
+			return TxnHealth{}, errors.Annotatef(err, "cannot check transaction queues in %q", name)
+		}
+		health.OversizedQueues += oversized
+		health.RemovedTokens += removed
+	}
+
+	healthColl, closer := st.getRawCollection(txnHealthC)
+	defer closer()
+	_, err = healthColl.UpsertId(txnHealthKey, txnHealthDoc{
+		DocID:           txnHealthKey,
+		Time:            health.Time,
+		Transactions:    health.Transactions,
+		OversizedQueues: health.OversizedQueues,
+		RemovedTokens:   health.RemovedTokens,
+	})
+	if err != nil {
+		return TxnHealth{}, errors.Annotate(err, "cannot record transaction queue health")
+	}
+	return health, nil
+}
+
+// TxnHealth returns the result of the most recent call to
+// CheckTxnQueues. It returns a NotFound error if the queues have never
+// been checked.
+func (st *State) TxnHealth() (TxnHealth, error) {
+	healthColl, closer := st.getRawCollection(txnHealthC)
+	defer closer()
+
+	var doc txnHealthDoc
+	err := healthColl.FindId(txnHealthKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return TxnHealth{}, errors.NotFoundf("transaction queue health")
+	} else if err != nil {
+		return TxnHealth{}, errors.Trace(err)
+	}
+	return TxnHealth{
+		Time:            doc.Time,
+		Transactions:    doc.Transactions,
+		OversizedQueues: doc.OversizedQueues,
+		RemovedTokens:   doc.RemovedTokens,
+	}, nil
+}
+
+// txnQueueCollections returns the names of the collections whose
+// documents may have txn-queue fields.
+func txnQueueCollections() []string {
+	names := []string{txnStashC}
+	for name, info := range allCollections() {
+		if !info.rawAccess {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// repairTxnQueues removes stale tokens from the txn-queues longer than
+// maxLength in coll, returning the number of such queues found and the
+// number of tokens removed.
+func repairTxnQueues(coll, txns *mgo.Collection, maxLength int) (int, int, error) {
+	// A queue is longer than maxLength if it has an element at that
+	// index.
+	sel := bson.D{{fmt.Sprintf("txn-queue.%d", maxLength), bson.D{{"$exists", true}}}}
+	iter := coll.Find(sel).Select(bson.D{{"txn-queue", 1}}).Iter()
+	var doc struct {
+		Id    interface{} `bson:"_id"`
+		Queue []string    `bson:"txn-queue"`
+	}
+	oversized, removed := 0, 0
+	for iter.Next(&doc) {
+		oversized++
+		logger.Warningf("%s document %v has %d queued transactions", coll.Name, doc.Id, len(doc.Queue))
+		stale, err := staleTxnTokens(txns, doc.Queue)
+		if err != nil {
+			iter.Close()
+			return 0, 0, errors.Trace(err)
+		}
+		if len(stale) == 0 {
+			continue
+		}
+		err = coll.UpdateId(doc.Id, bson.D{{"$pullAll", bson.D{{"txn-queue", stale}}}})
+		if err != nil && err != mgo.ErrNotFound {
+			iter.Close()
+			return 0, 0, errors.Annotatef(err, "cannot repair %s document %v", coll.Name, doc.Id)
+		}
+		logger.Infof("removed %d stale transactions from %s document %v", len(stale), coll.Name, doc.Id)
+		removed += len(stale)
+	}
+	if err := iter.Close(); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	return oversized, removed, nil
+}
+
+// staleTxnTokens returns those of the given txn-queue tokens whose
+// transactions have completed or no longer exist.
+func staleTxnTokens(txns *mgo.Collection, tokens []string) ([]string, error) {
+	// A token is the hex transaction id, followed by an underscore
+	// and a nonce.
+	ids := make([]bson.ObjectId, 0, len(tokens))
+	for _, token := range tokens {
+		if id, ok := txnTokenId(token); ok {
+			ids = append(ids, id)
+		}
+	}
+	var docs []struct {
+		Id    bson.ObjectId `bson:"_id"`
+		State int           `bson:"s"`
+	}
+	err := txns.Find(bson.D{{"_id", bson.D{{"$in", ids}}}}).Select(bson.D{{"s", 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pending := make(map[bson.ObjectId]bool)
+	for _, doc := range docs {
+		if doc.State != txnAborted && doc.State != txnApplied {
+			pending[doc.Id] = true
+		}
+	}
+	var stale []string
+	for _, token := range tokens {
+		if id, ok := txnTokenId(token); ok && !pending[id] {
+			stale = append(stale, token)
+		}
+	}
+	return stale, nil
+}
+
+// txnTokenId returns the id of the transaction a txn-queue token
+// refers to.
+func txnTokenId(token string) (bson.ObjectId, bool) {
+	const idLen = 24
+	if len(token) <= idLen || token[idLen] != '_' || !bson.IsObjectIdHex(token[:idLen]) {
+		return "", false
+	}
+	return bson.ObjectIdHex(token[:idLen]), true
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type txnQueuesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&txnQueuesSuite{})
+
+// addTxn records a transaction in the given mgo/txn state, returning
+// a queue token for it.
+func (s *txnQueuesSuite) addTxn(c *gc.C, txnState int) string {
+	id := bson.NewObjectId()
+	err := s.MgoSuite.Session.DB("juju").C("txns").Insert(bson.D{{"_id", id}, {"s", txnState}})
+	c.Assert(err, jc.ErrorIsNil)
+	return id.Hex() + "_0badcafe"
+}
+
+func (s *txnQueuesSuite) TestTxnHealthNotChecked(c *gc.C) {
+	_, err := s.State.TxnHealth()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *txnQueuesSuite) TestCheckTxnQueues(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	docID := state.DocID(s.State, m.Id())
+
+	// Fill the machine's queue with tokens for pruned, applied and
+	// aborted transactions, and one that is still being applied.
+	var tokens []string
+	for i := 0; i < 4; i++ {
+		tokens = append(tokens, bson.NewObjectId().Hex()+"_0badcafe")
+		tokens = append(tokens, s.addTxn(c, 6))
+		tokens = append(tokens, s.addTxn(c, 5))
+	}
+	pending := s.addTxn(c, 2)
+	tokens = append(tokens, pending)
+	err = s.machines.UpdateId(docID, bson.D{{"$pushAll", bson.D{{"txn-queue", tokens}}}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	before := time.Now()
+	health, err := s.State.CheckTxnQueues(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Time.Before(before), jc.IsFalse)
+	c.Assert(health.Transactions > len(tokens), jc.IsTrue)
+	c.Assert(health.OversizedQueues, gc.Equals, 1)
+	c.Assert(health.RemovedTokens >= len(tokens)-1, jc.IsTrue)
+
+	var doc struct {
+		Queue []string `bson:"txn-queue"`
+	}
+	err = s.machines.FindId(docID).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.Queue, jc.DeepEquals, []string{pending})
+
+	recorded, err := s.State.TxnHealth()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded.Time.Sub(health.Time) < time.Second, jc.IsTrue)
+	recorded.Time = health.Time
+	c.Assert(recorded, jc.DeepEquals, health)
+
+	// Nothing is left to repair, and the pending transaction is left
+	// alone.
+	health, err = s.State.CheckTxnQueues(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.OversizedQueues > 0, jc.IsTrue)
+	err = s.machines.FindId(docID).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.Queue, jc.DeepEquals, []string{pending})
+}
@@ -100,6 +100,10 @@ type unitDoc struct {
 	TxnRevno               int64 `bson:"txn-revno"`
 	PasswordHash           string
 
+	// UpgradeCharmURL holds the charm URL that the unit has been
+	// allowed to upgrade to by a rolling charm upgrade.
+	UpgradeCharmURL *charm.URL `bson:"upgradecharmurl,omitempty"`
+
 	// TODO(mue) No longer actively used, only in upgrades.go.
 	// To be removed later.
 	Ports          []port `bson:"ports"`
@@ -732,24 +736,50 @@ func (u *Unit) machine() (*Machine, error) {
 	return m, nil
 }
 
-// PublicAddress returns the public address of the unit.
+// PublicAddress returns the public address of the unit. If the unit's
+// service has an address policy, the address is selected according to
+// that policy.
 func (u *Unit) PublicAddress() (network.Address, error) {
 	m, err := u.machine()
 	if err != nil {
 		unitLogger.Errorf("%v", err)
 		return network.Address{}, errors.Trace(err)
 	}
-	return m.PublicAddress()
+	selector, ok, err := u.st.unitAddressSelector(u.doc.Service)
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	if !ok {
+		return m.PublicAddress()
+	}
+	addr, ok := selector.SelectPublicAddress(m.Addresses())
+	if !ok {
+		return network.Address{}, network.NoAddressf("public")
+	}
+	return addr, nil
 }
 
-// PrivateAddress returns the private address of the unit.
+// PrivateAddress returns the private address of the unit. If the unit's
+// service has an address policy, the address is selected according to
+// that policy.
 func (u *Unit) PrivateAddress() (network.Address, error) {
 	m, err := u.machine()
 	if err != nil {
 		unitLogger.Errorf("%v", err)
 		return network.Address{}, errors.Trace(err)
 	}
-	return m.PrivateAddress()
+	selector, ok, err := u.st.unitAddressSelector(u.doc.Service)
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	if !ok {
+		return m.PrivateAddress()
+	}
+	addr, ok := selector.SelectInternalAddress(m.Addresses(), false)
+	if !ok {
+		return network.Address{}, network.NoAddressf("private")
+	}
+	return addr, nil
 }
 
 // AvailabilityZone returns the name of the availability zone into which
@@ -1626,7 +1656,18 @@ func machineStorageParamsForStorageInstance(
 		volumeAttachmentParams := VolumeAttachmentParams{
 			charmStorage.ReadOnly,
 		}
-		if unit == storage.Owner() {
+		volume, err := st.StorageInstanceVolume(storage.StorageTag())
+		if err == nil {
+			// The storage instance already has a volume, either
+			// because it is shared by the service or because it
+			// was detached from another unit, so we will just add
+			// an attachment.
+			volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
+		} else if !errors.IsNotFound(err) || unit != storage.Owner() {
+			// The storage instance is owned by the service, so there
+			// should be a (shared) volume already.
+			return nil, errors.Annotatef(err, "getting volume for storage %q", storage.Tag().Id())
+		} else {
 			// The storage instance is owned by the unit, so we'll need
 			// to create a volume.
 			cons := allCons[storage.StorageName()]
@@ -1639,15 +1680,6 @@ func machineStorageParamsForStorageInstance(
 			volumes = append(volumes, MachineVolumeParams{
 				volumeParams, volumeAttachmentParams,
 			})
-		} else {
-			// The storage instance is owned by the service, so there
-			// should be a (shared) volume already, for which we will
-			// just add an attachment.
-			volume, err := st.StorageInstanceVolume(storage.StorageTag())
-			if err != nil {
-				return nil, errors.Annotatef(err, "getting volume for storage %q", storage.Tag().Id())
-			}
-			volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
 		}
 	case StorageKindFilesystem:
 		location, err := filesystemMountPoint(charmStorage, storage.StorageTag(), series)
@@ -1662,7 +1694,18 @@ func machineStorageParamsForStorageInstance(
 			location,
 			charmStorage.ReadOnly,
 		}
-		if unit == storage.Owner() {
+		filesystem, err := st.StorageInstanceFilesystem(storage.StorageTag())
+		if err == nil {
+			// The storage instance already has a filesystem, either
+			// because it is shared by the service or because it was
+			// detached from another unit, so we will just add an
+			// attachment.
+			filesystemAttachments[filesystem.FilesystemTag()] = filesystemAttachmentParams
+		} else if !errors.IsNotFound(err) || unit != storage.Owner() {
+			// The storage instance is owned by the service, so there
+			// should be a (shared) filesystem already.
+			return nil, errors.Annotatef(err, "getting filesystem for storage %q", storage.Tag().Id())
+		} else {
 			// The storage instance is owned by the unit, so we'll need
 			// to create a filesystem.
 			cons := allCons[storage.StorageName()]
@@ -1675,15 +1718,6 @@ func machineStorageParamsForStorageInstance(
 			filesystems = append(filesystems, MachineFilesystemParams{
 				filesystemParams, filesystemAttachmentParams,
 			})
-		} else {
-			// The storage instance is owned by the service, so there
-			// should be a (shared) filesystem already, for which we will
-			// just add an attachment.
-			filesystem, err := st.StorageInstanceFilesystem(storage.StorageTag())
-			if err != nil {
-				return nil, errors.Annotatef(err, "getting filesystem for storage %q", storage.Tag().Id())
-			}
-			filesystemAttachments[filesystem.FilesystemTag()] = filesystemAttachmentParams
 		}
 	default:
 		return nil, errors.Errorf("invalid storage kind %v", storage.Kind())
@@ -1783,17 +1817,25 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 	if cons.Arch != nil && *cons.Arch != "" {
 		suitableTerms = append(suitableTerms, bson.DocElem{"arch", *cons.Arch})
 	}
-	if cons.Mem != nil && *cons.Mem > 0 {
-		suitableTerms = append(suitableTerms, bson.DocElem{"mem", bson.D{{"$gte", *cons.Mem}}})
-	}
-	if cons.RootDisk != nil && *cons.RootDisk > 0 {
-		suitableTerms = append(suitableTerms, bson.DocElem{"rootdisk", bson.D{{"$gte", *cons.RootDisk}}})
-	}
-	if cons.CpuCores != nil && *cons.CpuCores > 0 {
-		suitableTerms = append(suitableTerms, bson.DocElem{"cpucores", bson.D{{"$gte", *cons.CpuCores}}})
-	}
-	if cons.CpuPower != nil && *cons.CpuPower > 0 {
-		suitableTerms = append(suitableTerms, bson.DocElem{"cpupower", bson.D{{"$gte", *cons.CpuPower}}})
+	for _, r := range []struct {
+		field    string
+		min, max *uint64
+	}{
+		{"mem", cons.Mem, cons.MaxMem},
+		{"rootdisk", cons.RootDisk, cons.MaxRootDisk},
+		{"cpucores", cons.CpuCores, cons.MaxCpuCores},
+		{"cpupower", cons.CpuPower, cons.MaxCpuPower},
+	} {
+		var term bson.D
+		if r.min != nil && *r.min > 0 {
+			term = append(term, bson.DocElem{"$gte", *r.min})
+		}
+		if r.max != nil {
+			term = append(term, bson.DocElem{"$lte", *r.max})
+		}
+		if len(term) > 0 {
+			suitableTerms = append(suitableTerms, bson.DocElem{r.field, term})
+		}
 	}
 	if cons.Tags != nil && len(*cons.Tags) > 0 {
 		suitableTerms = append(suitableTerms, bson.DocElem{"tags", bson.D{{"$all", *cons.Tags}}})
@@ -0,0 +1,212 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// UpgradeStrategyMode describes how a charm upgrade is applied to the
+// units of a service.
+type UpgradeStrategyMode string
+
+const (
+	// UpgradeAllAtOnce upgrades every unit of a service as soon as the
+	// service's charm changes. This is the default.
+	UpgradeAllAtOnce UpgradeStrategyMode = "all-at-once"
+
+	// UpgradeRolling upgrades the units of a service a few at a time,
+	// waiting for upgraded units to become healthy before upgrading
+	// any more of them.
+	UpgradeRolling UpgradeStrategyMode = "rolling"
+)
+
+// UpgradeStrategy holds the way in which charm upgrades are rolled
+// out to the units of a service.
+type UpgradeStrategy struct {
+	// Mode is the kind of upgrade strategy.
+	Mode UpgradeStrategyMode `bson:"mode"`
+
+	// MaxUnavailable is, for a rolling upgrade, the maximum number of
+	// units that may be upgrading, or upgraded but not yet healthy,
+	// at any time.
+	MaxUnavailable int `bson:"maxunavailable"`
+}
+
+// Validate returns an error if the upgrade strategy is not valid.
+func (s UpgradeStrategy) Validate() error {
+	switch s.Mode {
+	case UpgradeAllAtOnce:
+		if s.MaxUnavailable != 0 {
+			return errors.NotValidf("max-unavailable with %q upgrade strategy", s.Mode)
+		}
+	case UpgradeRolling:
+		if s.MaxUnavailable < 1 {
+			return errors.NotValidf("max-unavailable %d", s.MaxUnavailable)
+		}
+	default:
+		return errors.NotValidf("upgrade strategy %q", s.Mode)
+	}
+	return nil
+}
+
+// UpgradeStrategy returns the way in which charm upgrades are rolled
+// out to the units of the service.
+func (s *Service) UpgradeStrategy() UpgradeStrategy {
+	if s.doc.UpgradeStrategy == nil {
+		return UpgradeStrategy{Mode: UpgradeAllAtOnce}
+	}
+	return *s.doc.UpgradeStrategy
+}
+
+// SetUpgradeStrategy changes the way in which charm upgrades are
+// rolled out to the units of the service.
+func (s *Service) SetUpgradeStrategy(strategy UpgradeStrategy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set upgrade strategy for service %q", s)
+	if err := strategy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"upgradestrategy", &strategy}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errNotAlive
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.UpgradeStrategy = &strategy
+	return nil
+}
+
+// TargetCharmURL returns the charm URL the unit should be running,
+// and whether upgrading to it is forced. This is the service's charm
+// URL unless the service is being upgraded with a rolling strategy
+// and the unit has not yet been allowed to upgrade, in which case it
+// is the unit's current charm URL.
+func (u *Unit) TargetCharmURL() (*charm.URL, bool, error) {
+	service, err := u.Service()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	curl, force := service.CharmURL()
+	current := u.doc.CharmURL
+	if force || current == nil || service.UpgradeStrategy().Mode != UpgradeRolling {
+		return curl, force, nil
+	}
+	if permitted := u.doc.UpgradeCharmURL; permitted != nil && *permitted == *curl {
+		return curl, false, nil
+	}
+	return current, false, nil
+}
+
+// permitCharmUpgrade records that the unit may upgrade to the given
+// charm URL.
+func (u *Unit) permitCharmUpgrade(curl *charm.URL) error {
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"upgradecharmurl", curl}}}},
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return ErrDead
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	u.doc.UpgradeCharmURL = curl
+	return nil
+}
+
+// isHealthy reports whether the unit has finished running hooks and
+// its workload reports that it is active. Units whose charm does not
+// set a workload status are treated as healthy once idle.
+func (u *Unit) isHealthy() (bool, error) {
+	agentStatus, err := u.AgentStatus()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if agentStatus.Status != StatusIdle {
+		return false, nil
+	}
+	status, err := u.Status()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return status.Status == StatusActive || status.Status == StatusUnknown, nil
+}
+
+// AdvanceCharmRollouts allows more units of each service upgrading its
+// charm with a rolling strategy to upgrade, up to the service's maximum
+// number of unavailable units. Units that are upgrading, or that have
+// upgraded but are not yet healthy, count as unavailable.
+func (st *State) AdvanceCharmRollouts() error {
+	services, err := st.AllServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, service := range services {
+		if err := advanceCharmRollout(service); err != nil {
+			return errors.Annotatef(err, "cannot advance charm upgrade of service %q", service)
+		}
+	}
+	return nil
+}
+
+func advanceCharmRollout(service *Service) error {
+	strategy := service.UpgradeStrategy()
+	if service.Life() != Alive || strategy.Mode != UpgradeRolling {
+		return nil
+	}
+	curl, force := service.CharmURL()
+	if force {
+		// A forced upgrade applies to all units immediately.
+		return nil
+	}
+	units, err := service.AllUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var waiting []*Unit
+	var unavailable int
+	for _, unit := range units {
+		current := unit.doc.CharmURL
+		if unit.Life() != Alive || current == nil {
+			continue
+		}
+		switch permitted := unit.doc.UpgradeCharmURL; {
+		case *current == *curl:
+			healthy, err := unit.isHealthy()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if !healthy {
+				unavailable++
+			}
+		case permitted != nil && *permitted == *curl:
+			unavailable++
+		default:
+			waiting = append(waiting, unit)
+		}
+	}
+	for _, unit := range waiting {
+		if unavailable >= strategy.MaxUnavailable {
+			break
+		}
+		err := unit.permitCharmUpgrade(curl)
+		if err == ErrDead {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		logger.Infof("allowing unit %q to upgrade to charm %q", unit, curl)
+		unavailable++
+	}
+	return nil
+}
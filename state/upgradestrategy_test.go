@@ -0,0 +1,219 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type UpgradeStrategySuite struct {
+	ConnSuite
+	charm   *state.Charm
+	service *state.Service
+	units   []*state.Unit
+}
+
+var _ = gc.Suite(&UpgradeStrategySuite{})
+
+func (s *UpgradeStrategySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "mysql")
+	s.service = s.AddTestingService(c, "mysql", s.charm)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := s.service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(s.charm.URL())
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *UpgradeStrategySuite) TestDefaultUpgradeStrategy(c *gc.C) {
+	c.Assert(s.service.UpgradeStrategy(), jc.DeepEquals, state.UpgradeStrategy{
+		Mode: state.UpgradeAllAtOnce,
+	})
+}
+
+func (s *UpgradeStrategySuite) TestSetUpgradeStrategy(c *gc.C) {
+	strategy := state.UpgradeStrategy{Mode: state.UpgradeRolling, MaxUnavailable: 2}
+	err := s.service.SetUpgradeStrategy(strategy)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.UpgradeStrategy(), jc.DeepEquals, strategy)
+
+	service, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.UpgradeStrategy(), jc.DeepEquals, strategy)
+}
+
+func (s *UpgradeStrategySuite) TestSetUpgradeStrategyInvalid(c *gc.C) {
+	for i, test := range []struct {
+		strategy state.UpgradeStrategy
+		err      string
+	}{{
+		strategy: state.UpgradeStrategy{Mode: "sometimes"},
+		err:      `upgrade strategy "sometimes" not valid`,
+	}, {
+		strategy: state.UpgradeStrategy{Mode: state.UpgradeRolling},
+		err:      `max-unavailable 0 not valid`,
+	}, {
+		strategy: state.UpgradeStrategy{Mode: state.UpgradeAllAtOnce, MaxUnavailable: 1},
+		err:      `max-unavailable with "all-at-once" upgrade strategy not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := s.service.SetUpgradeStrategy(test.strategy)
+		c.Check(err, gc.ErrorMatches, `cannot set upgrade strategy for service "mysql": `+test.err)
+	}
+}
+
+func (s *UpgradeStrategySuite) TestSetUpgradeStrategyServiceNotAlive(c *gc.C) {
+	err := s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetUpgradeStrategy(state.UpgradeStrategy{Mode: state.UpgradeRolling, MaxUnavailable: 1})
+	c.Assert(err, gc.ErrorMatches, `cannot set upgrade strategy for service "mysql": not found or not alive`)
+}
+
+func (s *UpgradeStrategySuite) assertTargetCharmURLs(c *gc.C, expect ...*charm.URL) {
+	for i, unit := range s.units {
+		err := unit.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		curl, _, err := unit.TargetCharmURL()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(curl, gc.DeepEquals, expect[i], gc.Commentf("unit %q", unit))
+	}
+}
+
+func (s *UpgradeStrategySuite) setHealthy(c *gc.C, unit *state.Unit, curl *charm.URL) {
+	err := unit.SetCharmURL(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgradeStrategySuite) TestAllAtOnce(c *gc.C) {
+	newCharm := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err := s.service.SetCharm(newCharm, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	v2 := newCharm.URL()
+	s.assertTargetCharmURLs(c, v2, v2, v2)
+}
+
+func (s *UpgradeStrategySuite) TestRollingUpgrade(c *gc.C) {
+	err := s.service.SetUpgradeStrategy(state.UpgradeStrategy{Mode: state.UpgradeRolling, MaxUnavailable: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	newCharm := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err = s.service.SetCharm(newCharm, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	v1, v2 := s.charm.URL(), newCharm.URL()
+	s.assertTargetCharmURLs(c, v1, v1, v1)
+
+	// The first unit is allowed to upgrade.
+	err = s.State.AdvanceCharmRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertTargetCharmURLs(c, v2, v1, v1)
+
+	// No more units are allowed to upgrade while it is upgrading...
+	err = s.State.AdvanceCharmRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertTargetCharmURLs(c, v2, v1, v1)
+
+	// ...or once upgraded, until it is healthy.
+	err = s.units[0].SetCharmURL(v2)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].SetStatus(state.StatusMaintenance, "restarting", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AdvanceCharmRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertTargetCharmURLs(c, v2, v1, v1)
+
+	s.setHealthy(c, s.units[0], v2)
+	err = s.State.AdvanceCharmRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertTargetCharmURLs(c, v2, v2, v1)
+
+	s.setHealthy(c, s.units[1], v2)
+	err = s.State.AdvanceCharmRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertTargetCharmURLs(c, v2, v2, v2)
+}
+
+func (s *UpgradeStrategySuite) TestRollingUpgradeMaxUnavailable(c *gc.C) {
+	err := s.service.SetUpgradeStrategy(state.UpgradeStrategy{Mode: state.UpgradeRolling, MaxUnavailable: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	newCharm := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err = s.service.SetCharm(newCharm, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AdvanceCharmRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	v1, v2 := s.charm.URL(), newCharm.URL()
+	s.assertTargetCharmURLs(c, v2, v2, v1)
+}
+
+func (s *UpgradeStrategySuite) TestRollingUpgradeForced(c *gc.C) {
+	err := s.service.SetUpgradeStrategy(state.UpgradeStrategy{Mode: state.UpgradeRolling, MaxUnavailable: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	newCharm := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err = s.service.SetCharm(newCharm, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	v2 := newCharm.URL()
+	s.assertTargetCharmURLs(c, v2, v2, v2)
+}
+
+func (s *UpgradeStrategySuite) TestRollingUpgradeNewUnit(c *gc.C) {
+	err := s.service.SetUpgradeStrategy(state.UpgradeStrategy{Mode: state.UpgradeRolling, MaxUnavailable: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	newCharm := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err = s.service.SetCharm(newCharm, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A unit without a charm installs the service's charm directly.
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _, err := unit.TargetCharmURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, newCharm.URL())
+}
+
+func (s *UpgradeStrategySuite) TestWatchCharmRollouts(c *gc.C) {
+	w := s.State.WatchCharmRollouts()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Changing the service notifies.
+	err := s.service.SetUpgradeStrategy(state.UpgradeStrategy{Mode: state.UpgradeRolling, MaxUnavailable: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// So do changes to units and their statuses.
+	_, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	err = s.units[0].SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	err = s.units[0].SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Other entities' statuses do not.
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
@@ -1486,6 +1486,13 @@ func (u *Unit) WatchMeterStatus() NotifyWatcher {
 	})
 }
 
+// WatchProcesses returns a watcher for observing changes to the
+// workload processes tracked by a unit, including changes to their
+// status and resource usage.
+func (u *Unit) WatchProcesses() NotifyWatcher {
+	return newEntityWatcher(u.st, unitProcessesC, u.st.docID(u.globalKey()))
+}
+
 func newEntityWatcher(st *State, collName string, key interface{}) NotifyWatcher {
 	return newDocWatcher(st, []docKey{{collName, key}})
 }
@@ -1857,6 +1864,75 @@ func (w *cleanupWatcher) loop() (err error) {
 	}
 }
 
+// charmRolloutWatcher notifies of changes that may allow rolling
+// charm upgrades to progress.
+type charmRolloutWatcher struct {
+	commonWatcher
+	out chan struct{}
+}
+
+var _ Watcher = (*charmRolloutWatcher)(nil)
+
+// WatchCharmRollouts returns a NotifyWatcher that notifies of changes
+// to services, units and unit statuses, any of which may allow more
+// units of a service being upgraded with a rolling strategy to
+// upgrade.
+func (st *State) WatchCharmRollouts() NotifyWatcher {
+	return newCharmRolloutWatcher(st)
+}
+
+func newCharmRolloutWatcher(st *State) NotifyWatcher {
+	w := &charmRolloutWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *charmRolloutWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+// isUnitStatus reports whether the given statuses document id is
+// that of a unit's agent or workload status in this environment.
+func (w *charmRolloutWatcher) isUnitStatus(id interface{}) bool {
+	localID, err := w.st.strictLocalID(id.(string))
+	return err == nil && strings.HasPrefix(localID, "u#")
+}
+
+func (w *charmRolloutWatcher) loop() (err error) {
+	in := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(servicesC, in, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(servicesC, in)
+	w.st.watcher.WatchCollectionWithFilter(unitsC, in, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(unitsC, in)
+	w.st.watcher.WatchCollectionWithFilter(statusesC, in, w.isUnitStatus)
+	defer w.st.watcher.UnwatchCollection(statusesC, in)
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // actionStatusWatcher is a StringsWatcher that filters notifications
 // to Action Id's that match the ActionReceiver and ActionStatus set
 // provided.
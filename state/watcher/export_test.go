@@ -0,0 +1,10 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package watcher
+
+// KillShared kills the Watcher underlying the given view with the
+// given error, as if it had failed.
+func KillShared(s *Shared, err error) {
+	s.hub.watcher.tomb.Kill(err)
+}
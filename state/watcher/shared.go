@@ -0,0 +1,215 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package watcher
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"launchpad.net/tomb"
+)
+
+// Shared is a view of a Watcher that may be shared with other views,
+// so that a single goroutine tails the changelog on behalf of all of
+// them. It has the same methods as Watcher; stopping a view removes
+// only the watches made through it, and the underlying Watcher is
+// stopped when the last view using it is.
+type Shared struct {
+	tomb tomb.Tomb
+	hub  *hub
+
+	// mu guards watches and released.
+	mu sync.Mutex
+
+	// watches holds the watches made through this view that have
+	// not yet been removed.
+	watches map[sharedWatch]bool
+
+	// released is set when the view has been stopped and its
+	// watches removed from the underlying Watcher.
+	released bool
+}
+
+type sharedWatch struct {
+	key watchKey
+	ch  chan<- Change
+}
+
+// hub holds a Watcher and counts the views that use it.
+type hub struct {
+	watcher *Watcher
+	session *mgo.Session
+
+	// mu guards refs and replacement.
+	mu   sync.Mutex
+	refs int
+
+	// replacement holds the hub that new views are given once the
+	// Watcher has stopped, so that they too share a single Watcher.
+	replacement *hub
+}
+
+// NewShared returns a view of a new Watcher observing the changelog
+// collection, which must be a capped collection maintained by mgo/txn.
+// The Watcher uses its own copy of the collection's session, so it is
+// unaffected when the session is closed.
+func NewShared(changelog *mgo.Collection) *Shared {
+	return newHub(changelog).newView()
+}
+
+func newHub(changelog *mgo.Collection) *hub {
+	session := changelog.Database.Session.Copy()
+	return &hub{
+		watcher: New(changelog.With(session)),
+		session: session,
+	}
+}
+
+// Share returns a new view of the Watcher underlying s. If that Watcher
+// has stopped, the view is of a new Watcher observing the changelog
+// collection instead, which is shared in turn by later views made
+// through s.
+func (s *Shared) Share(changelog *mgo.Collection) *Shared {
+	return s.hub.live(changelog).newView()
+}
+
+// live returns h if its Watcher is still running, or else the hub
+// that replaces it, starting a new one on the changelog collection
+// if necessary.
+func (h *hub) live(changelog *mgo.Collection) *hub {
+	select {
+	case <-h.watcher.Dead():
+	default:
+		return h
+	}
+	h.mu.Lock()
+	if h.replacement == nil {
+		h.replacement = newHub(changelog)
+	}
+	replacement := h.replacement
+	h.mu.Unlock()
+	return replacement.live(changelog)
+}
+
+func (h *hub) newView() *Shared {
+	h.mu.Lock()
+	h.refs++
+	h.mu.Unlock()
+	s := &Shared{
+		hub:     h,
+		watches: make(map[sharedWatch]bool),
+	}
+	go func() {
+		defer s.tomb.Done()
+		select {
+		case <-s.tomb.Dying():
+		case <-h.watcher.Dead():
+			s.tomb.Kill(h.watcher.Err())
+		}
+		s.tomb.Kill(s.release())
+	}()
+	return s
+}
+
+// release removes the view's remaining watches from the underlying
+// Watcher, and stops the Watcher if no other view is using it.
+func (s *Shared) release() error {
+	s.mu.Lock()
+	s.released = true
+	for w := range s.watches {
+		s.hub.watcher.sendReq(reqUnwatch{w.key, w.ch})
+	}
+	s.watches = nil
+	s.mu.Unlock()
+
+	h := s.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.refs--
+	if h.refs > 0 {
+		return nil
+	}
+	err := h.watcher.Stop()
+	h.session.Close()
+	return errors.Trace(err)
+}
+
+// Stop stops the view. The underlying Watcher is stopped too if no
+// other view is using it.
+func (s *Shared) Stop() error {
+	s.tomb.Kill(nil)
+	return errors.Trace(s.tomb.Wait())
+}
+
+// Dead returns a channel that is closed when the view has stopped,
+// either because it was stopped or because the underlying Watcher
+// failed.
+func (s *Shared) Dead() <-chan struct{} {
+	return s.tomb.Dead()
+}
+
+// Err returns the error with which the view stopped, as Watcher.Err
+// does.
+func (s *Shared) Err() error {
+	return s.tomb.Err()
+}
+
+func (s *Shared) watch(key watchKey, info watchInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.released {
+		return
+	}
+	s.watches[sharedWatch{key, info.ch}] = true
+	s.hub.watcher.sendReq(reqWatch{key, info})
+}
+
+func (s *Shared) unwatch(key watchKey, ch chan<- Change) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.released {
+		// The view's watches have already been removed.
+		return
+	}
+	delete(s.watches, sharedWatch{key, ch})
+	s.hub.watcher.sendReq(reqUnwatch{key, ch})
+}
+
+// Watch is like Watcher.Watch.
+func (s *Shared) Watch(collection string, id interface{}, revno int64, ch chan<- Change) {
+	if id == nil {
+		panic("watcher: cannot watch a document with nil id")
+	}
+	s.watch(watchKey{collection, id}, watchInfo{ch, revno, nil})
+}
+
+// WatchCollection is like Watcher.WatchCollection.
+func (s *Shared) WatchCollection(collection string, ch chan<- Change) {
+	s.WatchCollectionWithFilter(collection, ch, nil)
+}
+
+// WatchCollectionWithFilter is like Watcher.WatchCollectionWithFilter.
+func (s *Shared) WatchCollectionWithFilter(collection string, ch chan<- Change, filter func(interface{}) bool) {
+	s.watch(watchKey{collection, nil}, watchInfo{ch, 0, filter})
+}
+
+// Unwatch is like Watcher.Unwatch.
+func (s *Shared) Unwatch(collection string, id interface{}, ch chan<- Change) {
+	if id == nil {
+		panic("watcher: cannot unwatch a document with nil id")
+	}
+	s.unwatch(watchKey{collection, id}, ch)
+}
+
+// UnwatchCollection is like Watcher.UnwatchCollection.
+func (s *Shared) UnwatchCollection(collection string, ch chan<- Change) {
+	s.unwatch(watchKey{collection, nil}, ch)
+}
+
+// StartSync forces the underlying Watcher to load new events from
+// the database, on behalf of all the views using it.
+func (s *Shared) StartSync() {
+	s.hub.watcher.StartSync()
+}
@@ -0,0 +1,123 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package watcher_test
+
+import (
+	"errors"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state/watcher"
+)
+
+func assertDead(c *gc.C, w *watcher.Shared) {
+	select {
+	case <-w.Dead():
+	case <-time.After(worstCase):
+		c.Fatalf("view did not stop")
+	}
+}
+
+func assertAlive(c *gc.C, w *watcher.Shared) {
+	select {
+	case <-w.Dead():
+		c.Fatalf("view stopped unexpectedly: %v", w.Err())
+	default:
+	}
+	c.Assert(w.Err(), gc.Equals, tomb.ErrStillAlive)
+}
+
+func (s *FastPeriodSuite) TestSharedViewsSeeChanges(c *gc.C) {
+	w1 := watcher.NewShared(s.log)
+	defer w1.Stop()
+	w2 := w1.Share(s.log)
+	defer w2.Stop()
+
+	ch2 := make(chan watcher.Change)
+	w1.Watch("test", "a", -1, s.ch)
+	w2.WatchCollection("test", ch2)
+
+	revno := s.insert(c, "test", "a")
+	w2.StartSync()
+	assertChange(c, s.ch, watcher.Change{"test", "a", revno})
+	assertChange(c, ch2, watcher.Change{"test", "a", revno})
+	assertNoChange(c, s.ch)
+	assertNoChange(c, ch2)
+}
+
+func (s *FastPeriodSuite) TestSharedStopRemovesOwnWatches(c *gc.C) {
+	w1 := watcher.NewShared(s.log)
+	w2 := w1.Share(s.log)
+	defer w2.Stop()
+
+	ch2 := make(chan watcher.Change)
+	w1.Watch("test", "a", -1, s.ch)
+	w2.Watch("test", "a", -1, ch2)
+
+	c.Assert(w1.Stop(), jc.ErrorIsNil)
+	assertDead(c, w1)
+	assertAlive(c, w2)
+
+	// Nothing is left waiting to send on the stopped view's channel,
+	// so the remaining view still sees changes.
+	revno := s.insert(c, "test", "a")
+	w2.StartSync()
+	assertChange(c, ch2, watcher.Change{"test", "a", revno})
+	assertNoChange(c, s.ch)
+
+	// Removing a watch after the view has stopped is harmless.
+	w1.Unwatch("test", "a", s.ch)
+}
+
+func (s *FastPeriodSuite) TestSharedStopsWithLastView(c *gc.C) {
+	w1 := watcher.NewShared(s.log)
+	w2 := w1.Share(s.log)
+	c.Assert(w1.Stop(), jc.ErrorIsNil)
+	c.Assert(w2.Stop(), jc.ErrorIsNil)
+	c.Assert(w2.Err(), jc.ErrorIsNil)
+
+	// A view shared from a stopped one stops straight away.
+	w3 := w2.Share(s.log)
+	assertDead(c, w3)
+	c.Assert(w3.Err(), jc.ErrorIsNil)
+}
+
+func (s *FastPeriodSuite) TestShareAfterWatcherDied(c *gc.C) {
+	w1 := watcher.NewShared(s.log)
+	watcher.KillShared(w1, errors.New("boom"))
+	assertDead(c, w1)
+	c.Assert(w1.Err(), gc.ErrorMatches, "boom")
+
+	// New views are given a new Watcher, which they share.
+	w2 := w1.Share(s.log)
+	defer w2.Stop()
+	assertAlive(c, w2)
+	w3 := w1.Share(s.log)
+	defer w3.Stop()
+
+	ch3 := make(chan watcher.Change)
+	w2.Watch("test", "a", -1, s.ch)
+	w3.Watch("test", "a", -1, ch3)
+	revno := s.insert(c, "test", "a")
+	w2.StartSync()
+	assertChange(c, s.ch, watcher.Change{"test", "a", revno})
+	assertChange(c, ch3, watcher.Change{"test", "a", revno})
+	assertAlive(c, w3)
+}
+
+func (s *FastPeriodSuite) TestSharedOutlivesSession(c *gc.C) {
+	session := s.log.Database.Session.Copy()
+	w := watcher.NewShared(s.log.With(session))
+	defer w.Stop()
+	session.Close()
+
+	w.Watch("test", "a", -1, s.ch)
+	revno := s.insert(c, "test", "a")
+	w.StartSync()
+	assertChange(c, s.ch, watcher.Change{"test", "a", revno})
+	assertAlive(c, w)
+}
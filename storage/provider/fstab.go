@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// fstabPath is the path to the filesystem table. Managed filesystems are
+// recorded there so that they are remounted when the machine reboots.
+var fstabPath = "/etc/fstab"
+
+// addFstabEntry records in the filesystem table that the filesystem on the
+// specified device should be mounted at the mount point, replacing any
+// existing entry for the mount point.
+//
+// The filesystem is identified by its UUID rather than by device path,
+// since kernel device names are not guaranteed to be stable across
+// reboots. Entries are added with the "nofail" option, so that a missing
+// device does not prevent the machine from booting.
+func addFstabEntry(run runCommandFunc, devicePath, mountPoint, fsType string, readOnly bool) error {
+	uuid, err := filesystemUUID(run, devicePath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	lines, err := readFstab()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	options := "defaults,nofail"
+	if readOnly {
+		options += ",ro"
+	}
+	entry := strings.Join([]string{
+		"UUID=" + uuid, mountPoint, fsType, options, "0", "0",
+	}, " ")
+	lines = append(removeFstabLines(lines, mountPoint), entry)
+	return errors.Trace(writeFstab(lines))
+}
+
+// filesystemUUID returns the UUID of the filesystem on the specified device.
+func filesystemUUID(run runCommandFunc, devicePath string) (string, error) {
+	output, err := run("blkid", "-o", "value", "-s", "UUID", devicePath)
+	if err != nil {
+		return "", errors.Annotatef(err, "getting UUID of filesystem on %q", devicePath)
+	}
+	uuid := strings.TrimSpace(output)
+	if uuid == "" {
+		return "", errors.Errorf("filesystem on %q has no UUID", devicePath)
+	}
+	return uuid, nil
+}
+
+// removeFstabEntry removes any entry for the specified mount point from
+// the filesystem table.
+func removeFstabEntry(mountPoint string) error {
+	lines, err := readFstab()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	remaining := removeFstabLines(lines, mountPoint)
+	if len(remaining) == len(lines) {
+		return nil
+	}
+	return errors.Trace(writeFstab(remaining))
+}
+
+// removeFstabLines returns the lines of the filesystem table, excluding
+// any entries for the specified mount point.
+func removeFstabLines(lines []string, mountPoint string) []string {
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && !strings.HasPrefix(fields[0], "#") && fields[1] == mountPoint {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+func readFstab() ([]string, error) {
+	data, err := ioutil.ReadFile(fstabPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "reading fstab")
+	}
+	content := strings.TrimRight(string(data), "\n")
+	if content == "" {
+		return nil, nil
+	}
+	return strings.Split(content, "\n"), nil
+}
+
+func writeFstab(lines []string) error {
+	var content string
+	if len(lines) > 0 {
+		content = strings.Join(lines, "\n") + "\n"
+	}
+	if err := utils.AtomicWriteFile(fstabPath, []byte(content), 0644); err != nil {
+		return errors.Annotate(err, "writing fstab")
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package provider
 import (
 	"path"
 	"path/filepath"
+	"strings"
 	"unicode"
 
 	"github.com/juju/errors"
@@ -146,6 +147,14 @@ func (s *managedFilesystemSource) attachFilesystem(arg storage.FilesystemAttachm
 	if err := mountFilesystem(s.run, s.dirFuncs, devicePath, arg.Path, arg.ReadOnly); err != nil {
 		return nil, errors.Trace(err)
 	}
+	// Loop devices do not survive a reboot, so there is
+	// no point in recording them in the filesystem table.
+	if !strings.HasPrefix(blockDevice.DeviceName, "loop") {
+		err := addFstabEntry(s.run, devicePath, arg.Path, defaultFilesystemType, arg.ReadOnly)
+		if err != nil {
+			return nil, errors.Annotate(err, "persisting mount")
+		}
+	}
 	return &storage.FilesystemAttachment{
 		arg.Filesystem,
 		arg.Machine,
@@ -162,6 +171,10 @@ func (s *managedFilesystemSource) DetachFilesystems(args []storage.FilesystemAtt
 	for i, arg := range args {
 		if err := maybeUnmount(s.run, s.dirFuncs, arg.Path); err != nil {
 			results[i] = err
+			continue
+		}
+		if err := removeFstabEntry(arg.Path); err != nil {
+			results[i] = errors.Annotate(err, "removing persisted mount")
 		}
 	}
 	return results, nil
@@ -4,6 +4,7 @@
 package provider_test
 
 import (
+	"io/ioutil"
 	"path/filepath"
 
 	"github.com/juju/names"
@@ -23,12 +24,27 @@ type managedfsSuite struct {
 	dirFuncs     *provider.MockDirFuncs
 	blockDevices map[names.VolumeTag]storage.BlockDevice
 	filesystems  map[names.FilesystemTag]storage.Filesystem
+	fstab        string
 }
 
 func (s *managedfsSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 	s.blockDevices = make(map[names.VolumeTag]storage.BlockDevice)
 	s.filesystems = make(map[names.FilesystemTag]storage.Filesystem)
+	s.fstab = filepath.Join(c.MkDir(), "fstab")
+	s.writeFstab(c, "/dev/sdz1 / ext4 defaults 0 1\n")
+	s.PatchValue(provider.FstabPath, s.fstab)
+}
+
+func (s *managedfsSuite) writeFstab(c *gc.C, content string) {
+	err := ioutil.WriteFile(s.fstab, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *managedfsSuite) assertFstab(c *gc.C, expect string) {
+	content, err := ioutil.ReadFile(s.fstab)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), gc.Equals, expect)
 }
 
 func (s *managedfsSuite) TearDownTest(c *gc.C) {
@@ -142,6 +158,8 @@ func (s *managedfsSuite) testAttachFilesystems(c *gc.C, readOnly, reattach bool)
 		args = append(args, "/dev/sda1", testMountPoint)
 		s.commands.expect("mount", args...)
 	}
+	cmd = s.commands.expect("blkid", "-o", "value", "-s", "UUID", "/dev/sda1")
+	cmd.respond("fa1c2c3e-f1e5-4d2c-9a3b-2a0e5f0c4b1d\n", nil)
 
 	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
 		DeviceName: "sda",
@@ -174,6 +192,87 @@ func (s *managedfsSuite) testAttachFilesystems(c *gc.C, readOnly, reattach bool)
 			},
 		},
 	}})
+
+	// The mount is recorded in fstab, so that it persists across reboots.
+	options := "defaults,nofail"
+	if readOnly {
+		options += ",ro"
+	}
+	s.assertFstab(c, "/dev/sdz1 / ext4 defaults 0 1\nUUID=fa1c2c3e-f1e5-4d2c-9a3b-2a0e5f0c4b1d "+testMountPoint+" ext4 "+options+" 0 0\n")
+}
+
+func (s *managedfsSuite) TestAttachFilesystemsReplacesFstabEntry(c *gc.C) {
+	s.writeFstab(c, "/dev/sdz1 / ext4 defaults 0 1\n/dev/sdb1 /in/the/place ext4 defaults 0 0\n")
+	s.testAttachFilesystems(c, false, true)
+}
+
+func (s *managedfsSuite) TestAttachFilesystemsNoUUID(c *gc.C) {
+	const testMountPoint = "/in/the/place"
+
+	source := s.initSource(c)
+	cmd := s.commands.expect("df", "--output=source", filepath.Dir(testMountPoint))
+	cmd.respond("headers\n/same/as/rootfs", nil)
+	cmd = s.commands.expect("df", "--output=source", testMountPoint)
+	cmd.respond("headers\n/same/as/rootfs", nil)
+	s.commands.expect("mount", "/dev/sda1", testMountPoint)
+	s.commands.expect("blkid", "-o", "value", "-s", "UUID", "/dev/sda1")
+
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		Size:       2,
+	}
+	s.filesystems[names.NewFilesystemTag("0/0")] = storage.Filesystem{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+	}
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/0"),
+		FilesystemId: "filesystem-0-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "inst-ance",
+		},
+		Path: testMountPoint,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `persisting mount: filesystem on "/dev/sda1" has no UUID`)
+	s.assertFstab(c, "/dev/sdz1 / ext4 defaults 0 1\n")
+}
+
+func (s *managedfsSuite) TestAttachFilesystemsLoopDevice(c *gc.C) {
+	const testMountPoint = "/in/the/place"
+
+	source := s.initSource(c)
+	cmd := s.commands.expect("df", "--output=source", filepath.Dir(testMountPoint))
+	cmd.respond("headers\n/same/as/rootfs", nil)
+	cmd = s.commands.expect("df", "--output=source", testMountPoint)
+	cmd.respond("headers\n/same/as/rootfs", nil)
+	s.commands.expect("mount", "/dev/loop0", testMountPoint)
+
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "loop0",
+		Size:       2,
+	}
+	s.filesystems[names.NewFilesystemTag("0/0")] = storage.Filesystem{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+	}
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/0"),
+		FilesystemId: "filesystem-0-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "inst-ance",
+		},
+		Path: testMountPoint,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	// Loop devices do not survive reboots, so fstab is left alone.
+	s.assertFstab(c, "/dev/sdz1 / ext4 defaults 0 1\n")
 }
 
 func (s *managedfsSuite) TestDetachFilesystems(c *gc.C) {
@@ -181,6 +280,13 @@ func (s *managedfsSuite) TestDetachFilesystems(c *gc.C) {
 	testDetachFilesystems(c, s.commands, source, true)
 }
 
+func (s *managedfsSuite) TestDetachFilesystemsRemovesFstabEntry(c *gc.C) {
+	s.writeFstab(c, "/dev/sdz1 / ext4 defaults 0 1\n/dev/sda1 /in/the/place ext4 defaults,nofail 0 0\n")
+	source := s.initSource(c)
+	testDetachFilesystems(c, s.commands, source, true)
+	s.assertFstab(c, "/dev/sdz1 / ext4 defaults 0 1\n")
+}
+
 func (s *managedfsSuite) TestDetachFilesystemsUnattached(c *gc.C) {
 	source := s.initSource(c)
 	testDetachFilesystems(c, s.commands, source, false)
@@ -54,6 +54,7 @@ type EnvUserParams struct {
 	User        string
 	DisplayName string
 	CreatedBy   names.Tag
+	Access      state.EnvUserAccess
 }
 
 // CharmParams defines the parameters for creating a charm.
@@ -192,8 +193,13 @@ func (factory *Factory) MakeEnvUser(c *gc.C, params *EnvUserParams) *state.Envir
 		c.Assert(err, jc.ErrorIsNil)
 		params.CreatedBy = env.Owner()
 	}
+	if params.Access == "" {
+		params.Access = state.EnvAdminAccess
+	}
 	createdByUserTag := params.CreatedBy.(names.UserTag)
-	envUser, err := factory.st.AddEnvironmentUser(names.NewUserTag(params.User), createdByUserTag, params.DisplayName)
+	envUser, err := factory.st.AddEnvironmentUserWithAccess(
+		names.NewUserTag(params.User), createdByUserTag, params.DisplayName, params.Access,
+	)
 	c.Assert(err, jc.ErrorIsNil)
 	return envUser
 }
@@ -36,6 +36,9 @@ type Options struct {
 	// knownHostsFile is a path to a file in which to save the host's
 	// fingerprint.
 	knownHostsFile string
+	// strictHostKeyChecking, if true, causes the connection to fail
+	// unless the host's key is found in the known hosts file.
+	strictHostKeyChecking bool
 }
 
 // SetProxyCommand sets a command to execute to proxy traffic through.
@@ -63,6 +66,15 @@ func (o *Options) SetKnownHostsFile(file string) {
 	o.knownHostsFile = file
 }
 
+// EnableStrictHostKeyChecking requires the host's key to be present
+// in the known hosts file; the connection fails if it is missing or
+// does not match.
+//
+// Host keys are not checked by default.
+func (o *Options) EnableStrictHostKeyChecking() {
+	o.strictHostKeyChecking = true
+}
+
 // AllowPasswordAuthentication allows the SSH
 // client to prompt the user for a password.
 //
@@ -16,6 +16,10 @@ import (
 
 var opensshCommonOptions = []string{"-o", "StrictHostKeyChecking no"}
 
+// opensshStrictOptions replace opensshCommonOptions when strict host
+// key checking is enabled.
+var opensshStrictOptions = []string{"-o", "StrictHostKeyChecking yes"}
+
 // default identities will not be attempted if
 // -i is specified and they are not explcitly
 // included.
@@ -64,10 +68,13 @@ func NewOpenSSHClient() (*OpenSSHClient, error) {
 }
 
 func opensshOptions(options *Options, commandKind opensshCommandKind) []string {
-	args := append([]string{}, opensshCommonOptions...)
 	if options == nil {
 		options = &Options{}
 	}
+	args := append([]string{}, opensshCommonOptions...)
+	if options.strictHostKeyChecking {
+		args = append([]string{}, opensshStrictOptions...)
+	}
 	if len(options.proxyCommand) > 0 {
 		args = append(args, "-o", "ProxyCommand "+utils.CommandString(options.proxyCommand...))
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/machiner"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/dependency"
@@ -32,10 +33,14 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 var newWorker = func(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
 	// TODO(fwereade): why on *earth* do we use the *uniter* facade for this
 	// worker? This code really ought to work anywhere...
-	tag := a.CurrentConfig().Tag()
-	unitTag, ok := tag.(names.UnitTag)
-	if !ok {
-		return nil, errors.Errorf("expected a unit tag; got %q", tag)
+	var addresser APIAddresser
+	switch tag := a.CurrentConfig().Tag().(type) {
+	case names.UnitTag:
+		addresser = uniter.NewState(apiCaller, tag)
+	case names.MachineTag:
+		addresser = machiner.NewState(apiCaller)
+	default:
+		return nil, errors.Errorf("expected a unit or machine tag; got %q", tag)
 	}
-	return NewAPIAddressUpdater(uniter.NewState(apiCaller, unitTag), agent.APIHostPortsSetter{a}), nil
+	return NewAPIAddressUpdater(addresser, agent.APIHostPortsSetter{a}), nil
 }
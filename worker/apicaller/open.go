@@ -110,9 +110,37 @@ func OpenAPIState(a agent.Agent) (_ api.Connection, _ *apiagent.Entity, err erro
 		}
 	}
 
+	// Record the address we connected to, so that it is tried first
+	// when next connecting. The agent config is only written when
+	// that changes the order in which the addresses are tried, so
+	// that reconnecting to the same server does not rewrite it.
+	addrs, err := agentConfig.APIAddresses()
+	if err != nil {
+		logger.Warningf("cannot read API addresses: %v", err)
+	} else if apiAddressRankChanged(addrs, st.Addr()) {
+		if err := a.ChangeConfig(func(c agent.ConfigSetter) error {
+			c.SetAPIAddressConnected(st.Addr(), time.Now())
+			return nil
+		}); err != nil {
+			logger.Warningf("cannot record API address %q: %v", st.Addr(), err)
+		}
+	}
 	return st, entity, nil
 }
 
+// apiAddressRankChanged reports whether recording a connection to addr
+// would change the order of the given API addresses, ranked by the time
+// they were last connected to. Only a known address that is not already
+// tried first moves.
+func apiAddressRankChanged(ranked []string, addr string) bool {
+	for i, rankedAddr := range ranked {
+		if rankedAddr == addr {
+			return i > 0
+		}
+	}
+	return false
+}
+
 func setAgentPassword(newPw, oldPw string, a agent.Agent, entity *apiagent.Entity) error {
 	// Change the configuration *before* setting the entity
 	// password, so that we avoid the possibility that
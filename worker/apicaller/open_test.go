@@ -6,6 +6,7 @@ package apicaller
 import (
 	"fmt"
 
+	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
@@ -87,6 +88,14 @@ func (s *OpenAPIStateSuite) TestOpenAPIStateWaitsProvisionedGivesUp(c *gc.C) {
 	c.Assert(called, gc.Equals, checkProvisionedStrategy.Min+1)
 }
 
+func (s *OpenAPIStateSuite) TestAPIAddressRankChanged(c *gc.C) {
+	ranked := []string{"10.0.0.1:17070", "10.0.0.2:17070"}
+	c.Check(apiAddressRankChanged(ranked, "10.0.0.1:17070"), jc.IsFalse)
+	c.Check(apiAddressRankChanged(ranked, "10.0.0.2:17070"), jc.IsTrue)
+	c.Check(apiAddressRankChanged(ranked, "10.0.0.3:17070"), jc.IsFalse)
+	c.Check(apiAddressRankChanged(nil, "10.0.0.1:17070"), jc.IsFalse)
+}
+
 type fakeAgent struct {
 	agent.Agent
 }
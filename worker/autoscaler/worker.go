@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/api/autoscaler"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.autoscaler")
+
+// Facade represents the API used by the autoscaler worker.
+type Facade interface {
+	EnvironConfig() (*config.Config, error)
+	ServiceInstanceGroups() ([]autoscaler.ServiceInstanceGroup, error)
+	SetInstanceGroupSizes([]autoscaler.InstanceGroupSize) error
+}
+
+// Config holds all necessary attributes to start an autoscaler worker.
+type Config struct {
+	Facade       Facade
+	NewEnviron   func(*config.Config) (environs.Environ, error)
+	PollInterval time.Duration
+	NewTimer     worker.NewTimerFunc
+}
+
+// Validate will err unless basic requirements for a valid
+// config are met.
+func (c *Config) Validate() error {
+	if c.Facade == nil {
+		return errors.New("missing Facade")
+	}
+	if c.NewEnviron == nil {
+		return errors.New("missing NewEnviron")
+	}
+	if c.NewTimer == nil {
+		return errors.New("missing Timer")
+	}
+	return nil
+}
+
+// New returns a worker that periodically checks the sizes of the cloud
+// instance groups linked to services, and reports any changes to the
+// Autoscaler facade so that units can be added or removed to match.
+func New(conf Config) (worker.Worker, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &autoscalerWorker{
+		config: conf,
+		sizes:  make(map[autoscaler.ServiceInstanceGroup]int),
+	}
+	f := func(stop <-chan struct{}) error {
+		return w.poll()
+	}
+	return worker.NewPeriodicWorker(f, conf.PollInterval, conf.NewTimer), nil
+}
+
+type autoscalerWorker struct {
+	config Config
+
+	// sizes records the last reported size of
+	// each instance group linked to a service.
+	sizes map[autoscaler.ServiceInstanceGroup]int
+}
+
+func (w *autoscalerWorker) poll() error {
+	cfg, err := w.config.Facade.EnvironConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot read environment config")
+	}
+	env, err := w.config.NewEnviron(cfg)
+	if err != nil {
+		return errors.Annotate(err, "cannot open environment")
+	}
+	groupsEnv, ok := environs.SupportsInstanceGroups(env)
+	if !ok {
+		logger.Tracef("environment does not support instance groups")
+		return nil
+	}
+	groups, err := w.config.Facade.ServiceInstanceGroups()
+	if err != nil {
+		return errors.Annotate(err, "cannot get service instance groups")
+	}
+
+	var changed []autoscaler.InstanceGroupSize
+	linked := make(map[autoscaler.ServiceInstanceGroup]bool)
+	for _, group := range groups {
+		linked[group] = true
+		size, err := groupsEnv.InstanceGroupSize(group.InstanceGroup)
+		if err != nil {
+			logger.Warningf("cannot get size of instance group %q: %v", group.InstanceGroup, err)
+			continue
+		}
+		if last, ok := w.sizes[group]; ok && last == size {
+			continue
+		}
+		logger.Debugf(
+			"instance group %q for %s has %d instance(s)",
+			group.InstanceGroup, group.Service, size,
+		)
+		changed = append(changed, autoscaler.InstanceGroupSize{
+			ServiceInstanceGroup: group,
+			Size:                 size,
+		})
+	}
+	// Forget about services that are no longer linked, so
+	// that their sizes are reported again if re-linked.
+	for group := range w.sizes {
+		if !linked[group] {
+			delete(w.sizes, group)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	if err := w.config.Facade.SetInstanceGroupSizes(changed); err != nil {
+		return errors.Annotate(err, "cannot set instance group sizes")
+	}
+	for _, size := range changed {
+		w.sizes[size.ServiceInstanceGroup] = size.Size
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apiautoscaler "github.com/juju/juju/api/autoscaler"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/autoscaler"
+)
+
+type autoscalerSuite struct {
+	coretesting.BaseSuite
+
+	facade  *fakeFacade
+	environ *fakeEnviron
+}
+
+var _ = gc.Suite(&autoscalerSuite{})
+
+var (
+	wordpressGroup = apiautoscaler.ServiceInstanceGroup{
+		Service:       names.NewServiceTag("wordpress"),
+		InstanceGroup: "wordpress-group",
+	}
+	mysqlGroup = apiautoscaler.ServiceInstanceGroup{
+		Service:       names.NewServiceTag("mysql"),
+		InstanceGroup: "mysql-group",
+	}
+)
+
+func groupSize(group apiautoscaler.ServiceInstanceGroup, size int) apiautoscaler.InstanceGroupSize {
+	return apiautoscaler.InstanceGroupSize{
+		ServiceInstanceGroup: group,
+		Size:                 size,
+	}
+}
+
+func (s *autoscalerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &fakeFacade{
+		groups: []apiautoscaler.ServiceInstanceGroup{wordpressGroup, mysqlGroup},
+		sets:   make(chan []apiautoscaler.InstanceGroupSize, 10),
+	}
+	s.environ = &fakeEnviron{sizes: map[string]int{
+		"wordpress-group": 2,
+		"mysql-group":     1,
+	}}
+}
+
+func (s *autoscalerSuite) startWorker(c *gc.C, env environs.Environ) worker.Worker {
+	w, err := autoscaler.New(autoscaler.Config{
+		Facade: s.facade,
+		NewEnviron: func(*config.Config) (environs.Environ, error) {
+			return env, nil
+		},
+		PollInterval: coretesting.ShortWait / 10,
+		NewTimer:     worker.NewTimer,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	})
+	return w
+}
+
+func (s *autoscalerSuite) assertSet(c *gc.C, expected ...apiautoscaler.InstanceGroupSize) {
+	select {
+	case sizes := <-s.facade.sets:
+		c.Assert(sizes, jc.SameContents, expected)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance group sizes")
+	}
+}
+
+func (s *autoscalerSuite) assertNoSet(c *gc.C) {
+	select {
+	case sizes := <-s.facade.sets:
+		c.Fatalf("unexpected instance group sizes: %v", sizes)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *autoscalerSuite) TestValidate(c *gc.C) {
+	_, err := autoscaler.New(autoscaler.Config{})
+	c.Assert(err, gc.ErrorMatches, "missing Facade")
+}
+
+func (s *autoscalerSuite) TestReportsChangedSizes(c *gc.C) {
+	s.startWorker(c, s.environ)
+	s.assertSet(c,
+		groupSize(wordpressGroup, 2),
+		groupSize(mysqlGroup, 1),
+	)
+	s.assertNoSet(c)
+
+	s.environ.setSize("wordpress-group", 4)
+	s.assertSet(c, groupSize(wordpressGroup, 4))
+	s.assertNoSet(c)
+}
+
+func (s *autoscalerSuite) TestMissingGroupNotReported(c *gc.C) {
+	s.startWorker(c, s.environ)
+	s.assertSet(c,
+		groupSize(wordpressGroup, 2),
+		groupSize(mysqlGroup, 1),
+	)
+
+	// A group that cannot be found must not be taken to have
+	// no instances, or every unit of its service would be destroyed.
+	s.environ.removeGroup("wordpress-group")
+	s.assertNoSet(c)
+
+	s.environ.setSize("wordpress-group", 3)
+	s.assertSet(c, groupSize(wordpressGroup, 3))
+}
+
+func (s *autoscalerSuite) TestInstanceGroupsNotSupported(c *gc.C) {
+	s.startWorker(c, &unsupportedEnviron{})
+	s.assertNoSet(c)
+}
+
+type fakeFacade struct {
+	groups []apiautoscaler.ServiceInstanceGroup
+	sets   chan []apiautoscaler.InstanceGroupSize
+}
+
+func (f *fakeFacade) EnvironConfig() (*config.Config, error) {
+	return nil, nil
+}
+
+func (f *fakeFacade) ServiceInstanceGroups() ([]apiautoscaler.ServiceInstanceGroup, error) {
+	return f.groups, nil
+}
+
+func (f *fakeFacade) SetInstanceGroupSizes(sizes []apiautoscaler.InstanceGroupSize) error {
+	f.sets <- sizes
+	return nil
+}
+
+type unsupportedEnviron struct {
+	environs.Environ
+}
+
+type fakeEnviron struct {
+	environs.Environ
+
+	mu    sync.Mutex
+	sizes map[string]int
+}
+
+func (e *fakeEnviron) setSize(name string, size int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sizes[name] = size
+}
+
+func (e *fakeEnviron) removeGroup(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.sizes, name)
+}
+
+func (e *fakeEnviron) InstanceGroupSize(name string) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	size, ok := e.sizes[name]
+	if !ok {
+		return 0, errors.NotFoundf("instance group %q", name)
+	}
+	return size, nil
+}
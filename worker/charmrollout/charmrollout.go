@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmrollout
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// RolloutAdvancer defines the interface for types capable of
+// advancing rolling charm upgrades.
+type RolloutAdvancer interface {
+	WatchCharmRollouts() state.NotifyWatcher
+	AdvanceCharmRollouts() error
+}
+
+// New returns a worker which allows more units of services being
+// upgraded with a rolling strategy to upgrade whenever services,
+// units or unit statuses change.
+func New(ra RolloutAdvancer) worker.Worker {
+	return worker.NewNotifyWorker(&charmRolloutWorker{ra: ra})
+}
+
+type charmRolloutWorker struct {
+	ra RolloutAdvancer
+}
+
+// SetUp is part of the worker.NotifyWatchHandler interface.
+func (w *charmRolloutWorker) SetUp() (watcher.NotifyWatcher, error) {
+	return w.ra.WatchCharmRollouts(), nil
+}
+
+// Handle is part of the worker.NotifyWatchHandler interface.
+func (w *charmRolloutWorker) Handle(_ <-chan struct{}) error {
+	if err := w.ra.AdvanceCharmRollouts(); err != nil {
+		return errors.Annotate(err, "cannot advance charm rollouts")
+	}
+	return nil
+}
+
+// TearDown is part of the worker.NotifyWatchHandler interface.
+func (w *charmRolloutWorker) TearDown() error {
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmrollout_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/charmrollout"
+)
+
+type CharmRolloutSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&CharmRolloutSuite{})
+
+func (s *CharmRolloutSuite) TestAdvancesOnChange(c *gc.C) {
+	advancer := newFakeRolloutAdvancer(nil)
+	w := charmrollout.New(advancer)
+	defer w.Kill()
+
+	for i := 0; i < 3; i++ {
+		advancer.watcher.changes <- struct{}{}
+		select {
+		case <-advancer.advanceCh:
+		case <-time.After(testing.LongWait):
+			c.Fatal("timed out waiting for rollouts to advance")
+		}
+	}
+}
+
+func (s *CharmRolloutSuite) TestNoChangeNoAdvance(c *gc.C) {
+	advancer := newFakeRolloutAdvancer(nil)
+	w := charmrollout.New(advancer)
+	defer w.Kill()
+
+	select {
+	case <-advancer.advanceCh:
+		c.Fatal("unexpected rollout advance")
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *CharmRolloutSuite) TestError(c *gc.C) {
+	advancer := newFakeRolloutAdvancer(errors.New("boom"))
+	w := charmrollout.New(advancer)
+	defer w.Kill()
+
+	advancer.watcher.changes <- struct{}{}
+	select {
+	case <-advancer.advanceCh:
+	case <-time.After(testing.LongWait):
+		c.Fatal("timed out waiting for rollouts to advance")
+	}
+	c.Assert(w.Wait(), gc.ErrorMatches, "cannot advance charm rollouts: boom")
+}
+
+func (s *CharmRolloutSuite) TestStops(c *gc.C) {
+	advancer := newFakeRolloutAdvancer(nil)
+	w := charmrollout.New(advancer)
+	w.Kill()
+	c.Assert(w.Wait(), jc.ErrorIsNil)
+	c.Assert(advancer.watcher.stopped, jc.IsTrue)
+}
+
+func newFakeRolloutAdvancer(err error) *fakeRolloutAdvancer {
+	return &fakeRolloutAdvancer{
+		watcher:   &fakeNotifyWatcher{changes: make(chan struct{})},
+		advanceCh: make(chan bool),
+		err:       err,
+	}
+}
+
+type fakeRolloutAdvancer struct {
+	watcher   *fakeNotifyWatcher
+	advanceCh chan bool
+	err       error
+}
+
+// WatchCharmRollouts implements the charmrollout.RolloutAdvancer
+// interface.
+func (a *fakeRolloutAdvancer) WatchCharmRollouts() state.NotifyWatcher {
+	return a.watcher
+}
+
+// AdvanceCharmRollouts implements the charmrollout.RolloutAdvancer
+// interface.
+func (a *fakeRolloutAdvancer) AdvanceCharmRollouts() error {
+	a.advanceCh <- true
+	return a.err
+}
+
+type fakeNotifyWatcher struct {
+	state.NotifyWatcher
+	changes chan struct{}
+	stopped bool
+}
+
+func (w *fakeNotifyWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *fakeNotifyWatcher) Stop() error {
+	w.stopped = true
+	return nil
+}
+
+func (w *fakeNotifyWatcher) Err() error {
+	return nil
+}
@@ -4,9 +4,12 @@
 package dblogpruner
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/state"
@@ -18,6 +21,11 @@ type LogPruneParams struct {
 	MaxLogAge       time.Duration
 	MaxCollectionMB int
 	PruneInterval   time.Duration
+
+	// LevelRetention holds the retention policies for logs of
+	// particular levels. These are applied in addition to
+	// MaxLogAge and MaxCollectionMB.
+	LevelRetention map[loggo.Level]state.LogRetention
 }
 
 const DefaultMaxLogAge = 3 * 24 * time.Hour // 3 days
@@ -34,6 +42,49 @@ func NewLogPruneParams() *LogPruneParams {
 	}
 }
 
+// ParseLevelRetention parses per-level log retention policies from a
+// string of the form "<level>=<max-age>[:<max-size-MB>],...", e.g.
+// "DEBUG=24h:512,INFO=:1024". An omitted age or size leaves logs of
+// that level unlimited in that respect.
+func ParseLevelRetention(value string) (map[loggo.Level]state.LogRetention, error) {
+	result := make(map[loggo.Level]state.LogRetention)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return result, nil
+	}
+	for _, spec := range strings.Split(value, ",") {
+		fields := strings.SplitN(strings.TrimSpace(spec), "=", 2)
+		if len(fields) != 2 {
+			return nil, errors.NotValidf("log retention %q", spec)
+		}
+		level, ok := loggo.ParseLevel(fields[0])
+		if !ok || level == loggo.UNSPECIFIED {
+			return nil, errors.NotValidf("log level %q", fields[0])
+		}
+		if _, ok := result[level]; ok {
+			return nil, errors.Errorf("log retention for %s specified more than once", level)
+		}
+		var policy state.LogRetention
+		limits := strings.SplitN(fields[1], ":", 2)
+		if limits[0] != "" {
+			age, err := time.ParseDuration(limits[0])
+			if err != nil || age < 0 {
+				return nil, errors.NotValidf("log retention age %q", limits[0])
+			}
+			policy.MaxAge = age
+		}
+		if len(limits) == 2 && limits[1] != "" {
+			size, err := strconv.Atoi(limits[1])
+			if err != nil || size < 0 {
+				return nil, errors.NotValidf("log retention size %q", limits[1])
+			}
+			policy.MaxLogsMB = size
+		}
+		result[level] = policy
+	}
+	return result, nil
+}
+
 // New returns a worker which periodically wakes up to remove old log
 // entries stored in MongoDB. This worker is intended to run just
 // once, on the MongoDB master.
@@ -57,7 +108,14 @@ func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
 		case <-stopCh:
 			return tomb.ErrDying
 		case <-time.After(p.PruneInterval):
-			minLogTime := time.Now().Add(-p.MaxLogAge)
+			now := time.Now()
+			if len(p.LevelRetention) > 0 {
+				err := state.PruneLogsByLevel(w.st, now, p.LevelRetention)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+			minLogTime := now.Add(-p.MaxLogAge)
 			err := state.PruneLogs(w.st, minLogTime, p.MaxCollectionMB)
 			if err != nil {
 				return errors.Trace(err)
@@ -106,12 +106,96 @@ func (s *suite) TestPrunesLogsBySize(c *gc.C) {
 	c.Fatal("pruning didn't happen as expected")
 }
 
+func (s *suite) TestPrunesLogsByLevel(c *gc.C) {
+	params := &dblogpruner.LogPruneParams{
+		MaxLogAge:       999 * time.Hour,
+		MaxCollectionMB: int(1e9),
+		PruneInterval:   time.Millisecond,
+		LevelRetention: map[loggo.Level]state.LogRetention{
+			loggo.DEBUG: {MaxAge: time.Hour},
+		},
+	}
+	s.pruner = dblogpruner.New(s.State, params)
+	s.AddCleanup(func(*gc.C) {
+		s.pruner.Kill()
+		c.Assert(s.pruner.Wait(), jc.ErrorIsNil)
+	})
+
+	old := time.Now().Add(-2 * time.Hour)
+	s.addLevelLogs(c, old, loggo.DEBUG, "prune", 10)
+	s.addLevelLogs(c, old, loggo.INFO, "keep", 10)
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		pruneRemaining, err := s.logsColl.Find(bson.M{"x": "prune"}).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if pruneRemaining == 0 {
+			keepCount, err := s.logsColl.Find(bson.M{"x": "keep"}).Count()
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(keepCount, gc.Equals, 10)
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
 func (s *suite) addLogs(c *gc.C, t0 time.Time, text string, count int) {
+	s.addLevelLogs(c, t0, loggo.INFO, text, count)
+}
+
+func (s *suite) addLevelLogs(c *gc.C, t0 time.Time, level loggo.Level, text string, count int) {
 	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("0"))
 	defer dbLogger.Close()
 
 	for offset := 0; offset < count; offset++ {
 		t := t0.Add(-time.Duration(offset) * time.Second)
-		dbLogger.Log(t, "some.module", "foo.go:42", loggo.INFO, text)
+		dbLogger.Log(t, "some.module", "foo.go:42", level, text)
+	}
+}
+
+type parseSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&parseSuite{})
+
+func (s *parseSuite) TestParseLevelRetention(c *gc.C) {
+	retention, err := dblogpruner.ParseLevelRetention("DEBUG=24h:512, TRACE=1h,INFO=:1024")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retention, jc.DeepEquals, map[loggo.Level]state.LogRetention{
+		loggo.DEBUG: {MaxAge: 24 * time.Hour, MaxLogsMB: 512},
+		loggo.TRACE: {MaxAge: time.Hour},
+		loggo.INFO:  {MaxLogsMB: 1024},
+	})
+}
+
+func (s *parseSuite) TestParseLevelRetentionEmpty(c *gc.C) {
+	retention, err := dblogpruner.ParseLevelRetention("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retention, gc.HasLen, 0)
+}
+
+func (s *parseSuite) TestParseLevelRetentionInvalid(c *gc.C) {
+	for i, test := range []struct {
+		value string
+		err   string
+	}{{
+		value: "DEBUG",
+		err:   `log retention "DEBUG" not valid`,
+	}, {
+		value: "LOUD=1h",
+		err:   `log level "LOUD" not valid`,
+	}, {
+		value: "DEBUG=soon",
+		err:   `log retention age "soon" not valid`,
+	}, {
+		value: "DEBUG=1h:big",
+		err:   `log retention size "big" not valid`,
+	}, {
+		value: "DEBUG=1h,DEBUG=2h",
+		err:   `log retention for DEBUG specified more than once`,
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		_, err := dblogpruner.ParseLevelRetention(test.value)
+		c.Check(err, gc.ErrorMatches, test.err)
 	}
 }
@@ -490,6 +490,9 @@ func (engine *engine) gotStopped(name string, err error, resourceLog []resourceA
 		case ErrUninstall:
 			// The task should never run again, and can be removed completely.
 			engine.uninstall(name)
+		case ErrBounce:
+			// The task wants to be restarted at once; there's nothing wrong.
+			engine.requestStart(name, engine.config.BounceDelay)
 		default:
 			// Something went wrong but we don't know what. Try again soon.
 			logger.Errorf("%q manifold worker returned unexpected error: %v", name, err)
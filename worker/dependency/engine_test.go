@@ -420,6 +420,27 @@ func (s *EngineSuite) TestErrUninstall(c *gc.C) {
 	mh2.AssertOneStart(c)
 }
 
+func (s *EngineSuite) TestErrBounce(c *gc.C) {
+
+	// Start a simple dependency.
+	mh1 := newManifoldHarness()
+	err := s.engine.Install("some-task", mh1.Manifold())
+	c.Assert(err, jc.ErrorIsNil)
+	mh1.AssertOneStart(c)
+
+	// Start its dependent.
+	mh2 := newManifoldHarness("some-task")
+	err = s.engine.Install("another-task", mh2.Manifold())
+	c.Assert(err, jc.ErrorIsNil)
+	mh2.AssertOneStart(c)
+
+	// Bounce the dependency; it should be restarted, and so should its
+	// dependent.
+	mh1.InjectError(c, dependency.ErrBounce)
+	mh1.AssertOneStart(c)
+	mh2.AssertStart(c)
+}
+
 // TestWorstError starts an engine with two manifolds that always error
 // with fatal errors. We test that the most important error is the one
 // returned by the engine.
@@ -122,6 +122,11 @@ var ErrMissing = errors.New("dependency not available")
 // should be completely removed.
 var ErrUninstall = errors.New("resource permanently unavailable")
 
+// ErrBounce can be returned by a worker to indicate to the engine that it
+// should be restarted immediately, and its dependents with it; for example
+// because the value it outputs has changed.
+var ErrBounce = errors.New("restart immediately")
+
 // OutputFunc is a type coercion function for a worker generated by a StartFunc.
 // When passed an out pointer to a type it recognises, it will assign a suitable
 // value and return no error.
@@ -171,10 +171,10 @@ type deployerService interface {
 	Stop() error
 }
 
-// findUpstartJob tries to find an init system job matching the
+// findInitSystemJob tries to find an init system job matching the
 // given unit name in one of these formats:
-//   jujud-<deployer-tag>:<unit-tag>.conf (for compatibility)
-//   jujud-<unit-tag>.conf (default)
+//   jujud-<deployer-tag>:<unit-tag> (for compatibility)
+//   jujud-<unit-tag> (default)
 func (ctx *SimpleContext) findInitSystemJob(unitName string) (deployerService, error) {
 	unitsAndJobs, err := ctx.deployedUnitsInitSystemJobs()
 	if err != nil {
@@ -33,6 +33,7 @@ type Firewaller struct {
 	environWatcher  apiwatcher.NotifyWatcher
 	machinesWatcher apiwatcher.StringsWatcher
 	portsWatcher    apiwatcher.StringsWatcher
+	rulesWatcher    apiwatcher.NotifyWatcher
 	machineds       map[names.MachineTag]*machineData
 	unitsChange     chan *unitsChange
 	unitds          map[names.UnitTag]*unitData
@@ -77,6 +78,11 @@ func NewFirewaller(st *apifirewaller.State) (_ worker.Worker, err error) {
 	}
 	logger.Debugf("started watching opened port ranges for the environment")
 
+	fw.rulesWatcher, err = st.WatchFirewallWhitelists()
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to start firewall whitelists watcher")
+	}
+
 	// We won't "wait" actually, because the environ is already
 	// available and has a guaranteed valid config, but until
 	// WaitForEnviron goes away, this code needs to stay.
@@ -89,9 +95,10 @@ func NewFirewaller(st *apifirewaller.State) (_ worker.Worker, err error) {
 	case config.FwGlobal:
 		fw.globalMode = true
 		fw.globalPortRef = make(map[network.PortRange]int)
-	case config.FwNone:
-		logger.Warningf("stopping firewaller - firewall-mode is %q", config.FwNone)
-		return nil, errors.Errorf("firewaller is disabled when firewall-mode is %q", config.FwNone)
+	case config.FwNone, config.FwMachine:
+		mode := fw.environ.Config().FirewallMode()
+		logger.Warningf("stopping firewaller - firewall-mode is %q", mode)
+		return nil, errors.Errorf("firewaller is disabled when firewall-mode is %q", mode)
 	}
 
 	go func() {
@@ -148,14 +155,29 @@ func (fw *Firewaller) loop() error {
 			if !ok {
 				return watcher.EnsureErr(fw.portsWatcher)
 			}
+			changed := make(map[names.MachineTag]*machineData)
 			for _, portsGlobalKey := range change {
 				machineTag, networkTag, err := parsePortsKey(portsGlobalKey)
 				if err != nil {
 					return errors.Trace(err)
 				}
-				if err := fw.openedPortsChanged(machineTag, networkTag); err != nil {
+				machined, err := fw.openedPortsChanged(machineTag, networkTag)
+				if err != nil {
 					return errors.Trace(err)
 				}
+				if machined != nil {
+					changed[machineTag] = machined
+				}
+			}
+			if err := fw.flushMachines(changed); err != nil {
+				return errors.Trace(err)
+			}
+		case _, ok := <-fw.rulesWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(fw.rulesWatcher)
+			}
+			if err := fw.whitelistsChanged(); err != nil {
+				return errors.Annotate(err, "cannot apply firewall whitelists")
 			}
 		case change := <-fw.unitsChange:
 			if err := fw.unitsChanged(change); err != nil {
@@ -250,14 +272,14 @@ func (fw *Firewaller) startUnit(unit *apifirewaller.Unit, machineTag names.Machi
 		return err
 	}
 
-	// check if the machine has ports open on any networks
+	// check if the machine has ports open on any networks; the caller
+	// flushes the machine's ports once the unit is being watched.
 	networkTags, err := m.ActiveNetworks()
 	if err != nil {
 		return errors.Annotatef(err, "failed getting %q active networks", machineTag)
 	}
 	for _, networkTag := range networkTags {
-		err := fw.openedPortsChanged(machineTag, networkTag)
-		if err != nil {
+		if _, err := fw.openedPortsChanged(machineTag, networkTag); err != nil {
 			return err
 		}
 	}
@@ -428,8 +450,9 @@ func (fw *Firewaller) unitsChanged(change *unitsChange) error {
 	return nil
 }
 
-// openedPortsChanged handles port change notifications
-func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, networkTag names.NetworkTag) error {
+// openedPortsChanged handles port change notifications. It returns
+// the machine's data if its ports need to be flushed, and nil otherwise.
+func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, networkTag names.NetworkTag) (*machineData, error) {
 
 	machined, ok := fw.machineds[machineTag]
 	if !ok {
@@ -437,17 +460,17 @@ func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, networkTag
 		// registering the machine, so if a machine is not found in
 		// firewaller's list, just skip the change.
 		logger.Errorf("failed to lookup %q, skipping port change", machineTag)
-		return nil
+		return nil, nil
 	}
 
 	m, err := machined.machine()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ports, err := m.OpenedPorts(networkTag)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	newPortRanges := make(map[network.PortRange]names.UnitTag)
@@ -458,16 +481,16 @@ func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, networkTag
 			// registering a unit. Skip handling the port change - it will
 			// be handled when the unit is registered.
 			logger.Errorf("failed to lookup %q, skipping port change", unitTag)
-			return nil
+			return nil, nil
 		}
 		newPortRanges[portRange] = unitd.tag
 	}
 
 	if !portMapsEqual(machined.definedPorts, newPortRanges) {
 		machined.definedPorts = newPortRanges
-		return fw.flushMachine(machined)
+		return machined, nil
 	}
-	return nil
+	return nil, nil
 }
 
 func portMapsEqual(a, b map[network.PortRange]names.UnitTag) bool {
@@ -492,16 +515,42 @@ func (fw *Firewaller) flushUnits(unitds []*unitData) error {
 	for _, unitd := range unitds {
 		machineds[unitd.machined.tag] = unitd.machined
 	}
+	return fw.flushMachines(machineds)
+}
+
+// flushMachine opens and closes ports for the passed machine.
+func (fw *Firewaller) flushMachine(machined *machineData) error {
+	return fw.flushMachines(map[names.MachineTag]*machineData{
+		machined.tag: machined,
+	})
+}
+
+// flushMachines opens and closes ports for the passed machines. In
+// global mode the changes for all of them are combined, so that a
+// change affecting many machines at once, such as exposing a service
+// with many units, is made with a single call to the environment.
+func (fw *Firewaller) flushMachines(machineds map[names.MachineTag]*machineData) error {
+	var allOpen, allClose []network.PortRange
 	for _, machined := range machineds {
-		if err := fw.flushMachine(machined); err != nil {
+		toOpen, toClose := fw.machinePortChanges(machined)
+		if fw.globalMode {
+			allOpen = append(allOpen, toOpen...)
+			allClose = append(allClose, toClose...)
+			continue
+		}
+		if err := fw.flushInstancePorts(machined, toOpen, toClose); err != nil {
 			return err
 		}
 	}
+	if fw.globalMode {
+		return fw.flushGlobalPorts(allOpen, allClose)
+	}
 	return nil
 }
 
-// flushMachine opens and closes ports for the passed machine.
-func (fw *Firewaller) flushMachine(machined *machineData) error {
+// machinePortChanges returns the port ranges that must be opened and
+// closed for the passed machine, and records them as done.
+func (fw *Firewaller) machinePortChanges(machined *machineData) (toOpen, toClose []network.PortRange) {
 	// Gather ports to open and close.
 	want := []network.PortRange{}
 	for portRange, unitTag := range machined.definedPorts {
@@ -514,13 +563,10 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 			want = append(want, portRange)
 		}
 	}
-	toOpen := diffRanges(want, machined.openedPorts)
-	toClose := diffRanges(machined.openedPorts, want)
+	toOpen = diffRanges(want, machined.openedPorts)
+	toClose = diffRanges(machined.openedPorts, want)
 	machined.openedPorts = want
-	if fw.globalMode {
-		return fw.flushGlobalPorts(toOpen, toClose)
-	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	return toOpen, toClose
 }
 
 // flushGlobalPorts opens and closes global ports in the environment.
@@ -665,6 +711,35 @@ func (fw *Firewaller) forgetUnit(unitd *unitData) {
 	}
 }
 
+// whitelistsChanged restricts access to each well-known service with
+// a whitelist to its whitelisted source address ranges, if the
+// environment supports it.
+func (fw *Firewaller) whitelistsChanged() error {
+	whitelists, err := fw.st.FirewallWhitelists()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(whitelists) == 0 {
+		return nil
+	}
+	whitelister, ok := environs.SupportsFirewallWhitelists(fw.environ)
+	if !ok {
+		logger.Warningf("environment does not support firewall whitelists, ignoring them")
+		return nil
+	}
+	for _, service := range network.WellKnownServices {
+		cidrs, ok := whitelists[service]
+		if !ok {
+			continue
+		}
+		logger.Infof("restricting access to %s to %v", service, cidrs)
+		if err := whitelister.SetFirewallWhitelist(service, cidrs); err != nil {
+			return errors.Annotatef(err, "cannot restrict access to %s", service)
+		}
+	}
+	return nil
+}
+
 // stopWatchers stops all the firewaller's watchers.
 func (fw *Firewaller) stopWatchers() {
 	if fw.environWatcher != nil {
@@ -676,6 +751,9 @@ func (fw *Firewaller) stopWatchers() {
 	if fw.portsWatcher != nil {
 		watcher.Stop(fw.portsWatcher, &fw.tomb)
 	}
+	if fw.rulesWatcher != nil {
+		watcher.Stop(fw.rulesWatcher, &fw.tomb)
+	}
 	for _, serviced := range fw.serviceds {
 		if serviced != nil {
 			watcher.Stop(serviced, &fw.tomb)
@@ -152,6 +152,43 @@ func (s *InstanceModeSuite) TestStartStop(c *gc.C) {
 	statetesting.AssertKillAndWait(c, fw)
 }
 
+func (s *InstanceModeSuite) TestFirewallWhitelists(c *gc.C) {
+	err := s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: network.SSHRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+	s.assertWhitelist(c, network.SSHRule, []string{"10.0.0.0/8"})
+
+	err = s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: network.JujuControllerRule,
+		WhitelistCIDRs:   []string{"192.168.0.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertWhitelist(c, network.JujuControllerRule, []string{"192.168.0.0/16"})
+}
+
+// assertWhitelist waits for the environment's whitelist for the given
+// service to match the expected one.
+func (s *InstanceModeSuite) assertWhitelist(c *gc.C, service network.WellKnownServiceType, expected []string) {
+	whitelister := s.Environ.(interface {
+		FirewallWhitelist(network.WellKnownServiceType) ([]string, error)
+	})
+	s.BackingState.StartSync()
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		got, err := whitelister.FirewallWhitelist(service)
+		c.Assert(err, jc.ErrorIsNil)
+		if reflect.DeepEqual(got, expected) {
+			return
+		}
+	}
+	c.Fatalf("timed out waiting for %s whitelist %v", service, expected)
+}
+
 func (s *InstanceModeSuite) TestNotExposedService(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, jc.ErrorIsNil)
@@ -636,6 +673,51 @@ func (s *GlobalModeSuite) TestGlobalMode(c *gc.C) {
 	s.assertEnvironPorts(c, nil)
 }
 
+func (s *GlobalModeSuite) TestGlobalModeBatchesMachines(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	var expected []network.PortRange
+	for i := 0; i < 3; i++ {
+		u, m := s.addUnit(c, svc)
+		s.startInstance(c, m)
+		port := 80 + i
+		err = u.OpenPort("tcp", port)
+		c.Assert(err, jc.ErrorIsNil)
+		expected = append(expected, network.PortRange{port, port, "tcp"})
+	}
+	s.assertEnvironPorts(c, expected)
+
+	ops := make(chan dummy.Operation, 10)
+	dummy.Listen(ops)
+	defer dummy.Listen(nil)
+
+	// Unexposing the service closes the ports of all its units'
+	// machines with a single call to the environment.
+	err = svc.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironPorts(c, nil)
+
+	select {
+	case op := <-ops:
+		closePorts, ok := op.(dummy.OpClosePorts)
+		c.Assert(ok, jc.IsTrue, gc.Commentf("unexpected operation %#v", op))
+		c.Assert(closePorts.Ports, jc.SameContents, expected)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for ports to be closed")
+	}
+	select {
+	case op := <-ops:
+		c.Fatalf("unexpected operation %#v", op)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
 func (s *GlobalModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -803,3 +885,23 @@ func (s *NoneModeSuite) TestDoesNotStartAtAll(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `firewaller is disabled when firewall-mode is "none"`)
 	c.Assert(fw, gc.IsNil)
 }
+
+type MachineModeSuite struct {
+	firewallerBaseSuite
+}
+
+var _ = gc.Suite(&MachineModeSuite{})
+
+func (s *MachineModeSuite) SetUpTest(c *gc.C) {
+	s.firewallerBaseSuite.setUpTest(c, config.FwMachine)
+}
+
+func (s *MachineModeSuite) TearDownTest(c *gc.C) {
+	s.firewallerBaseSuite.JujuConnSuite.TearDownTest(c)
+}
+
+func (s *MachineModeSuite) TestDoesNotStartAtAll(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, gc.ErrorMatches, `firewaller is disabled when firewall-mode is "machine"`)
+	c.Assert(fw, gc.IsNil)
+}
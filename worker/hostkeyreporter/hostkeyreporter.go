@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hostkeyreporter implements a worker that records the SSH
+// host keys of the machine it runs on, so that clients connecting to
+// the machine with "juju ssh" can verify its identity.
+package hostkeyreporter
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.hostkeyreporter")
+
+// reportPeriod is the time period between checks for changed host
+// keys. Keys normally only change if the machine is re-imaged, so
+// this need not be frequent.
+const reportPeriod = 10 * time.Minute
+
+// DefaultSSHDir is the directory in which the SSH daemon's host keys
+// are normally found.
+const DefaultSSHDir = "/etc/ssh"
+
+// Reporter is an interface that is supplied to NewWorker for recording
+// the SSH host keys of the local machine.
+type Reporter interface {
+	ReportKeys(publicKeys []string) error
+}
+
+// NewWorker returns a worker that reads the public SSH host keys from
+// the given directory, and reports them whenever they change.
+func NewWorker(reporter Reporter, sshDir string) worker.Worker {
+	var old []string
+	f := func(stop <-chan struct{}) error {
+		return doWork(reporter, sshDir, &old)
+	}
+	return worker.NewPeriodicWorker(f, reportPeriod, worker.NewTimer)
+}
+
+func doWork(reporter Reporter, sshDir string, old *[]string) error {
+	keys, err := readPublicKeys(sshDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(keys) == 0 {
+		// The SSH daemon may not have generated its keys yet.
+		logger.Debugf("no SSH host keys found in %s", sshDir)
+		return nil
+	}
+	if reflect.DeepEqual(keys, *old) {
+		logger.Tracef("no changes to SSH host keys detected")
+		return nil
+	}
+	logger.Infof("reporting %d SSH host keys", len(keys))
+	if err := reporter.ReportKeys(keys); err != nil {
+		return errors.Annotate(err, "cannot report SSH host keys")
+	}
+	*old = keys
+	return nil
+}
+
+// readPublicKeys returns the contents of the public host key files in
+// the given directory, sorted by file name.
+func readPublicKeys(sshDir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(sshDir, "ssh_host_*_key.pub"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Strings(paths)
+	var keys []string
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read SSH host key")
+		}
+		keys = append(keys, strings.TrimSpace(string(data)))
+	}
+	return keys, nil
+}
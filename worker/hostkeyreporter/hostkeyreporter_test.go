@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/hostkeyreporter"
+)
+
+var _ = gc.Suite(&HostKeyReporterSuite{})
+
+type HostKeyReporterSuite struct {
+	coretesting.BaseSuite
+	sshDir string
+}
+
+func (s *HostKeyReporterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.sshDir = c.MkDir()
+	s.writeKey(c, "ssh_host_rsa_key.pub", "ssh-rsa rsa-key root@host\n")
+	s.writeKey(c, "ssh_host_dsa_key.pub", "ssh-dss dsa-key root@host\n")
+	// Private keys and other files must not be reported.
+	s.writeKey(c, "ssh_host_rsa_key", "private")
+	s.writeKey(c, "sshd_config", "config")
+}
+
+func (s *HostKeyReporterSuite) writeKey(c *gc.C, name, content string) {
+	err := ioutil.WriteFile(filepath.Join(s.sshDir, name), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type ReporterFunc func([]string) error
+
+func (f ReporterFunc) ReportKeys(keys []string) error {
+	return f(keys)
+}
+
+func (s *HostKeyReporterSuite) TestWorker(c *gc.C) {
+	done := make(chan []string, 1)
+	reporter := ReporterFunc(func(keys []string) error {
+		done <- keys
+		return nil
+	})
+
+	w := hostkeyreporter.NewWorker(reporter, s.sshDir)
+	defer w.Wait()
+	defer w.Kill()
+
+	select {
+	case keys := <-done:
+		c.Assert(keys, jc.DeepEquals, []string{
+			"ssh-dss dsa-key root@host",
+			"ssh-rsa rsa-key root@host",
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for hostkeyreporter to report")
+	}
+}
+
+func (s *HostKeyReporterSuite) TestKeyChanges(c *gc.C) {
+	var reported [][]string
+	reporter := ReporterFunc(func(keys []string) error {
+		reported = append(reported, keys)
+		return nil
+	})
+	var old []string
+	err := hostkeyreporter.DoWork(reporter, s.sshDir, &old)
+	c.Assert(err, jc.ErrorIsNil)
+	err = hostkeyreporter.DoWork(reporter, s.sshDir, &old)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reported, gc.HasLen, 1)
+
+	s.writeKey(c, "ssh_host_ed25519_key.pub", "ssh-ed25519 ed-key root@host")
+	err = hostkeyreporter.DoWork(reporter, s.sshDir, &old)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reported, gc.HasLen, 2)
+	c.Assert(reported[1], jc.DeepEquals, []string{
+		"ssh-dss dsa-key root@host",
+		"ssh-ed25519 ed-key root@host",
+		"ssh-rsa rsa-key root@host",
+	})
+}
+
+func (s *HostKeyReporterSuite) TestNoKeys(c *gc.C) {
+	reporter := ReporterFunc(func(keys []string) error {
+		c.Fatalf("unexpected report %v", keys)
+		return nil
+	})
+	var old []string
+	err := hostkeyreporter.DoWork(reporter, c.MkDir(), &old)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *HostKeyReporterSuite) TestReportError(c *gc.C) {
+	reporter := ReporterFunc(func(keys []string) error {
+		return errors.New("boom")
+	})
+	var old []string
+	err := hostkeyreporter.DoWork(reporter, s.sshDir, &old)
+	c.Assert(err, gc.ErrorMatches, "cannot report SSH host keys: boom")
+	c.Assert(old, gc.HasLen, 0)
+}
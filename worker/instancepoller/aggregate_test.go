@@ -46,8 +46,8 @@ func (t *testInstance) Addresses() ([]network.Address, error) {
 	return t.addresses, nil
 }
 
-func (t *testInstance) Status() string {
-	return t.status
+func (t *testInstance) Status() instance.InstanceStatus {
+	return instance.InstanceStatus{Status: t.status}
 }
 
 type testInstanceGetter struct {
@@ -91,7 +91,7 @@ func (s *aggregateSuite) TestSingleRequest(c *gc.C) {
 	info, err := aggregator.instanceInfo("foo")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(info, gc.DeepEquals, instanceInfo{
-		status:    "foobar",
+		status:    instance.InstanceStatus{Status: "foobar"},
 		addresses: instance1.addresses,
 	})
 	c.Assert(testGetter.ids, gc.DeepEquals, []instance.Id{"foo"})
@@ -120,7 +120,7 @@ func (s *aggregateSuite) TestMultipleResponseHandling(c *gc.C) {
 	checkInfo := func(id instance.Id, expectStatus string) {
 		info, err := aggregator.instanceInfo(id)
 		c.Check(err, jc.ErrorIsNil)
-		c.Check(info.status, gc.Equals, expectStatus)
+		c.Check(info.status.Status, gc.Equals, expectStatus)
 		wg.Done()
 	}
 
@@ -55,7 +55,38 @@ func (s *machineSuite) TestSetsInstanceInfoInitially(c *gc.C) {
 	c.Assert(context.killAllErr, gc.Equals, nil)
 	c.Assert(m.addresses, gc.DeepEquals, testAddrs)
 	c.Assert(m.setAddressCount, gc.Equals, 1)
-	c.Assert(m.instStatus, gc.Equals, "running")
+	c.Assert(m.instStatus, jc.DeepEquals, instance.InstanceStatus{Status: "running"})
+}
+
+func (s *machineSuite) TestSetsInstanceStatusMessage(c *gc.C) {
+	status := instance.InstanceStatus{
+		Status:  "deploying",
+		Message: "waiting for deployment",
+	}
+	context := &testMachineContext{
+		getInstanceInfo: func(id instance.Id) (instanceInfo, error) {
+			c.Check(id, gc.Equals, instance.Id("i1234"))
+			return instanceInfo{testAddrs, status}, nil
+		},
+		dyingc: make(chan struct{}),
+	}
+	m := &testMachine{
+		tag:        names.NewMachineTag("99"),
+		instanceId: "i1234",
+		instStatus: instance.InstanceStatus{Status: "deploying"},
+		refresh:    func() error { return nil },
+		life:       params.Alive,
+	}
+	died := make(chan machine)
+	s.PatchValue(&ShortPoll, coretesting.ShortWait/10)
+	s.PatchValue(&LongPoll, coretesting.ShortWait/10)
+
+	go runMachine(context, m, nil, died)
+	time.Sleep(coretesting.ShortWait)
+
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+	c.Assert(m.instStatus, jc.DeepEquals, status)
 }
 
 func (s *machineSuite) TestShortPollIntervalWhenNoAddress(c *gc.C) {
@@ -109,6 +140,84 @@ func (s *machineSuite) TestLongPollIntervalWhenHasAllInstanceInfo(c *gc.C) {
 	c.Assert(count, jc.GreaterThan, 2)
 }
 
+func (s *machineSuite) TestLongPollIntervalExponent(c *gc.C) {
+	s.PatchValue(&ShortPoll, coretesting.LongWait)
+	s.PatchValue(&LongPoll, 1*time.Microsecond)
+	s.PatchValue(&LongPollBackoff, 2.0)
+	s.PatchValue(&MaxPoll, coretesting.LongWait)
+
+	// While the instance info is unchanged the long poll interval
+	// backs off just as the short one does.
+	maxCount := int(math.Log(float64(coretesting.ShortWait)/float64(LongPoll))/math.Log(LongPollBackoff) + 1)
+	count := countPolls(c, testAddrs, "i1234", "running", params.StatusStarted)
+	c.Assert(count, jc.GreaterThan, 2)
+	c.Assert(count, jc.LessThan, maxCount)
+	c.Logf("actual count: %v; max %v", count, maxCount)
+}
+
+func (s *machineSuite) TestLongPollIntervalMax(c *gc.C) {
+	s.PatchValue(&ShortPoll, coretesting.LongWait)
+	s.PatchValue(&LongPoll, 1*time.Microsecond)
+	s.PatchValue(&LongPollBackoff, 2.0)
+	s.PatchValue(&MaxPoll, 1*time.Millisecond)
+
+	// Backing off stops at MaxPoll, so there are more polls than
+	// unbounded backoff would allow.
+	maxCount := int(math.Log(float64(coretesting.ShortWait)/float64(LongPoll))/math.Log(LongPollBackoff) + 1)
+	count := countPolls(c, testAddrs, "i1234", "running", params.StatusStarted)
+	c.Assert(count, jc.GreaterThan, maxCount)
+}
+
+func (s *machineSuite) TestShortPollIntervalWhenBackedOffMachineLosesAddresses(c *gc.C) {
+	s.PatchValue(&ShortPoll, 1*time.Millisecond)
+	s.PatchValue(&ShortPollBackoff, 1.0)
+	s.PatchValue(&LongPoll, 1*time.Millisecond)
+	s.PatchValue(&LongPollBackoff, 20.0)
+	s.PatchValue(&MaxPoll, 20*time.Millisecond)
+
+	// The instance reports addresses for the first two polls, by
+	// which time the poll interval has backed off to MaxPoll, and
+	// none after that.
+	count := int32(0)
+	lost := make(chan struct{})
+	getInstanceInfo := func(id instance.Id) (instanceInfo, error) {
+		switch n := atomic.AddInt32(&count, 1); {
+		case n <= 2:
+			return instanceInfo{testAddrs, instance.InstanceStatus{Status: "running"}}, nil
+		case n == 3:
+			close(lost)
+		}
+		return instanceInfo{nil, instance.InstanceStatus{Status: "running"}}, nil
+	}
+	context := &testMachineContext{
+		getInstanceInfo: getInstanceInfo,
+		dyingc:          make(chan struct{}),
+	}
+	m := &testMachine{
+		tag:        names.NewMachineTag("99"),
+		instanceId: instance.Id("i1234"),
+		refresh:    func() error { return nil },
+		life:       params.Alive,
+		status:     params.StatusStarted,
+	}
+	died := make(chan machine)
+
+	go runMachine(context, m, nil, died)
+
+	select {
+	case <-lost:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("instance not polled after losing its addresses")
+	}
+	before := atomic.LoadInt32(&count)
+	time.Sleep(coretesting.ShortWait)
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+
+	// Polling at MaxPoll would give at most a few more polls.
+	c.Assert(int(atomic.LoadInt32(&count)-before), jc.GreaterThan, 5)
+}
+
 // countPolls sets up a machine loop with the given
 // addresses and status to be returned from getInstanceInfo,
 // waits for coretesting.ShortWait, and returns the
@@ -121,7 +230,7 @@ func countPolls(c *gc.C, addrs []network.Address, instId, instStatus string, mac
 		if addrs == nil {
 			return instanceInfo{}, fmt.Errorf("no instance addresses available")
 		}
-		return instanceInfo{addrs, instStatus}, nil
+		return instanceInfo{addrs, instance.InstanceStatus{Status: instStatus}}, nil
 	}
 	context := &testMachineContext{
 		getInstanceInfo: getInstanceInfo,
@@ -223,6 +332,47 @@ func (*machineSuite) TestChangedRefreshes(c *gc.C) {
 	c.Assert(m.addresses, gc.DeepEquals, testAddrs)
 }
 
+func (s *machineSuite) TestChangedPollsImmediately(c *gc.C) {
+	s.PatchValue(&ShortPoll, 1*time.Millisecond)
+	s.PatchValue(&LongPoll, coretesting.LongWait)
+	polled := make(chan struct{}, 10)
+	getInstanceInfo := func(id instance.Id) (instanceInfo, error) {
+		polled <- struct{}{}
+		return instanceInfo{testAddrs, instance.InstanceStatus{Status: "running"}}, nil
+	}
+	context := &testMachineContext{
+		getInstanceInfo: getInstanceInfo,
+		dyingc:          make(chan struct{}),
+	}
+	m := &testMachine{
+		tag:        names.NewMachineTag("99"),
+		instanceId: "i1234",
+		refresh:    func() error { return nil },
+		addresses:  testAddrs,
+		life:       params.Alive,
+		status:     params.StatusStarted,
+	}
+	died := make(chan machine)
+	changed := make(chan struct{})
+	go runMachine(context, m, changed, died)
+
+	// The machine has everything it needs, so after the first poll
+	// it waits for LongPoll; a change must cut that short.
+	select {
+	case <-polled:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("instance not polled")
+	}
+	changed <- struct{}{}
+	select {
+	case <-polled:
+	case <-time.After(coretesting.ShortWait):
+		c.Fatalf("instance not polled after change")
+	}
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+}
+
 var terminatingErrorsTests = []struct {
 	about  string
 	mutate func(m *testMachine, err error)
@@ -299,7 +449,7 @@ func instanceInfoGetter(
 
 	return func(id instance.Id) (instanceInfo, error) {
 		c.Check(id, gc.Equals, expectId)
-		return instanceInfo{addrs, status}, err
+		return instanceInfo{addrs, instance.InstanceStatus{Status: status}}, err
 	}
 }
 
@@ -328,7 +478,7 @@ type testMachine struct {
 	instanceId      instance.Id
 	instanceIdErr   error
 	tag             names.MachineTag
-	instStatus      string
+	instStatus      instance.InstanceStatus
 	status          params.Status
 	refresh         func() error
 	setAddressesErr error
@@ -378,16 +528,19 @@ func (m *testMachine) IsManual() (bool, error) {
 	return strings.HasPrefix(string(m.instanceId), "manual:"), nil
 }
 
-func (m *testMachine) InstanceStatus() (string, error) {
+func (m *testMachine) InstanceStatus() (params.StatusResult, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return m.instStatus, nil
+	return params.StatusResult{
+		Status: params.Status(m.instStatus.Status),
+		Info:   m.instStatus.Message,
+	}, nil
 }
 
-func (m *testMachine) SetInstanceStatus(status string) error {
+func (m *testMachine) SetInstanceStatus(status, message string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.instStatus = status
+	m.instStatus = instance.InstanceStatus{Status: status, Message: message}
 	return nil
 }
 
@@ -24,11 +24,15 @@ var logger = loggo.GetLogger("juju.worker.instancepoller")
 // with an exponent of ShortPollBackoff until a maximum(ish) of LongPoll.
 //
 // When a machine has an address and is started LongPoll will be used to
-// check that the instance address or status has not changed.
+// check that the instance address or status has not changed. While they
+// stay unchanged, the interval backs off with an exponent of
+// LongPollBackoff up to MaxPoll, and returns to LongPoll when they change.
 var (
 	ShortPoll        = 1 * time.Second
 	ShortPollBackoff = 2.0
 	LongPoll         = 15 * time.Minute
+	LongPollBackoff  = 2.0
+	MaxPoll          = 4 * time.Hour
 )
 
 type machine interface {
@@ -37,8 +41,8 @@ type machine interface {
 	InstanceId() (instance.Id, error)
 	ProviderAddresses() ([]network.Address, error)
 	SetProviderAddresses(...network.Address) error
-	InstanceStatus() (string, error)
-	SetInstanceStatus(status string) error
+	InstanceStatus() (params.StatusResult, error)
+	SetInstanceStatus(status, message string) error
 	String() string
 	Refresh() error
 	Life() params.Life
@@ -48,7 +52,7 @@ type machine interface {
 
 type instanceInfo struct {
 	addresses []network.Address
-	status    string
+	status    instance.InstanceStatus
 }
 
 type machineContext interface {
@@ -71,6 +75,9 @@ type machinesWatcher interface {
 type updaterContext interface {
 	newMachineContext() machineContext
 	getMachine(tag names.MachineTag) (machine, error)
+	// instanceChanges returns a channel on which the provider pushes
+	// the ids of changed instances, or nil if it cannot do so.
+	instanceChanges() <-chan []instance.Id
 	dying() <-chan struct{}
 }
 
@@ -78,6 +85,9 @@ type updater struct {
 	context     updaterContext
 	machines    map[names.MachineTag]chan struct{}
 	machineDead chan machine
+	// instances maps the ids of the instances of watched machines
+	// to the machines' tags, for dispatching pushed changes.
+	instances map[instance.Id]names.MachineTag
 }
 
 // watchMachinesLoop watches for changes provided by the given
@@ -89,6 +99,7 @@ func watchMachinesLoop(context updaterContext, w machinesWatcher) (err error) {
 		context:     context,
 		machines:    make(map[names.MachineTag]chan struct{}),
 		machineDead: make(chan machine),
+		instances:   make(map[instance.Id]names.MachineTag),
 	}
 	defer func() {
 		if stopErr := w.Stop(); stopErr != nil {
@@ -99,7 +110,7 @@ func watchMachinesLoop(context updaterContext, w machinesWatcher) (err error) {
 			}
 		}
 		for len(p.machines) > 0 {
-			delete(p.machines, (<-p.machineDead).Tag())
+			p.forgetMachine((<-p.machineDead).Tag())
 		}
 	}()
 	for {
@@ -115,14 +126,79 @@ func watchMachinesLoop(context updaterContext, w machinesWatcher) (err error) {
 			if err := p.startMachines(tags); err != nil {
 				return err
 			}
+		case ids := <-p.context.instanceChanges():
+			if err := p.notifyInstances(ids); err != nil {
+				return err
+			}
 		case m := <-p.machineDead:
-			delete(p.machines, m.Tag())
+			p.forgetMachine(m.Tag())
 		case <-p.context.dying():
 			return nil
 		}
 	}
 }
 
+// notifyInstances tells the goroutines of the machines with the given
+// instances that those instances have changed.
+func (p *updater) notifyInstances(ids []instance.Id) error {
+	for _, id := range ids {
+		tag, ok := p.instances[id]
+		if !ok {
+			// Machines are likely to have been provisioned since
+			// we last looked.
+			if err := p.mapInstances(); err != nil {
+				return err
+			}
+			if tag, ok = p.instances[id]; !ok {
+				logger.Debugf("ignoring change to unknown instance %q", id)
+				continue
+			}
+		}
+		if c := p.machines[tag]; c != nil {
+			c <- struct{}{}
+		}
+	}
+	return nil
+}
+
+// mapInstances records the instance ids of the watched machines that
+// are not already known.
+func (p *updater) mapInstances() error {
+	known := make(map[names.MachineTag]bool)
+	for _, tag := range p.instances {
+		known[tag] = true
+	}
+	for tag := range p.machines {
+		if known[tag] {
+			continue
+		}
+		m, err := p.context.getMachine(tag)
+		if params.IsCodeNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		id, err := m.InstanceId()
+		if params.IsCodeNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		p.instances[id] = tag
+	}
+	return nil
+}
+
+// forgetMachine removes all record of the machine with the given tag.
+func (p *updater) forgetMachine(tag names.MachineTag) {
+	delete(p.machines, tag)
+	for id, t := range p.instances {
+		if t == tag {
+			delete(p.instances, id)
+		}
+	}
+}
+
 func (p *updater) startMachines(tags []names.MachineTag) error {
 	for _, tag := range tags {
 		if c := p.machines[tag]; c == nil {
@@ -180,6 +256,8 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 	// has an address and the machine agent is started.
 	pollInterval := ShortPoll
 	pollInstance := true
+	longPolling := false
+	var lastInfo instanceInfo
 	for {
 		if pollInstance {
 			instInfo, err := pollInstanceInfo(context, m)
@@ -193,6 +271,7 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 				// so we won't need to worry about this case at all.
 				if params.IsCodeNotImplemented(err) {
 					pollInterval = 365 * 24 * time.Hour
+					longPolling = false
 				} else {
 					return err
 				}
@@ -205,14 +284,29 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 					machineStatus = statusInfo.Status
 				}
 			}
-			if len(instInfo.addresses) > 0 && instInfo.status != "" && machineStatus == params.StatusStarted {
-				// We've got at least one address and a status and instance is started, so poll infrequently.
-				pollInterval = LongPoll
+			if len(instInfo.addresses) > 0 && instInfo.status.Status != "" && machineStatus == params.StatusStarted {
+				// We've got at least one address and a status and instance is started, so poll infrequently,
+				// and increasingly rarely while nothing changes.
+				if longPolling && instanceInfoEqual(instInfo, lastInfo) {
+					pollInterval = time.Duration(float64(pollInterval) * LongPollBackoff)
+					if pollInterval > MaxPoll {
+						pollInterval = MaxPoll
+					}
+				} else {
+					pollInterval = LongPoll
+				}
+				longPolling = true
+			} else if longPolling {
+				// We had everything we needed but have lost it again,
+				// so go back to polling frequently.
+				pollInterval = ShortPoll
+				longPolling = false
 			} else if pollInterval < LongPoll {
 				// We have no addresses or not started - poll increasingly rarely
 				// until we do.
 				pollInterval = time.Duration(float64(pollInterval) * ShortPollBackoff)
 			}
+			lastInfo = instInfo
 			pollInstance = false
 		}
 		select {
@@ -227,6 +321,12 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 			if m.Life() == params.Dead {
 				return nil
 			}
+			// Something has changed, so whatever we learned by
+			// backing off no longer holds: poll now, and often
+			// until the instance settles again.
+			pollInterval = ShortPoll
+			longPolling = false
+			pollInstance = true
 		}
 	}
 }
@@ -256,11 +356,11 @@ func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo,
 		// This should never occur since the machine is provisioned.
 		// But just in case, we reset polled status so we try again next time.
 		logger.Warningf("cannot get current instance status for machine %v: %v", m.Id(), err)
-		instInfo.status = ""
+		instInfo.status = instance.InstanceStatus{}
 	} else {
-		if instInfo.status != currentInstStatus {
-			logger.Infof("machine %q instance status changed from %q to %q", m.Id(), currentInstStatus, instInfo.status)
-			if err = m.SetInstanceStatus(instInfo.status); err != nil {
+		if instInfo.status.Status != string(currentInstStatus.Status) || instInfo.status.Message != currentInstStatus.Info {
+			logger.Infof("machine %q instance status changed from %q to %q", m.Id(), currentInstStatus.Status, instInfo.status.Status)
+			if err = m.SetInstanceStatus(instInfo.status.Status, instInfo.status.Message); err != nil {
 				logger.Errorf("cannot set instance status on %q: %v", m, err)
 			}
 		}
@@ -278,6 +378,12 @@ func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo,
 	return instInfo, err
 }
 
+// instanceInfoEqual reports whether two polls of an instance found the
+// same addresses and status.
+func instanceInfoEqual(a, b instanceInfo) bool {
+	return a.status == b.status && addressesEqual(a.addresses, b.addresses)
+}
+
 // addressesEqual compares the addresses of the machine and the instance information.
 func addressesEqual(a0, a1 []network.Address) bool {
 	if len(a0) != len(a1) {
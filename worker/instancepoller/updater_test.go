@@ -14,6 +14,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -175,9 +176,69 @@ func (s *updaterSuite) TestManualMachinesIgnored(c *gc.C) {
 	c.Assert(watcher.stopped, jc.IsTrue)
 }
 
+func (*updaterSuite) TestInstanceChangesRefreshMachine(c *gc.C) {
+	refreshc := make(chan struct{}, 1)
+	m := &testMachine{
+		tag:        names.NewMachineTag("99"),
+		instanceId: "i1234",
+		life:       params.Alive,
+		refresh: func() error {
+			refreshc <- struct{}{}
+			return nil
+		},
+	}
+	dyingc := make(chan struct{})
+	context := &testUpdaterContext{
+		dyingc:  dyingc,
+		changes: make(chan []instance.Id),
+		newMachineContextFunc: func() machineContext {
+			return &testMachineContext{
+				getInstanceInfo: instanceInfoGetter(c, "i1234", testAddrs, "running", nil),
+				dyingc:          dyingc,
+			}
+		},
+		getMachineFunc: func(tag names.MachineTag) (machine, error) {
+			c.Check(tag, jc.DeepEquals, m.tag)
+			return m, nil
+		},
+	}
+	watcher := &testMachinesWatcher{
+		changes: make(chan []string),
+	}
+	done := make(chan error)
+	go func() {
+		done <- watchMachinesLoop(context, watcher)
+	}()
+	watcher.changes <- []string{"99"}
+
+	// A change to an unknown instance is ignored; one to the
+	// machine's instance is passed on to its poller.
+	context.changes <- []instance.Id{"i9999"}
+	select {
+	case <-refreshc:
+		c.Fatalf("machine refreshed for another instance's change")
+	case <-time.After(coretesting.ShortWait):
+	}
+	context.changes <- []instance.Id{"i1234"}
+	select {
+	case <-refreshc:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for machine to be refreshed")
+	}
+
+	close(context.dyingc)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for watchMachinesLoop to terminate")
+	}
+}
+
 type testUpdaterContext struct {
 	newMachineContextFunc func() machineContext
 	getMachineFunc        func(tag names.MachineTag) (machine, error)
+	changes               chan []instance.Id
 	dyingc                chan struct{}
 }
 
@@ -189,6 +250,10 @@ func (context *testUpdaterContext) getMachine(tag names.MachineTag) (machine, er
 	return context.getMachineFunc(tag)
 }
 
+func (context *testUpdaterContext) instanceChanges() <-chan []instance.Id {
+	return context.changes
+}
+
 func (context *testUpdaterContext) dying() <-chan struct{} {
 	return context.dyingc
 }
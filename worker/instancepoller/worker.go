@@ -9,6 +9,8 @@ import (
 
 	apiinstancepoller "github.com/juju/juju/api/instancepoller"
 	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/worker"
 )
 
@@ -18,6 +20,7 @@ type updaterWorker struct {
 	*aggregator
 
 	observer *worker.EnvironObserver
+	changes  <-chan []instance.Id
 }
 
 // NewWorker returns a worker that keeps track of
@@ -56,6 +59,13 @@ func (u *updaterWorker) loop() (err error) {
 			err = obsErr
 		}
 	}()
+	if notifier, ok := u.observer.Environ().(environs.InstanceChangeNotifier); ok {
+		u.changes, err = notifier.WatchInstanceChanges(u.tomb.Dying())
+		if err != nil {
+			return err
+		}
+		logger.Infof("instance poller receiving instance changes from the provider")
+	}
 	var w apiwatcher.StringsWatcher
 	w, err = u.st.WatchEnvironMachines()
 	if err != nil {
@@ -72,6 +82,10 @@ func (u *updaterWorker) getMachine(tag names.MachineTag) (machine, error) {
 	return u.st.Machine(tag)
 }
 
+func (u *updaterWorker) instanceChanges() <-chan []instance.Id {
+	return u.changes
+}
+
 func (u *updaterWorker) dying() <-chan struct{} {
 	return u.tomb.Dying()
 }
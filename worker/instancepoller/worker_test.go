@@ -72,7 +72,7 @@ func (s *workerSuite) TestWorker(c *gc.C) {
 		}
 		providerAddresses, err := m.ProviderAddresses()
 		c.Assert(err, jc.ErrorIsNil)
-		return reflect.DeepEqual(providerAddresses, s.addressesForIndex(index)) && (!isProvisioned || status == expectedStatus)
+		return reflect.DeepEqual(providerAddresses, s.addressesForIndex(index)) && (!isProvisioned || status.Status == params.Status(expectedStatus))
 	}
 
 	// Wait for the odd numbered machines in the
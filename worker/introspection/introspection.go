@@ -0,0 +1,189 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package introspection implements a worker that serves information
+// about the running agent over a local unix socket, so that an agent
+// that appears stuck can be diagnosed without restarting it.
+//
+// The socket is in the Linux abstract namespace, so it leaves nothing
+// behind in the file system when the agent stops. It serves:
+//
+//	/debug/pprof/     the standard Go runtime profiles
+//	/goroutines       a dump of the stacks of all goroutines
+//	/workers          the state of the agent's workers
+//	/api-addresses    the API server addresses, in the order they are tried
+package introspection
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	goyaml "gopkg.in/yaml.v2"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.introspection")
+
+// Reporter describes the state of the agent's workers. It is
+// implemented by worker runners and dependency engines.
+type Reporter interface {
+	Report() map[string]interface{}
+}
+
+// AgentConfigGetter provides the agent's current configuration.
+type AgentConfigGetter interface {
+	CurrentConfig() agent.Config
+}
+
+// Config holds the configuration for an introspection worker.
+type Config struct {
+	// SocketName holds the name of the abstract unix socket to
+	// listen on, without the leading "@".
+	SocketName string
+
+	// Reporter, if not nil, is used to describe the agent's
+	// workers.
+	Reporter Reporter
+
+	// Agent, if not nil, is used to describe the agent's API
+	// server addresses.
+	Agent AgentConfigGetter
+}
+
+// Validate returns an error if the config cannot be used to start a
+// worker.
+func (config Config) Validate() error {
+	if config.SocketName == "" {
+		return errors.NotValidf("empty SocketName")
+	}
+	return nil
+}
+
+type introspectionWorker struct {
+	tomb     tomb.Tomb
+	config   Config
+	listener net.Listener
+}
+
+// NewWorker returns a worker that serves introspection requests on the
+// configured socket until it is killed.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	listener, err := net.Listen("unix", "@"+config.SocketName)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot listen on introspection socket")
+	}
+	logger.Debugf("introspection socket listening on @%s", config.SocketName)
+	w := &introspectionWorker{
+		config:   config,
+		listener: listener,
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *introspectionWorker) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *introspectionWorker) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *introspectionWorker) loop() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/goroutines", goroutines)
+	mux.HandleFunc("/workers", w.workers)
+	mux.HandleFunc("/api-addresses", w.apiAddresses)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- http.Serve(w.listener, mux)
+	}()
+	select {
+	case <-w.tomb.Dying():
+		w.listener.Close()
+		<-served
+		return tomb.ErrDying
+	case err := <-served:
+		return errors.Annotate(err, "introspection server failed")
+	}
+}
+
+// goroutines writes the stacks of all the agent's goroutines.
+func goroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	runtimepprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// workers writes the state of the agent's workers, as YAML.
+func (w *introspectionWorker) workers(resp http.ResponseWriter, r *http.Request) {
+	if w.config.Reporter == nil {
+		http.Error(resp, "no worker report available", http.StatusNotFound)
+		return
+	}
+	data, err := goyaml.Marshal(w.config.Reporter.Report())
+	if err != nil {
+		http.Error(resp, fmt.Sprintf("cannot marshal report: %v", err), http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	resp.Write(data)
+}
+
+// apiAddress describes one of the agent's API server addresses.
+type apiAddress struct {
+	Address       string `yaml:"address"`
+	LastConnected string `yaml:"last-connected,omitempty"`
+}
+
+// apiAddresses writes the agent's API server addresses, as YAML, in
+// the order the agent tries them when connecting, along with the time
+// of the last successful connection to each.
+func (w *introspectionWorker) apiAddresses(resp http.ResponseWriter, r *http.Request) {
+	if w.config.Agent == nil {
+		http.Error(resp, "no agent configuration available", http.StatusNotFound)
+		return
+	}
+	config := w.config.Agent.CurrentConfig()
+	addrs, err := config.APIAddresses()
+	if err != nil {
+		http.Error(resp, fmt.Sprintf("cannot get API addresses: %v", err), http.StatusInternalServerError)
+		return
+	}
+	lastConnected := config.APIAddressesLastConnected()
+	report := make([]apiAddress, len(addrs))
+	for i, addr := range addrs {
+		report[i].Address = addr
+		if when, ok := lastConnected[addr]; ok {
+			report[i].LastConnected = when.UTC().Format(time.RFC3339)
+		}
+	}
+	data, err := goyaml.Marshal(report)
+	if err != nil {
+		http.Error(resp, fmt.Sprintf("cannot marshal report: %v", err), http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	resp.Write(data)
+}
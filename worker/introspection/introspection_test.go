@@ -0,0 +1,157 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/introspection"
+)
+
+type IntrospectionSuite struct {
+	testing.BaseSuite
+	name   string
+	worker worker.Worker
+}
+
+var _ = gc.Suite(&IntrospectionSuite{})
+
+type fakeReporter struct{}
+
+func (*fakeReporter) Report() map[string]interface{} {
+	return map[string]interface{}{
+		"api": map[string]interface{}{"state": "started"},
+	}
+}
+
+type fakeAgent struct{}
+
+func (*fakeAgent) CurrentConfig() agent.Config {
+	return &fakeConfig{}
+}
+
+type fakeConfig struct {
+	agent.Config
+}
+
+func (*fakeConfig) APIAddresses() ([]string, error) {
+	return []string{"0.1.2.5:1234", "0.1.2.4:1234", "0.1.2.3:1234"}, nil
+}
+
+func (*fakeConfig) APIAddressesLastConnected() map[string]time.Time {
+	t0 := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	return map[string]time.Time{
+		"0.1.2.4:1234": t0,
+		"0.1.2.5:1234": t0.Add(time.Minute),
+	}
+}
+
+func (s *IntrospectionSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("abstract unix sockets are only supported on linux")
+	}
+	s.BaseSuite.SetUpTest(c)
+	s.name = fmt.Sprintf("introspection-test-%d", os.Getpid())
+	w, err := introspection.NewWorker(introspection.Config{
+		SocketName: s.name,
+		Reporter:   &fakeReporter{},
+		Agent:      &fakeAgent{},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.worker = w
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(w), jc.ErrorIsNil)
+	})
+}
+
+func (s *IntrospectionSuite) get(c *gc.C, path string) (int, string) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			Dial: func(network, addr string) (net.Conn, error) {
+				return net.Dial("unix", "@"+s.name)
+			},
+		},
+	}
+	resp, err := client.Get("http://introspection" + path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp.StatusCode, string(body)
+}
+
+func (s *IntrospectionSuite) TestConfigValidate(c *gc.C) {
+	_, err := introspection.NewWorker(introspection.Config{})
+	c.Assert(err, gc.ErrorMatches, "empty SocketName not valid")
+}
+
+func (s *IntrospectionSuite) TestWorkers(c *gc.C) {
+	code, body := s.get(c, "/workers")
+	c.Assert(code, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, "api:\n  state: started\n")
+}
+
+func (s *IntrospectionSuite) TestWorkersNoReporter(c *gc.C) {
+	c.Assert(worker.Stop(s.worker), jc.ErrorIsNil)
+	w, err := introspection.NewWorker(introspection.Config{SocketName: s.name})
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	code, body := s.get(c, "/workers")
+	c.Assert(code, gc.Equals, http.StatusNotFound)
+	c.Assert(body, gc.Equals, "no worker report available\n")
+}
+
+func (s *IntrospectionSuite) TestAPIAddresses(c *gc.C) {
+	code, body := s.get(c, "/api-addresses")
+	c.Assert(code, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, `
+- address: 0.1.2.5:1234
+  last-connected: 2015-10-01T12:01:00Z
+- address: 0.1.2.4:1234
+  last-connected: 2015-10-01T12:00:00Z
+- address: 0.1.2.3:1234
+`[1:])
+}
+
+func (s *IntrospectionSuite) TestAPIAddressesNoAgent(c *gc.C) {
+	c.Assert(worker.Stop(s.worker), jc.ErrorIsNil)
+	w, err := introspection.NewWorker(introspection.Config{SocketName: s.name})
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	code, body := s.get(c, "/api-addresses")
+	c.Assert(code, gc.Equals, http.StatusNotFound)
+	c.Assert(body, gc.Equals, "no agent configuration available\n")
+}
+
+func (s *IntrospectionSuite) TestGoroutines(c *gc.C) {
+	code, body := s.get(c, "/goroutines")
+	c.Assert(code, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Matches, `(?s)goroutine \d+ \[running\]:.*`)
+}
+
+func (s *IntrospectionSuite) TestProfiles(c *gc.C) {
+	code, body := s.get(c, "/debug/pprof/")
+	c.Assert(code, gc.Equals, http.StatusOK)
+	c.Assert(body, jc.Contains, "goroutine")
+}
+
+func (s *IntrospectionSuite) TestStop(c *gc.C) {
+	c.Assert(worker.Stop(s.worker), jc.ErrorIsNil)
+	_, err := net.Dial("unix", "@"+s.name)
+	c.Assert(err, gc.NotNil)
+}
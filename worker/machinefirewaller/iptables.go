@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+)
+
+// chainName is the name of the iptables chain holding the rules
+// managed by Juju. It is jumped to from the INPUT chain.
+const chainName = "juju-firewall"
+
+// runIPTables runs the given iptables (or ip6tables) command.
+var runIPTables = func(command string, args ...string) error {
+	out, err := exec.Command(command, args...).CombinedOutput()
+	if err != nil {
+		return errors.Annotatef(err, "%s %s: %s", command, strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// IPTables is a Firewall which programs the local machine's IPv4 and
+// IPv6 firewalls using iptables and ip6tables. Incoming connections
+// are dropped unless they are for an allowed port range, SSH, or one
+// of AlwaysOpen, or arrive on the loopback or a trusted interface.
+type IPTables struct {
+	// AlwaysOpen holds port ranges that are allowed regardless of
+	// the ports opened by units, such as the API server port on
+	// state servers.
+	AlwaysOpen []network.PortRange
+
+	// TrustedInterfaces holds the names of network interfaces, such
+	// as container bridges, on which all incoming traffic is allowed.
+	TrustedInterfaces []string
+}
+
+var _ Firewall = (*IPTables)(nil)
+
+// SetAllowedPorts is part of the Firewall interface.
+func (fw *IPTables) SetAllowedPorts(ports []network.PortRange) error {
+	for _, command := range []string{"iptables", "ip6tables"} {
+		if err := fw.setAllowedPorts(command, ports); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (fw *IPTables) setAllowedPorts(command string, ports []network.PortRange) error {
+	if err := runIPTables(command, "-n", "-L", chainName); err != nil {
+		if err := runIPTables(command, "-N", chainName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := runIPTables(command, "-F", chainName); err != nil {
+		return errors.Trace(err)
+	}
+	for _, rule := range fw.rules(command == "ip6tables", ports) {
+		args := append([]string{"-A", chainName}, rule...)
+		if err := runIPTables(command, args...); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := runIPTables(command, "-C", "INPUT", "-j", chainName); err != nil {
+		return errors.Trace(runIPTables(command, "-I", "INPUT", "-j", chainName))
+	}
+	return nil
+}
+
+// rules returns the arguments for each rule to append to the Juju
+// chain, in order.
+func (fw *IPTables) rules(ipv6 bool, ports []network.PortRange) [][]string {
+	icmp := "icmp"
+	if ipv6 {
+		icmp = "ipv6-icmp"
+	}
+	rules := [][]string{
+		{"-i", "lo", "-j", "ACCEPT"},
+		{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT"},
+		{"-p", icmp, "-j", "ACCEPT"},
+		{"-p", "tcp", "--dport", "22", "-j", "ACCEPT"},
+	}
+	for _, name := range fw.TrustedInterfaces {
+		rules = append(rules, []string{"-i", name, "-j", "ACCEPT"})
+	}
+	allowed := append(append([]network.PortRange{}, fw.AlwaysOpen...), ports...)
+	for _, portRange := range allowed {
+		protocol := strings.ToLower(portRange.Protocol)
+		if protocol == "icmp" {
+			// ICMP is always allowed.
+			continue
+		}
+		dport := fmt.Sprint(portRange.FromPort)
+		if portRange.ToPort > portRange.FromPort {
+			dport = fmt.Sprintf("%d:%d", portRange.FromPort, portRange.ToPort)
+		}
+		rules = append(rules, []string{"-p", protocol, "--dport", dport, "-j", "ACCEPT"})
+	}
+	return append(rules, []string{"-j", "DROP"})
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller_test
+
+import (
+	"errors"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/machinefirewaller"
+)
+
+type IPTablesSuite struct {
+	coretesting.BaseSuite
+	commands []string
+	failing  map[string]bool
+}
+
+var _ = gc.Suite(&IPTablesSuite{})
+
+func (s *IPTablesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.commands = nil
+	s.failing = make(map[string]bool)
+	s.PatchValue(machinefirewaller.RunIPTables, func(command string, args ...string) error {
+		cmd := command + " " + strings.Join(args, " ")
+		s.commands = append(s.commands, cmd)
+		if s.failing[cmd] {
+			return errors.New("failed")
+		}
+		return nil
+	})
+}
+
+func (s *IPTablesSuite) TestSetAllowedPorts(c *gc.C) {
+	s.failing["iptables -n -L juju-firewall"] = true
+	s.failing["iptables -C INPUT -j juju-firewall"] = true
+	fw := &machinefirewaller.IPTables{
+		AlwaysOpen:        []network.PortRange{{FromPort: 17070, ToPort: 17070, Protocol: "tcp"}},
+		TrustedInterfaces: []string{"lxcbr0"},
+	}
+	err := fw.SetAllowedPorts([]network.PortRange{
+		{FromPort: 80, ToPort: 81, Protocol: "tcp"},
+		{FromPort: 53, ToPort: 53, Protocol: "udp"},
+		{FromPort: -1, ToPort: -1, Protocol: "icmp"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	rules := func(command, icmp string) []string {
+		return []string{
+			command + " -A juju-firewall -i lo -j ACCEPT",
+			command + " -A juju-firewall -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT",
+			command + " -A juju-firewall -p " + icmp + " -j ACCEPT",
+			command + " -A juju-firewall -p tcp --dport 22 -j ACCEPT",
+			command + " -A juju-firewall -i lxcbr0 -j ACCEPT",
+			command + " -A juju-firewall -p tcp --dport 17070 -j ACCEPT",
+			command + " -A juju-firewall -p tcp --dport 80:81 -j ACCEPT",
+			command + " -A juju-firewall -p udp --dport 53 -j ACCEPT",
+			command + " -A juju-firewall -j DROP",
+		}
+	}
+	var expected []string
+	expected = append(expected,
+		"iptables -n -L juju-firewall",
+		"iptables -N juju-firewall",
+		"iptables -F juju-firewall",
+	)
+	expected = append(expected, rules("iptables", "icmp")...)
+	expected = append(expected,
+		"iptables -C INPUT -j juju-firewall",
+		"iptables -I INPUT -j juju-firewall",
+		"ip6tables -n -L juju-firewall",
+		"ip6tables -F juju-firewall",
+	)
+	expected = append(expected, rules("ip6tables", "ipv6-icmp")...)
+	expected = append(expected, "ip6tables -C INPUT -j juju-firewall")
+	c.Assert(s.commands, jc.DeepEquals, expected)
+}
+
+func (s *IPTablesSuite) TestSetAllowedPortsError(c *gc.C) {
+	s.failing["iptables -F juju-firewall"] = true
+	fw := &machinefirewaller.IPTables{}
+	err := fw.SetAllowedPorts(nil)
+	c.Assert(err, gc.ErrorMatches, "failed")
+	c.Assert(s.commands, jc.DeepEquals, []string{
+		"iptables -n -L juju-firewall",
+		"iptables -F juju-firewall",
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machinefirewaller implements a worker that programs the
+// host firewall of the machine it runs on, when the environment's
+// firewall-mode is "machine". It is intended for clouds which offer no
+// security groups, so that the environment firewaller cannot restrict
+// access to machines itself.
+package machinefirewaller
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.machinefirewaller")
+
+// pollInterval is the time between unprompted checks of the port
+// ranges to allow. Changes to the opened ports are reported by a
+// watcher, but exposing or unexposing a service is not, so those are
+// only picked up when polling.
+var pollInterval = time.Minute
+
+// Facade is an interface that is supplied to NewWorker for reading the
+// port ranges that should be allowed on the local machine.
+type Facade interface {
+	WatchOpenedPorts() (apiwatcher.NotifyWatcher, error)
+	OpenedPorts() ([]network.PortRange, error)
+}
+
+// Firewall is an interface that is supplied to NewWorker for
+// programming the firewall of the local machine.
+type Firewall interface {
+	// SetAllowedPorts replaces the port ranges allowed through the
+	// firewall with those given.
+	SetAllowedPorts(ports []network.PortRange) error
+}
+
+type machineFirewaller struct {
+	tomb     tomb.Tomb
+	facade   Facade
+	firewall Firewall
+}
+
+// NewWorker returns a worker that keeps the given firewall allowing
+// only the port ranges opened by exposed services on the local machine.
+func NewWorker(facade Facade, firewall Firewall) worker.Worker {
+	w := &machineFirewaller{
+		facade:   facade,
+		firewall: firewall,
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *machineFirewaller) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *machineFirewaller) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *machineFirewaller) loop() error {
+	portsWatcher, err := w.facade.WatchOpenedPorts()
+	if err != nil {
+		return errors.Annotate(err, "cannot watch opened ports")
+	}
+	defer watcher.Stop(portsWatcher, &w.tomb)
+
+	var allowed []network.PortRange
+	applied := false
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-portsWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(portsWatcher)
+			}
+		case <-time.After(pollInterval):
+		}
+		ports, err := w.facade.OpenedPorts()
+		if err != nil {
+			return errors.Annotate(err, "cannot get opened ports")
+		}
+		if applied && reflect.DeepEqual(ports, allowed) {
+			continue
+		}
+		logger.Infof("allowing port ranges %v", ports)
+		if err := w.firewall.SetAllowedPorts(ports); err != nil {
+			return errors.Annotate(err, "cannot program firewall")
+		}
+		allowed, applied = ports, true
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/tomb"
+
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/machinefirewaller"
+)
+
+type MachineFirewallerSuite struct {
+	coretesting.BaseSuite
+	facade   *mockFacade
+	firewall *mockFirewall
+}
+
+var _ = gc.Suite(&MachineFirewallerSuite{})
+
+func (s *MachineFirewallerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(machinefirewaller.PollInterval, coretesting.LongWait)
+	s.facade = &mockFacade{
+		watcher: newMockWatcher(),
+	}
+	s.firewall = &mockFirewall{allowed: make(chan []network.PortRange, 1)}
+}
+
+func (s *MachineFirewallerSuite) startWorker(c *gc.C) worker.Worker {
+	w := machinefirewaller.NewWorker(s.facade, s.firewall)
+	s.AddCleanup(func(c *gc.C) {
+		w.Kill()
+		w.Wait()
+	})
+	return w
+}
+
+func (s *MachineFirewallerSuite) assertAllowed(c *gc.C, expected []network.PortRange) {
+	select {
+	case allowed := <-s.firewall.allowed:
+		c.Assert(allowed, jc.DeepEquals, expected)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for firewall to be programmed")
+	}
+}
+
+func (s *MachineFirewallerSuite) assertNotAllowed(c *gc.C) {
+	select {
+	case allowed := <-s.firewall.allowed:
+		c.Fatalf("unexpected firewall change: %v", allowed)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *MachineFirewallerSuite) TestProgramsFirewallOnChanges(c *gc.C) {
+	s.startWorker(c)
+
+	http := []network.PortRange{{FromPort: 80, ToPort: 80, Protocol: "tcp"}}
+	s.facade.setPorts(http)
+	s.facade.watcher.changes <- struct{}{}
+	s.assertAllowed(c, http)
+
+	// No change to the allowed ports, so the firewall is left alone.
+	s.facade.setPorts(http)
+	s.facade.watcher.changes <- struct{}{}
+	s.assertNotAllowed(c)
+
+	s.facade.setPorts(nil)
+	s.facade.watcher.changes <- struct{}{}
+	s.assertAllowed(c, nil)
+}
+
+func (s *MachineFirewallerSuite) TestPollsForChanges(c *gc.C) {
+	s.PatchValue(machinefirewaller.PollInterval, time.Millisecond)
+	http := []network.PortRange{{FromPort: 80, ToPort: 80, Protocol: "tcp"}}
+	s.facade.setPorts(http)
+	s.startWorker(c)
+	s.assertAllowed(c, http)
+}
+
+func (s *MachineFirewallerSuite) TestFirewallError(c *gc.C) {
+	s.firewall.err = errors.New("boom")
+	w := s.startWorker(c)
+	s.facade.setPorts(nil)
+	s.facade.watcher.changes <- struct{}{}
+	s.assertAllowed(c, nil)
+	c.Assert(w.Wait(), gc.ErrorMatches, "cannot program firewall: boom")
+}
+
+type mockFacade struct {
+	mu      sync.Mutex
+	watcher *mockWatcher
+	ports   []network.PortRange
+}
+
+func (f *mockFacade) setPorts(ports []network.PortRange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ports = ports
+}
+
+func (f *mockFacade) WatchOpenedPorts() (apiwatcher.NotifyWatcher, error) {
+	return f.watcher, nil
+}
+
+func (f *mockFacade) OpenedPorts() ([]network.PortRange, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ports, nil
+}
+
+type mockFirewall struct {
+	allowed chan []network.PortRange
+	err     error
+}
+
+func (f *mockFirewall) SetAllowedPorts(ports []network.PortRange) error {
+	f.allowed <- ports
+	return f.err
+}
+
+type mockWatcher struct {
+	tomb    tomb.Tomb
+	changes chan struct{}
+}
+
+func newMockWatcher() *mockWatcher {
+	w := &mockWatcher{changes: make(chan struct{})}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *mockWatcher) Stop() error {
+	w.tomb.Kill(nil)
+	return w.tomb.Wait()
+}
+
+func (w *mockWatcher) Err() error {
+	return w.tomb.Err()
+}
@@ -24,6 +24,11 @@ type Machiner struct {
 	tag                    names.MachineTag
 	machine                Machine
 	ignoreAddressesOnStart bool
+
+	// stopping records whether the machine's status has been set to
+	// stopped, and stoppedInfo the status message last set with it.
+	stopping    bool
+	stoppedInfo string
 }
 
 // NewMachiner returns a Worker that will wait for the identified machine
@@ -112,28 +117,45 @@ func (mr *Machiner) Handle(_ <-chan struct{}) error {
 		return nil
 	}
 	logger.Debugf("%q is now %s", mr.tag, life)
-	if err := mr.machine.SetStatus(params.StatusStopped, "", nil); err != nil {
-		return errors.Annotatef(err, "%s failed to set status stopped", mr.tag)
+	if !mr.stopping {
+		if err := mr.setStopped(""); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	// Attempt to mark the machine Dead. If the machine still has units
 	// assigned, or storage attached, this will fail with
 	// CodeHasAssignedUnits or CodeMachineHasAttachedStorage respectively.
 	// Once units or storage are removed, the watcher will trigger again
-	// and we'll reattempt.
+	// and we'll reattempt; meanwhile the machine's status says what it
+	// is waiting for.
 	if err := mr.machine.EnsureDead(); err != nil {
 		if params.IsCodeHasAssignedUnits(err) {
-			return nil
+			return mr.setStopped("waiting for units to be removed")
 		}
 		if params.IsCodeMachineHasAttachedStorage(err) {
 			logger.Tracef("machine still has storage attached")
-			return nil
+			return mr.setStopped("waiting for storage to be detached")
 		}
 		return errors.Annotatef(err, "%s failed to set machine to dead", mr.tag)
 	}
 	return worker.ErrTerminateAgent
 }
 
+// setStopped sets the machine's status to stopped with the given
+// message, unless it has already been set so.
+func (mr *Machiner) setStopped(info string) error {
+	if mr.stopping && mr.stoppedInfo == info {
+		return nil
+	}
+	if err := mr.machine.SetStatus(params.StatusStopped, info, nil); err != nil {
+		return errors.Annotatef(err, "%s failed to set status stopped", mr.tag)
+	}
+	mr.stopping = true
+	mr.stoppedInfo = info
+	return nil
+}
+
 func (mr *Machiner) TearDown() error {
 	// Nothing to do here.
 	return nil
@@ -63,6 +63,7 @@ func (s *MachinerSuite) TestMachinerStorageAttached(c *gc.C) {
 		nil, // Refresh
 		nil, // SetStatus
 		&params.Error{Code: params.CodeMachineHasAttachedStorage},
+		nil, // SetStatus
 	)
 
 	worker := machiner.NewMachiner(s.accessor, s.agentConfig, false)
@@ -109,6 +110,13 @@ func (s *MachinerSuite) TestMachinerStorageAttached(c *gc.C) {
 		},
 	}, {
 		FuncName: "EnsureDead",
+	}, {
+		FuncName: "SetStatus",
+		Args: []interface{}{
+			params.StatusStopped,
+			"waiting for storage to be detached",
+			map[string]interface{}(nil),
+		},
 	}})
 }
 
@@ -175,6 +183,24 @@ func (s *MachinerStateSuite) waitMachineStatus(c *gc.C, m *state.Machine, expect
 	}
 }
 
+func (s *MachinerStateSuite) waitMachineStatusInfo(c *gc.C, m *state.Machine, expectInfo string) {
+	timeout := time.After(worstCase)
+	for {
+		select {
+		case <-timeout:
+			c.Fatalf("timeout while waiting for machine status info to change")
+		case <-time.After(10 * time.Millisecond):
+			statusInfo, err := m.Status()
+			c.Assert(err, jc.ErrorIsNil)
+			if statusInfo.Message != expectInfo {
+				c.Logf("machine %q status info is %q, still waiting", m, statusInfo.Message)
+				continue
+			}
+			return
+		}
+	}
+}
+
 var _ worker.NotifyWatchHandler = (*machiner.Machiner)(nil)
 
 type mockConfig struct {
@@ -267,6 +293,9 @@ func (s *MachinerStateSuite) TestSetDeadWithDyingUnit(c *gc.C) {
 	c.Assert(s.machine.Refresh(), gc.IsNil)
 	c.Assert(s.machine.Life(), gc.Equals, state.Dying)
 
+	// The machine's status reports that it is waiting for the unit.
+	s.waitMachineStatusInfo(c, s.machine, "waiting for units to be removed")
+
 	// When the unit is ultimately destroyed, the machine becomes dead.
 	err = unit.Destroy()
 	c.Assert(err, jc.ErrorIsNil)
@@ -23,6 +23,7 @@ func (*dummyPaths) GetToolsDir() string        { return "/dummy/tools" }
 func (*dummyPaths) GetCharmDir() string        { return "/dummy/charm" }
 func (*dummyPaths) GetJujucSocket() string     { return "/dummy/jujuc.sock" }
 func (*dummyPaths) GetMetricsSpoolDir() string { return "/dummy/spool" }
+func (*dummyPaths) GetProcessesFile() string   { return "/dummy/processes" }
 
 func (s *ContextSuite) TestHookContextEnv(c *gc.C) {
 	ctx := meterstatus.NewLimitedContext("u/0")
@@ -42,24 +42,20 @@ func desiredPeerGroup(info *peerGroupInfo) ([]replicaset.Member, map[*machine]bo
 
 	// We may find extra peer group members if the machines
 	// have been removed or their state server status removed.
-	// This should only happen if they had been set to non-voting
-	// before removal, in which case we want to remove it
-	// from the members list. If we find a member that's still configured
-	// to vote, it's an error.
-	// TODO There are some other possibilities
-	// for what to do in that case.
-	// 1) leave them untouched, but deal
-	// with others as usual "i didn't see that bit"
-	// 2) leave them untouched, deal with others,
-	// but make sure the extras aren't eligible to
-	// be primary.
-	// 3) remove them "get rid of bad rubbish"
-	// 4) do nothing "nothing to see here"
+	// Extra members that have already been set to non-voting
+	// are simply removed from the members list. Members that
+	// are still voting, for example because their machine was
+	// removed before the worker had demoted it, are removed
+	// too, but only along with another change that keeps the
+	// total number of votes odd; any that cannot be paired in
+	// that way are left untouched until they can be.
+	var orphans []replicaset.Member
 	for _, member := range extra {
-		if member.Votes == nil || *member.Votes > 0 {
-			return nil, nil, fmt.Errorf("voting non-machine member %#v found in peer group", member)
+		if !isVotingMember(&member) {
+			changed = true
+			continue
 		}
-		changed = true
+		orphans = append(orphans, member)
 	}
 
 	toRemoveVote, toAddVote, toKeep := possiblePeerGroupChanges(info, members)
@@ -76,6 +72,11 @@ func desiredPeerGroup(info *peerGroupInfo) ([]replicaset.Member, map[*machine]bo
 		machineVoting[m] = voting
 		changed = true
 	}
+	var kept []replicaset.Member
+	toAddVote, toRemoveVote, kept = removeOrphans(orphans, toAddVote, toRemoveVote, setVoting)
+	if len(kept) < len(orphans) {
+		changed = true
+	}
 	adjustVotes(toRemoveVote, toAddVote, setVoting)
 
 	addNewMembers(members, toKeep, maxId, setVoting)
@@ -89,6 +90,7 @@ func desiredPeerGroup(info *peerGroupInfo) ([]replicaset.Member, map[*machine]bo
 	for _, member := range members {
 		memberSet = append(memberSet, *member)
 	}
+	memberSet = append(memberSet, kept...)
 	return memberSet, machineVoting, nil
 }
 
@@ -195,6 +197,51 @@ func adjustVotes(toRemoveVote, toAddVote []*machine, setVoting func(*machine, bo
 	}
 }
 
+// removeOrphans decides which of the given voting members without
+// machines can be removed from the peer group while keeping the total
+// number of votes odd. An orphan with an even number of votes can be
+// removed on its own; others are removed by replacing them with a
+// machine that is ready to vote, in pairs, or together with a machine
+// that is losing its vote, in that order of preference. It returns the
+// machines remaining to be considered for vote changes, and the
+// orphans that must be kept.
+func removeOrphans(
+	orphans []replicaset.Member,
+	toAddVote, toRemoveVote []*machine,
+	setVoting func(*machine, bool),
+) (remainingToAdd, remainingToRemove []*machine, kept []replicaset.Member) {
+	var single []replicaset.Member
+	for _, member := range orphans {
+		if member.Votes != nil && *member.Votes%2 == 0 {
+			logger.Infof("removing voting member %d with no machine", member.Id)
+			continue
+		}
+		single = append(single, member)
+	}
+	for len(single) > 0 {
+		member := single[0]
+		switch {
+		case len(toAddVote) > 0:
+			setVoting(toAddVote[0], true)
+			toAddVote = toAddVote[1:]
+		case len(single) > 1:
+			logger.Infof("removing voting member %d with no machine", single[1].Id)
+			single = append(single[:1], single[2:]...)
+		case len(toRemoveVote) > 0:
+			setVoting(toRemoveVote[0], false)
+			toRemoveVote = toRemoveVote[1:]
+		default:
+			logger.Warningf("cannot yet remove voting member %d with no machine", member.Id)
+			kept = append(kept, member)
+			single = single[1:]
+			continue
+		}
+		logger.Infof("removing voting member %d with no machine", member.Id)
+		single = single[1:]
+	}
+	return toAddVote, toRemoveVote, kept
+}
+
 // addNewMembers adds new members from toKeep
 // to the given set of members, allocating ids from
 // maxId upwards. It calls setVoting to set the voting
@@ -63,23 +63,44 @@ func desiredPeerGroupTests(ipVersion TestIPVersion) []desiredPeerGroupTest {
 			expectVoting:  []bool{true},
 			expectMembers: nil,
 		}, {
-			about:        "extra member with nil Vote",
-			machines:     mkMachines("11v", ipVersion),
-			members:      mkMembers("1v 2v", ipVersion),
-			statuses:     mkStatuses("1p 2s", ipVersion),
-			expectVoting: []bool{true},
-			expectErr:    "voting non-machine member.* found in peer group",
+			about:         "extra member with nil Vote that cannot be removed yet",
+			machines:      mkMachines("11v", ipVersion),
+			members:       mkMembers("1v 2v", ipVersion),
+			statuses:      mkStatuses("1p 2s", ipVersion),
+			expectVoting:  []bool{true},
+			expectMembers: nil,
 		}, {
-			about:    "extra member with >1 votes",
+			about:    "extra member with even votes is removed",
 			machines: mkMachines("11v", ipVersion),
 			members: append(mkMembers("1v", ipVersion), replicaset.Member{
 				Id:      2,
 				Votes:   newInt(2),
 				Address: fmt.Sprintf(ipVersion.formatHostPort, 12, mongoPort),
 			}),
-			statuses:     mkStatuses("1p 2s", ipVersion),
-			expectVoting: []bool{true},
-			expectErr:    "voting non-machine member.* found in peer group",
+			statuses:      mkStatuses("1p 2s", ipVersion),
+			expectVoting:  []bool{true},
+			expectMembers: mkMembers("1v", ipVersion),
+		}, {
+			about:         "two extra voting members are removed together",
+			machines:      mkMachines("11v", ipVersion),
+			members:       mkMembers("1v 2v 3v", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s", ipVersion),
+			expectVoting:  []bool{true},
+			expectMembers: mkMembers("1v", ipVersion),
+		}, {
+			about:         "extra voting member is replaced by a ready machine",
+			machines:      mkMachines("11v 12v 14v", ipVersion),
+			members:       mkMembers("1v 2v 3v 4", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s 4s", ipVersion),
+			expectVoting:  []bool{true, true, true},
+			expectMembers: mkMembers("1v 2v 4v", ipVersion),
+		}, {
+			about:         "extra voting member is removed along with a machine's vote",
+			machines:      mkMachines("11v 12v 13v 14", ipVersion),
+			members:       mkMembers("1v 2v 3v 4v 5v", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s 4s 5s", ipVersion),
+			expectVoting:  []bool{true, true, true, false},
+			expectMembers: mkMembers("1v 2v 3v 4", ipVersion),
 		}, {
 			about:         "new machine with no associated member",
 			machines:      mkMachines("11v 12v", ipVersion),
@@ -0,0 +1,14 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package problemreporter
+
+import (
+	"time"
+
+	"github.com/juju/juju/worker"
+)
+
+func NewRunnerWithClock(r worker.Runner, reporter Reporter, now func() time.Time) worker.Runner {
+	return newRunner(r, reporter, now)
+}
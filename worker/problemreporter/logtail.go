@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package problemreporter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+// LogTail is a loggo.Writer that remembers the most recent log lines,
+// so they can be included in problem reports.
+type LogTail struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+// NewLogTail returns a LogTail that remembers up to size lines.
+func NewLogTail(size int) *LogTail {
+	return &LogTail{lines: make([]string, size)}
+}
+
+// Write is part of the loggo.Writer interface.
+func (t *LogTail) Write(level loggo.Level, module, filename string, line int, timestamp time.Time, message string) {
+	if len(t.lines) == 0 {
+		return
+	}
+	var formatter loggo.DefaultFormatter
+	formatted := formatter.Format(level, module, filename, line, timestamp, message)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lines[t.next] = formatted
+	t.next = (t.next + 1) % len(t.lines)
+	if t.next == 0 {
+		t.full = true
+	}
+}
+
+// Lines returns the remembered log lines, oldest first.
+func (t *LogTail) Lines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.full {
+		return append([]string(nil), t.lines[:t.next]...)
+	}
+	lines := append([]string(nil), t.lines[t.next:]...)
+	return append(lines, t.lines[:t.next]...)
+}
+
+const logTailWriterName = "problem-reports"
+
+var (
+	logTailMu sync.Mutex
+	logTail   *LogTail
+)
+
+// InstallLogTail registers a LogTail remembering up to size lines
+// with loggo. Its lines are included in subsequent problem reports.
+func InstallLogTail(size int) error {
+	tail := NewLogTail(size)
+	if err := loggo.RegisterWriter(logTailWriterName, tail, loggo.DEBUG); err != nil {
+		return errors.Annotate(err, "cannot install log tail for problem reports")
+	}
+	logTailMu.Lock()
+	defer logTailMu.Unlock()
+	logTail = tail
+	return nil
+}
+
+// RecentLogs returns the log lines remembered by the installed
+// LogTail, or nil if none is installed.
+func RecentLogs() []string {
+	logTailMu.Lock()
+	defer logTailMu.Unlock()
+	if logTail == nil {
+		return nil
+	}
+	return logTail.Lines()
+}
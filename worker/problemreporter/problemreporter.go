@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The problemreporter package uploads problem reports, describing
+// crash-looping workers and failed hooks, from an agent to the
+// controller, where they can be listed with "juju problem-reports".
+package problemreporter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+var logger = loggo.GetLogger("juju.worker.problemreporter")
+
+const (
+	// WorkerCrashLoop is the kind of report made for a worker that
+	// keeps failing shortly after it is restarted.
+	WorkerCrashLoop = "worker-crash-loop"
+
+	// HookFailure is the kind of report made for a failed hook.
+	HookFailure = "hook-failed"
+)
+
+// Reporter uploads problem reports on behalf of an agent.
+type Reporter interface {
+	Report(params.ProblemReport) error
+}
+
+// ReportHookFailure reports that the named hook failed, along with
+// the agent's recent logs, which include the hook's output.
+func ReportHookFailure(reporter Reporter, hookName string) {
+	report := params.ProblemReport{
+		Kind:    HookFailure,
+		Source:  hookName,
+		Message: fmt.Sprintf("hook %q failed", hookName),
+		Logs:    RecentLogs(),
+		Time:    time.Now(),
+	}
+	if err := reporter.Report(report); err != nil {
+		logger.Errorf("cannot report failure of hook %q: %v", hookName, err)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package problemreporter_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/problemreporter"
+)
+
+type fakeReporter struct {
+	reports chan params.ProblemReport
+	err     error
+}
+
+func newFakeReporter() *fakeReporter {
+	return &fakeReporter{reports: make(chan params.ProblemReport, 10)}
+}
+
+func (r *fakeReporter) Report(report params.ProblemReport) error {
+	r.reports <- report
+	return r.err
+}
+
+type ProblemReporterSuite struct {
+	coretesting.BaseSuite
+	reporter *fakeReporter
+}
+
+var _ = gc.Suite(&ProblemReporterSuite{})
+
+func (s *ProblemReporterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.reporter = newFakeReporter()
+	s.PatchValue(&worker.RestartDelay, time.Millisecond)
+}
+
+func (s *ProblemReporterSuite) newRunner() worker.Runner {
+	return worker.NewRunner(
+		func(error) bool { return false },
+		func(err0, err1 error) bool { return true },
+	)
+}
+
+func failingWorker() (worker.Worker, error) {
+	return worker.NewSimpleWorker(func(<-chan struct{}) error {
+		return errors.New("boom")
+	}), nil
+}
+
+func (s *ProblemReporterSuite) TestCrashLoopReported(c *gc.C) {
+	runner := problemreporter.NewRunner(s.newRunner(), s.reporter)
+	defer worker.Stop(runner)
+	err := runner.StartWorker("failing", failingWorker)
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case report := <-s.reporter.reports:
+		c.Check(report.Kind, gc.Equals, problemreporter.WorkerCrashLoop)
+		c.Check(report.Source, gc.Equals, "failing")
+		c.Check(report.Message, gc.Equals, `worker "failing" failed 3 times: boom`)
+		c.Check(report.State["failures"], gc.Equals, "3")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for problem report")
+	}
+
+	// The crash loop is only reported once.
+	select {
+	case report := <-s.reporter.reports:
+		c.Fatalf("unexpected problem report %#v", report)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *ProblemReporterSuite) TestStartErrorsReported(c *gc.C) {
+	runner := problemreporter.NewRunner(s.newRunner(), s.reporter)
+	defer worker.Stop(runner)
+	err := runner.StartWorker("failing", func() (worker.Worker, error) {
+		return nil, errors.New("cannot start")
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case report := <-s.reporter.reports:
+		c.Check(report.Message, gc.Equals, `worker "failing" failed 3 times: cannot start`)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for problem report")
+	}
+}
+
+func (s *ProblemReporterSuite) TestHealthyRunsNotReported(c *gc.C) {
+	// Each failure happens long after the worker started.
+	now := time.Now()
+	clock := func() time.Time {
+		now = now.Add(time.Hour)
+		return now
+	}
+	runner := problemreporter.NewRunnerWithClock(s.newRunner(), s.reporter, clock)
+	defer worker.Stop(runner)
+	restarts := make(chan struct{}, 10)
+	err := runner.StartWorker("failing", func() (worker.Worker, error) {
+		select {
+		case restarts <- struct{}{}:
+		default:
+		}
+		return failingWorker()
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i := 0; i < 5; i++ {
+		select {
+		case <-restarts:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for worker to restart")
+		}
+	}
+	select {
+	case report := <-s.reporter.reports:
+		c.Fatalf("unexpected problem report %#v", report)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *ProblemReporterSuite) TestReportHookFailure(c *gc.C) {
+	problemreporter.ReportHookFailure(s.reporter, "install")
+	report := <-s.reporter.reports
+	c.Check(report.Kind, gc.Equals, problemreporter.HookFailure)
+	c.Check(report.Source, gc.Equals, "install")
+	c.Check(report.Message, gc.Equals, `hook "install" failed`)
+	c.Check(report.Time.IsZero(), jc.IsFalse)
+}
+
+func (s *ProblemReporterSuite) TestReportHookFailureError(c *gc.C) {
+	s.reporter.err = errors.New("no API")
+	problemreporter.ReportHookFailure(s.reporter, "install")
+	c.Check(c.GetTestLog(), jc.Contains, `cannot report failure of hook "install": no API`)
+}
+
+func (s *ProblemReporterSuite) TestLogTail(c *gc.C) {
+	tail := problemreporter.NewLogTail(2)
+	c.Check(tail.Lines(), gc.HasLen, 0)
+	for i := 0; i < 3; i++ {
+		tail.Write(loggo.INFO, "juju.test", "test.go", 42, time.Now(), fmt.Sprintf("message %d", i))
+	}
+	lines := tail.Lines()
+	c.Assert(lines, gc.HasLen, 2)
+	c.Check(lines[0], jc.HasSuffix, "message 1")
+	c.Check(lines[1], jc.HasSuffix, "message 2")
+}
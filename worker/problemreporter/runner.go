@@ -0,0 +1,143 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package problemreporter
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+const (
+	// crashLoopFailures is the number of consecutive failures after
+	// which a worker is reported as crash-looping.
+	crashLoopFailures = 3
+
+	// healthyRunTime is how long a worker must run before failing
+	// for the failure not to count towards a crash loop.
+	healthyRunTime = 5 * time.Minute
+)
+
+// runner is a worker.Runner that reports workers that keep failing
+// shortly after they are started.
+type runner struct {
+	worker.Runner
+	reporter Reporter
+	now      func() time.Time
+
+	mu       sync.Mutex
+	failures map[string]*failures
+}
+
+// failures records the consecutive failures of a worker.
+type failures struct {
+	count    int
+	first    time.Time
+	reported bool
+}
+
+// NewRunner returns a worker.Runner that starts workers with the given
+// runner, and reports any worker that fails repeatedly shortly after
+// being started. Each crash loop is reported once; a worker that runs
+// for a while before failing again starts a new one.
+func NewRunner(r worker.Runner, reporter Reporter) worker.Runner {
+	return newRunner(r, reporter, time.Now)
+}
+
+func newRunner(r worker.Runner, reporter Reporter, now func() time.Time) *runner {
+	return &runner{
+		Runner:   r,
+		reporter: reporter,
+		now:      now,
+		failures: make(map[string]*failures),
+	}
+}
+
+// StartWorker is part of the worker.Runner interface.
+func (r *runner) StartWorker(id string, start func() (worker.Worker, error)) error {
+	return r.Runner.StartWorker(id, func() (worker.Worker, error) {
+		started := r.now()
+		w, err := start()
+		if err != nil {
+			r.workerStopped(id, started, err)
+			return nil, err
+		}
+		return &reportingWorker{
+			Worker: w,
+			stopped: func(err error) {
+				r.workerStopped(id, started, err)
+			},
+		}, nil
+	})
+}
+
+// workerStopped records that the worker with the given id, started at
+// the given time, stopped with the given error, and reports the worker
+// if it is crash-looping.
+func (r *runner) workerStopped(id string, started time.Time, err error) {
+	now := r.now()
+	r.mu.Lock()
+	if err == nil || now.Sub(started) >= healthyRunTime {
+		delete(r.failures, id)
+	}
+	if err == nil {
+		r.mu.Unlock()
+		return
+	}
+	f := r.failures[id]
+	if f == nil {
+		f = &failures{first: now}
+		r.failures[id] = f
+	}
+	f.count++
+	if f.count < crashLoopFailures || f.reported {
+		r.mu.Unlock()
+		return
+	}
+	f.reported = true
+	report := params.ProblemReport{
+		Kind:    WorkerCrashLoop,
+		Source:  id,
+		Message: fmt.Sprintf("worker %q failed %d times: %v", id, f.count, err),
+		Stack:   errors.ErrorStack(err),
+		Logs:    RecentLogs(),
+		State: map[string]string{
+			"failures":      strconv.Itoa(f.count),
+			"first-failure": f.first.UTC().Format(time.RFC3339),
+			"last-run-time": now.Sub(started).String(),
+		},
+		Time: now,
+	}
+	r.mu.Unlock()
+
+	// Report in the background, so a slow or broken API connection
+	// does not delay restarting the worker.
+	go func() {
+		if err := r.reporter.Report(report); err != nil {
+			logger.Errorf("cannot report crash loop of worker %q: %v", id, err)
+		}
+	}()
+}
+
+// reportingWorker wraps a worker, notifying the runner when it stops.
+type reportingWorker struct {
+	worker.Worker
+	stopped func(error)
+	once    sync.Once
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *reportingWorker) Wait() error {
+	err := w.Worker.Wait()
+	w.once.Do(func() {
+		w.stopped(err)
+	})
+	return err
+}
@@ -0,0 +1,324 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package processhealth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/fslock"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/process"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+var launchPlugin = process.LaunchPlugin
+
+const (
+	// minRestartDelay is how long to wait before relaunching a process
+	// that failed again after being relaunched.
+	minRestartDelay = 30 * time.Second
+
+	// maxRestartDelay caps the delay between successive relaunches of
+	// a process that keeps failing.
+	maxRestartDelay = 10 * time.Minute
+)
+
+// ProcessSetter records the workload processes tracked by a unit.
+type ProcessSetter interface {
+	SetProcesses([]process.Info) error
+}
+
+// checker checks the health of the workload processes launched by a
+// unit, and relaunches them according to their restart policies.
+type checker struct {
+	tag         names.UnitTag
+	path        string
+	unit        ProcessSetter
+	machineLock *fslock.Lock
+	clock       clock.Clock
+
+	// restarts records, by process name, the relaunches of processes
+	// that have not been seen healthy since.
+	restarts map[string]restartBackoff
+}
+
+// restartBackoff records the consecutive relaunches of a process.
+type restartBackoff struct {
+	// attempts is the number of consecutive relaunches.
+	attempts int
+
+	// next is the earliest time at which the process may be
+	// relaunched again.
+	next time.Time
+}
+
+// Do checks the processes recorded in the unit's processes file, and
+// refreshes the resource usage of those whose plugins report it.
+//
+// The checks, and any relaunches, are run without holding the machine
+// lock, as they may take some time; the changes are then applied with
+// the lock held, so that they do not race with hooks changing the same
+// processes. A process that was replaced or untracked while it was
+// being checked is left alone, and any process relaunched in its place
+// is destroyed again.
+//
+// A process that keeps failing is relaunched with an exponentially
+// increasing delay, capped at maxRestartDelay, until it is next seen
+// healthy.
+func (c *checker) Do(stop <-chan struct{}) error {
+	infos, err := context.ReadProcessesFile(c.path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	statuses := make(map[string]string)
+	usages := make(map[string]process.Usage)
+	relaunched := make(map[string]process.Info)
+	for _, info := range infos {
+		select {
+		case <-stop:
+			return worker.ErrKilled
+		default:
+		}
+		if usage, ok := processUsage(info); ok {
+			if info.Details.Usage == nil || *info.Details.Usage != usage {
+				usages[info.Details.ID] = usage
+			}
+		}
+		status, ok := checkProcess(info)
+		if !ok {
+			continue
+		}
+		if status != info.Details.Status {
+			statuses[info.Details.ID] = status
+		}
+		if !process.IsFailedStatus(status) {
+			delete(c.restarts, info.Name)
+		}
+		if !needsRestart(info, status) {
+			continue
+		}
+		if !c.mayRestart(info.Name) {
+			logger.Debugf("not yet restarting process %q", info.Name)
+			continue
+		}
+		if details, ok := relaunch(info); ok {
+			replacement := info
+			replacement.Details = details
+			relaunched[info.Details.ID] = replacement
+		}
+	}
+	if len(statuses) == 0 && len(usages) == 0 && len(relaunched) == 0 {
+		return nil
+	}
+	orphans, err := c.update(stop, statuses, usages, relaunched)
+	for _, info := range orphans {
+		destroy(info)
+	}
+	return errors.Trace(err)
+}
+
+// mayRestart reports whether the named process may be relaunched now,
+// and if so records the relaunch so that the next one is delayed.
+func (c *checker) mayRestart(name string) bool {
+	now := c.clock.Now()
+	backoff := c.restarts[name]
+	if now.Before(backoff.next) {
+		return false
+	}
+	// The first relaunch is immediate; each subsequent one waits twice
+	// as long as the last, starting at minRestartDelay.
+	delay := maxRestartDelay
+	if backoff.attempts < 16 {
+		if d := minRestartDelay << uint(backoff.attempts); d < maxRestartDelay {
+			delay = d
+		}
+	}
+	if c.restarts == nil {
+		c.restarts = make(map[string]restartBackoff)
+	}
+	c.restarts[name] = restartBackoff{
+		attempts: backoff.attempts + 1,
+		next:     now.Add(delay),
+	}
+	return true
+}
+
+// update applies the given statuses, usages and relaunched processes,
+// keyed by the ID of the process they belong to, to the tracked
+// processes. It returns the relaunched processes that were not
+// recorded, because the process they replaced is no longer tracked or
+// the changes could not be applied.
+func (c *checker) update(
+	stop <-chan struct{},
+	statuses map[string]string,
+	usages map[string]process.Usage,
+	relaunched map[string]process.Info,
+) (orphans []process.Info, err error) {
+	var recorded set.Strings
+	defer func() {
+		for id, info := range relaunched {
+			if !recorded.Contains(id) {
+				orphans = append(orphans, info)
+			}
+		}
+	}()
+	unlock, err := c.acquireExecutionLock(stop)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to acquire machine lock")
+	}
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil && err == nil {
+			err = unlockErr
+		}
+	}()
+	infos, err := context.ReadProcessesFile(c.path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	changed := false
+	replaced := set.NewStrings()
+	for i, info := range infos {
+		id := info.Details.ID
+		if replacement, ok := relaunched[id]; ok {
+			logger.Infof("restarted process %q", info.Name)
+			infos[i].Details = replacement.Details
+			replaced.Add(id)
+			changed = true
+			continue
+		}
+		if usage, ok := usages[id]; ok {
+			infos[i].Details.Usage = &usage
+			changed = true
+		}
+		if status, ok := statuses[id]; ok && status != info.Details.Status {
+			logger.Infof("process %q is %s", info.Name, status)
+			infos[i].Details.Status = status
+			changed = true
+		}
+	}
+	if !changed {
+		return nil, nil
+	}
+	if err := context.WriteProcessesFile(c.path, infos); err != nil {
+		return nil, errors.Trace(err)
+	}
+	recorded = replaced
+	err = c.unit.SetProcesses(infos)
+	return nil, errors.Annotate(err, "cannot record tracked processes")
+}
+
+// acquireExecutionLock acquires the machine-level execution lock and
+// returns a function to be used to unlock it.
+func (c *checker) acquireExecutionLock(stop <-chan struct{}) (func() error, error) {
+	message := fmt.Sprintf("%s: checking workload processes", c.tag)
+	checkStop := func() error {
+		select {
+		case <-stop:
+			return worker.ErrKilled
+		default:
+			return nil
+		}
+	}
+	if err := c.machineLock.LockWithFunc(message, checkStop); err != nil {
+		return nil, err
+	}
+	return c.machineLock.Unlock, nil
+}
+
+// checkProcess returns the current status of the process, as reported
+// by its plugin and health check. It returns false if the process was
+// not launched by juju, or its status cannot be determined.
+func checkProcess(info process.Info) (string, bool) {
+	if info.Definition == nil {
+		return "", false
+	}
+	plugin, err := launchPlugin(info.Type)
+	if err != nil {
+		logger.Warningf("cannot check process %q: %v", info.Name, err)
+		return "", false
+	}
+	status, err := plugin.Status(info.Details.ID)
+	if err != nil {
+		logger.Warningf("cannot check process %q: %v", info.Name, err)
+		return "", false
+	}
+	if process.IsFailedStatus(status) || info.Definition.HealthCheck == nil {
+		return status, true
+	}
+	if err := info.Definition.HealthCheck.Run(); err != nil {
+		logger.Debugf("health check of process %q failed: %v", info.Name, err)
+		return process.UnhealthyStatus, true
+	}
+	return status, true
+}
+
+// processUsage returns the current resource usage of the process. It
+// returns false if the process's plugin does not report usage, or the
+// usage cannot be determined.
+func processUsage(info process.Info) (process.Usage, bool) {
+	plugin, err := launchPlugin(info.Type)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Warningf("cannot refresh usage of process %q: %v", info.Name, err)
+		}
+		return process.Usage{}, false
+	}
+	usagePlugin, ok := plugin.(process.UsagePlugin)
+	if !ok {
+		return process.Usage{}, false
+	}
+	usage, err := usagePlugin.Usage(info.Details.ID)
+	if err != nil {
+		logger.Warningf("cannot refresh usage of process %q: %v", info.Name, err)
+		return process.Usage{}, false
+	}
+	return usage, true
+}
+
+// destroy destroys a relaunched process that could not be tracked, so
+// that it is not leaked.
+func destroy(info process.Info) {
+	plugin, err := launchPlugin(info.Type)
+	if err == nil {
+		err = plugin.Destroy(info.Details.ID)
+	}
+	if err != nil {
+		logger.Errorf("cannot destroy untracked process %q: %v", info.Name, err)
+	}
+}
+
+// needsRestart reports whether a process with the given status should
+// be relaunched.
+func needsRestart(info process.Info, status string) bool {
+	if info.Definition == nil || info.Definition.Restart != process.RestartOnFailure {
+		return false
+	}
+	return process.IsFailedStatus(status)
+}
+
+// relaunch destroys what is left of the process and launches it again
+// from its definition, returning the details of the new process. It
+// returns false if the process could not be relaunched.
+func relaunch(info process.Info) (process.Details, bool) {
+	plugin, err := launchPlugin(info.Type)
+	if err != nil {
+		logger.Errorf("cannot restart process %q: %v", info.Name, err)
+		return process.Details{}, false
+	}
+	if err := plugin.Destroy(info.Details.ID); err != nil {
+		logger.Warningf("cannot destroy failed process %q: %v", info.Name, err)
+	}
+	details, err := plugin.Launch(*info.Definition)
+	if err != nil {
+		logger.Errorf("cannot restart process %q: %v", info.Name, err)
+		return process.Details{}, false
+	}
+	return details, true
+}
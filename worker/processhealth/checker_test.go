@@ -0,0 +1,388 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package processhealth_test
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/names"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/fslock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/processhealth"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+type CheckerSuite struct {
+	testing.IsolationSuite
+	stub     testing.Stub
+	statuses map[string]string
+	usages   map[string]process.Usage
+	path     string
+	lock     *fslock.Lock
+	clock    *coretesting.Clock
+	check    worker.PeriodicWorkerCall
+
+	// onLaunch, if set, is called when the fake plugin launches a
+	// process.
+	onLaunch func()
+}
+
+var _ = gc.Suite(&CheckerSuite{})
+
+func (s *CheckerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	s.statuses = make(map[string]string)
+	s.usages = nil
+	s.onLaunch = nil
+	s.clock = coretesting.NewClock(time.Now())
+	s.path = filepath.Join(c.MkDir(), "processes")
+	lock, err := fslock.NewLock(c.MkDir(), "machine-lock")
+	c.Assert(err, jc.ErrorIsNil)
+	s.lock = lock
+	s.PatchValue(processhealth.LaunchPlugin, func(processType string) (process.Plugin, error) {
+		if s.usages != nil {
+			return &fakeUsagePlugin{fakePlugin{s}}, nil
+		}
+		return &fakePlugin{s}, nil
+	})
+	s.check = processhealth.NewChecker(names.NewUnitTag("redis/0"), s.path, &fakeUnit{&s.stub}, s.lock, s.clock)
+}
+
+func (s *CheckerSuite) writeProcesses(c *gc.C, infos ...process.Info) {
+	err := context.WriteProcessesFile(s.path, infos)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CheckerSuite) readProcesses(c *gc.C) []process.Info {
+	infos, err := context.ReadProcessesFile(s.path)
+	c.Assert(err, jc.ErrorIsNil)
+	return infos
+}
+
+func (s *CheckerSuite) listen(c *gc.C) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { listener.Close() })
+	return listener.Addr().String()
+}
+
+func (s *CheckerSuite) closedAddress(c *gc.C) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	addr := listener.Addr().String()
+	listener.Close()
+	return addr
+}
+
+func launched(id, status string, def process.Definition) process.Info {
+	return process.Info{
+		Name:       def.Name,
+		Type:       def.Type,
+		Details:    process.Details{ID: id, Status: status},
+		Definition: &def,
+	}
+}
+
+func (s *CheckerSuite) TestHealthy(c *gc.C) {
+	info := launched("abc", "running", process.Definition{
+		Name:        "cache",
+		Type:        "docker",
+		Image:       "redis",
+		HealthCheck: &process.HealthCheck{Type: "tcp", Target: s.listen(c)},
+	})
+	s.writeProcesses(c, info)
+	s.statuses["abc"] = "running"
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCallNames(c, "Status")
+	c.Assert(s.readProcesses(c), jc.DeepEquals, []process.Info{info})
+	c.Assert(s.lock.IsLocked(), jc.IsFalse)
+}
+
+func (s *CheckerSuite) TestStatusChanged(c *gc.C) {
+	info := launched("abc", "running", process.Definition{Name: "cache", Type: "docker", Image: "redis"})
+	s.writeProcesses(c, info)
+	s.statuses["abc"] = "exited"
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info.Details.Status = "exited"
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"Status", []interface{}{"abc"}},
+		{"SetProcesses", []interface{}{[]process.Info{info}}},
+	})
+	c.Assert(s.readProcesses(c), jc.DeepEquals, []process.Info{info})
+	c.Assert(s.lock.IsLocked(), jc.IsFalse)
+}
+
+func (s *CheckerSuite) TestUnhealthy(c *gc.C) {
+	info := launched("abc", "running", process.Definition{
+		Name:        "cache",
+		Type:        "docker",
+		Image:       "redis",
+		HealthCheck: &process.HealthCheck{Type: "tcp", Target: s.closedAddress(c)},
+	})
+	s.writeProcesses(c, info)
+	s.statuses["abc"] = "running"
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info.Details.Status = "unhealthy"
+	s.stub.CheckCallNames(c, "Status", "SetProcesses")
+	s.stub.CheckCall(c, 1, "SetProcesses", []process.Info{info})
+	c.Assert(s.readProcesses(c), jc.DeepEquals, []process.Info{info})
+}
+
+func (s *CheckerSuite) TestRestartOnFailure(c *gc.C) {
+	def := process.Definition{
+		Name:        "cache",
+		Type:        "docker",
+		Image:       "redis",
+		HealthCheck: &process.HealthCheck{Type: "tcp", Target: s.closedAddress(c)},
+		Restart:     process.RestartOnFailure,
+	}
+	info := launched("abc", "running", def)
+	s.writeProcesses(c, info)
+	s.statuses["abc"] = "running"
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	restarted := launched("cache-id", "running", def)
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"Status", []interface{}{"abc"}},
+		{"Destroy", []interface{}{"abc"}},
+		{"Launch", []interface{}{def}},
+		{"SetProcesses", []interface{}{[]process.Info{restarted}}},
+	})
+	c.Assert(s.readProcesses(c), jc.DeepEquals, []process.Info{restarted})
+}
+
+func (s *CheckerSuite) TestRestartFailureRecordsStatus(c *gc.C) {
+	def := process.Definition{Name: "cache", Type: "docker", Image: "redis", Restart: process.RestartOnFailure}
+	info := launched("abc", "running", def)
+	s.writeProcesses(c, info)
+	s.statuses["abc"] = "exited"
+	s.stub.SetErrors(nil, nil, errors.New("no such image"))
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info.Details.Status = "exited"
+	s.stub.CheckCallNames(c, "Status", "Destroy", "Launch", "SetProcesses")
+	c.Assert(s.readProcesses(c), jc.DeepEquals, []process.Info{info})
+}
+
+func (s *CheckerSuite) TestRestartOutsideLock(c *gc.C) {
+	def := process.Definition{Name: "cache", Type: "docker", Image: "redis", Restart: process.RestartOnFailure}
+	s.writeProcesses(c, launched("abc", "running", def))
+	s.statuses["abc"] = "exited"
+	s.onLaunch = func() {
+		c.Check(s.lock.IsLocked(), jc.IsFalse)
+	}
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCallNames(c, "Status", "Destroy", "Launch", "SetProcesses")
+}
+
+func (s *CheckerSuite) TestRestartUntrackedWhileRelaunching(c *gc.C) {
+	def := process.Definition{Name: "cache", Type: "docker", Image: "redis", Restart: process.RestartOnFailure}
+	s.writeProcesses(c, launched("abc", "running", def))
+	s.statuses["abc"] = "exited"
+	s.onLaunch = func() {
+		// A hook untracks the process while it is being relaunched.
+		s.writeProcesses(c)
+	}
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"Status", []interface{}{"abc"}},
+		{"Destroy", []interface{}{"abc"}},
+		{"Launch", []interface{}{def}},
+		{"Destroy", []interface{}{"cache-id"}},
+	})
+	c.Assert(s.readProcesses(c), gc.HasLen, 0)
+}
+
+func (s *CheckerSuite) TestRestartBackoff(c *gc.C) {
+	def := process.Definition{Name: "cache", Type: "docker", Image: "redis", Restart: process.RestartOnFailure}
+	s.writeProcesses(c, launched("cache-id", "running", def))
+	s.statuses["cache-id"] = "exited"
+
+	// checkRestarted checks the process, and reports whether it was
+	// relaunched.
+	checkRestarted := func() bool {
+		s.stub.ResetCalls()
+		err := s.check(nil)
+		c.Assert(err, jc.ErrorIsNil)
+		for _, call := range s.stub.Calls() {
+			if call.FuncName == "Launch" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The first failure is relaunched immediately.
+	c.Assert(checkRestarted(), jc.IsTrue)
+	// The delay before the next relaunch doubles each time, until it
+	// reaches the cap.
+	for _, delay := range []time.Duration{
+		30 * time.Second,
+		time.Minute,
+		2 * time.Minute,
+		4 * time.Minute,
+		8 * time.Minute,
+		10 * time.Minute,
+		10 * time.Minute,
+	} {
+		c.Logf("waiting %v", delay)
+		s.clock.Advance(delay - time.Second)
+		c.Assert(checkRestarted(), jc.IsFalse)
+		s.clock.Advance(time.Second)
+		c.Assert(checkRestarted(), jc.IsTrue)
+	}
+
+	// Once the process is seen healthy, the delay is reset.
+	s.statuses["cache-id"] = "running"
+	c.Assert(checkRestarted(), jc.IsFalse)
+	s.statuses["cache-id"] = "exited"
+	c.Assert(checkRestarted(), jc.IsTrue)
+}
+
+func (s *CheckerSuite) TestNotLaunched(c *gc.C) {
+	info := process.Info{
+		Name:    "web",
+		Type:    "docker",
+		Details: process.Details{ID: "abc", Status: "running"},
+	}
+	s.writeProcesses(c, info)
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCalls(c, nil)
+}
+
+func (s *CheckerSuite) TestNoProcessesFile(c *gc.C) {
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCalls(c, nil)
+}
+
+func (s *CheckerSuite) TestStopped(c *gc.C) {
+	s.writeProcesses(c, launched("abc", "running", process.Definition{Name: "cache", Type: "docker", Image: "redis"}))
+	stop := make(chan struct{})
+	close(stop)
+
+	err := s.check(stop)
+	c.Assert(err, gc.Equals, worker.ErrKilled)
+	s.stub.CheckCalls(c, nil)
+}
+
+func (s *CheckerSuite) TestUsageRecorded(c *gc.C) {
+	info := launched("abc", "running", process.Definition{Name: "cache", Type: "docker", Image: "redis"})
+	s.writeProcesses(c, info)
+	s.statuses["abc"] = "running"
+	usage := process.Usage{CPU: 99.5, Memory: 1 << 30, Restarts: 7}
+	s.usages = map[string]process.Usage{"abc": usage}
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info.Details.Usage = &usage
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"Usage", []interface{}{"abc"}},
+		{"Status", []interface{}{"abc"}},
+		{"SetProcesses", []interface{}{[]process.Info{info}}},
+	})
+	c.Assert(s.readProcesses(c), jc.DeepEquals, []process.Info{info})
+	c.Assert(s.lock.IsLocked(), jc.IsFalse)
+}
+
+func (s *CheckerSuite) TestUsageUnchanged(c *gc.C) {
+	usage := process.Usage{CPU: 99.5, Memory: 1 << 30, Restarts: 7}
+	info := launched("abc", "running", process.Definition{Name: "cache", Type: "docker", Image: "redis"})
+	info.Details.Usage = &usage
+	s.writeProcesses(c, info)
+	s.statuses["abc"] = "running"
+	s.usages = map[string]process.Usage{"abc": usage}
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCallNames(c, "Usage", "Status")
+	c.Assert(s.readProcesses(c), jc.DeepEquals, []process.Info{info})
+}
+
+func (s *CheckerSuite) TestUsageNotLaunched(c *gc.C) {
+	// Usage is recorded for all tracked processes, not only those
+	// launched by juju.
+	info := process.Info{
+		Name:    "web",
+		Type:    "docker",
+		Details: process.Details{ID: "abc", Status: "running"},
+	}
+	s.writeProcesses(c, info)
+	usage := process.Usage{Memory: 1 << 20}
+	s.usages = map[string]process.Usage{"abc": usage}
+
+	err := s.check(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info.Details.Usage = &usage
+	s.stub.CheckCallNames(c, "Usage", "SetProcesses")
+	c.Assert(s.readProcesses(c), jc.DeepEquals, []process.Info{info})
+}
+
+type fakePlugin struct {
+	s *CheckerSuite
+}
+
+func (p *fakePlugin) Launch(def process.Definition) (process.Details, error) {
+	p.s.stub.AddCall("Launch", def)
+	if p.s.onLaunch != nil {
+		p.s.onLaunch()
+	}
+	if err := p.s.stub.NextErr(); err != nil {
+		return process.Details{}, err
+	}
+	return process.Details{ID: def.Name + "-id", Status: "running"}, nil
+}
+
+func (p *fakePlugin) Status(id string) (string, error) {
+	p.s.stub.AddCall("Status", id)
+	return p.s.statuses[id], p.s.stub.NextErr()
+}
+
+func (p *fakePlugin) Destroy(id string) error {
+	p.s.stub.AddCall("Destroy", id)
+	return p.s.stub.NextErr()
+}
+
+// fakeUsagePlugin is a fakePlugin that also reports resource usage.
+type fakeUsagePlugin struct {
+	fakePlugin
+}
+
+func (p *fakeUsagePlugin) Usage(id string) (process.Usage, error) {
+	p.s.stub.AddCall("Usage", id)
+	return p.s.usages[id], p.s.stub.NextErr()
+}
+
+type fakeUnit struct {
+	stub *testing.Stub
+}
+
+func (u *fakeUnit) SetProcesses(infos []process.Info) error {
+	u.stub.AddCall("SetProcesses", infos)
+	return u.stub.NextErr()
+}
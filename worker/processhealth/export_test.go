@@ -0,0 +1,27 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package processhealth
+
+import (
+	"github.com/juju/names"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/fslock"
+
+	"github.com/juju/juju/worker"
+)
+
+var LaunchPlugin = &launchPlugin
+
+// NewChecker returns the function run periodically by the process
+// health worker.
+func NewChecker(tag names.UnitTag, path string, unit ProcessSetter, machineLock *fslock.Lock, clock clock.Clock) worker.PeriodicWorkerCall {
+	c := &checker{
+		tag:         tag,
+		path:        path,
+		unit:        unit,
+		machineLock: machineLock,
+		clock:       clock,
+	}
+	return c.Do
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package processhealth provides a worker that periodically checks the
+// health of the workload processes launched by a unit, relaunches those
+// that have failed according to their restart policies, and records the
+// resource usage of those whose plugins report it.
+package processhealth
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/fslock"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	apiuniter "github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/uniter"
+)
+
+var logger = loggo.GetLogger("juju.worker.processhealth")
+
+const period = 30 * time.Second
+
+// ManifoldConfig identifies the resource names upon which the process
+// health manifold depends.
+type ManifoldConfig struct {
+	AgentName       string
+	APICallerName   string
+	MachineLockName string
+}
+
+// Manifold returns a process health manifold.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.APICallerName,
+			config.MachineLockName,
+		},
+		Start: func(getResource dependency.GetResourceFunc) (worker.Worker, error) {
+			var agent agent.Agent
+			if err := getResource(config.AgentName, &agent); err != nil {
+				return nil, err
+			}
+			var machineLock *fslock.Lock
+			if err := getResource(config.MachineLockName, &machineLock); err != nil {
+				return nil, err
+			}
+			var apiCaller base.APICaller
+			if err := getResource(config.APICallerName, &apiCaller); err != nil {
+				return nil, err
+			}
+
+			agentConfig := agent.CurrentConfig()
+			unitTag, ok := agentConfig.Tag().(names.UnitTag)
+			if !ok {
+				return nil, errors.Errorf("expected a unit tag, got %v", agentConfig.Tag())
+			}
+			unit, err := apiuniter.NewState(apiCaller, unitTag).Unit(unitTag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			c := &checker{
+				tag:         unitTag,
+				path:        uniter.NewPaths(agentConfig.DataDir(), unitTag).GetProcessesFile(),
+				unit:        unit,
+				machineLock: machineLock,
+				clock:       clock.WallClock,
+			}
+			return worker.NewPeriodicWorker(c.Do, period, worker.NewTimer), nil
+		},
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package processhealth_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/fslock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/processhealth"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	manifold dependency.Manifold
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.manifold = processhealth.Manifold(processhealth.ManifoldConfig{
+		AgentName:       "agent-name",
+		APICallerName:   "apicaller-name",
+		MachineLockName: "machine-lock-name",
+	})
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Check(s.manifold.Inputs, jc.DeepEquals, []string{
+		"agent-name", "apicaller-name", "machine-lock-name",
+	})
+}
+
+func (s *ManifoldSuite) TestStartMissingDeps(c *gc.C) {
+	lock, err := fslock.NewLock(c.MkDir(), "machine-lock")
+	c.Assert(err, jc.ErrorIsNil)
+	for _, missingDep := range []string{
+		"agent-name", "apicaller-name", "machine-lock-name",
+	} {
+		resources := dt.StubResources{
+			"agent-name":        dt.StubResource{Output: &fakeAgent{}},
+			"apicaller-name":    dt.StubResource{Output: &fakeAPICaller{}},
+			"machine-lock-name": dt.StubResource{Output: lock},
+		}
+		resources[missingDep] = dt.StubResource{Error: dependency.ErrMissing}
+		worker, err := s.manifold.Start(dt.StubGetResource(resources))
+		c.Check(worker, gc.IsNil)
+		c.Check(err, gc.Equals, dependency.ErrMissing)
+	}
+}
+
+type fakeAgent struct {
+	agent.Agent
+}
+
+type fakeAPICaller struct {
+	base.APICaller
+}
@@ -245,22 +245,28 @@ func (task *provisionerTask) processMachines(ids []string) error {
 	if err != nil {
 		return err
 	}
+	// Only report instances left running when there are some, as this
+	// happens every time the machines change.
 	if !task.harvestMode.HarvestUnknown() {
-		logger.Infof(
-			"%s is set to %s; unknown instances not stopped %v",
-			config.ProvisionerHarvestModeKey,
-			task.harvestMode.String(),
-			instanceIds(unknown),
-		)
+		if len(unknown) > 0 {
+			logger.Infof(
+				"%s is set to %s; unknown instances not stopped %v",
+				config.ProvisionerHarvestModeKey,
+				task.harvestMode.String(),
+				instanceIds(unknown),
+			)
+		}
 		unknown = nil
 	}
 	if task.harvestMode.HarvestNone() || !task.harvestMode.HarvestDestroyed() {
-		logger.Infof(
-			`%s is set to "%s"; will not harvest %s`,
-			config.ProvisionerHarvestModeKey,
-			task.harvestMode.String(),
-			instanceIds(stopping),
-		)
+		if len(stopping) > 0 {
+			logger.Infof(
+				`%s is set to "%s"; will not harvest %s`,
+				config.ProvisionerHarvestModeKey,
+				task.harvestMode.String(),
+				instanceIds(stopping),
+			)
+		}
 		stopping = nil
 	}
 
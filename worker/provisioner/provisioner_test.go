@@ -504,6 +504,55 @@ func (s *ProvisionerSuite) TestConstraints(c *gc.C) {
 	s.checkStartInstanceCustom(c, m, "pork", cons, nil, nil, nil, nil, false, nil, true)
 }
 
+func (s *ProvisionerSuite) TestDistributionGroup(c *gc.C) {
+	p := s.newEnvironProvisioner(c)
+	defer stop(c, p)
+
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	addUnitMachine := func() *state.Machine {
+		unit, err := wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.State.AssignUnit(unit, state.AssignNew)
+		c.Assert(err, jc.ErrorIsNil)
+		id, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		return m
+	}
+	startedGroup := func(m *state.Machine) []instance.Id {
+		s.BackingState.StartSync()
+		for {
+			select {
+			case o := <-s.op:
+				if o, ok := o.(dummy.OpStartInstance); ok {
+					c.Assert(o.MachineId, gc.Equals, m.Id())
+					s.waitInstanceId(c, m, o.Instance.Id())
+					return o.DistributionGroup
+				}
+			case <-time.After(coretesting.LongWait):
+				c.Fatalf("provisioner did not start an instance")
+			}
+		}
+	}
+
+	// The first unit of the service has nothing to be spread from...
+	m0 := addUnitMachine()
+	c.Assert(startedGroup(m0), gc.HasLen, 0)
+	inst0, err := m0.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// ...but subsequent units are started knowing where the
+	// existing ones are, so the provider can place them elsewhere.
+	m1 := addUnitMachine()
+	c.Assert(startedGroup(m1), jc.DeepEquals, []instance.Id{inst0})
+	inst1, err := m1.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	m2 := addUnitMachine()
+	c.Assert(startedGroup(m2), jc.SameContents, []instance.Id{inst0, inst1})
+}
+
 func (s *ProvisionerSuite) TestPossibleTools(c *gc.C) {
 
 	storageDir := c.MkDir()
@@ -1335,6 +1384,20 @@ func (s *ProvisionerSuite) TestHarvestNoneReapsNothing(c *gc.C) {
 
 	// Ensure we're doing nothing.
 	s.checkNoOperations(c)
+	c.Check(c.GetTestLog(), gc.Matches, `(?s).*provisioner-harvest-mode is set to none; unknown instances not stopped \[.+\].*`)
+}
+
+func (s *ProvisionerSuite) TestHarvestDestroyedLogsOnlyUnharvested(c *gc.C) {
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m0)
+	s.checkNoOperations(c)
+
+	// With no unknown instances there is nothing to report.
+	c.Check(c.GetTestLog(), gc.Not(jc.Contains), "unknown instances not stopped")
 }
 
 func (s *ProvisionerSuite) TestHarvestUnknownReapsOnlyUnknown(c *gc.C) {
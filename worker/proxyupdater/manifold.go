@@ -11,20 +11,27 @@ import (
 	"github.com/juju/juju/worker/util"
 )
 
-// ManifoldConfig defines the names of the manifolds on which a Manifold will depend.
-type ManifoldConfig util.ApiManifoldConfig
+// ManifoldConfig defines the names of the manifolds on which a Manifold will
+// depend, and whether the worker should write proxy settings to system files.
+type ManifoldConfig struct {
+	APICallerName    string
+	WriteSystemFiles bool
+}
 
 // Manifold returns a dependency manifold that runs a proxy updater worker,
 // using the api connection resource named in the supplied config.
 func Manifold(config ManifoldConfig) dependency.Manifold {
-	return util.ApiManifold(util.ApiManifoldConfig(config), newWorker)
+	apiConfig := util.ApiManifoldConfig{APICallerName: config.APICallerName}
+	return util.ApiManifold(apiConfig, newWorkerFunc(config.WriteSystemFiles))
 }
 
-// newWorker is not currently tested; it should eventually replace New as the
-// package's exposed factory func, and then all tests should pass through it.
-func newWorker(apiCaller base.APICaller) (worker.Worker, error) {
-	// TODO(fwereade): This shouldn't be an "environment" facade, it
-	// should be specific to the proxyupdater, and be watching for
-	// *proxy settings* changes, not just watching the "environment".
-	return New(environment.NewFacade(apiCaller), false), nil
+// newWorkerFunc is not currently tested; it should eventually replace New as
+// the package's exposed factory func, and then all tests should pass through it.
+func newWorkerFunc(writeSystemFiles bool) util.ApiStartFunc {
+	return func(apiCaller base.APICaller) (worker.Worker, error) {
+		// TODO(fwereade): This shouldn't be an "environment" facade, it
+		// should be specific to the proxyupdater, and be watching for
+		// *proxy settings* changes, not just watching the "environment".
+		return New(environment.NewFacade(apiCaller), writeSystemFiles), nil
+	}
 }
@@ -35,6 +35,12 @@ var newWorker = func(a agent.Agent, apiCaller base.APICaller) (worker.Worker, er
 		logger.Warningf("rsyslog manifold disabled by feature flag")
 		return nil, dependency.ErrMissing
 	}
+	if feature.IsDbLogEnabled() {
+		// Logs are sent to the API server by the log sender, so
+		// there's no need to forward them with rsyslog as well.
+		logger.Debugf("rsyslog manifold disabled when logging to the database")
+		return nil, dependency.ErrMissing
+	}
 
 	agentConfig := a.CurrentConfig()
 	tag := agentConfig.Tag()
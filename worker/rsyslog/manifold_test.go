@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rsyslog_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/feature"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/rsyslog"
+)
+
+type ManifoldSuite struct {
+	coretesting.BaseSuite
+	manifold    dependency.Manifold
+	getResource dependency.GetResourceFunc
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+type fakeAgent struct {
+	agent.Agent
+}
+
+type fakeAPICaller struct {
+	base.APICaller
+}
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.manifold = rsyslog.Manifold(rsyslog.ManifoldConfig{
+		AgentName:     "agent-name",
+		APICallerName: "api-caller-name",
+	})
+	s.getResource = dt.StubGetResource(dt.StubResources{
+		"agent-name":      dt.StubResource{Output: &fakeAgent{}},
+		"api-caller-name": dt.StubResource{Output: &fakeAPICaller{}},
+	})
+}
+
+func (s *ManifoldSuite) TestStartDisabledByFeatureFlag(c *gc.C) {
+	s.SetFeatureFlags(feature.DisableRsyslog)
+	worker, err := s.manifold.Start(s.getResource)
+	c.Check(worker, gc.IsNil)
+	c.Check(err, gc.Equals, dependency.ErrMissing)
+}
+
+func (s *ManifoldSuite) TestStartDisabledByDbLog(c *gc.C) {
+	s.SetFeatureFlags("db-log")
+	worker, err := s.manifold.Start(s.getResource)
+	c.Check(worker, gc.IsNil)
+	c.Check(err, gc.Equals, dependency.ErrMissing)
+}
@@ -39,6 +39,7 @@ type runner struct {
 	stopc         chan string
 	donec         chan doneInfo
 	startedc      chan startInfo
+	reportc       chan chan []workerReport
 	isFatal       func(error) bool
 	moreImportant func(err0, err1 error) bool
 }
@@ -76,6 +77,7 @@ func NewRunner(isFatal func(error) bool, moreImportant func(err0, err1 error) bo
 		stopc:         make(chan string),
 		donec:         make(chan doneInfo),
 		startedc:      make(chan startInfo),
+		reportc:       make(chan chan []workerReport),
 		isFatal:       isFatal,
 		moreImportant: moreImportant,
 	}
@@ -133,11 +135,75 @@ func Stop(worker Worker) error {
 	return worker.Wait()
 }
 
+// Report returns a map describing the state of each of the runner's
+// workers, including the reports of any workers that can themselves
+// report, such as nested runners. It returns nil if the runner is not
+// running.
+func (runner *runner) Report() map[string]interface{} {
+	reply := make(chan []workerReport, 1)
+	select {
+	case runner.reportc <- reply:
+	case <-runner.tomb.Dead():
+		return nil
+	}
+	report := make(map[string]interface{})
+	for _, w := range <-reply {
+		workerReport := map[string]interface{}{
+			"state": w.state,
+		}
+		if w.err != nil {
+			workerReport["error"] = w.err.Error()
+		}
+		// The workers' reports are gathered here rather than in the
+		// run loop, so that a slow worker cannot hold it up.
+		if reporter, ok := w.worker.(interface {
+			Report() map[string]interface{}
+		}); ok {
+			workerReport["report"] = reporter.Report()
+		}
+		report[w.id] = workerReport
+	}
+	return report
+}
+
 type workerInfo struct {
 	start        func() (Worker, error)
 	worker       Worker
 	restartDelay time.Duration
 	stopping     bool
+	err          error
+}
+
+// workerReport holds a snapshot of a worker's state, taken by the
+// run loop for Report.
+type workerReport struct {
+	id     string
+	state  string
+	err    error
+	worker Worker
+}
+
+// reportWorkers returns a snapshot of the state of the given workers.
+func reportWorkers(workers map[string]*workerInfo) []workerReport {
+	reports := make([]workerReport, 0, len(workers))
+	for id, info := range workers {
+		state := "started"
+		switch {
+		case info.stopping:
+			state = "stopping"
+		case info.worker == nil && info.err != nil:
+			state = "restarting"
+		case info.worker == nil:
+			state = "starting"
+		}
+		reports = append(reports, workerReport{
+			id:     id,
+			state:  state,
+			err:    info.err,
+			worker: info.worker,
+		})
+	}
+	return reports
 }
 
 func (runner *runner) run() error {
@@ -187,6 +253,8 @@ func (runner *runner) run() error {
 			// the new start function.
 			info.start = req.start
 			info.restartDelay = 0
+		case reply := <-runner.reportc:
+			reply <- reportWorkers(workers)
 		case id := <-runner.stopc:
 			logger.Debugf("stop %q", id)
 			if info := workers[id]; info != nil {
@@ -202,6 +270,8 @@ func (runner *runner) run() error {
 		case info := <-runner.donec:
 			logger.Debugf("%q done: %v", info.id, info.err)
 			workerInfo := workers[info.id]
+			workerInfo.worker = nil
+			workerInfo.err = info.err
 			if !workerInfo.stopping && info.err == nil {
 				logger.Debugf("removing %q from known workers", info.id)
 				delete(workers, info.id)
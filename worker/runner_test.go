@@ -6,6 +6,7 @@ package worker_test
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"sync/atomic"
 	"time"
 
@@ -313,6 +314,63 @@ func (*runnerSuite) TestFatalErrorWhileSelfStartWorker(c *gc.C) {
 	c.Assert(err, gc.Equals, fatalStarter.startErr)
 }
 
+type reporter interface {
+	Report() map[string]interface{}
+}
+
+func (*runnerSuite) TestReport(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	defer worker.Stop(runner)
+	starter := newTestWorkerStarter()
+	err := runner.StartWorker("id", testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, true)
+
+	nested := worker.NewRunner(noneFatal, noImportance)
+	err = runner.StartWorker("nested", func() (worker.Worker, error) {
+		return nested, nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := map[string]interface{}{
+		"id": map[string]interface{}{
+			"state": "started",
+		},
+		"nested": map[string]interface{}{
+			"state":  "started",
+			"report": map[string]interface{}{},
+		},
+	}
+	// The runner may not yet have recorded that the workers have
+	// started, so wait for it to do so.
+	var report map[string]interface{}
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		report = runner.(reporter).Report()
+		if reflect.DeepEqual(report, expected) {
+			break
+		}
+	}
+	c.Assert(report, jc.DeepEquals, expected)
+
+	// The worker's last error is reported, whether or not it has
+	// been restarted yet.
+	starter.die <- errors.New("boom")
+	starter.assertStarted(c, false)
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		report = runner.(reporter).Report()
+		if report["id"].(map[string]interface{})["error"] != nil {
+			break
+		}
+	}
+	c.Assert(report["id"].(map[string]interface{})["error"], gc.Equals, "boom")
+}
+
+func (*runnerSuite) TestReportWhenDead(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	c.Assert(worker.Stop(runner), gc.IsNil)
+	c.Assert(runner.(reporter).Report(), gc.IsNil)
+}
+
 type testWorkerStarter struct {
 	startCount int32
 
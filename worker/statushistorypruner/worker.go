@@ -4,10 +4,13 @@
 package statushistorypruner
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker"
 )
 
@@ -43,6 +46,21 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// ParseMaxLogsPerEntity parses the number of status history entries
+// to keep for each entity. An empty value gives the default of
+// params.DefaultMaxLogsPerEntity.
+func ParseMaxLogsPerEntity(value string) (uint, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return params.DefaultMaxLogsPerEntity, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || n == 0 {
+		return 0, errors.NotValidf("status history retention %q", value)
+	}
+	return uint(n), nil
+}
+
 // New returns a worker.Worker for history Pruner.
 func New(conf Config) (worker.Worker, error) {
 	if err := conf.Validate(); err != nil {
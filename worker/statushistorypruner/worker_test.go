@@ -128,6 +128,38 @@ func newMockTimer(d time.Duration) *mockTimer {
 	}
 }
 
+func (s *statusHistoryPrunerSuite) TestParseMaxLogsPerEntity(c *gc.C) {
+	for i, test := range []struct {
+		value    string
+		expected uint
+		err      string
+	}{{
+		value:    "",
+		expected: 100,
+	}, {
+		value:    " 500 ",
+		expected: 500,
+	}, {
+		value: "0",
+		err:   `status history retention "0" not valid`,
+	}, {
+		value: "-1",
+		err:   `status history retention "-1" not valid`,
+	}, {
+		value: "lots",
+		err:   `status history retention "lots" not valid`,
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		maxLogs, err := statushistorypruner.ParseMaxLogsPerEntity(test.value)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(maxLogs, gc.Equals, test.expected)
+	}
+}
+
 type fakeFacade struct {
 	passedMaxLogs chan int
 }
@@ -12,7 +12,7 @@ import (
 	"github.com/juju/juju/worker"
 )
 
-var logger = loggo.GetLogger("juju.worker.networktoolsversionchecker")
+var logger = loggo.GetLogger("juju.worker.toolsversionchecker")
 
 // VersionCheckerParams holds params for the version checker worker..
 type VersionCheckerParams struct {
@@ -44,6 +44,11 @@ type toolsVersionWorker struct {
 }
 
 func (w *toolsVersionWorker) doCheck() error {
-	err := w.api.UpdateToolsVersion()
-	return errors.Annotate(err, "cannot update tools information")
+	if err := w.api.UpdateToolsVersion(); err != nil {
+		// The tools metadata may be temporarily unreachable. Rather
+		// than failing, and having the worker restarted to check
+		// again straight away, wait until the next interval.
+		logger.Errorf("%v", errors.Annotate(err, "cannot update tools information"))
+	}
+	return nil
 }
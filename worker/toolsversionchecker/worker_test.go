@@ -4,6 +4,7 @@
 package toolsversionchecker_test
 
 import (
+	"errors"
 	"time"
 
 	jc "github.com/juju/testing/checkers"
@@ -38,11 +39,12 @@ func (dummyEnviron) Config() *config.Config {
 
 type facade struct {
 	called chan string
+	err    error
 }
 
 func (f *facade) UpdateToolsVersion() error {
 	f.called <- "UpdateToolsVersion"
-	return nil
+	return f.err
 }
 
 func newFacade() *facade {
@@ -75,3 +77,30 @@ func (s *ToolsCheckerSuite) TestWorker(c *gc.C) {
 	}
 
 }
+
+func (s *ToolsCheckerSuite) TestWorkerContinuesAfterError(c *gc.C) {
+	f := newFacade()
+	f.err = errors.New("cannot reach simplestreams")
+	params := &toolsversionchecker.VersionCheckerParams{
+		CheckInterval: coretesting.ShortWait,
+	}
+
+	checker := toolsversionchecker.NewPeriodicWorkerForTests(
+		f,
+		params,
+	)
+	s.AddCleanup(func(*gc.C) {
+		checker.Kill()
+		c.Assert(checker.Wait(), jc.ErrorIsNil)
+	})
+
+	// The failed check is logged, and the worker keeps checking.
+	for i := 0; i < 2; i++ {
+		select {
+		case called := <-f.called:
+			c.Assert(called, gc.Equals, "UpdateToolsVersion")
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting worker to seek new tool versions")
+		}
+	}
+}
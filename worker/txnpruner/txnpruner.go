@@ -7,18 +7,27 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker"
 )
 
+var logger = loggo.GetLogger("juju.worker.txnpruner")
+
 // TransactionPruner defines the interface for types capable of
 // pruning transactions.
 type TransactionPruner interface {
 	MaybePruneTransactions() error
+	CheckTxnQueues(maxLength int) (state.TxnHealth, error)
 }
 
-// New returns a worker which periodically prunes the data for
-// completed transactions.
+// maxTxnQueueLength is the length beyond which a document's txn-queue
+// is checked for the tokens of completed transactions.
+const maxTxnQueueLength = 1000
+
+// New returns a worker which periodically repairs oversized
+// transaction queues and prunes the data for completed transactions.
 func New(tp TransactionPruner, interval time.Duration) worker.Worker {
 	return worker.NewSimpleWorker(func(stopCh <-chan struct{}) error {
 		// Use a timer rather than a ticker because pruning could
@@ -29,7 +38,17 @@ func New(tp TransactionPruner, interval time.Duration) worker.Worker {
 		for {
 			select {
 			case <-timer.C:
-				err := tp.MaybePruneTransactions()
+				// Repair the queues first, so that the transactions
+				// they no longer refer to can be pruned.
+				health, err := tp.CheckTxnQueues(maxTxnQueueLength)
+				if err != nil {
+					return errors.Annotate(err, "checking transaction queues failed, txnpruner stopping")
+				}
+				if health.OversizedQueues > 0 {
+					logger.Warningf("found %d oversized transaction queues, removed %d stale transactions from them",
+						health.OversizedQueues, health.RemovedTokens)
+				}
+				err = tp.MaybePruneTransactions()
 				if err != nil {
 					return errors.Annotate(err, "pruning failed, txnpruner stopping")
 				}
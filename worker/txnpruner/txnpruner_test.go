@@ -9,6 +9,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/txnpruner"
 )
@@ -44,6 +45,27 @@ func (s *TxnPrunerSuite) TestPrunes(c *gc.C) {
 	}
 }
 
+func (s *TxnPrunerSuite) TestChecksQueuesBeforePruning(c *gc.C) {
+	fakePruner := newFakeTransactionPruner()
+	fakePruner.checkCh = make(chan int)
+	p := txnpruner.New(fakePruner, 10*time.Millisecond)
+	defer p.Kill()
+
+	select {
+	case maxLength := <-fakePruner.checkCh:
+		c.Assert(maxLength, gc.Equals, 1000)
+	case <-fakePruner.pruneCh:
+		c.Fatal("pruned before checking transaction queues")
+	case <-time.After(testing.LongWait):
+		c.Fatal("timed out waiting for transaction queues to be checked")
+	}
+	select {
+	case <-fakePruner.pruneCh:
+	case <-time.After(testing.LongWait):
+		c.Fatal("timed out waiting for pruning to happen")
+	}
+}
+
 func (s *TxnPrunerSuite) TestStops(c *gc.C) {
 	success := make(chan bool)
 	check := func() {
@@ -69,6 +91,16 @@ func newFakeTransactionPruner() *fakeTransactionPruner {
 
 type fakeTransactionPruner struct {
 	pruneCh chan bool
+	checkCh chan int
+}
+
+// CheckTxnQueues implements the txnpruner.TransactionPruner
+// interface.
+func (p *fakeTransactionPruner) CheckTxnQueues(maxLength int) (state.TxnHealth, error) {
+	if p.checkCh != nil {
+		p.checkCh <- maxLength
+	}
+	return state.TxnHealth{}, nil
 }
 
 // MaybePruneTransactions implements the txnpruner.TransactionPruner
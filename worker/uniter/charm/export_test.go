@@ -22,3 +22,8 @@ func IsManifestDeployer(d Deployer) bool {
 	_, ok := d.(*manifestDeployer)
 	return ok
 }
+
+var (
+	NewPackageCommander = &newPackageCommander
+	RunCommands         = &runCommands
+)
@@ -0,0 +1,146 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/exec"
+	"github.com/juju/utils/packaging/commands"
+	goyaml "gopkg.in/yaml.v2"
+)
+
+// metadataFile is the name of the file, within a charm directory, that
+// holds the charm's metadata.
+const metadataFile = "metadata.yaml"
+
+var (
+	validPackage = regexp.MustCompile(`^[a-z0-9][a-z0-9+.-]+$`)
+	validPPA     = regexp.MustCompile(`^ppa:[a-z0-9][a-z0-9+.-]*/[a-z0-9][a-z0-9+.-]*$`)
+)
+
+// Packages holds the system packages, and the package archives they
+// are installed from, that a charm requires on its machine. They are
+// declared in the "system-packages" section of the charm's metadata:
+//
+//     system-packages:
+//       ppas:
+//         - ppa:owner/archive
+//       packages:
+//         - some-package
+type Packages struct {
+	PPAs     []string `yaml:"ppas"`
+	Packages []string `yaml:"packages"`
+}
+
+// IsEmpty returns whether no packages or archives are declared.
+func (p *Packages) IsEmpty() bool {
+	return len(p.PPAs) == 0 && len(p.Packages) == 0
+}
+
+// Validate checks that the declared package and archive names are
+// well formed, so that they cannot be used to pass arbitrary options
+// to the package manager.
+func (p *Packages) Validate() error {
+	for _, ppa := range p.PPAs {
+		if !validPPA.MatchString(ppa) {
+			return errors.NotValidf("package archive %q", ppa)
+		}
+	}
+	for _, pkg := range p.Packages {
+		if !validPackage.MatchString(pkg) {
+			return errors.NotValidf("package name %q", pkg)
+		}
+	}
+	return nil
+}
+
+// ReadPackages returns the system packages declared in the metadata
+// of the charm deployed to charmDir.
+func ReadPackages(charmDir string) (*Packages, error) {
+	data, err := ioutil.ReadFile(filepath.Join(charmDir, metadataFile))
+	if os.IsNotExist(err) {
+		return &Packages{}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var meta struct {
+		SystemPackages Packages `yaml:"system-packages"`
+	}
+	if err := goyaml.Unmarshal(data, &meta); err != nil {
+		return nil, errors.Annotate(err, "cannot parse charm metadata")
+	}
+	if err := meta.SystemPackages.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &meta.SystemPackages, nil
+}
+
+// newPackageCommander is a helper function which returns the package
+// command builder for the given series.
+var newPackageCommander = func(series string) (commands.PackageCommander, error) {
+	return commands.NewPackageCommander(series)
+}
+
+// runCommands runs the given package manager commands.
+var runCommands = exec.RunCommands
+
+// InstallPackages adds the declared package archives to the machine,
+// and installs the declared packages, using the package manager for
+// the given series. The package manager is run with the given hook
+// environment, which carries the environment's proxy settings; it is
+// not run with the agent's own environment. The package manager's
+// proxy and mirror configuration is maintained by the machine agent.
+func InstallPackages(series string, pkgs *Packages, hookEnv []string) error {
+	if pkgs.IsEmpty() {
+		return nil
+	}
+	paccmder, err := newPackageCommander(series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, ppa := range pkgs.PPAs {
+		logger.Infof("adding package archive %q", ppa)
+		if err := runPackageCommand(paccmder.AddRepositoryCmd(ppa), hookEnv); err != nil {
+			return errors.Annotatef(err, "cannot add package archive %q", ppa)
+		}
+	}
+	if len(pkgs.PPAs) > 0 {
+		if err := runPackageCommand(paccmder.UpdateCmd(), hookEnv); err != nil {
+			return errors.Annotate(err, "cannot update package lists")
+		}
+	}
+	if len(pkgs.Packages) == 0 {
+		return nil
+	}
+	for _, pkg := range pkgs.Packages {
+		logger.Infof("installing system package %q", pkg)
+	}
+	if err := runPackageCommand(paccmder.InstallCmd(pkgs.Packages...), hookEnv); err != nil {
+		return errors.Annotate(err, "cannot install system packages")
+	}
+	return nil
+}
+
+// runPackageCommand runs a package manager command with the given
+// environment, and returns an error if it fails.
+func runPackageCommand(command string, env []string) error {
+	logger.Debugf("running %q", command)
+	result, err := runCommands(exec.RunParams{
+		Commands:    command,
+		Environment: env,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if result.Code != 0 {
+		return errors.Errorf("%q failed with code %d: %s", command, result.Code, strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
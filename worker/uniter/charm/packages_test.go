@@ -0,0 +1,144 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/exec"
+	"github.com/juju/utils/packaging/commands"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/charm"
+)
+
+type PackagesSuite struct {
+	testing.BaseSuite
+	paccmder commands.PackageCommander
+	run      []exec.RunParams
+	result   exec.ExecResponse
+}
+
+var _ = gc.Suite(&PackagesSuite{})
+
+func (s *PackagesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	var err error
+	s.paccmder, err = commands.NewPackageCommander("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	s.run = nil
+	s.result = exec.ExecResponse{}
+	s.PatchValue(charm.RunCommands, func(run exec.RunParams) (*exec.ExecResponse, error) {
+		s.run = append(s.run, run)
+		result := s.result
+		return &result, nil
+	})
+}
+
+func (s *PackagesSuite) commands() []string {
+	var commands []string
+	for _, run := range s.run {
+		commands = append(commands, run.Commands)
+	}
+	return commands
+}
+
+func writeMetadata(c *gc.C, content string) string {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return dir
+}
+
+func (s *PackagesSuite) TestReadPackages(c *gc.C) {
+	dir := writeMetadata(c, `
+name: wordpress
+system-packages:
+  ppas: [ppa:juju/stable]
+  packages: [php5, libapache2-mod-php5]
+`)
+	pkgs, err := charm.ReadPackages(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pkgs, jc.DeepEquals, &charm.Packages{
+		PPAs:     []string{"ppa:juju/stable"},
+		Packages: []string{"php5", "libapache2-mod-php5"},
+	})
+}
+
+func (s *PackagesSuite) TestReadPackagesNoneDeclared(c *gc.C) {
+	pkgs, err := charm.ReadPackages(writeMetadata(c, "name: wordpress\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pkgs.IsEmpty(), jc.IsTrue)
+
+	pkgs, err = charm.ReadPackages(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pkgs.IsEmpty(), jc.IsTrue)
+}
+
+func (s *PackagesSuite) TestReadPackagesInvalid(c *gc.C) {
+	for i, test := range []struct {
+		metadata string
+		err      string
+	}{{
+		metadata: "system-packages: {packages: [--force-yes]}",
+		err:      `package name "--force-yes" not valid`,
+	}, {
+		metadata: "system-packages: {packages: [foo bar]}",
+		err:      `package name "foo bar" not valid`,
+	}, {
+		metadata: "system-packages: {ppas: [http://example.com/ubuntu]}",
+		err:      `package archive "http://example.com/ubuntu" not valid`,
+	}, {
+		metadata: "system-packages: [foo]",
+		err:      "cannot parse charm metadata: .*",
+	}} {
+		c.Logf("test %d: %s", i, test.metadata)
+		_, err := charm.ReadPackages(writeMetadata(c, test.metadata))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *PackagesSuite) TestInstallPackages(c *gc.C) {
+	hookEnv := []string{"http_proxy=http://squid.internal:3128"}
+	err := charm.InstallPackages("trusty", &charm.Packages{
+		PPAs:     []string{"ppa:juju/stable"},
+		Packages: []string{"php5", "mysql-client"},
+	}, hookEnv)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.commands(), jc.DeepEquals, []string{
+		s.paccmder.AddRepositoryCmd("ppa:juju/stable"),
+		s.paccmder.UpdateCmd(),
+		s.paccmder.InstallCmd("php5", "mysql-client"),
+	})
+	for _, run := range s.run {
+		c.Check(run.Environment, jc.DeepEquals, hookEnv)
+	}
+}
+
+func (s *PackagesSuite) TestInstallPackagesWithoutPPAs(c *gc.C) {
+	err := charm.InstallPackages("trusty", &charm.Packages{
+		Packages: []string{"php5"},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.commands(), jc.DeepEquals, []string{
+		s.paccmder.InstallCmd("php5"),
+	})
+}
+
+func (s *PackagesSuite) TestInstallPackagesNothingDeclared(c *gc.C) {
+	err := charm.InstallPackages("trusty", &charm.Packages{}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.run, gc.HasLen, 0)
+}
+
+func (s *PackagesSuite) TestInstallPackagesError(c *gc.C) {
+	s.result = exec.ExecResponse{Code: 100, Stderr: []byte("E: Unable to locate package php5\n")}
+	err := charm.InstallPackages("trusty", &charm.Packages{
+		Packages: []string{"php5"},
+	}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot install system packages: ".*" failed with code 100: E: Unable to locate package php5`)
+}
@@ -6,10 +6,12 @@ package uniter
 import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/fslock"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/problemreporter"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/charmdir"
@@ -83,6 +85,8 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				CharmDirLocker:       charmDirLocker,
 				UpdateStatusSignal:   NewUpdateStatusTimer(),
 				NewOperationExecutor: operation.NewExecutor,
+				ProblemReporter:      problemreporter.NewState(apiCaller, unitTag),
+				RetryHookTimer:       NewRetryHookTimer(clock.WallClock),
 			}), nil
 		},
 	}
@@ -8,10 +8,12 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"github.com/juju/utils/series"
 	corecharm "gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/problemreporter"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -90,6 +92,27 @@ func (opc *operationCallbacks) NotifyHookFailed(hook string, ctx runner.Context)
 	if opc.u.observer != nil {
 		notifyHook(hook, ctx, opc.u.observer.HookFailed)
 	}
+	if opc.u.problemReporter != nil {
+		notifyHook(hook, ctx, func(hook string) {
+			problemreporter.ReportHookFailure(opc.u.problemReporter, hook)
+		})
+	}
+}
+
+// InstallCharmPackages is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) InstallCharmPackages(ctx runner.Context) error {
+	pkgs, err := charm.ReadPackages(opc.u.paths.State.CharmDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if pkgs.IsEmpty() {
+		return nil
+	}
+	hookEnv, err := ctx.HookVars(opc.u.paths)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return charm.InstallPackages(series.HostSeries(), pkgs, hookEnv)
 }
 
 // FailAction is part of the operation.Callbacks interface.
@@ -138,7 +138,10 @@ func (f *factory) NewUpdateStorage(tags []names.StorageTag) (Operation, error) {
 
 // NewResignLeadership is part of the Factory interface.
 func (f *factory) NewResignLeadership() (Operation, error) {
-	return &resignLeadership{}, nil
+	return &resignLeadership{
+		callbacks:     f.config.Callbacks,
+		runnerFactory: f.config.RunnerFactory,
+	}, nil
 }
 
 // NewAcceptLeadership is part of the Factory interface.
@@ -142,6 +142,12 @@ type Callbacks interface {
 	NotifyHookCompleted(string, runner.Context)
 	NotifyHookFailed(string, runner.Context)
 
+	// InstallCharmPackages installs the system packages declared by the
+	// deployed charm, in the environment of the given hook context.
+	// It's only used by RunHook operations, before the install and
+	// upgrade-charm hooks.
+	InstallCharmPackages(runner.Context) error
+
 	// The following methods exist primarily to allow us to test operation code
 	// without using a live api connection.
 
@@ -7,6 +7,8 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/runner"
+	"github.com/juju/juju/worker/uniter/runner/context"
 )
 
 type acceptLeadership struct {
@@ -61,7 +63,12 @@ func (al *acceptLeadership) checkState(state State) error {
 }
 
 type resignLeadership struct {
-	DoesNotRequireMachineLock
+	callbacks     Callbacks
+	runnerFactory runner.Factory
+
+	runner runner.Runner
+
+	RequiresMachineLock
 }
 
 // String is part of the Operation interface.
@@ -73,46 +80,40 @@ func (rl *resignLeadership) String() string {
 func (rl *resignLeadership) Prepare(state State) (*State, error) {
 	if !state.Leader {
 		// Nothing needs to be done -- state.Leader should only be set to
-		// false when committing the leader-deposed hook. This code is not
-		// helpful while Execute is a no-op, but it will become so.
+		// false when committing the leader-deposed hook.
 		return nil, ErrSkipExecute
 	}
+	rnr, err := rl.runnerFactory.NewHookRunner(hook.Info{Kind: hook.LeaderDeposed})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := rnr.Context().Prepare(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	rl.runner = rnr
 	return nil, nil
 }
 
 // Execute is part of the Operation interface.
+//
+// The leader-deposed hook is run in whatever state the uniter happens to
+// be in, and does not disturb it: any queued or failed hook is still
+// there afterwards. By the same token, a leader-deposed hook that fails
+// does not put the unit into an error state; leadership is gone
+// regardless, so the failure is just reported.
 func (rl *resignLeadership) Execute(state State) (*State, error) {
-	// TODO(fwereade): this hits a lot of interestingly intersecting problems.
-	//
-	// 1) we can't yet create a sufficiently dumbed-down hook context for a
-	//    leader-deposed hook to run as specced. (This is the proximate issue,
-	//    and is sufficient to prevent us from implementing this op right.)
-	// 2) we want to write a state-file change, so this has to be an operation
-	//    (or, at least, it has to be serialized with all other operations).
-	//      * note that the change we write must *not* include the RunHook
-	//        operation for leader-deposed -- we want to run this at high
-	//        priority, in any possible state, and not to disturn what's
-	//        there other than to note that we no longer think we're leader.
-	// 3) the hook execution itself *might* not need to be serialized with
-	//    other operations, which is moot until we consider that:
-	// 4) we want to invoke this behaviour from elsewhere (ie when we don't
-	//    have an api connection available), but:
-	// 5) we can't get around the serialization requirement in (2).
-	//
-	// So. I *think* that the right approach is to implement a no-api uniter
-	// variant, that we run *instead of* the normal uniter when the API is
-	// unavailable, and replace with a real uniter when appropriate; this
-	// implies that we need to take care not to allow the implementations to
-	// diverge, but implementing them both as "uniters" is probably the best
-	// way to encourage logic-sharing and prevent that problem.
-	//
-	// In the short term, though, we can just run leader-deposed as soon as we
-	// can build the right environment. Not sure whether this particular type
-	// will still be justified, or whether it'll just be a plain old RunHook --
-	// I *think* it will stay, because the state-writing behaviour will stay
-	// very different (ie just write `.Leader = false` and don't step on pre-
-	// queued hooks).
-	logger.Warningf("we should run a leader-deposed hook here, but we can't yet")
+	name := string(hook.LeaderDeposed)
+	err := rl.runner.RunHook(name)
+	switch {
+	case context.IsMissingHookError(errors.Cause(err)):
+		logger.Infof("skipped %q hook (missing)", name)
+	case err != nil:
+		logger.Errorf("hook %q failed: %v", name, err)
+		rl.callbacks.NotifyHookFailed(name, rl.runner.Context())
+	default:
+		logger.Infof("ran %q hook", name)
+		rl.callbacks.NotifyHookCompleted(name, rl.runner.Context())
+	}
 	return nil, nil
 }
 
@@ -4,6 +4,7 @@
 package operation_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/runner/context"
 )
 
 type LeaderSuite struct {
@@ -120,13 +122,19 @@ func (s *LeaderSuite) TestAcceptLeadership_DoesNotNeedGlobalMachineLock(c *gc.C)
 }
 
 func (s *LeaderSuite) TestResignLeadership_Prepare_Leader(c *gc.C) {
-	factory := operation.NewFactory(operation.FactoryParams{})
+	runnerFactory := NewRunHookRunnerFactory(nil)
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: runnerFactory,
+	})
 	op, err := factory.NewResignLeadership()
 	c.Assert(err, jc.ErrorIsNil)
 
 	newState, err := op.Prepare(operation.State{Leader: true})
 	c.Check(newState, gc.IsNil)
 	c.Check(err, jc.ErrorIsNil)
+	c.Check(*runnerFactory.MockNewHookRunner.gotHook, gc.DeepEquals, hook.Info{
+		Kind: hook.LeaderDeposed,
+	})
 }
 
 func (s *LeaderSuite) TestResignLeadership_Prepare_NotLeader(c *gc.C) {
@@ -139,18 +147,70 @@ func (s *LeaderSuite) TestResignLeadership_Prepare_NotLeader(c *gc.C) {
 	c.Check(err, gc.Equals, operation.ErrSkipExecute)
 }
 
-func (s *LeaderSuite) TestResignLeadership_Execute(c *gc.C) {
-	factory := operation.NewFactory(operation.FactoryParams{})
+func (s *LeaderSuite) TestResignLeadership_Prepare_BadRunner(c *gc.C) {
+	runnerFactory := &MockRunnerFactory{
+		MockNewHookRunner: &MockNewHookRunner{err: errors.New("splat")},
+	}
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: runnerFactory,
+	})
 	op, err := factory.NewResignLeadership()
 	c.Assert(err, jc.ErrorIsNil)
 
+	newState, err := op.Prepare(operation.State{Leader: true})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "splat")
+}
+
+func (s *LeaderSuite) newResignLeadership(c *gc.C, runErr error) (
+	operation.Operation, *ExecuteHookCallbacks, *MockRunnerFactory,
+) {
+	callbacks := &ExecuteHookCallbacks{
+		MockNotifyHookCompleted: &MockNotify{},
+		MockNotifyHookFailed:    &MockNotify{},
+	}
+	runnerFactory := NewRunHookRunnerFactory(runErr)
+	factory := operation.NewFactory(operation.FactoryParams{
+		Callbacks:     callbacks,
+		RunnerFactory: runnerFactory,
+	})
+	op, err := factory.NewResignLeadership()
+	c.Assert(err, jc.ErrorIsNil)
 	_, err = op.Prepare(operation.State{Leader: true})
+	c.Assert(err, jc.ErrorIsNil)
+	return op, callbacks, runnerFactory
+}
+
+func (s *LeaderSuite) TestResignLeadership_Execute(c *gc.C) {
+	op, callbacks, runnerFactory := s.newResignLeadership(c, nil)
+
+	newState, err := op.Execute(operation.State{})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(*runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "leader-deposed")
+	c.Check(*callbacks.MockNotifyHookCompleted.gotName, gc.Equals, "leader-deposed")
+	c.Check(callbacks.MockNotifyHookFailed.gotName, gc.IsNil)
+}
+
+func (s *LeaderSuite) TestResignLeadership_Execute_HookFailed(c *gc.C) {
+	op, callbacks, _ := s.newResignLeadership(c, errors.New("graaargh"))
+
+	// The failure is reported, but leadership is lost regardless.
+	newState, err := op.Execute(operation.State{})
+	c.Check(newState, gc.IsNil)
 	c.Check(err, jc.ErrorIsNil)
+	c.Check(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "leader-deposed")
+	c.Check(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
+}
+
+func (s *LeaderSuite) TestResignLeadership_Execute_MissingHook(c *gc.C) {
+	op, callbacks, _ := s.newResignLeadership(c, context.NewMissingHookError("blah"))
 
-	// Execute is a no-op (which logs that we should run leader-deposed)
 	newState, err := op.Execute(operation.State{})
 	c.Check(newState, gc.IsNil)
 	c.Check(err, jc.ErrorIsNil)
+	c.Check(callbacks.MockNotifyHookFailed.gotName, gc.IsNil)
+	c.Check(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
 }
 
 func (s *LeaderSuite) TestResignLeadership_Commit_ClearLeader(c *gc.C) {
@@ -185,9 +245,9 @@ func (s *LeaderSuite) TestResignLeadership_Commit_All(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 }
 
-func (s *LeaderSuite) TestResignLeadership_DoesNotNeedGlobalMachineLock(c *gc.C) {
+func (s *LeaderSuite) TestResignLeadership_NeedsGlobalMachineLock(c *gc.C) {
 	factory := operation.NewFactory(operation.FactoryParams{})
 	op, err := factory.NewResignLeadership()
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(op.NeedsGlobalMachineLock(), jc.IsFalse)
+	c.Assert(op.NeedsGlobalMachineLock(), jc.IsTrue)
 }
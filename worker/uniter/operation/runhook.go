@@ -81,6 +81,11 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	if err := rh.beforeHook(); err != nil {
 		return nil, err
 	}
+	if err := rh.installCharmPackages(); err != nil {
+		logger.Errorf("cannot install system packages for %q hook: %v", rh.name, err)
+		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
+		return nil, ErrHookFailed
+	}
 	if err := rh.callbacks.SetExecutingStatus(message); err != nil {
 		return nil, err
 	}
@@ -150,6 +155,17 @@ func (rh *runHook) beforeHook() error {
 	return nil
 }
 
+// installCharmPackages installs the system packages declared by the
+// charm before the install and upgrade-charm hooks, so that the hooks
+// can rely on them being present.
+func (rh *runHook) installCharmPackages() error {
+	switch rh.info.Kind {
+	case hooks.Install, hooks.UpgradeCharm:
+		return rh.callbacks.InstallCharmPackages(rh.runner.Context())
+	}
+	return nil
+}
+
 // afterHook runs after a hook completes, or after a hook that is
 // not implemented by the charm is expected to have run if it were
 // implemented.
@@ -184,6 +184,7 @@ func (s *RunHookSuite) getExecuteRunnerTest(c *gc.C, newHook newHook, kind hooks
 		PrepareHookCallbacks:    NewPrepareHookCallbacks(),
 		MockNotifyHookCompleted: &MockNotify{},
 		MockNotifyHookFailed:    &MockNotify{},
+		MockInstallPackages:     &MockNoArgs{},
 	}
 	factory := operation.NewFactory(operation.FactoryParams{
 		RunnerFactory: runnerFactory,
@@ -194,6 +195,34 @@ func (s *RunHookSuite) getExecuteRunnerTest(c *gc.C, newHook newHook, kind hooks
 	return op, callbacks, runnerFactory
 }
 
+func (s *RunHookSuite) TestExecuteInstallsCharmPackages(c *gc.C) {
+	for _, kind := range hooks.UnitHooks() {
+		c.Logf("hook %v", kind)
+		op, callbacks, _ := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, kind, nil)
+		_, err := op.Prepare(operation.State{})
+		c.Assert(err, jc.ErrorIsNil)
+
+		_, err = op.Execute(operation.State{})
+		c.Assert(err, jc.ErrorIsNil)
+		expect := kind == hooks.Install || kind == hooks.UpgradeCharm
+		c.Assert(callbacks.MockInstallPackages.called, gc.Equals, expect)
+	}
+}
+
+func (s *RunHookSuite) TestExecuteInstallCharmPackagesError(c *gc.C) {
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.Install, nil)
+	callbacks.MockInstallPackages.err = errors.New("apt-get failed")
+	_, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(operation.State{})
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, gc.IsNil)
+	c.Assert(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "some-hook-name")
+	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
+	c.Assert(runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.IsNil)
+}
+
 func (s *RunHookSuite) TestExecuteMissingHookError(c *gc.C) {
 	runErr := context.NewMissingHookError("blah-blah")
 	for _, kind := range hooks.UnitHooks() {
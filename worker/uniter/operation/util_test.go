@@ -185,6 +185,11 @@ type ExecuteHookCallbacks struct {
 	*PrepareHookCallbacks
 	MockNotifyHookCompleted *MockNotify
 	MockNotifyHookFailed    *MockNotify
+	MockInstallPackages     *MockNoArgs
+}
+
+func (cb *ExecuteHookCallbacks) InstallCharmPackages(ctx runner.Context) error {
+	return cb.MockInstallPackages.Call()
 }
 
 func (cb *ExecuteHookCallbacks) NotifyHookCompleted(hookName string, ctx runner.Context) {
@@ -53,6 +53,11 @@ func (paths Paths) GetMetricsSpoolDir() string {
 	return paths.State.MetricsSpoolDir
 }
 
+// GetProcessesFile exists to satisfy the runner.Paths interface.
+func (paths Paths) GetProcessesFile() string {
+	return paths.State.ProcessesFile
+}
+
 // RuntimePaths represents the set of paths that are relevant at runtime.
 type RuntimePaths struct {
 
@@ -94,6 +99,9 @@ type StatePaths struct {
 	// MetricsSpoolDir acts as temporary storage for metrics being sent from
 	// the uniter to state.
 	MetricsSpoolDir string
+
+	// ProcessesFile holds the workload processes tracked by the unit.
+	ProcessesFile string
 }
 
 // NewPaths returns the set of filesystem paths that the supplied unit should
@@ -143,6 +151,7 @@ func NewWorkerPaths(dataDir string, unitTag names.UnitTag, worker string) Paths
 			DeployerDir:     join(stateDir, "deployer"),
 			StorageDir:      join(stateDir, "storage"),
 			MetricsSpoolDir: join(stateDir, "spool", "metrics"),
+			ProcessesFile:   join(stateDir, "processes"),
 		},
 	}
 }
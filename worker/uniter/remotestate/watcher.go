@@ -34,6 +34,7 @@ type RemoteStateWatcher struct {
 	storageAttachmentChanges  chan storageAttachmentChange
 	leadershipTracker         leadership.Tracker
 	updateStatusChannel       func() <-chan time.Time
+	retryHookChannel          <-chan struct{}
 
 	tomb tomb.Tomb
 
@@ -48,6 +49,7 @@ type WatcherConfig struct {
 	State               State
 	LeadershipTracker   leadership.Tracker
 	UpdateStatusChannel func() <-chan time.Time
+	RetryHookChannel    <-chan struct{}
 	UnitTag             names.UnitTag
 }
 
@@ -62,6 +64,7 @@ func NewWatcher(config WatcherConfig) (*RemoteStateWatcher, error) {
 		storageAttachmentChanges:  make(chan storageAttachmentChange),
 		leadershipTracker:         config.LeadershipTracker,
 		updateStatusChannel:       config.UpdateStatusChannel,
+		retryHookChannel:          config.RetryHookChannel,
 		// Note: it is important that the out channel be buffered!
 		// The remote state watcher will perform a non-blocking send
 		// on the channel to wake up the observer. It is non-blocking
@@ -390,6 +393,10 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 			if err := w.updateStatusChanged(); err != nil {
 				return err
 			}
+
+		case <-w.retryHookChannel:
+			logger.Debugf("retry hook timer triggered")
+			w.retryHookChanged()
 		}
 
 		// Something changed.
@@ -405,6 +412,13 @@ func (w *RemoteStateWatcher) updateStatusChanged() error {
 	return nil
 }
 
+// retryHookChanged is called when the retry hook timer expires.
+func (w *RemoteStateWatcher) retryHookChanged() {
+	w.mu.Lock()
+	w.current.RetryHookVersion++
+	w.mu.Unlock()
+}
+
 // unitChanged responds to changes in the unit.
 func (w *RemoteStateWatcher) unitChanged() error {
 	if err := w.unit.Refresh(); err != nil {
@@ -414,10 +428,18 @@ func (w *RemoteStateWatcher) unitChanged() error {
 	if err != nil {
 		return err
 	}
+	// The charm URL the unit should run may change without the service
+	// changing, when a rolling upgrade allows the unit to upgrade.
+	url, force, err := w.service.CharmURL()
+	if err != nil {
+		return err
+	}
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.current.Life = w.unit.Life()
 	w.current.ResolvedMode = resolved
+	w.current.CharmURL = url
+	w.current.ForceCharmUpgrade = force
 	return nil
 }
 
@@ -24,6 +24,7 @@ type WatcherSuite struct {
 	leadership mockLeadershipTracker
 	watcher    *remotestate.RemoteStateWatcher
 	clock      *testing.Clock
+	retryHook  chan struct{}
 }
 
 // Duration is arbitrary, we'll trigger the ticker
@@ -73,11 +74,14 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 		return s.clock.After(statusTickDuration)
 	}
 
+	s.retryHook = make(chan struct{}, 1)
+
 	w, err := remotestate.NewWatcher(remotestate.WatcherConfig{
 		State:               &s.st,
 		LeadershipTracker:   &s.leadership,
 		UnitTag:             s.st.unit.tag,
 		UpdateStatusChannel: statusTicker,
+		RetryHookChannel:    s.retryHook,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.watcher = w
@@ -530,3 +534,17 @@ func (s *WatcherSuite) TestUpdateStatusTicker(c *gc.C) {
 	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
 	c.Assert(s.watcher.Snapshot().UpdateStatusVersion, gc.Equals, initial.UpdateStatusVersion+2)
 }
+
+func (s *WatcherSuite) TestRetryHookChannel(c *gc.C) {
+	signalAll(&s.st, &s.leadership)
+	initial := s.watcher.Snapshot()
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	s.retryHook <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().RetryHookVersion, gc.Equals, initial.RetryHookVersion+1)
+
+	s.retryHook <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().RetryHookVersion, gc.Equals, initial.RetryHookVersion+2)
+}
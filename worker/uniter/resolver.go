@@ -15,9 +15,15 @@ import (
 )
 
 type uniterResolver struct {
-	clearResolved   func() error
-	reportHookError func(hook.Info) error
-	fixDeployer     func() error
+	clearResolved       func() error
+	reportHookError     func(hook.Info) error
+	fixDeployer         func() error
+	startRetryHookTimer func() error
+	stopRetryHookTimer  func()
+
+	// retryHookTimerStarted records whether the retry hook
+	// timer has been started for the current hook error.
+	retryHookTimerStarted bool
 
 	leadershipResolver resolver.Resolver
 	actionsResolver    resolver.Resolver
@@ -29,19 +35,23 @@ func newUniterResolver(
 	clearResolved func() error,
 	reportHookError func(hook.Info) error,
 	fixDeployer func() error,
+	startRetryHookTimer func() error,
+	stopRetryHookTimer func(),
 	leadershipResolver resolver.Resolver,
 	actionsResolver resolver.Resolver,
 	relationsResolver resolver.Resolver,
 	storageResolver resolver.Resolver,
 ) *uniterResolver {
 	return &uniterResolver{
-		clearResolved:      clearResolved,
-		reportHookError:    reportHookError,
-		fixDeployer:        fixDeployer,
-		leadershipResolver: leadershipResolver,
-		actionsResolver:    actionsResolver,
-		relationsResolver:  relationsResolver,
-		storageResolver:    storageResolver,
+		clearResolved:       clearResolved,
+		reportHookError:     reportHookError,
+		fixDeployer:         fixDeployer,
+		startRetryHookTimer: startRetryHookTimer,
+		stopRetryHookTimer:  stopRetryHookTimer,
+		leadershipResolver:  leadershipResolver,
+		actionsResolver:     actionsResolver,
+		relationsResolver:   relationsResolver,
+		storageResolver:     storageResolver,
 	}
 }
 
@@ -117,6 +127,12 @@ func (s *uniterResolver) NextOp(
 
 	case operation.Continue:
 		logger.Infof("no operations in progress; waiting for changes")
+		// There's no hook error, so any retries, and the
+		// backoff between them, are finished with.
+		if s.retryHookTimerStarted {
+			s.stopRetryHookTimer()
+			s.retryHookTimerStarted = false
+		}
 		return s.nextOp(localState, remoteState, opFactory)
 
 	default:
@@ -163,6 +179,20 @@ func (s *uniterResolver) nextOpHookError(
 
 	switch remoteState.ResolvedMode {
 	case params.ResolvedNone:
+		if remoteState.RetryHookVersion > localState.RetryHookVersion {
+			// The retry timer has fired. If the hook fails
+			// again we'll start the timer again, with a longer
+			// delay; if it succeeds, the timer is stopped.
+			logger.Infof("retrying failed %q hook", localState.Hook.Kind)
+			s.retryHookTimerStarted = false
+			return opFactory.NewRunHook(*localState.Hook)
+		}
+		if !s.retryHookTimerStarted {
+			if err := s.startRetryHookTimer(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			s.retryHookTimerStarted = true
+		}
 		return nil, resolver.ErrNoOperation
 	case params.ResolvedRetryHooks:
 		if err := s.clearResolved(); err != nil {
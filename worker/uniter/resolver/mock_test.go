@@ -84,6 +84,10 @@ type mockOp struct {
 	commit func(operation.State) (*operation.State, error)
 }
 
+func (op mockOp) Prepare(st operation.State) (*operation.State, error) {
+	return &st, nil
+}
+
 func (op mockOp) Commit(st operation.State) (*operation.State, error) {
 	if op.commit != nil {
 		return op.commit(st)
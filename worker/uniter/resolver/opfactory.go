@@ -122,9 +122,17 @@ func (s *resolverOpFactory) wrapHookOp(op operation.Operation, info hook.Info) o
 	// No matter what has finished running, we reset the UpdateStatusVersion so that
 	// the update-status hook only fires after the next timer.
 	v := s.RemoteState.UpdateStatusVersion
-	return onCommitWrapper{op, func() {
+	op = onCommitWrapper{op, func() {
 		s.LocalState.UpdateStatusVersion = v
 	}}
+
+	// Record the retry hook version as soon as the hook is attempted,
+	// rather than when it's committed, so that a hook that fails again
+	// waits for the next retry signal.
+	retryHookVersion := s.RemoteState.RetryHookVersion
+	return onPrepareWrapper{op, func() {
+		s.LocalState.RetryHookVersion = retryHookVersion
+	}}
 }
 
 type onCommitWrapper struct {
@@ -147,3 +155,20 @@ func onCommit(op operation.Operation) {
 		onCommit(wrapper.Operation)
 	}
 }
+
+// onPrepareWrapper wraps an operation, calling f when it is prepared.
+// It must wrap any onCommitWrappers, rather than be wrapped by them,
+// so that onCommit can find them all.
+type onPrepareWrapper struct {
+	operation.Operation
+	f func()
+}
+
+func (op onPrepareWrapper) Prepare(state operation.State) (*operation.State, error) {
+	st, err := op.Operation.Prepare(state)
+	if err != nil {
+		return nil, err
+	}
+	op.f()
+	return st, nil
+}
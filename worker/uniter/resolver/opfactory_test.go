@@ -60,6 +60,31 @@ func (s *ResolverOpFactorySuite) testUpdateStatusChanged(
 	c.Assert(f.LocalState.UpdateStatusVersion, gc.Equals, 1)
 }
 
+func (s *ResolverOpFactorySuite) TestRetryHookVersion(c *gc.C) {
+	f := resolver.NewResolverOpFactory(s.opFactory)
+	f.RemoteState.RetryHookVersion = 1
+	f.RemoteState.ConfigVersion = 1
+
+	op, err := f.NewRunHook(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	f.RemoteState.RetryHookVersion = 2
+	c.Assert(f.LocalState.RetryHookVersion, gc.Equals, 0)
+
+	// Local state's RetryHookVersion should be set to what
+	// RemoteState's RetryHookVersion was when the operation
+	// was constructed, as soon as it is prepared; the hook
+	// may yet fail.
+	_, err = op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.LocalState.RetryHookVersion, gc.Equals, 1)
+
+	// Committing still records the other versions.
+	_, err = op.Commit(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.LocalState.RetryHookVersion, gc.Equals, 1)
+	c.Assert(f.LocalState.ConfigVersion, gc.Equals, 1)
+}
+
 func (s *ResolverOpFactorySuite) TestConfigChanged(c *gc.C) {
 	s.testConfigChanged(c, resolver.ResolverOpFactory.NewRunHook)
 	s.testConfigChanged(c, resolver.ResolverOpFactory.NewSkipHook)
@@ -9,7 +9,9 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter"
 	uniteractions "github.com/juju/juju/worker/uniter/actions"
 	"github.com/juju/juju/worker/uniter/hook"
@@ -26,6 +28,10 @@ type resolverSuite struct {
 	remoteState remotestate.Snapshot
 	opFactory   operation.Factory
 	resolver    resolver.Resolver
+
+	attachments          *storage.Attachments
+	retryHookTimerStarts int
+	retryHookTimerStops  int
 }
 
 var _ = gc.Suite(&resolverSuite{})
@@ -39,11 +45,19 @@ func (s *resolverSuite) SetUpTest(c *gc.C) {
 
 	attachments, err := storage.NewAttachments(&dummyStorageAccessor{}, names.NewUnitTag("u/0"), c.MkDir(), nil)
 	c.Assert(err, jc.ErrorIsNil)
+	s.attachments = attachments
+	s.retryHookTimerStarts = 0
+	s.retryHookTimerStops = 0
 
 	s.resolver = uniter.NewUniterResolver(
 		func() error { return errors.New("unexpected resolved") },
-		func(_ hook.Info) error { return errors.New("unexpected report hook error") },
+		func(_ hook.Info) error { return nil },
 		func() error { return nil },
+		func() error {
+			s.retryHookTimerStarts++
+			return nil
+		},
+		func() { s.retryHookTimerStops++ },
 		uniteractions.NewResolver(),
 		leadership.NewResolver(),
 		relation.NewRelationsResolver(&dummyRelations{}),
@@ -82,3 +96,72 @@ func (s *resolverSuite) TestNotStartedNotInstalled(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(op.String(), gc.Equals, "run install hook")
 }
+
+func (s *resolverSuite) hookErrorState() resolver.LocalState {
+	return resolver.LocalState{
+		CharmURL: s.charmURL,
+		State: operation.State{
+			Kind:      operation.RunHook,
+			Step:      operation.Pending,
+			Installed: true,
+			Started:   true,
+			Hook:      &hook.Info{Kind: hooks.ConfigChanged},
+		},
+	}
+}
+
+func (s *resolverSuite) TestHookErrorStartsRetryHookTimer(c *gc.C) {
+	localState := s.hookErrorState()
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(s.retryHookTimerStarts, gc.Equals, 1)
+
+	// The timer is only started once for each failure.
+	_, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(s.retryHookTimerStarts, gc.Equals, 1)
+}
+
+func (s *resolverSuite) TestHookErrorRetriedWhenTimerFires(c *gc.C) {
+	localState := s.hookErrorState()
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+
+	s.remoteState.RetryHookVersion = 1
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run config-changed hook")
+
+	// If the hook fails again, the timer is restarted.
+	localState.RetryHookVersion = 1
+	_, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(s.retryHookTimerStarts, gc.Equals, 2)
+	c.Assert(s.retryHookTimerStops, gc.Equals, 0)
+
+	// Once it succeeds, the timer is stopped.
+	localState.Kind = operation.Continue
+	localState.Hook = nil
+	_, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(s.retryHookTimerStops, gc.Equals, 1)
+}
+
+func (s *resolverSuite) TestHookErrorResolvedManually(c *gc.C) {
+	s.resolver = uniter.NewUniterResolver(
+		func() error { return nil },
+		func(_ hook.Info) error { return nil },
+		func() error { return nil },
+		func() error { return errors.New("timer should not be started") },
+		func() {},
+		uniteractions.NewResolver(),
+		leadership.NewResolver(),
+		relation.NewRelationsResolver(&dummyRelations{}),
+		storage.NewResolver(s.attachments),
+	)
+	// Resolving the unit takes precedence over waiting for a retry.
+	s.remoteState.ResolvedMode = params.ResolvedNoHooks
+	op, err := s.resolver.NextOp(s.hookErrorState(), s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "skip run config-changed hook")
+}
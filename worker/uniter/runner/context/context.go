@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/process"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
@@ -43,6 +44,10 @@ type Paths interface {
 	// GetMetricsSpoolDir returns the path to a metrics spool dir, used
 	// to store metrics recorded during a single hook run.
 	GetMetricsSpoolDir() string
+
+	// GetProcessesFile returns the path to the file holding the
+	// workload processes tracked by the unit.
+	GetProcessesFile() string
 }
 
 var logger = loggo.GetLogger("juju.worker.uniter.context")
@@ -164,6 +169,19 @@ type HookContext struct {
 	// This collection will be added to the unit on successful
 	// hook run, so the actual add will happen in a flush.
 	storageAddConstraints map[string][]params.StorageConstraints
+
+	// processesFile is the path to the file holding the workload
+	// processes tracked by the unit.
+	processesFile string
+
+	// processes holds the workload processes tracked by the unit,
+	// keyed on name. It is nil until first used.
+	processes map[string]process.Info
+
+	// processesChanged is true if the tracked processes were changed
+	// during the hook, in which case they are written out when the
+	// context is flushed.
+	processesChanged bool
 }
 
 func (ctx *HookContext) RequestReboot(priority jujuc.RebootPriority) error {
@@ -644,6 +662,15 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 		}
 	}
 
+	if ctx.processesChanged && writeChanges {
+		if err := ctx.writeProcesses(); err != nil {
+			logger.Errorf("%v", err)
+			if ctxErr == nil {
+				ctxErr = err
+			}
+		}
+	}
+
 	// TODO (tasdomas) 2014 09 03: context finalization needs to modified to apply all
 	//                             changes in one api call to minimize the risk
 	//                             of partial failures.
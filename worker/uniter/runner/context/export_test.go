@@ -19,6 +19,7 @@ var (
 	ValidatePortRange = validatePortRange
 	TryOpenPorts      = tryOpenPorts
 	TryClosePorts     = tryClosePorts
+	LaunchPlugin      = &launchPlugin
 )
 
 func NewHookContext(
@@ -181,3 +182,15 @@ func CachedSettings(cf0 ContextFactory, relId int, unitName string) (params.Sett
 	settings, found := cf.relationCaches[relId].members[unitName]
 	return settings, found
 }
+
+// NewProcessesHookContext returns a HookContext that tracks the
+// workload processes of the unit in the given file, and supports
+// nothing else.
+func NewProcessesHookContext(unit *uniter.Unit, processesFile string) *HookContext {
+	return &HookContext{
+		unit:          unit,
+		unitName:      unit.Name(),
+		processesFile: processesFile,
+		relationId:    -1,
+	}
+}
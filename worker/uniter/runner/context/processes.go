@@ -0,0 +1,202 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/process"
+)
+
+var launchPlugin = process.LaunchPlugin
+
+// processesDoc is the serialisation of the tracked processes. It is
+// stored as JSON, so that process details read back from the file
+// have the same types as when they were parsed.
+type processesDoc struct {
+	Processes []process.Info `json:"processes"`
+}
+
+// ReadProcessesFile returns the processes recorded in the processes
+// file at the given path. A missing file holds no processes.
+func ReadProcessesFile(path string) ([]process.Info, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read tracked processes")
+	}
+	var doc processesDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Annotate(err, "cannot read tracked processes")
+	}
+	return doc.Processes, nil
+}
+
+// WriteProcessesFile replaces the contents of the processes file at
+// the given path with the supplied processes.
+func WriteProcessesFile(path string, infos []process.Info) error {
+	data, err := json.Marshal(processesDoc{Processes: infos})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = utils.AtomicWriteFile(path, data, 0600)
+	return errors.Annotate(err, "cannot write tracked processes")
+}
+
+// loadProcesses reads the tracked processes from the processes file,
+// if they have not already been read.
+func (ctx *HookContext) loadProcesses() error {
+	if ctx.processes != nil {
+		return nil
+	}
+	infos, err := ReadProcessesFile(ctx.processesFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.processes = make(map[string]process.Info)
+	for _, info := range infos {
+		ctx.processes[info.Name] = info
+	}
+	return nil
+}
+
+// writeProcesses writes the tracked processes to the processes file,
+// and records them in state.
+func (ctx *HookContext) writeProcesses() error {
+	infos, err := ctx.Processes()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := WriteProcessesFile(ctx.processesFile, infos); err != nil {
+		return errors.Trace(err)
+	}
+	err = ctx.unit.SetProcesses(infos)
+	return errors.Annotate(err, "cannot record tracked processes")
+}
+
+// Processes implements jujuc.ContextProcesses.
+func (ctx *HookContext) Processes() ([]process.Info, error) {
+	if err := ctx.loadProcesses(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var names []string
+	for name := range ctx.processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	infos := make([]process.Info, len(names))
+	for i, name := range names {
+		infos[i] = ctx.processes[name]
+	}
+	return infos, nil
+}
+
+// TrackProcess implements jujuc.ContextProcesses. The change is
+// recorded when the context is flushed.
+func (ctx *HookContext) TrackProcess(info process.Info) error {
+	if err := info.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ctx.loadProcesses(); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.processes[info.Name] = info
+	ctx.processesChanged = true
+	return nil
+}
+
+// SetProcessStatus implements jujuc.ContextProcesses. The change is
+// recorded when the context is flushed.
+func (ctx *HookContext) SetProcessStatus(name, status string) error {
+	if err := ctx.loadProcesses(); err != nil {
+		return errors.Trace(err)
+	}
+	info, ok := ctx.processes[name]
+	if !ok {
+		return errors.NotFoundf("process %q", name)
+	}
+	info.Details.Status = status
+	ctx.processes[name] = info
+	ctx.processesChanged = true
+	return nil
+}
+
+// UntrackProcess implements jujuc.ContextProcesses. The change is
+// recorded when the context is flushed.
+func (ctx *HookContext) UntrackProcess(name string) error {
+	if err := ctx.loadProcesses(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := ctx.processes[name]; !ok {
+		return errors.NotFoundf("process %q", name)
+	}
+	delete(ctx.processes, name)
+	ctx.processesChanged = true
+	return nil
+}
+
+// LaunchProcess implements jujuc.ContextProcesses. The process is
+// launched immediately, but is only tracked once the context is
+// flushed.
+func (ctx *HookContext) LaunchProcess(def process.Definition) (process.Info, error) {
+	if err := def.Validate(); err != nil {
+		return process.Info{}, errors.Trace(err)
+	}
+	if err := ctx.loadProcesses(); err != nil {
+		return process.Info{}, errors.Trace(err)
+	}
+	if _, ok := ctx.processes[def.Name]; ok {
+		return process.Info{}, errors.AlreadyExistsf("process %q", def.Name)
+	}
+	plugin, err := launchPlugin(def.Type)
+	if err != nil {
+		return process.Info{}, errors.Trace(err)
+	}
+	details, err := plugin.Launch(def)
+	if err != nil {
+		return process.Info{}, errors.Trace(err)
+	}
+	info := process.Info{
+		Name:       def.Name,
+		Type:       def.Type,
+		Details:    details,
+		Definition: &def,
+	}
+	if err := ctx.TrackProcess(info); err != nil {
+		// The plugin launched something we cannot track; get rid of
+		// it rather than leak it.
+		if err := plugin.Destroy(details.ID); err != nil {
+			logger.Errorf("cannot destroy untracked process %q: %v", def.Name, err)
+		}
+		return process.Info{}, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// DestroyProcess implements jujuc.ContextProcesses. The process is
+// destroyed immediately, and is untracked when the context is flushed.
+func (ctx *HookContext) DestroyProcess(name string) error {
+	if err := ctx.loadProcesses(); err != nil {
+		return errors.Trace(err)
+	}
+	info, ok := ctx.processes[name]
+	if !ok {
+		return errors.NotFoundf("process %q", name)
+	}
+	plugin, err := launchPlugin(info.Type)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := plugin.Destroy(info.Details.ID); err != nil {
+		return errors.Trace(err)
+	}
+	return ctx.UntrackProcess(name)
+}
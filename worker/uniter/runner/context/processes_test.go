@@ -0,0 +1,256 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context_test
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+type ProcessesSuite struct {
+	HookContextSuite
+	path string
+	stub testing.Stub
+}
+
+var _ = gc.Suite(&ProcessesSuite{})
+
+var (
+	webProcess = process.Info{
+		Name:    "web",
+		Type:    "docker",
+		Details: process.Details{ID: "abc", Status: "running"},
+	}
+	dbProcess = process.Info{
+		Name:    "db",
+		Type:    "exec",
+		Details: process.Details{ID: "42", Status: "starting"},
+	}
+)
+
+func (s *ProcessesSuite) SetUpTest(c *gc.C) {
+	s.HookContextSuite.SetUpTest(c)
+	s.path = filepath.Join(c.MkDir(), "processes")
+	s.stub.ResetCalls()
+	s.PatchValue(context.LaunchPlugin, func(processType string) (process.Plugin, error) {
+		s.stub.AddCall("LaunchPlugin", processType)
+		if err := s.stub.NextErr(); err != nil {
+			return nil, err
+		}
+		return &fakePlugin{&s.stub}, nil
+	})
+}
+
+func (s *ProcessesSuite) newContext() *context.HookContext {
+	return context.NewProcessesHookContext(s.apiUnit, s.path)
+}
+
+func (s *ProcessesSuite) track(c *gc.C, infos ...process.Info) {
+	ctx := s.newContext()
+	for _, info := range infos {
+		err := ctx.TrackProcess(info)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := ctx.Flush("test", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ProcessesSuite) TestProcessesNoneTracked(c *gc.C) {
+	ctx := s.newContext()
+	infos, err := ctx.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 0)
+}
+
+func (s *ProcessesSuite) TestTrackPersistsOnFlush(c *gc.C) {
+	s.track(c, webProcess, dbProcess)
+
+	ctx := s.newContext()
+	infos, err := ctx.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{dbProcess, webProcess})
+
+	infos, err = s.unit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{dbProcess, webProcess})
+}
+
+func (s *ProcessesSuite) TestTrackInvalid(c *gc.C) {
+	ctx := s.newContext()
+	invalid := webProcess
+	invalid.Details.ID = ""
+	err := ctx.TrackProcess(invalid)
+	c.Assert(err, gc.ErrorMatches, `process "web" with empty id not valid`)
+}
+
+func (s *ProcessesSuite) TestChangesDiscardedOnHookError(c *gc.C) {
+	s.track(c, webProcess)
+
+	ctx := s.newContext()
+	err := ctx.SetProcessStatus("web", "stopped")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.TrackProcess(dbProcess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.Flush("test", errors.New("hook failed"))
+	c.Assert(err, gc.ErrorMatches, "hook failed")
+
+	ctx = s.newContext()
+	infos, err := ctx.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{webProcess})
+
+	infos, err = s.unit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{webProcess})
+}
+
+func (s *ProcessesSuite) TestSetStatusAndUntrack(c *gc.C) {
+	s.track(c, webProcess, dbProcess)
+
+	ctx := s.newContext()
+	err := ctx.SetProcessStatus("web", "stopped")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.UntrackProcess("db")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.Flush("test", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx = s.newContext()
+	infos, err := ctx.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	stopped := webProcess
+	stopped.Details.Status = "stopped"
+	c.Assert(infos, jc.DeepEquals, []process.Info{stopped})
+}
+
+func (s *ProcessesSuite) TestNotTracked(c *gc.C) {
+	ctx := s.newContext()
+	err := ctx.SetProcessStatus("web", "stopped")
+	c.Assert(err, gc.ErrorMatches, `process "web" not found`)
+	err = ctx.UntrackProcess("web")
+	c.Assert(err, gc.ErrorMatches, `process "web" not found`)
+}
+
+func (s *ProcessesSuite) TestLaunchProcess(c *gc.C) {
+	ctx := s.newContext()
+	def := process.Definition{
+		Name:        "cache",
+		Type:        "exec",
+		Command:     "memcached",
+		HealthCheck: &process.HealthCheck{Type: "tcp", Target: "localhost:11211"},
+		Restart:     process.RestartOnFailure,
+	}
+	info, err := ctx.LaunchProcess(def)
+	c.Assert(err, jc.ErrorIsNil)
+	expected := process.Info{
+		Name:    "cache",
+		Type:    "exec",
+		Details: process.Details{ID: "cache-id", Status: "running"},
+	}
+	c.Assert(info, jc.DeepEquals, process.Info{
+		Name:       expected.Name,
+		Type:       expected.Type,
+		Details:    expected.Details,
+		Definition: &def,
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"LaunchPlugin", []interface{}{"exec"}},
+		{"Launch", []interface{}{def}},
+	})
+
+	err = ctx.Flush("test", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The definition is kept by the unit agent, so that the process
+	// can be relaunched, but is not recorded in state.
+	infos, err := context.ReadProcessesFile(s.path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{info})
+	infos, err = s.unit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{expected})
+}
+
+func (s *ProcessesSuite) TestLaunchProcessAlreadyTracked(c *gc.C) {
+	s.track(c, webProcess)
+
+	ctx := s.newContext()
+	_, err := ctx.LaunchProcess(process.Definition{Name: "web", Type: "docker", Image: "nginx"})
+	c.Assert(err, gc.ErrorMatches, `process "web" already exists`)
+	s.stub.CheckCalls(c, nil)
+}
+
+func (s *ProcessesSuite) TestLaunchProcessError(c *gc.C) {
+	s.stub.SetErrors(nil, errors.New("boom"))
+	ctx := s.newContext()
+	_, err := ctx.LaunchProcess(process.Definition{Name: "web", Type: "docker", Image: "nginx"})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	infos, err := ctx.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 0)
+}
+
+func (s *ProcessesSuite) TestDestroyProcess(c *gc.C) {
+	s.track(c, webProcess, dbProcess)
+
+	ctx := s.newContext()
+	err := ctx.DestroyProcess("web")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"LaunchPlugin", []interface{}{"docker"}},
+		{"Destroy", []interface{}{"abc"}},
+	})
+
+	err = ctx.Flush("test", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	infos, err := s.unit.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{dbProcess})
+}
+
+func (s *ProcessesSuite) TestDestroyProcessError(c *gc.C) {
+	s.track(c, webProcess)
+
+	s.stub.SetErrors(nil, errors.New("boom"))
+	ctx := s.newContext()
+	err := ctx.DestroyProcess("web")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	infos, err := ctx.Processes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, jc.DeepEquals, []process.Info{webProcess})
+
+	err = ctx.DestroyProcess("db")
+	c.Assert(err, gc.ErrorMatches, `process "db" not found`)
+}
+
+// fakePlugin is a process.Plugin that records its calls, and launches
+// processes with the ID "<name>-id".
+type fakePlugin struct {
+	stub *testing.Stub
+}
+
+func (p *fakePlugin) Launch(def process.Definition) (process.Details, error) {
+	p.stub.AddCall("Launch", def)
+	if err := p.stub.NextErr(); err != nil {
+		return process.Details{}, err
+	}
+	return process.Details{ID: def.Name + "-id", Status: "running"}, nil
+}
+
+func (p *fakePlugin) Status(id string) (string, error) {
+	p.stub.AddCall("Status", id)
+	return "running", p.stub.NextErr()
+}
+
+func (p *fakePlugin) Destroy(id string) error {
+	p.stub.AddCall("Destroy", id)
+	return p.stub.NextErr()
+}
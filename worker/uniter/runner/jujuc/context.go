@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/process"
 	"github.com/juju/juju/storage"
 )
 
@@ -50,6 +51,7 @@ type HookContext interface {
 	ContextLeadership
 	ContextMetrics
 	ContextStorage
+	ContextProcesses
 	ContextRelations
 }
 
@@ -202,6 +204,34 @@ type ContextStorage interface {
 	AddUnitStorage(map[string]params.StorageConstraints) error
 }
 
+// ContextProcesses is the part of a hook context related to the
+// workload processes launched by the unit's charm.
+type ContextProcesses interface {
+	// Processes returns the workload processes tracked by the unit,
+	// sorted by name.
+	Processes() ([]process.Info, error)
+
+	// TrackProcess records the supplied workload process, replacing
+	// any process already tracked with the same name.
+	TrackProcess(process.Info) error
+
+	// SetProcessStatus updates the status of the named workload
+	// process, returning a NotFound error if it is not tracked.
+	SetProcessStatus(name, status string) error
+
+	// UntrackProcess stops tracking the named workload process,
+	// returning a NotFound error if it is not tracked.
+	UntrackProcess(name string) error
+
+	// LaunchProcess launches the defined workload process using the
+	// plugin for its type, and tracks it.
+	LaunchProcess(process.Definition) (process.Info, error)
+
+	// DestroyProcess destroys the named workload process using the
+	// plugin for its type, and stops tracking it.
+	DestroyProcess(name string) error
+}
+
 // ContextRelations exposes the relations associated with the unit.
 type ContextRelations interface {
 	// Relation returns the relation with the supplied id if it was found, and
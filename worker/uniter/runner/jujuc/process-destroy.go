@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// ProcessDestroyCommand implements the process-destroy command.
+type ProcessDestroyCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	name string
+}
+
+// NewProcessDestroyCommand makes a jujuc process-destroy command.
+func NewProcessDestroyCommand(ctx Context) (cmd.Command, error) {
+	return &ProcessDestroyCommand{ctx: ctx}, nil
+}
+
+func (c *ProcessDestroyCommand) Info() *cmd.Info {
+	doc := `
+process-destroy destroys a workload process previously launched with
+process-launch, or tracked with process-track if its type has a launch
+plugin, and stops tracking it.
+`
+	return &cmd.Info{
+		Name:    "process-destroy",
+		Args:    "<name>",
+		Purpose: "destroy a workload process",
+		Doc:     doc,
+	}
+}
+
+func (c *ProcessDestroyCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no process name specified")
+	}
+	c.name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *ProcessDestroyCommand) Run(ctx *cmd.Context) error {
+	err := c.ctx.DestroyProcess(c.name)
+	return errors.Annotatef(err, "cannot destroy process %q", c.name)
+}
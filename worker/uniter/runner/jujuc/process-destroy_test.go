@@ -0,0 +1,37 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "gopkg.in/check.v1"
+)
+
+type processDestroySuite struct {
+	processSuite
+}
+
+var _ = gc.Suite(&processDestroySuite{})
+
+func (s *processDestroySuite) TestDestroy(c *gc.C) {
+	hctx, info := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-destroy", "web")
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	s.Stub.CheckCall(c, 0, "DestroyProcess", "web")
+	c.Check(info.Processes.Processes, gc.HasLen, 0)
+}
+
+func (s *processDestroySuite) TestDestroyNotTracked(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-destroy", "db")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot destroy process \"db\": process \"db\" not found\n")
+}
+
+func (s *processDestroySuite) TestInitErrors(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-destroy")
+	c.Check(code, gc.Equals, 2)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: no process name specified\n")
+}
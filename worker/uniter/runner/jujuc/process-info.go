@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// ProcessInfoCommand implements the process-info command.
+type ProcessInfoCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	name string
+	out  cmd.Output
+}
+
+// NewProcessInfoCommand makes a jujuc process-info command.
+func NewProcessInfoCommand(ctx Context) (cmd.Command, error) {
+	return &ProcessInfoCommand{ctx: ctx}, nil
+}
+
+func (c *ProcessInfoCommand) Info() *cmd.Info {
+	doc := `
+process-info prints the workload processes tracked by the unit, whether
+launched with process-launch or recorded with process-track. If <name>
+is specified, only that process is printed, and it is an error if it is
+not tracked.
+`
+	return &cmd.Info{
+		Name:    "process-info",
+		Args:    "[<name>]",
+		Purpose: "print information about tracked workload processes",
+		Doc:     doc,
+	}
+}
+
+func (c *ProcessInfoCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "json", cmd.DefaultFormatters)
+}
+
+func (c *ProcessInfoCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.name, args = args[0], args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ProcessInfoCommand) Run(ctx *cmd.Context) error {
+	infos, err := c.ctx.Processes()
+	if err != nil {
+		return errors.Annotate(err, "cannot get processes")
+	}
+	if c.name == "" {
+		return c.out.Write(ctx, infos)
+	}
+	for _, info := range infos {
+		if info.Name == c.name {
+			return c.out.Write(ctx, info)
+		}
+	}
+	return errors.NotFoundf("process %q", c.name)
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+)
+
+type processInfoSuite struct {
+	processSuite
+}
+
+var _ = gc.Suite(&processInfoSuite{})
+
+const trackedProcessJSON = `{"name":"web","type":"docker","details":{"id":"abc123","status":"running","extra":{"image":"nginx"}}}`
+
+func (s *processInfoSuite) TestInfoAll(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-info")
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "["+trackedProcessJSON+"]\n")
+	s.Stub.CheckCallNames(c, "Processes")
+}
+
+func (s *processInfoSuite) TestInfoNamed(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-info", "web")
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, trackedProcessJSON+"\n")
+}
+
+func (s *processInfoSuite) TestInfoYAML(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-info", "--format", "yaml", "web")
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, `
+name: web
+type: docker
+details:
+  id: abc123
+  status: running
+  extra:
+    image: nginx
+`[1:])
+}
+
+func (s *processInfoSuite) TestInfoNotTracked(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-info", "db")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: process \"db\" not found\n")
+}
+
+func (s *processInfoSuite) TestInfoError(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	s.Stub.SetErrors(errors.New("boom"))
+	code, ctx := s.run(c, hctx, "process-info")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot get processes: boom\n")
+}
+
+func (s *processInfoSuite) TestInitErrors(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-info", "web", "extra")
+	c.Check(code, gc.Equals, 2)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: unrecognized args: [\"extra\"]\n")
+}
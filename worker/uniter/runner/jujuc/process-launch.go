@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/process"
+)
+
+// ProcessLaunchCommand implements the process-launch command.
+type ProcessLaunchCommand struct {
+	cmd.CommandBase
+	ctx         Context
+	def         process.Definition
+	healthCheck string
+	out         cmd.Output
+}
+
+// NewProcessLaunchCommand makes a jujuc process-launch command.
+func NewProcessLaunchCommand(ctx Context) (cmd.Command, error) {
+	return &ProcessLaunchCommand{ctx: ctx}, nil
+}
+
+func (c *ProcessLaunchCommand) Info() *cmd.Info {
+	doc := `
+process-launch asks juju to launch a workload process using the plugin
+for <type>, e.g. "docker" or "systemd", and tracks the launched process
+as if with process-track. It is an error to launch a process with the
+same name as one already tracked.
+
+Container plugins such as docker require --image, and run --command in
+place of the image's default command if it is specified. The systemd
+plugin requires --command, which is run by the shell.
+
+If --health-check is specified, the unit agent periodically checks the
+health of the process, marking it "unhealthy" if the check fails. The
+check is given as <type>:<target>, where <type> is one of:
+    http  the target URL must respond with a 2xx or 3xx status
+    tcp   the target host:port must accept connections
+    exec  the target command must exit with status zero
+e.g. "tcp:localhost:6379". If --restart is "on-failure", the unit agent
+relaunches the process whenever it fails or is unhealthy.
+
+The launched process is printed on success.
+`
+	return &cmd.Info{
+		Name:    "process-launch",
+		Args:    "<name> <type>",
+		Purpose: "launch a workload process",
+		Doc:     doc,
+	}
+}
+
+func (c *ProcessLaunchCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "json", cmd.DefaultFormatters)
+	f.StringVar(&c.def.Image, "image", "", "the image to launch the process from")
+	f.StringVar(&c.def.Command, "command", "", "the command that starts the process")
+	f.StringVar(&c.healthCheck, "health-check", "", "the health check for the process, as <type>:<target>")
+	f.StringVar((*string)(&c.def.Restart), "restart", string(process.RestartNever), `the restart policy: "never" or "on-failure"`)
+}
+
+func (c *ProcessLaunchCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("expected <name> <type>")
+	}
+	if err := cmd.CheckEmpty(args[2:]); err != nil {
+		return err
+	}
+	c.def.Name = args[0]
+	c.def.Type = args[1]
+	if c.healthCheck != "" {
+		check, err := process.ParseHealthCheck(c.healthCheck)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.def.HealthCheck = &check
+	}
+	return c.def.Validate()
+}
+
+func (c *ProcessLaunchCommand) Run(ctx *cmd.Context) error {
+	info, err := c.ctx.LaunchProcess(c.def)
+	if err != nil {
+		return errors.Annotatef(err, "cannot launch process %q", c.def.Name)
+	}
+	return c.out.Write(ctx, info)
+}
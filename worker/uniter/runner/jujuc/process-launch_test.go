@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+)
+
+type processLaunchSuite struct {
+	processSuite
+}
+
+var _ = gc.Suite(&processLaunchSuite{})
+
+func (s *processLaunchSuite) TestLaunch(c *gc.C) {
+	hctx, info := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-launch", "--image", "redis", "--command", "redis-server", "cache", "docker")
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals,
+		`{"name":"cache","type":"docker","details":{"id":"cache-id","status":"running"},`+
+			`"definition":{"name":"cache","type":"docker","image":"redis","command":"redis-server","restart":"never"}}`+"\n")
+
+	s.Stub.CheckCall(c, 0, "LaunchProcess", process.Definition{
+		Name:    "cache",
+		Type:    "docker",
+		Image:   "redis",
+		Command: "redis-server",
+		Restart: process.RestartNever,
+	})
+	c.Check(info.Processes.Processes, gc.HasLen, 2)
+}
+
+func (s *processLaunchSuite) TestLaunchHealthCheck(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-launch",
+		"--image", "redis",
+		"--health-check", "tcp:localhost:6379",
+		"--restart", "on-failure",
+		"cache", "docker",
+	)
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	s.Stub.CheckCall(c, 0, "LaunchProcess", process.Definition{
+		Name:        "cache",
+		Type:        "docker",
+		Image:       "redis",
+		HealthCheck: &process.HealthCheck{Type: "tcp", Target: "localhost:6379"},
+		Restart:     process.RestartOnFailure,
+	})
+}
+
+func (s *processLaunchSuite) TestLaunchAlreadyTracked(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-launch", "--image", "nginx", "web", "docker")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot launch process \"web\": process \"web\" already exists\n")
+}
+
+func (s *processLaunchSuite) TestLaunchError(c *gc.C) {
+	hctx, info := s.newHookContext()
+	s.Stub.SetErrors(errors.New("boom"))
+	code, ctx := s.run(c, hctx, "process-launch", "--command", "sleep 100", "sleeper", "systemd")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot launch process \"sleeper\": boom\n")
+	c.Check(info.Processes.Processes, jc.DeepEquals, map[string]process.Info{"web": trackedProcess})
+}
+
+func (s *processLaunchSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"cache"},
+		err:  "expected <name> <type>",
+	}, {
+		args: []string{"cache", "docker", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"Cache", "docker"},
+		err:  `process name "Cache" not valid`,
+	}, {
+		args: []string{"cache", ""},
+		err:  `process "cache" with empty type not valid`,
+	}, {
+		args: []string{"--health-check", "ping:localhost", "cache", "docker"},
+		err:  `health check type "ping" not valid`,
+	}, {
+		args: []string{"--restart", "always", "cache", "docker"},
+		err:  `process "cache": restart policy "always" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		hctx, _ := s.newHookContext()
+		code, ctx := s.run(c, hctx, "process-launch", test.args...)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Matches, "error: "+test.err+"\n")
+	}
+}
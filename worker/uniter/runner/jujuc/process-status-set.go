@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// ProcessStatusSetCommand implements the process-status-set command.
+type ProcessStatusSetCommand struct {
+	cmd.CommandBase
+	ctx    Context
+	name   string
+	status string
+}
+
+// NewProcessStatusSetCommand makes a jujuc process-status-set command.
+func NewProcessStatusSetCommand(ctx Context) (cmd.Command, error) {
+	return &ProcessStatusSetCommand{ctx: ctx}, nil
+}
+
+func (c *ProcessStatusSetCommand) Info() *cmd.Info {
+	doc := `
+process-status-set updates the plugin-specific status of a workload
+process previously tracked with process-track. The new status is
+reported by "juju status" and "juju list-processes", which also show
+the process's status history.
+`
+	return &cmd.Info{
+		Name:    "process-status-set",
+		Args:    "<name> <status>",
+		Purpose: "set the status of a tracked workload process",
+		Doc:     doc,
+	}
+}
+
+func (c *ProcessStatusSetCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("expected <name> <status>")
+	}
+	if args[1] == "" {
+		return errors.New("status must not be empty")
+	}
+	c.name, c.status = args[0], args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+func (c *ProcessStatusSetCommand) Run(ctx *cmd.Context) error {
+	err := c.ctx.SetProcessStatus(c.name, c.status)
+	return errors.Annotatef(err, "cannot set status of process %q", c.name)
+}
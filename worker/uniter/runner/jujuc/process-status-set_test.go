@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "gopkg.in/check.v1"
+)
+
+type processStatusSetSuite struct {
+	processSuite
+}
+
+var _ = gc.Suite(&processStatusSetSuite{})
+
+func (s *processStatusSetSuite) TestSetStatus(c *gc.C) {
+	hctx, info := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-status-set", "web", "stopped")
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	s.Stub.CheckCall(c, 0, "SetProcessStatus", "web", "stopped")
+	c.Check(info.Processes.Processes["web"].Details.Status, gc.Equals, "stopped")
+}
+
+func (s *processStatusSetSuite) TestSetStatusNotTracked(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-status-set", "db", "stopped")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot set status of process \"db\": process \"db\" not found\n")
+}
+
+func (s *processStatusSetSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"web"},
+		err:  "expected <name> <status>",
+	}, {
+		args: []string{"web", ""},
+		err:  "status must not be empty",
+	}, {
+		args: []string{"web", "stopped", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		hctx, _ := s.newHookContext()
+		code, ctx := s.run(c, hctx, "process-status-set", test.args...)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Matches, "error: "+test.err+"\n")
+	}
+}
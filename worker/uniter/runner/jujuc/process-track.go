@@ -0,0 +1,78 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/process"
+)
+
+// ProcessTrackCommand implements the process-track command.
+type ProcessTrackCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	info process.Info
+	out  cmd.Output
+}
+
+// NewProcessTrackCommand makes a jujuc process-track command.
+func NewProcessTrackCommand(ctx Context) (cmd.Command, error) {
+	return &ProcessTrackCommand{ctx: ctx}, nil
+}
+
+func (c *ProcessTrackCommand) Info() *cmd.Info {
+	doc := `
+process-track records a workload process that the charm has launched
+outside of juju's control, so that the unit can report on it. If a
+process with the same name is already tracked, it is replaced.
+
+<type> identifies the plugin that understands the process, e.g.
+"docker". <details> is a JSON object describing the launched process;
+it must include the "id" and "status" of the process, and any other
+fields must match the schema of the plugin.
+
+The tracked process is printed on success. Tracked processes may be
+read back with process-info, and are reported to the user by "juju
+status" and "juju list-processes".
+`
+	return &cmd.Info{
+		Name:    "process-track",
+		Args:    "<name> <type> <details>",
+		Purpose: "track a workload process",
+		Doc:     doc,
+	}
+}
+
+func (c *ProcessTrackCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "json", cmd.DefaultFormatters)
+}
+
+func (c *ProcessTrackCommand) Init(args []string) error {
+	if len(args) < 3 {
+		return errors.New("expected <name> <type> <details>")
+	}
+	if err := cmd.CheckEmpty(args[3:]); err != nil {
+		return err
+	}
+	details, err := process.ParseDetails(args[1], []byte(args[2]))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.info = process.Info{
+		Name:    args[0],
+		Type:    args[1],
+		Details: details,
+	}
+	return c.info.Validate()
+}
+
+func (c *ProcessTrackCommand) Run(ctx *cmd.Context) error {
+	if err := c.ctx.TrackProcess(c.info); err != nil {
+		return errors.Annotatef(err, "cannot track process %q", c.info.Name)
+	}
+	return c.out.Write(ctx, c.info)
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+)
+
+type processTrackSuite struct {
+	processSuite
+}
+
+var _ = gc.Suite(&processTrackSuite{})
+
+func (s *processTrackSuite) TestTrack(c *gc.C) {
+	hctx, info := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-track", "db", "exec", `{"id": "42", "status": "starting", "pid": 42}`)
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals,
+		`{"name":"db","type":"exec","details":{"id":"42","status":"starting","extra":{"pid":42}}}`+"\n")
+
+	expected := process.Info{
+		Name: "db",
+		Type: "exec",
+		Details: process.Details{
+			ID:     "42",
+			Status: "starting",
+			Extra:  map[string]interface{}{"pid": 42.0},
+		},
+	}
+	s.Stub.CheckCallNames(c, "TrackProcess")
+	c.Check(info.Processes.Processes, jc.DeepEquals, map[string]process.Info{
+		"web": trackedProcess,
+		"db":  expected,
+	})
+}
+
+func (s *processTrackSuite) TestTrackError(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	s.Stub.SetErrors(errors.New("boom"))
+	code, ctx := s.run(c, hctx, "process-track", "db", "exec", `{"id": "42", "status": "starting"}`)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot track process \"db\": boom\n")
+}
+
+func (s *processTrackSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"db", "exec"},
+		err:  "expected <name> <type> <details>",
+	}, {
+		args: []string{"db", "exec", "{}", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"db", "unknown", `{"id": "42", "status": "up"}`},
+		err:  `process plugin "unknown" not found`,
+	}, {
+		args: []string{"db", "exec", `not json`},
+		err:  "cannot parse details: .*",
+	}, {
+		args: []string{"db", "exec", `{"status": "up"}`},
+		err:  `details must include a non-empty "id" string`,
+	}, {
+		args: []string{"db", "exec", `{"id": "42", "status": "up", "pid": "42"}`},
+		err:  `invalid exec details: field "pid": expected number, got string`,
+	}, {
+		args: []string{"db", "exec", `{"id": "42", "status": "up", "colour": "red"}`},
+		err:  `invalid exec details: unknown field "colour"`,
+	}, {
+		args: []string{"Db", "exec", `{"id": "42", "status": "up"}`},
+		err:  `process name "Db" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		hctx, _ := s.newHookContext()
+		code, ctx := s.run(c, hctx, "process-track", test.args...)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Matches, "error: "+test.err+"\n")
+	}
+}
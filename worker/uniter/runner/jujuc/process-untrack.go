@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// ProcessUntrackCommand implements the process-untrack command.
+type ProcessUntrackCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	name string
+}
+
+// NewProcessUntrackCommand makes a jujuc process-untrack command.
+func NewProcessUntrackCommand(ctx Context) (cmd.Command, error) {
+	return &ProcessUntrackCommand{ctx: ctx}, nil
+}
+
+func (c *ProcessUntrackCommand) Info() *cmd.Info {
+	doc := `
+process-untrack stops tracking a workload process previously tracked
+with process-track. It does not stop the process itself. The process
+is no longer reported by process-info, "juju status" or "juju
+list-processes".
+`
+	return &cmd.Info{
+		Name:    "process-untrack",
+		Args:    "<name>",
+		Purpose: "stop tracking a workload process",
+		Doc:     doc,
+	}
+}
+
+func (c *ProcessUntrackCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no process name specified")
+	}
+	c.name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *ProcessUntrackCommand) Run(ctx *cmd.Context) error {
+	err := c.ctx.UntrackProcess(c.name)
+	return errors.Annotatef(err, "cannot untrack process %q", c.name)
+}
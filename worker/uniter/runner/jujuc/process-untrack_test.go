@@ -0,0 +1,37 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "gopkg.in/check.v1"
+)
+
+type processUntrackSuite struct {
+	processSuite
+}
+
+var _ = gc.Suite(&processUntrackSuite{})
+
+func (s *processUntrackSuite) TestUntrack(c *gc.C) {
+	hctx, info := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-untrack", "web")
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	s.Stub.CheckCall(c, 0, "UntrackProcess", "web")
+	c.Check(info.Processes.Processes, gc.HasLen, 0)
+}
+
+func (s *processUntrackSuite) TestUntrackNotTracked(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-untrack", "db")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot untrack process \"db\": process \"db\" not found\n")
+}
+
+func (s *processUntrackSuite) TestInitErrors(c *gc.C) {
+	hctx, _ := s.newHookContext()
+	code, ctx := s.run(c, hctx, "process-untrack")
+	c.Check(code, gc.Equals, 2)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: no process name specified\n")
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/process"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+var trackedProcess = process.Info{
+	Name: "web",
+	Type: "docker",
+	Details: process.Details{
+		ID:     "abc123",
+		Status: "running",
+		Extra:  map[string]interface{}{"image": "nginx"},
+	},
+}
+
+type processSuite struct {
+	ContextSuite
+}
+
+// newHookContext returns a hook context in which trackedProcess is
+// already tracked.
+func (s *processSuite) newHookContext() (*jujuctesting.Context, *jujuctesting.ContextInfo) {
+	hctx, info := s.NewHookContext()
+	info.SetProcess(trackedProcess)
+	return hctx, info
+}
+
+// run runs the named hook tool against hctx, returning its exit code
+// and the command context.
+func (s *processSuite) run(c *gc.C, hctx jujuc.Context, name string, args ...string) (int, *cmd.Context) {
+	com, err := jujuc.NewCommand(hctx, cmdString(name))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, args)
+	return code, ctx
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/process"
 )
 
 // ErrRestrictedContext indicates a method is not implemented in the given context.
@@ -96,6 +97,26 @@ func (*RestrictedContext) AddUnitStorage(map[string]params.StorageConstraints) e
 	return ErrRestrictedContext
 }
 
+// Processes implements jujuc.Context.
+func (*RestrictedContext) Processes() ([]process.Info, error) { return nil, ErrRestrictedContext }
+
+// TrackProcess implements jujuc.Context.
+func (*RestrictedContext) TrackProcess(process.Info) error { return ErrRestrictedContext }
+
+// SetProcessStatus implements jujuc.Context.
+func (*RestrictedContext) SetProcessStatus(name, status string) error { return ErrRestrictedContext }
+
+// UntrackProcess implements jujuc.Context.
+func (*RestrictedContext) UntrackProcess(name string) error { return ErrRestrictedContext }
+
+// LaunchProcess implements jujuc.Context.
+func (*RestrictedContext) LaunchProcess(process.Definition) (process.Info, error) {
+	return process.Info{}, ErrRestrictedContext
+}
+
+// DestroyProcess implements jujuc.Context.
+func (*RestrictedContext) DestroyProcess(name string) error { return ErrRestrictedContext }
+
 // Relation implements jujuc.Context.
 func (*RestrictedContext) Relation(id int) (ContextRelation, error) {
 	return nil, ErrRestrictedContext
@@ -59,6 +59,15 @@ var storageCommands = map[string]creator{
 	"storage-list" + cmdSuffix: NewStorageListCommand,
 }
 
+var processCommands = map[string]creator{
+	"process-track" + cmdSuffix:      NewProcessTrackCommand,
+	"process-status-set" + cmdSuffix: NewProcessStatusSetCommand,
+	"process-untrack" + cmdSuffix:    NewProcessUntrackCommand,
+	"process-launch" + cmdSuffix:     NewProcessLaunchCommand,
+	"process-destroy" + cmdSuffix:    NewProcessDestroyCommand,
+	"process-info" + cmdSuffix:       NewProcessInfoCommand,
+}
+
 var leaderCommands = map[string]creator{
 	"is-leader" + cmdSuffix:  NewIsLeaderCommand,
 	"leader-get" + cmdSuffix: NewLeaderGetCommand,
@@ -74,6 +83,7 @@ func allEnabledCommands() map[string]creator {
 	}
 	add(baseCommands)
 	add(storageCommands)
+	add(processCommands)
 	add(leaderCommands)
 	return all
 }
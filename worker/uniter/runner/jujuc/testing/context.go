@@ -18,6 +18,7 @@ type ContextInfo struct {
 	Leadership
 	Metrics
 	Storage
+	Processes
 	Relations
 	RelationHook
 	ActionHook
@@ -56,6 +57,7 @@ type Context struct {
 	ContextLeader
 	ContextMetrics
 	ContextStorage
+	ContextProcesses
 	ContextRelations
 	ContextRelationHook
 	ContextActionHook
@@ -78,6 +80,8 @@ func NewContext(stub *testing.Stub, info *ContextInfo) *Context {
 	ctx.ContextMetrics.info = &info.Metrics
 	ctx.ContextStorage.stub = stub
 	ctx.ContextStorage.info = &info.Storage
+	ctx.ContextProcesses.stub = stub
+	ctx.ContextProcesses.info = &info.Processes
 	ctx.ContextRelations.stub = stub
 	ctx.ContextRelations.info = &info.Relations
 	ctx.ContextRelationHook.stub = stub
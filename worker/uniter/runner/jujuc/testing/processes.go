@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/process"
+)
+
+// Processes holds the values for the hook sub-context.
+type Processes struct {
+	Processes map[string]process.Info
+}
+
+// SetProcess adds the process to the tracked processes.
+func (p *Processes) SetProcess(info process.Info) {
+	if p.Processes == nil {
+		p.Processes = make(map[string]process.Info)
+	}
+	p.Processes[info.Name] = info
+}
+
+// ContextProcesses is a test double for jujuc.ContextProcesses.
+type ContextProcesses struct {
+	contextBase
+	info *Processes
+}
+
+// Processes implements jujuc.ContextProcesses.
+func (c *ContextProcesses) Processes() ([]process.Info, error) {
+	c.stub.AddCall("Processes")
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var names []string
+	for name := range c.info.Processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	infos := make([]process.Info, len(names))
+	for i, name := range names {
+		infos[i] = c.info.Processes[name]
+	}
+	return infos, nil
+}
+
+// TrackProcess implements jujuc.ContextProcesses.
+func (c *ContextProcesses) TrackProcess(info process.Info) error {
+	c.stub.AddCall("TrackProcess", info)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	c.info.SetProcess(info)
+	return nil
+}
+
+// SetProcessStatus implements jujuc.ContextProcesses.
+func (c *ContextProcesses) SetProcessStatus(name, status string) error {
+	c.stub.AddCall("SetProcessStatus", name, status)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	info, ok := c.info.Processes[name]
+	if !ok {
+		return errors.NotFoundf("process %q", name)
+	}
+	info.Details.Status = status
+	c.info.Processes[name] = info
+	return nil
+}
+
+// UntrackProcess implements jujuc.ContextProcesses.
+func (c *ContextProcesses) UntrackProcess(name string) error {
+	c.stub.AddCall("UntrackProcess", name)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if _, ok := c.info.Processes[name]; !ok {
+		return errors.NotFoundf("process %q", name)
+	}
+	delete(c.info.Processes, name)
+	return nil
+}
+
+// LaunchProcess implements jujuc.ContextProcesses. The launched
+// process is given the ID "<name>-id" and the status "running".
+func (c *ContextProcesses) LaunchProcess(def process.Definition) (process.Info, error) {
+	c.stub.AddCall("LaunchProcess", def)
+	if err := c.stub.NextErr(); err != nil {
+		return process.Info{}, errors.Trace(err)
+	}
+
+	if _, ok := c.info.Processes[def.Name]; ok {
+		return process.Info{}, errors.AlreadyExistsf("process %q", def.Name)
+	}
+	info := process.Info{
+		Name: def.Name,
+		Type: def.Type,
+		Details: process.Details{
+			ID:     def.Name + "-id",
+			Status: "running",
+		},
+		Definition: &def,
+	}
+	c.info.SetProcess(info)
+	return info, nil
+}
+
+// DestroyProcess implements jujuc.ContextProcesses.
+func (c *ContextProcesses) DestroyProcess(name string) error {
+	c.stub.AddCall("DestroyProcess", name)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if _, ok := c.info.Processes[name]; !ok {
+		return errors.NotFoundf("process %q", name)
+	}
+	delete(c.info.Processes, name)
+	return nil
+}
@@ -23,6 +23,7 @@ type RealPaths struct {
 	charm        string
 	socket       string
 	metricsspool string
+	processes    string
 }
 
 func osDependentSockPath(c *gc.C) string {
@@ -39,6 +40,7 @@ func NewRealPaths(c *gc.C) RealPaths {
 		charm:        c.MkDir(),
 		socket:       osDependentSockPath(c),
 		metricsspool: c.MkDir(),
+		processes:    filepath.Join(c.MkDir(), "processes"),
 	}
 }
 
@@ -46,6 +48,10 @@ func (p RealPaths) GetMetricsSpoolDir() string {
 	return p.metricsspool
 }
 
+func (p RealPaths) GetProcessesFile() string {
+	return p.processes
+}
+
 func (p RealPaths) GetToolsDir() string {
 	return p.tools
 }
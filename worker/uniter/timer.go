@@ -5,11 +5,17 @@ package uniter
 
 import (
 	"time"
+
+	"github.com/juju/utils/clock"
 )
 
 const (
 	// interval at which the unit's status should be polled
 	statusPollInterval = 5 * time.Minute
+
+	// initial and maximum delays before a failed hook is retried
+	retryHookMinDelay = 5 * time.Second
+	retryHookMaxDelay = 5 * time.Minute
 )
 
 // updateStatusSignal returns a time channel that fires after a given interval.
@@ -21,3 +27,77 @@ func updateStatusSignal() <-chan time.Time {
 func NewUpdateStatusTimer() func() <-chan time.Time {
 	return updateStatusSignal
 }
+
+// RetryHookTimer signals when a failed hook should be retried. Each
+// time it is started it waits twice as long as the time before, up to
+// a maximum, until it is reset.
+//
+// A RetryHookTimer is not safe for concurrent use.
+type RetryHookTimer struct {
+	clock    clock.Clock
+	min, max time.Duration
+	delay    time.Duration
+	signal   chan struct{}
+	stop     chan struct{}
+}
+
+// NewRetryHookTimer returns a RetryHookTimer that uses the given clock,
+// waiting at first for 5 seconds and eventually for 5 minutes.
+func NewRetryHookTimer(clock clock.Clock) *RetryHookTimer {
+	return newRetryHookTimer(clock, retryHookMinDelay, retryHookMaxDelay)
+}
+
+func newRetryHookTimer(clock clock.Clock, min, max time.Duration) *RetryHookTimer {
+	return &RetryHookTimer{
+		clock:  clock,
+		min:    min,
+		max:    max,
+		delay:  min,
+		signal: make(chan struct{}, 1),
+	}
+}
+
+// Signal returns a channel that receives a value each time a started
+// timer expires.
+func (t *RetryHookTimer) Signal() <-chan struct{} {
+	return t.signal
+}
+
+// Start arranges for Signal to receive a value after the current delay,
+// which it returns, and lengthens the delay for next time. Any pending
+// signal is cancelled.
+func (t *RetryHookTimer) Start() time.Duration {
+	t.cancel()
+	delay := t.delay
+	if t.delay *= 2; t.delay > t.max {
+		t.delay = t.max
+	}
+	after := t.clock.After(delay)
+	stop := make(chan struct{})
+	t.stop = stop
+	go func() {
+		select {
+		case <-after:
+		case <-stop:
+			return
+		}
+		select {
+		case t.signal <- struct{}{}:
+		default:
+		}
+	}()
+	return delay
+}
+
+// Reset cancels any pending signal and restores the initial delay.
+func (t *RetryHookTimer) Reset() {
+	t.cancel()
+	t.delay = t.min
+}
+
+func (t *RetryHookTimer) cancel() {
+	if t.stop != nil {
+		close(t.stop)
+		t.stop = nil
+	}
+}
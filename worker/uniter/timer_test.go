@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter"
+)
+
+type retryHookTimerSuite struct {
+	coretesting.BaseSuite
+	clock *coretesting.Clock
+	timer *uniter.RetryHookTimer
+}
+
+var _ = gc.Suite(&retryHookTimerSuite{})
+
+func (s *retryHookTimerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = coretesting.NewClock(time.Now())
+	s.timer = uniter.NewRetryHookTimer(s.clock)
+	s.AddCleanup(func(*gc.C) { s.timer.Reset() })
+}
+
+func (s *retryHookTimerSuite) assertSignal(c *gc.C) {
+	select {
+	case <-s.timer.Signal():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for retry signal")
+	}
+}
+
+func (s *retryHookTimerSuite) assertNoSignal(c *gc.C) {
+	select {
+	case <-s.timer.Signal():
+		c.Fatalf("unexpected retry signal")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *retryHookTimerSuite) TestBackoff(c *gc.C) {
+	for _, expect := range []time.Duration{
+		5 * time.Second,
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+		80 * time.Second,
+		160 * time.Second,
+		5 * time.Minute,
+		5 * time.Minute,
+	} {
+		delay := s.timer.Start()
+		c.Assert(delay, gc.Equals, expect)
+		s.clock.Advance(delay - time.Second)
+		s.assertNoSignal(c)
+		s.clock.Advance(time.Second)
+		s.assertSignal(c)
+	}
+}
+
+func (s *retryHookTimerSuite) TestReset(c *gc.C) {
+	c.Assert(s.timer.Start(), gc.Equals, 5*time.Second)
+	c.Assert(s.timer.Start(), gc.Equals, 10*time.Second)
+	s.timer.Reset()
+	s.clock.Advance(time.Minute)
+	s.assertNoSignal(c)
+	c.Assert(s.timer.Start(), gc.Equals, 5*time.Second)
+}
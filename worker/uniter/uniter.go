@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/charmdir"
 	"github.com/juju/juju/worker/leadership"
+	"github.com/juju/juju/worker/problemreporter"
 	"github.com/juju/juju/worker/uniter/actions"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
@@ -85,9 +86,15 @@ type Uniter struct {
 	// need to be extended, perhaps a list of observers would be needed.
 	observer UniterExecutionObserver
 
+	// problemReporter, if set, is used to report failed hooks.
+	problemReporter problemreporter.Reporter
+
 	// updateStatusAt defines a function that will be used to generate signals for
 	// the update-status hook
 	updateStatusAt func() <-chan time.Time
+
+	// retryHookTimer, if set, is used to retry failed hooks.
+	retryHookTimer *RetryHookTimer
 }
 
 // UniterParams hold all the necessary parameters for a new Uniter.
@@ -104,6 +111,13 @@ type UniterParams struct {
 	// the observer is only a stop gap to be used in tests. A better approach would be to have the uniter tests start hooks
 	// that write to files, and have the tests watch the output to know that hooks have finished.
 	Observer UniterExecutionObserver
+	// ProblemReporter, if not nil, is used to report failed hooks to
+	// the controller.
+	ProblemReporter problemreporter.Reporter
+	// RetryHookTimer, if not nil, is used to retry failed hooks
+	// when the environment's automatically-retry-hooks setting
+	// allows it.
+	RetryHookTimer *RetryHookTimer
 }
 
 type NewExecutorFunc func(string, func() (*corecharm.URL, error), func(string) (func() error, error)) (operation.Executor, error)
@@ -121,6 +135,8 @@ func NewUniter(uniterParams *UniterParams) *Uniter {
 		updateStatusAt:       uniterParams.UpdateStatusSignal,
 		newOperationExecutor: uniterParams.NewOperationExecutor,
 		observer:             uniterParams.Observer,
+		problemReporter:      uniterParams.ProblemReporter,
+		retryHookTimer:       uniterParams.RetryHookTimer,
 	}
 	go func() {
 		defer u.tomb.Done()
@@ -179,6 +195,15 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		watcherMu sync.Mutex
 	)
 
+	var retryHookChannel <-chan struct{}
+	if u.retryHookTimer != nil {
+		retryHookChannel = u.retryHookTimer.Signal()
+		u.addCleanup(func() error {
+			u.retryHookTimer.Reset()
+			return nil
+		})
+	}
+
 	restartWatcher := func() error {
 		watcherMu.Lock()
 		defer watcherMu.Unlock()
@@ -195,6 +220,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 				LeadershipTracker:   u.leadershipTracker,
 				UnitTag:             unitTag,
 				UpdateStatusChannel: u.updateStatusAt,
+				RetryHookChannel:    retryHookChannel,
 			})
 		if err != nil {
 			return errors.Trace(err)
@@ -241,6 +267,28 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		return nil
 	}
 
+	startRetryHookTimer := func() error {
+		if u.retryHookTimer == nil {
+			return nil
+		}
+		envConfig, err := u.st.EnvironConfig()
+		if err != nil {
+			return errors.Annotate(err, "cannot read environment config")
+		}
+		if !envConfig.AutomaticallyRetryHooks() {
+			return nil
+		}
+		delay := u.retryHookTimer.Start()
+		logger.Infof("will retry failed hook in %v", delay)
+		return nil
+	}
+
+	stopRetryHookTimer := func() {
+		if u.retryHookTimer != nil {
+			u.retryHookTimer.Reset()
+		}
+	}
+
 	for {
 		if err = restartWatcher(); err != nil {
 			err = errors.Annotate(err, "(re)starting watcher")
@@ -248,13 +296,15 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		}
 
 		uniterResolver := &uniterResolver{
-			clearResolved:      clearResolved,
-			reportHookError:    u.reportHookError,
-			fixDeployer:        u.deployer.Fix,
-			actionsResolver:    actions.NewResolver(),
-			leadershipResolver: uniterleadership.NewResolver(),
-			relationsResolver:  relation.NewRelationsResolver(u.relations),
-			storageResolver:    storage.NewResolver(u.storage),
+			clearResolved:       clearResolved,
+			reportHookError:     u.reportHookError,
+			fixDeployer:         u.deployer.Fix,
+			startRetryHookTimer: startRetryHookTimer,
+			stopRetryHookTimer:  stopRetryHookTimer,
+			actionsResolver:     actions.NewResolver(),
+			leadershipResolver:  uniterleadership.NewResolver(),
+			relationsResolver:   relation.NewRelationsResolver(u.relations),
+			storageResolver:     storage.NewResolver(u.storage),
 		}
 
 		// We should not do anything until there has been a change
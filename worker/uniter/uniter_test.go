@@ -466,7 +466,7 @@ func (s *UniterSuite) TestUniterDyingReaction(c *gc.C) {
 			"steady state unit dying",
 			quickStart{},
 			unitDying,
-			waitHooks{"leader-settings-changed", "stop"},
+			waitHooks{"leader-deposed", "leader-settings-changed", "stop"},
 			waitUniterDead{},
 		), ut(
 			"steady state unit dead",
@@ -478,6 +478,8 @@ func (s *UniterSuite) TestUniterDyingReaction(c *gc.C) {
 			"hook error unit dying",
 			startupError{"start"},
 			unitDying,
+			// Leadership is resigned even while the unit is in error.
+			waitHooks{"leader-deposed"},
 			verifyWaiting{},
 			fixHook{"start"},
 			resolveError{state.ResolvedRetryHooks},
@@ -970,7 +972,7 @@ func (s *UniterSuite) TestUniterUpgradeConflicts(c *gc.C) {
 			verifyWaitingUpgradeError{revision: 1},
 			fixUpgradeError{},
 			resolveError{state.ResolvedNoHooks},
-			waitHooks{"upgrade-charm", "config-changed", "leader-settings-changed", "stop"},
+			waitHooks{"leader-deposed", "upgrade-charm", "config-changed", "leader-settings-changed", "stop"},
 			waitUniterDead{},
 		), ut(
 			"upgrade conflict unit dead",
@@ -1107,7 +1109,7 @@ func (s *UniterSuite) TestUniterUpgradeGitConflicts(c *gc.C) {
 			unitDying,
 			verifyWaiting{},
 			resolveError{state.ResolvedNoHooks},
-			waitHooks{"upgrade-charm", "config-changed", "leader-settings-changed", "stop"},
+			waitHooks{"leader-deposed", "upgrade-charm", "config-changed", "leader-settings-changed", "stop"},
 			waitUniterDead{},
 		), ugt(
 			"upgrade conflict unit dead",
@@ -1125,23 +1127,28 @@ func (s *UniterSuite) TestUniterRelations(c *gc.C) {
 		// leader-settings-changed hooks; and while we're dying we may
 		// never get to leader-settings-changed before it's time to run
 		// the stop (as we might not react to a config change in time).
+		// Leadership is always resigned first, though.
 		// It's actually clearer to just list the possible orders:
 		possibles := [][]string{{
+			"leader-deposed",
 			"leader-settings-changed",
 			"db-relation-departed mysql/0 db:0",
 			"db-relation-broken db:0",
 			"stop",
 		}, {
+			"leader-deposed",
 			"db-relation-departed mysql/0 db:0",
 			"leader-settings-changed",
 			"db-relation-broken db:0",
 			"stop",
 		}, {
+			"leader-deposed",
 			"db-relation-departed mysql/0 db:0",
 			"db-relation-broken db:0",
 			"leader-settings-changed",
 			"stop",
 		}, {
+			"leader-deposed",
 			"db-relation-departed mysql/0 db:0",
 			"db-relation-broken db:0",
 			"stop",
@@ -1664,7 +1671,7 @@ func (s *UniterSuite) TestUniterSubordinates(c *gc.C) {
 			waitSubordinateExists{"logging/0"},
 			unitDying,
 			waitSubordinateDying{},
-			waitHooks{"leader-settings-changed", "stop"},
+			waitHooks{"leader-deposed", "leader-settings-changed", "stop"},
 			verifyWaiting{},
 			removeSubordinate{},
 			waitUniterDead{},
@@ -1917,11 +1924,12 @@ func (s *UniterSuite) TestLeadership(c *gc.C) {
 			quickStart{minion: true},
 			verifyRunning{minion: true},
 		), ut(
-			"leader-settings-changed triggers when deposed (while stopped)",
+			"leader-deposed and leader-settings-changed trigger when deposed (while stopped)",
 			quickStart{},
 			stopUniter{},
 			forceMinion{},
-			verifyRunning{minion: true},
+			startUniter{},
+			waitHooks{"leader-deposed", "leader-settings-changed", "config-changed"},
 		),
 	})
 }
@@ -1933,10 +1941,10 @@ func (s *UniterSuite) TestLeadershipUnexpectedDepose(c *gc.C) {
 			// *would* happen if the uniter suddenly failed to renew its lease;
 			// it depends on an artificially shortened tracker refresh time to
 			// run in a reasonable amount of time.
-			"leader-settings-changed triggers when deposed (while running)",
+			"leader-deposed and leader-settings-changed trigger when deposed (while running)",
 			quickStart{},
 			forceMinion{},
-			waitHooks{"leader-settings-changed"},
+			waitHooks{"leader-deposed", "leader-settings-changed"},
 		),
 	})
 }
@@ -1980,7 +1988,7 @@ func (s *UniterSuite) TestStorage(c *gc.C) {
 			waitHooks{"wp-content-storage-attached"},
 			waitHooks(startupHooks(false)),
 			unitDying,
-			waitHooks{"leader-settings-changed"},
+			waitHooks{"leader-deposed", "leader-settings-changed"},
 			// "stop" hook is not called until storage is detached
 			waitHooks{"wp-content-storage-detaching", "stop"},
 			verifyStorageDetached{},
@@ -1999,7 +2007,7 @@ func (s *UniterSuite) TestStorage(c *gc.C) {
 			waitHooks(startupHooks(false)),
 			unitDying,
 			// storage-detaching is not called because it was never attached
-			waitHooks{"leader-settings-changed", "stop"},
+			waitHooks{"leader-deposed", "leader-settings-changed", "stop"},
 			verifyStorageDetached{},
 			waitUniterDead{},
 		), ut(
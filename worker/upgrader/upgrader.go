@@ -226,9 +226,25 @@ func (u *Upgrader) newUpgradeReadyError(newVersion version.Binary) *UpgradeReady
 }
 
 func (u *Upgrader) ensureTools(agentTools *coretools.Tools) error {
+	// The tools are verified against their checksum before they are
+	// used, so refuse any that don't have one.
+	if agentTools.SHA256 == "" {
+		return errors.Errorf("no checksum for tools %s", agentTools.Version)
+	}
+	// Another agent on this machine may already have downloaded the
+	// same tarball.
+	err := agenttools.UnpackCachedTools(u.dataDir, agentTools)
+	if err == nil {
+		logger.Infof("unpacked cached tools %s to %s", agentTools.Version, u.dataDir)
+		u.pruneToolsCache(agentTools)
+		return nil
+	} else if !errors.IsNotFound(err) {
+		logger.Warningf("%v", err)
+	}
+
 	logger.Infof("fetching tools from %q", agentTools.URL)
-	// The reader MUST verify the tools' hash, so there is no
-	// need to validate the peer. We cannot anyway: see http://pad.lv/1261780.
+	// The tools' hash is verified before they are cached, so there is
+	// no need to validate the peer. We cannot anyway: see http://pad.lv/1261780.
 	resp, err := utils.GetNonValidatingHTTPClient().Get(agentTools.URL)
 	if err != nil {
 		return err
@@ -237,10 +253,25 @@ func (u *Upgrader) ensureTools(agentTools *coretools.Tools) error {
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("bad HTTP response: %v", resp.Status)
 	}
-	err = agenttools.UnpackTools(u.dataDir, agentTools, resp.Body)
-	if err != nil {
+	if err := agenttools.CacheTools(u.dataDir, agentTools, resp.Body); err != nil {
+		return fmt.Errorf("cannot download tools: %v", err)
+	}
+	if err := agenttools.UnpackCachedTools(u.dataDir, agentTools); err != nil {
 		return fmt.Errorf("cannot unpack tools: %v", err)
 	}
 	logger.Infof("unpacked tools %s to %s", agentTools.Version, u.dataDir)
+	u.pruneToolsCache(agentTools)
 	return nil
 }
+
+// pruneToolsCache removes cached tools tarballs other than those of
+// the current and new tools, which are kept in case of a rollback.
+func (u *Upgrader) pruneToolsCache(newTools *coretools.Tools) {
+	keep := []string{newTools.SHA256}
+	if currentTools, err := agenttools.ReadTools(u.dataDir, toBinaryVersion(version.Current)); err == nil {
+		keep = append(keep, currentTools.SHA256)
+	}
+	if err := agenttools.PruneToolsCache(u.dataDir, keep...); err != nil {
+		logger.Warningf("cannot prune tools cache: %v", err)
+	}
+}
@@ -297,6 +297,60 @@ func (s *UpgraderSuite) TestUsesAlreadyDownloadedToolsIfAvailable(c *gc.C) {
 	})
 }
 
+func (s *UpgraderSuite) TestUsesCachedToolsIfAvailable(c *gc.C) {
+	stor := s.DefaultToolsStorage
+	oldTools := envtesting.PrimeTools(c, stor, s.DataDir(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.3-precise-amd64"))
+	s.patchVersion(oldTools.Version)
+	newTools := envtesting.AssertUploadFakeToolsVersions(
+		c, stor, s.Environ.Config().AgentStream(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.5-precise-amd64"))[0]
+	err := statetesting.SetAgentVersion(s.State, newTools.Version.Number)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Cache the new tools' tarball, and remove it from environment
+	// storage so that it cannot be downloaded.
+	name := envtools.StorageName(newTools.Version, "released")
+	r, err := stor.Get(name)
+	c.Assert(err, jc.ErrorIsNil)
+	err = agenttools.CacheTools(s.DataDir(), newTools, r)
+	r.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	err = stor.Remove(name)
+	c.Assert(err, jc.ErrorIsNil)
+
+	u := s.makeUpgrader(c)
+	err = u.Stop()
+	s.expectUpgradeChannelNotClosed(c)
+	envtesting.CheckUpgraderReadyError(c, err, &upgrader.UpgradeReadyError{
+		AgentName: s.machine.Tag().String(),
+		OldTools:  oldTools.Version,
+		NewTools:  newTools.Version,
+		DataDir:   s.DataDir(),
+	})
+	_, err = agenttools.ReadTools(s.DataDir(), newTools.Version)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgraderSuite) TestUpgraderCachesDownloadedTools(c *gc.C) {
+	stor := s.DefaultToolsStorage
+	oldTools := envtesting.PrimeTools(c, stor, s.DataDir(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.3-precise-amd64"))
+	s.patchVersion(oldTools.Version)
+	newTools := envtesting.AssertUploadFakeToolsVersions(
+		c, stor, s.Environ.Config().AgentStream(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.5-precise-amd64"))[0]
+	err := statetesting.SetAgentVersion(s.State, newTools.Version.Number)
+	c.Assert(err, jc.ErrorIsNil)
+
+	u := s.makeUpgrader(c)
+	err = u.Stop()
+	envtesting.CheckUpgraderReadyError(c, err, &upgrader.UpgradeReadyError{
+		AgentName: s.machine.Tag().String(),
+		OldTools:  oldTools.Version,
+		NewTools:  newTools.Version,
+		DataDir:   s.DataDir(),
+	})
+	_, err = os.Stat(agenttools.CachedToolsPath(s.DataDir(), newTools.SHA256))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *UpgraderSuite) TestUpgraderRefusesToDowngradeMinorVersions(c *gc.C) {
 	stor := s.DefaultToolsStorage
 	origTools := envtesting.PrimeTools(c, stor, s.DataDir(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.3-precise-amd64"))